@@ -14,6 +14,7 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/cmd/hub"
 	"open-cluster-management.io/ocm/pkg/cmd/spoke"
+	"open-cluster-management.io/ocm/pkg/cmd/webhook"
 	"open-cluster-management.io/ocm/pkg/version"
 )
 
@@ -53,6 +54,24 @@ func newNucleusCommand() *cobra.Command {
 	cmd.AddCommand(hub.NewHubOperatorCmd())
 	cmd.AddCommand(spoke.NewKlusterletOperatorCmd())
 	cmd.AddCommand(spoke.NewKlusterletAgentCmd())
+	cmd.AddCommand(newRenderCommand())
+	cmd.AddCommand(webhook.NewOperatorWebhook())
+
+	return cmd
+}
+
+func newRenderCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the manifests the operators apply instead of applying them",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(hub.NewClusterManagerRenderCmd())
+	cmd.AddCommand(spoke.NewKlusterletRenderCmd())
 
 	return cmd
 }
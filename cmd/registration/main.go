@@ -36,6 +36,7 @@ func main() {
 	defer logs.FlushLogs()
 
 	utilruntime.Must(features.HubMutableFeatureGate.Add(ocmfeature.DefaultHubRegistrationFeatureGates))
+	utilruntime.Must(features.AddClientGoFeatureGates(features.HubMutableFeatureGate))
 	features.HubMutableFeatureGate.AddFlag(pflag.CommandLine)
 
 	command := newRegistrationCommand()
@@ -65,6 +66,7 @@ func newRegistrationCommand() *cobra.Command {
 
 	cmd.AddCommand(hub.NewRegistrationController())
 	cmd.AddCommand(spoke.NewRegistrationAgent())
+	cmd.AddCommand(spoke.NewRegistrationCredential())
 	cmd.AddCommand(webhook.NewRegistrationWebhook())
 
 	return cmd
@@ -33,6 +33,7 @@ func main() {
 	defer logs.FlushLogs()
 
 	utilruntime.Must(features.HubMutableFeatureGate.Add(ocmfeature.DefaultHubWorkFeatureGates))
+	utilruntime.Must(features.AddClientGoFeatureGates(features.HubMutableFeatureGate))
 	features.HubMutableFeatureGate.AddFlag(pflag.CommandLine)
 
 	command := newWorkCommand()
@@ -28,6 +28,25 @@ type HubConfig struct {
 	// ResourceRequirements is the resource requirements for the cluster manager managed containers.
 	// The type has to be []byte to use "indent" template function.
 	ResourceRequirements []byte
+	// RestrictedPodSecurity renders the hub components compliant with the restricted Pod Security Standard, and
+	// enforces the standard on the cluster manager namespace.
+	RestrictedPodSecurity bool
+	// LeaderElectionArgs are the flags to tune the leader election of the hub controllers.
+	LeaderElectionArgs []string
+	// ShardedControllerArgs are the sharding and leader election flags of the registration and the
+	// manifestworkreplicaset controllers.
+	ShardedControllerArgs []string
+	// FIPSMode runs the hub components in the FIPS compliance mode, it follows the mode of the operator.
+	FIPSMode bool
+	// WorkForbiddenKinds is the comma separated kinds not allowed in the manifestworks by the work webhook.
+	WorkForbiddenKinds string
+	// WorkRequireExecutor rejects the manifestworks without the executor in the work webhook.
+	WorkRequireExecutor bool
+	// SPIFFETrustDomain is the trust domain of the SPIFFE IDs in the csrs of the agents approved by the hub.
+	SPIFFETrustDomain string
+	// HubCABundleConfigMap is the configmap in the namespace of the cluster manager with the CA bundles of the hub
+	// distributed to the agents.
+	HubCABundleConfigMap string
 }
 
 type Webhook struct {
@@ -8,6 +8,8 @@ import (
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -17,8 +19,11 @@ import (
 	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
 	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	clusterinformersv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformersv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
@@ -28,6 +33,14 @@ import (
 const (
 	// maxRequeueTime is the minimum informer resync period
 	maxRequeueTime = 10 * time.Minute
+
+	// RolloutScopeAnnotationKey is the annotation on a ClusterManagementAddOn to scope the progressive rollouts of
+	// its install strategies. With the value "ManagedClusterSet", the MaxConcurrency, MaxFailures and MinSuccessTime
+	// apply within each ManagedClusterSet of the placement decisions, and the rollout stops starting on new clusters
+	// once the MaxFailures is breached in any ManagedClusterSet.
+	// TODO move this to the api repo.
+	RolloutScopeAnnotationKey = "addon.open-cluster-management.io/experimental-rollout-scope"
+	RolloutScopeClusterSet    = "ManagedClusterSet"
 )
 
 // addonConfigurationController is a controller to update configuration of mca with the following order
@@ -41,6 +54,7 @@ type addonConfigurationController struct {
 	addonFilterFunc              factory.EventFilterFunc
 	placementLister              clusterlisterv1beta1.PlacementLister
 	placementDecisionGetter      helpers.PlacementDecisionGetter
+	clusterLister                clusterlisterv1.ManagedClusterLister
 
 	reconcilers []addonConfigurationReconcile
 }
@@ -63,6 +77,7 @@ func NewAddonConfigurationController(
 	clusterManagementAddonInformers addoninformerv1alpha1.ClusterManagementAddOnInformer,
 	placementInformer clusterinformersv1beta1.PlacementInformer,
 	placementDecisionInformer clusterinformersv1beta1.PlacementDecisionInformer,
+	clusterInformer clusterinformersv1.ManagedClusterInformer,
 	addonFilterFunc factory.EventFilterFunc,
 	recorder events.Recorder,
 ) factory.Controller {
@@ -72,6 +87,7 @@ func NewAddonConfigurationController(
 		managedClusterAddonIndexer:   addonInformers.Informer().GetIndexer(),
 		placementLister:              placementInformer.Lister(),
 		placementDecisionGetter:      helpers.PlacementDecisionGetter{Client: placementDecisionInformer.Lister()},
+		clusterLister:                clusterInformer.Lister(),
 		addonFilterFunc:              addonFilterFunc,
 	}
 
@@ -94,7 +110,8 @@ func NewAddonConfigurationController(
 		WithInformersQueueKeysFunc(
 			index.ClusterManagementAddonByPlacementDecisionQueueKey(clusterManagementAddonInformers), placementDecisionInformer.Informer()).
 		WithInformersQueueKeysFunc(
-			index.ClusterManagementAddonByPlacementQueueKey(clusterManagementAddonInformers), placementInformer.Informer())
+			index.ClusterManagementAddonByPlacementQueueKey(clusterManagementAddonInformers), placementInformer.Informer()).
+		WithInformersQueueKeysFunc(c.clusterSetScopedAddonQueueKeys, clusterInformer.Informer())
 
 	return controllerFactory.WithSync(c.sync).ToController("addon-configuration-controller", recorder)
 }
@@ -180,6 +197,13 @@ func (c *addonConfigurationController) buildConfigurationGraph(logger klog.Logge
 		return graph, nil
 	}
 
+	if cma.Annotations[RolloutScopeAnnotationKey] == RolloutScopeClusterSet {
+		graph.clusterSets, err = c.getClusterSets()
+		if err != nil {
+			return graph, err
+		}
+	}
+
 	// check each install strategy in status
 	var errs []error
 	for _, installProgression := range cma.Status.InstallProgressions {
@@ -199,3 +223,32 @@ func (c *addonConfigurationController) buildConfigurationGraph(logger klog.Logge
 
 	return graph, utilerrors.NewAggregate(errs)
 }
+
+// getClusterSets returns the ManagedClusterSets of all the clusters by their exclusive clusterset label.
+func (c *addonConfigurationController) getClusterSets() (map[string]string, error) {
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	clusterSets := map[string]string{}
+	for _, cluster := range clusters {
+		clusterSets[cluster.Name] = cluster.Labels[clusterv1beta2.ClusterSetLabel]
+	}
+	return clusterSets, nil
+}
+
+// clusterSetScopedAddonQueueKeys returns the keys of the addons whose rollouts are scoped by ManagedClusterSet,
+// a change of any cluster may move it to another ManagedClusterSet.
+func (c *addonConfigurationController) clusterSetScopedAddonQueueKeys(_ runtime.Object) []string {
+	cmas, err := c.clusterManagementAddonLister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	for _, cma := range cmas {
+		if cma.Annotations[RolloutScopeAnnotationKey] == RolloutScopeClusterSet {
+			keys = append(keys, cma.Name)
+		}
+	}
+	return keys
+}
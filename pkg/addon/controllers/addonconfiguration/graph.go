@@ -7,11 +7,13 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clustersdkv1alpha1 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1alpha1"
 	clustersdkv1beta1 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta1"
 
@@ -26,6 +28,9 @@ type configurationGraph struct {
 	nodes []*installStrategyNode
 	// defaults is the nodes with no install strategy
 	defaults *installStrategyNode
+	// clusterSets maps the cluster names to their ManagedClusterSets, it is only set when the progressive
+	// rollouts are scoped by ManagedClusterSet.
+	clusterSets map[string]string
 }
 
 // installStrategyNode is a node in configurationGraph defined by a install strategy
@@ -38,6 +43,9 @@ type installStrategyNode struct {
 	// children keeps a map of addons node as the children of this node
 	children map[string]*addonNode
 	clusters sets.Set[string]
+	// clusterSetTrackers keeps a decision tracker for each ManagedClusterSet when the progressive rollout is
+	// scoped by ManagedClusterSet, the MaxConcurrency, MaxFailures and MinSuccessTime apply within each of them.
+	clusterSetTrackers map[string]*clustersdkv1beta1.PlacementDecisionClustersTracker
 }
 
 // addonNode is node as a child of installStrategy node represting a mca
@@ -207,6 +215,13 @@ func (g *configurationGraph) addPlacementNode(
 		}
 
 		node.rolloutStrategy.Progressive = progressiveStrategy
+
+		if g.clusterSets != nil {
+			node.clusterSetTrackers, err = g.newClusterSetTrackers(placement, placementDecisionGetter, clusters)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// overrides configuration by install strategy
@@ -241,6 +256,34 @@ func (g *configurationGraph) addPlacementNode(
 	return nil
 }
 
+// newClusterSetTrackers partitions the decisions of the placement by the ManagedClusterSets of the clusters, the
+// clusters not in any ManagedClusterSet are tracked together.
+func (g *configurationGraph) newClusterSetTrackers(
+	placement *clusterv1beta1.Placement,
+	placementDecisionGetter helpers.PlacementDecisionGetter,
+	clusters sets.Set[string],
+) (map[string]*clustersdkv1beta1.PlacementDecisionClustersTracker, error) {
+	clusterSetClusters := map[string]sets.Set[string]{}
+	for cluster := range clusters {
+		clusterSet := g.clusterSets[cluster]
+		if _, ok := clusterSetClusters[clusterSet]; !ok {
+			clusterSetClusters[clusterSet] = sets.New[string]()
+		}
+		clusterSetClusters[clusterSet].Insert(cluster)
+	}
+
+	trackers := map[string]*clustersdkv1beta1.PlacementDecisionClustersTracker{}
+	for clusterSet, members := range clusterSetClusters {
+		tracker := clustersdkv1beta1.NewPlacementDecisionClustersTracker(
+			placement, clusterSetDecisionGetter{getter: placementDecisionGetter, clusters: members}, nil)
+		if err := tracker.Refresh(); err != nil {
+			return nil, err
+		}
+		trackers[clusterSet] = tracker
+	}
+	return trackers, nil
+}
+
 func (g *configurationGraph) generateRolloutResult() error {
 	for _, node := range g.nodes {
 		if err := node.generateRolloutResult(); err != nil {
@@ -335,36 +378,93 @@ func (n *installStrategyNode) generateRolloutResult() error {
 			}
 		}
 		n.rolloutResult = rolloutResult
+	} else if n.clusterSetTrackers != nil {
+		// placement addons rolled out per clusterset
+		var results []clustersdkv1alpha1.RolloutResult
+		for _, tracker := range n.clusterSetTrackers {
+			rolloutResult, err := n.getRolloutResult(tracker, tracker.ExistingClusterGroupsBesides().GetClusters())
+			if err != nil {
+				return err
+			}
+			results = append(results, rolloutResult)
+		}
+		n.rolloutResult = mergeRolloutResults(results)
 	} else {
 		// placement addons
-		rolloutHandler, err := clustersdkv1alpha1.NewRolloutHandler(n.pdTracker, getClusterRolloutStatus)
+		rolloutResult, err := n.getRolloutResult(n.pdTracker, nil)
 		if err != nil {
 			return err
 		}
+		n.rolloutResult = rolloutResult
+	}
 
-		// get existing addons
-		existingRolloutClusters := []clustersdkv1alpha1.ClusterRolloutStatus{}
-		for name, addon := range n.children {
-			clsRolloutStatus, err := getClusterRolloutStatus(name, addon)
-			if err != nil {
-				return err
-			}
-			existingRolloutClusters = append(existingRolloutClusters, clsRolloutStatus)
-		}
+	return nil
+}
 
-		// sort by cluster name
-		sort.SliceStable(existingRolloutClusters, func(i, j int) bool {
-			return existingRolloutClusters[i].ClusterName < existingRolloutClusters[j].ClusterName
-		})
+// getRolloutResult returns the rollout result of the addons tracked by the tracker. If clusters is not nil, only
+// the addons on these clusters are taken into account.
+func (n *installStrategyNode) getRolloutResult(
+	tracker *clustersdkv1beta1.PlacementDecisionClustersTracker,
+	clusters sets.Set[string],
+) (clustersdkv1alpha1.RolloutResult, error) {
+	rolloutHandler, err := clustersdkv1alpha1.NewRolloutHandler(tracker, getClusterRolloutStatus)
+	if err != nil {
+		return clustersdkv1alpha1.RolloutResult{}, err
+	}
 
-		_, rolloutResult, err := rolloutHandler.GetRolloutCluster(n.rolloutStrategy, existingRolloutClusters)
+	// get existing addons
+	existingRolloutClusters := []clustersdkv1alpha1.ClusterRolloutStatus{}
+	for name, addon := range n.children {
+		if clusters != nil && !clusters.Has(name) {
+			continue
+		}
+		clsRolloutStatus, err := getClusterRolloutStatus(name, addon)
 		if err != nil {
-			return err
+			return clustersdkv1alpha1.RolloutResult{}, err
 		}
-		n.rolloutResult = rolloutResult
+		existingRolloutClusters = append(existingRolloutClusters, clsRolloutStatus)
 	}
 
-	return nil
+	// sort by cluster name
+	sort.SliceStable(existingRolloutClusters, func(i, j int) bool {
+		return existingRolloutClusters[i].ClusterName < existingRolloutClusters[j].ClusterName
+	})
+
+	_, rolloutResult, err := rolloutHandler.GetRolloutCluster(n.rolloutStrategy, existingRolloutClusters)
+	return rolloutResult, err
+}
+
+// mergeRolloutResults merges the rollout results of the clustersets. Once the MaxFailures is breached in any
+// clusterset, the rollout does not start on any more clusters in the others either.
+func mergeRolloutResults(results []clustersdkv1alpha1.RolloutResult) clustersdkv1alpha1.RolloutResult {
+	merged := clustersdkv1alpha1.RolloutResult{}
+	for _, result := range results {
+		merged.MaxFailureBreach = merged.MaxFailureBreach || result.MaxFailureBreach
+	}
+
+	for _, result := range results {
+		for _, cluster := range result.ClustersToRollout {
+			if merged.MaxFailureBreach && cluster.Status == clustersdkv1alpha1.ToApply {
+				continue
+			}
+			merged.ClustersToRollout = append(merged.ClustersToRollout, cluster)
+		}
+		merged.ClustersTimeOut = append(merged.ClustersTimeOut, result.ClustersTimeOut...)
+		merged.ClustersRemoved = append(merged.ClustersRemoved, result.ClustersRemoved...)
+		if result.RecheckAfter != nil && (merged.RecheckAfter == nil || *result.RecheckAfter < *merged.RecheckAfter) {
+			merged.RecheckAfter = result.RecheckAfter
+		}
+	}
+
+	sortByClusterName := func(statuses []clustersdkv1alpha1.ClusterRolloutStatus) {
+		sort.SliceStable(statuses, func(i, j int) bool {
+			return statuses[i].ClusterName < statuses[j].ClusterName
+		})
+	}
+	sortByClusterName(merged.ClustersToRollout)
+	sortByClusterName(merged.ClustersTimeOut)
+	sortByClusterName(merged.ClustersRemoved)
+	return merged
 }
 
 // addonToUpdate finds the addons to be updated by placement
@@ -432,6 +532,33 @@ func (n *installStrategyNode) countAddonTimeOut() int {
 	return len(n.rolloutResult.ClustersTimeOut)
 }
 
+// clusterSetDecisionGetter only returns the decisions of the clusters in a ManagedClusterSet.
+type clusterSetDecisionGetter struct {
+	getter   helpers.PlacementDecisionGetter
+	clusters sets.Set[string]
+}
+
+func (g clusterSetDecisionGetter) List(selector labels.Selector, namespace string) ([]*clusterv1beta1.PlacementDecision, error) {
+	decisions, err := g.getter.List(selector, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []*clusterv1beta1.PlacementDecision
+	for _, decision := range decisions {
+		decision = decision.DeepCopy()
+		var clusterDecisions []clusterv1beta1.ClusterDecision
+		for _, clusterDecision := range decision.Status.Decisions {
+			if g.clusters.Has(clusterDecision.ClusterName) {
+				clusterDecisions = append(clusterDecisions, clusterDecision)
+			}
+		}
+		decision.Status.Decisions = clusterDecisions
+		filtered = append(filtered, decision)
+	}
+	return filtered, nil
+}
+
 func getClusterRolloutStatus(clusterName string, addonNode *addonNode) (clustersdkv1alpha1.ClusterRolloutStatus, error) {
 	if addonNode.status == nil {
 		return clustersdkv1alpha1.ClusterRolloutStatus{}, fmt.Errorf("failed to get rollout status on cluster %v", clusterName)
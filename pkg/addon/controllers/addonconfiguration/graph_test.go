@@ -6,6 +6,7 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"open-cluster-management.io/addon-framework/pkg/addonmanager/addontesting"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
@@ -711,3 +712,117 @@ func newDefaultConfigReference(group, resource, name, hash string) addonv1alpha1
 		},
 	}
 }
+
+func TestConfigurationGraphRolloutByClusterSet(t *testing.T) {
+	configRef := newInstallConfigReference("core", "Foo", "test", "<core-foo-test-hash>")
+	failedAddon := addontesting.NewAddon("test", "cluster1")
+	failedAddon.Status.ConfigReferences = []addonv1alpha1.ConfigReference{
+		{
+			ConfigGroupResource: configRef.ConfigGroupResource,
+			ConfigReferent:      configRef.DesiredConfig.ConfigReferent,
+			DesiredConfig:       configRef.DesiredConfig.DeepCopy(),
+		},
+	}
+	failedAddon.Status.Conditions = []metav1.Condition{
+		{
+			Type:               addonv1alpha1.ManagedClusterAddOnConditionProgressing,
+			Reason:             addonv1alpha1.ProgressingReasonFailed,
+			LastTransitionTime: fakeTime,
+		},
+	}
+
+	clusterSets := map[string]string{
+		"cluster1": "set-a",
+		"cluster2": "set-a",
+		"cluster3": "set-b",
+		"cluster4": "set-b",
+	}
+	decisions := []clusterv1beta1.ClusterDecision{
+		{ClusterName: "cluster1"}, {ClusterName: "cluster2"}, {ClusterName: "cluster3"}, {ClusterName: "cluster4"},
+	}
+	placementStrategy := addonv1alpha1.PlacementStrategy{
+		PlacementRef: addonv1alpha1.PlacementRef{Name: "placement", Namespace: "test"},
+		RolloutStrategy: clusterv1alpha1.RolloutStrategy{
+			Type: clusterv1alpha1.Progressive,
+			Progressive: &clusterv1alpha1.RolloutProgressive{
+				MaxConcurrency: intstr.FromInt32(1),
+			},
+		},
+	}
+	installProgression := addonv1alpha1.InstallProgression{
+		PlacementRef:     placementStrategy.PlacementRef,
+		ConfigReferences: []addonv1alpha1.InstallConfigReference{configRef},
+	}
+
+	cases := []struct {
+		name             string
+		addons           []*addonv1alpha1.ManagedClusterAddOn
+		expectedClusters []string
+	}{
+		{
+			name: "max concurrency applies in each clusterset",
+			addons: []*addonv1alpha1.ManagedClusterAddOn{
+				addontesting.NewAddon("test", "cluster1"),
+				addontesting.NewAddon("test", "cluster2"),
+				addontesting.NewAddon("test", "cluster3"),
+				addontesting.NewAddon("test", "cluster4"),
+			},
+			expectedClusters: []string{"cluster1", "cluster3"},
+		},
+		{
+			name: "failures in a clusterset stop the rollout in the others",
+			addons: []*addonv1alpha1.ManagedClusterAddOn{
+				failedAddon,
+				addontesting.NewAddon("test", "cluster2"),
+				addontesting.NewAddon("test", "cluster3"),
+				addontesting.NewAddon("test", "cluster4"),
+			},
+			expectedClusters: []string{"cluster1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClusterClient := fakecluster.NewSimpleClientset()
+			clusterInformers := clusterv1informers.NewSharedInformerFactory(fakeClusterClient, 10*time.Minute)
+			placementDecisionGetter := helpers.PlacementDecisionGetter{Client: clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister()}
+			placementLister := clusterInformers.Cluster().V1beta1().Placements().Lister()
+
+			placement := &clusterv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{Name: "placement", Namespace: "test"}}
+			if err := clusterInformers.Cluster().V1beta1().Placements().Informer().GetStore().Add(placement); err != nil {
+				t.Fatal(err)
+			}
+			decision := &clusterv1beta1.PlacementDecision{
+				ObjectMeta: metav1.ObjectMeta{Name: "placement", Namespace: "test",
+					Labels: map[string]string{
+						clusterv1beta1.PlacementLabel:          "placement",
+						clusterv1beta1.DecisionGroupIndexLabel: "0",
+					}},
+				Status: clusterv1beta1.PlacementDecisionStatus{Decisions: decisions},
+			}
+			if err := clusterInformers.Cluster().V1beta1().PlacementDecisions().Informer().GetStore().Add(decision); err != nil {
+				t.Fatal(err)
+			}
+
+			graph := newGraph(nil, nil)
+			graph.clusterSets = clusterSets
+			for _, addon := range c.addons {
+				graph.addAddonNode(addon)
+			}
+			if err := graph.addPlacementNode(placementStrategy, installProgression, placementLister, placementDecisionGetter); err != nil {
+				t.Fatal(err)
+			}
+			if err := graph.generateRolloutResult(); err != nil {
+				t.Fatalf("expected no error when refresh rollout result: %v", err)
+			}
+
+			var actual []string
+			for _, addon := range graph.getAddonsToUpdate() {
+				actual = append(actual, addon.mca.Namespace)
+			}
+			if !reflect.DeepEqual(actual, c.expectedClusters) {
+				t.Errorf("expected addons on clusters %v to update, but got %v", c.expectedClusters, actual)
+			}
+		})
+	}
+}
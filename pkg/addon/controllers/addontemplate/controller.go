@@ -2,17 +2,27 @@ package addontemplate
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -33,12 +43,21 @@ import (
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
+var crdGVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
 // addonTemplateController monitors ManagedClusterAddOns on hub to get all the in-used addon templates,
 // and starts an addon manager for every addon template to handle agent requests deployed by this template
 type addonTemplateController struct {
 	// addonManagers holds all addon managers that will be deployed with template type addons.
 	// The key is the name of the template type addon.
 	addonManagers map[string]context.CancelFunc
+	// addonConfigGVRs holds the GVRs of the custom configs the addon managers are started with.
+	// The key is the name of the template type addon.
+	addonConfigGVRs map[string][]schema.GroupVersionResource
 
 	kubeConfig        *rest.Config
 	addonClient       addonv1alpha1client.Interface
@@ -49,10 +68,11 @@ type addonTemplateController struct {
 	clusterInformers  clusterv1informers.SharedInformerFactory
 	dynamicInformers  dynamicinformer.DynamicSharedInformerFactory
 	workInformers     workv1informers.SharedInformerFactory
+	restMapper        meta.RESTMapper
 	runControllerFunc runController
 }
 
-type runController func(ctx context.Context, addonName string) error
+type runController func(ctx context.Context, addonName string, configGVRs []schema.GroupVersionResource) error
 
 // NewAddonTemplateController returns an instance of addonTemplateController
 func NewAddonTemplateController(
@@ -74,10 +94,12 @@ func NewAddonTemplateController(
 		workClient:       workClient,
 		cmaLister:        addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Lister(),
 		addonManagers:    make(map[string]context.CancelFunc),
+		addonConfigGVRs:  make(map[string][]schema.GroupVersionResource),
 		addonInformers:   addonInformers,
 		clusterInformers: clusterInformers,
 		dynamicInformers: dynamicInformers,
 		workInformers:    workInformers,
+		restMapper:       restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(hubKubeClient.Discovery())),
 	}
 
 	if len(runController) > 0 {
@@ -89,6 +111,7 @@ func NewAddonTemplateController(
 	return factory.New().WithInformersQueueKeysFunc(
 		queue.QueueKeyByMetaNamespaceName,
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer()).
+		WithInformersQueueKeysFunc(c.crdQueueKeys, dynamicInformers.ForResource(crdGVR).Informer()).
 		WithSync(c.sync).
 		ToController("addon-template-controller", recorder)
 }
@@ -100,6 +123,7 @@ func (c *addonTemplateController) stopUnusedManagers(
 	if ok {
 		stopFunc()
 		delete(c.addonManagers, addOnName)
+		delete(c.addonConfigGVRs, addOnName)
 		klog.FromContext(ctx).Info("Stopping the manager for addon", "addonName", addOnName)
 	}
 }
@@ -127,26 +151,67 @@ func (c *addonTemplateController) sync(ctx context.Context, syncCtx factory.Sync
 		return nil
 	}
 
-	_, exist := c.addonManagers[addonName]
-	if exist {
-		logger.Info("There already is a manager started for addon, skipping", "addonName", addonName)
-		return nil
+	configGVRs := c.configGVRs(ctx, addonName)
+	if _, exist := c.addonManagers[addonName]; exist {
+		if reflect.DeepEqual(configGVRs, c.addonConfigGVRs[addonName]) {
+			logger.Info("There already is a manager started for addon, skipping", "addonName", addonName)
+			return nil
+		}
+		// restart the manager so the informers of the configs are started with the resolved versions.
+		logger.Info("The configs of addon are changed, restarting the manager", "addonName", addonName)
+		c.stopUnusedManagers(ctx, syncCtx, addonName)
 	}
 
 	logger.Info("Starting an addon manager for addon", "addonName", addonName)
 
-	stopFunc := c.startManager(ctx, addonName)
+	stopFunc := c.startManager(ctx, addonName, configGVRs)
 	c.addonManagers[addonName] = stopFunc
+	c.addonConfigGVRs[addonName] = configGVRs
 	return nil
 }
 
+// crdQueueKeys resets the rest mapper so the versions of the configs are resolved again once a CRD is changed, and
+// returns the keys of the template type addons supporting the config defined by the CRD.
+func (c *addonTemplateController) crdQueueKeys(obj runtime.Object) []string {
+	if resettable, ok := c.restMapper.(meta.ResettableRESTMapper); ok {
+		resettable.Reset()
+	}
+
+	crd, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	resource, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+
+	cmas, err := c.cmaLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+	var keys []string
+	for _, cma := range cmas {
+		if !templateagent.SupportAddOnTemplate(cma) {
+			continue
+		}
+		for _, config := range cma.Spec.SupportedConfigs {
+			if config.Group == group && config.Resource == resource {
+				keys = append(keys, cma.Name)
+				break
+			}
+		}
+	}
+	return keys
+}
+
 func (c *addonTemplateController) startManager(
 	pctx context.Context,
-	addonName string) context.CancelFunc {
+	addonName string,
+	configGVRs []schema.GroupVersionResource) context.CancelFunc {
 	ctx, stopFunc := context.WithCancel(pctx)
 	logger := klog.FromContext(ctx)
 	go func() {
-		err := c.runControllerFunc(ctx, addonName)
+		err := c.runControllerFunc(ctx, addonName, configGVRs)
 		if err != nil {
 			logger.Error(err, "Error running controller for addon", "addonName", addonName)
 			utilruntime.HandleError(err)
@@ -166,7 +231,7 @@ func (c *addonTemplateController) startManager(
 }
 
 func (c *addonTemplateController) runController(
-	ctx context.Context, addonName string) error {
+	ctx context.Context, addonName string, configGVRs []schema.GroupVersionResource) error {
 	mgr, err := addonmanager.New(c.kubeConfig)
 	if err != nil {
 		return err
@@ -206,11 +271,37 @@ func (c *addonTemplateController) runController(
 			templateagent.ToAddOnInstallNamespacePrivateValues,
 		),
 	)
+	agentAddon.WithConfigGVRs(configGVRs...)
 	err = mgr.AddAgent(agentAddon)
 	if err != nil {
 		return err
 	}
 
+	// redeploy the agents once the ca bundle of the managed signing CAs is rotated, the ca bundles are labeled with
+	// the addon name.
+	agentAddon.WithCABundleLister(kubeInformers.Core().V1().ConfigMaps().Lister())
+	_, err = kubeInformers.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return false
+			}
+			return accessor.GetNamespace() == templateagent.AddonManagerNamespace() &&
+				accessor.GetLabels()[addonv1alpha1.AddonLabelKey] == addonName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(_ interface{}) {
+				c.triggerAddons(mgr, addonName)
+			},
+			UpdateFunc: func(_, _ interface{}) {
+				c.triggerAddons(mgr, addonName)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
 	err = mgr.StartWithInformers(ctx, c.workClient, c.workInformers.Work().V1().ManifestWorks(),
 		kubeInformers, c.addonInformers, c.clusterInformers, c.dynamicInformers)
 	if err != nil {
@@ -221,3 +312,72 @@ func (c *addonTemplateController) runController(
 
 	return nil
 }
+
+// configGVRs returns the GVRs of the configs supported by the addon other than the built-in configs. The versions of
+// the configs are resolved again once the CRDs are changed. The configs are skipped if the addon manager is not
+// granted the permission to list and watch them, otherwise the informers of the configs would never be synced.
+func (c *addonTemplateController) configGVRs(ctx context.Context, addonName string) []schema.GroupVersionResource {
+	cma, err := c.cmaLister.Get(addonName)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+
+	var gvrs []schema.GroupVersionResource
+	for _, config := range cma.Spec.SupportedConfigs {
+		if utils.ContainGR(utils.BuiltInAddOnConfigGVRs, config.Group, config.Resource) {
+			continue
+		}
+		gvr, err := c.restMapper.ResourceFor(schema.GroupVersionResource{Group: config.Group, Resource: config.Resource})
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to resolve the config of addon",
+				"addonName", addonName, "group", config.Group, "resource", config.Resource)
+			continue
+		}
+		if err := c.checkConfigAccess(ctx, gvr); err != nil {
+			klog.FromContext(ctx).Error(err, "The config of addon is not accessible",
+				"addonName", addonName, "group", config.Group, "resource", config.Resource)
+			continue
+		}
+		gvrs = append(gvrs, gvr)
+	}
+	return gvrs
+}
+
+// checkConfigAccess checks if the addon manager is allowed to list and watch the config.
+func (c *addonTemplateController) checkConfigAccess(ctx context.Context, gvr schema.GroupVersionResource) error {
+	for _, verb := range []string{"list", "watch"} {
+		review, err := c.kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx,
+			&authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Group:    gvr.Group,
+						Version:  gvr.Version,
+						Resource: gvr.Resource,
+						Verb:     verb,
+					},
+				},
+			}, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		if !review.Status.Allowed {
+			return fmt.Errorf("the addon manager is not allowed to %s %s", verb, gvr.GroupResource())
+		}
+	}
+	return nil
+}
+
+// triggerAddons triggers the addon manager to redeploy the agents of the addon on all the clusters.
+func (c *addonTemplateController) triggerAddons(mgr addonmanager.AddonManager, addonName string) {
+	addons, err := c.addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister().List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, addon := range addons {
+		if addon.Name == addonName {
+			mgr.Trigger(addon.Namespace, addonName)
+		}
+	}
+}
@@ -2,17 +2,25 @@ package addontemplate
 
 import (
 	"context"
+	"reflect"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
 	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	fakekube "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
 
 	"open-cluster-management.io/addon-framework/pkg/addonmanager/addontesting"
 	"open-cluster-management.io/addon-framework/pkg/utils"
@@ -140,7 +148,7 @@ func TestReconcile(t *testing.T) {
 		for range c.syncKeys {
 			wg.Add(1)
 		}
-		runController := func(ctx context.Context, addonName string) error {
+		runController := func(ctx context.Context, addonName string, configGVRs []schema.GroupVersionResource) error {
 			defer wg.Done()
 			increaseCount()
 			return nil
@@ -161,7 +169,8 @@ func TestReconcile(t *testing.T) {
 			}
 		}
 
-		fakeDynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		fakeDynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"})
 		dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(fakeDynamicClient, 0)
 
 		fakeClusterClient := fakecluster.NewSimpleClientset()
@@ -260,10 +269,171 @@ func TestRunController(t *testing.T) {
 		}
 		ctx := context.TODO()
 
-		err := controller.runController(ctx, c.addonName)
+		err := controller.runController(ctx, c.addonName, nil)
 		if len(c.expectedErr) == 0 {
 			assert.NoError(t, err)
 		}
 		assert.EqualErrorf(t, err, c.expectedErr, "name : %s, expected error %v, but got %v", c.name, c.expectedErr, err)
 	}
 }
+
+func TestConfigGVRs(t *testing.T) {
+	fooGVR := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}
+	bazGVR := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "bazs"}
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(fooGVR.GroupVersion().WithKind("Foo"), meta.RESTScopeNamespace)
+	restMapper.Add(bazGVR.GroupVersion().WithKind("Baz"), meta.RESTScopeNamespace)
+
+	cma := addontesting.NewClusterManagementAddon("test", "", "").Build()
+	cma.Spec.SupportedConfigs = []addonv1alpha1.ConfigMeta{
+		{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{
+			Group: utils.AddOnDeploymentConfigGVR.Group, Resource: utils.AddOnDeploymentConfigGVR.Resource}},
+		{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "foos"}},
+		{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "bars"}},
+		{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "bazs"}},
+	}
+	addonInformers := addoninformers.NewSharedInformerFactory(fakeaddon.NewSimpleClientset(cma), 10*time.Minute)
+	if err := addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer().GetStore().Add(cma); err != nil {
+		t.Fatal(err)
+	}
+
+	// the addon manager is not allowed to watch bazs
+	kubeClient := fakekube.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "bazs"
+			return true, review, nil
+		})
+
+	controller := &addonTemplateController{
+		kubeClient: kubeClient,
+		cmaLister:  addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Lister(),
+		restMapper: restMapper,
+	}
+	gvrs := controller.configGVRs(context.TODO(), "test")
+	if !reflect.DeepEqual(gvrs, []schema.GroupVersionResource{fooGVR}) {
+		t.Errorf("expected only the accessible custom config resolved, but got %v", gvrs)
+	}
+}
+
+func TestCRDQueueKeys(t *testing.T) {
+	newCMA := func(name string, configs ...addonv1alpha1.ConfigGroupResource) *addonv1alpha1.ClusterManagementAddOn {
+		cma := addontesting.NewClusterManagementAddon(name, "", "").Build()
+		cma.Spec.SupportedConfigs = []addonv1alpha1.ConfigMeta{
+			{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{
+				Group: utils.AddOnTemplateGVR.Group, Resource: utils.AddOnTemplateGVR.Resource}},
+		}
+		for _, config := range configs {
+			cma.Spec.SupportedConfigs = append(cma.Spec.SupportedConfigs, addonv1alpha1.ConfigMeta{ConfigGroupResource: config})
+		}
+		return cma
+	}
+	foos := addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "foos"}
+	bars := addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "bars"}
+
+	addonInformers := addoninformers.NewSharedInformerFactory(fakeaddon.NewSimpleClientset(), 10*time.Minute)
+	for _, cma := range []*addonv1alpha1.ClusterManagementAddOn{
+		newCMA("test1", foos), newCMA("test2", bars), newCMA("test3", foos, bars),
+	} {
+		if err := addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer().GetStore().Add(cma); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	controller := &addonTemplateController{
+		cmaLister:  addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Lister(),
+		restMapper: meta.NewDefaultRESTMapper(nil),
+	}
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "foos.example.com"},
+		"spec": map[string]interface{}{
+			"group": "example.com",
+			"names": map[string]interface{}{"plural": "foos"},
+		},
+	}}
+	keys := controller.crdQueueKeys(crd)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"test1", "test3"}) {
+		t.Errorf("expected the addons supporting foos are enqueued, but got %v", keys)
+	}
+}
+
+func TestRestartManagerOnConfigChange(t *testing.T) {
+	fooGVR := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}
+	cma := addontesting.NewClusterManagementAddon("test", "", "").Build()
+	cma.Spec.SupportedConfigs = []addonv1alpha1.ConfigMeta{
+		{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{
+			Group: utils.AddOnTemplateGVR.Group, Resource: utils.AddOnTemplateGVR.Resource}},
+		{ConfigGroupResource: addonv1alpha1.ConfigGroupResource{Group: "example.com", Resource: "foos"}},
+	}
+	addonInformers := addoninformers.NewSharedInformerFactory(fakeaddon.NewSimpleClientset(cma), 10*time.Minute)
+	if err := addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Informer().GetStore().Add(cma); err != nil {
+		t.Fatal(err)
+	}
+
+	var lock sync.Mutex
+	var started [][]schema.GroupVersionResource
+	stopped := 0
+	controller := &addonTemplateController{
+		kubeClient:       fakekube.NewSimpleClientset(),
+		cmaLister:        addonInformers.Addon().V1alpha1().ClusterManagementAddOns().Lister(),
+		addonManagers:    make(map[string]context.CancelFunc),
+		addonConfigGVRs:  make(map[string][]schema.GroupVersionResource),
+		addonInformers:   addonInformers,
+		clusterInformers: clusterv1informers.NewSharedInformerFactory(fakecluster.NewSimpleClientset(), 10*time.Minute),
+		dynamicInformers: dynamicinformer.NewDynamicSharedInformerFactory(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), 0),
+		workInformers:    workinformers.NewSharedInformerFactory(fakework.NewSimpleClientset(), 10*time.Minute),
+		restMapper:       meta.NewDefaultRESTMapper(nil),
+		runControllerFunc: func(ctx context.Context, addonName string, configGVRs []schema.GroupVersionResource) error {
+			lock.Lock()
+			defer lock.Unlock()
+			started = append(started, configGVRs)
+			go func() {
+				<-ctx.Done()
+				lock.Lock()
+				defer lock.Unlock()
+				stopped++
+			}()
+			return nil
+		},
+	}
+	controller.kubeClient.(*fakekube.Clientset).PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &authorizationv1.SelfSubjectAccessReview{
+				Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true},
+			}, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	sync := func() {
+		if err := controller.sync(ctx, testingcommon.NewFakeSyncContext(t, "test")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the crd of foos is not installed
+	sync()
+	sync()
+
+	// the crd of foos is installed
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(fooGVR.GroupVersion().WithKind("Foo"), meta.RESTScopeNamespace)
+	controller.restMapper = restMapper
+	sync()
+
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(started) == 2 && stopped == 1, nil
+		}); err != nil {
+		t.Fatalf("expected the manager is restarted once, but started %v and stopped %d times", started, stopped)
+	}
+	if !reflect.DeepEqual(controller.addonConfigGVRs["test"], []schema.GroupVersionResource{fooGVR}) {
+		t.Errorf("expected the manager is restarted with the resolved configs, but got %v", started)
+	}
+}
@@ -0,0 +1,178 @@
+package addontemplate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/addon-framework/pkg/index"
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	worklister "open-cluster-management.io/api/client/work/listers/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+// healthConditionController sets the conditions other than Available, e.g. Degraded, of the template type addons
+// with the health probes of the addon templates. The Available condition is set by the addon framework.
+type healthConditionController struct {
+	addonClient         addonv1alpha1client.Interface
+	addonLister         addonlisterv1alpha1.ManagedClusterAddOnLister
+	addonIndexer        cache.Indexer
+	addonTemplateLister addonlisterv1alpha1.AddOnTemplateLister
+	workLister          worklister.ManifestWorkLister
+}
+
+// NewHealthConditionController returns an instance of healthConditionController. The addonInformer must have the
+// index.AddonByConfig indexer.
+func NewHealthConditionController(
+	addonClient addonv1alpha1client.Interface,
+	addonInformer addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	addonTemplateInformer addoninformerv1alpha1.AddOnTemplateInformer,
+	workInformer workinformers.ManifestWorkInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &healthConditionController{
+		addonClient:         addonClient,
+		addonLister:         addonInformer.Lister(),
+		addonIndexer:        addonInformer.Informer().GetIndexer(),
+		addonTemplateLister: addonTemplateInformer.Lister(),
+		workLister:          workInformer.Lister(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespaceName, addonInformer.Informer()).
+		WithInformersQueueKeysFunc(c.templateQueueKeys, addonTemplateInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			func(obj runtime.Object) []string {
+				accessor, _ := meta.Accessor(obj)
+				namespace := accessor.GetNamespace()
+				if len(accessor.GetLabels()[addonapiv1alpha1.AddonNamespaceLabelKey]) > 0 {
+					namespace = accessor.GetLabels()[addonapiv1alpha1.AddonNamespaceLabelKey]
+				}
+				return []string{fmt.Sprintf("%s/%s", namespace, accessor.GetLabels()[addonapiv1alpha1.AddonLabelKey])}
+			},
+			func(obj interface{}) bool {
+				accessor, _ := meta.Accessor(obj)
+				return len(accessor.GetLabels()[addonapiv1alpha1.AddonLabelKey]) > 0
+			},
+			workInformer.Informer()).
+		WithSync(c.sync).
+		ToController("addon-health-condition-controller", recorder)
+}
+
+// templateQueueKeys returns the keys of the addons using the template, since the health probes are defined in the
+// annotation of the template which does not change the spec hash in the status of the addons.
+func (c *healthConditionController) templateQueueKeys(obj runtime.Object) []string {
+	accessor, _ := meta.Accessor(obj)
+	objs, err := c.addonIndexer.ByIndex(index.AddonByConfig,
+		fmt.Sprintf("%s/%s/%s", utils.AddOnTemplateGVR.Group, utils.AddOnTemplateGVR.Resource, accessor.GetName()))
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, obj := range objs {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *healthConditionController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	key := syncCtx.QueueKey()
+	namespace, addonName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		// ignore addon whose key is invalid
+		return nil
+	}
+
+	addon, err := c.addonLister.ManagedClusterAddOns(namespace).Get(addonName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ok, templateRef := templateagent.AddonTemplateConfigRef(addon.Status.ConfigReferences)
+	if !ok || templateRef.DesiredConfig == nil {
+		return nil
+	}
+	template, err := c.addonTemplateLister.Get(templateRef.DesiredConfig.Name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	probes, err := templateagent.GetHealthProbes(template)
+	if err != nil {
+		// the invalid probes are reported by the addon manager of the template.
+		klog.FromContext(ctx).V(4).Info("Skip the invalid health probes", "addonTemplate", template.Name, "err", err)
+		return nil
+	}
+
+	works, err := c.addonWorks(addon)
+	if err != nil {
+		return err
+	}
+
+	conditions := templateagent.HealthConditions(probes, works)
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	newAddon := addon.DeepCopy()
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&newAddon.Status.Conditions, condition)
+	}
+	addonPatcher := patcher.NewPatcher[
+		*addonapiv1alpha1.ManagedClusterAddOn,
+		addonapiv1alpha1.ManagedClusterAddOnSpec,
+		addonapiv1alpha1.ManagedClusterAddOnStatus](
+		c.addonClient.AddonV1alpha1().ManagedClusterAddOns(newAddon.Namespace))
+	_, err = addonPatcher.PatchStatus(ctx, newAddon, newAddon.Status, addon.Status)
+	return err
+}
+
+// addonWorks returns the works of the addon in the cluster namespace and in the hosting cluster namespace.
+func (c *healthConditionController) addonWorks(addon *addonapiv1alpha1.ManagedClusterAddOn) ([]*workapiv1.ManifestWork, error) {
+	requirement, _ := labels.NewRequirement(addonapiv1alpha1.AddonLabelKey, selection.Equals, []string{addon.Name})
+	notHostedRequirement, _ := labels.NewRequirement(addonapiv1alpha1.AddonNamespaceLabelKey, selection.DoesNotExist, []string{})
+	works, err := c.workLister.ManifestWorks(addon.Namespace).List(labels.NewSelector().Add(*requirement, *notHostedRequirement))
+	if err != nil {
+		return nil, err
+	}
+
+	hostingClusterName := addon.Annotations[addonapiv1alpha1.HostingClusterNameAnnotationKey]
+	if len(hostingClusterName) == 0 {
+		return works, nil
+	}
+	hostedRequirement, _ := labels.NewRequirement(addonapiv1alpha1.AddonNamespaceLabelKey, selection.Equals, []string{addon.Namespace})
+	hostedWorks, err := c.workLister.ManifestWorks(hostingClusterName).List(labels.NewSelector().Add(*requirement, *hostedRequirement))
+	if err != nil {
+		return nil, err
+	}
+	return append(works, hostedWorks...), nil
+}
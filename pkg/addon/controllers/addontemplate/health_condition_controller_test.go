@@ -0,0 +1,192 @@
+package addontemplate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	"open-cluster-management.io/addon-framework/pkg/addonmanager/addontesting"
+	"open-cluster-management.io/addon-framework/pkg/index"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	fakework "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestHealthConditionReconcile(t *testing.T) {
+	identifier := workapiv1.ResourceIdentifier{Group: "example.com", Resource: "foos", Name: "foo", Namespace: "ns1"}
+
+	newTemplate := func(probes string) *addonapiv1alpha1.AddOnTemplate {
+		template := &addonapiv1alpha1.AddOnTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "template1"},
+			Spec:       addonapiv1alpha1.AddOnTemplateSpec{AddonName: "test"},
+		}
+		if len(probes) > 0 {
+			template.Annotations = map[string]string{templateagent.HealthProbesAnnotationKey: probes}
+		}
+		return template
+	}
+	degradedProbes := `[{"resourceIdentifier":{"group":"example.com","resource":"foos","name":"foo","namespace":"ns1"},
+"jsonPaths":[{"name":"failed","path":".status.failed"}],"conditionType":"Degraded","expression":"values.failed > 0",
+"message":"foo has failed replicas"}]`
+
+	newAddon := func() *addonapiv1alpha1.ManagedClusterAddOn {
+		addon := addontesting.NewAddon("test", "cluster1")
+		addon.Status.ConfigReferences = []addonapiv1alpha1.ConfigReference{
+			{
+				ConfigGroupResource: addonapiv1alpha1.ConfigGroupResource{
+					Group:    "addon.open-cluster-management.io",
+					Resource: "addontemplates",
+				},
+				ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "template1"},
+				DesiredConfig: &addonapiv1alpha1.ConfigSpecHash{
+					ConfigReferent: addonapiv1alpha1.ConfigReferent{Name: "template1"},
+					SpecHash:       "hash",
+				},
+			},
+		}
+		return addon
+	}
+
+	newWork := func(failed int64) *workapiv1.ManifestWork {
+		work := addontesting.NewManifestWork("addon-test-deploy-0", "cluster1")
+		work.Labels = map[string]string{addonapiv1alpha1.AddonLabelKey: "test"}
+		work.Status.ResourceStatus.Manifests = []workapiv1.ManifestCondition{
+			{
+				ResourceMeta: workapiv1.ManifestResourceMeta{
+					Group:     identifier.Group,
+					Resource:  identifier.Resource,
+					Name:      identifier.Name,
+					Namespace: identifier.Namespace,
+				},
+				StatusFeedbacks: workapiv1.StatusFeedbackResult{
+					Values: []workapiv1.FeedbackValue{
+						{Name: "failed", Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(failed)}},
+					},
+				},
+			},
+		}
+		return work
+	}
+
+	assertCondition := func(status metav1.ConditionStatus, reason string) func(t *testing.T, actions []clienttesting.Action) {
+		return func(t *testing.T, actions []clienttesting.Action) {
+			testingcommon.AssertActions(t, actions, "patch")
+			addon := &addonapiv1alpha1.ManagedClusterAddOn{}
+			if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, addon); err != nil {
+				t.Fatal(err)
+			}
+			cond := meta.FindStatusCondition(addon.Status.Conditions, "Degraded")
+			if cond == nil || cond.Status != status || cond.Reason != reason {
+				t.Errorf("expected the Degraded condition %s with reason %s, but got %v", status, reason, cond)
+			}
+		}
+	}
+
+	cases := []struct {
+		name            string
+		syncKey         string
+		addons          []runtime.Object
+		templates       []runtime.Object
+		works           []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:            "no addon",
+			syncKey:         "cluster1/test",
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "no health probes",
+			syncKey:         "cluster1/test",
+			addons:          []runtime.Object{newAddon()},
+			templates:       []runtime.Object{newTemplate("")},
+			works:           []runtime.Object{newWork(1)},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "degraded",
+			syncKey:         "cluster1/test",
+			addons:          []runtime.Object{newAddon()},
+			templates:       []runtime.Object{newTemplate(degradedProbes)},
+			works:           []runtime.Object{newWork(1)},
+			validateActions: assertCondition(metav1.ConditionTrue, "HealthProbeTrue"),
+		},
+		{
+			name:            "not degraded",
+			syncKey:         "cluster1/test",
+			addons:          []runtime.Object{newAddon()},
+			templates:       []runtime.Object{newTemplate(degradedProbes)},
+			works:           []runtime.Object{newWork(0)},
+			validateActions: assertCondition(metav1.ConditionFalse, "HealthProbeFalse"),
+		},
+		{
+			name:            "no status feedback",
+			syncKey:         "cluster1/test",
+			addons:          []runtime.Object{newAddon()},
+			templates:       []runtime.Object{newTemplate(degradedProbes)},
+			validateActions: assertCondition(metav1.ConditionUnknown, "HealthProbeUnknown"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			objs := append(append([]runtime.Object{}, c.addons...), c.templates...)
+			addonClient := fakeaddon.NewSimpleClientset(objs...)
+			addonInformers := addoninformers.NewSharedInformerFactory(addonClient, 10*time.Minute)
+			workClient := fakework.NewSimpleClientset(c.works...)
+			workInformers := workinformers.NewSharedInformerFactory(workClient, 10*time.Minute)
+			if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().AddIndexers(
+				cache.Indexers{index.AddonByConfig: index.IndexAddonByConfig}); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, obj := range c.addons {
+				if err := addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, obj := range c.templates {
+				if err := addonInformers.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, obj := range c.works {
+				if err := workInformers.Work().V1().ManifestWorks().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &healthConditionController{
+				addonClient:         addonClient,
+				addonLister:         addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+				addonIndexer:        addonInformers.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetIndexer(),
+				addonTemplateLister: addonInformers.Addon().V1alpha1().AddOnTemplates().Lister(),
+				workLister:          workInformers.Work().V1().ManifestWorks().Lister(),
+			}
+
+			if keys := ctrl.templateQueueKeys(newTemplate("")); len(c.addons) > 0 && (len(keys) != 1 || keys[0] != "cluster1/test") {
+				t.Errorf("expected the addon is enqueued by the template, but got %v", keys)
+			}
+
+			addonClient.ClearActions()
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, c.syncKey)); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			c.validateActions(t, addonClient.Actions())
+		})
+	}
+}
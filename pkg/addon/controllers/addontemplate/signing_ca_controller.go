@@ -0,0 +1,287 @@
+package addontemplate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	"open-cluster-management.io/sdk-go/pkg/certrotation"
+
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+// Follow the rules below to set the value of SigningCAValidity/SigningCAResyncInterval:
+//
+// SigningCAValidity * 1/10 > SigningCAResyncInterval * 2
+var SigningCAValidity = time.Hour * 24 * 365
+var SigningCAResyncInterval = time.Minute * 10
+
+const (
+	// stageRatio is the ratio of the validity of the current signing CA after which the next signing CA is created
+	// and added to the ca bundle.
+	stageRatio = 0.6
+	// promoteRatio is the ratio of the validity of the current signing CA after which the staged next signing CA
+	// replaces it. It is less than the 80% after which the current signing CA would be replaced without staging.
+	promoteRatio = 0.7
+)
+
+// signingCARotationController maintains the signing CAs of the custom signer registrations of the addon templates
+// annotated with templateagent.ManagedSigningCAAnnotationKey. The rollover of a signing CA is staged: once 60% of
+// the validity of the current CA has passed, the next CA is created in the secret with the "-next" suffix and added
+// to the ca bundle, and it replaces the current CA once 70% has passed. So the agents trust the next CA for at least
+// 10% of the validity before it signs any certificate. All the unexpired CAs are kept in the ca bundle.
+type signingCARotationController struct {
+	kubeClient          kubernetes.Interface
+	addonTemplateLister addonlisterv1alpha1.AddOnTemplateLister
+	secretLister        corev1listers.SecretLister
+	configMapLister     corev1listers.ConfigMapLister
+	recorder            events.Recorder
+}
+
+// NewSigningCARotationController returns an instance of signingCARotationController. The secretInformer and the
+// configMapInformer must watch the namespace of the addon manager.
+func NewSigningCARotationController(
+	hubKubeClient kubernetes.Interface,
+	addonTemplateInformer addoninformerv1alpha1.AddOnTemplateInformer,
+	secretInformer corev1informers.SecretInformer,
+	configMapInformer corev1informers.ConfigMapInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &signingCARotationController{
+		kubeClient:          hubKubeClient,
+		addonTemplateLister: addonTemplateInformer.Lister(),
+		secretLister:        secretInformer.Lister(),
+		configMapLister:     configMapInformer.Lister(),
+		recorder:            recorder,
+	}
+
+	return factory.New().
+		ResyncEvery(SigningCAResyncInterval).
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, addonTemplateInformer.Informer()).
+		WithBareInformers(secretInformer.Informer(), configMapInformer.Informer()).
+		WithSync(c.sync).
+		ToController("addon-signing-ca-rotation-controller", recorder)
+}
+
+func (c *signingCARotationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	key := syncCtx.QueueKey()
+	if key != factory.DefaultQueueKey {
+		template, err := c.addonTemplateLister.Get(key)
+		if errors.IsNotFound(err) {
+			// the signing CAs are kept, since they may be shared with other templates.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return c.syncTemplate(ctx, template)
+	}
+
+	// rotate the signing CAs of all the templates periodically
+	templates, err := c.addonTemplateLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, template := range templates {
+		if err := c.syncTemplate(ctx, template); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *signingCARotationController) syncTemplate(ctx context.Context, template *addonapiv1alpha1.AddOnTemplate) error {
+	if !templateagent.IsSigningCAManaged(template) || !template.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	klog.FromContext(ctx).V(4).Info("Reconciling the signing CAs of addon template", "templateName", template.Name)
+
+	var errs []error
+	for _, registration := range template.Spec.Registration {
+		if registration.Type != addonapiv1alpha1.RegistrationTypeCustomSigner || registration.CustomSigner == nil {
+			continue
+		}
+		if err := c.rotate(ctx, template.Spec.AddonName, registration.CustomSigner.SigningCA.Name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to rotate the signing CA %q of addon template %q: %w",
+				registration.CustomSigner.SigningCA.Name, template.Name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *signingCARotationController) rotate(ctx context.Context, addonName, signingCAName string) error {
+	namespace := templateagent.AddonManagerNamespace()
+	nextSigningCAName := fmt.Sprintf("%s-next", signingCAName)
+	caBundleName := templateagent.SigningCABundleName(signingCAName)
+
+	promoted, err := c.promote(ctx, namespace, signingCAName, nextSigningCAName, caBundleName)
+	if err != nil || promoted {
+		// the signing CAs are reconciled again with the updated cache.
+		return err
+	}
+
+	signingCA, err := c.signingRotation(namespace, signingCAName, addonName).EnsureSigningCertKeyPair()
+	if err != nil {
+		return err
+	}
+
+	caBundleRotation := certrotation.CABundleRotation{
+		Namespace: namespace,
+		Name:      caBundleName,
+		Lister:    c.configMapLister,
+		Client:    c.kubeClient.CoreV1(),
+	}
+	certificates, err := caBundleRotation.EnsureConfigMapCABundle(signingCA)
+	if err != nil {
+		return err
+	}
+
+	// stage the next signing CA in the ca bundle
+	if lifetimePassed(signingCA.Config.Certs[0]) >= stageRatio {
+		nextSigningCA, err := c.signingRotation(namespace, nextSigningCAName, addonName).EnsureSigningCertKeyPair()
+		if err != nil {
+			return err
+		}
+		if !containsCertificate(certificates, nextSigningCA.Config.Certs[0]) {
+			caBytes, err := crypto.EncodeCertificates(append([]*x509.Certificate{nextSigningCA.Config.Certs[0]}, certificates...)...)
+			if err != nil {
+				return err
+			}
+			if _, _, err := resourceapply.ApplyConfigMap(ctx, c.kubeClient.CoreV1(), c.recorder, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: caBundleName},
+				Data:       map[string]string{"ca-bundle.crt": string(caBytes)},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// label the ca bundle with the addon name, so the agents of the addon are redeployed once it is changed.
+	caBundle, err := c.configMapLister.ConfigMaps(namespace).Get(caBundleName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if caBundle != nil && caBundle.Labels[addonapiv1alpha1.AddonLabelKey] == addonName {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{addonapiv1alpha1.AddonLabelKey: addonName},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeClient.CoreV1().ConfigMaps(namespace).Patch(
+		ctx, caBundleName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// promote replaces the current signing CA with the staged next signing CA once promoteRatio of the validity of the
+// current one has passed, and the next one is in the ca bundle.
+func (c *signingCARotationController) promote(ctx context.Context,
+	namespace, signingCAName, nextSigningCAName, caBundleName string) (bool, error) {
+	next, err := c.secretLister.Secrets(namespace).Get(nextSigningCAName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	current, err := c.secretLister.Secrets(namespace).Get(signingCAName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	currentCerts, err := cert.ParseCertsPEM(current.Data[corev1.TLSCertKey])
+	if err != nil || len(currentCerts) == 0 {
+		// the invalid current signing CA is replaced by the signing rotation.
+		return false, nil
+	}
+	if lifetimePassed(currentCerts[0]) < promoteRatio {
+		return false, nil
+	}
+	nextCerts, err := cert.ParseCertsPEM(next.Data[corev1.TLSCertKey])
+	if err != nil || len(nextCerts) == 0 {
+		return false, nil
+	}
+	caBundle, err := c.configMapLister.ConfigMaps(namespace).Get(caBundleName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	bundleCerts, err := cert.ParseCertsPEM([]byte(caBundle.Data["ca-bundle.crt"]))
+	if err != nil || !containsCertificate(bundleCerts, nextCerts[0]) {
+		return false, nil
+	}
+
+	promoted := current.DeepCopy()
+	promoted.Data = next.DeepCopy().Data
+	if _, err := c.kubeClient.CoreV1().Secrets(namespace).Update(ctx, promoted, metav1.UpdateOptions{}); err != nil {
+		return false, err
+	}
+	if err := c.kubeClient.CoreV1().Secrets(namespace).Delete(ctx, nextSigningCAName, metav1.DeleteOptions{}); err != nil &&
+		!errors.IsNotFound(err) {
+		return false, err
+	}
+	c.recorder.Eventf("SigningCAPromoted", "The next signing CA %s/%s is promoted", namespace, signingCAName)
+	return true, nil
+}
+
+func (c *signingCARotationController) signingRotation(namespace, name, addonName string) certrotation.SigningRotation {
+	return certrotation.SigningRotation{
+		Namespace:        namespace,
+		Name:             name,
+		SignerNamePrefix: fmt.Sprintf("%s-signing-ca", addonName),
+		Validity:         SigningCAValidity,
+		Lister:           c.secretLister,
+		Client:           c.kubeClient.CoreV1(),
+	}
+}
+
+// lifetimePassed returns the ratio of the validity of the certificate which has passed.
+func lifetimePassed(certificate *x509.Certificate) float64 {
+	validity := certificate.NotAfter.Sub(certificate.NotBefore)
+	if validity <= 0 {
+		return 1
+	}
+	return float64(time.Since(certificate.NotBefore)) / float64(validity)
+}
+
+func containsCertificate(certificates []*x509.Certificate, certificate *x509.Certificate) bool {
+	for _, c := range certificates {
+		if reflect.DeepEqual(c.Raw, certificate.Raw) {
+			return true
+		}
+	}
+	return false
+}
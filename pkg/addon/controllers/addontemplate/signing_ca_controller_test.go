@@ -0,0 +1,242 @@
+package addontemplate
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/cert"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	fakeaddon "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func newSigningCATemplate(managed bool) *addonv1alpha1.AddOnTemplate {
+	template := &addonv1alpha1.AddOnTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "template1"},
+		Spec: addonv1alpha1.AddOnTemplateSpec{
+			AddonName: "addon1",
+			Registration: []addonv1alpha1.RegistrationSpec{
+				{
+					Type: addonv1alpha1.RegistrationTypeCustomSigner,
+					CustomSigner: &addonv1alpha1.CustomSignerRegistrationConfig{
+						SignerName: "example.com/signer1",
+						SigningCA:  addonv1alpha1.SigningCARef{Name: "signer1-ca"},
+					},
+				},
+			},
+		},
+	}
+	if managed {
+		template.Annotations = map[string]string{templateagent.ManagedSigningCAAnnotationKey: "true"}
+	}
+	return template
+}
+
+// newSigningCASecret returns a secret of a self-signed signing CA which is valid from notBefore to notAfter.
+func newSigningCASecret(t *testing.T, name string, notBefore, notAfter time.Time) *corev1.Secret {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: templateagent.AddonManagerNamespace()},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+	}
+}
+
+func TestSigningCARotation(t *testing.T) {
+	namespace := templateagent.AddonManagerNamespace()
+
+	cases := []struct {
+		name     string
+		template *addonv1alpha1.AddOnTemplate
+		existing []runtime.Object
+		validate func(t *testing.T, kubeClient *fakekube.Clientset)
+	}{
+		{
+			name:     "signing ca is not managed",
+			template: newSigningCATemplate(false),
+			validate: func(t *testing.T, kubeClient *fakekube.Clientset) {
+				testingcommon.AssertNoActions(t, kubeClient.Actions())
+			},
+		},
+		{
+			name:     "create signing ca and ca bundle",
+			template: newSigningCATemplate(true),
+			validate: func(t *testing.T, kubeClient *fakekube.Clientset) {
+				secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), "signer1-ca", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+					t.Errorf("expected the signing ca is created, but got %v", secret.Data)
+				}
+
+				caBundle, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(
+					context.TODO(), "signer1-ca-ca-bundle", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(caBundle.Data["ca-bundle.crt"]) == 0 {
+					t.Errorf("expected the ca bundle is created, but got %v", caBundle.Data)
+				}
+				if caBundle.Labels[addonv1alpha1.AddonLabelKey] != "addon1" {
+					t.Errorf("expected the ca bundle is labeled with the addon name, but got %v", caBundle.Labels)
+				}
+			},
+		},
+		{
+			name:     "invalid signing ca is replaced",
+			template: newSigningCATemplate(true),
+			existing: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "signer1-ca", Namespace: namespace},
+					Data:       map[string][]byte{corev1.TLSCertKey: []byte("invalid")},
+				},
+			},
+			validate: func(t *testing.T, kubeClient *fakekube.Clientset) {
+				secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), "signer1-ca", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(secret.Data[corev1.TLSCertKey]) == "invalid" {
+					t.Errorf("expected the invalid signing ca is replaced")
+				}
+			},
+		},
+		{
+			name:     "stage the next signing ca",
+			template: newSigningCATemplate(true),
+			existing: []runtime.Object{
+				newSigningCASecret(t, "signer1-ca", time.Now().Add(-65*time.Hour), time.Now().Add(35*time.Hour)),
+			},
+			validate: func(t *testing.T, kubeClient *fakekube.Clientset) {
+				next, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), "signer1-ca-next", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				caBundle, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(
+					context.TODO(), "signer1-ca-ca-bundle", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				certs, err := cert.ParseCertsPEM([]byte(caBundle.Data["ca-bundle.crt"]))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(certs) != 2 {
+					t.Errorf("expected the current and the next signing ca in the ca bundle, but got %d", len(certs))
+				}
+				if !bytes.Contains([]byte(caBundle.Data["ca-bundle.crt"]), next.Data[corev1.TLSCertKey]) {
+					t.Errorf("expected the next signing ca is in the ca bundle")
+				}
+			},
+		},
+		{
+			name:     "promote the next signing ca",
+			template: newSigningCATemplate(true),
+			existing: func() []runtime.Object {
+				current := newSigningCASecret(t, "signer1-ca", time.Now().Add(-75*time.Hour), time.Now().Add(25*time.Hour))
+				next := newSigningCASecret(t, "signer1-ca-next", time.Now().Add(-5*time.Hour), time.Now().Add(95*time.Hour))
+				return []runtime.Object{current, next, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "signer1-ca-ca-bundle", Namespace: namespace},
+					Data: map[string]string{
+						"ca-bundle.crt": string(next.Data[corev1.TLSCertKey]) + string(current.Data[corev1.TLSCertKey]),
+					},
+				}}
+			}(),
+			validate: func(t *testing.T, kubeClient *fakekube.Clientset) {
+				current, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), "signer1-ca", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				caBundle, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(
+					context.TODO(), "signer1-ca-ca-bundle", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.HasPrefix([]byte(caBundle.Data["ca-bundle.crt"]), current.Data[corev1.TLSCertKey]) {
+					t.Errorf("expected the next signing ca is promoted")
+				}
+				if _, err := kubeClient.CoreV1().Secrets(namespace).Get(
+					context.TODO(), "signer1-ca-next", metav1.GetOptions{}); !errors.IsNotFound(err) {
+					t.Errorf("expected the next signing ca is deleted, but got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := fakekube.NewSimpleClientset(c.existing...)
+			kubeInformers := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 10*time.Minute,
+				kubeinformers.WithNamespace(namespace))
+			addonClient := fakeaddon.NewSimpleClientset(c.template)
+			addonInformers := addoninformers.NewSharedInformerFactory(addonClient, 10*time.Minute)
+			if err := addonInformers.Addon().V1alpha1().AddOnTemplates().Informer().GetStore().Add(c.template); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, obj := range c.existing {
+				switch obj.(type) {
+				case *corev1.Secret:
+					if err := kubeInformers.Core().V1().Secrets().Informer().GetStore().Add(obj); err != nil {
+						t.Fatal(err)
+					}
+				case *corev1.ConfigMap:
+					if err := kubeInformers.Core().V1().ConfigMaps().Informer().GetStore().Add(obj); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			ctrl := &signingCARotationController{
+				kubeClient:          kubeClient,
+				addonTemplateLister: addonInformers.Addon().V1alpha1().AddOnTemplates().Lister(),
+				secretLister:        kubeInformers.Core().V1().Secrets().Lister(),
+				configMapLister:     kubeInformers.Core().V1().ConfigMaps().Lister(),
+				recorder:            eventstesting.NewTestingEventRecorder(t),
+			}
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, c.template.Name)); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			c.validate(t, kubeClient)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -28,6 +29,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/addon/controllers/addontemplate"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/cmainstallprogression"
 	"open-cluster-management.io/ocm/pkg/addon/controllers/cmamanagedby"
+	"open-cluster-management.io/ocm/pkg/addon/templateagent"
 )
 
 func RunManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
@@ -147,6 +149,7 @@ func RunControllerManagerWithInformers(
 		addonInformers.Addon().V1alpha1().ClusterManagementAddOns(),
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
+		clusterInformers.Cluster().V1().ManagedClusters(),
 		utils.ManagedByAddonManager,
 		controllerContext.EventRecorder,
 	)
@@ -197,6 +200,25 @@ func RunControllerManagerWithInformers(
 		controllerContext.EventRecorder,
 	)
 
+	// the ca bundles of the managed signing CAs are in the namespace of the addon manager
+	addonManagerNamespaceInformers := kubeinformers.NewSharedInformerFactoryWithOptions(hubKubeClient, 10*time.Minute,
+		kubeinformers.WithNamespace(templateagent.AddonManagerNamespace()))
+	signingCARotationController := addontemplate.NewSigningCARotationController(
+		hubKubeClient,
+		addonInformers.Addon().V1alpha1().AddOnTemplates(),
+		addonManagerNamespaceInformers.Core().V1().Secrets(),
+		addonManagerNamespaceInformers.Core().V1().ConfigMaps(),
+		controllerContext.EventRecorder,
+	)
+
+	healthConditionController := addontemplate.NewHealthConditionController(
+		hubAddOnClient,
+		addonInformers.Addon().V1alpha1().ManagedClusterAddOns(),
+		addonInformers.Addon().V1alpha1().AddOnTemplates(),
+		workinformers.Work().V1().ManifestWorks(),
+		controllerContext.EventRecorder,
+	)
+
 	go addonManagementController.Run(ctx, 2)
 	go addonConfigurationController.Run(ctx, 2)
 	go addonOwnerController.Run(ctx, 2)
@@ -206,11 +228,14 @@ func RunControllerManagerWithInformers(
 	// There should be only one instance of addonTemplateController running, since the addonTemplateController will
 	// start a goroutine for each template-type addon it watches.
 	go addonTemplateController.Run(ctx, 1)
+	go signingCARotationController.Run(ctx, 1)
+	go healthConditionController.Run(ctx, 2)
 
 	clusterInformers.Start(ctx.Done())
 	addonInformers.Start(ctx.Done())
 	workinformers.Start(ctx.Done())
 	dynamicInformers.Start(ctx.Done())
+	addonManagerNamespaceInformers.Start(ctx.Done())
 
 	<-ctx.Done()
 	return nil
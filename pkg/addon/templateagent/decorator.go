@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -167,6 +168,85 @@ func (d *daemonSetDecorator) decorate(obj *unstructured.Unstructured) (*unstruct
 	return &unstructured.Unstructured{Object: result}, nil
 }
 
+// preDeleteHookDecorator decorates the pre-delete hook jobs and pods in the same way as the agent workloads, so the
+// hooks can access the hub with the registered credentials to clean up the addon before it is removed.
+type preDeleteHookDecorator struct {
+	decorators []podTemplateSpecDecorator
+}
+
+func newPreDeleteHookDecorator(
+	addonName string,
+	template *addonapiv1alpha1.AddOnTemplate,
+	orderedValues orderedValues,
+	privateValues addonfactory.Values,
+) decorator {
+	return &preDeleteHookDecorator{
+		decorators: []podTemplateSpecDecorator{
+			newEnvironmentDecorator(orderedValues),
+			newVolumeDecorator(addonName, template),
+			newNodePlacementDecorator(privateValues),
+			newImageDecorator(privateValues),
+		},
+	}
+}
+
+func (d *preDeleteHookDecorator) decorate(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if !isPreDeleteHook(obj) {
+		return obj, nil
+	}
+
+	var target interface{}
+	switch obj.GroupVersionKind() {
+	case batchv1.SchemeGroupVersion.WithKind("Job"):
+		job := &batchv1.Job{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, job); err != nil {
+			return obj, err
+		}
+		if err := d.decoratePodTemplate(&job.Spec.Template); err != nil {
+			return obj, err
+		}
+		target = job
+	case corev1.SchemeGroupVersion.WithKind("Pod"):
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+			return obj, err
+		}
+		podTemplate := &corev1.PodTemplateSpec{Spec: pod.Spec}
+		if err := d.decoratePodTemplate(podTemplate); err != nil {
+			return obj, err
+		}
+		pod.Spec = podTemplate.Spec
+		target = pod
+	default:
+		return obj, nil
+	}
+
+	result, err := runtime.DefaultUnstructuredConverter.ToUnstructured(target)
+	if err != nil {
+		return obj, err
+	}
+
+	return &unstructured.Unstructured{Object: result}, nil
+}
+
+func (d *preDeleteHookDecorator) decoratePodTemplate(podTemplate *corev1.PodTemplateSpec) error {
+	for _, decorator := range d.decorators {
+		if err := decorator.decorate(podTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPreDeleteHook returns true if the object is marked as a pre-delete hook of the addon.
+func isPreDeleteHook(obj *unstructured.Unstructured) bool {
+	if _, ok := obj.GetAnnotations()[addonapiv1alpha1.AddonPreDeleteHookAnnotationKey]; ok {
+		return true
+	}
+	_, ok := obj.GetLabels()[addonapiv1alpha1.AddonPreDeleteHookLabelKey]
+	return ok
+}
+
 type podTemplateSpecDecorator interface {
 	// decorate modifies the pod template in place
 	decorate(pod *corev1.PodTemplateSpec) error
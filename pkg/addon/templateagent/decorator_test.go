@@ -3,6 +3,7 @@ package templateagent
 import (
 	"testing"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 )
@@ -159,3 +161,84 @@ func TestNamespaceDecorator(t *testing.T) {
 	}
 
 }
+
+func TestPreDeleteHookDecorator(t *testing.T) {
+	template := &addonapiv1alpha1.AddOnTemplate{
+		Spec: addonapiv1alpha1.AddOnTemplateSpec{
+			Registration: []addonapiv1alpha1.RegistrationSpec{
+				{Type: addonapiv1alpha1.RegistrationTypeKubeClient},
+			},
+		},
+	}
+	values := orderedValues{{name: "CLUSTER_NAME", value: "cluster1"}}
+	hookAnnotations := map[string]string{addonapiv1alpha1.AddonPreDeleteHookAnnotationKey: ""}
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: "cleanup", Image: "cleanup"}}}
+
+	toUnstructured := func(obj runtime.Object) *unstructured.Unstructured {
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &unstructured.Unstructured{Object: data}
+	}
+
+	tests := []struct {
+		name            string
+		object          *unstructured.Unstructured
+		expectDecorated bool
+	}{
+		{
+			name: "pre-delete hook job",
+			object: toUnstructured(&batchv1.Job{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Annotations: hookAnnotations},
+				Spec:       batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: podSpec}},
+			}),
+			expectDecorated: true,
+		},
+		{
+			name: "pre-delete hook pod",
+			object: toUnstructured(&corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Annotations: hookAnnotations},
+				Spec:       podSpec,
+			}),
+			expectDecorated: true,
+		},
+		{
+			name: "job is not a hook",
+			object: toUnstructured(&batchv1.Job{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup"},
+				Spec:       batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: podSpec}},
+			}),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newPreDeleteHookDecorator("addon1", template, values, addonfactory.Values{})
+			result, err := d.decorate(tc.object)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			spec, found, err := unstructured.NestedMap(result.Object, "spec", "template", "spec")
+			if !found {
+				spec, _, err = unstructured.NestedMap(result.Object, "spec")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoratedPodSpec := &corev1.PodSpec{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, decoratedPodSpec); err != nil {
+				t.Fatal(err)
+			}
+
+			decorated := len(decoratedPodSpec.Containers[0].Env) == 1 && len(decoratedPodSpec.Volumes) == 1
+			if decorated != tc.expectDecorated {
+				t.Errorf("expected decorated %v, but got pod spec %v", tc.expectDecorated, decoratedPodSpec)
+			}
+		})
+	}
+}
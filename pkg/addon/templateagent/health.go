@@ -0,0 +1,296 @@
+package templateagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/utils/lru"
+
+	"open-cluster-management.io/addon-framework/pkg/agent"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// HealthProbesAnnotationKey is the annotation on an AddOnTemplate defining the health probes of the addon as a json
+// list of HealthProbe. Once any Available probe is set, the availability of the addon is determined by the probes
+// instead of the availability of the deployments and daemonsets in the template.
+// TODO move this to the api repo.
+const HealthProbesAnnotationKey = "addon.open-cluster-management.io/experimental-health-probes"
+
+// HealthProbe checks the health of the addon with a CEL expression over the status feedback of a resource.
+type HealthProbe struct {
+	// ResourceIdentifier identifies the resource in the manifests of the template.
+	ResourceIdentifier workapiv1.ResourceIdentifier `json:"resourceIdentifier"`
+
+	// JsonPaths are the status fields of the resource returned as the feedback values.
+	JsonPaths []workapiv1.JsonPath `json:"jsonPaths"`
+
+	// ConditionType is the type of the condition of the addon determined by the probe, it is Available by default.
+	// The addon is unavailable if the expression of any Available probe is false. The other conditions, e.g.
+	// Degraded, are True if the expression of any of their probes is true, and False otherwise.
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+
+	// Expression is a CEL expression evaluating to a bool. The feedback values are accessible with the variable
+	// "values" by the name of the json paths, e.g. values.replicas > 0. The value of a json path returning a list
+	// or an object is decoded from the raw json.
+	Expression string `json:"expression"`
+
+	// Message is the message of the condition once it is determined by the probe, that is once the expression is
+	// false for the Available condition, or once the expression is true for the other conditions.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// IsAvailableProbe returns if the probe determines the Available condition of the addon.
+func (p HealthProbe) IsAvailableProbe() bool {
+	return len(p.ConditionType) == 0 || p.ConditionType == addonapiv1alpha1.ManagedClusterAddOnConditionAvailable
+}
+
+// GetHealthProbes returns the health probes defined on the template, nil is returned if there are none.
+func GetHealthProbes(template *addonapiv1alpha1.AddOnTemplate) ([]HealthProbe, error) {
+	value, ok := template.Annotations[HealthProbesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var probes []HealthProbe
+	if err := json.Unmarshal([]byte(value), &probes); err != nil {
+		return nil, fmt.Errorf("failed to parse the health probes of addon template %s: %w", template.Name, err)
+	}
+	for _, probe := range probes {
+		if len(probe.JsonPaths) == 0 {
+			return nil, fmt.Errorf("no json paths are defined in the health probe of %v", probe.ResourceIdentifier)
+		}
+		if _, err := compileHealthProbe(probe.Expression); err != nil {
+			return nil, err
+		}
+	}
+	return probes, nil
+}
+
+// newWorkProber returns a work prober checking the feedback values of the resources with the health probes.
+func newWorkProber(probes []HealthProbe) *agent.WorkHealthProber {
+	prober := &agent.WorkHealthProber{
+		HealthCheck: func(identifier workapiv1.ResourceIdentifier, result workapiv1.StatusFeedbackResult) error {
+			return checkHealthProbes(probes, identifier, result)
+		},
+	}
+	for _, probe := range probes {
+		prober.ProbeFields = append(prober.ProbeFields, agent.ProbeField{
+			ResourceIdentifier: probe.ResourceIdentifier,
+			ProbeRules: []workapiv1.FeedbackRule{
+				{
+					Type:      workapiv1.JSONPathsType,
+					JsonPaths: probe.JsonPaths,
+				},
+			},
+		})
+	}
+	return prober
+}
+
+func checkHealthProbes(probes []HealthProbe,
+	identifier workapiv1.ResourceIdentifier, result workapiv1.StatusFeedbackResult) error {
+	for _, probe := range probes {
+		if !probe.IsAvailableProbe() || !equality.Semantic.DeepEqual(probe.ResourceIdentifier, identifier) {
+			continue
+		}
+		healthy, err := evaluateHealthProbe(probe, result)
+		if err != nil {
+			return err
+		}
+		if healthy {
+			continue
+		}
+		if len(probe.Message) > 0 {
+			return fmt.Errorf("%s", probe.Message)
+		}
+		return fmt.Errorf("the health probe %q of %s %s/%s is false",
+			probe.Expression, identifier.Resource, identifier.Namespace, identifier.Name)
+	}
+	return nil
+}
+
+// HealthConditions returns the conditions other than Available determined by the health probes with the status
+// feedback of the resources in the works of the addon.
+func HealthConditions(probes []HealthProbe, works []*workapiv1.ManifestWork) []metav1.Condition {
+	var conditionTypes []string
+	probesByType := map[string][]HealthProbe{}
+	for _, probe := range probes {
+		if probe.IsAvailableProbe() {
+			continue
+		}
+		if _, ok := probesByType[probe.ConditionType]; !ok {
+			conditionTypes = append(conditionTypes, probe.ConditionType)
+		}
+		probesByType[probe.ConditionType] = append(probesByType[probe.ConditionType], probe)
+	}
+
+	var conditions []metav1.Condition
+	for _, conditionType := range conditionTypes {
+		conditions = append(conditions, healthCondition(conditionType, probesByType[conditionType], works))
+	}
+	return conditions
+}
+
+func healthCondition(conditionType string, probes []HealthProbe, works []*workapiv1.ManifestWork) metav1.Condition {
+	var unknown []string
+	for _, probe := range probes {
+		result, ok := findStatusFeedback(works, probe.ResourceIdentifier)
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("no status feedback of %s %s/%s is found",
+				probe.ResourceIdentifier.Resource, probe.ResourceIdentifier.Namespace, probe.ResourceIdentifier.Name))
+			continue
+		}
+		matched, err := evaluateHealthProbe(probe, result)
+		if err != nil {
+			unknown = append(unknown, err.Error())
+			continue
+		}
+		if !matched {
+			continue
+		}
+		message := probe.Message
+		if len(message) == 0 {
+			message = fmt.Sprintf("the health probe %q of %s %s/%s is true", probe.Expression,
+				probe.ResourceIdentifier.Resource, probe.ResourceIdentifier.Namespace, probe.ResourceIdentifier.Name)
+		}
+		return metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HealthProbeTrue",
+			Message: message,
+		}
+	}
+
+	if len(unknown) > 0 {
+		return metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "HealthProbeUnknown",
+			Message: strings.Join(unknown, "; "),
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "HealthProbeFalse",
+		Message: "all the health probes are false",
+	}
+}
+
+// findStatusFeedback returns the status feedback of the resource in the works.
+func findStatusFeedback(works []*workapiv1.ManifestWork,
+	identifier workapiv1.ResourceIdentifier) (workapiv1.StatusFeedbackResult, bool) {
+	for _, work := range works {
+		for _, manifest := range work.Status.ResourceStatus.Manifests {
+			if manifest.ResourceMeta.Group == identifier.Group &&
+				manifest.ResourceMeta.Resource == identifier.Resource &&
+				manifest.ResourceMeta.Name == identifier.Name &&
+				manifest.ResourceMeta.Namespace == identifier.Namespace {
+				return manifest.StatusFeedbacks, true
+			}
+		}
+	}
+	return workapiv1.StatusFeedbackResult{}, false
+}
+
+func evaluateHealthProbe(probe HealthProbe, result workapiv1.StatusFeedbackResult) (bool, error) {
+	values, err := feedbackValues(result)
+	if err != nil {
+		return false, err
+	}
+	program, err := compileHealthProbe(probe.Expression)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := program.Eval(map[string]interface{}{"values": values})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate the health probe %q: %w", probe.Expression, err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("the health probe %q does not evaluate to a bool", probe.Expression)
+	}
+	return matched, nil
+}
+
+func feedbackValues(result workapiv1.StatusFeedbackResult) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, value := range result.Values {
+		switch {
+		case value.Value.Integer != nil:
+			values[value.Name] = *value.Value.Integer
+		case value.Value.String != nil:
+			values[value.Name] = *value.Value.String
+		case value.Value.Boolean != nil:
+			values[value.Name] = *value.Value.Boolean
+		case value.Value.JsonRaw != nil:
+			var raw interface{}
+			if err := json.Unmarshal([]byte(*value.Value.JsonRaw), &raw); err != nil {
+				return nil, fmt.Errorf("failed to decode the feedback value %s: %w", value.Name, err)
+			}
+			values[value.Name] = raw
+		}
+	}
+	return values, nil
+}
+
+// healthProbeCacheSize is the max number of the compiled health probes cached.
+const healthProbeCacheSize = 1024
+
+var (
+	healthProbeEnvOnce sync.Once
+	healthProbeEnv     *cel.Env
+	healthProbeEnvErr  error
+
+	healthProbePrograms = lru.New(healthProbeCacheSize)
+)
+
+// compileHealthProbe compiles the expression, the programs are cached by the expressions since the health check is
+// run on every sync of the addons. The cost of an evaluation is limited as the expressions are provided by the users.
+func compileHealthProbe(expression string) (cel.Program, error) {
+	healthProbeEnvOnce.Do(func() {
+		healthProbeEnv, healthProbeEnvErr = newHealthProbeEnv()
+	})
+	if healthProbeEnvErr != nil {
+		return nil, healthProbeEnvErr
+	}
+
+	if program, ok := healthProbePrograms.Get(expression); ok {
+		return program.(cel.Program), nil
+	}
+
+	ast, issues := healthProbeEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile the health probe %q: %w", expression, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("the health probe %q must evaluate to a bool, but got %v", expression, ast.OutputType())
+	}
+	program, err := healthProbeEnv.Program(ast, cel.CostLimit(celconfig.PerCallLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile the health probe %q: %w", expression, err)
+	}
+	healthProbePrograms.Add(expression, program)
+	return program, nil
+}
+
+func newHealthProbeEnv() (*cel.Env, error) {
+	env, err := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), true).
+		Env(environment.StoredExpressions)
+	if err != nil {
+		return nil, err
+	}
+	return env.Extend(
+		cel.Variable("values", cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
@@ -0,0 +1,163 @@
+package templateagent
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestGetHealthProbes(t *testing.T) {
+	cases := []struct {
+		name           string
+		annotations    map[string]string
+		expectedProbes int
+		expectedErr    bool
+	}{
+		{
+			name: "no health probes",
+		},
+		{
+			name: "valid health probes",
+			annotations: map[string]string{
+				HealthProbesAnnotationKey: `[{"resourceIdentifier":{"group":"example.com","resource":"foos","name":"foo","namespace":"ns1"},
+"jsonPaths":[{"name":"phase","path":".status.phase"}],"expression":"values.phase == 'Running'"}]`,
+			},
+			expectedProbes: 1,
+		},
+		{
+			name:        "invalid json",
+			annotations: map[string]string{HealthProbesAnnotationKey: "invalid"},
+			expectedErr: true,
+		},
+		{
+			name: "no json paths",
+			annotations: map[string]string{
+				HealthProbesAnnotationKey: `[{"resourceIdentifier":{"resource":"foos","name":"foo"},"expression":"true"}]`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid expression",
+			annotations: map[string]string{
+				HealthProbesAnnotationKey: `[{"resourceIdentifier":{"resource":"foos","name":"foo"},
+"jsonPaths":[{"name":"phase","path":".status.phase"}],"expression":"values.phase =="}]`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "expression not returning bool",
+			annotations: map[string]string{
+				HealthProbesAnnotationKey: `[{"resourceIdentifier":{"resource":"foos","name":"foo"},
+"jsonPaths":[{"name":"phase","path":".status.phase"}],"expression":"'Running'"}]`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			template := &addonapiv1alpha1.AddOnTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "template1", Annotations: c.annotations},
+			}
+			probes, err := GetHealthProbes(template)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if len(probes) != c.expectedProbes {
+				t.Errorf("expected %d probes, but got %v", c.expectedProbes, probes)
+			}
+		})
+	}
+}
+
+func TestHealthProbeWorkProber(t *testing.T) {
+	identifier := workapiv1.ResourceIdentifier{Group: "example.com", Resource: "foos", Name: "foo", Namespace: "ns1"}
+	probes := []HealthProbe{
+		{
+			ResourceIdentifier: identifier,
+			JsonPaths: []workapiv1.JsonPath{
+				{Name: "phase", Path: ".status.phase"},
+				{Name: "readyReplicas", Path: ".status.readyReplicas"},
+				{Name: "conditions", Path: ".status.conditions"},
+			},
+			Expression: "values.phase == 'Running' && values.readyReplicas > 0 && " +
+				"values.conditions.exists(c, c.type == 'Ready' && c.status == 'True')",
+			Message: "foo is not ready",
+		},
+	}
+
+	prober := newWorkProber(probes)
+	if len(prober.ProbeFields) != 1 || prober.ProbeFields[0].ResourceIdentifier != identifier {
+		t.Fatalf("unexpected probe fields %v", prober.ProbeFields)
+	}
+	if prober.ProbeFields[0].ProbeRules[0].Type != workapiv1.JSONPathsType {
+		t.Errorf("expected json paths feedback rule, but got %v", prober.ProbeFields[0].ProbeRules)
+	}
+
+	newResult := func(phase string, readyReplicas int64, conditions string) workapiv1.StatusFeedbackResult {
+		return workapiv1.StatusFeedbackResult{
+			Values: []workapiv1.FeedbackValue{
+				{Name: "phase", Value: workapiv1.FieldValue{Type: workapiv1.String, String: pointer.String(phase)}},
+				{Name: "readyReplicas", Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(readyReplicas)}},
+				{Name: "conditions", Value: workapiv1.FieldValue{Type: workapiv1.JsonRaw, JsonRaw: pointer.String(conditions)}},
+			},
+		}
+	}
+
+	cases := []struct {
+		name        string
+		identifier  workapiv1.ResourceIdentifier
+		result      workapiv1.StatusFeedbackResult
+		expectedErr string
+	}{
+		{
+			name:       "healthy",
+			identifier: identifier,
+			result:     newResult("Running", 1, `[{"type":"Ready","status":"True"}]`),
+		},
+		{
+			name:        "not ready",
+			identifier:  identifier,
+			result:      newResult("Running", 1, `[{"type":"Ready","status":"False"}]`),
+			expectedErr: "foo is not ready",
+		},
+		{
+			name:        "no ready replicas",
+			identifier:  identifier,
+			result:      newResult("Running", 0, `[{"type":"Ready","status":"True"}]`),
+			expectedErr: "foo is not ready",
+		},
+		{
+			name:       "resource without probes",
+			identifier: workapiv1.ResourceIdentifier{Group: "example.com", Resource: "bars", Name: "bar"},
+			result:     newResult("Pending", 0, `[]`),
+		},
+		{
+			name:       "missing feedback value",
+			identifier: identifier,
+			result: workapiv1.StatusFeedbackResult{
+				Values: []workapiv1.FeedbackValue{
+					{Name: "phase", Value: workapiv1.FieldValue{Type: workapiv1.String, String: pointer.String("Running")}},
+				},
+			},
+			expectedErr: `failed to evaluate the health probe "values.phase == 'Running' && values.readyReplicas > 0 && ` +
+				`values.conditions.exists(c, c.type == 'Ready' && c.status == 'True')": no such key: readyReplicas`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := prober.HealthCheck(c.identifier, c.result)
+			switch {
+			case len(c.expectedErr) == 0 && err != nil:
+				t.Errorf("unexpected err: %v", err)
+			case len(c.expectedErr) > 0 && (err == nil || err.Error() != c.expectedErr):
+				t.Errorf("expected err %q, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -30,6 +30,12 @@ const (
 	// AddonTemplateLabelKey is the label key to set addon template name. It is to set the resources on the hub relating
 	// to an addon template
 	AddonTemplateLabelKey = "open-cluster-management.io/addon-template-name"
+
+	// ManagedSigningCAAnnotationKey is the annotation on an AddOnTemplate to have the addon manager maintain the
+	// signing CAs of its custom signer registrations when it is set to "true". The signing CAs are rotated
+	// automatically, and the bundle of all the unexpired CAs is kept in a ConfigMap next to the signing CA secret.
+	// TODO move this to the api repo.
+	ManagedSigningCAAnnotationKey = "addon.open-cluster-management.io/experimental-managed-signing-ca"
 )
 
 var (
@@ -230,6 +236,37 @@ func CustomerSignerCSRApprover(logger klog.Logger, agentName string) agent.CSRAp
 	}
 }
 
+// IsSigningCAManaged returns true if the signing CAs of the custom signer registrations of the template are
+// maintained by the addon manager.
+func IsSigningCAManaged(template *addonapiv1alpha1.AddOnTemplate) bool {
+	return template.Annotations[ManagedSigningCAAnnotationKey] == "true"
+}
+
+// SigningCABundleName returns the name of the ConfigMap holding the ca bundle of a managed signing CA.
+func SigningCABundleName(signingCAName string) string {
+	return fmt.Sprintf("%s-ca-bundle", signingCAName)
+}
+
+// getSigningCABundle returns the ca bundles of the managed signing CAs of the template.
+func (a *CRDTemplateAgentAddon) getSigningCABundle(template *addonapiv1alpha1.AddOnTemplate) ([]byte, error) {
+	var caBundle []byte
+	for _, registration := range template.Spec.Registration {
+		if registration.Type != addonapiv1alpha1.RegistrationTypeCustomSigner || registration.CustomSigner == nil {
+			continue
+		}
+		if a.caBundleLister == nil {
+			return nil, fmt.Errorf("the ca bundle lister of addon %s is not set", a.addonName)
+		}
+		name := SigningCABundleName(registration.CustomSigner.SigningCA.Name)
+		configMap, err := a.caBundleLister.ConfigMaps(AddonManagerNamespace()).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the ca bundle %s/%s: %w", AddonManagerNamespace(), name, err)
+		}
+		caBundle = append(caBundle, []byte(configMap.Data["ca-bundle.crt"])...)
+	}
+	return caBundle, nil
+}
+
 func (a *CRDTemplateAgentAddon) TemplateCSRSignFunc() agent.CSRSignerFunc {
 
 	return func(csr *certificatesv1.CertificateSigningRequest) []byte {
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
 	rbacv1lister "k8s.io/client-go/listers/rbac/v1"
 	"k8s.io/klog/v2"
 
@@ -21,6 +22,7 @@ import (
 	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
 )
 
 const (
@@ -29,6 +31,10 @@ const (
 	NodePlacementPrivateValueKey    = "__NODE_PLACEMENT"
 	RegistriesPrivateValueKey       = "__REGISTRIES"
 	InstallNamespacePrivateValueKey = "__INSTALL_NAMESPACE"
+
+	// CustomSignerCABundleValueKey is the value key of the base64 encoded ca bundle of the managed signing CAs, it
+	// can be used in the manifests to distribute the bundle to the agents, but is not set as an env variable.
+	CustomSignerCABundleValueKey = "CUSTOM_SIGNER_CA_BUNDLE"
 )
 
 // templateBuiltinValues includes the built-in values for crd template agentAddon.
@@ -59,8 +65,11 @@ type CRDTemplateAgentAddon struct {
 	addonTemplateLister addonlisterv1alpha1.AddOnTemplateLister
 	cmaLister           addonlisterv1alpha1.ClusterManagementAddOnLister
 	rolebindingLister   rbacv1lister.RoleBindingLister
+	caBundleLister      corev1lister.ConfigMapLister
 	addonName           string
 	agentName           string
+	// configGVRs are the GVRs of the configs supported by the addon in addition to the built-in ones.
+	configGVRs []schema.GroupVersionResource
 }
 
 // NewCRDTemplateAgentAddon creates a CRDTemplateAgentAddon instance
@@ -92,6 +101,19 @@ func NewCRDTemplateAgentAddon(
 	return a
 }
 
+// WithConfigGVRs adds the GVRs of the configs supported by the addon in addition to the built-in configs, the spec
+// hashes of these configs are computed and the agents are redeployed once they are changed.
+func (a *CRDTemplateAgentAddon) WithConfigGVRs(gvrs ...schema.GroupVersionResource) *CRDTemplateAgentAddon {
+	a.configGVRs = append(a.configGVRs, gvrs...)
+	return a
+}
+
+// WithCABundleLister sets the lister of the ca bundles of the managed signing CAs in the addon manager namespace.
+func (a *CRDTemplateAgentAddon) WithCABundleLister(lister corev1lister.ConfigMapLister) *CRDTemplateAgentAddon {
+	a.caBundleLister = lister
+	return a
+}
+
 func (a *CRDTemplateAgentAddon) Manifests(
 	cluster *clusterv1.ManagedCluster,
 	addon *addonapiv1alpha1.ManagedClusterAddOn) ([]runtime.Object, error) {
@@ -107,11 +129,11 @@ func (a *CRDTemplateAgentAddon) Manifests(
 }
 
 func (a *CRDTemplateAgentAddon) GetAgentAddonOptions() agent.AgentAddonOptions {
-	// TODO: consider a new way for developers to define their supported config GVRs
 	supportedConfigGVRs := []schema.GroupVersionResource{}
 	for gvr := range utils.BuiltInAddOnConfigGVRs {
 		supportedConfigGVRs = append(supportedConfigGVRs, gvr)
 	}
+	supportedConfigGVRs = append(supportedConfigGVRs, a.configGVRs...)
 
 	agentAddonOptions := agent.AgentAddonOptions{
 		AddonName: a.addonName,
@@ -144,6 +166,30 @@ func (a *CRDTemplateAgentAddon) GetAgentAddonOptions() agent.AgentAddonOptions {
 	}
 	agentAddonOptions.ManifestConfigs = template.Spec.AgentSpec.ManifestConfigs
 
+	probes, err := GetHealthProbes(template)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return agentAddonOptions
+	}
+	var availableProbes []HealthProbe
+	for _, probe := range probes {
+		if probe.IsAvailableProbe() {
+			availableProbes = append(availableProbes, probe)
+			continue
+		}
+		// the feedback of the other probes is evaluated by the health condition controller.
+		agentAddonOptions.ManifestConfigs = append(agentAddonOptions.ManifestConfigs, workapiv1.ManifestConfigOption{
+			ResourceIdentifier: probe.ResourceIdentifier,
+			FeedbackRules:      []workapiv1.FeedbackRule{{Type: workapiv1.JSONPathsType, JsonPaths: probe.JsonPaths}},
+		})
+	}
+	if len(availableProbes) > 0 {
+		agentAddonOptions.HealthProber = &agent.HealthProber{
+			Type:       agent.HealthProberTypeWork,
+			WorkProber: newWorkProber(availableProbes),
+		}
+	}
+
 	return agentAddonOptions
 }
 
@@ -190,6 +236,7 @@ func (a *CRDTemplateAgentAddon) decorateObject(
 	decorators := []decorator{
 		newDeploymentDecorator(a.addonName, template, orderedValues, privateValues),
 		newDaemonSetDecorator(a.addonName, template, orderedValues, privateValues),
+		newPreDeleteHookDecorator(a.addonName, template, orderedValues, privateValues),
 		newNamespaceDecorator(privateValues),
 	}
 
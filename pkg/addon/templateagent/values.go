@@ -1,6 +1,7 @@
 package templateagent
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -104,6 +105,14 @@ func (a *CRDTemplateAgentAddon) getValues(
 	// the contained values are also set in the overrideValues, since these values should not be set externally.
 	overrideValues = addonfactory.MergeValues(overrideValues, builtinValues)
 
+	if IsSigningCAManaged(template) {
+		caBundle, err := a.getSigningCABundle(template)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		overrideValues[CustomSignerCABundleValueKey] = base64.StdEncoding.EncodeToString(caBundle)
+	}
+
 	for k, v := range overrideValues {
 		_, ok := v.(string)
 		if !ok {
@@ -2,12 +2,18 @@ package templateagent
 
 import (
 	"context"
+	"encoding/base64"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/ktesting"
 
@@ -106,12 +112,14 @@ func TestGetAddOnRegistriesPrivateValuesFromClusterAnnotation(t *testing.T) {
 
 func TestGetValues(t *testing.T) {
 	cases := []struct {
-		name             string
-		templateSpec     addonapiv1alpha1.AddOnTemplateSpec
-		values           addonfactory.Values
-		expectedPreset   orderedValues
-		expectedOverride map[string]interface{}
-		expectedPrivate  map[string]interface{}
+		name                string
+		templateAnnotations map[string]string
+		templateSpec        addonapiv1alpha1.AddOnTemplateSpec
+		caBundles           []runtime.Object
+		values              addonfactory.Values
+		expectedPreset      orderedValues
+		expectedOverride    map[string]interface{}
+		expectedPrivate     map[string]interface{}
 	}{
 		{
 			name: "with default value, registration set in template",
@@ -253,6 +261,43 @@ func TestGetValues(t *testing.T) {
 				InstallNamespacePrivateValueKey: "default-ns",
 			},
 		},
+		{
+			name:                "with the ca bundle of the managed signing ca",
+			templateAnnotations: map[string]string{ManagedSigningCAAnnotationKey: "true"},
+			templateSpec: addonapiv1alpha1.AddOnTemplateSpec{
+				Registration: []addonapiv1alpha1.RegistrationSpec{
+					{
+						Type: addonapiv1alpha1.RegistrationTypeCustomSigner,
+						CustomSigner: &addonapiv1alpha1.CustomSignerRegistrationConfig{
+							SignerName: "example.com/signer1",
+							SigningCA:  addonapiv1alpha1.SigningCARef{Name: "signer1-ca"},
+						},
+					},
+				},
+			},
+			caBundles: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "signer1-ca-ca-bundle", Namespace: AddonManagerNamespace()},
+					Data:       map[string]string{"ca-bundle.crt": "ca-data"},
+				},
+			},
+			expectedPreset: orderedValues{
+				{
+					name:  "HUB_KUBECONFIG",
+					value: "/managed/hub-kubeconfig/kubeconfig",
+				},
+				{
+					name:  "CLUSTER_NAME",
+					value: "test-cluster",
+				},
+			},
+			expectedOverride: map[string]interface{}{
+				"HUB_KUBECONFIG":          "/managed/hub-kubeconfig/kubeconfig",
+				"CLUSTER_NAME":            "test-cluster",
+				"CUSTOM_SIGNER_CA_BUNDLE": base64.StdEncoding.EncodeToString([]byte("ca-data")),
+			},
+			expectedPrivate: map[string]interface{}{},
+		},
 	}
 
 	for _, c := range cases {
@@ -263,9 +308,17 @@ func TestGetValues(t *testing.T) {
 				return c.values, nil
 			}
 
+			kubeInformers := kubeinformers.NewSharedInformerFactory(fakekube.NewSimpleClientset(c.caBundles...), 10*time.Minute)
+			for _, caBundle := range c.caBundles {
+				if err := kubeInformers.Core().V1().ConfigMaps().Informer().GetStore().Add(caBundle); err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			agentAddon := &CRDTemplateAgentAddon{
 				logger:         klog.FromContext(context.TODO()),
 				getValuesFuncs: []addonfactory.GetValuesFunc{getValueFunc},
+				caBundleLister: kubeInformers.Core().V1().ConfigMaps().Lister(),
 			}
 
 			cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}}
@@ -275,7 +328,7 @@ func TestGetValues(t *testing.T) {
 			}}
 
 			addonTemplate := &addonapiv1alpha1.AddOnTemplate{
-				ObjectMeta: metav1.ObjectMeta{Name: "test-addon"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-addon", Annotations: c.templateAnnotations},
 				Spec:       c.templateSpec,
 			}
 
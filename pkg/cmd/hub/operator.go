@@ -27,6 +27,24 @@ func NewHubOperatorCmd() *cobra.Command {
 			"e.g. 'environment=production', 'tier notin (frontend,backend)'")
 	flags.Int32Var(&cmOptions.DeploymentReplicas, "deployment-replicas", 0,
 		"Number of deployment replicas, operator will automatically determine replicas if not set")
+	flags.BoolVar(&cmOptions.RestrictedPodSecurity, "restricted-pod-security", false,
+		"If set, will deploy the hub components compliant with the restricted Pod Security Standard and enforce "+
+			"the standard on the cluster manager namespace")
 	opts.AddFlags(flags)
 	return cmd
 }
+
+// NewClusterManagerRenderCmd generates a command to render the manifests of a cluster manager
+func NewClusterManagerRenderCmd() *cobra.Command {
+	renderOptions := clustermanager.NewRenderOptions()
+	cmd := &cobra.Command{
+		Use:   "clustermanager",
+		Short: "Render the manifests the cluster manager operator applies for a ClusterManager",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return renderOptions.Render(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	renderOptions.AddFlags(cmd.Flags())
+	return cmd
+}
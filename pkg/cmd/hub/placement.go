@@ -12,14 +12,16 @@ import (
 
 func NewPlacementController() *cobra.Command {
 	opts := commonoptions.NewOptions()
+	controllerOpts := controllers.NewPlacementControllerOptions()
 	cmdConfig := opts.
-		NewControllerCommandConfig("placement", version.Get(), controllers.RunControllerManager)
+		NewControllerCommandConfig("placement", version.Get(), controllerOpts.RunControllerManager)
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = "controller"
 	cmd.Short = "Start the Placement Scheduling Controller"
 
 	flags := cmd.Flags()
 	opts.AddFlags(flags)
+	controllerOpts.AddFlags(flags)
 
 	return cmd
 }
@@ -8,6 +8,7 @@ import (
 
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/operator/operators/klusterlet"
@@ -47,12 +48,33 @@ func NewKlusterletOperatorCmd() *cobra.Command {
 
 	flags.BoolVar(&klOptions.EnableSyncLabels, "enable-sync-labels", false,
 		"If set, will sync the labels of Klusterlet CR to all agent resources")
+	flags.BoolVar(&klOptions.RestrictedPodSecurity, "restricted-pod-security", false,
+		"If set, will deploy the agents compliant with the restricted Pod Security Standard and enforce the "+
+			"standard on the agent namespace")
+	flags.BoolVar(&klOptions.MinimalRBAC, "minimal-rbac", false,
+		"If set, will grant the agents only the permissions required by the enabled features on the managed "+
+			"cluster instead of the broad cluster roles, and report the actions denied to the agents as events")
 
 	opts.AddFlags(flags)
 
 	return cmd
 }
 
+// NewKlusterletRenderCmd generates a command to render the manifests of a klusterlet
+func NewKlusterletRenderCmd() *cobra.Command {
+	renderOptions := klusterlet.NewRenderOptions()
+	cmd := &cobra.Command{
+		Use:   "klusterlet",
+		Short: "Render the manifests the klusterlet operator applies for a Klusterlet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return renderOptions.Render(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	renderOptions.AddFlags(cmd.Flags())
+	return cmd
+}
+
 // NewKlusterletAgentCmd is to start the singleton agent including registration/work
 func NewKlusterletAgentCmd() *cobra.Command {
 	commonOptions := commonoptions.NewAgentOptions()
@@ -62,7 +84,9 @@ func NewKlusterletAgentCmd() *cobra.Command {
 	agentConfig := singletonspoke.NewAgentConfig(commonOptions, registrationOption, workOptions)
 	cmdConfig := commonOptions.CommonOpts.
 		NewControllerCommandConfig("klusterlet", version.Get(), agentConfig.RunSpokeAgent).
-		WithHealthChecks(registrationOption.GetHealthCheckers()...)
+		WithHealthChecks(registrationOption.GetHealthCheckers()...).
+		WithHealthChecks(health.NewReadiness(
+			append(registrationOption.GetReadinessCheckers(), workOptions.GetReadinessCheckers()...)...))
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = agentCmdName
 	cmd.Short = "Start the klusterlet agent"
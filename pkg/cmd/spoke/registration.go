@@ -2,14 +2,17 @@ package spoke
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/spf13/cobra"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 	"open-cluster-management.io/ocm/pkg/registration/spoke"
 	"open-cluster-management.io/ocm/pkg/version"
 )
@@ -20,7 +23,8 @@ func NewRegistrationAgent() *cobra.Command {
 	cfg := spoke.NewSpokeAgentConfig(commonOptions, agentOptions)
 	cmdConfig := commonOptions.CommonOpts.
 		NewControllerCommandConfig("registration-agent", version.Get(), cfg.RunSpokeAgent).
-		WithHealthChecks(agentOptions.GetHealthCheckers()...)
+		WithHealthChecks(agentOptions.GetHealthCheckers()...).
+		WithHealthChecks(health.NewReadiness(agentOptions.GetReadinessCheckers()...))
 
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = agentCmdName
@@ -34,3 +38,23 @@ func NewRegistrationAgent() *cobra.Command {
 	features.SpokeMutableFeatureGate.AddFlag(flags)
 	return cmd
 }
+
+// NewRegistrationCredential generates a command to print the client certificate and key in a directory as an
+// ExecCredential, it is the exec credential plugin referenced in the kubeconfig of the addons.
+func NewRegistrationCredential() *cobra.Command {
+	certDir := ""
+	cmd := &cobra.Command{
+		Use:   "credential",
+		Short: "Print the client certificate for the hub as an ExecCredential",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			credential, err := clientcert.LoadExecCredential(certDir)
+			if err != nil {
+				return err
+			}
+			return json.NewEncoder(cmd.OutOrStdout()).Encode(credential)
+		},
+	}
+
+	cmd.Flags().StringVar(&certDir, "cert-dir", certDir, "The directory of the client certificate and key.")
+	return cmd
+}
@@ -8,6 +8,7 @@ import (
 
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/version"
@@ -20,7 +21,9 @@ func NewWorkAgent() *cobra.Command {
 	agentOption := spoke.NewWorkloadAgentOptions()
 	cfg := spoke.NewWorkAgentConfig(commonOptions, agentOption)
 	cmdConfig := commonOptions.CommonOpts.
-		NewControllerCommandConfig("work-agent", version.Get(), cfg.RunWorkloadAgent)
+		NewControllerCommandConfig("work-agent", version.Get(), cfg.RunWorkloadAgent).
+		WithHealthChecks(agentOption.GetHealthCheckers()...).
+		WithHealthChecks(health.NewReadiness(agentOption.GetReadinessCheckers()...))
 	cmd := cmdConfig.NewCommandWithContext(context.TODO())
 	cmd.Use = agentCmdName
 	cmd.Short = "Start the Work Agent"
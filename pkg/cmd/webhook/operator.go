@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"github.com/spf13/cobra"
+
+	"open-cluster-management.io/ocm/pkg/operator/webhook"
+)
+
+func NewOperatorWebhook() *cobra.Command {
+	ops := webhook.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "webhook-server",
+		Short: "Start the webhook server validating the ClusterManager and Klusterlet",
+		RunE: func(c *cobra.Command, args []string) error {
+			err := ops.RunWebhookServer()
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	ops.AddFlags(flags)
+
+	return cmd
+}
@@ -0,0 +1,127 @@
+package cloudevents
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	cloudeventswork "open-cluster-management.io/sdk-go/pkg/cloudevents/work"
+	agentclient "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/client"
+	agentlister "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/lister"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
+)
+
+// ResyncOptions configures how the agent resyncs the works from the sources.
+type ResyncOptions struct {
+	// Interval is the interval to resync the works from the sources periodically, the periodic resync is disabled
+	// if it is zero.
+	Interval time.Duration
+
+	// Window is the duration to wait before sending a resync request, the resync triggers (e.g. the reconnects
+	// when the broker is flapping) within the window are merged into one resync request.
+	Window time.Duration
+}
+
+// NewAgentWorkClientSet builds a work clientset for an agent with the given cloudevents agent options. It behaves
+// the same as the agent client holder in the sdk, but accepts the agent options directly, so the agent is able to
+// customize the cloudevents options, e.g. reloading the client certificate once it is rotated. The duplicated spec
+// events are ignored by the returned client.
+func NewAgentWorkClientSet(
+	ctx context.Context,
+	agentOptions *options.CloudEventsAgentOptions,
+	watcherStore store.WorkClientWatcherStore,
+	resyncOptions ResyncOptions,
+	codecs ...generic.Codec[*workv1.ManifestWork],
+) (workclientset.Interface, error) {
+	cloudEventsClient, err := generic.NewCloudEventAgentClient[*workv1.ManifestWork](
+		ctx,
+		agentOptions,
+		agentlister.NewWatcherStoreLister(watcherStore),
+		cloudeventswork.ManifestWorkStatusHash,
+		codecs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// start to subscribe
+	cloudEventsClient.Subscribe(ctx, newDeduplicatedWorkHandler(watcherStore))
+
+	manifestWorkClient := agentclient.NewManifestWorkAgentClient(cloudEventsClient, watcherStore, agentOptions.ClusterName)
+
+	// start a go routine to resync the works when the client is reconnected or the resync interval is reached
+	go runResync(ctx, resyncOptions, cloudEventsClient.ReconnectedChan(), func(ctx context.Context) error {
+		// TODO after supporting multiple sources, we should only resync agent known sources
+		return cloudEventsClient.Resync(ctx, types.SourceAll)
+	})
+
+	// start a go routine to resync the works after this client's store is initiated
+	go func() {
+		if store.WaitForStoreInit(ctx, watcherStore.HasInitiated) {
+			if err := cloudEventsClient.Resync(ctx, types.SourceAll); err != nil {
+				klog.Errorf("failed to send resync request, %v", err)
+			}
+		}
+	}()
+
+	return &workClientSetWrapper{
+		workV1ClientWrapper: newSharedWorkV1ClientWrapper(manifestWorkClient),
+	}, nil
+}
+
+// runResync sends a resync request once the client is reconnected or the resync interval is reached. The
+// reconnected signals received within the resync window are merged into one resync request.
+func runResync(ctx context.Context, resyncOptions ResyncOptions, reconnectedChan <-chan struct{},
+	resync func(ctx context.Context) error) {
+	var resyncTicker <-chan time.Time
+	if resyncOptions.Interval > 0 {
+		ticker := time.NewTicker(resyncOptions.Interval)
+		defer ticker.Stop()
+		resyncTicker = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reconnectedChan:
+		case <-resyncTicker:
+		}
+
+		if !waitResyncWindow(ctx, resyncOptions.Window, reconnectedChan) {
+			return
+		}
+
+		if err := resync(ctx); err != nil {
+			klog.Errorf("failed to send resync request, %v", err)
+		}
+	}
+}
+
+// waitResyncWindow waits for the resync window, the reconnected signals are drained during the window so the
+// cloudevents client is not blocked. It returns false if the context is done.
+func waitResyncWindow(ctx context.Context, window time.Duration, reconnectedChan <-chan struct{}) bool {
+	if window <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-reconnectedChan:
+			klog.V(4).Infof("the client is reconnected within the resync window, merge the resync requests")
+		case <-timer.C:
+			return true
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cloudevents
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestRunResync(t *testing.T) {
+	cases := []struct {
+		name            string
+		resyncOptions   ResyncOptions
+		reconnects      int
+		expectedResyncs int32
+	}{
+		{
+			name:            "resync for each reconnect",
+			reconnects:      3,
+			expectedResyncs: 3,
+		},
+		{
+			name:            "merge the reconnects in the resync window",
+			resyncOptions:   ResyncOptions{Window: 500 * time.Millisecond},
+			reconnects:      3,
+			expectedResyncs: 1,
+		},
+		{
+			name:            "periodic resync",
+			resyncOptions:   ResyncOptions{Interval: 100 * time.Millisecond},
+			expectedResyncs: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var resyncs int32
+			reconnectedChan := make(chan struct{})
+			go runResync(ctx, c.resyncOptions, reconnectedChan, func(ctx context.Context) error {
+				atomic.AddInt32(&resyncs, 1)
+				return nil
+			})
+
+			for i := 0; i < c.reconnects; i++ {
+				reconnectedChan <- struct{}{}
+			}
+
+			if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 2*time.Second, true,
+				func(ctx context.Context) (bool, error) {
+					return atomic.LoadInt32(&resyncs) >= c.expectedResyncs, nil
+				}); err != nil {
+				t.Errorf("expected %d resyncs, but got %d", c.expectedResyncs, atomic.LoadInt32(&resyncs))
+			}
+
+			if c.resyncOptions.Interval == 0 {
+				// no more resyncs are expected
+				time.Sleep(c.resyncOptions.Window + 100*time.Millisecond)
+				if actual := atomic.LoadInt32(&resyncs); actual != c.expectedResyncs {
+					t.Errorf("expected %d resyncs, but got %d", c.expectedResyncs, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cloudevents
+
+import (
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1alpha1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1alpha1"
+)
+
+// namespacedManifestWorkClient is a manifestwork agent or source client, which is scoped to a namespace by setting
+// the namespace.
+type namespacedManifestWorkClient interface {
+	workv1client.ManifestWorkInterface
+	SetNamespace(namespace string)
+}
+
+// workClientSetWrapper wraps a manifestwork agent or source client to a work clientset interface, so the manifestwork
+// informer factory can be built with it.
+type workClientSetWrapper struct {
+	workV1ClientWrapper *workV1ClientWrapper
+}
+
+var _ workclientset.Interface = &workClientSetWrapper{}
+
+func (c *workClientSetWrapper) WorkV1() workv1client.WorkV1Interface {
+	return c.workV1ClientWrapper
+}
+
+func (c *workClientSetWrapper) WorkV1alpha1() workv1alpha1client.WorkV1alpha1Interface {
+	return nil
+}
+
+func (c *workClientSetWrapper) Discovery() discovery.DiscoveryInterface {
+	return nil
+}
+
+// workV1ClientWrapper wraps a manifestwork agent or source client to a WorkV1Interface
+type workV1ClientWrapper struct {
+	manifestWorks func(namespace string) workv1client.ManifestWorkInterface
+}
+
+var _ workv1client.WorkV1Interface = &workV1ClientWrapper{}
+
+// newSharedWorkV1ClientWrapper returns a WorkV1Interface which scopes the shared manifestwork client to the namespace
+// of each call, it is only used by the agents, which access the works in the namespace of their cluster only.
+func newSharedWorkV1ClientWrapper(manifestWorkClient namespacedManifestWorkClient) *workV1ClientWrapper {
+	return &workV1ClientWrapper{
+		manifestWorks: func(namespace string) workv1client.ManifestWorkInterface {
+			manifestWorkClient.SetNamespace(namespace)
+			return manifestWorkClient
+		},
+	}
+}
+
+func (c *workV1ClientWrapper) ManifestWorks(namespace string) workv1client.ManifestWorkInterface {
+	return c.manifestWorks(namespace)
+}
+
+func (c *workV1ClientWrapper) AppliedManifestWorks() workv1client.AppliedManifestWorkInterface {
+	return nil
+}
+
+func (c *workV1ClientWrapper) RESTClient() rest.Interface {
+	return nil
+}
+
+// WrapManifestWorks returns a work clientset whose manifestwork clients are wrapped with the wrap func, e.g. to limit
+// the requests of a work source.
+func WrapManifestWorks(clientSet workclientset.Interface,
+	wrap func(namespace string, client workv1client.ManifestWorkInterface) workv1client.ManifestWorkInterface) workclientset.Interface {
+	return &workClientSetWrapper{
+		workV1ClientWrapper: &workV1ClientWrapper{
+			manifestWorks: func(namespace string) workv1client.ManifestWorkInterface {
+				return wrap(namespace, clientSet.WorkV1().ManifestWorks(namespace))
+			},
+		},
+	}
+}
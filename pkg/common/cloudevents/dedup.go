@@ -0,0 +1,51 @@
+package cloudevents
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
+)
+
+// newDeduplicatedWorkHandler returns a work handler that ignores the duplicated spec events before handling them
+// with the watcher store. The duplicated events may be delivered by the broker after the agent reconnects or
+// be replayed by the sources, handling them causes redundant applies on the managed cluster.
+func newDeduplicatedWorkHandler(watcherStore store.WorkClientWatcherStore) generic.ResourceHandler[*workv1.ManifestWork] {
+	return func(action types.ResourceAction, work *workv1.ManifestWork) error {
+		lastWork, err := watcherStore.Get(work.Namespace, work.Name)
+		switch {
+		case errors.IsNotFound(err):
+			return watcherStore.HandleReceivedWork(action, work)
+		case err != nil:
+			return err
+		}
+
+		if isDuplicatedWork(action, lastWork, work) {
+			klog.V(4).Infof("ignore the duplicated %s event of the work %s/%s with resource version %s",
+				action, work.Namespace, work.Name, work.ResourceVersion)
+			return nil
+		}
+
+		return watcherStore.HandleReceivedWork(action, work)
+	}
+}
+
+// isDuplicatedWork checks whether the received work has been handled. The works whose resource version is not
+// increased are ignored by the cloudevents client already, except the works that are not versioned by the source (the
+// resource version is always "0"), so the spec of these works are compared with the last one.
+func isDuplicatedWork(action types.ResourceAction, lastWork, work *workv1.ManifestWork) bool {
+	switch action {
+	case types.Modified:
+		return lastWork.ResourceVersion == work.ResourceVersion &&
+			lastWork.DeletionTimestamp.IsZero() &&
+			equality.Semantic.DeepEqual(lastWork.Spec, work.Spec)
+	case types.Deleted:
+		return !lastWork.DeletionTimestamp.IsZero()
+	default:
+		return false
+	}
+}
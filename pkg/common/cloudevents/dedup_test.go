@@ -0,0 +1,121 @@
+package cloudevents
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
+)
+
+func newWork(resourceVersion string, deleting bool) *workv1.ManifestWork {
+	work := newSpecWork()
+	work.Name = string(work.UID)
+	work.ResourceVersion = resourceVersion
+	if deleting {
+		work.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	}
+	return work
+}
+
+func TestIsDuplicatedWork(t *testing.T) {
+	changedWork := newWork("0", false)
+	changedWork.Spec.DeleteOption = &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan}
+
+	cases := []struct {
+		name       string
+		action     types.ResourceAction
+		lastWork   *workv1.ManifestWork
+		work       *workv1.ManifestWork
+		duplicated bool
+	}{
+		{
+			name:       "unversioned work is not changed",
+			action:     types.Modified,
+			lastWork:   newWork("0", false),
+			work:       newWork("0", false),
+			duplicated: true,
+		},
+		{
+			name:     "unversioned work is changed",
+			action:   types.Modified,
+			lastWork: newWork("0", false),
+			work:     changedWork,
+		},
+		{
+			name:     "work version is increased",
+			action:   types.Modified,
+			lastWork: newWork("1", false),
+			work:     newWork("2", false),
+		},
+		{
+			name:     "work starts deleting",
+			action:   types.Deleted,
+			lastWork: newWork("1", false),
+			work:     newWork("1", true),
+		},
+		{
+			name:       "work is deleting already",
+			action:     types.Deleted,
+			lastWork:   newWork("1", true),
+			work:       newWork("1", true),
+			duplicated: true,
+		},
+		{
+			name:     "work is added",
+			action:   types.Added,
+			lastWork: newWork("1", false),
+			work:     newWork("1", false),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if duplicated := isDuplicatedWork(c.action, c.lastWork, c.work); duplicated != c.duplicated {
+				t.Errorf("expected duplicated %v, but got %v", c.duplicated, duplicated)
+			}
+		})
+	}
+}
+
+func TestDeduplicatedWorkHandler(t *testing.T) {
+	watcherStore := store.NewAgentInformerWatcherStore()
+	workStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	watcherStore.SetStore(workStore)
+
+	watcher, err := watcherStore.GetWatcher("cluster1", metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	handler := newDeduplicatedWorkHandler(watcherStore)
+
+	go func() {
+		if err := handler(types.Added, newWork("0", false)); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}()
+	evt := <-watcher.ResultChan()
+	if evt.Type != watch.Added {
+		t.Fatalf("expected added event, but got %v", evt.Type)
+	}
+	if err := workStore.Add(evt.Object); err != nil {
+		t.Fatal(err)
+	}
+
+	// the duplicated work is ignored
+	if err := handler(types.Modified, newWork("0", false)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	select {
+	case evt := <-watcher.ResultChan():
+		t.Errorf("unexpected event %v", evt)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
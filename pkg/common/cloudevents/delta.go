@@ -0,0 +1,294 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+	jsonpatch "github.com/evanphx/json-patch"
+	jsonpatchcreator "gomodules.xyz/jsonpatch/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+const (
+	// ExtensionDeltaBase is the cloudevents extension to indicate the event data is a JSON patch (RFC 6902) against
+	// the data of the same resource with the resource version in this extension.
+	ExtensionDeltaBase = "deltabase"
+
+	// maxDeltaRatio is the max ratio of the patch size to the full data size, the full data is sent if the patch is
+	// larger, since applying a large patch saves little bandwidth.
+	maxDeltaRatio = 0.5
+)
+
+// deltaRecord is the last full data of a resource sent or received.
+type deltaRecord struct {
+	resourceVersion int32
+	data            []byte
+}
+
+// DeltaCodec wraps a manifestwork codec, the spec of a manifestwork is sent as a JSON patch against the last
+// generation sent to the agent if the patch is much smaller than the full spec, and the agent applies the patch to the
+// last generation it received. The full spec is always sent for the resync responses, so the agent recovers from a
+// lost event with the next resync. The events of the status are not changed.
+//
+// The codec must be used by both the source and the agent, and it must be wrapped by the encryption codec if the
+// events are encrypted, so the patch is encrypted as well. On the source, the encoded spec is only used as the base
+// of the next patches once it is published, so the work clientset of the source must be wrapped by WrapClientSet.
+type DeltaCodec struct {
+	generic.Codec[*workv1.ManifestWork]
+
+	lock    sync.Mutex
+	records map[string]deltaRecord
+	// pending is the data of the resources encoded but not published yet
+	pending map[string]deltaRecord
+}
+
+// NewDeltaCodec returns a codec that sends/receives the spec of the manifestworks as JSON patches.
+func NewDeltaCodec(codec generic.Codec[*workv1.ManifestWork]) *DeltaCodec {
+	return &DeltaCodec{
+		Codec:   codec,
+		records: map[string]deltaRecord{},
+		pending: map[string]deltaRecord{},
+	}
+}
+
+// Encode replaces the data of the spec event encoded by the wrapped codec with a JSON patch if it is small enough.
+func (c *DeltaCodec) Encode(source string, eventType types.CloudEventsType, work *workv1.ManifestWork) (*cloudevents.Event, error) {
+	evt, err := c.Codec.Encode(source, eventType, work)
+	if err != nil {
+		return nil, err
+	}
+	if eventType.SubResource != types.SubResourceSpec {
+		return evt, nil
+	}
+
+	resourceID, resourceVersion, err := eventResource(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, deleting := evt.Extensions()[types.ExtensionDeletionTimestamp]; deleting || len(evt.Data()) == 0 {
+		c.forget(resourceID)
+		return evt, nil
+	}
+
+	data := evt.Data()
+
+	// the resync responses are not published by the work client, so they are committed once encoded, the agent
+	// resyncs again if they are lost.
+	if eventType.Action == types.ResyncResponseAction {
+		c.records[resourceID] = deltaRecord{resourceVersion: resourceVersion, data: data}
+		delete(c.pending, resourceID)
+		return evt, nil
+	}
+
+	last, ok := c.records[resourceID]
+	c.pending[resourceID] = deltaRecord{resourceVersion: resourceVersion, data: data}
+	if !ok || last.resourceVersion == resourceVersion {
+		return evt, nil
+	}
+
+	patch, err := createPatch(last.data, data)
+	if err != nil {
+		klog.Warningf("failed to create patch for the event of resource %s, send the full data: %v", resourceID, err)
+		return evt, nil
+	}
+	if float64(len(patch)) > float64(len(data))*maxDeltaRatio {
+		return evt, nil
+	}
+
+	evt.SetExtension(ExtensionDeltaBase, last.resourceVersion)
+	if err := evt.SetData(cloudevents.ApplicationJSON, patch); err != nil {
+		return nil, fmt.Errorf("failed to set patch to the event: %v", err)
+	}
+	return evt, nil
+}
+
+// Decode applies the JSON patch in the event to the last received data of the resource and decodes the event with
+// the wrapped codec. The event is rejected if the last received data is not the base of the patch.
+func (c *DeltaCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWork, error) {
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, err
+	}
+	if eventType.SubResource != types.SubResourceSpec {
+		return c.Codec.Decode(evt)
+	}
+
+	resourceID, resourceVersion, err := eventResource(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, deleting := evt.Extensions()[types.ExtensionDeletionTimestamp]; deleting || len(evt.Data()) == 0 {
+		c.forget(resourceID)
+		return c.Codec.Decode(evt)
+	}
+
+	data := evt.Data()
+	if base, ok := evt.Extensions()[ExtensionDeltaBase]; ok {
+		baseVersion, err := cloudeventstypes.ToInteger(base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deltabase extension: %v", err)
+		}
+		last, ok := c.records[resourceID]
+		if !ok || last.resourceVersion != baseVersion {
+			return nil, fmt.Errorf("the base %d of the event %s is not received, wait for the resync", baseVersion, evt.ID())
+		}
+
+		patch, err := jsonpatch.DecodePatch(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the patch of the event %s: %v", evt.ID(), err)
+		}
+		if data, err = patch.Apply(last.data); err != nil {
+			return nil, fmt.Errorf("failed to apply the patch of the event %s: %v", evt.ID(), err)
+		}
+
+		patchedEvt := evt.Clone()
+		patchedEvt.SetExtension(ExtensionDeltaBase, nil)
+		if err := patchedEvt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+			return nil, fmt.Errorf("failed to set patched data to the event: %v", err)
+		}
+		evt = &patchedEvt
+	}
+
+	work, err := c.Codec.Decode(evt)
+	if err != nil {
+		return nil, err
+	}
+	// the out-of-order events are ignored by the client, keep the latest one as the base of the next patches
+	if last, ok := c.records[resourceID]; !ok || resourceVersion >= last.resourceVersion {
+		c.records[resourceID] = deltaRecord{resourceVersion: resourceVersion, data: data}
+	}
+	return work, nil
+}
+
+// commit uses the pending data of the resource with the resource version as the base of the next patches, it is
+// called once the data is published.
+func (c *DeltaCodec) commit(resourceID string, resourceVersion string) {
+	version, err := strconv.ParseInt(resourceVersion, 10, 32)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if pending, ok := c.pending[resourceID]; ok && pending.resourceVersion == int32(version) {
+		c.records[resourceID] = pending
+		delete(c.pending, resourceID)
+	}
+}
+
+// forget removes the records of the resource, the caller must hold the lock.
+func (c *DeltaCodec) forget(resourceID string) {
+	delete(c.records, resourceID)
+	delete(c.pending, resourceID)
+}
+
+// EvictionHandler returns an event handler of the manifestwork informer, which removes the records of the deleted
+// works, e.g. the works removed by a resync, whose deletion events are never received.
+func (c *DeltaCodec) EvictionHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			work, ok := obj.(*workv1.ManifestWork)
+			if !ok {
+				return
+			}
+
+			c.lock.Lock()
+			defer c.lock.Unlock()
+			c.forget(string(work.UID))
+		},
+	}
+}
+
+// WrapClientSet returns a work clientset of the source, which commits the spec of the works encoded by the codec
+// once they are published by the given clientset.
+func (c *DeltaCodec) WrapClientSet(clientSet workclientset.Interface) workclientset.Interface {
+	return &workClientSetWrapper{
+		workV1ClientWrapper: &workV1ClientWrapper{
+			manifestWorks: func(namespace string) workv1client.ManifestWorkInterface {
+				return &deltaManifestWorkClient{
+					ManifestWorkInterface: clientSet.WorkV1().ManifestWorks(namespace),
+					codec:                 c,
+				}
+			},
+		},
+	}
+}
+
+// deltaManifestWorkClient commits the spec of the works created or patched by the source work client, which returns
+// the work only if its spec event is published.
+type deltaManifestWorkClient struct {
+	workv1client.ManifestWorkInterface
+	codec *DeltaCodec
+}
+
+func (c *deltaManifestWorkClient) Create(
+	ctx context.Context, work *workv1.ManifestWork, opts metav1.CreateOptions) (*workv1.ManifestWork, error) {
+	created, err := c.ManifestWorkInterface.Create(ctx, work, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.codec.commit(string(created.UID), created.ResourceVersion)
+	return created, nil
+}
+
+func (c *deltaManifestWorkClient) Patch(ctx context.Context, name string, pt kubetypes.PatchType, data []byte,
+	opts metav1.PatchOptions, subresources ...string) (*workv1.ManifestWork, error) {
+	patched, err := c.ManifestWorkInterface.Patch(ctx, name, pt, data, opts, subresources...)
+	if err != nil {
+		return nil, err
+	}
+	c.codec.commit(string(patched.UID), patched.ResourceVersion)
+	return patched, nil
+}
+
+// eventResource returns the resource id and the resource version of the event.
+func eventResource(evt *cloudevents.Event) (string, int32, error) {
+	evtExtensions := evt.Context.GetExtensions()
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := cloudeventstypes.ToInteger(evtExtensions[types.ExtensionResourceVersion])
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get resourceversion extension: %v", err)
+	}
+
+	return resourceID, resourceVersion, nil
+}
+
+// createPatch returns the JSON patch from the original data to the modified data.
+func createPatch(original, modified []byte) ([]byte, error) {
+	operations, err := jsonpatchcreator.CreatePatch(original, modified)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(operations)
+}
@@ -0,0 +1,216 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	agentcodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/codec"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	sourcecodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
+)
+
+func newLargeSpecWork(resourceVersion int, data string) *workv1.ManifestWork {
+	work := newSpecWork()
+	work.ResourceVersion = fmt.Sprintf("%d", resourceVersion)
+	work.Generation = int64(resourceVersion)
+	for i := 0; i < 10; i++ {
+		// only the data of the last configmap changes
+		value := "static"
+		if i == 9 {
+			value = data
+		}
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, workv1.Manifest{
+			RawExtension: runtime.RawExtension{Raw: []byte(fmt.Sprintf(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test%d","namespace":"default"},"data":{"key":"%s"}}`,
+				i, value))},
+		})
+	}
+	return work
+}
+
+func assertConfigMapData(t *testing.T, work *workv1.ManifestWork, expected string) {
+	t.Helper()
+	if len(work.Spec.Workload.Manifests) != 11 {
+		t.Fatalf("unexpected work %v", work)
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := json.Unmarshal(work.Spec.Workload.Manifests[10].Raw, configMap); err != nil {
+		t.Fatal(err)
+	}
+	if configMap.Name != "test9" || configMap.Data["key"] != expected {
+		t.Errorf("expected configmap test9 with data %s, but got %v", expected, configMap)
+	}
+}
+
+func TestDeltaCodec(t *testing.T) {
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}
+	resyncEventType := specEventType
+	resyncEventType.Action = types.ResyncResponseAction
+
+	sourceCodec := NewDeltaCodec(sourcecodec.NewManifestBundleCodec())
+	agentCodec := NewDeltaCodec(agentcodec.NewManifestBundleCodec())
+
+	send := func(eventType types.CloudEventsType, work *workv1.ManifestWork, expectedDelta bool) *cloudevents.Event {
+		t.Helper()
+		evt, err := sourceCodec.Encode("source1", eventType, work)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if _, delta := evt.Extensions()[ExtensionDeltaBase]; delta != expectedDelta {
+			t.Errorf("expected delta %v, but got %v", expectedDelta, delta)
+		}
+		// the event is published
+		sourceCodec.commit(string(work.UID), work.ResourceVersion)
+		return evt
+	}
+
+	// the full spec is sent the first time
+	work, err := agentCodec.Decode(send(specEventType, newLargeSpecWork(1, "v1"), false))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertConfigMapData(t, work, "v1")
+
+	// the patch is sent when a small part of the spec changes
+	work, err = agentCodec.Decode(send(specEventType, newLargeSpecWork(2, "v2"), true))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertConfigMapData(t, work, "v2")
+	if work.ResourceVersion != "2" {
+		t.Errorf("expected resource version 2, but got %s", work.ResourceVersion)
+	}
+
+	// the patch is rejected if its base is lost
+	send(specEventType, newLargeSpecWork(3, "v3"), true)
+	if _, err := agentCodec.Decode(send(specEventType, newLargeSpecWork(4, "v4"), true)); err == nil {
+		t.Errorf("expected error, but failed")
+	}
+
+	// the full spec is sent with the resync
+	work, err = agentCodec.Decode(send(resyncEventType, newLargeSpecWork(4, "v4"), false))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertConfigMapData(t, work, "v4")
+
+	// the spec is not the base of the next patches if it is not published
+	if _, err := sourceCodec.Encode("source1", specEventType, newLargeSpecWork(5, "v5")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	evt := send(specEventType, newLargeSpecWork(6, "v6"), true)
+	if base := evt.Extensions()[ExtensionDeltaBase]; base != int32(4) {
+		t.Errorf("expected the patch against the resource version 4, but got %v", base)
+	}
+	work, err = agentCodec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assertConfigMapData(t, work, "v6")
+
+	// the full spec is sent if the most of the spec changes
+	changed := newLargeSpecWork(7, "v7")
+	for i := range changed.Spec.Workload.Manifests {
+		changed.Spec.Workload.Manifests[i].Raw = []byte(fmt.Sprintf(
+			`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"secret%d","namespace":"default"}}`, i))
+	}
+	if _, err := agentCodec.Decode(send(specEventType, changed, false)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the records are removed once the work is deleted
+	deleting := newLargeSpecWork(8, "v7")
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	if _, err := agentCodec.Decode(send(specEventType, deleting, false)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(sourceCodec.records) != 0 || len(sourceCodec.pending) != 0 || len(agentCodec.records) != 0 {
+		t.Errorf("expected the records are removed")
+	}
+}
+
+func TestDeltaCodecEviction(t *testing.T) {
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}
+
+	agentCodec := NewDeltaCodec(agentcodec.NewManifestBundleCodec())
+	evt, err := NewDeltaCodec(sourcecodec.NewManifestBundleCodec()).Encode("source1", specEventType, newLargeSpecWork(1, "v1"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	work, err := agentCodec.Decode(evt)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(agentCodec.records) != 1 {
+		t.Errorf("expected the work is recorded")
+	}
+
+	// the work is removed from the informer by a resync
+	agentCodec.EvictionHandler().OnDelete(cache.DeletedFinalStateUnknown{Key: "cluster1/test", Obj: work})
+	if len(agentCodec.records) != 0 {
+		t.Errorf("expected the records are removed")
+	}
+}
+
+func TestDeltaCodecWithEncryption(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "delta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	keyFile := newEncryptionKeyFile(t, tempDir, "cluster1")
+
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "update_request",
+	}
+
+	sourceDeltaCodec := NewDeltaCodec(sourcecodec.NewManifestBundleCodec())
+	sourceCodec := NewEncryptionCodec(sourceDeltaCodec, NewDirEncryptionKeyGetter(tempDir))
+	agentCodec := NewEncryptionCodec(NewDeltaCodec(agentcodec.NewManifestBundleCodec()), NewFileEncryptionKeyGetter(keyFile))
+
+	for i, data := range []string{"v1", "v2"} {
+		work := newLargeSpecWork(i+1, data)
+		evt, err := sourceCodec.Encode("source1", specEventType, work)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		sourceDeltaCodec.commit(string(work.UID), work.ResourceVersion)
+		received, err := agentCodec.Decode(evt)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		assertConfigMapData(t, received, data)
+	}
+
+	// the base of the patch is authenticated
+	evt, err := sourceCodec.Encode("source1", specEventType, newLargeSpecWork(3, "v3"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	evt.SetExtension(ExtensionDeltaBase, 1)
+	if _, err := agentCodec.Decode(evt); err == nil {
+		t.Errorf("expected error, but failed")
+	}
+}
@@ -0,0 +1,271 @@
+package cloudevents
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cloudeventstypes "github.com/cloudevents/sdk-go/v2/types"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	sourcecodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
+)
+
+const (
+	// ExtensionEncryption is the cloudevents extension to indicate the event data is encrypted and which
+	// algorithm is used to encrypt it.
+	ExtensionEncryption = "encryption"
+
+	// ExtensionEncryptedKey is the cloudevents extension that contains the data encryption key which is
+	// encrypted by the key of the cluster.
+	ExtensionEncryptedKey = "encryptedkey"
+
+	// EnvelopeAES256GCM is the envelope encryption algorithm, the event data is encrypted with a random data key
+	// by AES-256-GCM, and the data key is encrypted with the key of the cluster by AES-256-GCM.
+	EnvelopeAES256GCM = "envelope-aes256gcm"
+
+	// encryptionKeySize is the size of both the cluster key and the data key.
+	encryptionKeySize = 32
+)
+
+// EncryptionKeyGetter returns the key that is used to encrypt/decrypt the events of a given cluster.
+type EncryptionKeyGetter func(clusterName string) ([]byte, error)
+
+// NewFileEncryptionKeyGetter returns an EncryptionKeyGetter that reads the key of a cluster from a file, the file
+// contains a base64 encoded 32 bytes key.
+func NewFileEncryptionKeyGetter(keyFile string) EncryptionKeyGetter {
+	return func(_ string) ([]byte, error) {
+		return loadEncryptionKey(keyFile)
+	}
+}
+
+// NewDirEncryptionKeyGetter returns an EncryptionKeyGetter that reads the key of a cluster from a file whose name is
+// the cluster name in the given directory. This is used by the sources which send works to multiple clusters.
+func NewDirEncryptionKeyGetter(keyDir string) EncryptionKeyGetter {
+	return func(clusterName string) ([]byte, error) {
+		if len(clusterName) == 0 || strings.Contains(clusterName, "/") {
+			return nil, fmt.Errorf("invalid cluster name %q", clusterName)
+		}
+		return loadEncryptionKey(path.Join(keyDir, clusterName))
+	}
+}
+
+func loadEncryptionKey(keyFile string) ([]byte, error) {
+	data, err := os.ReadFile(path.Clean(keyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key file %s: %v", keyFile, err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption key file %s: %v", keyFile, err)
+	}
+
+	if len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("the encryption key in %s must be %d bytes, but got %d", keyFile, encryptionKeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// encryptionCodec wraps a manifestwork codec, the data of the encoded events is encrypted by the key of the cluster,
+// and the received events are decrypted and verified before they are decoded. The received events without encryption
+// are rejected, so a broker cannot inject or tamper with the workload definitions.
+type encryptionCodec struct {
+	generic.Codec[*workv1.ManifestWork]
+	keyGetter EncryptionKeyGetter
+}
+
+// NewEncryptionCodec returns a codec that encrypts/decrypts the event data of the given codec.
+func NewEncryptionCodec(codec generic.Codec[*workv1.ManifestWork],
+	keyGetter EncryptionKeyGetter) generic.Codec[*workv1.ManifestWork] {
+	return &encryptionCodec{
+		Codec:     codec,
+		keyGetter: keyGetter,
+	}
+}
+
+// Encode encrypts the data of the event encoded by the wrapped codec.
+func (c *encryptionCodec) Encode(source string, eventType types.CloudEventsType, work *workv1.ManifestWork) (*cloudevents.Event, error) {
+	evt, err := c.Codec.Encode(source, eventType, work)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterName, additionalData, err := eventAdditionalData(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterKey, err := c.keyGetter(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	// the encrypted data key is always set, so the events without data (e.g. the deleting event) can be verified
+	encryptedKey, err := seal(clusterKey, dataKey, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	evt.SetExtension(ExtensionEncryption, EnvelopeAES256GCM)
+	evt.SetExtension(ExtensionEncryptedKey, base64.StdEncoding.EncodeToString(encryptedKey))
+
+	if len(evt.Data()) == 0 {
+		return evt, nil
+	}
+
+	encryptedData, err := seal(dataKey, evt.Data(), additionalData)
+	if err != nil {
+		return nil, err
+	}
+	if err := evt.SetData("application/octet-stream", encryptedData); err != nil {
+		return nil, fmt.Errorf("failed to set encrypted data to the event: %v", err)
+	}
+
+	return evt, nil
+}
+
+// Decode decrypts the data of the event and decodes the event with the wrapped codec.
+func (c *encryptionCodec) Decode(evt *cloudevents.Event) (*workv1.ManifestWork, error) {
+	evtExtensions := evt.Context.GetExtensions()
+	encryption, err := cloudeventstypes.ToString(evtExtensions[ExtensionEncryption])
+	if err != nil {
+		return nil, fmt.Errorf("the event %s is rejected, it is not encrypted", evt.ID())
+	}
+	if encryption != EnvelopeAES256GCM {
+		return nil, fmt.Errorf("unsupported encryption %q of the event %s", encryption, evt.ID())
+	}
+
+	encodedKey, err := cloudeventstypes.ToString(evtExtensions[ExtensionEncryptedKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryptedkey extension: %v", err)
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryptedkey extension: %v", err)
+	}
+
+	clusterName, additionalData, err := eventAdditionalData(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterKey, err := c.keyGetter(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := open(clusterKey, encryptedKey, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data key of the event %s: %v", evt.ID(), err)
+	}
+
+	if len(evt.Data()) == 0 {
+		return c.Codec.Decode(evt)
+	}
+
+	data, err := open(dataKey, evt.Data(), additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data of the event %s: %v", evt.ID(), err)
+	}
+
+	decryptedEvt := evt.Clone()
+	if err := decryptedEvt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to set decrypted data to the event: %v", err)
+	}
+
+	return c.Codec.Decode(&decryptedEvt)
+}
+
+// eventAdditionalData returns the cluster name of the event and the additional data that is authenticated together
+// with the event data, so the encrypted data cannot be replayed for another resource or cluster.
+func eventAdditionalData(evt *cloudevents.Event) (string, []byte, error) {
+	evtExtensions := evt.Context.GetExtensions()
+
+	clusterName, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionClusterName])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get clustername extension: %v", err)
+	}
+
+	resourceID, err := cloudeventstypes.ToString(evtExtensions[types.ExtensionResourceID])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get resourceid extension: %v", err)
+	}
+
+	resourceVersion, err := cloudeventstypes.ToInteger(evtExtensions[types.ExtensionResourceVersion])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get resourceversion extension: %v", err)
+	}
+
+	additionalData := fmt.Sprintf("%s/%s/%d/%s", clusterName, resourceID, resourceVersion, evt.Type())
+
+	// the work meta is sent from the source together with the work spec, authenticate it as well
+	if _, ok := evtExtensions[sourcecodec.ExtensionWorkMeta]; ok {
+		workMeta, err := cloudeventstypes.ToString(evtExtensions[sourcecodec.ExtensionWorkMeta])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get workmeta extension: %v", err)
+		}
+		additionalData = fmt.Sprintf("%s/%s", additionalData, workMeta)
+	}
+
+	// the base of the patch is authenticated, so the patch cannot be applied to another resource version
+	if _, ok := evtExtensions[ExtensionDeltaBase]; ok {
+		deltaBase, err := cloudeventstypes.ToInteger(evtExtensions[ExtensionDeltaBase])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get deltabase extension: %v", err)
+		}
+		additionalData = fmt.Sprintf("%s/%d", additionalData, deltaBase)
+	}
+
+	return clusterName, []byte(additionalData), nil
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("the ciphertext is too short")
+	}
+
+	nonce, data := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, data, additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,191 @@
+package cloudevents
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	agentcodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/codec"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/payload"
+	sourcecodec "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
+)
+
+func newEncryptionKeyFile(t *testing.T, dir, name string) string {
+	key := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	keyFile := path.Join(dir, name)
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return keyFile
+}
+
+func newSpecWork() *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:             "test-uid",
+			ResourceVersion: "1",
+			Name:            "test",
+			Namespace:       "cluster1",
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(
+						`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"}}`)}},
+				},
+			},
+		},
+	}
+}
+
+func TestEncryptionCodec(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	keyFile := newEncryptionKeyFile(t, tempDir, "cluster1")
+	otherKeyFile := newEncryptionKeyFile(t, tempDir, "other")
+
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+
+	sourceCodec := NewEncryptionCodec(sourcecodec.NewManifestBundleCodec(), NewDirEncryptionKeyGetter(tempDir))
+	agentCodec := NewEncryptionCodec(agentcodec.NewManifestBundleCodec(), NewFileEncryptionKeyGetter(keyFile))
+
+	cases := []struct {
+		name        string
+		agentCodec  func() *encryptionCodec
+		unencrypted bool
+		expectedErr bool
+	}{
+		{
+			name: "decrypt works",
+		},
+		{
+			name:        "reject unencrypted works",
+			unencrypted: true,
+			expectedErr: true,
+		},
+		{
+			name: "reject works encrypted by other keys",
+			agentCodec: func() *encryptionCodec {
+				return NewEncryptionCodec(agentcodec.NewManifestBundleCodec(),
+					NewFileEncryptionKeyGetter(otherKeyFile)).(*encryptionCodec)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			codec := agentCodec.(*encryptionCodec)
+			if c.agentCodec != nil {
+				codec = c.agentCodec()
+			}
+
+			evt, err := sourceCodec.Encode("source1", specEventType, newSpecWork())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if c.unencrypted {
+				evt, err = sourcecodec.NewManifestBundleCodec().Encode("source1", specEventType, newSpecWork())
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+			}
+
+			work, err := codec.Decode(evt)
+			if c.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, but failed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(work.Spec.Workload.Manifests) != 1 {
+				t.Errorf("unexpected work %v", work)
+			}
+		})
+	}
+}
+
+func TestEncryptionCodecTamperedData(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	keyFile := newEncryptionKeyFile(t, tempDir, "cluster1")
+
+	specEventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceSpec,
+		Action:              "create_request",
+	}
+
+	sourceCodec := NewEncryptionCodec(sourcecodec.NewManifestBundleCodec(), NewDirEncryptionKeyGetter(tempDir))
+	agentCodec := NewEncryptionCodec(agentcodec.NewManifestBundleCodec(), NewFileEncryptionKeyGetter(keyFile))
+
+	evt, err := sourceCodec.Encode("source1", specEventType, newSpecWork())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the encrypted data cannot be replayed for another resource
+	evt.SetExtension(types.ExtensionResourceID, "another-uid")
+	if _, err := agentCodec.Decode(evt); err == nil {
+		t.Errorf("expected error, but failed")
+	}
+
+	evt.SetExtension(types.ExtensionResourceID, "test-uid")
+	data := evt.Data()
+	data[len(data)-1] ^= 0xff
+	if _, err := agentCodec.Decode(evt); err == nil {
+		t.Errorf("expected error, but failed")
+	}
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "encryption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	invalidKeyFile := path.Join(tempDir, "invalid")
+	if err := os.WriteFile(invalidKeyFile, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadEncryptionKey(invalidKeyFile); err == nil {
+		t.Errorf("expected error for the short key, but failed")
+	}
+	if _, err := loadEncryptionKey(path.Join(tempDir, "nonexistent")); err == nil {
+		t.Errorf("expected error for the nonexistent key, but failed")
+	}
+	if _, err := NewDirEncryptionKeyGetter(tempDir)("../invalid"); err == nil {
+		t.Errorf("expected error for the invalid cluster name, but failed")
+	}
+	if _, err := loadEncryptionKey(newEncryptionKeyFile(t, tempDir, "cluster1")); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
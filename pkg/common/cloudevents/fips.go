@@ -0,0 +1,32 @@
+package cloudevents
+
+import (
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/mqtt"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+)
+
+// ConfigureFIPS restricts the connection to the cloudevents broker to the FIPS approved algorithms in the FIPS
+// compliance mode. The config is loaded by the generic config loader of the sdk, the connections not encrypted or
+// not implemented with the go crypto are reported as violations.
+func ConfigureFIPS(config any) {
+	if !fips.Enabled() {
+		return
+	}
+
+	switch config := config.(type) {
+	case *mqtt.MQTTOptions:
+		if config.Dialer == nil || config.Dialer.TLSConfig == nil {
+			fips.ReportViolation("the connection to the MQTT broker is not encrypted")
+			return
+		}
+		fips.ConfigureTLS(config.Dialer.TLSConfig)
+	case *grpc.GRPCOptions:
+		if len(config.CAFile) == 0 {
+			fips.ReportViolation("the connection to the gRPC server %s is not encrypted", config.URL)
+		}
+	default:
+		fips.ReportViolation("the connection to the cloudevents broker with %T is not FIPS compliant", config)
+	}
+}
@@ -0,0 +1,273 @@
+package cloudevents
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/cert"
+	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+)
+
+// connectionCheckInterval is the interval to check the state of the grpc client connection.
+var connectionCheckInterval = 100 * time.Millisecond
+
+// rotatingConnection holds the current grpc client connection. It is closed by the client cert rotating controller
+// once the client certificate on disk changes, so that the agent reconnects with the new certificate.
+type rotatingConnection struct {
+	sync.Mutex
+	conn *grpc.ClientConn
+}
+
+func (c *rotatingConnection) set(conn *grpc.ClientConn) {
+	c.Lock()
+	defer c.Unlock()
+	c.conn = conn
+}
+
+func (c *rotatingConnection) Close() error {
+	c.Lock()
+	defer c.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// grpcAgentOptions implements the CloudEventsOptions for a grpc agent client. Compared with the grpc agent options
+// in the sdk, it loads the client certificate with a GetClientCertificate callback, so the certificate rotated by the
+// client certificate controller is picked up without restarting the agent.
+type grpcAgentOptions struct {
+	url         string
+	tlsConfig   *tls.Config
+	dialOptions []grpc.DialOption
+	connection  *rotatingConnection
+	errorChan   chan error
+}
+
+// NewGRPCAgentOptions returns the CloudEventsAgentOptions for a grpc agent client. If the client certificate is
+// specified, the certificate files are reloaded periodically and the connection is re-established with the new
+// certificate once the certificate is rotated.
+func NewGRPCAgentOptions(grpcOptions *grpcoptions.GRPCOptions, transportOptions *GRPCTransportOptions,
+	clusterName, agentID string) (*options.CloudEventsAgentOptions, error) {
+	dialOptions, err := transportOptions.DialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	o := &grpcAgentOptions{
+		url:         grpcOptions.URL,
+		dialOptions: dialOptions,
+		connection:  &rotatingConnection{},
+		errorChan:   make(chan error),
+	}
+
+	if len(grpcOptions.CAFile) != 0 {
+		tlsConfig, err := rotatingTLSConfig(grpcOptions.CAFile, grpcOptions.ClientCertFile, grpcOptions.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		o.tlsConfig = tlsConfig
+
+		// start a goroutine to periodically refresh client certificates for this connection
+		cert.StartClientCertRotating(tlsConfig.GetClientCertificate, o.connection)
+	}
+
+	return &options.CloudEventsAgentOptions{
+		CloudEventsOptions: o,
+		AgentID:            agentID,
+		ClusterName:        clusterName,
+	}, nil
+}
+
+func (o *grpcAgentOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	// grpc agent client doesn't need to update topic in the context
+	return ctx, nil
+}
+
+func (o *grpcAgentOptions) Protocol(ctx context.Context) (options.CloudEventsProtocol, error) {
+	transportCredentials := insecure.NewCredentials()
+	if o.tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(o.tlsConfig)
+	}
+
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(transportCredentials)}, o.dialOptions...)
+	conn, err := grpc.Dial(o.url, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to grpc server %s, %v", o.url, err)
+	}
+	o.connection.set(conn)
+
+	go o.monitorConnection(ctx, conn)
+
+	return protocol.NewProtocol(conn, protocol.WithSubscribeOption(&protocol.SubscribeOption{
+		Source: types.SourceAll,
+	}))
+}
+
+func (o *grpcAgentOptions) ErrorChan() <-chan error {
+	return o.errorChan
+}
+
+// monitorConnection checks the connection state periodically and sends an error to the error chan once the
+// connection is broken or is closed for the certificate rotation, the cloudevents client will reconnect then.
+func (o *grpcAgentOptions) monitorConnection(ctx context.Context, conn *grpc.ClientConn) {
+	ticker := time.NewTicker(connectionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return
+		case <-ticker.C:
+			// For a connected grpc client, if the connection is down, the state will be changed from Ready to Idle,
+			// if the connection is closed by the certificate rotation, the state will be Shutdown.
+			connState := conn.GetState()
+			if connState == connectivity.TransientFailure ||
+				connState == connectivity.Idle ||
+				connState == connectivity.Shutdown {
+				klog.V(4).Infof("grpc connection to %s is disconnected (state=%s)", o.url, connState)
+				conn.Close()
+				select {
+				case o.errorChan <- fmt.Errorf("grpc connection is disconnected (state=%s)", connState):
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}
+}
+
+// grpcSourceOptions implements the CloudEventsOptions for a grpc source client. Compared with the grpc source options
+// in the sdk, the connection is dialed with the grpc transport options.
+type grpcSourceOptions struct {
+	url         string
+	sourceID    string
+	tlsConfig   *tls.Config
+	dialOptions []grpc.DialOption
+	errorChan   chan error
+}
+
+// NewGRPCSourceOptions returns the CloudEventsSourceOptions for a grpc source client.
+func NewGRPCSourceOptions(grpcOptions *grpcoptions.GRPCOptions, transportOptions *GRPCTransportOptions,
+	sourceID string) (*options.CloudEventsSourceOptions, error) {
+	dialOptions, err := transportOptions.DialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	o := &grpcSourceOptions{
+		url:         grpcOptions.URL,
+		sourceID:    sourceID,
+		dialOptions: dialOptions,
+		errorChan:   make(chan error),
+	}
+
+	if len(grpcOptions.CAFile) != 0 {
+		tlsConfig, err := caTLSConfig(grpcOptions.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(grpcOptions.ClientCertFile) != 0 && len(grpcOptions.ClientKeyFile) != 0 {
+			certificate, err := tls.LoadX509KeyPair(grpcOptions.ClientCertFile, grpcOptions.ClientKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{certificate}
+		}
+		o.tlsConfig = tlsConfig
+	}
+
+	return &options.CloudEventsSourceOptions{
+		CloudEventsOptions: o,
+		SourceID:           sourceID,
+	}, nil
+}
+
+func (o *grpcSourceOptions) WithContext(ctx context.Context, evtCtx cloudevents.EventContext) (context.Context, error) {
+	// grpc source client doesn't need to update topic in the context
+	return ctx, nil
+}
+
+func (o *grpcSourceOptions) Protocol(ctx context.Context) (options.CloudEventsProtocol, error) {
+	transportCredentials := insecure.NewCredentials()
+	if o.tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(o.tlsConfig)
+	}
+
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(transportCredentials)}, o.dialOptions...)
+	conn, err := grpc.Dial(o.url, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to grpc server %s, %v", o.url, err)
+	}
+
+	return protocol.NewProtocol(conn, protocol.WithSubscribeOption(&protocol.SubscribeOption{
+		Source: o.sourceID,
+	}))
+}
+
+func (o *grpcSourceOptions) ErrorChan() <-chan error {
+	// the grpc client connection reconnects automatically, so no error is sent
+	return o.errorChan
+}
+
+func rotatingTLSConfig(caFile, clientCertFile, clientKeyFile string) (*tls.Config, error) {
+	tlsConfig, err := caTLSConfig(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clientCertFile) == 0 || len(clientKeyFile) == 0 {
+		return nil, fmt.Errorf("both clientCertFile and clientKeyFile are required when caFile is set")
+	}
+
+	loader := cert.CachingCertificateLoader(clientCertFile, clientKeyFile)
+	tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return loader()
+	}
+
+	return tlsConfig, nil
+}
+
+// caTLSConfig returns the TLS config verifying the grpc server with the CA file.
+func caTLSConfig(caFile string) (*tls.Config, error) {
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok := certPool.AppendCertsFromPEM(caPEM); !ok {
+		return nil, fmt.Errorf("invalid CA %s", caFile)
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    certPool,
+		MinVersion: tls.VersionTLS13,
+		MaxVersion: tls.VersionTLS13,
+	}
+	fips.ConfigureTLS(tlsConfig)
+
+	return tlsConfig, nil
+}
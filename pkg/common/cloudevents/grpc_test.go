@@ -0,0 +1,224 @@
+package cloudevents
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func TestNewGRPCAgentOptions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "grpc-options")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	testCert := testinghelpers.NewTestCert("test", 60*time.Second)
+	caFile := path.Join(tempDir, "ca.crt")
+	certFile := path.Join(tempDir, "tls.crt")
+	keyFile := path.Join(tempDir, "tls.key")
+	testinghelpers.WriteFile(caFile, testCert.Cert)
+	testinghelpers.WriteFile(certFile, testCert.Cert)
+	testinghelpers.WriteFile(keyFile, testCert.Key)
+
+	cases := []struct {
+		name        string
+		grpcOptions *grpcoptions.GRPCOptions
+		expectedErr bool
+		expectedTLS bool
+	}{
+		{
+			name:        "insecure",
+			grpcOptions: &grpcoptions.GRPCOptions{URL: "localhost:8443"},
+		},
+		{
+			name: "without client cert",
+			grpcOptions: &grpcoptions.GRPCOptions{
+				URL:    "localhost:8443",
+				CAFile: caFile,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid ca",
+			grpcOptions: &grpcoptions.GRPCOptions{
+				URL:            "localhost:8443",
+				CAFile:         keyFile,
+				ClientCertFile: certFile,
+				ClientKeyFile:  keyFile,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "with client cert",
+			grpcOptions: &grpcoptions.GRPCOptions{
+				URL:            "localhost:8443",
+				CAFile:         caFile,
+				ClientCertFile: certFile,
+				ClientKeyFile:  keyFile,
+			},
+			expectedTLS: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			agentOptions, err := NewGRPCAgentOptions(c.grpcOptions, NewGRPCTransportOptions(), "cluster1", "agent1")
+			if c.expectedErr && err == nil {
+				t.Fatalf("expected error, but failed")
+			}
+			if !c.expectedErr && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if c.expectedErr {
+				return
+			}
+
+			if agentOptions.ClusterName != "cluster1" || agentOptions.AgentID != "agent1" {
+				t.Errorf("unexpected agent options %v", agentOptions)
+			}
+
+			o, ok := agentOptions.CloudEventsOptions.(*grpcAgentOptions)
+			if !ok {
+				t.Fatalf("unexpected cloudevents options %T", agentOptions.CloudEventsOptions)
+			}
+			if (o.tlsConfig != nil) != c.expectedTLS {
+				t.Errorf("expected tls %v, but got %v", c.expectedTLS, o.tlsConfig != nil)
+			}
+		})
+	}
+}
+
+func TestRotatingTLSConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "grpc-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	caFile := path.Join(tempDir, "ca.crt")
+	certFile := path.Join(tempDir, "tls.crt")
+	keyFile := path.Join(tempDir, "tls.key")
+
+	oldCert := testinghelpers.NewTestCert("test", 60*time.Second)
+	testinghelpers.WriteFile(caFile, oldCert.Cert)
+	testinghelpers.WriteFile(certFile, oldCert.Cert)
+	testinghelpers.WriteFile(keyFile, oldCert.Key)
+
+	tlsConfig, err := rotatingTLSConfig(caFile, certFile, keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	cert, err := tlsConfig.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	oldCertData := cert.Certificate[0]
+
+	// rotate the client certificate
+	newCert := testinghelpers.NewTestCert("test", 120*time.Second)
+	testinghelpers.WriteFile(certFile, newCert.Cert)
+	testinghelpers.WriteFile(keyFile, newCert.Key)
+
+	// the cached certificate is refreshed after one second
+	time.Sleep(1100 * time.Millisecond)
+
+	cert, err = tlsConfig.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if bytes.Equal(oldCertData, cert.Certificate[0]) {
+		t.Errorf("expected the rotated certificate is loaded")
+	}
+}
+
+func TestNewGRPCSourceOptions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "grpc-source-options")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	testCert := testinghelpers.NewTestCert("test", 60*time.Second)
+	caFile := path.Join(tempDir, "ca.crt")
+	certFile := path.Join(tempDir, "tls.crt")
+	keyFile := path.Join(tempDir, "tls.key")
+	testinghelpers.WriteFile(caFile, testCert.Cert)
+	testinghelpers.WriteFile(certFile, testCert.Cert)
+	testinghelpers.WriteFile(keyFile, testCert.Key)
+
+	cases := []struct {
+		name                string
+		grpcOptions         *grpcoptions.GRPCOptions
+		transportOptions    *GRPCTransportOptions
+		expectedErr         bool
+		expectedTLS         bool
+		expectedCertificate bool
+	}{
+		{
+			name:             "insecure",
+			grpcOptions:      &grpcoptions.GRPCOptions{URL: "localhost:8443"},
+			transportOptions: NewGRPCTransportOptions(),
+		},
+		{
+			name:             "invalid transport options",
+			grpcOptions:      &grpcoptions.GRPCOptions{URL: "localhost:8443"},
+			transportOptions: &GRPCTransportOptions{Compression: "unknown"},
+			expectedErr:      true,
+		},
+		{
+			name:             "without client cert",
+			grpcOptions:      &grpcoptions.GRPCOptions{URL: "localhost:8443", CAFile: caFile},
+			transportOptions: &GRPCTransportOptions{Compression: "gzip"},
+			expectedTLS:      true,
+		},
+		{
+			name: "with client cert",
+			grpcOptions: &grpcoptions.GRPCOptions{
+				URL:            "localhost:8443",
+				CAFile:         caFile,
+				ClientCertFile: certFile,
+				ClientKeyFile:  keyFile,
+			},
+			expectedTLS:         true,
+			expectedCertificate: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sourceOptions, err := NewGRPCSourceOptions(c.grpcOptions, c.transportOptions, "source1")
+			if c.expectedErr && err == nil {
+				t.Fatalf("expected error, but failed")
+			}
+			if !c.expectedErr && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if c.expectedErr {
+				return
+			}
+
+			if sourceOptions.SourceID != "source1" {
+				t.Errorf("unexpected source options %v", sourceOptions)
+			}
+
+			o, ok := sourceOptions.CloudEventsOptions.(*grpcSourceOptions)
+			if !ok {
+				t.Fatalf("unexpected cloudevents options %T", sourceOptions.CloudEventsOptions)
+			}
+			if (o.tlsConfig != nil) != c.expectedTLS {
+				t.Errorf("expected tls %v, but got %v", c.expectedTLS, o.tlsConfig != nil)
+			}
+			if o.tlsConfig != nil && (len(o.tlsConfig.Certificates) > 0) != c.expectedCertificate {
+				t.Errorf("expected client certificate %v, but got %v", c.expectedCertificate, len(o.tlsConfig.Certificates) > 0)
+			}
+		})
+	}
+}
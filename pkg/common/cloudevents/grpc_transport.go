@@ -0,0 +1,106 @@
+package cloudevents
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	// register the gzip compressor
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+)
+
+// GRPCTransportOptions configures the transport of the grpc connection to the cloudevents grpc server. The large
+// works over the constrained links are sent with the compression and the larger message size, and the broken
+// connections are detected with the keepalive pings.
+type GRPCTransportOptions struct {
+	// Compression is the name of the compressor of the messages, e.g. gzip. The compressor must be registered in
+	// the grpc encoding registry, e.g. zstd is available once a zstd compressor is registered. The messages are not
+	// compressed if it is empty.
+	Compression string
+	// MaxSendMessageSize and MaxRecvMessageSize are the max size of the messages sent and received in bytes, the
+	// grpc defaults are used if they are zero.
+	MaxSendMessageSize int
+	MaxRecvMessageSize int
+	// KeepaliveTime is the interval of the keepalive pings without activities on the connection, the pings are not
+	// sent if it is zero.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is the time to wait for the ack of a keepalive ping before the connection is closed.
+	KeepaliveTimeout time.Duration
+	// KeepalivePermitWithoutStream sends the keepalive pings even if there are no active streams.
+	KeepalivePermitWithoutStream bool
+}
+
+// NewGRPCTransportOptions returns the grpc transport options with default value set
+func NewGRPCTransportOptions() *GRPCTransportOptions {
+	return &GRPCTransportOptions{
+		KeepaliveTimeout: 20 * time.Second,
+	}
+}
+
+func (o *GRPCTransportOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Compression, "grpc-compression", o.Compression,
+		"The compression of the messages sent to the cloudevents grpc server, e.g. gzip. The compressor must be "+
+			"registered to the grpc, the messages are not compressed if it is empty.")
+	fs.IntVar(&o.MaxSendMessageSize, "grpc-max-send-message-size", o.MaxSendMessageSize,
+		"The max size in bytes of the messages sent to the cloudevents grpc server, the grpc default is used if it is 0")
+	fs.IntVar(&o.MaxRecvMessageSize, "grpc-max-recv-message-size", o.MaxRecvMessageSize,
+		"The max size in bytes of the messages received from the cloudevents grpc server, the grpc default (4MB) is "+
+			"used if it is 0")
+	fs.DurationVar(&o.KeepaliveTime, "grpc-keepalive-time", o.KeepaliveTime,
+		"The interval of the keepalive pings to the cloudevents grpc server when the connection is idle, the "+
+			"keepalive pings are disabled if it is 0")
+	fs.DurationVar(&o.KeepaliveTimeout, "grpc-keepalive-timeout", o.KeepaliveTimeout,
+		"The time to wait for the ack of a keepalive ping before the connection to the cloudevents grpc server is closed")
+	fs.BoolVar(&o.KeepalivePermitWithoutStream, "grpc-keepalive-permit-without-stream", o.KeepalivePermitWithoutStream,
+		"Send the keepalive pings to the cloudevents grpc server even if there are no active streams")
+}
+
+func (o *GRPCTransportOptions) Validate() error {
+	if len(o.Compression) > 0 && encoding.GetCompressor(o.Compression) == nil {
+		return fmt.Errorf("grpc-compression %q is not a registered grpc compressor", o.Compression)
+	}
+	if o.MaxSendMessageSize < 0 || o.MaxRecvMessageSize < 0 {
+		return fmt.Errorf("grpc-max-send-message-size and grpc-max-recv-message-size must not be negative")
+	}
+	if o.KeepaliveTime < 0 || o.KeepaliveTimeout < 0 {
+		return fmt.Errorf("grpc-keepalive-time and grpc-keepalive-timeout must not be negative")
+	}
+	return nil
+}
+
+// DialOptions returns the grpc dial options of the transport options.
+func (o *GRPCTransportOptions) DialOptions() ([]grpc.DialOption, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	var callOptions []grpc.CallOption
+	if len(o.Compression) > 0 {
+		callOptions = append(callOptions, grpc.UseCompressor(o.Compression))
+	}
+	if o.MaxSendMessageSize > 0 {
+		callOptions = append(callOptions, grpc.MaxCallSendMsgSize(o.MaxSendMessageSize))
+	}
+	if o.MaxRecvMessageSize > 0 {
+		callOptions = append(callOptions, grpc.MaxCallRecvMsgSize(o.MaxRecvMessageSize))
+	}
+
+	var dialOptions []grpc.DialOption
+	if len(callOptions) > 0 {
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(callOptions...))
+	}
+	if o.KeepaliveTime > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.KeepaliveTime,
+			Timeout:             o.KeepaliveTimeout,
+			PermitWithoutStream: o.KeepalivePermitWithoutStream,
+		}))
+	}
+	return dialOptions, nil
+}
@@ -0,0 +1,66 @@
+package cloudevents
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGRPCTransportOptions(t *testing.T) {
+	cases := []struct {
+		name                string
+		options             *GRPCTransportOptions
+		expectedErr         bool
+		expectedDialOptions int
+	}{
+		{
+			name:    "default",
+			options: NewGRPCTransportOptions(),
+		},
+		{
+			name:                "gzip with message sizes",
+			options:             &GRPCTransportOptions{Compression: "gzip", MaxSendMessageSize: 1 << 24, MaxRecvMessageSize: 1 << 24},
+			expectedDialOptions: 1,
+		},
+		{
+			name:                "keepalive",
+			options:             &GRPCTransportOptions{KeepaliveTime: time.Minute, KeepaliveTimeout: 20 * time.Second},
+			expectedDialOptions: 1,
+		},
+		{
+			name: "all",
+			options: &GRPCTransportOptions{
+				Compression:                  "gzip",
+				MaxRecvMessageSize:           1 << 24,
+				KeepaliveTime:                time.Minute,
+				KeepaliveTimeout:             20 * time.Second,
+				KeepalivePermitWithoutStream: true,
+			},
+			expectedDialOptions: 2,
+		},
+		{
+			name:        "unregistered compressor",
+			options:     &GRPCTransportOptions{Compression: "unknown"},
+			expectedErr: true,
+		},
+		{
+			name:        "negative message size",
+			options:     &GRPCTransportOptions{MaxSendMessageSize: -1},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dialOptions, err := c.options.DialOptions()
+			if c.expectedErr && err == nil {
+				t.Fatalf("expected error, but failed")
+			}
+			if !c.expectedErr && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(dialOptions) != c.expectedDialOptions {
+				t.Errorf("expected %d dial options, but got %d", c.expectedDialOptions, len(dialOptions))
+			}
+		})
+	}
+}
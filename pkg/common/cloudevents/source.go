@@ -0,0 +1,76 @@
+package cloudevents
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+	cloudeventswork "open-cluster-management.io/sdk-go/pkg/cloudevents/work"
+	sourceclient "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/client"
+	sourcelister "open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/lister"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
+)
+
+// NewSourceWorkClientSet builds a work clientset for a source with the given cloudevents source options. It behaves
+// the same as the source client holder in the sdk, but accepts the source options directly, so the source is able
+// to customize the cloudevents options, e.g. the transport of the grpc connection.
+func NewSourceWorkClientSet(
+	ctx context.Context,
+	sourceOptions *options.CloudEventsSourceOptions,
+	watcherStore store.WorkClientWatcherStore,
+	codecs ...generic.Codec[*workv1.ManifestWork],
+) (workclientset.Interface, error) {
+	cloudEventsClient, err := generic.NewCloudEventSourceClient[*workv1.ManifestWork](
+		ctx,
+		sourceOptions,
+		sourcelister.NewWatcherStoreLister(watcherStore),
+		cloudeventswork.ManifestWorkStatusHash,
+		codecs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// start to subscribe
+	cloudEventsClient.Subscribe(ctx, watcherStore.HandleReceivedWork)
+
+	// start a go routine to resync the works of all clusters when the client is reconnected
+	go runResync(ctx, ResyncOptions{}, cloudEventsClient.ReconnectedChan(), func(ctx context.Context) error {
+		return cloudEventsClient.Resync(ctx, types.ClusterAll)
+	})
+
+	// start a go routine to resync the works after this client's store is initiated
+	go func() {
+		if store.WaitForStoreInit(ctx, watcherStore.HasInitiated) {
+			if err := cloudEventsClient.Resync(ctx, types.ClusterAll); err != nil {
+				klog.Errorf("failed to send resync request, %v", err)
+			}
+		}
+	}()
+
+	return &workClientSetWrapper{
+		workV1ClientWrapper: &workV1ClientWrapper{
+			manifestWorks: sourceManifestWorks(sourceOptions.SourceID, cloudEventsClient, watcherStore),
+		},
+	}, nil
+}
+
+// sourceManifestWorks returns the getter of the ManifestWorks clients of the source, a source client is built for
+// each call, so the works of different clusters can be accessed concurrently.
+func sourceManifestWorks(
+	sourceID string,
+	cloudEventsClient *generic.CloudEventSourceClient[*workv1.ManifestWork],
+	watcherStore store.WorkClientWatcherStore,
+) func(namespace string) workv1client.ManifestWorkInterface {
+	return func(namespace string) workv1client.ManifestWorkInterface {
+		manifestWorkClient := sourceclient.NewManifestWorkSourceClient(sourceID, cloudEventsClient, watcherStore)
+		manifestWorkClient.SetNamespace(namespace)
+		return manifestWorkClient
+	}
+}
@@ -0,0 +1,69 @@
+package cloudevents
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
+)
+
+func TestSourceManifestWorks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	workStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, work := range []*workv1.ManifestWork{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "work1"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "work2"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster2", Name: "work1"}},
+	} {
+		if err := workStore.Add(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+	watcherStore := store.NewSourceInformerWatcherStore(ctx)
+	watcherStore.SetStore(workStore)
+
+	manifestWorks := sourceManifestWorks("source1", nil, watcherStore)
+	cluster1Client := manifestWorks("cluster1")
+	cluster2Client := manifestWorks("cluster2")
+
+	// the client of a cluster is not changed by the clients of the other clusters built after it
+	works, err := cluster1Client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(works.Items) != 2 {
+		t.Errorf("expected 2 works in cluster1, but got %v", works.Items)
+	}
+	for _, work := range works.Items {
+		if work.Namespace != "cluster1" {
+			t.Errorf("expected the works of cluster1, but got %s/%s", work.Namespace, work.Name)
+		}
+	}
+
+	works, err = cluster2Client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(works.Items) != 1 || works.Items[0].Namespace != "cluster2" {
+		t.Errorf("expected the work of cluster2, but got %v", works.Items)
+	}
+
+	if _, err := cluster2Client.Get(ctx, "work2", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected not found error of the work of cluster1, but got %v", err)
+	}
+
+	// the work in another namespace is rejected before it is published
+	_, err = cluster1Client.Create(ctx, &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster2", Name: "work3"},
+	}, metav1.CreateOptions{})
+	if !errors.IsInvalid(err) {
+		t.Errorf("expected invalid error, but got %v", err)
+	}
+}
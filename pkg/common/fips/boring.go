@@ -0,0 +1,13 @@
+//go:build boringcrypto
+
+package fips
+
+import (
+	"crypto/boring"
+	// restrict all the TLS connections to the FIPS approved settings
+	_ "crypto/tls/fipsonly"
+)
+
+func boringEnabled() bool {
+	return boring.Enabled()
+}
@@ -0,0 +1,87 @@
+// Package fips restricts the crypto used by the components to the FIPS approved algorithms.
+//
+// The FIPS compliance mode is enabled once the binaries are built with the boringcrypto experiment
+// (GOEXPERIMENT=boringcrypto, see FIPS_ENABLED in the Makefile), or with the --fips-mode flag. In the later case, the
+// TLS configs are restricted, but the crypto primitives are not backed by a FIPS validated module, which is reported
+// as a violation.
+package fips
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+)
+
+var (
+	lock       sync.Mutex
+	enabled    bool
+	violations []string
+)
+
+// approvedCipherSuites are the FIPS approved cipher suites of TLS 1.2, the cipher suites of TLS 1.3 are not
+// configurable and are all approved except TLS_CHACHA20_POLY1305_SHA256, which is disabled by boringcrypto.
+var approvedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+var approvedCurves = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+
+// Enable turns on the FIPS compliance mode.
+func Enable() {
+	lock.Lock()
+	defer lock.Unlock()
+	enabled = true
+}
+
+// Enabled returns true if the FIPS compliance mode is on.
+func Enabled() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return enabled || boringEnabled()
+}
+
+// Validate checks the runtime at startup, it must be called after the mode is enabled.
+func Validate() {
+	if Enabled() && !boringEnabled() {
+		ReportViolation("the binary is not built with a FIPS validated crypto module")
+	}
+}
+
+// ConfigureTLS restricts the TLS config to the FIPS approved versions, cipher suites and curves. The min version is
+// raised to TLS 1.2 if it is lower, a config requiring TLS 1.3 is kept. The config is not changed if the FIPS
+// compliance mode is off.
+func ConfigureTLS(config *tls.Config) {
+	if config == nil || !Enabled() {
+		return
+	}
+
+	if config.MinVersion < tls.VersionTLS12 {
+		config.MinVersion = tls.VersionTLS12
+	}
+	config.CipherSuites = approvedCipherSuites
+	config.CurvePreferences = approvedCurves
+}
+
+// ReportViolation records an option not compliant with FIPS, the violations are reported in the status of the
+// ManagedCluster by the registration agent.
+func ReportViolation(format string, args ...interface{}) {
+	lock.Lock()
+	defer lock.Unlock()
+	violation := fmt.Sprintf(format, args...)
+	for _, v := range violations {
+		if v == violation {
+			return
+		}
+	}
+	violations = append(violations, violation)
+}
+
+// Violations returns the recorded violations.
+func Violations() []string {
+	lock.Lock()
+	defer lock.Unlock()
+	return append([]string{}, violations...)
+}
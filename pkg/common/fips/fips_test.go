@@ -0,0 +1,87 @@
+package fips
+
+import (
+	"context"
+	"crypto/tls"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigureTLS(t *testing.T) {
+	defer func() { enabled = false }()
+
+	config := &tls.Config{MinVersion: tls.VersionTLS13, MaxVersion: tls.VersionTLS13}
+	ConfigureTLS(config)
+	if !boringEnabled() && (config.MinVersion != tls.VersionTLS13 || len(config.CipherSuites) != 0) {
+		t.Errorf("expected the config is not changed if the mode is off, but got %v", config)
+	}
+
+	Enable()
+	ConfigureTLS(config)
+	if config.MinVersion != tls.VersionTLS13 || config.MaxVersion != tls.VersionTLS13 {
+		t.Errorf("expected TLS 1.3 is kept, but got min %x max %x", config.MinVersion, config.MaxVersion)
+	}
+	if !reflect.DeepEqual(config.CipherSuites, approvedCipherSuites) {
+		t.Errorf("expected the approved cipher suites, but got %v", config.CipherSuites)
+	}
+	if !reflect.DeepEqual(config.CurvePreferences, approvedCurves) {
+		t.Errorf("expected the approved curves, but got %v", config.CurvePreferences)
+	}
+
+	config = &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS12}
+	ConfigureTLS(config)
+	if config.MinVersion != tls.VersionTLS12 || config.MaxVersion != tls.VersionTLS12 {
+		t.Errorf("expected only TLS 1.2 is allowed, but got min %x max %x", config.MinVersion, config.MaxVersion)
+	}
+}
+
+func TestReportViolation(t *testing.T) {
+	defer func() { violations = nil }()
+
+	ReportViolation("the connection to %s is not encrypted", "broker")
+	ReportViolation("the connection to %s is not encrypted", "broker")
+	ReportViolation("invalid cipher suite")
+	expected := []string{"the connection to broker is not encrypted", "invalid cipher suite"}
+	if !reflect.DeepEqual(Violations(), expected) {
+		t.Errorf("expected violations %v, but got %v", expected, Violations())
+	}
+}
+
+func TestPublishViolations(t *testing.T) {
+	defer func() { violations = nil }()
+
+	kubeClient := kubefake.NewSimpleClientset()
+	if err := PublishViolations(context.TODO(), kubeClient.CoreV1(), "agent", "work-agent"); err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeClient.Actions()) != 1 {
+		t.Errorf("expected the configmap is not created without violations, but got %v", kubeClient.Actions())
+	}
+
+	ReportViolation("invalid cipher suite")
+	if err := PublishViolations(context.TODO(), kubeClient.CoreV1(), "agent", "work-agent"); err != nil {
+		t.Fatal(err)
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps("agent").Get(context.TODO(), ViolationsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+	if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(cm); err != nil {
+		t.Fatal(err)
+	}
+	published, err := PublishedViolations(kubeInformerFactory.Core().V1().ConfigMaps().Lister().ConfigMaps("agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"work-agent: invalid cipher suite"}
+	if !reflect.DeepEqual(published, expected) {
+		t.Errorf("expected published violations %v, but got %v", expected, published)
+	}
+}
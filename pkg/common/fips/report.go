@@ -0,0 +1,81 @@
+package fips
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+)
+
+// ViolationsConfigMapName is the name of the configmap in the agent namespace, in which the agents running in their own
+// processes, e.g. the work agent in the Default mode, publish their violations keyed by the component name. The
+// violations are reported in the status of the ManagedCluster by the registration agent.
+const ViolationsConfigMapName = "fips-violations"
+
+// PublishViolations writes the recorded violations of the component into the violations configmap, the key of the
+// component is removed if there is no violation.
+func PublishViolations(ctx context.Context, client corev1client.ConfigMapsGetter, namespace, component string) error {
+	value := strings.Join(Violations(), "\n")
+
+	cm, err := client.ConfigMaps(namespace).Get(ctx, ViolationsConfigMapName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if len(value) == 0 {
+			return nil
+		}
+		_, err = client.ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ViolationsConfigMapName, Namespace: namespace},
+			Data:       map[string]string{component: value},
+		}, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+
+	if current, ok := cm.Data[component]; current == value && (ok || len(value) == 0) {
+		return nil
+	}
+	cm = cm.DeepCopy()
+	if len(value) == 0 {
+		delete(cm.Data, component)
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[component] = value
+	}
+	_, err = client.ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// PublishedViolations returns the violations published by the other components in the violations configmap.
+func PublishedViolations(lister corev1lister.ConfigMapNamespaceLister) ([]string, error) {
+	cm, err := lister.Get(ViolationsConfigMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]string, 0, len(cm.Data))
+	for component := range cm.Data {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	var violations []string
+	for _, component := range components {
+		for _, violation := range strings.Split(cm.Data[component], "\n") {
+			if len(violation) > 0 {
+				violations = append(violations, component+": "+violation)
+			}
+		}
+	}
+	return violations, nil
+}
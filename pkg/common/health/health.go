@@ -0,0 +1,247 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+)
+
+// ReadinessCheckName is the name of the check aggregating the readiness contributors of an agent.
+const ReadinessCheckName = "readiness"
+
+// Readiness aggregates the health contributors of the controllers and the connections of an agent into one check,
+// which reports the agent is not ready, e.g. when the hub is unreachable, but must not restart the agent. The
+// library-go controller server only installs the custom checks on /healthz, so the liveness probes of the agents
+// exclude the check with /healthz?exclude=readiness, and the readiness probes use /healthz with all the checks.
+type Readiness struct {
+	checkers []healthz.HealthChecker
+}
+
+// NewReadiness returns the check aggregating the readiness contributors.
+func NewReadiness(checkers ...healthz.HealthChecker) *Readiness {
+	return &Readiness{checkers: checkers}
+}
+
+func (r *Readiness) Name() string {
+	return ReadinessCheckName
+}
+
+func (r *Readiness) Check(req *http.Request) error {
+	var errs []error
+	for _, checker := range r.checkers {
+		if err := checker.Check(req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ControllerHealth is the health contributor of a controller. The controller is unhealthy if its informers are not
+// synced after it starts, or all its syncs fail for longer than the failure threshold. It is healthy before it
+// starts, e.g. when it is waiting for the leader election.
+type ControllerHealth struct {
+	name             string
+	failureThreshold time.Duration
+	clock            clock.Clock
+
+	lock               sync.RWMutex
+	started            time.Time
+	informersSynced    []cache.InformerSynced
+	lastSuccessfulSync time.Time
+	lastError          error
+}
+
+// NewControllerHealth returns the health contributor of the named controller.
+func NewControllerHealth(name string, failureThreshold time.Duration) *ControllerHealth {
+	return &ControllerHealth{
+		name:             name,
+		failureThreshold: failureThreshold,
+		clock:            clock.RealClock{},
+	}
+}
+
+func (h *ControllerHealth) Name() string {
+	return h.name
+}
+
+func (h *ControllerHealth) Check(_ *http.Request) error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if h.started.IsZero() {
+		return nil
+	}
+	for _, synced := range h.informersSynced {
+		if !synced() {
+			return fmt.Errorf("the informers of controller %s are not synced", h.name)
+		}
+	}
+	if h.lastError == nil {
+		return nil
+	}
+
+	lastSuccessfulSync := h.lastSuccessfulSync
+	if lastSuccessfulSync.IsZero() {
+		lastSuccessfulSync = h.started
+	}
+	if h.clock.Since(lastSuccessfulSync) > h.failureThreshold {
+		return fmt.Errorf("controller %s has not synced successfully since %s: %v",
+			h.name, lastSuccessfulSync.Format(time.RFC3339), h.lastError)
+	}
+	return nil
+}
+
+// Start marks the controller started, the controller is unhealthy until the informers are synced.
+func (h *ControllerHealth) Start(informersSynced ...cache.InformerSynced) {
+	if h == nil {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.started = h.clock.Now()
+	h.informersSynced = informersSynced
+}
+
+// ObserveSync wraps the sync func of the controller to record the result of each sync, the sync func is returned
+// as it is if the contributor is nil.
+func (h *ControllerHealth) ObserveSync(sync factory.SyncFunc) factory.SyncFunc {
+	if h == nil {
+		return sync
+	}
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		err := sync(ctx, syncCtx)
+
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if err != nil {
+			h.lastError = err
+			return err
+		}
+		h.lastSuccessfulSync = h.clock.Now()
+		h.lastError = nil
+		return nil
+	}
+}
+
+// ConnectivityHealth is the health contributor of the connection to a remote server, e.g. the hub apiserver. The
+// connection is probed periodically after it starts, and it is unhealthy if the probes keep failing for longer than
+// the failure threshold.
+type ConnectivityHealth struct {
+	name             string
+	interval         time.Duration
+	failureThreshold time.Duration
+	clock            clock.Clock
+
+	lock               sync.RWMutex
+	started            time.Time
+	lastSuccessfulPing time.Time
+	lastError          error
+}
+
+// NewConnectivityHealth returns the health contributor of the named connection.
+func NewConnectivityHealth(name string, interval, failureThreshold time.Duration) *ConnectivityHealth {
+	return &ConnectivityHealth{
+		name:             name,
+		interval:         interval,
+		failureThreshold: failureThreshold,
+		clock:            clock.RealClock{},
+	}
+}
+
+func (h *ConnectivityHealth) Name() string {
+	return h.name
+}
+
+func (h *ConnectivityHealth) Check(_ *http.Request) error {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if h.started.IsZero() || h.lastError == nil {
+		return nil
+	}
+
+	lastSuccessfulPing := h.lastSuccessfulPing
+	if lastSuccessfulPing.IsZero() {
+		lastSuccessfulPing = h.started
+	}
+	if h.clock.Since(lastSuccessfulPing) > h.failureThreshold {
+		return fmt.Errorf("%s is not reachable since %s: %v",
+			h.name, lastSuccessfulPing.Format(time.RFC3339), h.lastError)
+	}
+	return nil
+}
+
+// Run probes the connection periodically until the context is done.
+func (h *ConnectivityHealth) Run(ctx context.Context, probe func(ctx context.Context) error) {
+	h.lock.Lock()
+	h.started = h.clock.Now()
+	h.lock.Unlock()
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		h.observeProbe(probe(ctx))
+	}, h.interval)
+}
+
+func (h *ConnectivityHealth) observeProbe(err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if err != nil {
+		h.lastError = err
+		return
+	}
+	h.lastSuccessfulPing = h.clock.Now()
+	h.lastError = nil
+}
+
+// HubCABundleHealth is the liveness check of an agent building its hub clients with the CA bundles of the hub
+// distributed by the hub. It fails once the CA bundle file is changed after the hub clients are built, so the agent
+// is restarted to trust the next CA of the hub before the hub rotates to it.
+type HubCABundleHealth struct {
+	interval time.Duration
+	changed  atomic.Bool
+}
+
+// NewHubCABundleHealth returns the liveness check of the CA bundles of the hub checked at the interval.
+func NewHubCABundleHealth(interval time.Duration) *HubCABundleHealth {
+	return &HubCABundleHealth{interval: interval}
+}
+
+func (h *HubCABundleHealth) Name() string {
+	return "hub-ca-bundle"
+}
+
+func (h *HubCABundleHealth) Check(_ *http.Request) error {
+	if h.changed.Load() {
+		return fmt.Errorf("the CA bundles of the hub change and restart is required")
+	}
+	return nil
+}
+
+// Run checks if the CA bundle file is changed from the loaded CA bundles the hub clients are built with until the
+// context is done.
+func (h *HubCABundleHealth) Run(ctx context.Context, caBundleFile string, loaded []byte) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		data, err := os.ReadFile(filepath.Clean(caBundleFile))
+		if err != nil {
+			// no work because the CA bundle file may not exist yet.
+			return
+		}
+		if !bytes.Equal(data, loaded) {
+			h.changed.Store(true)
+		}
+	}, h.interval)
+}
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestControllerHealth(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	h := NewControllerHealth("test", time.Minute)
+	h.clock = c
+
+	var syncErr error
+	sync := h.ObserveSync(func(ctx context.Context, syncCtx factory.SyncContext) error {
+		return syncErr
+	})
+
+	// healthy before the controller starts
+	if err := h.Check(nil); err != nil {
+		t.Errorf("expected healthy before start, but got %v", err)
+	}
+
+	synced := false
+	h.Start(func() bool { return synced })
+	if err := h.Check(nil); err == nil {
+		t.Errorf("expected unhealthy before the informers are synced")
+	}
+
+	synced = true
+	if err := h.Check(nil); err != nil {
+		t.Errorf("expected healthy after the informers are synced, but got %v", err)
+	}
+
+	// failures within the threshold are tolerated
+	syncErr = fmt.Errorf("failed")
+	_ = sync(context.TODO(), nil)
+	c.Step(30 * time.Second)
+	if err := h.Check(nil); err != nil {
+		t.Errorf("expected healthy within the failure threshold, but got %v", err)
+	}
+
+	c.Step(time.Minute)
+	if err := h.Check(nil); err == nil {
+		t.Errorf("expected unhealthy after the failure threshold")
+	}
+
+	// a successful sync recovers the controller
+	syncErr = nil
+	_ = sync(context.TODO(), nil)
+	if err := h.Check(nil); err != nil {
+		t.Errorf("expected healthy after a successful sync, but got %v", err)
+	}
+}
+
+func TestConnectivityHealth(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	h := NewConnectivityHealth("hub", time.Second, time.Minute)
+	h.clock = c
+
+	if err := h.Check(nil); err != nil {
+		t.Errorf("expected healthy before start, but got %v", err)
+	}
+
+	h.started = c.Now()
+	h.observeProbe(nil)
+	c.Step(2 * time.Minute)
+	h.observeProbe(fmt.Errorf("connection refused"))
+	if err := h.Check(nil); err == nil {
+		t.Errorf("expected unhealthy after the failure threshold")
+	}
+
+	h.observeProbe(nil)
+	if err := h.Check(nil); err != nil {
+		t.Errorf("expected healthy after a successful probe, but got %v", err)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	controller := NewControllerHealth("controller", time.Minute)
+	controller.clock = c
+	connectivity := NewConnectivityHealth("hub", time.Second, time.Minute)
+	connectivity.clock = c
+
+	readiness := NewReadiness(controller, connectivity)
+	if readiness.Name() != ReadinessCheckName {
+		t.Errorf("expected name %s, but got %s", ReadinessCheckName, readiness.Name())
+	}
+	if err := readiness.Check(nil); err != nil {
+		t.Errorf("expected ready, but got %v", err)
+	}
+
+	controller.Start(func() bool { return false })
+	if err := readiness.Check(nil); err == nil {
+		t.Errorf("expected not ready when a contributor is unhealthy")
+	}
+}
+
+func TestHubCABundleHealth(t *testing.T) {
+	caBundleFile := filepath.Join(t.TempDir(), "hub-ca-bundle.crt")
+	if err := os.WriteFile(caBundleFile, []byte("ca"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name         string
+		caBundleFile string
+		loaded       []byte
+		unhealthy    bool
+	}{
+		{
+			name:         "no ca bundle file",
+			caBundleFile: filepath.Join(filepath.Dir(caBundleFile), "missing.crt"),
+		},
+		{
+			name:         "ca bundle is not changed",
+			caBundleFile: caBundleFile,
+			loaded:       []byte("ca"),
+		},
+		{
+			name:         "ca bundle is created",
+			caBundleFile: caBundleFile,
+			unhealthy:    true,
+		},
+		{
+			name:         "ca bundle is changed",
+			caBundleFile: caBundleFile,
+			loaded:       []byte("former ca"),
+			unhealthy:    true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := NewHubCABundleHealth(time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			h.Run(ctx, c.caBundleFile, c.loaded)
+
+			err := h.Check(nil)
+			if c.unhealthy && err == nil {
+				t.Errorf("expected unhealthy, but got nil")
+			}
+			if !c.unhealthy && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// circuitBreakerJitter is the max factor of the backoff added randomly, so the agents of many managed clusters
+	// do not probe the hub at the same time once it returns.
+	circuitBreakerJitter = 0.2
+	// circuitBreakerProbeTimeout is the timeout of the probe request to the hub.
+	circuitBreakerProbeTimeout = 5 * time.Second
+)
+
+// ErrHubUnreachable is returned by the requests to the hub rejected by the circuit breaker.
+var ErrHubUnreachable = errors.New("the hub is unreachable")
+
+// CircuitBreaker rejects the requests to the hub without sending them once a number of consecutive requests fail to
+// reach the hub, so the agents do not retry aggressively and flood the logs while the hub is down. The hub is probed
+// with a cheap request with an exponential backoff, and the requests are sent again once the probe gets a response.
+type CircuitBreaker struct {
+	lock             sync.Mutex
+	failureThreshold int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	clock            clock.Clock
+
+	failures  int
+	open      bool
+	backoff   time.Duration
+	nextProbe time.Time
+	// openedAt and closedAt are the start and the end of the last outage of the hub.
+	openedAt time.Time
+	closedAt time.Time
+}
+
+var suppressRejectedRequestErrorsOnce sync.Once
+
+// NewCircuitBreaker returns a circuit breaker opening after the failureThreshold consecutive failures, the hub is
+// probed after the initialBackoff at first, and the backoff is doubled until the maxBackoff.
+func NewCircuitBreaker(failureThreshold int, initialBackoff, maxBackoff time.Duration) *CircuitBreaker {
+	suppressRejectedRequestErrorsOnce.Do(suppressRejectedRequestErrors)
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		initialBackoff:   initialBackoff,
+		maxBackoff:       maxBackoff,
+		clock:            clock.RealClock{},
+	}
+}
+
+// Wrap wraps the transport of the config with the circuit breaker.
+func (b *CircuitBreaker) Wrap(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &circuitBreakerRoundTripper{delegate: rt, breaker: b}
+	})
+}
+
+// LastOutage returns whether the hub is unreachable now, and the start and the end of the last outage of the hub.
+// The start is zero if the hub has never been unreachable, and the end is zero if the outage is not over.
+func (b *CircuitBreaker) LastOutage() (bool, time.Time, time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.open, b.openedAt, b.closedAt
+}
+
+// shouldProbe returns whether the request should probe the hub before it is sent, and an error if the request is
+// rejected since the circuit breaker is open.
+func (b *CircuitBreaker) shouldProbe() (bool, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.open {
+		return false, nil
+	}
+	now := b.clock.Now()
+	if now.Before(b.nextProbe) {
+		return false, fmt.Errorf("%w since %s, the request is not sent", ErrHubUnreachable,
+			b.openedAt.UTC().Format(time.RFC3339))
+	}
+	// only one request probes the hub in a backoff period
+	b.backoff = min(2*b.backoff, b.maxBackoff)
+	b.nextProbe = now.Add(wait.Jitter(b.backoff, circuitBreakerJitter))
+	return true, nil
+}
+
+// recordResult counts the consecutive requests failed to reach the hub, the circuit breaker is opened once the
+// number reaches the threshold and is closed once a request gets a response.
+func (b *CircuitBreaker) recordResult(err error) {
+	// a request canceled by the client tells nothing about the hub
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if ConnectionFailureReason(err) != ConnectionFailureUnreachable {
+		b.failures = 0
+		if b.open {
+			b.open = false
+			b.closedAt = b.clock.Now()
+			klog.Infof("The hub is reachable again after %s, the requests to the hub are resumed",
+				b.closedAt.Sub(b.openedAt).Round(time.Second))
+		}
+		return
+	}
+
+	b.failures++
+	if b.open || b.failures < b.failureThreshold {
+		return
+	}
+	b.open = true
+	b.openedAt = b.clock.Now()
+	b.closedAt = time.Time{}
+	b.backoff = b.initialBackoff
+	b.nextProbe = b.openedAt.Add(wait.Jitter(b.backoff, circuitBreakerJitter))
+	klog.Warningf("The hub is unreachable after %d consecutive failures, the requests to the hub are rejected "+
+		"until it is reachable again: %v", b.failures, err)
+}
+
+// suppressRejectedRequestErrors stops logging the errors of the requests rejected by the circuit breaker, e.g. the
+// errors of the controllers retrying to sync while the hub is down. The circuit breaker logs once the hub is
+// unreachable and once it is reachable again instead.
+func suppressRejectedRequestErrors() {
+	handlers := utilruntime.ErrorHandlers
+	utilruntime.ErrorHandlers = []func(error){
+		func(err error) {
+			if errors.Is(err, ErrHubUnreachable) {
+				klog.V(4).Info("Request to the hub is rejected by the circuit breaker", "err", err)
+				return
+			}
+			for _, handle := range handlers {
+				handle(err)
+			}
+		},
+	}
+}
+
+type circuitBreakerRoundTripper struct {
+	delegate http.RoundTripper
+	breaker  *CircuitBreaker
+}
+
+func (rt *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	probe, err := rt.breaker.shouldProbe()
+	if err != nil {
+		return nil, err
+	}
+	if probe {
+		// any response of the probe request means the hub is reachable, even if the request is not authorized.
+		probeErr := rt.probe(req)
+		rt.breaker.recordResult(probeErr)
+		if probeErr != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHubUnreachable, probeErr)
+		}
+	}
+
+	resp, err := rt.delegate.RoundTrip(req)
+	rt.breaker.recordResult(err)
+	return resp, err
+}
+
+// probe sends a cheap request to the livez endpoint of the hub apiserver with the headers of the request.
+func (rt *circuitBreakerRoundTripper) probe(req *http.Request) error {
+	ctx, cancel := context.WithTimeout(req.Context(), circuitBreakerProbeTimeout)
+	defer cancel()
+	probeURL := *req.URL
+	probeURL.Path = "/livez"
+	probeURL.RawQuery = ""
+	probeReq, err := http.NewRequestWithContext(ctx, http.MethodGet, probeURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	probeReq.Header = req.Header.Clone()
+	resp, err := rt.delegate.RoundTrip(probeReq)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
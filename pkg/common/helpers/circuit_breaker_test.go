@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeRoundTripper struct {
+	err      error
+	requests []string
+}
+
+func (f *fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req.URL.Path)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	breaker := NewCircuitBreaker(2, time.Second, 4*time.Second)
+	breaker.clock = fakeClock
+	delegate := &fakeRoundTripper{err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	rt := &circuitBreakerRoundTripper{delegate: delegate, breaker: breaker}
+
+	send := func(expectedErr bool, expectedRequests ...string) {
+		t.Helper()
+		delegate.requests = nil
+		req, _ := http.NewRequest(http.MethodGet, "https://hub:6443/apis/cluster.open-cluster-management.io/v1", nil)
+		_, err := rt.RoundTrip(req)
+		if expectedErr != (err != nil) {
+			t.Errorf("expected error %v, but got %v", expectedErr, err)
+		}
+		if strings.Join(delegate.requests, ",") != strings.Join(expectedRequests, ",") {
+			t.Errorf("expected requests %v, but got %v", expectedRequests, delegate.requests)
+		}
+	}
+
+	// the circuit breaker is opened after 2 consecutive failures
+	send(true, "/apis/cluster.open-cluster-management.io/v1")
+	send(true, "/apis/cluster.open-cluster-management.io/v1")
+	if unreachable, _, _ := breaker.LastOutage(); !unreachable {
+		t.Fatalf("expected the circuit breaker is opened")
+	}
+
+	// the requests are rejected without being sent
+	send(true)
+
+	// the hub is probed after the backoff, the request is rejected if the probe fails
+	fakeClock.Step(2 * time.Second)
+	send(true, "/livez")
+	send(true)
+
+	// the request is sent once the probe succeeds
+	delegate.err = nil
+	fakeClock.Step(3 * time.Second)
+	send(false, "/livez", "/apis/cluster.open-cluster-management.io/v1")
+	unreachable, start, end := breaker.LastOutage()
+	if unreachable || start.IsZero() || !end.After(start) {
+		t.Errorf("expected the last outage is over, but got %v %v %v", unreachable, start, end)
+	}
+	send(false, "/apis/cluster.open-cluster-management.io/v1")
+}
+
+func TestSuppressRejectedRequestErrors(t *testing.T) {
+	var handled []error
+	original := utilruntime.ErrorHandlers
+	defer func() { utilruntime.ErrorHandlers = original }()
+	utilruntime.ErrorHandlers = []func(error){func(err error) { handled = append(handled, err) }}
+
+	suppressRejectedRequestErrors()
+	utilruntime.HandleError(fmt.Errorf("controller failed to sync: %w", ErrHubUnreachable))
+	utilruntime.HandleError(errors.New("controller failed to sync"))
+	if len(handled) != 1 || handled[0].Error() != "controller failed to sync" {
+		t.Errorf("expected only the error not rejected by the circuit breaker is handled, but got %v", handled)
+	}
+}
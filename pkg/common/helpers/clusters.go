@@ -1,10 +1,15 @@
 package helpers
 
 import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	clusterlister "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clustersdkv1beta1 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta1"
 )
@@ -25,3 +30,87 @@ func GetClusterChanges(client clusterlister.PlacementDecisionLister, placement *
 
 	return pdtracker.GetClusterChanges()
 }
+
+// ManagedClusterConditionClusterClaimsValid reports whether the cluster claims of a managed cluster conform to the
+// cluster claim policy of the hub.
+// TODO move this to the api repo.
+const ManagedClusterConditionClusterClaimsValid = "ClusterClaimsValid"
+
+// GetClusterClaims returns the cluster claims of the managed cluster. Only the reserved cluster claims are returned
+// if the cluster claims violate the cluster claim policy of the hub, so the claims of a misconfigured cluster are not
+// used to select it.
+func GetClusterClaims(cluster *clusterv1.ManagedCluster) map[string]string {
+	valid := !meta.IsStatusConditionFalse(cluster.Status.Conditions, ManagedClusterConditionClusterClaimsValid)
+	reservedClaimNames := sets.New[string](clusterv1alpha1.ReservedClusterClaimNames[:]...)
+
+	claims := map[string]string{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		if valid || reservedClaimNames.Has(claim.Name) {
+			claims[claim.Name] = claim.Value
+		}
+	}
+	return claims
+}
+
+// The resource usage of the managed cluster is reported by the registration agent in the capacity of the managed
+// cluster with the resource names below, next to the capacity of the nodes.
+const (
+	// ResourceCPURequested is the cpu requested by the pods not terminated on the managed cluster.
+	ResourceCPURequested clusterv1.ResourceName = "usage.open-cluster-management.io/requested-cpu"
+	// ResourceMemoryRequested is the memory requested by the pods not terminated on the managed cluster.
+	ResourceMemoryRequested clusterv1.ResourceName = "usage.open-cluster-management.io/requested-memory"
+	// ResourceCPUUsed is the cpu used by the nodes of the managed cluster reported by the metrics server.
+	ResourceCPUUsed clusterv1.ResourceName = "usage.open-cluster-management.io/used-cpu"
+	// ResourceMemoryUsed is the memory used by the nodes of the managed cluster reported by the metrics server.
+	ResourceMemoryUsed clusterv1.ResourceName = "usage.open-cluster-management.io/used-memory"
+)
+
+// ResourceUsageWorkLabel is the label of the ManifestWorks reporting the resources requested on the managed cluster
+// in their status feedback, e.g. with the used requests of the ResourceQuotas, for the clusters whose registration
+// agent does not report them. The feedback values are named after the requested resources above, and must be
+// converted by the feedback schema of the ManifestWork, i.e. the cpu in millicores and the memory in bytes. The values
+// of all the labeled ManifestWorks in the cluster namespace are summed.
+const ResourceUsageWorkLabel = "usage.open-cluster-management.io/resource-usage"
+
+const (
+	// DecisionGroupOrderAnnotation is the annotation on the placement to order its decision groups. The value is a
+	// comma separated list of the group names, the groups listed get the lowest decision group indexes in the given
+	// order, and the others follow in their original order.
+	DecisionGroupOrderAnnotation = "cluster.open-cluster-management.io/experimental-decision-group-order"
+
+	// CanaryDecisionGroupsAnnotation is the annotation on the placement to set the canary decision groups. The value
+	// is a comma separated list of the group names. The canary groups are ordered before all the other groups, and
+	// they are taken as the mandatory decision groups of the progressive rollouts which do not set any.
+	CanaryDecisionGroupsAnnotation = "cluster.open-cluster-management.io/experimental-canary-decision-groups"
+)
+
+// GetCanaryDecisionGroups returns the names of the canary decision groups of the placement.
+func GetCanaryDecisionGroups(placement *clusterv1beta1.Placement) []string {
+	return splitGroupNames(placement.GetAnnotations()[CanaryDecisionGroupsAnnotation])
+}
+
+// GetDecisionGroupOrder returns the names of the decision groups of the placement in the order they are rolled out,
+// the canary groups come first.
+func GetDecisionGroupOrder(placement *clusterv1beta1.Placement) []string {
+	var order []string
+	names := sets.New[string]()
+	for _, name := range append(GetCanaryDecisionGroups(placement),
+		splitGroupNames(placement.GetAnnotations()[DecisionGroupOrderAnnotation])...) {
+		if names.Has(name) {
+			continue
+		}
+		names.Insert(name)
+		order = append(order, name)
+	}
+	return order
+}
+
+func splitGroupNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -12,6 +12,7 @@ import (
 
 	fakecluster "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 )
 
@@ -112,3 +113,65 @@ func TestPlacementDecisionClustersTracker_GetClusterChanges(t *testing.T) {
 		}
 	}
 }
+
+func TestGetDecisionGroupOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		expectedCanary []string
+		expectedOrder  []string
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name:          "order only",
+			annotations:   map[string]string{DecisionGroupOrderAnnotation: "wave1, wave2,,wave3"},
+			expectedOrder: []string{"wave1", "wave2", "wave3"},
+		},
+		{
+			name: "canary before order",
+			annotations: map[string]string{
+				CanaryDecisionGroupsAnnotation: "canary",
+				DecisionGroupOrderAnnotation:   "wave1,canary,wave2",
+			},
+			expectedCanary: []string{"canary"},
+			expectedOrder:  []string{"canary", "wave1", "wave2"},
+		},
+	}
+
+	for _, test := range tests {
+		placement := &clusterv1beta1.Placement{
+			ObjectMeta: metav1.ObjectMeta{Name: "placement1", Namespace: "default", Annotations: test.annotations},
+		}
+		if canary := GetCanaryDecisionGroups(placement); !reflect.DeepEqual(canary, test.expectedCanary) {
+			t.Errorf("Case: %v, expect canary groups %v, but got %v", test.name, test.expectedCanary, canary)
+		}
+		if order := GetDecisionGroupOrder(placement); !reflect.DeepEqual(order, test.expectedOrder) {
+			t.Errorf("Case: %v, expect group order %v, but got %v", test.name, test.expectedOrder, order)
+		}
+	}
+}
+
+func TestGetClusterClaims(t *testing.T) {
+	cluster := &clusterv1.ManagedCluster{
+		Status: clusterv1.ManagedClusterStatus{
+			ClusterClaims: []clusterv1.ManagedClusterClaim{
+				{Name: "id.k8s.io", Value: "cluster1"},
+				{Name: "region", Value: "us-east-1"},
+			},
+		},
+	}
+	expected := map[string]string{"id.k8s.io": "cluster1", "region": "us-east-1"}
+	if claims := GetClusterClaims(cluster); !reflect.DeepEqual(claims, expected) {
+		t.Errorf("expect claims %v, but got %v", expected, claims)
+	}
+
+	cluster.Status.Conditions = []metav1.Condition{
+		{Type: ManagedClusterConditionClusterClaimsValid, Status: metav1.ConditionFalse},
+	}
+	expected = map[string]string{"id.k8s.io": "cluster1"}
+	if claims := GetClusterClaims(cluster); !reflect.DeepEqual(claims, expected) {
+		t.Errorf("expect only the reserved claims %v, but got %v", expected, claims)
+	}
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// The reasons a client fails to connect to the hub apiserver.
+	ConnectionFailureCAMismatch  = "CAMismatch"
+	ConnectionFailureCertExpired = "CertExpired"
+	ConnectionFailureUnreachable = "Unreachable"
+)
+
+// ErrClientCertificateExpired is returned by CheckClientCertificate if the client certificate is expired.
+var ErrClientCertificateExpired = errors.New("client certificate is expired")
+
+// CheckClientCertificate returns an error if the client certificate of the config is expired. The apiserver
+// rejects an expired client certificate as unauthorized without telling why, so it is checked on the client side.
+func CheckClientCertificate(config *rest.Config) error {
+	certData := config.CertData
+	if len(certData) == 0 && len(config.CertFile) > 0 {
+		data, err := os.ReadFile(config.CertFile)
+		if err != nil {
+			return fmt.Errorf("unable to read the client certificate: %w", err)
+		}
+		certData = data
+	}
+	if len(certData) == 0 {
+		return nil
+	}
+
+	certs, err := certutil.ParseCertsPEM(certData)
+	if err != nil {
+		return fmt.Errorf("invalid client certificate: %w", err)
+	}
+	if notAfter := certs[0].NotAfter; time.Now().After(notAfter) {
+		return fmt.Errorf("%w at %s", ErrClientCertificateExpired, notAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ConnectionFailureReason returns why a client failed to connect to the apiserver with the error. It is one of
+// CAMismatch if the serving certificate is not signed by the CA of the client, e.g. it is replaced by a
+// TLS-inspecting proxy, CertExpired if the serving or the client certificate is expired, and Unreachable if the
+// apiserver or the proxy cannot be reached. It is empty for the other errors.
+func ConnectionFailureReason(err error) string {
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var invalidCertErr x509.CertificateInvalidError
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &unknownAuthorityErr):
+		return ConnectionFailureCAMismatch
+	case errors.Is(err, ErrClientCertificateExpired),
+		errors.As(err, &invalidCertErr) && invalidCertErr.Reason == x509.Expired:
+		return ConnectionFailureCertExpired
+	case errors.As(err, &opErr), errors.As(err, &dnsErr),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ConnectionFailureUnreachable
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func TestCheckClientCertificate(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      *rest.Config
+		expectedErr error
+	}{
+		{
+			name:   "no client certificate",
+			config: &rest.Config{},
+		},
+		{
+			name: "valid client certificate",
+			config: &rest.Config{TLSClientConfig: rest.TLSClientConfig{
+				CertData: testinghelpers.NewTestCert("test", time.Hour).Cert,
+			}},
+		},
+		{
+			name: "expired client certificate",
+			config: &rest.Config{TLSClientConfig: rest.TLSClientConfig{
+				CertData: testinghelpers.NewTestCert("test", -time.Hour).Cert,
+			}},
+			expectedErr: ErrClientCertificateExpired,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckClientCertificate(c.config)
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestConnectionFailureReason(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	closedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedServer.Close()
+
+	cases := []struct {
+		name           string
+		config         *rest.Config
+		err            error
+		expectedReason string
+	}{
+		{
+			name:           "ca mismatch",
+			config:         &rest.Config{Host: server.URL},
+			expectedReason: ConnectionFailureCAMismatch,
+		},
+		{
+			name:           "unreachable",
+			config:         &rest.Config{Host: closedServer.URL},
+			expectedReason: ConnectionFailureUnreachable,
+		},
+		{
+			name:           "client certificate expired",
+			err:            fmt.Errorf("invalid bootstrap kubeconfig: %w", ErrClientCertificateExpired),
+			expectedReason: ConnectionFailureCertExpired,
+		},
+		{
+			name:           "other errors",
+			err:            fmt.Errorf("unauthorized"),
+			expectedReason: "",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.err
+			if c.config != nil {
+				kubeClient, clientErr := kubernetes.NewForConfig(c.config)
+				if clientErr != nil {
+					t.Fatal(clientErr)
+				}
+				_, err = kubeClient.Discovery().RESTClient().Get().AbsPath("/version").DoRaw(context.TODO())
+			}
+			if reason := ConnectionFailureReason(err); reason != c.expectedReason {
+				t.Errorf("expected reason %q, but got %q for error %v", c.expectedReason, reason, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// deniedActionsCacheSize is the max number of the denied actions remembered to suppress the duplicated events.
+	deniedActionsCacheSize = 1000
+	// deniedActionsReportInterval is the interval to report a denied action again.
+	deniedActionsReportInterval = time.Hour
+)
+
+// ReportDeniedActions wraps the transport of the config to record a warning event once a request is forbidden by
+// the apiserver. It helps to find out the permissions missing in the agent roles when the RBAC is narrowly scoped.
+// A denied action is reported at most once in an hour.
+func ReportDeniedActions(config *rest.Config, recorder events.Recorder) {
+	reported := cache.NewLRUExpireCache(deniedActionsCacheSize)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &deniedActionsRoundTripper{
+			delegate: rt,
+			recorder: recorder,
+			reported: reported,
+		}
+	})
+}
+
+type deniedActionsRoundTripper struct {
+	delegate http.RoundTripper
+	recorder events.Recorder
+	reported *cache.LRUExpireCache
+}
+
+func (rt *deniedActionsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+
+	action := fmt.Sprintf("%s %s", requestVerb(req), req.URL.Path)
+	if _, ok := rt.reported.Get(action); !ok {
+		rt.reported.Add(action, struct{}{}, deniedActionsReportInterval)
+		rt.recorder.Warningf("ActionDenied", "The agent is not permitted to %s", action)
+	}
+	return resp, err
+}
+
+// requestVerb returns the kube verb of the request, list and get are not distinguished since it requires parsing
+// the request path.
+func requestVerb(req *http.Request) string {
+	switch req.Method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	case http.MethodGet:
+		if req.URL.Query().Get("watch") == "true" {
+			return "watch"
+		}
+		return "get"
+	default:
+		return req.Method
+	}
+}
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestReportDeniedActions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/ns1/secrets/denied" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"kind":"Secret","apiVersion":"v1","metadata":{"name":"allowed","namespace":"ns1"}}`))
+	}))
+	defer server.Close()
+
+	recorder := events.NewInMemoryRecorder("test")
+	config := &rest.Config{Host: server.URL}
+	ReportDeniedActions(config, recorder)
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kubeClient.CoreV1().Secrets("ns1").Get(context.TODO(), "allowed", metav1.GetOptions{}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := kubeClient.CoreV1().Secrets("ns1").Get(context.TODO(), "denied", metav1.GetOptions{}); err == nil {
+			t.Fatalf("expected forbidden err")
+		}
+	}
+
+	reported := recorder.Events()
+	if len(reported) != 1 {
+		t.Fatalf("expected 1 event, but got %v", reported)
+	}
+	if reported[0].Reason != "ActionDenied" ||
+		reported[0].Message != "The agent is not permitted to get /api/v1/namespaces/ns1/secrets/denied" {
+		t.Errorf("unexpected event %v", reported[0])
+	}
+}
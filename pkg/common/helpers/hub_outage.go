@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+)
+
+// HubOutagesConfigMapName is the name of the configmap in the agent namespace, in which the agents running in their
+// own processes, e.g. the work agent in the Default mode, publish the last outage of the hub observed by their circuit
+// breakers keyed by the component name. The outages are reported in the status of the ManagedCluster by the
+// registration agent.
+const HubOutagesConfigMapName = "hub-outages"
+
+// HubOutage is the last outage of the hub observed by the circuit breaker of a component.
+type HubOutage struct {
+	// Component is the name of the component observing the outage.
+	Component string `json:"-"`
+	// Unreachable is true if the hub is unreachable now.
+	Unreachable bool `json:"unreachable,omitempty"`
+	// Start is the start of the outage, it is zero if the hub has never been unreachable.
+	Start time.Time `json:"start,omitempty"`
+	// End is the end of the outage, it is zero if the outage is not over.
+	End time.Time `json:"end,omitempty"`
+}
+
+// LastHubOutage returns the last outage of the hub observed by the circuit breaker.
+func LastHubOutage(component string, breaker *CircuitBreaker) HubOutage {
+	unreachable, start, end := breaker.LastOutage()
+	return HubOutage{Component: component, Unreachable: unreachable, Start: start, End: end}
+}
+
+// PublishHubOutage writes the last outage of the hub observed by the circuit breaker of the component into the
+// outages configmap, nothing is written if the hub has never been unreachable.
+func PublishHubOutage(ctx context.Context, client corev1client.ConfigMapsGetter, namespace string, outage HubOutage) error {
+	if outage.Start.IsZero() {
+		return nil
+	}
+	data, err := json.Marshal(outage)
+	if err != nil {
+		return err
+	}
+	value := string(data)
+
+	cm, err := client.ConfigMaps(namespace).Get(ctx, HubOutagesConfigMapName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		_, err = client.ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: HubOutagesConfigMapName, Namespace: namespace},
+			Data:       map[string]string{outage.Component: value},
+		}, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+
+	if cm.Data[outage.Component] == value {
+		return nil
+	}
+	cm = cm.DeepCopy()
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[outage.Component] = value
+	_, err = client.ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// PublishedHubOutages returns the outages of the hub published by the other components in the outages configmap,
+// ordered by the component name.
+func PublishedHubOutages(lister corev1lister.ConfigMapNamespaceLister) ([]HubOutage, error) {
+	cm, err := lister.Get(HubOutagesConfigMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var outages []HubOutage
+	for component, value := range cm.Data {
+		outage := HubOutage{}
+		if err := json.Unmarshal([]byte(value), &outage); err != nil {
+			// the value is written by the agents, an invalid one is skipped until it is written again.
+			continue
+		}
+		outage.Component = component
+		outages = append(outages, outage)
+	}
+	sort.Slice(outages, func(i, j int) bool { return outages[i].Component < outages[j].Component })
+	return outages, nil
+}
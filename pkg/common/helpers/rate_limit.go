@@ -0,0 +1,127 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// rateLimitCacheSize is the max number of the events and the conditions remembered to suppress the duplicated
+	// ones.
+	rateLimitCacheSize = 1000
+	// EventRateLimitInterval is the interval to record an identical event again.
+	EventRateLimitInterval = 5 * time.Minute
+	// ConditionDedupInterval is the interval to update an identical condition again, so a condition lost on the
+	// hub, e.g. after the cluster is recreated, is updated eventually.
+	ConditionDedupInterval = 10 * time.Minute
+)
+
+// conditionUpdateFunc updates a condition of an object, e.g. the condition of the managed cluster on the hub.
+type conditionUpdateFunc = func(ctx context.Context, cond metav1.Condition) error
+
+// NewDeduplicatedConditionUpdater wraps a conditionUpdateFunc to skip updating a condition identical to the last one
+// updated successfully within the interval. It prevents the repeated patches to the hub when a controller fails with
+// the same error again and again.
+func NewDeduplicatedConditionUpdater(updater conditionUpdateFunc, interval time.Duration) conditionUpdateFunc {
+	return newDeduplicatedConditionUpdater(updater, interval, cache.NewLRUExpireCache(rateLimitCacheSize))
+}
+
+func newDeduplicatedConditionUpdater(updater conditionUpdateFunc, interval time.Duration,
+	updated *cache.LRUExpireCache) conditionUpdateFunc {
+	return func(ctx context.Context, cond metav1.Condition) error {
+		if last, ok := updated.Get(cond.Type); ok && equality.Semantic.DeepEqual(last, cond) {
+			klog.V(4).Infof("Skip updating the condition %s since it is not changed", cond.Type)
+			return nil
+		}
+
+		if err := updater(ctx, cond); err != nil {
+			updated.Remove(cond.Type)
+			return err
+		}
+		updated.Add(cond.Type, cond, interval)
+		return nil
+	}
+}
+
+// NewRateLimitedRecorder returns a recorder that records the identical events, which have the same component, type,
+// reason and message, at most once within the interval, the events suppressed are logged only. It prevents the event storms when the controllers keep
+// failing, e.g. during a hub outage. The recorders derived from it share the same limit.
+func NewRateLimitedRecorder(recorder events.Recorder, interval time.Duration) events.Recorder {
+	return &rateLimitedRecorder{
+		Recorder: recorder,
+		limiter: &eventLimiter{
+			interval: interval,
+			recorded: cache.NewLRUExpireCache(rateLimitCacheSize),
+		},
+	}
+}
+
+type eventLimiter struct {
+	lock     sync.Mutex
+	interval time.Duration
+	recorded *cache.LRUExpireCache
+}
+
+// allow returns true if the event has not been recorded within the interval.
+func (l *eventLimiter) allow(key string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, ok := l.recorded.Get(key); ok {
+		return false
+	}
+	l.recorded.Add(key, struct{}{}, l.interval)
+	return true
+}
+
+type rateLimitedRecorder struct {
+	events.Recorder
+	limiter *eventLimiter
+}
+
+func (r *rateLimitedRecorder) Event(reason, message string) {
+	if r.allow("Normal", reason, message) {
+		r.Recorder.Event(reason, message)
+	}
+}
+
+func (r *rateLimitedRecorder) Eventf(reason, messageFmt string, args ...interface{}) {
+	r.Event(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *rateLimitedRecorder) Warning(reason, message string) {
+	if r.allow("Warning", reason, message) {
+		r.Recorder.Warning(reason, message)
+	}
+}
+
+func (r *rateLimitedRecorder) Warningf(reason, messageFmt string, args ...interface{}) {
+	r.Warning(reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *rateLimitedRecorder) ForComponent(componentName string) events.Recorder {
+	return &rateLimitedRecorder{Recorder: r.Recorder.ForComponent(componentName), limiter: r.limiter}
+}
+
+func (r *rateLimitedRecorder) WithComponentSuffix(componentNameSuffix string) events.Recorder {
+	return &rateLimitedRecorder{Recorder: r.Recorder.WithComponentSuffix(componentNameSuffix), limiter: r.limiter}
+}
+
+func (r *rateLimitedRecorder) WithContext(ctx context.Context) events.Recorder {
+	return &rateLimitedRecorder{Recorder: r.Recorder.WithContext(ctx), limiter: r.limiter}
+}
+
+func (r *rateLimitedRecorder) allow(eventType, reason, message string) bool {
+	if r.limiter.allow(fmt.Sprintf("%s/%s/%s/%s", r.ComponentName(), eventType, reason, message)) {
+		return true
+	}
+	klog.V(4).Infof("Suppress the repeated %s event %s of %s: %s", eventType, reason, r.ComponentName(), message)
+	return false
+}
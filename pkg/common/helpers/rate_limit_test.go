@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestDeduplicatedConditionUpdater(t *testing.T) {
+	updates := 0
+	var updateErr error
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	updater := newDeduplicatedConditionUpdater(func(ctx context.Context, cond metav1.Condition) error {
+		updates++
+		return updateErr
+	}, time.Minute, cache.NewLRUExpireCacheWithClock(rateLimitCacheSize, fakeClock))
+
+	failed := metav1.Condition{Type: "ClusterCertificateRotated", Status: metav1.ConditionFalse, Reason: "ClientCertificateUpdateFailed"}
+	update := func(cond metav1.Condition, expectedUpdates int) {
+		t.Helper()
+		if err := updater(context.TODO(), cond); err != updateErr {
+			t.Errorf("expected error %v, but got %v", updateErr, err)
+		}
+		if updates != expectedUpdates {
+			t.Errorf("expected %d updates, but got %d", expectedUpdates, updates)
+		}
+	}
+
+	update(failed, 1)
+	// the identical condition is not updated again
+	update(failed, 1)
+
+	// the changed condition is updated
+	rotated := metav1.Condition{Type: "ClusterCertificateRotated", Status: metav1.ConditionTrue, Reason: "ClientCertificateUpdated"}
+	update(rotated, 2)
+
+	// the identical condition is updated again after the interval
+	fakeClock.Step(2 * time.Minute)
+	update(rotated, 3)
+
+	// the identical condition is updated again if the last update fails
+	updateErr = fmt.Errorf("hub is unavailable")
+	update(failed, 4)
+	update(failed, 5)
+}
+
+func TestRateLimitedRecorder(t *testing.T) {
+	inMemoryRecorder := events.NewInMemoryRecorder("test")
+	recorder := NewRateLimitedRecorder(inMemoryRecorder, time.Minute)
+	controllerRecorder := recorder.WithComponentSuffix("controller")
+
+	for i := 0; i < 3; i++ {
+		recorder.Warningf("ClientCertificateUpdateFailed", "failed to create csr: %v", "hub is unavailable")
+		controllerRecorder.Warning("ClientCertificateUpdateFailed", "hub is unavailable")
+	}
+	recorder.Warning("ClientCertificateUpdateFailed", "another error")
+	recorder.Event("ClientCertificateCreated", "a new client certificate is available")
+
+	if len(inMemoryRecorder.Events()) != 4 {
+		t.Errorf("expected 4 events, but got %v", inMemoryRecorder.Events())
+	}
+}
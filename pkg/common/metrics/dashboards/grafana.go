@@ -0,0 +1,221 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GrafanaDashboardLabel is the label of the configmaps with the dashboards, the grafana dashboard sidecar loads
+	// the dashboards in the configmaps with the label.
+	GrafanaDashboardLabel = "grafana_dashboard"
+	// DashboardFileSuffix is the suffix of the key of the dashboard json model in the configmap.
+	DashboardFileSuffix = ".json"
+
+	datasourceVariable = "${datasource}"
+	panelWidth         = 12
+	panelHeight        = 8
+
+	agentPanelDescription = "The metrics are exported by the agents on the managed clusters, the panel has data only " +
+		"if they are collected into the datasource, e.g. by federation or remote write."
+)
+
+// Dashboard is the json model of a grafana dashboard.
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+// TimeRange is the default time range of a dashboard.
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Templating is the variables of a dashboard.
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable is a variable of a dashboard, e.g. the prometheus datasource.
+type Variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+// Panel is a time series panel of a dashboard.
+type Panel struct {
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description,omitempty"`
+	Type        string      `json:"type"`
+	Datasource  Datasource  `json:"datasource"`
+	GridPos     GridPos     `json:"gridPos"`
+	FieldConfig FieldConfig `json:"fieldConfig"`
+	Targets     []Target    `json:"targets"`
+}
+
+// Datasource references the datasource of the queries of a panel.
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// GridPos is the position and the size of a panel.
+type GridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// FieldConfig sets the unit of the values of a panel.
+type FieldConfig struct {
+	Defaults FieldDefaults `json:"defaults"`
+}
+
+// FieldDefaults is the default config of the fields of a panel.
+type FieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+// Target is a prometheus query of a panel.
+type Target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+// Dashboards returns the dashboards of the registration, the work and the placement metrics.
+func Dashboards() []Dashboard {
+	return []Dashboard{
+		newDashboard("ocm-registration", "Open Cluster Management / Registration",
+			newPanel("Pending CSRs", "short",
+				target(fmt.Sprintf(`sum by (signer) (%s{state="pending"})`, registrationCSRs), "{{signer}}")),
+			newPanel("CSRs by state", "short",
+				target(fmt.Sprintf(`sum by (state) (%s)`, registrationCSRs), "{{state}}")),
+			newPanel("CSR approval duration (p99)", "s",
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le, signer) (rate(%s_bucket[5m])))`,
+					registrationCSRApprovalDuration), "{{signer}}")),
+			newPanel("Clusters halted creating CSRs", "short",
+				target(registrationCSRHaltedClusters, "halted")),
+			agentPanel("Agent requests to the hub", "reqps",
+				target(fmt.Sprintf(`sum by (code) (rate(%s[5m]))`, registrationAgentHubRequests), "{{code}}")),
+			agentPanel("Agent informer cache size", "short",
+				target(fmt.Sprintf(`sum by (informer) (%s)`, registrationAgentInformerCacheSize), "{{informer}}")),
+		),
+		newDashboard("ocm-work", "Open Cluster Management / Work",
+			agentPanel("ManifestWork spec to applied duration (p99)", "s",
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%s_bucket[5m])))`,
+					workAgentSpecToAppliedDuration), "p99")),
+			agentPanel("Manifest apply duration (p99)", "s",
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le, kind) (rate(%s_bucket[5m])))`,
+					workAgentManifestApplyDuration), "{{kind}}")),
+			agentPanel("Manifest apply errors", "ops",
+				target(fmt.Sprintf(`sum by (reason, kind) (rate(%s[5m]))`, workAgentManifestApplyErrors), "{{reason}} {{kind}}")),
+			agentPanel("Manifest applies skipped", "ops",
+				target(fmt.Sprintf(`sum by (kind) (rate(%s[5m]))`, workAgentManifestApplySkipped), "{{kind}}")),
+		),
+		newDashboard("ocm-placement", "Open Cluster Management / Placement",
+			newPanel("Scheduling duration (p99)", "s",
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%s_bucket[5m])))`, schedulingDuration), "schedule"),
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%s_bucket[5m])))`, schedulingBindDuration), "bind")),
+			newPanel("Plugin duration (p99)", "s",
+				target(fmt.Sprintf(`histogram_quantile(0.99, sum by (le, plugin_name) (rate(%s_bucket[5m])))`,
+					schedulingPluginDuration), "{{plugin_name}}")),
+			newPanel("Slowest placements", "s",
+				target(fmt.Sprintf(`topk(10, %s)`, schedulingPlacementDuration), "{{namespace}}/{{placement}}")),
+			newPanel("Candidate clusters", "short",
+				target(fmt.Sprintf(`topk(10, %s)`, schedulingPlacementCandidates), "{{namespace}}/{{placement}}")),
+			newPanel("Decision changes", "short",
+				target(fmt.Sprintf(`topk(10, sum by (namespace, placement) (increase(%s[1h])))`,
+					schedulingPlacementDecisionChanges), "{{namespace}}/{{placement}}")),
+			newPanel("Misscheduled placements", "short",
+				target(fmt.Sprintf(`sum by (namespace, placement) (increase(%s[1h])) > 0`,
+					schedulingPlacementMisscheduled), "{{namespace}}/{{placement}}")),
+		),
+	}
+}
+
+// newDashboard returns a dashboard with the panels laid out in two columns.
+func newDashboard(uid, title string, panels ...Panel) Dashboard {
+	for i := range panels {
+		panels[i].ID = i + 1
+		panels[i].GridPos = GridPos{X: (i % 2) * panelWidth, Y: (i / 2) * panelHeight, W: panelWidth, H: panelHeight}
+	}
+	return Dashboard{
+		UID:           uid,
+		Title:         title,
+		Tags:          []string{"open-cluster-management"},
+		Timezone:      "browser",
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          TimeRange{From: "now-6h", To: "now"},
+		Templating: Templating{List: []Variable{
+			{Name: "datasource", Label: "Data source", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: panels,
+	}
+}
+
+func newPanel(title, unit string, targets ...Target) Panel {
+	for i := range targets {
+		targets[i].RefID = string(rune('A' + i))
+	}
+	return Panel{
+		Title:       title,
+		Type:        "timeseries",
+		Datasource:  Datasource{Type: "prometheus", UID: datasourceVariable},
+		FieldConfig: FieldConfig{Defaults: FieldDefaults{Unit: unit}},
+		Targets:     targets,
+	}
+}
+
+// agentPanel returns a panel of the metrics of the agents, which are not scraped by the ServiceMonitors of the hub.
+func agentPanel(title, unit string, targets ...Target) Panel {
+	panel := newPanel(title, unit, targets...)
+	panel.Description = agentPanelDescription
+	return panel
+}
+
+func target(expr, legendFormat string) Target {
+	return Target{Expr: expr, LegendFormat: legendFormat}
+}
+
+// DashboardConfigMaps returns the configmaps with the json models of the dashboards, they are named after the uids
+// of the dashboards and labeled to be loaded by the grafana dashboard sidecar.
+func DashboardConfigMaps(namespace string) ([]*corev1.ConfigMap, error) {
+	var configMaps []*corev1.ConfigMap
+	for _, dashboard := range Dashboards() {
+		data, err := json.MarshalIndent(dashboard, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		configMaps = append(configMaps, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      DashboardConfigMapName(dashboard.UID),
+				Labels:    map[string]string{GrafanaDashboardLabel: "1"},
+			},
+			Data: map[string]string{dashboard.UID + DashboardFileSuffix: string(data)},
+		})
+	}
+	return configMaps, nil
+}
+
+// DashboardConfigMapName returns the name of the configmap with the dashboard of the uid.
+func DashboardConfigMapName(uid string) string {
+	return uid + "-dashboard"
+}
@@ -0,0 +1,51 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDashboardConfigMaps(t *testing.T) {
+	configMaps, err := DashboardConfigMaps("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configMaps) != 3 {
+		t.Fatalf("expected 3 dashboards, but got %d", len(configMaps))
+	}
+
+	for _, configMap := range configMaps {
+		if configMap.Namespace != "test" || configMap.Labels[GrafanaDashboardLabel] != "1" {
+			t.Errorf("unexpected configmap %s/%s with labels %v", configMap.Namespace, configMap.Name, configMap.Labels)
+		}
+		if len(configMap.Data) != 1 {
+			t.Errorf("expected one dashboard in configmap %s, but got %d", configMap.Name, len(configMap.Data))
+		}
+		for key, data := range configMap.Data {
+			dashboard := &Dashboard{}
+			if err := json.Unmarshal([]byte(data), dashboard); err != nil {
+				t.Fatalf("invalid dashboard %s: %v", key, err)
+			}
+			if key != dashboard.UID+DashboardFileSuffix || configMap.Name != dashboard.UID+"-dashboard" {
+				t.Errorf("unexpected key %s of dashboard %s in configmap %s", key, dashboard.UID, configMap.Name)
+			}
+
+			ids := map[int]bool{}
+			for _, panel := range dashboard.Panels {
+				if ids[panel.ID] {
+					t.Errorf("duplicated panel id %d in dashboard %s", panel.ID, dashboard.UID)
+				}
+				ids[panel.ID] = true
+				if len(panel.Targets) == 0 || panel.Targets[0].RefID != "A" || len(panel.Targets[0].Expr) == 0 {
+					t.Errorf("unexpected targets of panel %s in dashboard %s: %v", panel.Title, dashboard.UID, panel.Targets)
+				}
+				agent := strings.Contains(panel.Targets[0].Expr, "_agent_")
+				if documented := panel.Description == agentPanelDescription; agent != documented {
+					t.Errorf("expected panel %s of the agent metrics %v is documented, but got description %q",
+						panel.Title, agent, panel.Description)
+				}
+			}
+		}
+	}
+}
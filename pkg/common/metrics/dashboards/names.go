@@ -0,0 +1,26 @@
+package dashboards
+
+// The names of the metrics exported by the registration, the work and the placement components. The metrics
+// packages are not imported to avoid registering their metrics in the binaries generating the dashboards, the names
+// are checked against the metrics packages in the tests.
+const (
+	registrationCSRs                = "registration_csrs"
+	registrationCSRApprovalDuration = "registration_csr_approval_duration_seconds"
+	registrationCSRHaltedClusters   = "registration_csr_halted_clusters"
+
+	registrationAgentHubRequests       = "registration_agent_hub_requests_total"
+	registrationAgentInformerCacheSize = "registration_agent_informer_cache_size"
+
+	workAgentSpecToAppliedDuration = "work_agent_spec_to_applied_duration_seconds"
+	workAgentManifestApplyDuration = "work_agent_manifest_apply_duration_seconds"
+	workAgentManifestApplyErrors   = "work_agent_manifest_apply_errors_total"
+	workAgentManifestApplySkipped  = "work_agent_manifest_apply_skipped_total"
+
+	schedulingDuration                 = "scheduling_scheduling_duration_seconds"
+	schedulingBindDuration             = "scheduling_bind_duration_seconds"
+	schedulingPluginDuration           = "scheduling_plugin_duration_seconds"
+	schedulingPlacementDuration        = "scheduling_placement_duration_seconds"
+	schedulingPlacementCandidates      = "scheduling_placement_candidate_clusters"
+	schedulingPlacementDecisionChanges = "scheduling_placement_decision_changes_total"
+	schedulingPlacementMisscheduled    = "scheduling_placement_misscheduled_total"
+)
@@ -0,0 +1,42 @@
+package dashboards
+
+import (
+	"testing"
+
+	k8smetrics "k8s.io/component-base/metrics"
+
+	placementmetrics "open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	hubmetrics "open-cluster-management.io/ocm/pkg/registration/hub/metrics"
+	agentmetrics "open-cluster-management.io/ocm/pkg/registration/spoke/metrics"
+	workmetrics "open-cluster-management.io/ocm/pkg/work/spoke/metrics"
+)
+
+func TestMetricNames(t *testing.T) {
+	cases := []struct {
+		name      string
+		subsystem string
+		key       string
+	}{
+		{registrationCSRs, hubmetrics.RegistrationSubsystem, hubmetrics.CSRsKey},
+		{registrationCSRApprovalDuration, hubmetrics.RegistrationSubsystem, hubmetrics.CSRApprovalDurationKey},
+		{registrationCSRHaltedClusters, hubmetrics.RegistrationSubsystem, hubmetrics.CSRHaltedClustersKey},
+		{registrationAgentHubRequests, agentmetrics.RegistrationAgentSubsystem, agentmetrics.HubRequestsTotalKey},
+		{registrationAgentInformerCacheSize, agentmetrics.RegistrationAgentSubsystem, agentmetrics.InformerCacheSizeKey},
+		{workAgentSpecToAppliedDuration, workmetrics.WorkAgentSubsystem, workmetrics.SpecToAppliedDurationKey},
+		{workAgentManifestApplyDuration, workmetrics.WorkAgentSubsystem, workmetrics.ManifestApplyDurationKey},
+		{workAgentManifestApplyErrors, workmetrics.WorkAgentSubsystem, workmetrics.ManifestApplyErrorsTotalKey},
+		{workAgentManifestApplySkipped, workmetrics.WorkAgentSubsystem, workmetrics.ManifestApplySkippedTotalKey},
+		{schedulingDuration, placementmetrics.SchedulingSubsystem, placementmetrics.SchedulingDurationKey},
+		{schedulingBindDuration, placementmetrics.SchedulingSubsystem, placementmetrics.BindDurationKey},
+		{schedulingPluginDuration, placementmetrics.SchedulingSubsystem, placementmetrics.PluginDurationKey},
+		{schedulingPlacementDuration, placementmetrics.SchedulingSubsystem, placementmetrics.PlacementSchedulingDurationKey},
+		{schedulingPlacementCandidates, placementmetrics.SchedulingSubsystem, placementmetrics.PlacementCandidateClustersKey},
+		{schedulingPlacementDecisionChanges, placementmetrics.SchedulingSubsystem, placementmetrics.PlacementDecisionChangesKey},
+		{schedulingPlacementMisscheduled, placementmetrics.SchedulingSubsystem, placementmetrics.PlacementMisscheduledKey},
+	}
+	for _, c := range cases {
+		if expected := k8smetrics.BuildFQName("", c.subsystem, c.key); c.name != expected {
+			t.Errorf("expected metric name %s, but got %s", expected, c.name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PrometheusRuleGVR is the resource of the prometheus-operator PrometheusRules.
+var PrometheusRuleGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "prometheusrules"}
+
+const (
+	severityLabel   = "severity"
+	severityWarning = "warning"
+)
+
+// RuleGroup is a group of the Prometheus alert rules evaluated together.
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a Prometheus alert rule.
+type Rule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// AlertRuleGroups returns the alert rules of the registration, the work and the placement metrics. The metrics of
+// the hub components are scraped by the ServiceMonitors, while the metrics of the agents are exported on the managed
+// clusters, so the rules of the open-cluster-management-agents group are only evaluated if the metrics of the agents
+// are collected into the same Prometheus, e.g. by federation or remote write.
+func AlertRuleGroups() []RuleGroup {
+	return []RuleGroup{
+		{
+			Name: "open-cluster-management-registration",
+			Rules: []Rule{
+				warning("OCMManyPendingCSRs",
+					fmt.Sprintf(`sum(%s{state="pending"}) > 20`, registrationCSRs), "15m",
+					"There are {{ $value }} pending csrs of the managed clusters, the clusters may not be able to join "+
+						"the hub or rotate their client certificates."),
+				warning("OCMClustersHaltedCreatingCSRs",
+					fmt.Sprintf(`%s > 0`, registrationCSRHaltedClusters), "10m",
+					"{{ $value }} managed clusters are halted from creating csrs since their csrs reach the threshold."),
+				warning("OCMSlowCSRApproval",
+					fmt.Sprintf(`histogram_quantile(0.99, sum by (le, signer) (rate(%s_bucket[10m]))) > 300`,
+						registrationCSRApprovalDuration), "15m",
+					"The 99th percentile of the approval duration of the csrs of signer {{ $labels.signer }} is "+
+						"{{ $value }} seconds."),
+			},
+		},
+		{
+			Name: "open-cluster-management-placement",
+			Rules: []Rule{
+				// the counter only increases once a placement becomes misconfigured, so the alert fires for an hour
+				// after the placement becomes misconfigured.
+				warning("OCMPlacementMisscheduled",
+					fmt.Sprintf(`sum by (namespace, placement) (increase(%s[1h])) > 0`, schedulingPlacementMisscheduled), "5m",
+					"The placement {{ $labels.namespace }}/{{ $labels.placement }} became misconfigured in the last hour."),
+				warning("OCMPlacementThrashing",
+					fmt.Sprintf(`sum by (namespace, placement) (increase(%s[1h])) > 20`, schedulingPlacementDecisionChanges), "15m",
+					"{{ $value }} clusters are added to or removed from the decisions of the placement "+
+						"{{ $labels.namespace }}/{{ $labels.placement }} in the last hour."),
+				warning("OCMSlowPlacementScheduling",
+					fmt.Sprintf(`max by (namespace, placement) (%s) > 5`, schedulingPlacementDuration), "15m",
+					"The last scheduling of the placement {{ $labels.namespace }}/{{ $labels.placement }} took "+
+						"{{ $value }} seconds."),
+			},
+		},
+		{
+			Name: "open-cluster-management-agents",
+			Rules: []Rule{
+				warning("OCMHubRequestsFailing",
+					fmt.Sprintf(`sum by (namespace, pod) (rate(%[1]s{code=~"5..|<error>"}[5m])) / `+
+						`sum by (namespace, pod) (rate(%[1]s[5m])) > 0.1`, registrationAgentHubRequests), "10m",
+					"{{ $value | humanizePercentage }} of the requests of the registration agent "+
+						"{{ $labels.namespace }}/{{ $labels.pod }} to the hub fail."),
+				warning("OCMManifestApplyErrors",
+					fmt.Sprintf(`sum by (namespace, pod, reason) (rate(%s[10m])) > 0`, workAgentManifestApplyErrors), "15m",
+					"The work agent {{ $labels.namespace }}/{{ $labels.pod }} keeps failing to apply the manifests "+
+						"with reason {{ $labels.reason }}."),
+				warning("OCMSlowManifestWorkApply",
+					fmt.Sprintf(`histogram_quantile(0.99, sum by (le, namespace, pod) (rate(%s_bucket[10m]))) > 60`,
+						workAgentSpecToAppliedDuration), "15m",
+					"The 99th percentile of the duration to apply the manifestworks by the work agent "+
+						"{{ $labels.namespace }}/{{ $labels.pod }} is {{ $value }} seconds."),
+			},
+		},
+	}
+}
+
+func warning(alert, expr, forDuration, description string) Rule {
+	return Rule{
+		Alert:       alert,
+		Expr:        expr,
+		For:         forDuration,
+		Labels:      map[string]string{severityLabel: severityWarning},
+		Annotations: map[string]string{"description": description},
+	}
+}
+
+// PrometheusRule returns the prometheus-operator PrometheusRule with the alert rule groups.
+func PrometheusRule(namespace, name string) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(AlertRuleGroups())
+	if err != nil {
+		return nil, err
+	}
+	var groups []interface{}
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": PrometheusRuleGVR.GroupVersion().String(),
+		"kind":       "PrometheusRule",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"groups": groups,
+		},
+	}}, nil
+}
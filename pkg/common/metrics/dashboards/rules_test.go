@@ -0,0 +1,51 @@
+package dashboards
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAlertRuleGroups(t *testing.T) {
+	alerts := map[string]bool{}
+	for _, group := range AlertRuleGroups() {
+		if len(group.Name) == 0 || len(group.Rules) == 0 {
+			t.Errorf("expected named group with rules, but got %v", group)
+		}
+		for _, rule := range group.Rules {
+			if alerts[rule.Alert] {
+				t.Errorf("duplicated alert %s", rule.Alert)
+			}
+			alerts[rule.Alert] = true
+			if len(rule.Expr) == 0 || len(rule.For) == 0 || len(rule.Annotations["description"]) == 0 ||
+				len(rule.Labels[severityLabel]) == 0 {
+				t.Errorf("expected expr, for, description and severity of alert %s, but got %v", rule.Alert, rule)
+			}
+		}
+	}
+}
+
+func TestPrometheusRule(t *testing.T) {
+	rule, err := PrometheusRule("test", "ocm-alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.GetKind() != "PrometheusRule" || rule.GetNamespace() != "test" || rule.GetName() != "ocm-alerts" {
+		t.Errorf("unexpected prometheus rule %s %s/%s", rule.GetKind(), rule.GetNamespace(), rule.GetName())
+	}
+
+	groups, found, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if err != nil || !found {
+		t.Fatalf("expected groups in the spec, but got %v", rule.Object)
+	}
+	if len(groups) != len(AlertRuleGroups()) {
+		t.Errorf("expected %d groups, but got %d", len(AlertRuleGroups()), len(groups))
+	}
+	rules, _, err := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+	if err != nil || len(rules) == 0 {
+		t.Fatalf("expected rules in the first group, but got %v", groups[0])
+	}
+	if alert := rules[0].(map[string]interface{})["alert"]; alert != "OCMManyPendingCSRs" {
+		t.Errorf("unexpected first alert %v", alert)
+	}
+}
@@ -0,0 +1,49 @@
+package dashboards
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ServiceMonitorGVR is the resource of the prometheus-operator ServiceMonitors.
+var ServiceMonitorGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}
+
+// MetricsPortName is the name of the port of the services exposing the metrics of the hub components.
+const MetricsPortName = "metrics"
+
+// ServiceMonitor returns the prometheus-operator ServiceMonitor scraping the metrics port of the services with the
+// labels in the service namespace. The hub components serve the metrics with a self-signed serving certificate and
+// authorize the scrapes with the token of Prometheus, which must be allowed to get the /metrics non-resource url.
+func ServiceMonitor(namespace, name, serviceNamespace string, serviceLabels map[string]string) *unstructured.Unstructured {
+	matchLabels := map[string]interface{}{}
+	for key, value := range serviceLabels {
+		matchLabels[key] = value
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ServiceMonitorGVR.GroupVersion().String(),
+		"kind":       "ServiceMonitor",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": matchLabels,
+			},
+			"namespaceSelector": map[string]interface{}{
+				"matchNames": []interface{}{serviceNamespace},
+			},
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"port":            MetricsPortName,
+					"scheme":          "https",
+					"bearerTokenFile": "/var/run/secrets/kubernetes.io/serviceaccount/token",
+					"tlsConfig": map[string]interface{}{
+						"insecureSkipVerify": true,
+					},
+				},
+			},
+		},
+	}}
+}
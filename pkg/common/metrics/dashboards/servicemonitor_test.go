@@ -0,0 +1,27 @@
+package dashboards
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestServiceMonitor(t *testing.T) {
+	monitor := ServiceMonitor("monitoring", "registration", "open-cluster-management-hub", map[string]string{"app": "registration"})
+	if monitor.GetKind() != "ServiceMonitor" || monitor.GetNamespace() != "monitoring" || monitor.GetName() != "registration" {
+		t.Errorf("unexpected service monitor %s %s/%s", monitor.GetKind(), monitor.GetNamespace(), monitor.GetName())
+	}
+
+	matchLabels, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+	if matchLabels["app"] != "registration" {
+		t.Errorf("unexpected selector %v", matchLabels)
+	}
+	namespaces, _, _ := unstructured.NestedStringSlice(monitor.Object, "spec", "namespaceSelector", "matchNames")
+	if len(namespaces) != 1 || namespaces[0] != "open-cluster-management-hub" {
+		t.Errorf("unexpected namespace selector %v", namespaces)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	if len(endpoints) != 1 || endpoints[0].(map[string]interface{})["port"] != MetricsPortName {
+		t.Errorf("unexpected endpoints %v", endpoints)
+	}
+}
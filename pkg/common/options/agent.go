@@ -2,9 +2,12 @@ package options
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/pflag"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
@@ -14,6 +17,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/registration"
 )
@@ -34,14 +38,44 @@ type AgentOptions struct {
 	HubKubeconfigDir    string
 	HubKubeconfigFile   string
 	AgentID             string
+	// HubQPS and HubBurst are the rate limits of the clients connecting to the hub cluster, the client-go defaults
+	// are used if they are not set.
+	HubQPS   float32
+	HubBurst int
+	// ReportDeniedActions records a warning event once a request to the managed cluster is forbidden.
+	ReportDeniedActions bool
+	// Paused holds the agents during a maintenance window of the managed cluster, the work agent stops applying the
+	// manifestworks and the registration agent stops updating the status of the managed cluster on the hub, while
+	// the leases are still updated.
+	Paused bool
+	// HubCircuitBreakerThreshold is the number of the consecutive requests failed to reach the hub to open the
+	// circuit breaker of the hub clients, the circuit breaker is disabled if it is 0.
+	HubCircuitBreakerThreshold      int
+	HubCircuitBreakerInitialBackoff time.Duration
+	HubCircuitBreakerMaxBackoff     time.Duration
+
+	// ProxyCABundleFile is the file of the CA bundle of a TLS-inspecting proxy between the agents and the hub. It
+	// is appended to the CA of the hub kubeconfig, since the CA of a kubeconfig replaces the system trust bundle.
+	ProxyCABundleFile string
+
+	// hubCircuitBreaker is shared by all the hub clients of the agents.
+	hubCircuitBreaker *helpers.CircuitBreaker
+
+	// hubHTTPClients are the http clients to the hub keyed by the hub kubeconfig files, they are shared by all the
+	// hub clients of the agents.
+	hubHTTPClients     map[string]*http.Client
+	hubHTTPClientsLock sync.Mutex
 }
 
 // NewAgentOptions returns the flags with default value set
 func NewAgentOptions() *AgentOptions {
 	opts := &AgentOptions{
-		HubKubeconfigDir:   "/spoke/hub-kubeconfig",
-		ComponentNamespace: defaultSpokeComponentNamespace,
-		CommonOpts:         NewOptions(),
+		HubKubeconfigDir:                "/spoke/hub-kubeconfig",
+		ComponentNamespace:              defaultSpokeComponentNamespace,
+		CommonOpts:                      NewOptions(),
+		HubCircuitBreakerThreshold:      5,
+		HubCircuitBreakerInitialBackoff: 1 * time.Second,
+		HubCircuitBreakerMaxBackoff:     30 * time.Second,
 	}
 	// get component namespace of spoke agent
 	nsBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
@@ -63,6 +97,26 @@ func (o *AgentOptions) AddFlags(flags *pflag.FlagSet) {
 		"The mount path of hub-kubeconfig-secret in the container.")
 	flags.StringVar(&o.HubKubeconfigFile, "hub-kubeconfig", o.HubKubeconfigFile, "Location of kubeconfig file to connect to hub cluster.")
 	flags.StringVar(&o.AgentID, "agent-id", o.AgentID, "ID of the agent")
+	flags.Float32Var(&o.HubQPS, "hub-kube-api-qps", o.HubQPS,
+		"QPS to use while talking with apiserver on hub cluster, the client-go default is used if it is not set.")
+	flags.IntVar(&o.HubBurst, "hub-kube-api-burst", o.HubBurst,
+		"Burst to use while talking with apiserver on hub cluster, the client-go default is used if it is not set.")
+	flags.BoolVar(&o.ReportDeniedActions, "report-denied-actions", o.ReportDeniedActions,
+		"Record a warning event once a request to the managed cluster is forbidden.")
+	flags.BoolVar(&o.Paused, "paused", o.Paused,
+		"Pause the agents during a maintenance window of the managed cluster. The work agent stops applying and "+
+			"deleting the resources of the manifestworks, and the registration agent stops updating the status of the "+
+			"managed cluster on the hub, while the leases are still renewed.")
+	flags.IntVar(&o.HubCircuitBreakerThreshold, "hub-circuit-breaker-threshold", o.HubCircuitBreakerThreshold,
+		"The number of the consecutive requests failed to reach the hub before the requests to the hub are rejected "+
+			"without being sent, until a probe of the hub succeeds. The circuit breaker is disabled if it is 0.")
+	flags.DurationVar(&o.HubCircuitBreakerInitialBackoff, "hub-circuit-breaker-initial-backoff",
+		o.HubCircuitBreakerInitialBackoff, "The interval to probe the hub after the circuit breaker is opened.")
+	flags.DurationVar(&o.HubCircuitBreakerMaxBackoff, "hub-circuit-breaker-max-backoff", o.HubCircuitBreakerMaxBackoff,
+		"The max interval to probe the hub, the interval is doubled after each failed probe until the max.")
+	flags.StringVar(&o.ProxyCABundleFile, "proxy-ca-bundle-file", o.ProxyCABundleFile,
+		"The file of the CA bundle of the proxy between the agent and the hub, it is trusted in addition to the CA "+
+			"of the hub kubeconfig.")
 }
 
 // SpokeKubeConfig builds kubeconfig for the spoke/managed cluster
@@ -82,6 +136,95 @@ func (o *AgentOptions) SpokeKubeConfig(managedRestConfig *rest.Config) (*rest.Co
 	return spokeRestConfig, nil
 }
 
+// HubKubeConfig builds kubeconfig for the hub cluster from the kubeconfig file
+func (o *AgentOptions) HubKubeConfig(hubKubeconfigFile string) (*rest.Config, error) {
+	hubRestConfig, err := clientcmd.BuildConfigFromFlags("" /* leave masterurl as empty */, hubKubeconfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if o.HubQPS > 0 {
+		hubRestConfig.QPS = o.HubQPS
+	}
+	if o.HubBurst > 0 {
+		hubRestConfig.Burst = o.HubBurst
+	}
+	if breaker := o.HubCircuitBreaker(); breaker != nil {
+		breaker.Wrap(hubRestConfig)
+	}
+	// the CA bundles distributed by the hub make the agents trust the next CA of the hub ahead of its rotation.
+	if err := appendCABundle(hubRestConfig, path.Join(o.HubKubeconfigDir, clientcert.HubCABundleFile)); err != nil {
+		return nil, err
+	}
+	if len(o.ProxyCABundleFile) > 0 {
+		if err := appendCABundle(hubRestConfig, o.ProxyCABundleFile); err != nil {
+			return nil, err
+		}
+	}
+	return hubRestConfig, nil
+}
+
+// appendCABundle appends the CA bundle in the file to the CA of the hub kubeconfig. Nothing is changed if the bundle
+// file does not exist.
+func appendCABundle(config *rest.Config, caBundleFile string) error {
+	caBundle, err := os.ReadFile(path.Clean(caBundleFile))
+	if os.IsNotExist(err) || len(caBundle) == 0 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the CA bundle file %q: %w", caBundleFile, err)
+	}
+
+	caData := append([]byte{}, config.CAData...)
+	if len(caData) == 0 && len(config.CAFile) > 0 {
+		caData, err = os.ReadFile(config.CAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read the CA file %q of the hub kubeconfig: %w", config.CAFile, err)
+		}
+	}
+	if len(caData) > 0 && !strings.HasSuffix(string(caData), "\n") {
+		caData = append(caData, '\n')
+	}
+	config.CAData = append(caData, caBundle...)
+	config.CAFile = ""
+	return nil
+}
+
+// HubCircuitBreaker returns the circuit breaker shared by the hub clients, it is nil if the circuit breaker is
+// disabled.
+func (o *AgentOptions) HubCircuitBreaker() *helpers.CircuitBreaker {
+	if o.HubCircuitBreakerThreshold <= 0 {
+		return nil
+	}
+	if o.hubCircuitBreaker == nil {
+		o.hubCircuitBreaker = helpers.NewCircuitBreaker(
+			o.HubCircuitBreakerThreshold, o.HubCircuitBreakerInitialBackoff, o.HubCircuitBreakerMaxBackoff)
+	}
+	return o.hubCircuitBreaker
+}
+
+// HubHTTPClient returns the http client to the hub for the hub kubeconfig file. It is built from the hub rest config
+// once and shared by all the hub clients of the agents in the process, e.g. the registration agent and the work
+// agent in the Singleton mode, so the connections to the hub are reused. The transport wrappers of the rest config
+// used the first time are kept.
+func (o *AgentOptions) HubHTTPClient(hubKubeconfigFile string, hubRestConfig *rest.Config) (*http.Client, error) {
+	o.hubHTTPClientsLock.Lock()
+	defer o.hubHTTPClientsLock.Unlock()
+
+	key := path.Clean(hubKubeconfigFile)
+	if client, ok := o.hubHTTPClients[key]; ok {
+		return client, nil
+	}
+	client, err := rest.HTTPClientFor(hubRestConfig)
+	if err != nil {
+		return nil, err
+	}
+	if o.hubHTTPClients == nil {
+		o.hubHTTPClients = map[string]*http.Client{}
+	}
+	o.hubHTTPClients[key] = client
+	return client, nil
+}
+
 func (o *AgentOptions) Validate() error {
 	if o.SpokeClusterName == "" {
 		return fmt.Errorf("cluster name is empty")
@@ -89,6 +232,16 @@ func (o *AgentOptions) Validate() error {
 	if errMsgs := apimachineryvalidation.ValidateNamespaceName(o.SpokeClusterName, false); len(errMsgs) > 0 {
 		return fmt.Errorf("metadata.name format is not correct: %s", strings.Join(errMsgs, ","))
 	}
+	if o.HubQPS < 0 || o.HubBurst < 0 {
+		return fmt.Errorf("hub kube api qps and burst must not be negative")
+	}
+	if o.HubCircuitBreakerThreshold < 0 {
+		return fmt.Errorf("hub circuit breaker threshold must not be negative")
+	}
+	if o.HubCircuitBreakerThreshold > 0 &&
+		(o.HubCircuitBreakerInitialBackoff <= 0 || o.HubCircuitBreakerMaxBackoff < o.HubCircuitBreakerInitialBackoff) {
+		return fmt.Errorf("hub circuit breaker backoff must be positive and not exceed the max backoff")
+	}
 
 	return nil
 }
@@ -7,12 +7,15 @@ import (
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/version"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
 )
 
 type Options struct {
 	CmdConfig *controllercmd.ControllerCommandConfig
 	Burst     int
 	QPS       float32
+	FIPSMode  bool
 }
 
 // NewOptions returns the flags with default value set
@@ -34,13 +37,22 @@ func (o *Options) startWithQPS(startFunc controllercmd.StartFunc) controllercmd.
 	return func(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 		controllerContext.KubeConfig.QPS = o.QPS
 		controllerContext.KubeConfig.Burst = o.Burst
+		if o.FIPSMode {
+			fips.Enable()
+		}
+		fips.Validate()
 		return startFunc(ctx, controllerContext)
 	}
 }
 
 func (o *Options) AddFlags(flags *pflag.FlagSet) {
-	flags.Float32Var(&o.QPS, "kube-api-qps", o.QPS, "QPS to use while talking with apiserver on spoke cluster.")
-	flags.IntVar(&o.Burst, "kube-api-burst", o.Burst, "Burst to use while talking with apiserver on spoke cluster.")
+	flags.Float32Var(&o.QPS, "kube-api-qps", o.QPS,
+		"QPS to use while talking with apiserver on the cluster the component runs on, the managed cluster for agents.")
+	flags.IntVar(&o.Burst, "kube-api-burst", o.Burst,
+		"Burst to use while talking with apiserver on the cluster the component runs on, the managed cluster for agents.")
+	flags.BoolVar(&o.FIPSMode, "fips-mode", o.FIPSMode,
+		"Restrict the TLS connections to the FIPS approved algorithms. The mode is always on if the binary is built "+
+			"with boringcrypto.")
 	if o.CmdConfig != nil {
 		flags.BoolVar(&o.CmdConfig.DisableLeaderElection, "disable-leader-election", false, "Disable leader election.")
 		flags.DurationVar(&o.CmdConfig.LeaseDuration.Duration, "leader-election-lease-duration", 137*time.Second, ""+
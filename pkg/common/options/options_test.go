@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
@@ -150,9 +151,11 @@ func TestComplete(t *testing.T) {
 
 func TestValidate(t *testing.T) {
 	cases := []struct {
-		name        string
-		clusterName string
-		expectedErr bool
+		name              string
+		clusterName       string
+		breakerThreshold  int
+		breakerMaxBackoff time.Duration
+		expectedErr       bool
 	}{
 		{
 			name:        "empty cluster name",
@@ -168,12 +171,23 @@ func TestValidate(t *testing.T) {
 			clusterName: "cluster-1",
 			expectedErr: false,
 		},
+		{
+			name:              "hub circuit breaker max backoff is less than the initial backoff",
+			clusterName:       "cluster-1",
+			breakerThreshold:  3,
+			breakerMaxBackoff: time.Millisecond,
+			expectedErr:       true,
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			options := NewAgentOptions()
 			options.SpokeClusterName = c.clusterName
+			options.HubCircuitBreakerThreshold = c.breakerThreshold
+			if c.breakerMaxBackoff > 0 {
+				options.HubCircuitBreakerMaxBackoff = c.breakerMaxBackoff
+			}
 			err := options.Validate()
 			if err == nil && c.expectedErr {
 				t.Errorf("expect to get err")
@@ -263,3 +277,140 @@ func TestNewOptions(t *testing.T) {
 		t.Errorf("Should return err")
 	}
 }
+
+func TestHubKubeConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "testhubkubeconfig")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	kubeconfigFile := path.Join(tempDir, "kubeconfig")
+	testinghelpers.WriteFile(kubeconfigFile,
+		testinghelpers.NewKubeconfig("c1", "https://127.0.0.1:6443", "", nil, nil, nil))
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.TLSCertFile), []byte("cert"))
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.TLSKeyFile), []byte("key"))
+
+	cases := []struct {
+		name          string
+		qps           float32
+		burst         int
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		{
+			name: "client-go default",
+		},
+		{
+			name:          "rate limits are set",
+			qps:           100,
+			burst:         200,
+			expectedQPS:   100,
+			expectedBurst: 200,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			options := NewAgentOptions()
+			options.HubQPS = c.qps
+			options.HubBurst = c.burst
+			config, err := options.HubKubeConfig(kubeconfigFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.QPS != c.expectedQPS || config.Burst != c.expectedBurst {
+				t.Errorf("expect qps %v and burst %d, but got %v and %d",
+					c.expectedQPS, c.expectedBurst, config.QPS, config.Burst)
+			}
+		})
+	}
+}
+
+func TestHubKubeConfigWithHubCABundle(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "testhubcabundle")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	caData := testinghelpers.NewTestCert("hub-ca", time.Hour).Cert
+	nextCAData := testinghelpers.NewTestCert("next-hub-ca", time.Hour).Cert
+	kubeconfigFile := path.Join(tempDir, "kubeconfig")
+	testinghelpers.WriteFile(kubeconfigFile,
+		testinghelpers.NewKubeconfig("c1", "https://127.0.0.1:6443", "", caData, nil, nil))
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.TLSCertFile), []byte("cert"))
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.TLSKeyFile), []byte("key"))
+
+	options := NewAgentOptions()
+	options.HubKubeconfigDir = tempDir
+	config, err := options.HubKubeConfig(kubeconfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config.CAData) != string(caData) {
+		t.Errorf("expect the CA of the kubeconfig only, but got %s", config.CAData)
+	}
+
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.HubCABundleFile), nextCAData)
+	config, err = options.HubKubeConfig(kubeconfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config.CAData) != string(caData)+string(nextCAData) {
+		t.Errorf("expect the hub CA bundle is appended, but got %s", config.CAData)
+	}
+}
+
+func TestHubKubeConfigWithProxyCABundle(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "testproxycabundle")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	caData := testinghelpers.NewTestCert("hub-ca", time.Hour).Cert
+	proxyCAData := testinghelpers.NewTestCert("proxy-ca", time.Hour).Cert
+	kubeconfigFile := path.Join(tempDir, "kubeconfig")
+	testinghelpers.WriteFile(kubeconfigFile,
+		testinghelpers.NewKubeconfig("c1", "https://127.0.0.1:6443", "", caData, nil, nil))
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.TLSCertFile), []byte("cert"))
+	testinghelpers.WriteFile(path.Join(tempDir, clientcert.TLSKeyFile), []byte("key"))
+	proxyCAFile := path.Join(tempDir, "proxy-ca.crt")
+	testinghelpers.WriteFile(proxyCAFile, proxyCAData)
+
+	options := NewAgentOptions()
+	options.HubKubeconfigDir = tempDir
+	options.ProxyCABundleFile = proxyCAFile
+	config, err := options.HubKubeConfig(kubeconfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config.CAData) != string(caData)+string(proxyCAData) {
+		t.Errorf("expect the proxy CA bundle is appended, but got %s", config.CAData)
+	}
+}
+
+func TestHubHTTPClient(t *testing.T) {
+	opts := NewAgentOptions()
+	config := &rest.Config{Host: "https://hub:6443", TLSClientConfig: rest.TLSClientConfig{Insecure: true}}
+
+	client, err := opts.HubHTTPClient("/spoke/hub-kubeconfig/kubeconfig", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared, err := opts.HubHTTPClient("/spoke/hub-kubeconfig/./kubeconfig", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != shared {
+		t.Errorf("expected the http client to be shared for the same hub kubeconfig")
+	}
+
+	other, err := opts.HubHTTPClient("/spoke/other-kubeconfig/kubeconfig", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client == other {
+		t.Errorf("expected a different http client for another hub kubeconfig")
+	}
+}
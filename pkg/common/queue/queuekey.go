@@ -21,6 +21,17 @@ func FileterByLabelKeyValue(key, value string) factory.EventFilterFunc {
 	}
 }
 
+func FilterByAnnotation(key string) factory.EventFilterFunc {
+	return func(obj interface{}) bool {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return false
+		}
+		_, ok := accessor.GetAnnotations()[key]
+		return ok
+	}
+}
+
 func FilterByNames(names ...string) factory.EventFilterFunc {
 	return func(obj interface{}) bool {
 		accessor, _ := meta.Accessor(obj)
@@ -41,6 +41,19 @@ func TestFileter(t *testing.T) {
 			object:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"test": "value1"}}},
 			filtered: true,
 		},
+		{
+			name:     "filter by annotation with no annotation",
+			filter:   FilterByAnnotation("test"),
+			object:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"test": "value1"}}},
+			filtered: false,
+		},
+		{
+			name:   "filter by annotation with annotation",
+			filter: FilterByAnnotation("test"),
+			object: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name: "test", Annotations: map[string]string{"test": ""}}},
+			filtered: true,
+		},
 		{
 			name:     "filter by unmatched name",
 			filter:   FilterByNames("test"),
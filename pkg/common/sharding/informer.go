@@ -0,0 +1,250 @@
+package sharding
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coordinformers "k8s.io/client-go/informers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	workinformerv1alpha1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1alpha1"
+	worklisterv1alpha1 "open-cluster-management.io/api/client/work/listers/work/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// shardedInformer only delivers the events of the objects owned by the shard to the event handlers. The objects
+// newly owned after the shard members change are delivered to the handlers again as updates.
+type shardedInformer struct {
+	cache.SharedIndexInformer
+	owns func(obj interface{}) bool
+
+	lock     sync.Mutex
+	handlers []cache.ResourceEventHandler
+}
+
+func newShardedInformer(informer cache.SharedIndexInformer, sharder *Sharder,
+	owns func(obj interface{}) bool) *shardedInformer {
+	i := &shardedInformer{
+		SharedIndexInformer: informer,
+		owns:                owns,
+	}
+	sharder.AddMembershipHandler(i.redeliver)
+	return i
+}
+
+func (i *shardedInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	return i.SharedIndexInformer.AddEventHandler(i.filter(handler))
+}
+
+func (i *shardedInformer) AddEventHandlerWithResyncPeriod(
+	handler cache.ResourceEventHandler, resyncPeriod time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	return i.SharedIndexInformer.AddEventHandlerWithResyncPeriod(i.filter(handler), resyncPeriod)
+}
+
+func (i *shardedInformer) filter(handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+	filtered := cache.FilteringResourceEventHandler{
+		FilterFunc: i.owns,
+		Handler:    handler,
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.handlers = append(i.handlers, filtered)
+	return filtered
+}
+
+func (i *shardedInformer) redeliver() {
+	i.lock.Lock()
+	handlers := i.handlers
+	i.lock.Unlock()
+
+	for _, obj := range i.GetStore().List() {
+		for _, handler := range handlers {
+			handler.OnUpdate(obj, obj)
+		}
+	}
+}
+
+// TypedInformer is a typed informer, e.g. a LeaseInformer, with the sharded informer and the lister of the
+// wrapped typed informer.
+type TypedInformer[L any] struct {
+	informer cache.SharedIndexInformer
+	lister   L
+}
+
+func (i *TypedInformer[L]) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *TypedInformer[L]) Lister() L {
+	return i.lister
+}
+
+// managedClusterLister only lists the managed clusters owned by the shard, so the controllers enqueuing all the
+// managed clusters only enqueue those owned by the shard. The managed clusters not owned can still be got.
+type managedClusterLister struct {
+	clusterlisterv1.ManagedClusterLister
+	sharder *Sharder
+}
+
+func (l *managedClusterLister) List(selector labels.Selector) ([]*clusterv1.ManagedCluster, error) {
+	clusters, err := l.ManagedClusterLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	var owned []*clusterv1.ManagedCluster
+	for _, cluster := range clusters {
+		if l.sharder.OwnsCluster(cluster) {
+			owned = append(owned, cluster)
+		}
+	}
+	return owned, nil
+}
+
+// NewManagedClusterInformer returns a ManagedCluster informer whose event handlers only receive the events of the
+// managed clusters owned by the shard, and whose lister only lists the managed clusters owned by the shard.
+func NewManagedClusterInformer(
+	informer clusterinformerv1.ManagedClusterInformer, sharder *Sharder) clusterinformerv1.ManagedClusterInformer {
+	return &TypedInformer[clusterlisterv1.ManagedClusterLister]{
+		informer: newShardedInformer(informer.Informer(), sharder, func(obj interface{}) bool {
+			accessor, err := accessorOf(obj)
+			if err != nil {
+				return false
+			}
+			return sharder.OwnsCluster(accessor)
+		}),
+		lister: &managedClusterLister{ManagedClusterLister: informer.Lister(), sharder: sharder},
+	}
+}
+
+// NewLeaseInformer returns a Lease informer whose event handlers only receive the events of the leases in the
+// namespaces of the managed clusters owned by the shard.
+func NewLeaseInformer(informer coordinformers.LeaseInformer, clusterLister clusterlisterv1.ManagedClusterLister,
+	sharder *Sharder) coordinformers.LeaseInformer {
+	return NewClusterLabeledInformer(informer.Informer(), informer.Lister(), clusterLister, sharder)
+}
+
+// NewClusterLabeledInformer wraps the informer and the lister of a typed informer, the event handlers of the
+// returned informer only receive the events of the objects whose cluster name label is a managed cluster owned
+// by the shard. The returned informer implements the typed informer, e.g. a RoleInformer, whose lister is L.
+func NewClusterLabeledInformer[L any](informer cache.SharedIndexInformer, lister L,
+	clusterLister clusterlisterv1.ManagedClusterLister, sharder *Sharder) *TypedInformer[L] {
+	return &TypedInformer[L]{
+		informer: newShardedInformer(informer, sharder, func(obj interface{}) bool {
+			accessor, err := accessorOf(obj)
+			if err != nil {
+				return false
+			}
+			return ownsClusterByName(clusterLister, sharder, accessor.GetLabels()[clusterv1.ClusterNameLabelKey])
+		}),
+		lister: lister,
+	}
+}
+
+// NewClusterNamespacedInformer wraps the informer and the lister of a typed informer, the event handlers of the
+// returned informer only receive the events of the objects in the namespaces of the managed clusters owned by the
+// shard, e.g. the ManagedClusterAddOns.
+func NewClusterNamespacedInformer[L any](informer cache.SharedIndexInformer, lister L,
+	clusterLister clusterlisterv1.ManagedClusterLister, sharder *Sharder) *TypedInformer[L] {
+	return &TypedInformer[L]{
+		informer: newShardedInformer(informer, sharder, func(obj interface{}) bool {
+			accessor, err := accessorOf(obj)
+			if err != nil {
+				return false
+			}
+			return ownsClusterByName(clusterLister, sharder, accessor.GetNamespace())
+		}),
+		lister: lister,
+	}
+}
+
+// NewKeyedInformer wraps the informer and the lister of a typed informer, the event handlers of the returned
+// informer only receive the events of the objects whose keys returned by the key func are owned by the shard,
+// e.g. the ManifestWorks are handled by the shard owning their ManifestWorkReplicaSets.
+func NewKeyedInformer[L any](informer cache.SharedIndexInformer, lister L, sharder *Sharder,
+	keyFunc func(obj metav1.Object) string) *TypedInformer[L] {
+	return &TypedInformer[L]{
+		informer: newShardedInformer(informer, sharder, func(obj interface{}) bool {
+			accessor, err := accessorOf(obj)
+			if err != nil {
+				return false
+			}
+			key := keyFunc(accessor)
+			return len(key) > 0 && sharder.Owns(key)
+		}),
+		lister: lister,
+	}
+}
+
+// NewManifestWorkReplicaSetInformer returns a ManifestWorkReplicaSet informer whose event handlers only receive the
+// events of the ManifestWorkReplicaSets owned by the shard, they are assigned by the hash of namespace/name. The
+// indexer of the informer only returns the ManifestWorkReplicaSets owned by the shard from the indices, so the
+// events of the objects referenced by the ManifestWorkReplicaSets, e.g. the placements, only enqueue those owned by
+// the shard.
+func NewManifestWorkReplicaSetInformer(informer workinformerv1alpha1.ManifestWorkReplicaSetInformer,
+	sharder *Sharder) workinformerv1alpha1.ManifestWorkReplicaSetInformer {
+	owns := func(obj interface{}) bool {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return false
+		}
+		return sharder.Owns(key)
+	}
+	return &TypedInformer[worklisterv1alpha1.ManifestWorkReplicaSetLister]{
+		informer: &indexFilteredInformer{
+			shardedInformer: newShardedInformer(informer.Informer(), sharder, owns),
+		},
+		lister: informer.Lister(),
+	}
+}
+
+// indexFilteredInformer is a sharded informer whose indexer only returns the objects owned by the shard from the
+// indices.
+type indexFilteredInformer struct {
+	*shardedInformer
+}
+
+func (i *indexFilteredInformer) GetIndexer() cache.Indexer {
+	return &shardedIndexer{Indexer: i.shardedInformer.GetIndexer(), owns: i.owns}
+}
+
+type shardedIndexer struct {
+	cache.Indexer
+	owns func(obj interface{}) bool
+}
+
+func (i *shardedIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	objs, err := i.Indexer.ByIndex(indexName, indexedValue)
+	if err != nil {
+		return nil, err
+	}
+	var owned []interface{}
+	for _, obj := range objs {
+		if i.owns(obj) {
+			owned = append(owned, obj)
+		}
+	}
+	return owned, nil
+}
+
+func ownsClusterByName(clusterLister clusterlisterv1.ManagedClusterLister, sharder *Sharder, name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	cluster, err := clusterLister.Get(name)
+	if err != nil {
+		return false
+	}
+	return sharder.OwnsCluster(cluster)
+}
+
+func accessorOf(obj interface{}) (metav1.Object, error) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	return meta.Accessor(obj)
+}
@@ -0,0 +1,147 @@
+package sharding
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
+)
+
+// newTestSharder returns a sharder holding its lease with the members.
+func newTestSharder(identity string, members ...string) *Sharder {
+	now := time.Now()
+	sharder := NewSharder(kubefake.NewSimpleClientset(), "open-cluster-management-hub", "registration-controller", NewOptions())
+	sharder.identity = identity
+	sharder.clock = clocktesting.NewFakeClock(now)
+	sharder.members = members
+	sharder.renewed = now
+	return sharder
+}
+
+func TestClusterInformers(t *testing.T) {
+	sharder := newTestSharder("replica1", "replica1", "replica2")
+	clusterInformers := clusterinformers.NewSharedInformerFactory(clusterfake.NewSimpleClientset(), 10*time.Minute)
+	clusterStore := clusterInformers.Cluster().V1().ManagedClusters().Informer().GetStore()
+
+	var owned, notOwned string
+	for i := 0; len(owned) == 0 || len(notOwned) == 0; i++ {
+		cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster" + string(rune('a'+i))}}
+		if err := clusterStore.Add(cluster); err != nil {
+			t.Fatal(err)
+		}
+		if sharder.OwnsCluster(cluster) {
+			owned = cluster.Name
+		} else {
+			notOwned = cluster.Name
+		}
+	}
+
+	clusterInformer := NewManagedClusterInformer(clusterInformers.Cluster().V1().ManagedClusters(), sharder)
+	clusters, err := clusterInformer.Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cluster := range clusters {
+		if !sharder.OwnsCluster(cluster) {
+			t.Errorf("expected only the owned clusters are listed, but got %s", cluster.Name)
+		}
+	}
+	if _, err := clusterInformer.Lister().Get(notOwned); err != nil {
+		t.Errorf("expected the cluster not owned can be got, but got %v", err)
+	}
+
+	labeled := NewClusterLabeledInformer[any](cache.NewSharedIndexInformer(nil, nil, 0, cache.Indexers{}), nil,
+		clusterInformer.Lister(), sharder)
+	namespaced := NewClusterNamespacedInformer[any](cache.NewSharedIndexInformer(nil, nil, 0, cache.Indexers{}), nil,
+		clusterInformer.Lister(), sharder)
+	cases := []struct {
+		name     string
+		owns     func(obj interface{}) bool
+		obj      metav1.Object
+		expected bool
+	}{
+		{
+			name: "labeled with owned cluster",
+			owns: labeled.informer.(*shardedInformer).owns,
+			obj: &metav1.ObjectMeta{
+				Name: "role", Labels: map[string]string{clusterv1.ClusterNameLabelKey: owned}},
+			expected: true,
+		},
+		{
+			name: "labeled with cluster not owned",
+			owns: labeled.informer.(*shardedInformer).owns,
+			obj: &metav1.ObjectMeta{
+				Name: "role", Labels: map[string]string{clusterv1.ClusterNameLabelKey: notOwned}},
+		},
+		{
+			name: "not labeled",
+			owns: labeled.informer.(*shardedInformer).owns,
+			obj:  &metav1.ObjectMeta{Name: "role"},
+		},
+		{
+			name:     "in owned cluster namespace",
+			owns:     namespaced.informer.(*shardedInformer).owns,
+			obj:      &metav1.ObjectMeta{Name: "addon", Namespace: owned},
+			expected: true,
+		},
+		{
+			name: "in namespace of cluster not owned",
+			owns: namespaced.informer.(*shardedInformer).owns,
+			obj:  &metav1.ObjectMeta{Name: "addon", Namespace: notOwned},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := c.owns(c.obj); actual != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestManifestWorkReplicaSetInformerIndexer(t *testing.T) {
+	sharder := newTestSharder("replica1", "replica1", "replica2")
+	workInformers := workinformers.NewSharedInformerFactory(workfake.NewSimpleClientset(), 10*time.Minute)
+	replicaSetInformer := workInformers.Work().V1alpha1().ManifestWorkReplicaSets()
+	if err := replicaSetInformer.Informer().AddIndexers(cache.Indexers{
+		"placement": func(obj interface{}) ([]string, error) { return []string{"placement1"}, nil },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	owned := 0
+	for i := 0; i < 20; i++ {
+		replicaSet := &workv1alpha1.ManifestWorkReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mwrs" + string(rune('a'+i))},
+		}
+		if err := replicaSetInformer.Informer().GetStore().Add(replicaSet); err != nil {
+			t.Fatal(err)
+		}
+		if sharder.Owns("default/" + replicaSet.Name) {
+			owned++
+		}
+	}
+
+	sharded := NewManifestWorkReplicaSetInformer(replicaSetInformer, sharder)
+	objs, err := sharded.Informer().GetIndexer().ByIndex("placement", "placement1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != owned {
+		t.Errorf("expected %d owned ManifestWorkReplicaSets from the index, but got %d", owned, len(objs))
+	}
+	if replicaSets, _ := sharded.Lister().List(labels.Everything()); len(replicaSets) != 20 {
+		t.Errorf("expected the lister is not filtered, but got %d", len(replicaSets))
+	}
+}
@@ -0,0 +1,335 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	coordv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+const (
+	// ShardByCluster assigns each managed cluster to a shard by the hash of its name.
+	ShardByCluster = "cluster"
+	// ShardByClusterSet assigns all the managed clusters of a clusterset to the same shard by the hash of the
+	// clusterset name. The clusters without the clusterset label are assigned by their names.
+	ShardByClusterSet = "clusterset"
+
+	// ShardLeaseLabel is the label on the shard member leases, its value is the name of the sharded component.
+	ShardLeaseLabel = "open-cluster-management.io/shard-of"
+)
+
+// Options is the options to run the hub controllers of a component in multiple replicas, each replica owns a shard
+// of the managed clusters.
+type Options struct {
+	// ShardBy is the strategy to assign the managed clusters to the shards, sharding is disabled if it is empty.
+	ShardBy       string
+	LeaseDuration time.Duration
+}
+
+// NewOptions returns the sharding options with default value set
+func NewOptions() *Options {
+	return &Options{
+		LeaseDuration: 60 * time.Second,
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ShardBy, "shard-by", o.ShardBy,
+		"Run the controllers in multiple replicas each owning a shard of the managed clusters, the value is the "+
+			"strategy to assign the clusters to the shards, 'cluster' or 'clusterset'. The shard members are "+
+			"coordinated by leases, so leader election should be disabled when it is set.")
+	fs.DurationVar(&o.LeaseDuration, "shard-lease-duration", o.LeaseDuration,
+		"The duration a shard member is kept after it stops renewing its lease, its clusters are reassigned to "+
+			"the other members afterwards.")
+}
+
+func (o *Options) Validate() error {
+	switch o.ShardBy {
+	case "", ShardByCluster, ShardByClusterSet:
+	default:
+		return fmt.Errorf("unsupported shard-by strategy %q", o.ShardBy)
+	}
+	if o.LeaseDuration <= 0 {
+		return fmt.Errorf("shard-lease-duration must be positive")
+	}
+	return nil
+}
+
+// Enabled returns true if the controllers are sharded.
+func (o *Options) Enabled() bool {
+	return len(o.ShardBy) > 0
+}
+
+// Sharder tracks the live members of a sharded component with a lease per member, and assigns the keys to the
+// members with rendezvous hashing, so only the keys of a leaving or joining member are reassigned.
+type Sharder struct {
+	kubeClient    kubernetes.Interface
+	namespace     string
+	component     string
+	identity      string
+	shardBy       string
+	leaseDuration time.Duration
+	clock         clock.Clock
+
+	lock     sync.RWMutex
+	members  []string
+	handlers []func()
+	// renewed is the last time the lease of this member was renewed.
+	renewed time.Time
+}
+
+// NewSharder returns a Sharder for the component whose member leases are maintained in the namespace.
+func NewSharder(kubeClient kubernetes.Interface, namespace, component string, opts *Options) *Sharder {
+	identity, err := os.Hostname()
+	if err != nil {
+		identity = string(uuid.NewUUID())
+	}
+	return &Sharder{
+		kubeClient:    kubeClient,
+		namespace:     namespace,
+		component:     component,
+		identity:      identity,
+		shardBy:       opts.ShardBy,
+		leaseDuration: opts.LeaseDuration,
+		clock:         clock.RealClock{},
+	}
+}
+
+// Run renews the lease of this member and refreshes the live members until the context is done.
+func (s *Sharder) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, s.sync, s.leaseDuration/3)
+}
+
+// AddMembershipHandler registers a handler which is called after the members change.
+func (s *Sharder) AddMembershipHandler(handler func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Owns returns true if the key is assigned to this member. Nothing is owned once this member stops holding its
+// lease, since its keys are reassigned to the other members.
+func (s *Sharder) Owns(key string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.holdsLease() && ownerOf(s.members, key) == s.identity
+}
+
+// HoldsLease returns true if the lease of this member is renewed within the lease duration.
+func (s *Sharder) HoldsLease() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.holdsLease()
+}
+
+func (s *Sharder) holdsLease() bool {
+	return !s.renewed.IsZero() && s.clock.Since(s.renewed) < s.leaseDuration
+}
+
+// Fence returns a copy of the rest config whose requests other than reads are rejected while this member does not
+// hold its lease, so a member partitioned from the apiserver does not write the objects of the keys reassigned to
+// the other members. The sharder itself must not use the fenced config to renew its lease.
+func (s *Sharder) Fence(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &fencedRoundTripper{sharder: s, delegate: rt}
+	})
+	return config
+}
+
+type fencedRoundTripper struct {
+	sharder  *Sharder
+	delegate http.RoundTripper
+}
+
+func (f *fencedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if !f.sharder.HoldsLease() {
+			return nil, fmt.Errorf("the %s request is fenced since the shard lease of %s/%s is not held",
+				req.Method, f.sharder.component, f.sharder.identity)
+		}
+	}
+	return f.delegate.RoundTrip(req)
+}
+
+// OwnsCluster returns true if the managed cluster is assigned to this member.
+func (s *Sharder) OwnsCluster(cluster metav1.Object) bool {
+	return s.Owns(ClusterShardKey(s.shardBy, cluster))
+}
+
+// ClusterShardKey returns the key to assign the managed cluster to a shard with the strategy.
+func ClusterShardKey(shardBy string, cluster metav1.Object) string {
+	if shardBy == ShardByClusterSet {
+		if clusterSet := cluster.GetLabels()[clusterv1beta2.ClusterSetLabel]; len(clusterSet) > 0 {
+			return "clusterset/" + clusterSet
+		}
+	}
+	return cluster.GetName()
+}
+
+func (s *Sharder) sync(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	if err := s.renew(ctx); err != nil {
+		logger.Error(err, "failed to renew the shard lease", "component", s.component, "identity", s.identity)
+	}
+
+	leases, err := s.kubeClient.CoordinationV1().Leases(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{ShardLeaseLabel: s.component}).String(),
+	})
+	if err != nil {
+		logger.Error(err, "failed to list the shard leases", "component", s.component)
+		return
+	}
+
+	now := s.clock.Now()
+	var members []string
+	for _, lease := range leases.Items {
+		switch {
+		case isLeaseLive(lease, now):
+			members = append(members, *lease.Spec.HolderIdentity)
+		case isLeaseStale(lease, now, s.leaseDuration):
+			s.deleteStaleLease(ctx, lease)
+		}
+	}
+	sort.Strings(members)
+
+	s.lock.Lock()
+	changed := !equalMembers(s.members, members)
+	s.members = members
+	handlers := s.handlers
+	s.lock.Unlock()
+
+	if !changed {
+		return
+	}
+	logger.Info("Shard members changed", "component", s.component, "members", members)
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+func (s *Sharder) renew(ctx context.Context) error {
+	name := fmt.Sprintf("%s-shard-%s", s.component, s.identity)
+	now := metav1.NewMicroTime(s.clock.Now())
+	lease, err := s.kubeClient.CoordinationV1().Leases(s.namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		lease = &coordv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.namespace,
+				Labels:    map[string]string{ShardLeaseLabel: s.component},
+			},
+			Spec: coordv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(s.leaseDuration.Seconds())),
+				RenewTime:            &now,
+			},
+		}
+		_, err = s.kubeClient.CoordinationV1().Leases(s.namespace).Create(ctx, lease, metav1.CreateOptions{})
+	case err != nil:
+		return err
+	default:
+		lease = lease.DeepCopy()
+		lease.Spec.HolderIdentity = ptr.To(s.identity)
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.leaseDuration.Seconds()))
+		lease.Spec.RenewTime = &now
+		_, err = s.kubeClient.CoordinationV1().Leases(s.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.renewed = now.Time
+	return nil
+}
+
+// deleteStaleLease deletes the lease of a member which is gone, e.g. the pod of a replica is replaced, since the
+// names of the leases are not reused. The lease is not deleted if it is renewed in the meantime.
+func (s *Sharder) deleteStaleLease(ctx context.Context, lease coordv1.Lease) {
+	err := s.kubeClient.CoordinationV1().Leases(s.namespace).Delete(ctx, lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: ptr.To(lease.ResourceVersion)},
+	})
+	if err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+		klog.FromContext(ctx).Error(err, "failed to delete the stale shard lease", "name", lease.Name)
+	}
+}
+
+func isLeaseLive(lease coordv1.Lease, now time.Time) bool {
+	if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return lease.Spec.RenewTime.Add(duration).After(now)
+}
+
+// isLeaseStale returns true if the lease is not renewed for another lease duration after it expires.
+func isLeaseStale(lease coordv1.Lease, now time.Time, leaseDuration time.Duration) bool {
+	if lease.Spec.RenewTime == nil {
+		return lease.CreationTimestamp.Add(2 * leaseDuration).Before(now)
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		leaseDuration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return lease.Spec.RenewTime.Add(2 * leaseDuration).Before(now)
+}
+
+// ownerOf returns the member with the highest hash of the member and the key.
+func ownerOf(members []string, key string) string {
+	var owner string
+	var highest uint64
+	for _, member := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member + "/" + key))
+		if sum := mix(h.Sum64()); len(owner) == 0 || sum > highest {
+			owner, highest = member, sum
+		}
+	}
+	return owner
+}
+
+// mix is the finalizer of murmur3, the fnv hashes of the strings only differing in a few bytes are close, so they
+// are mixed to be compared.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func equalMembers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
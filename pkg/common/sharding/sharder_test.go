@@ -0,0 +1,216 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	coordv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+func newShardLease(component, identity string, renewTime time.Time) *coordv1.Lease {
+	return &coordv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-shard-%s", component, identity),
+			Namespace: "open-cluster-management-hub",
+			Labels:    map[string]string{ShardLeaseLabel: component},
+		},
+		Spec: coordv1.LeaseSpec{
+			HolderIdentity:       ptr.To(identity),
+			LeaseDurationSeconds: ptr.To(int32(60)),
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+}
+
+func TestSharderSync(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name            string
+		leases          []*coordv1.Lease
+		expectedMembers []string
+		expectedDeleted []string
+	}{
+		{
+			name:            "only this member",
+			expectedMembers: []string{"replica1"},
+		},
+		{
+			name: "live and expired members",
+			leases: []*coordv1.Lease{
+				newShardLease("registration-controller", "replica2", now),
+				newShardLease("registration-controller", "replica3", now.Add(-2*time.Minute)),
+				newShardLease("work-manager", "replica4", now),
+			},
+			expectedMembers: []string{"replica1", "replica2"},
+		},
+		{
+			name: "stale members",
+			leases: []*coordv1.Lease{
+				newShardLease("registration-controller", "replica2", now),
+				newShardLease("registration-controller", "replica3", now.Add(-5*time.Minute)),
+			},
+			expectedMembers: []string{"replica1", "replica2"},
+			expectedDeleted: []string{"registration-controller-shard-replica3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objs []runtime.Object
+			for _, lease := range c.leases {
+				objs = append(objs, lease)
+			}
+			kubeClient := kubefake.NewSimpleClientset(objs...)
+			sharder := NewSharder(kubeClient, "open-cluster-management-hub", "registration-controller", NewOptions())
+			sharder.identity = "replica1"
+			sharder.clock = clocktesting.NewFakeClock(now)
+
+			changed := 0
+			sharder.AddMembershipHandler(func() { changed++ })
+			sharder.sync(context.TODO())
+			sharder.sync(context.TODO())
+
+			if !equalMembers(sharder.members, c.expectedMembers) {
+				t.Errorf("expected members %v, but got %v", c.expectedMembers, sharder.members)
+			}
+			if changed != 1 {
+				t.Errorf("expected the membership handler is called once, but got %d", changed)
+			}
+
+			var deleted []string
+			for _, action := range kubeClient.Actions() {
+				if deleteAction, ok := action.(clienttesting.DeleteAction); ok {
+					deleted = append(deleted, deleteAction.GetName())
+				}
+			}
+			// the stale lease is deleted in the first sync.
+			if !equalMembers(deleted, c.expectedDeleted) {
+				t.Errorf("expected deleted leases %v, but got %v", c.expectedDeleted, deleted)
+			}
+		})
+	}
+}
+
+func TestSharderFence(t *testing.T) {
+	now := time.Now()
+	fakeClock := clocktesting.NewFakeClock(now)
+	sharder := NewSharder(kubefake.NewSimpleClientset(), "open-cluster-management-hub", "registration-controller", NewOptions())
+	sharder.identity = "replica1"
+	sharder.clock = fakeClock
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	client, err := rest.HTTPClientFor(sharder.Fence(&rest.Config{Host: server.URL}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(method string) error {
+		req, err := http.NewRequest(method, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// the lease is not held before it is renewed.
+	if err := send(http.MethodGet); err != nil {
+		t.Errorf("expected the read is not fenced, but got %v", err)
+	}
+	if err := send(http.MethodPut); err == nil {
+		t.Errorf("expected the write is fenced before the lease is renewed")
+	}
+	if sharder.Owns("cluster1") {
+		t.Errorf("expected nothing is owned before the lease is renewed")
+	}
+
+	sharder.sync(context.TODO())
+	if err := send(http.MethodPut); err != nil {
+		t.Errorf("expected the write is not fenced, but got %v", err)
+	}
+	if !sharder.Owns("cluster1") {
+		t.Errorf("expected the key is owned by the only member")
+	}
+
+	// the lease expires once it is not renewed within the lease duration.
+	fakeClock.Step(2 * time.Minute)
+	if err := send(http.MethodPost); err == nil {
+		t.Errorf("expected the write is fenced after the lease expires")
+	}
+	if sharder.Owns("cluster1") {
+		t.Errorf("expected nothing is owned after the lease expires")
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests are sent, but got %d", requests)
+	}
+}
+
+func TestOwnerOf(t *testing.T) {
+	members := []string{"replica1", "replica2", "replica3"}
+	owned := map[string]int{}
+	reassigned := 0
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("cluster%d", i)
+		owner := ownerOf(members, key)
+		owned[owner]++
+
+		// only the keys of the leaving member are reassigned
+		if newOwner := ownerOf([]string{"replica1", "replica2"}, key); newOwner != owner {
+			if owner != "replica3" {
+				t.Errorf("expected key %s owned by %s is not reassigned, but got %s", key, owner, newOwner)
+			}
+			reassigned++
+		}
+	}
+
+	for _, member := range members {
+		if owned[member] == 0 {
+			t.Errorf("expected member %s owns some keys", member)
+		}
+	}
+	if reassigned != owned["replica3"] {
+		t.Errorf("expected %d keys reassigned, but got %d", owned["replica3"], reassigned)
+	}
+	if owner := ownerOf(nil, "cluster1"); owner != "" {
+		t.Errorf("expected no owner without members, but got %s", owner)
+	}
+}
+
+func TestClusterShardKey(t *testing.T) {
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cluster1",
+			Labels: map[string]string{clusterv1beta2.ClusterSetLabel: "set1"},
+		},
+	}
+	if key := ClusterShardKey(ShardByCluster, cluster); key != "cluster1" {
+		t.Errorf("expected key cluster1, but got %s", key)
+	}
+	if key := ClusterShardKey(ShardByClusterSet, cluster); key != "clusterset/set1" {
+		t.Errorf("expected key clusterset/set1, but got %s", key)
+	}
+	cluster.Labels = nil
+	if key := ClusterShardKey(ShardByClusterSet, cluster); key != "cluster1" {
+		t.Errorf("expected key cluster1, but got %s", key)
+	}
+}
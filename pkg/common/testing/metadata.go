@@ -0,0 +1,31 @@
+package testing
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakemetadata "k8s.io/client-go/metadata/fake"
+)
+
+// NewFakeMetadataClient returns a fake metadata client serving the metadata of the objects.
+func NewFakeMetadataClient(objects ...runtime.Object) *fakemetadata.FakeMetadataClient {
+	scheme := fakemetadata.NewTestScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		panic(err)
+	}
+
+	var partialObjects []runtime.Object
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		partialObject := meta.AsPartialObjectMetadata(accessor)
+		partialObject.TypeMeta = metav1.TypeMeta{
+			APIVersion: obj.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       obj.GetObjectKind().GroupVersionKind().Kind,
+		}
+		partialObjects = append(partialObjects, partialObject)
+	}
+	return fakemetadata.NewSimpleMetadataClient(scheme, partialObjects...)
+}
@@ -0,0 +1,147 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	instrumentationScope = "open-cluster-management.io/ocm"
+
+	// WorkDriverKey is the resource attribute of the driver transporting the works between the hub and the agents.
+	WorkDriverKey = attribute.Key("ocm.work.driver")
+
+	WorkNamespaceKey  = attribute.Key("ocm.work.namespace")
+	WorkNameKey       = attribute.Key("ocm.work.name")
+	WorkUIDKey        = attribute.Key("ocm.work.uid")
+	WorkGenerationKey = attribute.Key("ocm.work.generation")
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Options is the options to export the traces of the work reconciliation to an OTLP collector, tracing is disabled
+// if the endpoint is empty.
+type Options struct {
+	Endpoint               string
+	SamplingRatePerMillion int32
+}
+
+// NewOptions returns the tracing options with default value set
+func NewOptions() *Options {
+	return &Options{
+		// 1% of the work generations are traced by default
+		SamplingRatePerMillion: 10000,
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "tracing-otlp-endpoint", o.Endpoint,
+		"The OTLP gRPC endpoint of the collector the traces of the work reconciliation are exported to, "+
+			"tracing is disabled if it is empty. The connection is insecure.")
+	fs.Int32Var(&o.SamplingRatePerMillion, "tracing-sampling-rate-per-million", o.SamplingRatePerMillion,
+		"The number of the work generations to trace per million, the hub and the agents make the same decision "+
+			"for a work generation.")
+}
+
+func (o *Options) Validate() error {
+	if o.SamplingRatePerMillion < 0 || o.SamplingRatePerMillion > 1000000 {
+		return fmt.Errorf("tracing-sampling-rate-per-million must be between 0 and 1000000")
+	}
+	return nil
+}
+
+// Setup sets the global tracer provider to export the spans of the service to the endpoint, and returns a function
+// to flush the spans and stop the provider. The global tracer provider is kept as noop if the endpoint is empty.
+func (o *Options) Setup(ctx context.Context, serviceName string, attrs ...attribute.KeyValue) (func(), error) {
+	if len(o.Endpoint) == 0 {
+		return func() {}, nil
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(o.Endpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)), resource.WithAttributes(attrs...))
+	if err != nil {
+		return nil, err
+	}
+
+	// the spans of a work generation share the trace id derived from the work, sampling by the trace id makes the
+	// hub and the agents keep or drop the whole trace together.
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(float64(o.SamplingRatePerMillion)/1000000)),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			klog.Errorf("failed to shutdown the tracer provider, %v", err)
+		}
+	}, nil
+}
+
+// StartWorkSpan starts a span of the reconciliation of the current generation of a work. The span is in the trace
+// derived from the uid and generation of the work, so the spans of a work generation on the hub and the agent are
+// correlated without propagating the trace context through the transport.
+func StartWorkSpan(ctx context.Context, name string, work metav1.Object,
+	opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx = trace.ContextWithRemoteSpanContext(ctx, workSpanContext(work))
+	opts = append(opts, trace.WithAttributes(
+		WorkNamespaceKey.String(work.GetNamespace()),
+		WorkNameKey.String(work.GetName()),
+		WorkUIDKey.String(string(work.GetUID())),
+		WorkGenerationKey.Int64(work.GetGeneration()),
+	))
+	return otel.Tracer(instrumentationScope).Start(ctx, name, opts...)
+}
+
+// Start starts a child span of the span in the context, it is noop if there is no span in the context.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(instrumentationScope).
+		Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndWithError records the error on the span if it is not nil and ends the span.
+func EndWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// workSpanContext returns the span context of the virtual root span of the work generation.
+func workSpanContext(work metav1.Object) trace.SpanContext {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", work.GetUID(), work.GetGeneration())))
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	copy(traceID[:], sum[:16])
+	copy(spanID[:], sum[16:24])
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Remote:  true,
+	})
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+type spanRecorder struct {
+	lock  sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *spanRecorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (r *spanRecorder) OnEnd(s sdktrace.ReadOnlySpan) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.spans = append(r.spans, s)
+}
+
+func (r *spanRecorder) Shutdown(context.Context) error { return nil }
+
+func (r *spanRecorder) ForceFlush(context.Context) error { return nil }
+
+func newWork(uid string, generation int64) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "cluster1",
+			Name:       "work1",
+			UID:        types.UID(uid),
+			Generation: generation,
+		},
+	}
+}
+
+func TestStartWorkSpan(t *testing.T) {
+	recorder := &spanRecorder{}
+	// sample by the trace id as the provider set up by the components, the remote work parent is not sampled.
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()), sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	// the spans of the same work generation from the hub and the agent
+	_, hubSpan := StartWorkSpan(context.TODO(), "ApplyManifestWork", newWork("uid1", 1))
+	hubSpan.End()
+	ctx, agentSpan := StartWorkSpan(context.TODO(), "ApplyManifestWork", newWork("uid1", 1))
+	_, manifestSpan := Start(ctx, "ApplyManifest")
+	EndWithError(manifestSpan, fmt.Errorf("failed"))
+	agentSpan.End()
+	// the span of a new generation
+	_, newSpan := StartWorkSpan(context.TODO(), "ApplyManifestWork", newWork("uid1", 2))
+	newSpan.End()
+	// the span without a parent is not recorded
+	_, orphanSpan := Start(context.TODO(), "ApplyManifest")
+	orphanSpan.End()
+
+	if len(recorder.spans) != 4 {
+		t.Fatalf("expected 4 spans, but got %d", len(recorder.spans))
+	}
+	traceID := recorder.spans[0].SpanContext().TraceID()
+	for _, s := range recorder.spans[1:3] {
+		if s.SpanContext().TraceID() != traceID {
+			t.Errorf("expected span %q in trace %s, but got %s", s.Name(), traceID, s.SpanContext().TraceID())
+		}
+	}
+	if recorder.spans[1].Parent().SpanID() != recorder.spans[2].SpanContext().SpanID() {
+		t.Errorf("expected the manifest span is the child of the work span")
+	}
+	if recorder.spans[1].Status().Code != codes.Error {
+		t.Errorf("expected the error is recorded on the manifest span")
+	}
+	if recorder.spans[3].SpanContext().TraceID() == traceID {
+		t.Errorf("expected the new generation in a new trace")
+	}
+
+	attrs := map[string]string{}
+	for _, attr := range recorder.spans[0].Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs[string(WorkUIDKey)] != "uid1" || attrs[string(WorkGenerationKey)] != "1" ||
+		attrs[string(WorkNamespaceKey)] != "cluster1" || attrs[string(WorkNameKey)] != "work1" {
+		t.Errorf("unexpected work attributes %v", attrs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		rate        int32
+		expectedErr bool
+	}{
+		{name: "default", rate: NewOptions().SamplingRatePerMillion},
+		{name: "disabled", rate: 0},
+		{name: "negative", rate: -1, expectedErr: true},
+		{name: "too large", rate: 1000001, expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := NewOptions()
+			o.SamplingRatePerMillion = c.rate
+			if err := o.Validate(); (err != nil) != c.expectedErr {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := NewOptions().Setup(context.TODO(), "test")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	shutdown()
+
+	_, span := StartWorkSpan(context.TODO(), "ApplyManifestWork", newWork("uid1", 1))
+	defer span.End()
+	if span.IsRecording() {
+		t.Errorf("expected the span is not recorded if tracing is disabled")
+	}
+}
@@ -0,0 +1,65 @@
+package features
+
+import (
+	"fmt"
+	"os"
+
+	clientfeatures "k8s.io/client-go/features"
+	"k8s.io/component-base/featuregate"
+)
+
+// watchListEnvVar enables the reflectors of client-go to stream the initial state of the informers with a watch
+// instead of listing it. The reflectors of client-go v0.30 only read the environment variable rather than the
+// WatchListClient feature.
+const watchListEnvVar = "ENABLE_CLIENT_GO_WATCH_LIST_ALPHA"
+
+// clientGoFeatureGates adapts a MutableFeatureGate to the feature gates of client-go.
+type clientGoFeatureGates struct {
+	featureGate featuregate.MutableFeatureGate
+}
+
+func (g *clientGoFeatureGates) Enabled(key clientfeatures.Feature) bool {
+	return g.featureGate.Enabled(featuregate.Feature(key))
+}
+
+func (g *clientGoFeatureGates) Add(features map[clientfeatures.Feature]clientfeatures.FeatureSpec) error {
+	specs := map[featuregate.Feature]featuregate.FeatureSpec{}
+	for name, spec := range features {
+		featureSpec := featuregate.FeatureSpec{Default: spec.Default, LockToDefault: spec.LockToDefault}
+		switch spec.PreRelease {
+		case clientfeatures.Alpha:
+			featureSpec.PreRelease = featuregate.Alpha
+		case clientfeatures.Beta:
+			featureSpec.PreRelease = featuregate.Beta
+		case clientfeatures.GA:
+			featureSpec.PreRelease = featuregate.GA
+		case clientfeatures.Deprecated:
+			featureSpec.PreRelease = featuregate.Deprecated
+		default:
+			return fmt.Errorf("unknown prerelease %q of the client-go feature %q", spec.PreRelease, name)
+		}
+		specs[featuregate.Feature(name)] = featureSpec
+	}
+	return g.featureGate.Add(specs)
+}
+
+// AddClientGoFeatureGates adds the features of client-go, e.g. WatchListClient, to the feature gate, so they are
+// set with the --feature-gates flag instead of the environment variables.
+func AddClientGoFeatureGates(featureGate featuregate.MutableFeatureGate) error {
+	gates := &clientGoFeatureGates{featureGate: featureGate}
+	if err := clientfeatures.AddFeaturesToExistingFeatureGates(gates); err != nil {
+		return err
+	}
+	clientfeatures.ReplaceFeatureGates(gates)
+	return nil
+}
+
+// SetupWatchListClient makes the informers started afterward stream their initial state from the apiserver if the
+// WatchListClient feature is enabled, so restarting a controller with a large number of objects does not spike the
+// memory of the controller and the apiserver.
+func SetupWatchListClient() error {
+	if !clientfeatures.FeatureGates().Enabled(clientfeatures.WatchListClient) {
+		return nil
+	}
+	return os.Setenv(watchListEnvVar, "true")
+}
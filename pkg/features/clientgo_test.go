@@ -0,0 +1,37 @@
+package features
+
+import (
+	"os"
+	"testing"
+
+	clientfeatures "k8s.io/client-go/features"
+	"k8s.io/component-base/featuregate"
+)
+
+func TestAddClientGoFeatureGates(t *testing.T) {
+	featureGate := featuregate.NewFeatureGate()
+	if err := AddClientGoFeatureGates(featureGate); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetupWatchListClient(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := os.LookupEnv(watchListEnvVar); ok {
+		t.Errorf("expected the watch list is not enabled by default")
+	}
+
+	if err := featureGate.Set("WatchListClient=true"); err != nil {
+		t.Fatal(err)
+	}
+	if !clientfeatures.FeatureGates().Enabled(clientfeatures.WatchListClient) {
+		t.Errorf("expected the WatchListClient feature of client-go is enabled by the feature gate")
+	}
+	if err := SetupWatchListClient(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(watchListEnvVar)
+	if os.Getenv(watchListEnvVar) != "true" {
+		t.Errorf("expected the watch list is enabled")
+	}
+}
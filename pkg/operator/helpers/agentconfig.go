@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+const (
+	// AgentConfigsAnnotation is the annotation on the klusterlet to configure the deployment of each agent. The value
+	// is a json object whose keys are the agent names, registration, work, agent for the singleton agent or addon
+	// for the addon agents, e.g.
+	// {"work": {"resourceRequirements": {"requests": {"cpu": "100m"}}, "priorityClassName": "high",
+	// "leaderElection": {"leaseDuration": "60s"}}.
+	// The fields set for an agent override the ones in the klusterlet spec. The addon agents are not deployed by the
+	// operator, so their config is set as the defaults of the addon namespace, see AddonNamespaceAnnotations and
+	// AddonLimitRange.
+	AgentConfigsAnnotation = "operator.open-cluster-management.io/experimental-agent-configs"
+
+	RegistrationAgent = "registration"
+	WorkAgent         = "work"
+	SingletonAgent    = "agent"
+	AddonAgent        = "addon"
+
+	// AddonAgentLimitRangeName is the name of the LimitRange in the addon namespace setting the default resource
+	// requirements of the containers of the addon agents.
+	AddonAgentLimitRangeName = "addon-agent-defaults"
+
+	// the annotations of the namespace read by the PodNodeSelector and PodTolerationRestriction admission plugins
+	namespaceNodeSelectorAnnotation = "scheduler.alpha.kubernetes.io/node-selector"
+	namespaceTolerationsAnnotation  = "scheduler.alpha.kubernetes.io/defaultTolerations"
+)
+
+// AgentConfig is the configuration of the deployment of an agent.
+type AgentConfig struct {
+	ResourceRequirements *corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+	NodeSelector         map[string]string            `json:"nodeSelector,omitempty"`
+	Tolerations          []corev1.Toleration          `json:"tolerations,omitempty"`
+	PriorityClassName    string                       `json:"priorityClassName,omitempty"`
+	// LeaderElection tunes the leader election of the agent, it is ignored if the klusterlet runs a single replica
+	// of the agent, which always disables the leader election.
+	LeaderElection *LeaderElectionConfig `json:"leaderElection,omitempty"`
+}
+
+// AgentConfigs returns the configurations of the agents set on the klusterlet.
+func AgentConfigs(klusterlet *operatorapiv1.Klusterlet) (map[string]AgentConfig, error) {
+	value, ok := klusterlet.GetAnnotations()[AgentConfigsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	configs := map[string]AgentConfig{}
+	if err := json.Unmarshal([]byte(value), &configs); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", AgentConfigsAnnotation, err)
+	}
+	for name := range configs {
+		if err := configs[name].LeaderElection.validate(); err != nil {
+			return nil, fmt.Errorf("invalid value of annotation %s: %v", AgentConfigsAnnotation, err)
+		}
+		switch name {
+		case RegistrationAgent, WorkAgent, SingletonAgent:
+		case AddonAgent:
+			if configs[name].LeaderElection != nil {
+				return nil, fmt.Errorf("invalid value of annotation %s: leaderElection is not supported for the addon agents",
+					AgentConfigsAnnotation)
+			}
+			// there is no default priority class per namespace, it is set in the AddOnDeploymentConfig on the hub
+			if len(configs[name].PriorityClassName) > 0 {
+				return nil, fmt.Errorf("invalid value of annotation %s: priorityClassName is not supported for the addon agents",
+					AgentConfigsAnnotation)
+			}
+		default:
+			return nil, fmt.Errorf("invalid value of annotation %s: unknown agent %q", AgentConfigsAnnotation, name)
+		}
+	}
+	return configs, nil
+}
+
+// AgentNodePlacement returns the node placement of the agent, the node selector and tolerations of the agent
+// config override the ones of the klusterlet.
+func AgentNodePlacement(nodePlacement operatorapiv1.NodePlacement, config AgentConfig) operatorapiv1.NodePlacement {
+	if config.NodeSelector != nil {
+		nodePlacement.NodeSelector = config.NodeSelector
+	}
+	if config.Tolerations != nil {
+		nodePlacement.Tolerations = config.Tolerations
+	}
+	return nodePlacement
+}
+
+// AgentResourceRequirements returns the resource requirements of the agent config in yaml, it returns nil if the
+// resource requirements are not set.
+func AgentResourceRequirements(config AgentConfig) ([]byte, error) {
+	if config.ResourceRequirements == nil {
+		return nil, nil
+	}
+	return yaml.Marshal(config.ResourceRequirements)
+}
+
+// AddonNamespaceAnnotations returns the annotations of the addon namespace to set the default node selector and
+// tolerations of the addon agents, they take effect if the PodNodeSelector and PodTolerationRestriction admission
+// plugins are enabled. The annotations not configured are returned with the "-" suffix, so they are removed from the
+// namespace.
+func AddonNamespaceAnnotations(config AgentConfig) (map[string]string, error) {
+	annotations := map[string]string{
+		namespaceNodeSelectorAnnotation + "-": "",
+		namespaceTolerationsAnnotation + "-":  "",
+	}
+	if len(config.NodeSelector) > 0 {
+		delete(annotations, namespaceNodeSelectorAnnotation+"-")
+		annotations[namespaceNodeSelectorAnnotation] = labels.Set(config.NodeSelector).String()
+	}
+	if len(config.Tolerations) > 0 {
+		tolerations, err := json.Marshal(config.Tolerations)
+		if err != nil {
+			return nil, err
+		}
+		delete(annotations, namespaceTolerationsAnnotation+"-")
+		annotations[namespaceTolerationsAnnotation] = string(tolerations)
+	}
+	return annotations, nil
+}
+
+// AddonLimitRange returns the LimitRange of the addon namespace setting the default resource requirements of the
+// containers of the addon agents, it returns nil if the resource requirements are not set.
+func AddonLimitRange(namespace string, config AgentConfig) *corev1.LimitRange {
+	if config.ResourceRequirements == nil {
+		return nil
+	}
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AddonAgentLimitRangeName,
+			Namespace: namespace,
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type:           corev1.LimitTypeContainer,
+					Default:        config.ResourceRequirements.Limits,
+					DefaultRequest: config.ResourceRequirements.Requests,
+				},
+			},
+		},
+	}
+}
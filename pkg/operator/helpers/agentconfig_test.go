@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestAgentConfigs(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]AgentConfig
+		expectErr   bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "valid configs",
+			annotations: map[string]string{
+				AgentConfigsAnnotation: `{"work": {"nodeSelector": {"role": "work"}, "priorityClassName": "high"}, "registration": {}}`,
+			},
+			expected: map[string]AgentConfig{
+				WorkAgent:         {NodeSelector: map[string]string{"role": "work"}, PriorityClassName: "high"},
+				RegistrationAgent: {},
+			},
+		},
+		{
+			name:        "invalid json",
+			annotations: map[string]string{AgentConfigsAnnotation: `{"work":`},
+			expectErr:   true,
+		},
+		{
+			name:        "unknown agent",
+			annotations: map[string]string{AgentConfigsAnnotation: `{"hub": {}}`},
+			expectErr:   true,
+		},
+		{
+			name:        "addon agents",
+			annotations: map[string]string{AgentConfigsAnnotation: `{"addon": {"nodeSelector": {"role": "addon"}}}`},
+			expected: map[string]AgentConfig{
+				AddonAgent: {NodeSelector: map[string]string{"role": "addon"}},
+			},
+		},
+		{
+			name:        "leader election",
+			annotations: map[string]string{AgentConfigsAnnotation: `{"work": {"leaderElection": {"leaseDuration": "60s"}}}`},
+			expected: map[string]AgentConfig{
+				WorkAgent: {LeaderElection: &LeaderElectionConfig{LeaseDuration: &metav1.Duration{Duration: 60 * time.Second}}},
+			},
+		},
+		{
+			name: "invalid leader election",
+			annotations: map[string]string{
+				AgentConfigsAnnotation: `{"work": {"leaderElection": {"leaseDuration": "10s", "renewDeadline": "20s"}}}`,
+			},
+			expectErr: true,
+		},
+		{
+			name:        "leader election of addon agents",
+			annotations: map[string]string{AgentConfigsAnnotation: `{"addon": {"leaderElection": {"disable": true}}}`},
+			expectErr:   true,
+		},
+		{
+			name:        "priority class of addon agents",
+			annotations: map[string]string{AgentConfigsAnnotation: `{"addon": {"priorityClassName": "high"}}`},
+			expectErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := &operatorapiv1.Klusterlet{
+				ObjectMeta: metav1.ObjectMeta{Name: "klusterlet", Annotations: c.annotations},
+			}
+			configs, err := AgentConfigs(klusterlet)
+			if c.expectErr != (err != nil) {
+				t.Fatalf("expect error %v, but got %v", c.expectErr, err)
+			}
+			if !equality.Semantic.DeepEqual(configs, c.expected) {
+				t.Errorf("expect configs %v, but got %v", c.expected, configs)
+			}
+		})
+	}
+}
+
+func TestAgentNodePlacement(t *testing.T) {
+	nodePlacement := operatorapiv1.NodePlacement{
+		NodeSelector: map[string]string{"role": "infra"},
+		Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}},
+	}
+
+	actual := AgentNodePlacement(nodePlacement, AgentConfig{})
+	if !equality.Semantic.DeepEqual(actual, nodePlacement) {
+		t.Errorf("expect node placement %v, but got %v", nodePlacement, actual)
+	}
+
+	config := AgentConfig{NodeSelector: map[string]string{"role": "work"}}
+	actual = AgentNodePlacement(nodePlacement, config)
+	expected := operatorapiv1.NodePlacement{
+		NodeSelector: config.NodeSelector,
+		Tolerations:  nodePlacement.Tolerations,
+	}
+	if !equality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expect node placement %v, but got %v", expected, actual)
+	}
+}
+
+func TestAddonNamespaceDefaults(t *testing.T) {
+	annotations, err := AddonNamespaceAnnotations(AgentConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{namespaceNodeSelectorAnnotation + "-": "", namespaceTolerationsAnnotation + "-": ""}
+	if !equality.Semantic.DeepEqual(annotations, expected) {
+		t.Errorf("expected annotations %v, but got %v", expected, annotations)
+	}
+	if limitRange := AddonLimitRange("addon-ns", AgentConfig{}); limitRange != nil {
+		t.Errorf("expected no limit range, but got %v", limitRange)
+	}
+
+	config := AgentConfig{
+		NodeSelector: map[string]string{"role": "addon"},
+		Tolerations:  []corev1.Toleration{{Key: "addon", Operator: corev1.TolerationOpExists}},
+		ResourceRequirements: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+		},
+	}
+	annotations, err = AddonNamespaceAnnotations(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]string{
+		namespaceNodeSelectorAnnotation: "role=addon",
+		namespaceTolerationsAnnotation:  `[{"key":"addon","operator":"Exists"}]`,
+	}
+	if !equality.Semantic.DeepEqual(annotations, expected) {
+		t.Errorf("expected annotations %v, but got %v", expected, annotations)
+	}
+
+	limitRange := AddonLimitRange("addon-ns", config)
+	if limitRange == nil || limitRange.Namespace != "addon-ns" || len(limitRange.Spec.Limits) != 1 ||
+		!equality.Semantic.DeepEqual(limitRange.Spec.Limits[0].DefaultRequest, config.ResourceRequirements.Requests) {
+		t.Errorf("unexpected limit range %v", limitRange)
+	}
+}
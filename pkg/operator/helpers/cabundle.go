@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// HubCABundleConfigMapAnnotation is the annotation on the cluster manager to set the name of the configmap in the
+// namespace of the cluster manager with the current and the next CA bundles of the hub, e.g. "hub-ca-bundle". The
+// registration controller distributes the CA bundles to all the cluster namespaces.
+const HubCABundleConfigMapAnnotation = "operator.open-cluster-management.io/experimental-hub-ca-bundle-configmap"
+
+// HubCABundleDistributionAnnotation is the annotation on the klusterlet to make the agents trust the CA bundles of
+// the hub distributed by the hub once it is "true".
+const HubCABundleDistributionAnnotation = "operator.open-cluster-management.io/experimental-hub-ca-bundle-distribution"
+
+// GetHubCABundleConfigMap returns the name of the configmap with the CA bundles of the hub set on the object, or an
+// empty string if the annotation is not set.
+func GetHubCABundleConfigMap(obj metav1.Object) (string, error) {
+	value, ok := obj.GetAnnotations()[HubCABundleConfigMapAnnotation]
+	if !ok {
+		return "", nil
+	}
+	if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+		return "", fmt.Errorf("invalid value of annotation %s: %s", HubCABundleConfigMapAnnotation, strings.Join(errs, ", "))
+	}
+	return value, nil
+}
+
+// HubCABundleDistributionEnabled returns true if the agents trust the CA bundles of the hub distributed by the hub.
+func HubCABundleDistributionEnabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[HubCABundleDistributionAnnotation] == "true"
+}
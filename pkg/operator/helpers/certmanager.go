@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CertManagerIssuerAnnotation is the annotation on the cluster manager to issue the serving certificates of the
+// registration and work webhooks with cert-manager instead of the built-in rotation. The value is a json object of
+// CertManagerIssuer, e.g. {"name": "ocm-ca-issuer", "kind": "ClusterIssuer"}.
+// The issued secrets must contain the CA of the certificates in the ca.crt key, which is injected into the webhook
+// configurations on the hub cluster.
+const CertManagerIssuerAnnotation = "operator.open-cluster-management.io/experimental-cert-manager-issuer"
+
+const (
+	// CertManagerIssuerKind and CertManagerClusterIssuerKind are the kinds of the cert-manager issuers.
+	CertManagerIssuerKind        = "Issuer"
+	CertManagerClusterIssuerKind = "ClusterIssuer"
+	// CertManagerGroup is the default group of the issuers.
+	CertManagerGroup = "cert-manager.io"
+	// CertManagerCAKey is the key of the CA in the secrets issued by cert-manager.
+	CertManagerCAKey = "ca.crt"
+)
+
+// CertificateGVR is the resource of the cert-manager certificates.
+var CertificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+// CertManagerIssuer is the reference of the cert-manager issuer of the serving certificates.
+type CertManagerIssuer struct {
+	// Name is the name of the issuer, an Issuer must be in the namespace of the cluster manager.
+	Name string `json:"name"`
+	// Kind is the kind of the issuer, Issuer or ClusterIssuer, defaults to Issuer.
+	Kind string `json:"kind,omitempty"`
+	// Group is the group of the issuer, defaults to cert-manager.io for the external issuers.
+	Group string `json:"group,omitempty"`
+}
+
+// GetCertManagerIssuer returns the cert-manager issuer on the object, or nil if the annotation is not set.
+func GetCertManagerIssuer(obj metav1.Object) (*CertManagerIssuer, error) {
+	value, ok := obj.GetAnnotations()[CertManagerIssuerAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	issuer := &CertManagerIssuer{}
+	if err := json.Unmarshal([]byte(value), issuer); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", CertManagerIssuerAnnotation, err)
+	}
+	if len(issuer.Name) == 0 {
+		return nil, fmt.Errorf("invalid value of annotation %s: the name of the issuer is required", CertManagerIssuerAnnotation)
+	}
+	if len(issuer.Kind) == 0 {
+		issuer.Kind = CertManagerIssuerKind
+	}
+	if len(issuer.Group) == 0 {
+		issuer.Group = CertManagerGroup
+	}
+	if issuer.Group == CertManagerGroup && issuer.Kind != CertManagerIssuerKind && issuer.Kind != CertManagerClusterIssuerKind {
+		return nil, fmt.Errorf("invalid value of annotation %s: unknown kind %q of the issuer", CertManagerIssuerAnnotation, issuer.Kind)
+	}
+	return issuer, nil
+}
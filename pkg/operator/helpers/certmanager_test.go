@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestGetCertManagerIssuer(t *testing.T) {
+	cases := []struct {
+		name         string
+		value        *string
+		expectIssuer *CertManagerIssuer
+		expectErr    bool
+	}{
+		{name: "no annotation"},
+		{
+			name:         "default kind and group",
+			value:        strPtr(`{"name": "ocm-issuer"}`),
+			expectIssuer: &CertManagerIssuer{Name: "ocm-issuer", Kind: CertManagerIssuerKind, Group: CertManagerGroup},
+		},
+		{
+			name:         "cluster issuer",
+			value:        strPtr(`{"name": "ocm-issuer", "kind": "ClusterIssuer"}`),
+			expectIssuer: &CertManagerIssuer{Name: "ocm-issuer", Kind: CertManagerClusterIssuerKind, Group: CertManagerGroup},
+		},
+		{
+			name:         "external issuer",
+			value:        strPtr(`{"name": "ocm-issuer", "kind": "AWSPCAClusterIssuer", "group": "awspca.cert-manager.io"}`),
+			expectIssuer: &CertManagerIssuer{Name: "ocm-issuer", Kind: "AWSPCAClusterIssuer", Group: "awspca.cert-manager.io"},
+		},
+		{name: "invalid json", value: strPtr(`{"name":`), expectErr: true},
+		{name: "no name", value: strPtr(`{"kind": "Issuer"}`), expectErr: true},
+		{name: "unknown kind", value: strPtr(`{"name": "ocm-issuer", "kind": "Unknown"}`), expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterManager := &operatorapiv1.ClusterManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager"}}
+			if c.value != nil {
+				clusterManager.Annotations = map[string]string{CertManagerIssuerAnnotation: *c.value}
+			}
+			issuer, err := GetCertManagerIssuer(clusterManager)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectErr, err)
+			}
+			if !reflect.DeepEqual(c.expectIssuer, issuer) {
+				t.Errorf("expect issuer %v, but got %v", c.expectIssuer, issuer)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HubCircuitBreakerThresholdAnnotation is the annotation on the klusterlet to set the number of the consecutive
+// requests failed to reach the hub before the agents reject the requests to the hub until it is reachable again,
+// e.g. "10". The circuit breaker is disabled if it is "0".
+const HubCircuitBreakerThresholdAnnotation = "operator.open-cluster-management.io/experimental-hub-circuit-breaker-threshold"
+
+// GetHubCircuitBreakerThreshold returns the threshold of the circuit breaker set on the object, or an empty string
+// if the annotation is not set.
+func GetHubCircuitBreakerThreshold(obj metav1.Object) (string, error) {
+	value, ok := obj.GetAnnotations()[HubCircuitBreakerThresholdAnnotation]
+	if !ok {
+		return "", nil
+	}
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < 0 {
+		return "", fmt.Errorf("invalid value of annotation %s: %q is not a non-negative integer",
+			HubCircuitBreakerThresholdAnnotation, value)
+	}
+	return strconv.Itoa(threshold), nil
+}
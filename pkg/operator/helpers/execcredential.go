@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AddOnKubeconfigExecCredentialAnnotation is the annotation on the klusterlet to allow the addons to reference an
+// exec credential plugin in their hub kubeconfigs instead of the client certificate and key files. Once it is "true",
+// the registration agent references the plugin set in the annotation
+// addon.open-cluster-management.io/experimental-hub-kubeconfig-exec-command of a ManagedClusterAddOn, the command
+// must be shipped in the image of the addon agent.
+const AddOnKubeconfigExecCredentialAnnotation = "operator.open-cluster-management.io/experimental-addon-kubeconfig-exec-credential"
+
+// AddOnKubeconfigExecCredentialEnabled returns true if the addons are allowed to reference the exec credential plugins.
+func AddOnKubeconfigExecCredentialEnabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[AddOnKubeconfigExecCredentialAnnotation] == "true"
+}
@@ -0,0 +1,101 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/component-base/featuregate"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+const (
+	// FeatureGatesTypeEffective is the condition on the operator CRs reporting the feature gates the components
+	// run with.
+	FeatureGatesTypeEffective   = "EffectiveFeatureGates"
+	FeatureGatesReasonEffective = "FeatureGatesEffective"
+)
+
+// ComponentFeatureGates are the feature gates set for a component and the known feature gates of the component.
+type ComponentFeatureGates struct {
+	Component       string
+	FeatureGates    []operatorapiv1.FeatureGate
+	DefaultFeatures map[featuregate.Feature]featuregate.FeatureSpec
+}
+
+// ValidateFeatureGates validates the feature gates at the path against the known feature gates. A feature gate is
+// invalid if it is unknown, set more than once, or locked to its default value and set to the other value.
+func ValidateFeatureGates(path *field.Path, features []operatorapiv1.FeatureGate,
+	defaultFeatures map[featuregate.Feature]featuregate.FeatureSpec) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]bool{}
+	for i, feature := range features {
+		featurePath := path.Index(i)
+		if seen[feature.Feature] {
+			errs = append(errs, field.Duplicate(featurePath.Child("feature"), feature.Feature))
+			continue
+		}
+		seen[feature.Feature] = true
+
+		defaultFeature, ok := defaultFeatures[featuregate.Feature(feature.Feature)]
+		if !ok {
+			errs = append(errs, field.NotSupported(featurePath.Child("feature"), feature.Feature, knownFeatures(defaultFeatures)))
+			continue
+		}
+		if featureLocked(feature, defaultFeature) {
+			errs = append(errs, field.Forbidden(featurePath.Child("mode"),
+				fmt.Sprintf("feature gate %s is %s and locked to %v", feature.Feature, defaultFeature.PreRelease, defaultFeature.Default)))
+		}
+	}
+	return errs
+}
+
+// EffectiveFeatureGates returns all the known feature gates of the component with the values the component runs
+// with, in the format of Feature=true|false sorted by the feature name.
+func EffectiveFeatureGates(features []operatorapiv1.FeatureGate,
+	defaultFeatures map[featuregate.Feature]featuregate.FeatureSpec) []string {
+	var effective []string
+	for _, name := range knownFeatures(defaultFeatures) {
+		enabled := defaultFeatures[featuregate.Feature(name)].Default
+		for _, feature := range features {
+			if feature.Feature == name && !featureLocked(feature, defaultFeatures[featuregate.Feature(name)]) {
+				enabled = feature.Mode == operatorapiv1.FeatureGateModeTypeEnable
+			}
+		}
+		effective = append(effective, fmt.Sprintf("%s=%v", name, enabled))
+	}
+	return effective
+}
+
+// BuildEffectiveFeatureCondition returns the condition reporting the effective feature gates of the components.
+func BuildEffectiveFeatureCondition(components ...ComponentFeatureGates) metav1.Condition {
+	var messages []string
+	for _, component := range components {
+		messages = append(messages, fmt.Sprintf("%s: %s", component.Component,
+			strings.Join(EffectiveFeatureGates(component.FeatureGates, component.DefaultFeatures), ",")))
+	}
+	return metav1.Condition{
+		Type:    FeatureGatesTypeEffective,
+		Status:  metav1.ConditionTrue,
+		Reason:  FeatureGatesReasonEffective,
+		Message: strings.Join(messages, "; "),
+	}
+}
+
+// featureLocked returns if the feature gate is locked to its default value and set to the other value.
+func featureLocked(feature operatorapiv1.FeatureGate, defaultFeature featuregate.FeatureSpec) bool {
+	return defaultFeature.LockToDefault &&
+		(feature.Mode == operatorapiv1.FeatureGateModeTypeEnable) != defaultFeature.Default
+}
+
+func knownFeatures(defaultFeatures map[featuregate.Feature]featuregate.FeatureSpec) []string {
+	var names []string
+	for name := range defaultFeatures {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/component-base/featuregate"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+var testFeatures = map[featuregate.Feature]featuregate.FeatureSpec{
+	"Alpha":  {Default: false, PreRelease: featuregate.Alpha},
+	"Beta":   {Default: true, PreRelease: featuregate.Beta},
+	"Locked": {Default: true, PreRelease: featuregate.GA, LockToDefault: true},
+}
+
+func TestValidateFeatureGates(t *testing.T) {
+	cases := []struct {
+		name           string
+		features       []operatorapiv1.FeatureGate
+		expectedErrors int
+	}{
+		{name: "no feature gates"},
+		{
+			name: "valid",
+			features: []operatorapiv1.FeatureGate{
+				{Feature: "Alpha", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+				{Feature: "Locked", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+			},
+		},
+		{
+			name:           "unknown",
+			features:       []operatorapiv1.FeatureGate{{Feature: "Unknown", Mode: operatorapiv1.FeatureGateModeTypeEnable}},
+			expectedErrors: 1,
+		},
+		{
+			name:           "locked",
+			features:       []operatorapiv1.FeatureGate{{Feature: "Locked", Mode: operatorapiv1.FeatureGateModeTypeDisable}},
+			expectedErrors: 1,
+		},
+		{
+			name: "duplicated",
+			features: []operatorapiv1.FeatureGate{
+				{Feature: "Beta", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+				{Feature: "Beta", Mode: operatorapiv1.FeatureGateModeTypeDisable},
+			},
+			expectedErrors: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := ValidateFeatureGates(field.NewPath("featureGates"), c.features, testFeatures)
+			if len(errs) != c.expectedErrors {
+				t.Errorf("expect %d errors, but got %v", c.expectedErrors, errs)
+			}
+		})
+	}
+}
+
+func TestEffectiveFeatureGates(t *testing.T) {
+	effective := EffectiveFeatureGates([]operatorapiv1.FeatureGate{
+		{Feature: "Alpha", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+		{Feature: "Locked", Mode: operatorapiv1.FeatureGateModeTypeDisable},
+		{Feature: "Unknown", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+	}, testFeatures)
+	if actual := strings.Join(effective, ","); actual != "Alpha=true,Beta=true,Locked=true" {
+		t.Errorf("unexpected effective feature gates %s", actual)
+	}
+
+	condition := BuildEffectiveFeatureCondition(
+		ComponentFeatureGates{Component: "Registration", DefaultFeatures: testFeatures},
+		ComponentFeatureGates{Component: "Work", DefaultFeatures: map[featuregate.Feature]featuregate.FeatureSpec{}},
+	)
+	if condition.Message != "Registration: Alpha=false,Beta=true,Locked=true; Work: " {
+		t.Errorf("unexpected condition message %q", condition.Message)
+	}
+}
+
+func TestConvertLockedFeatureGate(t *testing.T) {
+	flags, msg := ConvertToFeatureGateFlags("Registration", []operatorapiv1.FeatureGate{
+		{Feature: "Locked", Mode: operatorapiv1.FeatureGateModeTypeDisable},
+	}, testFeatures)
+	if len(flags) != 0 || !strings.Contains(msg, "Locked") {
+		t.Errorf("expect the locked feature gate is invalid, but got flags %v and message %q", flags, msg)
+	}
+}
@@ -50,6 +50,10 @@ const (
 	FeatureGatesReasonAllValid        = "FeatureGatesAllValid"
 	FeatureGatesReasonInvalidExisting = "InvalidFeatureGatesExisting"
 
+	AnnotationsTypeValid      = "ValidAnnotations"
+	AnnotationsReasonAllValid = "AnnotationsAllValid"
+	AnnotationsReasonInvalid  = "InvalidAnnotations"
+
 	// DefaultAddonNamespace is the default namespace for agent addon
 	DefaultAddonNamespace = "open-cluster-management-agent-addon"
 
@@ -217,7 +221,8 @@ func ApplyDeployment(
 	generationStatuses []operatorapiv1.GenerationStatus,
 	nodePlacement operatorapiv1.NodePlacement,
 	manifests resourceapply.AssetFunc,
-	recorder events.Recorder, file string) (*appsv1.Deployment, operatorapiv1.GenerationStatus, error) {
+	recorder events.Recorder, file string,
+	mutators ...func(deployment *appsv1.Deployment)) (*appsv1.Deployment, operatorapiv1.GenerationStatus, error) {
 	deploymentBytes, err := manifests(file)
 	if err != nil {
 		return nil, operatorapiv1.GenerationStatus{}, err
@@ -235,6 +240,9 @@ func ApplyDeployment(
 
 	deployment.(*appsv1.Deployment).Spec.Template.Spec.NodeSelector = nodePlacement.NodeSelector
 	deployment.(*appsv1.Deployment).Spec.Template.Spec.Tolerations = nodePlacement.Tolerations
+	for _, mutate := range mutators {
+		mutate(deployment.(*appsv1.Deployment))
+	}
 
 	updatedDeployment, updated, err := resourceapply.ApplyDeployment(
 		ctx,
@@ -650,19 +658,27 @@ func ResourceRequirements(resourceRequirementAcquirer operatorapiv1.ResourceRequ
 
 // AgentPriorityClassName return the name of the PriorityClass that should be used for the klusterlet agents
 func AgentPriorityClassName(klusterlet *operatorapiv1.Klusterlet, kubeVersion *version.Version) string {
-	if kubeVersion == nil || klusterlet == nil {
+	if klusterlet == nil || !PriorityClassSupported(kubeVersion) {
 		return ""
 	}
 
+	return klusterlet.Spec.PriorityClassName
+}
+
+// PriorityClassSupported returns whether the cluster of the kube version supports PriorityClass/v1
+func PriorityClassSupported(kubeVersion *version.Version) bool {
+	if kubeVersion == nil {
+		return false
+	}
+
 	// priorityclass.scheduling.k8s.io/v1 is supported since v1.14.
 	if cnt, err := kubeVersion.Compare("v1.14.0"); err != nil {
 		klog.Warningf("Ignore PriorityClass because it's failed to check whether the cluster supports PriorityClass/v1 or not: %v", err)
-		return ""
+		return false
 	} else if cnt == -1 {
-		return ""
+		return false
 	}
-
-	return klusterlet.Spec.PriorityClassName
+	return true
 }
 
 // SyncSecret forked from:
@@ -764,13 +780,33 @@ func BuildFeatureCondition(invalidMsgs ...string) metav1.Condition {
 	}
 }
 
+// BuildAnnotationsCondition returns the condition reporting if the experimental annotations configuring the
+// klusterlet or the cluster manager are valid, err is the error of parsing them.
+func BuildAnnotationsCondition(err error) metav1.Condition {
+	if err == nil {
+		return metav1.Condition{
+			Type:    AnnotationsTypeValid,
+			Status:  metav1.ConditionTrue,
+			Reason:  AnnotationsReasonAllValid,
+			Message: "Annotations are all valid",
+		}
+	}
+
+	return metav1.Condition{
+		Type:    AnnotationsTypeValid,
+		Status:  metav1.ConditionFalse,
+		Reason:  AnnotationsReasonInvalid,
+		Message: fmt.Sprintf("The components are not applied until the annotations are fixed: %v", err),
+	}
+}
+
 func ConvertToFeatureGateFlags(component string, features []operatorapiv1.FeatureGate,
 	defaultFeatureGates map[featuregate.Feature]featuregate.FeatureSpec) ([]string, string) {
 	var flags, invalidFeatures []string
 
 	for _, feature := range features {
 		defaultFeature, ok := defaultFeatureGates[featuregate.Feature(feature.Feature)]
-		if !ok {
+		if !ok || featureLocked(feature, defaultFeature) {
 			invalidFeatures = append(invalidFeatures, feature.Feature)
 			continue
 		}
@@ -0,0 +1,220 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/sharding"
+)
+
+// HighAvailabilityAnnotation is the annotation on the cluster manager to deploy the hub components highly available.
+// The value is a json object of HighAvailabilityConfig, e.g.
+// {"replicas": 3, "podDisruptionBudget": {"minAvailable": 2}, "antiAffinity": "Required"}.
+const HighAvailabilityAnnotation = "operator.open-cluster-management.io/experimental-high-availability"
+
+// HubComponentLabelKey is the label on the pod disruption budgets of the hub components, so the operator is able to
+// find and delete them once they are not required.
+const HubComponentLabelKey = "operator.open-cluster-management.io/hub-component"
+
+type AntiAffinityType string
+
+const (
+	// AntiAffinityPreferred spreads the pods of a component across the zones and the nodes when possible, it is
+	// the default anti-affinity of the hub components.
+	AntiAffinityPreferred AntiAffinityType = "Preferred"
+	// AntiAffinityRequired requires the pods of a component to be scheduled on different nodes.
+	AntiAffinityRequired AntiAffinityType = "Required"
+)
+
+// HighAvailabilityConfig is the configuration of the deployments of the hub components.
+type HighAvailabilityConfig struct {
+	// Replicas overrides the number of replicas of each hub component.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// PodDisruptionBudget creates a pod disruption budget for each hub component if it is set.
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+	// TopologySpreadConstraints are added to the pods of each hub component. The label selector defaults to the
+	// labels of the pods of the component if it is not set.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// AntiAffinity is Preferred or Required, the default is Preferred.
+	AntiAffinity AntiAffinityType `json:"antiAffinity,omitempty"`
+	// LeaderElection tunes the leader election of the hub controllers.
+	LeaderElection *LeaderElectionConfig `json:"leaderElection,omitempty"`
+	// ShardBy runs the registration and the manifestworkreplicaset controllers in shards with the strategy,
+	// 'cluster' or 'clusterset'. The leader election of the sharded controllers is disabled when it is set.
+	ShardBy string `json:"shardBy,omitempty"`
+}
+
+// LeaderElectionConfig overrides the leader election flags of the hub controllers or the klusterlet agents, the
+// defaults of the components are used for the fields not set.
+type LeaderElectionConfig struct {
+	// Disable disables the leader election, it is only expected for single replica deployments.
+	Disable       bool             `json:"disable,omitempty"`
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+	RetryPeriod   *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// PodDisruptionBudgetConfig sets one of minAvailable and maxUnavailable of the pod disruption budgets, the
+// minAvailable defaults to 1 if neither is set.
+type PodDisruptionBudgetConfig struct {
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// HighAvailability returns the high availability configuration of the cluster manager, it returns an empty
+// configuration if the annotation is not set.
+func HighAvailability(cm *operatorapiv1.ClusterManager) (*HighAvailabilityConfig, error) {
+	config := &HighAvailabilityConfig{}
+	value, ok := cm.GetAnnotations()[HighAvailabilityAnnotation]
+	if !ok {
+		return config, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", HighAvailabilityAnnotation, err)
+	}
+	if config.Replicas != nil && *config.Replicas <= 0 {
+		return nil, fmt.Errorf("invalid value of annotation %s: replicas must be positive", HighAvailabilityAnnotation)
+	}
+	if pdb := config.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: only one of minAvailable and maxUnavailable can be set",
+			HighAvailabilityAnnotation)
+	}
+	switch config.AntiAffinity {
+	case "", AntiAffinityPreferred, AntiAffinityRequired:
+	default:
+		return nil, fmt.Errorf("invalid value of annotation %s: unknown antiAffinity %q", HighAvailabilityAnnotation, config.AntiAffinity)
+	}
+	if err := config.LeaderElection.validate(); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", HighAvailabilityAnnotation, err)
+	}
+	switch config.ShardBy {
+	case "", sharding.ShardByCluster, sharding.ShardByClusterSet:
+	default:
+		return nil, fmt.Errorf("invalid value of annotation %s: unknown shardBy %q", HighAvailabilityAnnotation, config.ShardBy)
+	}
+	return config, nil
+}
+
+func (c *LeaderElectionConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, d := range []*metav1.Duration{c.LeaseDuration, c.RenewDeadline, c.RetryPeriod} {
+		if d != nil && d.Duration <= 0 {
+			return fmt.Errorf("leader election durations must be positive")
+		}
+	}
+	if c.LeaseDuration != nil && c.RenewDeadline != nil && c.LeaseDuration.Duration <= c.RenewDeadline.Duration {
+		return fmt.Errorf("leader election leaseDuration must be greater than renewDeadline")
+	}
+	if c.RenewDeadline != nil && c.RetryPeriod != nil && c.RenewDeadline.Duration <= c.RetryPeriod.Duration {
+		return fmt.Errorf("leader election renewDeadline must be greater than retryPeriod")
+	}
+	return nil
+}
+
+// LeaderElectionArgs returns the leader election flags of the hub controllers.
+func (c *HighAvailabilityConfig) LeaderElectionArgs() []string {
+	return c.LeaderElection.Args()
+}
+
+// ShardedControllerArgs returns the flags of the hub controllers supporting sharding. The shards are coordinated
+// by their own leases, so the leader election is disabled if the controllers are sharded.
+func (c *HighAvailabilityConfig) ShardedControllerArgs() []string {
+	if len(c.ShardBy) == 0 {
+		return c.LeaderElectionArgs()
+	}
+	return []string{fmt.Sprintf("--shard-by=%s", c.ShardBy), "--disable-leader-election"}
+}
+
+// Args returns the leader election flags of the config, it returns nil if the config is nil.
+func (c *LeaderElectionConfig) Args() []string {
+	if c == nil {
+		return nil
+	}
+	if c.Disable {
+		return []string{"--disable-leader-election"}
+	}
+
+	var args []string
+	if c.LeaseDuration != nil {
+		args = append(args, fmt.Sprintf("--leader-election-lease-duration=%s", c.LeaseDuration.Duration))
+	}
+	if c.RenewDeadline != nil {
+		args = append(args, fmt.Sprintf("--leader-election-renew-deadline=%s", c.RenewDeadline.Duration))
+	}
+	if c.RetryPeriod != nil {
+		args = append(args, fmt.Sprintf("--leader-election-retry-period=%s", c.RetryPeriod.Duration))
+	}
+	return args
+}
+
+// ApplyToDeployment adds the topology spread constraints and the anti-affinity to the pods of the deployment.
+func (c *HighAvailabilityConfig) ApplyToDeployment(deployment *appsv1.Deployment) {
+	podSpec := &deployment.Spec.Template.Spec
+	podLabels := &metav1.LabelSelector{MatchLabels: deployment.Spec.Template.Labels}
+
+	for _, constraint := range c.TopologySpreadConstraints {
+		constraint = *constraint.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = podLabels
+		}
+		podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, constraint)
+	}
+
+	if c.AntiAffinity == AntiAffinityRequired {
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = &corev1.Affinity{}
+		}
+		if podSpec.Affinity.PodAntiAffinity == nil {
+			podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{
+			{
+				TopologyKey:   corev1.LabelHostname,
+				LabelSelector: podLabels,
+			},
+		}
+	}
+}
+
+// PodDisruptionBudgetFor returns the pod disruption budget of the deployment, it returns nil if the pod disruption
+// budget is not required.
+func (c *HighAvailabilityConfig) PodDisruptionBudgetFor(deployment *appsv1.Deployment) *policyv1.PodDisruptionBudget {
+	if c.PodDisruptionBudget == nil {
+		return nil
+	}
+
+	labels := map[string]string{}
+	for k, v := range deployment.Labels {
+		labels[k] = v
+	}
+	labels[HubComponentLabelKey] = "true"
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:       deployment.Spec.Selector,
+			MinAvailable:   c.PodDisruptionBudget.MinAvailable,
+			MaxUnavailable: c.PodDisruptionBudget.MaxUnavailable,
+		},
+	}
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		minAvailable := intstr.FromInt32(1)
+		pdb.Spec.MinAvailable = &minAvailable
+	}
+	return pdb
+}
@@ -0,0 +1,135 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestHighAvailability(t *testing.T) {
+	cases := []struct {
+		name      string
+		value     *string
+		expectErr bool
+	}{
+		{name: "no annotation"},
+		{name: "valid", value: strPtr(`{"replicas": 3, "podDisruptionBudget": {"maxUnavailable": "50%"}, "antiAffinity": "Preferred"}`)},
+		{name: "invalid json", value: strPtr(`{"replicas":`), expectErr: true},
+		{name: "invalid replicas", value: strPtr(`{"replicas": -1}`), expectErr: true},
+		{
+			name:      "both minAvailable and maxUnavailable",
+			value:     strPtr(`{"podDisruptionBudget": {"minAvailable": 1, "maxUnavailable": 1}}`),
+			expectErr: true,
+		},
+		{name: "unknown anti-affinity", value: strPtr(`{"antiAffinity": "Always"}`), expectErr: true},
+		{name: "valid leader election", value: strPtr(`{"leaderElection": {"leaseDuration": "30s", "renewDeadline": "20s"}}`)},
+		{
+			name:      "renew deadline longer than lease duration",
+			value:     strPtr(`{"leaderElection": {"leaseDuration": "30s", "renewDeadline": "40s"}}`),
+			expectErr: true,
+		},
+		{name: "negative retry period", value: strPtr(`{"leaderElection": {"retryPeriod": "-1s"}}`), expectErr: true},
+		{name: "valid shard strategy", value: strPtr(`{"replicas": 3, "shardBy": "clusterset"}`)},
+		{name: "unknown shard strategy", value: strPtr(`{"shardBy": "namespace"}`), expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cm := &operatorapiv1.ClusterManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager"}}
+			if c.value != nil {
+				cm.Annotations = map[string]string{HighAvailabilityAnnotation: *c.value}
+			}
+			_, err := HighAvailability(cm)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestPodDisruptionBudgetFor(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "placement", Namespace: "open-cluster-management-hub"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "placement"}},
+		},
+	}
+
+	if pdb := (&HighAvailabilityConfig{}).PodDisruptionBudgetFor(deployment); pdb != nil {
+		t.Errorf("expect no pdb, but got %v", pdb)
+	}
+
+	pdb := (&HighAvailabilityConfig{PodDisruptionBudget: &PodDisruptionBudgetConfig{}}).PodDisruptionBudgetFor(deployment)
+	if pdb.Name != deployment.Name || pdb.Namespace != deployment.Namespace || pdb.Spec.Selector != deployment.Spec.Selector {
+		t.Errorf("unexpected pdb %v", pdb)
+	}
+	if pdb.Labels[HubComponentLabelKey] != "true" {
+		t.Errorf("expect the hub component label, but got %v", pdb.Labels)
+	}
+	if pdb.Spec.MinAvailable.IntValue() != 1 || pdb.Spec.MaxUnavailable != nil {
+		t.Errorf("expect minAvailable 1, but got %v", pdb.Spec)
+	}
+
+	maxUnavailable := intstr.FromString("50%")
+	pdb = (&HighAvailabilityConfig{PodDisruptionBudget: &PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable}}).
+		PodDisruptionBudgetFor(deployment)
+	if pdb.Spec.MinAvailable != nil || pdb.Spec.MaxUnavailable.String() != "50%" {
+		t.Errorf("expect maxUnavailable 50%%, but got %v", pdb.Spec)
+	}
+}
+
+func TestLeaderElectionArgs(t *testing.T) {
+	cases := []struct {
+		name         string
+		config       *LeaderElectionConfig
+		expectedArgs []string
+	}{
+		{name: "not set"},
+		{
+			name:         "disabled",
+			config:       &LeaderElectionConfig{Disable: true, LeaseDuration: &metav1.Duration{Duration: time.Minute}},
+			expectedArgs: []string{"--disable-leader-election"},
+		},
+		{
+			name: "tuned",
+			config: &LeaderElectionConfig{
+				LeaseDuration: &metav1.Duration{Duration: 30 * time.Second},
+				RetryPeriod:   &metav1.Duration{Duration: 5 * time.Second},
+			},
+			expectedArgs: []string{"--leader-election-lease-duration=30s", "--leader-election-retry-period=5s"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args := (&HighAvailabilityConfig{LeaderElection: c.config}).LeaderElectionArgs()
+			if !reflect.DeepEqual(args, c.expectedArgs) {
+				t.Errorf("expect args %v, but got %v", c.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestShardedControllerArgs(t *testing.T) {
+	leaderElection := &LeaderElectionConfig{LeaseDuration: &metav1.Duration{Duration: 30 * time.Second}}
+
+	args := (&HighAvailabilityConfig{LeaderElection: leaderElection}).ShardedControllerArgs()
+	if !reflect.DeepEqual(args, []string{"--leader-election-lease-duration=30s"}) {
+		t.Errorf("expect the leader election args if not sharded, but got %v", args)
+	}
+
+	args = (&HighAvailabilityConfig{LeaderElection: leaderElection, ShardBy: "cluster"}).ShardedControllerArgs()
+	if !reflect.DeepEqual(args, []string{"--shard-by=cluster", "--disable-leader-election"}) {
+		t.Errorf("expect the leader election disabled if sharded, but got %v", args)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
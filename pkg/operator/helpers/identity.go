@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AgentIdentityRotationAnnotation is the annotation on the klusterlet to rotate the identity of the agent, e.g. after
+// the managed cluster is cloned or the credentials are compromised. The value is an arbitrary generation, e.g. a
+// timestamp. Once it is changed, the registration agent removes the hub kubeconfig secret, regenerates the agent
+// name and bootstraps again. The agent name is not regenerated in the Singleton modes since it is the ID of the
+// klusterlet, only the credentials are.
+const AgentIdentityRotationAnnotation = "operator.open-cluster-management.io/experimental-agent-identity-rotation"
+
+// GetAgentIdentityGeneration returns the generation of the agent identity on the object, or an empty string if the
+// annotation is not set.
+func GetAgentIdentityGeneration(obj metav1.Object) (string, error) {
+	value := obj.GetAnnotations()[AgentIdentityRotationAnnotation]
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return "", fmt.Errorf("invalid value of annotation %s: %s", AgentIdentityRotationAnnotation,
+			strings.Join(errs, ", "))
+	}
+	return value, nil
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetAgentIdentityGeneration(t *testing.T) {
+	cases := []struct {
+		name               string
+		annotations        map[string]string
+		expectedGeneration string
+		expectedErr        bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:               "valid generation",
+			annotations:        map[string]string{AgentIdentityRotationAnnotation: "20240701T120000"},
+			expectedGeneration: "20240701T120000",
+		},
+		{
+			name:        "invalid generation",
+			annotations: map[string]string{AgentIdentityRotationAnnotation: "\" --agent-id=other"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			generation, err := GetAgentIdentityGeneration(&metav1.ObjectMeta{Annotations: c.annotations})
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if generation != c.expectedGeneration {
+				t.Errorf("expected generation %q, but got %q", c.expectedGeneration, generation)
+			}
+		})
+	}
+}
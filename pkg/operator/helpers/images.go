@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+// ImageConfigAnnotation is the annotation on the klusterlet or the cluster manager to pull the images of the
+// components from mirrored registries, e.g. for air-gapped installs. The value is a json object of ImageConfig, e.g.
+// {"registries": [{"source": "quay.io/open-cluster-management", "mirror": "registry.local/ocm"}],
+// "digests": {"registration": "sha256:..."}}.
+// The image of each component is still set by the image pull spec in the spec, then the registries are replaced by
+// the mirrors, and at last the image is pinned to the digest of the component if it is set.
+const ImageConfigAnnotation = "operator.open-cluster-management.io/experimental-image-config"
+
+// The components whose images can be pinned to digests.
+const (
+	RegistrationImageComponent = "registration"
+	WorkImageComponent         = "work"
+	PlacementImageComponent    = "placement"
+	AddOnManagerImageComponent = "addon-manager"
+	SingletonImageComponent    = "agent"
+)
+
+var digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ImageConfig is the configuration of the images of the components.
+type ImageConfig struct {
+	// Registries are the mirrors of the source registries, the same as the registries of AddOnDeploymentConfig.
+	Registries []addonapiv1alpha1.ImageMirror `json:"registries,omitempty"`
+	// Digests are the digests the images are pinned to, keyed by the component name.
+	Digests map[string]string `json:"digests,omitempty"`
+}
+
+// GetImageConfig returns the image config on the object, the components are the ones whose images can be pinned
+// to digests. It returns an empty config if the annotation is not set.
+func GetImageConfig(obj metav1.Object, components ...string) (*ImageConfig, error) {
+	config := &ImageConfig{}
+	value, ok := obj.GetAnnotations()[ImageConfigAnnotation]
+	if !ok {
+		return config, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", ImageConfigAnnotation, err)
+	}
+	for component, digest := range config.Digests {
+		if !sets.New(components...).Has(component) {
+			return nil, fmt.Errorf("invalid value of annotation %s: unknown component %q", ImageConfigAnnotation, component)
+		}
+		if !digestRegexp.MatchString(digest) {
+			return nil, fmt.Errorf("invalid value of annotation %s: invalid digest %q of component %s",
+				ImageConfigAnnotation, digest, component)
+		}
+	}
+	return config, nil
+}
+
+// Image returns the image of the component pulled from the mirrored registry and pinned to the digest.
+func (c *ImageConfig) Image(component, image string) string {
+	if len(image) == 0 {
+		return image
+	}
+
+	image = addonfactory.OverrideImage(c.Registries, image)
+	digest, ok := c.Digests[component]
+	if !ok {
+		return image
+	}
+	return fmt.Sprintf("%s@%s", imageRepository(image), digest)
+}
+
+// imageRepository returns the image without the tag and the digest.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// the tag is after the last colon in the last path segment, a colon before it separates the registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
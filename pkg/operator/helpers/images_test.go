@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestGetImageConfig(t *testing.T) {
+	cases := []struct {
+		name      string
+		value     string
+		expectErr bool
+	}{
+		{
+			name:  "valid",
+			value: `{"registries": [{"source": "quay.io/ocm", "mirror": "registry.local/ocm"}], "digests": {"work": "` + testDigest + `"}}`,
+		},
+		{name: "invalid json", value: `{"registries":`, expectErr: true},
+		{name: "unknown component", value: `{"digests": {"placement": "` + testDigest + `"}}`, expectErr: true},
+		{name: "invalid digest", value: `{"digests": {"work": "sha256:abc"}}`, expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := &operatorapiv1.Klusterlet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "klusterlet",
+					Annotations: map[string]string{ImageConfigAnnotation: c.value},
+				},
+			}
+			_, err := GetImageConfig(klusterlet, RegistrationImageComponent, WorkImageComponent)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	config := &ImageConfig{
+		Registries: []addonapiv1alpha1.ImageMirror{
+			{Source: "quay.io/open-cluster-management", Mirror: "registry.local:5000/ocm"},
+		},
+		Digests: map[string]string{WorkImageComponent: testDigest},
+	}
+
+	cases := []struct {
+		component string
+		image     string
+		expected  string
+	}{
+		{
+			component: RegistrationImageComponent,
+			image:     "quay.io/open-cluster-management/registration:v0.14.0",
+			expected:  "registry.local:5000/ocm/registration:v0.14.0",
+		},
+		{
+			component: RegistrationImageComponent,
+			image:     "docker.io/registration:latest",
+			expected:  "docker.io/registration:latest",
+		},
+		{
+			component: WorkImageComponent,
+			image:     "quay.io/open-cluster-management/work:v0.14.0",
+			expected:  "registry.local:5000/ocm/work@" + testDigest,
+		},
+		{
+			component: WorkImageComponent,
+			image:     "localhost:5000/work@sha256:fedcba",
+			expected:  "localhost:5000/work@" + testDigest,
+		},
+		{
+			component: WorkImageComponent,
+			image:     "",
+			expected:  "",
+		},
+	}
+
+	for _, c := range cases {
+		if actual := config.Image(c.component, c.image); actual != c.expected {
+			t.Errorf("expect image %s of %s, but got %s", c.expected, c.image, actual)
+		}
+	}
+}
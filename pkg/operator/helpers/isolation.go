@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+// HostedIsolationAnnotation is the annotation on the klusterlet in the Hosted mode to isolate its agents from the
+// agents of the other klusterlets on the management cluster. The value is a json object of HostedIsolation, e.g.
+// {"resourceQuota": {"requests.cpu": "1", "requests.memory": "1Gi", "pods": "10"}, "priority": 100000}.
+const HostedIsolationAnnotation = "operator.open-cluster-management.io/experimental-hosted-isolation"
+
+// AgentResourceQuota is the resource quota of the agent namespace on the management cluster.
+const AgentResourceQuota = "klusterlet-agent-quota"
+
+// maxAgentPriority is the highest priority of the user defined priority classes, the higher ones are reserved for
+// the system critical pods.
+const maxAgentPriority = int32(1000000000)
+
+// HostedIsolation is the isolation of the agents of a klusterlet in the Hosted mode.
+type HostedIsolation struct {
+	// ResourceQuota is the hard limits of the resource quota of the agent namespace.
+	ResourceQuota corev1.ResourceList `json:"resourceQuota,omitempty"`
+	// Priority is the value of the priority class created for the agents of the klusterlet. It is not used if the
+	// priority class name is set in the spec of the klusterlet.
+	Priority *int32 `json:"priority,omitempty"`
+}
+
+// GetHostedIsolation returns the isolation of the agents of the klusterlet, or nil if the annotation is not set.
+func GetHostedIsolation(klusterlet *operatorapiv1.Klusterlet) (*HostedIsolation, error) {
+	value, ok := klusterlet.GetAnnotations()[HostedIsolationAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	if !IsHosted(klusterlet.Spec.DeployOption.Mode) {
+		return nil, fmt.Errorf("invalid value of annotation %s: it is only supported in the Hosted mode", HostedIsolationAnnotation)
+	}
+
+	isolation := &HostedIsolation{}
+	if err := json.Unmarshal([]byte(value), isolation); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", HostedIsolationAnnotation, err)
+	}
+	for name, quantity := range isolation.ResourceQuota {
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("invalid value of annotation %s: negative quota of %s", HostedIsolationAnnotation, name)
+		}
+	}
+	if isolation.Priority != nil && *isolation.Priority > maxAgentPriority {
+		return nil, fmt.Errorf("invalid value of annotation %s: priority must not be greater than %d", HostedIsolationAnnotation, maxAgentPriority)
+	}
+	return isolation, nil
+}
+
+// AgentPriorityClass returns the name of the priority class created for the agents of the klusterlet.
+func AgentPriorityClass(klusterletName string) string {
+	return fmt.Sprintf("open-cluster-management-%s-agent", klusterletName)
+}
+
+// ResourceQuotaFor returns the resource quota of the agent namespace, or nil if no quota is set.
+func (i *HostedIsolation) ResourceQuotaFor(namespace string) *corev1.ResourceQuota {
+	if i == nil || len(i.ResourceQuota) == 0 {
+		return nil
+	}
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AgentResourceQuota,
+			Namespace: namespace,
+		},
+		Spec: corev1.ResourceQuotaSpec{Hard: i.ResourceQuota},
+	}
+}
+
+// PriorityClassFor returns the priority class of the agents of the klusterlet, or nil if the priority is not set.
+func (i *HostedIsolation) PriorityClassFor(klusterletName string) *schedulingv1.PriorityClass {
+	if i == nil || i.Priority == nil {
+		return nil
+	}
+	return &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: AgentPriorityClass(klusterletName),
+		},
+		Value:       *i.Priority,
+		Description: fmt.Sprintf("The priority of the agents of klusterlet %s", klusterletName),
+	}
+}
@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestGetHostedIsolation(t *testing.T) {
+	cases := []struct {
+		name           string
+		mode           operatorapiv1.InstallMode
+		value          *string
+		expectQuota    map[string]string
+		expectPriority *int32
+		expectErr      bool
+	}{
+		{name: "no annotation", mode: operatorapiv1.InstallModeHosted},
+		{
+			name:        "resource quota",
+			mode:        operatorapiv1.InstallModeHosted,
+			value:       strPtr(`{"resourceQuota": {"requests.cpu": "1", "pods": "10"}}`),
+			expectQuota: map[string]string{"requests.cpu": "1", "pods": "10"},
+		},
+		{
+			name:           "priority",
+			mode:           operatorapiv1.InstallModeHosted,
+			value:          strPtr(`{"priority": 100000}`),
+			expectPriority: ptr.To[int32](100000),
+		},
+		{name: "default mode", mode: operatorapiv1.InstallModeDefault, value: strPtr(`{"priority": 100000}`), expectErr: true},
+		{name: "invalid json", mode: operatorapiv1.InstallModeHosted, value: strPtr(`{"priority":`), expectErr: true},
+		{name: "negative quota", mode: operatorapiv1.InstallModeHosted, value: strPtr(`{"resourceQuota": {"pods": "-1"}}`), expectErr: true},
+		{name: "reserved priority", mode: operatorapiv1.InstallModeHosted, value: strPtr(`{"priority": 2000000000}`), expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := &operatorapiv1.Klusterlet{ObjectMeta: metav1.ObjectMeta{Name: "klusterlet"}}
+			klusterlet.Spec.DeployOption.Mode = c.mode
+			if c.value != nil {
+				klusterlet.Annotations = map[string]string{HostedIsolationAnnotation: *c.value}
+			}
+			isolation, err := GetHostedIsolation(klusterlet)
+			if c.expectErr != (err != nil) {
+				t.Fatalf("expect error %v, but got %v", c.expectErr, err)
+			}
+
+			quota := isolation.ResourceQuotaFor("agent")
+			if len(c.expectQuota) == 0 && quota != nil {
+				t.Errorf("expect no quota, but got %v", quota)
+			}
+			if len(c.expectQuota) > 0 {
+				if quota == nil || quota.Name != AgentResourceQuota || quota.Namespace != "agent" || len(quota.Spec.Hard) != len(c.expectQuota) {
+					t.Fatalf("expect quota %v, but got %v", c.expectQuota, quota)
+				}
+				for name, value := range c.expectQuota {
+					hard := quota.Spec.Hard[corev1.ResourceName(name)]
+					if hard.Cmp(resource.MustParse(value)) != 0 {
+						t.Errorf("expect quota of %s is %s, but got %s", name, value, hard.String())
+					}
+				}
+			}
+
+			priorityClass := isolation.PriorityClassFor("klusterlet")
+			switch {
+			case c.expectPriority == nil && priorityClass != nil:
+				t.Errorf("expect no priority class, but got %v", priorityClass)
+			case c.expectPriority != nil && (priorityClass == nil || priorityClass.Value != *c.expectPriority ||
+				priorityClass.Name != AgentPriorityClass("klusterlet")):
+				t.Errorf("expect priority %d, but got %v", *c.expectPriority, priorityClass)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// MonitoringAnnotation is the annotation on the cluster manager to install the Prometheus alert rules and the
+// Grafana dashboards of the registration, work and placement metrics on the management cluster. The value is a json
+// object of Monitoring, e.g. {"alertRules": true, "dashboards": true, "namespace": "monitoring"}.
+// The alert rules are installed as a prometheus-operator PrometheusRule, and the dashboards are installed as the
+// configmaps loaded by the Grafana dashboard sidecar. The metrics of the hub components are exposed by the services
+// in the cluster manager namespace and scraped by the prometheus-operator ServiceMonitors in the namespace.
+const MonitoringAnnotation = "operator.open-cluster-management.io/experimental-monitoring"
+
+// MonitoringAlertRulesName is the name of the PrometheusRule with the alert rules.
+const MonitoringAlertRulesName = "open-cluster-management-alert-rules"
+
+// Monitoring is the monitoring manifests installed with the cluster manager.
+type Monitoring struct {
+	// AlertRules installs the PrometheusRule with the alert rules.
+	AlertRules bool `json:"alertRules,omitempty"`
+	// Dashboards installs the configmaps with the dashboards.
+	Dashboards bool `json:"dashboards,omitempty"`
+	// Namespace is the namespace of the manifests on the management cluster, defaults to the namespace of the
+	// cluster manager.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// GetMonitoring returns the monitoring manifests of the cluster manager, or nil if the annotation is not set.
+func GetMonitoring(obj metav1.Object) (*Monitoring, error) {
+	value, ok := obj.GetAnnotations()[MonitoringAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	monitoring := &Monitoring{}
+	if err := json.Unmarshal([]byte(value), monitoring); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", MonitoringAnnotation, err)
+	}
+	if len(monitoring.Namespace) > 0 {
+		if errs := apimachineryvalidation.IsDNS1123Label(monitoring.Namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value of annotation %s: invalid namespace %q: %v",
+				MonitoringAnnotation, monitoring.Namespace, errs)
+		}
+	}
+	return monitoring, nil
+}
+
+// NamespaceOr returns the namespace of the manifests, or the default namespace if it is not set.
+func (m *Monitoring) NamespaceOr(defaultNamespace string) string {
+	if m == nil || len(m.Namespace) == 0 {
+		return defaultNamespace
+	}
+	return m.Namespace
+}
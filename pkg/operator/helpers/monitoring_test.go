@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestGetMonitoring(t *testing.T) {
+	cases := []struct {
+		name             string
+		value            *string
+		expectMonitoring *Monitoring
+		expectNamespace  string
+		expectErr        bool
+	}{
+		{name: "no annotation", expectNamespace: "open-cluster-management-hub"},
+		{
+			name:             "alert rules and dashboards",
+			value:            strPtr(`{"alertRules": true, "dashboards": true}`),
+			expectMonitoring: &Monitoring{AlertRules: true, Dashboards: true},
+			expectNamespace:  "open-cluster-management-hub",
+		},
+		{
+			name:             "dashboards in namespace",
+			value:            strPtr(`{"dashboards": true, "namespace": "monitoring"}`),
+			expectMonitoring: &Monitoring{Dashboards: true, Namespace: "monitoring"},
+			expectNamespace:  "monitoring",
+		},
+		{name: "invalid json", value: strPtr(`{"alertRules":`), expectErr: true},
+		{name: "invalid namespace", value: strPtr(`{"namespace": "Monitoring"}`), expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterManager := &operatorapiv1.ClusterManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager"}}
+			if c.value != nil {
+				clusterManager.Annotations = map[string]string{MonitoringAnnotation: *c.value}
+			}
+			monitoring, err := GetMonitoring(clusterManager)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectErr, err)
+			}
+			if !reflect.DeepEqual(c.expectMonitoring, monitoring) {
+				t.Errorf("expect monitoring %v, but got %v", c.expectMonitoring, monitoring)
+			}
+			if c.expectErr {
+				return
+			}
+			if namespace := monitoring.NamespaceOr("open-cluster-management-hub"); namespace != c.expectNamespace {
+				t.Errorf("expect namespace %s, but got %s", c.expectNamespace, namespace)
+			}
+		})
+	}
+}
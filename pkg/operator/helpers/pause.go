@@ -0,0 +1,16 @@
+package helpers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterletPausedAnnotation is the annotation on the klusterlet to pause the agents during a maintenance window of
+// the managed cluster. Once it is "true", the work agent stops applying the manifestworks and the registration agent
+// stops updating the status of the managed cluster on the hub, while the leases are still renewed. The agents are
+// restarted to pause and resume.
+const KlusterletPausedAnnotation = "operator.open-cluster-management.io/paused"
+
+// IsKlusterletPaused returns true if the agents of the klusterlet are paused by the annotation.
+func IsKlusterletPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[KlusterletPausedAnnotation] == "true"
+}
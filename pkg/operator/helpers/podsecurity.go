@@ -0,0 +1,19 @@
+package helpers
+
+const (
+	// PodSecurityEnforceLabel is the label on the namespace to enforce a Pod Security Standard on its pods.
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	// PodSecurityRestricted is the most restrictive Pod Security Standard.
+	PodSecurityRestricted = "restricted"
+)
+
+// WithRestrictedPodSecurity returns a copy of the labels of a namespace with the restricted Pod Security Standard
+// enforced.
+func WithRestrictedPodSecurity(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[PodSecurityEnforceLabel] = PodSecurityRestricted
+	return result
+}
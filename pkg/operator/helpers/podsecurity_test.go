@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithRestrictedPodSecurity(t *testing.T) {
+	labels := map[string]string{"app": "klusterlet"}
+	actual := WithRestrictedPodSecurity(labels)
+
+	expected := map[string]string{"app": "klusterlet", PodSecurityEnforceLabel: PodSecurityRestricted}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expect labels %v, but got %v", expected, actual)
+	}
+	if _, ok := labels[PodSecurityEnforceLabel]; ok {
+		t.Errorf("expect the labels are not changed, but got %v", labels)
+	}
+}
@@ -0,0 +1,206 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// ProxyConfigAnnotation is the annotation on the klusterlet to connect the agents to the hub through a proxy. The
+// value is a json object of ProxyConfig, e.g.
+// {"httpsProxy": "https://proxy.example.com:3128", "noProxy": ".cluster.local,10.0.0.0/8", "caBundle": "-----BEGIN...",
+// "bootstrapProxyURL": "https://proxy.example.com:3128"}.
+const ProxyConfigAnnotation = "operator.open-cluster-management.io/experimental-proxy-config"
+
+const (
+	// ProxyCABundleConfigMap is the configmap in the agent namespace with the CA bundle of the proxy.
+	ProxyCABundleConfigMap = "proxy-ca-bundle"
+	// ProxyCABundleKey is the key of the CA bundle in the configmap.
+	ProxyCABundleKey = "ca-bundle.crt"
+
+	proxyCABundleVolume    = "proxy-ca-bundle"
+	proxyCABundleMountPath = "/etc/ocm/proxy-ca"
+)
+
+// defaultCertDirs are the default directories of the system certificates, they are kept in SSL_CERT_DIR since it
+// overrides the default ones.
+var defaultCertDirs = []string{"/etc/ssl/certs", "/etc/pki/tls/certs"}
+
+// ProxyConfig is the proxy configuration of the agents.
+type ProxyConfig struct {
+	// HTTPProxy is the url of the proxy for http requests.
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// HTTPSProxy is the url of the proxy for https requests.
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// NoProxy is a comma-separated list of hosts, domains and CIDRs requested without the proxy.
+	NoProxy string `json:"noProxy,omitempty"`
+	// CABundle is the PEM encoded CA bundle trusted by the agents, e.g. for a proxy inspecting the tls traffic.
+	CABundle string `json:"caBundle,omitempty"`
+	// BootstrapProxyURL overrides the proxy url of the bootstrap kubeconfig, the hub kubeconfig built by the
+	// registration agent keeps it as well.
+	BootstrapProxyURL string `json:"bootstrapProxyURL,omitempty"`
+}
+
+// GetProxyConfig returns the proxy config on the object, or nil if the annotation is not set.
+func GetProxyConfig(obj metav1.Object) (*ProxyConfig, error) {
+	value, ok := obj.GetAnnotations()[ProxyConfigAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	config := &ProxyConfig{}
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", ProxyConfigAnnotation, err)
+	}
+	for _, proxy := range []string{config.HTTPProxy, config.HTTPSProxy, config.BootstrapProxyURL} {
+		if len(proxy) == 0 {
+			continue
+		}
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of annotation %s: %v", ProxyConfigAnnotation, err)
+		}
+		if (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || len(proxyURL.Host) == 0 {
+			return nil, fmt.Errorf("invalid value of annotation %s: invalid proxy url %q", ProxyConfigAnnotation, proxy)
+		}
+	}
+	if len(config.CABundle) > 0 {
+		if _, err := certutil.ParseCertsPEM([]byte(config.CABundle)); err != nil {
+			return nil, fmt.Errorf("invalid value of annotation %s: invalid ca bundle: %v", ProxyConfigAnnotation, err)
+		}
+	}
+	return config, nil
+}
+
+// ApplyToDeployment sets the proxy env vars on the containers of the deployment, and mounts the CA bundle
+// configmap if the CA bundle is set.
+func (c *ProxyConfig) ApplyToDeployment(deployment *appsv1.Deployment) {
+	if c == nil {
+		return
+	}
+
+	var env []corev1.EnvVar
+	for _, e := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: c.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: c.HTTPSProxy},
+		{Name: "NO_PROXY", Value: c.NoProxy},
+	} {
+		if len(e.Value) > 0 {
+			env = append(env, e)
+		}
+	}
+	if len(c.CABundle) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "SSL_CERT_DIR",
+			Value: strings.Join(append(defaultCertDirs, proxyCABundleMountPath), ":"),
+		})
+		deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: proxyCABundleVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ProxyCABundleConfigMap},
+				},
+			},
+		})
+	}
+
+	for i := range deployment.Spec.Template.Spec.Containers {
+		container := &deployment.Spec.Template.Spec.Containers[i]
+		container.Env = append(container.Env, env...)
+		if len(c.CABundle) > 0 {
+			// SSL_CERT_DIR is ignored by the hub clients since the CA of the hub kubeconfig replaces the system
+			// trust bundle, so the CA bundle is passed to the agents to be appended to it.
+			container.Args = append(container.Args, fmt.Sprintf("--proxy-ca-bundle-file=%s/%s",
+				proxyCABundleMountPath, ProxyCABundleKey))
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      proxyCABundleVolume,
+				MountPath: proxyCABundleMountPath,
+				ReadOnly:  true,
+			})
+		}
+	}
+}
+
+// ApplyBootstrapToDeployment applies the proxy config to the deployment of the registration agent, the CA bundle is
+// appended to the CA of the bootstrap kubeconfig and the bootstrap proxy url overrides the one in the bootstrap
+// kubeconfig.
+func (c *ProxyConfig) ApplyBootstrapToDeployment(deployment *appsv1.Deployment) {
+	if c == nil {
+		return
+	}
+
+	c.ApplyToDeployment(deployment)
+	for i := range deployment.Spec.Template.Spec.Containers {
+		container := &deployment.Spec.Template.Spec.Containers[i]
+		if len(c.CABundle) > 0 {
+			container.Args = append(container.Args, fmt.Sprintf("--bootstrap-ca-bundle-file=%s/%s",
+				proxyCABundleMountPath, ProxyCABundleKey))
+		}
+		if len(c.BootstrapProxyURL) > 0 {
+			container.Args = append(container.Args, fmt.Sprintf("--bootstrap-proxy-url=%s", c.BootstrapProxyURL))
+		}
+	}
+}
+
+// ProxyCABundle returns the configmap of the CA bundle in the namespace, or nil if the CA bundle is not set.
+func (c *ProxyConfig) ProxyCABundle(namespace string) *corev1.ConfigMap {
+	if c == nil || len(c.CABundle) == 0 {
+		return nil
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ProxyCABundleConfigMap,
+			Namespace: namespace,
+		},
+		Data: map[string]string{ProxyCABundleKey: c.CABundle},
+	}
+}
+
+// ApplyToRestConfig makes the client of the rest config connect through the proxy in the same way as the agents
+// do, and appends the CA bundle to the CA of the rest config.
+func (c *ProxyConfig) ApplyToRestConfig(config *rest.Config) {
+	if c == nil {
+		return
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  c.HTTPProxy,
+		HTTPSProxy: c.HTTPSProxy,
+		NoProxy:    c.NoProxy,
+	}).ProxyFunc()
+	config.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	switch {
+	case len(c.CABundle) == 0:
+	case len(config.CAData) > 0:
+		config.CAData = append(append(append([]byte{}, config.CAData...), '\n'), []byte(c.CABundle)...)
+	default:
+		// the agents trust the CA bundle only if the kubeconfig has no CA
+		config.CAData = []byte(c.CABundle)
+	}
+}
+
+// ApplyBootstrapToRestConfig makes the client of the bootstrap kubeconfig connect in the same way as the
+// registration agent does, the bootstrap proxy url overrides the proxy of the rest config.
+func (c *ProxyConfig) ApplyBootstrapToRestConfig(config *rest.Config) {
+	if c == nil {
+		return
+	}
+
+	c.ApplyToRestConfig(config)
+	if len(c.BootstrapProxyURL) > 0 {
+		// the url is validated when the config is loaded
+		proxyURL, _ := url.Parse(c.BootstrapProxyURL)
+		config.Proxy = http.ProxyURL(proxyURL)
+	}
+}
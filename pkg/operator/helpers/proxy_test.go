@@ -0,0 +1,170 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestGetProxyConfig(t *testing.T) {
+	caBundle, _, err := certutil.GenerateSelfSignedCertKey("proxy", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withCABundle, err := json.Marshal(&ProxyConfig{HTTPSProxy: "https://proxy:3129", CABundle: string(caBundle)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name         string
+		value        *string
+		expectConfig bool
+		expectErr    bool
+	}{
+		{name: "no annotation"},
+		{
+			name:         "valid",
+			value:        strPtr(`{"httpProxy": "http://proxy:3128", "httpsProxy": "https://proxy:3129", "noProxy": ".svc"}`),
+			expectConfig: true,
+		},
+		{
+			name:         "valid ca bundle",
+			value:        strPtr(string(withCABundle)),
+			expectConfig: true,
+		},
+		{name: "invalid json", value: strPtr(`{"httpProxy":`), expectErr: true},
+		{name: "invalid scheme", value: strPtr(`{"httpsProxy": "socks5://proxy:1080"}`), expectErr: true},
+		{name: "no host", value: strPtr(`{"httpsProxy": "proxy:3128"}`), expectErr: true},
+		{name: "invalid ca bundle", value: strPtr(`{"caBundle": "invalid"}`), expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := &operatorapiv1.Klusterlet{ObjectMeta: metav1.ObjectMeta{Name: "klusterlet"}}
+			if c.value != nil {
+				klusterlet.Annotations = map[string]string{ProxyConfigAnnotation: *c.value}
+			}
+			config, err := GetProxyConfig(klusterlet)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectErr, err)
+			}
+			if c.expectConfig != (config != nil) {
+				t.Errorf("expect config %v, but got %v", c.expectConfig, config)
+			}
+		})
+	}
+}
+
+func TestProxyConfigApplyToDeployment(t *testing.T) {
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "agent", Env: []corev1.EnvVar{{Name: "POD_NAME"}}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	deployment := newDeployment()
+	(*ProxyConfig)(nil).ApplyToDeployment(deployment)
+	if len(deployment.Spec.Template.Spec.Containers[0].Env) != 1 {
+		t.Errorf("expect the deployment is not changed, but got %v", deployment.Spec.Template.Spec)
+	}
+
+	deployment = newDeployment()
+	(&ProxyConfig{HTTPSProxy: "https://proxy:3129", NoProxy: ".svc", CABundle: "ca"}).ApplyToDeployment(deployment)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	var names []string
+	for _, env := range container.Env {
+		names = append(names, env.Name)
+	}
+	if strings.Join(names, ",") != "POD_NAME,HTTPS_PROXY,NO_PROXY,SSL_CERT_DIR" {
+		t.Errorf("unexpected env %v", container.Env)
+	}
+	if !strings.HasSuffix(container.Env[3].Value, ":"+proxyCABundleMountPath) {
+		t.Errorf("expect the ca bundle in SSL_CERT_DIR, but got %s", container.Env[3].Value)
+	}
+	volumes := deployment.Spec.Template.Spec.Volumes
+	if len(volumes) != 1 || volumes[0].ConfigMap.Name != ProxyCABundleConfigMap {
+		t.Errorf("expect the ca bundle volume, but got %v", volumes)
+	}
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != proxyCABundleMountPath {
+		t.Errorf("expect the ca bundle volume mount, but got %v", container.VolumeMounts)
+	}
+	if len(container.Args) != 1 || container.Args[0] != "--proxy-ca-bundle-file=/etc/ocm/proxy-ca/ca-bundle.crt" {
+		t.Errorf("expect the ca bundle passed to the agent, but got %v", container.Args)
+	}
+}
+
+func TestProxyConfigApplyBootstrapToDeployment(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "registration"}}},
+			},
+		},
+	}
+	(&ProxyConfig{HTTPSProxy: "https://proxy:3129", CABundle: "ca", BootstrapProxyURL: "https://proxy:3129"}).
+		ApplyBootstrapToDeployment(deployment)
+
+	expected := []string{
+		"--proxy-ca-bundle-file=/etc/ocm/proxy-ca/ca-bundle.crt",
+		"--bootstrap-ca-bundle-file=/etc/ocm/proxy-ca/ca-bundle.crt",
+		"--bootstrap-proxy-url=https://proxy:3129",
+	}
+	if args := deployment.Spec.Template.Spec.Containers[0].Args; strings.Join(args, ",") != strings.Join(expected, ",") {
+		t.Errorf("expect the bootstrap args %v, but got %v", expected, args)
+	}
+}
+
+func TestProxyConfigApplyBootstrapToRestConfig(t *testing.T) {
+	config := &rest.Config{Host: "https://hub:6443"}
+	(&ProxyConfig{HTTPSProxy: "http://proxy:3128", NoProxy: "hub", CABundle: "proxy-ca",
+		BootstrapProxyURL: "http://bootstrap-proxy:3128"}).ApplyBootstrapToRestConfig(config)
+
+	if string(config.CAData) != "proxy-ca" {
+		t.Errorf("expect the ca bundle trusted, but got %q", config.CAData)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://hub:6443/api", nil)
+	proxyURL, err := config.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "bootstrap-proxy:3128" {
+		t.Errorf("expect the bootstrap proxy is used, but got %v, %v", proxyURL, err)
+	}
+}
+
+func TestProxyConfigApplyToRestConfig(t *testing.T) {
+	config := &rest.Config{Host: "https://hub:6443"}
+	config.CAData = []byte("hub-ca")
+	(&ProxyConfig{HTTPSProxy: "http://proxy:3128", NoProxy: "local-hub", CABundle: "proxy-ca"}).ApplyToRestConfig(config)
+
+	if string(config.CAData) != "hub-ca\nproxy-ca" {
+		t.Errorf("expect the ca bundle appended, but got %q", config.CAData)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://hub:6443/api", nil)
+	proxyURL, err := config.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy:3128" {
+		t.Errorf("expect the proxy is used, but got %v, %v", proxyURL, err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://local-hub:6443/api", nil)
+	proxyURL, err = config.Proxy(req)
+	if err != nil || proxyURL != nil {
+		t.Errorf("expect no proxy, but got %v, %v", proxyURL, err)
+	}
+}
@@ -79,6 +79,28 @@ func KlusterletSecretQueueKeyFunc(klusterletLister operatorlister.KlusterletList
 	}
 }
 
+// KlusterletResourceQuotaQueueKeyFunc queues the klusterlet in the Hosted mode when the resource quota of its agent
+// namespace is changed, so the resource usage in the status of the klusterlet is updated.
+func KlusterletResourceQuotaQueueKeyFunc(klusterletLister operatorlister.KlusterletLister) factory.ObjectQueueKeysFunc {
+	return func(obj runtime.Object) []string {
+		accessor, _ := meta.Accessor(obj)
+		if accessor.GetName() != AgentResourceQuota {
+			return []string{}
+		}
+
+		klusterlets, err := klusterletLister.List(labels.Everything())
+		if err != nil {
+			return []string{}
+		}
+
+		if klusterlet := FindKlusterletByNamespace(klusterlets, accessor.GetNamespace()); klusterlet != nil {
+			return []string{klusterlet.Name}
+		}
+
+		return []string{}
+	}
+}
+
 func KlusterletDeploymentQueueKeyFunc(klusterletLister operatorlister.KlusterletLister) factory.ObjectQueueKeysFunc {
 	return func(obj runtime.Object) []string {
 		accessor, _ := meta.Accessor(obj)
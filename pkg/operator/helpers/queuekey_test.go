@@ -155,6 +155,53 @@ func TestKlusterletDeploymentQueueKeyFunc(t *testing.T) {
 	}
 }
 
+func TestKlusterletResourceQuotaQueueKeyFunc(t *testing.T) {
+	newQuota := func(name, namespace string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	cases := []struct {
+		name        string
+		object      runtime.Object
+		klusterlet  *operatorapiv1.Klusterlet
+		expectedKey []string
+	}{
+		{
+			name:        "key by agent resource quota",
+			object:      newQuota(AgentResourceQuota, "test"),
+			klusterlet:  newKlusterlet("testklusterlet", "test", ""),
+			expectedKey: []string{"testklusterlet"},
+		},
+		{
+			name:        "key by wrong resource quota",
+			object:      newQuota("dummy", "test"),
+			klusterlet:  newKlusterlet("testklusterlet", "test", ""),
+			expectedKey: []string{},
+		},
+		{
+			name:        "key by resource quota in other namespace",
+			object:      newQuota(AgentResourceQuota, "other"),
+			klusterlet:  newKlusterlet("testklusterlet", "test", ""),
+			expectedKey: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeOperatorClient := fakeoperatorclient.NewSimpleClientset(c.klusterlet)
+			operatorInformers := operatorinformers.NewSharedInformerFactory(fakeOperatorClient, 5*time.Minute)
+			store := operatorInformers.Operator().V1().Klusterlets().Informer().GetStore()
+			if err := store.Add(c.klusterlet); err != nil {
+				t.Fatal(err)
+			}
+			keyFunc := KlusterletResourceQuotaQueueKeyFunc(operatorInformers.Operator().V1().Klusterlets().Lister())
+			actualKey := keyFunc(c.object)
+			if !reflect.DeepEqual(actualKey, c.expectedKey) {
+				t.Errorf("Queued key is not correct: actual %v, expected %v", actualKey, c.expectedKey)
+			}
+		})
+	}
+}
+
 func TestClusterManagerQueueKeyFunc(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// renderSyncContext is the sync context used to run the sync of a controller once for the given key.
+type renderSyncContext struct {
+	factory.SyncContext
+	queueKey string
+}
+
+func (c renderSyncContext) QueueKey() string {
+	return c.queueKey
+}
+
+// NewRenderSyncContext returns a sync context to render the manifests of the object with the given name.
+func NewRenderSyncContext(name string, recorder events.Recorder) factory.SyncContext {
+	return renderSyncContext{
+		SyncContext: factory.NewSyncContext("render", recorder),
+		queueKey:    name,
+	}
+}
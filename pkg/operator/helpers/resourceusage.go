@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceUsageReportIntervalAnnotation is the annotation on the klusterlet to report the cpu and memory requested by
+// the pods and used by the nodes of the managed cluster in its capacity, e.g. "5m". The registration agent is granted
+// the permissions to list the pods and the node metrics on the managed cluster only if it is set.
+const ResourceUsageReportIntervalAnnotation = "operator.open-cluster-management.io/experimental-resource-usage-report-interval"
+
+// GetResourceUsageReportInterval returns the interval to report the resource usage set on the object, or an empty
+// string if the annotation is not set.
+func GetResourceUsageReportInterval(obj metav1.Object) (string, error) {
+	value, ok := obj.GetAnnotations()[ResourceUsageReportIntervalAnnotation]
+	if !ok {
+		return "", nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return "", fmt.Errorf("invalid value of annotation %s: %q is not a positive duration",
+			ResourceUsageReportIntervalAnnotation, value)
+	}
+	return interval.String(), nil
+}
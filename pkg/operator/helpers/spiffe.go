@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SPIFFETrustDomainAnnotation is the annotation on the klusterlet and the cluster manager to set the trust domain of
+// the SPIFFE IDs of the agents, e.g. "example.org". On the klusterlet, the registration agent sets the SPIFFE ID of
+// the cluster as the URI SAN of its client certificate, and on the cluster manager, the hub approves the csrs with it.
+const SPIFFETrustDomainAnnotation = "operator.open-cluster-management.io/experimental-spiffe-trust-domain"
+
+// GetSPIFFETrustDomain returns the SPIFFE trust domain set on the object, or an empty string if the annotation is
+// not set.
+func GetSPIFFETrustDomain(obj metav1.Object) (string, error) {
+	value, ok := obj.GetAnnotations()[SPIFFETrustDomainAnnotation]
+	if !ok {
+		return "", nil
+	}
+	if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+		return "", fmt.Errorf("invalid value of annotation %s: %s", SPIFFETrustDomainAnnotation, strings.Join(errs, ", "))
+	}
+	return value, nil
+}
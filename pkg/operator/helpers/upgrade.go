@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StagedUpgradeAnnotation is the annotation on the klusterlet to upgrade the agents in stages. The value is a json
+// object of StagedUpgradeConfig, e.g. {"timeout": "10m"}.
+// When the images of the agents change, the new images are rolled out and the agents must become available within
+// the timeout, otherwise the agents are rolled back to the images which were available before.
+const StagedUpgradeAnnotation = "operator.open-cluster-management.io/experimental-staged-upgrade"
+
+// DefaultStagedUpgradeTimeout is the time the upgraded agents have to become available by default.
+const DefaultStagedUpgradeTimeout = 10 * time.Minute
+
+// StagedUpgradeConfig is the configuration of the staged upgrade of the agents.
+type StagedUpgradeConfig struct {
+	// Timeout is the time the upgraded agents have to become available before they are rolled back.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GetStagedUpgradeConfig returns the staged upgrade config on the object, or nil if the annotation is not set.
+func GetStagedUpgradeConfig(obj metav1.Object) (*StagedUpgradeConfig, error) {
+	value, ok := obj.GetAnnotations()[StagedUpgradeAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	config := &StagedUpgradeConfig{}
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", StagedUpgradeAnnotation, err)
+	}
+	if config.Timeout.Duration < 0 {
+		return nil, fmt.Errorf("invalid value of annotation %s: timeout must not be negative", StagedUpgradeAnnotation)
+	}
+	if config.Timeout.Duration == 0 {
+		config.Timeout.Duration = DefaultStagedUpgradeTimeout
+	}
+	return config, nil
+}
@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func TestGetStagedUpgradeConfig(t *testing.T) {
+	cases := []struct {
+		name            string
+		value           *string
+		expectedTimeout time.Duration
+		expectErr       bool
+	}{
+		{name: "no annotation"},
+		{name: "default timeout", value: strPtr(`{}`), expectedTimeout: DefaultStagedUpgradeTimeout},
+		{name: "timeout", value: strPtr(`{"timeout": "5m"}`), expectedTimeout: 5 * time.Minute},
+		{name: "invalid json", value: strPtr(`{"timeout":`), expectErr: true},
+		{name: "negative timeout", value: strPtr(`{"timeout": "-5m"}`), expectErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			klusterlet := &operatorapiv1.Klusterlet{ObjectMeta: metav1.ObjectMeta{Name: "klusterlet"}}
+			if c.value != nil {
+				klusterlet.Annotations = map[string]string{StagedUpgradeAnnotation: *c.value}
+			}
+			config, err := GetStagedUpgradeConfig(klusterlet)
+			if c.expectErr != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectErr, err)
+			}
+			var timeout time.Duration
+			if config != nil {
+				timeout = config.Timeout.Duration
+			}
+			if timeout != c.expectedTimeout {
+				t.Errorf("expect timeout %v, but got %v", c.expectedTimeout, timeout)
+			}
+		})
+	}
+}
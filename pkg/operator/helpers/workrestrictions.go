@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkRestrictionsAnnotation is the annotation on the cluster manager to restrict the manifestworks accepted by the
+// work webhook. The value is a json object of WorkRestrictions, e.g.
+// {"forbiddenKinds": ["Namespace", "ClusterRoleBinding.rbac.authorization.k8s.io"], "requireExecutor": true}.
+// The restrictions are enforced on the creations and the spec changes of the manifestworks.
+const WorkRestrictionsAnnotation = "operator.open-cluster-management.io/experimental-work-restrictions"
+
+// WorkRestrictions is the restrictions of the manifestworks enforced by the work webhook.
+type WorkRestrictions struct {
+	// ForbiddenKinds is the kinds not allowed in the manifests, in the format of kind.group, the kinds of the core
+	// group have no group suffix.
+	ForbiddenKinds []string `json:"forbiddenKinds,omitempty"`
+	// RequireExecutor rejects the manifestworks without the executor.
+	RequireExecutor bool `json:"requireExecutor,omitempty"`
+}
+
+// GetWorkRestrictions returns the restrictions of the manifestworks, or an empty one if the annotation is not set.
+func GetWorkRestrictions(obj metav1.Object) (*WorkRestrictions, error) {
+	restrictions := &WorkRestrictions{}
+	value, ok := obj.GetAnnotations()[WorkRestrictionsAnnotation]
+	if !ok {
+		return restrictions, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), restrictions); err != nil {
+		return nil, fmt.Errorf("invalid value of annotation %s: %v", WorkRestrictionsAnnotation, err)
+	}
+	for _, kind := range restrictions.ForbiddenKinds {
+		if len(kind) == 0 {
+			return nil, fmt.Errorf("invalid value of annotation %s: empty forbidden kind", WorkRestrictionsAnnotation)
+		}
+	}
+	return restrictions, nil
+}
@@ -168,6 +168,17 @@ func (c certRotationController) syncOne(ctx context.Context, syncCtx factory.Syn
 		return nil
 	}
 
+	// the serving certs are issued by cert-manager, stop rotating them.
+	issuer, err := helpers.GetCertManagerIssuer(clustermanager)
+	if err != nil {
+		return err
+	}
+	if issuer != nil {
+		klog.V(4).Infof("Serving certs of ClusterManager %q are issued by cert-manager", clustermanagerName)
+		delete(c.rotationMap, clustermanagerName)
+		return nil
+	}
+
 	_, err = c.kubeClient.CoreV1().Namespaces().Get(ctx, clustermanagerNamespace, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
 		return fmt.Errorf("namespace %q does not exist yet", clustermanagerNamespace)
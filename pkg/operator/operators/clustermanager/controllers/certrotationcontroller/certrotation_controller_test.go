@@ -118,6 +118,30 @@ func TestCertRotation(t *testing.T) {
 				assertResourcesNotExist(t, kubeClient, helpers.ClusterManagerNamespace(testClusterManagerNameHosted, operatorapiv1.InstallModeHosted))
 			},
 		},
+		{
+			name: "Sync one clustermanager with serving certs issued by cert-manager",
+			clusterManagers: []*operatorapiv1.ClusterManager{
+				func() *operatorapiv1.ClusterManager {
+					clusterManager := newClusterManager(testClusterManagerNameDefault, operatorapiv1.InstallModeDefault)
+					clusterManager.Annotations = map[string]string{helpers.CertManagerIssuerAnnotation: `{"name": "ocm-issuer"}`}
+					return clusterManager
+				}(),
+			},
+			existingObjects: []runtime.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: helpers.ClusterManagerNamespace(testClusterManagerNameDefault, operatorapiv1.InstallModeDefault),
+					},
+				},
+			},
+			queueKey: testClusterManagerNameDefault,
+			validate: func(t *testing.T, kubeClient kubernetes.Interface, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				assertResourcesNotExist(t, kubeClient, helpers.ClusterManagerNamespace(testClusterManagerNameDefault, operatorapiv1.InstallModeDefault))
+			},
+		},
 		{
 			name: "Sync all clustermanagaers",
 			clusterManagers: []*operatorapiv1.ClusterManager{
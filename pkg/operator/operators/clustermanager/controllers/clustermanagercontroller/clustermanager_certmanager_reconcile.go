@@ -0,0 +1,151 @@
+package clustermanagercontroller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+// webhookServingCerts are the secrets of the serving certs of the webhooks and the services they serve.
+var webhookServingCerts = []struct {
+	secret  string
+	service string
+}{
+	{secret: helpers.RegistrationWebhookSecret, service: helpers.RegistrationWebhookService},
+	{secret: helpers.WorkWebhookSecret, service: helpers.WorkWebhookService},
+}
+
+// certManagerReconcile applies the cert-manager certificates of the webhook serving certs on the management
+// cluster if the cluster manager references a cert-manager issuer, and removes them otherwise.
+type certManagerReconcile struct {
+	dynamicClient dynamic.Interface
+	issuer        *helpers.CertManagerIssuer
+	recorder      events.Recorder
+}
+
+func (c *certManagerReconcile) reconcile(ctx context.Context, cm *operatorapiv1.ClusterManager,
+	config manifests.HubConfig) (*operatorapiv1.ClusterManager, reconcileState, error) {
+	if c.issuer == nil {
+		return c.clean(ctx, cm, config)
+	}
+
+	var errs []error
+	for _, cert := range webhookServingCerts {
+		if err := c.applyCertificate(ctx, certificate(config.ClusterManagerNamespace, cert.secret, cert.service, c.issuer)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		helpers.SetRelatedResourcesStatuses(&cm.Status.RelatedResources,
+			certificateRelatedResource(config.ClusterManagerNamespace, cert.secret))
+	}
+
+	if len(errs) > 0 {
+		meta.SetStatusCondition(&cm.Status.Conditions, metav1.Condition{
+			Type:    operatorapiv1.ConditionClusterManagerApplied,
+			Status:  metav1.ConditionFalse,
+			Reason:  "CertificateApplyFailed",
+			Message: fmt.Sprintf("Failed to apply cert-manager certificates: %v", utilerrors.NewAggregate(errs)),
+		})
+		return cm, reconcileStop, utilerrors.NewAggregate(errs)
+	}
+	return cm, reconcileContinue, nil
+}
+
+// clean deletes the certificates recorded in the related resources of the cluster manager, so nothing is requested
+// if cert-manager has never been used.
+func (c *certManagerReconcile) clean(ctx context.Context, cm *operatorapiv1.ClusterManager,
+	config manifests.HubConfig) (*operatorapiv1.ClusterManager, reconcileState, error) {
+	for _, cert := range webhookServingCerts {
+		relatedResource := certificateRelatedResource(config.ClusterManagerNamespace, cert.secret)
+		if !slices.Contains(cm.Status.RelatedResources, relatedResource) {
+			continue
+		}
+
+		// the secret is kept, it is rotated by the certrotation controller once the certificate is removed.
+		err := c.dynamicClient.Resource(helpers.CertificateGVR).Namespace(config.ClusterManagerNamespace).Delete(
+			ctx, cert.secret, metav1.DeleteOptions{})
+		switch {
+		case errors.IsNotFound(err) || meta.IsNoMatchError(err):
+		case err != nil:
+			return cm, reconcileStop, fmt.Errorf("failed to delete certificate %s/%s: %v", config.ClusterManagerNamespace, cert.secret, err)
+		default:
+			c.recorder.Eventf("CertificateDeleted", "certificate %s/%s is deleted", config.ClusterManagerNamespace, cert.secret)
+		}
+		helpers.RemoveRelatedResourcesStatuses(&cm.Status.RelatedResources, relatedResource)
+	}
+	return cm, reconcileContinue, nil
+}
+
+func certificateRelatedResource(namespace, name string) operatorapiv1.RelatedResourceMeta {
+	return operatorapiv1.RelatedResourceMeta{
+		Group:     helpers.CertificateGVR.Group,
+		Version:   helpers.CertificateGVR.Version,
+		Resource:  helpers.CertificateGVR.Resource,
+		Namespace: namespace,
+		Name:      name,
+	}
+}
+
+func (c *certManagerReconcile) applyCertificate(ctx context.Context, required *unstructured.Unstructured) error {
+	client := c.dynamicClient.Resource(helpers.CertificateGVR).Namespace(required.GetNamespace())
+	existing, err := client.Get(ctx, required.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(ctx, required, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create certificate %s/%s: %v", required.GetNamespace(), required.GetName(), err)
+		}
+		c.recorder.Eventf("CertificateCreated", "certificate %s/%s is created", required.GetNamespace(), required.GetName())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get certificate %s/%s: %v", required.GetNamespace(), required.GetName(), err)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Object["spec"], required.Object["spec"]) {
+		return nil
+	}
+	existing = existing.DeepCopy()
+	existing.Object["spec"] = required.Object["spec"]
+	if _, err := client.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update certificate %s/%s: %v", required.GetNamespace(), required.GetName(), err)
+	}
+	c.recorder.Eventf("CertificateUpdated", "certificate %s/%s is updated", required.GetNamespace(), required.GetName())
+	return nil
+}
+
+// certificate returns the cert-manager certificate of the serving cert of the webhook service, it is named after
+// the secret.
+func certificate(namespace, secret, service string, issuer *helpers.CertManagerIssuer) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": helpers.CertificateGVR.GroupVersion().String(),
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      secret,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"secretName": secret,
+			"commonName": fmt.Sprintf("%s.%s.svc", service, namespace),
+			"dnsNames":   []interface{}{fmt.Sprintf("%s.%s.svc", service, namespace)},
+			"usages":     []interface{}{"digital signature", "key encipherment", "server auth"},
+			"issuerRef": map[string]interface{}{
+				"name":  issuer.Name,
+				"kind":  issuer.Kind,
+				"group": issuer.Group,
+			},
+		},
+	}}
+}
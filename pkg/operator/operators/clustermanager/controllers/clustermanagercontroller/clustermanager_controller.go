@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	errorhelpers "errors"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
 	appsinformer "k8s.io/client-go/informers/apps/v1"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -32,6 +34,7 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/common/fips"
 	commonhelper "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -49,6 +52,7 @@ type clusterManagerController struct {
 	clusterManagerLister operatorlister.ClusterManagerLister
 	operatorKubeClient   kubernetes.Interface
 	operatorKubeconfig   *rest.Config
+	dynamicClient        dynamic.Interface
 	configMapLister      corev1listers.ConfigMapLister
 	recorder             events.Recorder
 	cache                resourceapply.ResourceCache
@@ -62,6 +66,7 @@ type clusterManagerController struct {
 	controlPlaneNodeLabelSelector string
 	deploymentReplicas            int32
 	operatorNamespace             string
+	restrictedPodSecurity         bool
 }
 
 type clusterManagerReconcile interface {
@@ -80,6 +85,7 @@ const (
 func NewClusterManagerController(
 	operatorKubeClient kubernetes.Interface,
 	operatorKubeconfig *rest.Config,
+	dynamicClient dynamic.Interface,
 	clusterManagerClient operatorv1client.ClusterManagerInterface,
 	clusterManagerInformer operatorinformer.ClusterManagerInformer,
 	deploymentInformer appsinformer.DeploymentInformer,
@@ -89,10 +95,12 @@ func NewClusterManagerController(
 	controlPlaneNodeLabelSelector string,
 	deploymentReplicas int32,
 	operatorNamespace string,
+	restrictedPodSecurity bool,
 ) factory.Controller {
 	controller := &clusterManagerController{
 		operatorKubeClient: operatorKubeClient,
 		operatorKubeconfig: operatorKubeconfig,
+		dynamicClient:      dynamicClient,
 		patcher: patcher.NewPatcher[
 			*operatorapiv1.ClusterManager, operatorapiv1.ClusterManagerSpec, operatorapiv1.ClusterManagerStatus](
 			clusterManagerClient),
@@ -106,6 +114,7 @@ func NewClusterManagerController(
 		controlPlaneNodeLabelSelector: controlPlaneNodeLabelSelector,
 		deploymentReplicas:            deploymentReplicas,
 		operatorNamespace:             operatorNamespace,
+		restrictedPodSecurity:         restrictedPodSecurity,
 	}
 
 	return factory.New().WithSync(controller.sync).
@@ -147,7 +156,53 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 		workDriver = clusterManager.Spec.WorkConfiguration.WorkDriver
 	}
 
+	imageConfig, err := helpers.GetImageConfig(clusterManager, helpers.RegistrationImageComponent, helpers.WorkImageComponent,
+		helpers.PlacementImageComponent, helpers.AddOnManagerImageComponent)
+	if err != nil {
+		klog.Errorf("failed to parse image config for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
+	certManagerIssuer, err := helpers.GetCertManagerIssuer(clusterManager)
+	if err != nil {
+		klog.Errorf("failed to parse cert-manager issuer for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
+	monitoring, err := helpers.GetMonitoring(clusterManager)
+	if err != nil {
+		klog.Errorf("failed to parse monitoring config for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
+	highAvailability, err := helpers.HighAvailability(clusterManager)
+	if err != nil {
+		klog.Errorf("failed to parse high availability config for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
+	workRestrictions, err := helpers.GetWorkRestrictions(clusterManager)
+	if err != nil {
+		klog.Errorf("failed to parse work restrictions for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
+	spiffeTrustDomain, err := helpers.GetSPIFFETrustDomain(clusterManager)
+	if err != nil {
+		klog.Errorf("failed to parse spiffe trust domain for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
+	hubCABundleConfigMap, err := helpers.GetHubCABundleConfigMap(clusterManager)
+	if err != nil {
+		klog.Errorf("failed to parse hub ca bundle configmap for cluster manager %s: %v", clusterManager.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalClusterManager, err)
+	}
+
 	replica := n.deploymentReplicas
+	if highAvailability.Replicas != nil {
+		replica = *highAvailability.Replicas
+	}
 	if replica <= 0 {
 		replica = helpers.DetermineReplica(ctx, n.operatorKubeClient, clusterManager.Spec.DeployOption.Mode, nil, n.controlPlaneNodeLabelSelector)
 	}
@@ -156,10 +211,10 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 	config := manifests.HubConfig{
 		ClusterManagerName:      clusterManager.Name,
 		ClusterManagerNamespace: clusterManagerNamespace,
-		RegistrationImage:       clusterManager.Spec.RegistrationImagePullSpec,
-		WorkImage:               clusterManager.Spec.WorkImagePullSpec,
-		PlacementImage:          clusterManager.Spec.PlacementImagePullSpec,
-		AddOnManagerImage:       clusterManager.Spec.AddOnManagerImagePullSpec,
+		RegistrationImage:       imageConfig.Image(helpers.RegistrationImageComponent, clusterManager.Spec.RegistrationImagePullSpec),
+		WorkImage:               imageConfig.Image(helpers.WorkImageComponent, clusterManager.Spec.WorkImagePullSpec),
+		PlacementImage:          imageConfig.Image(helpers.PlacementImageComponent, clusterManager.Spec.PlacementImagePullSpec),
+		AddOnManagerImage:       imageConfig.Image(helpers.AddOnManagerImageComponent, clusterManager.Spec.AddOnManagerImagePullSpec),
 		Replica:                 replica,
 		HostedMode:              clusterManager.Spec.DeployOption.Mode == operatorapiv1.InstallModeHosted,
 		RegistrationWebhook: manifests.Webhook{
@@ -171,6 +226,14 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 		ResourceRequirementResourceType: helpers.ResourceType(clusterManager),
 		ResourceRequirements:            resourceRequirements,
 		WorkDriver:                      string(workDriver),
+		RestrictedPodSecurity:           n.restrictedPodSecurity,
+		LeaderElectionArgs:              highAvailability.LeaderElectionArgs(),
+		ShardedControllerArgs:           highAvailability.ShardedControllerArgs(),
+		FIPSMode:                        fips.Enabled(),
+		WorkForbiddenKinds:              strings.Join(workRestrictions.ForbiddenKinds, ","),
+		WorkRequireExecutor:             workRestrictions.RequireExecutor,
+		SPIFFETrustDomain:               spiffeTrustDomain,
+		HubCABundleConfigMap:            hubCABundleConfigMap,
 	}
 
 	var registrationFeatureMsgs, workFeatureMsgs, addonFeatureMsgs string
@@ -198,6 +261,14 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 	}
 	_, addonFeatureMsgs = helpers.ConvertToFeatureGateFlags("Addon", addonFeatureGates, ocmfeature.DefaultHubAddonManagerFeatureGates)
 	featureGateCondition := helpers.BuildFeatureCondition(registrationFeatureMsgs, workFeatureMsgs, addonFeatureMsgs)
+	effectiveFeatureGateCondition := helpers.BuildEffectiveFeatureCondition(
+		helpers.ComponentFeatureGates{Component: "Registration", FeatureGates: registrationFeatureGates,
+			DefaultFeatures: ocmfeature.DefaultHubRegistrationFeatureGates},
+		helpers.ComponentFeatureGates{Component: "Work", FeatureGates: workFeatureGates,
+			DefaultFeatures: ocmfeature.DefaultHubWorkFeatureGates},
+		helpers.ComponentFeatureGates{Component: "Addon", FeatureGates: addonFeatureGates,
+			DefaultFeatures: ocmfeature.DefaultHubAddonManagerFeatureGates},
+	)
 
 	// Check if addon management is enabled by the feature gate
 	config.AddOnManagerEnabled = helpers.FeatureGateEnabled(addonFeatureGates, ocmfeature.DefaultHubAddonManagerFeatureGates, ocmfeature.AddonManagement)
@@ -238,6 +309,9 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 		&hubReconcile{cache: n.cache, recorder: n.recorder, hubKubeClient: hubClient},
 		&runtimeReconcile{cache: n.cache, recorder: n.recorder, hubKubeConfig: hubKubeConfig, hubKubeClient: hubClient,
 			kubeClient: managementClient, ensureSAKubeconfigs: n.ensureSAKubeconfigs},
+		&certManagerReconcile{recorder: n.recorder, dynamicClient: n.dynamicClient, issuer: certManagerIssuer},
+		&monitoringReconcile{recorder: n.recorder, kubeClient: managementClient, dynamicClient: n.dynamicClient,
+			monitoring: monitoring},
 		&webhookReconcile{cache: n.cache, recorder: n.recorder, hubKubeClient: hubClient, kubeClient: managementClient},
 	}
 
@@ -266,6 +340,19 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 			caBundle = cb
 		}
 	}
+	if certManagerIssuer != nil {
+		cb, err := n.certManagerCABundle(ctx, clusterManagerNamespace)
+		switch {
+		case err != nil:
+			return err
+		case len(cb) == 0:
+			// keep the CA bundle of the built-in rotation until the certs are issued, the secrets are not watched so
+			// requeue to check them later.
+			controllerContext.Queue().AddAfter(clusterManagerName, clusterManagerReSyncTime)
+		default:
+			caBundle = cb
+		}
+	}
 	encodedCaBundle := base64.StdEncoding.EncodeToString([]byte(caBundle))
 	config.RegistrationAPIServiceCABundle = encodedCaBundle
 	config.WorkAPIServiceCABundle = encodedCaBundle
@@ -294,6 +381,8 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 
 	// Update status
 	meta.SetStatusCondition(&clusterManager.Status.Conditions, featureGateCondition)
+	meta.SetStatusCondition(&clusterManager.Status.Conditions, helpers.BuildAnnotationsCondition(nil))
+	meta.SetStatusCondition(&clusterManager.Status.Conditions, effectiveFeatureGateCondition)
 	clusterManager.Status.ObservedGeneration = clusterManager.Generation
 	if len(errs) == 0 {
 		meta.SetStatusCondition(&clusterManager.Status.Conditions, metav1.Condition{
@@ -316,6 +405,41 @@ func (n *clusterManagerController) sync(ctx context.Context, controllerContext f
 	return utilerrors.NewAggregate(errs)
 }
 
+// reportInvalidAnnotations sets the condition of the cluster manager to report the error of parsing its
+// annotations, and returns the error so the cluster manager is synced again with backoff.
+func (n *clusterManagerController) reportInvalidAnnotations(ctx context.Context,
+	clusterManager *operatorapiv1.ClusterManager, err error) error {
+	newClusterManager := clusterManager.DeepCopy()
+	meta.SetStatusCondition(&newClusterManager.Status.Conditions, helpers.BuildAnnotationsCondition(err))
+	if _, updatedErr := n.patcher.PatchStatus(ctx, newClusterManager, newClusterManager.Status, clusterManager.Status); updatedErr != nil {
+		return utilerrors.NewAggregate([]error{err, updatedErr})
+	}
+	return err
+}
+
+// certManagerCABundle returns the CAs of the webhook serving certs issued by cert-manager, or an empty string if
+// any of the certs is not issued yet.
+func (n *clusterManagerController) certManagerCABundle(ctx context.Context, namespace string) (string, error) {
+	var cas []string
+	for _, cert := range webhookServingCerts {
+		secret, err := n.operatorKubeClient.CoreV1().Secrets(namespace).Get(ctx, cert.secret, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		ca := strings.TrimSpace(string(secret.Data[helpers.CertManagerCAKey]))
+		if len(ca) == 0 {
+			return "", nil
+		}
+		if !slices.Contains(cas, ca) {
+			cas = append(cas, ca)
+		}
+	}
+	return strings.Join(cas, "\n") + "\n", nil
+}
+
 func generateHubClients(hubKubeConfig *rest.Config) (kubernetes.Interface, apiextensionsclient.Interface,
 	migrationclient.StorageVersionMigrationsGetter, error) {
 	hubClient, err := kubernetes.NewForConfig(hubKubeConfig)
@@ -2,6 +2,8 @@ package clustermanagercontroller
 
 import (
 	"context"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	fakeapiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
@@ -20,7 +23,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	fakekube "k8s.io/client-go/kubernetes/fake"
@@ -36,6 +42,7 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/common/metrics/dashboards"
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
@@ -51,6 +58,7 @@ type testController struct {
 	hubKubeClient            *fakekube.Clientset
 	apiExtensionClient       *fakeapiextensions.Clientset
 	operatorClient           *fakeoperatorlient.Clientset
+	dynamicClient            *fakedynamic.FakeDynamicClient
 }
 
 func newClusterManager(name string) *operatorapiv1.ClusterManager {
@@ -258,8 +266,10 @@ func setup(t *testing.T, tc *testController, cd []runtime.Object, crds ...runtim
 	fakeManagementKubeClient := fakekube.NewSimpleClientset(cd...)
 	fakeAPIExtensionClient := fakeapiextensions.NewSimpleClientset(crds...)
 	fakeMigrationClient := fakemigrationclient.NewSimpleClientset()
+	fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
 
 	// set clients in test controller
+	tc.dynamicClient = fakeDynamicClient
 	tc.apiExtensionClient = fakeAPIExtensionClient
 	tc.hubKubeClient = fakeHubKubeClient
 	tc.managementKubeClient = fakeManagementKubeClient
@@ -267,6 +277,7 @@ func setup(t *testing.T, tc *testController, cd []runtime.Object, crds ...runtim
 	// set clients in clustermanager controller
 	tc.clusterManagerController.recorder = eventstesting.NewTestingEventRecorder(t)
 	tc.clusterManagerController.operatorKubeClient = fakeManagementKubeClient
+	tc.clusterManagerController.dynamicClient = fakeDynamicClient
 	tc.clusterManagerController.generateHubClusterClients = func(hubKubeConfig *rest.Config) (
 		kubernetes.Interface, apiextensionsclient.Interface, migrationclient.StorageVersionMigrationsGetter, error) {
 		return fakeHubKubeClient, fakeAPIExtensionClient, fakeMigrationClient.MigrationV1alpha1(), nil
@@ -453,7 +464,7 @@ func TestSyncDeploy(t *testing.T) {
 
 	// Check if resources are created as expected
 	// We expect create the namespace twice respectively in the management cluster and the hub cluster.
-	testingcommon.AssertEqualNumber(t, len(createKubeObjects), 28)
+	testingcommon.AssertEqualNumber(t, len(createKubeObjects), 30)
 	for _, object := range createKubeObjects {
 		ensureObject(t, object, clusterManager)
 	}
@@ -467,7 +478,323 @@ func TestSyncDeploy(t *testing.T) {
 		}
 	}
 	// Check if resources are created as expected
-	testingcommon.AssertEqualNumber(t, len(createCRDObjects), 12)
+	testingcommon.AssertEqualNumber(t, len(createCRDObjects), 16)
+}
+
+func TestSyncDeployHighAvailability(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{
+		helpers.HighAvailabilityAnnotation: `{"replicas": 3, "podDisruptionBudget": {}, "antiAffinity": "Required",
+"topologySpreadConstraints": [{"maxSkew": 1, "topologyKey": "topology.kubernetes.io/zone", "whenUnsatisfiable": "ScheduleAnyway"}]}`,
+	}
+	tc := newTestController(t, clusterManager)
+	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+	cd := setDeployment(clusterManager.Name, clusterManagerNamespace)
+	setup(t, tc, cd)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+
+	err := tc.clusterManagerController.sync(ctx, syncContext)
+	if err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+
+	var deployments, pdbs int
+	for _, action := range tc.managementKubeClient.Actions() {
+		switch object := action.(type) {
+		case clienttesting.UpdateActionImpl:
+			deployment, ok := object.Object.(*appsv1.Deployment)
+			if !ok {
+				continue
+			}
+			deployments++
+			if *deployment.Spec.Replicas != 3 {
+				t.Errorf("Expected 3 replicas of deployment %s, but got %d", deployment.Name, *deployment.Spec.Replicas)
+			}
+			podSpec := deployment.Spec.Template.Spec
+			if len(podSpec.TopologySpreadConstraints) != 1 || podSpec.TopologySpreadConstraints[0].LabelSelector == nil {
+				t.Errorf("Expected topology spread constraints of deployment %s, but got %v", deployment.Name, podSpec.TopologySpreadConstraints)
+			}
+			if len(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
+				t.Errorf("Expected required anti-affinity of deployment %s", deployment.Name)
+			}
+		case clienttesting.CreateActionImpl:
+			pdb, ok := object.Object.(*policyv1.PodDisruptionBudget)
+			if !ok {
+				continue
+			}
+			pdbs++
+			if pdb.Spec.MinAvailable.IntValue() != 1 {
+				t.Errorf("Expected minAvailable 1 of pdb %s, but got %v", pdb.Name, pdb.Spec.MinAvailable)
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, deployments, 6)
+	testingcommon.AssertEqualNumber(t, pdbs, 6)
+}
+
+func TestSyncDeployInvalidHighAvailability(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{
+		helpers.HighAvailabilityAnnotation: `{"replicas": 0}`,
+	}
+	tc := newTestController(t, clusterManager)
+	setup(t, tc, nil)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err == nil {
+		t.Errorf("Expected error when sync with invalid high availability config")
+	}
+
+	updated, err := tc.operatorClient.OperatorV1().ClusterManagers().Get(ctx, "testhub", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionFalse(updated.Status.Conditions, helpers.AnnotationsTypeValid) {
+		t.Errorf("Expected the annotations are reported invalid, but got %v", updated.Status.Conditions)
+	}
+}
+
+func TestSyncDeployImageConfig(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Spec.RegistrationImagePullSpec = "quay.io/open-cluster-management/registration:latest"
+	clusterManager.Spec.PlacementImagePullSpec = "quay.io/open-cluster-management/placement:latest"
+	clusterManager.Annotations = map[string]string{
+		helpers.ImageConfigAnnotation: `{"registries": [{"source": "quay.io/open-cluster-management", "mirror": "registry.local/ocm"}],
+"digests": {"placement": "` + digest + `"}}`,
+	}
+
+	objects, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 1})
+	if err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+
+	expected := map[string]string{
+		"testhub-registration-controller": "registry.local/ocm/registration:latest",
+		"testhub-registration-webhook":    "registry.local/ocm/registration:latest",
+		"testhub-placement-controller":    "registry.local/ocm/placement@" + digest,
+	}
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		image, ok := expected[deployment.Name]
+		if !ok {
+			continue
+		}
+		if actual := deployment.Spec.Template.Spec.Containers[0].Image; actual != image {
+			t.Errorf("Expected image %s of deployment %s, but got %s", image, deployment.Name, actual)
+		}
+		delete(expected, deployment.Name)
+	}
+	if len(expected) != 0 {
+		t.Errorf("Expected deployments %v are not rendered", expected)
+	}
+}
+
+func TestSyncDeployCertManager(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{
+		helpers.CertManagerIssuerAnnotation: `{"name": "ocm-issuer", "kind": "ClusterIssuer"}`,
+	}
+	tc := newTestController(t, clusterManager)
+	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+	cd := setDeployment(clusterManager.Name, clusterManagerNamespace)
+	for _, name := range []string{helpers.RegistrationWebhookSecret, helpers.WorkWebhookSecret} {
+		cd = append(cd, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: clusterManagerNamespace},
+			Data:       map[string][]byte{helpers.CertManagerCAKey: []byte("cert-manager-ca")},
+		})
+	}
+	setup(t, tc, cd)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+
+	for _, name := range []string{helpers.RegistrationWebhookSecret, helpers.WorkWebhookSecret} {
+		certificate, err := tc.dynamicClient.Resource(helpers.CertificateGVR).Namespace(clusterManagerNamespace).Get(
+			ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected certificate %s, %v", name, err)
+		}
+		issuer, _, _ := unstructured.NestedStringMap(certificate.Object, "spec", "issuerRef")
+		if issuer["name"] != "ocm-issuer" || issuer["kind"] != helpers.CertManagerClusterIssuerKind {
+			t.Errorf("Expected issuer of certificate %s, but got %v", name, issuer)
+		}
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		testingcommon.AssertEqualNameNamespace(t, secretName, "", name, "")
+	}
+
+	webhook, err := tc.hubKubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(
+		ctx, "managedclustervalidators.admission.cluster.open-cluster-management.io", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected webhook configuration, %v", err)
+	}
+	if caBundle := string(webhook.Webhooks[0].ClientConfig.CABundle); caBundle != "cert-manager-ca\n" {
+		t.Errorf("Expected the CA bundle issued by cert-manager, but got %q", caBundle)
+	}
+
+	updated, err := tc.operatorClient.OperatorV1().ClusterManagers().Get(ctx, "testhub", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(updated.Status.RelatedResources, certificateRelatedResource(clusterManagerNamespace, helpers.WorkWebhookSecret)) {
+		t.Errorf("Expected the certificates in the related resources, but got %v", updated.Status.RelatedResources)
+	}
+
+	// the certificates are removed once the issuer is not referenced
+	clusterManager.Annotations = map[string]string{}
+	clusterManager.Status.RelatedResources = updated.Status.RelatedResources
+	tc = newTestController(t, clusterManager)
+	setup(t, tc, cd)
+	if _, err := tc.dynamicClient.Resource(helpers.CertificateGVR).Namespace(clusterManagerNamespace).Create(
+		ctx, certificate(clusterManagerNamespace, helpers.WorkWebhookSecret, helpers.WorkWebhookService,
+			&helpers.CertManagerIssuer{Name: "ocm-issuer"}), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+	_, err = tc.dynamicClient.Resource(helpers.CertificateGVR).Namespace(clusterManagerNamespace).Get(
+		ctx, helpers.WorkWebhookSecret, metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected certificate is removed, but got %v", err)
+	}
+}
+
+func TestSyncDeployWithoutCertManager(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	tc := newTestController(t, clusterManager)
+	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+	setup(t, tc, setDeployment(clusterManager.Name, clusterManagerNamespace))
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+	for _, action := range tc.dynamicClient.Actions() {
+		if action.GetResource() == helpers.CertificateGVR {
+			t.Errorf("Expected no request of certificates, but got %v", action)
+		}
+	}
+}
+
+func TestSyncDeployMonitoring(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{
+		helpers.MonitoringAnnotation: `{"alertRules": true, "dashboards": true, "namespace": "monitoring"}`,
+	}
+	tc := newTestController(t, clusterManager)
+	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+	setup(t, tc, setDeployment(clusterManager.Name, clusterManagerNamespace))
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+
+	rule, err := tc.dynamicClient.Resource(dashboards.PrometheusRuleGVR).Namespace("monitoring").Get(
+		ctx, helpers.MonitoringAlertRulesName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected prometheusrule, %v", err)
+	}
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	testingcommon.AssertEqualNumber(t, len(groups), len(dashboards.AlertRuleGroups()))
+
+	configMaps, err := dashboards.DashboardConfigMaps("monitoring")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, configMap := range configMaps {
+		if _, err := tc.managementKubeClient.CoreV1().ConfigMaps("monitoring").Get(ctx, configMap.Name, metav1.GetOptions{}); err != nil {
+			t.Errorf("Expected dashboard configmap %s, %v", configMap.Name, err)
+		}
+	}
+
+	// the metrics of the hub components are scraped by the ServiceMonitors
+	for _, name := range []string{"testhub-registration-controller-metrics", "testhub-placement-controller-metrics"} {
+		service, err := tc.managementKubeClient.CoreV1().Services(clusterManagerNamespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected metrics service %s, %v", name, err)
+		}
+		monitor, err := tc.dynamicClient.Resource(dashboards.ServiceMonitorGVR).Namespace("monitoring").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Expected servicemonitor %s, %v", name, err)
+		}
+		matchLabels, _, _ := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+		if !reflect.DeepEqual(matchLabels, service.Labels) {
+			t.Errorf("Expected servicemonitor %s selects the service with labels %v, but got %v", name, service.Labels, matchLabels)
+		}
+	}
+
+	updated, err := tc.operatorClient.OperatorV1().ClusterManagers().Get(ctx, "testhub", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(updated.Status.RelatedResources,
+		monitoringRelatedResource(dashboards.PrometheusRuleGVR, "monitoring", helpers.MonitoringAlertRulesName)) {
+		t.Errorf("Expected the prometheusrule in the related resources, but got %v", updated.Status.RelatedResources)
+	}
+
+	// the resources in the former namespace are removed once the namespace is changed
+	clusterManager.Annotations = map[string]string{
+		helpers.MonitoringAnnotation: `{"alertRules": true, "namespace": "observability"}`,
+	}
+	clusterManager.Status.RelatedResources = updated.Status.RelatedResources
+	tc = newTestController(t, clusterManager)
+	setup(t, tc, setDeployment(clusterManager.Name, clusterManagerNamespace))
+	rule, err = dashboards.PrometheusRule("monitoring", helpers.MonitoringAlertRulesName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.dynamicClient.Resource(dashboards.PrometheusRuleGVR).Namespace("monitoring").Create(
+		ctx, rule, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, configMap := range configMaps {
+		if _, err := tc.managementKubeClient.CoreV1().ConfigMaps("monitoring").Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+	_, err = tc.dynamicClient.Resource(dashboards.PrometheusRuleGVR).Namespace("monitoring").Get(
+		ctx, helpers.MonitoringAlertRulesName, metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected prometheusrule in the former namespace is removed, but got %v", err)
+	}
+	if _, err := tc.dynamicClient.Resource(dashboards.PrometheusRuleGVR).Namespace("observability").Get(
+		ctx, helpers.MonitoringAlertRulesName, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected prometheusrule in the new namespace, %v", err)
+	}
+	for _, configMap := range configMaps {
+		_, err := tc.managementKubeClient.CoreV1().ConfigMaps("monitoring").Get(ctx, configMap.Name, metav1.GetOptions{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("Expected dashboard configmap %s is removed, but got %v", configMap.Name, err)
+		}
+	}
+}
+
+func TestSyncDeployWithoutMonitoring(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	tc := newTestController(t, clusterManager)
+	clusterManagerNamespace := helpers.ClusterManagerNamespace(clusterManager.Name, clusterManager.Spec.DeployOption.Mode)
+	setup(t, tc, setDeployment(clusterManager.Name, clusterManagerNamespace))
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "testhub")
+	if err := tc.clusterManagerController.sync(ctx, syncContext); err != nil {
+		t.Fatalf("Expected no error when sync, %v", err)
+	}
+	for _, action := range tc.dynamicClient.Actions() {
+		if action.GetResource().Group == dashboards.PrometheusRuleGVR.Group {
+			t.Errorf("Expected no request of the monitoring resources, but got %v", action)
+		}
+	}
 }
 
 func TestSyncDeployNoWebhook(t *testing.T) {
@@ -507,7 +834,7 @@ func TestSyncDeployNoWebhook(t *testing.T) {
 		}
 	}
 	// Check if resources are created as expected
-	testingcommon.AssertEqualNumber(t, len(createCRDObjects), 12)
+	testingcommon.AssertEqualNumber(t, len(createCRDObjects), 16)
 }
 
 // TestSyncDelete test cleanup hub deploy
@@ -535,7 +862,7 @@ func TestSyncDelete(t *testing.T) {
 			deleteKubeActions = append(deleteKubeActions, deleteKubeAction)
 		}
 	}
-	testingcommon.AssertEqualNumber(t, len(deleteKubeActions), 30) // delete namespace both from the hub cluster and the mangement cluster
+	testingcommon.AssertEqualNumber(t, len(deleteKubeActions), 32) // delete namespace both from the hub cluster and the mangement cluster
 
 	var deleteCRDActions []clienttesting.DeleteActionImpl
 	crdActions := tc.apiExtensionClient.Actions()
@@ -546,7 +873,7 @@ func TestSyncDelete(t *testing.T) {
 		}
 	}
 	// Check if resources are created as expected
-	testingcommon.AssertEqualNumber(t, len(deleteCRDActions), 16)
+	testingcommon.AssertEqualNumber(t, len(deleteCRDActions), 20)
 
 	for _, action := range deleteKubeActions {
 		switch action.Resource.Resource {
@@ -743,3 +1070,46 @@ func newSecret(name, namespace string) *corev1.Secret {
 		Data: map[string][]byte{},
 	}
 }
+
+func TestCleanPodDisruptionBudgets(t *testing.T) {
+	newPDB := func(name string, labels map[string]string) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "open-cluster-management-hub", Labels: labels},
+		}
+	}
+	hubLabels := map[string]string{helpers.HubComponentLabelKey: "true"}
+	kubeClient := fakekube.NewSimpleClientset(
+		newPDB("testhub-registration-controller", hubLabels),
+		newPDB("testhub-addon-manager-controller", hubLabels),
+		newPDB("testhub-registration-webhook", hubLabels),
+		newPDB("others", map[string]string{"app": "others"}),
+	)
+	r := &runtimeReconcile{kubeClient: kubeClient, recorder: eventstesting.NewTestingEventRecorder(t)}
+
+	// only the pod disruption budgets of the hub components not kept are deleted
+	if err := r.cleanPodDisruptionBudgets(ctx, "open-cluster-management-hub",
+		sets.New[string]("testhub-registration-controller")); err != nil {
+		t.Fatal(err)
+	}
+	var deleted []string
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() == "delete" {
+			deleted = append(deleted, action.(clienttesting.DeleteActionImpl).Name)
+		}
+	}
+	if !reflect.DeepEqual(deleted, []string{"testhub-addon-manager-controller", "testhub-registration-webhook"}) {
+		t.Errorf("unexpected deleted pod disruption budgets %v", deleted)
+	}
+
+	// nothing is deleted if no pod disruption budget exists
+	kubeClient = fakekube.NewSimpleClientset()
+	r.kubeClient = kubeClient
+	if err := r.cleanPodDisruptionBudgets(ctx, "open-cluster-management-hub", nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() != "list" {
+			t.Errorf("unexpected action %v", action)
+		}
+	}
+}
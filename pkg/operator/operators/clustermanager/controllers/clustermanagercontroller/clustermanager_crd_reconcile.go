@@ -37,9 +37,13 @@ var (
 
 	// crdResourceFiles should be deployed in the hub cluster
 	hubCRDResourceFiles = []string{
+		"cluster-manager/hub/0000_00_admission.cluster.open-cluster-management.io_clusteradmissionpolicies.crd.yaml",
+		"cluster-manager/hub/0000_00_admission.work.open-cluster-management.io_manifestworkconfigs.crd.yaml",
+		"cluster-manager/hub/0000_00_admission.work.open-cluster-management.io_manifestworkdefaultingpolicies.crd.yaml",
 		"cluster-manager/hub/0000_00_addon.open-cluster-management.io_clustermanagementaddons.crd.yaml",
 		"cluster-manager/hub/0000_00_clusters.open-cluster-management.io_managedclusters.crd.yaml",
 		"cluster-manager/hub/0000_00_clusters.open-cluster-management.io_managedclustersets.crd.yaml",
+		"cluster-manager/hub/0000_00_debug.work.open-cluster-management.io_resourcereads.crd.yaml",
 		"cluster-manager/hub/0000_00_work.open-cluster-management.io_manifestworks.crd.yaml",
 		"cluster-manager/hub/0000_00_work.open-cluster-management.io_manifestworkreplicasets.crd.yaml",
 		"cluster-manager/hub/0000_01_addon.open-cluster-management.io_managedclusteraddons.crd.yaml",
@@ -68,7 +72,7 @@ func (c *crdReconcile) reconcile(ctx context.Context, cm *operatorapiv1.ClusterM
 		crdmanager.EqualV1,
 	)
 
-	if err := crdManager.Apply(ctx,
+	err := crdManager.Apply(ctx,
 		func(name string) ([]byte, error) {
 			template, err := manifests.ClusterManagerManifestFiles.ReadFile(name)
 			if err != nil {
@@ -78,7 +82,9 @@ func (c *crdReconcile) reconcile(ctx context.Context, cm *operatorapiv1.ClusterM
 			helpers.SetRelatedResourcesStatusesWithObj(&cm.Status.RelatedResources, objData)
 			return objData, nil
 		},
-		hubCRDResourceFiles...); err != nil {
+		hubCRDResourceFiles...)
+	crdManager.ReportVersionSkews(cm.Name, &cm.Status.Conditions)
+	if err != nil {
 		meta.SetStatusCondition(&cm.Status.Conditions, metav1.Condition{
 			Type:    operatorapiv1.ConditionClusterManagerApplied,
 			Status:  metav1.ConditionFalse,
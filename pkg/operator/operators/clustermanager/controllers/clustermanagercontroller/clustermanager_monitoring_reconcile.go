@@ -0,0 +1,263 @@
+package clustermanagercontroller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/manifests"
+	"open-cluster-management.io/ocm/pkg/common/metrics/dashboards"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+const (
+	// metricsServiceSuffix is the suffix of the names of the services exposing the metrics of the hub components.
+	metricsServiceSuffix = "-metrics"
+	// metricsPort is the port the hub components serve the metrics on.
+	metricsPort = 8443
+)
+
+// metricsComponent is a hub component scraped by a ServiceMonitor.
+type metricsComponent struct {
+	// name is the name of the component, the service of the metrics is named after it.
+	name string
+	// app is the app label of the pods of the component.
+	app string
+}
+
+// metricsComponents returns the hub components deployed by the cluster manager serving the metrics.
+func metricsComponents(config manifests.HubConfig) []metricsComponent {
+	components := []metricsComponent{
+		{name: "registration-controller", app: "clustermanager-registration-controller"},
+		{name: "placement-controller", app: "clustermanager-placement-controller"},
+	}
+	if config.AddOnManagerEnabled {
+		components = append(components, metricsComponent{name: "addon-manager-controller", app: "clustermanager-addon-manager-controller"})
+	}
+	if config.MWReplicaSetEnabled {
+		components = append(components, metricsComponent{name: "work-controller", app: config.ClusterManagerName + "-work-controller"})
+	}
+	return components
+}
+
+// monitoringReconcile applies the Prometheus alert rules and the Grafana dashboards generated from the metrics of
+// the hub components and the agents on the management cluster if they are enabled by the monitoring annotation of
+// the cluster manager, together with the services and the ServiceMonitors to scrape the metrics of the hub
+// components. The applied resources are recorded in the related resources of the cluster manager, and the ones not
+// required any more, e.g. in the former namespace of the annotation, are removed.
+type monitoringReconcile struct {
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	monitoring    *helpers.Monitoring
+	recorder      events.Recorder
+}
+
+func (c *monitoringReconcile) reconcile(ctx context.Context, cm *operatorapiv1.ClusterManager,
+	config manifests.HubConfig) (*operatorapiv1.ClusterManager, reconcileState, error) {
+	namespace := c.monitoring.NamespaceOr(config.ClusterManagerNamespace)
+
+	var required []operatorapiv1.RelatedResourceMeta
+	var errs []error
+	if c.monitoring != nil && c.monitoring.AlertRules {
+		rule, err := dashboards.PrometheusRule(namespace, helpers.MonitoringAlertRulesName)
+		if err == nil {
+			err = c.applyUnstructured(ctx, dashboards.PrometheusRuleGVR, rule)
+		}
+		errs = append(errs, err)
+		required = append(required, monitoringRelatedResource(dashboards.PrometheusRuleGVR, namespace, helpers.MonitoringAlertRulesName))
+	}
+	if c.monitoring != nil && c.monitoring.Dashboards {
+		configMaps, err := dashboards.DashboardConfigMaps(namespace)
+		errs = append(errs, err)
+		for _, configMap := range configMaps {
+			if _, _, err := resourceapply.ApplyConfigMap(ctx, c.kubeClient.CoreV1(), c.recorder, configMap); err != nil {
+				errs = append(errs, fmt.Errorf("failed to apply dashboard configmap %s/%s: %v", namespace, configMap.Name, err))
+			}
+			required = append(required, monitoringRelatedResource(
+				corev1.SchemeGroupVersion.WithResource("configmaps"), namespace, configMap.Name))
+		}
+	}
+	// both the alert rules and the dashboards get the metrics of the hub components from the ServiceMonitors.
+	if c.monitoring != nil && (c.monitoring.AlertRules || c.monitoring.Dashboards) {
+		for _, component := range metricsComponents(config) {
+			service := metricsService(config, component)
+			if _, _, err := resourceapply.ApplyService(ctx, c.kubeClient.CoreV1(), c.recorder, service); err != nil {
+				errs = append(errs, fmt.Errorf("failed to apply service %s/%s: %v", service.Namespace, service.Name, err))
+			}
+			required = append(required, monitoringRelatedResource(
+				corev1.SchemeGroupVersion.WithResource("services"), service.Namespace, service.Name))
+
+			monitor := dashboards.ServiceMonitor(namespace, service.Name, service.Namespace, service.Labels)
+			err := c.applyUnstructured(ctx, dashboards.ServiceMonitorGVR, monitor)
+			if errors.IsNotFound(err) {
+				// the ServiceMonitor crd is not installed, the metrics may be scraped without the prometheus-operator
+				// with the services.
+				continue
+			}
+			errs = append(errs, err)
+			required = append(required, monitoringRelatedResource(dashboards.ServiceMonitorGVR, namespace, service.Name))
+		}
+	}
+
+	for _, relatedResource := range required {
+		helpers.SetRelatedResourcesStatuses(&cm.Status.RelatedResources, relatedResource)
+	}
+	errs = append(errs, c.deleteStale(ctx, cm, required))
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		meta.SetStatusCondition(&cm.Status.Conditions, metav1.Condition{
+			Type:    operatorapiv1.ConditionClusterManagerApplied,
+			Status:  metav1.ConditionFalse,
+			Reason:  "MonitoringApplyFailed",
+			Message: fmt.Sprintf("Failed to apply the alert rules and dashboards: %v", err),
+		})
+		// the monitoring manifests are optional, the other components are still applied
+		return cm, reconcileContinue, err
+	}
+	return cm, reconcileContinue, nil
+}
+
+func (c *monitoringReconcile) clean(ctx context.Context, cm *operatorapiv1.ClusterManager,
+	config manifests.HubConfig) (*operatorapiv1.ClusterManager, reconcileState, error) {
+	if err := c.deleteStale(ctx, cm, nil); err != nil {
+		return cm, reconcileStop, err
+	}
+	return cm, reconcileContinue, nil
+}
+
+// deleteStale deletes the monitoring resources recorded in the related resources of the cluster manager but not
+// required any more, so nothing is requested if they have never been applied.
+func (c *monitoringReconcile) deleteStale(ctx context.Context, cm *operatorapiv1.ClusterManager,
+	required []operatorapiv1.RelatedResourceMeta) error {
+	var errs []error
+	for _, relatedResource := range slices.Clone(cm.Status.RelatedResources) {
+		if !isMonitoringResource(relatedResource) || slices.Contains(required, relatedResource) {
+			continue
+		}
+		if err := c.delete(ctx, relatedResource); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		helpers.RemoveRelatedResourcesStatuses(&cm.Status.RelatedResources, relatedResource)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *monitoringReconcile) delete(ctx context.Context, relatedResource operatorapiv1.RelatedResourceMeta) error {
+	namespace, name := relatedResource.Namespace, relatedResource.Name
+	var err error
+	switch {
+	case relatedResource.Group == "" && relatedResource.Resource == "configmaps":
+		err = c.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	case relatedResource.Group == "" && relatedResource.Resource == "services":
+		err = c.kubeClient.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	default:
+		gvr := schema.GroupVersionResource{
+			Group: relatedResource.Group, Version: relatedResource.Version, Resource: relatedResource.Resource}
+		err = c.dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	}
+	switch {
+	case errors.IsNotFound(err) || meta.IsNoMatchError(err):
+	case err != nil:
+		return fmt.Errorf("failed to delete %s %s/%s: %v", relatedResource.Resource, namespace, name, err)
+	default:
+		c.recorder.Eventf("MonitoringResourceDeleted", "%s %s/%s is deleted", relatedResource.Resource, namespace, name)
+	}
+	return nil
+}
+
+func (c *monitoringReconcile) applyUnstructured(ctx context.Context, gvr schema.GroupVersionResource,
+	required *unstructured.Unstructured) error {
+	kind, namespace, name := required.GetKind(), required.GetNamespace(), required.GetName()
+	client := c.dynamicClient.Resource(gvr).Namespace(namespace)
+	existing, err := client.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(ctx, required, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create %s %s/%s: %w", gvr.Resource, namespace, name, err)
+		}
+		c.recorder.Eventf(kind+"Created", "%s %s/%s is created", gvr.Resource, namespace, name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s/%s: %v", gvr.Resource, namespace, name, err)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Object["spec"], required.Object["spec"]) {
+		return nil
+	}
+	existing = existing.DeepCopy()
+	existing.Object["spec"] = required.Object["spec"]
+	if _, err := client.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update %s %s/%s: %v", gvr.Resource, namespace, name, err)
+	}
+	c.recorder.Eventf(kind+"Updated", "%s %s/%s is updated", gvr.Resource, namespace, name)
+	return nil
+}
+
+// metricsService returns the service exposing the metrics of the hub component in the cluster manager namespace.
+func metricsService(config manifests.HubConfig, component metricsComponent) *corev1.Service {
+	name := config.ClusterManagerName + "-" + component.name + metricsServiceSuffix
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: config.ClusterManagerNamespace,
+			Name:      name,
+			Labels:    map[string]string{"app": name},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": component.app},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       dashboards.MetricsPortName,
+					Port:       metricsPort,
+					TargetPort: intstr.FromInt32(metricsPort),
+				},
+			},
+		},
+	}
+}
+
+func monitoringRelatedResource(gvr schema.GroupVersionResource, namespace, name string) operatorapiv1.RelatedResourceMeta {
+	return operatorapiv1.RelatedResourceMeta{
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: namespace,
+		Name:      name,
+	}
+}
+
+// isMonitoringResource returns true if the related resource is applied by the monitoring reconcile.
+func isMonitoringResource(relatedResource operatorapiv1.RelatedResourceMeta) bool {
+	switch {
+	case relatedResource.Group == dashboards.PrometheusRuleGVR.Group:
+		return true
+	case relatedResource.Group == "" && relatedResource.Resource == "configmaps":
+		for _, dashboard := range dashboards.Dashboards() {
+			if relatedResource.Name == dashboards.DashboardConfigMapName(dashboard.UID) {
+				return true
+			}
+		}
+		return false
+	case relatedResource.Group == "" && relatedResource.Resource == "services":
+		return strings.HasSuffix(relatedResource.Name, metricsServiceSuffix)
+	default:
+		return false
+	}
+}
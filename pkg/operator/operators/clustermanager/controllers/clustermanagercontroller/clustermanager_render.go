@@ -0,0 +1,94 @@
+package clustermanagercontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/typed/migration/v1alpha1"
+
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+// RenderClients are the clients the reconcile of the cluster manager is run against to render the manifests.
+type RenderClients struct {
+	KubeClient         kubernetes.Interface
+	APIExtensionClient apiextensionsclient.Interface
+	MigrationClient    migrationclient.StorageVersionMigrationsGetter
+	DynamicClient      dynamic.Interface
+	OperatorClient     operatorclient.Interface
+}
+
+// RenderOptions are the options of the operator the manifests of the cluster manager are rendered with.
+type RenderOptions struct {
+	OperatorNamespace     string
+	DeploymentReplicas    int32
+	RestrictedPodSecurity bool
+}
+
+// RenderManifests runs the reconcile of the cluster manager once against the given clients, so the objects the
+// operator applies on the hub cluster are recorded by the clients instead of being applied. The clients are
+// expected to be in-memory clients, so the secrets synced from the operator namespace and the CA bundle generated
+// on the hub cluster are not rendered. Only the Default mode is supported.
+func RenderManifests(ctx context.Context, clusterManager *operatorapiv1.ClusterManager, clients RenderClients,
+	options RenderOptions) error {
+	if helpers.IsHosted(clusterManager.Spec.DeployOption.Mode) {
+		return fmt.Errorf("rendering the manifests of the cluster manager in %s mode is not supported",
+			clusterManager.Spec.DeployOption.Mode)
+	}
+
+	clusterManager = clusterManager.DeepCopy()
+	clusterManager.Finalizers = append(clusterManager.Finalizers, clusterManagerFinalizer)
+	clusterManager.DeletionTimestamp = nil
+	clusterManager, err := clients.OperatorClient.OperatorV1().ClusterManagers().Create(ctx, clusterManager, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	clusterManagerIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := clusterManagerIndexer.Add(clusterManager); err != nil {
+		return err
+	}
+	recorder := events.NewInMemoryRecorder("render")
+
+	controller := &clusterManagerController{
+		patcher: patcher.NewPatcher[
+			*operatorapiv1.ClusterManager, operatorapiv1.ClusterManagerSpec, operatorapiv1.ClusterManagerStatus](
+			clients.OperatorClient.OperatorV1().ClusterManagers()),
+		clusterManagerLister: operatorlister.NewClusterManagerLister(clusterManagerIndexer),
+		operatorKubeClient:   clients.KubeClient,
+		operatorKubeconfig:   &rest.Config{},
+		dynamicClient:        clients.DynamicClient,
+		configMapLister: corev1listers.NewConfigMapLister(
+			cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		recorder: recorder,
+		cache:    resourceapply.NewResourceCache(),
+		ensureSAKubeconfigs: func(ctx context.Context, clusterManagerName, clusterManagerNamespace string,
+			hubConfig *rest.Config, hubClient, managementClient kubernetes.Interface, recorder events.Recorder,
+			mwctrEnabled, addonManagerEnabled bool) error {
+			return nil
+		},
+		generateHubClusterClients: func(hubConfig *rest.Config) (kubernetes.Interface, apiextensionsclient.Interface,
+			migrationclient.StorageVersionMigrationsGetter, error) {
+			return clients.KubeClient, clients.APIExtensionClient, clients.MigrationClient, nil
+		},
+		deploymentReplicas:    options.DeploymentReplicas,
+		operatorNamespace:     options.OperatorNamespace,
+		restrictedPodSecurity: options.RestrictedPodSecurity,
+	}
+
+	return controller.sync(ctx, helpers.NewRenderSyncContext(clusterManager.Name, recorder))
+}
@@ -0,0 +1,211 @@
+package clustermanagercontroller
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+	"open-cluster-management.io/ocm/pkg/operator/render"
+)
+
+func renderManifests(clusterManager *operatorapiv1.ClusterManager, options RenderOptions) ([]runtime.Object, error) {
+	clients := render.NewClients()
+	if err := RenderManifests(context.TODO(), clusterManager, RenderClients{
+		KubeClient:         clients.Kube,
+		APIExtensionClient: clients.APIExtensions,
+		MigrationClient:    clients.Migration.MigrationV1alpha1(),
+		DynamicClient:      clients.Dynamic,
+		OperatorClient:     clients.Operator,
+	}, options); err != nil {
+		return nil, err
+	}
+	return clients.Objects()
+}
+
+func TestRenderManifests(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	objects, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 3})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var crds, deployments, webhooks int
+	for _, object := range objects {
+		if object.GetObjectKind().GroupVersionKind().Kind == "" {
+			t.Errorf("Expected the kind of the rendered object %v", object)
+		}
+		switch o := object.(type) {
+		case *apiextensionsv1.CustomResourceDefinition:
+			crds++
+		case *appsv1.Deployment:
+			deployments++
+			if *o.Spec.Replicas != 3 {
+				t.Errorf("Expected 3 replicas of deployment %s, but got %d", o.Name, *o.Spec.Replicas)
+			}
+			ensureObject(t, o, clusterManager)
+		case *admissionv1.ValidatingWebhookConfiguration, *admissionv1.MutatingWebhookConfiguration:
+			webhooks++
+		}
+	}
+	testingcommon.AssertEqualNumber(t, crds, 16)
+	testingcommon.AssertEqualNumber(t, deployments, 6)
+	testingcommon.AssertEqualNumber(t, webhooks, 6)
+
+	clusterManager.Spec.DeployOption.Mode = operatorapiv1.InstallModeHosted
+	if _, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace}); err == nil {
+		t.Errorf("Expected error when render in hosted mode")
+	}
+}
+
+func TestRenderManifestsRestrictedPodSecurity(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	objects, err := renderManifests(clusterManager, RenderOptions{
+		OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 1, RestrictedPodSecurity: true})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var namespaces, deployments int
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *corev1.Namespace:
+			namespaces++
+			if o.Labels[helpers.PodSecurityEnforceLabel] != helpers.PodSecurityRestricted {
+				t.Errorf("Expected restricted pod security enforced on namespace %s, but got %v", o.Name, o.Labels)
+			}
+		case *appsv1.Deployment:
+			deployments++
+			securityContext := o.Spec.Template.Spec.SecurityContext
+			if securityContext == nil || securityContext.SeccompProfile == nil ||
+				securityContext.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+				t.Errorf("Expected RuntimeDefault seccomp profile of deployment %s, but got %v", o.Name, securityContext)
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, namespaces, 1)
+	testingcommon.AssertEqualNumber(t, deployments, 6)
+}
+
+func TestRenderManifestsWorkRestrictions(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{
+		helpers.WorkRestrictionsAnnotation: `{"forbiddenKinds":["Namespace","ClusterRoleBinding.rbac.authorization.k8s.io"],"requireExecutor":true}`,
+	}
+	objects, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 1})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var webhooks int
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok || deployment.Name != "testhub-work-webhook" {
+			continue
+		}
+		webhooks++
+		args := sets.New(deployment.Spec.Template.Spec.Containers[0].Args...)
+		if !args.HasAll("--forbidden-kinds=Namespace,ClusterRoleBinding.rbac.authorization.k8s.io", "--require-executor") {
+			t.Errorf("Expected the work restrictions in the args of the work webhook, but got %v", args.UnsortedList())
+		}
+	}
+	testingcommon.AssertEqualNumber(t, webhooks, 1)
+
+	clusterManager.Annotations[helpers.WorkRestrictionsAnnotation] = `{"forbiddenKinds":[""]}`
+	if _, err := helpers.GetWorkRestrictions(clusterManager); err == nil {
+		t.Errorf("Expected error with the empty forbidden kind")
+	}
+}
+
+func TestRenderManifestsSPIFFETrustDomain(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{helpers.SPIFFETrustDomainAnnotation: "example.org"}
+	objects, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 1})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var controllers int
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok || deployment.Name != "testhub-registration-controller" {
+			continue
+		}
+		controllers++
+		if !sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--spiffe-trust-domain=example.org") {
+			t.Errorf("Expected the spiffe trust domain in the args of the registration controller, but got %v",
+				deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+	testingcommon.AssertEqualNumber(t, controllers, 1)
+}
+
+func TestRenderManifestsShardBy(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{helpers.HighAvailabilityAnnotation: `{"replicas": 3, "shardBy": "cluster"}`}
+	clusterManager.Spec.WorkConfiguration = &operatorapiv1.WorkConfiguration{
+		FeatureGates: []operatorapiv1.FeatureGate{{Feature: "ManifestWorkReplicaSet", Mode: operatorapiv1.FeatureGateModeTypeEnable}},
+	}
+	objects, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 1})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var controllers int
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		args := sets.New(deployment.Spec.Template.Spec.Containers[0].Args...)
+		switch deployment.Name {
+		case "testhub-registration-controller", "testhub-work-controller":
+			controllers++
+			if !args.HasAll("--shard-by=cluster", "--disable-leader-election") {
+				t.Errorf("Expected the sharding args in %s, but got %v", deployment.Name, args.UnsortedList())
+			}
+		default:
+			if args.Has("--shard-by=cluster") || args.Has("--disable-leader-election") {
+				t.Errorf("Expected no sharding args in %s, but got %v", deployment.Name, args.UnsortedList())
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, controllers, 2)
+}
+
+func TestRenderManifestsHubCABundleConfigMap(t *testing.T) {
+	clusterManager := newClusterManager("testhub")
+	clusterManager.Annotations = map[string]string{helpers.HubCABundleConfigMapAnnotation: "hub-ca-bundle"}
+	objects, err := renderManifests(clusterManager, RenderOptions{OperatorNamespace: helpers.DefaultComponentNamespace, DeploymentReplicas: 1})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var controllers int
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok || deployment.Name != "testhub-registration-controller" {
+			continue
+		}
+		controllers++
+		if !sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--hub-ca-bundle-configmap=hub-ca-bundle") {
+			t.Errorf("Expected the hub ca bundle configmap in the args of the registration controller, but got %v",
+				deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+	testingcommon.AssertEqualNumber(t, controllers, 1)
+
+	clusterManager.Annotations[helpers.HubCABundleConfigMapAnnotation] = "Invalid_Name"
+	if _, err := helpers.GetHubCABundleConfigMap(clusterManager); err == nil {
+		t.Errorf("Expected error with the invalid configmap name")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -120,6 +121,12 @@ func (c *runtimeReconcile) reconcile(ctx context.Context, cm *operatorapiv1.Clus
 		}
 	}
 
+	// the config is validated when the cluster manager is synced
+	highAvailability, err := helpers.HighAvailability(cm)
+	if err != nil {
+		return cm, reconcileStop, err
+	}
+
 	var progressingDeployments []string
 	deployResources := deploymentFiles
 	if config.AddOnManagerEnabled {
@@ -128,6 +135,9 @@ func (c *runtimeReconcile) reconcile(ctx context.Context, cm *operatorapiv1.Clus
 	if config.MWReplicaSetEnabled {
 		deployResources = append(deployResources, mwReplicaSetDeploymentFiles...)
 	}
+	// the pod disruption budgets of the deployments not applied any more are removed once all deployments are applied
+	pdbs := sets.New[string]()
+	deploymentsApplied := true
 	for _, file := range deployResources {
 		updatedDeployment, currentGeneration, err := helpers.ApplyDeployment(
 			ctx,
@@ -144,18 +154,33 @@ func (c *runtimeReconcile) reconcile(ctx context.Context, cm *operatorapiv1.Clus
 				return objData, nil
 			},
 			c.recorder,
-			file)
+			file,
+			highAvailability.ApplyToDeployment)
 		if err != nil {
 			appliedErrs = append(appliedErrs, err)
+			deploymentsApplied = false
 			continue
 		}
 		helpers.SetGenerationStatuses(&cm.Status.Generations, currentGeneration)
 
+		if pdb := highAvailability.PodDisruptionBudgetFor(updatedDeployment); pdb != nil {
+			if _, _, err := resourceapply.ApplyPodDisruptionBudget(ctx, c.kubeClient.PolicyV1(), c.recorder, pdb); err != nil {
+				appliedErrs = append(appliedErrs, fmt.Errorf("failed to apply pod disruption budget %s/%s: %v", pdb.Namespace, pdb.Name, err))
+			}
+			pdbs.Insert(pdb.Name)
+		}
+
 		if updatedDeployment.Generation != updatedDeployment.Status.ObservedGeneration || *updatedDeployment.Spec.Replicas != updatedDeployment.Status.ReadyReplicas {
 			progressingDeployments = append(progressingDeployments, updatedDeployment.Name)
 		}
 	}
 
+	if deploymentsApplied {
+		if err := c.cleanPodDisruptionBudgets(ctx, config.ClusterManagerNamespace, pdbs); err != nil {
+			appliedErrs = append(appliedErrs, err)
+		}
+	}
+
 	if len(progressingDeployments) > 0 {
 		meta.SetStatusCondition(&cm.Status.Conditions, metav1.Condition{
 			Type:    operatorapiv1.ConditionProgressing,
@@ -185,8 +210,34 @@ func (c *runtimeReconcile) reconcile(ctx context.Context, cm *operatorapiv1.Clus
 	return cm, reconcileContinue, nil
 }
 
+// cleanPodDisruptionBudgets deletes the existing pod disruption budgets of the hub components except the given ones.
+func (c *runtimeReconcile) cleanPodDisruptionBudgets(ctx context.Context, namespace string, keep sets.Set[string]) error {
+	pdbs, err := c.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: helpers.HubComponentLabelKey + "=true",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pod disruption budgets in %s: %v", namespace, err)
+	}
+
+	var errs []error
+	for _, pdb := range pdbs.Items {
+		if keep.Has(pdb.Name) {
+			continue
+		}
+		if _, _, err := resourceapply.DeletePodDisruptionBudget(ctx, c.kubeClient.PolicyV1(), c.recorder, &pdb); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete pod disruption budget %s/%s: %v", pdb.Namespace, pdb.Name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 func (c *runtimeReconcile) clean(ctx context.Context, cm *operatorapiv1.ClusterManager,
 	config manifests.HubConfig) (*operatorapiv1.ClusterManager, reconcileState, error) {
+	// the pod disruption budgets are not in the static files, remove them explicitly
+	if err := c.cleanPodDisruptionBudgets(ctx, config.ClusterManagerNamespace, nil); err != nil {
+		return cm, reconcileStop, err
+	}
+
 	// Remove All Static files
 	managementResources := []string{namespaceResource} // because namespace is removed, we don't need to remove deployments explicitly
 	return cleanResources(ctx, c.kubeClient, cm, config, managementResources...)
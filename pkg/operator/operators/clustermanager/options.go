@@ -7,6 +7,7 @@ import (
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -26,6 +27,7 @@ type Options struct {
 	SkipRemoveCRDs                bool
 	ControlPlaneNodeLabelSelector string
 	DeploymentReplicas            int32
+	RestrictedPodSecurity         bool
 }
 
 // RunClusterManagerOperator starts a new cluster manager operator
@@ -36,6 +38,11 @@ func (o *Options) RunClusterManagerOperator(ctx context.Context, controllerConte
 		return err
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+
 	// kubeInformer is for 3 usages: configmapInformer, secretInformer, deploynmentInformer
 	// After we introduced hosted mode, the hub components could be installed in a customized
 	// namespace.(Before that, it only inform from "open-cluster-management-hub" namespace)
@@ -71,6 +78,7 @@ func (o *Options) RunClusterManagerOperator(ctx context.Context, controllerConte
 	clusterManagerController := clustermanagercontroller.NewClusterManagerController(
 		kubeClient,
 		controllerContext.KubeConfig,
+		dynamicClient,
 		operatorClient.OperatorV1().ClusterManagers(),
 		operatorInformer.Operator().V1().ClusterManagers(),
 		kubeInformer.Apps().V1().Deployments(),
@@ -80,6 +88,7 @@ func (o *Options) RunClusterManagerOperator(ctx context.Context, controllerConte
 		o.ControlPlaneNodeLabelSelector,
 		o.DeploymentReplicas,
 		controllerContext.OperatorNamespace,
+		o.RestrictedPodSecurity,
 	)
 
 	statusController := clustermanagerstatuscontroller.NewClusterManagerStatusController(
@@ -0,0 +1,83 @@
+package clustermanager
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+	"open-cluster-management.io/ocm/pkg/operator/operators/clustermanager/controllers/clustermanagercontroller"
+	"open-cluster-management.io/ocm/pkg/operator/render"
+)
+
+// RenderOptions are the options to render the manifests of a cluster manager instead of applying them, so that
+// the manifests can be applied by other tools.
+type RenderOptions struct {
+	File                  string
+	OutputDir             string
+	OperatorNamespace     string
+	DeploymentReplicas    int32
+	RestrictedPodSecurity bool
+	FIPSMode              bool
+}
+
+func NewRenderOptions() *RenderOptions {
+	return &RenderOptions{
+		OperatorNamespace: helpers.DefaultComponentNamespace,
+	}
+}
+
+func (o *RenderOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.File, "file", "f", o.File, "The file of the ClusterManager to render, - to read it from stdin.")
+	flags.StringVar(&o.OutputDir, "output-dir", o.OutputDir,
+		"The directory to write the manifests into, the manifests are written to stdout if not set.")
+	flags.StringVar(&o.OperatorNamespace, "operator-namespace", o.OperatorNamespace, "The namespace of the operator.")
+	flags.Int32Var(&o.DeploymentReplicas, "deployment-replicas", o.DeploymentReplicas,
+		"Number of deployment replicas, 1 replica is rendered if not set")
+	flags.BoolVar(&o.RestrictedPodSecurity, "restricted-pod-security", o.RestrictedPodSecurity,
+		"If set, will render the hub components compliant with the restricted Pod Security Standard")
+	flags.BoolVar(&o.FIPSMode, "fips-mode", o.FIPSMode,
+		"If set, will render the components running in the FIPS compliance mode")
+}
+
+// Render writes the manifests of the cluster manager in the file to the output dir or out.
+func (o *RenderOptions) Render(ctx context.Context, in io.Reader, out io.Writer) error {
+	if len(o.File) == 0 {
+		return fmt.Errorf("the file of the ClusterManager is required")
+	}
+
+	clusterManager := &operatorapiv1.ClusterManager{}
+	if err := render.ReadInput(o.File, in, clusterManager); err != nil {
+		return err
+	}
+
+	if o.FIPSMode {
+		fips.Enable()
+	}
+
+	clients := render.NewClients()
+	if err := clustermanagercontroller.RenderManifests(ctx, clusterManager, clustermanagercontroller.RenderClients{
+		KubeClient:         clients.Kube,
+		APIExtensionClient: clients.APIExtensions,
+		MigrationClient:    clients.Migration.MigrationV1alpha1(),
+		DynamicClient:      clients.Dynamic,
+		OperatorClient:     clients.Operator,
+	}, clustermanagercontroller.RenderOptions{
+		OperatorNamespace:     o.OperatorNamespace,
+		DeploymentReplicas:    o.DeploymentReplicas,
+		RestrictedPodSecurity: o.RestrictedPodSecurity,
+	}); err != nil {
+		return err
+	}
+
+	objects, err := clients.Objects()
+	if err != nil {
+		return err
+	}
+	return render.WriteObjects(objects, o.OutputDir, out)
+}
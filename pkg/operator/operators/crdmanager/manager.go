@@ -52,6 +52,9 @@ type Manager[T CRD] struct {
 	client  crdClient[T]
 	equal   func(old, new T) bool
 	version *versionutil.Version
+	// applied and skews are the names of the crds applied by the last Apply and their version skews.
+	applied []string
+	skews   []VersionSkew
 }
 
 type crdClient[T CRD] interface {
@@ -177,6 +180,7 @@ func (m *Manager[T]) Clean(ctx context.Context, skip bool, manifests resourceapp
 
 func (m *Manager[T]) Apply(ctx context.Context, manifests resourceapply.AssetFunc, files ...string) error {
 	var errs []error
+	m.applied, m.skews = nil, nil
 
 	for _, file := range files {
 		objBytes, err := manifests(file)
@@ -205,6 +209,7 @@ func (m *Manager[T]) applyOne(ctx context.Context, required T) error {
 	if err != nil {
 		return err
 	}
+	m.applied = append(m.applied, accessor.GetName())
 	existing, err := m.client.Get(ctx, accessor.GetName(), metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
 		_, err := m.client.Create(ctx, required, metav1.CreateOptions{})
@@ -224,6 +229,15 @@ func (m *Manager[T]) applyOne(ctx context.Context, required T) error {
 		return nil
 	}
 
+	// the objects still stored in the versions which are not served by the required crd must be migrated before the
+	// crd is upgraded, otherwise they are not accessible anymore.
+	if unserved := unservedStoredVersions(existing, required); len(unserved) > 0 {
+		m.skews = append(m.skews, VersionSkew{Name: accessor.GetName(), Reason: ReasonCRDUpgradeBlocked, StoredVersions: unserved})
+		klog.Warningf("crd %s is not updated to version %s since the stored versions %s are not served",
+			accessor.GetName(), m.version.String(), strings.Join(unserved, ","))
+		return nil
+	}
+
 	existingAccessor, err := meta.Accessor(existing)
 	if err != nil {
 		return err
@@ -275,7 +289,11 @@ func (m *Manager[T]) shouldUpdate(old, new T) (bool, error) {
 	}
 
 	// do not update when version is higher
-	return cnt > 0, nil
+	if cnt < 0 {
+		m.skews = append(m.skews, VersionSkew{Name: accessor.GetName(), Reason: ReasonCRDVersionNewer, InstalledVersion: existingVersion})
+		return false, nil
+	}
+	return true, nil
 }
 
 func EqualV1(old, new *apiextensionsv1.CustomResourceDefinition) bool {
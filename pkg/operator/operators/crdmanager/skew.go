@@ -0,0 +1,128 @@
+package crdmanager
+
+import (
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// ConditionVersionSkew is true if the installed crds are skewed from the crds bundled with the operator.
+	ConditionVersionSkew = "VersionSkew"
+	// ReasonCRDVersionNewer is set if the installed crds are applied by an operator of a newer version, they are not
+	// downgraded.
+	ReasonCRDVersionNewer = "CRDVersionNewer"
+	// ReasonCRDUpgradeBlocked is set if the bundled crds do not serve the versions the objects of the installed crds
+	// are still stored in, the crds are not upgraded until the objects are migrated.
+	ReasonCRDUpgradeBlocked = "CRDUpgradeBlocked"
+)
+
+var versionSkew = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+	Subsystem:      "operator",
+	Name:           "crd_version_skew",
+	StabilityLevel: k8smetrics.ALPHA,
+	Help:           "Whether the installed crd is skewed from the crd bundled with the operator, 1 if it is skewed.",
+}, []string{"owner", "crd", "reason"})
+
+func init() {
+	legacyregistry.MustRegister(versionSkew)
+}
+
+// VersionSkew is the skew between an installed crd and the crd bundled with the operator.
+type VersionSkew struct {
+	// Name is the name of the crd.
+	Name string
+	// Reason is the reason of the skew, ReasonCRDVersionNewer or ReasonCRDUpgradeBlocked.
+	Reason string
+	// InstalledVersion is the ocm version the installed crd is applied with.
+	InstalledVersion string
+	// StoredVersions are the stored versions of the installed crd which are not served by the bundled crd.
+	StoredVersions []string
+}
+
+func (s VersionSkew) String() string {
+	if s.Reason == ReasonCRDUpgradeBlocked {
+		return fmt.Sprintf("crd %s is not upgraded since the stored versions %s are not served by the new crd",
+			s.Name, strings.Join(s.StoredVersions, ","))
+	}
+	return fmt.Sprintf("crd %s is applied by a newer operator of version %s", s.Name, s.InstalledVersion)
+}
+
+// ReportVersionSkews records the version skew metric of the crds applied by the last Apply for the owner, which is
+// the cluster manager or the klusterlet applying the crds, and sets the VersionSkew condition in the conditions of
+// the owner. The condition is removed if there is no skew.
+func (m *Manager[T]) ReportVersionSkews(owner string, conditions *[]metav1.Condition) {
+	skewed := map[string]string{}
+	for _, skew := range m.skews {
+		skewed[skew.Name] = skew.Reason
+	}
+	for _, name := range m.applied {
+		for _, reason := range []string{ReasonCRDVersionNewer, ReasonCRDUpgradeBlocked} {
+			value := 0.0
+			if skewed[name] == reason {
+				value = 1
+			}
+			versionSkew.WithLabelValues(owner, name, reason).Set(value)
+		}
+	}
+
+	if len(m.skews) == 0 {
+		meta.RemoveStatusCondition(conditions, ConditionVersionSkew)
+		return
+	}
+	reason := ReasonCRDVersionNewer
+	var messages []string
+	for _, skew := range m.skews {
+		if skew.Reason == ReasonCRDUpgradeBlocked {
+			reason = ReasonCRDUpgradeBlocked
+		}
+		messages = append(messages, skew.String())
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    ConditionVersionSkew,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: strings.Join(messages, "; "),
+	})
+}
+
+// unservedStoredVersions returns the stored versions of the existing crd which are not served by the required crd.
+func unservedStoredVersions[T CRD](existing, required T) []string {
+	served := sets.New[string]()
+	var storedVersions []string
+	switch e := any(existing).(type) {
+	case *apiextensionsv1.CustomResourceDefinition:
+		storedVersions = e.Status.StoredVersions
+		for _, v := range any(required).(*apiextensionsv1.CustomResourceDefinition).Spec.Versions {
+			if v.Served {
+				served.Insert(v.Name)
+			}
+		}
+	case *apiextensionsv1beta1.CustomResourceDefinition:
+		storedVersions = e.Status.StoredVersions
+		r := any(required).(*apiextensionsv1beta1.CustomResourceDefinition)
+		if len(r.Spec.Versions) == 0 {
+			served.Insert(r.Spec.Version)
+		}
+		for _, v := range r.Spec.Versions {
+			if v.Served {
+				served.Insert(v.Name)
+			}
+		}
+	}
+
+	var unserved []string
+	for _, v := range storedVersions {
+		if !served.Has(v) {
+			unserved = append(unserved, v)
+		}
+	}
+	return unserved
+}
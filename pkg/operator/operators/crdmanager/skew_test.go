@@ -0,0 +1,124 @@
+package crdmanager
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	fakeapiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/component-base/metrics/testutil"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestVersionSkewV1(t *testing.T) {
+	withVersions := func(crd *apiextensionsv1.CustomResourceDefinition, stored []string,
+		served ...string) *apiextensionsv1.CustomResourceDefinition {
+		for _, v := range served {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v, Served: true})
+		}
+		crd.Status.StoredVersions = stored
+		return crd
+	}
+
+	cases := []struct {
+		name           string
+		desiredVersion string
+		required       *apiextensionsv1.CustomResourceDefinition
+		existing       runtime.Object
+		expectedReason string
+		expectedVerbs  []string
+	}{
+		{
+			name:           "no skew",
+			desiredVersion: "v0.9.0",
+			required:       withVersions(newV1CRD("foo", ""), nil, "v1"),
+			existing:       withVersions(newV1CRD("foo", "v0.8.0"), []string{"v1"}, "v1"),
+			expectedVerbs:  []string{"get", "update"},
+		},
+		{
+			name:           "newer crd",
+			desiredVersion: "v0.8.0",
+			required:       withVersions(newV1CRD("foo", ""), nil, "v1"),
+			existing:       withVersions(newV1CRD("foo", "v0.9.0"), []string{"v1"}, "v1"),
+			expectedReason: ReasonCRDVersionNewer,
+			expectedVerbs:  []string{"get"},
+		},
+		{
+			name:           "stored version not served",
+			desiredVersion: "v0.9.0",
+			required:       withVersions(newV1CRD("foo", ""), nil, "v1"),
+			existing:       withVersions(newV1CRD("foo", "v0.8.0"), []string{"v1alpha1", "v1"}, "v1alpha1", "v1"),
+			expectedReason: ReasonCRDUpgradeBlocked,
+			expectedVerbs:  []string{"get"},
+		},
+		{
+			name:           "stored version still served",
+			desiredVersion: "v0.9.0",
+			required:       withVersions(newV1CRD("foo", ""), nil, "v1alpha1", "v1"),
+			existing:       withVersions(newV1CRD("foo", "v0.8.0"), []string{"v1alpha1"}, "v1alpha1"),
+			expectedVerbs:  []string{"get", "update"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := fakeapiextensions.NewSimpleClientset(c.existing)
+			manager := NewManager[*apiextensionsv1.CustomResourceDefinition](client.ApiextensionsV1().CustomResourceDefinitions(), EqualV1)
+			v, _ := versionutil.ParseSemantic(c.desiredVersion)
+			manager.version = v
+			err := manager.Apply(context.TODO(), func(string) ([]byte, error) {
+				return json.Marshal(c.required)
+			}, "foo")
+			if err != nil {
+				t.Errorf("apply error: %v", err)
+			}
+			testingcommon.AssertActions(t, client.Actions(), c.expectedVerbs...)
+
+			var conditions []metav1.Condition
+			manager.ReportVersionSkews("test", &conditions)
+			condition := meta.FindStatusCondition(conditions, ConditionVersionSkew)
+			switch {
+			case len(c.expectedReason) == 0 && condition != nil:
+				t.Errorf("expect no skew, but got %v", condition)
+			case len(c.expectedReason) > 0 && (condition == nil || condition.Reason != c.expectedReason):
+				t.Errorf("expect skew %s, but got %v", c.expectedReason, condition)
+			}
+
+			for _, reason := range []string{ReasonCRDVersionNewer, ReasonCRDUpgradeBlocked} {
+				value, err := testutil.GetGaugeMetricValue(versionSkew.WithLabelValues("test", "foo", reason))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if expected := reason == c.expectedReason; expected != (value == 1) {
+					t.Errorf("expect metric of reason %s is %v, but got %v", reason, expected, value)
+				}
+			}
+		})
+	}
+}
+
+func TestUnservedStoredVersionsV1Beta1(t *testing.T) {
+	existing := newV1Beta1CRD("foo", "")
+	existing.Status.StoredVersions = []string{"v1alpha1"}
+	required := newV1Beta1CRD("foo", "")
+	required.Spec.Version = "v1"
+
+	unserved := unservedStoredVersions(existing, required)
+	if len(unserved) != 1 || unserved[0] != "v1alpha1" {
+		t.Errorf("expect unserved stored version v1alpha1, but got %v", unserved)
+	}
+
+	required.Spec.Versions = []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+		{Name: "v1alpha1", Served: true}, {Name: "v1", Served: true, Storage: true},
+	}
+	if unserved := unservedStoredVersions(existing, required); len(unserved) != 0 {
+		t.Errorf("expect no unserved stored version, but got %v", unserved)
+	}
+}
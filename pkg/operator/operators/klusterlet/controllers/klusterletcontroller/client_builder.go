@@ -6,6 +6,7 @@ package klusterletcontroller
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
@@ -36,6 +37,9 @@ type managedClusterClients struct {
 	// Only used for Hosted mode to generate managed cluster kubeconfig
 	// with minimum permission for registration and work.
 	kubeconfig *rest.Config
+	// kubeconfigHash is the hash of the external managed kubeconfig secret in Hosted mode, the agents are
+	// restarted when it changes.
+	kubeconfigHash string
 }
 
 type managedClusterClientsBuilder struct {
@@ -47,6 +51,11 @@ type managedClusterClientsBuilder struct {
 	mode            operatorapiv1.InstallMode
 	secretNamespace string
 	secretName      string
+
+	// validatedKubeConfigHashes are the hashes of the external managed kubeconfigs whose connectivity is validated,
+	// keyed by the namespace and the name of the secret, so the managed cluster is only requested again once the
+	// kubeconfig is rotated.
+	validatedKubeConfigHashes map[string]string
 }
 
 func newManagedClusterClientsBuilder(
@@ -98,18 +107,31 @@ func (m *managedClusterClientsBuilder) build(ctx context.Context) (*managedClust
 		return nil, err
 	}
 
-	managedKubeConfig, err := getManagedKubeConfig(ctx, m.kubeClient, m.secretNamespace, m.secretName)
+	managedKubeConfig, kubeconfigHash, err := getManagedKubeConfig(ctx, m.kubeClient, m.secretNamespace, m.secretName)
 	if err != nil {
 		return nil, err
 	}
 
 	clients := &managedClusterClients{
-		kubeconfig: managedKubeConfig,
+		kubeconfig:     managedKubeConfig,
+		kubeconfigHash: kubeconfigHash,
 	}
 
 	if clients.kubeClient, err = kubernetes.NewForConfig(managedKubeConfig); err != nil {
 		return nil, err
 	}
+	// validate the connectivity with the kubeconfig, it may be rotated to an invalid one.
+	secretKey := m.secretNamespace + "/" + m.secretName
+	if m.validatedKubeConfigHashes[secretKey] != kubeconfigHash {
+		if _, err := clients.kubeClient.Discovery().ServerVersion(); err != nil {
+			return nil, fmt.Errorf("failed to connect to the managed cluster with the kubeconfig in secret %s/%s: %w",
+				m.secretNamespace, m.secretName, err)
+		}
+		if m.validatedKubeConfigHashes == nil {
+			m.validatedKubeConfigHashes = map[string]string{}
+		}
+		m.validatedKubeConfigHashes[secretKey] = kubeconfigHash
+	}
 	if clients.apiExtensionClient, err = apiextensionsclient.NewForConfig(managedKubeConfig); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,195 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+const (
+	// conditionAppliedManifestWorksCleanedUp reports the progress of the cleanup of the AppliedManifestWorks by the
+	// work agent when the klusterlet is deleting.
+	conditionAppliedManifestWorksCleanedUp = "AppliedManifestWorksCleanedUp"
+	reasonAppliedManifestWorksDeleting     = "AppliedManifestWorksDeleting"
+	reasonAppliedManifestWorksDeleted      = "AppliedManifestWorksDeleted"
+	reasonAppliedManifestWorksOrphaned     = "AppliedManifestWorksOrphaned"
+
+	// appliedManifestWorkCleanupTimeout is how long to wait for the work agent to clean up the AppliedManifestWorks
+	// before they are orphaned.
+	appliedManifestWorkCleanupTimeout = 10 * time.Minute
+	appliedManifestWorkCleanupRecheck = 10 * time.Second
+)
+
+// cleanUpAppliedManifestWorks deletes the orphaned AppliedManifestWorks of the klusterlet, whose ManifestWorks are
+// already removed from the hub, and waits for the work agent to remove the applied resources following the delete
+// options of the ManifestWorks, before the agents are removed. The AppliedManifestWorks of the ManifestWorks still on
+// the hub are kept, so the workloads on the managed cluster are not removed by deleting the klusterlet.
+// It returns true once all orphaned AppliedManifestWorks are removed, or the work agent is not able to clean them up
+// in time, in which case the finalizers of the remaining AppliedManifestWorks are removed by the managedReconcile,
+// and the applied resources are orphaned.
+func (n *klusterletCleanupController) cleanUpAppliedManifestWorks(ctx context.Context,
+	klusterlet *operatorapiv1.Klusterlet, config klusterletConfig,
+	amwClient workv1client.AppliedManifestWorkInterface, recorder events.Recorder) (bool, error) {
+	appliedManifestWorks, err := amwClient.List(ctx, metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to list AppliedManifestWorks: %w", err)
+	}
+
+	hubWorkClient, clusterName, err := n.hubWorkClient(ctx, config.AgentNamespace)
+	if err != nil {
+		// the ManifestWorks on the hub are unknown, nothing is deleted.
+		klog.Warningf("Unable to connect to the hub to find orphaned AppliedManifestWorks of klusterlet %s: %v",
+			klusterlet.Name, err)
+	}
+
+	// the AppliedManifestWorks created after the klusterlet is deleting are recreated by the work agent for the
+	// ManifestWorks still on the hub, they are not waited for.
+	var remaining []workapiv1.AppliedManifestWork
+	var names []string
+	for _, amw := range appliedManifestWorks.Items {
+		if string(klusterlet.UID) != amw.Spec.AgentID || !amw.CreationTimestamp.Before(klusterlet.DeletionTimestamp) {
+			continue
+		}
+		// the AppliedManifestWorks being deleted are waited for, since the work agent is already cleaning them up.
+		if amw.DeletionTimestamp.IsZero() && !manifestWorkRemoved(ctx, hubWorkClient, clusterName, amw) {
+			continue
+		}
+		remaining = append(remaining, amw)
+		names = append(names, amw.Name)
+	}
+	sort.Strings(names)
+
+	cond := meta.FindStatusCondition(klusterlet.Status.Conditions, conditionAppliedManifestWorksCleanedUp)
+	if len(remaining) == 0 {
+		if cond != nil {
+			meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+				Type: conditionAppliedManifestWorksCleanedUp, Status: metav1.ConditionTrue, Reason: reasonAppliedManifestWorksDeleted,
+				Message: "All AppliedManifestWorks are cleaned up by the work agent",
+			})
+		}
+		return true, nil
+	}
+	if cond != nil && cond.Reason == reasonAppliedManifestWorksOrphaned {
+		return true, nil
+	}
+
+	available, err := n.workAgentAvailable(ctx, klusterlet, config)
+	if err != nil {
+		return false, err
+	}
+	timedOut := cond != nil && cond.Status == metav1.ConditionFalse &&
+		cond.LastTransitionTime.Add(appliedManifestWorkCleanupTimeout).Before(time.Now())
+	if !available || timedOut {
+		klog.Warningf("The work agent of klusterlet %s is not able to clean up AppliedManifestWorks %v, orphan them",
+			klusterlet.Name, names)
+		meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+			Type: conditionAppliedManifestWorksCleanedUp, Status: metav1.ConditionTrue, Reason: reasonAppliedManifestWorksOrphaned,
+			Message: fmt.Sprintf("The work agent is not able to clean up AppliedManifestWorks %s, the applied resources are orphaned",
+				strings.Join(names, ",")),
+		})
+		return true, nil
+	}
+
+	var errs []error
+	for _, amw := range remaining {
+		if !amw.DeletionTimestamp.IsZero() {
+			continue
+		}
+		err := amwClient.Delete(ctx, amw.Name, metav1.DeleteOptions{})
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("unable to delete AppliedManifestWork %q: %w", amw.Name, err))
+		default:
+			recorder.Eventf("AppliedManifestWorkDeleted", "AppliedManifestWork %s is deleted", amw.Name)
+		}
+	}
+	if len(errs) > 0 {
+		return false, utilerrors.NewAggregate(errs)
+	}
+
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+		Type: conditionAppliedManifestWorksCleanedUp, Status: metav1.ConditionFalse, Reason: reasonAppliedManifestWorksDeleting,
+		Message: fmt.Sprintf("Waiting for the work agent to clean up %d AppliedManifestWorks: %s",
+			len(names), strings.Join(names, ",")),
+	})
+	return false, nil
+}
+
+// manifestWorkRemoved checks whether the ManifestWork of the AppliedManifestWork is removed from the hub. It returns
+// false if the hub is not accessible.
+func manifestWorkRemoved(ctx context.Context, hubWorkClient workv1client.ManifestWorksGetter, clusterName string,
+	amw workapiv1.AppliedManifestWork) bool {
+	if hubWorkClient == nil {
+		return false
+	}
+	_, err := hubWorkClient.ManifestWorks(clusterName).Get(ctx, amw.Spec.ManifestWorkName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return true
+	case err != nil:
+		klog.Warningf("Unable to get ManifestWork %s/%s of AppliedManifestWork %s: %v",
+			clusterName, amw.Spec.ManifestWorkName, amw.Name, err)
+	}
+	return false
+}
+
+// buildHubWorkClient builds the work client of the hub with the hub kubeconfig secret in the agent namespace, and
+// returns it together with the name of the cluster.
+func buildHubWorkClient(ctx context.Context, kubeClient kubernetes.Interface,
+	agentNamespace string) (workv1client.ManifestWorksGetter, string, error) {
+	secret, err := kubeClient.CoreV1().Secrets(agentNamespace).Get(ctx, helpers.HubKubeConfig, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	clusterName := string(secret.Data["cluster-name"])
+	if len(clusterName) == 0 {
+		return nil, "", fmt.Errorf("the cluster name in the secret %s/%s is empty", agentNamespace, helpers.HubKubeConfig)
+	}
+	hubConfig, err := helpers.LoadClientConfigFromSecret(secret)
+	if err != nil {
+		return nil, "", err
+	}
+	workClient, err := workv1client.NewForConfig(hubConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	return workClient, clusterName, nil
+}
+
+// workAgentAvailable checks whether the work agent of the klusterlet is still running to clean up the
+// AppliedManifestWorks.
+func (n *klusterletCleanupController) workAgentAvailable(ctx context.Context,
+	klusterlet *operatorapiv1.Klusterlet, config klusterletConfig) (bool, error) {
+	name := fmt.Sprintf("%s-work-agent", config.KlusterletName)
+	if helpers.IsSingleton(klusterlet.Spec.DeployOption.Mode) {
+		name = fmt.Sprintf("%s-agent", config.KlusterletName)
+	}
+	deployment, err := n.kubeClient.AppsV1().Deployments(config.AgentNamespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return deployment.Status.AvailableReplicas > 0, nil
+}
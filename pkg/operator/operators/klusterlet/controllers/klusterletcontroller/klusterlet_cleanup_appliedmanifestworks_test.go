@@ -0,0 +1,185 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestCleanUpAppliedManifestWorks(t *testing.T) {
+	deletionTime := metav1.Now()
+	before := metav1.NewTime(deletionTime.Add(-time.Hour))
+	newWork := func(name, agentID string, created metav1.Time, terminating bool) *workapiv1.AppliedManifestWork {
+		w := &workapiv1.AppliedManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: created,
+				Finalizers:        []string{workapiv1.AppliedManifestWorkFinalizer},
+			},
+			Spec: workapiv1.AppliedManifestWorkSpec{AgentID: agentID, ManifestWorkName: name},
+		}
+		if terminating {
+			w.DeletionTimestamp = &deletionTime
+		}
+		return w
+	}
+	workAgent := func(available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "klusterlet-work-agent", Namespace: "testns"},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+
+	cases := []struct {
+		name              string
+		works             []runtime.Object
+		hubWorks          []runtime.Object
+		hubUnavailable    bool
+		deployment        *appsv1.Deployment
+		condition         *metav1.Condition
+		expectedDone      bool
+		expectedWorkVerbs []string
+		expectedReason    string
+	}{
+		{
+			name:              "no works",
+			deployment:        workAgent(1),
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+		},
+		{
+			name: "delete works",
+			works: []runtime.Object{
+				newWork("work1", "uid", before, false),
+				newWork("work2", "uid", before, true),
+				newWork("other", "other-uid", before, false),
+				newWork("recreated", "uid", metav1.NewTime(deletionTime.Add(time.Minute)), false),
+				newWork("kept", "uid", before, false),
+			},
+			hubWorks: []runtime.Object{
+				&workapiv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "cluster1"}},
+			},
+			deployment:        workAgent(1),
+			expectedWorkVerbs: []string{"list", "delete"},
+			expectedReason:    reasonAppliedManifestWorksDeleting,
+		},
+		{
+			name: "works on the hub",
+			works: []runtime.Object{
+				newWork("work1", "uid", before, false),
+			},
+			hubWorks: []runtime.Object{
+				&workapiv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "work1", Namespace: "cluster1"}},
+			},
+			deployment:        workAgent(1),
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+		},
+		{
+			name:              "hub not available",
+			works:             []runtime.Object{newWork("work1", "uid", before, false)},
+			hubUnavailable:    true,
+			deployment:        workAgent(1),
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+		},
+		{
+			name:              "work agent not available",
+			works:             []runtime.Object{newWork("work1", "uid", before, false)},
+			deployment:        workAgent(0),
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+			expectedReason:    reasonAppliedManifestWorksOrphaned,
+		},
+		{
+			name:              "work agent not found",
+			works:             []runtime.Object{newWork("work1", "uid", before, false)},
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+			expectedReason:    reasonAppliedManifestWorksOrphaned,
+		},
+		{
+			name:       "cleanup timed out",
+			works:      []runtime.Object{newWork("work1", "uid", before, true)},
+			deployment: workAgent(1),
+			condition: &metav1.Condition{
+				Type: conditionAppliedManifestWorksCleanedUp, Status: metav1.ConditionFalse, Reason: reasonAppliedManifestWorksDeleting,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-appliedManifestWorkCleanupTimeout - time.Minute)),
+			},
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+			expectedReason:    reasonAppliedManifestWorksOrphaned,
+		},
+		{
+			name:       "works cleaned up",
+			deployment: workAgent(1),
+			condition: &metav1.Condition{
+				Type: conditionAppliedManifestWorksCleanedUp, Status: metav1.ConditionFalse, Reason: reasonAppliedManifestWorksDeleting,
+				LastTransitionTime: metav1.Now(),
+			},
+			expectedDone:      true,
+			expectedWorkVerbs: []string{"list"},
+			expectedReason:    reasonAppliedManifestWorksDeleted,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if c.deployment != nil {
+				objects = append(objects, c.deployment)
+			}
+			hubWorkClient := fakeworkclient.NewSimpleClientset(c.hubWorks...)
+			controller := &klusterletCleanupController{
+				kubeClient: fakekube.NewSimpleClientset(objects...),
+				hubWorkClient: func(_ context.Context, _ string) (workv1client.ManifestWorksGetter, string, error) {
+					if c.hubUnavailable {
+						return nil, "", fmt.Errorf("hub kubeconfig secret not found")
+					}
+					return hubWorkClient.WorkV1(), "cluster1", nil
+				},
+			}
+			workClient := fakeworkclient.NewSimpleClientset(c.works...)
+
+			klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+			klusterlet.UID = "uid"
+			klusterlet.DeletionTimestamp = &deletionTime
+			if c.condition != nil {
+				klusterlet.Status.Conditions = []metav1.Condition{*c.condition}
+			}
+			config := klusterletConfig{KlusterletName: klusterlet.Name, AgentNamespace: "testns"}
+
+			done, err := controller.cleanUpAppliedManifestWorks(context.TODO(), klusterlet, config,
+				workClient.WorkV1().AppliedManifestWorks(), events.NewInMemoryRecorder("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if done != c.expectedDone {
+				t.Errorf("expect done %v, but got %v", c.expectedDone, done)
+			}
+			testingcommon.AssertActions(t, workClient.Actions(), c.expectedWorkVerbs...)
+
+			cond := meta.FindStatusCondition(klusterlet.Status.Conditions, conditionAppliedManifestWorksCleanedUp)
+			switch {
+			case len(c.expectedReason) == 0 && cond != nil:
+				t.Errorf("expect no condition, but got %v", cond)
+			case len(c.expectedReason) > 0 && (cond == nil || cond.Reason != c.expectedReason):
+				t.Errorf("expect condition with reason %s, but got %v", c.expectedReason, cond)
+			}
+		})
+	}
+}
@@ -41,6 +41,7 @@ type klusterletCleanupController struct {
 	controlPlaneNodeLabelSelector string
 	deploymentReplicas            int32
 	disableAddonNamespace         bool
+	hubWorkClient                 func(ctx context.Context, agentNamespace string) (workv1client.ManifestWorksGetter, string, error)
 }
 
 // NewKlusterletCleanupController construct klusterlet cleanup controller
@@ -70,6 +71,9 @@ func NewKlusterletCleanupController(
 		controlPlaneNodeLabelSelector: controlPlaneNodeLabelSelector,
 		deploymentReplicas:            deploymentReplicas,
 		disableAddonNamespace:         disableAddonNamespace,
+		hubWorkClient: func(ctx context.Context, agentNamespace string) (workv1client.ManifestWorksGetter, string, error) {
+			return buildHubWorkClient(ctx, kubeClient, agentNamespace)
+		},
 	}
 
 	return factory.New().WithSync(controller.sync).
@@ -171,6 +175,18 @@ func (n *klusterletCleanupController) sync(ctx context.Context, controllerContex
 		// after trying to connect to the managed cluster times out for a period of time, we will stop removing
 		// resources on managed clusters, but just clean the resources on the hosting cluster and finish the cleanup
 		if cleanupManagedClusterResources {
+			// the agents are kept until the work agent cleans up the AppliedManifestWorks.
+			done, err := n.cleanUpAppliedManifestWorks(ctx, klusterlet, config,
+				managedClusterClients.appliedManifestWorkClient, controllerContext.Recorder())
+			if err != nil {
+				return err
+			}
+			if !done {
+				controllerContext.Queue().AddAfter(klusterletName, appliedManifestWorkCleanupRecheck)
+				_, err := n.patcher.PatchStatus(ctx, klusterlet, klusterlet.Status, originalKlusterlet.Status)
+				return err
+			}
+
 			reconcilers = append(reconcilers,
 				&crdReconcile{
 					managedClusterClients: managedClusterClients,
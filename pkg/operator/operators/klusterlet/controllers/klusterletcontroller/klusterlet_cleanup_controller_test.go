@@ -51,9 +51,9 @@ func TestSyncDelete(t *testing.T) {
 		}
 	}
 
-	// 11 managed static manifests + 12 management static manifests + 1 hub kubeconfig + 2 namespaces + 2 deployments
-	if len(deleteActions) != 29 {
-		t.Errorf("Expected 28 delete actions, but got %d", len(deleteActions))
+	// 12 managed static manifests + 12 management static manifests + 1 hub kubeconfig + 2 namespaces + 2 deployments
+	if len(deleteActions) != 30 {
+		t.Errorf("Expected 30 delete actions, but got %d", len(deleteActions))
 	}
 
 	var updateWorkActions []clienttesting.PatchActionImpl
@@ -111,9 +111,9 @@ func TestSyncDeleteHosted(t *testing.T) {
 	}
 
 	// 11 static manifests + 3 secrets(hub-kubeconfig-secret, external-managed-kubeconfig-registration,external-managed-kubeconfig-work)
-	// + 2 deployments(registration-agent,work-agent) + 1 namespace
-	if len(deleteActionsManagement) != 17 {
-		t.Errorf("Expected 17 delete actions, but got %d", len(deleteActionsManagement))
+	// + 2 deployments(registration-agent,work-agent) + 1 namespace + 1 priority class
+	if len(deleteActionsManagement) != 18 {
+		t.Errorf("Expected 18 delete actions, but got %d", len(deleteActionsManagement))
 	}
 
 	var deleteActionsManaged []clienttesting.DeleteActionImpl
@@ -125,9 +125,9 @@ func TestSyncDeleteHosted(t *testing.T) {
 		}
 	}
 
-	// 12 static manifests + 2 namespaces
-	if len(deleteActionsManaged) != 15 {
-		t.Errorf("Expected 14 delete actions, but got %d", len(deleteActionsManaged))
+	// 13 static manifests + 2 namespaces + 1 aggregate clusterrole
+	if len(deleteActionsManaged) != 16 {
+		t.Errorf("Expected 16 delete actions, but got %d", len(deleteActionsManaged))
 	}
 
 	var updateWorkActions []clienttesting.PatchActionImpl
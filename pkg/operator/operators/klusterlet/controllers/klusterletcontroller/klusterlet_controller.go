@@ -2,7 +2,9 @@ package klusterletcontroller
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
@@ -29,6 +31,7 @@ import (
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/fips"
 	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
@@ -55,6 +58,8 @@ type klusterletController struct {
 	deploymentReplicas            int32
 	disableAddonNamespace         bool
 	enableSyncLabels              bool
+	restrictedPodSecurity         bool
+	minimalRBAC                   bool
 }
 
 type klusterletReconcile interface {
@@ -77,6 +82,7 @@ func NewKlusterletController(
 	klusterletInformer operatorinformer.KlusterletInformer,
 	secretInformers map[string]coreinformer.SecretInformer,
 	deploymentInformer appsinformer.DeploymentInformer,
+	resourceQuotaInformer coreinformer.ResourceQuotaInformer,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	kubeVersion *version.Version,
 	operatorNamespace string,
@@ -84,6 +90,8 @@ func NewKlusterletController(
 	deploymentReplicas int32,
 	disableAddonNamespace bool,
 	enableSyncLabels bool,
+	restrictedPodSecurity bool,
+	minimalRBAC bool,
 	recorder events.Recorder) factory.Controller {
 	controller := &klusterletController{
 		kubeClient: kubeClient,
@@ -98,6 +106,8 @@ func NewKlusterletController(
 		deploymentReplicas:            deploymentReplicas,
 		disableAddonNamespace:         disableAddonNamespace,
 		enableSyncLabels:              enableSyncLabels,
+		restrictedPodSecurity:         restrictedPodSecurity,
+		minimalRBAC:                   minimalRBAC,
 	}
 
 	return factory.New().WithSync(controller.sync).
@@ -107,6 +117,8 @@ func NewKlusterletController(
 			secretInformers[helpers.ExternalManagedKubeConfig].Informer()).
 		WithInformersQueueKeysFunc(helpers.KlusterletDeploymentQueueKeyFunc(
 			controller.klusterletLister), deploymentInformer.Informer()).
+		WithInformersQueueKeysFunc(helpers.KlusterletResourceQuotaQueueKeyFunc(
+			controller.klusterletLister), resourceQuotaInformer.Informer()).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, klusterletInformer.Informer()).
 		ToController("KlusterletController", recorder)
 }
@@ -175,6 +187,82 @@ type klusterletConfig struct {
 
 	// Labels of the agents are synced from klusterlet CR.
 	Labels map[string]string
+
+	// AgentConfigs are the deployment configurations of each agent, they override the ones above.
+	AgentConfigs map[string]helpers.AgentConfig
+	// LeaderElectionArgs are the leader election flags of the agent, they are set from the agent config.
+	LeaderElectionArgs []string
+
+	// ProxyConfig is the proxy configuration of the agents, it is nil if the agents connect to the hub directly.
+	ProxyConfig *helpers.ProxyConfig
+
+	// HostedIsolation is the isolation of the agents on the management cluster in the Hosted mode.
+	HostedIsolation *helpers.HostedIsolation
+
+	// AgentIdentityGeneration is the requested generation of the agent identity, the registration agent rotates
+	// its identity once it is changed.
+	AgentIdentityGeneration string
+
+	// Paused pauses the agents during a maintenance window of the managed cluster.
+	Paused bool
+
+	// ResourceUsageReportInterval is the interval of the registration agent to report the resource usage of the
+	// managed cluster, the report is disabled if it is empty.
+	ResourceUsageReportInterval string
+
+	// SPIFFETrustDomain is the trust domain of the SPIFFE ID set in the client certificate of the registration agent.
+	SPIFFETrustDomain string
+
+	// HubCircuitBreakerThreshold is the threshold of the circuit breaker of the hub clients of the agents, the
+	// default of the agents is used if it is empty.
+	HubCircuitBreakerThreshold string
+
+	// HubCABundleDistribution makes the agents trust the CA bundles of the hub distributed by the hub.
+	HubCABundleDistribution bool
+
+	// AddOnKubeconfigExecCredential allows the addons to reference the exec credential plugins in their hub
+	// kubeconfigs.
+	AddOnKubeconfigExecCredential bool
+
+	// RestrictedPodSecurity renders the agents compliant with the restricted Pod Security Standard, and enforces
+	// the standard on the agent namespace.
+	RestrictedPodSecurity bool
+
+	// FIPSMode runs the agents in the FIPS compliance mode, it follows the mode of the operator.
+	FIPSMode bool
+
+	// MinimalRBAC grants the agents only the permissions required by the enabled features on the managed cluster,
+	// instead of the broad execution permissions of the work agent and the cluster wide addon management permissions
+	// of the registration agent. The actions denied to the agents are reported as events.
+	MinimalRBAC bool
+	// AddonManagement and ExecutorValidatingCaches are the features requiring additional permissions in the
+	// minimal RBAC mode, they are set only if MinimalRBAC is true.
+	AddonManagement          bool
+	ExecutorValidatingCaches bool
+}
+
+// forAgent returns the config and the node placement to render the deployment of the agent, with the agent
+// config applied.
+func (config klusterletConfig) forAgent(agent string, nodePlacement operatorapiv1.NodePlacement) (
+	klusterletConfig, operatorapiv1.NodePlacement, error) {
+	agentConfig, ok := config.AgentConfigs[agent]
+	if !ok {
+		return config, nodePlacement, nil
+	}
+
+	resourceRequirements, err := helpers.AgentResourceRequirements(agentConfig)
+	if err != nil {
+		return config, nodePlacement, err
+	}
+	if resourceRequirements != nil {
+		config.ResourceRequirementResourceType = operatorapiv1.ResourceQosClassResourceRequirement
+		config.ResourceRequirements = resourceRequirements
+	}
+	if len(agentConfig.PriorityClassName) > 0 {
+		config.PriorityClassName = agentConfig.PriorityClassName
+	}
+	config.LeaderElectionArgs = agentConfig.LeaderElection.Args()
+	return config, helpers.AgentNodePlacement(nodePlacement, agentConfig), nil
 }
 
 // If multiplehubs feature gate is enabled, using the bootstrapkubeconfigs from klusterlet CR.
@@ -216,6 +304,67 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 		return err
 	}
 
+	agentConfigs, err := helpers.AgentConfigs(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse agent configs for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+	if !helpers.PriorityClassSupported(n.kubeVersion) {
+		for name, agentConfig := range agentConfigs {
+			agentConfig.PriorityClassName = ""
+			agentConfigs[name] = agentConfig
+		}
+	}
+
+	imageConfig, err := helpers.GetImageConfig(klusterlet, helpers.RegistrationImageComponent, helpers.WorkImageComponent,
+		helpers.SingletonImageComponent)
+	if err != nil {
+		klog.Errorf("Failed to parse image config for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+
+	proxyConfig, err := helpers.GetProxyConfig(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse proxy config for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+
+	upgradeConfig, err := helpers.GetStagedUpgradeConfig(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse staged upgrade config for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+
+	hostedIsolation, err := helpers.GetHostedIsolation(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse hosted isolation for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+
+	agentIdentityGeneration, err := helpers.GetAgentIdentityGeneration(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse agent identity generation for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+	resourceUsageReportInterval, err := helpers.GetResourceUsageReportInterval(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse resource usage report interval for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+	spiffeTrustDomain, err := helpers.GetSPIFFETrustDomain(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse spiffe trust domain for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+	hubCircuitBreakerThreshold, err := helpers.GetHubCircuitBreakerThreshold(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse hub circuit breaker threshold for klusterlet %s: %v", klusterlet.Name, err)
+		return n.reportInvalidAnnotations(ctx, originalKlusterlet, err)
+	}
+	if hostedIsolation != nil && !helpers.PriorityClassSupported(n.kubeVersion) {
+		hostedIsolation.Priority = nil
+	}
+
 	replica := n.deploymentReplicas
 	if replica <= 0 {
 		replica = helpers.DetermineReplica(ctx, n.kubeClient, klusterlet.Spec.DeployOption.Mode, n.kubeVersion, n.controlPlaneNodeLabelSelector)
@@ -226,10 +375,10 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 		KlusterletNamespace:                    helpers.KlusterletNamespace(klusterlet),
 		AgentNamespace:                         helpers.AgentNamespace(klusterlet),
 		AgentID:                                string(klusterlet.UID),
-		RegistrationImage:                      klusterlet.Spec.RegistrationImagePullSpec,
-		WorkImage:                              klusterlet.Spec.WorkImagePullSpec,
+		RegistrationImage:                      imageConfig.Image(helpers.RegistrationImageComponent, klusterlet.Spec.RegistrationImagePullSpec),
+		WorkImage:                              imageConfig.Image(helpers.WorkImageComponent, klusterlet.Spec.WorkImagePullSpec),
 		ClusterName:                            klusterlet.Spec.ClusterName,
-		SingletonImage:                         klusterlet.Spec.ImagePullSpec,
+		SingletonImage:                         imageConfig.Image(helpers.SingletonImageComponent, klusterlet.Spec.ImagePullSpec),
 		HubKubeConfigSecret:                    helpers.HubKubeConfig,
 		ExternalServerURL:                      getServersFromKlusterlet(klusterlet),
 		OperatorNamespace:                      n.operatorNamespace,
@@ -249,6 +398,23 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 		ResourceRequirementResourceType: helpers.ResourceType(klusterlet),
 		ResourceRequirements:            resourceRequirements,
 		DisableAddonNamespace:           n.disableAddonNamespace,
+		AgentConfigs:                    agentConfigs,
+		ProxyConfig:                     proxyConfig,
+		HostedIsolation:                 hostedIsolation,
+		AgentIdentityGeneration:         agentIdentityGeneration,
+		Paused:                          helpers.IsKlusterletPaused(klusterlet),
+		ResourceUsageReportInterval:     resourceUsageReportInterval,
+		SPIFFETrustDomain:               spiffeTrustDomain,
+		HubCircuitBreakerThreshold:      hubCircuitBreakerThreshold,
+		HubCABundleDistribution:         helpers.HubCABundleDistributionEnabled(klusterlet),
+		AddOnKubeconfigExecCredential:   helpers.AddOnKubeconfigExecCredentialEnabled(klusterlet),
+		RestrictedPodSecurity:           n.restrictedPodSecurity,
+		FIPSMode:                        fips.Enabled(),
+		MinimalRBAC:                     n.minimalRBAC,
+	}
+	// the agents use the priority class of the klusterlet unless a priority class is set in the spec.
+	if hostedIsolation != nil && hostedIsolation.Priority != nil && len(config.PriorityClassName) == 0 {
+		config.PriorityClassName = helpers.AgentPriorityClass(klusterlet.Name)
 	}
 
 	config.populateBootstrap(klusterlet)
@@ -299,9 +465,6 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 
 	// If there are some invalid feature gates of registration or work, will output condition `ValidFeatureGates`
 	// False in Klusterlet.
-	// TODO: For the work feature gates, when splitting permissions in the future, if the ExecutorValidatingCaches
-	//       function is enabled, additional permissions for get, list, and watch RBAC resources required by this
-	//       function need to be applied
 	var registrationFeatureMsgs, workFeatureMsgs string
 	registrationFeatureGates := helpers.DefaultSpokeRegistrationFeatureGates
 	if klusterlet.Spec.RegistrationConfiguration != nil {
@@ -328,7 +491,20 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 	}
 
 	config.WorkFeatureGates, workFeatureMsgs = helpers.ConvertToFeatureGateFlags("Work", workFeatureGates, ocmfeature.DefaultSpokeWorkFeatureGates)
+	if config.MinimalRBAC {
+		config.AddonManagement = helpers.FeatureGateEnabled(
+			registrationFeatureGates, ocmfeature.DefaultSpokeRegistrationFeatureGates, ocmfeature.AddonManagement)
+		config.ExecutorValidatingCaches = helpers.FeatureGateEnabled(
+			workFeatureGates, ocmfeature.DefaultSpokeWorkFeatureGates, ocmfeature.ExecutorValidatingCaches)
+	}
 	meta.SetStatusCondition(&klusterlet.Status.Conditions, helpers.BuildFeatureCondition(registrationFeatureMsgs, workFeatureMsgs))
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, helpers.BuildAnnotationsCondition(nil))
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, helpers.BuildEffectiveFeatureCondition(
+		helpers.ComponentFeatureGates{Component: "Registration", FeatureGates: registrationFeatureGates,
+			DefaultFeatures: ocmfeature.DefaultSpokeRegistrationFeatureGates},
+		helpers.ComponentFeatureGates{Component: "Work", FeatureGates: workFeatureGates,
+			DefaultFeatures: ocmfeature.DefaultSpokeWorkFeatureGates},
+	))
 
 	// for singleton agent, the QPS and Burst use the max one between the configurations of registration and work
 	config.AgentKubeAPIQPS = config.RegistrationKubeAPIQPS
@@ -340,6 +516,12 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 		config.AgentKubeAPIBurst = config.WorkKubeAPIBurst
 	}
 
+	// roll back the images of the agents if the upgraded agents are not available in time.
+	upgrade, upgradeRecheck, err := n.stageUpgrade(ctx, klusterlet, &config, upgradeConfig, controllerContext.Recorder())
+	if err != nil {
+		return err
+	}
+
 	reconcilers := []klusterletReconcile{
 		&crdReconcile{
 			managedClusterClients: managedClusterClients,
@@ -385,6 +567,15 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 
 	klusterlet.Status.ObservedGeneration = klusterlet.Generation
 
+	if len(errs) == 0 && upgrade != nil {
+		if err := n.saveUpgradeState(ctx, config.AgentNamespace, upgrade, controllerContext.Recorder()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if upgradeRecheck > 0 {
+		controllerContext.Queue().AddAfter(klusterletName, upgradeRecheck)
+	}
+
 	if len(errs) == 0 {
 		meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
 			Type: operatorapiv1.ConditionKlusterletApplied, Status: metav1.ConditionTrue, Reason: operatorapiv1.ReasonKlusterletApplied,
@@ -403,6 +594,17 @@ func (n *klusterletController) sync(ctx context.Context, controllerContext facto
 	return utilerrors.NewAggregate(errs)
 }
 
+// reportInvalidAnnotations sets the condition of the klusterlet to report the error of parsing its annotations,
+// and returns the error so the klusterlet is synced again with backoff.
+func (n *klusterletController) reportInvalidAnnotations(ctx context.Context, klusterlet *operatorapiv1.Klusterlet, err error) error {
+	newKlusterlet := klusterlet.DeepCopy()
+	meta.SetStatusCondition(&newKlusterlet.Status.Conditions, helpers.BuildAnnotationsCondition(err))
+	if _, updatedErr := n.patcher.PatchStatus(ctx, newKlusterlet, newKlusterlet.Status, klusterlet.Status); updatedErr != nil {
+		return utilerrors.NewAggregate([]error{err, updatedErr})
+	}
+	return err
+}
+
 // TODO also read CABundle from ExternalServerURLs and set into registration deployment
 func getServersFromKlusterlet(klusterlet *operatorapiv1.Klusterlet) string {
 	if klusterlet.Spec.ExternalServerURLs == nil {
@@ -432,14 +634,34 @@ func getAppliedManifestWorkEvictionGracePeriod(klusterlet *operatorapiv1.Kluster
 }
 
 // getManagedKubeConfig is a helper func for Hosted mode, it will retrieve managed cluster
-// kubeconfig from "external-managed-kubeconfig" secret.
-func getManagedKubeConfig(ctx context.Context, kubeClient kubernetes.Interface, namespace, secretName string) (*rest.Config, error) {
+// kubeconfig from "external-managed-kubeconfig" secret, together with the hash of the secret data.
+func getManagedKubeConfig(ctx context.Context, kubeClient kubernetes.Interface, namespace, secretName string) (*rest.Config, string, error) {
 	managedKubeconfigSecret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return helpers.LoadClientConfigFromSecret(managedKubeconfigSecret)
+	managedKubeConfig, err := helpers.LoadClientConfigFromSecret(managedKubeconfigSecret)
+	if err != nil {
+		return nil, "", err
+	}
+	return managedKubeConfig, secretDataHash(managedKubeconfigSecret), nil
+}
+
+// secretDataHash returns the hash of the data of the secret.
+func secretDataHash(secret *corev1.Secret) string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write(secret.Data[key])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
 }
 
 // syncPullSecret will sync pull secret from the sourceClient cluster to the targetClient cluster in desired namespace.
@@ -473,14 +695,18 @@ func ensureNamespace(
 	ctx context.Context,
 	kubeClient kubernetes.Interface,
 	klusterlet *operatorapiv1.Klusterlet,
-	namespace string, labels map[string]string, recorder events.Recorder) error {
+	namespace string, labels, annotations map[string]string, recorder events.Recorder) error {
+	namespaceAnnotations := map[string]string{
+		"workload.openshift.io/allowed": "management",
+	}
+	for k, v := range annotations {
+		namespaceAnnotations[k] = v
+	}
 	_, _, err := resourceapply.ApplyNamespace(ctx, kubeClient.CoreV1(), recorder, &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: namespace,
-			Annotations: map[string]string{
-				"workload.openshift.io/allowed": "management",
-			},
-			Labels: labels,
+			Name:        namespace,
+			Annotations: namespaceAnnotations,
+			Labels:      labels,
 		},
 	})
 	if err != nil {
@@ -5,7 +5,11 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -37,6 +41,7 @@ import (
 	fakeoperatorclient "open-cluster-management.io/api/client/operator/clientset/versioned/fake"
 	operatorinformers "open-cluster-management.io/api/client/operator/informers/externalversions"
 	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
 	operatorapiv1 "open-cluster-management.io/api/operator/v1"
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
@@ -204,6 +209,9 @@ func newTestController(t *testing.T, klusterlet *operatorapiv1.Klusterlet, recor
 		operatorNamespace: "open-cluster-management",
 		managedClusterClientsBuilder: newManagedClusterClientsBuilder(fakeKubeClient, fakeAPIExtensionClient,
 			fakeWorkClient.WorkV1().AppliedManifestWorks(), recorder),
+		hubWorkClient: func(_ context.Context, _ string) (workv1client.ManifestWorksGetter, string, error) {
+			return fakeWorkClient.WorkV1(), klusterlet.Spec.ClusterName, nil
+		},
 	}
 
 	store := operatorInformers.Operator().V1().Klusterlets().Informer().GetStore()
@@ -307,6 +315,9 @@ func newTestControllerHosted(
 			fakeAPIExtensionClient: fakeManagedAPIExtensionClient,
 			fakeKubeClient:         fakeManagedKubeClient,
 		},
+		hubWorkClient: func(_ context.Context, _ string) (workv1client.ManifestWorksGetter, string, error) {
+			return fakeManagedWorkClient.WorkV1(), klusterlet.Spec.ClusterName, nil
+		},
 	}
 
 	store := operatorInformers.Operator().V1().Klusterlets().Informer().GetStore()
@@ -578,6 +589,8 @@ func TestSyncDeploy(t *testing.T) {
 				t, klusterlet,
 				testinghelper.NamedCondition(operatorapiv1.ConditionKlusterletApplied, "KlusterletApplied", metav1.ConditionTrue),
 				testinghelper.NamedCondition(helpers.FeatureGatesTypeValid, helpers.FeatureGatesReasonAllValid, metav1.ConditionTrue),
+				testinghelper.NamedCondition(helpers.AnnotationsTypeValid, helpers.AnnotationsReasonAllValid, metav1.ConditionTrue),
+				testinghelper.NamedCondition(helpers.FeatureGatesTypeEffective, helpers.FeatureGatesReasonEffective, metav1.ConditionTrue),
 			)
 		})
 	}
@@ -660,6 +673,8 @@ func TestSyncDeploySingleton(t *testing.T) {
 				t, klusterlet,
 				testinghelper.NamedCondition(operatorapiv1.ConditionKlusterletApplied, "KlusterletApplied", metav1.ConditionTrue),
 				testinghelper.NamedCondition(helpers.FeatureGatesTypeValid, helpers.FeatureGatesReasonAllValid, metav1.ConditionTrue),
+				testinghelper.NamedCondition(helpers.AnnotationsTypeValid, helpers.AnnotationsReasonAllValid, metav1.ConditionTrue),
+				testinghelper.NamedCondition(helpers.FeatureGatesTypeEffective, helpers.FeatureGatesReasonEffective, metav1.ConditionTrue),
 			)
 		})
 	}
@@ -759,6 +774,10 @@ func TestSyncDeployHosted(t *testing.T) {
 	conditionApplied := testinghelper.NamedCondition(operatorapiv1.ConditionKlusterletApplied, "KlusterletApplied", metav1.ConditionTrue)
 	conditionFeaturesValid := testinghelper.NamedCondition(
 		helpers.FeatureGatesTypeValid, helpers.FeatureGatesReasonAllValid, metav1.ConditionTrue)
+	conditionFeaturesEffective := testinghelper.NamedCondition(
+		helpers.FeatureGatesTypeEffective, helpers.FeatureGatesReasonEffective, metav1.ConditionTrue)
+	conditionAnnotationsValid := testinghelper.NamedCondition(
+		helpers.AnnotationsTypeValid, helpers.AnnotationsReasonAllValid, metav1.ConditionTrue)
 	testingcommon.AssertActions(t, operatorAction, "patch")
 	klusterlet = &operatorapiv1.Klusterlet{}
 	patchData := operatorAction[0].(clienttesting.PatchActionImpl).Patch
@@ -768,7 +787,7 @@ func TestSyncDeployHosted(t *testing.T) {
 	}
 	testinghelper.AssertOnlyConditions(
 		t, klusterlet, conditionReady, conditionApplied,
-		conditionFeaturesValid)
+		conditionFeaturesValid, conditionFeaturesEffective, conditionAnnotationsValid)
 }
 
 func TestSyncDeployHostedCreateAgentNamespace(t *testing.T) {
@@ -897,6 +916,8 @@ func TestSyncDisableAddonNamespace(t *testing.T) {
 		t, klusterlet,
 		testinghelper.NamedCondition(operatorapiv1.ConditionKlusterletApplied, "KlusterletApplied", metav1.ConditionTrue),
 		testinghelper.NamedCondition(helpers.FeatureGatesTypeValid, helpers.FeatureGatesReasonAllValid, metav1.ConditionTrue),
+		testinghelper.NamedCondition(helpers.AnnotationsTypeValid, helpers.AnnotationsReasonAllValid, metav1.ConditionTrue),
+		testinghelper.NamedCondition(helpers.FeatureGatesTypeEffective, helpers.FeatureGatesReasonEffective, metav1.ConditionTrue),
 	)
 }
 
@@ -1185,6 +1206,8 @@ func TestDeployOnKube111(t *testing.T) {
 		t, updatedKlusterlet,
 		testinghelper.NamedCondition(operatorapiv1.ConditionKlusterletApplied, "KlusterletApplied", metav1.ConditionTrue),
 		testinghelper.NamedCondition(helpers.FeatureGatesTypeValid, helpers.FeatureGatesReasonAllValid, metav1.ConditionTrue),
+		testinghelper.NamedCondition(helpers.AnnotationsTypeValid, helpers.AnnotationsReasonAllValid, metav1.ConditionTrue),
+		testinghelper.NamedCondition(helpers.FeatureGatesTypeEffective, helpers.FeatureGatesReasonEffective, metav1.ConditionTrue),
 	)
 
 	// Delete the klusterlet
@@ -1208,9 +1231,9 @@ func TestDeployOnKube111(t *testing.T) {
 		}
 	}
 
-	// 12 managed static manifests + 11 management static manifests + 1 hub kubeconfig + 2 namespaces + 2 deployments + 2 kube111 clusterrolebindings
-	if len(deleteActions) != 31 {
-		t.Errorf("Expected 30 delete actions, but got %d", len(deleteActions))
+	// 13 managed static manifests + 11 management static manifests + 1 hub kubeconfig + 2 namespaces + 2 deployments + 2 kube111 clusterrolebindings
+	if len(deleteActions) != 32 {
+		t.Errorf("Expected 32 delete actions, but got %d", len(deleteActions))
 	}
 }
 
@@ -1283,6 +1306,106 @@ func TestRenderingResourceRequirements(t *testing.T) {
 	}
 }
 
+func TestRenderingAgentConfigs(t *testing.T) {
+	workResources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+	config := newFakeKlusterletConfigWithResourceRequirement(t, &operatorapiv1.ResourceRequirement{
+		Type: operatorapiv1.ResourceQosClassDefault,
+	})
+	config.ResourceRequirementResourceType = operatorapiv1.ResourceQosClassDefault
+	config.ResourceRequirements = nil
+	config.PriorityClassName = "klusterlet-critical"
+	config.AgentConfigs = map[string]helpers.AgentConfig{
+		helpers.WorkAgent: {
+			ResourceRequirements: workResources,
+			NodeSelector:         map[string]string{"node-role": "work"},
+			PriorityClassName:    "work-critical",
+			LeaderElection:       &helpers.LeaderElectionConfig{LeaseDuration: &metav1.Duration{Duration: time.Minute}},
+		},
+	}
+	config.Replica = 3
+	nodePlacement := operatorapiv1.NodePlacement{
+		NodeSelector: map[string]string{"node-role": "infra"},
+		Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}},
+	}
+
+	cases := []struct {
+		agent                 string
+		file                  string
+		expectedResources     corev1.ResourceRequirements
+		expectedPriorityClass string
+		expectedNodeSelector  map[string]string
+		expectedArg           string
+	}{
+		{
+			agent: helpers.RegistrationAgent,
+			file:  "klusterlet/management/klusterlet-registration-deployment.yaml",
+			expectedResources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2m"),
+					corev1.ResourceMemory: resource.MustParse("16Mi"),
+				},
+			},
+			expectedPriorityClass: "klusterlet-critical",
+			expectedNodeSelector:  map[string]string{"node-role": "infra"},
+		},
+		{
+			agent:                 helpers.WorkAgent,
+			file:                  "klusterlet/management/klusterlet-work-deployment.yaml",
+			expectedResources:     *workResources,
+			expectedPriorityClass: "work-critical",
+			expectedNodeSelector:  map[string]string{"node-role": "work"},
+			expectedArg:           "--leader-election-lease-duration=1m0s",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.agent, func(t *testing.T) {
+			agentConfig, agentNodePlacement, err := config.forAgent(c.agent, nodePlacement)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(agentNodePlacement.NodeSelector, c.expectedNodeSelector) {
+				t.Errorf("expect node selector %v, but got %v", c.expectedNodeSelector, agentNodePlacement.NodeSelector)
+			}
+			if !equality.Semantic.DeepEqual(agentNodePlacement.Tolerations, nodePlacement.Tolerations) {
+				t.Errorf("expect tolerations %v, but got %v", nodePlacement.Tolerations, agentNodePlacement.Tolerations)
+			}
+
+			manifest, err := manifests.KlusterletManifestFiles.ReadFile(c.file)
+			if err != nil {
+				t.Fatalf("Failed to read file %s", c.file)
+			}
+			objData := assets.MustCreateAssetFromTemplate(c.file, manifest, agentConfig).Data
+			deploy := &appsv1.Deployment{}
+			if err = yaml.Unmarshal(objData, deploy); err != nil {
+				t.Fatalf("Failed to unmarshal deployment: %v", err)
+			}
+			actual := deploy.Spec.Template.Spec.Containers[0].Resources
+			if !equality.Semantic.DeepEqual(actual.Requests, c.expectedResources.Requests) ||
+				!equality.Semantic.DeepEqual(actual.Limits, c.expectedResources.Limits) {
+				t.Errorf("expect resources %v, but got %v", c.expectedResources, actual)
+			}
+			if deploy.Spec.Template.Spec.PriorityClassName != c.expectedPriorityClass {
+				t.Errorf("expect priority class %q, but got %q", c.expectedPriorityClass, deploy.Spec.Template.Spec.PriorityClassName)
+			}
+			args := deploy.Spec.Template.Spec.Containers[0].Args
+			for _, arg := range args {
+				if strings.HasPrefix(arg, "--leader-election-") && arg != c.expectedArg {
+					t.Errorf("unexpected leader election arg %q", arg)
+				}
+			}
+			if len(c.expectedArg) > 0 && !slices.Contains(args, c.expectedArg) {
+				t.Errorf("expect arg %q in %v", c.expectedArg, args)
+			}
+		})
+	}
+}
+
 func newKubeConfig(host string) []byte {
 	configData, _ := runtime.Encode(clientcmdlatest.Codec, &clientcmdapi.Config{
 		Clusters: map[string]*clientcmdapi.Cluster{"test-cluster": {
@@ -1454,3 +1577,171 @@ func TestGetAppliedManifestWorkEvictionGracePeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestManagedKubeConfigRotated(t *testing.T) {
+	secret := newSecret(helpers.ExternalManagedKubeConfig, "testns")
+	secret.Data["kubeconfig"] = newKubeConfig("https://10.0.118.47:6443")
+	hash := secretDataHash(secret)
+	secret.Data["kubeconfig"] = newKubeConfig("https://10.0.118.48:6443")
+	if rotatedHash := secretDataHash(secret); rotatedHash == hash {
+		t.Errorf("expect the hash is changed when the kubeconfig is rotated")
+	}
+
+	cases := []struct {
+		name              string
+		existingHash      *string
+		rotating          bool
+		rolledOut         bool
+		expectedCondition metav1.ConditionStatus
+	}{
+		{name: "deployment not found"},
+		{name: "hash not changed", existingHash: &hash},
+		{name: "hash changed", existingHash: strPtr("old"), expectedCondition: metav1.ConditionTrue},
+		{name: "agents restarting", existingHash: &hash, rotating: true, expectedCondition: metav1.ConditionTrue},
+		{name: "agents restarted", existingHash: &hash, rotating: true, rolledOut: true, expectedCondition: metav1.ConditionFalse},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if c.existingHash != nil {
+				deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "klusterlet-registration-agent", Namespace: "testns"}}
+				deployment.Spec.Template.Annotations = map[string]string{managedKubeConfigHashAnnotation: *c.existingHash}
+				if c.rolledOut {
+					deployment.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+				}
+				objects = append(objects, deployment)
+			}
+			r := &runtimeReconcile{
+				kubeClient:            fakekube.NewSimpleClientset(objects...),
+				managedClusterClients: &managedClusterClients{kubeconfigHash: hash},
+				recorder:              events.NewInMemoryRecorder("test"),
+			}
+			klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+			if c.rotating {
+				meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+					Type: conditionManagedKubeConfigRotated, Status: metav1.ConditionTrue, Reason: reasonAgentsRestarting,
+				})
+			}
+
+			if err := r.checkManagedKubeConfigRotated(context.TODO(), klusterlet, "testns", "klusterlet-registration-agent"); err != nil {
+				t.Fatal(err)
+			}
+			var status metav1.ConditionStatus
+			if cond := meta.FindStatusCondition(klusterlet.Status.Conditions, conditionManagedKubeConfigRotated); cond != nil {
+				status = cond.Status
+			}
+			if status != c.expectedCondition {
+				t.Errorf("expect rotated condition %q, but got %v", c.expectedCondition, klusterlet.Status.Conditions)
+			}
+
+			deployment := &appsv1.Deployment{}
+			r.setManagedKubeConfigHash(deployment)
+			if deployment.Spec.Template.Annotations[managedKubeConfigHashAnnotation] != hash {
+				t.Errorf("expect the hash is set on the pod template, but got %v", deployment.Spec.Template.Annotations)
+			}
+		})
+	}
+}
+
+func TestBuildManagedClusterClientsValidateOnce(t *testing.T) {
+	var versionRequests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			versionRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"major":"1","minor":"30","gitVersion":"v1.30.0"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	secret := newSecret(helpers.ExternalManagedKubeConfig, "testns")
+	secret.Data["kubeconfig"] = newKubeConfig(server.URL)
+	kubeClient := fakekube.NewSimpleClientset(secret)
+	builder := newManagedClusterClientsBuilder(kubeClient, fakeapiextensions.NewSimpleClientset(),
+		fakeworkclient.NewSimpleClientset().WorkV1().AppliedManifestWorks(), events.NewInMemoryRecorder("test"))
+	builder.withMode(operatorapiv1.InstallModeHosted).withKubeConfigSecret("testns", helpers.ExternalManagedKubeConfig)
+
+	build := func() {
+		if _, err := builder.build(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the connectivity is validated only once for the same kubeconfig
+	build()
+	build()
+	if count := versionRequests.Load(); count != 1 {
+		t.Errorf("expect the connectivity is validated once, but got %d requests", count)
+	}
+
+	// the connectivity is validated again once the kubeconfig is rotated
+	secret.Data["token"] = []byte("rotated")
+	if _, err := kubeClient.CoreV1().Secrets("testns").Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	build()
+	if count := versionRequests.Load(); count != 2 {
+		t.Errorf("expect the connectivity is validated for the rotated kubeconfig, but got %d requests", count)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestApplyAddonLimitRange(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset()
+	r := &managedReconcile{managedClusterClients: &managedClusterClients{kubeClient: kubeClient}}
+	ctx := context.TODO()
+
+	getLimitRange := func() *corev1.LimitRange {
+		limitRange, err := kubeClient.CoreV1().LimitRanges(helpers.DefaultAddonNamespace).Get(
+			ctx, helpers.AddonAgentLimitRangeName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return limitRange
+	}
+
+	// nothing is deleted if the limit range is not required and does not exist
+	if err := r.applyAddonLimitRange(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("unexpected action %v", action)
+		}
+	}
+
+	config := helpers.AgentConfig{ResourceRequirements: &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+	}}
+	if err := r.applyAddonLimitRange(ctx, helpers.AddonLimitRange(helpers.DefaultAddonNamespace, config)); err != nil {
+		t.Fatal(err)
+	}
+	if limitRange := getLimitRange(); limitRange == nil ||
+		!equality.Semantic.DeepEqual(limitRange.Spec.Limits[0].DefaultRequest, config.ResourceRequirements.Requests) {
+		t.Errorf("unexpected limit range %v", limitRange)
+	}
+
+	config.ResourceRequirements.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20m")}
+	if err := r.applyAddonLimitRange(ctx, helpers.AddonLimitRange(helpers.DefaultAddonNamespace, config)); err != nil {
+		t.Fatal(err)
+	}
+	if limitRange := getLimitRange(); limitRange == nil ||
+		!equality.Semantic.DeepEqual(limitRange.Spec.Limits[0].DefaultRequest, config.ResourceRequirements.Requests) {
+		t.Errorf("unexpected limit range %v", limitRange)
+	}
+
+	if err := r.applyAddonLimitRange(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if limitRange := getLimitRange(); limitRange != nil {
+		t.Errorf("expected the limit range is deleted, but got %v", limitRange)
+	}
+}
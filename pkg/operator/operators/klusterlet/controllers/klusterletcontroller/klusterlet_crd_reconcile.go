@@ -64,6 +64,7 @@ func (r *crdReconcile) reconcile(ctx context.Context, klusterlet *operatorapiv1.
 			},
 			crdV1beta1StaticFiles...,
 		)
+		crdManager.ReportVersionSkews(klusterlet.Name, &klusterlet.Status.Conditions)
 	} else {
 		crdManager := crdmanager.NewManager[*apiextensionsv1.CustomResourceDefinition](
 			r.managedClusterClients.apiExtensionClient.ApiextensionsV1().CustomResourceDefinitions(),
@@ -81,6 +82,7 @@ func (r *crdReconcile) reconcile(ctx context.Context, klusterlet *operatorapiv1.
 			},
 			crdV1StaticFiles...,
 		)
+		crdManager.ReportVersionSkews(klusterlet.Name, &klusterlet.Status.Conditions)
 	}
 
 	if applyErr != nil {
@@ -0,0 +1,162 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+const (
+	// conditionAgentResourceQuotaExhausted reports the resource usage of the agent namespace on the management
+	// cluster against its quota in the Hosted mode, it is true if any resource in the quota is used up.
+	conditionAgentResourceQuotaExhausted = "AgentResourceQuotaExhausted"
+	reasonResourceQuotaExhausted         = "ResourceQuotaExhausted"
+	reasonResourceQuotaAvailable         = "ResourceQuotaAvailable"
+)
+
+// applyIsolation applies the resource quota of the agent namespace and the priority class of the agents on the
+// management cluster in the Hosted mode, and deletes them if they are not set.
+func (r *runtimeReconcile) applyIsolation(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
+	config klusterletConfig) error {
+	if err := r.applyResourceQuota(ctx, klusterlet, config); err != nil {
+		return err
+	}
+	return r.applyPriorityClass(ctx, config)
+}
+
+func (r *runtimeReconcile) applyResourceQuota(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
+	config klusterletConfig) error {
+	client := r.kubeClient.CoreV1().ResourceQuotas(config.AgentNamespace)
+	required := config.HostedIsolation.ResourceQuotaFor(config.AgentNamespace)
+	if required == nil {
+		meta.RemoveStatusCondition(&klusterlet.Status.Conditions, conditionAgentResourceQuotaExhausted)
+		// check the existence first so the agent namespace is not requested to delete on every sync.
+		_, err := client.Get(ctx, helpers.AgentResourceQuota, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			return nil
+		case err != nil:
+			return err
+		}
+		err = client.Delete(ctx, helpers.AgentResourceQuota, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		r.recorder.Eventf("ResourceQuotaDeleted", "resource quota %s/%s is deleted", config.AgentNamespace, helpers.AgentResourceQuota)
+		return nil
+	}
+
+	quota, err := client.Get(ctx, helpers.AgentResourceQuota, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		quota, err = client.Create(ctx, required, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create resource quota %s/%s: %v", required.Namespace, required.Name, err)
+		}
+		r.recorder.Eventf("ResourceQuotaCreated", "resource quota %s/%s is created", required.Namespace, required.Name)
+	case err != nil:
+		return err
+	case !equality.Semantic.DeepEqual(quota.Spec.Hard, required.Spec.Hard):
+		quota = quota.DeepCopy()
+		quota.Spec.Hard = required.Spec.Hard
+		quota, err = client.Update(ctx, quota, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update resource quota %s/%s: %v", required.Namespace, required.Name, err)
+		}
+		r.recorder.Eventf("ResourceQuotaUpdated", "resource quota %s/%s is updated", required.Namespace, required.Name)
+	}
+
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, resourceUsageCondition(quota))
+	return nil
+}
+
+// resourceUsageCondition returns the condition of the resource usage in the status of the quota. The usage is
+// calculated by the quota controller, so it is empty until the quota is synced.
+func resourceUsageCondition(quota *corev1.ResourceQuota) metav1.Condition {
+	var names []string
+	for name := range quota.Spec.Hard {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var usages, exhausted []string
+	for _, name := range names {
+		hard := quota.Spec.Hard[corev1.ResourceName(name)]
+		used, ok := quota.Status.Used[corev1.ResourceName(name)]
+		if !ok {
+			usages = append(usages, fmt.Sprintf("%s unknown/%s", name, hard.String()))
+			continue
+		}
+		usages = append(usages, fmt.Sprintf("%s %s/%s", name, used.String(), hard.String()))
+		if used.Cmp(hard) >= 0 {
+			exhausted = append(exhausted, name)
+		}
+	}
+
+	if len(exhausted) > 0 {
+		return metav1.Condition{
+			Type: conditionAgentResourceQuotaExhausted, Status: metav1.ConditionTrue, Reason: reasonResourceQuotaExhausted,
+			Message: fmt.Sprintf("Resources %s of the agents are used up: %s",
+				strings.Join(exhausted, ","), strings.Join(usages, ", ")),
+		}
+	}
+	return metav1.Condition{
+		Type: conditionAgentResourceQuotaExhausted, Status: metav1.ConditionFalse, Reason: reasonResourceQuotaAvailable,
+		Message: fmt.Sprintf("Resource usage of the agents: %s", strings.Join(usages, ", ")),
+	}
+}
+
+func (r *runtimeReconcile) applyPriorityClass(ctx context.Context, config klusterletConfig) error {
+	client := r.kubeClient.SchedulingV1().PriorityClasses()
+	name := helpers.AgentPriorityClass(config.KlusterletName)
+	required := config.HostedIsolation.PriorityClassFor(config.KlusterletName)
+
+	existing, err := client.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if required == nil {
+			return nil
+		}
+	case err != nil:
+		return err
+	case required == nil:
+		return r.deletePriorityClass(ctx, name)
+	case existing.Value == required.Value:
+		return nil
+	default:
+		// the value of the priority class is immutable, so it is recreated, the agents get the new priority once
+		// they are restarted.
+		if err := r.deletePriorityClass(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	if _, err := client.Create(ctx, required, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create priority class %s: %v", name, err)
+	}
+	r.recorder.Eventf("PriorityClassCreated", "priority class %s is created", name)
+	return nil
+}
+
+func (r *runtimeReconcile) deletePriorityClass(ctx context.Context, name string) error {
+	err := r.kubeClient.SchedulingV1().PriorityClasses().Delete(ctx, name, metav1.DeleteOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	r.recorder.Eventf("PriorityClassDeleted", "priority class %s is deleted", name)
+	return nil
+}
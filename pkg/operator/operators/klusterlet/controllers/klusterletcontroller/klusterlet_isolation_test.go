@@ -0,0 +1,144 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+func TestResourceUsageCondition(t *testing.T) {
+	quota := &corev1.ResourceQuota{
+		Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+			corev1.ResourcePods:           resource.MustParse("10"),
+			corev1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+		}},
+	}
+
+	condition := resourceUsageCondition(quota)
+	if condition.Status != metav1.ConditionFalse ||
+		condition.Message != "Resource usage of the agents: pods unknown/10, requests.memory unknown/1Gi" {
+		t.Errorf("unexpected condition of the unsynced quota: %v", condition)
+	}
+
+	quota.Status.Used = corev1.ResourceList{
+		corev1.ResourcePods:           resource.MustParse("10"),
+		corev1.ResourceRequestsMemory: resource.MustParse("512Mi"),
+	}
+	condition = resourceUsageCondition(quota)
+	if condition.Status != metav1.ConditionTrue || condition.Reason != reasonResourceQuotaExhausted ||
+		condition.Message != "Resources pods of the agents are used up: pods 10/10, requests.memory 512Mi/1Gi" {
+		t.Errorf("unexpected condition of the exhausted quota: %v", condition)
+	}
+}
+
+func TestApplyIsolation(t *testing.T) {
+	cases := []struct {
+		name              string
+		existing          []runtime.Object
+		isolation         *helpers.HostedIsolation
+		expectedVerbs     []string
+		expectedCondition bool
+	}{
+		{
+			name:          "no isolation",
+			expectedVerbs: []string{"get", "get"},
+		},
+		{
+			name: "isolation removed",
+			existing: []runtime.Object{
+				&corev1.ResourceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.AgentResourceQuota, Namespace: "klusterlet-cluster1"},
+					Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}},
+				},
+				&schedulingv1.PriorityClass{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.AgentPriorityClass("klusterlet")},
+					Value:      100,
+				},
+			},
+			expectedVerbs: []string{"get", "delete", "get", "delete"},
+		},
+		{
+			name: "create isolation",
+			isolation: &helpers.HostedIsolation{
+				ResourceQuota: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Priority:      ptr.To[int32](1000),
+			},
+			expectedVerbs:     []string{"get", "create", "get", "create"},
+			expectedCondition: true,
+		},
+		{
+			name: "update isolation",
+			existing: []runtime.Object{
+				&corev1.ResourceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.AgentResourceQuota, Namespace: "klusterlet-cluster1"},
+					Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}},
+				},
+				&schedulingv1.PriorityClass{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.AgentPriorityClass("klusterlet")},
+					Value:      100,
+				},
+			},
+			isolation: &helpers.HostedIsolation{
+				ResourceQuota: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Priority:      ptr.To[int32](1000),
+			},
+			expectedVerbs:     []string{"get", "update", "get", "delete", "create"},
+			expectedCondition: true,
+		},
+		{
+			name: "isolation not changed",
+			existing: []runtime.Object{
+				&corev1.ResourceQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.AgentResourceQuota, Namespace: "klusterlet-cluster1"},
+					Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}},
+				},
+				&schedulingv1.PriorityClass{
+					ObjectMeta: metav1.ObjectMeta{Name: helpers.AgentPriorityClass("klusterlet")},
+					Value:      1000,
+				},
+			},
+			isolation: &helpers.HostedIsolation{
+				ResourceQuota: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Priority:      ptr.To[int32](1000),
+			},
+			expectedVerbs:     []string{"get", "get"},
+			expectedCondition: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := fakekube.NewSimpleClientset(c.existing...)
+			r := &runtimeReconcile{
+				kubeClient: kubeClient,
+				recorder:   events.NewInMemoryRecorder("test"),
+			}
+			klusterlet := newKlusterletHosted("klusterlet", "testns", "cluster1")
+			config := klusterletConfig{
+				KlusterletName:  klusterlet.Name,
+				AgentNamespace:  "klusterlet-cluster1",
+				HostedIsolation: c.isolation,
+			}
+
+			if err := r.applyIsolation(context.TODO(), klusterlet, config); err != nil {
+				t.Fatal(err)
+			}
+			testingcommon.AssertActions(t, kubeClient.Actions(), c.expectedVerbs...)
+			if condition := meta.FindStatusCondition(klusterlet.Status.Conditions, conditionAgentResourceQuotaExhausted); (condition != nil) != c.expectedCondition {
+				t.Errorf("expect resource usage condition %v, but got %v", c.expectedCondition, klusterlet.Status.Conditions)
+			}
+		})
+	}
+}
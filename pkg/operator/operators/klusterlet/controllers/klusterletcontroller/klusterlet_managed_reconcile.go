@@ -11,11 +11,14 @@ import (
 	"github.com/openshift/library-go/pkg/assets"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes"
 
@@ -27,11 +30,16 @@ import (
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
 
+const (
+	addonManagementClusterRoleFile = "klusterlet/managed/klusterlet-registration-clusterrole-addon-management.yaml"
+	addonManagementRoleBindingFile = "klusterlet/managed/klusterlet-registration-rolebinding-addon-management.yaml"
+)
+
 var (
 	managedStaticResourceFiles = []string{
 		"klusterlet/managed/klusterlet-registration-serviceaccount.yaml",
 		"klusterlet/managed/klusterlet-registration-clusterrole.yaml",
-		"klusterlet/managed/klusterlet-registration-clusterrole-addon-management.yaml",
+		addonManagementClusterRoleFile,
 		"klusterlet/managed/klusterlet-registration-clusterrolebinding.yaml",
 		"klusterlet/managed/klusterlet-registration-clusterrolebinding-addon-management.yaml",
 		"klusterlet/managed/klusterlet-work-serviceaccount.yaml",
@@ -43,7 +51,15 @@ var (
 	}
 
 	cleanedManagedStaticResourceFiles = append(managedStaticResourceFiles,
-		"klusterlet/managed/klusterlet-work-clusterrolebinding-execution.yaml")
+		"klusterlet/managed/klusterlet-work-clusterrolebinding-execution.yaml",
+		addonManagementRoleBindingFile)
+
+	// minimalRBACExcludedFiles are the broad permissions not granted to the agents in the minimal RBAC mode
+	minimalRBACExcludedFiles = sets.New[string](
+		"klusterlet/managed/klusterlet-registration-clusterrolebinding-addon-management.yaml",
+		"klusterlet/managed/klusterlet-work-clusterrole-execution.yaml",
+		"klusterlet/managed/klusterlet-work-clusterrolebinding-execution-admin.yaml",
+	)
 
 	kube111StaticResourceFiles = []string{
 		"klusterletkube111/klusterlet-registration-operator-clusterrolebinding.yaml",
@@ -73,11 +89,20 @@ func (r *managedReconcile) reconcile(ctx context.Context, klusterlet *operatorap
 		// For now, whether in Default or Hosted mode, the addons will be deployed on the managed cluster.
 		// sync image pull secret from management cluster to managed cluster for addon namespace
 		// TODO(zhujian7): In the future, we may consider deploy addons on the management cluster in Hosted mode.
-		// Ensure the addon namespace on the managed cluster
+		// Ensure the addon namespace on the managed cluster, the config of the addon agents is set as the defaults
+		// of the namespace.
+		addonConfig := config.AgentConfigs[helpers.AddonAgent]
+		addonNamespaceAnnotations, err := helpers.AddonNamespaceAnnotations(addonConfig)
+		if err != nil {
+			return klusterlet, reconcileStop, err
+		}
 		if err := ensureNamespace(
 			ctx,
 			r.managedClusterClients.kubeClient,
-			klusterlet, helpers.DefaultAddonNamespace, labels, r.recorder); err != nil {
+			klusterlet, helpers.DefaultAddonNamespace, labels, addonNamespaceAnnotations, r.recorder); err != nil {
+			return klusterlet, reconcileStop, err
+		}
+		if err := r.applyAddonLimitRange(ctx, helpers.AddonLimitRange(helpers.DefaultAddonNamespace, addonConfig)); err != nil {
 			return klusterlet, reconcileStop, err
 		}
 
@@ -97,11 +122,11 @@ func (r *managedReconcile) reconcile(ctx context.Context, klusterlet *operatorap
 
 	labels[klusterletNamespaceLabelKey] = klusterlet.Name
 	if err := ensureNamespace(
-		ctx, r.managedClusterClients.kubeClient, klusterlet, config.KlusterletNamespace, labels, r.recorder); err != nil {
+		ctx, r.managedClusterClients.kubeClient, klusterlet, config.KlusterletNamespace, labels, nil, r.recorder); err != nil {
 		return klusterlet, reconcileStop, err
 	}
 
-	managedResource := managedStaticResourceFiles
+	managedResource, removedResource := managedStaticResources(config)
 	// If kube version is less than 1.12, deploy static resource for kube 1.11 at first
 	// TODO remove this when we do not support kube 1.11 any longer
 	if cnt, err := r.kubeVersion.Compare("v1.12.0"); err == nil && cnt < 0 {
@@ -133,6 +158,12 @@ func (r *managedReconcile) reconcile(ctx context.Context, klusterlet *operatorap
 		}
 	}
 
+	// remove the permissions not granted in the minimal RBAC mode, they may be applied before the mode is enabled.
+	if err := removeStaticResources(ctx, r.managedClusterClients.kubeClient, r.managedClusterClients.apiExtensionClient,
+		removedResource, config); err != nil {
+		errs = append(errs, err)
+	}
+
 	// add aggregation clusterrole for work, this is not allowed in library-go for now, so need an additional creating
 	if err := r.createAggregationRule(ctx, klusterlet); err != nil {
 		errs = append(errs, err)
@@ -150,6 +181,36 @@ func (r *managedReconcile) reconcile(ctx context.Context, klusterlet *operatorap
 	return klusterlet, reconcileContinue, nil
 }
 
+// managedStaticResources returns the static resources to apply on the managed cluster, and the ones to remove since
+// they are not required by the enabled features in the minimal RBAC mode. In this mode, the work agent is not bound
+// to the admin and execution ClusterRoles, the execution permissions are granted by the ClusterRoles aggregated to
+// the work agent instead. And the addon management permissions of the registration agent are bound in the default
+// addon namespace only.
+func managedStaticResources(config klusterletConfig) (applied, removed []string) {
+	if !config.MinimalRBAC {
+		// the RoleBinding is applied only in the minimal RBAC mode, remove it once the mode is turned off.
+		return managedStaticResourceFiles, []string{addonManagementRoleBindingFile}
+	}
+
+	addonManagement := config.AddonManagement && !config.DisableAddonNamespace
+	for _, file := range managedStaticResourceFiles {
+		switch {
+		case minimalRBACExcludedFiles.Has(file):
+			removed = append(removed, file)
+		case file == addonManagementClusterRoleFile && !addonManagement:
+			removed = append(removed, file)
+		default:
+			applied = append(applied, file)
+		}
+	}
+	if addonManagement {
+		applied = append(applied, addonManagementRoleBindingFile)
+	} else {
+		removed = append(removed, addonManagementRoleBindingFile)
+	}
+	return applied, removed
+}
+
 func (r *managedReconcile) createAggregationRule(ctx context.Context, klusterlet *operatorapiv1.Klusterlet) error {
 	aggregateClusterRoleName := fmt.Sprintf("open-cluster-management:%s-work:aggregate", klusterlet.Name)
 	_, err := r.managedClusterClients.kubeClient.RbacV1().ClusterRoles().Get(ctx, aggregateClusterRoleName, metav1.GetOptions{})
@@ -226,6 +287,36 @@ func (r *managedReconcile) clean(ctx context.Context, klusterlet *operatorapiv1.
 	return klusterlet, reconcileContinue, nil
 }
 
+// applyAddonLimitRange creates or updates the LimitRange of the default resource requirements of the addon agents,
+// the LimitRange is deleted if it exists but the resource requirements are not set any more.
+func (r *managedReconcile) applyAddonLimitRange(ctx context.Context, required *corev1.LimitRange) error {
+	limitRanges := r.managedClusterClients.kubeClient.CoreV1().LimitRanges(helpers.DefaultAddonNamespace)
+	existing, err := limitRanges.Get(ctx, helpers.AddonAgentLimitRangeName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if required == nil {
+			return nil
+		}
+		_, err = limitRanges.Create(ctx, required, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	case required == nil:
+		err = limitRanges.Delete(ctx, helpers.AddonAgentLimitRangeName, metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	case equality.Semantic.DeepEqual(existing.Spec, required.Spec):
+		return nil
+	}
+
+	existing = existing.DeepCopy()
+	existing.Spec = required.Spec
+	_, err = limitRanges.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
 // cleanUpAppliedManifestWorks removes finalizer from the AppliedManifestWorks whose name starts with
 // the hash of the given hub host.
 func (r *managedReconcile) cleanUpAppliedManifestWorks(ctx context.Context, klusterlet *operatorapiv1.Klusterlet, _ klusterletConfig) error {
@@ -54,7 +54,11 @@ func (r *managementReconcile) reconcile(ctx context.Context, klusterlet *operato
 		labels = helpers.GetKlusterletAgentLabels(klusterlet)
 	}
 
-	err := ensureNamespace(ctx, r.kubeClient, klusterlet, config.AgentNamespace, labels, r.recorder)
+	agentNamespaceLabels := labels
+	if config.RestrictedPodSecurity {
+		agentNamespaceLabels = helpers.WithRestrictedPodSecurity(labels)
+	}
+	err := ensureNamespace(ctx, r.kubeClient, klusterlet, config.AgentNamespace, agentNamespaceLabels, nil, r.recorder)
 	if err != nil {
 		return klusterlet, reconcileStop, err
 	}
@@ -0,0 +1,99 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	operatorlister "open-cluster-management.io/api/client/operator/listers/operator/v1"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+// RenderClients are the clients the reconcile of the klusterlet is run against to render the manifests.
+type RenderClients struct {
+	KubeClient         kubernetes.Interface
+	APIExtensionClient apiextensionsclient.Interface
+	WorkClient         workclientset.Interface
+	OperatorClient     operatorclient.Interface
+}
+
+// RenderOptions are the options of the operator the manifests of the klusterlet are rendered with.
+type RenderOptions struct {
+	// KubeVersion is the version of the managed cluster.
+	KubeVersion           *version.Version
+	OperatorNamespace     string
+	DeploymentReplicas    int32
+	DisableAddonNamespace bool
+	EnableSyncLabels      bool
+	RestrictedPodSecurity bool
+	MinimalRBAC           bool
+}
+
+// RenderManifests runs the reconcile of the klusterlet once against the given clients, so the objects the operator
+// applies on the managed cluster are recorded by the clients instead of being applied. The clients are expected to
+// be in-memory clients, so the secrets synced from the operator namespace are not rendered and the bootstrap hub
+// kubeconfig secret still needs to be created separately. The cluster name must be set on the klusterlet, and the
+// Hosted modes are not supported.
+func RenderManifests(ctx context.Context, klusterlet *operatorapiv1.Klusterlet, clients RenderClients,
+	options RenderOptions) error {
+	if helpers.IsHosted(klusterlet.Spec.DeployOption.Mode) {
+		return fmt.Errorf("rendering the manifests of the klusterlet in %s mode is not supported",
+			klusterlet.Spec.DeployOption.Mode)
+	}
+	if len(klusterlet.Spec.ClusterName) == 0 {
+		return fmt.Errorf("the cluster name of the klusterlet is required to render the manifests")
+	}
+
+	klusterlet = klusterlet.DeepCopy()
+	klusterlet.Finalizers = append(klusterlet.Finalizers, klusterletFinalizer)
+	klusterlet.DeletionTimestamp = nil
+	// the agents are rendered as connected to the hub, otherwise the work agent is scaled to 0
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+		Type:   operatorapiv1.ConditionHubConnectionDegraded,
+		Status: metav1.ConditionFalse,
+		Reason: operatorapiv1.ReasonHubConnectionFunctional,
+	})
+	klusterlet, err := clients.OperatorClient.OperatorV1().Klusterlets().Create(ctx, klusterlet, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	klusterletIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := klusterletIndexer.Add(klusterlet); err != nil {
+		return err
+	}
+	recorder := events.NewInMemoryRecorder("render")
+
+	controller := &klusterletController{
+		kubeClient: clients.KubeClient,
+		patcher: patcher.NewPatcher[
+			*operatorapiv1.Klusterlet, operatorapiv1.KlusterletSpec, operatorapiv1.KlusterletStatus](
+			clients.OperatorClient.OperatorV1().Klusterlets()),
+		klusterletLister:  operatorlister.NewKlusterletLister(klusterletIndexer),
+		kubeVersion:       options.KubeVersion,
+		operatorNamespace: options.OperatorNamespace,
+		cache:             resourceapply.NewResourceCache(),
+		managedClusterClientsBuilder: newManagedClusterClientsBuilder(
+			clients.KubeClient, clients.APIExtensionClient, clients.WorkClient.WorkV1().AppliedManifestWorks(), recorder),
+		deploymentReplicas:    options.DeploymentReplicas,
+		disableAddonNamespace: options.DisableAddonNamespace,
+		enableSyncLabels:      options.EnableSyncLabels,
+		restrictedPodSecurity: options.RestrictedPodSecurity,
+		minimalRBAC:           options.MinimalRBAC,
+	}
+
+	return controller.sync(ctx, helpers.NewRenderSyncContext(klusterlet.Name, recorder))
+}
@@ -0,0 +1,414 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+	"open-cluster-management.io/ocm/pkg/operator/render"
+)
+
+func renderManifests(klusterlet *operatorapiv1.Klusterlet, options RenderOptions) ([]runtime.Object, error) {
+	clients := render.NewClients()
+	if err := RenderManifests(context.TODO(), klusterlet, RenderClients{
+		KubeClient:         clients.Kube,
+		APIExtensionClient: clients.APIExtensions,
+		WorkClient:         clients.Work,
+		OperatorClient:     clients.Operator,
+	}, options); err != nil {
+		return nil, err
+	}
+	return clients.Objects()
+}
+
+func TestRenderManifests(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	var namespaces []string
+	deployments := map[string]*appsv1.Deployment{}
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *corev1.Namespace:
+			namespaces = append(namespaces, o.Name)
+		case *appsv1.Deployment:
+			deployments[o.Name] = o
+		}
+	}
+	testingcommon.AssertEqualNameNamespace(t, namespaces[0], "", helpers.DefaultAddonNamespace, "")
+	testingcommon.AssertEqualNumber(t, len(namespaces), 2)
+	testingcommon.AssertEqualNumber(t, len(deployments), 2)
+	// the work agent is rendered as connected to the hub
+	work, ok := deployments["klusterlet-work-agent"]
+	if !ok {
+		t.Fatalf("Expected the work agent deployment, but got %v", deployments)
+	}
+	if *work.Spec.Replicas == 0 {
+		t.Errorf("Expected the work agent is not scaled to 0")
+	}
+
+	klusterlet.Spec.ClusterName = ""
+	if _, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace}); err == nil {
+		t.Errorf("Expected error when render without cluster name")
+	}
+}
+
+func TestRenderManifestsProxyConfig(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{
+		helpers.ProxyConfigAnnotation: `{"httpsProxy": "http://proxy:3128", "noProxy": ".svc"}`,
+	}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	deployments := 0
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *corev1.ConfigMap:
+			if o.Name == helpers.ProxyCABundleConfigMap {
+				t.Errorf("Expected no proxy ca bundle without the ca bundle")
+			}
+		case *appsv1.Deployment:
+			deployments++
+			for _, container := range o.Spec.Template.Spec.Containers {
+				env := map[string]string{}
+				for _, e := range container.Env {
+					env[e.Name] = e.Value
+				}
+				if env["HTTPS_PROXY"] != "http://proxy:3128" || env["NO_PROXY"] != ".svc" {
+					t.Errorf("Expected proxy env in container %s of deployment %s, but got %v", container.Name, o.Name, container.Env)
+				}
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, deployments, 2)
+
+	klusterlet.Annotations[helpers.ProxyConfigAnnotation] = `{"httpsProxy": "proxy"}`
+	if _, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace}); err == nil {
+		t.Errorf("Expected error when render with invalid proxy config")
+	}
+}
+
+func TestRenderManifestsRestrictedPodSecurity(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+
+	objects, err := renderManifests(klusterlet, RenderOptions{
+		KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace, RestrictedPodSecurity: true})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	deployments := 0
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *corev1.Namespace:
+			enforced := o.Labels[helpers.PodSecurityEnforceLabel] == helpers.PodSecurityRestricted
+			if expected := o.Name == "testns"; enforced != expected {
+				t.Errorf("Expected restricted pod security enforced %v on namespace %s, but got %v", expected, o.Name, o.Labels)
+			}
+		case *appsv1.Deployment:
+			deployments++
+			securityContext := o.Spec.Template.Spec.SecurityContext
+			if securityContext == nil || securityContext.SeccompProfile == nil ||
+				securityContext.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
+				t.Errorf("Expected RuntimeDefault seccomp profile of deployment %s, but got %v", o.Name, securityContext)
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, deployments, 2)
+}
+
+func TestRenderManifestsMinimalRBAC(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Spec.WorkConfiguration.FeatureGates = []operatorapiv1.FeatureGate{
+		{Feature: "ExecutorValidatingCaches", Mode: operatorapiv1.FeatureGateModeTypeEnable},
+	}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace, MinimalRBAC: true})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	addonManagementRoleBindings, deployments := 0, 0
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *rbacv1.ClusterRoleBinding:
+			if o.RoleRef.Name == "admin" || o.Name == "open-cluster-management:klusterlet-registration:addon-management" {
+				t.Errorf("Expected no broad ClusterRoleBinding, but got %s", o.Name)
+			}
+		case *rbacv1.ClusterRole:
+			if o.Name == "open-cluster-management:klusterlet-work:execution" {
+				t.Errorf("Expected no execution ClusterRole rendered")
+			}
+			if o.Name == "open-cluster-management:klusterlet-work:agent" && len(o.Rules) != 6 {
+				t.Errorf("Expected the permissions of the executor validating caches, but got %v", o.Rules)
+			}
+		case *rbacv1.RoleBinding:
+			if o.Name == "open-cluster-management:klusterlet-registration:addon-management" {
+				testingcommon.AssertEqualNameNamespace(t, o.Name, o.Namespace, o.Name, helpers.DefaultAddonNamespace)
+				addonManagementRoleBindings++
+			}
+		case *appsv1.Deployment:
+			deployments++
+			if !sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has("--report-denied-actions") {
+				t.Errorf("Expected the denied actions reported by deployment %s, but got %v",
+					o.Name, o.Spec.Template.Spec.Containers[0].Args)
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, addonManagementRoleBindings, 1)
+	testingcommon.AssertEqualNumber(t, deployments, 2)
+}
+
+func TestManagedStaticResources(t *testing.T) {
+	cases := []struct {
+		name                   string
+		config                 klusterletConfig
+		expectedRoleBinding    bool
+		expectedRemovedBinding bool
+	}{
+		{
+			name:                   "minimal RBAC off",
+			config:                 klusterletConfig{},
+			expectedRemovedBinding: true,
+		},
+		{
+			name:                "minimal RBAC with addon management",
+			config:              klusterletConfig{MinimalRBAC: true, AddonManagement: true},
+			expectedRoleBinding: true,
+		},
+		{
+			name:                   "minimal RBAC without addon management",
+			config:                 klusterletConfig{MinimalRBAC: true},
+			expectedRemovedBinding: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			applied, removed := managedStaticResources(c.config)
+			if sets.New(applied...).Has(addonManagementRoleBindingFile) != c.expectedRoleBinding {
+				t.Errorf("Expected the RoleBinding applied %v, but got %v", c.expectedRoleBinding, applied)
+			}
+			if sets.New(removed...).Has(addonManagementRoleBindingFile) != c.expectedRemovedBinding {
+				t.Errorf("Expected the RoleBinding removed %v, but got %v", c.expectedRemovedBinding, removed)
+			}
+		})
+	}
+}
+
+func TestRenderManifestsAgentIdentityRotation(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.AgentIdentityRotationAnnotation: "2"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		rotated := sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--agent-identity-generation=2")
+		if expected := deployment.Name == "klusterlet-registration-agent"; rotated != expected {
+			t.Errorf("Expected agent identity generation %v of deployment %s, but got %v",
+				expected, deployment.Name, deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+}
+
+func TestRenderManifestsAddOnKubeconfigExecCredential(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.AddOnKubeconfigExecCredentialAnnotation: "true"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		allowed := sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--addon-kubeconfig-exec-credential")
+		if expected := deployment.Name == "klusterlet-registration-agent"; allowed != expected {
+			t.Errorf("Expected the exec credential allowed %v of deployment %s, but got %v",
+				expected, deployment.Name, deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+}
+
+func TestRenderManifestsPaused(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.KlusterletPausedAnnotation: "true"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	deployments := 0
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		deployments++
+		if !sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--paused") {
+			t.Errorf("Expected deployment %s paused, but got %v", deployment.Name, deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+	testingcommon.AssertEqualNumber(t, deployments, 2)
+}
+
+func TestRenderManifestsResourceUsageReport(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.ResourceUsageReportIntervalAnnotation: "5m"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	clusterRoles := 0
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *appsv1.Deployment:
+			reported := sets.New(o.Spec.Template.Spec.Containers[0].Args...).Has("--resource-usage-report-interval=5m0s")
+			if expected := o.Name == "klusterlet-registration-agent"; reported != expected {
+				t.Errorf("Expected the resource usage reported %v of deployment %s, but got %v",
+					expected, o.Name, o.Spec.Template.Spec.Containers[0].Args)
+			}
+		case *rbacv1.ClusterRole:
+			if o.Name != "open-cluster-management:klusterlet-registration:agent" {
+				continue
+			}
+			clusterRoles++
+			resources := sets.New[string]()
+			for _, rule := range o.Rules {
+				for _, resource := range rule.Resources {
+					resources.Insert(rule.APIGroups[0] + "/" + resource)
+				}
+			}
+			if !resources.HasAll("/pods", "metrics.k8s.io/nodes") {
+				t.Errorf("Expected the registration agent is allowed to list the pods and node metrics, but got %v", o.Rules)
+			}
+		}
+	}
+	testingcommon.AssertEqualNumber(t, clusterRoles, 1)
+
+	klusterlet.Annotations = map[string]string{helpers.ResourceUsageReportIntervalAnnotation: "invalid"}
+	if _, err := helpers.GetResourceUsageReportInterval(klusterlet); err == nil {
+		t.Errorf("Expected error with the invalid interval")
+	}
+}
+
+func TestRenderManifestsSPIFFETrustDomain(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.SPIFFETrustDomainAnnotation: "example.org"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		set := sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--spiffe-trust-domain=example.org")
+		if expected := deployment.Name == "klusterlet-registration-agent"; set != expected {
+			t.Errorf("Expected the spiffe trust domain set %v of deployment %s, but got %v",
+				expected, deployment.Name, deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+
+	klusterlet.Annotations = map[string]string{helpers.SPIFFETrustDomainAnnotation: "spiffe://example.org"}
+	if _, err := helpers.GetSPIFFETrustDomain(klusterlet); err == nil {
+		t.Errorf("Expected error with the invalid trust domain")
+	}
+}
+
+func TestRenderManifestsHubCircuitBreakerThreshold(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.HubCircuitBreakerThresholdAnnotation: "0"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	deployments := 0
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		deployments++
+		if !sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--hub-circuit-breaker-threshold=0") {
+			t.Errorf("Expected the hub circuit breaker threshold set of deployment %s, but got %v",
+				deployment.Name, deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+	if deployments == 0 {
+		t.Errorf("Expected the agent deployments are rendered")
+	}
+
+	klusterlet.Annotations = map[string]string{helpers.HubCircuitBreakerThresholdAnnotation: "-1"}
+	if _, err := helpers.GetHubCircuitBreakerThreshold(klusterlet); err == nil {
+		t.Errorf("Expected error with the negative threshold")
+	}
+}
+
+func TestRenderManifestsHubCABundleDistribution(t *testing.T) {
+	kubeVersion, _ := version.ParseGeneric("v1.30.0")
+	klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+	klusterlet.Annotations = map[string]string{helpers.HubCABundleDistributionAnnotation: "true"}
+
+	objects, err := renderManifests(klusterlet, RenderOptions{KubeVersion: kubeVersion, OperatorNamespace: helpers.DefaultComponentNamespace})
+	if err != nil {
+		t.Fatalf("Expected no error when render, %v", err)
+	}
+
+	for _, object := range objects {
+		deployment, ok := object.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		enabled := sets.New(deployment.Spec.Template.Spec.Containers[0].Args...).Has("--hub-ca-bundle-distribution")
+		if expected := deployment.Name == "klusterlet-registration-agent"; enabled != expected {
+			t.Errorf("Expected the hub ca bundle distribution %v of deployment %s, but got %v",
+				expected, deployment.Name, deployment.Spec.Template.Spec.Containers[0].Args)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/library-go/pkg/assets"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +24,18 @@ import (
 	"open-cluster-management.io/ocm/pkg/operator/helpers"
 )
 
+const (
+	// managedKubeConfigHashAnnotation is the annotation on the pod template of the agents in Hosted mode with the
+	// hash of the external managed kubeconfig secret, so the agents are restarted when it is rotated.
+	managedKubeConfigHashAnnotation = "operator.open-cluster-management.io/external-managed-kubeconfig-hash"
+
+	// conditionManagedKubeConfigRotated is true while the agents are restarted for the rotated external managed
+	// kubeconfig, and false once they are restarted. The last transition time is the time they are restarted.
+	conditionManagedKubeConfigRotated = "ExternalManagedKubeConfigRotated"
+	reasonAgentsRestarting            = "AgentsRestarting"
+	reasonAgentsRestarted             = "AgentsRestarted"
+)
+
 // runtimeReconcile ensure all runtime of klusterlet is applied
 type runtimeReconcile struct {
 	managedClusterClients *managedClusterClients
@@ -34,6 +47,16 @@ type runtimeReconcile struct {
 
 func (r *runtimeReconcile) reconcile(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
 	config klusterletConfig) (*operatorapiv1.Klusterlet, reconcileState, error) {
+	if err := r.applyProxyCABundle(ctx, config); err != nil {
+		return klusterlet, reconcileStop, err
+	}
+
+	if helpers.IsHosted(config.InstallMode) {
+		if err := r.applyIsolation(ctx, klusterlet, config); err != nil {
+			return klusterlet, reconcileStop, err
+		}
+	}
+
 	if helpers.IsSingleton(config.InstallMode) {
 		return r.installSingletonAgent(ctx, klusterlet, config)
 	}
@@ -55,24 +78,34 @@ func (r *runtimeReconcile) installAgent(ctx context.Context, klusterlet *operato
 			r.recorder); err != nil {
 			return klusterlet, reconcileStop, err
 		}
+		if err := r.checkManagedKubeConfigRotated(ctx, klusterlet, runtimeConfig.AgentNamespace,
+			fmt.Sprintf("%s-registration-agent", runtimeConfig.KlusterletName)); err != nil {
+			return klusterlet, reconcileStop, err
+		}
 	}
 	// Deploy registration agent
+	registrationConfig, registrationNodePlacement, err := runtimeConfig.forAgent(helpers.RegistrationAgent, klusterlet.Spec.NodePlacement)
+	if err != nil {
+		return klusterlet, reconcileStop, err
+	}
 	_, generationStatus, err := helpers.ApplyDeployment(
 		ctx,
 		r.kubeClient,
 		klusterlet.Status.Generations,
-		klusterlet.Spec.NodePlacement,
+		registrationNodePlacement,
 		func(name string) ([]byte, error) {
 			template, err := manifests.KlusterletManifestFiles.ReadFile(name)
 			if err != nil {
 				return nil, err
 			}
-			objData := assets.MustCreateAssetFromTemplate(name, template, runtimeConfig).Data
+			objData := assets.MustCreateAssetFromTemplate(name, template, registrationConfig).Data
 			helpers.SetRelatedResourcesStatusesWithObj(&klusterlet.Status.RelatedResources, objData)
 			return objData, nil
 		},
 		r.recorder,
-		"klusterlet/management/klusterlet-registration-deployment.yaml")
+		"klusterlet/management/klusterlet-registration-deployment.yaml",
+		registrationConfig.ProxyConfig.ApplyBootstrapToDeployment,
+		r.setManagedKubeConfigHash)
 
 	if err != nil {
 		// TODO update condition
@@ -106,11 +139,15 @@ func (r *runtimeReconcile) installAgent(ctx context.Context, klusterlet *operato
 	}
 
 	// Deploy work agent
+	workConfig, workNodePlacement, err := workConfig.forAgent(helpers.WorkAgent, klusterlet.Spec.NodePlacement)
+	if err != nil {
+		return klusterlet, reconcileStop, err
+	}
 	_, generationStatus, err = helpers.ApplyDeployment(
 		ctx,
 		r.kubeClient,
 		klusterlet.Status.Generations,
-		klusterlet.Spec.NodePlacement,
+		workNodePlacement,
 		func(name string) ([]byte, error) {
 			template, err := manifests.KlusterletManifestFiles.ReadFile(name)
 			if err != nil {
@@ -121,7 +158,9 @@ func (r *runtimeReconcile) installAgent(ctx context.Context, klusterlet *operato
 			return objData, nil
 		},
 		r.recorder,
-		"klusterlet/management/klusterlet-work-deployment.yaml")
+		"klusterlet/management/klusterlet-work-deployment.yaml",
+		workConfig.ProxyConfig.ApplyToDeployment,
+		r.setManagedKubeConfigHash)
 
 	if err != nil {
 		// TODO update condition
@@ -154,24 +193,34 @@ func (r *runtimeReconcile) installSingletonAgent(ctx context.Context, klusterlet
 			r.recorder); err != nil {
 			return klusterlet, reconcileStop, err
 		}
+		if err := r.checkManagedKubeConfigRotated(ctx, klusterlet, config.AgentNamespace,
+			fmt.Sprintf("%s-agent", config.KlusterletName)); err != nil {
+			return klusterlet, reconcileStop, err
+		}
 	}
 	// Deploy singleton agent
+	agentConfig, agentNodePlacement, err := config.forAgent(helpers.SingletonAgent, klusterlet.Spec.NodePlacement)
+	if err != nil {
+		return klusterlet, reconcileStop, err
+	}
 	_, generationStatus, err := helpers.ApplyDeployment(
 		ctx,
 		r.kubeClient,
 		klusterlet.Status.Generations,
-		klusterlet.Spec.NodePlacement,
+		agentNodePlacement,
 		func(name string) ([]byte, error) {
 			template, err := manifests.KlusterletManifestFiles.ReadFile(name)
 			if err != nil {
 				return nil, err
 			}
-			objData := assets.MustCreateAssetFromTemplate(name, template, config).Data
+			objData := assets.MustCreateAssetFromTemplate(name, template, agentConfig).Data
 			helpers.SetRelatedResourcesStatusesWithObj(&klusterlet.Status.RelatedResources, objData)
 			return objData, nil
 		},
 		r.recorder,
-		"klusterlet/management/klusterlet-agent-deployment.yaml")
+		"klusterlet/management/klusterlet-agent-deployment.yaml",
+		agentConfig.ProxyConfig.ApplyBootstrapToDeployment,
+		r.setManagedKubeConfigHash)
 
 	if err != nil {
 		// TODO update condition
@@ -194,6 +243,93 @@ func (r *runtimeReconcile) installSingletonAgent(ctx context.Context, klusterlet
 	return klusterlet, reconcileContinue, nil
 }
 
+// checkManagedKubeConfigRotated sets the condition if the external managed kubeconfig is changed since the agent
+// deployment was applied, the deployment is then restarted with the new hash. The condition is set to false once
+// the deployment is rolled out with the new hash.
+func (r *runtimeReconcile) checkManagedKubeConfigRotated(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
+	namespace, deploymentName string) error {
+	deployment, err := r.kubeClient.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	hash, ok := deployment.Spec.Template.Annotations[managedKubeConfigHashAnnotation]
+	if !ok {
+		return nil
+	}
+	if hash == r.managedClusterClients.kubeconfigHash {
+		if meta.IsStatusConditionTrue(klusterlet.Status.Conditions, conditionManagedKubeConfigRotated) &&
+			deploymentRolledOut(deployment) {
+			meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+				Type: conditionManagedKubeConfigRotated, Status: metav1.ConditionFalse, Reason: reasonAgentsRestarted,
+				Message: "The agents are restarted with the rotated external managed kubeconfig",
+			})
+		}
+		return nil
+	}
+
+	r.recorder.Eventf("ExternalManagedKubeConfigRotated",
+		"external managed kubeconfig of klusterlet %s is rotated, restart the agents", klusterlet.Name)
+	// remove the condition at first to update the last transition time
+	meta.RemoveStatusCondition(&klusterlet.Status.Conditions, conditionManagedKubeConfigRotated)
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+		Type: conditionManagedKubeConfigRotated, Status: metav1.ConditionTrue, Reason: reasonAgentsRestarting,
+		Message: "The external managed kubeconfig is rotated and the connectivity is validated, the agents are restarting to use it",
+	})
+	return nil
+}
+
+// deploymentRolledOut returns true if all the replicas of the deployment are updated to its latest generation and
+// available.
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// setManagedKubeConfigHash sets the hash of the external managed kubeconfig on the pod template of the agent
+// deployment in Hosted mode.
+func (r *runtimeReconcile) setManagedKubeConfigHash(deployment *appsv1.Deployment) {
+	if len(r.managedClusterClients.kubeconfigHash) == 0 {
+		return
+	}
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations[managedKubeConfigHashAnnotation] = r.managedClusterClients.kubeconfigHash
+}
+
+// applyProxyCABundle applies the configmap of the proxy CA bundle mounted by the agents, or deletes it if the
+// CA bundle is not set.
+func (r *runtimeReconcile) applyProxyCABundle(ctx context.Context, config klusterletConfig) error {
+	caBundle := config.ProxyConfig.ProxyCABundle(config.AgentNamespace)
+	if caBundle == nil {
+		_, err := r.kubeClient.CoreV1().ConfigMaps(config.AgentNamespace).Get(ctx, helpers.ProxyCABundleConfigMap, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+			return nil
+		case err != nil:
+			return err
+		}
+		err = r.kubeClient.CoreV1().ConfigMaps(config.AgentNamespace).Delete(ctx, helpers.ProxyCABundleConfigMap, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, _, err := resourceapply.ApplyConfigMap(ctx, r.kubeClient.CoreV1(), r.recorder, caBundle)
+	return err
+}
+
 func (r *runtimeReconcile) createManagedClusterKubeconfig(
 	ctx context.Context,
 	klusterlet *operatorapiv1.Klusterlet,
@@ -254,5 +390,12 @@ func (r *runtimeReconcile) clean(ctx context.Context, klusterlet *operatorapiv1.
 		r.recorder.Eventf("DeploymentDeleted", "deployment %s is deleted", deployment)
 	}
 
+	// the resource quota is removed with the agent namespace, while the priority class is cluster scoped.
+	if helpers.IsHosted(klusterlet.Spec.DeployOption.Mode) {
+		if err := r.deletePriorityClass(ctx, helpers.AgentPriorityClass(config.KlusterletName)); err != nil {
+			return klusterlet, reconcileStop, err
+		}
+	}
+
 	return klusterlet, reconcileContinue, nil
 }
@@ -0,0 +1,237 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+const (
+	// upgradeStateConfigMap is the configmap in the agent namespace to record the state of the staged upgrade.
+	upgradeStateConfigMap = "klusterlet-upgrade-state"
+	upgradeStateKey       = "state"
+
+	// conditionAgentUpgradeProgressing is true when the upgraded agents are rolled out and not available yet.
+	conditionAgentUpgradeProgressing = "AgentUpgradeProgressing"
+	reasonAgentUpgradeProgressing    = "UpgradeProgressing"
+	reasonAgentUpgradeSucceeded      = "UpgradeSucceeded"
+	reasonAgentUpgradeRolledBack     = "UpgradeRolledBack"
+)
+
+// agentImages are the images of the agents which are upgraded together.
+type agentImages struct {
+	Registration string `json:"registration,omitempty"`
+	Work         string `json:"work,omitempty"`
+	Singleton    string `json:"singleton,omitempty"`
+}
+
+func imagesOf(config klusterletConfig) agentImages {
+	return agentImages{
+		Registration: config.RegistrationImage,
+		Work:         config.WorkImage,
+		Singleton:    config.SingletonImage,
+	}
+}
+
+func (images agentImages) applyTo(config *klusterletConfig) {
+	config.RegistrationImage = images.Registration
+	config.WorkImage = images.Work
+	config.SingletonImage = images.Singleton
+}
+
+// upgradeState is the state of the staged upgrade of the agents.
+type upgradeState struct {
+	// Stable are the images the agents were available with.
+	Stable agentImages `json:"stable"`
+	// Candidate are the images the agents are upgraded to.
+	Candidate *agentImages `json:"candidate,omitempty"`
+	// StartTime is the time the candidate images are rolled out.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// RolledBack is true if the agents did not become available with the candidate images within the timeout.
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// stageUpgrade decides the images of the agents to apply when the staged upgrade is enabled. The images in the
+// config are the candidate ones, they are replaced by the stable ones if the candidate ones failed to become
+// available within the timeout. It returns the state to save once the agents are applied, and the time after
+// which the upgrade should be checked again, which is 0 if the upgrade is not progressing.
+func (n *klusterletController) stageUpgrade(ctx context.Context, klusterlet *operatorapiv1.Klusterlet,
+	config *klusterletConfig, upgradeConfig *helpers.StagedUpgradeConfig, recorder events.Recorder) (*upgradeState, time.Duration, error) {
+	if upgradeConfig == nil {
+		_, err := n.kubeClient.CoreV1().ConfigMaps(config.AgentNamespace).Get(ctx, upgradeStateConfigMap, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(err):
+		case err != nil:
+			return nil, 0, err
+		default:
+			err = n.kubeClient.CoreV1().ConfigMaps(config.AgentNamespace).Delete(ctx, upgradeStateConfigMap, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, 0, err
+			}
+		}
+		meta.RemoveStatusCondition(&klusterlet.Status.Conditions, conditionAgentUpgradeProgressing)
+		return nil, 0, nil
+	}
+
+	candidate := imagesOf(*config)
+	state, err := n.getUpgradeState(ctx, config.AgentNamespace)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case state == nil:
+		// the agents are installed at the first time, there is nothing to roll back to.
+		return &upgradeState{Stable: candidate}, 0, nil
+	case state.Stable == candidate:
+		return &upgradeState{Stable: candidate}, 0, nil
+	case state.Candidate == nil || *state.Candidate != candidate:
+		recorder.Eventf("AgentUpgradeStarted", "agents of klusterlet %s are upgraded to %+v", klusterlet.Name, candidate)
+		state = &upgradeState{Stable: state.Stable, Candidate: &candidate, StartTime: &metav1.Time{Time: time.Now()}}
+	}
+
+	if state.RolledBack {
+		state.Stable.applyTo(config)
+		return state, 0, nil
+	}
+
+	rolledOut, err := n.agentsRolledOut(ctx, *config)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rolledOut && agentsAvailable(klusterlet) {
+		recorder.Eventf("AgentUpgradeSucceeded", "agents of klusterlet %s are upgraded to %+v", klusterlet.Name, candidate)
+		meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+			Type: conditionAgentUpgradeProgressing, Status: metav1.ConditionFalse, Reason: reasonAgentUpgradeSucceeded,
+			Message: fmt.Sprintf("Agents are upgraded to %+v", candidate),
+		})
+		return &upgradeState{Stable: candidate}, 0, nil
+	}
+
+	remaining := time.Until(state.StartTime.Add(upgradeConfig.Timeout.Duration))
+	if remaining <= 0 {
+		recorder.Warningf("AgentUpgradeRolledBack", "agents of klusterlet %s are not available with %+v in %s, roll back to %+v",
+			klusterlet.Name, candidate, upgradeConfig.Timeout.Duration, state.Stable)
+		meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+			Type: conditionAgentUpgradeProgressing, Status: metav1.ConditionFalse, Reason: reasonAgentUpgradeRolledBack,
+			Message: fmt.Sprintf("Agents are not available with %+v in %s and are rolled back to %+v",
+				candidate, upgradeConfig.Timeout.Duration, state.Stable),
+		})
+		state.RolledBack = true
+		state.Stable.applyTo(config)
+		return state, 0, nil
+	}
+
+	meta.SetStatusCondition(&klusterlet.Status.Conditions, metav1.Condition{
+		Type: conditionAgentUpgradeProgressing, Status: metav1.ConditionTrue, Reason: reasonAgentUpgradeProgressing,
+		Message: fmt.Sprintf("Agents are upgraded to %+v, waiting for them to be available", candidate),
+	})
+	return state, remaining, nil
+}
+
+// agentsRolledOut checks if all the replicas of the agent deployments are updated to the images of the config and
+// the deployments are available.
+func (n *klusterletController) agentsRolledOut(ctx context.Context, config klusterletConfig) (bool, error) {
+	images := map[string]string{
+		fmt.Sprintf("%s-registration-agent", config.KlusterletName): config.RegistrationImage,
+		fmt.Sprintf("%s-work-agent", config.KlusterletName):         config.WorkImage,
+	}
+	if helpers.IsSingleton(config.InstallMode) {
+		images = map[string]string{fmt.Sprintf("%s-agent", config.KlusterletName): config.SingletonImage}
+	}
+
+	for name, image := range images {
+		deployment, err := n.kubeClient.AppsV1().Deployments(config.AgentNamespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		containers := deployment.Spec.Template.Spec.Containers
+		if len(containers) == 0 || containers[0].Image != image {
+			return false, nil
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation ||
+			deployment.Status.UpdatedReplicas != replicas ||
+			helpers.NumOfUnavailablePod(deployment) > 0 {
+			return false, nil
+		}
+		if !deploymentConditionTrue(deployment, appsv1.DeploymentAvailable) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func deploymentConditionTrue(deployment *appsv1.Deployment, conditionType appsv1.DeploymentConditionType) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// agentsAvailable checks the availability conditions of the agents reported on the klusterlet, the registration and
+// work agents are not degraded and the agents are connected to the hub.
+func agentsAvailable(klusterlet *operatorapiv1.Klusterlet) bool {
+	for _, conditionType := range []string{
+		operatorapiv1.ConditionRegistrationDesiredDegraded,
+		operatorapiv1.ConditionWorkDesiredDegraded,
+		operatorapiv1.ConditionHubConnectionDegraded,
+	} {
+		if meta.IsStatusConditionTrue(klusterlet.Status.Conditions, conditionType) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *klusterletController) getUpgradeState(ctx context.Context, namespace string) (*upgradeState, error) {
+	configMap, err := n.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, upgradeStateConfigMap, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &upgradeState{}
+	if err := json.Unmarshal([]byte(configMap.Data[upgradeStateKey]), state); err != nil {
+		return nil, fmt.Errorf("invalid upgrade state in configmap %s/%s: %v", namespace, upgradeStateConfigMap, err)
+	}
+	return state, nil
+}
+
+func (n *klusterletController) saveUpgradeState(ctx context.Context, namespace string, state *upgradeState,
+	recorder events.Recorder) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = resourceapply.ApplyConfigMap(ctx, n.kubeClient.CoreV1(), recorder, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: upgradeStateConfigMap, Namespace: namespace},
+		Data:       map[string]string{upgradeStateKey: string(data)},
+	})
+	return err
+}
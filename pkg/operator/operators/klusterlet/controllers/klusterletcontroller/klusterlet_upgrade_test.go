@@ -0,0 +1,202 @@
+package klusterletcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+func newUpgradeState(t *testing.T, state upgradeState) *corev1.ConfigMap {
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: upgradeStateConfigMap, Namespace: "testns"},
+		Data:       map[string]string{upgradeStateKey: string(data)},
+	}
+}
+
+func newAgentDeployment(name, image string, available bool) *appsv1.Deployment {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testns", Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "agent", Image: image}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1},
+	}
+	if available {
+		deployment.Status.AvailableReplicas = 1
+		deployment.Status.Conditions = []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		}
+	}
+	return deployment
+}
+
+func TestStageUpgrade(t *testing.T) {
+	stable := agentImages{Registration: "registration:v1", Work: "work:v1", Singleton: "agent:v1"}
+	candidate := agentImages{Registration: "registration:v2", Work: "work:v2", Singleton: "agent:v2"}
+	upgradeConfig := &helpers.StagedUpgradeConfig{Timeout: metav1.Duration{Duration: 10 * time.Minute}}
+	now := metav1.Now()
+	expired := metav1.NewTime(now.Add(-time.Hour))
+
+	cases := []struct {
+		name            string
+		existingObjects []runtime.Object
+		conditions      []metav1.Condition
+		upgradeConfig   *helpers.StagedUpgradeConfig
+		expectedImages  agentImages
+		expectedState   *upgradeState
+		expectedReason  string
+		expectedRecheck bool
+		expectedRemoved bool
+	}{
+		{
+			name:            "disabled",
+			existingObjects: []runtime.Object{newUpgradeState(t, upgradeState{Stable: stable})},
+			expectedImages:  candidate,
+			expectedRemoved: true,
+		},
+		{
+			name:           "first install",
+			upgradeConfig:  upgradeConfig,
+			expectedImages: candidate,
+			expectedState:  &upgradeState{Stable: candidate},
+		},
+		{
+			name:            "start upgrade",
+			upgradeConfig:   upgradeConfig,
+			existingObjects: []runtime.Object{newUpgradeState(t, upgradeState{Stable: stable})},
+			expectedImages:  candidate,
+			expectedState:   &upgradeState{Stable: stable, Candidate: &candidate},
+			expectedReason:  reasonAgentUpgradeProgressing,
+			expectedRecheck: true,
+		},
+		{
+			name:          "upgrade succeeded",
+			upgradeConfig: upgradeConfig,
+			existingObjects: []runtime.Object{
+				newUpgradeState(t, upgradeState{Stable: stable, Candidate: &candidate, StartTime: &now}),
+				newAgentDeployment("klusterlet-registration-agent", candidate.Registration, true),
+				newAgentDeployment("klusterlet-work-agent", candidate.Work, true),
+			},
+			expectedImages: candidate,
+			expectedState:  &upgradeState{Stable: candidate},
+			expectedReason: reasonAgentUpgradeSucceeded,
+		},
+		{
+			name:          "upgraded agents degraded",
+			upgradeConfig: upgradeConfig,
+			existingObjects: []runtime.Object{
+				newUpgradeState(t, upgradeState{Stable: stable, Candidate: &candidate, StartTime: &now}),
+				newAgentDeployment("klusterlet-registration-agent", candidate.Registration, true),
+				newAgentDeployment("klusterlet-work-agent", candidate.Work, true),
+			},
+			conditions: []metav1.Condition{
+				{Type: operatorapiv1.ConditionWorkDesiredDegraded, Status: metav1.ConditionTrue},
+			},
+			expectedImages:  candidate,
+			expectedState:   &upgradeState{Stable: stable, Candidate: &candidate},
+			expectedReason:  reasonAgentUpgradeProgressing,
+			expectedRecheck: true,
+		},
+		{
+			name:          "upgrade not available in time",
+			upgradeConfig: upgradeConfig,
+			existingObjects: []runtime.Object{
+				newUpgradeState(t, upgradeState{Stable: stable, Candidate: &candidate, StartTime: &expired}),
+				newAgentDeployment("klusterlet-registration-agent", candidate.Registration, false),
+				newAgentDeployment("klusterlet-work-agent", candidate.Work, true),
+			},
+			expectedImages: stable,
+			expectedState:  &upgradeState{Stable: stable, Candidate: &candidate, RolledBack: true},
+			expectedReason: reasonAgentUpgradeRolledBack,
+		},
+		{
+			name:          "upgrade rolled back",
+			upgradeConfig: upgradeConfig,
+			existingObjects: []runtime.Object{
+				newUpgradeState(t, upgradeState{Stable: stable, Candidate: &candidate, StartTime: &expired, RolledBack: true}),
+			},
+			expectedImages: stable,
+			expectedState:  &upgradeState{Stable: stable, Candidate: &candidate, RolledBack: true},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := fakekube.NewSimpleClientset(c.existingObjects...)
+			controller := &klusterletController{kubeClient: kubeClient}
+			klusterlet := newKlusterlet("klusterlet", "testns", "cluster1")
+			klusterlet.Status.Conditions = c.conditions
+			config := klusterletConfig{
+				KlusterletName: "klusterlet",
+				AgentNamespace: "testns",
+				InstallMode:    operatorapiv1.InstallModeDefault,
+			}
+			candidate.applyTo(&config)
+
+			state, recheck, err := controller.stageUpgrade(context.TODO(), klusterlet, &config, c.upgradeConfig,
+				events.NewInMemoryRecorder("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if imagesOf(config) != c.expectedImages {
+				t.Errorf("expect images %+v, but got %+v", c.expectedImages, imagesOf(config))
+			}
+			if c.expectedRecheck != (recheck > 0) {
+				t.Errorf("expect recheck %v, but got %v", c.expectedRecheck, recheck)
+			}
+			if c.expectedState == nil {
+				if state != nil {
+					t.Errorf("expect no state, but got %+v", state)
+				}
+			} else {
+				if state == nil {
+					t.Fatalf("expect state %+v, but got nil", c.expectedState)
+				}
+				state.StartTime = nil
+				c.expectedState.StartTime = nil
+				expected, _ := json.Marshal(c.expectedState)
+				actual, _ := json.Marshal(state)
+				if string(expected) != string(actual) {
+					t.Errorf("expect state %s, but got %s", expected, actual)
+				}
+			}
+
+			condition := meta.FindStatusCondition(klusterlet.Status.Conditions, conditionAgentUpgradeProgressing)
+			switch {
+			case len(c.expectedReason) == 0 && condition != nil:
+				t.Errorf("expect no condition, but got %v", condition)
+			case len(c.expectedReason) > 0 && (condition == nil || condition.Reason != c.expectedReason):
+				t.Errorf("expect condition with reason %s, but got %v", c.expectedReason, condition)
+			}
+
+			if c.expectedRemoved {
+				if _, err := kubeClient.CoreV1().ConfigMaps("testns").Get(
+					context.TODO(), upgradeStateConfigMap, metav1.GetOptions{}); err == nil {
+					t.Errorf("expect the upgrade state is removed")
+				}
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	coreinformer "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	operatorv1client "open-cluster-management.io/api/client/operator/clientset/versioned/typed/operator/v1"
@@ -104,6 +105,12 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 	}
 	klusterlet = klusterlet.DeepCopy()
 
+	proxyConfig, err := helpers.GetProxyConfig(klusterlet)
+	if err != nil {
+		klog.Errorf("Failed to parse proxy config for klusterlet %s: %v", klusterlet.Name, err)
+		return err
+	}
+
 	// if the ssar checking is already processing, requeue it after 30s.
 	if c.inSSARChecking(klusterletName) {
 		klog.V(4).Infof("Reconciling Klusterlet %q is already processing now", klusterletName)
@@ -126,6 +133,7 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 			klusterletAgent{
 				clusterName: klusterlet.Spec.ClusterName,
 				namespace:   agentNamespace,
+				proxyConfig: proxyConfig,
 			},
 			klusterlet.Generation,
 			checkHubConfigSecret,
@@ -156,6 +164,7 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 				klusterletAgent{
 					clusterName: klusterlet.Spec.ClusterName,
 					namespace:   agentNamespace,
+					proxyConfig: proxyConfig,
 				},
 				klusterlet.Generation,
 				checkBootstrapSecret,
@@ -184,6 +193,8 @@ func (c *ssarController) sync(ctx context.Context, controllerContext factory.Syn
 type klusterletAgent struct {
 	clusterName string
 	namespace   string
+	// proxyConfig is the proxy the agent connects to the hub through, the checks use it as well.
+	proxyConfig *helpers.ProxyConfig
 }
 
 func checkAgentDegradedCondition(
@@ -212,8 +223,9 @@ func checkBootstrapSecret(ctx context.Context, kubeClient kubernetes.Interface,
 		}
 	}
 
-	// Check if bootstrap secret works by building kube client
-	bootstrapClient, host, err := buildKubeClientWithSecret(bootstrapSecret)
+	// Check if bootstrap secret works by building kube client, the CA bundle and the bootstrap proxy url of the proxy
+	// config are applied in the same way as the registration agent does.
+	bootstrapClient, host, err := buildKubeClientWithSecret(bootstrapSecret, agent.proxyConfig.ApplyBootstrapToRestConfig)
 	if err != nil {
 		return metav1.Condition{
 			Status: metav1.ConditionTrue,
@@ -223,12 +235,25 @@ func checkBootstrapSecret(ctx context.Context, kubeClient kubernetes.Interface,
 		}
 	}
 
+	// Check if the client certificate of the bootstrap secret is expired, the apiserver rejects it as unauthorized
+	// without telling why.
+	if restConfig, err := helpers.LoadClientConfigFromSecret(bootstrapSecret); err == nil {
+		if err := commonhelpers.CheckClientCertificate(restConfig); err != nil {
+			return metav1.Condition{
+				Status: metav1.ConditionTrue,
+				Reason: bootstrapSecretFailureReason(err),
+				Message: fmt.Sprintf("Invalid client certificate in bootstrap secret %q/%q: %v",
+					agent.namespace, helpers.BootstrapHubKubeConfig, err),
+			}
+		}
+	}
+
 	// Check the bootstrap client permissions by creating SelfSubjectAccessReviews
 	allowed, failedReview, err := commonhelpers.CreateSelfSubjectAccessReviews(ctx, bootstrapClient, commonhelpers.GetBootstrapSSARs())
 	if err != nil {
 		return metav1.Condition{
 			Status: metav1.ConditionTrue,
-			Reason: "BootstrapSecretError",
+			Reason: bootstrapSecretFailureReason(err),
 			Message: fmt.Sprintf("Failed to create %+v with bootstrap secret %q %q: %v",
 				failedReview, agent.namespace, helpers.BootstrapHubKubeConfig, err),
 		}
@@ -250,6 +275,25 @@ func checkBootstrapSecret(ctx context.Context, kubeClient kubernetes.Interface,
 	}
 }
 
+// bootstrapSecretFailureReason returns BootstrapSecretCAMismatch, BootstrapSecretCertExpired or
+// BootstrapSecretUnreachable if the failure to connect to the hub with the bootstrap secret is one of them, otherwise
+// BootstrapSecretError.
+func bootstrapSecretFailureReason(err error) string {
+	if reason := commonhelpers.ConnectionFailureReason(err); len(reason) > 0 {
+		return "BootstrapSecret" + reason
+	}
+	return "BootstrapSecretError"
+}
+
+// hubConfigSecretFailureReason returns HubKubeConfigCAMismatch, HubKubeConfigCertExpired or HubKubeConfigUnreachable
+// if the failure to connect to the hub with the hub config secret is one of them, otherwise HubKubeConfigError.
+func hubConfigSecretFailureReason(err error) string {
+	if reason := commonhelpers.ConnectionFailureReason(err); len(reason) > 0 {
+		return "HubKubeConfig" + reason
+	}
+	return operatorapiv1.ReasonHubKubeConfigError
+}
+
 // Check hub-kubeconfig-secret, if the secret is invalid, return degraded condition
 func checkHubConfigSecret(ctx context.Context, kubeClient kubernetes.Interface, agent klusterletAgent) metav1.Condition {
 	hubConfigSecret, err := kubeClient.CoreV1().Secrets(agent.namespace).Get(ctx, helpers.HubKubeConfig, metav1.GetOptions{})
@@ -271,7 +315,7 @@ func checkHubConfigSecret(ctx context.Context, kubeClient kubernetes.Interface,
 		}
 	}
 
-	hubClient, host, err := buildKubeClientWithSecret(hubConfigSecret)
+	hubClient, host, err := buildKubeClientWithSecret(hubConfigSecret, agent.proxyConfig.ApplyToRestConfig)
 	if err != nil {
 		return metav1.Condition{
 			Status: metav1.ConditionTrue,
@@ -302,7 +346,7 @@ func checkHubConfigSecret(ctx context.Context, kubeClient kubernetes.Interface,
 	if err != nil {
 		return metav1.Condition{
 			Status: metav1.ConditionTrue,
-			Reason: operatorapiv1.ReasonHubKubeConfigError,
+			Reason: hubConfigSecretFailureReason(err),
 			Message: fmt.Sprintf("Failed to create %+v with hub config secret %q/%q to apiserver %s: %v",
 				failedReview, hubConfigSecret.Namespace, hubConfigSecret.Name, host, err),
 		}
@@ -324,11 +368,12 @@ func checkHubConfigSecret(ctx context.Context, kubeClient kubernetes.Interface,
 	}
 }
 
-func buildKubeClientWithSecret(secret *corev1.Secret) (kubernetes.Interface, string, error) {
+func buildKubeClientWithSecret(secret *corev1.Secret, applyProxy func(*rest.Config)) (kubernetes.Interface, string, error) {
 	restConfig, err := helpers.LoadClientConfigFromSecret(secret)
 	if err != nil {
 		return nil, "", err
 	}
+	applyProxy(restConfig)
 
 	// reduce qps and burst of client, because too many managed clusters registration on hub and send ssar requests at once could cause resource pressure
 	restConfig.QPS = 2
@@ -3,6 +3,7 @@ package ssarcontroller
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -53,10 +54,14 @@ func newSecret(name, namespace string) *corev1.Secret {
 }
 
 func newKubeConfig(host string) []byte {
+	return newKubeConfigWithTLSVerify(host, false)
+}
+
+func newKubeConfigWithTLSVerify(host string, verify bool) []byte {
 	configData, _ := runtime.Encode(clientcmdlatest.Codec, &clientcmdapi.Config{
 		Clusters: map[string]*clientcmdapi.Cluster{"default-cluster": {
 			Server:                host,
-			InsecureSkipTLSVerify: true,
+			InsecureSkipTLSVerify: !verify,
 		}},
 		Contexts: map[string]*clientcmdapi.Context{"default-context": {
 			Cluster: "default-cluster",
@@ -165,6 +170,20 @@ func TestSync(t *testing.T) {
 
 	apiServerHost := apiServer.URL
 
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer tlsServer.Close()
+
+	// the apiserver behind a TLS-inspecting proxy whose CA is set in the proxy config of the klusterlet
+	proxiedAPIServer := httptest.NewTLSServer(apiServer.Config.Handler)
+	defer proxiedAPIServer.Close()
+	proxiedKlusterlet := newKlusterlet("testklusterlet", "test", "cluster1")
+	proxyConfig, _ := json.Marshal(helpers.ProxyConfig{CABundle: string(pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: proxiedAPIServer.Certificate().Raw}))})
+	proxiedKlusterlet.Annotations = map[string]string{helpers.ProxyConfigAnnotation: string(proxyConfig)}
+
+	closedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	closedServer.Close()
+
 	cases := []struct {
 		name                               string
 		object                             []runtime.Object
@@ -214,6 +233,42 @@ func TestSync(t *testing.T) {
 				testinghelper.NamedCondition(operatorapiv1.ConditionHubConnectionDegraded, "BootstrapSecretError,HubKubeConfigUnauthorized", metav1.ConditionTrue),
 			},
 		},
+		{
+			name: "Bootstrap secret with mismatched CA",
+			object: []runtime.Object{
+				newSecretWithKubeConfig(helpers.BootstrapHubKubeConfig, "test", newKubeConfigWithTLSVerify(tlsServer.URL, true)),
+				newSecretWithKubeConfig(helpers.HubKubeConfig, "test", newKubeConfig(apiServerHost)),
+			},
+			klusterlet: newKlusterlet("testklusterlet", "test", "cluster1"),
+			expectedConditions: []metav1.Condition{
+				testinghelper.NamedCondition(operatorapiv1.ConditionHubConnectionDegraded, "BootstrapSecretCAMismatch,HubKubeConfigUnauthorized", metav1.ConditionTrue),
+			},
+		},
+		{
+			name: "Bootstrap secret with the CA of the proxy",
+			object: []runtime.Object{
+				newSecretWithKubeConfig(helpers.BootstrapHubKubeConfig, "test", newKubeConfigWithTLSVerify(proxiedAPIServer.URL, true)),
+				newSecretWithKubeConfig(helpers.HubKubeConfig, "test", newKubeConfig(apiServerHost)),
+			},
+			allowToOperateManagedClusters:      true,
+			allowToOperateManagedClusterStatus: true,
+			allowToOperateManifestWorks:        true,
+			klusterlet:                         proxiedKlusterlet,
+			expectedConditions: []metav1.Condition{
+				testinghelper.NamedCondition(operatorapiv1.ConditionHubConnectionDegraded, "HubConnectionFunctional", metav1.ConditionFalse),
+			},
+		},
+		{
+			name: "Bootstrap secret to unreachable apiserver",
+			object: []runtime.Object{
+				newSecretWithKubeConfig(helpers.BootstrapHubKubeConfig, "test", newKubeConfig(closedServer.URL)),
+				newSecretWithKubeConfig(helpers.HubKubeConfig, "test", newKubeConfig(apiServerHost)),
+			},
+			klusterlet: newKlusterlet("testklusterlet", "test", "cluster1"),
+			expectedConditions: []metav1.Condition{
+				testinghelper.NamedCondition(operatorapiv1.ConditionHubConnectionDegraded, "BootstrapSecretUnreachable,HubKubeConfigUnauthorized", metav1.ConditionTrue),
+			},
+		},
 		{
 			name: "Bad hub config secret",
 			object: []runtime.Object{
@@ -30,6 +30,8 @@ type Options struct {
 	DeploymentReplicas            int32
 	DisableAddonNamespace         bool
 	EnableSyncLabels              bool
+	RestrictedPodSecurity         bool
+	MinimalRBAC                   bool
 }
 
 // RunKlusterletOperator starts a new klusterlet operator
@@ -66,6 +68,8 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 	hubConfigSecretInformer := newOneTermInformer(helpers.HubKubeConfig)
 	bootstrapConfigSecretInformer := newOneTermInformer(helpers.BootstrapHubKubeConfig)
 	externalConfigSecretInformer := newOneTermInformer(helpers.ExternalManagedKubeConfig)
+	// only the resource quotas of the agent namespaces are watched for the hosted isolation
+	resourceQuotaInformer := newOneTermInformer(helpers.AgentResourceQuota)
 
 	secretInformers := map[string]corev1informers.SecretInformer{
 		helpers.HubKubeConfig:             hubConfigSecretInformer.Core().V1().Secrets(),
@@ -105,6 +109,7 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 		operatorInformer.Operator().V1().Klusterlets(),
 		secretInformers,
 		deploymentInformer.Apps().V1().Deployments(),
+		resourceQuotaInformer.Core().V1().ResourceQuotas(),
 		workClient.WorkV1().AppliedManifestWorks(),
 		kubeVersion,
 		helpers.GetOperatorNamespace(),
@@ -112,6 +117,8 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 		o.DeploymentReplicas,
 		o.DisableAddonNamespace,
 		o.EnableSyncLabels,
+		o.RestrictedPodSecurity,
+		o.MinimalRBAC,
 		controllerContext.EventRecorder)
 
 	klusterletCleanupController := klusterletcontroller.NewKlusterletCleanupController(
@@ -158,6 +165,7 @@ func (o *Options) RunKlusterletOperator(ctx context.Context, controllerContext *
 	go bootstrapConfigSecretInformer.Start(ctx.Done())
 	go externalConfigSecretInformer.Start(ctx.Done())
 	go deploymentInformer.Start(ctx.Done())
+	go resourceQuotaInformer.Start(ctx.Done())
 	go klusterletController.Run(ctx, 1)
 	go klusterletCleanupController.Run(ctx, 1)
 	go statusController.Run(ctx, 1)
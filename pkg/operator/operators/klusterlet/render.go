@@ -0,0 +1,106 @@
+package klusterlet
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	versionutil "k8s.io/apimachinery/pkg/util/version"
+
+	operatorapiv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+	"open-cluster-management.io/ocm/pkg/operator/operators/klusterlet/controllers/klusterletcontroller"
+	"open-cluster-management.io/ocm/pkg/operator/render"
+)
+
+// defaultRenderKubeVersion is the version of the managed cluster the manifests are rendered for by default.
+const defaultRenderKubeVersion = "v1.30.0"
+
+// RenderOptions are the options to render the manifests of a klusterlet instead of applying them, so that the
+// manifests can be applied by other tools.
+type RenderOptions struct {
+	File                  string
+	OutputDir             string
+	OperatorNamespace     string
+	KubeVersion           string
+	DeploymentReplicas    int32
+	DisableAddonNamespace bool
+	EnableSyncLabels      bool
+	RestrictedPodSecurity bool
+	FIPSMode              bool
+	MinimalRBAC           bool
+}
+
+func NewRenderOptions() *RenderOptions {
+	return &RenderOptions{
+		OperatorNamespace: helpers.DefaultComponentNamespace,
+		KubeVersion:       defaultRenderKubeVersion,
+	}
+}
+
+func (o *RenderOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.File, "file", "f", o.File, "The file of the Klusterlet to render, - to read it from stdin.")
+	flags.StringVar(&o.OutputDir, "output-dir", o.OutputDir,
+		"The directory to write the manifests into, the manifests are written to stdout if not set.")
+	flags.StringVar(&o.OperatorNamespace, "operator-namespace", o.OperatorNamespace, "The namespace of the operator.")
+	flags.StringVar(&o.KubeVersion, "kube-version", o.KubeVersion, "The kube version of the managed cluster.")
+	flags.Int32Var(&o.DeploymentReplicas, "deployment-replicas", o.DeploymentReplicas,
+		"Number of deployment replicas, 1 replica is rendered if not set")
+	flags.BoolVar(&o.DisableAddonNamespace, "disable-default-addon-namespace", o.DisableAddonNamespace,
+		"If set, will not render default open-cluster-management-agent-addon ns")
+	flags.BoolVar(&o.EnableSyncLabels, "enable-sync-labels", o.EnableSyncLabels,
+		"If set, will sync the labels of Klusterlet CR to all agent resources")
+	flags.BoolVar(&o.RestrictedPodSecurity, "restricted-pod-security", o.RestrictedPodSecurity,
+		"If set, will render the agents compliant with the restricted Pod Security Standard")
+	flags.BoolVar(&o.FIPSMode, "fips-mode", o.FIPSMode,
+		"If set, will render the components running in the FIPS compliance mode")
+	flags.BoolVar(&o.MinimalRBAC, "minimal-rbac", o.MinimalRBAC,
+		"If set, will render only the permissions required by the enabled features for the agents")
+}
+
+// Render writes the manifests of the klusterlet in the file to the output dir or out.
+func (o *RenderOptions) Render(ctx context.Context, in io.Reader, out io.Writer) error {
+	if len(o.File) == 0 {
+		return fmt.Errorf("the file of the Klusterlet is required")
+	}
+	kubeVersion, err := versionutil.ParseGeneric(o.KubeVersion)
+	if err != nil {
+		return fmt.Errorf("invalid kube version %q: %v", o.KubeVersion, err)
+	}
+
+	klusterlet := &operatorapiv1.Klusterlet{}
+	if err := render.ReadInput(o.File, in, klusterlet); err != nil {
+		return err
+	}
+
+	if o.FIPSMode {
+		fips.Enable()
+	}
+
+	clients := render.NewClients()
+	if err := klusterletcontroller.RenderManifests(ctx, klusterlet, klusterletcontroller.RenderClients{
+		KubeClient:         clients.Kube,
+		APIExtensionClient: clients.APIExtensions,
+		WorkClient:         clients.Work,
+		OperatorClient:     clients.Operator,
+	}, klusterletcontroller.RenderOptions{
+		KubeVersion:           kubeVersion,
+		OperatorNamespace:     o.OperatorNamespace,
+		DeploymentReplicas:    o.DeploymentReplicas,
+		DisableAddonNamespace: o.DisableAddonNamespace,
+		EnableSyncLabels:      o.EnableSyncLabels,
+		RestrictedPodSecurity: o.RestrictedPodSecurity,
+		MinimalRBAC:           o.MinimalRBAC,
+	}); err != nil {
+		return err
+	}
+
+	objects, err := clients.Objects()
+	if err != nil {
+		return err
+	}
+	return render.WriteObjects(objects, o.OutputDir, out)
+}
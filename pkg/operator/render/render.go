@@ -0,0 +1,429 @@
+// Package render renders the manifests the operator applies without applying them, so that the manifests can be
+// applied by other tools. The reconcile of the operator is run once with the clients built on a recording transport,
+// which keeps the objects in memory instead of sending the requests to a kube apiserver, and the objects created or
+// updated through it are the rendered manifests.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/openshift/api"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+
+	operatorclient "open-cluster-management.io/api/client/operator/clientset/versioned"
+	workclient "open-cluster-management.io/api/client/work/clientset/versioned"
+)
+
+var (
+	renderScheme = runtime.NewScheme()
+	renderCodecs = serializer.NewCodecFactory(renderScheme)
+)
+
+func init() {
+	utilruntime.Must(api.InstallKube(renderScheme))
+	utilruntime.Must(apiextensionsv1beta1.AddToScheme(renderScheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(renderScheme))
+	utilruntime.Must(apiregistrationv1.AddToScheme(renderScheme))
+	utilruntime.Must(admissionv1.AddToScheme(renderScheme))
+}
+
+// Clients are the clients the reconcile is run with to render the manifests. They share a recording transport, so
+// the objects are kept in memory and nothing is sent to a kube apiserver.
+type Clients struct {
+	Kube          kubernetes.Interface
+	APIExtensions apiextensionsclient.Interface
+	Migration     migrationclient.Interface
+	Dynamic       dynamic.Interface
+	Work          workclient.Interface
+	Operator      operatorclient.Interface
+
+	recorder *recorder
+}
+
+// NewClients returns the clients to render the manifests. The deployments created or updated through the clients
+// are available at once, so that the reconcile does not wait for them.
+func NewClients() *Clients {
+	r := &recorder{objects: map[string][]byte{}}
+	// the requests are served in memory, so they are not rate limited
+	config := &rest.Config{Host: "https://render.local", Transport: r, QPS: -1}
+	return &Clients{
+		Kube:          kubernetes.NewForConfigOrDie(config),
+		APIExtensions: apiextensionsclient.NewForConfigOrDie(config),
+		Migration:     migrationclient.NewForConfigOrDie(config),
+		Dynamic:       dynamic.NewForConfigOrDie(config),
+		Work:          workclient.NewForConfigOrDie(config),
+		Operator:      operatorclient.NewForConfigOrDie(config),
+		recorder:      r,
+	}
+}
+
+// Objects returns the objects created or updated through the clients in the order they are first applied, with
+// their last state. The objects deleted afterwards are not returned.
+func (c *Clients) Objects() ([]runtime.Object, error) {
+	c.recorder.lock.Lock()
+	defer c.recorder.lock.Unlock()
+
+	var rendered []runtime.Object
+	for _, path := range c.recorder.applied {
+		data, ok := c.recorder.objects[path]
+		if !ok {
+			continue
+		}
+		obj, err := renderedObject(data)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, obj)
+	}
+	return rendered, nil
+}
+
+// renderedObject decodes the object into its type if the type is known, otherwise into an unstructured object.
+func renderedObject(data []byte) (runtime.Object, error) {
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	u.SetResourceVersion("")
+	u.SetGeneration(0)
+	u.SetUID("")
+
+	gvk := u.GroupVersionKind()
+	if !renderScheme.Recognizes(gvk) {
+		return u, nil
+	}
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj, _, err := renderCodecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return obj, nil
+}
+
+// recorder is a http.RoundTripper serving the requests of the clients from the objects in memory, the object paths
+// created or updated are recorded in the order they are first applied.
+type recorder struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+	applied []string
+	version int
+}
+
+// request is a parsed request path, the path of a namespace is /api/v1/namespaces/<name> and the path of a
+// namespaced object is /api(s)/<group version>/namespaces/<namespace>/<resource>/<name>.
+type request struct {
+	resource    schema.GroupResource
+	collection  string
+	name        string
+	subresource string
+}
+
+func (r request) path() string {
+	return r.collection + "/" + r.name
+}
+
+func parseRequest(path string) (request, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var prefix []string
+	req := request{}
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		prefix, segments = segments[:2], segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		prefix, segments = segments[:3], segments[3:]
+		req.resource.Group = prefix[1]
+	default:
+		return req, fmt.Errorf("unsupported path %q", path)
+	}
+
+	// the namespace is an object of the namespaces, the other resources are in the namespace
+	if len(segments) > 2 && segments[0] == "namespaces" && segments[2] != "status" && segments[2] != "finalize" {
+		prefix, segments = append(prefix, segments[:2]...), segments[2:]
+	}
+	if len(segments) == 0 || len(segments) > 3 {
+		return req, fmt.Errorf("unsupported path %q", path)
+	}
+
+	req.resource.Resource = segments[0]
+	req.collection = "/" + strings.Join(append(prefix, segments[0]), "/")
+	if len(segments) > 1 {
+		req.name = segments[1]
+	}
+	if len(segments) > 2 {
+		req.subresource = segments[2]
+	}
+	return req, nil
+}
+
+func (r *recorder) RoundTrip(httpReq *http.Request) (*http.Response, error) {
+	req, err := parseRequest(httpReq.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	if httpReq.Body != nil {
+		defer httpReq.Body.Close()
+		if body, err = io.ReadAll(httpReq.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch {
+	case httpReq.Method == http.MethodGet && len(req.name) == 0:
+		return r.list(req, httpReq.URL.Query().Get("labelSelector"))
+	case httpReq.Method == http.MethodGet:
+		data, ok := r.objects[req.path()]
+		if !ok {
+			return statusResponse(errors.NewNotFound(req.resource, req.name))
+		}
+		return response(http.StatusOK, data)
+	case httpReq.Method == http.MethodPost && len(req.name) == 0:
+		return r.create(req, body)
+	case httpReq.Method == http.MethodPut:
+		if _, ok := r.objects[req.path()]; !ok {
+			return statusResponse(errors.NewNotFound(req.resource, req.name))
+		}
+		return r.store(http.StatusOK, req, body)
+	case httpReq.Method == http.MethodPatch:
+		return r.patch(req, types.PatchType(httpReq.Header.Get("Content-Type")), body)
+	case httpReq.Method == http.MethodDelete && len(req.name) > 0:
+		if _, ok := r.objects[req.path()]; !ok {
+			return statusResponse(errors.NewNotFound(req.resource, req.name))
+		}
+		delete(r.objects, req.path())
+		return statusResponse(nil)
+	}
+	return statusResponse(errors.NewMethodNotSupported(req.resource, httpReq.Method))
+}
+
+func (r *recorder) list(req request, labelSelector string) (*http.Response, error) {
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return statusResponse(errors.NewBadRequest(err.Error()))
+	}
+
+	items := []json.RawMessage{}
+	for path, data := range r.objects {
+		name, ok := strings.CutPrefix(path, req.collection+"/")
+		if !ok || strings.Contains(name, "/") {
+			continue
+		}
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(data); err != nil {
+			return nil, err
+		}
+		if selector.Matches(labels.Set(u.GetLabels())) {
+			items = append(items, data)
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{}, "items": items})
+	if err != nil {
+		return nil, err
+	}
+	return response(http.StatusOK, data)
+}
+
+func (r *recorder) create(req request, body []byte) (*http.Response, error) {
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(body); err != nil {
+		return statusResponse(errors.NewBadRequest(err.Error()))
+	}
+	req.name = u.GetName()
+	if _, ok := r.objects[req.path()]; ok {
+		return statusResponse(errors.NewAlreadyExists(req.resource, req.name))
+	}
+	return r.store(http.StatusCreated, req, body)
+}
+
+func (r *recorder) patch(req request, patchType types.PatchType, patch []byte) (*http.Response, error) {
+	data, ok := r.objects[req.path()]
+	if !ok {
+		return statusResponse(errors.NewNotFound(req.resource, req.name))
+	}
+
+	var err error
+	switch patchType {
+	case types.MergePatchType:
+		data, err = jsonpatch.MergePatch(data, patch)
+	case types.JSONPatchType:
+		var p jsonpatch.Patch
+		if p, err = jsonpatch.DecodePatch(patch); err == nil {
+			data, err = p.Apply(data)
+		}
+	default:
+		return statusResponse(errors.NewBadRequest(fmt.Sprintf("unsupported patch type %q", patchType)))
+	}
+	if err != nil {
+		return statusResponse(errors.NewBadRequest(err.Error()))
+	}
+	return r.store(http.StatusOK, req, data)
+}
+
+// store keeps the object and records its path, the updates of the subresources are kept but not recorded. The
+// deployments are available once they are stored.
+func (r *recorder) store(code int, req request, data []byte) (*http.Response, error) {
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return statusResponse(errors.NewBadRequest(err.Error()))
+	}
+	r.version++
+	u.SetResourceVersion(fmt.Sprintf("%d", r.version))
+	if req.resource.Group == "apps" && req.resource.Resource == "deployments" {
+		replicas, found, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		if err != nil {
+			return statusResponse(errors.NewBadRequest(err.Error()))
+		}
+		if !found {
+			replicas = 1
+		}
+		if err := unstructured.SetNestedMap(u.Object, map[string]interface{}{
+			"observedGeneration": u.GetGeneration(),
+			"replicas":           replicas,
+			"readyReplicas":      replicas,
+			"availableReplicas":  replicas,
+		}, "status"); err != nil {
+			return nil, err
+		}
+	}
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	path := req.path()
+	if _, ok := r.objects[path]; !ok || len(req.subresource) == 0 {
+		r.record(path)
+	}
+	r.objects[path] = data
+	return response(code, data)
+}
+
+func (r *recorder) record(path string) {
+	for _, p := range r.applied {
+		if p == path {
+			return
+		}
+	}
+	r.applied = append(r.applied, path)
+}
+
+func response(code int, data []byte) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": []string{runtime.ContentTypeJSON}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}, nil
+}
+
+// statusResponse returns the status of the error, or a success status if err is nil.
+func statusResponse(err *errors.StatusError) (*http.Response, error) {
+	status := metav1.Status{Status: metav1.StatusSuccess, Code: http.StatusOK}
+	if err != nil {
+		status = err.ErrStatus
+	}
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+	data, marshalErr := json.Marshal(status)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	return response(int(status.Code), data)
+}
+
+// ReadInput reads the yaml of the object to render from the file, or from in if the file is "-".
+func ReadInput(file string, in io.Reader, obj interface{}) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(file))
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, obj)
+}
+
+// WriteObjects writes the objects as yaml. If the output dir is set, each object is written into a file in the dir
+// prefixed with its order, otherwise all objects are written to out as a multi-document yaml.
+func WriteObjects(objects []runtime.Object, outputDir string, out io.Writer) error {
+	if len(outputDir) > 0 {
+		if err := os.MkdirAll(outputDir, 0750); err != nil {
+			return err
+		}
+	}
+
+	for i, obj := range objects {
+		data, err := renderedYaml(obj)
+		if err != nil {
+			return err
+		}
+
+		if len(outputDir) == 0 {
+			if _, err := fmt.Fprintf(out, "---\n%s", data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%03d-%s-%s.yaml", i, strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind), accessor.GetName())
+		name = strings.ReplaceAll(name, ":", "-")
+		if err := os.WriteFile(filepath.Join(outputDir, name), data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderedYaml returns the yaml of the object without the status and the creation timestamp.
+func renderedYaml(obj runtime.Object) ([]byte, error) {
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(unstructuredObj, "status")
+	if metadata, ok := unstructuredObj["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	return yaml.Marshal(unstructuredObj)
+}
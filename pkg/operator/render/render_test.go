@@ -0,0 +1,156 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func TestRenderedObjects(t *testing.T) {
+	clients := NewClients()
+	client := clients.Kube
+	ctx := context.TODO()
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}
+	if _, err := client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "ns1"},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+	}
+	created, err := client.AppsV1().Deployments("ns1").Create(ctx, deployment, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Status.ReadyReplicas != 2 {
+		t.Errorf("expect the deployment is available, but got %v", created.Status)
+	}
+	created.Labels = map[string]string{"updated": "true"}
+	if _, err := client.AppsV1().Deployments("ns1").Update(ctx, created, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := clients.Objects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("expect 2 objects, but got %d", len(objects))
+	}
+	if kind := objects[0].GetObjectKind().GroupVersionKind().Kind; kind != "Namespace" {
+		t.Errorf("expect the namespace at first, but got %s", kind)
+	}
+	rendered := objects[1].(*appsv1.Deployment)
+	if rendered.Labels["updated"] != "true" || len(rendered.ResourceVersion) != 0 {
+		t.Errorf("expect the last state of the deployment, but got %v", rendered.ObjectMeta)
+	}
+
+	out := &bytes.Buffer{}
+	if err := WriteObjects(objects, "", out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "---\n") != 2 || strings.Contains(out.String(), "status:") {
+		t.Errorf("unexpected output %s", out.String())
+	}
+
+	dir := t.TempDir()
+	if err := WriteObjects(objects, dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"000-namespace-ns1.yaml", "001-deployment-agent.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expect file %s: %v", name, err)
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	clients := NewClients()
+	client := clients.Kube
+	ctx := context.TODO()
+
+	if _, err := client.CoreV1().ConfigMaps("ns1").Get(ctx, "cm1", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expect not found error, but got %v", err)
+	}
+
+	for _, name := range []string{"cm1", "cm2", "cm3"} {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1", Labels: map[string]string{"app": name}},
+		}
+		if _, err := client.CoreV1().ConfigMaps("ns1").Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.CoreV1().ConfigMaps("ns1").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm1", Namespace: "ns1"},
+	}, metav1.CreateOptions{}); !errors.IsAlreadyExists(err) {
+		t.Errorf("expect already exists error, but got %v", err)
+	}
+
+	// the objects are listed by the label selector
+	cms, err := client.CoreV1().ConfigMaps("ns1").List(ctx, metav1.ListOptions{LabelSelector: "app in (cm1,cm2)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cms.Items) != 2 {
+		t.Errorf("expect 2 configmaps, but got %v", cms.Items)
+	}
+
+	patched, err := client.CoreV1().ConfigMaps("ns1").Patch(ctx, "cm2", types.MergePatchType,
+		[]byte(`{"data":{"key":"value"}}`), metav1.PatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patched.Data["key"] != "value" {
+		t.Errorf("expect the patched configmap, but got %v", patched.Data)
+	}
+
+	if err := client.CoreV1().ConfigMaps("ns1").Delete(ctx, "cm1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CoreV1().ConfigMaps("ns1").Delete(ctx, "cm1", metav1.DeleteOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expect not found error, but got %v", err)
+	}
+
+	// the objects of the unknown kinds are rendered as unstructured objects
+	certificate := &unstructured.Unstructured{}
+	certificate.SetAPIVersion("cert-manager.io/v1")
+	certificate.SetKind("Certificate")
+	certificate.SetNamespace("ns1")
+	certificate.SetName("cert1")
+	if _, err := clients.Dynamic.Resource(schema.GroupVersionResource{
+		Group: "cert-manager.io", Version: "v1", Resource: "certificates",
+	}).Namespace("ns1").Create(ctx, certificate, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := clients.Objects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 3 {
+		t.Fatalf("expect 3 objects, but got %d", len(objects))
+	}
+	if cm, ok := objects[0].(*corev1.ConfigMap); !ok || cm.Name != "cm2" || cm.Data["key"] != "value" {
+		t.Errorf("expect the patched configmap cm2, but got %v", objects[0])
+	}
+	if cm, ok := objects[1].(*corev1.ConfigMap); !ok || cm.Name != "cm3" {
+		t.Errorf("expect the configmap cm3, but got %v", objects[1])
+	}
+	if cert, ok := objects[2].(*unstructured.Unstructured); !ok || cert.GetKind() != "Certificate" {
+		t.Errorf("expect the certificate, but got %v", objects[2])
+	}
+}
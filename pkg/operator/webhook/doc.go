@@ -0,0 +1,2 @@
+// package webhook contains the admission hooks to validate the ClusterManager and Klusterlet create and update operations
+package webhook
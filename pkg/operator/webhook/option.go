@@ -0,0 +1,29 @@
+package webhook
+
+import "github.com/spf13/pflag"
+
+// Config contains the server (the webhook) cert and key.
+type Options struct {
+	Port    int
+	CertDir string
+	// FIPSMode restricts the TLS configs of the webhook server to the FIPS approved algorithms.
+	FIPSMode bool
+}
+
+// NewOptions constructs a new set of default options for webhook.
+func NewOptions() *Options {
+	return &Options{
+		Port: 9443,
+	}
+}
+
+func (c *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&c.Port, "port", c.Port,
+		"Port is the port that the webhook server serves at.")
+	fs.StringVar(&c.CertDir, "certdir", c.CertDir,
+		"CertDir is the directory that contains the server key and certificate. If not set, "+
+			"webhook server would look up the server key and certificate in {TempDir}/k8s-webhook-server/serving-certs")
+	fs.BoolVar(&c.FIPSMode, "fips-mode", c.FIPSMode,
+		"Restrict the TLS connections to the FIPS approved algorithms. The mode is always on if the binary is built "+
+			"with boringcrypto.")
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth" // Import all auth plugins (e.g. Azure, GCP, OIDC, etc.) to ensure exec-entrypoint and run can make use of them.
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	internalv1 "open-cluster-management.io/ocm/pkg/operator/webhook/v1"
+)
+
+var (
+	scheme = runtime.NewScheme()
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(operatorv1.Install(scheme))
+}
+
+func (c *Options) RunWebhookServer() error {
+	logger := klog.LoggerWithName(klog.FromContext(context.Background()), "Webhook Server")
+	// This line prevents controller-runtime from complaining about log.SetLogger never being called
+	ctrl.SetLogger(logger)
+
+	if c.FIPSMode {
+		fips.Enable()
+	}
+	fips.Validate()
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme:                 scheme,
+		HealthProbeBindAddress: ":8000",
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    c.Port,
+			CertDir: c.CertDir,
+			TLSOpts: []func(config *tls.Config){
+				func(config *tls.Config) {
+					config.MinVersion = tls.VersionTLS12
+				},
+				fips.ConfigureTLS,
+			},
+		}),
+	})
+	if err != nil {
+		logger.Error(err, "unable to start manager")
+		return err
+	}
+
+	// add healthz/readyz check handler
+	if err := mgr.AddHealthzCheck("healthz-ping", healthz.Ping); err != nil {
+		logger.Error(err, "unable to add healthz check handler")
+		return err
+	}
+
+	if err := mgr.AddReadyzCheck("readyz-ping", healthz.Ping); err != nil {
+		logger.Error(err, "unable to add readyz check handler")
+		return err
+	}
+
+	if err = (&internalv1.ClusterManagerWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		logger.Error(err, "unable to create ClusterManager webhook")
+		return err
+	}
+	if err = (&internalv1.KlusterletWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+		logger.Error(err, "unable to create Klusterlet webhook")
+		return err
+	}
+
+	logger.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		logger.Error(err, "problem running manager")
+		return err
+	}
+	return nil
+}
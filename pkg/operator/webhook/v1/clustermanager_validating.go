@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ocmfeature "open-cluster-management.io/api/feature"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+var _ webhook.CustomValidator = &ClusterManagerWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterManagerWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusterManager, ok := obj.(*operatorv1.ClusterManager)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request clustermanager obj format is not right")
+	}
+	return nil, validateClusterManager(clusterManager, nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterManagerWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (
+	admission.Warnings, error) {
+	clusterManager, ok := newObj.(*operatorv1.ClusterManager)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request clustermanager obj format is not right")
+	}
+	oldClusterManager, ok := oldObj.(*operatorv1.ClusterManager)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request old clustermanager obj format is not right")
+	}
+	return nil, validateClusterManager(clusterManager, oldClusterManager)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterManagerWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateClusterManager validates the feature gates of the components. On update, only the feature gates which
+// are changed are validated, so the cluster manager is still updatable if a feature gate is removed in this version.
+func validateClusterManager(clusterManager, oldClusterManager *operatorv1.ClusterManager) error {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+
+	registrationFeatureGates := clusterManagerRegistrationFeatureGates(clusterManager.Spec)
+	if oldClusterManager == nil ||
+		!equality.Semantic.DeepEqual(registrationFeatureGates, clusterManagerRegistrationFeatureGates(oldClusterManager.Spec)) {
+		errs = append(errs, helpers.ValidateFeatureGates(specPath.Child("registrationConfiguration", "featureGates"),
+			registrationFeatureGates, ocmfeature.DefaultHubRegistrationFeatureGates)...)
+	}
+
+	workFeatureGates := clusterManagerWorkFeatureGates(clusterManager.Spec)
+	if oldClusterManager == nil ||
+		!equality.Semantic.DeepEqual(workFeatureGates, clusterManagerWorkFeatureGates(oldClusterManager.Spec)) {
+		errs = append(errs, helpers.ValidateFeatureGates(specPath.Child("workConfiguration", "featureGates"),
+			workFeatureGates, ocmfeature.DefaultHubWorkFeatureGates)...)
+	}
+
+	addOnFeatureGates := clusterManagerAddOnFeatureGates(clusterManager.Spec)
+	if oldClusterManager == nil ||
+		!equality.Semantic.DeepEqual(addOnFeatureGates, clusterManagerAddOnFeatureGates(oldClusterManager.Spec)) {
+		errs = append(errs, helpers.ValidateFeatureGates(specPath.Child("addOnManagerConfiguration", "featureGates"),
+			addOnFeatureGates, ocmfeature.DefaultHubAddonManagerFeatureGates)...)
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(operatorv1.GroupVersion.WithKind("ClusterManager").GroupKind(), clusterManager.Name, errs)
+	}
+	return nil
+}
+
+func clusterManagerRegistrationFeatureGates(spec operatorv1.ClusterManagerSpec) []operatorv1.FeatureGate {
+	if spec.RegistrationConfiguration == nil {
+		return nil
+	}
+	return spec.RegistrationConfiguration.FeatureGates
+}
+
+func clusterManagerWorkFeatureGates(spec operatorv1.ClusterManagerSpec) []operatorv1.FeatureGate {
+	if spec.WorkConfiguration == nil {
+		return nil
+	}
+	return spec.WorkConfiguration.FeatureGates
+}
+
+func clusterManagerAddOnFeatureGates(spec operatorv1.ClusterManagerSpec) []operatorv1.FeatureGate {
+	if spec.AddOnManagerConfiguration == nil {
+		return nil
+	}
+	return spec.AddOnManagerConfiguration.FeatureGates
+}
@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func newClusterManager(registrationGates, addOnGates []operatorv1.FeatureGate) *operatorv1.ClusterManager {
+	return &operatorv1.ClusterManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager"},
+		Spec: operatorv1.ClusterManagerSpec{
+			RegistrationConfiguration: &operatorv1.RegistrationHubConfiguration{FeatureGates: registrationGates},
+			AddOnManagerConfiguration: &operatorv1.AddOnManagerConfiguration{FeatureGates: addOnGates},
+		},
+	}
+}
+
+func TestClusterManagerValidateCreate(t *testing.T) {
+	cases := []struct {
+		name           string
+		clusterManager *operatorv1.ClusterManager
+		expectedError  bool
+	}{
+		{
+			name:           "no feature gates",
+			clusterManager: &operatorv1.ClusterManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster-manager"}},
+		},
+		{
+			name: "valid feature gates",
+			clusterManager: newClusterManager(
+				[]operatorv1.FeatureGate{{Feature: "DefaultClusterSet", Mode: operatorv1.FeatureGateModeTypeEnable}},
+				[]operatorv1.FeatureGate{{Feature: "AddonManagement", Mode: operatorv1.FeatureGateModeTypeDisable}},
+			),
+		},
+		{
+			name: "unknown feature gate",
+			clusterManager: newClusterManager(
+				nil, []operatorv1.FeatureGate{{Feature: "DefaultClusterSet", Mode: operatorv1.FeatureGateModeTypeEnable}},
+			),
+			expectedError: true,
+		},
+	}
+
+	w := ClusterManagerWebhook{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := w.ValidateCreate(context.Background(), c.clusterManager)
+			if c.expectedError != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestClusterManagerValidateUpdate(t *testing.T) {
+	unknown := []operatorv1.FeatureGate{{Feature: "Unknown", Mode: operatorv1.FeatureGateModeTypeEnable}}
+	cases := []struct {
+		name          string
+		old, new      *operatorv1.ClusterManager
+		expectedError bool
+	}{
+		{
+			name: "invalid feature gates not changed",
+			old:  newClusterManager(unknown, nil),
+			new: newClusterManager(unknown,
+				[]operatorv1.FeatureGate{{Feature: "AddonManagement", Mode: operatorv1.FeatureGateModeTypeEnable}}),
+		},
+		{
+			name:          "invalid feature gates added",
+			old:           newClusterManager(nil, nil),
+			new:           newClusterManager(unknown, nil),
+			expectedError: true,
+		},
+	}
+
+	w := ClusterManagerWebhook{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := w.ValidateUpdate(context.Background(), c.old, c.new)
+			if c.expectedError != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
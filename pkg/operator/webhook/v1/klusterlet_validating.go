@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ocmfeature "open-cluster-management.io/api/feature"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+
+	"open-cluster-management.io/ocm/pkg/operator/helpers"
+)
+
+var _ webhook.CustomValidator = &KlusterletWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *KlusterletWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	klusterlet, ok := obj.(*operatorv1.Klusterlet)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request klusterlet obj format is not right")
+	}
+	return nil, validateKlusterlet(klusterlet, nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *KlusterletWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (
+	admission.Warnings, error) {
+	klusterlet, ok := newObj.(*operatorv1.Klusterlet)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request klusterlet obj format is not right")
+	}
+	oldKlusterlet, ok := oldObj.(*operatorv1.Klusterlet)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request old klusterlet obj format is not right")
+	}
+	return nil, validateKlusterlet(klusterlet, oldKlusterlet)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *KlusterletWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateKlusterlet validates the feature gates of the agents, only the changed ones are validated on update.
+func validateKlusterlet(klusterlet, oldKlusterlet *operatorv1.Klusterlet) error {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+
+	registrationFeatureGates := klusterletRegistrationFeatureGates(klusterlet.Spec)
+	if oldKlusterlet == nil ||
+		!equality.Semantic.DeepEqual(registrationFeatureGates, klusterletRegistrationFeatureGates(oldKlusterlet.Spec)) {
+		errs = append(errs, helpers.ValidateFeatureGates(specPath.Child("registrationConfiguration", "featureGates"),
+			registrationFeatureGates, ocmfeature.DefaultSpokeRegistrationFeatureGates)...)
+	}
+
+	workFeatureGates := klusterletWorkFeatureGates(klusterlet.Spec)
+	if oldKlusterlet == nil ||
+		!equality.Semantic.DeepEqual(workFeatureGates, klusterletWorkFeatureGates(oldKlusterlet.Spec)) {
+		errs = append(errs, helpers.ValidateFeatureGates(specPath.Child("workConfiguration", "featureGates"),
+			workFeatureGates, ocmfeature.DefaultSpokeWorkFeatureGates)...)
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(operatorv1.GroupVersion.WithKind("Klusterlet").GroupKind(), klusterlet.Name, errs)
+	}
+	return nil
+}
+
+func klusterletRegistrationFeatureGates(spec operatorv1.KlusterletSpec) []operatorv1.FeatureGate {
+	if spec.RegistrationConfiguration == nil {
+		return nil
+	}
+	return spec.RegistrationConfiguration.FeatureGates
+}
+
+func klusterletWorkFeatureGates(spec operatorv1.KlusterletSpec) []operatorv1.FeatureGate {
+	if spec.WorkConfiguration == nil {
+		return nil
+	}
+	return spec.WorkConfiguration.FeatureGates
+}
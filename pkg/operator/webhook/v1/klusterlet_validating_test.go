@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+)
+
+func newKlusterlet(registrationGates, workGates []operatorv1.FeatureGate) *operatorv1.Klusterlet {
+	return &operatorv1.Klusterlet{
+		ObjectMeta: metav1.ObjectMeta{Name: "klusterlet"},
+		Spec: operatorv1.KlusterletSpec{
+			RegistrationConfiguration: &operatorv1.RegistrationConfiguration{FeatureGates: registrationGates},
+			WorkConfiguration:         &operatorv1.WorkAgentConfiguration{FeatureGates: workGates},
+		},
+	}
+}
+
+func TestKlusterletValidateCreate(t *testing.T) {
+	cases := []struct {
+		name          string
+		klusterlet    *operatorv1.Klusterlet
+		expectedError bool
+	}{
+		{
+			name:       "no feature gates",
+			klusterlet: &operatorv1.Klusterlet{ObjectMeta: metav1.ObjectMeta{Name: "klusterlet"}},
+		},
+		{
+			name: "valid feature gates",
+			klusterlet: newKlusterlet(
+				[]operatorv1.FeatureGate{{Feature: "MultipleHubs", Mode: operatorv1.FeatureGateModeTypeEnable}},
+				[]operatorv1.FeatureGate{{Feature: "RawFeedbackJsonString", Mode: operatorv1.FeatureGateModeTypeEnable}},
+			),
+		},
+		{
+			name: "hub feature gate on the agent",
+			klusterlet: newKlusterlet(
+				[]operatorv1.FeatureGate{{Feature: "DefaultClusterSet", Mode: operatorv1.FeatureGateModeTypeEnable}}, nil,
+			),
+			expectedError: true,
+		},
+	}
+
+	w := KlusterletWebhook{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := w.ValidateCreate(context.Background(), c.klusterlet)
+			if c.expectedError != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestKlusterletValidateUpdate(t *testing.T) {
+	unknown := []operatorv1.FeatureGate{{Feature: "Unknown", Mode: operatorv1.FeatureGateModeTypeEnable}}
+	cases := []struct {
+		name          string
+		old, new      *operatorv1.Klusterlet
+		expectedError bool
+	}{
+		{
+			name: "invalid feature gates not changed",
+			old:  newKlusterlet(nil, unknown),
+			new: newKlusterlet(
+				[]operatorv1.FeatureGate{{Feature: "ClusterClaim", Mode: operatorv1.FeatureGateModeTypeDisable}}, unknown),
+		},
+		{
+			name:          "invalid feature gates added",
+			old:           newKlusterlet(nil, nil),
+			new:           newKlusterlet(nil, unknown),
+			expectedError: true,
+		},
+	}
+
+	w := KlusterletWebhook{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := w.ValidateUpdate(context.Background(), c.old, c.new)
+			if c.expectedError != (err != nil) {
+				t.Errorf("expect error %v, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
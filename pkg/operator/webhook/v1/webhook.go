@@ -0,0 +1,25 @@
+package v1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+)
+
+type ClusterManagerWebhook struct{}
+
+func (r *ClusterManagerWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(r).
+		For(&operatorv1.ClusterManager{}).
+		Complete()
+}
+
+type KlusterletWebhook struct{}
+
+func (r *KlusterletWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(r).
+		For(&operatorv1.Klusterlet{}).
+		Complete()
+}
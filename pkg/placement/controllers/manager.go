@@ -6,22 +6,34 @@ import (
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/clock"
 
 	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterscheme "open-cluster-management.io/api/client/cluster/clientset/versioned/scheme"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 	"open-cluster-management.io/ocm/pkg/placement/debugger"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/external"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/flapping"
 )
 
-// RunControllerManager starts the controllers on hub to make placement decisions.
+// RunControllerManager starts the controllers on hub to make placement decisions with the default options.
 func RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	return NewPlacementControllerOptions().RunControllerManager(ctx, controllerContext)
+}
+
+// RunControllerManager starts the controllers on hub to make placement decisions.
+func (o *PlacementControllerOptions) RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	clusterClient, err := clusterclient.NewForConfig(controllerContext.KubeConfig)
 	if err != nil {
 		return err
@@ -34,15 +46,30 @@ func RunControllerManager(ctx context.Context, controllerContext *controllercmd.
 
 	clusterInformers := clusterinformers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
 
-	return RunControllerManagerWithInformers(ctx, controllerContext, kubeClient, clusterClient, clusterInformers)
+	// only the resource usage ManifestWorks are watched
+	var workInformers workinformers.SharedInformerFactory
+	if o.ResourceUsageWorks {
+		workClient, err := workclientset.NewForConfig(controllerContext.KubeConfig)
+		if err != nil {
+			return err
+		}
+		workInformers = workinformers.NewSharedInformerFactoryWithOptions(workClient, 10*time.Minute,
+			workinformers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+				listOptions.LabelSelector = helpers.ResourceUsageWorkLabel
+			}),
+		)
+	}
+
+	return o.RunControllerManagerWithInformers(ctx, controllerContext, kubeClient, clusterClient, clusterInformers, workInformers)
 }
 
-func RunControllerManagerWithInformers(
+func (o *PlacementControllerOptions) RunControllerManagerWithInformers(
 	ctx context.Context,
 	controllerContext *controllercmd.ControllerContext,
 	kubeClient kubernetes.Interface,
 	clusterClient clusterclient.Interface,
 	clusterInformers clusterinformers.SharedInformerFactory,
+	workInformers workinformers.SharedInformerFactory,
 ) error {
 	recorder, err := helpers.NewEventRecorder(ctx, clusterscheme.Scheme, kubeClient, "placement-controller")
 	if err != nil {
@@ -51,27 +78,66 @@ func RunControllerManagerWithInformers(
 
 	metrics := metrics.NewScheduleMetrics(clock.RealClock{})
 
+	schedulingCache, err := schedulingcache.NewSchedulingCache(
+		clusterInformers.Cluster().V1().ManagedClusters(),
+		clusterInformers.Cluster().V1alpha1().AddOnPlacementScores(),
+		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
+	)
+	if err != nil {
+		return err
+	}
+	if workInformers != nil {
+		if err := schedulingCache.AddResourceUsageWorks(workInformers.Work().V1().ManifestWorks()); err != nil {
+			return err
+		}
+	}
+
 	scheduler := scheduling.NewPluginScheduler(
 		scheduling.NewSchedulerHandler(
 			clusterClient,
 			clusterInformers.Cluster().V1beta1().PlacementDecisions().Lister(),
 			clusterInformers.Cluster().V1alpha1().AddOnPlacementScores().Lister(),
 			clusterInformers.Cluster().V1().ManagedClusters().Lister(),
-			recorder, metrics),
+			clusterInformers.Cluster().V1beta1().Placements().Lister(),
+			recorder, metrics, schedulingCache),
 	)
 
+	flappingTracker := flapping.NewTracker()
+	if _, err := clusterInformers.Cluster().V1().ManagedClusters().Informer().AddEventHandler(flappingTracker); err != nil {
+		return err
+	}
+	scheduler = scheduler.WithFlappingTracker(flappingTracker)
+
+	if len(o.ScoreProvidersConfigFile) > 0 {
+		externalPrioritizers, err := newExternalPrioritizers(o.ScoreProvidersConfigFile)
+		if err != nil {
+			return err
+		}
+		scheduler = scheduler.WithExternalPrioritizers(externalPrioritizers...)
+	}
+
 	if controllerContext.Server != nil {
 		debug := debugger.NewDebugger(
 			scheduler,
 			clusterInformers.Cluster().V1beta1().Placements(),
 			clusterInformers.Cluster().V1().ManagedClusters(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings(),
 		)
 
 		installDebugger(controllerContext.Server.Handler.NonGoRestfulMux, debug)
 	}
 
+	// only the score breakdown configmaps are watched
+	scoreBreakdownInformers := informers.NewSharedInformerFactoryWithOptions(kubeClient, 10*time.Minute,
+		informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = scheduling.ScoreBreakdownLabel
+		}),
+	)
+
 	schedulingController := scheduling.NewSchedulingController(
 		ctx,
+		kubeClient,
 		clusterClient,
 		clusterInformers.Cluster().V1().ManagedClusters(),
 		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
@@ -79,11 +145,18 @@ func RunControllerManagerWithInformers(
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
 		clusterInformers.Cluster().V1alpha1().AddOnPlacementScores(),
+		scoreBreakdownInformers.Core().V1().ConfigMaps(),
+		schedulingCache,
 		scheduler,
+		o.ScoreBatchWindow,
 		controllerContext.EventRecorder, recorder, metrics,
 	)
 
 	go clusterInformers.Start(ctx.Done())
+	go scoreBreakdownInformers.Start(ctx.Done())
+	if workInformers != nil {
+		go workInformers.Start(ctx.Done())
+	}
 
 	go schedulingController.Run(ctx, 1)
 
@@ -92,6 +165,23 @@ func RunControllerManagerWithInformers(
 	return nil
 }
 
+func newExternalPrioritizers(configFile string) ([]*external.External, error) {
+	config, err := external.LoadProvidersConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var prioritizers []*external.External
+	for _, providerConfig := range config.Providers {
+		p, err := external.New(providerConfig)
+		if err != nil {
+			return nil, err
+		}
+		prioritizers = append(prioritizers, p)
+	}
+	return prioritizers, nil
+}
+
 func installDebugger(mux *mux.PathRecorderMux, d *debugger.Debugger) {
 	mux.HandlePrefix(debugger.DebugPath, http.HandlerFunc(d.Handler))
 }
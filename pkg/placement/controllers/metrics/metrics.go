@@ -3,6 +3,7 @@ package metrics
 import (
 	"time"
 
+	"k8s.io/client-go/tools/cache"
 	k8smetrics "k8s.io/component-base/metrics"
 	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/utils/clock"
@@ -15,6 +16,11 @@ const (
 	SchedulingDurationKey = "scheduling_duration_seconds"
 	BindDurationKey       = "bind_duration_seconds"
 	PluginDurationKey     = "plugin_duration_seconds"
+
+	PlacementSchedulingDurationKey = "placement_duration_seconds"
+	PlacementCandidateClustersKey  = "placement_candidate_clusters"
+	PlacementDecisionChangesKey    = "placement_decision_changes_total"
+	PlacementMisscheduledKey       = "placement_misscheduled_total"
 )
 
 // Metric histograms for tracking various durations.
@@ -43,8 +49,38 @@ var (
 		Buckets:        k8smetrics.ExponentialBuckets(10e-7, 10, 10),
 	}, []string{"name", "plugin_type", "plugin_name"})
 
+	// Metrics labeled by placement to alert on the placements thrashing.
+	placementSchedulingDuration = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+		Subsystem:      SchedulingSubsystem,
+		Name:           PlacementSchedulingDurationKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "How long in seconds the last scheduling of a placement took.",
+	}, []string{"namespace", "placement"})
+
+	placementCandidateClusters = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+		Subsystem:      SchedulingSubsystem,
+		Name:           PlacementCandidateClustersKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of candidate clusters evaluated in the last scheduling of a placement.",
+	}, []string{"namespace", "placement"})
+
+	placementDecisionChanges = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      SchedulingSubsystem,
+		Name:           PlacementDecisionChangesKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of clusters added to or removed from the decisions of a placement.",
+	}, []string{"namespace", "placement", "change"})
+
+	placementMisscheduled = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      SchedulingSubsystem,
+		Name:           PlacementMisscheduledKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of times a placement became misconfigured.",
+	}, []string{"namespace", "placement"})
+
 	metrics = []k8smetrics.Registerable{
 		schedulingDuration, bindDuration, PluginDuration,
+		placementSchedulingDuration, placementCandidateClusters, placementDecisionChanges, placementMisscheduled,
 	}
 )
 
@@ -104,7 +140,11 @@ func (m *ScheduleMetrics) StartBind(key string) {
 
 	m.bindStartTimes[key] = m.clock.Now()
 	if startTime, exists := m.scheduleStartTimes[key]; exists {
-		m.scheduling.Observe(m.SinceInSeconds(startTime))
+		duration := m.SinceInSeconds(startTime)
+		m.scheduling.Observe(duration)
+		if namespace, name, err := cache.SplitMetaNamespaceKey(key); err == nil {
+			placementSchedulingDuration.WithLabelValues(namespace, name).Set(duration)
+		}
 		delete(m.scheduleStartTimes, key)
 	}
 }
@@ -120,3 +160,58 @@ func (m *ScheduleMetrics) Done(key string) {
 		delete(m.bindStartTimes, key)
 	}
 }
+
+// ObserveCandidates records the number of candidate clusters evaluated to schedule the placement of the key.
+func (m *ScheduleMetrics) ObserveCandidates(key string, candidates int) {
+	if m == nil {
+		return
+	}
+
+	if namespace, name, err := cache.SplitMetaNamespaceKey(key); err == nil {
+		placementCandidateClusters.WithLabelValues(namespace, name).Set(float64(candidates))
+	}
+}
+
+// ObserveDecisionChanges counts the clusters added to and removed from the decisions of the placement of the key.
+func (m *ScheduleMetrics) ObserveDecisionChanges(key string, added, removed int) {
+	if m == nil {
+		return
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	placementDecisionChanges.WithLabelValues(namespace, name, "added").Add(float64(added))
+	placementDecisionChanges.WithLabelValues(namespace, name, "removed").Add(float64(removed))
+}
+
+// ObserveMisscheduled counts the placement of the key becoming misconfigured.
+func (m *ScheduleMetrics) ObserveMisscheduled(key string) {
+	if m == nil {
+		return
+	}
+
+	if namespace, name, err := cache.SplitMetaNamespaceKey(key); err == nil {
+		placementMisscheduled.WithLabelValues(namespace, name).Inc()
+	}
+}
+
+// Forget removes the metrics of the placement of the key after it is deleted.
+func (m *ScheduleMetrics) Forget(key string) {
+	if m == nil {
+		return
+	}
+
+	delete(m.scheduleStartTimes, key)
+	delete(m.bindStartTimes, key)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	placementSchedulingDuration.DeleteLabelValues(namespace, name)
+	placementCandidateClusters.DeleteLabelValues(namespace, name)
+	placementDecisionChanges.DeleteLabelValues(namespace, name, "added")
+	placementDecisionChanges.DeleteLabelValues(namespace, name, "removed")
+	placementMisscheduled.DeleteLabelValues(namespace, name)
+}
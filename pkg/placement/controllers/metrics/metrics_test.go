@@ -68,3 +68,67 @@ func TestMetrics(t *testing.T) {
 		}
 	}
 }
+
+func TestPlacementMetrics(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Unix(0, 0))
+	metrics := NewScheduleMetrics(c)
+
+	metrics.ObserveCandidates("ns1/placement1", 10)
+	metrics.StartSchedule("ns1/placement1")
+	c.Step(2 * time.Second)
+	metrics.StartBind("ns1/placement1")
+	metrics.Done("ns1/placement1")
+	metrics.ObserveDecisionChanges("ns1/placement1", 3, 1)
+	metrics.ObserveDecisionChanges("ns1/placement1", 1, 1)
+	metrics.ObserveMisscheduled("ns1/placement1")
+
+	metrics.ObserveCandidates("ns1/placement2", 5)
+	metrics.ObserveMisscheduled("ns1/placement2")
+	metrics.Forget("ns1/placement2")
+
+	mfs, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Errorf("failed to gather metrics")
+	}
+
+	for _, mf := range mfs {
+		// only check the metrics of the placements in ns1
+		placementMetrics := mf.GetMetric()[:0:0]
+		for _, m := range mf.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "namespace" && label.GetValue() == "ns1" {
+					placementMetrics = append(placementMetrics, m)
+				}
+			}
+		}
+
+		switch *mf.Name {
+		case SchedulingSubsystem + "_" + PlacementSchedulingDurationKey:
+			if len(placementMetrics) != 1 || placementMetrics[0].GetGauge().GetValue() != 2 {
+				t.Errorf("placement scheduling duration is not correct")
+			}
+		case SchedulingSubsystem + "_" + PlacementCandidateClustersKey:
+			if len(placementMetrics) != 1 || placementMetrics[0].GetGauge().GetValue() != 10 {
+				t.Errorf("placement candidate clusters is not correct")
+			}
+		case SchedulingSubsystem + "_" + PlacementDecisionChangesKey:
+			for _, m := range placementMetrics {
+				for _, label := range m.GetLabel() {
+					if label.GetName() != "change" {
+						continue
+					}
+					if label.GetValue() == "added" && m.GetCounter().GetValue() != 4 {
+						t.Errorf("placement added decisions is not correct")
+					}
+					if label.GetValue() == "removed" && m.GetCounter().GetValue() != 2 {
+						t.Errorf("placement removed decisions is not correct")
+					}
+				}
+			}
+		case SchedulingSubsystem + "_" + PlacementMisscheduledKey:
+			if len(placementMetrics) != 1 || placementMetrics[0].GetCounter().GetValue() != 1 {
+				t.Errorf("placement misscheduled is not correct")
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package hub
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// PlacementControllerOptions holds configuration for placement controller
+type PlacementControllerOptions struct {
+	ScoreProvidersConfigFile string
+	ScoreBatchWindow         time.Duration
+	// ResourceUsageWorks reads the resources requested on the managed clusters from the status feedback of the
+	// ManifestWorks labeled with the resource usage label, for the clusters whose registration agent does not report
+	// them.
+	ResourceUsageWorks bool
+}
+
+// NewPlacementControllerOptions returns a PlacementControllerOptions
+func NewPlacementControllerOptions() *PlacementControllerOptions {
+	return &PlacementControllerOptions{}
+}
+
+// AddFlags registers flags for placement controller
+func (o *PlacementControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ScoreProvidersConfigFile, "score-providers-config", o.ScoreProvidersConfigFile,
+		"The config file of the out-of-tree score providers, the placements refer to a score provider with the "+
+			"builtin prioritizer named External/<provider name>")
+	fs.DurationVar(&o.ScoreBatchWindow, "score-batch-window", o.ScoreBatchWindow,
+		"The duration the placements wait before being scheduled once the AddOnPlacementScores they refer to change, "+
+			"the score changes within the window are handled in one schedule. The placements are scheduled immediately if it is 0")
+	fs.BoolVar(&o.ResourceUsageWorks, "resource-usage-works", o.ResourceUsageWorks,
+		"Read the cpu and memory requested on the managed clusters for the ResourceAvailable prioritizers from the status "+
+			"feedback of the ManifestWorks labeled with usage.open-cluster-management.io/resource-usage, if the registration "+
+			"agents do not report them. The feedback values must be converted by the feedback schema of the ManifestWorks")
+}
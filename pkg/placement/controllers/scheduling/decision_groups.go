@@ -0,0 +1,110 @@
+package scheduling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+)
+
+// DecisionGroupByLabelAnnotation is the annotation on the placement to group the clusters which do not match any
+// decision group in spec.decisionStrategy.groupStrategy.decisionGroups by the value of the given cluster label. The
+// value is a label key, e.g. "region", or an expression of a label key restricting the label values to group by,
+// e.g. "region in (us-east-1,eu-west-1)" or "region!=test". The name of each group is the label value, and the
+// clusters without the label or with a value not matched are put into the group without name. The groups are still
+// divided by clustersPerDecisionGroup.
+const DecisionGroupByLabelAnnotation = "cluster.open-cluster-management.io/experimental-decision-group-by-label"
+
+// decisionGroupByLabel is the label key and the expression of the label values to group the clusters by.
+type decisionGroupByLabel struct {
+	key      string
+	selector labels.Selector
+}
+
+// getDecisionGroupByLabel returns how to group the rest of the clusters by label, it returns nil if it is not set.
+func getDecisionGroupByLabel(placement *clusterapiv1beta1.Placement) (*decisionGroupByLabel, *framework.Status) {
+	value, ok := placement.GetAnnotations()[DecisionGroupByLabelAnnotation]
+	if !ok {
+		return nil, framework.NewStatus("", framework.Success, "")
+	}
+
+	misconfigured := func(reason string) (*decisionGroupByLabel, *framework.Status) {
+		msg := fmt.Sprintf("invalid value %q of annotation %s: %s", value, DecisionGroupByLabelAnnotation, reason)
+		return nil, framework.NewStatus("", framework.Misconfigured, msg)
+	}
+
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return misconfigured(err.Error())
+	}
+	requirements, _ := selector.Requirements()
+	if len(requirements) != 1 {
+		return misconfigured("exactly one label key is required")
+	}
+	key := requirements[0].Key()
+	if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+		return misconfigured(strings.Join(errs, "; "))
+	}
+	return &decisionGroupByLabel{key: key, selector: selector}, framework.NewStatus("", framework.Success, "")
+}
+
+// divideDecisionGroupsByLabel groups the clusters matching the label expression by the value of the label, and
+// divides each group by the group length. The groups are sorted by the label value.
+func divideDecisionGroupsByLabel(
+	byLabel *decisionGroupByLabel,
+	clusters []*clusterapiv1.ManagedCluster,
+	clusterNames sets.Set[string],
+	groupLength int,
+) []clusterDecisionGroup {
+	matched := map[string][]clusterapiv1beta1.ClusterDecision{}
+	for _, cluster := range clusters {
+		// the clusters without the label or with a value not matched are left to the group without name
+		value := cluster.Labels[byLabel.key]
+		if len(value) == 0 || !clusterNames.Has(cluster.Name) || !byLabel.selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+		matched[value] = append(matched[value], clusterapiv1beta1.ClusterDecision{
+			ClusterName: cluster.Name,
+		})
+		clusterNames.Delete(cluster.Name)
+	}
+
+	values := sets.List(sets.KeySet(matched))
+	var groups []clusterDecisionGroup
+	for _, value := range values {
+		groups = append(groups, divideDecisionGroups(value, matched[value], groupLength)...)
+	}
+	return groups
+}
+
+// sortDecisionGroups orders the decision groups by the canary groups and the group order of the placement, the
+// groups not listed keep their original order after the listed ones.
+func sortDecisionGroups(placement *clusterapiv1beta1.Placement, groups []clusterDecisionGroup) {
+	order := commonhelpers.GetDecisionGroupOrder(placement)
+	if len(order) == 0 {
+		return
+	}
+
+	ranks := map[string]int{}
+	for i, name := range order {
+		ranks[name] = i
+	}
+	rank := func(group clusterDecisionGroup) int {
+		if r, ok := ranks[group.decisionGroupName]; ok {
+			return r
+		}
+		return len(order)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return rank(groups[i]) < rank(groups[j])
+	})
+}
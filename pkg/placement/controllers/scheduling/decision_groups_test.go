@@ -0,0 +1,161 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGenerateDecisionGroups(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "us").WithLabel("canary", "true").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "us").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "us").Build(),
+		testinghelpers.NewManagedCluster("cluster4").WithLabel("region", "eu").Build(),
+		testinghelpers.NewManagedCluster("cluster5").Build(),
+	}
+	canaryGroup := clusterapiv1beta1.DecisionGroup{
+		GroupName: "canary",
+		ClusterSelector: clusterapiv1beta1.ClusterSelector{
+			LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		annotations    map[string]string
+		groupStrategy  clusterapiv1beta1.GroupStrategy
+		expectedGroups [][]string
+		expectedCode   framework.Code
+	}{
+		{
+			name: "group by label",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation: "region",
+			},
+			groupStrategy: clusterapiv1beta1.GroupStrategy{
+				DecisionGroups: []clusterapiv1beta1.DecisionGroup{canaryGroup},
+			},
+			expectedGroups: [][]string{
+				{"canary", "cluster1"},
+				{"eu", "cluster4"},
+				{"us", "cluster2", "cluster3"},
+				{"", "cluster5"},
+			},
+		},
+		{
+			name: "group by label values",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation: "region in (eu)",
+			},
+			expectedGroups: [][]string{
+				{"eu", "cluster4"},
+				{"", "cluster1", "cluster2", "cluster3", "cluster5"},
+			},
+		},
+		{
+			name: "group by label excluding values",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation: "region!=eu",
+			},
+			expectedGroups: [][]string{
+				{"us", "cluster1", "cluster2", "cluster3"},
+				{"", "cluster4", "cluster5"},
+			},
+		},
+		{
+			name: "group by label with fixed group size",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation: "region",
+			},
+			groupStrategy: clusterapiv1beta1.GroupStrategy{
+				ClustersPerDecisionGroup: intstr.FromInt32(2),
+			},
+			expectedGroups: [][]string{
+				{"eu", "cluster4"},
+				{"us", "cluster1", "cluster2"},
+				{"us", "cluster3"},
+				{"", "cluster5"},
+			},
+		},
+		{
+			name: "order groups",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation:             "region",
+				commonhelpers.DecisionGroupOrderAnnotation: "us,eu",
+			},
+			groupStrategy: clusterapiv1beta1.GroupStrategy{
+				ClustersPerDecisionGroup: intstr.FromString("40%"),
+			},
+			expectedGroups: [][]string{
+				{"us", "cluster1", "cluster2"},
+				{"us", "cluster3"},
+				{"eu", "cluster4"},
+				{"", "cluster5"},
+			},
+		},
+		{
+			name: "canary groups first",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation:               "region",
+				commonhelpers.DecisionGroupOrderAnnotation:   "us",
+				commonhelpers.CanaryDecisionGroupsAnnotation: "eu",
+			},
+			expectedGroups: [][]string{
+				{"eu", "cluster4"},
+				{"us", "cluster1", "cluster2", "cluster3"},
+				{"", "cluster5"},
+			},
+		},
+		{
+			name: "invalid label key",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation: "region/zone/",
+			},
+			expectedCode: framework.Misconfigured,
+		},
+		{
+			name: "multiple label keys",
+			annotations: map[string]string{
+				DecisionGroupByLabelAnnotation: "region,zone",
+			},
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).
+				WithGroupStrategy(c.groupStrategy).Build()
+			ctrl := &schedulingController{}
+			groups, status := ctrl.generateDecisionGroups(placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status.Code())
+			}
+			if status.IsError() {
+				return
+			}
+
+			var actual [][]string
+			for _, group := range groups {
+				names := []string{group.decisionGroupName}
+				for _, d := range group.clusterDecisions {
+					names = append(names, d.ClusterName)
+				}
+				actual = append(actual, names)
+			}
+			if !reflect.DeepEqual(actual, c.expectedGroups) {
+				t.Errorf("expected groups %v, but got %v", c.expectedGroups, actual)
+			}
+		})
+	}
+}
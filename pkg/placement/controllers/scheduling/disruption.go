@@ -0,0 +1,230 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// DisruptionBudgetMaxDisruptionsAnnotation is the annotation on the placement to limit the number of the
+	// clusters removed from its decisions within the disruption window. The value is an integer or a percentage of
+	// the number of the existing decisions, e.g. "1" or "25%". The clusters which are still available but not
+	// selected any more, because of rebalancing, taints or spec changes, are kept in the decisions once the budget
+	// is used up, and they are removed after the earlier disruptions are out of the window.
+	DisruptionBudgetMaxDisruptionsAnnotation = "cluster.open-cluster-management.io/experimental-disruption-budget-max-disruptions"
+
+	// DisruptionBudgetWindowAnnotation is the annotation on the placement to set the disruption window, the value
+	// is a duration, e.g. "30m". The default window is 1 hour.
+	DisruptionBudgetWindowAnnotation = "cluster.open-cluster-management.io/experimental-disruption-budget-window"
+
+	defaultDisruptionWindow = time.Hour
+)
+
+var DisruptionBudgetClock = clock.Clock(clock.RealClock{})
+
+// disruptionBudget is parsed from the disruption budget annotations of a placement.
+type disruptionBudget struct {
+	maxDisruptions intstr.IntOrString
+	window         time.Duration
+}
+
+// getDisruptionBudget returns nil if the disruption budget is not set for the placement.
+func getDisruptionBudget(placement *clusterapiv1beta1.Placement) (*disruptionBudget, error) {
+	annotations := placement.GetAnnotations()
+	value, ok := annotations[DisruptionBudgetMaxDisruptionsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	budget := &disruptionBudget{
+		maxDisruptions: intstr.Parse(value),
+		window:         defaultDisruptionWindow,
+	}
+	if _, err := intstr.GetScaledValueFromIntOrPercent(&budget.maxDisruptions, 100, true); err != nil {
+		return nil, fmt.Errorf("invalid value %q of annotation %s: %v", value, DisruptionBudgetMaxDisruptionsAnnotation, err)
+	}
+	if budget.maxDisruptions.Type == intstr.Int && budget.maxDisruptions.IntVal < 0 {
+		return nil, fmt.Errorf("invalid value %q of annotation %s", value, DisruptionBudgetMaxDisruptionsAnnotation)
+	}
+
+	if value, ok := annotations[DisruptionBudgetWindowAnnotation]; ok {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid value %q of annotation %s", value, DisruptionBudgetWindowAnnotation)
+		}
+		budget.window = window
+	}
+
+	return budget, nil
+}
+
+// disruptionTracker keeps the clusters removed from the decisions of each placement within the disruption window.
+// The disruptions are kept in memory, so the budget is reset once the scheduler restarts.
+type disruptionTracker struct {
+	sync.Mutex
+	disruptions map[string]map[string]time.Time
+}
+
+func newDisruptionTracker() *disruptionTracker {
+	return &disruptionTracker{
+		disruptions: map[string]map[string]time.Time{},
+	}
+}
+
+// limitDisruptions keeps the existing decisions which are not selected any more if the disruption budget of the
+// placement is used up. The newly selected clusters with the lowest scores are deferred to keep the number of the
+// decisions. It returns the decisions and the time after which the placement should be scheduled again to remove
+// the kept clusters.
+func (d *disruptionTracker) limitDisruptions(
+	ctx context.Context,
+	handle plugins.Handle,
+	placement *clusterapiv1beta1.Placement,
+	maxNumOfDecisions *int,
+	clusters []*clusterapiv1.ManagedCluster,
+	decisions []*clusterapiv1.ManagedCluster,
+	scoreSum PrioritizerScore,
+) ([]*clusterapiv1.ManagedCluster, *time.Duration, *framework.Status) {
+	budget, err := getDisruptionBudget(placement)
+	if err != nil {
+		return decisions, nil, framework.NewStatus("", framework.Warning, err.Error())
+	}
+	if budget == nil {
+		return decisions, nil, framework.NewStatus("", framework.Success, "")
+	}
+
+	existing, err := getExistingDecisions(handle, placement)
+	if err != nil {
+		return decisions, nil, framework.NewStatus("", framework.Error, err.Error())
+	}
+
+	selected := sets.New[string]()
+	for _, cluster := range decisions {
+		selected.Insert(cluster.Name)
+	}
+
+	// only the clusters still available are able to be kept, the removal of the others is not counted
+	var removed []*clusterapiv1.ManagedCluster
+	for _, cluster := range clusters {
+		if existing.Has(cluster.Name) && !selected.Has(cluster.Name) {
+			removed = append(removed, cluster)
+		}
+	}
+	sort.SliceStable(removed, func(i, j int) bool {
+		if scoreSum[removed[i].Name] == scoreSum[removed[j].Name] {
+			return removed[i].Name < removed[j].Name
+		}
+		return scoreSum[removed[i].Name] < scoreSum[removed[j].Name]
+	})
+
+	d.Lock()
+	defer d.Unlock()
+
+	key := fmt.Sprintf("%s/%s", placement.Namespace, placement.Name)
+	now := DisruptionBudgetClock.Now()
+	disruptions := map[string]time.Time{}
+	for name, t := range d.disruptions[key] {
+		if now.Before(t.Add(budget.window)) {
+			disruptions[name] = t
+		}
+	}
+
+	maxDisruptions, _ := intstr.GetScaledValueFromIntOrPercent(&budget.maxDisruptions, existing.Len(), true)
+	allowed := maxDisruptions - len(disruptions)
+
+	var kept []*clusterapiv1.ManagedCluster
+	for _, cluster := range removed {
+		if _, ok := disruptions[cluster.Name]; ok {
+			// the cluster is removed again within the window, it is counted once
+			continue
+		}
+		if allowed > 0 {
+			disruptions[cluster.Name] = now
+			allowed--
+			continue
+		}
+		kept = append(kept, cluster)
+	}
+
+	if !isDryRun(ctx) {
+		if len(disruptions) > 0 {
+			d.disruptions[key] = disruptions
+		} else {
+			delete(d.disruptions, key)
+		}
+	}
+
+	if len(kept) == 0 {
+		return decisions, nil, framework.NewStatus("", framework.Success, "")
+	}
+
+	// defer the newly selected clusters with the lowest scores
+	numOfDeferred := 0
+	if maxNumOfDecisions != nil {
+		numOfDeferred = len(decisions) + len(kept) - *maxNumOfDecisions
+	}
+	result := []*clusterapiv1.ManagedCluster{}
+	for i := len(decisions) - 1; i >= 0; i-- {
+		if numOfDeferred > 0 && !existing.Has(decisions[i].Name) {
+			numOfDeferred--
+			continue
+		}
+		result = append([]*clusterapiv1.ManagedCluster{decisions[i]}, result...)
+	}
+	result = append(result, kept...)
+
+	// schedule again once the earliest disruption is out of the window
+	var requeueAfter *time.Duration
+	for _, t := range disruptions {
+		r := t.Add(budget.window).Sub(now)
+		requeueAfter = setRequeueAfter(requeueAfter, &r)
+	}
+
+	var keptNames []string
+	for _, cluster := range kept {
+		keptNames = append(keptNames, cluster.Name)
+	}
+	msg := fmt.Sprintf("Clusters %v are kept in the decisions by the disruption budget", keptNames)
+	if !isDryRun(ctx) {
+		handle.EventRecorder().Eventf(
+			placement, nil, corev1.EventTypeWarning,
+			"DisruptionBudgetExceeded", "Schedule", msg)
+	}
+
+	return result, requeueAfter, framework.NewStatus("", framework.Warning, msg)
+}
+
+// getExistingDecisions returns the names of the clusters in the existing decisions of the placement.
+func getExistingDecisions(handle plugins.Handle, placement *clusterapiv1beta1.Placement) (sets.Set[string], error) {
+	requirement, err := labels.NewRequirement(clusterapiv1beta1.PlacementLabel, selection.Equals, []string{placement.Name})
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := handle.DecisionLister().PlacementDecisions(placement.Namespace).List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return nil, err
+	}
+
+	existing := sets.New[string]()
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			existing.Insert(d.ClusterName)
+		}
+	}
+	return existing, nil
+}
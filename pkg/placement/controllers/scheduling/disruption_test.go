@@ -0,0 +1,119 @@
+package scheduling
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestLimitDisruptions(t *testing.T) {
+	fakeTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalClock := DisruptionBudgetClock
+	DisruptionBudgetClock = testingclock.NewFakeClock(fakeTime)
+	defer func() {
+		DisruptionBudgetClock = originalClock
+	}()
+
+	// the placement selects cluster4 and cluster5 only, while cluster1, cluster2 and cluster3 are in the existing
+	// decisions.
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+			WithDecisions("cluster1", "cluster2", "cluster3").Build(),
+	}
+	newPlacement := func(annotations map[string]string) *clusterapiv1beta1.Placement {
+		return testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, annotations).WithNOC(3).
+			AddPredicate(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "new"}}, nil).Build()
+	}
+	newClusters := func() []*clusterapiv1.ManagedCluster {
+		return []*clusterapiv1.ManagedCluster{
+			testinghelpers.NewManagedCluster("cluster1").Build(),
+			testinghelpers.NewManagedCluster("cluster2").Build(),
+			testinghelpers.NewManagedCluster("cluster3").Build(),
+			testinghelpers.NewManagedCluster("cluster4").WithLabel("app", "new").Build(),
+			testinghelpers.NewManagedCluster("cluster5").WithLabel("app", "new").Build(),
+		}
+	}
+
+	cases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedDecisions    []string
+		expectedCode         framework.Code
+		expectedRequeueAfter *time.Duration
+	}{
+		{
+			name:              "no disruption budget",
+			expectedDecisions: []string{"cluster4", "cluster5"},
+			expectedCode:      framework.Success,
+		},
+		{
+			name:              "invalid disruption budget",
+			annotations:       map[string]string{DisruptionBudgetMaxDisruptionsAnnotation: "one"},
+			expectedDecisions: []string{"cluster4", "cluster5"},
+			expectedCode:      framework.Warning,
+		},
+		{
+			name:              "disruptions within budget",
+			annotations:       map[string]string{DisruptionBudgetMaxDisruptionsAnnotation: "100%"},
+			expectedDecisions: []string{"cluster4", "cluster5"},
+			expectedCode:      framework.Success,
+		},
+		{
+			name: "disruptions exceed budget",
+			annotations: map[string]string{
+				DisruptionBudgetMaxDisruptionsAnnotation: "1",
+				DisruptionBudgetWindowAnnotation:         "30m",
+			},
+			expectedDecisions:    []string{"cluster4", "cluster2", "cluster3"},
+			expectedCode:         framework.Warning,
+			expectedRequeueAfter: func() *time.Duration { d := 30 * time.Minute; return &d }(),
+		},
+		{
+			name:              "no disruption allowed",
+			annotations:       map[string]string{DisruptionBudgetMaxDisruptionsAnnotation: "0"},
+			expectedDecisions: []string{"cluster1", "cluster2", "cluster3"},
+			expectedCode:      framework.Warning,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := newPlacement(c.annotations)
+			objs := append([]runtime.Object{placement}, initObjs...)
+			clusterClient := clusterfake.NewSimpleClientset(objs...)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, objs...))
+
+			result, status := s.Schedule(context.TODO(), placement, newClusters())
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected code %v, but got %v: %s", c.expectedCode, status.Code(), status.Message())
+			}
+
+			var decisions []string
+			for _, cluster := range result.Decisions() {
+				decisions = append(decisions, cluster.Name)
+			}
+			if !reflect.DeepEqual(decisions, c.expectedDecisions) {
+				t.Errorf("expected decisions %v, but got %v", c.expectedDecisions, decisions)
+			}
+			if result.NumOfUnscheduled() != 3-len(c.expectedDecisions) {
+				t.Errorf("unexpected unscheduled %d", result.NumOfUnscheduled())
+			}
+			if c.expectedRequeueAfter != nil && !reflect.DeepEqual(result.RequeueAfter(), c.expectedRequeueAfter) {
+				t.Errorf("expected requeue after %v, but got %v", *c.expectedRequeueAfter, result.RequeueAfter())
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package scheduling
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -35,6 +36,11 @@ type enqueuer struct {
 	queue                workqueue.RateLimitingInterface
 	enqueuePlacementFunc func(obj interface{}, queue workqueue.RateLimitingInterface)
 
+	// scoreBatchWindow is the duration the placements wait before being scheduled once the AddOnPlacementScores
+	// they refer to change, the score changes within the window are handled in one schedule. The placements are
+	// scheduled immediately if it is 0.
+	scoreBatchWindow time.Duration
+
 	clusterLister            clusterlisterv1.ManagedClusterLister
 	clusterSetLister         clusterlisterv1beta2.ManagedClusterSetLister
 	placementIndexer         cache.Indexer
@@ -83,6 +89,17 @@ func enqueuePlacement(obj interface{}, queue workqueue.RateLimitingInterface) {
 	queue.Add(key)
 }
 
+// enqueuePlacementAfter adds the placement to the queue after the delay, the placement already waiting in the queue
+// is added only once.
+func enqueuePlacementAfter(obj interface{}, queue workqueue.RateLimitingInterface, delay time.Duration) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	queue.AddAfter(key, delay)
+}
+
 func (e *enqueuer) enqueueClusterSetBinding(obj interface{}) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -206,10 +223,15 @@ func (e *enqueuer) enqueuePlacementScore(obj interface{}) {
 
 	for _, o := range objs {
 		placement := o.(*clusterapiv1beta1.Placement)
-		if filteredBindingNamespaces.Has(placement.Namespace) {
-			e.logger.V(4).Info("Enqueue placement because of score", "placementNamespace", placement.Namespace, "placementName", placement.Name, "scoreKey", key)
-			e.enqueuePlacementFunc(placement, e.queue)
+		if !filteredBindingNamespaces.Has(placement.Namespace) {
+			continue
 		}
+		e.logger.V(4).Info("Enqueue placement because of score", "placementNamespace", placement.Namespace, "placementName", placement.Name, "scoreKey", key)
+		if e.scoreBatchWindow > 0 {
+			enqueuePlacementAfter(placement, e.queue, e.scoreBatchWindow)
+			continue
+		}
+		e.enqueuePlacementFunc(placement, e.queue)
 	}
 }
 
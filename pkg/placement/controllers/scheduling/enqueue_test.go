@@ -412,3 +412,44 @@ func TestEnqueuePlacementsByScore(t *testing.T) {
 		})
 	}
 }
+
+func TestEnqueuePlacementScoreWithBatchWindow(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacement("ns1", "placement1").WithScoreCoordinateAddOn("score1", "cpu", 1).Build(),
+		testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel(clusterapiv1beta2.ClusterSetLabel, "clusterset1").Build(),
+		testinghelpers.NewClusterSet("clusterset1").Build(),
+		testinghelpers.NewClusterSetBinding("ns1", "clusterset1"),
+	}
+
+	_, ctx := ktesting.NewTestContext(t)
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := newClusterInformerFactory(t, clusterClient, initObjs...)
+
+	syncCtx := testingcommon.NewFakeSyncContext(t, "fake")
+	q := newEnqueuer(
+		ctx,
+		syncCtx.Queue(),
+		clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+		clusterInformerFactory.Cluster().V1beta1().Placements(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+	)
+	q.scoreBatchWindow = 100 * time.Millisecond
+
+	// the score changes within the window are batched
+	q.enqueuePlacementScore(testinghelpers.NewAddOnPlacementScore("cluster1", "score1").Build())
+	q.enqueuePlacementScore(testinghelpers.NewAddOnPlacementScore("cluster2", "score1").Build())
+	if l := syncCtx.Queue().Len(); l != 0 {
+		t.Errorf("expected no placement queued within the batch window, but got %d", l)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if l := syncCtx.Queue().Len(); l != 1 {
+		t.Fatalf("expected 1 placement queued after the batch window, but got %d", l)
+	}
+	key, _ := syncCtx.Queue().Get()
+	if key != "ns1/placement1" {
+		t.Errorf("expected placement ns1/placement1 queued, but got %v", key)
+	}
+}
@@ -0,0 +1,69 @@
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// ScoreHysteresisAnnotation is the annotation on the placement to prevent its decisions from changing on every
+	// small score update. The value is a non-negative integer added to the score of each existing decision, so a
+	// cluster not selected replaces an existing decision only if its score is higher by more than the value.
+	ScoreHysteresisAnnotation = "cluster.open-cluster-management.io/experimental-score-hysteresis"
+
+	// hysteresisName is the name of the score record of the hysteresis in the schedule result.
+	hysteresisName = "Hysteresis"
+)
+
+// getScoreHysteresis returns 0 if the hysteresis is not set for the placement.
+func getScoreHysteresis(placement *clusterapiv1beta1.Placement) (int64, error) {
+	value, ok := placement.GetAnnotations()[ScoreHysteresisAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	hysteresis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || hysteresis < 0 {
+		return 0, fmt.Errorf("invalid value %q of annotation %s", value, ScoreHysteresisAnnotation)
+	}
+	return hysteresis, nil
+}
+
+// applyScoreHysteresis adds the hysteresis to the scores of the existing decisions of the placement, and records
+// it as a score record with weight 1.
+func applyScoreHysteresis(
+	handle plugins.Handle,
+	placement *clusterapiv1beta1.Placement,
+	results *scheduleResult,
+	scoreSum PrioritizerScore,
+) *framework.Status {
+	hysteresis, err := getScoreHysteresis(placement)
+	if err != nil {
+		return framework.NewStatus("", framework.Warning, err.Error())
+	}
+	if hysteresis == 0 {
+		return framework.NewStatus("", framework.Success, "")
+	}
+
+	existing, err := getExistingDecisions(handle, placement)
+	if err != nil {
+		return framework.NewStatus("", framework.Error, err.Error())
+	}
+
+	scores := PrioritizerScore{}
+	for name := range scoreSum {
+		if existing.Has(name) {
+			scores[name] = hysteresis
+			scoreSum[name] += hysteresis
+		} else {
+			scores[name] = 0
+		}
+	}
+	results.scoreRecords = append(results.scoreRecords, PrioritizerResult{Name: hysteresisName, Weight: 1, Scores: scores})
+
+	return framework.NewStatus("", framework.Success, "")
+}
@@ -0,0 +1,69 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestApplyScoreHysteresis(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+			WithDecisions("cluster1").Build(),
+	}
+
+	cases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedCode     framework.Code
+		expectedScoreSum PrioritizerScore
+		expectedRecord   bool
+	}{
+		{
+			name:             "no hysteresis",
+			expectedCode:     framework.Success,
+			expectedScoreSum: PrioritizerScore{"cluster1": 50, "cluster2": 60},
+		},
+		{
+			name:             "hysteresis keeps the existing decision",
+			annotations:      map[string]string{ScoreHysteresisAnnotation: "20"},
+			expectedCode:     framework.Success,
+			expectedScoreSum: PrioritizerScore{"cluster1": 70, "cluster2": 60},
+			expectedRecord:   true,
+		},
+		{
+			name:             "invalid hysteresis",
+			annotations:      map[string]string{ScoreHysteresisAnnotation: "-1"},
+			expectedCode:     framework.Warning,
+			expectedScoreSum: PrioritizerScore{"cluster1": 50, "cluster2": 60},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			handle := testinghelpers.NewFakePluginHandle(t, clusterfake.NewSimpleClientset(initObjs...), initObjs...)
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).Build()
+			results := &scheduleResult{}
+			scoreSum := PrioritizerScore{"cluster1": 50, "cluster2": 60}
+
+			status := applyScoreHysteresis(handle, placement, results, scoreSum)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status.Code())
+			}
+			if !reflect.DeepEqual(scoreSum, c.expectedScoreSum) {
+				t.Errorf("expected score sum %v, but got %v", c.expectedScoreSum, scoreSum)
+			}
+			if (len(results.scoreRecords) > 0) != c.expectedRecord {
+				t.Errorf("unexpected score records %v", results.scoreRecords)
+			}
+		})
+	}
+}
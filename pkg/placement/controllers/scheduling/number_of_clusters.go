@@ -0,0 +1,102 @@
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// MinNumberOfClustersAnnotation is the annotation on the placement to select at least the given number of
+	// clusters. Once the annotation or MaxNumberOfClustersAnnotation is set, the numberOfClusters in the spec is
+	// ignored, and the number of the selected clusters is scaled between the min and the max by the scores.
+	MinNumberOfClustersAnnotation = "cluster.open-cluster-management.io/experimental-min-number-of-clusters"
+
+	// MaxNumberOfClustersAnnotation is the annotation on the placement to select at most the given number of
+	// clusters, the number is not limited if it is not set.
+	MaxNumberOfClustersAnnotation = "cluster.open-cluster-management.io/experimental-max-number-of-clusters"
+
+	// ScoreThresholdAnnotation is the annotation on the placement to select the clusters beyond the min number
+	// only if their scores are above the threshold. All the clusters up to the max number are selected if it is
+	// not set.
+	ScoreThresholdAnnotation = "cluster.open-cluster-management.io/experimental-score-threshold"
+)
+
+// numberOfClusters is the number of the clusters to select for a placement.
+type numberOfClusters struct {
+	// min is the number of the clusters required, the decisions are unscheduled if less clusters are selected.
+	min int
+	// max is the number of the clusters to select, it is nil if all the feasible clusters are selected.
+	max *int
+	// threshold is the score the clusters beyond min must be above.
+	threshold *int64
+}
+
+func getNumberOfClusters(placement *clusterapiv1beta1.Placement) (*numberOfClusters, error) {
+	annotations := placement.GetAnnotations()
+	minValue, hasMin := annotations[MinNumberOfClustersAnnotation]
+	maxValue, hasMax := annotations[MaxNumberOfClustersAnnotation]
+	if !hasMin && !hasMax {
+		if placement.Spec.NumberOfClusters == nil {
+			return &numberOfClusters{}, nil
+		}
+		noc := int(*placement.Spec.NumberOfClusters)
+		return &numberOfClusters{min: noc, max: &noc}, nil
+	}
+
+	n := &numberOfClusters{}
+	if hasMin {
+		min, err := strconv.Atoi(minValue)
+		if err != nil || min < 0 {
+			return nil, fmt.Errorf("invalid value %q of annotation %s", minValue, MinNumberOfClustersAnnotation)
+		}
+		n.min = min
+	}
+	if hasMax {
+		max, err := strconv.Atoi(maxValue)
+		if err != nil || max < n.min {
+			return nil, fmt.Errorf("invalid value %q of annotation %s", maxValue, MaxNumberOfClustersAnnotation)
+		}
+		n.max = &max
+	}
+	if value, ok := annotations[ScoreThresholdAnnotation]; ok {
+		threshold, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of annotation %s", value, ScoreThresholdAnnotation)
+		}
+		n.threshold = &threshold
+	}
+
+	return n, nil
+}
+
+// numOfDecisions returns the number of the clusters to select from the candidate clusters which are sorted by the
+// scores.
+func (n *numberOfClusters) numOfDecisions(candidates []string, scoreSum PrioritizerScore) int {
+	num := len(candidates)
+	if n.threshold != nil {
+		num = 0
+		for _, name := range candidates {
+			if scoreSum[name] <= *n.threshold {
+				break
+			}
+			num++
+		}
+		if num < n.min {
+			num = n.min
+		}
+	}
+	if n.max != nil && num > *n.max {
+		num = *n.max
+	}
+	return num
+}
+
+// numOfUnscheduled returns the number of the unscheduled decisions once the given number of clusters are selected.
+func (n *numberOfClusters) numOfUnscheduled(scheduled int) int {
+	if scheduled >= n.min {
+		return 0
+	}
+	return n.min - scheduled
+}
@@ -0,0 +1,138 @@
+package scheduling
+
+import (
+	"testing"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestNumberOfClusters(t *testing.T) {
+	candidates := []string{"cluster1", "cluster2", "cluster3", "cluster4", "cluster5"}
+	scoreSum := PrioritizerScore{
+		"cluster1": 90,
+		"cluster2": 80,
+		"cluster3": 60,
+		"cluster4": 40,
+		"cluster5": 20,
+	}
+
+	cases := []struct {
+		name                   string
+		noc                    *int32
+		annotations            map[string]string
+		expectedErr            bool
+		expectedNumOfDecisions int
+		expectedUnscheduled    int
+	}{
+		{
+			name:                   "number of clusters not set",
+			expectedNumOfDecisions: 5,
+		},
+		{
+			name:                   "number of clusters in spec",
+			noc:                    newInt32(3),
+			expectedNumOfDecisions: 3,
+		},
+		{
+			name:                   "number of clusters in spec more than candidates",
+			noc:                    newInt32(7),
+			expectedNumOfDecisions: 5,
+			expectedUnscheduled:    2,
+		},
+		{
+			name: "min and max without threshold",
+			noc:  newInt32(1),
+			annotations: map[string]string{
+				MinNumberOfClustersAnnotation: "2",
+				MaxNumberOfClustersAnnotation: "4",
+			},
+			expectedNumOfDecisions: 4,
+		},
+		{
+			name: "scale by threshold",
+			annotations: map[string]string{
+				MinNumberOfClustersAnnotation: "1",
+				MaxNumberOfClustersAnnotation: "4",
+				ScoreThresholdAnnotation:      "50",
+			},
+			expectedNumOfDecisions: 3,
+		},
+		{
+			name: "scale up to max",
+			annotations: map[string]string{
+				MaxNumberOfClustersAnnotation: "2",
+				ScoreThresholdAnnotation:      "10",
+			},
+			expectedNumOfDecisions: 2,
+		},
+		{
+			name: "scale down to min",
+			annotations: map[string]string{
+				MinNumberOfClustersAnnotation: "2",
+				ScoreThresholdAnnotation:      "95",
+			},
+			expectedNumOfDecisions: 2,
+		},
+		{
+			name: "min more than candidates",
+			annotations: map[string]string{
+				MinNumberOfClustersAnnotation: "6",
+				ScoreThresholdAnnotation:      "95",
+			},
+			expectedNumOfDecisions: 6,
+			expectedUnscheduled:    1,
+		},
+		{
+			name:        "invalid min",
+			annotations: map[string]string{MinNumberOfClustersAnnotation: "-1"},
+			expectedErr: true,
+		},
+		{
+			name: "max less than min",
+			annotations: map[string]string{
+				MinNumberOfClustersAnnotation: "3",
+				MaxNumberOfClustersAnnotation: "2",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid threshold",
+			annotations: map[string]string{
+				MaxNumberOfClustersAnnotation: "2",
+				ScoreThresholdAnnotation:      "high",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).Build()
+			placement.Spec.NumberOfClusters = c.noc
+
+			noc, err := getNumberOfClusters(placement)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("unexpected err %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			numOfDecisions := noc.numOfDecisions(candidates, scoreSum)
+			if numOfDecisions != c.expectedNumOfDecisions {
+				t.Errorf("expected %d decisions, but got %d", c.expectedNumOfDecisions, numOfDecisions)
+			}
+			scheduled := numOfDecisions
+			if scheduled > len(candidates) {
+				scheduled = len(candidates)
+			}
+			if unscheduled := noc.numOfUnscheduled(scheduled); unscheduled != c.expectedUnscheduled {
+				t.Errorf("expected %d unscheduled decisions, but got %d", c.expectedUnscheduled, unscheduled)
+			}
+		})
+	}
+}
+
+func newInt32(i int32) *int32 {
+	return &i
+}
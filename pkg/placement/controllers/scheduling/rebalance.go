@@ -0,0 +1,281 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// RebalanceScheduleAnnotation is the annotation on the placement to re-evaluate the existing decisions
+	// periodically. The value is a cron expression in the standard format, e.g. "0 2 * * *".
+	RebalanceScheduleAnnotation = "cluster.open-cluster-management.io/experimental-rebalance-schedule"
+
+	// RebalanceScoreThresholdAnnotation is the annotation on the placement to replace an existing decision only if
+	// a cluster not selected has a score higher than it by at least the threshold. The steady score is excluded when
+	// comparing the scores. If the schedule is not set, the existing decisions are re-evaluated whenever the
+	// placement is scheduled, and at most once within the rebalance cooldown.
+	RebalanceScoreThresholdAnnotation = "cluster.open-cluster-management.io/experimental-rebalance-score-threshold"
+
+	// RebalanceMaxReplacementsAnnotation is the annotation on the placement to limit the number of the existing
+	// decisions replaced in one rebalance, the default value is 1.
+	RebalanceMaxReplacementsAnnotation = "cluster.open-cluster-management.io/experimental-rebalance-max-replacements"
+
+	defaultRebalanceMaxReplacements = 1
+	defaultRebalanceScoreThreshold  = 1
+
+	// rebalanceCooldown is the minimal interval between two rebalances of a placement without schedule.
+	rebalanceCooldown = 5 * time.Minute
+)
+
+var RebalanceClock = clock.Clock(clock.RealClock{})
+
+type dryRunKey struct{}
+
+// withDryRun returns a context with which the scheduler does not record the rebalance of the placement, it is
+// used when a placement is simulated.
+func withDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// rebalancePolicy is parsed from the rebalance annotations of a placement.
+type rebalancePolicy struct {
+	schedule        cron.Schedule
+	scoreThreshold  int64
+	maxReplacements int
+}
+
+// getRebalancePolicy returns nil if the rebalance is not enabled for the placement.
+func getRebalancePolicy(placement *clusterapiv1beta1.Placement) (*rebalancePolicy, error) {
+	annotations := placement.GetAnnotations()
+	scheduleValue, hasSchedule := annotations[RebalanceScheduleAnnotation]
+	thresholdValue, hasThreshold := annotations[RebalanceScoreThresholdAnnotation]
+	if !hasSchedule && !hasThreshold {
+		return nil, nil
+	}
+
+	policy := &rebalancePolicy{
+		scoreThreshold:  defaultRebalanceScoreThreshold,
+		maxReplacements: defaultRebalanceMaxReplacements,
+	}
+
+	if hasSchedule {
+		schedule, err := cron.ParseStandard(scheduleValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of annotation %s: %v", scheduleValue, RebalanceScheduleAnnotation, err)
+		}
+		policy.schedule = schedule
+	}
+
+	if hasThreshold {
+		threshold, err := strconv.ParseInt(thresholdValue, 10, 64)
+		if err != nil || threshold < 1 {
+			return nil, fmt.Errorf("invalid value %q of annotation %s", thresholdValue, RebalanceScoreThresholdAnnotation)
+		}
+		policy.scoreThreshold = threshold
+	}
+
+	if value, ok := annotations[RebalanceMaxReplacementsAnnotation]; ok {
+		maxReplacements, err := strconv.Atoi(value)
+		if err != nil || maxReplacements < 1 {
+			return nil, fmt.Errorf("invalid value %q of annotation %s", value, RebalanceMaxReplacementsAnnotation)
+		}
+		policy.maxReplacements = maxReplacements
+	}
+
+	return policy, nil
+}
+
+// rebalancer replaces the existing decisions of the placements with the clusters having higher scores gradually.
+// The existing decisions are kept by the steady prioritizer, so the replacement is done by moving the steady score
+// from the existing decisions to the replacing clusters. The last rebalance time of each placement is kept in
+// memory, after the scheduler restarts, the placements with schedule are rebalanced from the next scheduled time.
+type rebalancer struct {
+	sync.Mutex
+	lastRebalanceTimes map[string]time.Time
+}
+
+func newRebalancer() *rebalancer {
+	return &rebalancer{
+		lastRebalanceTimes: map[string]time.Time{},
+	}
+}
+
+// forget removes the last rebalance time of the deleted placement.
+func (r *rebalancer) forget(key string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.lastRebalanceTimes, key)
+}
+
+// rebalance updates the steady scores and the score sum of the clusters if the rebalance of the placement is due,
+// and returns the time after which the placement should be scheduled again for the next rebalance.
+func (r *rebalancer) rebalance(
+	ctx context.Context,
+	handle plugins.Handle,
+	placement *clusterapiv1beta1.Placement,
+	maxNumOfDecisions *int,
+	results *scheduleResult,
+	scoreSum PrioritizerScore,
+) (*time.Duration, *framework.Status) {
+	policy, err := getRebalancePolicy(placement)
+	if err != nil {
+		return nil, framework.NewStatus("", framework.Warning, err.Error())
+	}
+	if policy == nil {
+		return nil, framework.NewStatus("", framework.Success, "")
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	key := fmt.Sprintf("%s/%s", placement.Namespace, placement.Name)
+	now := RebalanceClock.Now()
+	lastRebalanceTime, ok := r.lastRebalanceTimes[key]
+	if !ok && policy.schedule != nil {
+		// the placement is not rebalanced until the next scheduled time
+		lastRebalanceTime = now
+		if !isDryRun(ctx) {
+			r.lastRebalanceTimes[key] = now
+		}
+	}
+
+	var nextRebalanceTime time.Time
+	switch {
+	case policy.schedule != nil:
+		nextRebalanceTime = policy.schedule.Next(lastRebalanceTime)
+	case ok:
+		nextRebalanceTime = lastRebalanceTime.Add(rebalanceCooldown)
+	}
+
+	if now.Before(nextRebalanceTime) {
+		requeueAfter := nextRebalanceTime.Sub(now)
+		return &requeueAfter, framework.NewStatus("", framework.Success, "")
+	}
+
+	replaced, replacing := replaceDecisions(maxNumOfDecisions, results, scoreSum, policy)
+
+	// the placement with schedule is rebalanced once at each scheduled time, while the placement without
+	// schedule is rebalanced again after the cooldown only if any decision is replaced.
+	var requeueAfter *time.Duration
+	switch {
+	case policy.schedule != nil:
+		d := policy.schedule.Next(now).Sub(now)
+		requeueAfter = &d
+	case len(replaced) > 0:
+		d := rebalanceCooldown
+		requeueAfter = &d
+	}
+	if !isDryRun(ctx) && (policy.schedule != nil || len(replaced) > 0) {
+		r.lastRebalanceTimes[key] = now
+	}
+
+	if len(replaced) > 0 && !isDryRun(ctx) {
+		handle.EventRecorder().Eventf(
+			placement, nil, corev1.EventTypeNormal,
+			"PlacementRebalanced", "Rebalance",
+			"Clusters %v are replaced with clusters %v", replaced, replacing)
+	}
+
+	return requeueAfter, framework.NewStatus("", framework.Success, "")
+}
+
+// replaceDecisions moves the steady score from the existing decisions with the lowest scores to the clusters not
+// selected with the highest scores, if the score difference is not less than the threshold. It returns the names
+// of the replaced clusters and the replacing clusters.
+func replaceDecisions(
+	maxNumOfDecisions *int,
+	results *scheduleResult,
+	scoreSum PrioritizerScore,
+	policy *rebalancePolicy,
+) ([]string, []string) {
+	// all the clusters are selected if the number of clusters is not limited
+	if maxNumOfDecisions == nil {
+		return nil, nil
+	}
+
+	steadyIndex := -1
+	for i, r := range results.scoreRecords {
+		if r.Name == PrioritizerSteady && r.Weight > 0 {
+			steadyIndex = i
+		}
+	}
+	if steadyIndex < 0 {
+		return nil, nil
+	}
+	steady := results.scoreRecords[steadyIndex]
+
+	baseScore := func(name string) int64 {
+		return scoreSum[name] - steady.Scores[name]*int64(steady.Weight)
+	}
+
+	var existing, candidates []string
+	for name := range scoreSum {
+		if steady.Scores[name] > 0 {
+			existing = append(existing, name)
+		} else {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Slice(existing, func(i, j int) bool {
+		if baseScore(existing[i]) == baseScore(existing[j]) {
+			return existing[i] > existing[j]
+		}
+		return baseScore(existing[i]) < baseScore(existing[j])
+	})
+	sort.Slice(candidates, func(i, j int) bool {
+		if baseScore(candidates[i]) == baseScore(candidates[j]) {
+			return candidates[i] < candidates[j]
+		}
+		return baseScore(candidates[i]) > baseScore(candidates[j])
+	})
+
+	// the candidates with the highest scores are selected anyway if the existing decisions are not enough
+	if free := *maxNumOfDecisions - len(existing); free > 0 {
+		if free >= len(candidates) {
+			return nil, nil
+		}
+		candidates = candidates[free:]
+	}
+
+	scores := PrioritizerScore{}
+	for name, score := range steady.Scores {
+		scores[name] = score
+	}
+
+	var replaced, replacing []string
+	for i := 0; i < policy.maxReplacements && i < len(existing) && i < len(candidates); i++ {
+		if baseScore(candidates[i])-baseScore(existing[i]) < policy.scoreThreshold {
+			break
+		}
+		replaced = append(replaced, existing[i])
+		replacing = append(replacing, candidates[i])
+	}
+
+	for i := range replaced {
+		scoreSum[replaced[i]] -= scores[replaced[i]] * int64(steady.Weight)
+		scoreSum[replacing[i]] += plugins.MaxClusterScore * int64(steady.Weight)
+		scores[replaced[i]] = 0
+		scores[replacing[i]] = plugins.MaxClusterScore
+	}
+	results.scoreRecords[steadyIndex].Scores = scores
+
+	return replaced, replacing
+}
@@ -0,0 +1,210 @@
+package scheduling
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestGetRebalancePolicy(t *testing.T) {
+	cases := []struct {
+		name              string
+		annotations       map[string]string
+		expectedPolicy    bool
+		expectedErr       bool
+		expectedThreshold int64
+		expectedMax       int
+	}{
+		{
+			name: "no rebalance",
+		},
+		{
+			name:              "schedule",
+			annotations:       map[string]string{RebalanceScheduleAnnotation: "0 2 * * *"},
+			expectedPolicy:    true,
+			expectedThreshold: defaultRebalanceScoreThreshold,
+			expectedMax:       defaultRebalanceMaxReplacements,
+		},
+		{
+			name: "threshold and max replacements",
+			annotations: map[string]string{
+				RebalanceScoreThresholdAnnotation:  "50",
+				RebalanceMaxReplacementsAnnotation: "2",
+			},
+			expectedPolicy:    true,
+			expectedThreshold: 50,
+			expectedMax:       2,
+		},
+		{
+			name:        "invalid schedule",
+			annotations: map[string]string{RebalanceScheduleAnnotation: "every day"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid threshold",
+			annotations: map[string]string{RebalanceScoreThresholdAnnotation: "0"},
+			expectedErr: true,
+		},
+		{
+			name: "invalid max replacements",
+			annotations: map[string]string{
+				RebalanceScoreThresholdAnnotation:  "10",
+				RebalanceMaxReplacementsAnnotation: "-1",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, c.annotations).Build()
+			policy, err := getRebalancePolicy(placement)
+			if (err != nil) != c.expectedErr {
+				t.Fatalf("unexpected err %v", err)
+			}
+			if (policy != nil) != c.expectedPolicy {
+				t.Fatalf("unexpected policy %v", policy)
+			}
+			if policy == nil {
+				return
+			}
+			if policy.scoreThreshold != c.expectedThreshold || policy.maxReplacements != c.expectedMax {
+				t.Errorf("unexpected policy %v", policy)
+			}
+		})
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	fakeTime := time.Date(2024, 1, 1, 1, 30, 0, 0, time.UTC)
+	originalClock := RebalanceClock
+	defer func() {
+		RebalanceClock = originalClock
+	}()
+
+	initObjs := []runtime.Object{
+		testinghelpers.NewPlacementDecision(placementNamespace, testinghelpers.PlacementDecisionName(placementName, 1)).
+			WithLabel(clusterapiv1beta1.PlacementLabel, placementName).
+			WithDecisions("cluster1", "cluster2").Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster1", "demo").WithScore("demo", 30).Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster2", "demo").WithScore("demo", 40).Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster3", "demo").WithScore("demo", 90).Build(),
+	}
+	newPlacement := func(annotations map[string]string) *clusterapiv1beta1.Placement {
+		return testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, annotations).WithNOC(2).
+			WithScoreCoordinateAddOn("demo", "demo", 1).Build()
+	}
+	newClusters := func() []*clusterapiv1.ManagedCluster {
+		return []*clusterapiv1.ManagedCluster{
+			testinghelpers.NewManagedCluster("cluster1").Build(),
+			testinghelpers.NewManagedCluster("cluster2").Build(),
+			testinghelpers.NewManagedCluster("cluster3").Build(),
+		}
+	}
+
+	type schedule struct {
+		after             time.Duration
+		dryRun            bool
+		expectedDecisions []string
+	}
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		schedules   []schedule
+	}{
+		{
+			name: "no rebalance",
+			schedules: []schedule{
+				{expectedDecisions: []string{"cluster2", "cluster1"}},
+			},
+		},
+		{
+			name:        "score delta below threshold",
+			annotations: map[string]string{RebalanceScoreThresholdAnnotation: "100"},
+			schedules: []schedule{
+				{expectedDecisions: []string{"cluster2", "cluster1"}},
+			},
+		},
+		{
+			name:        "score delta exceeds threshold",
+			annotations: map[string]string{RebalanceScoreThresholdAnnotation: "50"},
+			schedules: []schedule{
+				{expectedDecisions: []string{"cluster3", "cluster2"}},
+			},
+		},
+		{
+			name:        "rebalance at scheduled time",
+			annotations: map[string]string{RebalanceScheduleAnnotation: "0 * * * *"},
+			schedules: []schedule{
+				{expectedDecisions: []string{"cluster2", "cluster1"}},
+				{after: 40 * time.Minute, dryRun: true, expectedDecisions: []string{"cluster3", "cluster2"}},
+				{after: 40 * time.Minute, expectedDecisions: []string{"cluster3", "cluster2"}},
+				{after: 40 * time.Minute, expectedDecisions: []string{"cluster2", "cluster1"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fakeClock := testingclock.NewFakeClock(fakeTime)
+			RebalanceClock = fakeClock
+
+			placement := newPlacement(c.annotations)
+			objs := append([]runtime.Object{placement}, initObjs...)
+			clusterClient := clusterfake.NewSimpleClientset(objs...)
+			s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, objs...))
+
+			for i, sc := range c.schedules {
+				fakeClock.SetTime(fakeTime.Add(sc.after))
+				ctx := context.TODO()
+				if sc.dryRun {
+					ctx = withDryRun(ctx)
+				}
+
+				result, status := s.Schedule(ctx, placement, newClusters())
+				if status.Code() != framework.Success {
+					t.Fatalf("unexpected status %v", status.AsError())
+				}
+
+				var decisions []string
+				for _, cluster := range result.Decisions() {
+					decisions = append(decisions, cluster.Name)
+				}
+				if !reflect.DeepEqual(decisions, sc.expectedDecisions) {
+					t.Errorf("schedule %d: expected decisions %v, but got %v", i, sc.expectedDecisions, decisions)
+				}
+			}
+		})
+	}
+}
+
+func TestForgetRebalance(t *testing.T) {
+	placement := testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, map[string]string{
+		RebalanceScheduleAnnotation: "0 * * * *",
+	}).WithNOC(2).Build()
+	clusterClient := clusterfake.NewSimpleClientset(placement)
+	s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, placement))
+
+	if _, status := s.Schedule(context.TODO(), placement, nil); status.Code() != framework.Success {
+		t.Fatalf("unexpected status %v", status.AsError())
+	}
+	if len(s.rebalancer.lastRebalanceTimes) != 1 {
+		t.Fatalf("expected the last rebalance time is kept, but got %v", s.rebalancer.lastRebalanceTimes)
+	}
+
+	s.Forget(placementNamespace + "/" + placementName)
+	if len(s.rebalancer.lastRebalanceTimes) != 0 {
+		t.Errorf("expected the last rebalance time is removed, but got %v", s.rebalancer.lastRebalanceTimes)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
 
@@ -20,13 +22,18 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 	"open-cluster-management.io/ocm/pkg/placement/plugins"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/addon"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/balance"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/external"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/flapping"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/predicate"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/preemption"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/resource"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/steady"
 	"open-cluster-management.io/ocm/pkg/placement/plugins/tainttoleration"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/topology"
 )
 
 const (
@@ -34,6 +41,10 @@ const (
 	PrioritizerSteady                    string = "Steady"
 	PrioritizerResourceAllocatableCPU    string = "ResourceAllocatableCPU"
 	PrioritizerResourceAllocatableMemory string = "ResourceAllocatableMemory"
+	PrioritizerResourceAvailableCPU      string = "ResourceAvailableCPU"
+	PrioritizerResourceAvailableMemory   string = "ResourceAvailableMemory"
+	PrioritizerTopologyProximity         string = "TopologyProximity"
+	PrioritizerFlapping                  string = "Flapping"
 )
 
 // PrioritizerScore defines the score for each cluster
@@ -48,6 +59,12 @@ type Scheduler interface {
 	) (ScheduleResult, *framework.Status)
 }
 
+// placementForgetter is implemented by the schedulers keeping in memory state per placement, the state is removed
+// once the placement is deleted.
+type placementForgetter interface {
+	Forget(placementKey string)
+}
+
 type ScheduleResult interface {
 	// FilterResults returns results for each filter
 	FilterResults() []FilterResult
@@ -66,6 +83,9 @@ type ScheduleResult interface {
 
 	// RequeueAfter returns the requeue time interval of the placement
 	RequeueAfter() *time.Duration
+
+	// PreemptedPlacements returns the namespaced names of the placements preempted by the decisions
+	PreemptedPlacements() []string
 }
 
 type FilterResult struct {
@@ -91,6 +111,8 @@ type scheduleResult struct {
 	scoreRecords    []PrioritizerResult
 	scoreSum        PrioritizerScore
 	requeueAfter    *time.Duration
+
+	preemptedPlacements []string
 }
 
 type schedulerHandler struct {
@@ -99,7 +121,9 @@ type schedulerHandler struct {
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
 	scoreLister             clusterlisterv1alpha1.AddOnPlacementScoreLister
 	clusterLister           clusterlisterv1.ManagedClusterLister
+	placementLister         clusterlisterv1beta1.PlacementLister
 	clusterClient           clusterclient.Interface
+	schedulingCache         *schedulingcache.SchedulingCache
 }
 
 func NewSchedulerHandler(
@@ -107,8 +131,10 @@ func NewSchedulerHandler(
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister,
 	scoreLister clusterlisterv1alpha1.AddOnPlacementScoreLister,
 	clusterLister clusterlisterv1.ManagedClusterLister,
+	placementLister clusterlisterv1beta1.PlacementLister,
 	eventsRecorder kevents.EventRecorder,
 	metricsRecorder *metrics.ScheduleMetrics,
+	schedulingCache *schedulingcache.SchedulingCache,
 ) plugins.Handle {
 
 	return &schedulerHandler{
@@ -117,7 +143,9 @@ func NewSchedulerHandler(
 		placementDecisionLister: placementDecisionLister,
 		scoreLister:             scoreLister,
 		clusterLister:           clusterLister,
+		placementLister:         placementLister,
 		clusterClient:           clusterClient,
+		schedulingCache:         schedulingCache,
 	}
 }
 
@@ -137,6 +165,10 @@ func (s *schedulerHandler) ClusterLister() clusterlisterv1.ManagedClusterLister
 	return s.clusterLister
 }
 
+func (s *schedulerHandler) PlacementLister() clusterlisterv1beta1.PlacementLister {
+	return s.placementLister
+}
+
 func (s *schedulerHandler) ClusterClient() clusterclient.Interface {
 	return s.clusterClient
 }
@@ -145,6 +177,13 @@ func (s *schedulerHandler) MetricsRecorder() *metrics.ScheduleMetrics {
 	return s.metricsRecorder
 }
 
+func (s *schedulerHandler) SchedulingCache() *schedulingcache.SchedulingCache {
+	if !s.schedulingCache.HasSynced() {
+		return nil
+	}
+	return s.schedulingCache
+}
+
 // Initialize the default prioritizer weight.
 // Balane and Steady weight 1, others weight 0.
 // The default weight can be replaced by each placement's PrioritizerConfigs.
@@ -160,20 +199,67 @@ var defaultPrioritizerConfig = map[clusterapiv1beta1.ScoreCoordinate]int32{
 }
 
 type pluginScheduler struct {
-	handle             plugins.Handle
-	filters            []plugins.Filter
-	prioritizerWeights map[clusterapiv1beta1.ScoreCoordinate]int32
+	handle               plugins.Handle
+	filters              []plugins.Filter
+	prioritizerWeights   map[clusterapiv1beta1.ScoreCoordinate]int32
+	externalPrioritizers map[string]plugins.Prioritizer
+	flappingTracker      *flapping.Tracker
+	rebalancer           *rebalancer
+	disruptionTracker    *disruptionTracker
+	preemption           *preemption.Preemption
 }
 
 func NewPluginScheduler(handle plugins.Handle) *pluginScheduler {
+	preemptionFilter := preemption.New(handle)
 	return &pluginScheduler{
 		handle: handle,
 		filters: []plugins.Filter{
 			predicate.New(handle),
 			tainttoleration.New(handle),
+			preemptionFilter,
 		},
 		prioritizerWeights: defaultPrioritizerConfig,
+		flappingTracker:    flapping.NewTracker(),
+		rebalancer:         newRebalancer(),
+		disruptionTracker:  newDisruptionTracker(),
+		preemption:         preemptionFilter,
+	}
+}
+
+// Forget removes the in memory rebalance state of the deleted placement.
+func (s *pluginScheduler) Forget(placementKey string) {
+	s.rebalancer.forget(placementKey)
+}
+
+// WithFlappingTracker sets the tracker of the cluster availability transitions used by the Flapping prioritizer,
+// the tracker should be registered as the event handler of the managed cluster informer.
+func (s *pluginScheduler) WithFlappingTracker(tracker *flapping.Tracker) *pluginScheduler {
+	s.flappingTracker = tracker
+	return s
+}
+
+// WithExternalPrioritizers registers the prioritizers of the out-of-tree score providers, a placement refers to them
+// with the BuiltIn prioritizer names, e.g. "External/latency". The prioritizers with non-zero default weight are
+// added to the default prioritizers.
+func (s *pluginScheduler) WithExternalPrioritizers(prioritizers ...*external.External) *pluginScheduler {
+	weights := make(map[clusterapiv1beta1.ScoreCoordinate]int32)
+	for sc, w := range s.prioritizerWeights {
+		weights[sc] = w
+	}
+
+	s.externalPrioritizers = make(map[string]plugins.Prioritizer)
+	for _, p := range prioritizers {
+		s.externalPrioritizers[p.Name()] = p
+		if p.Weight() != 0 {
+			weights[clusterapiv1beta1.ScoreCoordinate{
+				Type:    clusterapiv1beta1.ScoreCoordinateTypeBuiltIn,
+				BuiltIn: p.Name(),
+			}] = p.Weight()
+		}
 	}
+	s.prioritizerWeights = weights
+
+	return s
 }
 
 func (s *pluginScheduler) Schedule(
@@ -218,6 +304,12 @@ func (s *pluginScheduler) Schedule(
 		results.filteredRecords[strings.Join(filterPipline, ",")] = filtered
 	}
 
+	// get the range of the number of clusters to select
+	noc, err := getNumberOfClusters(placement)
+	if err != nil {
+		return results, framework.NewStatus("", framework.Misconfigured, err.Error())
+	}
+
 	// Prioritize clusters
 	// 1. Get weight for each prioritizers.
 	// For example, weights is {"Steady": 1, "Balance":1, "AddOn/default/ratio":3}.
@@ -231,7 +323,7 @@ func (s *pluginScheduler) Schedule(
 	}
 
 	// 2. Generate prioritizers for each placement whose weight != 0.
-	prioritizers, status := getPrioritizers(weights, s.handle)
+	prioritizers, status := getPrioritizers(weights, s.externalPrioritizers, s.flappingTracker, s.handle)
 	switch {
 	case status.IsError():
 		return results, status
@@ -279,7 +371,25 @@ func (s *pluginScheduler) Schedule(
 
 	}
 
-	// 4. Sort clusters by score, if score is equal, sort by name
+	// Keep the existing decisions unless the other clusters have higher scores by more than the hysteresis.
+	status = applyScoreHysteresis(s.handle, placement, results, scoreSum)
+	switch {
+	case status.IsError():
+		return results, status
+	case status.Code() == framework.Warning:
+		logger.Info("Warning status message", "message", status.Message())
+		finalStatus = status
+	}
+
+	// 4. Replace the existing decisions with the clusters having higher scores if the rebalance is due.
+	rebalanceRequeueAfter, status := s.rebalancer.rebalance(ctx, s.handle, placement, noc.max, results, scoreSum)
+	if status.Code() == framework.Warning {
+		logger.Info("Warning status message", "message", status.Message())
+		finalStatus = status
+	}
+	results.requeueAfter = setRequeueAfter(results.requeueAfter, rebalanceRequeueAfter)
+
+	// 5. Sort clusters by score, if score is equal, sort by name
 	sort.SliceStable(filtered, func(i, j int) bool {
 		if scoreSum[filtered[i].Name] == scoreSum[filtered[j].Name] {
 			return filtered[i].Name < filtered[j].Name
@@ -292,13 +402,32 @@ func (s *pluginScheduler) Schedule(
 	results.scoreSum = scoreSum
 
 	// select clusters and generate cluster decisions
-	decisions := selectClusters(placement, filtered)
-	scheduled, unscheduled := len(decisions), 0
-	if placement.Spec.NumberOfClusters != nil {
-		unscheduled = int(*placement.Spec.NumberOfClusters) - scheduled
+	candidates := make([]string, 0, len(filtered))
+	for _, cluster := range filtered {
+		candidates = append(candidates, cluster.Name)
 	}
+	decisions := selectClusters(placement, filtered, noc.numOfDecisions(candidates, scoreSum))
+
+	// keep the existing decisions if the disruption budget is used up
+	decisions, disruptionRequeueAfter, status := s.disruptionTracker.limitDisruptions(ctx, s.handle, placement, noc.max, clusters, decisions, scoreSum)
+	switch {
+	case status.IsError():
+		return results, status
+	case status.Code() == framework.Warning:
+		logger.Info("Warning status message", "message", status.Message())
+		finalStatus = status
+	}
+	results.requeueAfter = setRequeueAfter(results.requeueAfter, disruptionRequeueAfter)
+
 	results.scheduledDecisions = decisions
-	results.unscheduledDecisions = unscheduled
+	results.unscheduledDecisions = noc.numOfUnscheduled(len(decisions))
+
+	// find the placements with lower priority preempted by the decisions
+	preempted, err := s.preemption.Preempted(placement, decisions)
+	if err != nil {
+		return results, framework.NewStatus(s.preemption.Name(), framework.Error, err.Error())
+	}
+	results.preemptedPlacements = s.recordPreemption(ctx, placement, preempted)
 
 	// set placement requeue time
 	for _, f := range s.filters {
@@ -317,11 +446,56 @@ func (s *pluginScheduler) Schedule(
 	return results, finalStatus
 }
 
+// recordPreemption records the preemption events on both the placement and the preempted placements, and returns
+// the sorted namespaced names of the preempted placements.
+func (s *pluginScheduler) recordPreemption(
+	ctx context.Context,
+	placement *clusterapiv1beta1.Placement,
+	preempted map[string][]string,
+) []string {
+	var keys []string
+	for key := range preempted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if isDryRun(ctx) {
+		return keys
+	}
+
+	for _, key := range keys {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+		s.handle.EventRecorder().Eventf(
+			placement, nil, corev1.EventTypeNormal,
+			"PlacementPreempting", "Preempt",
+			"Placement %s is preempted on clusters %v", key, preempted[key])
+
+		preemptedPlacement, err := s.handle.PlacementLister().Placements(namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		s.handle.EventRecorder().Eventf(
+			preemptedPlacement, nil, corev1.EventTypeWarning,
+			"PlacementPreempted", "Preempt",
+			"Clusters %v are preempted by placement %s/%s with higher priority",
+			preempted[key], placement.Namespace, placement.Name)
+	}
+
+	return keys
+}
+
 // selects clusters based on given cluster slice and number of clusters
-func selectClusters(placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) []*clusterapiv1.ManagedCluster {
-	numOfDecisions := len(clusters)
-	if placement.Spec.NumberOfClusters != nil {
-		numOfDecisions = int(*placement.Spec.NumberOfClusters)
+func selectClusters(
+	placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster,
+	numOfDecisions int,
+) []*clusterapiv1.ManagedCluster {
+	// select clusters one by one to spread them among the topologies if spread constraints are defined
+	if len(placement.Spec.SpreadPolicy.SpreadConstraints) > 0 {
+		return selectClustersWithSpreadConstraints(placement.Spec.SpreadPolicy.SpreadConstraints, clusters, numOfDecisions)
 	}
 
 	// truncate the cluster slice if the desired number of decisions is less than
@@ -385,7 +559,8 @@ func mergeWeights(defaultWeight map[clusterapiv1beta1.ScoreCoordinate]int32,
 }
 
 // Generate prioritizers for the placement.
-func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle plugins.Handle,
+func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32,
+	externalPrioritizers map[string]plugins.Prioritizer, flappingTracker *flapping.Tracker, handle plugins.Handle,
 ) (map[clusterapiv1beta1.ScoreCoordinate]plugins.Prioritizer, *framework.Status) {
 	result := make(map[clusterapiv1beta1.ScoreCoordinate]plugins.Prioritizer)
 	status := framework.NewStatus("", framework.Success, "")
@@ -399,8 +574,20 @@ func getPrioritizers(weights map[clusterapiv1beta1.ScoreCoordinate]int32, handle
 				result[k] = balance.New(handle)
 			case k.BuiltIn == PrioritizerSteady:
 				result[k] = steady.New(handle)
-			case k.BuiltIn == PrioritizerResourceAllocatableCPU || k.BuiltIn == PrioritizerResourceAllocatableMemory:
+			case k.BuiltIn == PrioritizerResourceAllocatableCPU || k.BuiltIn == PrioritizerResourceAllocatableMemory ||
+				k.BuiltIn == PrioritizerResourceAvailableCPU || k.BuiltIn == PrioritizerResourceAvailableMemory:
 				result[k] = resource.NewResourcePrioritizerBuilder(handle).WithPrioritizerName(k.BuiltIn).Build()
+			case k.BuiltIn == PrioritizerTopologyProximity:
+				result[k] = topology.New(handle)
+			case k.BuiltIn == PrioritizerFlapping:
+				result[k] = flapping.New(handle, flappingTracker)
+			case strings.HasPrefix(k.BuiltIn, external.PrioritizerPrefix):
+				p, ok := externalPrioritizers[k.BuiltIn]
+				if !ok {
+					msg := fmt.Sprintf("score provider of builtin prioritizer %s is not configured", k.BuiltIn)
+					return nil, framework.NewStatus("", framework.Misconfigured, msg)
+				}
+				result[k] = p
 			default:
 				msg := fmt.Sprintf("incorrect builtin prioritizer: %s", k.BuiltIn)
 				return nil, framework.NewStatus("", framework.Misconfigured, msg)
@@ -458,3 +645,7 @@ func (r *scheduleResult) NumOfUnscheduled() int {
 func (r *scheduleResult) RequeueAfter() *time.Duration {
 	return r.requeueAfter
 }
+
+func (r *scheduleResult) PreemptedPlacements() []string {
+	return r.preemptedPlacements
+}
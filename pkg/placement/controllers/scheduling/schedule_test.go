@@ -18,6 +18,7 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/placement/plugins/external"
 )
 
 func TestSchedule(t *testing.T) {
@@ -58,6 +59,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -96,6 +101,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -169,6 +178,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -204,6 +217,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -274,6 +291,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -333,6 +354,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -397,6 +422,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -444,6 +473,10 @@ func TestSchedule(t *testing.T) {
 					Name:             "Predicate,TaintToleration",
 					FilteredClusters: []string{"cluster1", "cluster2"},
 				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
+					FilteredClusters: []string{"cluster1", "cluster2"},
+				},
 			},
 			expectedScoreResult: []PrioritizerResult{
 				{
@@ -488,6 +521,10 @@ func TestSchedule(t *testing.T) {
 				},
 				{
 					Name:             "Predicate,TaintToleration",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
 					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
 				},
 			},
@@ -539,6 +576,10 @@ func TestSchedule(t *testing.T) {
 				},
 				{
 					Name:             "Predicate,TaintToleration",
+					FilteredClusters: []string{"cluster1", "cluster2", "cluster3"},
+				},
+				{
+					Name:             "Predicate,TaintToleration,Preemption",
 					FilteredClusters: []string{"cluster3", "cluster1", "cluster2"},
 				},
 			},
@@ -607,3 +648,73 @@ func TestSchedule(t *testing.T) {
 func TestFilterResults(t *testing.T) {
 
 }
+
+func TestWithExternalPrioritizers(t *testing.T) {
+	latency, err := external.New(external.ProviderConfig{
+		Name: "latency", Type: external.ProviderTypeHTTP, URL: "http://localhost:8080/score", Weight: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, err := external.New(external.ProviderConfig{
+		Name: "cost", Type: external.ProviderTypeHTTP, URL: "http://localhost:8081/score"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, nil)).WithExternalPrioritizers(latency, cost)
+
+	cases := []struct {
+		name                 string
+		placement            *clusterapiv1beta1.Placement
+		expectedPrioritizers []string
+		expectedCode         framework.Code
+	}{
+		{
+			name:                 "default weights",
+			placement:            testinghelpers.NewPlacement(placementNamespace, placementName).Build(),
+			expectedPrioritizers: []string{"Balance", "External/latency", "Steady"},
+			expectedCode:         framework.Success,
+		},
+		{
+			name: "external prioritizer configured explicitly",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).
+				WithPrioritizerPolicy("Exact").WithPrioritizerConfig("External/cost", 1).Build(),
+			expectedPrioritizers: []string{"External/cost"},
+			expectedCode:         framework.Success,
+		},
+		{
+			name: "external prioritizer not configured",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).
+				WithPrioritizerConfig("External/unknown", 1).Build(),
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			weights, status := getWeights(s.prioritizerWeights, c.placement)
+			if err := status.AsError(); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			prioritizers, status := getPrioritizers(weights, s.externalPrioritizers, s.flappingTracker, s.handle)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected status code %v, but got %v", c.expectedCode, status.Code())
+			}
+
+			var names []string
+			for _, p := range prioritizers {
+				names = append(names, p.Name())
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, c.expectedPrioritizers) {
+				t.Errorf("expected prioritizers %v, but got %v", c.expectedPrioritizers, names)
+			}
+		})
+	}
+
+	// the default weights are not changed
+	if len(defaultPrioritizerConfig) != 2 {
+		t.Errorf("unexpected default prioritizer config %v", defaultPrioritizerConfig)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -22,6 +23,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	kevents "k8s.io/client-go/tools/events"
 	"k8s.io/klog/v2"
@@ -43,6 +47,7 @@ import (
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 	"open-cluster-management.io/ocm/pkg/placement/helpers"
 )
 
@@ -50,6 +55,7 @@ const (
 	schedulingControllerName = "SchedulingController"
 	maxNumOfClusterDecisions = 100
 	maxEventMessageLength    = 1000 //the event message can have at most 1024 characters, use 1000 as limitation here to keep some buffer
+	preemptionRequeueDelay   = time.Second
 )
 
 // decisionGroups groups the cluster decisions by group strategy
@@ -62,12 +68,15 @@ type clusterDecisionGroup struct {
 
 // schedulingController schedules cluster decisions for Placements
 type schedulingController struct {
+	kubeClient              kubernetes.Interface
 	clusterClient           clusterclient.Interface
 	clusterLister           clusterlisterv1.ManagedClusterLister
 	clusterSetLister        clusterlisterv1beta2.ManagedClusterSetLister
 	clusterSetBindingLister clusterlisterv1beta2.ManagedClusterSetBindingLister
 	placementLister         clusterlisterv1beta1.PlacementLister
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
+	configMapLister         corev1listers.ConfigMapLister
+	schedulingCache         *schedulingcache.SchedulingCache
 	scheduler               Scheduler
 	eventsRecorder          kevents.EventRecorder
 	metricsRecorder         *metrics.ScheduleMetrics
@@ -76,6 +85,7 @@ type schedulingController struct {
 // NewSchedulingController return an instance of schedulingController
 func NewSchedulingController(
 	ctx context.Context,
+	kubeClient kubernetes.Interface,
 	clusterClient clusterclient.Interface,
 	clusterInformer clusterinformerv1.ManagedClusterInformer,
 	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
@@ -83,22 +93,29 @@ func NewSchedulingController(
 	placementInformer clusterinformerv1beta1.PlacementInformer,
 	placementDecisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
 	placementScoreInformer clusterinformerv1alpha1.AddOnPlacementScoreInformer,
+	scoreBreakdownInformer corev1informers.ConfigMapInformer,
+	schedulingCache *schedulingcache.SchedulingCache,
 	scheduler Scheduler,
+	scoreBatchWindow time.Duration,
 	recorder events.Recorder, krecorder kevents.EventRecorder,
 	metricsRecorder *metrics.ScheduleMetrics,
 ) factory.Controller {
 	syncCtx := factory.NewSyncContext(schedulingControllerName, recorder)
 
 	enQueuer := newEnqueuer(ctx, syncCtx.Queue(), clusterInformer, clusterSetInformer, placementInformer, clusterSetBindingInformer)
+	enQueuer.scoreBatchWindow = scoreBatchWindow
 
 	// build controller
 	c := &schedulingController{
+		kubeClient:              kubeClient,
 		clusterClient:           clusterClient,
 		clusterLister:           clusterInformer.Lister(),
 		clusterSetLister:        clusterSetInformer.Lister(),
 		clusterSetBindingLister: clusterSetBindingInformer.Lister(),
 		placementLister:         placementInformer.Lister(),
 		placementDecisionLister: placementDecisionInformer.Lister(),
+		configMapLister:         scoreBreakdownInformer.Lister(),
+		schedulingCache:         schedulingCache,
 		scheduler:               scheduler,
 		eventsRecorder:          krecorder,
 		metricsRecorder:         metricsRecorder,
@@ -175,7 +192,8 @@ func NewSchedulingController(
 		},
 			queue.FileterByLabel(clusterapiv1beta1.PlacementLabel),
 			placementDecisionInformer.Informer()).
-		WithBareInformers(clusterInformer.Informer(), clusterSetInformer.Informer(), clusterSetBindingInformer.Informer(), placementScoreInformer.Informer()).
+		WithBareInformers(clusterInformer.Informer(), clusterSetInformer.Informer(), clusterSetBindingInformer.Informer(),
+			placementScoreInformer.Informer(), scoreBreakdownInformer.Informer()).
 		WithSync(c.sync).
 		ToController(schedulingControllerName, recorder)
 }
@@ -188,6 +206,10 @@ func (c *schedulingController) sync(ctx context.Context, syncCtx factory.SyncCon
 	placement, err := c.getPlacement(queueKey)
 	if errors.IsNotFound(err) {
 		// no work if placement is deleted
+		c.metricsRecorder.Forget(queueKey)
+		if forgetter, ok := c.scheduler.(placementForgetter); ok {
+			forgetter.Forget(queueKey)
+		}
 		return nil
 	}
 	if err != nil {
@@ -241,6 +263,7 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	}
 
 	// schedule placement with scheduler
+	c.metricsRecorder.ObserveCandidates(queueKey, len(clusters))
 	c.metricsRecorder.StartSchedule(queueKey)
 	scheduleResult, status := c.scheduler.Schedule(ctx, placement, clusters)
 	// generate placement decision and status
@@ -268,6 +291,9 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 	}
 
 	// create/update placement decisions
+	if err := c.observeDecisionChanges(queueKey, placement, scheduleResult.Decisions()); err != nil {
+		return err
+	}
 	c.metricsRecorder.StartBind(queueKey)
 	defer c.metricsRecorder.Done(queueKey)
 	err = c.bind(ctx, placement, decisions, scheduleResult.PrioritizerScores(), status)
@@ -275,11 +301,32 @@ func (c *schedulingController) syncPlacement(ctx context.Context, syncCtx factor
 		return err
 	}
 
+	// requeue the preempted placements to release the preempted clusters, the delay gives the informer a chance
+	// to observe the updated decisions of this placement.
+	if syncCtx != nil {
+		for _, key := range scheduleResult.PreemptedPlacements() {
+			logger.V(4).Info("Requeue preempted placement", "placementKey", key)
+			syncCtx.Queue().AddAfter(key, preemptionRequeueDelay)
+		}
+	}
+
+	// record the score breakdown of the clusters if it is enabled
+	if err := c.syncScoreBreakdown(ctx, placement, scheduleResult); err != nil {
+		return err
+	}
+
 	// update placement status if necessary to signal no bindings
 	if err := c.updateStatus(ctx, placement, groupStatus, int32(len(scheduleResult.Decisions())), misconfiguredCondition, satisfiedCondition); err != nil {
 		return err
 	}
 
+	// count the placement becoming misconfigured only once the condition is persisted, so the resyncs and the
+	// retries of a placement staying misconfigured are not counted again.
+	if misconfiguredCondition.Status == metav1.ConditionTrue &&
+		!meta.IsStatusConditionTrue(placement.Status.Conditions, clusterapiv1beta1.PlacementConditionMisconfigured) {
+		c.metricsRecorder.ObserveMisscheduled(queueKey)
+	}
+
 	return status.AsError()
 }
 
@@ -347,7 +394,7 @@ func (c *schedulingController) getAvailableClusters(clusterSetNames []string) ([
 		if err != nil {
 			return nil, err
 		}
-		clusters, err := clustersdkv1beta2.GetClustersFromClusterSet(clusterSet, c.clusterLister)
+		clusters, err := c.getClustersFromClusterSet(clusterSet)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get clusterset: %v, clusters, Error: %v", clusterSet.Name, err)
 		}
@@ -370,6 +417,15 @@ func (c *schedulingController) getAvailableClusters(clusterSetNames []string) ([
 	return result, nil
 }
 
+// getClustersFromClusterSet returns the clusters of the clusterset from the scheduling cache once it is synced, so
+// the clusters are not listed for each schedule.
+func (c *schedulingController) getClustersFromClusterSet(clusterSet *clusterapiv1beta2.ManagedClusterSet) ([]*clusterapiv1.ManagedCluster, error) {
+	if c.schedulingCache.HasSynced() {
+		return c.schedulingCache.ClustersInClusterSet(clusterSet)
+	}
+	return clustersdkv1beta2.GetClustersFromClusterSet(clusterSet, c.clusterLister)
+}
+
 // updateStatus updates the status of the placement according to intermediate scheduling data.
 func (c *schedulingController) updateStatus(
 	ctx context.Context,
@@ -525,6 +581,15 @@ func (c *schedulingController) generateDecisionGroups(
 		groups = append(groups, decisionGroups...)
 	}
 
+	// The rest of the clusters are grouped by the label value if the label is set.
+	byLabel, status := getDecisionGroupByLabel(placement)
+	if status.IsError() {
+		return groups, status
+	}
+	if byLabel != nil {
+		groups = append(groups, divideDecisionGroupsByLabel(byLabel, clusters, clusterNameSet, groupLength)...)
+	}
+
 	// The rest of the clusters will also be put into decision groups.
 	var matched []clusterapiv1beta1.ClusterDecision
 	for _, cluster := range clusterNameSet.UnsortedList() {
@@ -537,6 +602,9 @@ func (c *schedulingController) generateDecisionGroups(
 	decisionGroups := divideDecisionGroups("", matched, groupLength)
 	groups = append(groups, decisionGroups...)
 
+	// Order the groups by the canary groups and the group order, the decision group index follows the order.
+	sortDecisionGroups(placement, groups)
+
 	// generate at least on empty decisionGroup, this is to ensure there's an empty placement decision if no cluster selected.
 	if len(groups) == 0 {
 		groups = append(groups, clusterDecisionGroup{})
@@ -662,6 +730,34 @@ func (c *schedulingController) bind(
 	return errorhelpers.NewMultiLineAggregate(errs)
 }
 
+// observeDecisionChanges records the number of clusters added to and removed from the existing decisions of the
+// placement by the new decisions.
+func (c *schedulingController) observeDecisionChanges(
+	queueKey string, placement *clusterapiv1beta1.Placement, decisions []*clusterapiv1.ManagedCluster) error {
+	requirement, err := labels.NewRequirement(clusterapiv1beta1.PlacementLabel, selection.Equals, []string{placement.Name})
+	if err != nil {
+		return err
+	}
+	pds, err := c.placementDecisionLister.PlacementDecisions(placement.Namespace).List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return err
+	}
+
+	existing := sets.New[string]()
+	for _, pd := range pds {
+		for _, decision := range pd.Status.Decisions {
+			existing.Insert(decision.ClusterName)
+		}
+	}
+	decided := sets.New[string]()
+	for _, cluster := range decisions {
+		decided.Insert(cluster.Name)
+	}
+
+	c.metricsRecorder.ObserveDecisionChanges(queueKey, decided.Difference(existing).Len(), existing.Difference(decided).Len())
+	return nil
+}
+
 // createOrUpdatePlacementDecision creates a new PlacementDecision if it does not exist and
 // then updates the status with the given ClusterDecision slice if necessary
 func (c *schedulingController) createOrUpdatePlacementDecision(
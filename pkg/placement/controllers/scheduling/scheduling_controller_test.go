@@ -8,14 +8,18 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	kevents "k8s.io/client-go/tools/events"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/utils/clock"
 
 	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
@@ -32,6 +36,7 @@ import (
 
 type testScheduler struct {
 	result ScheduleResult
+	status *framework.Status
 }
 
 const (
@@ -43,7 +48,7 @@ func (s *testScheduler) Schedule(ctx context.Context,
 	placement *clusterapiv1beta1.Placement,
 	clusters []*clusterapiv1.ManagedCluster,
 ) (ScheduleResult, *framework.Status) {
-	return s.result, nil
+	return s.result, s.status
 }
 
 func TestSchedulingController_sync(t *testing.T) {
@@ -558,13 +563,18 @@ func TestSchedulingController_sync(t *testing.T) {
 			clusterInformerFactory := newClusterInformerFactory(t, clusterClient, c.initObjs...)
 			s := &testScheduler{result: c.scheduleResult}
 
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 5*time.Minute)
+
 			ctrl := schedulingController{
+				kubeClient:              kubeClient,
 				clusterClient:           clusterClient,
 				clusterLister:           clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
 				clusterSetLister:        clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
 				clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
 				placementLister:         clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
 				placementDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+				configMapLister:         kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
 				scheduler:               s,
 				eventsRecorder:          kevents.NewFakeRecorder(100),
 				metricsRecorder:         metrics.NewScheduleMetrics(clock.RealClock{}),
@@ -581,6 +591,77 @@ func TestSchedulingController_sync(t *testing.T) {
 	}
 }
 
+func TestSchedulingMisscheduledMetric(t *testing.T) {
+	cases := []struct {
+		name          string
+		placement     *clusterapiv1beta1.Placement
+		expectedCount float64
+	}{
+		{
+			name:          "placement becomes misconfigured",
+			placement:     testinghelpers.NewPlacement("misscheduled1", placementName).Build(),
+			expectedCount: 1,
+		},
+		{
+			name: "placement stays misconfigured",
+			placement: testinghelpers.NewPlacement("misscheduled2", placementName).
+				WithMisconfiguredCondition(metav1.ConditionTrue).Build(),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.placement)
+			clusterInformerFactory := newClusterInformerFactory(t, clusterClient, c.placement)
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 5*time.Minute)
+
+			ctrl := schedulingController{
+				kubeClient:              kubeClient,
+				clusterClient:           clusterClient,
+				clusterLister:           clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				clusterSetLister:        clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
+				clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
+				placementLister:         clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
+				placementDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+				configMapLister:         kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+				scheduler: &testScheduler{
+					result: &scheduleResult{},
+					status: framework.NewStatus("plugin", framework.Misconfigured, "invalid"),
+				},
+				eventsRecorder:  kevents.NewFakeRecorder(100),
+				metricsRecorder: metrics.NewScheduleMetrics(clock.RealClock{}),
+			}
+
+			_ = ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, c.placement.Namespace+"/"+c.placement.Name))
+
+			if count := misscheduledCount(t, c.placement.Namespace); count != c.expectedCount {
+				t.Errorf("expected misscheduled count %v, but got %v", c.expectedCount, count)
+			}
+		})
+	}
+}
+
+func misscheduledCount(t *testing.T, namespace string) float64 {
+	mfs, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != metrics.SchedulingSubsystem+"_"+metrics.PlacementMisscheduledKey {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "namespace" && label.GetValue() == namespace {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
 func TestGetValidManagedClusterSetBindings(t *testing.T) {
 	placementNamespace := "ns1"
 	cases := []struct {
@@ -1406,13 +1487,18 @@ func TestBind(t *testing.T) {
 
 			s := &testScheduler{}
 
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 5*time.Minute)
+
 			ctrl := schedulingController{
+				kubeClient:              kubeClient,
 				clusterClient:           clusterClient,
 				clusterLister:           clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
 				clusterSetLister:        clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
 				clusterSetBindingLister: clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
 				placementLister:         clusterInformerFactory.Cluster().V1beta1().Placements().Lister(),
 				placementDecisionLister: clusterInformerFactory.Cluster().V1beta1().PlacementDecisions().Lister(),
+				configMapLister:         kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
 				scheduler:               s,
 				eventsRecorder:          kevents.NewFakeRecorder(100),
 				metricsRecorder:         metrics.NewScheduleMetrics(clock.RealClock{}),
@@ -0,0 +1,225 @@
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// ScoreBreakdownAnnotation is the annotation on the placement to record the score breakdown of the selected
+	// clusters in a configmap named "<placement name>-score-breakdown" in the placement namespace. The value is the
+	// number of the top rejected clusters whose score breakdown is recorded as well, e.g. "0" means only the selected
+	// clusters are recorded.
+	ScoreBreakdownAnnotation = "cluster.open-cluster-management.io/experimental-score-breakdown"
+
+	// ScoreBreakdownLabel is the label on the score breakdown configmap, the value is the placement name.
+	ScoreBreakdownLabel = "cluster.open-cluster-management.io/score-breakdown-placement"
+
+	// ScoreBreakdownDataKey is the key of the score breakdown in the configmap data.
+	ScoreBreakdownDataKey = "breakdown.json"
+
+	scoreBreakdownSuffix = "-score-breakdown"
+
+	// maxNumOfRejectedClusters limits the number of the rejected clusters recorded in the score breakdown, so the
+	// configmap does not exceed the size limitation.
+	maxNumOfRejectedClusters = 100
+)
+
+// ScoreBreakdown records the score contributions of each prioritizer for the clusters of a placement.
+type ScoreBreakdown struct {
+	Clusters []ClusterScoreBreakdown `json:"clusters"`
+}
+
+// ClusterScoreBreakdown is the score breakdown of one cluster, the clusters are sorted in the same order as the
+// scheduler selects them.
+type ClusterScoreBreakdown struct {
+	ClusterName  string                      `json:"clusterName"`
+	Selected     bool                        `json:"selected"`
+	Score        int64                       `json:"score"`
+	Prioritizers []PrioritizerScoreBreakdown `json:"prioritizers"`
+}
+
+// PrioritizerScoreBreakdown is the score contribution of one prioritizer, the WeightedScore is added to the total
+// score of the cluster.
+type PrioritizerScoreBreakdown struct {
+	Name          string `json:"name"`
+	Weight        int32  `json:"weight"`
+	Score         int64  `json:"score"`
+	WeightedScore int64  `json:"weightedScore"`
+}
+
+// ScoreBreakdownConfigMapName returns the name of the score breakdown configmap of a placement.
+func ScoreBreakdownConfigMapName(placementName string) string {
+	return placementName + scoreBreakdownSuffix
+}
+
+// numOfRejectedClusters returns whether the score breakdown is enabled for the placement and how many top rejected
+// clusters are recorded.
+func numOfRejectedClusters(placement *clusterapiv1beta1.Placement) (int, bool, error) {
+	value, ok := placement.GetAnnotations()[ScoreBreakdownAnnotation]
+	if !ok {
+		return 0, false, nil
+	}
+	if len(value) == 0 {
+		return 0, true, nil
+	}
+
+	num, err := strconv.Atoi(value)
+	if err != nil || num < 0 {
+		return 0, true, fmt.Errorf("invalid value %q of annotation %s", value, ScoreBreakdownAnnotation)
+	}
+	if num > maxNumOfRejectedClusters {
+		num = maxNumOfRejectedClusters
+	}
+	return num, true, nil
+}
+
+// buildScoreBreakdown builds the score breakdown of the selected clusters and the top rejected clusters.
+func buildScoreBreakdown(scheduleResult ScheduleResult, numOfRejected int) *ScoreBreakdown {
+	prioritizerResults := append([]PrioritizerResult{}, scheduleResult.PrioritizerResults()...)
+	sort.SliceStable(prioritizerResults, func(i, j int) bool {
+		return prioritizerResults[i].Name < prioritizerResults[j].Name
+	})
+	scoreSum := scheduleResult.PrioritizerScores()
+
+	breakdown := &ScoreBreakdown{Clusters: []ClusterScoreBreakdown{}}
+	newClusterScoreBreakdown := func(clusterName string, selected bool) ClusterScoreBreakdown {
+		b := ClusterScoreBreakdown{
+			ClusterName:  clusterName,
+			Selected:     selected,
+			Score:        scoreSum[clusterName],
+			Prioritizers: []PrioritizerScoreBreakdown{},
+		}
+		for _, r := range prioritizerResults {
+			score := r.Scores[clusterName]
+			b.Prioritizers = append(b.Prioritizers, PrioritizerScoreBreakdown{
+				Name:          r.Name,
+				Weight:        r.Weight,
+				Score:         score,
+				WeightedScore: score * int64(r.Weight),
+			})
+		}
+		return b
+	}
+
+	selected := map[string]bool{}
+	for _, cluster := range scheduleResult.Decisions() {
+		selected[cluster.Name] = true
+		breakdown.Clusters = append(breakdown.Clusters, newClusterScoreBreakdown(cluster.Name, true))
+	}
+
+	// the feasible clusters which are not selected are rejected, sort them by score in the same way as the scheduler
+	var rejected []string
+	for name := range scoreSum {
+		if !selected[name] {
+			rejected = append(rejected, name)
+		}
+	}
+	sort.Slice(rejected, func(i, j int) bool {
+		if scoreSum[rejected[i]] == scoreSum[rejected[j]] {
+			return rejected[i] < rejected[j]
+		}
+		return scoreSum[rejected[i]] > scoreSum[rejected[j]]
+	})
+	if len(rejected) > numOfRejected {
+		rejected = rejected[:numOfRejected]
+	}
+	for _, name := range rejected {
+		breakdown.Clusters = append(breakdown.Clusters, newClusterScoreBreakdown(name, false))
+	}
+
+	return breakdown
+}
+
+// syncScoreBreakdown creates or updates the score breakdown configmap of the placement if it is enabled, otherwise
+// the configmap is deleted if it exists. The configmap is owned by the placement, so it is garbage collected once
+// the placement is deleted.
+func (c *schedulingController) syncScoreBreakdown(
+	ctx context.Context,
+	placement *clusterapiv1beta1.Placement,
+	scheduleResult ScheduleResult,
+) error {
+	name := ScoreBreakdownConfigMapName(placement.Name)
+	existing, err := c.configMapLister.ConfigMaps(placement.Namespace).Get(name)
+	switch {
+	case errors.IsNotFound(err):
+		existing = nil
+	case err != nil:
+		return err
+	}
+
+	// the invalid annotation value is not retried, only the selected clusters are recorded in this case
+	numOfRejected, enabled, err := numOfRejectedClusters(placement)
+	if err != nil {
+		c.eventsRecorder.Eventf(
+			placement, nil, corev1.EventTypeWarning,
+			"ScoreBreakdownInvalid", "ScoreBreakdownUpdate",
+			"Only the selected clusters are recorded in the score breakdown: %v", err)
+	}
+
+	if !enabled {
+		if existing == nil {
+			return nil
+		}
+		err := c.kubeClient.CoreV1().ConfigMaps(placement.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	data, err := json.Marshal(buildScoreBreakdown(scheduleResult, numOfRejected))
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: placement.Namespace,
+			Labels: map[string]string{
+				ScoreBreakdownLabel: placement.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(placement, clusterapiv1beta1.GroupVersion.WithKind("Placement")),
+			},
+		},
+		Data: map[string]string{
+			ScoreBreakdownDataKey: string(data),
+		},
+	}
+
+	if existing == nil {
+		_, err := c.kubeClient.CoreV1().ConfigMaps(placement.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			// the configmap is not created by the controller, do not override it
+			c.eventsRecorder.Eventf(
+				placement, nil, corev1.EventTypeWarning,
+				"ScoreBreakdownConflict", "ScoreBreakdownUpdate",
+				"ConfigMap %s already exists without label %s", name, ScoreBreakdownLabel)
+			return nil
+		}
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Data, configMap.Data) && reflect.DeepEqual(existing.Labels, configMap.Labels) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = configMap.Labels
+	updated.OwnerReferences = configMap.OwnerReferences
+	updated.Data = configMap.Data
+	_, err = c.kubeClient.CoreV1().ConfigMaps(placement.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
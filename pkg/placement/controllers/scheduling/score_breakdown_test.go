@@ -0,0 +1,196 @@
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	kevents "k8s.io/client-go/tools/events"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func newTestScheduleResult() *scheduleResult {
+	return &scheduleResult{
+		scheduledDecisions: []*clusterapiv1.ManagedCluster{
+			testinghelpers.NewManagedCluster("cluster1").Build(),
+		},
+		scoreRecords: []PrioritizerResult{
+			{Name: "Steady", Weight: 1, Scores: PrioritizerScore{"cluster1": 100, "cluster2": 0, "cluster3": 0}},
+			{Name: "Balance", Weight: 2, Scores: PrioritizerScore{"cluster1": 100, "cluster2": 100, "cluster3": -100}},
+		},
+		scoreSum: PrioritizerScore{"cluster1": 300, "cluster2": 200, "cluster3": -200},
+	}
+}
+
+func TestBuildScoreBreakdown(t *testing.T) {
+	cases := []struct {
+		name             string
+		numOfRejected    int
+		expectedClusters []string
+	}{
+		{
+			name:             "selected clusters only",
+			numOfRejected:    0,
+			expectedClusters: []string{"cluster1"},
+		},
+		{
+			name:             "top rejected clusters",
+			numOfRejected:    1,
+			expectedClusters: []string{"cluster1", "cluster2"},
+		},
+		{
+			name:             "all rejected clusters",
+			numOfRejected:    10,
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			breakdown := buildScoreBreakdown(newTestScheduleResult(), c.numOfRejected)
+
+			var clusters []string
+			for _, b := range breakdown.Clusters {
+				clusters = append(clusters, b.ClusterName)
+			}
+			if !reflect.DeepEqual(clusters, c.expectedClusters) {
+				t.Errorf("expected clusters %v, but got %v", c.expectedClusters, clusters)
+			}
+		})
+	}
+
+	breakdown := buildScoreBreakdown(newTestScheduleResult(), 1)
+	expected := ClusterScoreBreakdown{
+		ClusterName: "cluster2",
+		Selected:    false,
+		Score:       200,
+		Prioritizers: []PrioritizerScoreBreakdown{
+			{Name: "Balance", Weight: 2, Score: 100, WeightedScore: 200},
+			{Name: "Steady", Weight: 1, Score: 0, WeightedScore: 0},
+		},
+	}
+	if !reflect.DeepEqual(breakdown.Clusters[1], expected) {
+		t.Errorf("expected breakdown %v, but got %v", expected, breakdown.Clusters[1])
+	}
+}
+
+func TestSyncScoreBreakdown(t *testing.T) {
+	cases := []struct {
+		name            string
+		placement       *clusterapiv1beta1.Placement
+		existingObjs    []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:            "disabled",
+			placement:       testinghelpers.NewPlacement(placementNamespace, placementName).Build(),
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:      "disabled with existing configmap",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).Build(),
+			existingObjs: []runtime.Object{
+				newScoreBreakdownConfigMap(map[string]string{ScoreBreakdownDataKey: "{}"}),
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete")
+			},
+		},
+		{
+			name: "create configmap",
+			placement: testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, map[string]string{
+				ScoreBreakdownAnnotation: "1",
+			}).Build(),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create")
+				configMap := actions[0].(clienttesting.CreateActionImpl).Object.(*corev1.ConfigMap)
+				if configMap.Name != ScoreBreakdownConfigMapName(placementName) ||
+					configMap.Labels[ScoreBreakdownLabel] != placementName {
+					t.Errorf("unexpected configmap %v", configMap)
+				}
+				breakdown := &ScoreBreakdown{}
+				if err := json.Unmarshal([]byte(configMap.Data[ScoreBreakdownDataKey]), breakdown); err != nil {
+					t.Fatal(err)
+				}
+				if len(breakdown.Clusters) != 2 {
+					t.Errorf("unexpected breakdown %v", breakdown)
+				}
+			},
+		},
+		{
+			name: "update configmap",
+			placement: testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, map[string]string{
+				ScoreBreakdownAnnotation: "",
+			}).Build(),
+			existingObjs: []runtime.Object{
+				newScoreBreakdownConfigMap(map[string]string{ScoreBreakdownDataKey: "{}"}),
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "update")
+			},
+		},
+		{
+			name: "configmap not changed",
+			placement: testinghelpers.NewPlacementWithAnnotations(placementNamespace, placementName, map[string]string{
+				ScoreBreakdownAnnotation: "invalid",
+			}).Build(),
+			existingObjs: []runtime.Object{
+				newScoreBreakdownConfigMap(map[string]string{ScoreBreakdownDataKey: func() string {
+					data, _ := json.Marshal(buildScoreBreakdown(newTestScheduleResult(), 0))
+					return string(data)
+				}()}),
+			},
+			validateActions: testingcommon.AssertNoActions,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.existingObjs...)
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 5*time.Minute)
+			for _, obj := range c.existingObjs {
+				if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &schedulingController{
+				kubeClient:      kubeClient,
+				configMapLister: kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+				eventsRecorder:  kevents.NewFakeRecorder(100),
+			}
+
+			if err := ctrl.syncScoreBreakdown(context.TODO(), c.placement, newTestScheduleResult()); err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+
+			c.validateActions(t, kubeClient.Actions())
+		})
+	}
+}
+
+func newScoreBreakdownConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScoreBreakdownConfigMapName(placementName),
+			Namespace: placementNamespace,
+			Labels: map[string]string{
+				ScoreBreakdownLabel: placementName,
+			},
+		},
+		Data: data,
+	}
+}
@@ -0,0 +1,109 @@
+package scheduling
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+// SimulationResult is the result of simulating a placement, it contains the would-be decisions and the score
+// breakdown of each prioritizer.
+type SimulationResult struct {
+	FilterResults      []FilterResult           `json:"filterResults,omitempty"`
+	PrioritizerResults []PrioritizerResult      `json:"prioritizerResults,omitempty"`
+	PrioritizerScores  PrioritizerScore         `json:"prioritizerScores,omitempty"`
+	DecisionGroups     []SimulatedDecisionGroup `json:"decisionGroups,omitempty"`
+	NumOfUnscheduled   int                      `json:"numOfUnscheduled"`
+	Conditions         []metav1.Condition       `json:"conditions,omitempty"`
+}
+
+// SimulatedDecisionGroup is a would-be decision group of the simulated placement.
+type SimulatedDecisionGroup struct {
+	GroupName  string   `json:"groupName,omitempty"`
+	GroupIndex int32    `json:"groupIndex"`
+	Clusters   []string `json:"clusters"`
+}
+
+// Simulator evaluates a placement against the current clusters in the same way as the scheduling controller, but
+// never writes the placement decisions or the placement status. The placement does not need to exist.
+type Simulator struct {
+	controller *schedulingController
+}
+
+func NewSimulator(
+	scheduler Scheduler,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
+	clusterSetBindingInformer clusterinformerv1beta2.ManagedClusterSetBindingInformer,
+) *Simulator {
+	return &Simulator{
+		controller: &schedulingController{
+			clusterLister:           clusterInformer.Lister(),
+			clusterSetLister:        clusterSetInformer.Lister(),
+			clusterSetBindingLister: clusterSetBindingInformer.Lister(),
+			scheduler:               scheduler,
+		},
+	}
+}
+
+// Simulate returns the simulation result of the given placement, the scheduling failures are reflected by the
+// conditions of the result.
+func (s *Simulator) Simulate(ctx context.Context, placement *clusterapiv1beta1.Placement) (*SimulationResult, error) {
+	c := s.controller
+
+	bindings, err := c.getValidManagedClusterSetBindings(placement.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSetNames := c.getEligibleClusterSets(placement, bindings)
+
+	clusters, err := c.getAvailableClusters(clusterSetNames)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleResult, status := c.scheduler.Schedule(withDryRun(ctx), placement, clusters)
+
+	result := &SimulationResult{
+		FilterResults:      scheduleResult.FilterResults(),
+		PrioritizerResults: scheduleResult.PrioritizerResults(),
+		PrioritizerScores:  scheduleResult.PrioritizerScores(),
+		NumOfUnscheduled:   scheduleResult.NumOfUnscheduled(),
+	}
+
+	decisionGroups, groupStatus := c.generateDecisionGroups(placement, scheduleResult.Decisions())
+	if groupStatus.IsError() {
+		status = groupStatus
+	}
+	for index, group := range decisionGroups {
+		simulatedGroup := SimulatedDecisionGroup{
+			GroupName:  group.decisionGroupName,
+			GroupIndex: int32(index),
+			Clusters:   []string{},
+		}
+		for _, decision := range group.clusterDecisions {
+			simulatedGroup.Clusters = append(simulatedGroup.Clusters, decision.ClusterName)
+		}
+		result.DecisionGroups = append(result.DecisionGroups, simulatedGroup)
+	}
+
+	result.Conditions = []metav1.Condition{
+		newMisconfiguredCondition(status),
+		newSatisfiedCondition(
+			placement.Spec.ClusterSets,
+			clusterSetNames,
+			len(bindings),
+			len(clusters),
+			len(scheduleResult.Decisions()),
+			scheduleResult.NumOfUnscheduled(),
+			status,
+		),
+	}
+
+	return result, nil
+}
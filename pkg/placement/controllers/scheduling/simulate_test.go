@@ -0,0 +1,109 @@
+package scheduling
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestSimulate(t *testing.T) {
+	clusterSetName := "clusterSets"
+
+	cases := []struct {
+		name              string
+		placement         *clusterapiv1beta1.Placement
+		initObjs          []runtime.Object
+		expectedClusters  []string
+		expectedSatisfied metav1.ConditionStatus
+		expectedReason    string
+	}{
+		{
+			name:      "no bindings",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewClusterSet(clusterSetName).Build(),
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).Build(),
+			},
+			expectedClusters:  []string{},
+			expectedSatisfied: metav1.ConditionFalse,
+			expectedReason:    "NoManagedClusterSetBindings",
+		},
+		{
+			name:      "clusters selected",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).WithNOC(2).Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewClusterSet(clusterSetName).Build(),
+				testinghelpers.NewClusterSetBinding(placementNamespace, clusterSetName),
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).Build(),
+				testinghelpers.NewManagedCluster("cluster4").Build(),
+			},
+			expectedClusters:  []string{"cluster1", "cluster2"},
+			expectedSatisfied: metav1.ConditionTrue,
+			expectedReason:    "AllDecisionsScheduled",
+		},
+		{
+			name:      "not all decisions scheduled",
+			placement: testinghelpers.NewPlacement(placementNamespace, placementName).WithNOC(3).Build(),
+			initObjs: []runtime.Object{
+				testinghelpers.NewClusterSet(clusterSetName).Build(),
+				testinghelpers.NewClusterSetBinding(placementNamespace, clusterSetName),
+				testinghelpers.NewManagedCluster("cluster1").WithLabel(clusterapiv1beta2.ClusterSetLabel, clusterSetName).Build(),
+			},
+			expectedClusters:  []string{"cluster1"},
+			expectedSatisfied: metav1.ConditionFalse,
+			expectedReason:    "NotAllDecisionsScheduled",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.initObjs...)
+			clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, c.initObjs...)
+			scheduler := NewPluginScheduler(testinghelpers.NewFakePluginHandle(t, clusterClient, c.initObjs...))
+
+			simulator := NewSimulator(
+				scheduler,
+				clusterInformerFactory.Cluster().V1().ManagedClusters(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings(),
+			)
+
+			result, err := simulator.Simulate(context.TODO(), c.placement)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			clusters := []string{}
+			for _, group := range result.DecisionGroups {
+				clusters = append(clusters, group.Clusters...)
+			}
+			sort.Strings(clusters)
+			if !reflect.DeepEqual(clusters, c.expectedClusters) {
+				t.Errorf("expected clusters %v, but got %v", c.expectedClusters, clusters)
+			}
+
+			satisfied := meta.FindStatusCondition(result.Conditions, clusterapiv1beta1.PlacementConditionSatisfied)
+			if satisfied == nil || satisfied.Status != c.expectedSatisfied || satisfied.Reason != c.expectedReason {
+				t.Errorf("unexpected satisfied condition %v", satisfied)
+			}
+
+			// nothing is written by the simulation
+			if actions := clusterClient.Actions(); len(actions) != 0 {
+				t.Errorf("expected no actions, but got %v", actions)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package scheduling
+
+import (
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/helpers"
+)
+
+// spreadConstraint tracks the number of the selected clusters in each topology of a spread constraints term.
+type spreadConstraint struct {
+	term    clusterapiv1beta1.SpreadConstraintsTerm
+	maxSkew int
+	// counts is the number of the selected clusters of each topology, all the topologies of the candidate
+	// clusters are included, so the global minimum is able to be calculated.
+	counts map[string]int
+}
+
+func newSpreadConstraint(term clusterapiv1beta1.SpreadConstraintsTerm, clusters []*clusterapiv1.ManagedCluster) *spreadConstraint {
+	c := &spreadConstraint{
+		term:    term,
+		maxSkew: int(term.MaxSkew),
+		counts:  map[string]int{},
+	}
+	if c.maxSkew < 1 {
+		c.maxSkew = 1
+	}
+	for _, cluster := range clusters {
+		if topology, ok := c.topology(cluster); ok {
+			c.counts[topology] = 0
+		}
+	}
+	return c
+}
+
+func (c *spreadConstraint) doNotSchedule() bool {
+	return c.term.WhenUnsatisfiable == clusterapiv1beta1.DoNotSchedule
+}
+
+// topology returns the topology of the cluster, it returns false if the cluster does not have the topology key.
+func (c *spreadConstraint) topology(cluster *clusterapiv1.ManagedCluster) (string, bool) {
+	var topology string
+	var ok bool
+	switch c.term.TopologyKeyType {
+	case clusterapiv1beta1.TopologyKeyTypeClaim:
+		topology, ok = helpers.GetClusterClaims(cluster)[c.term.TopologyKey]
+	default:
+		topology, ok = cluster.Labels[c.term.TopologyKey]
+	}
+	return topology, ok
+}
+
+// satisfied returns whether the skew is still within the max skew after the cluster is selected. The clusters
+// without the topology key are not selected if the term is DoNotSchedule.
+func (c *spreadConstraint) satisfied(cluster *clusterapiv1.ManagedCluster) bool {
+	topology, ok := c.topology(cluster)
+	if !ok {
+		return !c.doNotSchedule()
+	}
+
+	globalMin := -1
+	for _, count := range c.counts {
+		if globalMin < 0 || count < globalMin {
+			globalMin = count
+		}
+	}
+
+	return c.counts[topology]+1-globalMin <= c.maxSkew
+}
+
+func (c *spreadConstraint) add(cluster *clusterapiv1.ManagedCluster) {
+	if topology, ok := c.topology(cluster); ok {
+		c.counts[topology]++
+	}
+}
+
+// selectClustersWithSpreadConstraints selects the clusters one by one by the score order, each time the cluster
+// with the highest score which satisfies all the spread constraints is selected. If no cluster is able to satisfy
+// all of them, the ScheduleAnyway terms are relaxed from the least important one, while the DoNotSchedule terms are
+// never relaxed, so the selection stops once no cluster satisfies the DoNotSchedule terms.
+func selectClustersWithSpreadConstraints(
+	terms []clusterapiv1beta1.SpreadConstraintsTerm,
+	clusters []*clusterapiv1.ManagedCluster,
+	numOfDecisions int,
+) []*clusterapiv1.ManagedCluster {
+	var doNotSchedule, scheduleAnyway []*spreadConstraint
+	for _, term := range terms {
+		c := newSpreadConstraint(term, clusters)
+		if c.doNotSchedule() {
+			doNotSchedule = append(doNotSchedule, c)
+		} else {
+			scheduleAnyway = append(scheduleAnyway, c)
+		}
+	}
+
+	candidates := append([]*clusterapiv1.ManagedCluster{}, clusters...)
+	selected := []*clusterapiv1.ManagedCluster{}
+	for len(selected) < numOfDecisions && len(candidates) > 0 {
+		index := -1
+		for numOfTerms := len(scheduleAnyway); numOfTerms >= 0 && index < 0; numOfTerms-- {
+			index = findCandidate(candidates, append(append([]*spreadConstraint{}, doNotSchedule...), scheduleAnyway[:numOfTerms]...))
+		}
+		if index < 0 {
+			break
+		}
+
+		cluster := candidates[index]
+		for _, c := range doNotSchedule {
+			c.add(cluster)
+		}
+		for _, c := range scheduleAnyway {
+			c.add(cluster)
+		}
+		selected = append(selected, cluster)
+		candidates = append(candidates[:index], candidates[index+1:]...)
+	}
+
+	return selected
+}
+
+// findCandidate returns the index of the first candidate which satisfies all the given spread constraints.
+func findCandidate(candidates []*clusterapiv1.ManagedCluster, constraints []*spreadConstraint) int {
+	for i, cluster := range candidates {
+		satisfied := true
+		for _, c := range constraints {
+			if !c.satisfied(cluster) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return i
+		}
+	}
+	return -1
+}
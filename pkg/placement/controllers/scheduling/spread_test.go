@@ -0,0 +1,89 @@
+package scheduling
+
+import (
+	"reflect"
+	"testing"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestSelectClustersWithSpreadConstraints(t *testing.T) {
+	// the clusters are sorted by score
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithLabel("region", "us").WithLabel("zone", "us-1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithLabel("region", "us").WithLabel("zone", "us-1").Build(),
+		testinghelpers.NewManagedCluster("cluster3").WithLabel("region", "us").WithLabel("zone", "us-2").Build(),
+		testinghelpers.NewManagedCluster("cluster4").WithLabel("region", "eu").WithLabel("zone", "eu-1").Build(),
+		testinghelpers.NewManagedCluster("cluster5").WithClaim("provider", "aws").Build(),
+	}
+
+	cases := []struct {
+		name             string
+		placement        *clusterapiv1beta1.Placement
+		expectedClusters []string
+	}{
+		{
+			name: "spread by region",
+			placement: testinghelpers.NewPlacement("test", "test").WithNOC(2).
+				WithSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1, clusterapiv1beta1.DoNotSchedule).Build(),
+			expectedClusters: []string{"cluster1", "cluster4"},
+		},
+		{
+			name: "do not schedule when max skew is not satisfied",
+			placement: testinghelpers.NewPlacement("test", "test").WithNOC(4).
+				WithSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1, clusterapiv1beta1.DoNotSchedule).Build(),
+			expectedClusters: []string{"cluster1", "cluster4", "cluster2"},
+		},
+		{
+			// the cluster without the topology key is preferred to the cluster breaking the max skew
+			name: "schedule anyway when max skew is not satisfied",
+			placement: testinghelpers.NewPlacement("test", "test").WithNOC(5).
+				WithSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 1, clusterapiv1beta1.ScheduleAnyway).Build(),
+			expectedClusters: []string{"cluster1", "cluster4", "cluster2", "cluster5", "cluster3"},
+		},
+		{
+			name: "larger max skew",
+			placement: testinghelpers.NewPlacement("test", "test").WithNOC(3).
+				WithSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 2, clusterapiv1beta1.DoNotSchedule).Build(),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster4"},
+		},
+		{
+			name: "multiple topology keys",
+			placement: testinghelpers.NewPlacement("test", "test").WithNOC(3).
+				WithSpreadConstraint("region", clusterapiv1beta1.TopologyKeyTypeLabel, 2, clusterapiv1beta1.DoNotSchedule).
+				WithSpreadConstraint("zone", clusterapiv1beta1.TopologyKeyTypeLabel, 1, clusterapiv1beta1.ScheduleAnyway).Build(),
+			expectedClusters: []string{"cluster1", "cluster3", "cluster4"},
+		},
+		{
+			name: "spread by claim",
+			placement: testinghelpers.NewPlacement("test", "test").
+				WithSpreadConstraint("provider", clusterapiv1beta1.TopologyKeyTypeClaim, 1, clusterapiv1beta1.DoNotSchedule).Build(),
+			expectedClusters: []string{"cluster5"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			noc, err := getNumberOfClusters(c.placement)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, cluster := range clusters {
+				names = append(names, cluster.Name)
+			}
+			selected := selectClusters(c.placement, clusters, noc.numOfDecisions(names, nil))
+
+			names = nil
+			for _, cluster := range selected {
+				names = append(names, cluster.Name)
+			}
+			if !reflect.DeepEqual(names, c.expectedClusters) {
+				t.Errorf("expected clusters %v, but got %v", c.expectedClusters, names)
+			}
+		})
+	}
+}
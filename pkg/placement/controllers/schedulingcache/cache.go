@@ -0,0 +1,507 @@
+package schedulingcache
+
+import (
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterinformerv1alpha1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1alpha1"
+	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
+	workinformerv1 "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	clustersdkv1beta2 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta2"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	workhelper "open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// SchedulingCache keeps the data the placements are scheduled with, and updates it incrementally with the events of
+// the ManagedClusters, the AddOnPlacementScores and the PlacementDecisions, so the scheduling latency does not grow with
+// the number of the clusters. It caches
+//  1. the claims and the resources of each cluster, which are converted once for each change of the cluster;
+//  2. the AddOnPlacementScores of each cluster;
+//  3. the members of each clusterset, which are updated per cluster once built;
+//  4. the placements selecting each cluster, which are updated with the PlacementDecisions;
+//  5. the resources requested on each cluster reported by the resource usage ManifestWorks, if they are added.
+//
+// The methods of a nil SchedulingCache fall back to read from the given objects, so the callers do not need to know
+// whether the cache is enabled.
+type SchedulingCache struct {
+	lock sync.RWMutex
+	// clusters is the snapshot of the clusters by name
+	clusters map[string]*clusterSnapshot
+	// scores is the AddOnPlacementScores by cluster namespace and name
+	scores map[string]map[string]*clusterapiv1alpha1.AddOnPlacementScore
+	// clusterSets is the members of the clustersets by name
+	clusterSets map[string]*clusterSetMembers
+	// decisions is the placement key and the selected clusters of the PlacementDecisions by namespace/name
+	decisions map[string]*decisionSnapshot
+	// placementsOnClusters is the number of the decisions of each placement selecting a cluster, by cluster name
+	// and placement key (namespace/name)
+	placementsOnClusters map[string]map[string]int
+	// workUsages is the resources requested on the clusters reported by the resource usage ManifestWorks, by cluster
+	// namespace and work name
+	workUsages map[string]map[string]map[clusterapiv1.ResourceName]float64
+
+	registrations []cache.ResourceEventHandlerRegistration
+}
+
+type clusterSnapshot struct {
+	cluster     *clusterapiv1.ManagedCluster
+	claims      map[string]string
+	allocatable map[clusterapiv1.ResourceName]float64
+	capacity    map[clusterapiv1.ResourceName]float64
+}
+
+type decisionSnapshot struct {
+	placementKey string
+	clusters     sets.Set[string]
+}
+
+type clusterSetMembers struct {
+	// resourceVersion is the resource version of the clusterset the members are built with, the members are
+	// rebuilt once the clusterset changes.
+	resourceVersion string
+	selector        labels.Selector
+	members         sets.Set[string]
+}
+
+// NewSchedulingCache returns a SchedulingCache filled by the informers of the ManagedClusters, the
+// AddOnPlacementScores and the PlacementDecisions.
+func NewSchedulingCache(
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	placementScoreInformer clusterinformerv1alpha1.AddOnPlacementScoreInformer,
+	decisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
+) (*SchedulingCache, error) {
+	c := newSchedulingCache()
+
+	clusterRegistration, err := clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if cluster, ok := obj.(*clusterapiv1.ManagedCluster); ok {
+				c.setCluster(cluster)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if cluster, ok := newObj.(*clusterapiv1.ManagedCluster); ok {
+				c.setCluster(cluster)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if cluster, ok := obj.(*clusterapiv1.ManagedCluster); ok {
+				c.deleteCluster(cluster.Name)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scoreRegistration, err := placementScoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if score, ok := obj.(*clusterapiv1alpha1.AddOnPlacementScore); ok {
+				c.setScore(score)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if score, ok := newObj.(*clusterapiv1alpha1.AddOnPlacementScore); ok {
+				c.setScore(score)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if score, ok := obj.(*clusterapiv1alpha1.AddOnPlacementScore); ok {
+				c.deleteScore(score.Namespace, score.Name)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decisionRegistration, err := decisionInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if decision, ok := obj.(*clusterapiv1beta1.PlacementDecision); ok {
+				c.setDecision(decision)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if decision, ok := newObj.(*clusterapiv1beta1.PlacementDecision); ok {
+				c.setDecision(decision)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if decision, ok := obj.(*clusterapiv1beta1.PlacementDecision); ok {
+				c.deleteDecision(decision.Namespace, decision.Name)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.registrations = []cache.ResourceEventHandlerRegistration{clusterRegistration, scoreRegistration, decisionRegistration}
+	return c, nil
+}
+
+// AddResourceUsageWorks fills the cache with the resources requested on the clusters reported by the status feedback
+// of the ManifestWorks of the informer, which should only watch the ManifestWorks labeled with the
+// ResourceUsageWorkLabel of the common helpers.
+func (c *SchedulingCache) AddResourceUsageWorks(workInformer workinformerv1.ManifestWorkInformer) error {
+	registration, err := workInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if work, ok := obj.(*workapiv1.ManifestWork); ok {
+				c.setWork(work)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if work, ok := newObj.(*workapiv1.ManifestWork); ok {
+				c.setWork(work)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if work, ok := obj.(*workapiv1.ManifestWork); ok {
+				c.deleteWork(work.Namespace, work.Name)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	c.registrations = append(c.registrations, registration)
+	return nil
+}
+
+func newSchedulingCache() *SchedulingCache {
+	return &SchedulingCache{
+		clusters:             map[string]*clusterSnapshot{},
+		scores:               map[string]map[string]*clusterapiv1alpha1.AddOnPlacementScore{},
+		clusterSets:          map[string]*clusterSetMembers{},
+		decisions:            map[string]*decisionSnapshot{},
+		placementsOnClusters: map[string]map[string]int{},
+		workUsages:           map[string]map[string]map[clusterapiv1.ResourceName]float64{},
+	}
+}
+
+// HasSynced returns true once the cache has handled the initial list of the informers.
+func (c *SchedulingCache) HasSynced() bool {
+	if c == nil {
+		return false
+	}
+	for _, registration := range c.registrations {
+		if !registration.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// ClustersInClusterSet returns the clusters belonging to the clusterset.
+func (c *SchedulingCache) ClustersInClusterSet(clusterSet *clusterapiv1beta2.ManagedClusterSet) ([]*clusterapiv1.ManagedCluster, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	members, ok := c.clusterSets[clusterSet.Name]
+	if !ok || members.resourceVersion != clusterSet.ResourceVersion {
+		selector, err := clustersdkv1beta2.BuildClusterSelector(clusterSet)
+		if err != nil {
+			return nil, err
+		}
+		members = &clusterSetMembers{
+			resourceVersion: clusterSet.ResourceVersion,
+			selector:        selector,
+			members:         sets.New[string](),
+		}
+		for name, snapshot := range c.clusters {
+			if selector.Matches(labels.Set(snapshot.cluster.Labels)) {
+				members.members.Insert(name)
+			}
+		}
+		c.clusterSets[clusterSet.Name] = members
+	}
+
+	clusters := make([]*clusterapiv1.ManagedCluster, 0, members.members.Len())
+	for name := range members.members {
+		clusters = append(clusters, c.clusters[name].cluster)
+	}
+	return clusters, nil
+}
+
+// ClusterClaims returns the claims of the cluster.
+func (c *SchedulingCache) ClusterClaims(cluster *clusterapiv1.ManagedCluster) map[string]string {
+	if snapshot := c.getSnapshot(cluster); snapshot != nil {
+		return snapshot.claims
+	}
+	return clusterClaims(cluster)
+}
+
+// ClusterResource returns the allocatable and the capacity of the resource of the cluster, an error is returned if
+// either of them is not reported by the cluster.
+func (c *SchedulingCache) ClusterResource(
+	cluster *clusterapiv1.ManagedCluster, resourceName clusterapiv1.ResourceName) (allocatable, capacity float64, err error) {
+	snapshot := c.getSnapshot(cluster)
+	if snapshot == nil {
+		snapshot = newClusterSnapshot(cluster)
+	}
+
+	allocatable, ok := snapshot.allocatable[resourceName]
+	if !ok {
+		return 0, 0, fmt.Errorf("no allocatable %s found in cluster %s", resourceName, cluster.Name)
+	}
+	capacity, ok = snapshot.capacity[resourceName]
+	if !ok {
+		return allocatable, 0, fmt.Errorf("no capacity %s found in cluster %s", resourceName, cluster.Name)
+	}
+	return allocatable, capacity, nil
+}
+
+// ClusterCapacity returns the capacity of the resource in the status of the cluster, which also carries the resource
+// usage reported by the registration agent. The requested resources not reported by the registration agent are read
+// from the resource usage ManifestWorks of the cluster.
+func (c *SchedulingCache) ClusterCapacity(cluster *clusterapiv1.ManagedCluster, resourceName clusterapiv1.ResourceName) (float64, error) {
+	snapshot := c.getSnapshot(cluster)
+	if snapshot == nil {
+		snapshot = newClusterSnapshot(cluster)
+	}
+
+	if capacity, ok := snapshot.capacity[resourceName]; ok {
+		return capacity, nil
+	}
+	if usage, ok := c.workUsage(cluster.Name, resourceName); ok {
+		return usage, nil
+	}
+	return 0, fmt.Errorf("no capacity %s found in cluster %s", resourceName, cluster.Name)
+}
+
+// AddOnPlacementScore returns the AddOnPlacementScore with the name in the cluster namespace.
+func (c *SchedulingCache) AddOnPlacementScore(namespace, name string) (*clusterapiv1alpha1.AddOnPlacementScore, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	score, ok := c.scores[namespace][name]
+	if !ok {
+		return nil, apierrors.NewNotFound(clusterapiv1alpha1.Resource("addonplacementscores"), name)
+	}
+	return score, nil
+}
+
+// PlacementsOnCluster returns the keys (namespace/name) of the placements whose decisions select the cluster.
+func (c *SchedulingCache) PlacementsOnCluster(clusterName string) sets.Set[string] {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	placements := sets.New[string]()
+	for key := range c.placementsOnClusters[clusterName] {
+		placements.Insert(key)
+	}
+	return placements
+}
+
+// getSnapshot returns the snapshot of the cluster, or nil if the cluster in the cache is not the same as the given
+// one.
+func (c *SchedulingCache) getSnapshot(cluster *clusterapiv1.ManagedCluster) *clusterSnapshot {
+	if c == nil {
+		return nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	snapshot, ok := c.clusters[cluster.Name]
+	if !ok || snapshot.cluster.ResourceVersion != cluster.ResourceVersion {
+		return nil
+	}
+	return snapshot
+}
+
+func (c *SchedulingCache) setCluster(cluster *clusterapiv1.ManagedCluster) {
+	snapshot := newClusterSnapshot(cluster)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.clusters[cluster.Name] = snapshot
+	for _, members := range c.clusterSets {
+		if members.selector.Matches(labels.Set(cluster.Labels)) {
+			members.members.Insert(cluster.Name)
+		} else {
+			members.members.Delete(cluster.Name)
+		}
+	}
+}
+
+func (c *SchedulingCache) deleteCluster(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.clusters, name)
+	for _, members := range c.clusterSets {
+		members.members.Delete(name)
+	}
+}
+
+func (c *SchedulingCache) setScore(score *clusterapiv1alpha1.AddOnPlacementScore) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.scores[score.Namespace]; !ok {
+		c.scores[score.Namespace] = map[string]*clusterapiv1alpha1.AddOnPlacementScore{}
+	}
+	c.scores[score.Namespace][score.Name] = score
+}
+
+func (c *SchedulingCache) deleteScore(namespace, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.scores[namespace], name)
+	if len(c.scores[namespace]) == 0 {
+		delete(c.scores, namespace)
+	}
+}
+
+func (c *SchedulingCache) setDecision(decision *clusterapiv1beta1.PlacementDecision) {
+	snapshot := &decisionSnapshot{clusters: sets.New[string]()}
+	if placementName := decision.Labels[clusterapiv1beta1.PlacementLabel]; len(placementName) != 0 {
+		snapshot.placementKey = fmt.Sprintf("%s/%s", decision.Namespace, placementName)
+		for _, d := range decision.Status.Decisions {
+			snapshot.clusters.Insert(d.ClusterName)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := fmt.Sprintf("%s/%s", decision.Namespace, decision.Name)
+	c.removeDecisionLocked(key)
+	c.decisions[key] = snapshot
+	for clusterName := range snapshot.clusters {
+		if _, ok := c.placementsOnClusters[clusterName]; !ok {
+			c.placementsOnClusters[clusterName] = map[string]int{}
+		}
+		c.placementsOnClusters[clusterName][snapshot.placementKey]++
+	}
+}
+
+func (c *SchedulingCache) deleteDecision(namespace, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.removeDecisionLocked(fmt.Sprintf("%s/%s", namespace, name))
+}
+
+func (c *SchedulingCache) removeDecisionLocked(key string) {
+	snapshot, ok := c.decisions[key]
+	if !ok {
+		return
+	}
+	delete(c.decisions, key)
+	for clusterName := range snapshot.clusters {
+		placements := c.placementsOnClusters[clusterName]
+		if placements[snapshot.placementKey]--; placements[snapshot.placementKey] <= 0 {
+			delete(placements, snapshot.placementKey)
+		}
+		if len(placements) == 0 {
+			delete(c.placementsOnClusters, clusterName)
+		}
+	}
+}
+
+// workUsage returns the sum of the resource requested on the cluster reported by the resource usage ManifestWorks,
+// false is returned if no ManifestWork reports it.
+func (c *SchedulingCache) workUsage(clusterName string, resourceName clusterapiv1.ResourceName) (float64, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var usage float64
+	var found bool
+	for _, workUsage := range c.workUsages[clusterName] {
+		if value, ok := workUsage[resourceName]; ok {
+			usage += value
+			found = true
+		}
+	}
+	return usage, found
+}
+
+func (c *SchedulingCache) setWork(work *workapiv1.ManifestWork) {
+	usage := workResourceUsage(work)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.workUsages[work.Namespace]; !ok {
+		c.workUsages[work.Namespace] = map[string]map[clusterapiv1.ResourceName]float64{}
+	}
+	c.workUsages[work.Namespace][work.Name] = usage
+}
+
+func (c *SchedulingCache) deleteWork(namespace, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.workUsages[namespace], name)
+	if len(c.workUsages[namespace]) == 0 {
+		delete(c.workUsages, namespace)
+	}
+}
+
+// workResourceUsage returns the resources requested on the cluster reported by the status feedback of the
+// ManifestWork. The values not converted by the feedback schema are ignored, since their units are unknown.
+func workResourceUsage(work *workapiv1.ManifestWork) map[clusterapiv1.ResourceName]float64 {
+	usage := map[clusterapiv1.ResourceName]float64{}
+	for _, manifest := range work.Status.ResourceStatus.Manifests {
+		if workhelper.GetFeedbackSchemaVersion(manifest.Conditions) != workhelper.FeedbackSchemaV1 {
+			continue
+		}
+		for _, value := range manifest.StatusFeedbacks.Values {
+			if value.Value.Type != workapiv1.Integer || value.Value.Integer == nil {
+				continue
+			}
+			switch resourceName := clusterapiv1.ResourceName(value.Name); resourceName {
+			case commonhelpers.ResourceCPURequested:
+				// the cpu is converted to millicores, while the capacity of the cluster is in cores
+				usage[resourceName] += float64(*value.Value.Integer) / 1000
+			case commonhelpers.ResourceMemoryRequested:
+				usage[resourceName] += float64(*value.Value.Integer)
+			}
+		}
+	}
+	return usage
+}
+
+func newClusterSnapshot(cluster *clusterapiv1.ManagedCluster) *clusterSnapshot {
+	snapshot := &clusterSnapshot{
+		cluster:     cluster,
+		claims:      clusterClaims(cluster),
+		allocatable: map[clusterapiv1.ResourceName]float64{},
+		capacity:    map[clusterapiv1.ResourceName]float64{},
+	}
+	for name, quantity := range cluster.Status.Allocatable {
+		snapshot.allocatable[name] = quantity.AsApproximateFloat64()
+	}
+	for name, quantity := range cluster.Status.Capacity {
+		snapshot.capacity[name] = quantity.AsApproximateFloat64()
+	}
+	return snapshot
+}
+
+// clusterClaims returns the claims of the cluster, the custom claims are ignored if they violate the cluster claim
+// policy of the hub.
+func clusterClaims(cluster *clusterapiv1.ManagedCluster) map[string]string {
+	return commonhelpers.GetClusterClaims(cluster)
+}
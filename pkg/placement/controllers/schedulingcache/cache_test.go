@@ -0,0 +1,339 @@
+package schedulingcache
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterapiv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	clustersdkv1beta2 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta2"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	workhelper "open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+func newCluster(name, resourceVersion string, labels map[string]string) *clusterapiv1.ManagedCluster {
+	return &clusterapiv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion, Labels: labels},
+	}
+}
+
+func newClusterSet(name, resourceVersion string) *clusterapiv1beta2.ManagedClusterSet {
+	return &clusterapiv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion},
+	}
+}
+
+func clusterNames(clusters []*clusterapiv1.ManagedCluster) []string {
+	names := []string{}
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestClustersInClusterSet(t *testing.T) {
+	c := newSchedulingCache()
+	c.setCluster(newCluster("cluster1", "1", map[string]string{clusterapiv1beta2.ClusterSetLabel: "set1"}))
+	c.setCluster(newCluster("cluster2", "1", map[string]string{clusterapiv1beta2.ClusterSetLabel: "set2"}))
+
+	assertClusters := func(clusterSet *clusterapiv1beta2.ManagedClusterSet, expected ...string) {
+		t.Helper()
+		clusters, err := c.ClustersInClusterSet(clusterSet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := clusterNames(clusters); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Errorf("expected clusters %v in clusterset %s, but got %v", expected, clusterSet.Name, actual)
+		}
+	}
+
+	set1 := newClusterSet("set1", "1")
+	assertClusters(set1, "cluster1")
+
+	// the members are updated with the cluster events
+	c.setCluster(newCluster("cluster2", "2", map[string]string{clusterapiv1beta2.ClusterSetLabel: "set1"}))
+	c.setCluster(newCluster("cluster3", "1", map[string]string{clusterapiv1beta2.ClusterSetLabel: "set1"}))
+	assertClusters(set1, "cluster1", "cluster2", "cluster3")
+	c.deleteCluster("cluster1")
+	c.setCluster(newCluster("cluster3", "2", map[string]string{}))
+	assertClusters(set1, "cluster2")
+
+	// the members are rebuilt once the clusterset changes
+	set1 = newClusterSet("set1", "2")
+	set1.Spec.ClusterSelector = clusterapiv1beta2.ManagedClusterSelector{
+		SelectorType:  clusterapiv1beta2.LabelSelector,
+		LabelSelector: &metav1.LabelSelector{},
+	}
+	assertClusters(set1, "cluster2", "cluster3")
+}
+
+func TestClusterSnapshot(t *testing.T) {
+	cluster := newCluster("cluster1", "1", nil)
+	cluster.Status.ClusterClaims = []clusterapiv1.ManagedClusterClaim{{Name: "region", Value: "us-east-1"}}
+	cluster.Status.Allocatable = clusterapiv1.ResourceList{clusterapiv1.ResourceCPU: resource.MustParse("2")}
+	cluster.Status.Capacity = clusterapiv1.ResourceList{clusterapiv1.ResourceCPU: resource.MustParse("4")}
+
+	c := newSchedulingCache()
+	c.setCluster(cluster)
+
+	updated := cluster.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Status.ClusterClaims[0].Value = "us-west-1"
+	updated.Status.Allocatable[clusterapiv1.ResourceCPU] = resource.MustParse("1")
+
+	cases := []struct {
+		name                string
+		cache               *SchedulingCache
+		cluster             *clusterapiv1.ManagedCluster
+		expectedRegion      string
+		expectedAllocatable float64
+	}{
+		{name: "cached", cache: c, cluster: cluster, expectedRegion: "us-east-1", expectedAllocatable: 2},
+		{name: "cache is stale", cache: c, cluster: updated, expectedRegion: "us-west-1", expectedAllocatable: 1},
+		{name: "cache is not enabled", cluster: cluster, expectedRegion: "us-east-1", expectedAllocatable: 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if region := tc.cache.ClusterClaims(tc.cluster)["region"]; region != tc.expectedRegion {
+				t.Errorf("expected region %s, but got %s", tc.expectedRegion, region)
+			}
+			allocatable, capacity, err := tc.cache.ClusterResource(tc.cluster, clusterapiv1.ResourceCPU)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allocatable != tc.expectedAllocatable || capacity != 4 {
+				t.Errorf("expected allocatable %v and capacity 4, but got %v and %v", tc.expectedAllocatable, allocatable, capacity)
+			}
+			if _, _, err := tc.cache.ClusterResource(tc.cluster, clusterapiv1.ResourceMemory); err == nil {
+				t.Errorf("expected error for the resource not reported")
+			}
+		})
+	}
+}
+
+func TestAddOnPlacementScore(t *testing.T) {
+	c := newSchedulingCache()
+	c.setScore(&clusterapiv1alpha1.AddOnPlacementScore{ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "demo"}})
+
+	if _, err := c.AddOnPlacementScore("cluster1", "demo"); err != nil {
+		t.Errorf("expected the score is cached, but got %v", err)
+	}
+	c.deleteScore("cluster1", "demo")
+	if _, err := c.AddOnPlacementScore("cluster1", "demo"); !errors.IsNotFound(err) {
+		t.Errorf("expected not found error, but got %v", err)
+	}
+}
+
+func BenchmarkClustersInClusterSet1000(b *testing.B) {
+	benchmarkClustersInClusterSet(b, 1000, true)
+}
+
+func BenchmarkClustersInClusterSet10000(b *testing.B) {
+	benchmarkClustersInClusterSet(b, 10000, true)
+}
+
+func BenchmarkListClustersInClusterSet1000(b *testing.B) {
+	benchmarkClustersInClusterSet(b, 1000, false)
+}
+
+func BenchmarkListClustersInClusterSet10000(b *testing.B) {
+	benchmarkClustersInClusterSet(b, 10000, false)
+}
+
+// benchmarkClustersInClusterSet compares getting the clusters of a clusterset from the scheduling cache with listing
+// them with the lister, a cluster is updated in each iteration to include the cost of the incremental updates.
+func benchmarkClustersInClusterSet(b *testing.B, num int, cached bool) {
+	c := newSchedulingCache()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for i := 0; i < num; i++ {
+		cluster := newCluster(fmt.Sprintf("cluster%d", i), "1", map[string]string{
+			clusterapiv1beta2.ClusterSetLabel: fmt.Sprintf("set%d", i%2),
+		})
+		cluster.Status.ClusterClaims = []clusterapiv1.ManagedClusterClaim{{Name: "region", Value: "us-east-1"}}
+		c.setCluster(cluster)
+		if err := indexer.Add(cluster); err != nil {
+			b.Fatal(err)
+		}
+	}
+	lister := clusterlisterv1.NewManagedClusterLister(indexer)
+	clusterSet := newClusterSet("set0", "1")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		updated := newCluster(fmt.Sprintf("cluster%d", i%num), fmt.Sprintf("%d", i+2), map[string]string{
+			clusterapiv1beta2.ClusterSetLabel: fmt.Sprintf("set%d", i%2),
+		})
+		var clusters []*clusterapiv1.ManagedCluster
+		var err error
+		if cached {
+			c.setCluster(updated)
+			clusters, err = c.ClustersInClusterSet(clusterSet)
+		} else {
+			err = indexer.Update(updated)
+			if err == nil {
+				clusters, err = clustersdkv1beta2.GetClustersFromClusterSet(clusterSet, lister)
+			}
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, cluster := range clusters {
+			_ = c.ClusterClaims(cluster)
+		}
+	}
+}
+
+func TestPlacementsOnCluster(t *testing.T) {
+	newDecision := func(name, placementName string, clusterNames ...string) *clusterapiv1beta1.PlacementDecision {
+		decision := &clusterapiv1beta1.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      name,
+				Labels:    map[string]string{clusterapiv1beta1.PlacementLabel: placementName},
+			},
+		}
+		for _, clusterName := range clusterNames {
+			decision.Status.Decisions = append(decision.Status.Decisions, clusterapiv1beta1.ClusterDecision{ClusterName: clusterName})
+		}
+		return decision
+	}
+
+	c := newSchedulingCache()
+	assertPlacements := func(clusterName string, expected ...string) {
+		t.Helper()
+		if actual := sets.List(c.PlacementsOnCluster(clusterName)); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Errorf("expected placements %v on cluster %s, but got %v", expected, clusterName, actual)
+		}
+	}
+
+	c.setDecision(newDecision("p1-decision-1", "p1", "cluster1", "cluster2"))
+	c.setDecision(newDecision("p1-decision-2", "p1", "cluster3"))
+	c.setDecision(newDecision("p2-decision-1", "p2", "cluster1"))
+	assertPlacements("cluster1", "ns1/p1", "ns1/p2")
+	assertPlacements("cluster3", "ns1/p1")
+
+	c.setDecision(newDecision("p1-decision-1", "p1", "cluster2"))
+	assertPlacements("cluster1", "ns1/p2")
+	assertPlacements("cluster2", "ns1/p1")
+
+	c.deleteDecision("ns1", "p2-decision-1")
+	c.deleteDecision("ns1", "p1-decision-2")
+	assertPlacements("cluster1")
+	assertPlacements("cluster3")
+	if len(c.placementsOnClusters) != 1 {
+		t.Errorf("expected the clusters without placements are pruned, but got %v", c.placementsOnClusters)
+	}
+}
+
+func newResourceUsageWork(namespace, name string, schemaApplied bool, values map[clusterapiv1.ResourceName]int64) *workapiv1.ManifestWork {
+	manifest := workapiv1.ManifestCondition{}
+	if schemaApplied {
+		manifest.Conditions = []metav1.Condition{workhelper.NewFeedbackSchemaAppliedCondition(workhelper.FeedbackSchemaV1)}
+	}
+	for resourceName, value := range values {
+		manifest.StatusFeedbacks.Values = append(manifest.StatusFeedbacks.Values, workapiv1.FeedbackValue{
+			Name:  string(resourceName),
+			Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(value)},
+		})
+	}
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: workapiv1.ManifestWorkStatus{
+			ResourceStatus: workapiv1.ManifestResourceStatus{Manifests: []workapiv1.ManifestCondition{manifest}},
+		},
+	}
+}
+
+func TestResourceUsageWorks(t *testing.T) {
+	c := newSchedulingCache()
+	c.setWork(newResourceUsageWork("cluster1", "quota1", true, map[clusterapiv1.ResourceName]int64{
+		commonhelpers.ResourceCPURequested:    500,
+		commonhelpers.ResourceMemoryRequested: 1024,
+	}))
+	c.setWork(newResourceUsageWork("cluster1", "quota2", true, map[clusterapiv1.ResourceName]int64{
+		commonhelpers.ResourceCPURequested: 1500,
+	}))
+	// the values not converted by the feedback schema are ignored
+	c.setWork(newResourceUsageWork("cluster2", "quota1", false, map[clusterapiv1.ResourceName]int64{
+		commonhelpers.ResourceCPURequested: 500,
+	}))
+	reported := newCluster("cluster3", "1", nil)
+	reported.Status.Capacity = clusterapiv1.ResourceList{commonhelpers.ResourceCPURequested: resource.MustParse("3")}
+	c.setCluster(reported)
+	c.setWork(newResourceUsageWork("cluster3", "quota1", true, map[clusterapiv1.ResourceName]int64{
+		commonhelpers.ResourceCPURequested: 500,
+	}))
+
+	cases := []struct {
+		name          string
+		cluster       *clusterapiv1.ManagedCluster
+		resourceName  clusterapiv1.ResourceName
+		expected      float64
+		expectedError bool
+	}{
+		{
+			name:         "cpu summed in cores",
+			cluster:      newCluster("cluster1", "1", nil),
+			resourceName: commonhelpers.ResourceCPURequested,
+			expected:     2,
+		},
+		{
+			name:         "memory",
+			cluster:      newCluster("cluster1", "1", nil),
+			resourceName: commonhelpers.ResourceMemoryRequested,
+			expected:     1024,
+		},
+		{
+			name:          "not converted",
+			cluster:       newCluster("cluster2", "1", nil),
+			resourceName:  commonhelpers.ResourceCPURequested,
+			expectedError: true,
+		},
+		{
+			name:         "reported by the registration agent",
+			cluster:      reported,
+			resourceName: commonhelpers.ResourceCPURequested,
+			expected:     3,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := c.ClusterCapacity(tc.cluster, tc.resourceName)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("expected error, but got %v", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tc.expected {
+				t.Errorf("expected %v, but got %v", tc.expected, value)
+			}
+		})
+	}
+
+	c.deleteWork("cluster1", "quota1")
+	if value, err := c.ClusterCapacity(newCluster("cluster1", "1", nil), commonhelpers.ResourceCPURequested); err != nil || value != 1.5 {
+		t.Errorf("expected 1.5 cores after the work is deleted, but got %v, %v", value, err)
+	}
+	c.deleteWork("cluster1", "quota2")
+	if _, err := c.ClusterCapacity(newCluster("cluster1", "1", nil), commonhelpers.ResourceCPURequested); err == nil {
+		t.Errorf("expected error after the works are deleted")
+	}
+}
@@ -2,6 +2,7 @@ package debugger
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -10,17 +11,25 @@ import (
 
 	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
+	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
 	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/scheduling"
 )
 
 const DebugPath = "/debug/placements/"
 
-// Debugger provides a debug http endpoint for scheduler
+// maxSimulationBodyBytes limits the size of the placement in a simulation request.
+const maxSimulationBodyBytes = 1024 * 1024
+
+// Debugger provides a debug http endpoint for scheduler. A GET request returns the schedule results of an existing
+// placement, and a POST request with a placement in the body simulates the placement without writing any placement
+// decision.
 type Debugger struct {
 	scheduler       scheduling.Scheduler
+	simulator       *scheduling.Simulator
 	clusterLister   clusterlisterv1.ManagedClusterLister
 	placementLister clusterlisterv1beta1.PlacementLister
 }
@@ -35,9 +44,12 @@ type DebugResult struct {
 func NewDebugger(
 	scheduler scheduling.Scheduler,
 	placementInformer clusterinformerv1beta1.PlacementInformer,
-	clusterInformer clusterinformerv1.ManagedClusterInformer) *Debugger {
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
+	clusterSetBindingInformer clusterinformerv1beta2.ManagedClusterSetBindingInformer) *Debugger {
 	return &Debugger{
 		scheduler:       scheduler,
+		simulator:       scheduling.NewSimulator(scheduler, clusterInformer, clusterSetInformer, clusterSetBindingInformer),
 		clusterLister:   clusterInformer.Lister(),
 		placementLister: placementInformer.Lister(),
 	}
@@ -50,6 +62,11 @@ func (d *Debugger) Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == http.MethodPost {
+		d.simulate(w, r, namespace, name)
+		return
+	}
+
 	placement, err := d.placementLister.Placements(namespace).Get(name)
 	if err != nil {
 		d.reportErr(w, err)
@@ -71,6 +88,28 @@ func (d *Debugger) Handler(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(resultByte)
 }
 
+// simulate evaluates the placement in the request body against the current clusters, the namespace and name of the
+// placement are overridden by the request path.
+func (d *Debugger) simulate(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	placement := &clusterapiv1beta1.Placement{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSimulationBodyBytes)).Decode(placement); err != nil {
+		d.reportErr(w, fmt.Errorf("failed to decode placement: %v", err))
+		return
+	}
+	placement.Namespace = namespace
+	placement.Name = name
+
+	result, err := d.simulator.Simulate(r.Context(), placement)
+	if err != nil {
+		d.reportErr(w, err)
+		return
+	}
+
+	resultByte, _ := json.Marshal(result)
+
+	_, _ = w.Write(resultByte)
+}
+
 func (d *Debugger) parsePath(path string) (string, string, error) {
 	metaNamespaceKey := strings.TrimPrefix(path, DebugPath)
 	return cache.SplitMetaNamespaceKey(metaNamespaceKey)
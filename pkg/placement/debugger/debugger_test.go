@@ -1,6 +1,7 @@
 package debugger
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -63,6 +64,10 @@ func (r *testResult) RequeueAfter() *time.Duration {
 	return nil
 }
 
+func (r *testResult) PreemptedPlacements() []string {
+	return nil
+}
+
 func TestDebugger(t *testing.T) {
 	placementNamespace := "test"
 
@@ -94,7 +99,9 @@ func TestDebugger(t *testing.T) {
 			clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, c.initObjs...)
 			s := &testScheduler{result: &testResult{filterResults: c.filterResults, prioritizeResults: c.prioritizeResults}}
 			debugger := NewDebugger(
-				s, clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters())
+				s, clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings())
 			server := httptest.NewServer(http.HandlerFunc(debugger.Handler))
 			res, err := http.Get(fmt.Sprintf("%s%s%s", server.URL, DebugPath, c.key))
 
@@ -125,3 +132,46 @@ func TestDebugger(t *testing.T) {
 		})
 	}
 }
+
+func TestDebuggerSimulate(t *testing.T) {
+	initObjs := []runtime.Object{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+	}
+	filterResults := []scheduling.FilterResult{{Name: "filter1", FilteredClusters: []string{"cluster1"}}}
+
+	clusterClient := clusterfake.NewSimpleClientset(initObjs...)
+	clusterInformerFactory := testinghelpers.NewClusterInformerFactory(clusterClient, initObjs...)
+	s := &testScheduler{result: &testResult{filterResults: filterResults}}
+	debugger := NewDebugger(
+		s, clusterInformerFactory.Cluster().V1beta1().Placements(), clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets(),
+		clusterInformerFactory.Cluster().V1beta2().ManagedClusterSetBindings())
+	server := httptest.NewServer(http.HandlerFunc(debugger.Handler))
+	defer server.Close()
+
+	// the simulated placement does not exist
+	body, err := json.Marshal(testinghelpers.NewPlacement("test", "test").WithNOC(1).Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(fmt.Sprintf("%s%s%s", server.URL, DebugPath, "test/simulated"), "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Expect no error but get %v", err)
+	}
+	defer res.Body.Close()
+
+	result := &scheduling.SimulationResult{}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		t.Fatalf("Unexpected error unmarshaling reulst: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.FilterResults, filterResults) {
+		t.Errorf("Expect filter result to be: %v. but got: %v", filterResults, result.FilterResults)
+	}
+	if len(result.Conditions) != 2 {
+		t.Errorf("Expect misconfigured and satisfied conditions, but got: %v", result.Conditions)
+	}
+	if actions := clusterClient.Actions(); len(actions) != 0 {
+		t.Errorf("Expect no actions, but got %v", actions)
+	}
+}
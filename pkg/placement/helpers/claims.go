@@ -0,0 +1,182 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+const (
+	// ClaimSelectorOpGt selects the clusters whose claim value is greater than the value of the requirement.
+	ClaimSelectorOpGt metav1.LabelSelectorOperator = "Gt"
+	// ClaimSelectorOpLt selects the clusters whose claim value is less than the value of the requirement.
+	ClaimSelectorOpLt metav1.LabelSelectorOperator = "Lt"
+)
+
+// claimSelector matches the cluster claims with the requirements, the requirements are ANDed. Unlike the label
+// selector, the claim values are not required to be valid label values.
+type claimSelector struct {
+	requirements []claimRequirement
+}
+
+type claimRequirement struct {
+	key      string
+	operator metav1.LabelSelectorOperator
+	values   sets.Set[string]
+	bound    *claimBound
+}
+
+// claimBound is the value of a Gt or Lt requirement. If the value is an integer, the claim values are compared as
+// integers, otherwise the value must be a version like "1.28" or "v1.28.3" and the claim values are compared as
+// semantic versions, with only the components given in the requirement, e.g. "Gt 1.27" selects the claim value
+// "v1.28.0" but not "v1.27.5". The pre-release and build metadata of the versions are ignored.
+type claimBound struct {
+	number  *int64
+	version []int64
+}
+
+// convertClaimSelector converts ClusterClaimSelector to claimSelector
+func convertClaimSelector(clusterClaimSelector *clusterapiv1beta1.ClusterClaimSelector) (*claimSelector, error) {
+	selector := &claimSelector{}
+	for _, expr := range clusterClaimSelector.MatchExpressions {
+		if errs := validation.IsQualifiedName(expr.Key); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid claim key %q: %s", expr.Key, strings.Join(errs, "; "))
+		}
+
+		requirement := claimRequirement{
+			key:      expr.Key,
+			operator: expr.Operator,
+			values:   sets.New[string](expr.Values...),
+		}
+		switch expr.Operator {
+		case metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn:
+			if len(expr.Values) == 0 {
+				return nil, fmt.Errorf("values must be non-empty for operator %q of claim %q", expr.Operator, expr.Key)
+			}
+		case metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist:
+			if len(expr.Values) != 0 {
+				return nil, fmt.Errorf("values must be empty for operator %q of claim %q", expr.Operator, expr.Key)
+			}
+		case ClaimSelectorOpGt, ClaimSelectorOpLt:
+			if len(expr.Values) != 1 {
+				return nil, fmt.Errorf("exactly one value is required for operator %q of claim %q", expr.Operator, expr.Key)
+			}
+			bound, err := parseClaimBound(expr.Values[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of operator %q of claim %q: %v", expr.Operator, expr.Key, err)
+			}
+			requirement.bound = bound
+		default:
+			return nil, fmt.Errorf("%q is not a valid claim selector operator", expr.Operator)
+		}
+		selector.requirements = append(selector.requirements, requirement)
+	}
+
+	return selector, nil
+}
+
+// Matches returns true if the cluster claims match all the requirements.
+func (s *claimSelector) Matches(clusterclaims map[string]string) bool {
+	for _, r := range s.requirements {
+		if !r.matches(clusterclaims) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *claimRequirement) matches(clusterclaims map[string]string) bool {
+	value, ok := clusterclaims[r.key]
+	switch r.operator {
+	case metav1.LabelSelectorOpIn:
+		return ok && r.values.Has(value)
+	case metav1.LabelSelectorOpNotIn:
+		return !ok || !r.values.Has(value)
+	case metav1.LabelSelectorOpExists:
+		return ok
+	case metav1.LabelSelectorOpDoesNotExist:
+		return !ok
+	case ClaimSelectorOpGt:
+		result, comparable := r.bound.compare(value)
+		return ok && comparable && result > 0
+	case ClaimSelectorOpLt:
+		result, comparable := r.bound.compare(value)
+		return ok && comparable && result < 0
+	}
+	return false
+}
+
+func parseClaimBound(value string) (*claimBound, error) {
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return &claimBound{number: &number}, nil
+	}
+	version, err := parseVersion(value)
+	if err != nil {
+		return nil, err
+	}
+	return &claimBound{version: version}, nil
+}
+
+// compare returns an integer comparing the claim value with the bound, it returns false if the claim value is not
+// comparable with the bound.
+func (b *claimBound) compare(value string) (int, bool) {
+	if b.number != nil {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case number > *b.number:
+			return 1, true
+		case number < *b.number:
+			return -1, true
+		}
+		return 0, true
+	}
+
+	version, err := parseVersion(value)
+	if err != nil {
+		return 0, false
+	}
+	for i := range b.version {
+		var component int64
+		if i < len(version) {
+			component = version[i]
+		}
+		switch {
+		case component > b.version[i]:
+			return 1, true
+		case component < b.version[i]:
+			return -1, true
+		}
+	}
+	return 0, true
+}
+
+// parseVersion parses a version like "1", "1.28", "v1.28.3" or "v1.28.3-rc.1+k3s1" into its numeric components.
+func parseVersion(value string) ([]int64, error) {
+	v := strings.TrimPrefix(strings.TrimPrefix(value, "v"), "V")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("%q is neither an integer nor a version", value)
+	}
+	var version []int64
+	for _, part := range parts {
+		component, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || component < 0 {
+			return nil, fmt.Errorf("%q is neither an integer nor a version", value)
+		}
+		version = append(version, component)
+	}
+	return version, nil
+}
@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+)
+
+func TestClaimSelectorMatches(t *testing.T) {
+	cases := []struct {
+		name          string
+		requirement   metav1.LabelSelectorRequirement
+		clusterclaims map[string]string
+		expectedMatch bool
+	}{
+		{
+			name:          "in with a value which is not a valid label value",
+			requirement:   metav1.LabelSelectorRequirement{Key: "version", Operator: metav1.LabelSelectorOpIn, Values: []string{"v1.28.3+k3s1"}},
+			clusterclaims: map[string]string{"version": "v1.28.3+k3s1"},
+			expectedMatch: true,
+		},
+		{
+			name:          "not in without the claim",
+			requirement:   metav1.LabelSelectorRequirement{Key: "cloud", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"Amazon"}},
+			clusterclaims: map[string]string{},
+			expectedMatch: true,
+		},
+		{
+			name:          "exists",
+			requirement:   metav1.LabelSelectorRequirement{Key: "cloud", Operator: metav1.LabelSelectorOpExists},
+			clusterclaims: map[string]string{"cloud": "Amazon"},
+			expectedMatch: true,
+		},
+		{
+			name:          "does not exist",
+			requirement:   metav1.LabelSelectorRequirement{Key: "cloud", Operator: metav1.LabelSelectorOpDoesNotExist},
+			clusterclaims: map[string]string{"cloud": "Amazon"},
+			expectedMatch: false,
+		},
+		{
+			name:          "numeric greater than",
+			requirement:   metav1.LabelSelectorRequirement{Key: "nodes", Operator: ClaimSelectorOpGt, Values: []string{"9"}},
+			clusterclaims: map[string]string{"nodes": "10"},
+			expectedMatch: true,
+		},
+		{
+			name:          "numeric less than",
+			requirement:   metav1.LabelSelectorRequirement{Key: "nodes", Operator: ClaimSelectorOpLt, Values: []string{"9"}},
+			clusterclaims: map[string]string{"nodes": "10"},
+			expectedMatch: false,
+		},
+		{
+			name:          "numeric with a claim value which is not an integer",
+			requirement:   metav1.LabelSelectorRequirement{Key: "nodes", Operator: ClaimSelectorOpGt, Values: []string{"9"}},
+			clusterclaims: map[string]string{"nodes": "many"},
+			expectedMatch: false,
+		},
+		{
+			name:          "version greater than",
+			requirement:   metav1.LabelSelectorRequirement{Key: "version", Operator: ClaimSelectorOpGt, Values: []string{"1.27"}},
+			clusterclaims: map[string]string{"version": "v1.28.3+k3s1"},
+			expectedMatch: true,
+		},
+		{
+			name:          "version is compared with the components of the requirement",
+			requirement:   metav1.LabelSelectorRequirement{Key: "version", Operator: ClaimSelectorOpGt, Values: []string{"1.27"}},
+			clusterclaims: map[string]string{"version": "v1.27.5"},
+			expectedMatch: false,
+		},
+		{
+			name:          "version less than",
+			requirement:   metav1.LabelSelectorRequirement{Key: "version", Operator: ClaimSelectorOpLt, Values: []string{"v1.9.10"}},
+			clusterclaims: map[string]string{"version": "1.9.2"},
+			expectedMatch: true,
+		},
+		{
+			name:          "greater than without the claim",
+			requirement:   metav1.LabelSelectorRequirement{Key: "version", Operator: ClaimSelectorOpGt, Values: []string{"1.27"}},
+			clusterclaims: map[string]string{},
+			expectedMatch: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			selector, err := convertClaimSelector(&clusterapiv1beta1.ClusterClaimSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{c.requirement},
+			})
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if result := selector.Matches(c.clusterclaims); result != c.expectedMatch {
+				t.Errorf("expected match to be %v but get : %v", c.expectedMatch, result)
+			}
+		})
+	}
+}
+
+func TestConvertClaimSelectorInvalid(t *testing.T) {
+	cases := []struct {
+		name        string
+		requirement metav1.LabelSelectorRequirement
+	}{
+		{
+			name:        "invalid key",
+			requirement: metav1.LabelSelectorRequirement{Key: "-cloud", Operator: metav1.LabelSelectorOpExists},
+		},
+		{
+			name:        "unknown operator",
+			requirement: metav1.LabelSelectorRequirement{Key: "cloud", Operator: "Like", Values: []string{"Amazon"}},
+		},
+		{
+			name:        "in without values",
+			requirement: metav1.LabelSelectorRequirement{Key: "cloud", Operator: metav1.LabelSelectorOpIn},
+		},
+		{
+			name:        "exists with values",
+			requirement: metav1.LabelSelectorRequirement{Key: "cloud", Operator: metav1.LabelSelectorOpExists, Values: []string{"Amazon"}},
+		},
+		{
+			name:        "greater than with multiple values",
+			requirement: metav1.LabelSelectorRequirement{Key: "nodes", Operator: ClaimSelectorOpGt, Values: []string{"1", "2"}},
+		},
+		{
+			name:        "less than with invalid version",
+			requirement: metav1.LabelSelectorRequirement{Key: "version", Operator: ClaimSelectorOpLt, Values: []string{"1.2.3.4"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := convertClaimSelector(&clusterapiv1beta1.ClusterClaimSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{c.requirement},
+			})
+			if err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+		})
+	}
+}
@@ -6,11 +6,13 @@ import (
 
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 )
 
 type ClusterSelector struct {
 	labelSelector labels.Selector
-	claimSelector labels.Selector
+	claimSelector *claimSelector
 }
 
 func NewClusterSelector(selector clusterapiv1beta1.ClusterSelector) (*ClusterSelector, error) {
@@ -36,7 +38,7 @@ func (c *ClusterSelector) Matches(clusterlabels, clusterclaims map[string]string
 		return false
 	}
 	// match with claim selector
-	if ok := c.claimSelector.Matches(labels.Set(clusterclaims)); !ok {
+	if ok := c.claimSelector.Matches(clusterclaims); !ok {
 		return false
 	}
 	return true
@@ -52,23 +54,8 @@ func convertLabelSelector(labelSelector *metav1.LabelSelector) (labels.Selector,
 	return selector, nil
 }
 
-// convertClaimSelector converts ClusterClaimSelector to labels.Selector
-func convertClaimSelector(clusterClaimSelector *clusterapiv1beta1.ClusterClaimSelector) (labels.Selector, error) {
-	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
-		MatchExpressions: clusterClaimSelector.MatchExpressions,
-	})
-	if err != nil {
-		return labels.Nothing(), err
-	}
-
-	return selector, nil
-}
-
-// GetClusterClaims returns a map containing cluster claims from the status of cluster
+// GetClusterClaims returns a map containing cluster claims from the status of cluster, the custom claims are ignored
+// if they violate the cluster claim policy of the hub.
 func GetClusterClaims(cluster *clusterapiv1.ManagedCluster) map[string]string {
-	claims := map[string]string{}
-	for _, claim := range cluster.Status.ClusterClaims {
-		claims[claim.Name] = claim.Value
-	}
-	return claims
+	return commonhelpers.GetClusterClaims(cluster)
 }
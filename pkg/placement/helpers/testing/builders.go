@@ -96,6 +96,18 @@ func (b *PlacementBuilder) WithScoreCoordinateAddOn(resourceName, scoreName stri
 	return b
 }
 
+func (b *PlacementBuilder) WithSpreadConstraint(topologyKey string, topologyKeyType clusterapiv1beta1.TopologyKeyType,
+	maxSkew int32, whenUnsatisfiable clusterapiv1beta1.UnsatisfiableMaxSkewAction) *PlacementBuilder {
+	b.placement.Spec.SpreadPolicy.SpreadConstraints = append(b.placement.Spec.SpreadPolicy.SpreadConstraints,
+		clusterapiv1beta1.SpreadConstraintsTerm{
+			TopologyKey:       topologyKey,
+			TopologyKeyType:   topologyKeyType,
+			MaxSkew:           maxSkew,
+			WhenUnsatisfiable: whenUnsatisfiable,
+		})
+	return b
+}
+
 func (b *PlacementBuilder) WithClusterSets(clusterSets ...string) *PlacementBuilder {
 	b.placement.Spec.ClusterSets = clusterSets
 	return b
@@ -260,6 +272,14 @@ func (b *ManagedClusterBuilder) WithLabel(name, value string) *ManagedClusterBui
 	return b
 }
 
+func (b *ManagedClusterBuilder) WithAnnotation(name, value string) *ManagedClusterBuilder {
+	if b.cluster.Annotations == nil {
+		b.cluster.Annotations = map[string]string{}
+	}
+	b.cluster.Annotations[name] = value
+	return b
+}
+
 func (b *ManagedClusterBuilder) WithClaim(name, value string) *ManagedClusterBuilder {
 	claimMap := map[string]string{}
 	for _, claim := range b.cluster.Status.ClusterClaims {
@@ -14,6 +14,7 @@ import (
 	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 )
 
 type FakePluginHandle struct {
@@ -21,6 +22,7 @@ type FakePluginHandle struct {
 	placementDecisionLister clusterlisterv1beta1.PlacementDecisionLister
 	scoreLister             clusterlisterv1alpha1.AddOnPlacementScoreLister
 	clusterLister           clusterlisterv1.ManagedClusterLister
+	placementLister         clusterlisterv1beta1.PlacementLister
 	client                  clusterclient.Interface
 	metricsRecorder         *metrics.ScheduleMetrics
 }
@@ -35,12 +37,18 @@ func (f *FakePluginHandle) ScoreLister() clusterlisterv1alpha1.AddOnPlacementSco
 func (f *FakePluginHandle) ClusterLister() clusterlisterv1.ManagedClusterLister {
 	return f.clusterLister
 }
+func (f *FakePluginHandle) PlacementLister() clusterlisterv1beta1.PlacementLister {
+	return f.placementLister
+}
 func (f *FakePluginHandle) ClusterClient() clusterclient.Interface {
 	return f.client
 }
 func (f *FakePluginHandle) MetricsRecorder() *metrics.ScheduleMetrics {
 	return f.metricsRecorder
 }
+func (f *FakePluginHandle) SchedulingCache() *schedulingcache.SchedulingCache {
+	return nil
+}
 
 func NewFakePluginHandle(
 	t *testing.T, client *clusterfake.Clientset, objects ...runtime.Object) *FakePluginHandle {
@@ -51,6 +59,7 @@ func NewFakePluginHandle(
 		placementDecisionLister: informers.Cluster().V1beta1().PlacementDecisions().Lister(),
 		scoreLister:             informers.Cluster().V1alpha1().AddOnPlacementScores().Lister(),
 		clusterLister:           informers.Cluster().V1().ManagedClusters().Lister(),
+		placementLister:         informers.Cluster().V1beta1().Placements().Lister(),
 		metricsRecorder:         metrics.NewScheduleMetrics(clock.RealClock{}),
 	}
 }
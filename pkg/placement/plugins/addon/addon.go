@@ -3,14 +3,17 @@ package addon
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 	"open-cluster-management.io/ocm/pkg/placement/plugins"
 )
 
@@ -19,8 +22,30 @@ const (
 	description    = `
 	Customize prioritizer get cluster scores from AddOnPlacementScores with sepcific
 	resource name and score name. The clusters which doesn't have corresponding
-	AddOnPlacementScores resource or has expired score is given score 0.
+	AddOnPlacementScores resource is given score 0, and the expired score is handled
+	with the stale score policy of the placement, it is given score 0 by default.
 	`
+
+	// StaleScorePolicyAnnotation is the annotation on the placement to set how the expired scores in the
+	// AddOnPlacementScores, i.e. past their validUntil, are handled. It is one of Ignore, Decay and LastValue, and
+	// the default is Ignore.
+	StaleScorePolicyAnnotation = "cluster.open-cluster-management.io/experimental-stale-score-policy"
+
+	// StaleScoreDecayPeriodAnnotation is the annotation on the placement to set the duration, e.g. 30m, in which the
+	// expired scores decay linearly to 0 with the Decay policy. The default is 10m.
+	StaleScoreDecayPeriodAnnotation = "cluster.open-cluster-management.io/experimental-stale-score-decay-period"
+
+	// StaleScorePolicyIgnore gives the expired scores 0.
+	StaleScorePolicyIgnore = "Ignore"
+	// StaleScorePolicyDecay decays the expired scores linearly to 0 in the decay period after they expire.
+	StaleScorePolicyDecay = "Decay"
+	// StaleScorePolicyLastValue keeps using the last values of the expired scores.
+	StaleScorePolicyLastValue = "LastValue"
+
+	defaultStaleScoreDecayPeriod = 10 * time.Minute
+	// staleScoreDecaySteps is the number of the times the placement is scheduled again in the decay period, so the
+	// decayed scores are refreshed without the changes of the AddOnPlacementScores.
+	staleScoreDecaySteps = 10
 )
 
 var _ plugins.Prioritizer = &AddOn{}
@@ -31,6 +56,8 @@ type AddOn struct {
 	prioritizerName string
 	resourceName    string
 	scoreName       string
+	// requeueTime is the time to schedule the placement again to refresh the decaying scores, it is set by Score.
+	requeueTime *time.Time
 }
 
 type AddOnBuilder struct {
@@ -74,38 +101,55 @@ func (c *AddOn) Score(ctx context.Context, placement *clusterapiv1beta1.Placemen
 	expiredScores := ""
 	status := framework.NewStatus(c.Name(), framework.Success, "")
 
+	policy, decayPeriod, err := getStaleScorePolicy(placement)
+	if err != nil {
+		return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(c.Name(), framework.Misconfigured, err.Error())
+	}
+
+	now := AddOnClock.Now()
+	c.requeueTime = nil
+	schedulingCache := c.handle.SchedulingCache()
 	for _, cluster := range clusters {
 		namespace := cluster.Name
 		// default score is 0
 		scores[cluster.Name] = 0
 
 		// get AddOnPlacementScores CR with resourceName
-		addOnScores, err := c.handle.ScoreLister().AddOnPlacementScores(namespace).Get(c.resourceName)
+		addOnScores, err := c.getAddOnPlacementScore(schedulingCache, namespace)
 		if err != nil {
 			klog.FromContext(ctx).Info("Failed to get AddOnPlacementScores", "error", err)
 			continue
 		}
 
-		// check score valid time
-		if (addOnScores.Status.ValidUntil != nil) && AddOnClock.Now().After(addOnScores.Status.ValidUntil.Time) {
-			expiredScores = fmt.Sprintf("%s %s/%s", expiredScores, namespace, c.resourceName)
-			continue
-		}
-
 		// get AddOnPlacementScores score with scoreName
+		var score int64
 		for _, v := range addOnScores.Status.Scores {
 			if v.Name == c.scoreName {
-				scores[cluster.Name] = int64(v.Value)
+				score = int64(v.Value)
 			}
 		}
+
+		// check score valid time
+		if (addOnScores.Status.ValidUntil == nil) || !now.After(addOnScores.Status.ValidUntil.Time) {
+			scores[cluster.Name] = score
+			continue
+		}
+
+		expiredScores = fmt.Sprintf("%s %s/%s", expiredScores, namespace, c.resourceName)
+		switch policy {
+		case StaleScorePolicyLastValue:
+			scores[cluster.Name] = score
+		case StaleScorePolicyDecay:
+			scores[cluster.Name] = c.decayScore(score, addOnScores.Status.ValidUntil.Time, decayPeriod, now)
+		}
 	}
 
 	if len(expiredScores) > 0 {
-		status = framework.NewStatus(
-			c.Name(),
-			framework.Warning,
-			fmt.Sprintf("AddOnPlacementScores%s expired", expiredScores),
-		)
+		message := fmt.Sprintf("AddOnPlacementScores%s expired", expiredScores)
+		if policy != StaleScorePolicyIgnore {
+			message = fmt.Sprintf("%s, handled with the %s policy", message, policy)
+		}
+		status = framework.NewStatus(c.Name(), framework.Warning, message)
 	}
 
 	return plugins.PluginScoreResult{
@@ -113,6 +157,60 @@ func (c *AddOn) Score(ctx context.Context, placement *clusterapiv1beta1.Placemen
 	}, status
 }
 
+// decayScore returns the score decayed linearly from its value at validUntil to 0 at the end of the decay period,
+// the requeue time is set to refresh the score if it is still decaying.
+func (c *AddOn) decayScore(score int64, validUntil time.Time, decayPeriod time.Duration, now time.Time) int64 {
+	elapsed := now.Sub(validUntil)
+	if elapsed >= decayPeriod {
+		return 0
+	}
+
+	requeueTime := now.Add(decayPeriod / staleScoreDecaySteps)
+	if end := validUntil.Add(decayPeriod); requeueTime.After(end) {
+		requeueTime = end
+	}
+	if c.requeueTime == nil || requeueTime.Before(*c.requeueTime) {
+		c.requeueTime = &requeueTime
+	}
+	return score * int64(decayPeriod-elapsed) / int64(decayPeriod)
+}
+
+// getStaleScorePolicy returns the stale score policy and the decay period set in the annotations of the placement.
+func getStaleScorePolicy(placement *clusterapiv1beta1.Placement) (string, time.Duration, error) {
+	annotations := placement.GetAnnotations()
+
+	policy := annotations[StaleScorePolicyAnnotation]
+	switch policy {
+	case "":
+		policy = StaleScorePolicyIgnore
+	case StaleScorePolicyIgnore, StaleScorePolicyDecay, StaleScorePolicyLastValue:
+	default:
+		return "", 0, fmt.Errorf("invalid stale score policy %q, it must be one of %s, %s and %s",
+			policy, StaleScorePolicyIgnore, StaleScorePolicyDecay, StaleScorePolicyLastValue)
+	}
+
+	decayPeriod := defaultStaleScoreDecayPeriod
+	if value := annotations[StaleScoreDecayPeriodAnnotation]; len(value) > 0 {
+		period, err := time.ParseDuration(value)
+		if err != nil || period <= 0 {
+			return "", 0, fmt.Errorf("invalid stale score decay period %q, it must be a positive duration", value)
+		}
+		decayPeriod = period
+	}
+	return policy, decayPeriod, nil
+}
+
+// getAddOnPlacementScore reads the AddOnPlacementScore from the scheduling cache if it is enabled.
+func (c *AddOn) getAddOnPlacementScore(
+	schedulingCache *schedulingcache.SchedulingCache, namespace string) (*clusterapiv1alpha1.AddOnPlacementScore, error) {
+	if schedulingCache != nil {
+		return schedulingCache.AddOnPlacementScore(namespace, c.resourceName)
+	}
+	return c.handle.ScoreLister().AddOnPlacementScores(namespace).Get(c.resourceName)
+}
+
 func (c *AddOn) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
-	return plugins.PluginRequeueResult{}, framework.NewStatus(c.Name(), framework.Success, "")
+	return plugins.PluginRequeueResult{
+		RequeueTime: c.requeueTime,
+	}, framework.NewStatus(c.Name(), framework.Success, "")
 }
@@ -108,3 +108,97 @@ func TestScoreClusterWithAddOn(t *testing.T) {
 		})
 	}
 }
+
+func TestScoreClusterWithStaleScorePolicy(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+	}
+	existingAddOnScores := []runtime.Object{
+		testinghelpers.NewAddOnPlacementScore("cluster1", "test").WithScore("score1", 30).WithValidUntil(expiredTime).Build(),
+		testinghelpers.NewAddOnPlacementScore("cluster2", "test").WithScore("score1", -40).WithValidUntil(expiredTime).Build(),
+	}
+	decayRequeueTime := fakeTime.Add(6 * time.Second)
+
+	cases := []struct {
+		name                string
+		annotations         map[string]string
+		expectedScores      map[string]int64
+		expectedRequeueTime *time.Time
+		expectedErr         string
+	}{
+		{
+			name:           "ignore by default",
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0},
+			expectedErr:    "AddOnPlacementScores cluster1/test cluster2/test expired",
+		},
+		{
+			name:           "use the last values",
+			annotations:    map[string]string{StaleScorePolicyAnnotation: StaleScorePolicyLastValue},
+			expectedScores: map[string]int64{"cluster1": 30, "cluster2": -40},
+			expectedErr:    "AddOnPlacementScores cluster1/test cluster2/test expired, handled with the LastValue policy",
+		},
+		{
+			name: "decay the scores",
+			annotations: map[string]string{
+				StaleScorePolicyAnnotation:      StaleScorePolicyDecay,
+				StaleScoreDecayPeriodAnnotation: "1m",
+			},
+			expectedScores:      map[string]int64{"cluster1": 15, "cluster2": -20},
+			expectedRequeueTime: &decayRequeueTime,
+			expectedErr:         "AddOnPlacementScores cluster1/test cluster2/test expired, handled with the Decay policy",
+		},
+		{
+			name: "scores decayed to 0",
+			annotations: map[string]string{
+				StaleScorePolicyAnnotation:      StaleScorePolicyDecay,
+				StaleScoreDecayPeriodAnnotation: "20s",
+			},
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0},
+			expectedErr:    "AddOnPlacementScores cluster1/test cluster2/test expired, handled with the Decay policy",
+		},
+		{
+			name:           "invalid policy",
+			annotations:    map[string]string{StaleScorePolicyAnnotation: "Unknown"},
+			expectedScores: map[string]int64{},
+			expectedErr:    `invalid stale score policy "Unknown", it must be one of Ignore, Decay and LastValue`,
+		},
+		{
+			name: "invalid decay period",
+			annotations: map[string]string{
+				StaleScorePolicyAnnotation:      StaleScorePolicyDecay,
+				StaleScoreDecayPeriodAnnotation: "-1m",
+			},
+			expectedScores: map[string]int64{},
+			expectedErr:    `invalid stale score decay period "-1m", it must be a positive duration`,
+		},
+	}
+
+	AddOnClock = testingclock.NewFakeClock(fakeTime)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addon := &AddOn{
+				handle:          testinghelpers.NewFakePluginHandle(t, nil, existingAddOnScores...),
+				prioritizerName: "AddOn/test/score1",
+				resourceName:    "test",
+				scoreName:       "score1",
+			}
+			placement := testinghelpers.NewPlacementWithAnnotations("test", "test", c.annotations).Build()
+
+			scoreResult, status := addon.Score(context.TODO(), placement, clusters)
+			if err := status.AsError(); err == nil || err.Error() != c.expectedErr {
+				t.Errorf("expect err %s but get %v", c.expectedErr, err)
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+
+			requeueResult, _ := addon.RequeueAfter(context.TODO(), placement)
+			requeueTime := requeueResult.RequeueTime
+			if (requeueTime == nil) != (c.expectedRequeueTime == nil) ||
+				(requeueTime != nil && !requeueTime.Equal(*c.expectedRequeueTime)) {
+				t.Errorf("Expect requeue time %v, but got %v", c.expectedRequeueTime, requeueTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package external
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type ProviderType string
+
+const (
+	// ProviderTypeHTTP is the score provider that is called with a POST request, the request and response bodies
+	// are the JSON encoded ScoreRequest and ScoreResponse.
+	ProviderTypeHTTP ProviderType = "HTTP"
+
+	// ProviderTypeGRPC is the score provider that is called with the unary gRPC method ScoreMethod, the request and
+	// response messages are the ScoreRequest and ScoreResponse in the google.protobuf.Struct format.
+	ProviderTypeGRPC ProviderType = "GRPC"
+)
+
+type FailurePolicyType string
+
+const (
+	// Fail means the placement fails to be scheduled if the score provider is unavailable.
+	Fail FailurePolicyType = "Fail"
+
+	// Ignore means all the clusters are given a zero score if the score provider is unavailable.
+	Ignore FailurePolicyType = "Ignore"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// ProvidersConfig is the configuration of the external score providers of the placement controller.
+type ProvidersConfig struct {
+	Providers []ProviderConfig `json:"providers"`
+}
+
+// ProviderConfig is the configuration of one external score provider.
+type ProviderConfig struct {
+	// Name is the name of the score provider, a placement refers to the provider with the BuiltIn prioritizer named
+	// "External/<Name>".
+	Name string `json:"name"`
+
+	// Type is the type of the score provider, HTTP or GRPC.
+	Type ProviderType `json:"type"`
+
+	// URL is the address of the score provider. It is a http(s) URL for the HTTP provider, and a host:port address
+	// for the GRPC provider.
+	URL string `json:"url"`
+
+	// CAFile is the CA bundle to verify the score provider, the connection is insecure if it is empty.
+	CAFile string `json:"caFile,omitempty"`
+
+	// CertFile and KeyFile are the client certificate and key to connect to the score provider.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	// Timeout is the timeout of calling the score provider, 10s by default.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// Weight is the default weight of the score provider. If it is not zero, the provider is enabled for all the
+	// placements in the Additive prioritizer policy mode, otherwise it is only enabled for the placements that
+	// configure it explicitly.
+	Weight int32 `json:"weight,omitempty"`
+
+	// FailurePolicy defines how to handle the failure of calling the score provider, Fail or Ignore, Fail by
+	// default.
+	FailurePolicy FailurePolicyType `json:"failurePolicy,omitempty"`
+}
+
+// LoadProvidersConfig loads the providers config from the given file and sets the default values.
+func LoadProvidersConfig(configFile string) (*ProvidersConfig, error) {
+	data, err := os.ReadFile(path.Clean(configFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read score providers config file %s: %v", configFile, err)
+	}
+
+	config := &ProvidersConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal score providers config file %s: %v", configFile, err)
+	}
+
+	names := sets.New[string]()
+	for i := range config.Providers {
+		provider := &config.Providers[i]
+		if err := validateProviderConfig(provider); err != nil {
+			return nil, err
+		}
+		if names.Has(provider.Name) {
+			return nil, fmt.Errorf("duplicated score provider %q", provider.Name)
+		}
+		names.Insert(provider.Name)
+
+		if provider.Timeout.Duration == 0 {
+			provider.Timeout.Duration = defaultTimeout
+		}
+		if len(provider.FailurePolicy) == 0 {
+			provider.FailurePolicy = Fail
+		}
+	}
+
+	return config, nil
+}
+
+func validateProviderConfig(provider *ProviderConfig) error {
+	if len(provider.Name) == 0 {
+		return fmt.Errorf("the name of score provider is required")
+	}
+	if len(provider.URL) == 0 {
+		return fmt.Errorf("the url of score provider %q is required", provider.Name)
+	}
+	if provider.Type != ProviderTypeHTTP && provider.Type != ProviderTypeGRPC {
+		return fmt.Errorf("unsupported type %q of score provider %q", provider.Type, provider.Name)
+	}
+	if provider.FailurePolicy != "" && provider.FailurePolicy != Fail && provider.FailurePolicy != Ignore {
+		return fmt.Errorf("unsupported failure policy %q of score provider %q", provider.FailurePolicy, provider.Name)
+	}
+	if (len(provider.CertFile) == 0) != (len(provider.KeyFile) == 0) {
+		return fmt.Errorf("both certFile and keyFile of score provider %q are required", provider.Name)
+	}
+	if provider.Weight < -10 || provider.Weight > 10 {
+		return fmt.Errorf("the weight of score provider %q must be between -10 and 10", provider.Name)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package external
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestLoadProvidersConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "score-providers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cases := []struct {
+		name        string
+		config      string
+		expectedErr bool
+		validate    func(t *testing.T, config *ProvidersConfig)
+	}{
+		{
+			name: "defaults",
+			config: `
+providers:
+- name: latency
+  type: HTTP
+  url: http://localhost:8080/score
+  weight: 2
+- name: cost
+  type: GRPC
+  url: localhost:9090
+  timeout: 3s
+  failurePolicy: Ignore
+`,
+			validate: func(t *testing.T, config *ProvidersConfig) {
+				if len(config.Providers) != 2 {
+					t.Fatalf("unexpected providers %v", config.Providers)
+				}
+				latency, cost := config.Providers[0], config.Providers[1]
+				if latency.Timeout.Duration != defaultTimeout || latency.FailurePolicy != Fail || latency.Weight != 2 {
+					t.Errorf("unexpected provider %v", latency)
+				}
+				if cost.Timeout.Duration != 3*time.Second || cost.FailurePolicy != Ignore || cost.Weight != 0 {
+					t.Errorf("unexpected provider %v", cost)
+				}
+			},
+		},
+		{
+			name: "duplicated providers",
+			config: `
+providers:
+- name: latency
+  type: HTTP
+  url: http://localhost:8080/score
+- name: latency
+  type: GRPC
+  url: localhost:9090
+`,
+			expectedErr: true,
+		},
+		{
+			name: "unsupported type",
+			config: `
+providers:
+- name: latency
+  type: Unix
+  url: /var/run/score.sock
+`,
+			expectedErr: true,
+		},
+		{
+			name: "missing key file",
+			config: `
+providers:
+- name: latency
+  type: HTTP
+  url: https://localhost:8080/score
+  certFile: /etc/tls/tls.crt
+`,
+			expectedErr: true,
+		},
+		{
+			name: "invalid weight",
+			config: `
+providers:
+- name: latency
+  type: HTTP
+  url: https://localhost:8080/score
+  weight: 11
+`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			configFile := path.Join(tempDir, "config.yaml")
+			if err := os.WriteFile(configFile, []byte(c.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadProvidersConfig(configFile)
+			if c.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, but failed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			c.validate(t, config)
+		})
+	}
+}
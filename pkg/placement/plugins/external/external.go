@@ -0,0 +1,169 @@
+package external
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// PrioritizerPrefix is the prefix of the BuiltIn prioritizer name to refer to an external score provider in
+	// placements, e.g. "External/latency".
+	PrioritizerPrefix = "External/"
+
+	description = `
+	External prioritizer calls an out-of-tree score provider with the placement and the candidate clusters, and
+	gives each cluster the score returned by the provider. The scores out of the range between -100 and 100 are
+	truncated.
+	`
+)
+
+// ScoreRequest is the request sent to the score providers.
+type ScoreRequest struct {
+	Placement *clusterapiv1beta1.Placement   `json:"placement"`
+	Clusters  []*clusterapiv1.ManagedCluster `json:"clusters"`
+}
+
+// ScoreResponse is the response returned by the score providers, the scores are keyed by the cluster names, the
+// clusters not in the response are given a zero score.
+type ScoreResponse struct {
+	Scores map[string]int64 `json:"scores"`
+}
+
+// ScoreProvider is the client of an out-of-tree score provider.
+type ScoreProvider interface {
+	Score(ctx context.Context, request *ScoreRequest) (*ScoreResponse, error)
+}
+
+var _ plugins.Prioritizer = &External{}
+
+type External struct {
+	config   ProviderConfig
+	provider ScoreProvider
+}
+
+// New builds an External prioritizer with the given provider config.
+func New(config ProviderConfig) (*External, error) {
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider ScoreProvider
+	switch config.Type {
+	case ProviderTypeHTTP:
+		provider = newHTTPScoreProvider(config.URL, tlsConfig)
+	case ProviderTypeGRPC:
+		provider, err = newGRPCScoreProvider(config.URL, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %q of score provider %q", config.Type, config.Name)
+	}
+
+	return &External{
+		config:   config,
+		provider: provider,
+	}, nil
+}
+
+func (e *External) Name() string {
+	return PrioritizerPrefix + e.config.Name
+}
+
+func (e *External) Description() string {
+	return description
+}
+
+// Weight returns the default weight of the score provider.
+func (e *External) Weight() int32 {
+	return e.config.Weight
+}
+
+func (e *External) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		scores[cluster.Name] = 0
+	}
+
+	if len(clusters) == 0 {
+		return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(e.Name(), framework.Success, "")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.config.Timeout.Duration)
+	defer cancel()
+
+	resp, err := e.provider.Score(ctx, &ScoreRequest{Placement: placement, Clusters: clusters})
+	if err != nil {
+		msg := fmt.Sprintf("failed to call score provider %s: %v", e.config.Name, err)
+		if e.config.FailurePolicy == Ignore {
+			return plugins.PluginScoreResult{Scores: scores}, framework.NewStatus(e.Name(), framework.Warning, msg)
+		}
+		return plugins.PluginScoreResult{}, framework.NewStatus(e.Name(), framework.Error, msg)
+	}
+
+	for name, score := range resp.Scores {
+		if _, ok := scores[name]; !ok {
+			continue
+		}
+		switch {
+		case score > plugins.MaxClusterScore:
+			score = plugins.MaxClusterScore
+		case score < plugins.MinClusterScore:
+			score = plugins.MinClusterScore
+		}
+		scores[name] = score
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, framework.NewStatus(e.Name(), framework.Success, "")
+}
+
+func (e *External) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(e.Name(), framework.Success, "")
+}
+
+// newTLSConfig returns the tls config to connect to the score provider, it returns nil if the CA is not specified.
+func newTLSConfig(config ProviderConfig) (*tls.Config, error) {
+	if len(config.CAFile) == 0 {
+		return nil, nil
+	}
+
+	caData, err := os.ReadFile(path.Clean(config.CAFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file of score provider %q: %v", config.Name, err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("invalid CA file of score provider %q", config.Name)
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    certPool,
+		MinVersion: tls.VersionTLS12,
+	}
+	fips.ConfigureTLS(tlsConfig)
+
+	if len(config.CertFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate of score provider %q: %v", config.Name, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
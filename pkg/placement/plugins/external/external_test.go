@@ -0,0 +1,199 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+type fakeScoreProvider struct {
+	scores map[string]int64
+	err    error
+}
+
+func (f *fakeScoreProvider) Score(_ context.Context, _ *ScoreRequest) (*ScoreResponse, error) {
+	return &ScoreResponse{Scores: f.scores}, f.err
+}
+
+func TestScoreClusterWithExternal(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+
+	cases := []struct {
+		name           string
+		failurePolicy  FailurePolicyType
+		provider       *fakeScoreProvider
+		expectedScores map[string]int64
+		expectedCode   framework.Code
+	}{
+		{
+			name:           "scores are truncated",
+			provider:       &fakeScoreProvider{scores: map[string]int64{"cluster1": 200, "cluster2": 50, "cluster3": -200}},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 50, "cluster3": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:           "unknown and missing clusters",
+			provider:       &fakeScoreProvider{scores: map[string]int64{"cluster1": 20, "cluster4": 50}},
+			expectedScores: map[string]int64{"cluster1": 20, "cluster2": 0, "cluster3": 0},
+			expectedCode:   framework.Success,
+		},
+		{
+			name:          "failed with fail policy",
+			failurePolicy: Fail,
+			provider:      &fakeScoreProvider{err: fmt.Errorf("unavailable")},
+			expectedCode:  framework.Error,
+		},
+		{
+			name:           "failed with ignore policy",
+			failurePolicy:  Ignore,
+			provider:       &fakeScoreProvider{err: fmt.Errorf("unavailable")},
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0},
+			expectedCode:   framework.Warning,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := &External{
+				config: ProviderConfig{
+					Name:          "test",
+					Timeout:       metav1.Duration{Duration: time.Second},
+					FailurePolicy: c.failurePolicy,
+				},
+				provider: c.provider,
+			}
+
+			if e.Name() != "External/test" {
+				t.Errorf("unexpected name %s", e.Name())
+			}
+
+			scoreResult, status := e.Score(context.TODO(), testinghelpers.NewPlacement("test", "test").Build(), clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v", c.expectedCode, status.Code())
+			}
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
+
+func TestHTTPScoreProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &ScoreRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		scores := map[string]int64{}
+		for i, cluster := range request.Clusters {
+			scores[cluster.Name] = int64(i * 10)
+		}
+		_ = json.NewEncoder(w).Encode(&ScoreResponse{Scores: scores})
+	}))
+	defer server.Close()
+
+	e, err := New(ProviderConfig{
+		Name:    "test",
+		Type:    ProviderTypeHTTP,
+		URL:     server.URL,
+		Timeout: metav1.Duration{Duration: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	scoreResult, status := e.Score(context.TODO(), testinghelpers.NewPlacement("test", "test").Build(),
+		[]*clusterapiv1.ManagedCluster{
+			testinghelpers.NewManagedCluster("cluster1").Build(),
+			testinghelpers.NewManagedCluster("cluster2").Build(),
+		})
+	if err := status.AsError(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedScores := map[string]int64{"cluster1": 0, "cluster2": 10}
+	if !apiequality.Semantic.DeepEqual(scoreResult.Scores, expectedScores) {
+		t.Errorf("Expect score %v, but got %v", expectedScores, scoreResult.Scores)
+	}
+}
+
+func TestGRPCScoreProvider(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "io.open_cluster_management.placement.v1alpha1.ScoreProvider",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Score",
+				Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error,
+					_ grpc.UnaryServerInterceptor) (interface{}, error) {
+					in := &structpb.Struct{}
+					if err := dec(in); err != nil {
+						return nil, err
+					}
+
+					scores := map[string]interface{}{}
+					for i, cluster := range in.Fields["clusters"].GetListValue().GetValues() {
+						name := cluster.GetStructValue().Fields["metadata"].GetStructValue().Fields["name"].GetStringValue()
+						scores[name] = i * 20
+					}
+					return structpb.NewStruct(map[string]interface{}{"scores": scores})
+				},
+			},
+		},
+	}, struct{}{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	e, err := New(ProviderConfig{
+		Name:    "test",
+		Type:    ProviderTypeGRPC,
+		URL:     lis.Addr().String(),
+		Timeout: metav1.Duration{Duration: 5 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	scoreResult, status := e.Score(context.TODO(), testinghelpers.NewPlacement("test", "test").Build(),
+		[]*clusterapiv1.ManagedCluster{
+			testinghelpers.NewManagedCluster("cluster1").Build(),
+			testinghelpers.NewManagedCluster("cluster2").Build(),
+		})
+	if err := status.AsError(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedScores := map[string]int64{"cluster1": 0, "cluster2": 20}
+	if !apiequality.Semantic.DeepEqual(scoreResult.Scores, expectedScores) {
+		t.Errorf("Expect score %v, but got %v", expectedScores, scoreResult.Scores)
+	}
+}
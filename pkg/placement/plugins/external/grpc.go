@@ -0,0 +1,75 @@
+package external
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ScoreMethod is the unary gRPC method implemented by the GRPC score providers. Both the request and the response
+// messages are google.protobuf.Struct, whose fields are the same as the JSON encoded ScoreRequest and ScoreResponse,
+// so the providers are able to be implemented without sharing a generated protocol.
+const ScoreMethod = "/io.open_cluster_management.placement.v1alpha1.ScoreProvider/Score"
+
+// grpcScoreProvider calls the score provider with the ScoreMethod.
+type grpcScoreProvider struct {
+	conn *grpc.ClientConn
+}
+
+func newGRPCScoreProvider(address string, tlsConfig *tls.Config) (*grpcScoreProvider, error) {
+	transportCredentials := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+
+	// the connection is established lazily once the provider is called
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grpc connection to %s: %v", address, err)
+	}
+
+	return &grpcScoreProvider{conn: conn}, nil
+}
+
+func (p *grpcScoreProvider) Score(ctx context.Context, request *ScoreRequest) (*ScoreResponse, error) {
+	in, err := toStruct(request)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &structpb.Struct{}
+	if err := p.conn.Invoke(ctx, ScoreMethod, in, out); err != nil {
+		return nil, err
+	}
+
+	data, err := out.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	scoreResponse := &ScoreResponse{}
+	if err := json.Unmarshal(data, scoreResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return scoreResponse, nil
+}
+
+func toStruct(obj interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,67 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxResponseBytes limits the size of the response read from the score providers.
+const maxResponseBytes = 10 * 1024 * 1024
+
+// httpScoreProvider calls the score provider with a POST request.
+type httpScoreProvider struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPScoreProvider(url string, tlsConfig *tls.Config) *httpScoreProvider {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &httpScoreProvider{
+		url:    url,
+		client: &http.Client{Transport: transport},
+	}
+}
+
+func (p *httpScoreProvider) Score(ctx context.Context, request *ScoreRequest) (*ScoreResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(data))
+	}
+
+	scoreResponse := &ScoreResponse{}
+	if err := json.Unmarshal(data, scoreResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return scoreResponse, nil
+}
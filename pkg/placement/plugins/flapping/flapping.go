@@ -0,0 +1,203 @@
+package flapping
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	description = `
+	Flapping prioritizer penalizes the clusters whose availability changes frequently. Each transition of the
+	ManagedClusterConditionAvailable condition from or to True adds a penalty which decays by half every half
+	life, the clusters without recent transitions are given the score 0 and the clusters flapping are given
+	negative scores down to the lowest score.
+	`
+
+	// transitionPenalty is the penalty of a transition just happened.
+	transitionPenalty = 25.0
+
+	// penaltyHalfLife is the duration after which the penalty of a transition decays by half.
+	penaltyHalfLife = 30 * time.Minute
+
+	// maxTransitionAge is the duration after which a transition is forgotten, the penalty is less than 1 once
+	// the transition is older than it.
+	maxTransitionAge = 5 * penaltyHalfLife
+
+	// penaltyRefreshPeriod is the interval to schedule the placement again to refresh the decaying penalties while
+	// any cluster is flapping.
+	penaltyRefreshPeriod = penaltyHalfLife / 3
+)
+
+var FlappingClock = clock.Clock(clock.RealClock{})
+
+var _ plugins.Prioritizer = &Flapping{}
+
+// Tracker records the availability transitions of the clusters, it is registered as the event handler of the
+// managed cluster informer. The transitions are kept in memory, so they are forgotten once the scheduler restarts.
+type Tracker struct {
+	sync.Mutex
+	transitions map[string][]time.Time
+}
+
+var _ cache.ResourceEventHandler = &Tracker{}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		transitions: map[string][]time.Time{},
+	}
+}
+
+func (t *Tracker) OnAdd(obj interface{}, isInInitialList bool) {}
+
+func (t *Tracker) OnUpdate(oldObj, newObj interface{}) {
+	oldCluster, ok := oldObj.(*clusterapiv1.ManagedCluster)
+	if !ok {
+		return
+	}
+	newCluster, ok := newObj.(*clusterapiv1.ManagedCluster)
+	if !ok {
+		return
+	}
+
+	// the cluster becoming available for the first time is not a transition
+	if meta.FindStatusCondition(oldCluster.Status.Conditions, clusterapiv1.ManagedClusterConditionAvailable) == nil {
+		return
+	}
+	if isAvailable(oldCluster) == isAvailable(newCluster) {
+		return
+	}
+	t.record(newCluster.Name, FlappingClock.Now())
+}
+
+func (t *Tracker) OnDelete(obj interface{}) {
+	var name string
+	switch o := obj.(type) {
+	case *clusterapiv1.ManagedCluster:
+		name = o.Name
+	case cache.DeletedFinalStateUnknown:
+		cluster, ok := o.Obj.(*clusterapiv1.ManagedCluster)
+		if !ok {
+			return
+		}
+		name = cluster.Name
+	default:
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	delete(t.transitions, name)
+}
+
+func (t *Tracker) record(clusterName string, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	var transitions []time.Time
+	for _, transition := range t.transitions[clusterName] {
+		if now.Sub(transition) < maxTransitionAge {
+			transitions = append(transitions, transition)
+		}
+	}
+	t.transitions[clusterName] = append(transitions, now)
+}
+
+// penalty returns the sum of the decayed penalties of the transitions of the cluster, and the time after which
+// all the transitions are forgotten.
+func (t *Tracker) penalty(clusterName string, now time.Time) (float64, time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	var penalty float64
+	var expiration time.Time
+	for _, transition := range t.transitions[clusterName] {
+		age := now.Sub(transition)
+		if age >= maxTransitionAge {
+			continue
+		}
+		if age < 0 {
+			age = 0
+		}
+		penalty += transitionPenalty * math.Pow(0.5, float64(age)/float64(penaltyHalfLife))
+		if e := transition.Add(maxTransitionAge); e.After(expiration) {
+			expiration = e
+		}
+	}
+	return penalty, expiration
+}
+
+func isAvailable(cluster *clusterapiv1.ManagedCluster) bool {
+	return meta.IsStatusConditionPresentAndEqual(
+		cluster.Status.Conditions, clusterapiv1.ManagedClusterConditionAvailable, metav1.ConditionTrue)
+}
+
+type Flapping struct {
+	handle  plugins.Handle
+	tracker *Tracker
+	// requeueTime is the time to schedule the placement again to refresh the decaying penalties, it is set by Score.
+	requeueTime *time.Time
+}
+
+func New(handle plugins.Handle, tracker *Tracker) *Flapping {
+	return &Flapping{
+		handle:  handle,
+		tracker: tracker,
+	}
+}
+
+func (f *Flapping) Name() string {
+	return reflect.TypeOf(*f).Name()
+}
+
+func (f *Flapping) Description() string {
+	return description
+}
+
+func (f *Flapping) Score(
+	ctx context.Context, placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	now := FlappingClock.Now()
+	f.requeueTime = nil
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		decayed, expiration := f.tracker.penalty(cluster.Name, now)
+		penalty := int64(math.Round(decayed))
+		if penalty > 0 {
+			// the score of the flapping cluster is refreshed until all its transitions are forgotten
+			requeueTime := now.Add(penaltyRefreshPeriod)
+			if requeueTime.After(expiration) {
+				requeueTime = expiration
+			}
+			if f.requeueTime == nil || requeueTime.Before(*f.requeueTime) {
+				f.requeueTime = &requeueTime
+			}
+		}
+		if penalty > -plugins.MinClusterScore {
+			penalty = -plugins.MinClusterScore
+		}
+		scores[cluster.Name] = -penalty
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, framework.NewStatus(f.Name(), framework.Success, "")
+}
+
+func (f *Flapping) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{
+		RequeueTime: f.requeueTime,
+	}, framework.NewStatus(f.Name(), framework.Success, "")
+}
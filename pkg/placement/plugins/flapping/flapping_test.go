@@ -0,0 +1,107 @@
+package flapping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func newCluster(name string, available metav1.ConditionStatus) *clusterapiv1.ManagedCluster {
+	cluster := testinghelpers.NewManagedCluster(name).Build()
+	if len(available) > 0 {
+		cluster.Status.Conditions = []metav1.Condition{
+			{Type: clusterapiv1.ManagedClusterConditionAvailable, Status: available},
+		}
+	}
+	return cluster
+}
+
+func TestScoreClusterWithFlapping(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	originalClock := FlappingClock
+	FlappingClock = fakeClock
+	defer func() {
+		FlappingClock = originalClock
+	}()
+
+	tracker := NewTracker()
+	flapping := New(testinghelpers.NewFakePluginHandle(t, nil), tracker)
+	clusters := []*clusterapiv1.ManagedCluster{
+		newCluster("cluster1", metav1.ConditionTrue),
+		newCluster("cluster2", metav1.ConditionTrue),
+		newCluster("cluster3", metav1.ConditionTrue),
+	}
+
+	placement := testinghelpers.NewPlacement("test", "test").Build()
+	score := func() map[string]int64 {
+		result, status := flapping.Score(context.TODO(), placement, clusters)
+		if status.IsError() {
+			t.Fatalf("unexpected status %v", status)
+		}
+		return result.Scores
+	}
+	assertRequeueAfter := func(expected time.Duration) {
+		t.Helper()
+		result, _ := flapping.RequeueAfter(context.TODO(), placement)
+		switch {
+		case expected == 0 && result.RequeueTime != nil:
+			t.Errorf("expected no requeue, but got %v", result.RequeueTime)
+		case expected != 0 && (result.RequeueTime == nil || result.RequeueTime.Sub(fakeClock.Now()) != expected):
+			t.Errorf("expected requeue after %v, but got %v", expected, result.RequeueTime)
+		}
+	}
+
+	// the clusters joined are not penalized
+	tracker.OnUpdate(newCluster("cluster1", ""), newCluster("cluster1", metav1.ConditionTrue))
+	// the changes other than the availability are not transitions
+	tracker.OnUpdate(newCluster("cluster2", metav1.ConditionTrue), newCluster("cluster2", metav1.ConditionTrue))
+	expected := map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0}
+	if actual := score(); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected scores %v, but got %v", expected, actual)
+	}
+	assertRequeueAfter(0)
+
+	// cluster2 flaps twice and cluster3 once
+	tracker.OnUpdate(newCluster("cluster2", metav1.ConditionTrue), newCluster("cluster2", metav1.ConditionUnknown))
+	tracker.OnUpdate(newCluster("cluster2", metav1.ConditionUnknown), newCluster("cluster2", metav1.ConditionTrue))
+	tracker.OnUpdate(newCluster("cluster3", metav1.ConditionTrue), newCluster("cluster3", metav1.ConditionFalse))
+	expected = map[string]int64{"cluster1": 0, "cluster2": -50, "cluster3": -25}
+	if actual := score(); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected scores %v, but got %v", expected, actual)
+	}
+	// the placement is scheduled again to refresh the decaying penalties
+	assertRequeueAfter(penaltyRefreshPeriod)
+
+	// the penalty decays by half after the half life
+	fakeClock.Step(penaltyHalfLife)
+	expected = map[string]int64{"cluster1": 0, "cluster2": -25, "cluster3": -13}
+	if actual := score(); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected scores %v, but got %v", expected, actual)
+	}
+
+	// the penalty does not exceed the lowest score
+	for i := 0; i < 10; i++ {
+		tracker.OnUpdate(newCluster("cluster2", metav1.ConditionTrue), newCluster("cluster2", metav1.ConditionUnknown))
+	}
+	expected = map[string]int64{"cluster1": 0, "cluster2": -100, "cluster3": -13}
+	if actual := score(); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected scores %v, but got %v", expected, actual)
+	}
+
+	// the transitions are forgotten after the max age or once the cluster is deleted
+	tracker.OnDelete(newCluster("cluster2", metav1.ConditionUnknown))
+	fakeClock.Step(maxTransitionAge)
+	expected = map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0}
+	if actual := score(); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected scores %v, but got %v", expected, actual)
+	}
+	assertRequeueAfter(0)
+}
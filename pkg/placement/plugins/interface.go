@@ -16,6 +16,7 @@ import (
 
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/metrics"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 )
 
 const (
@@ -68,6 +69,9 @@ type Handle interface {
 	// ClusterLister lists all ManagedClusters
 	ClusterLister() clusterlisterv1.ManagedClusterLister
 
+	// PlacementLister lists all Placements
+	PlacementLister() clusterlisterv1beta1.PlacementLister
+
 	// ClusterClient returns the cluster client
 	ClusterClient() clusterclient.Interface
 
@@ -76,6 +80,9 @@ type Handle interface {
 
 	// MetricsRecorder record the metrics for plugins
 	MetricsRecorder() *metrics.ScheduleMetrics
+
+	// SchedulingCache returns the scheduling cache, it is nil if the cache is not enabled or not synced yet.
+	SchedulingCache() *schedulingcache.SchedulingCache
 }
 
 // PluginFilterResult contains the details of a filter plugin result.
@@ -16,10 +16,12 @@ var _ plugins.Filter = &Predicate{}
 
 const description = "Predicate filter filters the clusters based on predicate defined in placement"
 
-type Predicate struct{}
+type Predicate struct {
+	handle plugins.Handle
+}
 
 func New(handle plugins.Handle) *Predicate {
-	return &Predicate{}
+	return &Predicate{handle: handle}
 }
 
 func (p *Predicate) Name() string {
@@ -60,9 +62,10 @@ func (p *Predicate) Filter(
 	}
 
 	// match cluster with selectors one by one
+	schedulingCache := p.handle.SchedulingCache()
 	matched := []*clusterapiv1.ManagedCluster{}
 	for _, cluster := range clusters {
-		claims := helpers.GetClusterClaims(cluster)
+		claims := schedulingCache.ClusterClaims(cluster)
 		for _, cs := range clusterSelectors {
 			if ok := cs.Matches(cluster.Labels, claims); !ok {
 				continue
@@ -109,7 +109,7 @@ func TestMatchWithClusterPredicates(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			p := &Predicate{}
+			p := New(testinghelpers.NewFakePluginHandle(t, nil))
 			result, status := p.Filter(context.TODO(), c.placement, c.clusters)
 			clusters := result.Filtered
 			err := status.AsError()
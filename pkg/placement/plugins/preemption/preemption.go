@@ -0,0 +1,299 @@
+package preemption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// PriorityAnnotation is the annotation on the placement to set its priority, the value is an integer and
+	// the default priority is 0. The placement with higher priority preempts the slots of the clusters claimed
+	// by the placements with lower priority.
+	PriorityAnnotation = "cluster.open-cluster-management.io/experimental-priority"
+
+	// ClusterSlotsAnnotation is the annotation on the managed cluster to set the max number of the placements
+	// which select the cluster at the same time. The number of the placements is not limited if it is not set.
+	ClusterSlotsAnnotation = "cluster.open-cluster-management.io/experimental-placement-slots"
+
+	description = `
+	Preemption filters out the clusters whose slots are all claimed by the placements with the same or higher
+	priority. A placement with higher priority is able to select a cluster whose slots are all claimed, and the
+	placements with lower priority on the cluster are preempted.
+	`
+)
+
+var _ plugins.Filter = &Preemption{}
+
+type Preemption struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *Preemption {
+	return &Preemption{
+		handle: handle,
+	}
+}
+
+func (p *Preemption) Name() string {
+	return reflect.TypeOf(*p).Name()
+}
+
+func (p *Preemption) Description() string {
+	return description
+}
+
+// claim is a placement which selects a cluster.
+type claim struct {
+	namespace string
+	name      string
+	priority  int32
+}
+
+func (c claim) key() string {
+	return fmt.Sprintf("%s/%s", c.namespace, c.name)
+}
+
+// ahead returns whether the claim keeps the slot over the other claim, the claim with higher priority is ahead,
+// and the name is compared if the priorities are the same.
+func (c claim) ahead(other claim) bool {
+	if c.priority == other.priority {
+		return c.key() < other.key()
+	}
+	return c.priority > other.priority
+}
+
+func (p *Preemption) Filter(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginFilterResult, *framework.Status) {
+	priority, err := GetPriority(placement)
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(p.Name(), framework.Misconfigured, err.Error())
+	}
+
+	claims, err := p.getClaims(clusters)
+	if err != nil {
+		return plugins.PluginFilterResult{}, framework.NewStatus(p.Name(), framework.Error, err.Error())
+	}
+
+	current := claim{namespace: placement.Namespace, name: placement.Name, priority: priority}
+	matched := []*clusterapiv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		slots, ok := getClusterSlots(cluster)
+		if !ok {
+			matched = append(matched, cluster)
+			continue
+		}
+
+		// the existing claim keeps the slot if it is ahead of others, while the new claim is only able to
+		// preempt the claims with lower priority.
+		occupied := 0
+		existing := false
+		for _, c := range claims[cluster.Name] {
+			if c.key() == current.key() {
+				existing = true
+			}
+		}
+		for _, c := range claims[cluster.Name] {
+			switch {
+			case c.key() == current.key():
+			case existing && c.ahead(current):
+				occupied++
+			case !existing && c.priority >= current.priority:
+				occupied++
+			}
+		}
+		if occupied < slots {
+			matched = append(matched, cluster)
+		}
+	}
+
+	return plugins.PluginFilterResult{
+		Filtered: matched,
+	}, framework.NewStatus(p.Name(), framework.Success, "")
+}
+
+func (p *Preemption) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(p.Name(), framework.Success, "")
+}
+
+// Preempted returns the placements which lose the slots of the clusters once the placement selects them. The keys
+// of the returned map are the namespaced names of the preempted placements, and the values are the cluster names.
+func (p *Preemption) Preempted(placement *clusterapiv1beta1.Placement, clusters []*clusterapiv1.ManagedCluster) (map[string][]string, error) {
+	priority, err := GetPriority(placement)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.getClaims(clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	current := claim{namespace: placement.Namespace, name: placement.Name, priority: priority}
+	preempted := map[string][]string{}
+	for _, cluster := range clusters {
+		slots, ok := getClusterSlots(cluster)
+		if !ok {
+			continue
+		}
+
+		var others []claim
+		for _, c := range claims[cluster.Name] {
+			if c.key() != current.key() {
+				others = append(others, c)
+			}
+		}
+		if len(others)+1 <= slots {
+			continue
+		}
+
+		sort.Slice(others, func(i, j int) bool {
+			return others[i].ahead(others[j])
+		})
+		// the placement is ahead of the claims with lower priority only, and the claims with lower priority
+		// behind the slots are preempted.
+		var ranked []claim
+		inserted := false
+		for _, c := range others {
+			if !inserted && c.priority < current.priority {
+				ranked = append(ranked, current)
+				inserted = true
+			}
+			ranked = append(ranked, c)
+		}
+		if !inserted {
+			ranked = append(ranked, current)
+		}
+		for i := slots; i < len(ranked); i++ {
+			if c := ranked[i]; c.priority < current.priority {
+				preempted[c.key()] = append(preempted[c.key()], cluster.Name)
+			}
+		}
+	}
+
+	return preempted, nil
+}
+
+// getClaims returns the claims of the placements on each of the clusters whose slots are limited.
+func (p *Preemption) getClaims(clusters []*clusterapiv1.ManagedCluster) (map[string][]claim, error) {
+	limited := sets.New[string]()
+	for _, cluster := range clusters {
+		if _, ok := getClusterSlots(cluster); ok {
+			limited.Insert(cluster.Name)
+		}
+	}
+	claims := map[string][]claim{}
+	if limited.Len() == 0 {
+		return claims, nil
+	}
+
+	placementsOnClusters, err := p.getPlacementsOnClusters(limited)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := map[string]int32{}
+	for clusterName, placementKeys := range placementsOnClusters {
+		for _, key := range sets.List(placementKeys) {
+			namespace, name, err := cache.SplitMetaNamespaceKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			priority, ok := priorities[key]
+			if !ok {
+				placement, err := p.handle.PlacementLister().Placements(namespace).Get(name)
+				switch {
+				case errors.IsNotFound(err):
+				case err != nil:
+					return nil, err
+				default:
+					// the invalid priority is reported on the placement itself, it is taken as the default value here
+					priority, _ = GetPriority(placement)
+				}
+				priorities[key] = priority
+			}
+
+			claims[clusterName] = append(claims[clusterName], claim{
+				namespace: namespace,
+				name:      name,
+				priority:  priority,
+			})
+		}
+	}
+
+	return claims, nil
+}
+
+// getPlacementsOnClusters returns the keys of the placements selecting each of the clusters. The placements are read
+// from the index of the scheduling cache, and all the decisions are listed only if the cache is not synced.
+func (p *Preemption) getPlacementsOnClusters(clusterNames sets.Set[string]) (map[string]sets.Set[string], error) {
+	placements := map[string]sets.Set[string]{}
+	if schedulingCache := p.handle.SchedulingCache(); schedulingCache != nil {
+		for clusterName := range clusterNames {
+			placements[clusterName] = schedulingCache.PlacementsOnCluster(clusterName)
+		}
+		return placements, nil
+	}
+
+	decisions, err := p.handle.DecisionLister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, decision := range decisions {
+		placementName := decision.Labels[clusterapiv1beta1.PlacementLabel]
+		if len(placementName) == 0 {
+			continue
+		}
+		for _, d := range decision.Status.Decisions {
+			if !clusterNames.Has(d.ClusterName) {
+				continue
+			}
+			if _, ok := placements[d.ClusterName]; !ok {
+				placements[d.ClusterName] = sets.New[string]()
+			}
+			placements[d.ClusterName].Insert(fmt.Sprintf("%s/%s", decision.Namespace, placementName))
+		}
+	}
+	return placements, nil
+}
+
+// GetPriority returns the priority of the placement.
+func GetPriority(placement *clusterapiv1beta1.Placement) (int32, error) {
+	value, ok := placement.GetAnnotations()[PriorityAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	priority, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of annotation %s", value, PriorityAnnotation)
+	}
+	return int32(priority), nil
+}
+
+// getClusterSlots returns the number of the slots of the cluster, it returns false if the number is not limited.
+// The invalid value is ignored.
+func getClusterSlots(cluster *clusterapiv1.ManagedCluster) (int, bool) {
+	value, ok := cluster.GetAnnotations()[ClusterSlotsAnnotation]
+	if !ok {
+		return 0, false
+	}
+	slots, err := strconv.Atoi(value)
+	if err != nil || slots < 0 {
+		return 0, false
+	}
+	return slots, true
+}
@@ -0,0 +1,147 @@
+package preemption
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+// cluster1 has 1 slot claimed by ns1/low, cluster2 has 2 slots claimed by ns1/low and ns2/mid, and the slots of
+// cluster3 are not limited.
+func newTestObjects() ([]*clusterapiv1.ManagedCluster, []runtime.Object) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").WithAnnotation(ClusterSlotsAnnotation, "1").Build(),
+		testinghelpers.NewManagedCluster("cluster2").WithAnnotation(ClusterSlotsAnnotation, "2").Build(),
+		testinghelpers.NewManagedCluster("cluster3").Build(),
+	}
+	objs := []runtime.Object{
+		testinghelpers.NewPlacement("ns1", "low").Build(),
+		testinghelpers.NewPlacementWithAnnotations("ns2", "mid", map[string]string{PriorityAnnotation: "5"}).Build(),
+		testinghelpers.NewPlacementDecision("ns1", "low-decision-1").
+			WithLabel(clusterapiv1beta1.PlacementLabel, "low").
+			WithDecisions("cluster1", "cluster2", "cluster3").Build(),
+		testinghelpers.NewPlacementDecision("ns2", "mid-decision-1").
+			WithLabel(clusterapiv1beta1.PlacementLabel, "mid").
+			WithDecisions("cluster2").Build(),
+	}
+	return clusters, objs
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		name             string
+		placement        *clusterapiv1beta1.Placement
+		expectedClusters []string
+		expectedCode     framework.Code
+	}{
+		{
+			name:             "new placement with default priority",
+			placement:        testinghelpers.NewPlacement("ns3", "new").Build(),
+			expectedClusters: []string{"cluster3"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "new placement with the same priority as some placements",
+			placement: testinghelpers.NewPlacementWithAnnotations("ns3", "new", map[string]string{
+				PriorityAnnotation: "5",
+			}).Build(),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "new placement with higher priority",
+			placement: testinghelpers.NewPlacementWithAnnotations("ns3", "new", map[string]string{
+				PriorityAnnotation: "10",
+			}).Build(),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name:             "existing placement keeps the slots",
+			placement:        testinghelpers.NewPlacement("ns1", "low").Build(),
+			expectedClusters: []string{"cluster1", "cluster2", "cluster3"},
+			expectedCode:     framework.Success,
+		},
+		{
+			name: "invalid priority",
+			placement: testinghelpers.NewPlacementWithAnnotations("ns3", "new", map[string]string{
+				PriorityAnnotation: "high",
+			}).Build(),
+			expectedCode: framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusters, objs := newTestObjects()
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, objs...))
+
+			result, status := p.Filter(context.TODO(), c.placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Fatalf("expected code %v, but got %v", c.expectedCode, status.Code())
+			}
+			if status.Code() != framework.Success {
+				return
+			}
+
+			var names []string
+			for _, cluster := range result.Filtered {
+				names = append(names, cluster.Name)
+			}
+			if !reflect.DeepEqual(names, c.expectedClusters) {
+				t.Errorf("expected clusters %v, but got %v", c.expectedClusters, names)
+			}
+		})
+	}
+}
+
+func TestPreempted(t *testing.T) {
+	cases := []struct {
+		name              string
+		placement         *clusterapiv1beta1.Placement
+		expectedPreempted map[string][]string
+	}{
+		{
+			name:              "no preemption with the same priority",
+			placement:         testinghelpers.NewPlacement("ns3", "new").Build(),
+			expectedPreempted: map[string][]string{},
+		},
+		{
+			name: "preempt placements with lower priority",
+			placement: testinghelpers.NewPlacementWithAnnotations("ns3", "new", map[string]string{
+				PriorityAnnotation: "10",
+			}).Build(),
+			expectedPreempted: map[string][]string{"ns1/low": {"cluster1", "cluster2"}},
+		},
+		{
+			name: "placement with the same priority is not preempted",
+			placement: testinghelpers.NewPlacementWithAnnotations("ns3", "new", map[string]string{
+				PriorityAnnotation: "5",
+			}).Build(),
+			expectedPreempted: map[string][]string{"ns1/low": {"cluster1", "cluster2"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusters, objs := newTestObjects()
+			p := New(testinghelpers.NewFakePluginHandle(t, nil, objs...))
+
+			preempted, err := p.Preempted(c.placement, clusters)
+			if err != nil {
+				t.Fatalf("unexpected err %v", err)
+			}
+			if !reflect.DeepEqual(preempted, c.expectedPreempted) {
+				t.Errorf("expected preempted %v, but got %v", c.expectedPreempted, preempted)
+			}
+		})
+	}
+}
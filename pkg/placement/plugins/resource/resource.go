@@ -3,13 +3,16 @@ package resource
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/controllers/schedulingcache"
 	"open-cluster-management.io/ocm/pkg/placement/plugins"
 )
 
@@ -20,6 +23,11 @@ const (
 	decisions based on the resource allocatable of managed clusters.
 	The clusters that has the most allocatable are given the highest score,
 	while the least is given the lowest score.
+	ResourceAvailableCPU and ResourceAvailableMemory prioritizer makes the scheduling
+	decisions based on the resource allocatable minus the resource requested by the pods,
+	which is reported by the registration agent once the resource usage report is enabled,
+	or by the status feedback of the resource usage ManifestWorks in the cluster namespace.
+	The clusters not reporting the requested resource are not scored.
 	`
 )
 
@@ -30,6 +38,13 @@ var resourceMap = map[string]clusterapiv1.ResourceName{
 	"Memory": clusterapiv1.ResourceMemory,
 }
 
+// requestedResourceMap maps the resources to the resources requested by the pods reported in the capacity of the
+// managed clusters.
+var requestedResourceMap = map[clusterapiv1.ResourceName]clusterapiv1.ResourceName{
+	clusterapiv1.ResourceCPU:    helpers.ResourceCPURequested,
+	clusterapiv1.ResourceMemory: helpers.ResourceMemoryRequested,
+}
+
 type ResourcePrioritizer struct {
 	handle          plugins.Handle
 	prioritizerName string
@@ -82,8 +97,11 @@ func (r *ResourcePrioritizer) Description() string {
 func (r *ResourcePrioritizer) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
 	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
 	status := framework.NewStatus(r.Name(), framework.Success, "")
-	if r.algorithm == "Allocatable" {
-		return mostResourceAllocatableScores(r.resource, clusters), status
+	switch r.algorithm {
+	case "Allocatable":
+		return mostResourceAllocatableScores(r.handle.SchedulingCache(), r.resource, clusters), status
+	case "Available":
+		return mostResourceAvailableScores(r.handle.SchedulingCache(), r.resource, clusters), status
 	}
 	return plugins.PluginScoreResult{}, status
 }
@@ -95,11 +113,12 @@ func (r *ResourcePrioritizer) RequeueAfter(ctx context.Context, placement *clust
 // Calculate clusters scores based on the resource allocatable.
 // The clusters that has the most allocatable are given the highest score, while the least is given the lowest score.
 // The score range is from -100 to 100.
-func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clusters []*clusterapiv1.ManagedCluster) plugins.PluginScoreResult {
+func mostResourceAllocatableScores(schedulingCache *schedulingcache.SchedulingCache,
+	resourceName clusterapiv1.ResourceName, clusters []*clusterapiv1.ManagedCluster) plugins.PluginScoreResult {
 	scores := map[string]int64{}
 
 	// get resourceName's min and max allocatable among all the clusters
-	minAllocatable, maxAllocatable, err := getClustersMinMaxAllocatableResource(clusters, resourceName)
+	minAllocatable, maxAllocatable, err := getClustersMinMaxAllocatableResource(schedulingCache, clusters, resourceName)
 	if err != nil {
 		return plugins.PluginScoreResult{
 			Scores: scores,
@@ -108,7 +127,7 @@ func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clust
 
 	for _, cluster := range clusters {
 		// get one cluster resourceName's allocatable
-		allocatable, _, err := getClusterResource(cluster, resourceName)
+		allocatable, _, err := schedulingCache.ClusterResource(cluster, resourceName)
 		if err != nil {
 			continue
 		}
@@ -127,31 +146,14 @@ func mostResourceAllocatableScores(resourceName clusterapiv1.ResourceName, clust
 	}
 }
 
-// Go through one cluster resources and return the allocatable and capacity of the resourceName.
-func getClusterResource(cluster *clusterapiv1.ManagedCluster, resourceName clusterapiv1.ResourceName) (allocatable, capacity float64, err error) {
-	if v, exist := cluster.Status.Allocatable[resourceName]; exist {
-		allocatable = v.AsApproximateFloat64()
-	} else {
-		return allocatable, capacity, fmt.Errorf("no allocatable %s found in cluster %s", resourceName, cluster.ObjectMeta.Name)
-	}
-
-	if v, exist := cluster.Status.Capacity[resourceName]; exist {
-		capacity = v.AsApproximateFloat64()
-	} else {
-		return allocatable, capacity, fmt.Errorf("no capacity %s found in cluster %s", resourceName, cluster.ObjectMeta.Name)
-	}
-
-	return allocatable, capacity, nil
-}
-
 // Go through all the cluster resources and return the min and max allocatable value of the resourceName.
-func getClustersMinMaxAllocatableResource(clusters []*clusterapiv1.ManagedCluster,
+func getClustersMinMaxAllocatableResource(schedulingCache *schedulingcache.SchedulingCache, clusters []*clusterapiv1.ManagedCluster,
 	resourceName clusterapiv1.ResourceName) (minAllocatable, maxAllocatable float64, err error) {
 	allocatable := sort.Float64Slice{}
 
 	// get allocatable resources
 	for _, cluster := range clusters {
-		if alloc, _, err := getClusterResource(cluster, resourceName); err == nil {
+		if alloc, _, err := schedulingCache.ClusterResource(cluster, resourceName); err == nil {
 			allocatable = append(allocatable, alloc)
 		}
 	}
@@ -165,3 +167,49 @@ func getClustersMinMaxAllocatableResource(clusters []*clusterapiv1.ManagedCluste
 	sort.Float64s(allocatable)
 	return allocatable[0], allocatable[len(allocatable)-1], nil
 }
+
+// Calculate clusters scores based on the resource available, which is the allocatable minus the requested.
+// The clusters that has the most available are given the highest score, while the least is given the lowest score.
+// The score range is from -100 to 100.
+func mostResourceAvailableScores(schedulingCache *schedulingcache.SchedulingCache,
+	resourceName clusterapiv1.ResourceName, clusters []*clusterapiv1.ManagedCluster) plugins.PluginScoreResult {
+	scores := map[string]int64{}
+
+	available := map[string]float64{}
+	for _, cluster := range clusters {
+		allocatable, _, err := schedulingCache.ClusterResource(cluster, resourceName)
+		if err != nil {
+			continue
+		}
+		requested, err := schedulingCache.ClusterCapacity(cluster, requestedResourceMap[resourceName])
+		if err != nil {
+			continue
+		}
+		available[cluster.Name] = allocatable - requested
+	}
+	if len(available) == 0 {
+		return plugins.PluginScoreResult{
+			Scores: scores,
+		}
+	}
+
+	minAvailable, maxAvailable := math.MaxFloat64, -math.MaxFloat64
+	for _, value := range available {
+		minAvailable = math.Min(minAvailable, value)
+		maxAvailable = math.Max(maxAvailable, value)
+	}
+
+	for name, value := range available {
+		// score = ((resource_x_available - min(resource_x_available)) / (max(resource_x_available) - min(resource_x_available)) - 0.5) * 2 * 100
+		if (maxAvailable - minAvailable) != 0 {
+			ratio := (value - minAvailable) / (maxAvailable - minAvailable)
+			scores[name] = int64((ratio - 0.5) * 2.0 * 100.0)
+		} else {
+			scores[name] = 100.0
+		}
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}
+}
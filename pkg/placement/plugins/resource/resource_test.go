@@ -10,6 +10,7 @@ import (
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
 )
 
@@ -119,6 +120,22 @@ func TestScoreClusterWithResource(t *testing.T) {
 			},
 			expectedScores: map[string]int64{},
 		},
+		{
+			name:      "scores of ResourceAvailableCPU",
+			resource:  clusterapiv1.ResourceCPU,
+			algorithm: "Available",
+			placement: testinghelpers.NewPlacement("test", "test").Build(),
+			clusters: []*clusterapiv1.ManagedCluster{
+				testinghelpers.NewManagedCluster("cluster1").WithResource(clusterapiv1.ResourceCPU, "10", "10").
+					WithResource(helpers.ResourceCPURequested, "0", "8").Build(),
+				testinghelpers.NewManagedCluster("cluster2").WithResource(clusterapiv1.ResourceCPU, "6", "8").
+					WithResource(helpers.ResourceCPURequested, "0", "1").Build(),
+				testinghelpers.NewManagedCluster("cluster3").WithResource(clusterapiv1.ResourceCPU, "8", "8").
+					WithResource(helpers.ResourceCPURequested, "0", "0").Build(),
+				testinghelpers.NewManagedCluster("cluster4").WithResource(clusterapiv1.ResourceCPU, "8", "8").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": -100, "cluster2": 0, "cluster3": 100},
+		},
 	}
 
 	for _, c := range cases {
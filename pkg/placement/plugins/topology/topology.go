@@ -0,0 +1,163 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	"open-cluster-management.io/ocm/pkg/placement/plugins"
+)
+
+const (
+	// ReferenceClusterAnnotation is the annotation on the placement to specify the name of the reference cluster, the
+	// topology labels of the reference cluster are compared with the topology labels of each cluster.
+	ReferenceClusterAnnotation = "cluster.open-cluster-management.io/experimental-topology-reference-cluster"
+
+	// ReferenceLabelsAnnotation is the annotation on the placement to specify the reference topology with a label
+	// set, e.g. "topology.kubernetes.io/region=us-east-1,topology.kubernetes.io/zone=us-east-1a". It is ignored if
+	// the reference cluster is specified.
+	ReferenceLabelsAnnotation = "cluster.open-cluster-management.io/experimental-topology-reference-labels"
+
+	// TopologyKeysAnnotation is the annotation on the placement to specify the comma separated topology label keys,
+	// the keys are ordered from the broadest topology domain to the narrowest one. By default, the region and zone
+	// labels are used.
+	TopologyKeysAnnotation = "cluster.open-cluster-management.io/experimental-topology-keys"
+
+	LabelTopologyRegion = "topology.kubernetes.io/region"
+	LabelTopologyZone   = "topology.kubernetes.io/zone"
+
+	description = `
+	TopologyProximity prioritizer scores the clusters by their topology labels (e.g. region and zone) relative to a
+	reference cluster or label set. The cluster in the same narrowest topology domain as the reference is given the
+	highest score, while the cluster not in the same broadest topology domain is given the lowest score.
+	`
+)
+
+var defaultTopologyKeys = []string{LabelTopologyRegion, LabelTopologyZone}
+
+var _ plugins.Prioritizer = &TopologyProximity{}
+
+type TopologyProximity struct {
+	handle plugins.Handle
+}
+
+func New(handle plugins.Handle) *TopologyProximity {
+	return &TopologyProximity{
+		handle: handle,
+	}
+}
+
+func (t *TopologyProximity) Name() string {
+	return reflect.TypeOf(*t).Name()
+}
+
+func (t *TopologyProximity) Description() string {
+	return description
+}
+
+func (t *TopologyProximity) Score(ctx context.Context, placement *clusterapiv1beta1.Placement,
+	clusters []*clusterapiv1.ManagedCluster) (plugins.PluginScoreResult, *framework.Status) {
+	scores := map[string]int64{}
+	for _, cluster := range clusters {
+		scores[cluster.Name] = 0
+	}
+
+	reference, status := t.getReference(placement)
+	if status.Code() != framework.Success {
+		return plugins.PluginScoreResult{Scores: scores}, status
+	}
+
+	keys := getTopologyKeys(placement)
+	for _, cluster := range clusters {
+		scores[cluster.Name] = proximityScore(keys, reference, cluster.Labels)
+	}
+
+	return plugins.PluginScoreResult{
+		Scores: scores,
+	}, framework.NewStatus(t.Name(), framework.Success, "")
+}
+
+func (t *TopologyProximity) RequeueAfter(ctx context.Context, placement *clusterapiv1beta1.Placement) (plugins.PluginRequeueResult, *framework.Status) {
+	return plugins.PluginRequeueResult{}, framework.NewStatus(t.Name(), framework.Success, "")
+}
+
+// getReference returns the reference topology labels of the placement. The reference cluster takes precedence over
+// the reference label set.
+func (t *TopologyProximity) getReference(placement *clusterapiv1beta1.Placement) (map[string]string, *framework.Status) {
+	annotations := placement.GetAnnotations()
+
+	if clusterName := annotations[ReferenceClusterAnnotation]; len(clusterName) > 0 {
+		cluster, err := t.handle.ClusterLister().Get(clusterName)
+		switch {
+		case errors.IsNotFound(err):
+			return nil, framework.NewStatus(
+				t.Name(),
+				framework.Warning,
+				fmt.Sprintf("the reference cluster %s is not found", clusterName),
+			)
+		case err != nil:
+			return nil, framework.NewStatus(t.Name(), framework.Error, err.Error())
+		}
+		return cluster.Labels, framework.NewStatus(t.Name(), framework.Success, "")
+	}
+
+	if referenceLabels := annotations[ReferenceLabelsAnnotation]; len(referenceLabels) > 0 {
+		reference, err := labels.ConvertSelectorToLabelsMap(referenceLabels)
+		if err != nil {
+			return nil, framework.NewStatus(
+				t.Name(),
+				framework.Misconfigured,
+				fmt.Sprintf("invalid reference labels %q: %v", referenceLabels, err),
+			)
+		}
+		return reference, framework.NewStatus(t.Name(), framework.Success, "")
+	}
+
+	return nil, framework.NewStatus(
+		t.Name(),
+		framework.Warning,
+		fmt.Sprintf("neither %s nor %s annotation is specified", ReferenceClusterAnnotation, ReferenceLabelsAnnotation),
+	)
+}
+
+func getTopologyKeys(placement *clusterapiv1beta1.Placement) []string {
+	value := placement.GetAnnotations()[TopologyKeysAnnotation]
+	if len(value) == 0 {
+		return defaultTopologyKeys
+	}
+
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return defaultTopologyKeys
+	}
+	return keys
+}
+
+// proximityScore counts the topology domains the cluster shares with the reference from the broadest one, and
+// normalizes the count to a value between -100 and 100. A narrower domain is only counted if the broader domains are
+// shared, e.g. the clusters in the zones with the same name of different regions are not close to each other.
+func proximityScore(keys []string, reference, clusterLabels map[string]string) int64 {
+	matched := 0
+	for _, key := range keys {
+		value, ok := reference[key]
+		if !ok || len(value) == 0 || clusterLabels[key] != value {
+			break
+		}
+		matched++
+	}
+
+	return plugins.MinClusterScore + (plugins.MaxClusterScore-plugins.MinClusterScore)*int64(matched)/int64(len(keys))
+}
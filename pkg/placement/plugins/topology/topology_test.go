@@ -0,0 +1,104 @@
+package topology
+
+import (
+	"context"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterapiv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management.io/ocm/pkg/placement/controllers/framework"
+	testinghelpers "open-cluster-management.io/ocm/pkg/placement/helpers/testing"
+)
+
+func TestScoreClusterWithTopologyProximity(t *testing.T) {
+	clusters := []*clusterapiv1.ManagedCluster{
+		testinghelpers.NewManagedCluster("cluster1").
+			WithLabel(LabelTopologyRegion, "us-east-1").WithLabel(LabelTopologyZone, "us-east-1a").Build(),
+		testinghelpers.NewManagedCluster("cluster2").
+			WithLabel(LabelTopologyRegion, "us-east-1").WithLabel(LabelTopologyZone, "us-east-1b").Build(),
+		testinghelpers.NewManagedCluster("cluster3").
+			WithLabel(LabelTopologyRegion, "eu-west-1").WithLabel(LabelTopologyZone, "us-east-1a").Build(),
+		testinghelpers.NewManagedCluster("cluster4").Build(),
+	}
+
+	cases := []struct {
+		name           string
+		placement      *clusterapiv1beta1.Placement
+		existingObjs   []runtime.Object
+		expectedScores map[string]int64
+		expectedCode   framework.Code
+	}{
+		{
+			name:           "no reference",
+			placement:      testinghelpers.NewPlacement("test", "test").Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0, "cluster4": 0},
+			expectedCode:   framework.Warning,
+		},
+		{
+			name: "reference cluster",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ReferenceClusterAnnotation: "data",
+			}).Build(),
+			existingObjs: []runtime.Object{
+				testinghelpers.NewManagedCluster("data").
+					WithLabel(LabelTopologyRegion, "us-east-1").WithLabel(LabelTopologyZone, "us-east-1a").Build(),
+			},
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": 0, "cluster3": -100, "cluster4": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "reference cluster not found",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ReferenceClusterAnnotation: "data",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0, "cluster4": 0},
+			expectedCode:   framework.Warning,
+		},
+		{
+			name: "reference labels",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ReferenceLabelsAnnotation: "topology.kubernetes.io/region=us-east-1",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": -100, "cluster4": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "reference labels with customized topology keys",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ReferenceLabelsAnnotation: "topology.kubernetes.io/zone=us-east-1a",
+				TopologyKeysAnnotation:    "topology.kubernetes.io/zone",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 100, "cluster2": -100, "cluster3": 100, "cluster4": -100},
+			expectedCode:   framework.Success,
+		},
+		{
+			name: "invalid reference labels",
+			placement: testinghelpers.NewPlacementWithAnnotations("test", "test", map[string]string{
+				ReferenceLabelsAnnotation: "topology.kubernetes.io/region",
+			}).Build(),
+			expectedScores: map[string]int64{"cluster1": 0, "cluster2": 0, "cluster3": 0, "cluster4": 0},
+			expectedCode:   framework.Misconfigured,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			topology := &TopologyProximity{
+				handle: testinghelpers.NewFakePluginHandle(t, nil, c.existingObjs...),
+			}
+
+			scoreResult, status := topology.Score(context.TODO(), c.placement, clusters)
+			if status.Code() != c.expectedCode {
+				t.Errorf("Expect status code %v, but got %v", c.expectedCode, status.Code())
+			}
+
+			if !apiequality.Semantic.DeepEqual(scoreResult.Scores, c.expectedScores) {
+				t.Errorf("Expect score %v, but got %v", c.expectedScores, scoreResult.Scores)
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package clientcert
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
 	"math/rand"
+	"net"
+	"net/url"
 	"reflect"
 	"time"
 
@@ -33,6 +36,12 @@ const (
 
 	ClusterNameFile = "cluster-name"
 	AgentNameFile   = "agent-name"
+	// AgentIdentityGenerationFile is the generation of the agent identity the client certificate is issued for, it
+	// is only set if the generation is specified.
+	AgentIdentityGenerationFile = "agent-identity-generation"
+	// HubCABundleFile is the current and the next CA bundles of the hub distributed by the hub, they are appended to
+	// the CA of the hub kubeconfig. It is only set if the hub CA bundle distribution is enabled.
+	HubCABundleFile = "hub-ca-bundle.crt"
 
 	// ClusterCertificateRotatedCondition is a condition type that client certificate is rotated
 	ClusterCertificateRotatedCondition = "ClusterCertificateRotated"
@@ -50,6 +59,10 @@ type CSROption struct {
 	Subject *pkix.Name
 	// DNSNames represents DNS names used to create the client certificate
 	DNSNames []string
+	// IPAddresses represents IP addresses used to create the client certificate
+	IPAddresses []net.IP
+	// URIs represents URIs used to create the client certificate, e.g. a SPIFFE ID
+	URIs []*url.URL
 	// SignerName is the name of the signer specified in the created csrs
 	SignerName string
 
@@ -314,7 +327,7 @@ func (c *clientCertificateController) sync(ctx context.Context, syncCtx factory.
 	}
 
 	keyData, createdCSRName, err := func() ([]byte, string, error) {
-		// create a new private key
+		// create a new private key, the ECDSA P-256 key is approved by FIPS, so it is kept in the FIPS compliance mode
 		keyData, err := keyutil.MakeEllipticPrivateKeyPEM()
 		if err != nil {
 			return nil, "", err
@@ -324,7 +337,12 @@ func (c *clientCertificateController) sync(ctx context.Context, syncCtx factory.
 		if err != nil {
 			return keyData, "", fmt.Errorf("invalid private key for certificate request: %w", err)
 		}
-		csrData, err := certutil.MakeCSR(privateKey, c.Subject, c.DNSNames, nil)
+		csrData, err := certutil.MakeCSRFromTemplate(privateKey, &x509.CertificateRequest{
+			Subject:     *c.Subject,
+			DNSNames:    c.DNSNames,
+			IPAddresses: c.IPAddresses,
+			URIs:        c.URIs,
+		})
 		if err != nil {
 			return keyData, "", fmt.Errorf("unable to generate certificate request: %w", err)
 		}
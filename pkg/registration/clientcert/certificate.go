@@ -6,6 +6,9 @@ import (
 	"crypto/x509/pkix"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -30,6 +33,8 @@ import (
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
 
+const spiffeScheme = "spiffe"
+
 // IsCertificateValid return true if
 // 1) All certs in client certificate are not expired.
 // 2) At least one cert matches the given subject if specified
@@ -84,6 +89,37 @@ func certMatchSubject(cert *x509.Certificate, subject *pkix.Name) bool {
 	return sets.New(cert.Subject.OrganizationalUnit...).Equal(sets.New(subject.OrganizationalUnit...))
 }
 
+// ParseSANs parses the IP addresses and the URIs used as the SANs of the client certificate. The URIs must be
+// absolute, and a SPIFFE ID must have a trust domain and a path without the port, user info, query and fragment.
+func ParseSANs(ipAddresses, uris []string) ([]net.IP, []*url.URL, error) {
+	var ipSANs []net.IP
+	for _, ipAddress := range ipAddresses {
+		ip := net.ParseIP(ipAddress)
+		if ip == nil {
+			return nil, nil, fmt.Errorf("invalid IP address SAN %q", ipAddress)
+		}
+		ipSANs = append(ipSANs, ip)
+	}
+
+	var uriSANs []*url.URL
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid URI SAN %q: %w", uri, err)
+		}
+		if !u.IsAbs() || (len(u.Host) == 0 && len(u.Opaque) == 0) {
+			return nil, nil, fmt.Errorf("invalid URI SAN %q: it must be an absolute URI", uri)
+		}
+		if u.Scheme == spiffeScheme && (len(u.Host) == 0 || len(u.Port()) > 0 || u.User != nil ||
+			len(u.RawQuery) > 0 || len(u.Fragment) > 0 || len(strings.Trim(u.Path, "/")) == 0) {
+			return nil, nil, fmt.Errorf("invalid SPIFFE ID %q: it must be spiffe://<trust domain>/<path>", uri)
+		}
+		uriSANs = append(uriSANs, u)
+	}
+
+	return ipSANs, uriSANs, nil
+}
+
 // getCertValidityPeriod returns the validity period of the client certificate in the secret
 func getCertValidityPeriod(secret *corev1.Secret) (*time.Time, *time.Time, error) {
 	if secret.Data == nil {
@@ -212,6 +212,61 @@ func TestGetCertValidityPeriod(t *testing.T) {
 	}
 }
 
+func TestParseSANs(t *testing.T) {
+	cases := []struct {
+		name        string
+		ipAddresses []string
+		uris        []string
+		expectedErr string
+	}{
+		{
+			name: "no sans",
+		},
+		{
+			name:        "valid sans",
+			ipAddresses: []string{"10.0.0.1", "fd00::1"},
+			uris:        []string{"spiffe://cluster.local/ns/testns/sa/testagent", "https://example.com/agent"},
+		},
+		{
+			name:        "invalid ip address",
+			ipAddresses: []string{"10.0.0.256"},
+			expectedErr: "invalid IP address SAN \"10.0.0.256\"",
+		},
+		{
+			name:        "relative uri",
+			uris:        []string{"/ns/testns/sa/testagent"},
+			expectedErr: "invalid URI SAN \"/ns/testns/sa/testagent\": it must be an absolute URI",
+		},
+		{
+			name:        "spiffe id without path",
+			uris:        []string{"spiffe://cluster.local"},
+			expectedErr: "invalid SPIFFE ID \"spiffe://cluster.local\": it must be spiffe://<trust domain>/<path>",
+		},
+		{
+			name:        "spiffe id with port",
+			uris:        []string{"spiffe://cluster.local:8443/ns/testns/sa/testagent"},
+			expectedErr: "invalid SPIFFE ID \"spiffe://cluster.local:8443/ns/testns/sa/testagent\": it must be spiffe://<trust domain>/<path>",
+		},
+		{
+			name:        "spiffe id with query",
+			uris:        []string{"spiffe://cluster.local/ns/testns?sa=testagent"},
+			expectedErr: "invalid SPIFFE ID \"spiffe://cluster.local/ns/testns?sa=testagent\": it must be spiffe://<trust domain>/<path>",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ipSANs, uriSANs, err := ParseSANs(c.ipAddresses, c.uris)
+			testingcommon.AssertError(t, err, c.expectedErr)
+			if err != nil {
+				return
+			}
+			if len(ipSANs) != len(c.ipAddresses) || len(uriSANs) != len(c.uris) {
+				t.Errorf("expected %d IP SANs and %d URI SANs, but got %v and %v", len(c.ipAddresses), len(c.uris), ipSANs, uriSANs)
+			}
+		})
+	}
+}
+
 func TestBuildKubeconfig(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -2,7 +2,9 @@ package clientcert
 
 import (
 	"context"
+	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"testing"
 	"time"
@@ -37,6 +39,11 @@ func TestSync(t *testing.T) {
 	testSubject := &pkix.Name{
 		CommonName: commonName,
 	}
+	ipSANs, uriSANs, err := ParseSANs([]string{"10.0.0.1"},
+		[]string{"spiffe://example.org/open-cluster-management/cluster/testcluster"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
 
 	cases := []struct {
 		name              string
@@ -189,6 +196,8 @@ func TestSync(t *testing.T) {
 				Subject:         testSubject,
 				SignerName:      certificates.KubeAPIServerClientSignerName,
 				HaltCSRCreation: func() bool { return false },
+				IPAddresses:     ipSANs,
+				URIs:            uriSANs,
 			}
 
 			updater := &fakeStatusUpdater{}
@@ -226,6 +235,23 @@ func TestSync(t *testing.T) {
 				t.Errorf("condition is not correct, expected %v, got %v", c.expectedCondition, updater.cond)
 			}
 
+			if ctrl.csrData != nil {
+				block, _ := pem.Decode(ctrl.csrData)
+				if block == nil {
+					t.Fatalf("invalid csr data %s", string(ctrl.csrData))
+				}
+				csr, err := x509.ParseCertificateRequest(block.Bytes)
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if len(csr.IPAddresses) != 1 || !csr.IPAddresses[0].Equal(ipSANs[0]) {
+					t.Errorf("expected IP SANs %v, but got %v", ipSANs, csr.IPAddresses)
+				}
+				if len(csr.URIs) != 1 || csr.URIs[0].String() != uriSANs[0].String() {
+					t.Errorf("expected URI SANs %v, but got %v", uriSANs, csr.URIs)
+				}
+			}
+
 			c.validateActions(t, hubKubeClient.Actions(), agentKubeClient.Actions())
 		})
 	}
@@ -265,11 +291,13 @@ func (f *fakeStatusUpdater) update(_ context.Context, cond metav1.Condition) err
 type mockCSRControl struct {
 	approved       bool
 	issuedCertData []byte
+	csrData        []byte
 	csrClient      *clienttesting.Fake
 }
 
 func (m *mockCSRControl) create(
-	_ context.Context, _ events.Recorder, objMeta metav1.ObjectMeta, _ []byte, _ string, _ *int32) (string, error) {
+	_ context.Context, _ events.Recorder, objMeta metav1.ObjectMeta, csrData []byte, _ string, _ *int32) (string, error) {
+	m.csrData = csrData
 	mockCSR := &unstructured.Unstructured{}
 	_, err := m.csrClient.Invokes(clienttesting.CreateActionImpl{
 		ActionImpl: clienttesting.ActionImpl{
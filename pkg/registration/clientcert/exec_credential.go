@@ -0,0 +1,70 @@
+package clientcert
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthenticationv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// execCredentialRefreshInterval is the max time the clients cache the credential returned by the exec credential
+// plugin, so the rotated client certificate is picked up in time.
+const execCredentialRefreshInterval = 5 * time.Minute
+
+// SetExecCredentialPlugin sets the auth info of the kubeconfig to run the exec credential plugin with the command and
+// args, instead of referencing the client certificate and key files. The plugin is expected to output the client
+// certificate and key in the secret, e.g. with the credential command of the registration, so the clients pick up
+// the rotated certificate without reloading the kubeconfig.
+func SetExecCredentialPlugin(kubeconfig *clientcmdapi.Config, command string, args []string) {
+	for _, authInfo := range kubeconfig.AuthInfos {
+		authInfo.ClientCertificate = ""
+		authInfo.ClientKey = ""
+		authInfo.Exec = &clientcmdapi.ExecConfig{
+			APIVersion:      clientauthenticationv1.SchemeGroupVersion.String(),
+			Command:         command,
+			Args:            args,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+	}
+}
+
+// LoadExecCredential returns the ExecCredential with the client certificate and key in the directory. The credential
+// expires once the certificate expires, or after the refresh interval.
+func LoadExecCredential(certDir string) (*clientauthenticationv1.ExecCredential, error) {
+	certData, err := os.ReadFile(path.Clean(path.Join(certDir, TLSCertFile)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+	}
+	keyData, err := os.ReadFile(path.Clean(path.Join(certDir, TLSKeyFile)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the client key: %w", err)
+	}
+	certs, err := certutil.ParseCertsPEM(certData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the client certificate: %w", err)
+	}
+
+	expiration := time.Now().Add(execCredentialRefreshInterval)
+	for _, cert := range certs {
+		if cert.NotAfter.Before(expiration) {
+			expiration = cert.NotAfter
+		}
+	}
+
+	return &clientauthenticationv1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clientauthenticationv1.SchemeGroupVersion.String(),
+			Kind:       "ExecCredential",
+		},
+		Status: &clientauthenticationv1.ExecCredentialStatus{
+			ExpirationTimestamp:   &metav1.Time{Time: expiration},
+			ClientCertificateData: string(certData),
+			ClientKeyData:         string(keyData),
+		},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package clientcert
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func TestSetExecCredentialPlugin(t *testing.T) {
+	kubeconfig := BuildKubeconfig("hub", "https://127.0.0.1:6443", []byte("ca"), "", TLSCertFile, TLSKeyFile)
+	SetExecCredentialPlugin(&kubeconfig, "/registration", []string{"credential", "--cert-dir=/managed/hub-kubeconfig"})
+
+	authInfo := kubeconfig.AuthInfos["default-auth"]
+	if len(authInfo.ClientCertificate) != 0 || len(authInfo.ClientKey) != 0 {
+		t.Errorf("expected the cert files are not referenced, but got %v", authInfo)
+	}
+	if authInfo.Exec == nil || authInfo.Exec.Command != "/registration" || len(authInfo.Exec.Args) != 2 {
+		t.Errorf("unexpected exec config %v", authInfo.Exec)
+	}
+}
+
+func TestLoadExecCredential(t *testing.T) {
+	cases := []struct {
+		name               string
+		certDuration       time.Duration
+		withoutKey         bool
+		expectedErr        bool
+		expectedExpiration time.Duration
+	}{
+		{
+			name:        "no key",
+			withoutKey:  true,
+			expectedErr: true,
+		},
+		{
+			name:               "cert expires after the refresh interval",
+			certDuration:       time.Hour,
+			expectedExpiration: execCredentialRefreshInterval,
+		},
+		{
+			name:               "cert expires before the refresh interval",
+			certDuration:       time.Minute,
+			expectedExpiration: time.Minute,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "credential")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			cert := testinghelpers.NewTestCert("test", c.certDuration)
+			if err := os.WriteFile(path.Join(dir, TLSCertFile), cert.Cert, 0600); err != nil {
+				t.Fatal(err)
+			}
+			if !c.withoutKey {
+				if err := os.WriteFile(path.Join(dir, TLSKeyFile), cert.Key, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			credential, err := LoadExecCredential(dir)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected err %v, but got %v", c.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if credential.Status.ClientCertificateData != string(cert.Cert) ||
+				credential.Status.ClientKeyData != string(cert.Key) {
+				t.Errorf("unexpected credential data")
+			}
+			expiration := time.Until(credential.Status.ExpirationTimestamp.Time)
+			if expiration > c.expectedExpiration || expiration < c.expectedExpiration-time.Minute {
+				t.Errorf("expected expiration in %v, but got %v", c.expectedExpiration, expiration)
+			}
+		})
+	}
+}
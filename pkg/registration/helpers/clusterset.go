@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	clustersdkv1beta2 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1beta2"
+)
+
+// ExclusiveClusterSetAnnotationKey is the annotation to mark a ManagedClusterSet as exclusive. A ManagedCluster
+// can only belong to one exclusive ManagedClusterSet.
+// TODO move this to the api repo once the exclusivity mode is added to the ManagedClusterSet spec.
+const ExclusiveClusterSetAnnotationKey = "cluster.open-cluster-management.io/experimental-exclusive"
+
+// IsExclusiveClusterSet returns true if the ManagedClusterSet is marked as exclusive.
+func IsExclusiveClusterSet(clusterSet *clusterv1beta2.ManagedClusterSet) bool {
+	if clusterSet == nil {
+		return false
+	}
+	return clusterSet.Annotations[ExclusiveClusterSetAnnotationKey] == "true"
+}
+
+// GetExclusiveClusterSetsOfCluster returns the exclusive ManagedClusterSets selecting the ManagedCluster,
+// sorted by name. The ManagedClusterSets being deleted are ignored.
+func GetExclusiveClusterSetsOfCluster(cluster *clusterv1.ManagedCluster,
+	clusterSetLister clusterlisterv1beta2.ManagedClusterSetLister) ([]*clusterv1beta2.ManagedClusterSet, error) {
+	clusterSets, err := clusterSetLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var exclusiveSets []*clusterv1beta2.ManagedClusterSet
+	for _, clusterSet := range clusterSets {
+		if !IsExclusiveClusterSet(clusterSet) || !clusterSet.DeletionTimestamp.IsZero() {
+			continue
+		}
+		selector, err := clustersdkv1beta2.BuildClusterSelector(clusterSet)
+		if err != nil {
+			return nil, err
+		}
+		if selector.Matches(labels.Set(cluster.Labels)) {
+			exclusiveSets = append(exclusiveSets, clusterSet)
+		}
+	}
+	sort.Slice(exclusiveSets, func(i, j int) bool {
+		return exclusiveSets[i].Name < exclusiveSets[j].Name
+	})
+	return exclusiveSets, nil
+}
@@ -16,6 +16,16 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 )
 
+// ClusterCSRThreshold is the max number of the csrs of a managed cluster, the registration agent halts creating
+// new csrs once the threshold is reached.
+// TODO(qiujian16) expose it if necessary in the future.
+const ClusterCSRThreshold = 10
+
+// JoinClusterClaimsAnnotationKey is the annotation the registration agent sets on the managed cluster with the
+// cluster claims of the managed cluster encoded as a json object, while the managed cluster is not accepted yet.
+// The claims in the status are only reported once the managed cluster is accepted.
+const JoinClusterClaimsAnnotationKey = "agent.open-cluster-management.io/join-cluster-claims"
+
 // Check whether a CSR is in terminal state
 func IsCSRInTerminalState(status *certificatesv1.CertificateSigningRequestStatus) bool {
 	for _, c := range status.Conditions {
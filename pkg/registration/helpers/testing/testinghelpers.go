@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"math/rand"
 	"net"
+	"net/url"
 	"os"
 	"time"
 
@@ -271,6 +272,9 @@ type CSRHolder struct {
 	Orgs         []string
 	Username     string
 	ReqBlockType string
+	DNSNames     []string
+	IPAddresses  []net.IP
+	URIs         []*url.URL
 }
 
 func NewCSR(holder CSRHolder) *certv1.CertificateSigningRequest {
@@ -284,9 +288,10 @@ func NewCSR(holder CSRHolder) *certv1.CertificateSigningRequest {
 			CommonName:   holder.CN,
 			Organization: holder.Orgs,
 		},
-		DNSNames:       []string{},
+		DNSNames:       holder.DNSNames,
 		EmailAddresses: []string{},
-		IPAddresses:    []net.IP{},
+		IPAddresses:    holder.IPAddresses,
+		URIs:           holder.URIs,
 	}, pk)
 	if err != nil {
 		panic(err)
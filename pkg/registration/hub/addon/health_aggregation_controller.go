@@ -0,0 +1,148 @@
+package addon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// ManagedClusterConditionAddOnsHealthy rolls up the availability of all the addons of a managed cluster.
+	// TODO move this to the api repo.
+	ManagedClusterConditionAddOnsHealthy = "AddonsHealthy"
+
+	ReasonAddOnsAvailable     = "AddOnsAvailable"
+	ReasonAddOnsDegraded      = "AddOnsDegraded"
+	ReasonAddOnsStatusUnknown = "AddOnsStatusUnknown"
+	ReasonNoAddOns            = "NoAddOns"
+)
+
+// addOnHealthAggregationController aggregates the available conditions of the ManagedClusterAddOns into the
+// AddonsHealthy condition of the ManagedCluster.
+type addOnHealthAggregationController struct {
+	patcher       patcher.Patcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus]
+	clusterLister clusterv1listers.ManagedClusterLister
+	addOnLister   addonlisterv1alpha1.ManagedClusterAddOnLister
+}
+
+// NewAddOnHealthAggregationController returns an instance of addOnHealthAggregationController
+func NewAddOnHealthAggregationController(
+	clusterClient clientset.Interface,
+	clusterInformer clusterv1informer.ManagedClusterInformer,
+	addOnInformers addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &addOnHealthAggregationController{
+		patcher: patcher.NewPatcher[
+			*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+			clusterClient.ClusterV1().ManagedClusters()),
+		clusterLister: clusterInformer.Lister(),
+		addOnLister:   addOnInformers.Lister(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespace, addOnInformers.Informer()).
+		WithSync(c.sync).
+		ToController("AddOnHealthAggregationController", recorder)
+}
+
+func (c *addOnHealthAggregationController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	clusterName := syncCtx.QueueKey()
+	if clusterName == factory.DefaultQueueKey {
+		return nil
+	}
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	// the addon status is not reported until the cluster is available
+	if meta.FindStatusCondition(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) == nil {
+		return nil
+	}
+
+	addOns, err := c.addOnLister.ManagedClusterAddOns(clusterName).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("unable to list addOns of cluster %q: %w", clusterName, err)
+	}
+
+	var total int
+	var unhealthy, unknown []string
+	for _, addOn := range addOns {
+		if !addOn.DeletionTimestamp.IsZero() {
+			continue
+		}
+		total++
+		switch getAddOnLabelValue(addOn) {
+		case addOnStatusUnhealthy:
+			unhealthy = append(unhealthy, addOn.Name)
+		case addOnStatusUnreachable:
+			unknown = append(unknown, addOn.Name)
+		}
+	}
+
+	newCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&newCluster.Status.Conditions, addOnsHealthyCondition(total, unhealthy, unknown))
+	_, err = c.patcher.PatchStatus(ctx, newCluster, newCluster.Status, cluster.Status)
+	return err
+}
+
+func addOnsHealthyCondition(total int, unhealthy, unknown []string) metav1.Condition {
+	condition := metav1.Condition{Type: ManagedClusterConditionAddOnsHealthy}
+	if total == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonNoAddOns
+		condition.Message = "No ManagedClusterAddOns are installed"
+		return condition
+	}
+
+	available := total - len(unhealthy) - len(unknown)
+	messages := []string{fmt.Sprintf("%d/%d ManagedClusterAddOns are available", available, total)}
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		messages = append(messages, fmt.Sprintf("%d unhealthy: %s", len(unhealthy), strings.Join(unhealthy, ", ")))
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		messages = append(messages, fmt.Sprintf("%d unknown: %s", len(unknown), strings.Join(unknown, ", ")))
+	}
+	condition.Message = strings.Join(messages, "; ")
+
+	switch {
+	case len(unhealthy) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonAddOnsDegraded
+	case len(unknown) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = ReasonAddOnsStatusUnknown
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonAddOnsAvailable
+	}
+	return condition
+}
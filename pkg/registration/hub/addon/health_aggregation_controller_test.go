@@ -0,0 +1,133 @@
+package addon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newAddOnWithAvailability(name string, status metav1.ConditionStatus) *addonv1alpha1.ManagedClusterAddOn {
+	addOn := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testinghelpers.TestManagedClusterName},
+	}
+	if len(status) > 0 {
+		addOn.Status.Conditions = []metav1.Condition{
+			{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: status},
+		}
+	}
+	return addOn
+}
+
+func TestAddOnHealthAggregation(t *testing.T) {
+	cases := []struct {
+		name              string
+		addOns            []runtime.Object
+		expectedCondition metav1.Condition
+	}{
+		{
+			name: "no addons",
+			expectedCondition: metav1.Condition{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonNoAddOns,
+				Message: "No ManagedClusterAddOns are installed",
+			},
+		},
+		{
+			name: "all addons are available",
+			addOns: []runtime.Object{
+				newAddOnWithAvailability("addon1", metav1.ConditionTrue),
+				newAddOnWithAvailability("addon2", metav1.ConditionTrue),
+			},
+			expectedCondition: metav1.Condition{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonAddOnsAvailable,
+				Message: "2/2 ManagedClusterAddOns are available",
+			},
+		},
+		{
+			name: "addons are degraded",
+			addOns: []runtime.Object{
+				newAddOnWithAvailability("addon1", metav1.ConditionTrue),
+				newAddOnWithAvailability("addon2", metav1.ConditionFalse),
+				newAddOnWithAvailability("addon3", ""),
+			},
+			expectedCondition: metav1.Condition{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonAddOnsDegraded,
+				Message: "1/3 ManagedClusterAddOns are available; 1 unhealthy: addon2; 1 unknown: addon3",
+			},
+		},
+		{
+			name: "addon status is unknown",
+			addOns: []runtime.Object{
+				newAddOnWithAvailability("addon1", metav1.ConditionTrue),
+				newAddOnWithAvailability("addon2", metav1.ConditionUnknown),
+			},
+			expectedCondition: metav1.Condition{
+				Status:  metav1.ConditionUnknown,
+				Reason:  ReasonAddOnsStatusUnknown,
+				Message: "1/2 ManagedClusterAddOns are available; 1 unknown: addon2",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cluster := testinghelpers.NewAvailableManagedCluster()
+			clusterClient := clusterfake.NewSimpleClientset(cluster)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			if err := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+				t.Fatal(err)
+			}
+
+			addOnClient := addonfake.NewSimpleClientset(c.addOns...)
+			addOnInformerFactory := addoninformers.NewSharedInformerFactory(addOnClient, time.Minute*10)
+			for _, addOn := range c.addOns {
+				if err := addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer().GetStore().Add(addOn); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &addOnHealthAggregationController{
+				patcher: patcher.NewPatcher[
+					*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+					clusterClient.ClusterV1().ManagedClusters()),
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				addOnLister:   addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Lister(),
+			}
+			syncCtx := testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName)
+			if err := ctrl.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			updated, err := clusterClient.ClusterV1().ManagedClusters().Get(
+				context.TODO(), testinghelpers.TestManagedClusterName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, ManagedClusterConditionAddOnsHealthy)
+			if condition == nil {
+				t.Fatalf("expected the AddonsHealthy condition, but got %v", updated.Status.Conditions)
+			}
+			if condition.Status != c.expectedCondition.Status || condition.Reason != c.expectedCondition.Reason ||
+				condition.Message != c.expectedCondition.Message {
+				t.Errorf("expected condition %v, but got %v", c.expectedCondition, *condition)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package autoaccept
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	kevents "k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	informerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	listerv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/registration/hub/clusterclaim"
+	"open-cluster-management.io/ocm/pkg/registration/hub/managedcluster"
+)
+
+// ClusterAcceptedByRuleAnnotationKey records the name of the auto accept rule which accepted the managed cluster.
+const ClusterAcceptedByRuleAnnotationKey = "open-cluster-management.io/automatically-accepted-by-rule"
+
+// autoAcceptController sets hubAcceptsClient of the joining managed clusters to true if their join cluster claims
+// match one of the auto accept rules. The claims are reported by the registration agent, so a cluster is only
+// accepted if its bootstrap csr is also requested by a member of the trusted groups. A cluster is only accepted
+// automatically when it joins for the first time, so the hub cluster admin is still able to deny it afterwards.
+// A cluster whose join cluster claims violate the cluster claim policy is not accepted automatically.
+type autoAcceptController struct {
+	clusterClient   clientset.Interface
+	clusterLister   listerv1.ManagedClusterLister
+	csrIndexer      cache.Indexer
+	rules           []Rule
+	groups          sets.Set[string]
+	claimPolicy     clusterclaim.Policy
+	mcEventRecorder kevents.EventRecorder
+}
+
+// NewAutoAcceptController creates a new auto accept controller. The csrInformer is the informer of either the v1
+// or the v1beta1 csrs.
+func NewAutoAcceptController(
+	clusterClient clientset.Interface,
+	clusterInformer informerv1.ManagedClusterInformer,
+	csrInformer cache.SharedIndexInformer,
+	rules []Rule,
+	groups []string,
+	claimPolicy clusterclaim.Policy,
+	recorder events.Recorder,
+	mcEventRecorder kevents.EventRecorder) factory.Controller {
+	c := &autoAcceptController{
+		clusterClient:   clusterClient,
+		clusterLister:   clusterInformer.Lister(),
+		csrIndexer:      csrInformer.GetIndexer(),
+		rules:           rules,
+		groups:          sets.New(groups...),
+		claimPolicy:     claimPolicy,
+		mcEventRecorder: mcEventRecorder,
+	}
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithInformersQueueKeysFunc(queue.QueueKeyByLabel(clusterv1.ClusterNameLabelKey), csrInformer).
+		WithSync(c.sync).
+		ToController("autoAcceptController", recorder)
+}
+
+func (c *autoAcceptController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	managedClusterName := syncCtx.QueueKey()
+	logger.V(4).Info("Reconciling ManagedCluster", "managedClusterName", managedClusterName)
+	managedCluster, err := c.clusterLister.Get(managedClusterName)
+	if errors.IsNotFound(err) {
+		// Spoke cluster not found, could have been deleted, do nothing.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !managedCluster.DeletionTimestamp.IsZero() || managedCluster.Spec.HubAcceptsClient {
+		return nil
+	}
+
+	// the cluster was accepted before, it is denied by the hub cluster admin afterwards.
+	if _, ok := managedCluster.Annotations[managedcluster.ClusterAcceptedAnnotationKey]; ok {
+		return nil
+	}
+	if meta.FindStatusCondition(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionHubAccepted) != nil {
+		return nil
+	}
+
+	claims, err := joinClaims(managedCluster)
+	if err != nil {
+		// the annotation is set by the registration agent, wait for it to be corrected.
+		logger.Info("Skip auto accepting the managed cluster", "managedClusterName", managedClusterName, "err", err)
+		return nil
+	}
+	if violations := c.claimPolicy.Violations(claimList(claims)); len(violations) > 0 {
+		logger.Info("Skip auto accepting the managed cluster whose join cluster claims violate the cluster claim policy",
+			"managedClusterName", managedClusterName, "violations", violations)
+		return nil
+	}
+	rule, ok := matchRule(c.rules, claims)
+	if !ok {
+		return nil
+	}
+
+	trusted, err := c.requestedByTrustedGroups(managedClusterName)
+	if err != nil {
+		return err
+	}
+	if !trusted {
+		logger.V(4).Info("No bootstrap csr of the managed cluster is requested by the auto accept groups",
+			"managedClusterName", managedClusterName)
+		return nil
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s","%s":"%s"}},"spec":{"hubAcceptsClient":true}}`,
+		managedcluster.ClusterAcceptedAnnotationKey, time.Now().Format(time.RFC3339), ClusterAcceptedByRuleAnnotationKey, rule.Name)
+	if _, err := c.clusterClient.ClusterV1().ManagedClusters().Patch(ctx, managedClusterName,
+		types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return err
+	}
+
+	cluster := managedCluster.DeepCopy()
+	cluster.SetNamespace(cluster.Name)
+	c.mcEventRecorder.Eventf(cluster, nil, corev1.EventTypeNormal, "ManagedClusterAutoAccepted", "AutoAccept",
+		"Managed cluster %s is accepted by the auto accept rule %s (%s)", managedClusterName, rule.Name, rule.Selector.String())
+	return nil
+}
+
+// requestedByTrustedGroups checks if any csr of the managed cluster is requested by a member of the auto accept groups.
+func (c *autoAcceptController) requestedByTrustedGroups(clusterName string) (bool, error) {
+	trusted := false
+	selector := labels.SelectorFromSet(labels.Set{clusterv1.ClusterNameLabelKey: clusterName})
+	err := cache.ListAll(c.csrIndexer, selector, func(obj interface{}) {
+		switch csr := obj.(type) {
+		case *certificatesv1.CertificateSigningRequest:
+			trusted = trusted || c.groups.HasAny(csr.Spec.Groups...)
+		case *certificatesv1beta1.CertificateSigningRequest:
+			trusted = trusted || c.groups.HasAny(csr.Spec.Groups...)
+		}
+	})
+	return trusted, err
+}
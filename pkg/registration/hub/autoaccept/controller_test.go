@@ -0,0 +1,244 @@
+package autoaccept
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	certv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterscheme "open-cluster-management.io/api/client/cluster/clientset/versioned/scheme"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	registrationhelpers "open-cluster-management.io/ocm/pkg/registration/helpers"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/registration/hub/clusterclaim"
+	"open-cluster-management.io/ocm/pkg/registration/hub/managedcluster"
+)
+
+const testBootstrapGroup = "system:serviceaccounts:open-cluster-management"
+
+func newClusterWithClaims(claims map[string]string) *clusterv1.ManagedCluster {
+	cluster := testinghelpers.NewManagedCluster()
+	data, err := json.Marshal(claims)
+	if err != nil {
+		panic(err)
+	}
+	cluster.Annotations = map[string]string{registrationhelpers.JoinClusterClaimsAnnotationKey: string(data)}
+	return cluster
+}
+
+func newBootstrapCSR(groups ...string) *certv1.CertificateSigningRequest {
+	csr := testinghelpers.NewCSR(testinghelpers.CSRHolder{
+		Name:   "bootstrap",
+		Labels: map[string]string{clusterv1.ClusterNameLabelKey: testinghelpers.TestManagedClusterName},
+	})
+	csr.Spec.Groups = groups
+	return csr
+}
+
+func TestParseRules(t *testing.T) {
+	cases := []struct {
+		name          string
+		rules         []string
+		expectedNames []string
+		expectedErr   bool
+	}{
+		{
+			name:          "named and unnamed rules",
+			rules:         []string{"vsphere-dev:platform=vsphere,env!=prod", "platform=aws"},
+			expectedNames: []string{"vsphere-dev", "rule-1"},
+		},
+		{
+			name:        "empty selector",
+			rules:       []string{"all:"},
+			expectedErr: true,
+		},
+		{
+			name:        "empty name",
+			rules:       []string{":platform=aws"},
+			expectedErr: true,
+		},
+		{
+			name:        "no positive requirement",
+			rules:       []string{"no-prod:env!=prod,!gpu"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid selector",
+			rules:       []string{"platform in (aws"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rules, err := ParseRules(c.rules)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if len(rules) != len(c.expectedNames) {
+				t.Fatalf("expected %d rules, but got %d", len(c.expectedNames), len(rules))
+			}
+			for i, rule := range rules {
+				if rule.Name != c.expectedNames[i] {
+					t.Errorf("expected rule name %q, but got %q", c.expectedNames[i], rule.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncAutoAccept(t *testing.T) {
+	rules, err := ParseRules([]string{"vsphere-dev:platform=vsphere,env!=prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deniedCluster := newClusterWithClaims(map[string]string{"platform": "vsphere", "env": "dev"})
+	deniedCluster.Status.Conditions = []metav1.Condition{
+		testinghelpers.NewManagedClusterCondition(clusterv1.ManagedClusterConditionHubAccepted,
+			"False", "HubClusterAdminDenied", "Denied by hub cluster admin", nil),
+	}
+
+	statusClaimsCluster := testinghelpers.NewManagedCluster()
+	statusClaimsCluster.Status.ClusterClaims = []clusterv1.ManagedClusterClaim{
+		{Name: "platform", Value: "vsphere"},
+		{Name: "env", Value: "dev"},
+	}
+
+	cases := []struct {
+		name            string
+		startingObjects []runtime.Object
+		csrs            []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:            "claims match the rule",
+			startingObjects: []runtime.Object{newClusterWithClaims(map[string]string{"platform": "vsphere", "env": "dev"})},
+			csrs:            []runtime.Object{newBootstrapCSR(testBootstrapGroup, "system:authenticated")},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				patch := actions[0].(clienttesting.PatchActionImpl).Patch
+				cluster := &clusterv1.ManagedCluster{}
+				if err := json.Unmarshal(patch, cluster); err != nil {
+					t.Fatal(err)
+				}
+				if !cluster.Spec.HubAcceptsClient {
+					t.Errorf("expected the cluster is accepted")
+				}
+				if cluster.Annotations[ClusterAcceptedByRuleAnnotationKey] != "vsphere-dev" {
+					t.Errorf("expected the matched rule is recorded, but got %v", cluster.Annotations)
+				}
+				if _, ok := cluster.Annotations[managedcluster.ClusterAcceptedAnnotationKey]; !ok {
+					t.Errorf("expected the accepted annotation, but got %v", cluster.Annotations)
+				}
+			},
+		},
+		{
+			name:            "claims do not match the rule",
+			startingObjects: []runtime.Object{newClusterWithClaims(map[string]string{"platform": "vsphere", "env": "prod"})},
+			csrs:            []runtime.Object{newBootstrapCSR(testBootstrapGroup)},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "only the status claims match the rule",
+			startingObjects: []runtime.Object{statusClaimsCluster},
+			csrs:            []runtime.Object{newBootstrapCSR(testBootstrapGroup)},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name: "join claims violate the cluster claim policy",
+			startingObjects: []runtime.Object{newClusterWithClaims(
+				map[string]string{"platform": "vsphere", "env": "dev", "owner": "a"})},
+			csrs:            []runtime.Object{newBootstrapCSR(testBootstrapGroup)},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "csr is not requested by the auto accept groups",
+			startingObjects: []runtime.Object{newClusterWithClaims(map[string]string{"platform": "vsphere", "env": "dev"})},
+			csrs:            []runtime.Object{newBootstrapCSR("system:authenticated")},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "no csr of the cluster",
+			startingObjects: []runtime.Object{newClusterWithClaims(map[string]string{"platform": "vsphere", "env": "dev"})},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "cluster is accepted",
+			startingObjects: []runtime.Object{testinghelpers.NewAcceptedManagedCluster()},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "cluster is denied by the hub cluster admin",
+			startingObjects: []runtime.Object{deniedCluster},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:            "cluster is deleting",
+			startingObjects: []runtime.Object{testinghelpers.NewDeletingManagedCluster()},
+			validateActions: testingcommon.AssertNoActions,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.startingObjects...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			clusterStore := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore()
+			for _, cluster := range c.startingObjects {
+				if err := clusterStore.Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), time.Minute*10)
+			csrStore := kubeInformerFactory.Certificates().V1().CertificateSigningRequests().Informer().GetStore()
+			for _, csr := range c.csrs {
+				if err := csrStore.Add(csr); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctx := context.TODO()
+			mcEventRecorder, err := helpers.NewEventRecorder(ctx, clusterscheme.Scheme, kubefake.NewSimpleClientset(), "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := &autoAcceptController{
+				clusterClient:   clusterClient,
+				clusterLister:   clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				csrIndexer:      kubeInformerFactory.Certificates().V1().CertificateSigningRequests().Informer().GetIndexer(),
+				rules:           rules,
+				groups:          sets.New(testBootstrapGroup),
+				claimPolicy:     clusterclaim.Policy{AllowedNames: []string{"platform", "env"}},
+				mcEventRecorder: mcEventRecorder,
+			}
+			syncErr := ctrl.sync(ctx, testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName))
+			if syncErr != nil {
+				t.Errorf("unexpected err: %v", syncErr)
+			}
+
+			c.validateActions(t, clusterClient.Actions())
+		})
+	}
+}
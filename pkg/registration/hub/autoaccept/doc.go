@@ -0,0 +1,3 @@
+// package autoaccept contains the hub-side controller accepting the joining managed clusters whose cluster claims
+// match the auto accept rules.
+package autoaccept
@@ -0,0 +1,95 @@
+package autoaccept
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
+)
+
+// Rule accepts the managed clusters whose cluster claims match the selector.
+type Rule struct {
+	Name     string
+	Selector labels.Selector
+}
+
+// ParseRules parses the auto accept rules. A rule is a label selector on the cluster claims, optionally prefixed
+// with the rule name and a colon, e.g. "vsphere-dev:platform=vsphere,env!=prod". A rule without a name is named
+// after its index. A rule must have at least one positive requirement, so it never matches a cluster without claims.
+func ParseRules(rules []string) ([]Rule, error) {
+	var parsed []Rule
+	for i, rule := range rules {
+		name := fmt.Sprintf("rule-%d", i)
+		selector := rule
+		if index := strings.Index(rule, ":"); index >= 0 {
+			name, selector = strings.TrimSpace(rule[:index]), rule[index+1:]
+		}
+		if len(name) == 0 {
+			return nil, fmt.Errorf("the name of the auto accept rule %q is empty", rule)
+		}
+		if len(strings.TrimSpace(selector)) == 0 {
+			return nil, fmt.Errorf("the selector of the auto accept rule %q is empty", rule)
+		}
+		s, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto accept rule %q: %v", rule, err)
+		}
+		if !hasPositiveRequirement(s) {
+			return nil, fmt.Errorf("the auto accept rule %q requires at least one claim to be present", rule)
+		}
+		parsed = append(parsed, Rule{Name: name, Selector: s})
+	}
+	return parsed, nil
+}
+
+func hasPositiveRequirement(selector labels.Selector) bool {
+	requirements, _ := selector.Requirements()
+	for _, r := range requirements {
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In, selection.Exists, selection.GreaterThan, selection.LessThan:
+			return true
+		}
+	}
+	return false
+}
+
+// joinClaims returns the cluster claims the registration agent reported in the join cluster claims annotation of
+// the managed cluster. The claims in the status are empty until the managed cluster is accepted.
+func joinClaims(cluster *clusterv1.ManagedCluster) (labels.Set, error) {
+	data, ok := cluster.Annotations[helpers.JoinClusterClaimsAnnotationKey]
+	if !ok {
+		return labels.Set{}, nil
+	}
+	claims := labels.Set{}
+	if err := json.Unmarshal([]byte(data), &claims); err != nil {
+		return nil, fmt.Errorf("invalid join cluster claims of the managed cluster %q: %v", cluster.Name, err)
+	}
+	return claims, nil
+}
+
+// claimList converts the claims to the cluster claims to be checked against the cluster claim policy.
+func claimList(claims labels.Set) []clusterv1.ManagedClusterClaim {
+	var list []clusterv1.ManagedClusterClaim
+	for name, value := range claims {
+		list = append(list, clusterv1.ManagedClusterClaim{Name: name, Value: value})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// matchRule returns the first rule matching the claims.
+func matchRule(rules []Rule, claims labels.Set) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.Selector.Matches(claims) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,142 @@
+package cabundle
+
+import (
+	"context"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// HubCABundleConfigMapName is the name of the configmap in each cluster namespace with the CA bundles of the
+	// hub, the agents trust the CA bundles when they connect to the hub.
+	HubCABundleConfigMapName = "hub-ca-bundle"
+	// CABundleKey is the key of the current CA bundle of the hub.
+	CABundleKey = "ca-bundle.crt"
+	// NextCABundleKey is the key of the CA bundle the hub rotates to, it is distributed ahead of the rotation so the
+	// agents trust the new CA before the hub apiserver serves with it.
+	NextCABundleKey = "next-ca-bundle.crt"
+)
+
+// caBundleController distributes the current and the next CA bundles of the hub in the source configmap to the
+// hub-ca-bundle configmap in each cluster namespace.
+type caBundleController struct {
+	kubeClient      kubernetes.Interface
+	clusterLister   clusterv1listers.ManagedClusterLister
+	configMapLister corev1listers.ConfigMapLister
+	sourceNamespace string
+	sourceName      string
+	eventRecorder   events.Recorder
+}
+
+// NewCABundleController creates a new controller distributing the CA bundles in the source configmap to the cluster
+// namespaces. The source configmap informer only watches the namespace of the source configmap, and the configmap
+// informer watches the configmaps labeled with the cluster name.
+func NewCABundleController(
+	kubeClient kubernetes.Interface,
+	clusterInformer clusterv1informer.ManagedClusterInformer,
+	sourceConfigMapInformer corev1informers.ConfigMapInformer,
+	configMapInformer corev1informers.ConfigMapInformer,
+	sourceNamespace, sourceName string,
+	recorder events.Recorder) factory.Controller {
+	c := &caBundleController{
+		kubeClient:      kubeClient,
+		clusterLister:   clusterInformer.Lister(),
+		configMapLister: sourceConfigMapInformer.Lister(),
+		sourceNamespace: sourceNamespace,
+		sourceName:      sourceName,
+		eventRecorder:   recorder.WithComponentSuffix("hub-ca-bundle-controller"),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			c.allClusters,
+			queue.FilterByNames(sourceName),
+			sourceConfigMapInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaNamespace,
+			queue.FilterByNames(HubCABundleConfigMapName),
+			configMapInformer.Informer()).
+		WithSync(c.sync).
+		ToController("HubCABundleController", recorder)
+}
+
+func (c *caBundleController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	clusterName := syncCtx.QueueKey()
+	if len(clusterName) == 0 {
+		return nil
+	}
+	logger.V(4).Info("Reconciling the hub CA bundle of ManagedCluster", "clusterName", clusterName)
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if errors.IsNotFound(err) {
+		// the configmap is deleted with the cluster namespace
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	source, err := c.configMapLister.ConfigMaps(c.sourceNamespace).Get(c.sourceName)
+	if errors.IsNotFound(err) {
+		logger.V(4).Info("The hub CA bundle configmap is not found", "namespace", c.sourceNamespace, "name", c.sourceName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{}
+	for _, key := range []string{CABundleKey, NextCABundleKey} {
+		if value, ok := source.Data[key]; ok {
+			data[key] = value
+		}
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clusterName,
+			Name:      HubCABundleConfigMapName,
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabelKey: clusterName,
+			},
+		},
+		Data: data,
+	}
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.kubeClient.CoreV1(), c.eventRecorder, configMap)
+	return err
+}
+
+// allClusters returns the names of all the clusters, the CA bundles of all the clusters are changed once the source
+// configmap is changed.
+func (c *caBundleController) allClusters(_ runtime.Object) []string {
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
@@ -0,0 +1,123 @@
+package cabundle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+const (
+	testSourceNamespace = "open-cluster-management-hub"
+	testSourceName      = "hub-ca"
+)
+
+func TestSync(t *testing.T) {
+	source := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testSourceNamespace, Name: testSourceName},
+		Data: map[string]string{
+			CABundleKey:     "current",
+			NextCABundleKey: "next",
+			"other":         "other",
+		},
+	}
+
+	cases := []struct {
+		name            string
+		clusters        []runtime.Object
+		configMaps      []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:       "no cluster",
+			configMaps: []runtime.Object{source},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:     "no source configmap",
+			clusters: []runtime.Object{testinghelpers.NewManagedCluster()},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:     "deleting cluster",
+			clusters: []runtime.Object{testinghelpers.NewDeletingManagedCluster()},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:       "distribute the ca bundles",
+			clusters:   []runtime.Object{testinghelpers.NewManagedCluster()},
+			configMaps: []runtime.Object{source},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "create")
+				configMap := actions[1].(clienttesting.CreateActionImpl).Object.(*corev1.ConfigMap)
+				if configMap.Namespace != testinghelpers.TestManagedClusterName || configMap.Name != HubCABundleConfigMapName {
+					t.Errorf("unexpected configmap %s/%s", configMap.Namespace, configMap.Name)
+				}
+				if configMap.Labels[clusterv1.ClusterNameLabelKey] != testinghelpers.TestManagedClusterName {
+					t.Errorf("expected the cluster name label, but got %v", configMap.Labels)
+				}
+				if len(configMap.Data) != 2 || configMap.Data[CABundleKey] != "current" || configMap.Data[NextCABundleKey] != "next" {
+					t.Errorf("unexpected data %v", configMap.Data)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.configMaps...)
+			kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*10)
+			configMapStore := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore()
+			for _, configMap := range c.configMaps {
+				if err := configMapStore.Add(configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			clusterClient := clusterfake.NewSimpleClientset(c.clusters...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			clusterStore := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore()
+			for _, cluster := range c.clusters {
+				if err := clusterStore.Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &caBundleController{
+				kubeClient:      kubeClient,
+				clusterLister:   clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				configMapLister: kubeInformerFactory.Core().V1().ConfigMaps().Lister(),
+				sourceNamespace: testSourceNamespace,
+				sourceName:      testSourceName,
+				eventRecorder:   eventstesting.NewTestingEventRecorder(t),
+			}
+
+			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName))
+			if syncErr != nil {
+				t.Errorf("unexpected err: %v", syncErr)
+			}
+
+			c.validateActions(t, kubeClient.Actions())
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package clusterclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	informerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	listerv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	ReasonClusterClaimsValid          = "ClusterClaimsValid"
+	ReasonClusterClaimsPolicyViolated = "ClusterClaimsPolicyViolated"
+)
+
+// clusterClaimPolicyController checks the cluster claims reported by the managed clusters against the policy, and
+// sets the ClusterClaimsValid condition of the managed clusters. The cluster claims in the status are owned by the
+// agent, so they are not changed by the hub, instead the custom claims of a cluster violating the policy are ignored
+// where they are read while the condition is false, see helpers.GetClusterClaims. The condition is removed if the
+// policy is disabled.
+type clusterClaimPolicyController struct {
+	patcher       patcher.Patcher[*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus]
+	clusterLister listerv1.ManagedClusterLister
+	policy        Policy
+	eventRecorder events.Recorder
+}
+
+// NewClusterClaimPolicyController creates a new cluster claim policy controller
+func NewClusterClaimPolicyController(
+	clusterClient clientset.Interface,
+	clusterInformer informerv1.ManagedClusterInformer,
+	policy Policy,
+	recorder events.Recorder) factory.Controller {
+	c := &clusterClaimPolicyController{
+		patcher: patcher.NewPatcher[
+			*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+			clusterClient.ClusterV1().ManagedClusters()),
+		clusterLister: clusterInformer.Lister(),
+		policy:        policy,
+		eventRecorder: recorder.WithComponentSuffix("cluster-claim-policy-controller"),
+	}
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterInformer.Informer()).
+		WithSync(c.sync).
+		ToController("ClusterClaimPolicyController", recorder)
+}
+
+func (c *clusterClaimPolicyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	managedClusterName := syncCtx.QueueKey()
+	logger.V(4).Info("Reconciling ManagedCluster", "managedClusterName", managedClusterName)
+	managedCluster, err := c.clusterLister.Get(managedClusterName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !managedCluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	newManagedCluster := managedCluster.DeepCopy()
+	if !c.policy.Enabled() {
+		meta.RemoveStatusCondition(&newManagedCluster.Status.Conditions, helpers.ManagedClusterConditionClusterClaimsValid)
+		_, err := c.patcher.PatchStatus(ctx, newManagedCluster, newManagedCluster.Status, managedCluster.Status)
+		return err
+	}
+
+	violations := c.policy.Violations(managedCluster.Status.ClusterClaims)
+	cond := clusterClaimsValidCondition(violations)
+	meta.SetStatusCondition(&newManagedCluster.Status.Conditions, cond)
+	updated, err := c.patcher.PatchStatus(ctx, newManagedCluster, newManagedCluster.Status, managedCluster.Status)
+	if err != nil {
+		return err
+	}
+	if updated && cond.Status == metav1.ConditionFalse {
+		c.eventRecorder.Warningf(ReasonClusterClaimsPolicyViolated,
+			"The custom cluster claims of managed cluster %s are ignored: %s", managedClusterName, cond.Message)
+	}
+	return nil
+}
+
+func clusterClaimsValidCondition(violations []string) metav1.Condition {
+	if len(violations) == 0 {
+		return metav1.Condition{
+			Type:    helpers.ManagedClusterConditionClusterClaimsValid,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonClusterClaimsValid,
+			Message: "The cluster claims conform to the cluster claim policy",
+		}
+	}
+
+	message := strings.Join(violations, "; ")
+	if n := len(violations); n > maxReportedViolations {
+		message = fmt.Sprintf("%s; and %d more violations", strings.Join(violations[:maxReportedViolations], "; "),
+			n-maxReportedViolations)
+	}
+	return metav1.Condition{
+		Type:    helpers.ManagedClusterConditionClusterClaimsValid,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonClusterClaimsPolicyViolated,
+		Message: message,
+	}
+}
@@ -0,0 +1,205 @@
+package clusterclaim
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newClusterWithClaims(claims ...clusterv1.ManagedClusterClaim) *clusterv1.ManagedCluster {
+	cluster := testinghelpers.NewAvailableManagedCluster()
+	cluster.Status.ClusterClaims = claims
+	return cluster
+}
+
+func TestSync(t *testing.T) {
+	policy := Policy{
+		MaxClaims:    2,
+		MaxClaimSize: 20,
+		AllowedNames: []string{"region", "team.example.com/*"},
+	}
+
+	invalidCluster := newClusterWithClaims(
+		clusterv1.ManagedClusterClaim{Name: "id.k8s.io", Value: "cluster1"},
+	)
+	meta.SetStatusCondition(&invalidCluster.Status.Conditions, clusterClaimsValidCondition([]string{"violation"}))
+
+	violatingCluster := newClusterWithClaims(
+		clusterv1.ManagedClusterClaim{Name: "id.k8s.io", Value: "cluster1"},
+		clusterv1.ManagedClusterClaim{Name: "owner", Value: "a"},
+	)
+	meta.SetStatusCondition(&violatingCluster.Status.Conditions,
+		clusterClaimsValidCondition([]string{"cluster claim \"owner\" is not allowed"}))
+
+	cases := []struct {
+		name            string
+		policy          *Policy
+		startingObjects []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name: "sync a deleted spoke cluster",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "valid cluster claims",
+			startingObjects: []runtime.Object{newClusterWithClaims(
+				clusterv1.ManagedClusterClaim{Name: "id.k8s.io", Value: "a-long-reserved-cluster-claim-value"},
+				clusterv1.ManagedClusterClaim{Name: "region", Value: "us-east-1"},
+				clusterv1.ManagedClusterClaim{Name: "team.example.com/a", Value: "a"},
+			)},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				assertCondition(t, actions, metav1.ConditionTrue, "")
+			},
+		},
+		{
+			name: "cluster claims violating the policy",
+			startingObjects: []runtime.Object{newClusterWithClaims(
+				clusterv1.ManagedClusterClaim{Name: "id.k8s.io", Value: "cluster1"},
+				clusterv1.ManagedClusterClaim{Name: "region", Value: "a-long-cluster-claim-value"},
+				clusterv1.ManagedClusterClaim{Name: "team.example.com/a", Value: "a"},
+				clusterv1.ManagedClusterClaim{Name: "owner", Value: "a"},
+			)},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				managedCluster := assertCondition(t, actions, metav1.ConditionFalse,
+					"3 custom cluster claims exceed the limit 2; "+
+						"cluster claim \"region\" has 32 bytes, exceeding the limit 20; "+
+						"cluster claim \"owner\" is not allowed")
+				if managedCluster.Status.ClusterClaims != nil {
+					t.Errorf("expected the cluster claims are not changed, but got %v", managedCluster.Status.ClusterClaims)
+				}
+			},
+		},
+		{
+			name:            "cluster claims violating the policy reported again",
+			startingObjects: []runtime.Object{violatingCluster},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:            "invalid custom cluster claims removed by the agent",
+			startingObjects: []runtime.Object{invalidCluster},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				assertCondition(t, actions, metav1.ConditionTrue, "")
+			},
+		},
+		{
+			name:            "policy disabled",
+			policy:          &Policy{},
+			startingObjects: []runtime.Object{invalidCluster},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				managedCluster := &clusterv1.ManagedCluster{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, managedCluster); err != nil {
+					t.Fatal(err)
+				}
+				if meta.FindStatusCondition(managedCluster.Status.Conditions,
+					helpers.ManagedClusterConditionClusterClaimsValid) != nil {
+					t.Errorf("expected the condition is removed, but got %v", managedCluster.Status.Conditions)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := policy
+			if c.policy != nil {
+				p = *c.policy
+			}
+			clusterClient := clusterfake.NewSimpleClientset(c.startingObjects...)
+			clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, time.Minute*10)
+			clusterStore := clusterInformerFactory.Cluster().V1().ManagedClusters().Informer().GetStore()
+			for _, cluster := range c.startingObjects {
+				if err := clusterStore.Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := clusterClaimPolicyController{
+				patcher: patcher.NewPatcher[
+					*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+					clusterClient.ClusterV1().ManagedClusters()),
+				clusterLister: clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				policy:        p,
+				eventRecorder: eventstesting.NewTestingEventRecorder(t),
+			}
+			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testinghelpers.TestManagedClusterName))
+			if syncErr != nil {
+				t.Errorf("unexpected err: %v", syncErr)
+			}
+
+			c.validateActions(t, clusterClient.Actions())
+		})
+	}
+}
+
+func assertCondition(t *testing.T, actions []clienttesting.Action, status metav1.ConditionStatus,
+	message string) *clusterv1.ManagedCluster {
+	testingcommon.AssertActions(t, actions, "patch")
+	managedCluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, managedCluster); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(managedCluster.Status.Conditions, helpers.ManagedClusterConditionClusterClaimsValid)
+	if cond == nil || cond.Status != status {
+		t.Fatalf("expected condition %s to be %s, but got %v", helpers.ManagedClusterConditionClusterClaimsValid, status, cond)
+	}
+	if len(message) > 0 && cond.Message != message {
+		t.Errorf("expected message %q, but got %q", message, cond.Message)
+	}
+	return managedCluster
+}
+
+func TestClusterClaimsValidCondition(t *testing.T) {
+	var violations []string
+	for i := 0; i < maxReportedViolations+2; i++ {
+		violations = append(violations, "violation")
+	}
+	cond := clusterClaimsValidCondition(violations)
+	if cond.Status != metav1.ConditionFalse || !strings.HasSuffix(cond.Message, "; and 2 more violations") {
+		t.Errorf("unexpected condition %v", cond)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		policy      Policy
+		expectedErr bool
+	}{
+		{name: "empty policy"},
+		{name: "valid policy", policy: Policy{MaxClaims: 10, MaxClaimSize: 1024, AllowedNames: []string{"region", "team/*"}}},
+		{name: "negative max claims", policy: Policy{MaxClaims: -1}, expectedErr: true},
+		{name: "negative max claim size", policy: Policy{MaxClaimSize: -1}, expectedErr: true},
+		{name: "wildcard allowed name", policy: Policy{AllowedNames: []string{"*"}}, expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.policy.Validate()
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package clusterclaim
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+)
+
+// maxReportedViolations is the max number of the violations listed in the condition message.
+const maxReportedViolations = 10
+
+// Policy restricts the custom cluster claims a managed cluster may report. The reserved cluster claims are always
+// allowed. A zero limit or an empty allow-list means no restriction.
+type Policy struct {
+	// MaxClaims is the max number of the custom cluster claims.
+	MaxClaims int
+	// MaxClaimSize is the max size in bytes of the name and the value of a custom cluster claim.
+	MaxClaimSize int
+	// AllowedNames are the names of the custom cluster claims allowed, a name ending with * matches the names
+	// with the prefix.
+	AllowedNames []string
+}
+
+// Enabled returns true if the policy restricts the cluster claims.
+func (p Policy) Enabled() bool {
+	return p.MaxClaims > 0 || p.MaxClaimSize > 0 || len(p.AllowedNames) > 0
+}
+
+// Validate returns an error if the policy is invalid.
+func (p Policy) Validate() error {
+	if p.MaxClaims < 0 {
+		return fmt.Errorf("the max number of cluster claims %d is negative", p.MaxClaims)
+	}
+	if p.MaxClaimSize < 0 {
+		return fmt.Errorf("the max size of cluster claims %d is negative", p.MaxClaimSize)
+	}
+	for _, name := range p.AllowedNames {
+		if len(strings.TrimSuffix(name, "*")) == 0 {
+			return fmt.Errorf("invalid cluster claim name %q in the allow-list", name)
+		}
+	}
+	return nil
+}
+
+// Violations returns the violations of the policy by the cluster claims.
+func (p Policy) Violations(claims []clusterv1.ManagedClusterClaim) []string {
+	reservedClaimNames := sets.New[string](clusterv1alpha1.ReservedClusterClaimNames[:]...)
+
+	var violations []string
+	var customClaims int
+	for _, claim := range claims {
+		if reservedClaimNames.Has(claim.Name) {
+			continue
+		}
+		customClaims++
+		if !p.allowed(claim.Name) {
+			violations = append(violations, fmt.Sprintf("cluster claim %q is not allowed", claim.Name))
+			continue
+		}
+		if size := len(claim.Name) + len(claim.Value); p.MaxClaimSize > 0 && size > p.MaxClaimSize {
+			violations = append(violations, fmt.Sprintf("cluster claim %q has %d bytes, exceeding the limit %d",
+				claim.Name, size, p.MaxClaimSize))
+		}
+	}
+	if p.MaxClaims > 0 && customClaims > p.MaxClaims {
+		violations = append([]string{fmt.Sprintf("%d custom cluster claims exceed the limit %d",
+			customClaims, p.MaxClaims)}, violations...)
+	}
+	return violations
+}
+
+func (p Policy) allowed(name string) bool {
+	if len(p.AllowedNames) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedNames {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(name, prefix) {
+			return true
+		}
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
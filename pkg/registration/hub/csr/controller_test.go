@@ -2,6 +2,8 @@ package csr
 
 import (
 	"context"
+	"net"
+	"net/url"
 	"testing"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/klog/v2/ktesting"
@@ -209,10 +212,11 @@ func TestSync(t *testing.T) {
 						eventRecorder: recorder,
 						approvalUsers: sets.Set[string]{},
 					},
-					NewCSRRenewalReconciler(kubeClient, recorder),
+					NewCSRRenewalReconciler(kubeClient, SANPolicy{}, recorder),
 					NewCSRBootstrapReconciler(
 						kubeClient,
 						c.approvalUsers,
+						SANPolicy{},
 						recorder,
 					),
 				},
@@ -229,6 +233,19 @@ func TestSync(t *testing.T) {
 
 func TestIsSpokeClusterClientCertRenewal(t *testing.T) {
 	invalidSignerName := "invalidsigner"
+	withSANs := func(dnsNames []string, ipAddresses []net.IP, uris ...string) testinghelpers.CSRHolder {
+		csr := validCSR
+		csr.DNSNames = dnsNames
+		csr.IPAddresses = ipAddresses
+		for _, uri := range uris {
+			u, err := url.Parse(uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csr.URIs = append(csr.URIs, u)
+		}
+		return csr
+	}
 
 	cases := []struct {
 		name        string
@@ -308,12 +325,75 @@ func TestIsSpokeClusterClientCertRenewal(t *testing.T) {
 			clusterName: "managedcluster1",
 			commonName:  validCSR.CN,
 		},
+		{
+			name:      "a csr with a DNS SAN",
+			csr:       withSANs([]string{"managedcluster1.example.com"}, nil),
+			isRenewal: false,
+		},
+		{
+			name:        "a csr with an allowed IP SAN",
+			csr:         withSANs(nil, []net.IP{net.ParseIP("10.0.0.1")}),
+			isRenewal:   true,
+			clusterName: "managedcluster1",
+			commonName:  validCSR.CN,
+		},
+		{
+			name:      "a csr with an IP SAN not allowed",
+			csr:       withSANs(nil, []net.IP{net.ParseIP("10.1.0.1")}),
+			isRenewal: false,
+		},
+		{
+			name:        "a csr with the SPIFFE ID of the cluster",
+			csr:         withSANs(nil, nil, "spiffe://example.org/open-cluster-management/cluster/managedcluster1"),
+			isRenewal:   true,
+			clusterName: "managedcluster1",
+			commonName:  validCSR.CN,
+		},
+		{
+			name:      "a csr with the SPIFFE ID in another trust domain",
+			csr:       withSANs(nil, nil, "spiffe://example.com/open-cluster-management/cluster/managedcluster1"),
+			isRenewal: false,
+		},
+		{
+			name:      "a csr with the SPIFFE ID of another cluster",
+			csr:       withSANs(nil, nil, "spiffe://example.org/open-cluster-management/cluster/managedcluster2"),
+			isRenewal: false,
+		},
+		{
+			name: "a csr with multiple SPIFFE IDs",
+			csr: withSANs(nil, nil, "spiffe://example.org/open-cluster-management/cluster/managedcluster1",
+				"spiffe://example.org/open-cluster-management/cluster/managedcluster1/agent"),
+			isRenewal: false,
+		},
+		{
+			name: "a csr with the SPIFFE ID and an allowed URI SAN",
+			csr: withSANs(nil, nil, "spiffe://example.org/open-cluster-management/cluster/managedcluster1",
+				"https://agents.example.org/managedcluster1/registration"),
+			isRenewal:   true,
+			clusterName: "managedcluster1",
+			commonName:  validCSR.CN,
+		},
+		{
+			name:      "a csr with the URI SAN of another cluster",
+			csr:       withSANs(nil, nil, "https://agents.example.org/managedcluster2/registration"),
+			isRenewal: false,
+		},
+		{
+			name:      "a csr with a URI SAN matching the prefix partially",
+			csr:       withSANs(nil, nil, "https://agents.example.org/managedcluster1-other"),
+			isRenewal: false,
+		},
+	}
+
+	sanPolicy, err := NewSANPolicy("example.org", []string{"10.0.0.0/24"}, []string{"https://agents.example.org/{cluster}"})
+	if err != nil {
+		t.Fatal(err)
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			logger, _ := ktesting.NewTestContext(t)
-			isRenewal, clusterName, commonName := validateCSR(logger, newCSRInfo(logger, testinghelpers.NewCSR(c.csr)))
+			isRenewal, clusterName, commonName := validateCSR(logger, newCSRInfo(logger, testinghelpers.NewCSR(c.csr)), sanPolicy)
 			if isRenewal != c.isRenewal {
 				t.Errorf("expected %t, but failed", c.isRenewal)
 			}
@@ -326,3 +406,178 @@ func TestIsSpokeClusterClientCertRenewal(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSANPolicy(t *testing.T) {
+	cases := []struct {
+		name        string
+		cidrs       []string
+		uriPrefixes []string
+		expectedErr string
+	}{
+		{
+			name: "empty policy",
+		},
+		{
+			name:        "valid policy",
+			cidrs:       []string{"10.0.0.0/24", "fd00::/64"},
+			uriPrefixes: []string{"https://agents.example.org/{cluster}/"},
+		},
+		{
+			name:        "invalid cidr",
+			cidrs:       []string{"10.0.0.1"},
+			expectedErr: "invalid allowed IP SAN network \"10.0.0.1\": invalid CIDR address: 10.0.0.1",
+		},
+		{
+			name:        "relative uri prefix",
+			uriPrefixes: []string{"/{cluster}/"},
+			expectedErr: "invalid allowed URI SAN prefix \"/{cluster}/\": it must be an absolute URI with a host",
+		},
+		{
+			name:        "spiffe uri prefix",
+			uriPrefixes: []string{"spiffe://example.org/"},
+			expectedErr: "invalid allowed URI SAN prefix \"spiffe://example.org/\": the SPIFFE ID is allowed by the trust domain",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewSANPolicy("", c.cidrs, c.uriPrefixes)
+			testingcommon.AssertError(t, err, c.expectedErr)
+		})
+	}
+}
+
+func TestGroupPolicyReconcile(t *testing.T) {
+	resourceAttributes := authorizationv1.ResourceAttributes{
+		Verb:        "approve",
+		Group:       "register.open-cluster-management.io",
+		Resource:    "managedclusters",
+		Subresource: "clientcertificates",
+	}
+
+	cases := []struct {
+		name          string
+		groups        []string
+		allowed       bool
+		expectedState reconcileState
+		expectedSAR   bool
+		approved      bool
+	}{
+		{
+			name:          "requester not in the approval groups",
+			groups:        []string{"system:authenticated"},
+			allowed:       true,
+			expectedState: reconcileContinue,
+		},
+		{
+			name:          "requester in the approval groups is not allowed",
+			groups:        []string{"system:authenticated", "cluster-bootstrappers"},
+			expectedState: reconcileContinue,
+			expectedSAR:   true,
+		},
+		{
+			name:          "requester in the approval groups is allowed",
+			groups:        []string{"system:authenticated", "cluster-bootstrappers"},
+			allowed:       true,
+			expectedState: reconcileStop,
+			expectedSAR:   true,
+			approved:      true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeClient.PrependReactor(
+				"create",
+				"subjectaccessreviews",
+				func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					sar := action.(clienttesting.CreateActionImpl).Object.(*authorizationv1.SubjectAccessReview)
+					expected := resourceAttributes
+					expected.Name = "managedcluster1"
+					if *sar.Spec.ResourceAttributes != expected {
+						t.Errorf("expected resource attributes %v, but got %v", expected, *sar.Spec.ResourceAttributes)
+					}
+					return true, &authorizationv1.SubjectAccessReview{
+						Status: authorizationv1.SubjectAccessReviewStatus{
+							Allowed: c.allowed,
+						},
+					}, nil
+				},
+			)
+
+			logger, ctx := ktesting.NewTestContext(t)
+			csr := testinghelpers.NewCSR(validCSR)
+			csr.Spec.Username = "bootstrapper"
+			csr.Spec.Groups = c.groups
+
+			approved := false
+			reconciler := NewCSRGroupPolicyReconciler(kubeClient, []string{"cluster-bootstrappers"}, resourceAttributes, SANPolicy{},
+				eventstesting.NewTestingEventRecorder(t))
+			state, err := reconciler.Reconcile(ctx, newCSRInfo(logger, csr), func(kubernetes.Interface) error {
+				approved = true
+				return nil
+			})
+			if err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+			if state != c.expectedState {
+				t.Errorf("expected state %v, but got %v", c.expectedState, state)
+			}
+			if approved != c.approved {
+				t.Errorf("expected approved %v, but got %v", c.approved, approved)
+			}
+			if c.expectedSAR {
+				testingcommon.AssertActions(t, kubeClient.Actions(), "create")
+			} else {
+				testingcommon.AssertNoActions(t, kubeClient.Actions())
+			}
+		})
+	}
+}
+
+func TestParseResourceAttributes(t *testing.T) {
+	cases := []struct {
+		name        string
+		value       string
+		expected    authorizationv1.ResourceAttributes
+		expectedErr bool
+	}{
+		{
+			name:  "resource with subresource",
+			value: "approve:register.open-cluster-management.io/managedclusters/clientcertificates",
+			expected: authorizationv1.ResourceAttributes{
+				Verb:        "approve",
+				Group:       "register.open-cluster-management.io",
+				Resource:    "managedclusters",
+				Subresource: "clientcertificates",
+			},
+		},
+		{
+			name:     "core resource",
+			value:    "create:/secrets",
+			expected: authorizationv1.ResourceAttributes{Verb: "create", Resource: "secrets"},
+		},
+		{
+			name:        "no verb",
+			value:       "register.open-cluster-management.io/managedclusters",
+			expectedErr: true,
+		},
+		{
+			name:        "no resource",
+			value:       "approve:register.open-cluster-management.io",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			attributes, err := ParseResourceAttributes(c.value)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if attributes != c.expected {
+				t.Errorf("expected %v, but got %v", c.expected, attributes)
+			}
+		})
+	}
+}
@@ -47,12 +47,14 @@ type Reconciler interface {
 
 type csrRenewalReconciler struct {
 	kubeClient    kubernetes.Interface
+	sanPolicy     SANPolicy
 	eventRecorder events.Recorder
 }
 
-func NewCSRRenewalReconciler(kubeClient kubernetes.Interface, recorder events.Recorder) Reconciler {
+func NewCSRRenewalReconciler(kubeClient kubernetes.Interface, sanPolicy SANPolicy, recorder events.Recorder) Reconciler {
 	return &csrRenewalReconciler{
 		kubeClient:    kubeClient,
+		sanPolicy:     sanPolicy,
 		eventRecorder: recorder.WithComponentSuffix("csr-approving-controller"),
 	}
 }
@@ -60,7 +62,7 @@ func NewCSRRenewalReconciler(kubeClient kubernetes.Interface, recorder events.Re
 func (r *csrRenewalReconciler) Reconcile(ctx context.Context, csr csrInfo, approveCSR approveCSRFunc) (reconcileState, error) {
 	logger := klog.FromContext(ctx)
 	// Check whether current csr is a valid spoker cluster csr.
-	valid, _, commonName := validateCSR(logger, csr)
+	valid, _, commonName := validateCSR(logger, csr, r.sanPolicy)
 	if !valid {
 		logger.V(4).Info("CSR was not recognized", "csrName", csr.name)
 		return reconcileStop, nil
@@ -92,15 +94,18 @@ func (r *csrRenewalReconciler) Reconcile(ctx context.Context, csr csrInfo, appro
 type csrBootstrapReconciler struct {
 	kubeClient    kubernetes.Interface
 	approvalUsers sets.Set[string]
+	sanPolicy     SANPolicy
 	eventRecorder events.Recorder
 }
 
 func NewCSRBootstrapReconciler(kubeClient kubernetes.Interface,
 	approvalUsers []string,
+	sanPolicy SANPolicy,
 	recorder events.Recorder) Reconciler {
 	return &csrBootstrapReconciler{
 		kubeClient:    kubeClient,
 		approvalUsers: sets.New(approvalUsers...),
+		sanPolicy:     sanPolicy,
 		eventRecorder: recorder.WithComponentSuffix("csr-approving-controller"),
 	}
 }
@@ -108,7 +113,7 @@ func NewCSRBootstrapReconciler(kubeClient kubernetes.Interface,
 func (b *csrBootstrapReconciler) Reconcile(ctx context.Context, csr csrInfo, approveCSR approveCSRFunc) (reconcileState, error) {
 	logger := klog.FromContext(ctx)
 	// Check whether current csr is a valid spoker cluster csr.
-	valid, clusterName, _ := validateCSR(logger, csr)
+	valid, clusterName, _ := validateCSR(logger, csr, b.sanPolicy)
 	if !valid {
 		logger.V(4).Info("CSR was not recognized", "csrName", csr.name)
 		return reconcileStop, nil
@@ -127,10 +132,95 @@ func (b *csrBootstrapReconciler) Reconcile(ctx context.Context, csr csrInfo, app
 	return reconcileStop, nil
 }
 
+type csrGroupPolicyReconciler struct {
+	kubeClient         kubernetes.Interface
+	approvalGroups     sets.Set[string]
+	resourceAttributes authorizationv1.ResourceAttributes
+	sanPolicy          SANPolicy
+	eventRecorder      events.Recorder
+}
+
+// NewCSRGroupPolicyReconciler returns a reconciler to approve the csr if the requester belongs to one of the approval
+// groups and passes the SubjectAccessReview against the resource attributes, the name of the resource attributes is
+// set to the cluster name, so the approval rights can be delegated per cluster with RBAC.
+func NewCSRGroupPolicyReconciler(kubeClient kubernetes.Interface,
+	approvalGroups []string,
+	resourceAttributes authorizationv1.ResourceAttributes,
+	sanPolicy SANPolicy,
+	recorder events.Recorder) Reconciler {
+	return &csrGroupPolicyReconciler{
+		kubeClient:         kubeClient,
+		approvalGroups:     sets.New(approvalGroups...),
+		resourceAttributes: resourceAttributes,
+		sanPolicy:          sanPolicy,
+		eventRecorder:      recorder.WithComponentSuffix("csr-approving-controller"),
+	}
+}
+
+func (g *csrGroupPolicyReconciler) Reconcile(ctx context.Context, csr csrInfo, approveCSR approveCSRFunc) (reconcileState, error) {
+	logger := klog.FromContext(ctx)
+	// Check whether current csr is a valid spoker cluster csr.
+	valid, clusterName, _ := validateCSR(logger, csr, g.sanPolicy)
+	if !valid {
+		logger.V(4).Info("CSR was not recognized", "csrName", csr.name)
+		return reconcileStop, nil
+	}
+
+	// Check whether the requester belongs to one of the approval groups.
+	if !g.approvalGroups.HasAny(csr.groups...) {
+		return reconcileContinue, nil
+	}
+
+	resourceAttributes := g.resourceAttributes
+	resourceAttributes.Name = clusterName
+	allowed, err := subjectAccessReview(ctx, g.kubeClient, csr, &resourceAttributes)
+	if err != nil {
+		return reconcileContinue, err
+	}
+	if !allowed {
+		logger.V(4).Info("Managed cluster csr cannot be auto approved due to subject access review not approved",
+			"csrName", csr.name, "verb", resourceAttributes.Verb, "resource", resourceAttributes.Resource)
+		return reconcileContinue, nil
+	}
+
+	if err := approveCSR(g.kubeClient); err != nil {
+		return reconcileContinue, err
+	}
+
+	g.eventRecorder.Eventf("ManagedClusterAutoApproved", "managed cluster %q is auto approved by the group policy.", clusterName)
+	return reconcileStop, nil
+}
+
+// ParseResourceAttributes parses the resource attributes of the SubjectAccessReview in the format
+// <verb>:<group>/<resource>[/<subresource>], the group is empty for the core group, e.g. create:/secrets.
+func ParseResourceAttributes(value string) (authorizationv1.ResourceAttributes, error) {
+	verb, resource, found := strings.Cut(value, ":")
+	if !found || len(verb) == 0 {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf(
+			"invalid resource attributes %q, the format is <verb>:<group>/<resource>[/<subresource>]", value)
+	}
+	parts := strings.Split(resource, "/")
+	if len(parts) < 2 || len(parts) > 3 || len(parts[1]) == 0 || (len(parts) == 3 && len(parts[2]) == 0) {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf(
+			"invalid resource attributes %q, the format is <verb>:<group>/<resource>[/<subresource>]", value)
+	}
+
+	attributes := authorizationv1.ResourceAttributes{
+		Verb:     verb,
+		Group:    parts[0],
+		Resource: parts[1],
+	}
+	if len(parts) == 3 {
+		attributes.Subresource = parts[2]
+	}
+	return attributes, nil
+}
+
 // To validate a managed cluster csr, we check
 // 1. if the signer name in csr request is valid.
 // 2. if organization field and commonName field in csr request is valid.
-func validateCSR(logger klog.Logger, csr csrInfo) (bool, string, string) {
+// 3. if the SANs of the csr request are allowed by the SAN policy.
+func validateCSR(logger klog.Logger, csr csrInfo, sanPolicy SANPolicy) (bool, string, string) {
 	spokeClusterName, existed := csr.labels[clusterv1.ClusterNameLabelKey]
 	if !existed {
 		return false, "", ""
@@ -169,24 +259,35 @@ func validateCSR(logger klog.Logger, csr csrInfo) (bool, string, string) {
 		return false, "", ""
 	}
 
+	if err := sanPolicy.validate(spokeClusterName, x509cr); err != nil {
+		logger.V(4).Info("CSR was not recognized", "csrName", csr.name, "err", err)
+		return false, "", ""
+	}
+
 	return true, spokeClusterName, x509cr.Subject.CommonName
 }
 
 // Using SubjectAccessReview API to check whether a spoke agent has been authorized to renew its csr,
 // a spoke agent is authorized after its spoke cluster is accepted by hub cluster admin.
 func authorize(ctx context.Context, kubeClient kubernetes.Interface, csr csrInfo) (bool, error) {
+	return subjectAccessReview(ctx, kubeClient, csr, &authorizationv1.ResourceAttributes{
+		Group:       "register.open-cluster-management.io",
+		Resource:    "managedclusters",
+		Verb:        "renew",
+		Subresource: "clientcertificates",
+	})
+}
+
+// subjectAccessReview checks whether the requester of the csr is allowed to access the resource.
+func subjectAccessReview(ctx context.Context, kubeClient kubernetes.Interface, csr csrInfo,
+	resourceAttributes *authorizationv1.ResourceAttributes) (bool, error) {
 	sar := &authorizationv1.SubjectAccessReview{
 		Spec: authorizationv1.SubjectAccessReviewSpec{
-			User:   csr.username,
-			UID:    csr.uid,
-			Groups: csr.groups,
-			Extra:  csr.extra,
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Group:       "register.open-cluster-management.io",
-				Resource:    "managedclusters",
-				Verb:        "renew",
-				Subresource: "clientcertificates",
-			},
+			User:               csr.username,
+			UID:                csr.uid,
+			Groups:             csr.groups,
+			Extra:              csr.extra,
+			ResourceAttributes: resourceAttributes,
 		},
 	}
 
@@ -0,0 +1,109 @@
+package csr
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"open-cluster-management.io/ocm/pkg/registration/hub/user"
+)
+
+// ClusterNamePlaceholder is replaced by the name of the managed cluster in the allowed URI prefixes, so a prefix is
+// able to allow the URIs of each cluster only, e.g. https://agents.example.org/{cluster}/.
+const ClusterNamePlaceholder = "{cluster}"
+
+// SANPolicy is the policy of the SANs allowed in the csrs of the managed clusters besides the subject. The DNS and
+// the email SANs are never allowed.
+type SANPolicy struct {
+	// TrustDomain is the trust domain of the SPIFFE ID of the managed cluster allowed as a URI SAN, no SPIFFE ID is
+	// allowed if it is empty.
+	TrustDomain string
+	// AllowedIPNets are the networks of the IP SANs allowed, no IP SAN is allowed if it is empty.
+	AllowedIPNets []*net.IPNet
+	// AllowedURIPrefixes are the prefixes of the URI SANs allowed besides the SPIFFE ID of the managed cluster,
+	// ClusterNamePlaceholder in a prefix is replaced by the cluster name.
+	AllowedURIPrefixes []string
+}
+
+// NewSANPolicy returns the SANPolicy with the allowed networks in CIDR notation and the allowed URI prefixes, an
+// error is returned if a network or a prefix is invalid.
+func NewSANPolicy(trustDomain string, allowedCIDRs, allowedURIPrefixes []string) (SANPolicy, error) {
+	policy := SANPolicy{TrustDomain: trustDomain}
+	for _, cidr := range allowedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return SANPolicy{}, fmt.Errorf("invalid allowed IP SAN network %q: %w", cidr, err)
+		}
+		policy.AllowedIPNets = append(policy.AllowedIPNets, ipNet)
+	}
+	for _, prefix := range allowedURIPrefixes {
+		u, err := url.Parse(strings.ReplaceAll(prefix, ClusterNamePlaceholder, "cluster"))
+		if err != nil {
+			return SANPolicy{}, fmt.Errorf("invalid allowed URI SAN prefix %q: %w", prefix, err)
+		}
+		if !u.IsAbs() || len(u.Host) == 0 {
+			return SANPolicy{}, fmt.Errorf("invalid allowed URI SAN prefix %q: it must be an absolute URI with a host", prefix)
+		}
+		if u.Scheme == user.SPIFFEScheme {
+			return SANPolicy{}, fmt.Errorf("invalid allowed URI SAN prefix %q: the SPIFFE ID is allowed by the trust domain", prefix)
+		}
+		policy.AllowedURIPrefixes = append(policy.AllowedURIPrefixes, prefix)
+	}
+	return policy, nil
+}
+
+// validate checks if the SANs of the csr request of the managed cluster are allowed by the policy.
+func (p SANPolicy) validate(clusterName string, x509cr *x509.CertificateRequest) error {
+	if len(x509cr.DNSNames) > 0 || len(x509cr.EmailAddresses) > 0 {
+		return fmt.Errorf("DNS and email SANs are not allowed")
+	}
+
+	for _, ip := range x509cr.IPAddresses {
+		if !p.ipAllowed(ip) {
+			return fmt.Errorf("IP SAN %q is not in the allowed networks", ip.String())
+		}
+	}
+
+	var spiffeIDs int
+	for _, uri := range x509cr.URIs {
+		if uri.Scheme == user.SPIFFEScheme {
+			// an X509-SVID has only one SPIFFE ID, it must identify the managed cluster
+			spiffeIDs++
+			if spiffeIDs > 1 {
+				return fmt.Errorf("only one SPIFFE ID SAN is allowed")
+			}
+			if err := user.ValidateClusterSPIFFEID(p.TrustDomain, clusterName, uri); err != nil {
+				return err
+			}
+			continue
+		}
+		if !p.uriAllowed(clusterName, uri) {
+			return fmt.Errorf("URI SAN %q does not have an allowed prefix", uri.String())
+		}
+	}
+	return nil
+}
+
+func (p SANPolicy) ipAllowed(ip net.IP) bool {
+	for _, ipNet := range p.AllowedIPNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// uriAllowed returns true if the URI has one of the allowed prefixes, the prefix must match at a path boundary, so
+// https://example.org/a does not allow https://example.org/ab or https://example.org.io.
+func (p SANPolicy) uriAllowed(clusterName string, uri *url.URL) bool {
+	value := uri.String()
+	for _, prefix := range p.AllowedURIPrefixes {
+		prefix = strings.ReplaceAll(prefix, ClusterNamePlaceholder, clusterName)
+		if value == prefix || strings.HasPrefix(value, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -34,6 +34,9 @@ const (
 	maxCleanupPriority cleanupPriority = 100
 )
 
+// listPageSize is the max number of the resources returned in one list request.
+var listPageSize int64 = 500
+
 func newGCResourcesController(
 	metadataClient metadata.Interface,
 	resourceList []schema.GroupVersionResource,
@@ -55,8 +58,7 @@ func (r *gcResourcesController) reconcile(ctx context.Context, cluster *clusterv
 
 	// delete the resources in order. to delete the next resource after all resource instances are deleted.
 	for _, resourceGVR := range r.resourceGVRList {
-		resourceList, err := r.metadataClient.Resource(resourceGVR).
-			Namespace(cluster.Name).List(ctx, metav1.ListOptions{})
+		resourceList, err := r.listResources(ctx, resourceGVR, cluster.Name)
 		if errors.IsNotFound(err) {
 			continue
 		}
@@ -104,6 +106,25 @@ func (r *gcResourcesController) reconcile(ctx context.Context, cluster *clusterv
 	return gcReconcileContinue, nil
 }
 
+// listResources lists the metadata of the resources in the namespace page by page, so a namespace with a large
+// number of resources is not returned in one response.
+func (r *gcResourcesController) listResources(
+	ctx context.Context, gvr schema.GroupVersionResource, namespace string) (*metav1.PartialObjectMetadataList, error) {
+	resourceList := &metav1.PartialObjectMetadataList{}
+	options := metav1.ListOptions{Limit: listPageSize}
+	for {
+		page, err := r.metadataClient.Resource(gvr).Namespace(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		resourceList.Items = append(resourceList.Items, page.Items...)
+		if len(page.Continue) == 0 {
+			return resourceList, nil
+		}
+		options.Continue = page.Continue
+	}
+}
+
 func mapPriorityResource(resourceList *metav1.PartialObjectMetadataList) map[cleanupPriority][]string {
 	priorityResourceMap := map[cleanupPriority][]string{}
 	appendResourceFunc := func(priority cleanupPriority, name string) {
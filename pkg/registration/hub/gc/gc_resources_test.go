@@ -83,6 +83,39 @@ func TestGCResourcesController(t *testing.T) {
 	}
 }
 
+func TestListResources(t *testing.T) {
+	scheme := fakemetadataclient.NewTestScheme()
+	_ = metav1.AddMetaToScheme(scheme)
+	metadataClient := fakemetadataclient.NewSimpleMetadataClient(scheme)
+	// the fake metadata client expects the items in a metav1.List
+	pages := []*metav1.List{
+		{
+			ListMeta: metav1.ListMeta{Continue: "page2"},
+			Items: []runtime.RawExtension{
+				{Object: newWorkMetadata(testinghelpers.TestManagedClusterName, "work1", nil)}},
+		},
+		{
+			Items: []runtime.RawExtension{
+				{Object: newWorkMetadata(testinghelpers.TestManagedClusterName, "work2", nil)}},
+		},
+	}
+	metadataClient.PrependReactor("list", "manifestworks", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		page := pages[0]
+		pages = pages[1:]
+		return true, page, nil
+	})
+
+	ctrl := &gcResourcesController{metadataClient: metadataClient}
+	resourceList, err := ctrl.listResources(context.TODO(), workGvr, testinghelpers.TestManagedClusterName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resourceList.Items) != 2 {
+		t.Errorf("expected the works of all the pages are listed, but got %v", resourceList.Items)
+	}
+	testingcommon.AssertActions(t, metadataClient.Actions(), "list", "list")
+}
+
 func TestGetFirstDeletePriority(t *testing.T) {
 	cases := []struct {
 		name                        string
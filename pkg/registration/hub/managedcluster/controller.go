@@ -20,8 +20,11 @@ import (
 
 	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	informerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	informerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
 	listerv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	listerv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	v1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	ocmfeature "open-cluster-management.io/api/feature"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
@@ -29,12 +32,13 @@ import (
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
+	"open-cluster-management.io/ocm/pkg/registration/hub/managedclusterset"
 	"open-cluster-management.io/ocm/pkg/registration/hub/manifests"
 )
 
-// this is an internal annotation to indicate a managed cluster is already accepted automatically, it is not
+// ClusterAcceptedAnnotationKey is an internal annotation to indicate a managed cluster is already accepted automatically, it is not
 // expected to be changed or removed outside.
-const clusterAcceptedAnnotationKey = "open-cluster-management.io/automatically-accepted-on"
+const ClusterAcceptedAnnotationKey = "open-cluster-management.io/automatically-accepted-on"
 
 var staticFiles = []string{
 	"rbac/managedcluster-clusterrole.yaml",
@@ -45,12 +49,13 @@ var staticFiles = []string{
 
 // managedClusterController reconciles instances of ManagedCluster on the hub.
 type managedClusterController struct {
-	kubeClient    kubernetes.Interface
-	clusterClient clientset.Interface
-	clusterLister listerv1.ManagedClusterLister
-	applier       *apply.PermissionApplier
-	patcher       patcher.Patcher[*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus]
-	eventRecorder events.Recorder
+	kubeClient       kubernetes.Interface
+	clusterClient    clientset.Interface
+	clusterLister    listerv1.ManagedClusterLister
+	clusterSetLister listerv1beta2.ManagedClusterSetLister
+	applier          *apply.PermissionApplier
+	patcher          patcher.Patcher[*v1.ManagedCluster, v1.ManagedClusterSpec, v1.ManagedClusterStatus]
+	eventRecorder    events.Recorder
 }
 
 // NewManagedClusterController creates a new managed cluster controller
@@ -58,15 +63,17 @@ func NewManagedClusterController(
 	kubeClient kubernetes.Interface,
 	clusterClient clientset.Interface,
 	clusterInformer informerv1.ManagedClusterInformer,
+	clusterSetInformer informerv1beta2.ManagedClusterSetInformer,
 	roleInformer rbacv1informers.RoleInformer,
 	clusterRoleInformer rbacv1informers.ClusterRoleInformer,
 	rolebindingInformer rbacv1informers.RoleBindingInformer,
 	clusterRoleBindingInformer rbacv1informers.ClusterRoleBindingInformer,
 	recorder events.Recorder) factory.Controller {
 	c := &managedClusterController{
-		kubeClient:    kubeClient,
-		clusterClient: clusterClient,
-		clusterLister: clusterInformer.Lister(),
+		kubeClient:       kubeClient,
+		clusterClient:    clusterClient,
+		clusterLister:    clusterInformer.Lister(),
+		clusterSetLister: clusterSetInformer.Lister(),
 		applier: apply.NewPermissionApplier(
 			kubeClient,
 			roleInformer.Lister(),
@@ -116,7 +123,7 @@ func (c *managedClusterController) sync(ctx context.Context, syncCtx factory.Syn
 		// If the ManagedClusterAutoApproval feature is enabled, we automatically accept a cluster only
 		// when it joins for the first time, afterwards users can deny it again.
 		if features.HubMutableFeatureGate.Enabled(ocmfeature.ManagedClusterAutoApproval) {
-			if _, ok := managedCluster.Annotations[clusterAcceptedAnnotationKey]; !ok {
+			if _, ok := managedCluster.Annotations[ClusterAcceptedAnnotationKey]; !ok {
 				return c.acceptCluster(ctx, managedClusterName)
 			}
 		}
@@ -194,6 +201,13 @@ func (c *managedClusterController) sync(ctx context.Context, syncCtx factory.Syn
 		acceptedCondition.Message = applyErrors.Error()
 	}
 
+	// the cluster is assigned to a clusterset by the membership rules with assignOnJoin once it is accepted.
+	if !meta.IsStatusConditionTrue(managedCluster.Status.Conditions, v1.ManagedClusterConditionHubAccepted) {
+		if err := c.assignClusterSetOnJoin(ctx, managedCluster); err != nil {
+			return err
+		}
+	}
+
 	meta.SetStatusCondition(&newManagedCluster.Status.Conditions, acceptedCondition)
 	updated, updatedErr := c.patcher.PatchStatus(ctx, newManagedCluster, newManagedCluster.Status, managedCluster.Status)
 	if updatedErr != nil {
@@ -205,6 +219,27 @@ func (c *managedClusterController) sync(ctx context.Context, syncCtx factory.Syn
 	return operatorhelpers.NewMultiLineAggregate(errs)
 }
 
+// assignClusterSetOnJoin sets the clusterset label of the accepted cluster with the membership rules with assignOnJoin,
+// unless the cluster already belongs to a clusterset or it was assigned before.
+func (c *managedClusterController) assignClusterSetOnJoin(ctx context.Context, managedCluster *v1.ManagedCluster) error {
+	if _, ok := managedCluster.Labels[clusterv1beta2.ClusterSetLabel]; ok {
+		return nil
+	}
+	if _, ok := managedCluster.Annotations[managedclusterset.ClusterSetAssignedByRulesAnnotationKey]; ok {
+		return nil
+	}
+	clusterSetName, err := managedclusterset.AssignOnJoinClusterSet(c.clusterSetLister, managedCluster)
+	if err != nil || len(clusterSetName) == 0 {
+		return err
+	}
+	if err := managedclusterset.PatchClusterSetLabel(ctx, c.clusterClient, managedCluster.Name, clusterSetName, true); err != nil {
+		return err
+	}
+	c.eventRecorder.Eventf("ManagedClusterAssignedByMembershipRules",
+		"managed cluster %s is assigned to ManagedClusterSet %s on join", managedCluster.Name, clusterSetName)
+	return nil
+}
+
 func (c *managedClusterController) removeManagedClusterResources(ctx context.Context, managedClusterName string) error {
 	var errs []error
 	// Clean up managed cluster manifests
@@ -222,7 +257,7 @@ func (c *managedClusterController) acceptCluster(ctx context.Context, managedClu
 	// TODO support patching both annotations and spec simultaneously in the patcher
 	acceptedTime := time.Now()
 	patch := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}},"spec":{"hubAcceptsClient":true}}`,
-		clusterAcceptedAnnotationKey, acceptedTime.Format(time.RFC3339))
+		ClusterAcceptedAnnotationKey, acceptedTime.Format(time.RFC3339))
 	_, err := c.clusterClient.ClusterV1().ManagedClusters().Patch(ctx, managedClusterName,
 		types.MergePatchType, []byte(patch), metav1.PatchOptions{})
 	return err
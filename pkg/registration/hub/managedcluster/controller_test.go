@@ -17,6 +17,7 @@ import (
 	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	v1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	ocmfeature "open-cluster-management.io/api/feature"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
@@ -24,13 +25,26 @@ import (
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/features"
 	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/registration/hub/managedclusterset"
 )
 
 func TestSyncManagedCluster(t *testing.T) {
+	labeledCluster := testinghelpers.NewAcceptingManagedCluster()
+	labeledCluster.Labels = map[string]string{"region": "eu"}
+	assignOnJoinClusterSet := &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "eu-prod",
+			Annotations: map[string]string{
+				managedclusterset.MembershipRulesAnnotationKey: `{"clusterSelector":{"matchLabels":{"region":"eu"}},"assignOnJoin":true}`,
+			},
+		},
+	}
+
 	cases := []struct {
 		name                string
 		autoApprovalEnabled bool
 		startingObjects     []runtime.Object
+		clusterSets         []runtime.Object
 		validateActions     func(t *testing.T, actions []clienttesting.Action)
 	}{
 		{
@@ -67,6 +81,36 @@ func TestSyncManagedCluster(t *testing.T) {
 				testingcommon.AssertCondition(t, managedCluster.Status.Conditions, expectedCondition)
 			},
 		},
+		{
+			name:            "assign an accepted spoke cluster to the clusterset",
+			startingObjects: []runtime.Object{labeledCluster},
+			clusterSets:     []runtime.Object{assignOnJoinClusterSet},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch", "patch")
+				managedCluster := &v1.ManagedCluster{}
+				if err := json.Unmarshal(actions[0].(clienttesting.PatchAction).GetPatch(), managedCluster); err != nil {
+					t.Fatal(err)
+				}
+				if managedCluster.Labels[clusterv1beta2.ClusterSetLabel] != "eu-prod" {
+					t.Errorf("expected the cluster is assigned to the clusterset, but got %v", managedCluster.Labels)
+				}
+				if managedCluster.Annotations[managedclusterset.ClusterSetAssignedByRulesAnnotationKey] != "eu-prod" {
+					t.Errorf("expected the assignment is recorded, but got %v", managedCluster.Annotations)
+				}
+			},
+		},
+		{
+			name: "do not assign an accepted spoke cluster again",
+			startingObjects: []runtime.Object{func() runtime.Object {
+				cluster := testinghelpers.NewAcceptedManagedCluster()
+				cluster.Labels = map[string]string{"region": "eu"}
+				return cluster
+			}()},
+			clusterSets: []runtime.Object{assignOnJoinClusterSet},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
 		{
 			name:            "sync an accepted spoke cluster",
 			startingObjects: []runtime.Object{testinghelpers.NewAcceptedManagedCluster()},
@@ -126,12 +170,20 @@ func TestSyncManagedCluster(t *testing.T) {
 				}
 			}
 
+			clusterSetStore := clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore()
+			for _, clusterSet := range c.clusterSets {
+				if err := clusterSetStore.Add(clusterSet); err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			features.HubMutableFeatureGate.Set(fmt.Sprintf("%s=%v", ocmfeature.ManagedClusterAutoApproval, c.autoApprovalEnabled))
 
 			ctrl := managedClusterController{
 				kubeClient,
 				clusterClient,
 				clusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+				clusterInformerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
 				apply.NewPermissionApplier(
 					kubeClient,
 					kubeInformer.Rbac().V1().Roles().Lister(),
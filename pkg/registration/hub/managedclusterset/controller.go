@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
@@ -27,12 +30,22 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
 
 const (
 	// TODO move these to api repos
 	ReasonClusterSelected   = "ClustersSelected"
 	ReasonNoClusterMatchced = "NoClusterMatched"
+
+	// ManagedClusterSetConditionMembershipConflict is set on an exclusive ManagedClusterSet, it is true if any
+	// of its ManagedClusters also belongs to other exclusive ManagedClusterSets.
+	ManagedClusterSetConditionMembershipConflict = "MembershipConflict"
+	ReasonMembershipConflict                     = "ClustersInOtherExclusiveSets"
+	ReasonNoMembershipConflict                   = "NoMembershipConflict"
+
+	// maxConflictsInMessage is the max number of conflicting ManagedClusters listed in the condition message
+	maxConflictsInMessage = 10
 )
 
 // managedClusterSetController reconciles instances of ManagedClusterSet on the hub.
@@ -115,6 +128,45 @@ func NewManagedClusterSetController(
 		utilruntime.HandleError(err)
 	}
 
+	// the membership conflicts of an exclusive clusterset depend on the other exclusive clustersets
+	_, err = clusterSetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if clusterSet, ok := obj.(*clusterv1beta2.ManagedClusterSet); ok && helpers.IsExclusiveClusterSet(clusterSet) {
+				c.enqueueExclusiveClusterSets()
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldClusterSet, ok := oldObj.(*clusterv1beta2.ManagedClusterSet)
+			if !ok {
+				return
+			}
+			newClusterSet, ok := newObj.(*clusterv1beta2.ManagedClusterSet)
+			if !ok {
+				return
+			}
+			if !helpers.IsExclusiveClusterSet(oldClusterSet) && !helpers.IsExclusiveClusterSet(newClusterSet) {
+				return
+			}
+			if helpers.IsExclusiveClusterSet(oldClusterSet) == helpers.IsExclusiveClusterSet(newClusterSet) &&
+				reflect.DeepEqual(oldClusterSet.Spec, newClusterSet.Spec) &&
+				oldClusterSet.DeletionTimestamp.Equal(newClusterSet.DeletionTimestamp) {
+				return
+			}
+			c.enqueueExclusiveClusterSets()
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if clusterSet, ok := obj.(*clusterv1beta2.ManagedClusterSet); ok && helpers.IsExclusiveClusterSet(clusterSet) {
+				c.enqueueExclusiveClusterSets()
+			}
+		},
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
 	return factory.New().
 		WithSyncContext(syncCtx).
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterSetInformer.Informer()).
@@ -174,6 +226,16 @@ func (c *managedClusterSetController) syncClusterSet(ctx context.Context, origin
 	}
 	meta.SetStatusCondition(&clusterSet.Status.Conditions, emptyCondition)
 
+	if helpers.IsExclusiveClusterSet(clusterSet) {
+		conflictCondition, err := c.membershipConflictCondition(clusterSet, clusters)
+		if err != nil {
+			return err
+		}
+		meta.SetStatusCondition(&clusterSet.Status.Conditions, conflictCondition)
+	} else {
+		meta.RemoveStatusCondition(&clusterSet.Status.Conditions, ManagedClusterSetConditionMembershipConflict)
+	}
+
 	_, err = c.patcher.PatchStatus(ctx, clusterSet, clusterSet.Status, originalClusterSet.Status)
 	if err != nil {
 		return fmt.Errorf("failed to update status of ManagedClusterSet %q: %w", clusterSet.Name, err)
@@ -182,6 +244,65 @@ func (c *managedClusterSetController) syncClusterSet(ctx context.Context, origin
 	return nil
 }
 
+// membershipConflictCondition returns the condition listing the clusters of an exclusive clusterset which also
+// belong to other exclusive clustersets.
+func (c *managedClusterSetController) membershipConflictCondition(
+	clusterSet *clusterv1beta2.ManagedClusterSet, clusters []*v1.ManagedCluster) (metav1.Condition, error) {
+	var conflicts []string
+	for _, cluster := range clusters {
+		exclusiveSets, err := helpers.GetExclusiveClusterSetsOfCluster(cluster, c.clusterSetLister)
+		if err != nil {
+			return metav1.Condition{}, err
+		}
+		var others []string
+		for _, exclusiveSet := range exclusiveSets {
+			if exclusiveSet.Name != clusterSet.Name {
+				others = append(others, exclusiveSet.Name)
+			}
+		}
+		if len(others) > 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", cluster.Name, strings.Join(others, ", ")))
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return metav1.Condition{
+			Type:    ManagedClusterSetConditionMembershipConflict,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNoMembershipConflict,
+			Message: "No ManagedCluster belongs to other exclusive ManagedClusterSets",
+		}, nil
+	}
+
+	sort.Strings(conflicts)
+	message := fmt.Sprintf("%d ManagedClusters also belong to other exclusive ManagedClusterSets: ", len(conflicts))
+	if len(conflicts) > maxConflictsInMessage {
+		message += strings.Join(conflicts[:maxConflictsInMessage], "; ") + "; ..."
+	} else {
+		message += strings.Join(conflicts, "; ")
+	}
+	return metav1.Condition{
+		Type:    ManagedClusterSetConditionMembershipConflict,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonMembershipConflict,
+		Message: message,
+	}, nil
+}
+
+// enqueueExclusiveClusterSets enqueue all the exclusive clustersets
+func (c *managedClusterSetController) enqueueExclusiveClusterSets() {
+	clusterSets, err := c.clusterSetLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error to list clustersets. Error %v", err))
+		return
+	}
+	for _, clusterSet := range clusterSets {
+		if helpers.IsExclusiveClusterSet(clusterSet) {
+			c.queue.Add(clusterSet.Name)
+		}
+	}
+}
+
 // enqueueClusterClusterSet enqueue a cluster related clusterset
 func (c *managedClusterSetController) enqueueClusterClusterSet(cluster *v1.ManagedCluster) {
 	clusterSets, err := clustersdkv1beta2.GetClusterSetsOfCluster(cluster, c.clusterSetLister)
@@ -212,6 +333,16 @@ func (c *managedClusterSetController) enqueueUpdateClusterClusterSet(oldCluster,
 	for diffSet := range diffClusterSets {
 		c.queue.Add(diffSet)
 	}
+
+	// the membership conflicts of the exclusive clustersets the cluster stays in may change as well
+	if diffClusterSets.Len() == 0 {
+		return
+	}
+	for _, clusterSet := range append(oldClusterSets, newClusterSets...) {
+		if helpers.IsExclusiveClusterSet(clusterSet) {
+			c.queue.Add(clusterSet.Name)
+		}
+	}
 }
 
 // getDiffClusterSetsNames return the diff clustersets names
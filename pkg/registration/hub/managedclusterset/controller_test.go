@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -18,6 +19,7 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
 
 func TestSyncClusterSet(t *testing.T) {
@@ -261,6 +263,131 @@ func TestSyncClusterSet(t *testing.T) {
 	}
 }
 
+func TestSyncExclusiveClusterSet(t *testing.T) {
+	prodSet := newExclusiveClusterSet("prod", map[string]string{"env": "prod"})
+	teamSet := newExclusiveClusterSet("team-a", map[string]string{"team": "a"})
+
+	cases := []struct {
+		name                string
+		existingClusterSets []*clusterv1beta2.ManagedClusterSet
+		existingClusters    []*clusterv1.ManagedCluster
+		expectCondition     *metav1.Condition
+	}{
+		{
+			name:                "no membership conflict",
+			existingClusterSets: []*clusterv1beta2.ManagedClusterSet{prodSet, teamSet},
+			existingClusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{"env": "prod"}),
+				newManagedCluster("cluster2", map[string]string{"team": "a"}),
+			},
+			expectCondition: &metav1.Condition{
+				Type:    ManagedClusterSetConditionMembershipConflict,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonNoMembershipConflict,
+				Message: "No ManagedCluster belongs to other exclusive ManagedClusterSets",
+			},
+		},
+		{
+			name:                "membership conflict",
+			existingClusterSets: []*clusterv1beta2.ManagedClusterSet{prodSet, teamSet},
+			existingClusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{"env": "prod", "team": "a"}),
+				newManagedCluster("cluster2", map[string]string{"env": "prod"}),
+			},
+			expectCondition: &metav1.Condition{
+				Type:    ManagedClusterSetConditionMembershipConflict,
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonMembershipConflict,
+				Message: "1 ManagedClusters also belong to other exclusive ManagedClusterSets: cluster1 (team-a)",
+			},
+		},
+		{
+			name: "clusterset which is not exclusive does not conflict",
+			existingClusterSets: []*clusterv1beta2.ManagedClusterSet{prodSet, func() *clusterv1beta2.ManagedClusterSet {
+				set := teamSet.DeepCopy()
+				set.Annotations = nil
+				return set
+			}()},
+			existingClusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{"env": "prod", "team": "a"}),
+			},
+			expectCondition: &metav1.Condition{
+				Type:    ManagedClusterSetConditionMembershipConflict,
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonNoMembershipConflict,
+				Message: "No ManagedCluster belongs to other exclusive ManagedClusterSets",
+			},
+		},
+		{
+			name: "condition is removed from the clusterset which is not exclusive",
+			existingClusterSets: []*clusterv1beta2.ManagedClusterSet{func() *clusterv1beta2.ManagedClusterSet {
+				set := prodSet.DeepCopy()
+				set.Annotations = nil
+				set.Status.Conditions = []metav1.Condition{
+					{Type: ManagedClusterSetConditionMembershipConflict, Status: metav1.ConditionTrue},
+				}
+				return set
+			}(), teamSet},
+			existingClusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{"env": "prod", "team": "a"}),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			for _, cluster := range c.existingClusters {
+				objects = append(objects, cluster)
+			}
+			for _, clusterSet := range c.existingClusterSets {
+				objects = append(objects, clusterSet)
+			}
+			clusterClient := clusterfake.NewSimpleClientset(objects...)
+
+			informerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, 5*time.Minute)
+			for _, cluster := range c.existingClusters {
+				if err := informerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, clusterSet := range c.existingClusterSets {
+				if err := informerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(clusterSet); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := managedClusterSetController{
+				patcher: patcher.NewPatcher[
+					*clusterv1beta2.ManagedClusterSet, clusterv1beta2.ManagedClusterSetSpec, clusterv1beta2.ManagedClusterSetStatus](
+					clusterClient.ClusterV1beta2().ManagedClusterSets()),
+				clusterLister:    informerFactory.Cluster().V1().ManagedClusters().Lister(),
+				clusterSetLister: informerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
+				eventRecorder:    eventstesting.NewTestingEventRecorder(t),
+			}
+
+			clusterSet := c.existingClusterSets[0]
+			if err := ctrl.syncClusterSet(context.Background(), clusterSet); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			updatedSet, err := clusterClient.ClusterV1beta2().ManagedClusterSets().Get(
+				context.Background(), clusterSet.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.expectCondition == nil {
+				if meta.FindStatusCondition(updatedSet.Status.Conditions, ManagedClusterSetConditionMembershipConflict) != nil {
+					t.Errorf("expected no membership conflict condition, but got %v", updatedSet.Status.Conditions)
+				}
+				return
+			}
+			if !hasCondition(updatedSet.Status.Conditions, *c.expectCondition) {
+				t.Errorf("expected condition:%v. is not found: %v", *c.expectCondition, updatedSet.Status.Conditions)
+			}
+		})
+	}
+}
+
 func TestGetDiffClustersets(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -417,6 +544,21 @@ func newManagedClusterSet(name string) *clusterv1beta2.ManagedClusterSet {
 	return clusterSet
 }
 
+func newExclusiveClusterSet(name string, matchLabels map[string]string) *clusterv1beta2.ManagedClusterSet {
+	return &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{helpers.ExclusiveClusterSetAnnotationKey: "true"},
+		},
+		Spec: clusterv1beta2.ManagedClusterSetSpec{
+			ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+				SelectorType:  clusterv1beta2.LabelSelector,
+				LabelSelector: &metav1.LabelSelector{MatchLabels: matchLabels},
+			},
+		},
+	}
+}
+
 func hasCondition(conditions []metav1.Condition, expectCondition metav1.Condition) bool {
 	for _, condition := range conditions {
 		if condition.Type != expectCondition.Type {
@@ -0,0 +1,292 @@
+package managedclusterset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
+	v1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+// MembershipRulesAnnotationKey is the annotation on a ManagedClusterSet holding its membership rules in json.
+// TODO move the membership rules to the ManagedClusterSet spec in the api repo.
+const MembershipRulesAnnotationKey = "cluster.open-cluster-management.io/experimental-membership-rules"
+
+// ClusterSetAssignedByRulesAnnotationKey records the name of the ManagedClusterSet which a managed cluster is assigned
+// to by the membership rules with assignOnJoin when it is accepted, the cluster is not assigned again once it is set.
+const ClusterSetAssignedByRulesAnnotationKey = "cluster.open-cluster-management.io/clusterset-assigned-by-rules"
+
+// MembershipRules add the managed clusters matching the selector to a ManagedClusterSet by setting the clusterset
+// label on them, and remove the clusters which no longer match. The rules only match the labels of the managed
+// clusters set on the hub, the cluster claims are reported by the managed clusters so they are not trusted. The
+// rules only act on the accepted managed clusters. The registration webhook only allows the users with the
+// managedclustersets/join permission to set the rules.
+type MembershipRules struct {
+	// ClusterSelector selects the managed clusters by their labels.
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// ExcludedClusters are the names of the pinned managed clusters, whose clusterset label is never changed by
+	// the rules.
+	ExcludedClusters []string `json:"excludedClusters,omitempty"`
+	// AssignOnJoin assigns a managed cluster matching the rules to the ManagedClusterSet only once, when it is
+	// accepted by the hub. The cluster is never removed by the rules, so the clusterset label can be changed manually
+	// afterwards.
+	AssignOnJoin bool `json:"assignOnJoin,omitempty"`
+}
+
+type membershipRuleMatcher struct {
+	clusterSelector labels.Selector
+	excluded        sets.Set[string]
+	assignOnJoin    bool
+}
+
+// membershipRuleController maintains the clusterset label of the managed clusters with the membership rules of
+// the ManagedClusterSets.
+type membershipRuleController struct {
+	clusterClient    clientset.Interface
+	clusterLister    clusterlisterv1.ManagedClusterLister
+	clusterSetLister clusterlisterv1beta2.ManagedClusterSetLister
+	eventRecorder    events.Recorder
+}
+
+// NewMembershipRuleController creates a new controller evaluating the membership rules of the ManagedClusterSets
+func NewMembershipRuleController(
+	clusterClient clientset.Interface,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &membershipRuleController{
+		clusterClient:    clusterClient,
+		clusterLister:    clusterInformer.Lister(),
+		clusterSetLister: clusterSetInformer.Lister(),
+		eventRecorder:    recorder.WithComponentSuffix("managed-cluster-set-membership-rule-controller"),
+	}
+
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			queue.FilterByAnnotation(MembershipRulesAnnotationKey),
+			clusterSetInformer.Informer()).
+		WithInformersQueueKeysFunc(c.affectedClusterSets, clusterInformer.Informer()).
+		WithSync(c.sync).
+		ToController("ManagedClusterSetMembershipRuleController", recorder)
+}
+
+func (c *membershipRuleController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	clusterSetName := syncCtx.QueueKey()
+	if len(clusterSetName) == 0 {
+		return nil
+	}
+	logger.V(4).Info("Reconciling the membership rules of ManagedClusterSet", "clusterSetName", clusterSetName)
+
+	clusterSet, err := c.clusterSetLister.Get(clusterSetName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !clusterSet.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	rules, ok := clusterSet.Annotations[MembershipRulesAnnotationKey]
+	if !ok {
+		return nil
+	}
+	matcher, err := parseMembershipRules(rules)
+	if err != nil {
+		c.eventRecorder.Warningf("InvalidMembershipRules",
+			"The membership rules of ManagedClusterSet %q are invalid: %v", clusterSetName, err)
+		return nil
+	}
+	// the rules with assignOnJoin are applied by the managed cluster controller when a cluster is accepted.
+	if matcher.assignOnJoin {
+		return nil
+	}
+	// the rules maintain the clusterset label, so they only work with the clustersets selecting the label
+	switch clusterSet.Spec.ClusterSelector.SelectorType {
+	case "", clusterv1beta2.ExclusiveClusterSetLabel:
+	default:
+		c.eventRecorder.Warningf("InvalidMembershipRules",
+			"The membership rules of ManagedClusterSet %q are ignored with the selector type %q",
+			clusterSetName, clusterSet.Spec.ClusterSelector.SelectorType)
+		return nil
+	}
+
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, cluster := range clusters {
+		if matcher.excluded.Has(cluster.Name) || !cluster.Spec.HubAcceptsClient || !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		currentClusterSet := cluster.Labels[clusterv1beta2.ClusterSetLabel]
+		switch matched := matcher.matches(cluster); {
+		case matched && len(currentClusterSet) == 0:
+			if err := PatchClusterSetLabel(ctx, c.clusterClient, cluster.Name, clusterSetName, false); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			c.eventRecorder.Eventf("ManagedClusterAddedByMembershipRules",
+				"ManagedCluster %q is added to ManagedClusterSet %q", cluster.Name, clusterSetName)
+		case matched && currentClusterSet != clusterSetName:
+			// the cluster is already a member of another clusterset, leave it there.
+			logger.V(4).Info("ManagedCluster already belongs to another ManagedClusterSet",
+				"clusterName", cluster.Name, "clusterSetName", currentClusterSet)
+		case !matched && currentClusterSet == clusterSetName:
+			if err := PatchClusterSetLabel(ctx, c.clusterClient, cluster.Name, "", false); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			c.eventRecorder.Eventf("ManagedClusterRemovedByMembershipRules",
+				"ManagedCluster %q is removed from ManagedClusterSet %q", cluster.Name, clusterSetName)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// AssignOnJoinClusterSet returns the name of the first ManagedClusterSet, ordered by name, whose membership rules with
+// assignOnJoin match the cluster. It returns an empty string if there is none.
+func AssignOnJoinClusterSet(clusterSetLister clusterlisterv1beta2.ManagedClusterSetLister, cluster *v1.ManagedCluster) (string, error) {
+	clusterSets, err := clusterSetLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(clusterSets, func(i, j int) bool { return clusterSets[i].Name < clusterSets[j].Name })
+	for _, clusterSet := range clusterSets {
+		rules, ok := clusterSet.Annotations[MembershipRulesAnnotationKey]
+		if !ok || !clusterSet.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch clusterSet.Spec.ClusterSelector.SelectorType {
+		case "", clusterv1beta2.ExclusiveClusterSetLabel:
+		default:
+			continue
+		}
+		matcher, err := parseMembershipRules(rules)
+		if err != nil || !matcher.assignOnJoin || matcher.excluded.Has(cluster.Name) {
+			continue
+		}
+		if matcher.matches(cluster) {
+			return clusterSet.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// PatchClusterSetLabel sets the clusterset label of the cluster, the label is removed if the clusterset is empty. The
+// assignment is recorded in the annotation of the cluster if assigned is true.
+func PatchClusterSetLabel(
+	ctx context.Context, clusterClient clientset.Interface, clusterName, clusterSetName string, assigned bool) error {
+	var value interface{}
+	if len(clusterSetName) > 0 {
+		value = clusterSetName
+	}
+	metadata := map[string]interface{}{
+		"labels": map[string]interface{}{
+			clusterv1beta2.ClusterSetLabel: value,
+		},
+	}
+	if assigned {
+		metadata["annotations"] = map[string]interface{}{
+			ClusterSetAssignedByRulesAnnotationKey: clusterSetName,
+		}
+	}
+	patch := map[string]interface{}{
+		"metadata": metadata,
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = clusterClient.ClusterV1().ManagedClusters().Patch(
+		ctx, clusterName, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch the clusterset label of ManagedCluster %q: %w", clusterName, err)
+	}
+	return nil
+}
+
+// affectedClusterSets returns the names of the clustersets whose members may be changed by the cluster, which are
+// the clustersets with the rules matching the cluster, and the current clusterset of the cluster if it has rules.
+func (c *membershipRuleController) affectedClusterSets(obj runtime.Object) []string {
+	cluster, ok := obj.(*v1.ManagedCluster)
+	if !ok {
+		return nil
+	}
+	clusterSets, err := c.clusterSetLister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, clusterSet := range clusterSets {
+		rules, ok := clusterSet.Annotations[MembershipRulesAnnotationKey]
+		if !ok {
+			continue
+		}
+		if clusterSet.Name == cluster.Labels[clusterv1beta2.ClusterSetLabel] {
+			names = append(names, clusterSet.Name)
+			continue
+		}
+		if matcher, err := parseMembershipRules(rules); err == nil && matcher.matches(cluster) {
+			names = append(names, clusterSet.Name)
+		}
+	}
+	return names
+}
+
+// parseMembershipRules parses the membership rules in the annotation of a ManagedClusterSet.
+func parseMembershipRules(data string) (*membershipRuleMatcher, error) {
+	rules := &MembershipRules{}
+	if err := json.Unmarshal([]byte(data), rules); err != nil {
+		return nil, err
+	}
+	if rules.ClusterSelector == nil {
+		return nil, fmt.Errorf("clusterSelector is required")
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(rules.ClusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clusterSelector: %v", err)
+	}
+	return &membershipRuleMatcher{
+		clusterSelector: selector,
+		excluded:        sets.New[string](rules.ExcludedClusters...),
+		assignOnJoin:    rules.AssignOnJoin,
+	}, nil
+}
+
+func (m *membershipRuleMatcher) matches(cluster *v1.ManagedCluster) bool {
+	// the clusterset label is maintained by the rules, so it is not taken into account.
+	clusterLabels := labels.Set{}
+	for key, value := range cluster.Labels {
+		if key != clusterv1beta2.ClusterSetLabel {
+			clusterLabels[key] = value
+		}
+	}
+	return m.clusterSelector.Matches(clusterLabels)
+}
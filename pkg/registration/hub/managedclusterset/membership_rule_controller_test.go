@@ -0,0 +1,268 @@
+package managedclusterset
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func newClusterSetWithRules(name, rules string) *clusterv1beta2.ManagedClusterSet {
+	return &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{MembershipRulesAnnotationKey: rules},
+		},
+		Spec: clusterv1beta2.ManagedClusterSetSpec{
+			ClusterSelector: clusterv1beta2.ManagedClusterSelector{
+				SelectorType: clusterv1beta2.ExclusiveClusterSetLabel,
+			},
+		},
+	}
+}
+
+func newClusterWithClaims(name string, labels map[string]string, claims map[string]string) *clusterv1.ManagedCluster {
+	cluster := newAcceptedManagedCluster(name, labels)
+	for claim, value := range claims {
+		cluster.Status.ClusterClaims = append(cluster.Status.ClusterClaims,
+			clusterv1.ManagedClusterClaim{Name: claim, Value: value})
+	}
+	return cluster
+}
+
+func newAcceptedManagedCluster(name string, labels map[string]string) *clusterv1.ManagedCluster {
+	cluster := newManagedCluster(name, labels)
+	cluster.Spec.HubAcceptsClient = true
+	return cluster
+}
+
+func TestSyncMembershipRules(t *testing.T) {
+	rules := `{"clusterSelector":{"matchLabels":{"env":"prod","platform":"AWS"}},"excludedClusters":["pinned"]}`
+	assignOnJoinRules := `{"clusterSelector":{"matchLabels":{"platform":"AWS"}},"assignOnJoin":true}`
+
+	cases := []struct {
+		name               string
+		clusterSet         *clusterv1beta2.ManagedClusterSet
+		clusters           []*clusterv1.ManagedCluster
+		expectedClusterSet map[string]*string
+	}{
+		{
+			name:       "add and remove clusters",
+			clusterSet: newClusterSetWithRules("prod-aws", rules),
+			clusters: []*clusterv1.ManagedCluster{
+				newAcceptedManagedCluster("cluster1", map[string]string{"env": "prod", "platform": "AWS"}),
+				newAcceptedManagedCluster("cluster2", map[string]string{"env": "prod", "platform": "GCP", clusterv1beta2.ClusterSetLabel: "prod-aws"}),
+				newAcceptedManagedCluster("cluster3", map[string]string{"env": "dev", "platform": "AWS"}),
+			},
+			expectedClusterSet: map[string]*string{
+				"cluster1": ptr.To("prod-aws"),
+				"cluster2": nil,
+			},
+		},
+		{
+			name:       "cluster claims are not matched",
+			clusterSet: newClusterSetWithRules("prod-aws", rules),
+			clusters: []*clusterv1.ManagedCluster{
+				newClusterWithClaims("cluster1", map[string]string{"env": "prod"}, map[string]string{"platform": "AWS"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+		{
+			name:       "clusters in other clustersets are kept",
+			clusterSet: newClusterSetWithRules("prod-aws", rules),
+			clusters: []*clusterv1.ManagedCluster{
+				newAcceptedManagedCluster("cluster1", map[string]string{"env": "prod", "platform": "AWS", clusterv1beta2.ClusterSetLabel: "other"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+		{
+			name:       "pinned clusters are not changed",
+			clusterSet: newClusterSetWithRules("prod-aws", rules),
+			clusters: []*clusterv1.ManagedCluster{
+				newAcceptedManagedCluster("pinned", map[string]string{clusterv1beta2.ClusterSetLabel: "prod-aws"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+		{
+			name:       "invalid rules",
+			clusterSet: newClusterSetWithRules("prod-aws", `{"excludedClusters":["pinned"]}`),
+			clusters: []*clusterv1.ManagedCluster{
+				newAcceptedManagedCluster("cluster1", map[string]string{"env": "prod"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+		{
+			name: "rules are ignored with label selector",
+			clusterSet: func() *clusterv1beta2.ManagedClusterSet {
+				set := newClusterSetWithRules("prod-aws", rules)
+				set.Spec.ClusterSelector.SelectorType = clusterv1beta2.LabelSelector
+				return set
+			}(),
+			clusters: []*clusterv1.ManagedCluster{
+				newAcceptedManagedCluster("cluster1", map[string]string{"env": "prod", "platform": "AWS"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+		{
+			name:       "clusters not accepted are not changed",
+			clusterSet: newClusterSetWithRules("prod-aws", rules),
+			clusters: []*clusterv1.ManagedCluster{
+				newManagedCluster("cluster1", map[string]string{"env": "prod", "platform": "AWS"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+		{
+			name:       "rules with assignOnJoin are applied on acceptance only",
+			clusterSet: newClusterSetWithRules("prod-aws", assignOnJoinRules),
+			clusters: []*clusterv1.ManagedCluster{
+				newAcceptedManagedCluster("cluster1", map[string]string{"platform": "AWS"}),
+				newAcceptedManagedCluster("cluster2", map[string]string{"platform": "GCP", clusterv1beta2.ClusterSetLabel: "prod-aws"}),
+			},
+			expectedClusterSet: map[string]*string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			objects := []runtime.Object{c.clusterSet}
+			for _, cluster := range c.clusters {
+				objects = append(objects, cluster)
+			}
+			clusterClient := clusterfake.NewSimpleClientset(objects...)
+			informerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, 5*time.Minute)
+			for _, cluster := range c.clusters {
+				if err := informerFactory.Cluster().V1().ManagedClusters().Informer().GetStore().Add(cluster); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := informerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(c.clusterSet); err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := membershipRuleController{
+				clusterClient:    clusterClient,
+				clusterLister:    informerFactory.Cluster().V1().ManagedClusters().Lister(),
+				clusterSetLister: informerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
+				eventRecorder:    eventstesting.NewTestingEventRecorder(t),
+			}
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, c.clusterSet.Name)); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			patched := map[string]*string{}
+			for _, action := range clusterClient.Actions() {
+				if action.GetVerb() != "patch" {
+					continue
+				}
+				patchAction := action.(clienttesting.PatchActionImpl)
+				patch := struct {
+					Metadata struct {
+						Labels map[string]*string `json:"labels"`
+					} `json:"metadata"`
+				}{}
+				if err := json.Unmarshal(patchAction.Patch, &patch); err != nil {
+					t.Fatal(err)
+				}
+				patched[patchAction.Name] = patch.Metadata.Labels[clusterv1beta2.ClusterSetLabel]
+			}
+			if len(patched) != len(c.expectedClusterSet) {
+				t.Fatalf("expected patched clusters %v, but got %v", c.expectedClusterSet, patched)
+			}
+			for name, expected := range c.expectedClusterSet {
+				actual, ok := patched[name]
+				if !ok {
+					t.Errorf("expected cluster %q is patched", name)
+					continue
+				}
+				if (expected == nil) != (actual == nil) || (expected != nil && *expected != *actual) {
+					t.Errorf("expected the clusterset label of cluster %q is %v, but got %v", name, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestAffectedClusterSets(t *testing.T) {
+	clusterSets := []*clusterv1beta2.ManagedClusterSet{
+		newClusterSetWithRules("aws", `{"clusterSelector":{"matchLabels":{"platform":"AWS"}}}`),
+		newClusterSetWithRules("gcp", `{"clusterSelector":{"matchLabels":{"platform":"GCP"}}}`),
+		newClusterSetWithRules("azure", `{"clusterSelector":{"matchLabels":{"platform":"Azure"}}}`),
+		newManagedClusterSet("no-rules"),
+	}
+	informerFactory := clusterinformers.NewSharedInformerFactory(clusterfake.NewSimpleClientset(), 5*time.Minute)
+	for _, clusterSet := range clusterSets {
+		if err := informerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(clusterSet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctrl := membershipRuleController{
+		clusterSetLister: informerFactory.Cluster().V1beta2().ManagedClusterSets().Lister(),
+	}
+
+	cluster := newManagedCluster("cluster1", map[string]string{"platform": "AWS", clusterv1beta2.ClusterSetLabel: "gcp"})
+	names := sets.New(ctrl.affectedClusterSets(cluster)...)
+	if !names.Equal(sets.New("aws", "gcp")) {
+		t.Errorf("expected the clustersets aws and gcp are affected, but got %v", sets.List(names))
+	}
+}
+
+func TestAssignOnJoinClusterSet(t *testing.T) {
+	clusterSets := []*clusterv1beta2.ManagedClusterSet{
+		newClusterSetWithRules("aws", `{"clusterSelector":{"matchLabels":{"platform":"AWS"}}}`),
+		newClusterSetWithRules("aws-prod", `{"clusterSelector":{"matchLabels":{"platform":"AWS"}},"assignOnJoin":true}`),
+		newClusterSetWithRules("aws-dev", `{"clusterSelector":{"matchLabels":{"platform":"AWS"}},"assignOnJoin":true,"excludedClusters":["cluster2"]}`),
+	}
+	informerFactory := clusterinformers.NewSharedInformerFactory(clusterfake.NewSimpleClientset(), 5*time.Minute)
+	for _, clusterSet := range clusterSets {
+		if err := informerFactory.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(clusterSet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lister := informerFactory.Cluster().V1beta2().ManagedClusterSets().Lister()
+
+	cases := []struct {
+		name               string
+		cluster            *clusterv1.ManagedCluster
+		expectedClusterSet string
+	}{
+		{
+			name:               "first clusterset by name",
+			cluster:            newManagedCluster("cluster1", map[string]string{"platform": "AWS"}),
+			expectedClusterSet: "aws-dev",
+		},
+		{
+			name:               "excluded cluster",
+			cluster:            newManagedCluster("cluster2", map[string]string{"platform": "AWS"}),
+			expectedClusterSet: "aws-prod",
+		},
+		{
+			name:    "no matched clusterset",
+			cluster: newManagedCluster("cluster1", map[string]string{"platform": "GCP"}),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterSet, err := AssignOnJoinClusterSet(lister, c.cluster)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if clusterSet != c.expectedClusterSet {
+				t.Errorf("expected clusterset %q, but got %q", c.expectedClusterSet, clusterSet)
+			}
+		})
+	}
+}
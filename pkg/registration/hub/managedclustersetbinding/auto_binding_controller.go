@@ -0,0 +1,209 @@
+package managedclustersetbinding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformerv1beta2 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta2"
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+const (
+	// BindingNamespaceSelectorAnnotationKey is the annotation on a ManagedClusterSet granting it to the namespaces
+	// selected by the label selector, e.g. "team in (a,b)". A ManagedClusterSetBinding is created in each of
+	// these namespaces.
+	// TODO move this to the api repo.
+	BindingNamespaceSelectorAnnotationKey = "cluster.open-cluster-management.io/experimental-binding-namespace-selector"
+
+	// AutoBindingLabelKey is the label on the ManagedClusterSetBindings created by the auto binding controller,
+	// only these bindings are garbage collected by the controller.
+	AutoBindingLabelKey = "cluster.open-cluster-management.io/auto-binding"
+)
+
+// autoBindingController creates the ManagedClusterSetBindings in the namespaces granted by a ManagedClusterSet,
+// and deletes the ones it created once the namespaces are no longer granted.
+type autoBindingController struct {
+	clusterClient           clientset.Interface
+	clusterSetLister        clusterlisterv1beta2.ManagedClusterSetLister
+	clusterSetBindingLister clusterlisterv1beta2.ManagedClusterSetBindingLister
+	namespaceLister         corev1listers.NamespaceLister
+	eventRecorder           events.Recorder
+}
+
+// NewAutoBindingController creates a new controller provisioning the ManagedClusterSetBindings
+func NewAutoBindingController(
+	clusterClient clientset.Interface,
+	clusterSetInformer clusterinformerv1beta2.ManagedClusterSetInformer,
+	clusterSetBindingInformer clusterinformerv1beta2.ManagedClusterSetBindingInformer,
+	namespaceInformer corev1informers.NamespaceInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &autoBindingController{
+		clusterClient:           clusterClient,
+		clusterSetLister:        clusterSetInformer.Lister(),
+		clusterSetBindingLister: clusterSetBindingInformer.Lister(),
+		namespaceLister:         namespaceInformer.Lister(),
+		eventRecorder:           recorder.WithComponentSuffix("managed-clusterset-auto-binding-controller"),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, clusterSetInformer.Informer()).
+		WithFilteredEventsInformersQueueKeysFunc(
+			func(obj runtime.Object) []string {
+				binding, ok := obj.(*clusterv1beta2.ManagedClusterSetBinding)
+				if !ok {
+					return nil
+				}
+				return []string{binding.Spec.ClusterSet}
+			},
+			queue.FileterByLabel(AutoBindingLabelKey),
+			clusterSetBindingInformer.Informer()).
+		WithInformersQueueKeysFunc(c.grantingClusterSets, namespaceInformer.Informer()).
+		WithSync(c.sync).
+		ToController("ManagedClusterSetAutoBindingController", recorder)
+}
+
+func (c *autoBindingController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	clusterSetName := syncCtx.QueueKey()
+	if len(clusterSetName) == 0 {
+		return nil
+	}
+	logger.V(4).Info("Reconciling the auto bindings of ManagedClusterSet", "clusterSetName", clusterSetName)
+
+	// the namespaces granted by the clusterset, the bindings are garbage collected if the clusterset is deleted
+	// or its grant is revoked.
+	granted := map[string]bool{}
+	clusterSet, err := c.clusterSetLister.Get(clusterSetName)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	case clusterSet.DeletionTimestamp.IsZero():
+		granted, err = c.grantedNamespaces(clusterSet)
+		if err != nil {
+			c.eventRecorder.Warningf("InvalidBindingNamespaceSelector",
+				"The binding namespace selector of ManagedClusterSet %q is invalid: %v", clusterSetName, err)
+			return nil
+		}
+	}
+
+	var errs []error
+	for namespace := range granted {
+		if err := c.ensureBinding(ctx, namespace, clusterSetName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	bindings, err := c.clusterSetBindingLister.List(labels.SelectorFromSet(labels.Set{AutoBindingLabelKey: "true"}))
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		if binding.Spec.ClusterSet != clusterSetName || granted[binding.Namespace] {
+			continue
+		}
+		err := c.clusterClient.ClusterV1beta2().ManagedClusterSetBindings(binding.Namespace).Delete(
+			ctx, binding.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		c.eventRecorder.Eventf("ManagedClusterSetBindingDeleted",
+			"ManagedClusterSetBinding %s/%s is deleted since the namespace is no longer granted", binding.Namespace, binding.Name)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ensureBinding creates the binding of the clusterset in the namespace. An existing binding with the same name is
+// kept as it is, since it may be created by the users.
+func (c *autoBindingController) ensureBinding(ctx context.Context, namespace, clusterSetName string) error {
+	_, err := c.clusterSetBindingLister.ManagedClusterSetBindings(namespace).Get(clusterSetName)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.IsNotFound(err):
+		return err
+	}
+
+	binding := &clusterv1beta2.ManagedClusterSetBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterSetName,
+			Namespace: namespace,
+			Labels:    map[string]string{AutoBindingLabelKey: "true"},
+		},
+		Spec: clusterv1beta2.ManagedClusterSetBindingSpec{
+			ClusterSet: clusterSetName,
+		},
+	}
+	_, err = c.clusterClient.ClusterV1beta2().ManagedClusterSetBindings(namespace).Create(ctx, binding, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create ManagedClusterSetBinding %s/%s: %w", namespace, clusterSetName, err)
+	}
+	c.eventRecorder.Eventf("ManagedClusterSetBindingCreated",
+		"ManagedClusterSetBinding %s/%s is created", namespace, clusterSetName)
+	return nil
+}
+
+// grantedNamespaces returns the active namespaces selected by the binding namespace selector of the clusterset.
+func (c *autoBindingController) grantedNamespaces(clusterSet *clusterv1beta2.ManagedClusterSet) (map[string]bool, error) {
+	granted := map[string]bool{}
+	value, ok := clusterSet.Annotations[BindingNamespaceSelectorAnnotationKey]
+	if !ok {
+		return granted, nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return nil, fmt.Errorf("the selector must not select all the namespaces")
+	}
+
+	namespaces, err := c.namespaceLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, namespace := range namespaces {
+		if namespace.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		granted[namespace.Name] = true
+	}
+	return granted, nil
+}
+
+// grantingClusterSets returns the names of the clustersets with a binding namespace selector, a change of any
+// namespace may change the namespaces they grant.
+func (c *autoBindingController) grantingClusterSets(_ runtime.Object) []string {
+	clusterSets, err := c.clusterSetLister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, clusterSet := range clusterSets {
+		if _, ok := clusterSet.Annotations[BindingNamespaceSelectorAnnotationKey]; ok {
+			names = append(names, clusterSet.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,150 @@
+package managedclustersetbinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newGrantingClusterSet(name, selector string) *clusterv1beta2.ManagedClusterSet {
+	return &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{BindingNamespaceSelectorAnnotationKey: selector},
+		},
+	}
+}
+
+func newAutoBinding(name, namespace string) *clusterv1beta2.ManagedClusterSetBinding {
+	binding := newManagedClusterSetBinding(name, namespace)
+	binding.Labels = map[string]string{AutoBindingLabelKey: "true"}
+	return binding
+}
+
+func TestSyncAutoBinding(t *testing.T) {
+	namespaces := []runtime.Object{
+		newNamespace("team-a", map[string]string{"team": "a"}),
+		newNamespace("team-b", map[string]string{"team": "b"}),
+	}
+
+	cases := []struct {
+		name            string
+		clusterSet      *clusterv1beta2.ManagedClusterSet
+		bindings        []*clusterv1beta2.ManagedClusterSetBinding
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:       "create binding in the granted namespace",
+			clusterSet: newGrantingClusterSet("dev", "team=a"),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create")
+				binding := actions[0].(clienttesting.CreateAction).GetObject().(*clusterv1beta2.ManagedClusterSetBinding)
+				if binding.Namespace != "team-a" || binding.Spec.ClusterSet != "dev" {
+					t.Errorf("unexpected binding %v", binding)
+				}
+				if binding.Labels[AutoBindingLabelKey] != "true" {
+					t.Errorf("expected the binding is labeled, but got %v", binding.Labels)
+				}
+			},
+		},
+		{
+			name:       "binding created by users is kept",
+			clusterSet: newGrantingClusterSet("dev", "team=a"),
+			bindings: []*clusterv1beta2.ManagedClusterSetBinding{
+				newManagedClusterSetBinding("dev", "team-a"),
+				newManagedClusterSetBinding("dev", "team-b"),
+			},
+			validateActions: testingcommon.AssertNoActions,
+		},
+		{
+			name:       "delete binding in the namespace no longer granted",
+			clusterSet: newGrantingClusterSet("dev", "team=a"),
+			bindings: []*clusterv1beta2.ManagedClusterSetBinding{
+				newAutoBinding("dev", "team-a"),
+				newAutoBinding("dev", "team-b"),
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete")
+				testingcommon.AssertDelete(t, actions[0], "managedclustersetbindings", "team-b", "dev")
+			},
+		},
+		{
+			name: "delete bindings once the clusterset is deleted",
+			bindings: []*clusterv1beta2.ManagedClusterSetBinding{
+				newAutoBinding("dev", "team-a"),
+				newAutoBinding("prod", "team-a"),
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "delete")
+				testingcommon.AssertDelete(t, actions[0], "managedclustersetbindings", "team-a", "dev")
+			},
+		},
+		{
+			name:            "invalid selector",
+			clusterSet:      newGrantingClusterSet("dev", "team in (a"),
+			bindings:        []*clusterv1beta2.ManagedClusterSetBinding{newAutoBinding("dev", "team-a")},
+			validateActions: testingcommon.AssertNoActions,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if c.clusterSet != nil {
+				objects = append(objects, c.clusterSet)
+			}
+			for _, binding := range c.bindings {
+				objects = append(objects, binding)
+			}
+			clusterClient := clusterfake.NewSimpleClientset(objects...)
+			clusterInformers := clusterinformers.NewSharedInformerFactory(clusterClient, 5*time.Minute)
+			if c.clusterSet != nil {
+				if err := clusterInformers.Cluster().V1beta2().ManagedClusterSets().Informer().GetStore().Add(c.clusterSet); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, binding := range c.bindings {
+				if err := clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings().Informer().GetStore().Add(binding); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeInformers := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 5*time.Minute)
+			for _, namespace := range namespaces {
+				if err := kubeInformers.Core().V1().Namespaces().Informer().GetStore().Add(namespace); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := autoBindingController{
+				clusterClient:           clusterClient,
+				clusterSetLister:        clusterInformers.Cluster().V1beta2().ManagedClusterSets().Lister(),
+				clusterSetBindingLister: clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings().Lister(),
+				namespaceLister:         kubeInformers.Core().V1().Namespaces().Lister(),
+				eventRecorder:           eventstesting.NewTestingEventRecorder(t),
+			}
+			clusterClient.ClearActions()
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "dev")); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			c.validateActions(t, clusterClient.Actions())
+		})
+	}
+}
@@ -2,18 +2,23 @@ package hub
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	certv1 "k8s.io/api/certificates/v1"
 	certv1beta1 "k8s.io/api/certificates/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
@@ -22,15 +27,20 @@ import (
 	clusterv1client "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterscheme "open-cluster-management.io/api/client/cluster/clientset/versioned/scheme"
 	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	workv1client "open-cluster-management.io/api/client/work/clientset/versioned"
 	workv1informers "open-cluster-management.io/api/client/work/informers/externalversions"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
 
 	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/sharding"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
 	"open-cluster-management.io/ocm/pkg/registration/hub/addon"
+	"open-cluster-management.io/ocm/pkg/registration/hub/autoaccept"
+	"open-cluster-management.io/ocm/pkg/registration/hub/cabundle"
+	"open-cluster-management.io/ocm/pkg/registration/hub/clusterclaim"
 	"open-cluster-management.io/ocm/pkg/registration/hub/clusterrole"
 	"open-cluster-management.io/ocm/pkg/registration/hub/csr"
 	"open-cluster-management.io/ocm/pkg/registration/hub/gc"
@@ -38,13 +48,41 @@ import (
 	"open-cluster-management.io/ocm/pkg/registration/hub/managedcluster"
 	"open-cluster-management.io/ocm/pkg/registration/hub/managedclusterset"
 	"open-cluster-management.io/ocm/pkg/registration/hub/managedclustersetbinding"
+	"open-cluster-management.io/ocm/pkg/registration/hub/metrics"
 	"open-cluster-management.io/ocm/pkg/registration/hub/taint"
 )
 
 // HubManagerOptions holds configuration for hub manager controller
 type HubManagerOptions struct {
 	ClusterAutoApprovalUsers []string
-	GCResourceList           []string
+	// ClusterApprovalGroups and ClusterApprovalSAR delegate the approval of the cluster registration requests, a
+	// request is approved if the requester belongs to one of the groups and passes the SubjectAccessReview.
+	ClusterApprovalGroups []string
+	ClusterApprovalSAR    string
+	// ClusterAutoAcceptRules accept the joining managed clusters whose bootstrap csr is requested by a member of
+	// the ClusterAutoAcceptGroups.
+	ClusterAutoAcceptRules  []string
+	ClusterAutoAcceptGroups []string
+	// MaxClusterClaims, MaxClusterClaimSize and ClusterClaimAllowList restrict the custom cluster claims reported
+	// by the managed clusters, the custom claims of a cluster violating them are ignored by the hub.
+	MaxClusterClaims      int
+	MaxClusterClaimSize   int
+	ClusterClaimAllowList []string
+	GCResourceList        []string
+	ClusterSelector       string
+	EnableAutoBinding     bool
+	// HubCABundleConfigMap is the name of the configmap in the namespace of the controller with the current and the
+	// next CA bundles of the hub, they are distributed to all the cluster namespaces.
+	HubCABundleConfigMap string
+	// SPIFFETrustDomain is the trust domain of the SPIFFE IDs of the agents, the csrs with the SPIFFE ID
+	// spiffe://<trust domain>/open-cluster-management/cluster/<cluster name> as the URI SAN are approved only if it is
+	// set.
+	SPIFFETrustDomain string
+	// ClusterCSRAllowedIPCIDRs and ClusterCSRAllowedURIPrefixes allow the agents to request the IP and the URI SANs
+	// in their client certificates, the csrs with the SANs not allowed are not approved.
+	ClusterCSRAllowedIPCIDRs     []string
+	ClusterCSRAllowedURIPrefixes []string
+	ShardingOptions              *sharding.Options
 }
 
 // NewHubManagerOptions returns a HubManagerOptions
@@ -52,6 +90,8 @@ func NewHubManagerOptions() *HubManagerOptions {
 	return &HubManagerOptions{
 		GCResourceList: []string{"addon.open-cluster-management.io/v1alpha1/managedclusteraddons",
 			"work.open-cluster-management.io/v1/manifestworks"},
+		ClusterApprovalSAR: "approve:register.open-cluster-management.io/managedclusters/clientcertificates",
+		ShardingOptions:    sharding.NewOptions(),
 	}
 }
 
@@ -59,35 +99,119 @@ func NewHubManagerOptions() *HubManagerOptions {
 func (m *HubManagerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringSliceVar(&m.ClusterAutoApprovalUsers, "cluster-auto-approval-users", m.ClusterAutoApprovalUsers,
 		"A bootstrap user list whose cluster registration requests can be automatically approved.")
+	fs.StringSliceVar(&m.ClusterApprovalGroups, "cluster-approval-groups", m.ClusterApprovalGroups,
+		"A group list whose members' cluster registration requests can be automatically approved if the requester "+
+			"passes the SubjectAccessReview of --cluster-approval-sar as well.")
+	fs.StringVar(&m.ClusterApprovalSAR, "cluster-approval-sar", m.ClusterApprovalSAR,
+		"The SubjectAccessReview a requester in --cluster-approval-groups must pass to get its cluster registration "+
+			"request approved, the format is <verb>:<group>/<resource>[/<subresource>] and the resource name is the "+
+			"cluster name, so the approval rights can be granted per cluster with RBAC.")
+	fs.StringArrayVar(&m.ClusterAutoAcceptRules, "cluster-auto-accept-rules", m.ClusterAutoAcceptRules,
+		"A rule to accept the joining managed clusters automatically, the flag can be set multiple times. A rule is a "+
+			"label selector on the cluster claims the managed cluster reports when joining, optionally prefixed with the "+
+			"rule name and a colon, e.g. vsphere-dev:platform=vsphere,env!=prod, and requires at least one claim to be "+
+			"present. The matched rule is recorded in an event and an annotation of the managed cluster.")
+	fs.StringSliceVar(&m.ClusterAutoAcceptGroups, "cluster-auto-accept-groups", m.ClusterAutoAcceptGroups,
+		"The groups trusted to join managed clusters, a managed cluster is only accepted by the auto accept rules if "+
+			"its bootstrap csr is requested by a member of the groups. It is required by --cluster-auto-accept-rules.")
+	fs.IntVar(&m.MaxClusterClaims, "max-cluster-claims", m.MaxClusterClaims,
+		"The max number of the custom cluster claims a managed cluster may report, 0 means no limit. The custom cluster "+
+			"claims of a managed cluster violating the limits are ignored by the hub, including the placements, "+
+			"and reported by its ClusterClaimsValid condition. A joining cluster violating them is not accepted automatically.")
+	fs.IntVar(&m.MaxClusterClaimSize, "max-cluster-claim-size", m.MaxClusterClaimSize,
+		"The max size in bytes of the name and the value of a custom cluster claim, 0 means no limit.")
+	fs.StringSliceVar(&m.ClusterClaimAllowList, "cluster-claim-allow-list", m.ClusterClaimAllowList,
+		"The names of the custom cluster claims a managed cluster may report, a name ending with * matches the names "+
+			"with the prefix. All names are allowed if it is empty.")
 	fs.StringSliceVar(&m.GCResourceList, "gc-resource-list", m.GCResourceList,
 		"A list GVR user can customize which are cleaned up after cluster is deleted. Format is group/version/resource, "+
 			"and the default are managedclusteraddon and manifestwork. The resources will be deleted in order."+
 			"The flag works only when ResourceCleanup feature gate is enable.")
+	fs.StringVar(&m.ClusterSelector, "cluster-selector", m.ClusterSelector,
+		"A label selector to scope the managed clusters watched by the controllers, e.g. "+
+			"cluster.open-cluster-management.io/clusterset=shard1 to only manage the clusters of a clusterset shard. "+
+			"The managed clusters not selected are ignored, so they should be managed by another shard.")
+	fs.BoolVar(&m.EnableAutoBinding, "enable-auto-clusterset-binding", m.EnableAutoBinding,
+		"Create the ManagedClusterSetBindings in the namespaces granted by the "+
+			"cluster.open-cluster-management.io/experimental-binding-namespace-selector annotation of the "+
+			"ManagedClusterSets, and delete them once the namespaces are no longer granted.")
+	fs.StringVar(&m.HubCABundleConfigMap, "hub-ca-bundle-configmap", m.HubCABundleConfigMap,
+		"The name of the configmap in the namespace of the controller with the current and the next CA bundles of "+
+			"the hub in the keys ca-bundle.crt and next-ca-bundle.crt. The CA bundles are distributed to the "+
+			"hub-ca-bundle configmap in each cluster namespace, so the agents trust the next CA before the hub CA "+
+			"is rotated. The CA bundles are not distributed if it is empty.")
+	fs.StringVar(&m.SPIFFETrustDomain, "spiffe-trust-domain", m.SPIFFETrustDomain,
+		"The trust domain of the SPIFFE IDs of the agents. The csr of a managed cluster may have the SPIFFE ID "+
+			"spiffe://<trust domain>/open-cluster-management/cluster/<cluster name> as its only URI SAN, the csrs with "+
+			"the SPIFFE IDs are not approved if it is empty.")
+	fs.StringSliceVar(&m.ClusterCSRAllowedIPCIDRs, "cluster-csr-allowed-ip-cidrs", m.ClusterCSRAllowedIPCIDRs,
+		"The networks in CIDR notation of the IP SANs allowed in the csrs of the managed clusters, the csrs with the "+
+			"IP SANs are not approved if it is empty.")
+	fs.StringSliceVar(&m.ClusterCSRAllowedURIPrefixes, "cluster-csr-allowed-uri-prefixes", m.ClusterCSRAllowedURIPrefixes,
+		"The prefixes of the URI SANs allowed in the csrs of the managed clusters besides the SPIFFE ID, e.g. "+
+			"https://agents.example.org/{cluster}/ where {cluster} is replaced by the cluster name, so a cluster is "+
+			"only allowed to request its own URIs. The csrs with other URI SANs are not approved.")
+	m.ShardingOptions.AddFlags(fs)
+}
+
+// validateClusterApproval validates the options delegating the approval of the cluster registration requests and
+// returns the resource attributes of --cluster-approval-sar. They are validated even if the ManagedClusterAutoApproval
+// feature gate is disabled, and the approval groups are rejected without the gate since they would be ignored.
+func (m *HubManagerOptions) validateClusterApproval(autoApprovalEnabled bool) (authorizationv1.ResourceAttributes, error) {
+	resourceAttributes, err := csr.ParseResourceAttributes(m.ClusterApprovalSAR)
+	if err != nil {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf("invalid --cluster-approval-sar: %w", err)
+	}
+	if len(m.ClusterApprovalGroups) > 0 && !autoApprovalEnabled {
+		return authorizationv1.ResourceAttributes{}, fmt.Errorf(
+			"--cluster-approval-groups requires the %s feature gate", ocmfeature.ManagedClusterAutoApproval)
+	}
+	return resourceAttributes, nil
 }
 
 // RunControllerManager starts the controllers on hub to manage spoke cluster registration.
 func (m *HubManagerOptions) RunControllerManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
-	kubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+	if err := features.SetupWatchListClient(); err != nil {
+		return err
+	}
+
+	if err := m.ShardingOptions.Validate(); err != nil {
+		return err
+	}
+	kubeConfig := controllerContext.KubeConfig
+	var sharder *sharding.Sharder
+	if m.ShardingOptions.Enabled() {
+		sharderClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+		if err != nil {
+			return err
+		}
+		sharder = sharding.NewSharder(
+			sharderClient, controllerContext.OperatorNamespace, "registration-controller", m.ShardingOptions)
+		// the writes of the controllers are fenced by the shard lease of the replica.
+		kubeConfig = sharder.Fence(controllerContext.KubeConfig)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
 
-	metadataClient, err := metadata.NewForConfig(controllerContext.KubeConfig)
+	metadataClient, err := metadata.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
 
-	clusterClient, err := clusterv1client.NewForConfig(controllerContext.KubeConfig)
+	clusterClient, err := clusterv1client.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
 
-	workClient, err := workv1client.NewForConfig(controllerContext.KubeConfig)
+	workClient, err := workv1client.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
 
-	addOnClient, err := addonclient.NewForConfig(controllerContext.KubeConfig)
+	addOnClient, err := addonclient.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
@@ -117,10 +241,38 @@ func (m *HubManagerOptions) RunControllerManager(ctx context.Context, controller
 	return m.RunControllerManagerWithInformers(
 		ctx, controllerContext,
 		kubeClient, metadataClient, clusterClient, addOnClient,
-		kubeInfomers, clusterInformers, workInformers, addOnInformers,
+		kubeInfomers, clusterInformers, workInformers, addOnInformers, sharder,
 	)
 }
 
+// newSelectedClusterInformerFactory returns a separate cluster informer factory whose managed cluster informer only
+// watches the managed clusters selected by the label selector, it returns nil if the selector is empty. It is only
+// used by the controllers reconciling a single managed cluster, the controllers need the whole view of the clusters,
+// e.g. the clusterset controllers, use the unfiltered informers.
+func newSelectedClusterInformerFactory(clusterClient clusterv1client.Interface,
+	clusterSelector string) (clusterv1informers.SharedInformerFactory, error) {
+	if len(clusterSelector) == 0 {
+		return nil, nil
+	}
+
+	selector, err := labels.Parse(clusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector %q: %v", clusterSelector, err)
+	}
+	clusterInformers := clusterv1informers.NewSharedInformerFactory(clusterClient, 30*time.Minute)
+	clusterInformers.InformerFor(&clusterv1.ManagedCluster{},
+		func(client clusterv1client.Interface, resync time.Duration) cache.SharedIndexInformer {
+			return clusterv1informer.NewFilteredManagedClusterInformer(client, resync,
+				cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+				func(listOptions *metav1.ListOptions) {
+					listOptions.LabelSelector = selector.String()
+				})
+		})
+	return clusterInformers, nil
+}
+
+// RunControllerManagerWithInformers starts the controllers with the clients and informers. The controllers
+// reconciling a single managed cluster only handle the clusters owned by the shard if the sharder is not nil.
 func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	ctx context.Context,
 	controllerContext *controllercmd.ControllerContext,
@@ -132,35 +284,116 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	clusterInformers clusterv1informers.SharedInformerFactory,
 	workInformers workv1informers.SharedInformerFactory,
 	addOnInformers addoninformers.SharedInformerFactory,
+	sharder *sharding.Sharder,
 ) error {
 	logger := klog.FromContext(ctx)
+	autoAcceptRules, err := autoaccept.ParseRules(m.ClusterAutoAcceptRules)
+	if err != nil {
+		return err
+	}
+	if len(autoAcceptRules) > 0 && len(m.ClusterAutoAcceptGroups) == 0 {
+		return fmt.Errorf("--cluster-auto-accept-groups is required by --cluster-auto-accept-rules")
+	}
+	autoApprovalEnabled := features.HubMutableFeatureGate.Enabled(ocmfeature.ManagedClusterAutoApproval)
+	approvalResourceAttributes, err := m.validateClusterApproval(autoApprovalEnabled)
+	if err != nil {
+		return err
+	}
+	if !autoApprovalEnabled && len(m.ClusterAutoApprovalUsers) > 0 {
+		klog.Warningf("--cluster-auto-approval-users is ignored since the %s feature gate is disabled",
+			ocmfeature.ManagedClusterAutoApproval)
+	}
+	clusterClaimPolicy := clusterclaim.Policy{
+		MaxClaims:    m.MaxClusterClaims,
+		MaxClaimSize: m.MaxClusterClaimSize,
+		AllowedNames: m.ClusterClaimAllowList,
+	}
+	if err := clusterClaimPolicy.Validate(); err != nil {
+		return err
+	}
+
+	// the controllers reconciling a single managed cluster only handle the clusters selected by the cluster selector
+	// and owned by the shard, the others need the whole view of the clusters and run in every replica.
+	shardedClusterInformer := clusterInformers.Cluster().V1().ManagedClusters()
+	selectedClusterInformers, err := newSelectedClusterInformerFactory(clusterClient, m.ClusterSelector)
+	if err != nil {
+		return err
+	}
+	if selectedClusterInformers != nil {
+		shardedClusterInformer = selectedClusterInformers.Cluster().V1().ManagedClusters()
+	}
+	shardedLeaseInformer := kubeInformers.Coordination().V1().Leases()
+	shardedRoleInformer := kubeInformers.Rbac().V1().Roles()
+	shardedRoleBindingInformer := kubeInformers.Rbac().V1().RoleBindings()
+	shardedClusterRoleInformer := kubeInformers.Rbac().V1().ClusterRoles()
+	shardedClusterRoleBindingInformer := kubeInformers.Rbac().V1().ClusterRoleBindings()
+	shardedConfigMapInformer := kubeInformers.Core().V1().ConfigMaps()
+	shardedAddOnInformer := addOnInformers.Addon().V1alpha1().ManagedClusterAddOns()
+	if sharder != nil {
+		// every event source of the sharded controllers is filtered, so the objects of the clusters owned by the
+		// other shards are not reconciled by this replica.
+		shardedClusterInformer = sharding.NewManagedClusterInformer(shardedClusterInformer, sharder)
+		clusterLister := shardedClusterInformer.Lister()
+		shardedLeaseInformer = sharding.NewLeaseInformer(shardedLeaseInformer, clusterLister, sharder)
+		shardedRoleInformer = sharding.NewClusterLabeledInformer(
+			shardedRoleInformer.Informer(), shardedRoleInformer.Lister(), clusterLister, sharder)
+		shardedRoleBindingInformer = sharding.NewClusterLabeledInformer(
+			shardedRoleBindingInformer.Informer(), shardedRoleBindingInformer.Lister(), clusterLister, sharder)
+		shardedClusterRoleInformer = sharding.NewClusterLabeledInformer(
+			shardedClusterRoleInformer.Informer(), shardedClusterRoleInformer.Lister(), clusterLister, sharder)
+		shardedClusterRoleBindingInformer = sharding.NewClusterLabeledInformer(
+			shardedClusterRoleBindingInformer.Informer(), shardedClusterRoleBindingInformer.Lister(), clusterLister, sharder)
+		shardedConfigMapInformer = sharding.NewClusterNamespacedInformer(
+			shardedConfigMapInformer.Informer(), shardedConfigMapInformer.Lister(), clusterLister, sharder)
+		shardedAddOnInformer = sharding.NewClusterNamespacedInformer(
+			shardedAddOnInformer.Informer(), shardedAddOnInformer.Lister(), clusterLister, sharder)
+		go sharder.Run(ctx)
+	}
+
 	managedClusterController := managedcluster.NewManagedClusterController(
 		kubeClient,
 		clusterClient,
-		clusterInformers.Cluster().V1().ManagedClusters(),
-		kubeInformers.Rbac().V1().Roles(),
-		kubeInformers.Rbac().V1().ClusterRoles(),
-		kubeInformers.Rbac().V1().RoleBindings(),
-		kubeInformers.Rbac().V1().ClusterRoleBindings(),
+		shardedClusterInformer,
+		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+		shardedRoleInformer,
+		shardedClusterRoleInformer,
+		shardedRoleBindingInformer,
+		shardedClusterRoleBindingInformer,
 		controllerContext.EventRecorder,
 	)
 
 	taintController := taint.NewTaintController(
 		clusterClient,
-		clusterInformers.Cluster().V1().ManagedClusters(),
+		shardedClusterInformer,
 		controllerContext.EventRecorder,
 	)
 
-	csrReconciles := []csr.Reconciler{csr.NewCSRRenewalReconciler(kubeClient, controllerContext.EventRecorder)}
-	if features.HubMutableFeatureGate.Enabled(ocmfeature.ManagedClusterAutoApproval) {
+	sanPolicy, err := csr.NewSANPolicy(m.SPIFFETrustDomain, m.ClusterCSRAllowedIPCIDRs, m.ClusterCSRAllowedURIPrefixes)
+	if err != nil {
+		return err
+	}
+	csrReconciles := []csr.Reconciler{csr.NewCSRRenewalReconciler(kubeClient, sanPolicy, controllerContext.EventRecorder)}
+	if autoApprovalEnabled {
 		csrReconciles = append(csrReconciles, csr.NewCSRBootstrapReconciler(
 			kubeClient,
 			m.ClusterAutoApprovalUsers,
+			sanPolicy,
 			controllerContext.EventRecorder,
 		))
+
+		if len(m.ClusterApprovalGroups) > 0 {
+			csrReconciles = append(csrReconciles, csr.NewCSRGroupPolicyReconciler(
+				kubeClient,
+				m.ClusterApprovalGroups,
+				approvalResourceAttributes,
+				sanPolicy,
+				controllerContext.EventRecorder,
+			))
+		}
 	}
 
 	var csrController factory.Controller
+	var csrInformer cache.SharedIndexInformer
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.V1beta1CSRAPICompatibility) {
 		v1CSRSupported, v1beta1CSRSupported, err := helpers.IsCSRSupported(kubeClient)
 		if err != nil {
@@ -168,8 +401,9 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		}
 
 		if !v1CSRSupported && v1beta1CSRSupported {
+			csrInformer = kubeInformers.Certificates().V1beta1().CertificateSigningRequests().Informer()
 			csrController = csr.NewCSRApprovingController[*certv1beta1.CertificateSigningRequest](
-				kubeInformers.Certificates().V1beta1().CertificateSigningRequests().Informer(),
+				csrInformer,
 				kubeInformers.Certificates().V1beta1().CertificateSigningRequests().Lister(),
 				csr.NewCSRV1beta1Approver(kubeClient),
 				csrReconciles,
@@ -179,14 +413,18 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		}
 	}
 	if csrController == nil {
+		csrInformer = kubeInformers.Certificates().V1().CertificateSigningRequests().Informer()
 		csrController = csr.NewCSRApprovingController[*certv1.CertificateSigningRequest](
-			kubeInformers.Certificates().V1().CertificateSigningRequests().Informer(),
+			csrInformer,
 			kubeInformers.Certificates().V1().CertificateSigningRequests().Lister(),
 			csr.NewCSRV1Approver(kubeClient),
 			csrReconciles,
 			controllerContext.EventRecorder,
 		)
 	}
+	if err := metrics.RegisterCSRMetrics(csrInformer); err != nil {
+		return err
+	}
 
 	mcRecorder, err := commonhelpers.NewEventRecorder(ctx, clusterscheme.Scheme, kubeClient, "registration-controller")
 	if err != nil {
@@ -195,16 +433,38 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	leaseController := lease.NewClusterLeaseController(
 		kubeClient,
 		clusterClient,
-		clusterInformers.Cluster().V1().ManagedClusters(),
-		kubeInformers.Coordination().V1().Leases(),
+		shardedClusterInformer,
+		shardedLeaseInformer,
 		controllerContext.EventRecorder,
 		mcRecorder,
 	)
 
+	var autoAcceptController factory.Controller
+	if len(autoAcceptRules) > 0 {
+		autoAcceptController = autoaccept.NewAutoAcceptController(
+			clusterClient,
+			shardedClusterInformer,
+			csrInformer,
+			autoAcceptRules,
+			m.ClusterAutoAcceptGroups,
+			clusterClaimPolicy,
+			controllerContext.EventRecorder,
+			mcRecorder,
+		)
+	}
+
+	// the cluster claim policy controller also runs when the policy is disabled to remove the stale conditions.
+	clusterClaimPolicyController := clusterclaim.NewClusterClaimPolicyController(
+		clusterClient,
+		shardedClusterInformer,
+		clusterClaimPolicy,
+		controllerContext.EventRecorder,
+	)
+
 	clockSyncController := lease.NewClockSyncController(
 		clusterClient,
-		clusterInformers.Cluster().V1().ManagedClusters(),
-		kubeInformers.Coordination().V1().Leases(),
+		shardedClusterInformer,
+		shardedLeaseInformer,
 		controllerContext.EventRecorder,
 	)
 
@@ -215,6 +475,13 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		controllerContext.EventRecorder,
 	)
 
+	membershipRuleController := managedclusterset.NewMembershipRuleController(
+		clusterClient,
+		clusterInformers.Cluster().V1().ManagedClusters(),
+		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+		controllerContext.EventRecorder,
+	)
+
 	managedClusterSetBindingController := managedclustersetbinding.NewManagedClusterSetBindingController(
 		clusterClient,
 		clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
@@ -222,6 +489,21 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		controllerContext.EventRecorder,
 	)
 
+	// the namespaces granted by the clustersets are not labeled with the cluster name, so they are watched by
+	// a separate informer.
+	var autoBindingController factory.Controller
+	var namespaceInformers kubeinformers.SharedInformerFactory
+	if m.EnableAutoBinding {
+		namespaceInformers = kubeinformers.NewSharedInformerFactory(kubeClient, 30*time.Minute)
+		autoBindingController = managedclustersetbinding.NewAutoBindingController(
+			clusterClient,
+			clusterInformers.Cluster().V1beta2().ManagedClusterSets(),
+			clusterInformers.Cluster().V1beta2().ManagedClusterSetBindings(),
+			namespaceInformers.Core().V1().Namespaces(),
+			controllerContext.EventRecorder,
+		)
+	}
+
 	clusterroleController := clusterrole.NewManagedClusterClusterroleController(
 		kubeClient,
 		clusterInformers.Cluster().V1().ManagedClusters(),
@@ -243,6 +525,13 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		controllerContext.EventRecorder,
 	)
 
+	addOnHealthAggregationController := addon.NewAddOnHealthAggregationController(
+		clusterClient,
+		shardedClusterInformer,
+		shardedAddOnInformer,
+		controllerContext.EventRecorder,
+	)
+
 	var defaultManagedClusterSetController, globalManagedClusterSetController factory.Controller
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.DefaultClusterSet) {
 		defaultManagedClusterSetController = managedclusterset.NewDefaultManagedClusterSetController(
@@ -257,11 +546,31 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 		)
 	}
 
+	// the source configmap is not labeled with the cluster name, so it is watched by a separate informer.
+	var caBundleController factory.Controller
+	var caBundleInformers kubeinformers.SharedInformerFactory
+	if len(m.HubCABundleConfigMap) > 0 {
+		caBundleInformers = kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 30*time.Minute,
+			kubeinformers.WithNamespace(controllerContext.OperatorNamespace),
+			kubeinformers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+				listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", m.HubCABundleConfigMap).String()
+			}))
+		caBundleController = cabundle.NewCABundleController(
+			kubeClient,
+			shardedClusterInformer,
+			caBundleInformers.Core().V1().ConfigMaps(),
+			shardedConfigMapInformer,
+			controllerContext.OperatorNamespace,
+			m.HubCABundleConfigMap,
+			controllerContext.EventRecorder,
+		)
+	}
+
 	gcController := gc.NewGCController(
 		kubeInformers.Rbac().V1().ClusterRoles().Lister(),
 		kubeInformers.Rbac().V1().ClusterRoleBindings().Lister(),
 		kubeInformers.Rbac().V1().RoleBindings().Lister(),
-		clusterInformers.Cluster().V1().ManagedClusters(),
+		shardedClusterInformer,
 		workInformers.Work().V1().ManifestWorks().Lister(),
 		clusterClient,
 		kubeClient,
@@ -272,6 +581,9 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	)
 
 	go clusterInformers.Start(ctx.Done())
+	if selectedClusterInformers != nil {
+		go selectedClusterInformers.Start(ctx.Done())
+	}
 	go workInformers.Start(ctx.Done())
 	go kubeInformers.Start(ctx.Done())
 	go addOnInformers.Start(ctx.Done())
@@ -281,16 +593,31 @@ func (m *HubManagerOptions) RunControllerManagerWithInformers(
 	go csrController.Run(ctx, 1)
 	go leaseController.Run(ctx, 1)
 	go clockSyncController.Run(ctx, 1)
+	if autoAcceptController != nil {
+		go autoAcceptController.Run(ctx, 1)
+	}
+	go clusterClaimPolicyController.Run(ctx, 1)
 	go managedClusterSetController.Run(ctx, 1)
+	go membershipRuleController.Run(ctx, 1)
 	go managedClusterSetBindingController.Run(ctx, 1)
+	if autoBindingController != nil {
+		go namespaceInformers.Start(ctx.Done())
+		go autoBindingController.Run(ctx, 1)
+	}
 	go clusterroleController.Run(ctx, 1)
 	go addOnHealthCheckController.Run(ctx, 1)
 	go addOnFeatureDiscoveryController.Run(ctx, 1)
+	go addOnHealthAggregationController.Run(ctx, 1)
 	if features.HubMutableFeatureGate.Enabled(ocmfeature.DefaultClusterSet) {
 		go defaultManagedClusterSetController.Run(ctx, 1)
 		go globalManagedClusterSetController.Run(ctx, 1)
 	}
 
+	if caBundleController != nil {
+		go caBundleInformers.Start(ctx.Done())
+		go caBundleController.Run(ctx, 1)
+	}
+
 	go gcController.Run(ctx, 1)
 
 	<-ctx.Done()
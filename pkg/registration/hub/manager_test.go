@@ -3,17 +3,22 @@ package hub
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	fakeclusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	ocmfeature "open-cluster-management.io/api/feature"
 
 	"open-cluster-management.io/ocm/pkg/features"
@@ -99,3 +104,118 @@ var _ = ginkgo.Describe("start hub manager", func() {
 		stopHub()
 	})
 })
+
+func TestNewSelectedClusterInformerFactory(t *testing.T) {
+	newCluster := func(name, clusterSet string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{clusterv1beta2.ClusterSetLabel: clusterSet},
+			},
+		}
+	}
+
+	cases := []struct {
+		name             string
+		selector         string
+		expectedClusters int
+		expectedErr      bool
+	}{
+		{
+			name: "no selector",
+		},
+		{
+			name:             "clusterset selector",
+			selector:         clusterv1beta2.ClusterSetLabel + "=shard1",
+			expectedClusters: 2,
+		},
+		{
+			name:        "invalid selector",
+			selector:    "a=b=c",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := fakeclusterclient.NewSimpleClientset(
+				newCluster("cluster1", "shard1"), newCluster("cluster2", "shard1"), newCluster("cluster3", "shard2"))
+			clusterInformers, err := newSelectedClusterInformerFactory(clusterClient, c.selector)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(c.selector) == 0 {
+				if clusterInformers != nil {
+					t.Errorf("expect no informer factory without selector")
+				}
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			lister := clusterInformers.Cluster().V1().ManagedClusters().Lister()
+			clusterInformers.Start(ctx.Done())
+			clusterInformers.WaitForCacheSync(ctx.Done())
+
+			clusters, err := lister.List(labels.Everything())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(clusters) != c.expectedClusters {
+				t.Errorf("expect %d clusters, but got %d", c.expectedClusters, len(clusters))
+			}
+		})
+	}
+}
+
+func TestValidateClusterApproval(t *testing.T) {
+	cases := []struct {
+		name                string
+		options             *HubManagerOptions
+		autoApprovalEnabled bool
+		expectedErr         bool
+	}{
+		{
+			name:    "default options without the feature gate",
+			options: NewHubManagerOptions(),
+		},
+		{
+			name: "invalid sar without the feature gate",
+			options: &HubManagerOptions{
+				ClusterApprovalSAR: "approve",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "approval groups without the feature gate",
+			options: &HubManagerOptions{
+				ClusterApprovalGroups: []string{"cluster-bootstrappers"},
+				ClusterApprovalSAR:    "approve:register.open-cluster-management.io/managedclusters/clientcertificates",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "approval groups with the feature gate",
+			options: &HubManagerOptions{
+				ClusterApprovalGroups: []string{"cluster-bootstrappers"},
+				ClusterApprovalSAR:    "approve:register.open-cluster-management.io/managedclusters/clientcertificates",
+			},
+			autoApprovalEnabled: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.options.validateClusterApproval(c.autoApprovalEnabled)
+			if c.expectedErr != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
+)
+
+const (
+	// Constants for metric names.
+	RegistrationSubsystem  = "registration"
+	CSRsKey                = "csrs"
+	CSRApprovalDurationKey = "csr_approval_duration_seconds"
+	CSRHaltedClustersKey   = "csr_halted_clusters"
+
+	// The approval states of the csrs.
+	CSRStatePending  = "pending"
+	CSRStateApproved = "approved"
+	CSRStateDenied   = "denied"
+	CSRStateFailed   = "failed"
+)
+
+var (
+	csrApprovalDuration = k8smetrics.NewHistogramVec(&k8smetrics.HistogramOpts{
+		Subsystem:      RegistrationSubsystem,
+		Name:           CSRApprovalDurationKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "How long in seconds it takes from the creation of a csr of a managed cluster until it is approved.",
+		Buckets:        k8smetrics.ExponentialBuckets(0.1, 2, 16),
+	}, []string{"signer"})
+
+	csrsDesc = k8smetrics.NewDesc(
+		k8smetrics.BuildFQName("", RegistrationSubsystem, CSRsKey),
+		"Number of the csrs of the managed clusters by signer and approval state.",
+		[]string{"signer", "state"}, nil, k8smetrics.ALPHA, "")
+
+	csrHaltedClustersDesc = k8smetrics.NewDesc(
+		k8smetrics.BuildFQName("", RegistrationSubsystem, CSRHaltedClustersKey),
+		"Number of the managed clusters halted from creating csrs since they reach the csr threshold.",
+		nil, nil, k8smetrics.ALPHA, "")
+
+	// csrs is the collector of the csrs, it is registered once and collects the csrs in the store of the informer
+	// registered last.
+	csrs         = &csrCollector{}
+	registerOnce sync.Once
+)
+
+func init() {
+	legacyregistry.MustRegister(csrApprovalDuration)
+}
+
+// RegisterCSRMetrics exports the metrics of the csrs in the informer, the informer is supposed to have the csrs of
+// the managed clusters only. The approval duration is observed once a csr turns approved.
+func RegisterCSRMetrics(csrInformer cache.SharedIndexInformer) error {
+	_, err := csrInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: observeApproval,
+	})
+	if err != nil {
+		return err
+	}
+	csrs.setStore(csrInformer.GetStore())
+	registerOnce.Do(func() {
+		legacyregistry.CustomMustRegister(csrs)
+	})
+	return nil
+}
+
+func observeApproval(oldObj, newObj interface{}) {
+	_, oldState, _, _, ok := csrInfo(oldObj)
+	if !ok || oldState == CSRStateApproved {
+		return
+	}
+	signer, state, _, duration, ok := csrInfo(newObj)
+	if !ok || state != CSRStateApproved {
+		return
+	}
+	csrApprovalDuration.WithLabelValues(signer).Observe(duration.Seconds())
+}
+
+type csrCollector struct {
+	k8smetrics.BaseStableCollector
+	lock  sync.RWMutex
+	store cache.Store
+}
+
+func (c *csrCollector) setStore(store cache.Store) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.store = store
+}
+
+func (c *csrCollector) DescribeWithStability(ch chan<- *k8smetrics.Desc) {
+	ch <- csrsDesc
+	ch <- csrHaltedClustersDesc
+}
+
+func (c *csrCollector) CollectWithStability(ch chan<- k8smetrics.Metric) {
+	type key struct{ signer, state string }
+	counts := map[key]int{}
+	clusterCounts := map[string]int{}
+	c.lock.RLock()
+	store := c.store
+	c.lock.RUnlock()
+	if store == nil {
+		return
+	}
+	for _, obj := range store.List() {
+		signer, state, cluster, _, ok := csrInfo(obj)
+		if !ok {
+			continue
+		}
+		counts[key{signer: signer, state: state}]++
+		if len(cluster) > 0 {
+			clusterCounts[cluster]++
+		}
+	}
+
+	for k, count := range counts {
+		ch <- k8smetrics.NewLazyConstMetric(csrsDesc, k8smetrics.GaugeValue, float64(count), k.signer, k.state)
+	}
+
+	// the registration agent halts creating csrs once the csrs of the cluster reach the threshold
+	halted := 0
+	for _, count := range clusterCounts {
+		if count >= helpers.ClusterCSRThreshold {
+			halted++
+		}
+	}
+	ch <- k8smetrics.NewLazyConstMetric(csrHaltedClustersDesc, k8smetrics.GaugeValue, float64(halted))
+}
+
+// csrInfo returns the signer, the approval state and the cluster of the csr, and how long it took to approve the
+// csr if it is approved.
+func csrInfo(obj interface{}) (signer, state, cluster string, approvalDuration time.Duration, ok bool) {
+	switch csr := obj.(type) {
+	case *certificatesv1.CertificateSigningRequest:
+		var approved, denied, failed bool
+		for _, condition := range csr.Status.Conditions {
+			switch condition.Type {
+			case certificatesv1.CertificateDenied:
+				denied = true
+			case certificatesv1.CertificateFailed:
+				failed = true
+			case certificatesv1.CertificateApproved:
+				approved = true
+				approvalDuration = sinceCreation(csr.CreationTimestamp, condition.LastUpdateTime)
+			}
+		}
+		state = approvalState(approved, denied, failed)
+		return csr.Spec.SignerName, state, csr.Labels[clusterv1.ClusterNameLabelKey], approvalDuration, true
+	case *certificatesv1beta1.CertificateSigningRequest:
+		var approved, denied, failed bool
+		for _, condition := range csr.Status.Conditions {
+			switch condition.Type {
+			case certificatesv1beta1.CertificateDenied:
+				denied = true
+			case certificatesv1beta1.CertificateFailed:
+				failed = true
+			case certificatesv1beta1.CertificateApproved:
+				approved = true
+				approvalDuration = sinceCreation(csr.CreationTimestamp, condition.LastUpdateTime)
+			}
+		}
+		state = approvalState(approved, denied, failed)
+		if csr.Spec.SignerName != nil {
+			signer = *csr.Spec.SignerName
+		}
+		return signer, state, csr.Labels[clusterv1.ClusterNameLabelKey], approvalDuration, true
+	default:
+		return "", "", "", 0, false
+	}
+}
+
+func approvalState(approved, denied, failed bool) string {
+	switch {
+	case denied:
+		return CSRStateDenied
+	case failed:
+		return CSRStateFailed
+	case approved:
+		return CSRStateApproved
+	default:
+		return CSRStatePending
+	}
+}
+
+func sinceCreation(creation, approval metav1.Time) time.Duration {
+	if approval.IsZero() {
+		return time.Since(creation.Time)
+	}
+	return approval.Sub(creation.Time)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
+)
+
+func newCSR(name, cluster string, created time.Time, conditions ...certificatesv1.CertificateSigningRequestCondition,
+) *certificatesv1.CertificateSigningRequest {
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Labels:            map[string]string{clusterv1.ClusterNameLabelKey: cluster},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   certificatesv1.CertificateSigningRequestSpec{SignerName: certificatesv1.KubeAPIServerClientSignerName},
+		Status: certificatesv1.CertificateSigningRequestStatus{Conditions: conditions},
+	}
+}
+
+func TestCSRCollector(t *testing.T) {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	now := time.Now()
+	approved := certificatesv1.CertificateSigningRequestCondition{Type: certificatesv1.CertificateApproved}
+	failed := certificatesv1.CertificateSigningRequestCondition{Type: certificatesv1.CertificateFailed}
+	_ = store.Add(newCSR("cluster1-approved", "cluster1", now, approved))
+	_ = store.Add(newCSR("cluster1-failed", "cluster1", now, approved, failed))
+	for i := 0; i < helpers.ClusterCSRThreshold; i++ {
+		_ = store.Add(newCSR(fmt.Sprintf("cluster2-%d", i), "cluster2", now))
+	}
+
+	collector := &csrCollector{}
+	collector.setStore(store)
+	expected := fmt.Sprintf(`
+		# HELP registration_csr_halted_clusters [ALPHA] Number of the managed clusters halted from creating csrs since they reach the csr threshold.
+		# TYPE registration_csr_halted_clusters gauge
+		registration_csr_halted_clusters 1
+		# HELP registration_csrs [ALPHA] Number of the csrs of the managed clusters by signer and approval state.
+		# TYPE registration_csrs gauge
+		registration_csrs{signer="kubernetes.io/kube-apiserver-client",state="approved"} 1
+		registration_csrs{signer="kubernetes.io/kube-apiserver-client",state="failed"} 1
+		registration_csrs{signer="kubernetes.io/kube-apiserver-client",state="pending"} %d
+	`, helpers.ClusterCSRThreshold)
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected),
+		"registration_csrs", "registration_csr_halted_clusters"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestObserveApproval(t *testing.T) {
+	created := time.Now().Add(-10 * time.Second)
+	pending := newCSR("csr1", "cluster1", created)
+	approved := newCSR("csr1", "cluster1", created, certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateApproved,
+		LastUpdateTime: metav1.NewTime(created.Add(5 * time.Second)),
+	})
+
+	observeApproval(pending, approved)
+	// the approved csr updated again is not observed
+	observeApproval(approved, approved)
+
+	mfs, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics")
+	}
+	for _, mf := range mfs {
+		if *mf.Name != RegistrationSubsystem+"_"+CSRApprovalDurationKey {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if m.GetHistogram().GetSampleCount() != 1 || m.GetHistogram().GetSampleSum() != 5 {
+				t.Errorf("unexpected approval duration %v", m.GetHistogram())
+			}
+		}
+	}
+}
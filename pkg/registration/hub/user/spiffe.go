@@ -0,0 +1,64 @@
+package user
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// SPIFFEScheme is the scheme of the SPIFFE IDs
+	SPIFFEScheme = "spiffe"
+	// SPIFFEClusterPathPrefix is the path prefix of the SPIFFE IDs of the managed clusters. The SPIFFE ID
+	// spiffe://<trust domain>/open-cluster-management/cluster/<cluster name> identifies the agents of a managed
+	// cluster. It is only a SAN of the client certificates of the agents, the kube apiserver of the hub still
+	// identifies the agents by the subject of the certificates.
+	SPIFFEClusterPathPrefix = "/open-cluster-management/cluster/"
+)
+
+// ClusterSPIFFEID returns the SPIFFE ID of the agents of the managed cluster in the trust domain
+func ClusterSPIFFEID(trustDomain, clusterName string) string {
+	return fmt.Sprintf("%s://%s%s%s", SPIFFEScheme, trustDomain, SPIFFEClusterPathPrefix, clusterName)
+}
+
+// ValidateClusterSPIFFEID checks if the SPIFFE ID identifies the agents of the managed cluster in the trust domain,
+// an error is returned if the trust domain is empty.
+func ValidateClusterSPIFFEID(trustDomain, clusterName string, id *url.URL) error {
+	if len(trustDomain) == 0 {
+		return fmt.Errorf("SPIFFE ID %q is not allowed without a trust domain", id.String())
+	}
+	if id.String() != ClusterSPIFFEID(trustDomain, clusterName) {
+		return fmt.Errorf("SPIFFE ID %q is not %q", id.String(), ClusterSPIFFEID(trustDomain, clusterName))
+	}
+	return nil
+}
+
+// ClusterNameFromSPIFFEID returns the name of the managed cluster identified by the SPIFFE ID, an error is returned
+// if the SPIFFE ID does not identify a managed cluster.
+func ClusterNameFromSPIFFEID(id *url.URL) (string, error) {
+	if id.Scheme != SPIFFEScheme || len(id.Host) == 0 {
+		return "", fmt.Errorf("%q is not a SPIFFE ID", id.String())
+	}
+	if !strings.HasPrefix(id.Path, SPIFFEClusterPathPrefix) {
+		return "", fmt.Errorf("SPIFFE ID %q does not identify a managed cluster", id.String())
+	}
+	clusterName := strings.TrimPrefix(id.Path, SPIFFEClusterPathPrefix)
+	if len(clusterName) == 0 || strings.Contains(clusterName, "/") {
+		return "", fmt.Errorf("SPIFFE ID %q does not identify a managed cluster", id.String())
+	}
+	return clusterName, nil
+}
+
+// ClusterUserFromSPIFFEID maps the SPIFFE ID of the agents of a managed cluster in the trust domain to the user and
+// the groups of the agents. It is only used by the work gateway, which authenticates the X509-SVIDs itself, the kube
+// apiserver of the hub does not map the SPIFFE IDs.
+func ClusterUserFromSPIFFEID(trustDomain string, id *url.URL) (string, []string, error) {
+	clusterName, err := ClusterNameFromSPIFFEID(id)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := ValidateClusterSPIFFEID(trustDomain, clusterName, id); err != nil {
+		return "", nil, err
+	}
+	return id.String(), []string{SubjectPrefix + clusterName, ManagedClustersGroup}, nil
+}
@@ -15,6 +15,14 @@ import (
 
 const (
 	defaultAddOnInstallationNamespace = "open-cluster-management-agent-addon"
+
+	// ExecCommandAnnotationKey is the annotation on the ManagedClusterAddOn to reference an exec credential plugin in
+	// the hub kubeconfig of the addon instead of the client certificate and key files, once it is allowed by the
+	// registration agent. The value is the comma separated command and args run in the addon agent container, so the
+	// command must be shipped in the image of the addon agent, e.g.
+	// "/registration,credential,--cert-dir=/managed/hub-kubeconfig" if the registration binary is in the image and the
+	// hub kubeconfig secret is mounted at /managed/hub-kubeconfig.
+	ExecCommandAnnotationKey = "addon.open-cluster-management.io/experimental-hub-kubeconfig-exec-command"
 )
 
 // registrationConfig contains necessary information for addon registration
@@ -35,6 +43,8 @@ type registrationConfig struct {
 type addonInstallOption struct {
 	InstallationNamespace             string `json:"installationNamespace"`
 	AgentRunningOutsideManagedCluster bool   `json:"agentRunningOutsideManagedCluster"`
+	// ExecCommand is the command and args of the exec credential plugin referenced in the hub kubeconfig of the addon.
+	ExecCommand []string `json:"execCommand,omitempty"`
 }
 
 func (c *registrationConfig) x509Subject(clusterName, agentName string) *pkix.Name {
@@ -72,6 +82,15 @@ func getAddOnInstallationNamespace(addOn *addonv1alpha1.ManagedClusterAddOn) str
 	return installationNamespace
 }
 
+// getAddOnExecCommand returns the command and args of the exec credential plugin set in the annotation of the addon.
+func getAddOnExecCommand(addOn *addonv1alpha1.ManagedClusterAddOn) []string {
+	value := strings.TrimSpace(addOn.Annotations[ExecCommandAnnotationKey])
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
 // isAddonRunningOutsideManagedCluster returns whether the addon agent is running on the managed cluster
 func isAddonRunningOutsideManagedCluster(addOn *addonv1alpha1.ManagedClusterAddOn) bool {
 	hostingCluster, ok := addOn.Annotations[addonv1alpha1.HostingClusterNameAnnotationKey]
@@ -92,6 +111,7 @@ func getRegistrationConfigs(addOn *addonv1alpha1.ManagedClusterAddOn) (map[strin
 			addonInstallOption: addonInstallOption{
 				AgentRunningOutsideManagedCluster: isAddonRunningOutsideManagedCluster(addOn),
 				InstallationNamespace:             getAddOnInstallationNamespace(addOn),
+				ExecCommand:                       getAddOnExecCommand(addOn),
 			},
 			registration: registration,
 		}
@@ -0,0 +1,139 @@
+package hubproxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+)
+
+// kubeconfigController writes the kubeconfig of an addon to access the hub through the proxy in the hub kubeconfig
+// secret of the addon, the token in the kubeconfig is rotated once half of its validity has passed.
+type kubeconfigController struct {
+	server          *Server
+	addOnName       string
+	secretNamespace string
+	secretName      string
+	secretLister    corev1listers.SecretLister
+	secretClient    corev1client.SecretsGetter
+}
+
+// NewKubeconfigController returns a controller maintaining the hub kubeconfig secret of the addon, the secret
+// informer must watch the secrets in the namespace of the secret.
+func (s *Server) NewKubeconfigController(
+	addOnName, secretNamespace, secretName string,
+	secretClient corev1client.SecretsGetter,
+	secretInformer corev1informers.SecretInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &kubeconfigController{
+		server:          s,
+		addOnName:       addOnName,
+		secretNamespace: secretNamespace,
+		secretName:      secretName,
+		secretLister:    secretInformer.Lister(),
+		secretClient:    secretClient,
+	}
+
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			queue.FilterByNames(secretName),
+			secretInformer.Informer()).
+		WithSync(c.sync).
+		ResyncEvery(10*time.Minute).
+		ToController(fmt.Sprintf("AddOnHubProxyKubeconfigController@addon:%s", addOnName), recorder)
+}
+
+func (c *kubeconfigController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling the addon hub proxy kubeconfig", "addOnName", c.addOnName)
+
+	secret, err := c.secretLister.Secrets(c.secretNamespace).Get(c.secretName)
+	notFound := errors.IsNotFound(err)
+	switch {
+	case notFound:
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: c.secretNamespace, Name: c.secretName},
+			Type:       corev1.SecretTypeOpaque,
+		}
+	case err != nil:
+		return err
+	}
+
+	if c.isKubeconfigValid(secret.Data[clientcert.KubeconfigFile]) {
+		return nil
+	}
+
+	token, expiration := c.server.issuer.issue(c.addOnName)
+	kubeconfigData, err := clientcmd.Write(c.server.buildKubeconfig(token))
+	if err != nil {
+		return err
+	}
+
+	secret = secret.DeepCopy()
+	secret.Data = map[string][]byte{clientcert.KubeconfigFile: kubeconfigData}
+	if notFound {
+		_, err = c.secretClient.Secrets(c.secretNamespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		_, err = c.secretClient.Secrets(c.secretNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	syncCtx.Recorder().Eventf("AddOnHubProxyKubeconfigRotated",
+		"The hub kubeconfig of addon %s in secret %s/%s is rotated, it expires at %s",
+		c.addOnName, c.secretNamespace, c.secretName, expiration.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// isKubeconfigValid returns true if the kubeconfig is built by the proxy for the addon, and its token is valid for
+// at least half of the token validity.
+func (c *kubeconfigController) isKubeconfigValid(kubeconfigData []byte) bool {
+	if len(kubeconfigData) == 0 {
+		return false
+	}
+	kubeconfig, err := clientcmd.Load(kubeconfigData)
+	if err != nil {
+		return false
+	}
+	authInfo, ok := kubeconfig.AuthInfos["default-auth"]
+	if !ok {
+		return false
+	}
+	addOnName, expiration, err := c.server.issuer.verify(authInfo.Token)
+	if err != nil || addOnName != c.addOnName {
+		return false
+	}
+	if c.server.issuer.now().Add(TokenValidity / 2).After(expiration) {
+		return false
+	}
+
+	// the kubeconfig is rebuilt if the url or the serving certificate of the proxy is changed
+	expected, err := clientcmd.Write(c.server.buildKubeconfig(authInfo.Token))
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(kubeconfigData, expected)
+}
+
+// buildKubeconfig returns the kubeconfig to access the hub through the proxy with the token.
+func (s *Server) buildKubeconfig(token string) clientcmdapi.Config {
+	kubeconfig := clientcert.BuildKubeconfig(s.options.HubClusterName, s.options.URL, s.state.certData, "", "", "")
+	kubeconfig.AuthInfos["default-auth"] = &clientcmdapi.AuthInfo{Token: token}
+	return kubeconfig
+}
@@ -0,0 +1,138 @@
+package hubproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+)
+
+func TestSyncKubeconfig(t *testing.T) {
+	hubServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer hubServer.Close()
+	server, _ := newTestServer(t, hubServer)
+
+	now := time.Now()
+	newSecret := func(issuedAt time.Time) *corev1.Secret {
+		issuer := newTokenIssuer(server.state.tokenKey)
+		issuer.now = func() time.Time { return issuedAt }
+		token, _ := issuer.issue("addon1")
+		kubeconfigData, err := clientcmd.Write(server.buildKubeconfig(token))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "addon1-ns", Name: "addon1-hub-kubeconfig", ResourceVersion: "1"},
+			Data:       map[string][]byte{clientcert.KubeconfigFile: kubeconfigData},
+		}
+	}
+
+	cases := []struct {
+		name            string
+		secret          *corev1.Secret
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name: "create the kubeconfig",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "create")
+				secret := actions[0].(clienttesting.CreateActionImpl).Object.(*corev1.Secret)
+				assertKubeconfig(t, server, secret)
+			},
+		},
+		{
+			name:   "valid kubeconfig",
+			secret: newSecret(now.Add(-time.Hour)),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:   "rotate the kubeconfig",
+			secret: newSecret(now.Add(-TokenValidity / 2)),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "update")
+				secret := actions[0].(clienttesting.UpdateActionImpl).Object.(*corev1.Secret)
+				assertKubeconfig(t, server, secret)
+			},
+		},
+		{
+			name: "replace the client certificate",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "addon1-ns", Name: "addon1-hub-kubeconfig", ResourceVersion: "1"},
+				Data: map[string][]byte{
+					clientcert.TLSCertFile:    []byte("cert"),
+					clientcert.TLSKeyFile:     []byte("key"),
+					clientcert.KubeconfigFile: []byte("kubeconfig"),
+				},
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "update")
+				secret := actions[0].(clienttesting.UpdateActionImpl).Object.(*corev1.Secret)
+				if len(secret.Data) != 1 {
+					t.Errorf("expected only the kubeconfig in the secret, but got %v", secret.Data)
+				}
+				assertKubeconfig(t, server, secret)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if c.secret != nil {
+				objects = append(objects, c.secret)
+			}
+			kubeClient := kubefake.NewSimpleClientset(objects...)
+			informerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+			if c.secret != nil {
+				if err := informerFactory.Core().V1().Secrets().Informer().GetStore().Add(c.secret); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &kubeconfigController{
+				server:          server,
+				addOnName:       "addon1",
+				secretNamespace: "addon1-ns",
+				secretName:      "addon1-hub-kubeconfig",
+				secretLister:    informerFactory.Core().V1().Secrets().Lister(),
+				secretClient:    kubeClient.CoreV1(),
+			}
+			if err := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "addon1-hub-kubeconfig")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			c.validateActions(t, kubeClient.Actions())
+		})
+	}
+}
+
+func assertKubeconfig(t *testing.T, server *Server, secret *corev1.Secret) {
+	kubeconfig, err := clientcmd.Load(secret.Data[clientcert.KubeconfigFile])
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := kubeconfig.Clusters["hub"]
+	if cluster == nil || cluster.Server != server.options.URL || string(cluster.CertificateAuthorityData) != string(server.state.certData) {
+		t.Errorf("expected the kubeconfig to access the proxy, but got %v", cluster)
+	}
+	addOnName, expiration, err := server.issuer.verify(kubeconfig.AuthInfos["default-auth"].Token)
+	if err != nil || addOnName != "addon1" {
+		t.Errorf("expected a valid token of addon1, but got %q, %v", addOnName, err)
+	}
+	if time.Until(expiration) < TokenValidity-time.Minute {
+		t.Errorf("expected a new token, but it expires at %v", expiration)
+	}
+}
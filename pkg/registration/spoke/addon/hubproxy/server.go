@@ -0,0 +1,233 @@
+// Package hubproxy terminates the hub credentials of the addons in the registration agent. The client certificates of
+// the addons are kept in the agent namespace, and the addon agents access the hub through the proxy with the scoped,
+// auto-rotated token kubeconfigs written in their hub kubeconfig secrets, so they do not handle the client
+// certificates and their rotation themselves.
+package hubproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+)
+
+// Options are the options of the addon hub proxy.
+type Options struct {
+	// URL is the url of the proxy reachable from the addon agents, it is the server of their kubeconfigs.
+	URL string
+	// BindAddress is the address the proxy listens on.
+	BindAddress string
+	// Namespace is the agent namespace storing the state of the proxy and the client certificates of the addons.
+	Namespace string
+	// HubClusterName, HubServer, HubCAData and HubProxyURL are read from the hub kubeconfig of the agent. The proxy
+	// connects to the hub apiserver with them.
+	HubClusterName string
+	HubServer      string
+	HubCAData      []byte
+	HubProxyURL    string
+}
+
+// Server is the addon hub proxy. It authenticates the addon agents with the tokens in their kubeconfigs and forwards
+// their requests to the hub with the client certificates of the addons.
+type Server struct {
+	options      Options
+	hubURL       *url.URL
+	hubCAs       *x509.CertPool
+	hubProxy     func(*http.Request) (*url.URL, error)
+	state        *servingState
+	issuer       *tokenIssuer
+	secretLister corev1listers.SecretLister
+
+	lock       sync.Mutex
+	transports map[string]*addOnTransport
+}
+
+// addOnTransport is the transport to the hub with the client certificate in the secret of the resource version.
+type addOnTransport struct {
+	resourceVersion string
+	transport       *http.Transport
+}
+
+// NewServer returns an addon hub proxy. The serving state is loaded from the state secret, and the secret informer
+// must watch the secrets in the agent namespace.
+func NewServer(ctx context.Context, options Options, secretClient corev1client.SecretsGetter,
+	secretInformer corev1informers.SecretInformer) (*Server, error) {
+	proxyURL, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid addon hub proxy url %q: %w", options.URL, err)
+	}
+	hubURL, err := url.Parse(options.HubServer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hub server %q: %w", options.HubServer, err)
+	}
+	hubCAs := x509.NewCertPool()
+	if !hubCAs.AppendCertsFromPEM(options.HubCAData) {
+		return nil, fmt.Errorf("no valid CA certificate of the hub")
+	}
+	hubProxy := http.ProxyFromEnvironment
+	if len(options.HubProxyURL) > 0 {
+		u, err := url.Parse(options.HubProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hub proxy url %q: %w", options.HubProxyURL, err)
+		}
+		hubProxy = http.ProxyURL(u)
+	}
+
+	state, err := loadOrCreateState(ctx, secretClient, options.Namespace, proxyURL.Hostname())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the state of the addon hub proxy: %w", err)
+	}
+
+	return &Server{
+		options:      options,
+		hubURL:       hubURL,
+		hubCAs:       hubCAs,
+		hubProxy:     hubProxy,
+		state:        state,
+		issuer:       newTokenIssuer(state.tokenKey),
+		secretLister: secretInformer.Lister(),
+		transports:   map[string]*addOnTransport{},
+	}, nil
+}
+
+// ClientCertSecretNamespace returns the namespace of the secrets of the client certificates of the addons.
+func (s *Server) ClientCertSecretNamespace() string {
+	return s.options.Namespace
+}
+
+// ClientCertSecretName returns the name of the secret of the client certificate of the addon.
+func ClientCertSecretName(addOnName string) string {
+	return fmt.Sprintf("addon-%s-hub-client-cert", addOnName)
+}
+
+// Run serves the proxy until the context is done.
+func (s *Server) Run(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	cert, err := tls.X509KeyPair(s.state.certData, s.state.keyData)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              s.options.BindAddress,
+		Handler:           s,
+		ReadHeaderTimeout: 30 * time.Second,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shut down the addon hub proxy")
+		}
+	}()
+
+	logger.Info("Serving the addon hub proxy", "address", s.options.BindAddress)
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP forwards the request of an addon agent to the hub with the client certificate of the addon.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context())
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	addOnName, _, err := s.issuer.verify(token)
+	if err != nil {
+		logger.V(4).Info("Reject the request to the hub", "error", err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	transport, err := s.transport(addOnName)
+	if err != nil {
+		logger.Error(err, "Failed to build the transport to the hub", "addOnName", addOnName)
+		http.Error(w, fmt.Sprintf("the client certificate of addon %q is not ready", addOnName), http.StatusServiceUnavailable)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(s.hubURL)
+			// the token is only for the proxy, the addon is authenticated with its client certificate by the hub
+			pr.Out.Header.Del("Authorization")
+		},
+		Transport: transport,
+		// flush the watch events immediately
+		FlushInterval: -1,
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// transport returns the transport to the hub with the current client certificate of the addon. A new transport is
+// built once the certificate is rotated, and the idle connections with the previous certificate are closed.
+func (s *Server) transport(addOnName string) (http.RoundTripper, error) {
+	secret, err := s.secretLister.Secrets(s.options.Namespace).Get(ClientCertSecretName(addOnName))
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cached, ok := s.transports[addOnName]
+	if ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.transport, nil
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[clientcert.TLSCertFile], secret.Data[clientcert.TLSKeyFile])
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		Proxy: s.hubProxy,
+		TLSClientConfig: &tls.Config{
+			RootCAs:      s.hubCAs,
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		},
+		ForceAttemptHTTP2:   true,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	if ok {
+		cached.transport.CloseIdleConnections()
+	}
+	s.transports[addOnName] = &addOnTransport{resourceVersion: secret.ResourceVersion, transport: transport}
+	return transport, nil
+}
+
+// Forget closes the idle connections of the addon to the hub, it is called once the registration of the addon is
+// stopped.
+func (s *Server) Forget(addOnName string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if cached, ok := s.transports[addOnName]; ok {
+		cached.transport.CloseIdleConnections()
+		delete(s.transports, addOnName)
+	}
+}
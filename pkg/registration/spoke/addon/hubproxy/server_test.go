@@ -0,0 +1,168 @@
+package hubproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+const testNamespace = "open-cluster-management-agent"
+
+func newTestServer(t *testing.T, hubServer *httptest.Server, secrets ...*corev1.Secret) (*Server, *kubefake.Clientset) {
+	kubeClient := kubefake.NewSimpleClientset()
+	informerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+	for _, secret := range secrets {
+		if err := informerFactory.Core().V1().Secrets().Informer().GetStore().Add(secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hubCAData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: hubServer.Certificate().Raw})
+	server, err := NewServer(context.TODO(), Options{
+		URL:            "https://addon-hub-proxy.open-cluster-management-agent.svc:9443",
+		BindAddress:    ":9443",
+		Namespace:      testNamespace,
+		HubClusterName: "hub",
+		HubServer:      hubServer.URL,
+		HubCAData:      hubCAData,
+	}, kubeClient.CoreV1(), informerFactory.Core().V1().Secrets())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, kubeClient
+}
+
+func TestNewServer(t *testing.T) {
+	hubServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer hubServer.Close()
+
+	server, kubeClient := newTestServer(t, hubServer)
+	secret, err := kubeClient.CoreV1().Secrets(testNamespace).Get(context.TODO(), StateSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the state secret is created, but got %v", err)
+	}
+	if !isServingCertValid(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey],
+		"addon-hub-proxy.open-cluster-management-agent.svc") {
+		t.Errorf("expected a valid serving certificate of the proxy")
+	}
+
+	// the state is kept when the agent is restarted
+	kubeClient.ClearActions()
+	state, err := loadOrCreateState(context.TODO(), kubeClient.CoreV1(), testNamespace,
+		"addon-hub-proxy.open-cluster-management-agent.svc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(state.tokenKey) != string(server.state.tokenKey) || string(state.certData) != string(server.state.certData) {
+		t.Errorf("expected the state is kept")
+	}
+	if actions := kubeClient.Actions(); len(actions) != 1 || actions[0].GetVerb() != "get" {
+		t.Errorf("expected only the state secret is read, but got %v", actions)
+	}
+
+	// the serving certificate is regenerated for another host, but the token key is kept
+	state, err = loadOrCreateState(context.TODO(), kubeClient.CoreV1(), testNamespace, "addon-hub-proxy.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(state.tokenKey) != string(server.state.tokenKey) {
+		t.Errorf("expected the token key is kept")
+	}
+	if string(state.certData) == string(server.state.certData) {
+		t.Errorf("expected the serving certificate is regenerated")
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	var authorization, commonName string
+	hubServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if len(r.TLS.PeerCertificates) > 0 {
+			commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		_, _ = io.WriteString(w, r.URL.Path)
+	}))
+	hubServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	hubServer.StartTLS()
+	defer hubServer.Close()
+
+	cert := testinghelpers.NewTestCert("system:open-cluster-management:cluster1:addon:addon1", time.Hour)
+	server, _ := newTestServer(t, hubServer, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: ClientCertSecretName("addon1"), ResourceVersion: "1"},
+		Data: map[string][]byte{
+			clientcert.TLSCertFile: cert.Cert,
+			clientcert.TLSKeyFile:  cert.Key,
+		},
+	})
+	token1, _ := server.issuer.issue("addon1")
+	token2, _ := server.issuer.issue("addon2")
+
+	cases := []struct {
+		name               string
+		authorization      string
+		expectedStatusCode int
+		expectedCommonName string
+	}{
+		{
+			name:               "no token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "invalid token",
+			authorization:      "Bearer invalid",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "no client certificate",
+			authorization:      "Bearer " + token2,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "forward to the hub",
+			authorization:      "Bearer " + token1,
+			expectedStatusCode: http.StatusOK,
+			expectedCommonName: "system:open-cluster-management:cluster1:addon:addon1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			authorization, commonName = "", ""
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/cluster1/configmaps", nil)
+			if len(c.authorization) > 0 {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			if recorder.Code != c.expectedStatusCode {
+				t.Fatalf("expected status code %d, but got %d: %s", c.expectedStatusCode, recorder.Code, recorder.Body.String())
+			}
+			if c.expectedStatusCode != http.StatusOK {
+				return
+			}
+			if recorder.Body.String() != "/api/v1/namespaces/cluster1/configmaps" {
+				t.Errorf("expected the request path is kept, but got %q", recorder.Body.String())
+			}
+			if len(authorization) > 0 {
+				t.Errorf("expected the token is not forwarded to the hub, but got %q", authorization)
+			}
+			if commonName != c.expectedCommonName {
+				t.Errorf("expected the client certificate of %q, but got %q", c.expectedCommonName, commonName)
+			}
+		})
+	}
+}
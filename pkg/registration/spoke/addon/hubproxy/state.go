@@ -0,0 +1,107 @@
+package hubproxy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// StateSecretName is the name of the secret in the agent namespace storing the serving certificate and the token
+	// key of the proxy, so the kubeconfigs of the addons are kept valid when the agent is restarted.
+	StateSecretName = "addon-hub-proxy"
+
+	tokenKeyFile = "token.key"
+
+	// the serving certificate is regenerated when the agent is started if it expires in this period
+	servingCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// servingState is the serving certificate and the token key of the proxy.
+type servingState struct {
+	// certData is the self-signed serving certificate chain, it is the CA bundle of the kubeconfigs of the addons as well
+	certData []byte
+	keyData  []byte
+	tokenKey []byte
+}
+
+// loadOrCreateState loads the serving state of the proxy from the state secret, the serving certificate is
+// regenerated if it is missing, expiring or not valid for the host, and the token key is generated if it is missing.
+func loadOrCreateState(ctx context.Context, client corev1client.SecretsGetter, namespace, host string) (*servingState, error) {
+	secret, err := client.Secrets(namespace).Get(ctx, StateSecretName, metav1.GetOptions{})
+	notFound := errors.IsNotFound(err)
+	switch {
+	case notFound:
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: StateSecretName},
+			Type:       corev1.SecretTypeOpaque,
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	state := &servingState{
+		certData: secret.Data[corev1.TLSCertKey],
+		keyData:  secret.Data[corev1.TLSPrivateKeyKey],
+		tokenKey: secret.Data[tokenKeyFile],
+	}
+	changed := false
+	if !isServingCertValid(state.certData, state.keyData, host) {
+		klog.FromContext(ctx).Info("Generate the serving certificate of the addon hub proxy", "host", host)
+		state.certData, state.keyData, err = certutil.GenerateSelfSignedCertKey(host, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the serving certificate: %w", err)
+		}
+		changed = true
+	}
+	if len(state.tokenKey) == 0 {
+		state.tokenKey = make([]byte, 32)
+		if _, err := rand.Read(state.tokenKey); err != nil {
+			return nil, fmt.Errorf("failed to generate the token key: %w", err)
+		}
+		changed = true
+	}
+	if !changed {
+		return state, nil
+	}
+
+	secret = secret.DeepCopy()
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       state.certData,
+		corev1.TLSPrivateKeyKey: state.keyData,
+		tokenKeyFile:            state.tokenKey,
+	}
+	if notFound {
+		_, err = client.Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		_, err = client.Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// isServingCertValid returns true if the serving certificate is valid for the host and does not expire soon.
+func isServingCertValid(certData, keyData []byte, host string) bool {
+	if _, err := tls.X509KeyPair(certData, keyData); err != nil {
+		return false
+	}
+	certs, err := certutil.ParseCertsPEM(certData)
+	if err != nil || len(certs) == 0 {
+		return false
+	}
+	if time.Now().Add(servingCertRenewBefore).After(certs[0].NotAfter) {
+		return false
+	}
+	return certs[0].VerifyHostname(host) == nil
+}
@@ -0,0 +1,68 @@
+package hubproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TokenValidity is the validity of the tokens issued to the addons, the kubeconfigs of the addons are rotated once
+// half of it has passed.
+var TokenValidity = 24 * time.Hour
+
+// tokenIssuer issues and verifies the bearer tokens of the addons to access the hub through the proxy. A token is
+// <base64 encoded addon name>.<expiration in unix seconds>.<signature>, where the signature is the HMAC-SHA256 of the
+// first two parts with the key of the proxy, so the tokens are verified without being stored.
+type tokenIssuer struct {
+	key []byte
+	now func() time.Time
+}
+
+func newTokenIssuer(key []byte) *tokenIssuer {
+	return &tokenIssuer{key: key, now: time.Now}
+}
+
+// issue returns a token of the addon and its expiration.
+func (i *tokenIssuer) issue(addOnName string) (string, time.Time) {
+	expiration := i.now().Add(TokenValidity).Truncate(time.Second)
+	payload := fmt.Sprintf("%s.%d", base64.RawURLEncoding.EncodeToString([]byte(addOnName)), expiration.Unix())
+	return fmt.Sprintf("%s.%s", payload, i.sign(payload)), expiration
+}
+
+// verify returns the addon name and the expiration of the token, an error is returned if the token is not issued by
+// the proxy or it is expired.
+func (i *tokenIssuer) verify(token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || len(parts[0]) == 0 {
+		return "", time.Time{}, fmt.Errorf("malformed token")
+	}
+
+	payload := fmt.Sprintf("%s.%s", parts[0], parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(i.sign(payload))) {
+		return "", time.Time{}, fmt.Errorf("invalid token signature")
+	}
+
+	seconds, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("malformed token expiration: %w", err)
+	}
+	addOnName, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("malformed token addon name: %w", err)
+	}
+	expiration := time.Unix(seconds, 0)
+	if !i.now().Before(expiration) {
+		return "", time.Time{}, fmt.Errorf("token of addon %q is expired", addOnName)
+	}
+	return string(addOnName), expiration, nil
+}
+
+func (i *tokenIssuer) sign(payload string) string {
+	mac := hmac.New(sha256.New, i.key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,80 @@
+package hubproxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToken(t *testing.T) {
+	now := time.Now()
+	issuer := newTokenIssuer([]byte("key"))
+	issuer.now = func() time.Time { return now }
+
+	token, expiration := issuer.issue("addon.example")
+	cases := []struct {
+		name          string
+		token         string
+		now           time.Time
+		issuer        *tokenIssuer
+		expectedAddOn string
+		expectedErr   bool
+	}{
+		{
+			name:          "valid token",
+			token:         token,
+			now:           now.Add(time.Hour),
+			issuer:        issuer,
+			expectedAddOn: "addon.example",
+		},
+		{
+			name:        "expired token",
+			token:       token,
+			now:         now.Add(TokenValidity),
+			issuer:      issuer,
+			expectedErr: true,
+		},
+		{
+			name:        "token of another key",
+			token:       token,
+			now:         now,
+			issuer:      newTokenIssuer([]byte("another-key")),
+			expectedErr: true,
+		},
+		{
+			name:        "tampered token",
+			token:       "YWRkb24y" + token[len("YWRkb24uZXhhbXBsZQ"):],
+			now:         now,
+			issuer:      issuer,
+			expectedErr: true,
+		},
+		{
+			name:        "malformed token",
+			token:       "token",
+			now:         now,
+			issuer:      issuer,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.issuer.now = func() time.Time { return c.now }
+			addOnName, actualExpiration, err := c.issuer.verify(c.token)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got addon %q", addOnName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if addOnName != c.expectedAddOn {
+				t.Errorf("expected addon %q, but got %q", c.expectedAddOn, addOnName)
+			}
+			if !actualExpiration.Equal(expiration) {
+				t.Errorf("expected expiration %v, but got %v", expiration, actualExpiration)
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
 	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/common/health"
 )
 
 const leaseDurationTimes = 5
@@ -50,6 +52,7 @@ func NewManagedClusterAddOnLeaseController(clusterName string,
 	managementLeaseClient coordv1client.CoordinationV1Interface,
 	spokeLeaseClient coordv1client.CoordinationV1Interface,
 	resyncInterval time.Duration,
+	controllerHealth *health.ControllerHealth,
 	recorder events.Recorder) factory.Controller {
 	c := &managedClusterAddOnLeaseController{
 		clusterName: clusterName,
@@ -68,7 +71,7 @@ func NewManagedClusterAddOnLeaseController(clusterName string,
 	// informer cache sync and result in fatal exit of this controller. The code will be factored
 	// when we no longer support kubernetes version lower than 1.17.
 	return factory.New().
-		WithSync(c.sync).
+		WithSync(controllerHealth.ObserveSync(c.sync)).
 		ResyncEvery(resyncInterval).
 		ToController("ManagedClusterAddOnLeaseController", recorder)
 }
@@ -16,6 +16,7 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
@@ -25,8 +26,11 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+	"open-cluster-management.io/ocm/pkg/registration/spoke/addon/hubproxy"
 )
 
 const (
@@ -41,9 +45,11 @@ const (
 // may have multiple registrationConfigs. A clientcert.NewClientCertificateController will be started
 // for each of them.
 type addOnRegistrationController struct {
-	clusterName          string
-	agentName            string
-	kubeconfigData       []byte
+	clusterName    string
+	agentName      string
+	kubeconfigData []byte
+	// execCredential allows the addons to reference the exec credential plugins in their hub kubeconfigs.
+	execCredential       bool
 	managementKubeClient kubernetes.Interface // in-cluster local management kubeClient
 	spokeKubeClient      kubernetes.Interface
 	hubAddOnLister       addonlisterv1alpha1.ManagedClusterAddOnLister
@@ -52,6 +58,9 @@ type addOnRegistrationController struct {
 	csrControl clientcert.CSRControl
 	recorder   events.Recorder
 	csrIndexer cache.Indexer
+	// hubProxy is set in the addon hub proxy mode, the addons access the hub through the proxy with the token
+	// kubeconfigs instead of their client certificates.
+	hubProxy *hubproxy.Server
 
 	startRegistrationFunc func(ctx context.Context, config registrationConfig) context.CancelFunc
 
@@ -65,17 +74,21 @@ func NewAddOnRegistrationController(
 	clusterName string,
 	agentName string,
 	kubeconfigData []byte,
+	execCredential bool,
 	addOnClient addonclient.Interface,
 	managementKubeClient kubernetes.Interface,
 	managedKubeClient kubernetes.Interface,
 	csrControl clientcert.CSRControl,
 	hubAddOnInformers addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	hubProxy *hubproxy.Server,
+	controllerHealth *health.ControllerHealth,
 	recorder events.Recorder,
 ) factory.Controller {
 	c := &addOnRegistrationController{
 		clusterName:          clusterName,
 		agentName:            agentName,
 		kubeconfigData:       kubeconfigData,
+		execCredential:       execCredential,
 		managementKubeClient: managementKubeClient,
 		spokeKubeClient:      managedKubeClient,
 		hubAddOnLister:       hubAddOnInformers.Lister(),
@@ -85,6 +98,7 @@ func NewAddOnRegistrationController(
 			addOnClient.AddonV1alpha1().ManagedClusterAddOns(clusterName)),
 		recorder:                 recorder,
 		csrIndexer:               csrControl.Informer().GetIndexer(),
+		hubProxy:                 hubProxy,
 		addOnRegistrationConfigs: map[string]map[string]registrationConfig{},
 	}
 
@@ -101,7 +115,7 @@ func NewAddOnRegistrationController(
 		WithInformersQueueKeysFunc(
 			queue.QueueKeyByMetaName,
 			hubAddOnInformers.Informer()).
-		WithSync(c.sync).
+		WithSync(controllerHealth.ObserveSync(c.sync)).
 		ResyncEvery(10*time.Minute).
 		ToController("AddOnRegistrationController", recorder)
 }
@@ -212,7 +226,7 @@ func (c *addOnRegistrationController) startRegistration(ctx context.Context, con
 
 	additionalSecretData := map[string][]byte{}
 	if config.registration.SignerName == certificatesv1.KubeAPIServerClientSignerName {
-		additionalSecretData[clientcert.KubeconfigFile] = c.kubeconfigData
+		additionalSecretData[clientcert.KubeconfigFile] = c.addOnKubeconfigData(ctx, config)
 	}
 
 	// build and start a client cert controller
@@ -221,6 +235,27 @@ func (c *addOnRegistrationController) startRegistration(ctx context.Context, con
 		SecretName:           config.secretName,
 		AdditionalSecretData: additionalSecretData,
 	}
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	secretClient := kubeClient.CoreV1()
+
+	var certInformerFactory informers.SharedInformerFactory
+	if c.useHubProxy(config) {
+		// the client certificate is kept in the agent namespace for the proxy, and the hub kubeconfig secret of the
+		// addon only contains the token kubeconfig to access the hub through the proxy
+		certInformerFactory = informers.NewSharedInformerFactoryWithOptions(
+			c.managementKubeClient, 10*time.Minute, informers.WithNamespace(c.hubProxy.ClientCertSecretNamespace()))
+		clientCertOption = clientcert.ClientCertOption{
+			SecretNamespace: c.hubProxy.ClientCertSecretNamespace(),
+			SecretName:      hubproxy.ClientCertSecretName(config.addOnName),
+		}
+		secretInformer = certInformerFactory.Core().V1().Secrets()
+		secretClient = c.managementKubeClient.CoreV1()
+
+		kubeconfigController := c.hubProxy.NewKubeconfigController(
+			config.addOnName, config.InstallationNamespace, config.secretName,
+			kubeClient.CoreV1(), kubeInformerFactory.Core().V1().Secrets(), c.recorder)
+		go kubeconfigController.Run(ctx, 1)
+	}
 
 	csrOption := clientcert.CSROption{
 		ObjectMeta: metav1.ObjectMeta{
@@ -240,25 +275,55 @@ func (c *addOnRegistrationController) startRegistration(ctx context.Context, con
 
 	controllerName := fmt.Sprintf("ClientCertController@addon:%s:signer:%s", config.addOnName, config.registration.SignerName)
 
-	statusUpdater := c.generateStatusUpdate(c.clusterName, config.addOnName)
+	statusUpdater := helpers.NewDeduplicatedConditionUpdater(
+		c.generateStatusUpdate(c.clusterName, config.addOnName), helpers.ConditionDedupInterval)
 
 	clientCertController := clientcert.NewClientCertificateController(
 		clientCertOption,
 		csrOption,
 		c.csrControl,
-		kubeInformerFactory.Core().V1().Secrets(),
-		kubeClient.CoreV1(),
+		secretInformer,
+		secretClient,
 		statusUpdater,
 		c.recorder,
 		controllerName,
 	)
 
 	go kubeInformerFactory.Start(ctx.Done())
+	if certInformerFactory != nil {
+		go certInformerFactory.Start(ctx.Done())
+	}
 	go clientCertController.Run(ctx, 1)
 
 	return stopFunc
 }
 
+// addOnKubeconfigData returns the hub kubeconfig of the addon, it references the exec credential plugin of the addon
+// if it is allowed, otherwise the client certificate and key files in the secret.
+func (c *addOnRegistrationController) addOnKubeconfigData(ctx context.Context, config registrationConfig) []byte {
+	if !c.execCredential || len(config.ExecCommand) == 0 {
+		return c.kubeconfigData
+	}
+
+	kubeconfig, err := clientcmd.Load(c.kubeconfigData)
+	if err == nil {
+		clientcert.SetExecCredentialPlugin(kubeconfig, config.ExecCommand[0], config.ExecCommand[1:])
+		var data []byte
+		if data, err = clientcmd.Write(*kubeconfig); err == nil {
+			return data
+		}
+	}
+	klog.FromContext(ctx).Error(err, "Failed to reference the exec credential plugin in the hub kubeconfig",
+		"addOnName", config.addOnName)
+	return c.kubeconfigData
+}
+
+// useHubProxy returns true if the addon accesses the hub through the addon hub proxy with the registration config,
+// only the client certificates signed by the kube-apiserver-client signer are used by the proxy.
+func (c *addOnRegistrationController) useHubProxy(config registrationConfig) bool {
+	return c.hubProxy != nil && config.registration.SignerName == certificatesv1.KubeAPIServerClientSignerName
+}
+
 func (c *addOnRegistrationController) haltCSRCreationFunc(addonName string) func() bool {
 	return func() bool {
 		items, err := c.csrIndexer.ByIndex(indexByAddon, fmt.Sprintf("%s/%s", c.clusterName, addonName))
@@ -309,6 +374,15 @@ func (c *addOnRegistrationController) stopRegistration(ctx context.Context, conf
 		return err
 	}
 
+	if c.useHubProxy(config) {
+		c.hubProxy.Forget(config.addOnName)
+		err := c.managementKubeClient.CoreV1().Secrets(c.hubProxy.ClientCertSecretNamespace()).
+			Delete(ctx, hubproxy.ClientCertSecretName(config.addOnName), metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
 
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
@@ -19,6 +20,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 )
 
 func TestFilterCSREvents(t *testing.T) {
@@ -461,3 +463,53 @@ func hash(registration addonv1alpha1.RegistrationConfig, installNamespace string
 	})
 	return h
 }
+
+func TestAddOnKubeconfigData(t *testing.T) {
+	kubeconfigData, err := clientcmd.Write(clientcert.BuildKubeconfig("hub", "https://hub:6443", []byte("ca"), "",
+		clientcert.TLSCertFile, clientcert.TLSKeyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	execConfig := registrationConfig{addonInstallOption: addonInstallOption{
+		ExecCommand: []string{"/registration", "credential", "--cert-dir=/managed/hub-kubeconfig"},
+	}}
+
+	cases := []struct {
+		name           string
+		execCredential bool
+		config         registrationConfig
+		expectedExec   bool
+	}{
+		{
+			name:   "exec credential is not allowed",
+			config: execConfig,
+		},
+		{
+			name:           "addon does not reference the plugin",
+			execCredential: true,
+		},
+		{
+			name:           "addon references the plugin",
+			execCredential: true,
+			config:         execConfig,
+			expectedExec:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := &addOnRegistrationController{kubeconfigData: kubeconfigData, execCredential: c.execCredential}
+			kubeconfig, err := clientcmd.Load(controller.addOnKubeconfigData(context.TODO(), c.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, authInfo := range kubeconfig.AuthInfos {
+				if exec := authInfo.Exec != nil; exec != c.expectedExec {
+					t.Errorf("expected the exec credential plugin %v, but got %v", c.expectedExec, authInfo.Exec)
+				}
+				if c.expectedExec && authInfo.Exec.Command != "/registration" {
+					t.Errorf("unexpected command %s", authInfo.Exec.Command)
+				}
+			}
+		})
+	}
+}
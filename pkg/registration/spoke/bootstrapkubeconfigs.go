@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
@@ -21,9 +24,10 @@ import (
 // no need to select another bootstrapkubeconfig.
 // 2. selects the first available bootstrap kubeconfig from the given list of configurations.
 // If no suitable kubeconfig is found, it returns -1 and an error indicating that no bootstrap kubeconfig is available for the specified managed cluster.
-func selectBootstrapKubeConfigs(ctx context.Context,
-	managedCluster, hubKubeConfigFilePath string, bootstrapKubeConfigFilePaths []string) (int, error) {
+func (o *SpokeAgentOptions) selectBootstrapKubeConfigs(ctx context.Context,
+	managedCluster, hubKubeConfigFilePath string) (int, error) {
 	logger := klog.FromContext(ctx)
+	bootstrapKubeConfigFilePaths := o.BootstrapKubeconfigs
 
 	for index, fp := range bootstrapKubeConfigFilePaths {
 		equal, err := compareServerEndpoint(fp, hubKubeConfigFilePath)
@@ -32,9 +36,10 @@ func selectBootstrapKubeConfigs(ctx context.Context,
 			continue
 		}
 		if equal {
-			err := checkBootstrapKubeConfigValid(ctx, managedCluster, fp)
+			err := o.checkBootstrapKubeConfigValid(ctx, managedCluster, fp)
 			if err != nil {
-				logger.Error(err, "failed to check matched bootstrap kubeconfig", "index", index)
+				logger.Error(err, "failed to check matched bootstrap kubeconfig", "index", index,
+					"reason", commonhelpers.ConnectionFailureReason(err))
 				break
 			}
 			logger.Info("found matched bootstrap kubeconfig and it's valid, no need to reselect another one", "index", index)
@@ -43,9 +48,10 @@ func selectBootstrapKubeConfigs(ctx context.Context,
 	}
 
 	for index, fp := range bootstrapKubeConfigFilePaths {
-		err := checkBootstrapKubeConfigValid(ctx, managedCluster, fp)
+		err := o.checkBootstrapKubeConfigValid(ctx, managedCluster, fp)
 		if err != nil {
-			logger.Error(err, "failed to check bootstrap kubeconfig", "index", index)
+			logger.Error(err, "failed to check bootstrap kubeconfig", "index", index,
+				"reason", commonhelpers.ConnectionFailureReason(err))
 			continue
 		}
 		return index, nil
@@ -72,13 +78,21 @@ func compareServerEndpoint(bootstrapKubeConfigFilePath, hubKubeConfigFilePath st
 }
 
 // An "valid" bootstrap kubeconfig means:
-// 1. It has the right permissions by creating self subject access reviews.
-// 2. If a managed cluster exists and the hubAcceptsClient flag is set to true.
-func checkBootstrapKubeConfigValid(ctx context.Context, managedCluster, bootstrapKubeConfigFilePath string) error {
-	bootstrapKubeConfig, err := clientcmd.BuildConfigFromFlags("", bootstrapKubeConfigFilePath)
+// 1. Its client certificate is not expired.
+// 2. It has the right permissions by creating self subject access reviews.
+// 3. If a managed cluster exists and the hubAcceptsClient flag is set to true.
+//
+// The failures of CA mismatch, expired certificate and unreachable hub are told apart with
+// commonhelpers.ConnectionFailureReason.
+func (o *SpokeAgentOptions) checkBootstrapKubeConfigValid(
+	ctx context.Context, managedCluster, bootstrapKubeConfigFilePath string) error {
+	bootstrapKubeConfig, err := o.loadBootstrapClientConfig(bootstrapKubeConfigFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to build bootstrap kubeconfig: %w", err)
 	}
+	if err := commonhelpers.CheckClientCertificate(bootstrapKubeConfig); err != nil {
+		return fmt.Errorf("invalid bootstrap kubeconfig: %w", err)
+	}
 
 	// Send sarr check if bootstrapkubeconfig has right permission
 	bootstrapKubeClient, err := kubernetes.NewForConfig(bootstrapKubeConfig)
@@ -112,6 +126,83 @@ func checkBootstrapKubeConfigValid(ctx context.Context, managedCluster, bootstra
 	return nil
 }
 
+// loadBootstrapClientConfig loads the client config from the bootstrap kubeconfig, with the CA bundle file appended
+// to its CA bundle and its proxy url overridden if they are set in the options.
+func (o *SpokeAgentOptions) loadBootstrapClientConfig(filename string) (*rest.Config, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundle, err := o.bootstrapCABundle()
+	if err != nil {
+		return nil, err
+	}
+	if len(caBundle) > 0 {
+		caData := config.CAData
+		if len(caData) == 0 && len(config.CAFile) > 0 {
+			if caData, err = os.ReadFile(config.CAFile); err != nil {
+				return nil, err
+			}
+		}
+		config.CAData = appendCABundle(caData, caBundle)
+		config.CAFile = ""
+	}
+
+	if len(o.BootstrapProxyURL) > 0 {
+		proxyURL, err := url.Parse(o.BootstrapProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		config.Proxy = http.ProxyURL(proxyURL)
+	}
+	return config, nil
+}
+
+// parseBootstrapKubeconfig parses the bootstrap kubeconfig in the same way as parseKubeconfig, with the CA bundle
+// file appended to the CA bundle and the proxy url overridden if they are set in the options. The hub kubeconfig is
+// built with the returned values.
+func (o *SpokeAgentOptions) parseBootstrapKubeconfig(filename string) (string, string, string, []byte, error) {
+	ctxCluster, server, proxyURL, caData, err := parseKubeconfig(filename)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	caBundle, err := o.bootstrapCABundle()
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	if len(caBundle) > 0 {
+		caData = appendCABundle(caData, caBundle)
+	}
+	if len(o.BootstrapProxyURL) > 0 {
+		proxyURL = o.BootstrapProxyURL
+	}
+	return ctxCluster, server, proxyURL, caData, nil
+}
+
+func (o *SpokeAgentOptions) bootstrapCABundle() ([]byte, error) {
+	var caBundle []byte
+	for _, file := range []string{o.BootstrapCABundleFile, o.proxyCABundleFile} {
+		if len(file) == 0 {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the bootstrap ca bundle file: %w", err)
+		}
+		caBundle = appendCABundle(caBundle, data)
+	}
+	return caBundle, nil
+}
+
+func appendCABundle(caData, caBundle []byte) []byte {
+	if len(caData) == 0 {
+		return caBundle
+	}
+	return append(append(append([]byte{}, caData...), '\n'), caBundle...)
+}
+
 // reSelectChecker is a health checker that checks if the bootstrap kubeconfig should be reselected.
 //
 // It is used by 2 controllers: the hubTimeoutController and hubAcceptController.
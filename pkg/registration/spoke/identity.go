@@ -0,0 +1,59 @@
+package spoke
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+)
+
+// rotateAgentIdentity removes the hub kubeconfig secret and the files dumped from it if the secret is not created
+// for the requested generation of the agent identity. The cluster name is kept, so a new agent name is generated
+// unless it is specified with the flag, and the agent bootstraps again with a new client certificate. The client
+// certificates of the addons are renewed afterwards since they are issued to the agent name, and the csrs of the
+// previous identity are garbage collected by the hub.
+func (o *SpokeAgentConfig) rotateAgentIdentity(ctx context.Context,
+	coreClient corev1client.CoreV1Interface, recorder events.Recorder) error {
+	generation := o.registrationOption.AgentIdentityGeneration
+	if len(generation) == 0 {
+		return nil
+	}
+
+	secret, err := coreClient.Secrets(o.agentOptions.ComponentNamespace).Get(
+		ctx, o.registrationOption.HubKubeconfigSecret, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	if string(secret.Data[clientcert.AgentIdentityGenerationFile]) == generation {
+		return nil
+	}
+
+	klog.FromContext(ctx).Info("Rotate the agent identity",
+		"agentName", string(secret.Data[clientcert.AgentNameFile]), "generation", generation)
+	err = coreClient.Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	for key := range secret.Data {
+		if key == clientcert.ClusterNameFile {
+			continue
+		}
+		if err := os.Remove(path.Join(o.agentOptions.HubKubeconfigDir, key)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	recorder.Eventf("AgentIdentityRotated", "The identity of agent %q is removed for the generation %q",
+		string(secret.Data[clientcert.AgentNameFile]), generation)
+	return nil
+}
@@ -0,0 +1,109 @@
+package spoke
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/events/eventstesting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+)
+
+func TestRotateAgentIdentity(t *testing.T) {
+	cases := []struct {
+		name            string
+		generation      string
+		secretData      map[string][]byte
+		expectedActions []string
+		expectedFiles   []string
+	}{
+		{
+			name:          "no generation requested",
+			secretData:    map[string][]byte{clientcert.AgentNameFile: []byte("agent1")},
+			expectedFiles: []string{clientcert.ClusterNameFile, clientcert.AgentNameFile, clientcert.TLSCertFile},
+		},
+		{
+			name:            "no secret",
+			generation:      "1",
+			expectedActions: []string{"get"},
+			expectedFiles:   []string{clientcert.ClusterNameFile, clientcert.AgentNameFile, clientcert.TLSCertFile},
+		},
+		{
+			name:       "generation not changed",
+			generation: "1",
+			secretData: map[string][]byte{
+				clientcert.AgentNameFile:               []byte("agent1"),
+				clientcert.AgentIdentityGenerationFile: []byte("1"),
+			},
+			expectedActions: []string{"get"},
+			expectedFiles:   []string{clientcert.ClusterNameFile, clientcert.AgentNameFile, clientcert.TLSCertFile},
+		},
+		{
+			name:       "generation changed",
+			generation: "2",
+			secretData: map[string][]byte{
+				clientcert.ClusterNameFile:             []byte("cluster1"),
+				clientcert.AgentNameFile:               []byte("agent1"),
+				clientcert.TLSCertFile:                 []byte("cert"),
+				clientcert.AgentIdentityGenerationFile: []byte("1"),
+			},
+			expectedActions: []string{"get", "delete"},
+			expectedFiles:   []string{clientcert.ClusterNameFile},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir, err := os.MkdirTemp("", "identity")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for _, file := range []string{clientcert.ClusterNameFile, clientcert.AgentNameFile, clientcert.TLSCertFile} {
+				if err := os.WriteFile(path.Join(dir, file), []byte(file), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			kubeClient := kubefake.NewSimpleClientset()
+			if c.secretData != nil {
+				kubeClient = kubefake.NewSimpleClientset(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "hub-kubeconfig-secret"},
+					Data:       c.secretData,
+				})
+			}
+
+			agentOptions := commonoptions.NewAgentOptions()
+			agentOptions.ComponentNamespace = "ns1"
+			agentOptions.HubKubeconfigDir = dir
+			options := NewSpokeAgentOptions()
+			options.AgentIdentityGeneration = c.generation
+			cfg := NewSpokeAgentConfig(agentOptions, options)
+
+			if err := cfg.rotateAgentIdentity(context.TODO(), kubeClient.CoreV1(),
+				eventstesting.NewTestingEventRecorder(t)); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			testingcommon.AssertActions(t, kubeClient.Actions(), c.expectedActions...)
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+			}
+			if len(files) != len(c.expectedFiles) {
+				t.Errorf("expected files %v, but got %v", c.expectedFiles, files)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ import (
 	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
 	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/health"
 )
 
 const leaseUpdateJitterFactor = 0.25
@@ -34,6 +36,7 @@ func NewManagedClusterLeaseController(
 	clusterName string,
 	hubClient clientset.Interface,
 	hubClusterInformer clusterv1informer.ManagedClusterInformer,
+	controllerHealth *health.ControllerHealth,
 	recorder events.Recorder) factory.Controller {
 	c := &managedClusterLeaseController{
 		clusterName:      clusterName,
@@ -48,7 +51,7 @@ func NewManagedClusterLeaseController(
 
 	return factory.New().
 		WithInformers(hubClusterInformer.Informer()).
-		WithSync(c.sync).
+		WithSync(controllerHealth.ObserveSync(c.sync)).
 		ToController("ManagedClusterLeaseController", recorder)
 }
 
@@ -0,0 +1,56 @@
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+)
+
+// ManagedClusterConditionFIPSCompliant reports whether the agent runs with the options compliant with FIPS, it is
+// only reported once the FIPS compliance mode is enabled.
+const ManagedClusterConditionFIPSCompliant = "FIPSCompliant"
+
+// fipsReconcile reports the options of the agent not compliant with FIPS, which are recorded by the components in the
+// agent process at startup, or published in the agent namespace by the agents running in their own processes, e.g.
+// the work agent.
+type fipsReconcile struct {
+	violations func() []string
+	// configMapLister lists the configmaps in the agent namespace, it is nil if the violations are not published.
+	configMapLister corev1lister.ConfigMapNamespaceLister
+}
+
+func (r *fipsReconcile) reconcile(ctx context.Context, cluster *clusterv1.ManagedCluster) (*clusterv1.ManagedCluster, reconcileState, error) {
+	violations := r.violations()
+	if r.configMapLister != nil {
+		published, err := fips.PublishedViolations(r.configMapLister)
+		if err != nil {
+			return cluster, reconcileContinue, err
+		}
+		violations = append(violations, published...)
+	}
+	if len(violations) == 0 {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    ManagedClusterConditionFIPSCompliant,
+			Status:  metav1.ConditionTrue,
+			Reason:  "FIPSCompliant",
+			Message: "The agent is running in the FIPS compliance mode.",
+		})
+		return cluster, reconcileContinue, nil
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    ManagedClusterConditionFIPSCompliant,
+		Status:  metav1.ConditionFalse,
+		Reason:  "FIPSNonCompliantOptions",
+		Message: fmt.Sprintf("The agent is configured with the options not compliant with FIPS: %s.", strings.Join(violations, "; ")),
+	})
+	return cluster, reconcileContinue, nil
+}
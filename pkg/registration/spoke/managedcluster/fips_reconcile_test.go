@@ -0,0 +1,81 @@
+package managedcluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func TestFIPSReconcile(t *testing.T) {
+	cases := []struct {
+		name               string
+		violations         []string
+		configMaps         []runtime.Object
+		expectedStatus     metav1.ConditionStatus
+		expectedViolations []string
+	}{
+		{
+			name:           "compliant",
+			expectedStatus: metav1.ConditionTrue,
+		},
+		{
+			name:               "non compliant",
+			violations:         []string{"the connection to the MQTT broker is not encrypted"},
+			expectedStatus:     metav1.ConditionFalse,
+			expectedViolations: []string{"the connection to the MQTT broker is not encrypted"},
+		},
+		{
+			name: "non compliant work agent",
+			configMaps: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fips.ViolationsConfigMapName, Namespace: "open-cluster-management-agent"},
+				Data:       map[string]string{"work-agent": "the connection to the gRPC server is not encrypted"},
+			}},
+			expectedStatus:     metav1.ConditionFalse,
+			expectedViolations: []string{"work-agent: the connection to the gRPC server is not encrypted"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.configMaps...)
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+			for _, cm := range c.configMaps {
+				if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(cm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := &fipsReconcile{
+				violations:      func() []string { return c.violations },
+				configMapLister: kubeInformerFactory.Core().V1().ConfigMaps().Lister().ConfigMaps("open-cluster-management-agent"),
+			}
+			updated, state, err := r.reconcile(context.TODO(), testinghelpers.NewJoinedManagedCluster())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if state != reconcileContinue {
+				t.Errorf("expected the reconcile continues")
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, ManagedClusterConditionFIPSCompliant)
+			if condition == nil || condition.Status != c.expectedStatus {
+				t.Fatalf("expected the condition status %s, but got %v", c.expectedStatus, condition)
+			}
+			for _, violation := range c.expectedViolations {
+				if !strings.Contains(condition.Message, violation) {
+					t.Errorf("expected the violation %q in the message, but got %q", violation, condition.Message)
+				}
+			}
+		})
+	}
+}
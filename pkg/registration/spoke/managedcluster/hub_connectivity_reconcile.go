@@ -0,0 +1,63 @@
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// ManagedClusterConditionHubConnectivity reports the round-trip time of the agent to the hub apiserver and the
+// last time the agent contacted the hub successfully.
+const ManagedClusterConditionHubConnectivity = "HubConnectivity"
+
+// hubConnectivityReconcile measures the round-trip time of a request to the hub apiserver, it is measured at most
+// once per interval to avoid updating the status of the managed cluster on every sync.
+type hubConnectivityReconcile struct {
+	hubClusterClient clientset.Interface
+	interval         time.Duration
+	clock            clock.Clock
+
+	lastProbe          time.Time
+	lastSuccessfulPing time.Time
+}
+
+func (r *hubConnectivityReconcile) reconcile(ctx context.Context, cluster *clusterv1.ManagedCluster) (*clusterv1.ManagedCluster, reconcileState, error) {
+	now := r.clock.Now()
+	if now.Sub(r.lastProbe) < r.interval {
+		return cluster, reconcileContinue, nil
+	}
+	r.lastProbe = now
+
+	_, err := r.hubClusterClient.ClusterV1().ManagedClusters().Get(ctx, cluster.Name, metav1.GetOptions{})
+	rtt := r.clock.Since(now)
+	if err != nil {
+		message := fmt.Sprintf("Failed to contact the hub apiserver: %v.", err)
+		if !r.lastSuccessfulPing.IsZero() {
+			message = fmt.Sprintf("%s Last successful contact at %s.", message, r.lastSuccessfulPing.UTC().Format(time.RFC3339))
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    ManagedClusterConditionHubConnectivity,
+			Status:  metav1.ConditionFalse,
+			Reason:  "HubUnreachable",
+			Message: message,
+		})
+		return cluster, reconcileContinue, nil
+	}
+
+	r.lastSuccessfulPing = now
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:   ManagedClusterConditionHubConnectivity,
+		Status: metav1.ConditionTrue,
+		Reason: "HubReachable",
+		Message: fmt.Sprintf("Round-trip time to the hub apiserver is %s. Last successful contact at %s.",
+			rtt.Round(time.Millisecond), now.UTC().Format(time.RFC3339)),
+	})
+	return cluster, reconcileContinue, nil
+}
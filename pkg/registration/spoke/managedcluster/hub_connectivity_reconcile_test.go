@@ -0,0 +1,60 @@
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func TestHubConnectivityReconcile(t *testing.T) {
+	cluster := testinghelpers.NewJoinedManagedCluster()
+	clusterClient := clusterfake.NewSimpleClientset(cluster)
+	c := testingclock.NewFakeClock(time.Now())
+	r := &hubConnectivityReconcile{hubClusterClient: clusterClient, interval: time.Minute, clock: c}
+
+	// the round-trip time is reported
+	updated, _, err := r.reconcile(context.TODO(), cluster.DeepCopy())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, ManagedClusterConditionHubConnectivity)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Fatalf("expected the hub is reachable, but got %v", condition)
+	}
+
+	// the hub is not probed again within the interval
+	c.Step(30 * time.Second)
+	updated, _, _ = r.reconcile(context.TODO(), cluster.DeepCopy())
+	if meta.FindStatusCondition(updated.Status.Conditions, ManagedClusterConditionHubConnectivity) != nil {
+		t.Errorf("expected the hub is not probed within the interval")
+	}
+	if len(clusterClient.Actions()) != 1 {
+		t.Errorf("expected 1 request to the hub, but got %d", len(clusterClient.Actions()))
+	}
+
+	// the last successful contact is kept if the hub is unreachable
+	clusterClient.PrependReactor("get", "managedclusters", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+	c.Step(time.Minute)
+	updated, _, _ = r.reconcile(context.TODO(), cluster.DeepCopy())
+	condition = meta.FindStatusCondition(updated.Status.Conditions, ManagedClusterConditionHubConnectivity)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected the hub is unreachable, but got %v", condition)
+	}
+	if !strings.Contains(condition.Message, "Last successful contact at") {
+		t.Errorf("expected the last successful contact in the message, but got %q", condition.Message)
+	}
+}
@@ -0,0 +1,72 @@
+package managedcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+// ManagedClusterConditionHubUnreachable reports the last outage of the hub observed by the circuit breakers of the
+// hub clients of the agents.
+const ManagedClusterConditionHubUnreachable = "HubUnreachable"
+
+// hubOutageTracker tracks the outages of the hub, it is implemented by the circuit breaker of the hub clients.
+type hubOutageTracker interface {
+	LastOutage() (bool, time.Time, time.Time)
+}
+
+// hubUnreachableReconcile reports the last outages of the hub observed by the agent process, and published in the
+// agent namespace by the agents running in their own processes, e.g. the work agent. The status can only be updated
+// once the hub is reachable again, so the condition tells when the agents lost the connection to the hub and for how
+// long.
+type hubUnreachableReconcile struct {
+	tracker hubOutageTracker
+	// configMapLister lists the configmaps in the agent namespace, it is nil if the outages are not published.
+	configMapLister corev1lister.ConfigMapNamespaceLister
+}
+
+func (r *hubUnreachableReconcile) reconcile(_ context.Context, cluster *clusterv1.ManagedCluster) (*clusterv1.ManagedCluster, reconcileState, error) {
+	unreachable, start, end := r.tracker.LastOutage()
+	outages := []commonhelpers.HubOutage{{Component: "agent", Unreachable: unreachable, Start: start, End: end}}
+	if r.configMapLister != nil {
+		published, err := commonhelpers.PublishedHubOutages(r.configMapLister)
+		if err != nil {
+			return cluster, reconcileContinue, err
+		}
+		outages = append(outages, published...)
+	}
+
+	condition := metav1.Condition{
+		Type:    ManagedClusterConditionHubUnreachable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "HubReachable",
+		Message: "No outage of the hub is observed by the agent.",
+	}
+	var messages []string
+	for _, outage := range outages {
+		switch {
+		case outage.Unreachable:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "HubUnreachable"
+			messages = append(messages, fmt.Sprintf("The hub is unreachable from the %s since %s.",
+				outage.Component, outage.Start.UTC().Format(time.RFC3339)))
+		case !outage.Start.IsZero():
+			messages = append(messages, fmt.Sprintf("The hub was unreachable from the %s from %s to %s.",
+				outage.Component, outage.Start.UTC().Format(time.RFC3339), outage.End.UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(messages) > 0 {
+		condition.Message = strings.Join(messages, " ")
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	return cluster, reconcileContinue, nil
+}
@@ -0,0 +1,94 @@
+package managedcluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+type fakeHubOutageTracker struct {
+	unreachable bool
+	start, end  time.Time
+}
+
+func (f *fakeHubOutageTracker) LastOutage() (bool, time.Time, time.Time) {
+	return f.unreachable, f.start, f.end
+}
+
+func TestHubUnreachableReconcile(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name            string
+		tracker         *fakeHubOutageTracker
+		configMaps      []runtime.Object
+		expectedStatus  metav1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:            "no outage",
+			tracker:         &fakeHubOutageTracker{},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedMessage: "No outage",
+		},
+		{
+			name:            "hub is unreachable",
+			tracker:         &fakeHubOutageTracker{unreachable: true, start: start},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "unreachable from the agent since 2024-01-01T00:00:00Z",
+		},
+		{
+			name:            "hub is reachable again",
+			tracker:         &fakeHubOutageTracker{start: start, end: start.Add(time.Hour)},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedMessage: "from 2024-01-01T00:00:00Z to 2024-01-01T01:00:00Z",
+		},
+		{
+			name:    "hub is unreachable from the work agent",
+			tracker: &fakeHubOutageTracker{},
+			configMaps: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: commonhelpers.HubOutagesConfigMapName, Namespace: "open-cluster-management-agent"},
+				Data:       map[string]string{"work-agent": `{"unreachable":true,"start":"2024-01-01T00:00:00Z"}`},
+			}},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "unreachable from the work-agent since 2024-01-01T00:00:00Z",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset(c.configMaps...)
+			kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 10*time.Minute)
+			for _, cm := range c.configMaps {
+				if err := kubeInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(cm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := &hubUnreachableReconcile{
+				tracker:         c.tracker,
+				configMapLister: kubeInformerFactory.Core().V1().ConfigMaps().Lister().ConfigMaps("open-cluster-management-agent"),
+			}
+			updated, _, err := r.reconcile(context.TODO(), testinghelpers.NewJoinedManagedCluster())
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, ManagedClusterConditionHubUnreachable)
+			if condition == nil || condition.Status != c.expectedStatus {
+				t.Fatalf("expected condition status %s, but got %v", c.expectedStatus, condition)
+			}
+			if !strings.Contains(condition.Message, c.expectedMessage) {
+				t.Errorf("expected message contains %q, but got %q", c.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
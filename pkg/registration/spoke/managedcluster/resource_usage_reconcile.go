@@ -0,0 +1,170 @@
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+)
+
+const (
+	nodeMetricsPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+	podListPageSize = 500
+)
+
+// nodeMetricsList is the part of the NodeMetricsList of the metrics server used to aggregate the usage.
+type nodeMetricsList struct {
+	Items []struct {
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"items"`
+}
+
+// resourceUsageReconcile reports the cpu and memory requested by the pods and used by the nodes of the managed
+// cluster, it is reported at most once per interval since listing the pods is expensive on a large cluster. The
+// used resources are not reported if the metrics server is not installed.
+type resourceUsageReconcile struct {
+	kubeClient kubernetes.Interface
+	interval   time.Duration
+	clock      clock.Clock
+
+	lastReport time.Time
+}
+
+func (r *resourceUsageReconcile) reconcile(ctx context.Context, cluster *clusterv1.ManagedCluster) (*clusterv1.ManagedCluster, reconcileState, error) {
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+		return cluster, reconcileContinue, nil
+	}
+
+	now := r.clock.Now()
+	if now.Sub(r.lastReport) < r.interval {
+		// keep the last reported usage until the next report
+		return cluster, reconcileContinue, nil
+	}
+
+	requested, err := r.getRequestedResources(ctx)
+	if err != nil {
+		return cluster, reconcileContinue, fmt.Errorf("unable to get the requested resources of managed cluster %q: %w", cluster.Name, err)
+	}
+	used, err := r.getUsedResources(ctx)
+	if err != nil {
+		return cluster, reconcileContinue, fmt.Errorf("unable to get the used resources of managed cluster %q: %w", cluster.Name, err)
+	}
+	r.lastReport = now
+
+	if cluster.Status.Capacity == nil {
+		cluster.Status.Capacity = clusterv1.ResourceList{}
+	}
+	cluster.Status.Capacity[helpers.ResourceCPURequested] = requested[corev1.ResourceCPU]
+	cluster.Status.Capacity[helpers.ResourceMemoryRequested] = requested[corev1.ResourceMemory]
+	if used == nil {
+		delete(cluster.Status.Capacity, helpers.ResourceCPUUsed)
+		delete(cluster.Status.Capacity, helpers.ResourceMemoryUsed)
+	} else {
+		cluster.Status.Capacity[helpers.ResourceCPUUsed] = used[corev1.ResourceCPU]
+		cluster.Status.Capacity[helpers.ResourceMemoryUsed] = used[corev1.ResourceMemory]
+	}
+	return cluster, reconcileContinue, nil
+}
+
+// getRequestedResources returns the cpu and memory requested by the pods not terminated.
+func (r *resourceUsageReconcile) getRequestedResources(ctx context.Context) (corev1.ResourceList, error) {
+	requested := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(0, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(0, resource.BinarySI),
+	}
+
+	options := metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+		Limit:         podListPageSize,
+	}
+	for {
+		pods, err := r.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pods.Items {
+			addResources(requested, podRequests(&pods.Items[i]))
+		}
+		if len(pods.Continue) == 0 {
+			return requested, nil
+		}
+		options.Continue = pods.Continue
+	}
+}
+
+// getUsedResources returns the cpu and memory used by the nodes, nil is returned if the metrics server is not
+// available.
+func (r *resourceUsageReconcile) getUsedResources(ctx context.Context) (corev1.ResourceList, error) {
+	raw, err := r.kubeClient.Discovery().RESTClient().Get().AbsPath(nodeMetricsPath).Do(ctx).Raw()
+	if errors.IsNotFound(err) || errors.IsServiceUnavailable(err) {
+		klog.V(4).Infof("The metrics server is not available: %v", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &nodeMetricsList{}
+	if err := json.Unmarshal(raw, metrics); err != nil {
+		return nil, err
+	}
+	used := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(0, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(0, resource.BinarySI),
+	}
+	for _, item := range metrics.Items {
+		addResources(used, item.Usage)
+	}
+	return used, nil
+}
+
+// podRequests returns the effective cpu and memory requests of the pod, which is the larger one of the sum of the
+// containers and any of the init containers, plus the pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			value, ok := container.Resources.Requests[name]
+			if !ok {
+				continue
+			}
+			if current, ok := requests[name]; !ok || value.Cmp(current) > 0 {
+				requests[name] = value.DeepCopy()
+			}
+		}
+	}
+	addResources(requests, pod.Spec.Overhead)
+	return requests
+}
+
+// addResources adds the cpu and memory of the resources to the total.
+func addResources(total, resources corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		value, ok := resources[name]
+		if !ok {
+			continue
+		}
+		if current, ok := total[name]; ok {
+			current.Add(value)
+			total[name] = current
+		} else {
+			total[name] = value.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package managedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	testingclock "k8s.io/utils/clock/testing"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+)
+
+func newPod(name string, containerRequests, initContainerRequests corev1.ResourceList) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: containerRequests}}},
+		},
+	}
+	if initContainerRequests != nil {
+		pod.Spec.InitContainers = []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: initContainerRequests}}}
+	}
+	return pod
+}
+
+func TestResourceUsageReconcile(t *testing.T) {
+	pods := &corev1.PodList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"},
+		Items: []corev1.Pod{
+			newPod("pod1", corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}, nil),
+			newPod("pod2", corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("250m"),
+			}, corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			}),
+		},
+	}
+	metricsAvailable := true
+	requests := 0
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.URL.Path == "/api/v1/pods":
+			if err := json.NewEncoder(w).Encode(pods); err != nil {
+				t.Error(err)
+			}
+		case req.URL.Path == nodeMetricsPath && metricsAvailable:
+			_, _ = w.Write([]byte(`{"items":[{"usage":{"cpu":"300m","memory":"2Gi"}},{"usage":{"cpu":"200m","memory":"1Gi"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer apiServer.Close()
+
+	kubeClient := kubernetes.NewForConfigOrDie(&rest.Config{Host: apiServer.URL})
+	c := testingclock.NewFakeClock(time.Now())
+	r := &resourceUsageReconcile{kubeClient: kubeClient, interval: time.Minute, clock: c}
+
+	assertCapacity := func(cluster *clusterv1.ManagedCluster, name clusterv1.ResourceName, expected string) {
+		t.Helper()
+		actual, ok := cluster.Status.Capacity[name]
+		if expected == "" {
+			if ok {
+				t.Errorf("expected %s is not reported, but got %s", name, actual.String())
+			}
+			return
+		}
+		if !ok || actual.Cmp(resource.MustParse(expected)) != 0 {
+			t.Errorf("expected %s %s, but got %s", name, expected, actual.String())
+		}
+	}
+
+	// the usage is not reported for the unavailable cluster
+	updated, _, err := r.reconcile(context.TODO(), testinghelpers.NewUnAvailableManagedCluster())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Status.Capacity) != 0 || requests != 0 {
+		t.Errorf("expected the usage is not reported, but got %v", updated.Status.Capacity)
+	}
+
+	cluster := testinghelpers.NewAvailableManagedCluster()
+	updated, _, err = r.reconcile(context.TODO(), cluster.DeepCopy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCapacity(updated, helpers.ResourceCPURequested, "1500m")
+	assertCapacity(updated, helpers.ResourceMemoryRequested, "1536Mi")
+	assertCapacity(updated, helpers.ResourceCPUUsed, "500m")
+	assertCapacity(updated, helpers.ResourceMemoryUsed, "3Gi")
+
+	// the usage is not reported again within the interval
+	requests = 0
+	c.Step(30 * time.Second)
+	if _, _, err := r.reconcile(context.TODO(), cluster.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request within the interval, but got %d", requests)
+	}
+
+	// the used resources are removed if the metrics server is not available
+	metricsAvailable = false
+	c.Step(time.Minute)
+	updated, _, err = r.reconcile(context.TODO(), updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCapacity(updated, helpers.ResourceCPURequested, "1500m")
+	assertCapacity(updated, helpers.ResourceCPUUsed, "")
+	assertCapacity(updated, helpers.ResourceMemoryUsed, "")
+}
@@ -13,7 +13,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/discovery"
 	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
 	kevents "k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
 
 	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterv1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
@@ -21,6 +23,11 @@ import (
 	clusterv1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/common/health"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
 // managedClusterStatusController checks the kube-apiserver health on managed cluster to determine it whether is available
@@ -54,6 +61,13 @@ func NewManagedClusterStatusController(
 	nodeInformer corev1informers.NodeInformer,
 	maxCustomClusterClaims int,
 	resyncInterval time.Duration,
+	hubConnectivityReportInterval time.Duration,
+	resourceUsageReportInterval time.Duration,
+	hubCircuitBreaker *commonhelpers.CircuitBreaker,
+	managedClusterKubeClient kubernetes.Interface,
+	agentNamespace string,
+	agentConfigMapInformer corev1informers.ConfigMapInformer,
+	controllerHealth *health.ControllerHealth,
 	recorder events.Recorder,
 	hubEventRecorder kevents.EventRecorder) factory.Controller {
 	c := newManagedClusterStatusController(
@@ -67,10 +81,41 @@ func NewManagedClusterStatusController(
 		recorder,
 		hubEventRecorder,
 	)
+	if hubConnectivityReportInterval > 0 {
+		c.reconcilers = append(c.reconcilers, &hubConnectivityReconcile{
+			hubClusterClient: hubClusterClient,
+			interval:         hubConnectivityReportInterval,
+			clock:            clock.RealClock{},
+		})
+	}
+	if resourceUsageReportInterval > 0 {
+		c.reconcilers = append(c.reconcilers, &resourceUsageReconcile{
+			kubeClient: managedClusterKubeClient,
+			interval:   resourceUsageReportInterval,
+			clock:      clock.RealClock{},
+		})
+	}
+	controllerFactory := factory.New().
+		WithInformers(hubClusterInformer.Informer(), nodeInformer.Informer(), claimInformer.Informer())
+	if hubCircuitBreaker != nil {
+		c.reconcilers = append(c.reconcilers, &hubUnreachableReconcile{
+			tracker:         hubCircuitBreaker,
+			configMapLister: agentConfigMapInformer.Lister().ConfigMaps(agentNamespace),
+		})
+		controllerFactory = controllerFactory.WithFilteredEventsInformers(
+			queue.FilterByNames(commonhelpers.HubOutagesConfigMapName), agentConfigMapInformer.Informer())
+	}
+	if fips.Enabled() {
+		c.reconcilers = append(c.reconcilers, &fipsReconcile{
+			violations:      fips.Violations,
+			configMapLister: agentConfigMapInformer.Lister().ConfigMaps(agentNamespace),
+		})
+		controllerFactory = controllerFactory.WithFilteredEventsInformers(
+			queue.FilterByNames(fips.ViolationsConfigMapName), agentConfigMapInformer.Informer())
+	}
 
-	return factory.New().
-		WithInformers(hubClusterInformer.Informer(), nodeInformer.Informer(), claimInformer.Informer()).
-		WithSync(c.sync).
+	return controllerFactory.
+		WithSync(controllerHealth.ObserveSync(c.sync)).
 		ResyncEvery(resyncInterval).
 		ToController("ManagedClusterStatusController", recorder)
 }
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// Constants for metric names.
+	RegistrationAgentSubsystem = "registration_agent"
+	InformerCacheSizeKey       = "informer_cache_size"
+	HubRequestsTotalKey        = "hub_requests_total"
+
+	// hubRequestErrorCode is the code of the hub requests failed without a response.
+	hubRequestErrorCode = "<error>"
+)
+
+var (
+	informerCacheSizeDesc = k8smetrics.NewDesc(
+		k8smetrics.BuildFQName("", RegistrationAgentSubsystem, InformerCacheSizeKey),
+		"Number of the objects in the cache of the informers by informer name.",
+		[]string{"informer"}, nil, k8smetrics.ALPHA, "")
+
+	hubRequests = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      RegistrationAgentSubsystem,
+		Name:           HubRequestsTotalKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help: "Number of the requests to the hub by method and status code, the code is <error> if the request " +
+			"fails without a response.",
+	}, []string{"method", "code"})
+
+	// informers is the collector of the informer caches, it is registered once and collects the informers added
+	// by RegisterInformers.
+	informers    = &informerCollector{informers: map[string]cache.SharedIndexInformer{}}
+	registerOnce sync.Once
+)
+
+func init() {
+	legacyregistry.MustRegister(hubRequests)
+}
+
+// RegisterInformers exports the number of the objects in the cache of the informers by name, the informer replaces
+// the one registered with the same name before, e.g. after the agent restarts the controllers with a new hub.
+func RegisterInformers(namedInformers map[string]cache.SharedIndexInformer) {
+	informers.add(namedInformers)
+	registerOnce.Do(func() {
+		legacyregistry.CustomMustRegister(informers)
+	})
+}
+
+// WrapHubTransport counts the requests of the clients built with the config by method and status code, so the error
+// rate of the requests to the hub is exported.
+func WrapHubTransport(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &hubRequestsRoundTripper{delegate: rt}
+	})
+}
+
+type hubRequestsRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *hubRequestsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	code := hubRequestErrorCode
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	hubRequests.WithLabelValues(req.Method, code).Inc()
+	return resp, err
+}
+
+type informerCollector struct {
+	k8smetrics.BaseStableCollector
+	lock      sync.RWMutex
+	informers map[string]cache.SharedIndexInformer
+}
+
+func (c *informerCollector) add(namedInformers map[string]cache.SharedIndexInformer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for name, informer := range namedInformers {
+		c.informers[name] = informer
+	}
+}
+
+func (c *informerCollector) DescribeWithStability(ch chan<- *k8smetrics.Desc) {
+	ch <- informerCacheSizeDesc
+}
+
+func (c *informerCollector) CollectWithStability(ch chan<- k8smetrics.Metric) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	for name, informer := range c.informers {
+		size := len(informer.GetStore().ListKeys())
+		ch <- k8smetrics.NewLazyConstMetric(informerCacheSizeDesc, k8smetrics.GaugeValue, float64(size), name)
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestInformerCollector(t *testing.T) {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &corev1.Node{}, 0, cache.Indexers{})
+	for _, name := range []string{"node1", "node2"} {
+		if err := informer.GetStore().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collector := &informerCollector{informers: map[string]cache.SharedIndexInformer{}}
+	collector.add(map[string]cache.SharedIndexInformer{"nodes": informer})
+	expected := `
+		# HELP registration_agent_informer_cache_size [ALPHA] Number of the objects in the cache of the informers by informer name.
+		# TYPE registration_agent_informer_cache_size gauge
+		registration_agent_informer_cache_size{informer="nodes"} 2
+	`
+	if err := testutil.CustomCollectAndCompare(collector, strings.NewReader(expected),
+		"registration_agent_informer_cache_size"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWrapHubTransport(t *testing.T) {
+	hubRequests.Reset()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+	WrapHubTransport(config)
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	if _, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), "node1", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected error, but failed")
+	}
+
+	count, err := testutil.GetCounterMetricValue(hubRequests.WithLabelValues(http.MethodGet, "500"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the client retries the requests failed with 500
+	if count < 1 {
+		t.Errorf("expected the failed requests are counted, but got %v", count)
+	}
+}
@@ -2,20 +2,46 @@ package spoke
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apiserver/pkg/server/healthz"
+	certutil "k8s.io/client-go/util/cert"
 
 	ocmfeature "open-cluster-management.io/api/feature"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
 
 var ClientCertHealthCheckInterval = 30 * time.Second
 
+// HubCABundleHealthCheckInterval is the interval to check if the CA bundles of the hub are changed.
+var HubCABundleHealthCheckInterval = 30 * time.Second
+
+// The names of the readiness contributors of the controllers and the hub connection.
+const (
+	managedClusterLeaseHealthName  = "managedcluster-lease-controller"
+	managedClusterStatusHealthName = "managedcluster-status-controller"
+	addOnLeaseHealthName           = "addon-lease-controller"
+	addOnRegistrationHealthName    = "addon-registration-controller"
+	hubConnectivityHealthName      = "hub-connectivity"
+)
+
+var (
+	// HealthFailureThreshold is how long a controller keeps failing to sync, or the hub keeps unreachable, before
+	// the agent reports not ready.
+	HealthFailureThreshold = 5 * time.Minute
+	// HubConnectivityCheckInterval is the interval to probe the connection to the hub.
+	HubConnectivityCheckInterval = 30 * time.Second
+)
+
 // SpokeAgentOptions holds configuration for spoke cluster agent
 type SpokeAgentOptions struct {
 	// The differences among BootstrapKubeconfig, BootstrapKubeconfigSecret, BootstrapKubeconfigSecrets are:
@@ -26,25 +52,69 @@ type SpokeAgentOptions struct {
 	BootstrapKubeconfig       string
 	BootstrapKubeconfigSecret string
 	BootstrapKubeconfigs      []string
+	// BootstrapCABundleFile is the file of a CA bundle appended to the CA bundle of the bootstrap kubeconfig, and
+	// BootstrapProxyURL overrides the proxy url of the bootstrap kubeconfig. They are for the agent connecting to the
+	// hub through a TLS-inspecting proxy which presents a certificate signed by its own CA.
+	BootstrapCABundleFile string
+	BootstrapProxyURL     string
+	// proxyCABundleFile is the CA bundle of the proxy set in the agent options, it is trusted by the bootstrap
+	// kubeconfig as well.
+	proxyCABundleFile string
 
 	// TODO: The hubConnectionTimoutSeconds should always greater than leaseDurationSeconds, we need to make timeout as a build-in part of
 	// leaseController in the furture and relate timeoutseconds to leaseDurationSeconds. @xuezhaojun
 	// See more details in: https://github.com/open-cluster-management-io/ocm/pull/443#discussion_r1610868646
 	HubConnectionTimeoutSeconds int32
 
-	HubKubeconfigSecret         string
-	SpokeExternalServerURLs     []string
-	ClusterHealthCheckPeriod    time.Duration
+	HubKubeconfigSecret      string
+	SpokeExternalServerURLs  []string
+	ClusterHealthCheckPeriod time.Duration
+	// HubConnectivityReportInterval is the interval to report the round-trip time to the hub apiserver in the
+	// status of the managed cluster, it is disabled if it is 0.
+	HubConnectivityReportInterval time.Duration
+	// ResourceUsageReportInterval is the interval to report the cpu and memory requested by the pods and used by
+	// the nodes in the capacity of the managed cluster, it is disabled if it is 0.
+	ResourceUsageReportInterval time.Duration
 	MaxCustomClusterClaims      int
 	ClientCertExpirationSeconds int32
-	ClusterAnnotations          map[string]string
+	// ClientCertIPSANs and ClientCertURISANs are the IP addresses and the URIs set as the SANs of the client
+	// certificate besides the subject, they are approved only if the hub allows them.
+	ClientCertIPSANs  []string
+	ClientCertURISANs []string
+	// SPIFFETrustDomain is the trust domain of the SPIFFE ID of the agent, the SPIFFE ID is set as the URI SAN of the
+	// client certificate requested by the agent if it is set.
+	SPIFFETrustDomain  string
+	ClusterAnnotations map[string]string
+	// AgentIdentityGeneration is the requested generation of the agent identity, the agent name is regenerated and
+	// the agent bootstraps again once it is changed.
+	AgentIdentityGeneration string
+	// AddOnKubeconfigExecCredential allows the addons to reference an exec credential plugin in their hub
+	// kubeconfigs instead of the client certificate and key files, see addon.ExecCommandAnnotationKey.
+	AddOnKubeconfigExecCredential bool
+	// HubCABundleDistribution stores the current and the next CA bundles of the hub distributed by the hub in the
+	// hub kubeconfig secret, they are trusted by the agents besides the CA in the hub kubeconfig.
+	HubCABundleDistribution bool
+	// AddOnHubProxyBindAddress and AddOnHubProxyURL enable the addon hub proxy. The client certificates of the addons
+	// are kept by the agent, and the addons access the hub through the proxy listening on the bind address with the
+	// token kubeconfigs whose server is the proxy url.
+	AddOnHubProxyBindAddress string
+	AddOnHubProxyURL         string
 
 	clientCertHealthChecker          *clientCertHealthChecker
 	bootstrapKubeconfigHealthChecker *bootstrapKubeconfigHealthChecker
+	hubCABundleHealth                *health.HubCABundleHealth
 	reSelectChecker                  *reSelectChecker
+
+	controllerHealths     map[string]*health.ControllerHealth
+	hubConnectivityHealth *health.ConnectivityHealth
 }
 
 func NewSpokeAgentOptions() *SpokeAgentOptions {
+	controllerHealths := map[string]*health.ControllerHealth{}
+	for _, name := range controllerHealthNames() {
+		controllerHealths[name] = health.NewControllerHealth(name, HealthFailureThreshold)
+	}
+
 	options := &SpokeAgentOptions{
 		BootstrapKubeconfigSecret: "bootstrap-hub-kubeconfig",
 		HubKubeconfigSecret:       "hub-kubeconfig-secret",
@@ -54,8 +124,12 @@ func NewSpokeAgentOptions() *SpokeAgentOptions {
 		clientCertHealthChecker: &clientCertHealthChecker{
 			interval: ClientCertHealthCheckInterval,
 		},
+		hubCABundleHealth:           health.NewHubCABundleHealth(HubCABundleHealthCheckInterval),
 		HubConnectionTimeoutSeconds: 600, // by default, the timeout is 10 minutes
 		reSelectChecker:             &reSelectChecker{shouldReSelect: false},
+		controllerHealths:           controllerHealths,
+		hubConnectivityHealth: health.NewConnectivityHealth(
+			hubConnectivityHealthName, HubConnectivityCheckInterval, HealthFailureThreshold),
 	}
 
 	options.bootstrapKubeconfigHealthChecker = &bootstrapKubeconfigHealthChecker{
@@ -73,6 +147,11 @@ func (o *SpokeAgentOptions) AddFlags(fs *pflag.FlagSet) {
 		"The name of secret in component namespace storing kubeconfig for agent bootstrap.")
 	fs.StringArrayVar(&o.BootstrapKubeconfigs, "bootstrap-kubeconfigs", o.BootstrapKubeconfigs,
 		"The name of secrets in component namespace storing bootstrap kubeconfigs for agent bootstrap.")
+	fs.StringVar(&o.BootstrapCABundleFile, "bootstrap-ca-bundle-file", o.BootstrapCABundleFile,
+		"The path of a CA bundle file appended to the CA bundle of the bootstrap kubeconfig, e.g. the CA of a "+
+			"TLS-inspecting proxy between the agent and the hub. It is kept in the hub kubeconfig as well.")
+	fs.StringVar(&o.BootstrapProxyURL, "bootstrap-proxy-url", o.BootstrapProxyURL,
+		"The proxy url overriding the one in the bootstrap kubeconfig. It is kept in the hub kubeconfig as well.")
 	fs.Int32Var(&o.HubConnectionTimeoutSeconds, "hub-connection-timeout-seconds", o.HubConnectionTimeoutSeconds,
 		"The timeout in seconds to connect to hub cluster.")
 	fs.StringVar(&o.HubKubeconfigSecret, "hub-kubeconfig-secret", o.HubKubeconfigSecret,
@@ -81,13 +160,52 @@ func (o *SpokeAgentOptions) AddFlags(fs *pflag.FlagSet) {
 		"A list of reachable spoke cluster api server URLs for hub cluster.")
 	fs.DurationVar(&o.ClusterHealthCheckPeriod, "cluster-healthcheck-period", o.ClusterHealthCheckPeriod,
 		"The period to check managed cluster kube-apiserver health")
+	fs.DurationVar(&o.HubConnectivityReportInterval, "hub-connectivity-report-interval", o.HubConnectivityReportInterval,
+		"The interval to report the round-trip time to the hub apiserver and the last successful contact in the "+
+			"HubConnectivity condition of the managed cluster. The report is disabled if it is 0, and it is not "+
+			"reported more often than the cluster healthcheck period.")
+	fs.DurationVar(&o.ResourceUsageReportInterval, "resource-usage-report-interval", o.ResourceUsageReportInterval,
+		"The interval to report the cpu and memory requested by the pods and used by the nodes of the managed cluster "+
+			"in the capacity of the managed cluster, the used resources are read from the metrics server. The report "+
+			"is disabled if it is 0, and it is not reported more often than the cluster healthcheck period. The agent "+
+			"requires the permission to list the pods and the nodes.metrics.k8s.io on the managed cluster to report it.")
 	fs.IntVar(&o.MaxCustomClusterClaims, "max-custom-cluster-claims", o.MaxCustomClusterClaims,
 		"The max number of custom cluster claims to expose.")
 	fs.Int32Var(&o.ClientCertExpirationSeconds, "client-cert-expiration-seconds", o.ClientCertExpirationSeconds,
 		"The requested duration in seconds of validity of the issued client certificate. If this is not set, "+
 			"the value of --cluster-signing-duration command-line flag of the kube-controller-manager will be used.")
+	fs.StringVar(&o.SPIFFETrustDomain, "spiffe-trust-domain", o.SPIFFETrustDomain,
+		"The trust domain of the SPIFFE ID of the agent. If it is set, the SPIFFE ID "+
+			"spiffe://<trust domain>/open-cluster-management/cluster/<cluster name> is set as the URI SAN of the client "+
+			"certificate requested by the agent, it must be the trust domain set on the hub. It is applied to the "+
+			"client certificate requested after the change, e.g. at the next rotation.")
+	fs.StringSliceVar(&o.ClientCertIPSANs, "client-cert-ip-sans", o.ClientCertIPSANs,
+		"The IP addresses set as the SANs of the client certificate requested by the agent. The csr is approved "+
+			"only if the IP addresses are in the networks allowed by the hub.")
+	fs.StringSliceVar(&o.ClientCertURISANs, "client-cert-uri-sans", o.ClientCertURISANs,
+		"The URIs set as the SANs of the client certificate requested by the agent besides the SPIFFE ID of "+
+			"--spiffe-trust-domain. The csr is approved only if the URIs have the prefixes allowed by the hub. They are "+
+			"applied to the client certificate requested after the change, e.g. at the next rotation.")
 	fs.StringToStringVar(&o.ClusterAnnotations, "cluster-annotations", o.ClusterAnnotations, `the annotations with the reserve
 	 prefix "agent.open-cluster-management.io" set on ManagedCluster when creating only, other actors can update it afterwards.`)
+	fs.StringVar(&o.AgentIdentityGeneration, "agent-identity-generation", o.AgentIdentityGeneration,
+		"The generation of the agent identity. Once it is changed, the hub kubeconfig secret is removed, the agent "+
+			"name is regenerated and the agent bootstraps again.")
+	fs.BoolVar(&o.AddOnKubeconfigExecCredential, "addon-kubeconfig-exec-credential", o.AddOnKubeconfigExecCredential,
+		"If set, the hub kubeconfig of an addon references the exec credential plugin set in the annotation "+
+			"addon.open-cluster-management.io/experimental-hub-kubeconfig-exec-command of the ManagedClusterAddOn, "+
+			"instead of the client certificate and key files. The addons pick up the rotated client certificate with "+
+			"the plugin without reloading the kubeconfig.")
+	fs.BoolVar(&o.HubCABundleDistribution, "hub-ca-bundle-distribution", o.HubCABundleDistribution,
+		"Store the current and the next CA bundles of the hub in the hub-ca-bundle configmap of the cluster namespace "+
+			"on the hub in the hub kubeconfig secret. The agents trust them besides the CA in the hub kubeconfig, and the "+
+			"agent is restarted once they are changed, so it trusts the next CA of the hub before the hub is rotated.")
+	fs.StringVar(&o.AddOnHubProxyBindAddress, "addon-hub-proxy-bind-address", o.AddOnHubProxyBindAddress,
+		"The address the addon hub proxy listens on, e.g. ':9443'. The proxy is enabled with the addon-hub-proxy-url, "+
+			"the client certificates of the addons are kept in the agent namespace, and the hub kubeconfigs of the "+
+			"addons contain the tokens to access the hub through the proxy, which are rotated by the agent.")
+	fs.StringVar(&o.AddOnHubProxyURL, "addon-hub-proxy-url", o.AddOnHubProxyURL,
+		"The https url of the addon hub proxy reachable from the addon agents, e.g. the url of a service of the agent.")
 }
 
 // Validate verifies the inputs.
@@ -112,21 +230,80 @@ func (o *SpokeAgentOptions) Validate() error {
 		}
 	}
 
+	if len(o.BootstrapCABundleFile) > 0 {
+		if _, err := certutil.CertsFromFile(o.BootstrapCABundleFile); err != nil {
+			return fmt.Errorf("invalid bootstrap ca bundle file: %w", err)
+		}
+	}
+
+	if len(o.BootstrapProxyURL) > 0 {
+		proxyURL, err := url.Parse(o.BootstrapProxyURL)
+		if err != nil || len(proxyURL.Scheme) == 0 || len(proxyURL.Host) == 0 {
+			return fmt.Errorf("invalid bootstrap proxy url %q", o.BootstrapProxyURL)
+		}
+	}
+
 	if o.ClusterHealthCheckPeriod <= 0 {
 		return errors.New("cluster healthcheck period must greater than zero")
 	}
 
+	if o.HubConnectivityReportInterval < 0 {
+		return errors.New("hub connectivity report interval must not be negative")
+	}
+
+	if o.ResourceUsageReportInterval < 0 {
+		return errors.New("resource usage report interval must not be negative")
+	}
+
 	if o.ClientCertExpirationSeconds != 0 && o.ClientCertExpirationSeconds < 3600 {
 		return errors.New("client certificate expiration seconds must greater or qual to 3600")
 	}
 
+	if len(o.SPIFFETrustDomain) > 0 {
+		if errs := validation.IsDNS1123Subdomain(o.SPIFFETrustDomain); len(errs) > 0 {
+			return fmt.Errorf("invalid spiffe trust domain %q: %s", o.SPIFFETrustDomain, strings.Join(errs, ", "))
+		}
+	}
+
+	if _, _, err := clientcert.ParseSANs(o.ClientCertIPSANs, o.ClientCertURISANs); err != nil {
+		return err
+	}
+
+	if (len(o.AddOnHubProxyBindAddress) == 0) != (len(o.AddOnHubProxyURL) == 0) {
+		return errors.New("addon hub proxy bind address and url must be set together")
+	}
+	if len(o.AddOnHubProxyURL) > 0 && !helpers.IsValidHTTPSURL(o.AddOnHubProxyURL) {
+		return fmt.Errorf("invalid addon hub proxy url %q", o.AddOnHubProxyURL)
+	}
+
 	return nil
 }
 
+// GetHealthCheckers returns the liveness checks of the agent, they restart the agent to bootstrap again.
 func (o *SpokeAgentOptions) GetHealthCheckers() []healthz.HealthChecker {
 	return []healthz.HealthChecker{
 		o.bootstrapKubeconfigHealthChecker,
 		o.clientCertHealthChecker,
+		o.hubCABundleHealth,
 		o.reSelectChecker,
 	}
 }
+
+// GetReadinessCheckers returns the readiness contributors of the controllers and the hub connection of the
+// registration agent, they are aggregated by health.Readiness.
+func (o *SpokeAgentOptions) GetReadinessCheckers() []healthz.HealthChecker {
+	checkers := []healthz.HealthChecker{o.hubConnectivityHealth}
+	for _, name := range controllerHealthNames() {
+		checkers = append(checkers, o.controllerHealths[name])
+	}
+	return checkers
+}
+
+func controllerHealthNames() []string {
+	return []string{
+		managedClusterLeaseHealthName,
+		managedClusterStatusHealthName,
+		addOnLeaseHealthName,
+		addOnRegistrationHealthName,
+	}
+}
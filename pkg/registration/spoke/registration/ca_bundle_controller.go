@@ -0,0 +1,135 @@
+package registration
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+	"open-cluster-management.io/ocm/pkg/registration/hub/cabundle"
+)
+
+// hubCABundleController watches the hub-ca-bundle configmap in the cluster namespace on the hub, and stores the
+// current and the next CA bundles of the hub in the hub kubeconfig secret, so the agents trust the next CA of the hub
+// before the hub is rotated to it.
+type hubCABundleController struct {
+	clusterName                  string
+	hubKubeconfigSecretNamespace string
+	hubKubeconfigSecretName      string
+	hubConfigMapLister           corev1listers.ConfigMapLister
+	spokeSecretLister            corev1listers.SecretLister
+	spokeCoreClient              corev1client.CoreV1Interface
+}
+
+// NewHubCABundleController returns a new HubCABundleController
+func NewHubCABundleController(
+	clusterName, hubKubeconfigSecretNamespace, hubKubeconfigSecretName string,
+	spokeCoreClient corev1client.CoreV1Interface,
+	hubConfigMapInformer corev1informers.ConfigMapInformer,
+	spokeSecretInformer corev1informers.SecretInformer,
+	recorder events.Recorder) factory.Controller {
+	c := &hubCABundleController{
+		clusterName:                  clusterName,
+		hubKubeconfigSecretNamespace: hubKubeconfigSecretNamespace,
+		hubKubeconfigSecretName:      hubKubeconfigSecretName,
+		hubConfigMapLister:           hubConfigMapInformer.Lister(),
+		spokeSecretLister:            spokeSecretInformer.Lister(),
+		spokeCoreClient:              spokeCoreClient,
+	}
+
+	return factory.New().
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			queue.FilterByNames(cabundle.HubCABundleConfigMapName),
+			hubConfigMapInformer.Informer()).
+		// the client certificate controller overwrites the data of the secret once the certificate is rotated, the
+		// CA bundles are stored again then.
+		WithFilteredEventsInformersQueueKeysFunc(
+			queue.QueueKeyByMetaName,
+			queue.FilterByNames(hubKubeconfigSecretName),
+			spokeSecretInformer.Informer()).
+		WithSync(c.sync).
+		ToController("HubCABundleController", recorder)
+}
+
+func (c *hubCABundleController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Reconciling the hub CA bundle", "hubKubeconfigSecretName", c.hubKubeconfigSecretName)
+
+	secret, err := c.spokeSecretLister.Secrets(c.hubKubeconfigSecretNamespace).Get(c.hubKubeconfigSecretName)
+	if errors.IsNotFound(err) {
+		// the secret is not created until the agent is bootstrapped
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	configMap, err := c.hubConfigMapLister.ConfigMaps(c.clusterName).Get(cabundle.HubCABundleConfigMapName)
+	switch {
+	case errors.IsNotFound(err):
+		// keep the CA bundles already stored, the hub may stop distributing them while the agents still depend on them
+		return nil
+	case err != nil:
+		return err
+	}
+
+	caBundle, err := mergeCABundles(
+		[]byte(configMap.Data[cabundle.CABundleKey]), []byte(configMap.Data[cabundle.NextCABundleKey]))
+	if err != nil {
+		return fmt.Errorf("invalid CA bundles in configmap %s/%s: %w", c.clusterName, cabundle.HubCABundleConfigMapName, err)
+	}
+	if len(caBundle) == 0 || bytes.Equal(secret.Data[clientcert.HubCABundleFile], caBundle) {
+		return nil
+	}
+
+	secretCopy := secret.DeepCopy()
+	if secretCopy.Data == nil {
+		secretCopy.Data = map[string][]byte{}
+	}
+	secretCopy.Data[clientcert.HubCABundleFile] = caBundle
+	if _, err := c.spokeCoreClient.Secrets(c.hubKubeconfigSecretNamespace).Update(ctx, secretCopy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	syncCtx.Recorder().Eventf("HubCABundleUpdated", "The CA bundles of the hub are updated in secret %s/%s",
+		c.hubKubeconfigSecretNamespace, c.hubKubeconfigSecretName)
+	return nil
+}
+
+// mergeCABundles returns the certificates in the CA bundles in PEM format, the duplicated certificates are removed.
+func mergeCABundles(caBundles ...[]byte) ([]byte, error) {
+	var certs []*x509.Certificate
+	seen := map[string]bool{}
+	for _, caBundle := range caBundles {
+		if len(bytes.TrimSpace(caBundle)) == 0 {
+			continue
+		}
+		parsed, err := certutil.ParseCertsPEM(caBundle)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range parsed {
+			if seen[string(cert.Raw)] {
+				continue
+			}
+			seen[string(cert.Raw)] = true
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		return nil, nil
+	}
+	return certutil.EncodeCertificates(certs...)
+}
@@ -0,0 +1,147 @@
+package registration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	certutil "k8s.io/client-go/util/cert"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
+	"open-cluster-management.io/ocm/pkg/registration/hub/cabundle"
+)
+
+func TestSyncHubCABundle(t *testing.T) {
+	currentCA := testinghelpers.NewTestCert("current", time.Hour).Cert
+	nextCA := testinghelpers.NewTestCert("next", time.Hour).Cert
+	caBundle, err := mergeCABundles(currentCA, nextCA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testinghelpers.TestManagedClusterName, Name: cabundle.HubCABundleConfigMapName},
+			Data:       data,
+		}
+	}
+
+	cases := []struct {
+		name            string
+		configMap       *corev1.ConfigMap
+		secret          *corev1.Secret
+		expectErr       bool
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:      "no secret",
+			configMap: newConfigMap(map[string]string{cabundle.CABundleKey: string(currentCA)}),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:   "no configmap",
+			secret: testinghelpers.NewHubKubeconfigSecret(testNamespace, testSecretName, "", nil, map[string][]byte{}),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name:      "invalid ca bundle",
+			configMap: newConfigMap(map[string]string{cabundle.CABundleKey: "invalid"}),
+			secret:    testinghelpers.NewHubKubeconfigSecret(testNamespace, testSecretName, "", nil, map[string][]byte{}),
+			expectErr: true,
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+		{
+			name: "store the ca bundles",
+			configMap: newConfigMap(map[string]string{
+				cabundle.CABundleKey:     string(currentCA),
+				cabundle.NextCABundleKey: string(nextCA) + string(currentCA),
+			}),
+			secret: testinghelpers.NewHubKubeconfigSecret(testNamespace, testSecretName, "", nil, map[string][]byte{
+				clientcert.KubeconfigFile: []byte("kubeconfig"),
+			}),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "update")
+				secret := actions[0].(clienttesting.UpdateActionImpl).Object.(*corev1.Secret)
+				if string(secret.Data[clientcert.KubeconfigFile]) != "kubeconfig" {
+					t.Errorf("expected the kubeconfig is kept, but got %v", secret.Data)
+				}
+				certs, err := certutil.ParseCertsPEM(secret.Data[clientcert.HubCABundleFile])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(certs) != 2 {
+					t.Errorf("expected 2 deduplicated certificates, but got %d", len(certs))
+				}
+			},
+		},
+		{
+			name: "ca bundles are not changed",
+			configMap: newConfigMap(map[string]string{
+				cabundle.CABundleKey:     string(currentCA),
+				cabundle.NextCABundleKey: string(nextCA),
+			}),
+			secret: testinghelpers.NewHubKubeconfigSecret(testNamespace, testSecretName, "", nil, map[string][]byte{
+				clientcert.HubCABundleFile: caBundle,
+			}),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertNoActions(t, actions)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if c.secret != nil {
+				objects = append(objects, c.secret)
+			}
+			spokeKubeClient := kubefake.NewSimpleClientset(objects...)
+			spokeInformerFactory := kubeinformers.NewSharedInformerFactory(spokeKubeClient, 10*time.Minute)
+			if c.secret != nil {
+				if err := spokeInformerFactory.Core().V1().Secrets().Informer().GetStore().Add(c.secret); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			hubInformerFactory := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 10*time.Minute)
+			if c.configMap != nil {
+				if err := hubInformerFactory.Core().V1().ConfigMaps().Informer().GetStore().Add(c.configMap); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctrl := &hubCABundleController{
+				clusterName:                  testinghelpers.TestManagedClusterName,
+				hubKubeconfigSecretNamespace: testNamespace,
+				hubKubeconfigSecretName:      testSecretName,
+				hubConfigMapLister:           hubInformerFactory.Core().V1().ConfigMaps().Lister(),
+				spokeSecretLister:            spokeInformerFactory.Core().V1().Secrets().Lister(),
+				spokeCoreClient:              spokeKubeClient.CoreV1(),
+			}
+
+			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, testSecretName))
+			if c.expectErr && syncErr == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectErr && syncErr != nil {
+				t.Errorf("unexpected err: %v", syncErr)
+			}
+
+			c.validateActions(t, spokeKubeClient.Actions())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package registration
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -9,13 +10,17 @@ import (
 	"github.com/openshift/library-go/pkg/operator/events"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	clientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1alpha1informer "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1alpha1"
+	clusterv1alpha1listers "open-cluster-management.io/api/client/cluster/listers/cluster/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
 	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
 
 var (
@@ -30,13 +35,17 @@ type managedClusterCreatingController struct {
 	spokeCABundle           []byte
 	clusterAnnotations      map[string]string
 	hubClusterClient        clientset.Interface
+	claimLister             clusterv1alpha1listers.ClusterClaimLister
 }
 
 // NewManagedClusterCreatingController creates a new managedClusterCreatingController on the managed cluster.
+// The claimInformer is nil if the ClusterClaim feature is disabled, otherwise the cluster claims are reported
+// in the join cluster claims annotation of the managed cluster until the managed cluster is accepted.
 func NewManagedClusterCreatingController(
 	clusterName string, spokeExternalServerURLs []string, annotations map[string]string,
 	spokeCABundle []byte,
 	hubClusterClient clientset.Interface,
+	claimInformer clusterv1alpha1informer.ClusterClaimInformer,
 	recorder events.Recorder) factory.Controller {
 
 	c := &managedClusterCreatingController{
@@ -47,7 +56,13 @@ func NewManagedClusterCreatingController(
 		hubClusterClient:        hubClusterClient,
 	}
 
-	return factory.New().
+	f := factory.New()
+	if claimInformer != nil {
+		c.claimLister = claimInformer.Lister()
+		f = f.WithInformers(claimInformer.Informer())
+	}
+
+	return f.
 		WithSync(c.sync).
 		ResyncEvery(wait.Jitter(CreatingControllerSyncInterval, 1.0)).
 		ToController("ManagedClusterCreatingController", recorder)
@@ -65,12 +80,25 @@ func (c *managedClusterCreatingController) sync(ctx context.Context, syncCtx fac
 		return err
 	}
 
+	joinClaims, claimErr := c.joinClusterClaims()
+	if claimErr != nil {
+		return claimErr
+	}
+
 	// create ManagedCluster if not found
 	if errors.IsNotFound(err) {
+		annotations := map[string]string{}
+		for k, v := range c.clusterAnnotations {
+			annotations[k] = v
+		}
+		if len(joinClaims) > 0 {
+			annotations[helpers.JoinClusterClaimsAnnotationKey] = joinClaims
+		}
+
 		managedCluster := &clusterv1.ManagedCluster{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        c.clusterName,
-				Annotations: c.clusterAnnotations,
+				Annotations: annotations,
 			},
 		}
 
@@ -94,9 +122,16 @@ func (c *managedClusterCreatingController) sync(ctx context.Context, syncCtx fac
 		return nil
 	}
 
-	// do not update ManagedClusterClientConfigs in ManagedCluster if spokeExternalServerURLs is empty
-	if len(c.spokeExternalServerURLs) == 0 {
-		return nil
+	clusterCopy := existingCluster.DeepCopy()
+
+	// refresh the join cluster claims until the managed cluster is accepted, the reserved claims may be created
+	// after the managed cluster.
+	if !existingCluster.Spec.HubAcceptsClient && len(joinClaims) > 0 &&
+		existingCluster.Annotations[helpers.JoinClusterClaimsAnnotationKey] != joinClaims {
+		if clusterCopy.Annotations == nil {
+			clusterCopy.Annotations = map[string]string{}
+		}
+		clusterCopy.Annotations[helpers.JoinClusterClaimsAnnotationKey] = joinClaims
 	}
 
 	// merge ClientConfig
@@ -117,12 +152,12 @@ func (c *managedClusterCreatingController) sync(ctx context.Context, syncCtx fac
 			})
 		}
 	}
-	if len(existingCluster.Spec.ManagedClusterClientConfigs) == len(managedClusterClientConfigs) {
+	if len(existingCluster.Spec.ManagedClusterClientConfigs) == len(managedClusterClientConfigs) &&
+		existingCluster.Annotations[helpers.JoinClusterClaimsAnnotationKey] == clusterCopy.Annotations[helpers.JoinClusterClaimsAnnotationKey] {
 		return nil
 	}
 
-	// update ManagedClusterClientConfigs in ManagedCluster
-	clusterCopy := existingCluster.DeepCopy()
+	// update ManagedClusterClientConfigs and the join cluster claims in ManagedCluster
 	clusterCopy.Spec.ManagedClusterClientConfigs = managedClusterClientConfigs
 	_, err = c.hubClusterClient.ClusterV1().ManagedClusters().Update(ctx, clusterCopy, metav1.UpdateOptions{})
 	// ManagedClusterClientConfigs in ManagedCluster is only allowed updated during bootstrap.
@@ -134,6 +169,32 @@ func (c *managedClusterCreatingController) sync(ctx context.Context, syncCtx fac
 	return nil
 }
 
+// joinClusterClaims returns the cluster claims of the managed cluster encoded as a json object, or an empty string
+// if the ClusterClaim feature is disabled or there is no cluster claim.
+func (c *managedClusterCreatingController) joinClusterClaims() (string, error) {
+	if c.claimLister == nil {
+		return "", nil
+	}
+
+	claims, err := c.claimLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	if len(claims) == 0 {
+		return "", nil
+	}
+
+	values := make(map[string]string, len(claims))
+	for _, claim := range claims {
+		values[claim.Name] = claim.Spec.Value
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 func skipUnauthorizedError(err error) error {
 	if errors.IsUnauthorized(err) || errors.IsForbidden(err) {
 		return nil
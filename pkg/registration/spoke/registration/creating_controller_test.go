@@ -3,14 +3,19 @@ package registration
 import (
 	"context"
 	"testing"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clienttesting "k8s.io/client-go/testing"
 
 	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
 	testinghelpers "open-cluster-management.io/ocm/pkg/registration/helpers/testing"
 )
 
@@ -77,3 +82,67 @@ func TestCreateSpokeCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestJoinClusterClaims(t *testing.T) {
+	cases := []struct {
+		name            string
+		startingObjects []runtime.Object
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name: "create a new cluster with the join claims",
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "create")
+				actual := actions[1].(clienttesting.CreateActionImpl).Object.(*clusterv1.ManagedCluster)
+				if claims := actual.Annotations[helpers.JoinClusterClaimsAnnotationKey]; claims != `{"platform":"vsphere"}` {
+					t.Errorf("unexpected join claims %q", claims)
+				}
+			},
+		},
+		{
+			name:            "refresh the join claims of a cluster not accepted",
+			startingObjects: []runtime.Object{testinghelpers.NewManagedCluster()},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "update")
+				actual := actions[1].(clienttesting.UpdateActionImpl).Object.(*clusterv1.ManagedCluster)
+				if claims := actual.Annotations[helpers.JoinClusterClaimsAnnotationKey]; claims != `{"platform":"vsphere"}` {
+					t.Errorf("unexpected join claims %q", claims)
+				}
+			},
+		},
+		{
+			name:            "do not refresh the join claims of an accepted cluster",
+			startingObjects: []runtime.Object{testinghelpers.NewAcceptedManagedCluster()},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get")
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterClient := clusterfake.NewSimpleClientset(c.startingObjects...)
+			claimInformer := clusterinformers.NewSharedInformerFactory(clusterfake.NewSimpleClientset(), time.Minute*10).
+				Cluster().V1alpha1().ClusterClaims()
+			if err := claimInformer.Informer().GetStore().Add(&clusterv1alpha1.ClusterClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+				Spec:       clusterv1alpha1.ClusterClaimSpec{Value: "vsphere"},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := managedClusterCreatingController{
+				clusterName:      testinghelpers.TestManagedClusterName,
+				hubClusterClient: clusterClient,
+				claimLister:      claimInformer.Lister(),
+			}
+
+			syncErr := ctrl.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, ""))
+			if syncErr != nil {
+				t.Errorf("unexpected err: %v", syncErr)
+			}
+
+			c.validateActions(t, clusterClient.Actions())
+		})
+	}
+}
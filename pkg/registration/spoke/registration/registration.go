@@ -3,6 +3,8 @@ package registration
 import (
 	"crypto/x509/pkix"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
@@ -25,14 +27,12 @@ import (
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
 	"open-cluster-management.io/ocm/pkg/registration/hub/user"
 )
 
 const (
 	indexByCluster = "indexByCluster"
-
-	// TODO(qiujian16) expose it if necessary in the future.
-	clusterCSRThreshold = 10
 )
 
 // NewClientCertForHubController returns a controller to
@@ -41,12 +41,16 @@ const (
 func NewClientCertForHubController(
 	clusterName string,
 	agentName string,
+	agentIdentityGeneration string,
 	clientCertSecretNamespace string,
 	clientCertSecretName string,
 	kubeconfigData []byte,
 	spokeSecretInformer corev1informers.SecretInformer,
 	csrControl clientcert.CSRControl,
 	csrExpirationSeconds int32,
+	ipAddresses []net.IP,
+	uris []*url.URL,
+	spiffeTrustDomain string,
 	spokeKubeClient kubernetes.Interface,
 	statusUpdater clientcert.StatusUpdateFunc,
 	recorder events.Recorder,
@@ -58,15 +62,8 @@ func NewClientCertForHubController(
 	if err != nil {
 		utilruntime.HandleError(err)
 	}
-	clientCertOption := clientcert.ClientCertOption{
-		SecretNamespace: clientCertSecretNamespace,
-		SecretName:      clientCertSecretName,
-		AdditionalSecretData: map[string][]byte{
-			clientcert.ClusterNameFile: []byte(clusterName),
-			clientcert.AgentNameFile:   []byte(agentName),
-			clientcert.KubeconfigFile:  kubeconfigData,
-		},
-	}
+	clientCertOption := newClientCertOption(clusterName, agentName, agentIdentityGeneration,
+		clientCertSecretNamespace, clientCertSecretName, kubeconfigData)
 
 	var csrExpirationSecondsInCSROption *int32
 	if csrExpirationSeconds != 0 {
@@ -110,7 +107,18 @@ func NewClientCertForHubController(
 		},
 		HaltCSRCreation:   haltCSRCreationFunc(csrControl.Informer().GetIndexer(), clusterName),
 		ExpirationSeconds: csrExpirationSecondsInCSROption,
+		IPAddresses:       ipAddresses,
+	}
+	if len(spiffeTrustDomain) > 0 {
+		// the trust domain is validated, so the SPIFFE ID is always valid
+		spiffeID, err := url.Parse(user.ClusterSPIFFEID(spiffeTrustDomain, clusterName))
+		if err != nil {
+			utilruntime.HandleError(err)
+		} else {
+			csrOption.URIs = []*url.URL{spiffeID}
+		}
 	}
+	csrOption.URIs = append(csrOption.URIs, uris...)
 
 	return clientcert.NewClientCertificateController(
 		clientCertOption,
@@ -124,6 +132,23 @@ func NewClientCertForHubController(
 	)
 }
 
+func newClientCertOption(clusterName, agentName, agentIdentityGeneration, secretNamespace, secretName string,
+	kubeconfigData []byte) clientcert.ClientCertOption {
+	clientCertOption := clientcert.ClientCertOption{
+		SecretNamespace: secretNamespace,
+		SecretName:      secretName,
+		AdditionalSecretData: map[string][]byte{
+			clientcert.ClusterNameFile: []byte(clusterName),
+			clientcert.AgentNameFile:   []byte(agentName),
+			clientcert.KubeconfigFile:  kubeconfigData,
+		},
+	}
+	if len(agentIdentityGeneration) > 0 {
+		clientCertOption.AdditionalSecretData[clientcert.AgentIdentityGenerationFile] = []byte(agentIdentityGeneration)
+	}
+	return clientCertOption
+}
+
 func haltCSRCreationFunc(indexer cache.Indexer, clusterName string) func() bool {
 	return func() bool {
 		items, err := indexer.ByIndex(indexByCluster, clusterName)
@@ -131,7 +156,7 @@ func haltCSRCreationFunc(indexer cache.Indexer, clusterName string) func() bool
 			return false
 		}
 
-		if len(items) >= clusterCSRThreshold {
+		if len(items) >= helpers.ClusterCSRThreshold {
 			return true
 		}
 		return false
@@ -17,6 +17,7 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
@@ -25,6 +26,7 @@ import (
 	clusterv1client "open-cluster-management.io/api/client/cluster/clientset/versioned"
 	clusterscheme "open-cluster-management.io/api/client/cluster/clientset/versioned/scheme"
 	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1alpha1informers "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
 
@@ -32,9 +34,12 @@ import (
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/registration/clientcert"
+	"open-cluster-management.io/ocm/pkg/registration/hub/cabundle"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/addon"
+	"open-cluster-management.io/ocm/pkg/registration/spoke/addon/hubproxy"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/lease"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/managedcluster"
+	"open-cluster-management.io/ocm/pkg/registration/spoke/metrics"
 	"open-cluster-management.io/ocm/pkg/registration/spoke/registration"
 )
 
@@ -53,6 +58,7 @@ type SpokeAgentConfig struct {
 
 // NewSpokeAgentConfig returns a SpokeAgentConfig
 func NewSpokeAgentConfig(commonOpts *commonoptions.AgentOptions, opts *SpokeAgentOptions) *SpokeAgentConfig {
+	opts.proxyCABundleFile = commonOpts.ProxyCABundleFile
 	return &SpokeAgentConfig{
 		agentOptions:       commonOpts,
 		registrationOption: opts,
@@ -96,6 +102,8 @@ func NewSpokeAgentConfig(commonOpts *commonoptions.AgentOptions, opts *SpokeAgen
 //     checking the health of the agent);
 func (o *SpokeAgentConfig) RunSpokeAgent(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
 	kubeConfig := controllerContext.KubeConfig
+	// suppress the repeated events, e.g. the failures to rotate the client certificate during a hub outage
+	recorder := helpers.NewRateLimitedRecorder(controllerContext.EventRecorder, helpers.EventRateLimitInterval)
 
 	// load spoke client config and create spoke clients,
 	// the registration agent may not running in the spoke/managed cluster.
@@ -103,6 +111,9 @@ func (o *SpokeAgentConfig) RunSpokeAgent(ctx context.Context, controllerContext
 	if err != nil {
 		return err
 	}
+	if o.agentOptions.ReportDeniedActions {
+		helpers.ReportDeniedActions(spokeClientConfig, recorder)
+	}
 
 	spokeKubeClient, err := kubernetes.NewForConfig(spokeClientConfig)
 	if err != nil {
@@ -121,7 +132,7 @@ func (o *SpokeAgentConfig) RunSpokeAgent(ctx context.Context, controllerContext
 		spokeKubeClient,
 		informers.NewSharedInformerFactory(spokeKubeClient, 10*time.Minute),
 		clusterv1informers.NewSharedInformerFactory(spokeClusterClient, 10*time.Minute),
-		controllerContext.EventRecorder,
+		recorder,
 	)
 }
 
@@ -148,11 +159,22 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		return err
 	}
 
+	// remove the identity of the agent before loading it if a new generation is requested
+	if err := o.rotateAgentIdentity(ctx, managementKubeClient.CoreV1(), recorder); err != nil {
+		return err
+	}
+
 	if err := o.registrationOption.Validate(); err != nil {
 		logger.Error(err, "Error during Validating")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
+	// the SANs are validated above
+	ipSANs, uriSANs, err := clientcert.ParseSANs(o.registrationOption.ClientCertIPSANs, o.registrationOption.ClientCertURISANs)
+	if err != nil {
+		return err
+	}
+
 	if err := o.agentOptions.Complete(); err != nil {
 		logger.Error(err, "Error during Complete")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
@@ -178,8 +200,8 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 	//
 	// hubValiedConfig is a function to check if the hub client config is valid.
 	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.MultipleHubs) {
-		index, err := selectBootstrapKubeConfigs(ctx, o.agentOptions.SpokeClusterName, o.agentOptions.
-			HubKubeconfigFile, o.registrationOption.BootstrapKubeconfigs)
+		index, err := o.registrationOption.selectBootstrapKubeConfigs(ctx, o.agentOptions.SpokeClusterName,
+			o.agentOptions.HubKubeconfigFile)
 		if err != nil {
 			return fmt.Errorf("failed to select a bootstrap kubeconfig: %w", err)
 		}
@@ -191,7 +213,7 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 	}
 
 	// load bootstrap client config and create bootstrap clients
-	bootstrapClientConfig, err := clientcmd.BuildConfigFromFlags("", o.currentBootstrapKubeConfig)
+	bootstrapClientConfig, err := o.registrationOption.loadBootstrapClientConfig(o.currentBootstrapKubeConfig)
 	if err != nil {
 		return fmt.Errorf("unable to load bootstrap kubeconfig from file %q: %w", o.currentBootstrapKubeConfig, err)
 	}
@@ -205,12 +227,20 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 	}
 
 	// start a SpokeClusterCreatingController to make sure there is a spoke cluster on hub cluster
+	var joinClaimInformer clusterv1alpha1informers.ClusterClaimInformer
+	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.ClusterClaim) {
+		joinClaimInformer = spokeClusterInformerFactory.Cluster().V1alpha1().ClusterClaims()
+	}
 	spokeClusterCreatingController := registration.NewManagedClusterCreatingController(
 		o.agentOptions.SpokeClusterName, o.registrationOption.SpokeExternalServerURLs, o.registrationOption.ClusterAnnotations,
 		spokeClusterCABundle,
 		bootstrapClusterClient,
+		joinClaimInformer,
 		recorder,
 	)
+	if joinClaimInformer != nil {
+		go spokeClusterInformerFactory.Start(ctx.Done())
+	}
 	go spokeClusterCreatingController.Run(ctx, 1)
 
 	secretInformer := namespacedManagementKubeInformerFactory.Core().V1().Secrets()
@@ -250,7 +280,8 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			managementKubeClient, 10*time.Minute, informers.WithNamespace(o.agentOptions.ComponentNamespace))
 
 		// create a kubeconfig with references to the key/cert files in the same secret
-		contextClusterName, server, proxyURL, caData, err := parseKubeconfig(o.currentBootstrapKubeConfig)
+		contextClusterName, server, proxyURL, caData, err := o.registrationOption.parseBootstrapKubeconfig(
+			o.currentBootstrapKubeConfig)
 		if err != nil {
 			return err
 		}
@@ -261,19 +292,22 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			return err
 		}
 
+		controllerName := fmt.Sprintf("BootstrapClientCertController@cluster:%s", o.agentOptions.SpokeClusterName)
 		csrControl, err := clientcert.NewCSRControl(logger, bootstrapInformerFactory.Certificates(), bootstrapKubeClient)
 		if err != nil {
 			return err
 		}
 
-		controllerName := fmt.Sprintf("BootstrapClientCertController@cluster:%s", o.agentOptions.SpokeClusterName)
 		clientCertForHubController := registration.NewClientCertForHubController(
-			o.agentOptions.SpokeClusterName, o.agentOptions.AgentID, o.agentOptions.ComponentNamespace, o.registrationOption.HubKubeconfigSecret,
+			o.agentOptions.SpokeClusterName, o.agentOptions.AgentID, o.registrationOption.AgentIdentityGeneration,
+			o.agentOptions.ComponentNamespace, o.registrationOption.HubKubeconfigSecret,
 			kubeconfigData,
 			// store the secret in the cluster where the agent pod runs
 			bootstrapNamespacedManagementKubeInformerFactory.Core().V1().Secrets(),
 			csrControl,
 			o.registrationOption.ClientCertExpirationSeconds,
+			ipSANs, uriSANs,
+			o.registrationOption.SPIFFETrustDomain,
 			managementKubeClient,
 			registration.GenerateBootstrapStatusUpdater(),
 			recorder,
@@ -304,23 +338,33 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		stopBootstrap()
 	}
 
+	// the CA bundles of the hub distributed by the hub are loaded into the hub client config
+	hubCABundleFile := path.Join(o.agentOptions.HubKubeconfigDir, clientcert.HubCABundleFile)
+	loadedHubCABundle, _ := os.ReadFile(path.Clean(hubCABundleFile))
+
 	// create hub clients and shared informer factories from hub kube config
-	hubClientConfig, err := clientcmd.BuildConfigFromFlags("", o.agentOptions.HubKubeconfigFile)
+	hubClientConfig, err := o.agentOptions.HubKubeConfig(o.agentOptions.HubKubeconfigFile)
 	if err != nil {
 		return fmt.Errorf("unable to load hub kubeconfig from file %q: %w", o.agentOptions.HubKubeconfigFile, err)
 	}
+	metrics.WrapHubTransport(hubClientConfig)
+	// the http client is shared with the work agent in the Singleton mode.
+	hubHTTPClient, err := o.agentOptions.HubHTTPClient(o.agentOptions.HubKubeconfigFile, hubClientConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create hub http client: %w", err)
+	}
 
-	hubKubeClient, err := kubernetes.NewForConfig(hubClientConfig)
+	hubKubeClient, err := kubernetes.NewForConfigAndClient(hubClientConfig, hubHTTPClient)
 	if err != nil {
 		return fmt.Errorf("failed to create hub kube client: %w", err)
 	}
 
-	hubClusterClient, err := clusterv1client.NewForConfig(hubClientConfig)
+	hubClusterClient, err := clusterv1client.NewForConfigAndClient(hubClientConfig, hubHTTPClient)
 	if err != nil {
 		return fmt.Errorf("failed to create hub cluster client: %w", err)
 	}
 
-	addOnClient, err := addonclient.NewForConfig(hubClientConfig)
+	addOnClient, err := addonclient.NewForConfigAndClient(hubClientConfig, hubHTTPClient)
 	if err != nil {
 		return fmt.Errorf("failed to create addon client: %w", err)
 	}
@@ -365,31 +409,38 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		return fmt.Errorf("failed to create CSR control: %w", err)
 	}
 
+	statusUpdater := helpers.NewDeduplicatedConditionUpdater(registration.GenerateStatusUpdater(
+		hubClusterClient,
+		hubClusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
+		o.agentOptions.SpokeClusterName), helpers.ConditionDedupInterval)
+	// the client certificate is still rotated when the agent is paused, but the condition is not updated
+	if o.agentOptions.Paused {
+		statusUpdater = registration.GenerateBootstrapStatusUpdater()
+	}
+
 	// create another ClientCertForHubController for client certificate rotation
 	controllerName := fmt.Sprintf("ClientCertController@cluster:%s", o.agentOptions.SpokeClusterName)
 	clientCertForHubController := registration.NewClientCertForHubController(
-		o.agentOptions.SpokeClusterName, o.agentOptions.AgentID, o.agentOptions.ComponentNamespace, o.registrationOption.HubKubeconfigSecret,
+		o.agentOptions.SpokeClusterName, o.agentOptions.AgentID, o.registrationOption.AgentIdentityGeneration,
+		o.agentOptions.ComponentNamespace, o.registrationOption.HubKubeconfigSecret,
 		kubeconfigData,
 		namespacedManagementKubeInformerFactory.Core().V1().Secrets(),
 		csrControl,
 		o.registrationOption.ClientCertExpirationSeconds,
+		ipSANs, uriSANs,
+		o.registrationOption.SPIFFETrustDomain,
 		managementKubeClient,
-		registration.GenerateStatusUpdater(
-			hubClusterClient,
-			hubClusterInformerFactory.Cluster().V1().ManagedClusters().Lister(),
-			o.agentOptions.SpokeClusterName),
+		statusUpdater,
 		recorder,
 		controllerName,
 	)
-	if err != nil {
-		return fmt.Errorf("failed to create client cert for hub controller: %w", err)
-	}
 
 	// create ManagedClusterLeaseController to keep the spoke cluster heartbeat
 	managedClusterLeaseController := lease.NewManagedClusterLeaseController(
 		o.agentOptions.SpokeClusterName,
 		hubKubeClient,
 		hubClusterInformerFactory.Cluster().V1().ManagedClusters(),
+		o.registrationOption.controllerHealths[managedClusterLeaseHealthName],
 		recorder,
 	)
 
@@ -407,13 +458,36 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		spokeKubeInformerFactory.Core().V1().Nodes(),
 		o.registrationOption.MaxCustomClusterClaims,
 		o.registrationOption.ClusterHealthCheckPeriod,
+		o.registrationOption.HubConnectivityReportInterval,
+		o.registrationOption.ResourceUsageReportInterval,
+		o.agentOptions.HubCircuitBreaker(),
+		spokeKubeClient,
+		o.agentOptions.ComponentNamespace,
+		namespacedManagementKubeInformerFactory.Core().V1().ConfigMaps(),
+		o.registrationOption.controllerHealths[managedClusterStatusHealthName],
 		recorder,
 		hubEventRecorder,
 	)
 
 	var addOnLeaseController factory.Controller
 	var addOnRegistrationController factory.Controller
+	var addOnHubProxy *hubproxy.Server
 	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.AddonManagement) {
+		if len(o.registrationOption.AddOnHubProxyURL) > 0 {
+			addOnHubProxy, err = hubproxy.NewServer(ctx, hubproxy.Options{
+				URL:            o.registrationOption.AddOnHubProxyURL,
+				BindAddress:    o.registrationOption.AddOnHubProxyBindAddress,
+				Namespace:      o.agentOptions.ComponentNamespace,
+				HubClusterName: contextClusterName,
+				HubServer:      server,
+				HubCAData:      caData,
+				HubProxyURL:    proxyURL,
+			}, managementKubeClient.CoreV1(), namespacedManagementKubeInformerFactory.Core().V1().Secrets())
+			if err != nil {
+				return fmt.Errorf("failed to create the addon hub proxy: %w", err)
+			}
+		}
+
 		addOnLeaseController = addon.NewManagedClusterAddOnLeaseController(
 			o.agentOptions.SpokeClusterName,
 			addOnClient,
@@ -422,6 +496,7 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			managementKubeClient.CoordinationV1(),
 			spokeKubeClient.CoordinationV1(),
 			AddOnLeaseControllerSyncInterval, //TODO: this interval time should be allowed to change from outside
+			o.registrationOption.controllerHealths[addOnLeaseHealthName],
 			recorder,
 		)
 
@@ -429,11 +504,36 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 			o.agentOptions.SpokeClusterName,
 			o.agentOptions.AgentID,
 			kubeconfigData,
+			o.registrationOption.AddOnKubeconfigExecCredential,
 			addOnClient,
 			managementKubeClient,
 			spokeKubeClient,
 			csrControl,
 			addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
+			addOnHubProxy,
+			o.registrationOption.controllerHealths[addOnRegistrationHealthName],
+			recorder,
+		)
+	}
+
+	var hubCABundleController factory.Controller
+	var hubCABundleInformerFactory informers.SharedInformerFactory
+	if o.registrationOption.HubCABundleDistribution {
+		// only watch the hub-ca-bundle configmap in the cluster namespace
+		hubCABundleInformerFactory = informers.NewSharedInformerFactoryWithOptions(
+			hubKubeClient,
+			10*time.Minute,
+			informers.WithNamespace(o.agentOptions.SpokeClusterName),
+			informers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+				listOptions.FieldSelector = fields.OneTermEqualSelector("metadata.name", cabundle.HubCABundleConfigMapName).String()
+			}),
+		)
+		hubCABundleController = registration.NewHubCABundleController(
+			o.agentOptions.SpokeClusterName,
+			o.agentOptions.ComponentNamespace, o.registrationOption.HubKubeconfigSecret,
+			managementKubeClient.CoreV1(),
+			hubCABundleInformerFactory.Core().V1().ConfigMaps(),
+			namespacedManagementKubeInformerFactory.Core().V1().Secrets(),
 			recorder,
 		)
 	}
@@ -464,6 +564,17 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		)
 	}
 
+	// export the cache sizes of the informers the controllers depend on
+	namedInformers := map[string]cache.SharedIndexInformer{
+		"hub-managedclusters":    hubClusterInformerFactory.Cluster().V1().ManagedClusters().Informer(),
+		"hub-kubeconfig-secrets": namespacedManagementKubeInformerFactory.Core().V1().Secrets().Informer(),
+		"nodes":                  spokeKubeInformerFactory.Core().V1().Nodes().Informer(),
+	}
+	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.AddonManagement) {
+		namedInformers["hub-managedclusteraddons"] = addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer()
+	}
+	metrics.RegisterInformers(namedInformers)
+
 	go hubKubeInformerFactory.Start(ctx.Done())
 	go hubClusterInformerFactory.Start(ctx.Done())
 	go namespacedManagementKubeInformerFactory.Start(ctx.Done())
@@ -474,12 +585,36 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		go spokeClusterInformerFactory.Start(ctx.Done())
 	}
 
+	hubClusterSynced := hubClusterInformerFactory.Cluster().V1().ManagedClusters().Informer().HasSynced
+	o.registrationOption.controllerHealths[managedClusterLeaseHealthName].Start(hubClusterSynced)
+	go o.registrationOption.hubConnectivityHealth.Run(ctx, func(ctx context.Context) error {
+		_, err := hubClusterClient.ClusterV1().ManagedClusters().Get(ctx, o.agentOptions.SpokeClusterName, metav1.GetOptions{})
+		return err
+	})
+
 	go clientCertForHubController.Run(ctx, 1)
 	go managedClusterLeaseController.Run(ctx, 1)
-	go managedClusterHealthCheckController.Run(ctx, 1)
+	// the status of the managed cluster is not updated when the agent is paused, the lease is still renewed
+	if o.agentOptions.Paused {
+		logger.Info("The agent is paused, the status of the managed cluster is not updated")
+	} else {
+		o.registrationOption.controllerHealths[managedClusterStatusHealthName].Start(hubClusterSynced,
+			spokeKubeInformerFactory.Core().V1().Nodes().Informer().HasSynced)
+		go managedClusterHealthCheckController.Run(ctx, 1)
+	}
 	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.AddonManagement) {
+		addOnSynced := addOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns().Informer().HasSynced
+		o.registrationOption.controllerHealths[addOnLeaseHealthName].Start(addOnSynced)
+		o.registrationOption.controllerHealths[addOnRegistrationHealthName].Start(addOnSynced)
 		go addOnLeaseController.Run(ctx, 1)
 		go addOnRegistrationController.Run(ctx, 1)
+		if addOnHubProxy != nil {
+			go func() {
+				if err := addOnHubProxy.Run(ctx); err != nil {
+					logger.Error(err, "Failed to serve the addon hub proxy")
+				}
+			}()
+		}
 	}
 
 	// start health checking of hub client certificate
@@ -488,6 +623,14 @@ func (o *SpokeAgentConfig) RunSpokeAgentWithSpokeInformers(ctx context.Context,
 		go o.registrationOption.clientCertHealthChecker.start(ctx, tlsCertFile)
 	}
 
+	if o.registrationOption.HubCABundleDistribution {
+		go hubCABundleInformerFactory.Start(ctx.Done())
+		go hubCABundleController.Run(ctx, 1)
+		if o.registrationOption.hubCABundleHealth != nil {
+			go o.registrationOption.hubCABundleHealth.Run(ctx, hubCABundleFile, loadedHubCABundle)
+		}
+	}
+
 	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.MultipleHubs) {
 		go hubAcceptController.Run(ctx, 1)
 		go hubTimeoutController.Run(ctx, 1)
@@ -547,7 +690,7 @@ func (o *SpokeAgentConfig) HasValidHubClientConfig(ctx context.Context) (bool, e
 		return false, err
 	}
 
-	return isHubKubeconfigValid(o.currentBootstrapKubeConfig, o.agentOptions.HubKubeconfigFile)
+	return o.registrationOption.isHubKubeconfigValid(o.currentBootstrapKubeConfig, o.agentOptions.HubKubeconfigFile)
 }
 
 // The hub kubeconfig is valid when it shares the same value of the following with the
@@ -556,9 +699,11 @@ func (o *SpokeAgentConfig) HasValidHubClientConfig(ctx context.Context) (bool, e
 // 2. The proxy url
 // 3. The CA bundle
 // 4. The current context cluster name
-func isHubKubeconfigValid(
+//
+// The CA bundle file and the proxy url in the options are applied to the bootstrap hub kubeconfig before comparing.
+func (o *SpokeAgentOptions) isHubKubeconfigValid(
 	bootstrapKubeConfigFilePath, hubeKubeConfigFilePath string) (bool, error) {
-	bootstrapCtxCluster, bootstrapServer, bootstrapProxyURL, bootstrapCABndle, err := parseKubeconfig(
+	bootstrapCtxCluster, bootstrapServer, bootstrapProxyURL, bootstrapCABndle, err := o.parseBootstrapKubeconfig(
 		bootstrapKubeConfigFilePath)
 	if err != nil {
 		return false, err
@@ -58,6 +58,22 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: "cluster healthcheck period must greater than zero",
 		},
+		{
+			name: "invalid bootstrap proxy url",
+			options: &SpokeAgentOptions{
+				BootstrapKubeconfig: "/spoke/bootstrap/kubeconfig",
+				BootstrapProxyURL:   "127.0.0.1:3128",
+			},
+			expectedErr: "invalid bootstrap proxy url \"127.0.0.1:3128\"",
+		},
+		{
+			name: "bootstrap ca bundle file not found",
+			options: &SpokeAgentOptions{
+				BootstrapKubeconfig:   "/spoke/bootstrap/kubeconfig",
+				BootstrapCABundleFile: "/spoke/bootstrap/not-found.crt",
+			},
+			expectedErr: "invalid bootstrap ca bundle file: open /spoke/bootstrap/not-found.crt: no such file or directory",
+		},
 		{
 			name:        "default completed options",
 			options:     defaultCompletedOptions,
@@ -85,6 +101,59 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "invalid client cert uri sans",
+			options: &SpokeAgentOptions{
+				HubKubeconfigSecret:         "hub-kubeconfig-secret",
+				ClusterHealthCheckPeriod:    1 * time.Minute,
+				MaxCustomClusterClaims:      20,
+				BootstrapKubeconfig:         "/spoke/bootstrap/kubeconfig",
+				ClientCertExpirationSeconds: 3600,
+				ClientCertIPSANs:            []string{"10.0.0.1"},
+				ClientCertURISANs:           []string{"spiffe://cluster.local"},
+			},
+			expectedErr: "invalid SPIFFE ID \"spiffe://cluster.local\": it must be spiffe://<trust domain>/<path>",
+		},
+		{
+			name: "invalid spiffe trust domain",
+			options: &SpokeAgentOptions{
+				HubKubeconfigSecret:         "hub-kubeconfig-secret",
+				ClusterHealthCheckPeriod:    1 * time.Minute,
+				MaxCustomClusterClaims:      20,
+				BootstrapKubeconfig:         "/spoke/bootstrap/kubeconfig",
+				ClientCertExpirationSeconds: 3600,
+				SPIFFETrustDomain:           "Example.org",
+			},
+			expectedErr: "invalid spiffe trust domain \"Example.org\": " +
+				"a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must " +
+				"start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is " +
+				"'[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+		},
+		{
+			name: "addon hub proxy url without bind address",
+			options: &SpokeAgentOptions{
+				HubKubeconfigSecret:         "hub-kubeconfig-secret",
+				ClusterHealthCheckPeriod:    1 * time.Minute,
+				MaxCustomClusterClaims:      20,
+				BootstrapKubeconfig:         "/spoke/bootstrap/kubeconfig",
+				ClientCertExpirationSeconds: 3600,
+				AddOnHubProxyURL:            "https://klusterlet-addon-hub-proxy.open-cluster-management-agent.svc:9443",
+			},
+			expectedErr: "addon hub proxy bind address and url must be set together",
+		},
+		{
+			name: "invalid addon hub proxy url",
+			options: &SpokeAgentOptions{
+				HubKubeconfigSecret:         "hub-kubeconfig-secret",
+				ClusterHealthCheckPeriod:    1 * time.Minute,
+				MaxCustomClusterClaims:      20,
+				BootstrapKubeconfig:         "/spoke/bootstrap/kubeconfig",
+				ClientCertExpirationSeconds: 3600,
+				AddOnHubProxyBindAddress:    ":9443",
+				AddOnHubProxyURL:            "http://klusterlet-addon-hub-proxy.open-cluster-management-agent.svc:9443",
+			},
+			expectedErr: "invalid addon hub proxy url \"http://klusterlet-addon-hub-proxy.open-cluster-management-agent.svc:9443\"",
+		},
 		{
 			name: "MultipleHubs enabled, but bootstrapkubeconfigs is empty",
 			options: &SpokeAgentOptions{
@@ -403,3 +472,65 @@ func TestParseKubeconfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBootstrapKubeconfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "testparsebootstrapkubeconfig")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cert := testinghelpers.NewTestCert("bootstrap", 60*time.Second)
+	kubeconfigFile := path.Join(tempDir, "kubeconfig")
+	testinghelpers.WriteFile(kubeconfigFile, testinghelpers.NewKubeconfig(
+		"test-cluster", "https://127.0.0.1:6443", "", []byte("hub-ca"), cert.Key, cert.Cert))
+	caBundleFile := path.Join(tempDir, "ca-bundle.crt")
+	testinghelpers.WriteFile(caBundleFile, []byte("proxy-ca"))
+
+	cases := []struct {
+		name             string
+		options          *SpokeAgentOptions
+		expectedCAData   []byte
+		expectedProxyURL string
+	}{
+		{
+			name:           "no overrides",
+			options:        &SpokeAgentOptions{},
+			expectedCAData: []byte("hub-ca"),
+		},
+		{
+			name: "with ca bundle and proxy url",
+			options: &SpokeAgentOptions{
+				BootstrapCABundleFile: caBundleFile,
+				BootstrapProxyURL:     "https://127.0.0.1:3129",
+			},
+			expectedCAData:   []byte("hub-ca\nproxy-ca"),
+			expectedProxyURL: "https://127.0.0.1:3129",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, proxyURL, caData, err := c.options.parseBootstrapKubeconfig(kubeconfigFile)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if c.expectedProxyURL != proxyURL {
+				t.Errorf("expect proxy url %s, but %s", c.expectedProxyURL, proxyURL)
+			}
+			if !reflect.DeepEqual(c.expectedCAData, caData) {
+				t.Errorf("expect ca data %q, but %q", c.expectedCAData, caData)
+			}
+
+			config, err := c.options.loadBootstrapClientConfig(kubeconfigFile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(c.expectedCAData, config.CAData) {
+				t.Errorf("expect ca data %q of the client config, but %q", c.expectedCAData, config.CAData)
+			}
+			if (config.Proxy != nil) != (len(c.expectedProxyURL) > 0) {
+				t.Errorf("expect the proxy of the client config is set: %v", len(c.expectedProxyURL) > 0)
+			}
+		})
+	}
+}
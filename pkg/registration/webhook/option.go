@@ -1,17 +1,37 @@
 package webhook
 
-import "github.com/spf13/pflag"
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+const defaultComponentNamespace = "open-cluster-management-hub"
 
 // Config contains the server (the webhook) cert and key.
 type Options struct {
 	Port    int
 	CertDir string
+	// PolicyExemptGroups are the groups whose requests are not validated by the cluster admission policies.
+	PolicyExemptGroups []string
+	// FIPSMode restricts the TLS configs of the webhook server to the FIPS approved algorithms.
+	FIPSMode bool
 }
 
 // NewOptions constructs a new set of default options for webhook.
 func NewOptions() *Options {
+	componentNamespace := defaultComponentNamespace
+	if nsBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
+		componentNamespace = string(nsBytes)
+	}
 	return &Options{
 		Port: 9443,
+		// the kube system controllers and the hub controllers
+		PolicyExemptGroups: []string{
+			"system:serviceaccounts:kube-system",
+			fmt.Sprintf("system:serviceaccounts:%s", componentNamespace),
+		},
 	}
 }
 
@@ -21,4 +41,9 @@ func (c *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.CertDir, "certdir", c.CertDir,
 		"CertDir is the directory that contains the server key and certificate. If not set, "+
 			"webhook server would look up the server key and certificate in {TempDir}/k8s-webhook-server/serving-certs")
+	fs.StringSliceVar(&c.PolicyExemptGroups, "policy-exempt-groups", c.PolicyExemptGroups,
+		"The groups whose requests are not validated by the cluster admission policies.")
+	fs.BoolVar(&c.FIPSMode, "fips-mode", c.FIPSMode,
+		"Restrict the TLS connections to the FIPS approved algorithms. The mode is always on if the binary is built "+
+			"with boringcrypto.")
 }
@@ -0,0 +1,462 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	kevents "k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const clusterGroup = "cluster.open-cluster-management.io"
+
+// auditResources are the resources validated by the policies, the failed validations of the policies with the Audit
+// action are recorded in the annotations of the resources.
+var auditResources = map[string]schema.GroupVersionResource{
+	"managedclusters":           {Group: clusterGroup, Version: "v1", Resource: "managedclusters"},
+	"managedclustersets":        {Group: clusterGroup, Version: "v1beta2", Resource: "managedclustersets"},
+	"managedclustersetbindings": {Group: clusterGroup, Version: "v1beta2", Resource: "managedclustersetbindings"},
+}
+
+// maxAuditRetries is the max number of retries to record the failed validations of a resource, the resource of a
+// create request may not be persisted yet, or may be rejected by the other validations.
+const maxAuditRetries = 5
+
+// Evaluator validates the cluster resources with the ClusterAdmissionPolicies on the hub. The expressions of a
+// policy are compiled once per resource version of the policy. The requests of the members of the exempt groups,
+// e.g. the hub controllers, are not validated, and no request is validated until the policy CRD is installed.
+type Evaluator struct {
+	client       dynamic.Interface
+	informer     cache.SharedIndexInformer
+	discovery    discovery.DiscoveryInterface
+	exemptGroups sets.Set[string]
+	env          *cel.Env
+	recorder     kevents.EventRecorder
+
+	// started is set once the policy CRD is installed and the informer is started.
+	started atomic.Bool
+
+	lock     sync.Mutex
+	compiled map[types.UID]*compiledPolicy
+
+	// the failed validations of the policies with the Audit action are recorded once the requested resources are
+	// persisted, so the events refer to the resources with the uids.
+	auditQueue   workqueue.RateLimitingInterface
+	auditLock    sync.Mutex
+	auditResults map[auditKey]string
+}
+
+type auditKey struct {
+	resource  string
+	namespace string
+	name      string
+}
+
+type compiledPolicy struct {
+	resourceVersion string
+	validations     []compiledValidation
+}
+
+type compiledValidation struct {
+	validation Validation
+	program    cel.Program
+	err        error
+}
+
+// NewEvaluator returns an Evaluator watching the ClusterAdmissionPolicies with the dynamic client.
+func NewEvaluator(client dynamic.Interface, discoveryClient discovery.DiscoveryInterface, exemptGroups []string) (*Evaluator, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Evaluator{
+		client: client,
+		informer: dynamicinformer.NewFilteredDynamicInformer(
+			client, ClusterAdmissionPolicyResource, "", 10*time.Minute, cache.Indexers{}, nil).Informer(),
+		discovery:    discoveryClient,
+		exemptGroups: sets.New(exemptGroups...),
+		env:          env,
+		compiled:     map[types.UID]*compiledPolicy{},
+		auditQueue:   workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		auditResults: map[auditKey]string{},
+	}
+	_, err = e.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if policy, ok := obj.(*unstructured.Unstructured); ok {
+				e.lock.Lock()
+				defer e.lock.Unlock()
+				delete(e.compiled, policy.GetUID())
+			}
+		},
+	})
+	return e, err
+}
+
+func newEnv() (*cel.Env, error) {
+	env, err := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), true).
+		Env(environment.StoredExpressions)
+	if err != nil {
+		return nil, err
+	}
+	return env.Extend(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+	)
+}
+
+// SetEventRecorder sets the recorder of the events of the failed validations with the Audit action.
+func (e *Evaluator) SetEventRecorder(recorder kevents.EventRecorder) {
+	e.recorder = recorder
+}
+
+// Start runs the informer of the policies until the context is done. The informer is started once the policy CRD
+// is installed, which is checked every minute.
+func (e *Evaluator) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		e.auditQueue.ShutDown()
+	}()
+	go wait.UntilWithContext(ctx, e.runAuditWorker, time.Second)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if e.started.Load() {
+			return
+		}
+		installed, err := e.crdInstalled()
+		if err != nil {
+			klog.Warningf("failed to discover the cluster admission policies: %v", err)
+			return
+		}
+		if !installed {
+			klog.V(4).Infof("the cluster admission policy CRD is not installed, the policies are not evaluated")
+			return
+		}
+		e.started.Store(true)
+		go e.informer.Run(ctx.Done())
+	}, time.Minute)
+	return nil
+}
+
+func (e *Evaluator) crdInstalled() (bool, error) {
+	resources, err := e.discovery.ServerResourcesForGroupVersion(ClusterAdmissionPolicyResource.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == ClusterAdmissionPolicyResource.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ReadyCheck fails until the policies are synced once the policy CRD is installed.
+func (e *Evaluator) ReadyCheck(_ *http.Request) error {
+	if e.started.Load() && !e.informer.HasSynced() {
+		return fmt.Errorf("the cluster admission policies are not synced")
+	}
+	return nil
+}
+
+// Validate evaluates the policies matching the resource and the operation, it returns a forbidden error
+// containing the messages of all the failed validations of the policies with the Deny action, and the warnings of
+// the policies with the Warn action. oldObj is nil for a create request.
+func (e *Evaluator) Validate(resource string, operation admissionv1.Operation, userInfo authenticationv1.UserInfo,
+	name string, obj, oldObj runtime.Object) (admission.Warnings, error) {
+	if e == nil || !e.started.Load() || e.exemptGroups.HasAny(userInfo.Groups...) {
+		return nil, nil
+	}
+
+	groupResource := schema.GroupResource{Group: clusterGroup, Resource: resource}
+	if !e.informer.HasSynced() {
+		return nil, apierrors.NewInternalError(fmt.Errorf("the cluster admission policies are not synced"))
+	}
+
+	policies := e.listPolicies(resource, operation)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	activation := map[string]interface{}{"object": nil, "oldObject": nil}
+	for key, o := range map[string]runtime.Object{"object": obj, "oldObject": oldObj} {
+		if o == nil {
+			continue
+		}
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return nil, apierrors.NewBadRequest(err.Error())
+		}
+		activation[key] = data
+	}
+
+	var violations, auditViolations []string
+	var warnings admission.Warnings
+	audited := false
+	for _, policy := range policies {
+		actions := policy.Spec.ValidationActions
+		if len(actions) == 0 {
+			actions = []ValidationAction{Deny}
+		}
+		audited = audited || containsAction(actions, Audit)
+		for _, v := range e.compile(policy).validations {
+			passed, err := evaluate(v, activation)
+			if err != nil && policy.Spec.FailurePolicy == Ignore {
+				klog.Warningf("ignore the failed expression %q of the cluster admission policy %q: %v",
+					v.validation.Expression, policy.Name, err)
+				continue
+			}
+			if err == nil && passed {
+				continue
+			}
+
+			var violation string
+			switch {
+			case err != nil:
+				violation = fmt.Sprintf("policy %q: %v", policy.Name, err)
+			case len(v.validation.Message) > 0:
+				violation = fmt.Sprintf("policy %q: %s", policy.Name, v.validation.Message)
+			default:
+				violation = fmt.Sprintf("policy %q: failed expression: %s", policy.Name, v.validation.Expression)
+			}
+			if containsAction(actions, Deny) {
+				violations = append(violations, violation)
+			}
+			if containsAction(actions, Warn) {
+				warnings = append(warnings, violation)
+			}
+			if containsAction(actions, Audit) {
+				auditViolations = append(auditViolations, violation)
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		if audited {
+			e.audit(resource, obj, operation, strings.Join(auditViolations, "; "))
+		}
+		return warnings, nil
+	}
+	return warnings, apierrors.NewForbidden(groupResource, name, fmt.Errorf("%s", strings.Join(violations, "; ")))
+}
+
+// audit queues the failed validations of an admitted request to be recorded in the annotation of the requested
+// object, the annotation is removed if the validations pass. Nothing is queued if the annotation is up to date, so
+// the update of the annotation does not trigger another one.
+func (e *Evaluator) audit(resource string, obj runtime.Object, operation admissionv1.Operation, violations string) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		klog.Warningf("failed to audit the %s request of %s: %v", operation, resource, err)
+		return
+	}
+	if accessor.GetAnnotations()[AuditViolationsAnnotationKey] == violations {
+		return
+	}
+	if len(violations) > 0 {
+		klog.Infof("The %s request of %s %q violates the cluster admission policies: %s",
+			operation, resource, accessor.GetName(), violations)
+	}
+
+	key := auditKey{resource: resource, namespace: accessor.GetNamespace(), name: accessor.GetName()}
+	e.auditLock.Lock()
+	e.auditResults[key] = violations
+	e.auditLock.Unlock()
+	e.auditQueue.Add(key)
+}
+
+func (e *Evaluator) runAuditWorker(ctx context.Context) {
+	for e.processNextAudit(ctx) {
+	}
+}
+
+func (e *Evaluator) processNextAudit(ctx context.Context) bool {
+	item, quit := e.auditQueue.Get()
+	if quit {
+		return false
+	}
+	defer e.auditQueue.Done(item)
+
+	key := item.(auditKey)
+	e.auditLock.Lock()
+	violations := e.auditResults[key]
+	e.auditLock.Unlock()
+
+	err := e.recordAudit(ctx, key, violations)
+	if err != nil && e.auditQueue.NumRequeues(item) < maxAuditRetries {
+		e.auditQueue.AddRateLimited(item)
+		return true
+	}
+	if err != nil {
+		klog.Warningf("failed to record the cluster admission policy violations of %s %s/%s: %v",
+			key.resource, key.namespace, key.name, err)
+	}
+
+	e.auditQueue.Forget(item)
+	e.auditLock.Lock()
+	// the result is kept if it is changed by another request in the meantime
+	if e.auditResults[key] == violations {
+		delete(e.auditResults, key)
+	}
+	e.auditLock.Unlock()
+	return true
+}
+
+// recordAudit records the failed validations in the annotation and an event of the persisted object.
+func (e *Evaluator) recordAudit(ctx context.Context, key auditKey, violations string) error {
+	gvr, ok := auditResources[key.resource]
+	if !ok {
+		return fmt.Errorf("unsupported resource %s", key.resource)
+	}
+	obj, err := e.client.Resource(gvr).Namespace(key.namespace).Get(ctx, key.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if obj.GetAnnotations()[AuditViolationsAnnotationKey] == violations {
+		return nil
+	}
+
+	var value interface{}
+	if len(violations) > 0 {
+		value = violations
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid":             obj.GetUID(),
+			"resourceVersion": obj.GetResourceVersion(),
+			"annotations":     map[string]interface{}{AuditViolationsAnnotationKey: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	obj, err = e.client.Resource(gvr).Namespace(key.namespace).Patch(ctx, key.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 && e.recorder != nil {
+		e.recorder.Eventf(obj, nil, corev1.EventTypeWarning, "ClusterAdmissionPolicyViolated", "Audit", "%s", violations)
+	}
+	return nil
+}
+
+// listPolicies returns the policies matching the resource and the operation sorted by name.
+func (e *Evaluator) listPolicies(resource string, operation admissionv1.Operation) []*ClusterAdmissionPolicy {
+	var policies []*ClusterAdmissionPolicy
+	for _, obj := range e.informer.GetStore().List() {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		policy := &ClusterAdmissionPolicy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, policy); err != nil {
+			klog.Warningf("failed to convert the cluster admission policy %q: %v", u.GetName(), err)
+			continue
+		}
+		if !contains(policy.Spec.Resources, resource) {
+			continue
+		}
+		if len(policy.Spec.Operations) > 0 && !contains(policy.Spec.Operations, string(operation)) {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+func (e *Evaluator) compile(policy *ClusterAdmissionPolicy) *compiledPolicy {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if compiled, ok := e.compiled[policy.UID]; ok && compiled.resourceVersion == policy.ResourceVersion {
+		return compiled
+	}
+
+	compiled := &compiledPolicy{resourceVersion: policy.ResourceVersion}
+	for _, validation := range policy.Spec.Validations {
+		compiled.validations = append(compiled.validations, compileValidation(e.env, validation))
+	}
+	e.compiled[policy.UID] = compiled
+	return compiled
+}
+
+func compileValidation(env *cel.Env, validation Validation) compiledValidation {
+	v := compiledValidation{validation: validation}
+	ast, issues := env.Compile(validation.Expression)
+	if issues != nil && issues.Err() != nil {
+		v.err = fmt.Errorf("failed to compile expression %q: %v", validation.Expression, issues.Err())
+		return v
+	}
+	v.program, v.err = env.Program(ast, cel.CostLimit(celconfig.PerCallLimit))
+	return v
+}
+
+func evaluate(v compiledValidation, activation map[string]interface{}) (bool, error) {
+	if v.err != nil {
+		return false, v.err
+	}
+	val, _, err := v.program.Eval(activation)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate expression %q: %v", v.validation.Expression, err)
+	}
+	passed, ok := val.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q must be evaluated to a bool", v.validation.Expression)
+	}
+	return passed, nil
+}
+
+func containsAction(actions []ValidationAction, action ValidationAction) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	kevents "k8s.io/client-go/tools/events"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func newPolicy(t *testing.T, name string, spec ClusterAdmissionPolicySpec) *unstructured.Unstructured {
+	policy := &ClusterAdmissionPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ClusterAdmissionPolicyResource.GroupVersion().String(),
+			Kind:       "ClusterAdmissionPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name), ResourceVersion: "1"},
+		Spec:       spec,
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func newCluster(name string, taints ...clusterv1.Taint) *clusterv1.ManagedCluster {
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       clusterv1.ManagedClusterSpec{Taints: taints},
+	}
+}
+
+const exemptGroup = "system:serviceaccounts:open-cluster-management-hub"
+
+var testUser = authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:authenticated"}}
+
+func newDiscovery(crdInstalled bool) *fakediscovery.FakeDiscovery {
+	client := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	if crdInstalled {
+		client.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: ClusterAdmissionPolicyResource.GroupVersion().String(),
+				APIResources: []metav1.APIResource{{Name: ClusterAdmissionPolicyResource.Resource}},
+			},
+		}
+	}
+	return client
+}
+
+func TestValidate(t *testing.T) {
+	namingPolicy := ClusterAdmissionPolicySpec{
+		Resources:  []string{"managedclusters"},
+		Operations: []string{"CREATE"},
+		Validations: []Validation{
+			{Expression: "object.metadata.name.startsWith('prod-') || object.metadata.name.startsWith('dev-')",
+				Message: "the cluster name must start with prod- or dev-"},
+		},
+	}
+	taintPolicy := ClusterAdmissionPolicySpec{
+		Resources: []string{"managedclusters"},
+		Validations: []Validation{
+			{Expression: "!has(object.spec.taints) || object.spec.taints.all(t, t.key != 'forbidden')"},
+		},
+	}
+	invalidPolicy := ClusterAdmissionPolicySpec{
+		Resources:   []string{"managedclusters"},
+		Validations: []Validation{{Expression: "object.metadata.name +"}},
+	}
+
+	cases := []struct {
+		name             string
+		policies         []*unstructured.Unstructured
+		operation        admissionv1.Operation
+		userInfo         *authenticationv1.UserInfo
+		cluster          *clusterv1.ManagedCluster
+		oldCluster       *clusterv1.ManagedCluster
+		expectedError    string
+		expectedWarnings int
+		expectedAudit    string
+	}{
+		{
+			name:      "no policies",
+			operation: admissionv1.Create,
+			cluster:   newCluster("cluster1"),
+		},
+		{
+			name:          "naming convention is violated",
+			policies:      []*unstructured.Unstructured{newPolicy(t, "naming", namingPolicy)},
+			operation:     admissionv1.Create,
+			cluster:       newCluster("cluster1"),
+			expectedError: "the cluster name must start with prod- or dev-",
+		},
+		{
+			name:       "naming convention is not validated on update",
+			policies:   []*unstructured.Unstructured{newPolicy(t, "naming", namingPolicy)},
+			operation:  admissionv1.Update,
+			cluster:    newCluster("cluster1"),
+			oldCluster: newCluster("cluster1"),
+		},
+		{
+			name:      "naming convention is followed",
+			policies:  []*unstructured.Unstructured{newPolicy(t, "naming", namingPolicy)},
+			operation: admissionv1.Create,
+			cluster:   newCluster("dev-cluster1"),
+		},
+		{
+			name:      "hub controllers are exempt",
+			policies:  []*unstructured.Unstructured{newPolicy(t, "naming", namingPolicy)},
+			operation: admissionv1.Create,
+			userInfo: &authenticationv1.UserInfo{
+				Username: "system:serviceaccount:open-cluster-management-hub:registration-controller-sa",
+				Groups:   []string{exemptGroup, "system:authenticated"},
+			},
+			cluster: newCluster("cluster1"),
+		},
+		{
+			name:          "forbidden taint",
+			policies:      []*unstructured.Unstructured{newPolicy(t, "taint", taintPolicy)},
+			operation:     admissionv1.Update,
+			cluster:       newCluster("cluster1", clusterv1.Taint{Key: "forbidden", Effect: clusterv1.TaintEffectNoSelect}),
+			oldCluster:    newCluster("cluster1"),
+			expectedError: "failed expression",
+		},
+		{
+			name:          "invalid expression fails",
+			policies:      []*unstructured.Unstructured{newPolicy(t, "invalid", invalidPolicy)},
+			operation:     admissionv1.Create,
+			cluster:       newCluster("cluster1"),
+			expectedError: "failed to compile expression",
+		},
+		{
+			name: "invalid expression is ignored",
+			policies: func() []*unstructured.Unstructured {
+				spec := invalidPolicy
+				spec.FailurePolicy = Ignore
+				return []*unstructured.Unstructured{newPolicy(t, "invalid", spec)}
+			}(),
+			operation: admissionv1.Create,
+			cluster:   newCluster("cluster1"),
+		},
+		{
+			name: "naming convention is violated with warn",
+			policies: func() []*unstructured.Unstructured {
+				spec := namingPolicy
+				spec.ValidationActions = []ValidationAction{Warn}
+				return []*unstructured.Unstructured{newPolicy(t, "naming", spec)}
+			}(),
+			operation:        admissionv1.Create,
+			cluster:          newCluster("cluster1"),
+			expectedWarnings: 1,
+		},
+		{
+			name: "naming convention is violated with warn and audit",
+			policies: func() []*unstructured.Unstructured {
+				spec := namingPolicy
+				spec.ValidationActions = []ValidationAction{Warn, Audit}
+				return []*unstructured.Unstructured{newPolicy(t, "naming", spec)}
+			}(),
+			operation:        admissionv1.Create,
+			cluster:          newCluster("cluster1"),
+			expectedWarnings: 1,
+			expectedAudit:    "the cluster name must start with prod- or dev-",
+		},
+		{
+			name: "audit does not deny the other policies",
+			policies: func() []*unstructured.Unstructured {
+				spec := namingPolicy
+				spec.ValidationActions = []ValidationAction{Audit}
+				return []*unstructured.Unstructured{newPolicy(t, "naming", spec), newPolicy(t, "taint", taintPolicy)}
+			}(),
+			operation:     admissionv1.Create,
+			cluster:       newCluster("cluster1", clusterv1.Taint{Key: "forbidden", Effect: clusterv1.TaintEffectNoSelect}),
+			expectedError: "policy \"taint\"",
+		},
+		{
+			name: "audit annotation is removed once the validations pass",
+			policies: func() []*unstructured.Unstructured {
+				spec := taintPolicy
+				spec.ValidationActions = []ValidationAction{Audit}
+				return []*unstructured.Unstructured{newPolicy(t, "taint", spec)}
+			}(),
+			operation: admissionv1.Update,
+			cluster: func() *clusterv1.ManagedCluster {
+				cluster := newCluster("cluster1")
+				cluster.Annotations = map[string]string{AuditViolationsAnnotationKey: "policy \"taint\": failed expression"}
+				return cluster
+			}(),
+			oldCluster: newCluster("cluster1"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+				map[schema.GroupVersionResource]string{
+					ClusterAdmissionPolicyResource:    "ClusterAdmissionPolicyList",
+					auditResources["managedclusters"]: "ManagedClusterList",
+				})
+			// the requested cluster is persisted once the request is admitted
+			if _, err := client.Resource(auditResources["managedclusters"]).Create(
+				context.TODO(), newUnstructuredCluster(t, c.cluster), metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			for _, policy := range c.policies {
+				if _, err := client.Resource(ClusterAdmissionPolicyResource).Create(
+					context.TODO(), policy, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			evaluator, err := NewEvaluator(client, newDiscovery(true), []string{exemptGroup})
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := kevents.NewFakeRecorder(10)
+			evaluator.SetEventRecorder(recorder)
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			go func() {
+				_ = evaluator.Start(ctx)
+			}()
+			if !cache.WaitForCacheSync(ctx.Done(), evaluator.informer.HasSynced) {
+				t.Fatal("failed to sync the policies")
+			}
+
+			var oldObj runtime.Object
+			if c.oldCluster != nil {
+				oldObj = c.oldCluster
+			}
+			userInfo := testUser
+			if c.userInfo != nil {
+				userInfo = *c.userInfo
+			}
+			warnings, err := evaluator.Validate("managedclusters", c.operation, userInfo, c.cluster.Name, c.cluster, oldObj)
+			if len(warnings) != c.expectedWarnings {
+				t.Errorf("expected %d warnings, but got %v", c.expectedWarnings, warnings)
+			}
+			assertAuditAnnotation(t, client, c.cluster.Name, c.expectedAudit)
+			switch {
+			case len(c.expectedAudit) > 0:
+				select {
+				case <-recorder.Events:
+				case <-time.After(5 * time.Second):
+					t.Errorf("expected an event of the audit")
+				}
+			case len(recorder.Events) > 0:
+				t.Errorf("expected no events, but got %d", len(recorder.Events))
+			}
+			if len(c.expectedError) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, but got %v", err)
+				}
+				return
+			}
+			if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), c.expectedError) {
+				t.Errorf("expected forbidden error containing %q, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func newUnstructuredCluster(t *testing.T, cluster *clusterv1.ManagedCluster) *unstructured.Unstructured {
+	cluster = cluster.DeepCopy()
+	cluster.APIVersion = clusterv1.GroupVersion.String()
+	cluster.Kind = "ManagedCluster"
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+// assertAuditAnnotation waits until the audit annotation of the cluster contains the expected violations, or is
+// removed if no violation is expected.
+func assertAuditAnnotation(t *testing.T, client *dynamicfake.FakeDynamicClient, name, expected string) {
+	var annotation string
+	err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			cluster, err := client.Resource(auditResources["managedclusters"]).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			var ok bool
+			annotation, ok = cluster.GetAnnotations()[AuditViolationsAnnotationKey]
+			if len(expected) == 0 {
+				return !ok, nil
+			}
+			return strings.Contains(annotation, expected), nil
+		})
+	if err != nil {
+		t.Errorf("expected audit annotation %q, but got %q: %v", expected, annotation, err)
+	}
+}
+
+func TestNilEvaluator(t *testing.T) {
+	var evaluator *Evaluator
+	if _, err := evaluator.Validate("managedclusters", admissionv1.Create, testUser, "cluster1", newCluster("cluster1"), nil); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+}
+
+func TestCRDNotInstalled(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{ClusterAdmissionPolicyResource: "ClusterAdmissionPolicyList"})
+	evaluator, err := NewEvaluator(client, newDiscovery(false), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go func() {
+		_ = evaluator.Start(ctx)
+	}()
+
+	if err := evaluator.ReadyCheck(nil); err != nil {
+		t.Errorf("expected ready without the policy CRD, but got %v", err)
+	}
+	if _, err := evaluator.Validate("managedclusters", admissionv1.Create, testUser, "cluster1", newCluster("cluster1"), nil); err != nil {
+		t.Errorf("expected no error without the policy CRD, but got %v", err)
+	}
+	if evaluator.started.Load() {
+		t.Errorf("expected the informer is not started without the policy CRD")
+	}
+}
@@ -0,0 +1,85 @@
+package policy
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClusterAdmissionPolicyResource is the resource of the ClusterAdmissionPolicy, the policy is a cluster scoped
+// resource defined by the admin to validate the ManagedClusters, ManagedClusterSets and ManagedClusterSetBindings
+// with CEL expressions.
+var ClusterAdmissionPolicyResource = schema.GroupVersionResource{
+	Group:    "admission.cluster.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "clusteradmissionpolicies",
+}
+
+// AuditViolationsAnnotationKey is the annotation of a cluster resource recording the failed validations of the
+// policies with the Audit action, it is removed once the resource passes the validations.
+const AuditViolationsAnnotationKey = "admission.cluster.open-cluster-management.io/audit-violations"
+
+// FailurePolicyType defines how the errors of compiling and evaluating the expressions are handled.
+type FailurePolicyType string
+
+const (
+	// Fail rejects the request if an expression of the policy fails to compile or evaluate.
+	Fail FailurePolicyType = "Fail"
+	// Ignore skips the expression which fails to compile or evaluate.
+	Ignore FailurePolicyType = "Ignore"
+)
+
+// ValidationAction defines how the failed validations of a policy are enforced.
+type ValidationAction string
+
+const (
+	// Deny rejects the request.
+	Deny ValidationAction = "Deny"
+	// Warn returns the failed validations to the client as warnings.
+	Warn ValidationAction = "Warn"
+	// Audit records the failed validations in an annotation and an event of the requested object.
+	Audit ValidationAction = "Audit"
+)
+
+// ClusterAdmissionPolicy validates the create and update requests of the cluster resources with CEL expressions.
+type ClusterAdmissionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterAdmissionPolicySpec `json:"spec"`
+}
+
+type ClusterAdmissionPolicySpec struct {
+	// Resources are the resources validated by the policy, the supported resources are managedclusters,
+	// managedclustersets and managedclustersetbindings.
+	Resources []string `json:"resources"`
+
+	// Operations are the operations validated by the policy, the supported operations are CREATE and UPDATE.
+	// All the supported operations are validated if it is empty.
+	// +optional
+	Operations []string `json:"operations,omitempty"`
+
+	// Validations are the CEL expressions, the request is rejected if any of the expressions is evaluated to
+	// false. The expressions access the requested object with the object variable, and the existing object of an
+	// update request with the oldObject variable, oldObject is null for a create request.
+	Validations []Validation `json:"validations"`
+
+	// FailurePolicy defines how the errors of compiling and evaluating the expressions are handled, the
+	// default is Fail.
+	// +optional
+	FailurePolicy FailurePolicyType `json:"failurePolicy,omitempty"`
+
+	// ValidationActions are the actions taken when the validations fail, including the failed expressions with
+	// the Fail failure policy. Warn and Audit without Deny allow the admin to trial a policy on the existing fleet
+	// before enforcing it. The default is Deny.
+	// +optional
+	ValidationActions []ValidationAction `json:"validationActions,omitempty"`
+}
+
+type Validation struct {
+	// Expression is a CEL expression evaluated to a bool.
+	Expression string `json:"expression"`
+
+	// Message is returned when the expression is evaluated to false. The default message contains the expression.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -3,18 +3,30 @@ package webhook
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Import all auth plugins (e.g. Azure, GCP, OIDC, etc.) to ensure exec-entrypoint and run can make use of them.
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	clusterv1client "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/registration/webhook/policy"
 	internalv1 "open-cluster-management.io/ocm/pkg/registration/webhook/v1"
 	internalv1beta2 "open-cluster-management.io/ocm/pkg/registration/webhook/v1beta2"
 )
@@ -34,6 +46,11 @@ func (c *Options) RunWebhookServer() error {
 	// This line prevents controller-runtime from complaining about log.SetLogger never being called
 	ctrl.SetLogger(logger)
 
+	if c.FIPSMode {
+		fips.Enable()
+	}
+	fips.Validate()
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: ":8000",
@@ -44,6 +61,7 @@ func (c *Options) RunWebhookServer() error {
 				func(config *tls.Config) {
 					config.MinVersion = tls.VersionTLS12
 				},
+				fips.ConfigureTLS,
 			},
 		}),
 	})
@@ -63,21 +81,85 @@ func (c *Options) RunWebhookServer() error {
 		return err
 	}
 
-	if err = (&internalv1.ManagedClusterWebhook{}).Init(mgr); err != nil {
+	// the admin defined cluster admission policies are evaluated by all the webhooks
+	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	policyEvaluator, err := policy.NewEvaluator(dynamicClient, discoveryClient, c.PolicyExemptGroups)
+	if err != nil {
+		logger.Error(err, "unable to create cluster admission policy evaluator")
+		return err
+	}
+	if err := mgr.Add(policyEvaluator); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("cluster-admission-policies", policyEvaluator.ReadyCheck); err != nil {
+		logger.Error(err, "unable to add readyz check handler")
+		return err
+	}
+	// the failed validations of the policies with the Audit action are recorded in events
+	ctx := ctrl.SetupSignalHandler()
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	recorder, err := helpers.NewEventRecorder(ctx, scheme, kubeClient, "cluster-manager-registration-webhook")
+	if err != nil {
+		return err
+	}
+	policyEvaluator.SetEventRecorder(recorder)
+
+	// the clustersets are watched to validate the exclusive clusterset memberships of the clusters
+	clusterClient, err := clusterv1client.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	clusterInformers := clusterv1informers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
+	clusterSetInformer := clusterInformers.Cluster().V1beta2().ManagedClusterSets().Informer()
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		clusterInformers.Start(ctx.Done())
+		<-ctx.Done()
+		return nil
+	})); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("managed-cluster-sets", func(_ *http.Request) error {
+		if !clusterSetInformer.HasSynced() {
+			return fmt.Errorf("the managed cluster sets are not synced")
+		}
+		return nil
+	}); err != nil {
+		logger.Error(err, "unable to add readyz check handler")
+		return err
+	}
+
+	clusterWebhook := &internalv1.ManagedClusterWebhook{}
+	clusterWebhook.SetPolicyEvaluator(policyEvaluator)
+	clusterWebhook.SetClusterSetLister(clusterInformers.Cluster().V1beta2().ManagedClusterSets().Lister())
+	if err = clusterWebhook.Init(mgr); err != nil {
 		logger.Error(err, "unable to create ManagedCluster webhook")
 		return err
 	}
-	if err = (&internalv1beta2.ManagedClusterSetBindingWebhook{}).Init(mgr); err != nil {
+	clusterSetBindingWebhook := &internalv1beta2.ManagedClusterSetBindingWebhook{}
+	clusterSetBindingWebhook.SetPolicyEvaluator(policyEvaluator)
+	if err = clusterSetBindingWebhook.Init(mgr); err != nil {
 		logger.Error(err, "unable to create ManagedClusterSetBinding webhook", "version", "v1beta2")
 		return err
 	}
-	if err = (&internalv1beta2.ManagedClusterSet{}).SetupWebhookWithManager(mgr); err != nil {
+	clusterSetWebhook := &internalv1beta2.ManagedClusterSetWebhook{}
+	clusterSetWebhook.SetPolicyEvaluator(policyEvaluator)
+	if err = clusterSetWebhook.Init(mgr); err != nil {
 		logger.Error(err, "unable to create ManagedClusterSet webhook", "version", "v1beta2")
 		return err
 	}
 
 	logger.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		logger.Error(err, "problem running manager")
 		return err
 	}
@@ -3,6 +3,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	operatorhelpers "github.com/openshift/library-go/pkg/operator/v1helpers"
@@ -13,6 +14,7 @@ import (
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -59,8 +61,15 @@ func (r *ManagedClusterWebhook) ValidateCreate(ctx context.Context, obj runtime.
 	if len(managedCluster.Labels) > 0 {
 		clusterSetName = managedCluster.Labels[clusterv1beta2.ClusterSetLabel]
 	}
+	if err := r.allowSetClusterSetLabel(req.UserInfo, "", clusterSetName); err != nil {
+		return nil, err
+	}
+
+	if err := r.validateExclusiveClusterSets(managedCluster, nil); err != nil {
+		return nil, err
+	}
 
-	return nil, r.allowSetClusterSetLabel(req.UserInfo, "", clusterSetName)
+	return r.policyEvaluator.Validate("managedclusters", req.Operation, req.UserInfo, managedCluster.Name, managedCluster, nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -108,8 +117,65 @@ func (r *ManagedClusterWebhook) ValidateUpdate(ctx context.Context, oldObj, newO
 	if len(managedCluster.Labels) > 0 {
 		currentClusterSetName = managedCluster.Labels[clusterv1beta2.ClusterSetLabel]
 	}
+	if err := r.allowSetClusterSetLabel(req.UserInfo, originalClusterSetName, currentClusterSetName); err != nil {
+		return nil, err
+	}
+
+	if err := r.validateExclusiveClusterSets(managedCluster, oldManagedCluster); err != nil {
+		return nil, err
+	}
+
+	return r.policyEvaluator.Validate("managedclusters", req.Operation, req.UserInfo, managedCluster.Name,
+		managedCluster, oldManagedCluster)
+}
 
-	return nil, r.allowSetClusterSetLabel(req.UserInfo, originalClusterSetName, currentClusterSetName)
+// validateExclusiveClusterSets rejects the labels putting the cluster into more than one exclusive clusterset.
+// The conflicts which already exist, for example caused by the change of a clusterset selector, are reported by
+// the clusterset controller and do not block the other updates of the cluster.
+func (r *ManagedClusterWebhook) validateExclusiveClusterSets(cluster, oldCluster *v1.ManagedCluster) error {
+	if r.clusterSetLister == nil {
+		return nil
+	}
+	if oldCluster != nil && reflect.DeepEqual(oldCluster.Labels, cluster.Labels) {
+		return nil
+	}
+
+	exclusiveSets, err := helpers.GetExclusiveClusterSetsOfCluster(cluster, r.clusterSetLister)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if len(exclusiveSets) <= 1 {
+		return nil
+	}
+
+	existingSets := sets.New[string]()
+	if oldCluster != nil {
+		oldExclusiveSets, err := helpers.GetExclusiveClusterSetsOfCluster(oldCluster, r.clusterSetLister)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		for _, clusterSet := range oldExclusiveSets {
+			existingSets.Insert(clusterSet.Name)
+		}
+	}
+
+	var names []string
+	joined := false
+	for _, clusterSet := range exclusiveSets {
+		names = append(names, clusterSet.Name)
+		if !existingSets.Has(clusterSet.Name) {
+			joined = true
+		}
+	}
+	if !joined {
+		return nil
+	}
+	return apierrors.NewForbidden(
+		v1.Resource("managedclusters"),
+		cluster.Name,
+		fmt.Errorf("the cluster cannot belong to more than one exclusive ManagedClusterSet, but is selected by %s",
+			strings.Join(names, ", ")),
+	)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -3,6 +3,7 @@ package v1
 import (
 	"context"
 	"testing"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authorizationv1 "k8s.io/api/authorization/v1"
@@ -13,8 +14,12 @@ import (
 	clienttesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	clusterfake "open-cluster-management.io/api/client/cluster/clientset/versioned/fake"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	v1 "open-cluster-management.io/api/cluster/v1"
 	"open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/registration/helpers"
 )
 
 func TestValidateCreate(t *testing.T) {
@@ -603,3 +608,91 @@ func TestValidateUpdate(t *testing.T) {
 		t.Errorf("Non cluster obj, Expect Error but got nil")
 	}
 }
+
+func TestValidateExclusiveClusterSets(t *testing.T) {
+	newExclusiveSet := func(name string, matchLabels map[string]string) *v1beta2.ManagedClusterSet {
+		return &v1beta2.ManagedClusterSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{helpers.ExclusiveClusterSetAnnotationKey: "true"},
+			},
+			Spec: v1beta2.ManagedClusterSetSpec{
+				ClusterSelector: v1beta2.ManagedClusterSelector{
+					SelectorType:  v1beta2.LabelSelector,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: matchLabels},
+				},
+			},
+		}
+	}
+	newCluster := func(labels map[string]string) *v1.ManagedCluster {
+		return &v1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: labels}}
+	}
+
+	clusterSets := []*v1beta2.ManagedClusterSet{
+		newExclusiveSet("prod", map[string]string{"env": "prod"}),
+		newExclusiveSet("team-a", map[string]string{"team": "a"}),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "global"},
+			Spec: v1beta2.ManagedClusterSetSpec{
+				ClusterSelector: v1beta2.ManagedClusterSelector{
+					SelectorType:  v1beta2.LabelSelector,
+					LabelSelector: &metav1.LabelSelector{},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		cluster       *v1.ManagedCluster
+		oldCluster    *v1.ManagedCluster
+		expectedError bool
+	}{
+		{
+			name:    "create a cluster in one exclusive clusterset",
+			cluster: newCluster(map[string]string{"env": "prod"}),
+		},
+		{
+			name:          "create a cluster in two exclusive clustersets",
+			cluster:       newCluster(map[string]string{"env": "prod", "team": "a"}),
+			expectedError: true,
+		},
+		{
+			name:          "join another exclusive clusterset",
+			oldCluster:    newCluster(map[string]string{"env": "prod"}),
+			cluster:       newCluster(map[string]string{"env": "prod", "team": "a"}),
+			expectedError: true,
+		},
+		{
+			name:       "move to another exclusive clusterset",
+			oldCluster: newCluster(map[string]string{"env": "prod"}),
+			cluster:    newCluster(map[string]string{"team": "a"}),
+		},
+		{
+			name:       "existing conflict does not block other label changes",
+			oldCluster: newCluster(map[string]string{"env": "prod", "team": "a"}),
+			cluster:    newCluster(map[string]string{"env": "prod", "team": "a", "region": "east"}),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterSetInformer := clusterinformers.NewSharedInformerFactory(
+				clusterfake.NewSimpleClientset(), 10*time.Minute).Cluster().V1beta2().ManagedClusterSets()
+			for _, clusterSet := range clusterSets {
+				if err := clusterSetInformer.Informer().GetStore().Add(clusterSet); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			w := ManagedClusterWebhook{clusterSetLister: clusterSetInformer.Lister()}
+			err := w.validateExclusiveClusterSets(c.cluster, c.oldCluster)
+			if err != nil && !c.expectedError {
+				t.Errorf("expected no error, but got %v", err)
+			}
+			if err == nil && c.expectedError {
+				t.Errorf("expected error, but got nil")
+			}
+		})
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	clusterlisterv1beta2 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta2"
 	v1 "open-cluster-management.io/api/cluster/v1"
+
+	"open-cluster-management.io/ocm/pkg/registration/webhook/policy"
 )
 
 type ManagedClusterWebhook struct {
-	kubeClient kubernetes.Interface
+	kubeClient       kubernetes.Interface
+	policyEvaluator  *policy.Evaluator
+	clusterSetLister clusterlisterv1beta2.ManagedClusterSetLister
 }
 
 func (r *ManagedClusterWebhook) Init(mgr ctrl.Manager) error {
@@ -25,6 +30,16 @@ func (r *ManagedClusterWebhook) SetExternalKubeClientSet(client kubernetes.Inter
 	r.kubeClient = client
 }
 
+// SetPolicyEvaluator sets the evaluator of the cluster admission policies
+func (r *ManagedClusterWebhook) SetPolicyEvaluator(evaluator *policy.Evaluator) {
+	r.policyEvaluator = evaluator
+}
+
+// SetClusterSetLister sets the lister of the clustersets to validate the exclusive clusterset memberships
+func (r *ManagedClusterWebhook) SetClusterSetLister(lister clusterlisterv1beta2.ManagedClusterSetLister) {
+	r.clusterSetLister = lister
+}
+
 func (r *ManagedClusterWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(r).
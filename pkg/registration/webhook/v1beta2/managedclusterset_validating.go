@@ -0,0 +1,110 @@
+package v1beta2
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/apps/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/registration/hub/managedclusterset"
+)
+
+var _ webhook.CustomValidator = &ManagedClusterSetWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (w *ManagedClusterSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (
+	admission.Warnings, error) {
+	return w.validate(ctx, obj, nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (w *ManagedClusterSetWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (
+	admission.Warnings, error) {
+	return w.validate(ctx, newObj, oldObj)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (w *ManagedClusterSetWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (
+	admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the user setting the membership rules of the clusterset is allowed to add the clusters to the
+// clusterset, and evaluates the cluster admission policies on the clusterset. The old clusterset is nil on creation.
+func (w *ManagedClusterSetWebhook) validate(ctx context.Context, obj, oldObj runtime.Object) (admission.Warnings, error) {
+	clusterSet, ok := obj.(*v1beta2.ManagedClusterSet)
+	if !ok {
+		return nil, apierrors.NewBadRequest("Request clusterset obj format is not right")
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	rules, hasRules := clusterSet.Annotations[managedclusterset.MembershipRulesAnnotationKey]
+	oldRules := ""
+	if oldClusterSet, ok := oldObj.(*v1beta2.ManagedClusterSet); ok {
+		oldRules = oldClusterSet.Annotations[managedclusterset.MembershipRulesAnnotationKey]
+	}
+	if hasRules && rules != oldRules {
+		if err := allowJoinClusterSet(w.kubeClient, clusterSet.Name, req.UserInfo); err != nil {
+			return nil, err
+		}
+	}
+
+	if w.policyEvaluator == nil {
+		return nil, nil
+	}
+	return w.policyEvaluator.Validate("managedclustersets", req.Operation, req.UserInfo, clusterSet.Name, clusterSet, oldObj)
+}
+
+// allowJoinClusterSet checks if the user has permission to add the clusters to a particular cluster set
+func allowJoinClusterSet(kubeClient kubernetes.Interface, clusterSetName string, userInfo authenticationv1.UserInfo) error {
+	extra := make(map[string]authorizationv1.ExtraValue)
+	for k, v := range userInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:       "cluster.open-cluster-management.io",
+				Resource:    "managedclustersets",
+				Subresource: "join",
+				Verb:        "create",
+				Name:        clusterSetName,
+			},
+		},
+	}
+	sar, err := kubeClient.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return apierrors.NewForbidden(
+			v1beta1.Resource("managedclustersets/join"),
+			clusterSetName,
+			err,
+		)
+	}
+	if !sar.Status.Allowed {
+		return apierrors.NewForbidden(
+			v1beta1.Resource("managedclustersets/join"),
+			clusterSetName,
+			fmt.Errorf("user %q is not allowed to set the membership rules of cluster set %q", userInfo.Username, clusterSetName),
+		)
+	}
+	return nil
+}
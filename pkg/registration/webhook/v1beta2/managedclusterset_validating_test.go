@@ -0,0 +1,107 @@
+package v1beta2
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/registration/hub/managedclusterset"
+)
+
+func newClusterSetWithRules(rules string) *v1beta2.ManagedClusterSet {
+	clusterSet := &v1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "clusterset-1"},
+	}
+	if len(rules) > 0 {
+		clusterSet.Annotations = map[string]string{managedclusterset.MembershipRulesAnnotationKey: rules}
+	}
+	return clusterSet
+}
+
+func TestValidateMembershipRules(t *testing.T) {
+	rules := `{"clusterSelector":{"matchLabels":{"env":"prod"}}}`
+	cases := []struct {
+		name          string
+		clusterSet    *v1beta2.ManagedClusterSet
+		oldClusterSet *v1beta2.ManagedClusterSet
+		allowJoin     bool
+		expectedError bool
+		expectedSAR   bool
+	}{
+		{
+			name:       "clusterset without rules",
+			clusterSet: newClusterSetWithRules(""),
+		},
+		{
+			name:        "create clusterset with rules",
+			clusterSet:  newClusterSetWithRules(rules),
+			allowJoin:   true,
+			expectedSAR: true,
+		},
+		{
+			name:          "create clusterset with rules without permission",
+			clusterSet:    newClusterSetWithRules(rules),
+			expectedError: true,
+			expectedSAR:   true,
+		},
+		{
+			name:          "rules are not changed",
+			clusterSet:    newClusterSetWithRules(rules),
+			oldClusterSet: newClusterSetWithRules(rules),
+		},
+		{
+			name:          "change rules without permission",
+			clusterSet:    newClusterSetWithRules(`{"clusterSelector":{}}`),
+			oldClusterSet: newClusterSetWithRules(rules),
+			expectedError: true,
+			expectedSAR:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			kubeClient.PrependReactor(
+				"create",
+				"subjectaccessreviews",
+				func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, &authorizationv1.SubjectAccessReview{
+						Status: authorizationv1.SubjectAccessReviewStatus{
+							Allowed: c.allowJoin,
+						},
+					}, nil
+				},
+			)
+			w := ManagedClusterSetWebhook{
+				kubeClient: kubeClient,
+			}
+			ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+			})
+
+			var err error
+			if c.oldClusterSet == nil {
+				_, err = w.ValidateCreate(ctx, c.clusterSet)
+			} else {
+				_, err = w.ValidateUpdate(ctx, c.oldClusterSet, c.clusterSet)
+			}
+			if err != nil && !c.expectedError {
+				t.Errorf("expect nil error but got err: %v", err)
+			}
+			if err == nil && c.expectedError {
+				t.Errorf("expect error but got nil")
+			}
+			if sar := len(kubeClient.Actions()) > 0; sar != c.expectedSAR {
+				t.Errorf("expected the subject access review %v, but got %v", c.expectedSAR, sar)
+			}
+		})
+	}
+}
@@ -36,7 +36,10 @@ func (b *ManagedClusterSetBindingWebhook) ValidateCreate(ctx context.Context, ob
 	if err != nil {
 		return nil, apierrors.NewBadRequest(err.Error())
 	}
-	return nil, AllowBindingToClusterSet(b.kubeClient, binding.Spec.ClusterSet, req.UserInfo)
+	if err := AllowBindingToClusterSet(b.kubeClient, binding.Spec.ClusterSet, req.UserInfo); err != nil {
+		return nil, err
+	}
+	return b.policyEvaluator.Validate("managedclustersetbindings", req.Operation, req.UserInfo, binding.Name, binding, nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -51,7 +54,15 @@ func (b *ManagedClusterSetBindingWebhook) ValidateUpdate(ctx context.Context, ol
 	if binding.Name != binding.Spec.ClusterSet {
 		return nil, apierrors.NewBadRequest("The ManagedClusterSetBinding must have the same name as the target ManagedClusterSet")
 	}
-	return nil, nil
+
+	if b.policyEvaluator == nil {
+		return nil, nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	return b.policyEvaluator.Validate("managedclustersetbindings", req.Operation, req.UserInfo, binding.Name, binding, oldObj)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -7,6 +7,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"open-cluster-management.io/api/cluster/v1beta2"
+
+	"open-cluster-management.io/ocm/pkg/registration/webhook/policy"
 )
 
 var (
@@ -29,13 +31,39 @@ type ManagedClusterSet struct {
 	v1beta2.ManagedClusterSet
 }
 
+type ManagedClusterSetWebhook struct {
+	kubeClient      kubernetes.Interface
+	policyEvaluator *policy.Evaluator
+}
+
+func (w *ManagedClusterSetWebhook) Init(mgr ctrl.Manager) error {
+	err := w.SetupWebhookWithManager(mgr)
+	if err != nil {
+		return err
+	}
+	w.kubeClient, err = kubernetes.NewForConfig(mgr.GetConfig())
+	return err
+}
+
+// SetExternalKubeClientSet is function to enable the webhook injecting to kube admssion
+func (w *ManagedClusterSetWebhook) SetExternalKubeClientSet(client kubernetes.Interface) {
+	w.kubeClient = client
+}
+
 type ManagedClusterSetBindingWebhook struct {
-	kubeClient kubernetes.Interface
+	kubeClient      kubernetes.Interface
+	policyEvaluator *policy.Evaluator
 }
 
-func (src *ManagedClusterSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetPolicyEvaluator sets the evaluator of the cluster admission policies
+func (w *ManagedClusterSetWebhook) SetPolicyEvaluator(evaluator *policy.Evaluator) {
+	w.policyEvaluator = evaluator
+}
+
+func (w *ManagedClusterSetWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(src).
+		WithValidator(w).
+		For(&ManagedClusterSet{}).
 		Complete()
 }
 
@@ -53,6 +81,11 @@ func (b *ManagedClusterSetBindingWebhook) SetExternalKubeClientSet(client kubern
 	b.kubeClient = client
 }
 
+// SetPolicyEvaluator sets the evaluator of the cluster admission policies
+func (b *ManagedClusterSetBindingWebhook) SetPolicyEvaluator(evaluator *policy.Evaluator) {
+	b.policyEvaluator = evaluator
+}
+
 func (b *ManagedClusterSetBindingWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(b).
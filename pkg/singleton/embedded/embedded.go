@@ -0,0 +1,109 @@
+// Package embedded runs the registration and work agents of a managed cluster as a library, so that other binaries
+// can embed the agents in their own process instead of deploying the klusterlet agent separately.
+//
+// The agents are run the same way as the klusterlet agent in the Singleton mode, except that the process is not
+// managed by the agent: there is no leader election, no health check server, and errors are returned from Run
+// instead of exiting the process. The embedding binary is responsible for running a single instance of the agents
+// for a managed cluster.
+package embedded
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/controllercmd"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/spf13/pflag"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
+
+	ocmfeature "open-cluster-management.io/api/feature"
+
+	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
+	"open-cluster-management.io/ocm/pkg/features"
+	registration "open-cluster-management.io/ocm/pkg/registration/spoke"
+	singletonspoke "open-cluster-management.io/ocm/pkg/singleton/spoke"
+	work "open-cluster-management.io/ocm/pkg/work/spoke"
+)
+
+// Options are the options of the embedded agents.
+type Options struct {
+	// AgentOptions are the options shared by the registration and work agents, e.g. the cluster name and the
+	// location of the hub kubeconfig.
+	AgentOptions *commonoptions.AgentOptions
+	// RegistrationOptions are the options of the registration agent, e.g. the bootstrap kubeconfig.
+	RegistrationOptions *registration.SpokeAgentOptions
+	// WorkOptions are the options of the work agent.
+	WorkOptions *work.WorkloadAgentOptions
+
+	// KubeConfig is the config to connect to the cluster the agents run on. It is the managed cluster unless
+	// AgentOptions.SpokeKubeconfigFile is set.
+	KubeConfig *rest.Config
+	// EventRecorder records the events of the agents, the events are logged if it is not set.
+	EventRecorder events.Recorder
+	// FeatureGates are the feature gates of the agents, the default feature gates are used if not set.
+	FeatureGates map[string]bool
+}
+
+// NewOptions returns the options of the embedded agents with the default values set, the default feature gates
+// of the agents are registered as well.
+func NewOptions() *Options {
+	utilruntime.Must(features.SpokeMutableFeatureGate.Add(ocmfeature.DefaultSpokeRegistrationFeatureGates))
+	utilruntime.Must(features.SpokeMutableFeatureGate.Add(ocmfeature.DefaultSpokeWorkFeatureGates))
+	return &Options{
+		AgentOptions:        commonoptions.NewAgentOptions(),
+		RegistrationOptions: registration.NewSpokeAgentOptions(),
+		WorkOptions:         work.NewWorkloadAgentOptions(),
+	}
+}
+
+// AddFlags adds the flags of the klusterlet agent to the flag set, for the binaries exposing the options of the
+// embedded agents as flags. The feature gates are set with the FeatureGates field instead.
+func (o *Options) AddFlags(flags *pflag.FlagSet) {
+	o.AgentOptions.AddFlags(flags)
+	o.RegistrationOptions.AddFlags(flags)
+	o.WorkOptions.AddFlags(flags)
+}
+
+// Validate validates the options with the feature gates set, the options are also completed and validated by the
+// agents when they start.
+func (o *Options) Validate() error {
+	if o.KubeConfig == nil {
+		return fmt.Errorf("kubeconfig of the embedded agents is required")
+	}
+	if err := o.RegistrationOptions.Validate(); err != nil {
+		return err
+	}
+	// the cluster name can be generated or loaded from the hub kubeconfig secret when it is not set
+	if len(o.AgentOptions.SpokeClusterName) > 0 {
+		return o.AgentOptions.Validate()
+	}
+	return nil
+}
+
+// Run runs the registration agent, and the work agent once the managed cluster is registered to the hub. It blocks
+// until the context is done or either of the agents stops.
+func (o *Options) Run(ctx context.Context) error {
+	// the feature gates are shared by all the agents in the process.
+	if err := features.SpokeMutableFeatureGate.SetFromMap(o.FeatureGates); err != nil {
+		return err
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	kubeConfig := rest.CopyConfig(o.KubeConfig)
+	kubeConfig.QPS = o.AgentOptions.CommonOpts.QPS
+	kubeConfig.Burst = o.AgentOptions.CommonOpts.Burst
+	recorder := o.EventRecorder
+	if recorder == nil {
+		recorder = events.NewLoggingEventRecorder("klusterlet-agent")
+	}
+
+	agentConfig := singletonspoke.NewAgentConfig(o.AgentOptions, o.RegistrationOptions, o.WorkOptions)
+	return agentConfig.RunSpokeAgent(ctx, &controllercmd.ControllerContext{
+		KubeConfig:        kubeConfig,
+		EventRecorder:     recorder,
+		OperatorNamespace: o.AgentOptions.ComponentNamespace,
+	})
+}
@@ -0,0 +1,95 @@
+package embedded
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/rest"
+
+	ocmfeature "open-cluster-management.io/api/feature"
+
+	"open-cluster-management.io/ocm/pkg/features"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		setOptions  func(o *Options)
+		expectedErr bool
+	}{
+		{
+			name: "valid",
+			setOptions: func(o *Options) {
+				o.KubeConfig = &rest.Config{}
+				o.RegistrationOptions.BootstrapKubeconfig = "/spoke/bootstrap/kubeconfig"
+				o.AgentOptions.SpokeClusterName = "cluster1"
+			},
+		},
+		{
+			name: "without cluster name",
+			setOptions: func(o *Options) {
+				o.KubeConfig = &rest.Config{}
+				o.RegistrationOptions.BootstrapKubeconfig = "/spoke/bootstrap/kubeconfig"
+			},
+		},
+		{
+			name: "without kubeconfig",
+			setOptions: func(o *Options) {
+				o.RegistrationOptions.BootstrapKubeconfig = "/spoke/bootstrap/kubeconfig"
+			},
+			expectedErr: true,
+		},
+		{
+			name: "without bootstrap kubeconfig",
+			setOptions: func(o *Options) {
+				o.KubeConfig = &rest.Config{}
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid cluster name",
+			setOptions: func(o *Options) {
+				o.KubeConfig = &rest.Config{}
+				o.RegistrationOptions.BootstrapKubeconfig = "/spoke/bootstrap/kubeconfig"
+				o.AgentOptions.SpokeClusterName = "Invalid_Name"
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := NewOptions()
+			c.setOptions(o)
+			err := o.Validate()
+			if c.expectedErr && err == nil {
+				t.Errorf("expect error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("expect no error, but got %v", err)
+			}
+		})
+	}
+}
+
+func TestRunWithInvalidOptions(t *testing.T) {
+	o := NewOptions()
+	o.FeatureGates = map[string]bool{"UnknownFeature": true}
+	if err := o.Run(context.TODO()); err == nil {
+		t.Errorf("expect error of unknown feature gate, but got nil")
+	}
+
+	o = NewOptions()
+	o.FeatureGates = map[string]bool{string(ocmfeature.ClusterClaim): false}
+	defer func() {
+		if err := features.SpokeMutableFeatureGate.SetFromMap(map[string]bool{string(ocmfeature.ClusterClaim): true}); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := o.Run(context.TODO()); err == nil {
+		t.Errorf("expect error of missing kubeconfig, but got nil")
+	}
+	if features.SpokeMutableFeatureGate.Enabled(ocmfeature.ClusterClaim) {
+		t.Errorf("expect feature gate %s disabled", ocmfeature.ClusterClaim)
+	}
+}
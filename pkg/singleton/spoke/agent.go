@@ -2,12 +2,18 @@ package spoke
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/klog/v2"
 
+	clusterv1client "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	commonoptions "open-cluster-management.io/ocm/pkg/common/options"
 	registration "open-cluster-management.io/ocm/pkg/registration/spoke"
 	work "open-cluster-management.io/ocm/pkg/work/spoke"
@@ -30,29 +36,71 @@ func NewAgentConfig(
 	}
 }
 
+// RunSpokeAgent runs the registration agent and then the work agent once the hub kubeconfig is ready. It returns
+// once the context is done or either of the agents stops. The clients and informers of the managed cluster, and the
+// http client to the hub, are shared by the agents.
 func (a *AgentConfig) RunSpokeAgent(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, 2)
+
+	spokeRestConfig, err := a.agentOption.SpokeKubeConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return err
+	}
+	if a.agentOption.ReportDeniedActions {
+		commonhelpers.ReportDeniedActions(spokeRestConfig, controllerContext.EventRecorder)
+	}
+	if err := a.workOption.AuditOptions.Setup(ctx, spokeRestConfig); err != nil {
+		return err
+	}
+	spokeClients, err := work.NewSpokeClients(spokeRestConfig)
+	if err != nil {
+		return err
+	}
+	spokeClusterClient, err := clusterv1client.NewForConfigAndClient(spokeRestConfig, spokeClients.HTTPClient)
+	if err != nil {
+		return err
+	}
+
 	registrationCfg := registration.NewSpokeAgentConfig(a.agentOption, a.registrationOption)
 	// start registration agent at first
 	go func() {
-		if err := registrationCfg.RunSpokeAgent(ctx, controllerContext); err != nil {
-			klog.Fatal(err)
-		}
+		errCh <- registrationCfg.RunSpokeAgentWithSpokeInformers(
+			ctx,
+			controllerContext.KubeConfig,
+			spokeRestConfig,
+			spokeClients.KubeClient,
+			informers.NewSharedInformerFactory(spokeClients.KubeClient, 10*time.Minute),
+			clusterv1informers.NewSharedInformerFactory(spokeClusterClient, 10*time.Minute),
+			controllerContext.EventRecorder,
+		)
 	}()
 
 	// wait for the hub client config ready.
 	klog.Info("Waiting for hub client config and managed cluster to be ready")
-	if err := wait.PollUntilContextCancel(ctx, 1*time.Second, true, registrationCfg.HasValidHubClientConfig); err != nil {
+	hubClientConfigReady := func(ctx context.Context) (bool, error) {
+		select {
+		case err := <-errCh:
+			return false, fmt.Errorf("registration agent stopped: %v", err)
+		default:
+		}
+		return registrationCfg.HasValidHubClientConfig(ctx)
+	}
+	if err := wait.PollUntilContextCancel(ctx, 1*time.Second, true, hubClientConfigReady); err != nil {
 		return err
 	}
 
 	workCfg := work.NewWorkAgentConfig(a.agentOption, a.workOption)
 	// start work agent
 	go func() {
-		if err := workCfg.RunWorkloadAgent(ctx, controllerContext); err != nil {
-			klog.Fatal(err)
-		}
+		errCh <- workCfg.RunWorkloadAgentWithSpokeClients(ctx, controllerContext, spokeClients)
 	}()
 
-	<-ctx.Done()
-	return nil
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
 }
@@ -0,0 +1,210 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// ManifestWorkFeedbackSchemaAnnotationKey is the annotation of a manifestwork declaring the units of the status
+// feedback values of its manifests as a json FeedbackSchema. The agent converts the values with units to the canonical
+// types below, and reports the version of the applied schema in the FeedbackSchemaApplied condition of the manifest,
+// so the consumers on the hub interpret the values without the conventions of each source. The values are reported
+// as read, without the condition, by the agents which do not support the schema version.
+// TODO move this to the api repo.
+const ManifestWorkFeedbackSchemaAnnotationKey = "work.open-cluster-management.io/feedback-schema"
+
+// ManifestFeedbackSchemaApplied is the condition of a manifest reporting the version of the feedback schema applied
+// to its feedback values in the message, it is missing if the values are not converted by a schema.
+const ManifestFeedbackSchemaApplied = "FeedbackSchemaApplied"
+
+// FeedbackSchemaV1 is the version of the feedback schema supported by the agent.
+const FeedbackSchemaV1 = "v1"
+
+// FeedbackUnit is the unit of a feedback value.
+type FeedbackUnit string
+
+const (
+	// FeedbackUnitBytes values are reported as integers in bytes, the quantities, e.g. 1Gi, are converted. The
+	// fractions are rounded up.
+	FeedbackUnitBytes FeedbackUnit = "bytes"
+	// FeedbackUnitMillicores values are reported as integers in millicores, the quantities are converted, e.g. 500m is
+	// 500 and 2 is 2000, and the integers are in cores.
+	FeedbackUnitMillicores FeedbackUnit = "millicores"
+	// FeedbackUnitTimestamp values are reported as integers in seconds since the unix epoch, the RFC3339 strings are
+	// converted and the integers are kept.
+	FeedbackUnitTimestamp FeedbackUnit = "timestamp"
+)
+
+// FeedbackSchema defines the units of the status feedback values of the manifests of a manifestwork.
+type FeedbackSchema struct {
+	// Version is the version of the schema, e.g. v1.
+	Version string `json:"version"`
+
+	// Rules are the units of the feedback values of the manifests.
+	Rules []FeedbackSchemaRule `json:"rules"`
+}
+
+// FeedbackSchemaRule defines the units of the feedback values of a manifest.
+type FeedbackSchemaRule struct {
+	// ResourceIdentifier identifies the manifest in the manifestwork.
+	ResourceIdentifier workapiv1.ResourceIdentifier `json:"resourceIdentifier"`
+
+	// Values are the units of the feedback values by their names.
+	Values []FeedbackValueSchema `json:"values"`
+}
+
+// FeedbackValueSchema defines the unit of a feedback value.
+type FeedbackValueSchema struct {
+	// Name is the name of the feedback value, i.e. the name of its json path.
+	Name string `json:"name"`
+
+	// Unit is the unit of the value, bytes, millicores or timestamp.
+	Unit FeedbackUnit `json:"unit"`
+}
+
+// GetFeedbackSchema returns the feedback schema of the manifestwork, nil is returned if there is none. The schema of
+// an unsupported version is returned without being validated, it is not applied to the values.
+func GetFeedbackSchema(manifestWork *workapiv1.ManifestWork) (*FeedbackSchema, error) {
+	value, ok := manifestWork.Annotations[ManifestWorkFeedbackSchemaAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	schema := &FeedbackSchema{}
+	if err := json.Unmarshal([]byte(value), schema); err != nil {
+		return nil, fmt.Errorf("failed to parse the feedback schema: %w", err)
+	}
+	if schema.Version != FeedbackSchemaV1 {
+		return schema, nil
+	}
+	for _, rule := range schema.Rules {
+		for _, valueSchema := range rule.Values {
+			switch valueSchema.Unit {
+			case FeedbackUnitBytes, FeedbackUnitMillicores, FeedbackUnitTimestamp:
+			default:
+				return nil, fmt.Errorf("unsupported unit %q of feedback value %s of %v",
+					valueSchema.Unit, valueSchema.Name, rule.ResourceIdentifier)
+			}
+		}
+	}
+	return schema, nil
+}
+
+// FindValueSchemas returns the units of the feedback values of the manifest, nil is returned if the version of the
+// schema is not supported.
+func (s *FeedbackSchema) FindValueSchemas(resourceMeta workapiv1.ManifestResourceMeta) []FeedbackValueSchema {
+	if s == nil || s.Version != FeedbackSchemaV1 {
+		return nil
+	}
+
+	identifier := workapiv1.ResourceIdentifier{
+		Group:     resourceMeta.Group,
+		Resource:  resourceMeta.Resource,
+		Namespace: resourceMeta.Namespace,
+		Name:      resourceMeta.Name,
+	}
+
+	var valueSchemas []FeedbackValueSchema
+	for _, rule := range s.Rules {
+		if rule.ResourceIdentifier == identifier {
+			valueSchemas = append(valueSchemas, rule.Values...)
+		}
+	}
+	return valueSchemas
+}
+
+// ConvertFeedbackValues converts the feedback values with units. The values failed to be converted are removed, so
+// the consumers never read a value in an unexpected type.
+func ConvertFeedbackValues(values []workapiv1.FeedbackValue, valueSchemas []FeedbackValueSchema) ([]workapiv1.FeedbackValue, error) {
+	if len(valueSchemas) == 0 {
+		return values, nil
+	}
+
+	units := map[string]FeedbackUnit{}
+	for _, valueSchema := range valueSchemas {
+		units[valueSchema.Name] = valueSchema.Unit
+	}
+
+	var errs []error
+	converted := make([]workapiv1.FeedbackValue, 0, len(values))
+	for _, value := range values {
+		unit, ok := units[value.Name]
+		if !ok {
+			converted = append(converted, value)
+			continue
+		}
+		fieldValue, err := convertFieldValue(value.Value, unit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to convert feedback value %s to %s: %w", value.Name, unit, err))
+			continue
+		}
+		converted = append(converted, workapiv1.FeedbackValue{Name: value.Name, Value: fieldValue})
+	}
+	return converted, utilerrors.NewAggregate(errs)
+}
+
+// NewFeedbackSchemaAppliedCondition returns the FeedbackSchemaApplied condition of a manifest with the feedback values
+// converted by the schema of the version.
+func NewFeedbackSchemaAppliedCondition(version string) metav1.Condition {
+	return metav1.Condition{
+		Type:    ManifestFeedbackSchemaApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  "FeedbackSchemaApplied",
+		Message: version,
+	}
+}
+
+// GetFeedbackSchemaVersion returns the version of the feedback schema applied to the feedback values of a manifest by
+// its conditions, an empty string is returned if the values are not converted by a schema.
+func GetFeedbackSchemaVersion(conditions []metav1.Condition) string {
+	condition := meta.FindStatusCondition(conditions, ManifestFeedbackSchemaApplied)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return ""
+	}
+	return condition.Message
+}
+
+func convertFieldValue(value workapiv1.FieldValue, unit FeedbackUnit) (workapiv1.FieldValue, error) {
+	var converted int64
+	switch {
+	case unit == FeedbackUnitTimestamp && value.Type == workapiv1.Integer && value.Integer != nil:
+		converted = *value.Integer
+	case unit == FeedbackUnitTimestamp && value.Type == workapiv1.String && value.String != nil:
+		t, err := time.Parse(time.RFC3339, *value.String)
+		if err != nil {
+			return workapiv1.FieldValue{}, err
+		}
+		converted = t.Unix()
+	case value.Type == workapiv1.Integer && value.Integer != nil:
+		converted = quantityValue(*resource.NewQuantity(*value.Integer, resource.DecimalSI), unit)
+	case value.Type == workapiv1.String && value.String != nil:
+		q, err := resource.ParseQuantity(*value.String)
+		if err != nil {
+			return workapiv1.FieldValue{}, err
+		}
+		converted = quantityValue(q, unit)
+	default:
+		return workapiv1.FieldValue{}, fmt.Errorf("unsupported value type %s", value.Type)
+	}
+
+	return workapiv1.FieldValue{
+		Type:    workapiv1.Integer,
+		Integer: pointer.Int64(converted),
+	}, nil
+}
+
+func quantityValue(q resource.Quantity, unit FeedbackUnit) int64 {
+	if unit == FeedbackUnitMillicores {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
@@ -0,0 +1,189 @@
+package helper
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestGetFeedbackSchema(t *testing.T) {
+	deployment := workapiv1.ManifestResourceMeta{Group: "apps", Version: "v1", Resource: "deployments", Namespace: "ns1", Name: "deploy1"}
+	cases := []struct {
+		name                 string
+		annotation           string
+		expectedValueSchemas []FeedbackValueSchema
+		expectedErr          bool
+	}{
+		{
+			name: "no schema",
+		},
+		{
+			name: "schema of the manifest",
+			annotation: `{"version":"v1","rules":[` +
+				`{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},` +
+				`"values":[{"name":"memory","unit":"bytes"}]},` +
+				`{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy2"},` +
+				`"values":[{"name":"cpu","unit":"millicores"}]}]}`,
+			expectedValueSchemas: []FeedbackValueSchema{{Name: "memory", Unit: FeedbackUnitBytes}},
+		},
+		{
+			name: "unsupported version",
+			annotation: `{"version":"v2","rules":[` +
+				`{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},` +
+				`"values":[{"name":"memory","unit":"kibibytes"}]}]}`,
+		},
+		{
+			name: "unsupported unit",
+			annotation: `{"version":"v1","rules":[` +
+				`{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},` +
+				`"values":[{"name":"memory","unit":"kibibytes"}]}]}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid json",
+			annotation:  `{`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work := &workapiv1.ManifestWork{}
+			if len(c.annotation) > 0 {
+				work.Annotations = map[string]string{ManifestWorkFeedbackSchemaAnnotationKey: c.annotation}
+			}
+			schema, err := GetFeedbackSchema(work)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			valueSchemas := schema.FindValueSchemas(deployment)
+			if !equality.Semantic.DeepEqual(valueSchemas, c.expectedValueSchemas) {
+				t.Errorf("expected value schemas %v, but got %v", c.expectedValueSchemas, valueSchemas)
+			}
+		})
+	}
+}
+
+func TestConvertFeedbackValues(t *testing.T) {
+	integerValue := func(name string, value int64) workapiv1.FeedbackValue {
+		return workapiv1.FeedbackValue{Name: name, Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(value)}}
+	}
+	stringValue := func(name, value string) workapiv1.FeedbackValue {
+		return workapiv1.FeedbackValue{Name: name, Value: workapiv1.FieldValue{Type: workapiv1.String, String: pointer.String(value)}}
+	}
+	valueSchemas := []FeedbackValueSchema{
+		{Name: "memory", Unit: FeedbackUnitBytes},
+		{Name: "memoryLimit", Unit: FeedbackUnitBytes},
+		{Name: "cpu", Unit: FeedbackUnitMillicores},
+		{Name: "cpuLimit", Unit: FeedbackUnitMillicores},
+		{Name: "startTime", Unit: FeedbackUnitTimestamp},
+		{Name: "completionTime", Unit: FeedbackUnitTimestamp},
+	}
+
+	cases := []struct {
+		name           string
+		values         []workapiv1.FeedbackValue
+		valueSchemas   []FeedbackValueSchema
+		expectedValues []workapiv1.FeedbackValue
+		expectedErr    bool
+	}{
+		{
+			name:           "no schema",
+			values:         []workapiv1.FeedbackValue{stringValue("memory", "1Gi")},
+			expectedValues: []workapiv1.FeedbackValue{stringValue("memory", "1Gi")},
+		},
+		{
+			name: "convert the values",
+			values: []workapiv1.FeedbackValue{
+				stringValue("memory", "1Gi"),
+				integerValue("memoryLimit", 1024),
+				stringValue("cpu", "500m"),
+				integerValue("cpuLimit", 2),
+				stringValue("startTime", "2024-01-01T00:00:00Z"),
+				integerValue("completionTime", 1704067260),
+				integerValue("replicas", 3),
+			},
+			valueSchemas: valueSchemas,
+			expectedValues: []workapiv1.FeedbackValue{
+				integerValue("memory", 1073741824),
+				integerValue("memoryLimit", 1024),
+				integerValue("cpu", 500),
+				integerValue("cpuLimit", 2000),
+				integerValue("startTime", 1704067200),
+				integerValue("completionTime", 1704067260),
+				integerValue("replicas", 3),
+			},
+		},
+		{
+			name: "invalid values",
+			values: []workapiv1.FeedbackValue{
+				stringValue("memory", "large"),
+				stringValue("startTime", "yesterday"),
+				{Name: "cpu", Value: workapiv1.FieldValue{Type: workapiv1.Boolean, Boolean: pointer.Bool(true)}},
+				integerValue("replicas", 3),
+			},
+			valueSchemas: valueSchemas,
+			expectedValues: []workapiv1.FeedbackValue{
+				integerValue("replicas", 3),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values, err := ConvertFeedbackValues(c.values, c.valueSchemas)
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(values, c.expectedValues) {
+				t.Errorf("expected values %v, but got %v", c.expectedValues, values)
+			}
+		})
+	}
+}
+
+func TestGetFeedbackSchemaVersion(t *testing.T) {
+	cases := []struct {
+		name            string
+		conditions      []metav1.Condition
+		expectedVersion string
+	}{
+		{
+			name: "no condition",
+		},
+		{
+			name:            "schema applied",
+			conditions:      []metav1.Condition{NewFeedbackSchemaAppliedCondition(FeedbackSchemaV1)},
+			expectedVersion: FeedbackSchemaV1,
+		},
+		{
+			name: "schema not applied",
+			conditions: []metav1.Condition{
+				{Type: ManifestFeedbackSchemaApplied, Status: metav1.ConditionFalse, Message: FeedbackSchemaV1},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if version := GetFeedbackSchemaVersion(c.conditions); version != c.expectedVersion {
+				t.Errorf("expected version %q, but got %q", c.expectedVersion, version)
+			}
+		})
+	}
+}
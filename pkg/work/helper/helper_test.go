@@ -331,17 +331,26 @@ func TestDeleteAppliedResourcess(t *testing.T) {
 			expectedResourcesPendingFinalization: []workapiv1.AppliedManifestResourceMeta{},
 			owner:                                metav1.OwnerReference{Name: "n1", UID: "a"},
 		},
-	}
-
-	scheme := runtime.NewScheme()
-	if err := corev1.AddToScheme(scheme); err != nil {
-		t.Fatal(err)
+		{
+			name: "delete resources without owner by uids",
+			existingResources: []runtime.Object{
+				newSecret("ns1", "n1", false, "ns1-n1"),
+				newSecret("ns2", "n2", false, "ns2-n2-xxx"),
+			},
+			resourcesToRemove: []workapiv1.AppliedManifestResourceMeta{
+				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{Resource: "secrets", Namespace: "ns1", Name: "n1"}, UID: "ns1-n1"},
+				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{Resource: "secrets", Namespace: "ns2", Name: "n2"}, UID: "ns2-n2"},
+			},
+			expectedResourcesPendingFinalization: []workapiv1.AppliedManifestResourceMeta{
+				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{Resource: "secrets", Namespace: "ns1", Name: "n1"}, UID: "ns1-n1"},
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(scheme, c.existingResources...)
-			actual, err := DeleteAppliedResources(context.TODO(), c.resourcesToRemove, "testing", fakeDynamicClient, eventstesting.NewTestingEventRecorder(t), c.owner)
+			fakeMetadataClient := testingcommon.NewFakeMetadataClient(c.existingResources...)
+			actual, err := DeleteAppliedResources(context.TODO(), c.resourcesToRemove, "testing", fakeMetadataClient, eventstesting.NewTestingEventRecorder(t), c.owner)
 			if err != nil {
 				t.Errorf("unexpected err: %v", err)
 			}
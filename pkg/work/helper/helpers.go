@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/klog/v2"
 
 	clusterlister "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
@@ -141,11 +142,13 @@ func MergeStatusConditions(conditions []metav1.Condition, newConditions []metav1
 
 // DeleteAppliedResources deletes all given applied resources and returns those pending for finalization
 // If the uid recorded in resources is different from what we get by client, ignore the deletion.
+// The resources not owned by the owner are not deleted, unless the owner is empty.
+// Only the metadata of the resources is needed, so they are read with the metadata client.
 func DeleteAppliedResources(
 	ctx context.Context,
 	resources []workapiv1.AppliedManifestResourceMeta,
 	reason string,
-	dynamicClient dynamic.Interface,
+	metadataClient metadata.Interface,
 	recorder events.Recorder,
 	owner metav1.OwnerReference) ([]workapiv1.AppliedManifestResourceMeta, []error) {
 	var resourcesPendingFinalization []workapiv1.AppliedManifestResourceMeta
@@ -162,7 +165,7 @@ func DeleteAppliedResources(
 
 	for _, resource := range resources {
 		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource}
-		u, err := dynamicClient.
+		u, err := metadataClient.
 			Resource(gvr).
 			Namespace(resource.Namespace).
 			Get(ctx, resource.Name, metav1.GetOptions{})
@@ -180,14 +183,15 @@ func DeleteAppliedResources(
 
 		existingOwner := u.GetOwnerReferences()
 
-		// If it is not owned by us, skip
-		if !IsOwnedBy(owner, existingOwner) {
+		// If it is not owned by us, skip. The resources are not owned with the ownerrefs if the owner is empty, e.g.
+		// on a remote target cluster, they are deleted by the uids.
+		if len(owner.UID) > 0 && !IsOwnedBy(owner, existingOwner) {
 			continue
 		}
 
 		// If there are still any other existing appliedManifestWorks owners, update ownerrefs only.
-		if existOtherAppliedManifestWorkOwners(owner, existingOwner) {
-			err := ApplyOwnerReferences(ctx, dynamicClient, gvr, u, *ownerCopy)
+		if len(owner.UID) > 0 && existOtherAppliedManifestWorkOwners(owner, existingOwner) {
+			err := applyOwnerReferencesWithMetadataClient(ctx, metadataClient, gvr, u, *ownerCopy)
 			if err != nil {
 				errs = append(errs, fmt.Errorf(
 					"failed to remove owner from resource %v with key %s/%s: %w",
@@ -209,10 +213,10 @@ func DeleteAppliedResources(
 
 		// delete the resource which is not deleted yet
 		uid := types.UID(resource.UID)
-		err = dynamicClient.
+		err = metadataClient.
 			Resource(gvr).
 			Namespace(resource.Namespace).
-			Delete(context.TODO(), resource.Name, metav1.DeleteOptions{
+			Delete(ctx, resource.Name, metav1.DeleteOptions{
 				Preconditions: &metav1.Preconditions{
 					UID: &uid,
 				},
@@ -362,6 +366,33 @@ func ApplyOwnerReferences(ctx context.Context, dynamicClient dynamic.Interface,
 	if err != nil {
 		return fmt.Errorf("type %t cannot be accessed: %v", existing, err)
 	}
+	patchData, err := ownerReferencesPatch(accessor, requiredOwner)
+	if err != nil || patchData == nil {
+		return err
+	}
+
+	klog.V(2).Infof("Patching resource %v %s/%s with patch %s", gvr, accessor.GetNamespace(), accessor.GetName(), string(patchData))
+	_, err = dynamicClient.Resource(gvr).Namespace(accessor.GetNamespace()).Patch(ctx, accessor.GetName(), types.MergePatchType, patchData, metav1.PatchOptions{})
+	return err
+}
+
+// applyOwnerReferencesWithMetadataClient is the same as ApplyOwnerReferences, but patches the resource with the
+// metadata client.
+func applyOwnerReferencesWithMetadataClient(ctx context.Context, metadataClient metadata.Interface, gvr schema.GroupVersionResource,
+	existing metav1.Object, requiredOwner metav1.OwnerReference) error {
+	patchData, err := ownerReferencesPatch(existing, requiredOwner)
+	if err != nil || patchData == nil {
+		return err
+	}
+
+	klog.V(2).Infof("Patching resource %v %s/%s with patch %s", gvr, existing.GetNamespace(), existing.GetName(), string(patchData))
+	_, err = metadataClient.Resource(gvr).Namespace(existing.GetNamespace()).Patch(ctx, existing.GetName(), types.MergePatchType, patchData, metav1.PatchOptions{})
+	return err
+}
+
+// ownerReferencesPatch returns the merge patch to apply the required owner to the existing resource, it returns
+// nil if the owner references are not changed.
+func ownerReferencesPatch(accessor metav1.Object, requiredOwner metav1.OwnerReference) ([]byte, error) {
 	patch := &unstructured.Unstructured{}
 	patch.SetUID(accessor.GetUID())
 	patch.SetResourceVersion(accessor.GetResourceVersion())
@@ -373,17 +404,10 @@ func ApplyOwnerReferences(ctx context.Context, dynamicClient dynamic.Interface,
 	patch.SetOwnerReferences(patchedOwner)
 
 	if !modified {
-		return nil
-	}
-
-	patchData, err := json.Marshal(patch)
-	if err != nil {
-		return err
+		return nil, nil
 	}
 
-	klog.V(2).Infof("Patching resource %v %s/%s with patch %s", gvr, accessor.GetNamespace(), accessor.GetName(), string(patchData))
-	_, err = dynamicClient.Resource(gvr).Namespace(accessor.GetNamespace()).Patch(ctx, accessor.GetName(), types.MergePatchType, patchData, metav1.PatchOptions{})
-	return err
+	return json.Marshal(patch)
 }
 
 // OwnedByTheWork checks whether the manifest resource will be owned by the manifest work based on the deleteOption
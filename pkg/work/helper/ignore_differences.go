@@ -0,0 +1,137 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// ManifestWorkIgnoreDifferencesAnnotationKey is the annotation of a manifestwork declaring the fields of its
+// manifests which are changed on the managed cluster by others, e.g. the annotations added by mutating webhooks or
+// the replicas managed by a HPA, as a json list of IgnoreDifferencesRule. The agent keeps the values of the ignored
+// fields on the managed cluster instead of reverting them to the manifests. The status feedback still reads the
+// ignored fields, e.g. the replicas scaled by a HPA.
+// TODO move this to the api repo.
+const ManifestWorkIgnoreDifferencesAnnotationKey = "work.open-cluster-management.io/ignore-differences"
+
+// IgnoreDifferencesRule defines the ignored fields of a manifest of a manifestwork.
+type IgnoreDifferencesRule struct {
+	// ResourceIdentifier identifies the manifest in the manifestwork.
+	ResourceIdentifier workapiv1.ResourceIdentifier `json:"resourceIdentifier"`
+
+	// JSONPaths are the paths of the ignored fields, e.g. .spec.replicas or
+	// .metadata.annotations['example.com/key']. The fields in lists cannot be ignored.
+	JSONPaths []string `json:"jsonPaths"`
+}
+
+// GetIgnoreDifferencesRules returns the ignore differences rules of the manifestwork, nil is returned if there are
+// none.
+func GetIgnoreDifferencesRules(manifestWork *workapiv1.ManifestWork) ([]IgnoreDifferencesRule, error) {
+	value, ok := manifestWork.Annotations[ManifestWorkIgnoreDifferencesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var rules []IgnoreDifferencesRule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse the ignore differences rules: %w", err)
+	}
+	for _, rule := range rules {
+		for _, path := range rule.JSONPaths {
+			if _, err := ParseFieldPath(path); err != nil {
+				return nil, fmt.Errorf("invalid ignore differences rule of %v: %w", rule.ResourceIdentifier, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// FindIgnoredFields returns the ignored fields of the manifest by the rules, each field is the list of the keys to
+// the field. The rules must be validated by GetIgnoreDifferencesRules.
+func FindIgnoredFields(resourceMeta workapiv1.ManifestResourceMeta, rules []IgnoreDifferencesRule) [][]string {
+	identifier := workapiv1.ResourceIdentifier{
+		Group:     resourceMeta.Group,
+		Resource:  resourceMeta.Resource,
+		Namespace: resourceMeta.Namespace,
+		Name:      resourceMeta.Name,
+	}
+
+	var fields [][]string
+	for _, rule := range rules {
+		if rule.ResourceIdentifier != identifier {
+			continue
+		}
+		for _, path := range rule.JSONPaths {
+			if field, err := ParseFieldPath(path); err == nil {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// ParseFieldPath parses a path of a field in the form of .spec.replicas or .metadata.annotations['example.com/key']
+// to the list of the keys to the field.
+func ParseFieldPath(path string) ([]string, error) {
+	var field []string
+	rest := strings.TrimSpace(path)
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in the path %q", path)
+			}
+			field = append(field, rest[:end])
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated key in the path %q", path)
+			}
+			field = append(field, rest[2:2+end])
+			rest = rest[2+end+2:]
+		default:
+			return nil, fmt.Errorf("unsupported path %q, the keys must be in the form of .key or ['key']", path)
+		}
+	}
+	if len(field) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return field, nil
+}
+
+// RemoveFields removes the fields from the object.
+func RemoveFields(obj *unstructured.Unstructured, fields [][]string) {
+	for _, field := range fields {
+		unstructured.RemoveNestedField(obj.Object, field...)
+	}
+}
+
+// CopyFields sets the fields of the object to their values in the source object, the fields are removed from the
+// object if they are not in the source object.
+func CopyFields(source, obj *unstructured.Unstructured, fields [][]string) error {
+	for _, field := range fields {
+		value, found, err := unstructured.NestedFieldCopy(source.Object, field...)
+		if err != nil {
+			return err
+		}
+		if !found {
+			unstructured.RemoveNestedField(obj.Object, field...)
+			continue
+		}
+		if err := unstructured.SetNestedField(obj.Object, value, field...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
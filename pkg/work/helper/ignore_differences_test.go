@@ -0,0 +1,148 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestParseFieldPath(t *testing.T) {
+	cases := []struct {
+		path          string
+		expectedField []string
+		expectedErr   bool
+	}{
+		{path: ".spec.replicas", expectedField: []string{"spec", "replicas"}},
+		{path: ".metadata.annotations['example.com/key']", expectedField: []string{"metadata", "annotations", "example.com/key"}},
+		{path: `.metadata.labels["app.kubernetes.io/name"]`, expectedField: []string{"metadata", "labels", "app.kubernetes.io/name"}},
+		{path: ".spec.containers[0].image", expectedErr: true},
+		{path: "spec.replicas", expectedErr: true},
+		{path: ".spec..replicas", expectedErr: true},
+		{path: ".metadata.annotations['key", expectedErr: true},
+		{path: "", expectedErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			field, err := ParseFieldPath(c.path)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got field %v", field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(field, c.expectedField) {
+				t.Errorf("expected field %v, but got %v", c.expectedField, field)
+			}
+		})
+	}
+}
+
+func TestGetIgnoreDifferencesRules(t *testing.T) {
+	deployment := workapiv1.ResourceIdentifier{Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1"}
+	cases := []struct {
+		name           string
+		annotation     string
+		expectedFields [][]string
+		expectedErr    bool
+	}{
+		{
+			name: "no rules",
+		},
+		{
+			name: "rules of the manifest",
+			annotation: `[{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},` +
+				`"jsonPaths":[".spec.replicas"]},` +
+				`{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy2"},` +
+				`"jsonPaths":[".metadata.annotations['example.com/key']"]}]`,
+			expectedFields: [][]string{{"spec", "replicas"}},
+		},
+		{
+			name:        "invalid json",
+			annotation:  `{`,
+			expectedErr: true,
+		},
+		{
+			name: "invalid path",
+			annotation: `[{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},` +
+				`"jsonPaths":[".spec.containers[0].image"]}]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work := &workapiv1.ManifestWork{}
+			if len(c.annotation) > 0 {
+				work.Annotations = map[string]string{ManifestWorkIgnoreDifferencesAnnotationKey: c.annotation}
+			}
+			rules, err := GetIgnoreDifferencesRules(work)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fields := FindIgnoredFields(workapiv1.ManifestResourceMeta{
+				Group:     deployment.Group,
+				Version:   "v1",
+				Resource:  deployment.Resource,
+				Namespace: deployment.Namespace,
+				Name:      deployment.Name,
+			}, rules)
+			if !reflect.DeepEqual(fields, c.expectedFields) {
+				t.Errorf("expected fields %v, but got %v", c.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func TestCopyFields(t *testing.T) {
+	source := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy1")
+	source.SetAnnotations(map[string]string{"example.com/key": "mutated"})
+	_ = unstructured.SetNestedField(source.Object, int64(3), "spec", "replicas")
+
+	obj := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy1")
+	obj.SetAnnotations(map[string]string{"example.com/key": "value", "example.com/other": "value"})
+	_ = unstructured.SetNestedField(obj.Object, "Recreate", "spec", "strategy", "type")
+
+	fields := [][]string{
+		{"spec", "replicas"},
+		{"spec", "strategy"},
+		{"metadata", "annotations", "example.com/key"},
+	}
+	if err := CopyFields(source, obj, fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); replicas != 3 {
+		t.Errorf("expected the replicas are copied, but got %d", replicas)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "strategy"); found {
+		t.Errorf("expected the strategy missing in the source is removed")
+	}
+	expectedAnnotations := map[string]string{"example.com/key": "mutated", "example.com/other": "value"}
+	if !reflect.DeepEqual(obj.GetAnnotations(), expectedAnnotations) {
+		t.Errorf("expected annotations %v, but got %v", expectedAnnotations, obj.GetAnnotations())
+	}
+
+	RemoveFields(obj, fields)
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); found {
+		t.Errorf("expected the replicas are removed")
+	}
+	if !reflect.DeepEqual(obj.GetAnnotations(), map[string]string{"example.com/other": "value"}) {
+		t.Errorf("expected the annotation is removed, but got %v", obj.GetAnnotations())
+	}
+}
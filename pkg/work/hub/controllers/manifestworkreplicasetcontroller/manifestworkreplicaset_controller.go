@@ -11,6 +11,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/events"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
@@ -103,15 +104,7 @@ func NewManifestWorkReplicaSetController(
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaNamespaceName, manifestWorkReplicaSetInformer.Informer()).
 		WithFilteredEventsInformersQueueKeyFunc(func(obj runtime.Object) string {
 			accessor, _ := meta.Accessor(obj)
-			labelValue, ok := accessor.GetLabels()[ManifestWorkReplicaSetControllerNameLabelKey]
-			if !ok {
-				return ""
-			}
-			keys := strings.Split(labelValue, ".")
-			if len(keys) != 2 {
-				return ""
-			}
-			return fmt.Sprintf("%s/%s", keys[0], keys[1])
+			return ManifestWorkReplicaSetKey(accessor)
 		},
 			queue.FileterByLabel(ManifestWorkReplicaSetControllerNameLabelKey),
 			manifestWorkInformer.Informer()).
@@ -120,6 +113,20 @@ func NewManifestWorkReplicaSetController(
 		WithSync(controller.sync).ToController("ManifestWorkReplicaSetController", recorder)
 }
 
+// ManifestWorkReplicaSetKey returns the namespace/name key of the ManifestWorkReplicaSet of the ManifestWork from
+// its label, it returns empty if the ManifestWork is not created by a ManifestWorkReplicaSet.
+func ManifestWorkReplicaSetKey(work metav1.Object) string {
+	labelValue, ok := work.GetLabels()[ManifestWorkReplicaSetControllerNameLabelKey]
+	if !ok {
+		return ""
+	}
+	keys := strings.Split(labelValue, ".")
+	if len(keys) != 2 {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", keys[0], keys[1])
+}
+
 func newController(
 	workClient workclientset.Interface,
 	workApplier *workapplier.WorkApplier,
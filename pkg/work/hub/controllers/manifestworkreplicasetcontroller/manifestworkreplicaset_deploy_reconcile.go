@@ -3,7 +3,9 @@ package manifestworkreplicasetcontroller
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,12 +14,15 @@ import (
 
 	clusterlister "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
 	worklisterv1 "open-cluster-management.io/api/client/work/listers/work/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	workapiv1alpha1 "open-cluster-management.io/api/work/v1alpha1"
 	clustersdkv1alpha1 "open-cluster-management.io/sdk-go/pkg/apis/cluster/v1alpha1"
 	workapplier "open-cluster-management.io/sdk-go/pkg/apis/work/v1/applier"
 
 	"open-cluster-management.io/ocm/pkg/common/helpers"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 )
 
@@ -91,7 +96,8 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 			continue
 		}
 
-		_, rolloutResult, err := rolloutHandler.GetRolloutCluster(placementRef.RolloutStrategy, existingRolloutClsStatus)
+		rolloutStrategy := withCanaryDecisionGroups(placementRef.RolloutStrategy, placement)
+		_, rolloutResult, err := rolloutHandler.GetRolloutCluster(rolloutStrategy, existingRolloutClsStatus)
 
 		if err != nil {
 			errs = append(errs, err)
@@ -113,12 +119,15 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 					continue
 				}
 
-				_, err = d.workApplier.Apply(ctx, mw)
+				existing, _ := d.manifestWorkLister.ManifestWorks(mw.Namespace).Get(mw.Name)
+				start := time.Now()
+				applied, err := d.workApplier.Apply(ctx, mw)
 				if err != nil {
 					fmt.Printf("err is %v\n", err)
 					errs = append(errs, err)
 					continue
 				}
+				traceApplied(ctx, existing, applied, start)
 				existingClusterNames.Insert(rolloutStatue.ClusterName)
 			}
 		}
@@ -183,6 +192,16 @@ func (d *deployReconciler) reconcile(ctx context.Context, mwrSet *workapiv1alpha
 	return mwrSet, reconcileContinue, nil
 }
 
+// traceApplied records the span of creating or updating the manifestwork once its uid and generation are known, it
+// is skipped if the generation already existed before the apply.
+func traceApplied(ctx context.Context, existing, applied *workv1.ManifestWork, start time.Time) {
+	if existing != nil && existing.UID == applied.UID && existing.Generation == applied.Generation {
+		return
+	}
+	_, span := tracing.StartWorkSpan(ctx, "ApplyManifestWork", applied, trace.WithTimestamp(start))
+	span.End()
+}
+
 func (d *deployReconciler) clusterRolloutStatusFunc(clusterName string, manifestWork workv1.ManifestWork) (clustersdkv1alpha1.ClusterRolloutStatus, error) {
 	clsRolloutStatus := clustersdkv1alpha1.ClusterRolloutStatus{
 		ClusterName:        clusterName,
@@ -224,6 +243,39 @@ func (d *deployReconciler) clusterRolloutStatusFunc(clusterName string, manifest
 	return clsRolloutStatus, nil
 }
 
+// withCanaryDecisionGroups returns the rollout strategy with the canary decision groups of the placement as the
+// mandatory decision groups, if the strategy is progressive and no mandatory decision groups are set.
+func withCanaryDecisionGroups(strategy clusterv1alpha1.RolloutStrategy, placement *clusterv1beta1.Placement) clusterv1alpha1.RolloutStrategy {
+	canaryGroups := helpers.GetCanaryDecisionGroups(placement)
+	if len(canaryGroups) == 0 {
+		return strategy
+	}
+
+	var mandatoryDecisionGroups []clusterv1alpha1.MandatoryDecisionGroup
+	for _, name := range canaryGroups {
+		mandatoryDecisionGroups = append(mandatoryDecisionGroups, clusterv1alpha1.MandatoryDecisionGroup{GroupName: name})
+	}
+
+	strategy = *strategy.DeepCopy()
+	switch strategy.Type {
+	case clusterv1alpha1.Progressive:
+		if strategy.Progressive == nil {
+			strategy.Progressive = &clusterv1alpha1.RolloutProgressive{}
+		}
+		if len(strategy.Progressive.MandatoryDecisionGroups.MandatoryDecisionGroups) == 0 {
+			strategy.Progressive.MandatoryDecisionGroups.MandatoryDecisionGroups = mandatoryDecisionGroups
+		}
+	case clusterv1alpha1.ProgressivePerGroup:
+		if strategy.ProgressivePerGroup == nil {
+			strategy.ProgressivePerGroup = &clusterv1alpha1.RolloutProgressivePerGroup{}
+		}
+		if len(strategy.ProgressivePerGroup.MandatoryDecisionGroups.MandatoryDecisionGroups) == 0 {
+			strategy.ProgressivePerGroup.MandatoryDecisionGroups.MandatoryDecisionGroups = mandatoryDecisionGroups
+		}
+	}
+	return strategy
+}
+
 // GetManifestworkApplied return only True status if there all clusters have manifests applied as expected
 func GetManifestworkApplied(reason string, message string) metav1.Condition {
 	if reason == workapiv1alpha1.ReasonAsExpected {
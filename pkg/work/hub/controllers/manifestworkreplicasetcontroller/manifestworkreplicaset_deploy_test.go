@@ -629,3 +629,35 @@ func TestRequeueWithProgressDeadline(t *testing.T) {
 		t.Errorf("expect to get err %t", err)
 	}
 }
+
+func TestWithCanaryDecisionGroups(t *testing.T) {
+	placement, _ := helpertest.CreateTestPlacement("place-test", "default", "cls1")
+	placement.Annotations = map[string]string{helpers.CanaryDecisionGroupsAnnotation: "canary"}
+	canaryGroups := []clusterv1alpha1.MandatoryDecisionGroup{{GroupName: "canary"}}
+
+	strategy := withCanaryDecisionGroups(clusterv1alpha1.RolloutStrategy{Type: clusterv1alpha1.All}, placement)
+	assert.Nil(t, strategy.Progressive)
+	assert.Nil(t, strategy.ProgressivePerGroup)
+
+	strategy = withCanaryDecisionGroups(clusterv1alpha1.RolloutStrategy{Type: clusterv1alpha1.Progressive}, placement)
+	assert.Equal(t, canaryGroups, strategy.Progressive.MandatoryDecisionGroups.MandatoryDecisionGroups)
+
+	strategy = withCanaryDecisionGroups(clusterv1alpha1.RolloutStrategy{Type: clusterv1alpha1.ProgressivePerGroup}, placement)
+	assert.Equal(t, canaryGroups, strategy.ProgressivePerGroup.MandatoryDecisionGroups.MandatoryDecisionGroups)
+
+	// the mandatory decision groups set in the rollout strategy are kept
+	mandatoryGroups := []clusterv1alpha1.MandatoryDecisionGroup{{GroupIndex: 1}}
+	original := clusterv1alpha1.RolloutStrategy{
+		Type: clusterv1alpha1.Progressive,
+		Progressive: &clusterv1alpha1.RolloutProgressive{
+			MandatoryDecisionGroups: clusterv1alpha1.MandatoryDecisionGroups{MandatoryDecisionGroups: mandatoryGroups},
+		},
+	}
+	strategy = withCanaryDecisionGroups(original, placement)
+	assert.Equal(t, mandatoryGroups, strategy.Progressive.MandatoryDecisionGroups.MandatoryDecisionGroups)
+
+	// the placement without canary groups does not change the rollout strategy
+	placement.Annotations = nil
+	strategy = withCanaryDecisionGroups(clusterv1alpha1.RolloutStrategy{Type: clusterv1alpha1.Progressive}, placement)
+	assert.Nil(t, strategy.Progressive)
+}
@@ -0,0 +1,118 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/registration/hub/user"
+)
+
+var manifestWorksResource = schema.GroupResource{Group: workv1.GroupName, Resource: "manifestworks"}
+
+// authenticate returns the user of the request. The user is taken from the verified client certificate if there is
+// one, otherwise the bearer token of the request is reviewed by the kube apiserver. The X509-SVID of the agents is
+// mapped to the user of the managed cluster identified by its SPIFFE ID.
+func (s *Server) authenticate(r *http.Request) (*authenticationv1.UserInfo, error) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		cert := r.TLS.VerifiedChains[0][0]
+		if len(s.spiffeTrustDomain) > 0 && len(cert.URIs) == 1 && cert.URIs[0].Scheme == user.SPIFFEScheme {
+			username, groups, err := user.ClusterUserFromSPIFFEID(s.spiffeTrustDomain, cert.URIs[0])
+			if err != nil {
+				return nil, apierrors.NewUnauthorized(err.Error())
+			}
+			return &authenticationv1.UserInfo{
+				Username: username,
+				Groups:   append(groups, "system:authenticated"),
+			}, nil
+		}
+		if len(cert.Subject.CommonName) == 0 {
+			return nil, apierrors.NewUnauthorized("the client certificate has no common name")
+		}
+		return &authenticationv1.UserInfo{
+			Username: cert.Subject.CommonName,
+			Groups:   append(slices.Clone(cert.Subject.Organization), "system:authenticated"),
+		}, nil
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(strings.TrimSpace(token)) == 0 {
+		return nil, apierrors.NewUnauthorized("a bearer token or a client certificate is required")
+	}
+
+	review, err := s.kubeClient.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimSpace(token)},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to review the token: %v", err))
+	}
+	if !review.Status.Authenticated {
+		return nil, apierrors.NewUnauthorized(fmt.Sprintf("invalid bearer token: %s", review.Status.Error))
+	}
+	return &review.Status.User, nil
+}
+
+// authorize checks if the user is allowed to take the verb on the manifestworks of the cluster namespace with a
+// SubjectAccessReview, so the gateway grants the same permissions as the kube apiserver.
+func (s *Server) authorize(r *http.Request, userInfo *authenticationv1.UserInfo, verb, cluster, name string) error {
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.Extra))
+	for k, v := range userInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	sar, err := s.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:     manifestWorksResource.Group,
+				Resource:  manifestWorksResource.Resource,
+				Verb:      verb,
+				Namespace: cluster,
+				Name:      name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to review the access: %v", err))
+	}
+	if !sar.Status.Allowed {
+		return apierrors.NewForbidden(manifestWorksResource, name,
+			fmt.Errorf("user %q cannot %s manifestworks in the cluster %q", userInfo.Username, verb, cluster))
+	}
+	return nil
+}
+
+// withAuth wraps the handler so the request is authenticated and authorized before it is handled.
+func (s *Server) withAuth(verb string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cluster, ok := clusterName(w, r)
+		if !ok {
+			return
+		}
+
+		userInfo, err := s.authenticate(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.authorize(r, userInfo, verb, cluster, r.PathValue("name")); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		handler(w, r)
+	}
+}
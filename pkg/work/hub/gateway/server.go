@@ -0,0 +1,326 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/fips"
+)
+
+const (
+	// maxRequestBodyBytes is the max size of the request body, it is aligned with the max request size of the
+	// kube apiserver.
+	maxRequestBodyBytes = 3 * 1024 * 1024
+
+	shutdownTimeout = 10 * time.Second
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Options defines the options of the work gateway.
+type Options struct {
+	// BindAddress is the address that the gateway listens on, the gateway is disabled if it is empty.
+	BindAddress string
+	// CertFile and KeyFile are the serving certificate and key of the gateway, they are required when the gateway
+	// is enabled.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the CA bundle to verify the client certificates. The clients can authenticate with a valid
+	// certificate if it is set, otherwise they authenticate with a bearer token.
+	ClientCAFile string
+	// ClientSPIFFETrustDomain is the trust domain of the X509-SVIDs of the agents, a client certificate with the
+	// SPIFFE ID spiffe://<trust domain>/open-cluster-management/cluster/<cluster name> authenticates as the agents of
+	// the managed cluster. The SPIFFE IDs are not mapped if it is empty.
+	ClientSPIFFETrustDomain string
+}
+
+// Validate verifies the gateway options.
+func (o *Options) Validate() error {
+	if len(o.BindAddress) == 0 {
+		return nil
+	}
+	if len(o.CertFile) == 0 || len(o.KeyFile) == 0 {
+		return fmt.Errorf("the serving certificate and key of the work gateway are required")
+	}
+	return nil
+}
+
+// Server is an HTTP gateway in front of the work client. The non-Kubernetes systems (e.g. ticketing systems or
+// pipelines) can create/patch/delete the ManifestWorks and read their status per cluster with it, without
+// speaking CloudEvents or kube APIs. The API is described by the OpenAPI document served at /openapi.json.
+//
+// The requests are authenticated with a client certificate or a bearer token (TokenReview), and authorized against
+// the manifestworks of the cluster namespace (SubjectAccessReview) on the hub.
+type Server struct {
+	workClient workclientset.Interface
+	kubeClient kubernetes.Interface
+	mux        *http.ServeMux
+	// spiffeTrustDomain is the trust domain of the SPIFFE IDs mapped to the managed clusters
+	spiffeTrustDomain string
+}
+
+// NewServer returns a gateway server with the given work client, the kube client is used to review the tokens and
+// the access of the requests.
+func NewServer(workClient workclientset.Interface, kubeClient kubernetes.Interface) *Server {
+	s := &Server{
+		workClient: workClient,
+		kubeClient: kubeClient,
+		mux:        http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /openapi.json", s.openAPI)
+	s.mux.HandleFunc("GET /api/v1/clusters/{cluster}/manifestworks", s.withAuth("list", s.listWorks))
+	s.mux.HandleFunc("POST /api/v1/clusters/{cluster}/manifestworks", s.withAuth("create", s.createWork))
+	s.mux.HandleFunc("GET /api/v1/clusters/{cluster}/manifestworks/{name}", s.withAuth("get", s.getWork))
+	s.mux.HandleFunc("PATCH /api/v1/clusters/{cluster}/manifestworks/{name}", s.withAuth("patch", s.patchWork))
+	s.mux.HandleFunc("DELETE /api/v1/clusters/{cluster}/manifestworks/{name}", s.withAuth("delete", s.deleteWork))
+	s.mux.HandleFunc("GET /api/v1/clusters/{cluster}/manifestworks/{name}/status", s.withAuth("get", s.getWorkStatus))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Run starts the gateway on TLS and blocks until the context is done. It returns the error if the gateway fails to
+// serve.
+func (s *Server) Run(ctx context.Context, opts *Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	s.spiffeTrustDomain = opts.ClientSPIFFETrustDomain
+	server := &http.Server{
+		Addr:              opts.BindAddress,
+		Handler:           s,
+		ReadHeaderTimeout: 30 * time.Second,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+
+	if len(opts.ClientCAFile) != 0 {
+		caData, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs := x509.NewCertPool()
+		if ok := clientCAs.AppendCertsFromPEM(caData); !ok {
+			return fmt.Errorf("invalid client CA %s", opts.ClientCAFile)
+		}
+		// the clients without a certificate authenticate with a bearer token
+		server.TLSConfig.ClientCAs = clientCAs
+		server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	fips.ConfigureTLS(server.TLSConfig)
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("failed to shutdown the work gateway, %v", err)
+		}
+	}()
+
+	klog.Infof("Starting the work gateway on %s", opts.BindAddress)
+
+	err := server.ListenAndServeTLS(opts.CertFile, opts.KeyFile)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(openAPISpec); err != nil {
+		klog.Errorf("failed to write the openapi spec, %v", err)
+	}
+}
+
+func (s *Server) listWorks(w http.ResponseWriter, r *http.Request) {
+	cluster, ok := clusterName(w, r)
+	if !ok {
+		return
+	}
+
+	works, err := s.workClient.WorkV1().ManifestWorks(cluster).List(r.Context(), metav1.ListOptions{
+		LabelSelector: r.URL.Query().Get("labelSelector"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeObject(w, http.StatusOK, works)
+}
+
+func (s *Server) createWork(w http.ResponseWriter, r *http.Request) {
+	cluster, ok := clusterName(w, r)
+	if !ok {
+		return
+	}
+
+	work := &workv1.ManifestWork{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(work); err != nil {
+		writeError(w, apierrors.NewBadRequest(fmt.Sprintf("failed to decode the manifestwork: %v", err)))
+		return
+	}
+
+	if len(work.Namespace) != 0 && work.Namespace != cluster {
+		writeError(w, apierrors.NewBadRequest(fmt.Sprintf(
+			"the namespace %q of the manifestwork does not match the cluster %q", work.Namespace, cluster)))
+		return
+	}
+	work.Namespace = cluster
+
+	created, err := s.workClient.WorkV1().ManifestWorks(cluster).Create(r.Context(), work, metav1.CreateOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeObject(w, http.StatusCreated, created)
+}
+
+func (s *Server) getWork(w http.ResponseWriter, r *http.Request) {
+	work, ok := s.work(w, r)
+	if !ok {
+		return
+	}
+
+	writeObject(w, http.StatusOK, work)
+}
+
+func (s *Server) getWorkStatus(w http.ResponseWriter, r *http.Request) {
+	work, ok := s.work(w, r)
+	if !ok {
+		return
+	}
+
+	writeObject(w, http.StatusOK, work.Status)
+}
+
+func (s *Server) patchWork(w http.ResponseWriter, r *http.Request) {
+	cluster, ok := clusterName(w, r)
+	if !ok {
+		return
+	}
+
+	var patchType types.PatchType
+	switch contentType := r.Header.Get("Content-Type"); types.PatchType(contentType) {
+	case types.MergePatchType, types.JSONPatchType:
+		patchType = types.PatchType(contentType)
+	default:
+		writeError(w, &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnsupportedMediaType,
+			Reason:  metav1.StatusReasonUnsupportedMediaType,
+			Message: fmt.Sprintf("unsupported patch type %q", contentType),
+		}})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	if err != nil {
+		writeError(w, apierrors.NewBadRequest(fmt.Sprintf("failed to read the patch: %v", err)))
+		return
+	}
+
+	patched, err := s.workClient.WorkV1().ManifestWorks(cluster).Patch(
+		r.Context(), r.PathValue("name"), patchType, data, metav1.PatchOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeObject(w, http.StatusOK, patched)
+}
+
+func (s *Server) deleteWork(w http.ResponseWriter, r *http.Request) {
+	cluster, ok := clusterName(w, r)
+	if !ok {
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := s.workClient.WorkV1().ManifestWorks(cluster).Delete(r.Context(), name, metav1.DeleteOptions{}); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeObject(w, http.StatusOK, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusSuccess,
+		Code:     http.StatusOK,
+		Message:  fmt.Sprintf("manifestwork %s/%s is deleting", cluster, name),
+	})
+}
+
+func (s *Server) work(w http.ResponseWriter, r *http.Request) (*workv1.ManifestWork, bool) {
+	cluster, ok := clusterName(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	work, err := s.workClient.WorkV1().ManifestWorks(cluster).Get(r.Context(), r.PathValue("name"), metav1.GetOptions{})
+	if err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+
+	return work, true
+}
+
+func clusterName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	cluster := r.PathValue("cluster")
+	if errs := apimachineryvalidation.ValidateNamespaceName(cluster, false); len(errs) > 0 {
+		writeError(w, apierrors.NewBadRequest(fmt.Sprintf("invalid cluster name %q: %v", cluster, errs)))
+		return "", false
+	}
+	return cluster, true
+}
+
+func writeObject(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.Errorf("failed to write the response, %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var status metav1.Status
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		status = apiStatus.Status()
+	} else {
+		status = apierrors.NewInternalError(err).Status()
+	}
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+
+	code := int(status.Code)
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	writeObject(w, code, &status)
+}
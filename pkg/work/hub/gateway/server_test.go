@@ -0,0 +1,331 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newWork(namespace, name string) *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Status: workv1.ManifestWorkStatus{
+			Conditions: []metav1.Condition{
+				{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, Reason: "AppliedManifestWorkComplete"},
+			},
+		},
+	}
+}
+
+// newKubeClient returns a kube client that authenticates the token "valid-token" as the user "admin" and allows
+// the user to access the manifestworks of the allowed namespace.
+func newKubeClient(allowedNamespace string) *kubefake.Clientset {
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "valid-token" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "admin"},
+			}
+		}
+		return true, review, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.User == "admin" && sar.Spec.ResourceAttributes.Namespace == allowedNamespace &&
+			sar.Spec.ResourceAttributes.Resource == "manifestworks"
+		return true, sar, nil
+	})
+	return kubeClient
+}
+
+func TestServer(t *testing.T) {
+	cases := []struct {
+		name         string
+		method       string
+		path         string
+		token        string
+		contentType  string
+		body         string
+		works        []runtime.Object
+		expectedCode int
+		validate     func(t *testing.T, body []byte)
+	}{
+		{
+			name:         "get openapi spec",
+			method:       http.MethodGet,
+			path:         "/openapi.json",
+			expectedCode: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				spec := map[string]interface{}{}
+				if err := json.Unmarshal(body, &spec); err != nil {
+					t.Errorf("invalid openapi spec: %v", err)
+				}
+			},
+		},
+		{
+			name:         "list works",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/cluster1/manifestworks",
+			works:        []runtime.Object{newWork("cluster1", "work1"), newWork("cluster2", "work2")},
+			expectedCode: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				works := &workv1.ManifestWorkList{}
+				if err := json.Unmarshal(body, works); err != nil {
+					t.Fatal(err)
+				}
+				if len(works.Items) != 1 || works.Items[0].Name != "work1" {
+					t.Errorf("unexpected works %v", works.Items)
+				}
+			},
+		},
+		{
+			name:         "list works without token",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/cluster1/manifestworks",
+			token:        "-",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "list works with invalid token",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/cluster1/manifestworks",
+			token:        "invalid-token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "list works of forbidden cluster",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/cluster2/manifestworks",
+			works:        []runtime.Object{newWork("cluster2", "work2")},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "invalid cluster name",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/Cluster_1/manifestworks",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "create work",
+			method:       http.MethodPost,
+			path:         "/api/v1/clusters/cluster1/manifestworks",
+			body:         `{"metadata":{"name":"work1"},"spec":{}}`,
+			expectedCode: http.StatusCreated,
+			validate: func(t *testing.T, body []byte) {
+				work := &workv1.ManifestWork{}
+				if err := json.Unmarshal(body, work); err != nil {
+					t.Fatal(err)
+				}
+				if work.Namespace != "cluster1" || work.Name != "work1" {
+					t.Errorf("unexpected work %v", work)
+				}
+			},
+		},
+		{
+			name:         "create work in another namespace",
+			method:       http.MethodPost,
+			path:         "/api/v1/clusters/cluster1/manifestworks",
+			body:         `{"metadata":{"name":"work1","namespace":"cluster2"},"spec":{}}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "create existing work",
+			method:       http.MethodPost,
+			path:         "/api/v1/clusters/cluster1/manifestworks",
+			body:         `{"metadata":{"name":"work1"},"spec":{}}`,
+			works:        []runtime.Object{newWork("cluster1", "work1")},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "get work status",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/cluster1/manifestworks/work1/status",
+			works:        []runtime.Object{newWork("cluster1", "work1")},
+			expectedCode: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				status := &workv1.ManifestWorkStatus{}
+				if err := json.Unmarshal(body, status); err != nil {
+					t.Fatal(err)
+				}
+				if len(status.Conditions) != 1 {
+					t.Errorf("unexpected status %v", status)
+				}
+			},
+		},
+		{
+			name:         "get nonexistent work",
+			method:       http.MethodGet,
+			path:         "/api/v1/clusters/cluster1/manifestworks/work1",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "patch work",
+			method:       http.MethodPatch,
+			path:         "/api/v1/clusters/cluster1/manifestworks/work1",
+			contentType:  "application/merge-patch+json",
+			body:         `{"metadata":{"labels":{"test":"true"}}}`,
+			works:        []runtime.Object{newWork("cluster1", "work1")},
+			expectedCode: http.StatusOK,
+			validate: func(t *testing.T, body []byte) {
+				work := &workv1.ManifestWork{}
+				if err := json.Unmarshal(body, work); err != nil {
+					t.Fatal(err)
+				}
+				if work.Labels["test"] != "true" {
+					t.Errorf("unexpected work %v", work)
+				}
+			},
+		},
+		{
+			name:         "patch work with unsupported patch type",
+			method:       http.MethodPatch,
+			path:         "/api/v1/clusters/cluster1/manifestworks/work1",
+			contentType:  "application/json",
+			body:         `{}`,
+			works:        []runtime.Object{newWork("cluster1", "work1")},
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:         "delete work",
+			method:       http.MethodDelete,
+			path:         "/api/v1/clusters/cluster1/manifestworks/work1",
+			works:        []runtime.Object{newWork("cluster1", "work1")},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			workClient := fakeworkclient.NewSimpleClientset(c.works...)
+			server := NewServer(workClient, newKubeClient("cluster1"))
+
+			req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			switch c.token {
+			case "":
+				req.Header.Set("Authorization", "Bearer valid-token")
+			case "-":
+			default:
+				req.Header.Set("Authorization", "Bearer "+c.token)
+			}
+			if len(c.contentType) != 0 {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			if recorder.Code != c.expectedCode {
+				t.Fatalf("expected code %d, but got %d: %s", c.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if c.validate != nil {
+				c.validate(t, recorder.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestAuthenticateSPIFFE(t *testing.T) {
+	cases := []struct {
+		name           string
+		spiffeID       string
+		expectedUser   string
+		expectedGroups []string
+		expectedErr    bool
+	}{
+		{
+			name:           "SPIFFE ID of a cluster",
+			spiffeID:       "spiffe://example.org/open-cluster-management/cluster/cluster1",
+			expectedUser:   "spiffe://example.org/open-cluster-management/cluster/cluster1",
+			expectedGroups: []string{"system:open-cluster-management:cluster1", "system:open-cluster-management:managed-clusters", "system:authenticated"},
+		},
+		{
+			name:        "SPIFFE ID in another trust domain",
+			spiffeID:    "spiffe://example.com/open-cluster-management/cluster/cluster1",
+			expectedErr: true,
+		},
+		{
+			name:        "SPIFFE ID of a workload",
+			spiffeID:    "spiffe://example.org/ns/default/sa/default",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			id, err := url.Parse(c.spiffeID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := NewServer(fakeworkclient.NewSimpleClientset(), newKubeClient("cluster1"))
+			server.spiffeTrustDomain = "example.org"
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/clusters/cluster1/manifestworks", nil)
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{URIs: []*url.URL{id}}}}}
+
+			userInfo, err := server.authenticate(req)
+			if c.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, but got user %v", userInfo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if userInfo.Username != c.expectedUser {
+				t.Errorf("expected user %q, but got %q", c.expectedUser, userInfo.Username)
+			}
+			if strings.Join(userInfo.Groups, ",") != strings.Join(c.expectedGroups, ",") {
+				t.Errorf("expected groups %v, but got %v", c.expectedGroups, userInfo.Groups)
+			}
+		})
+	}
+}
+
+func TestAuthenticateClientCert(t *testing.T) {
+	organization := make([]string, 1, 2)
+	organization[0] = "system:open-cluster-management:cluster1"
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "agent", Organization: organization}}
+
+	server := NewServer(fakeworkclient.NewSimpleClientset(), newKubeClient("cluster1"))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/clusters/cluster1/manifestworks", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	userInfo, err := server.authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if userInfo.Username != "agent" {
+		t.Errorf("expected user agent, but got %q", userInfo.Username)
+	}
+	expectedGroups := []string{"system:open-cluster-management:cluster1", "system:authenticated"}
+	if strings.Join(userInfo.Groups, ",") != strings.Join(expectedGroups, ",") {
+		t.Errorf("expected groups %v, but got %v", expectedGroups, userInfo.Groups)
+	}
+
+	// the groups of the user do not share the backing array of the organization of the certificate
+	userInfo.Groups[0] = "changed"
+	if cert.Subject.Organization[0] != "system:open-cluster-management:cluster1" || organization[:2][1] != "" {
+		t.Errorf("expected the organization of the certificate is not changed, but got %v", organization[:2])
+	}
+}
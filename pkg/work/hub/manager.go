@@ -2,10 +2,13 @@ package hub
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 
 	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
@@ -13,13 +16,16 @@ import (
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
 	workv1informer "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	workv1alpha1informer "open-cluster-management.io/api/client/work/informers/externalversions/work/v1alpha1"
 	workapplier "open-cluster-management.io/sdk-go/pkg/apis/work/v1/applier"
-	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
-	"open-cluster-management.io/sdk-go/pkg/cloudevents/work"
-	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
 
+	"open-cluster-management.io/ocm/pkg/common/sharding"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
+	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/work/hub/controllers/manifestworkreplicasetcontroller"
+	"open-cluster-management.io/ocm/pkg/work/hub/gateway"
+	"open-cluster-management.io/ocm/pkg/work/source"
 )
 
 const sourceID = "mwrsctrl"
@@ -38,7 +44,35 @@ func NewWorkHubManagerConfig(opts *WorkHubManagerOptions) *WorkHubManagerConfig
 
 // RunWorkHubManager starts the controllers on hub.
 func (c *WorkHubManagerConfig) RunWorkHubManager(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
-	hubClusterClient, err := clusterclientset.NewForConfig(controllerContext.KubeConfig)
+	if err := features.SetupWatchListClient(); err != nil {
+		return err
+	}
+
+	shutdownTracing, err := c.workOptions.TracingOptions.Setup(
+		ctx, "work-manager", tracing.WorkDriverKey.String(c.workOptions.WorkDriver))
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	if err := c.workOptions.ShardingOptions.Validate(); err != nil {
+		return err
+	}
+	kubeConfig := controllerContext.KubeConfig
+	var sharder *sharding.Sharder
+	if c.workOptions.ShardingOptions.Enabled() {
+		kubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+		if err != nil {
+			return err
+		}
+		sharder = sharding.NewSharder(
+			kubeClient, controllerContext.OperatorNamespace, "work-manager", c.workOptions.ShardingOptions)
+		// the writes of the controllers are fenced by the shard lease of the replica, the ManifestWorks published
+		// with the cloudevents drivers are not fenced.
+		kubeConfig = sharder.Fence(controllerContext.KubeConfig)
+	}
+
+	hubClusterClient, err := clusterclientset.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
@@ -46,11 +80,21 @@ func (c *WorkHubManagerConfig) RunWorkHubManager(ctx context.Context, controller
 	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(hubClusterClient, 30*time.Minute)
 
 	// build a hub work client for ManifestWorkReplicaSets
-	replicaSetsClient, err := workclientset.NewForConfig(controllerContext.KubeConfig)
+	replicaSetsClient, err := workclientset.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
 
+	replicaSetSelector, err := labels.Parse(c.workOptions.ManifestWorkReplicaSetSelector)
+	if err != nil {
+		return fmt.Errorf("invalid ManifestWorkReplicaSet selector %q: %v", c.workOptions.ManifestWorkReplicaSetSelector, err)
+	}
+	replicaSetInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(replicaSetsClient, 30*time.Minute,
+		workinformers.WithTweakListOptions(func(listOptions *metav1.ListOptions) {
+			listOptions.LabelSelector = replicaSetSelector.String()
+		}))
+	replicaSetInformer := replicaSetInformerFactory.Work().V1alpha1().ManifestWorkReplicaSets()
+
 	// we need a separated filtered manifestwork informers so we only watch the manifestworks that manifestworkreplicaset cares.
 	// This could reduce a lot of memory consumptions
 	workInformOption := workinformers.WithTweakListOptions(
@@ -71,12 +115,15 @@ func (c *WorkHubManagerConfig) RunWorkHubManager(ctx context.Context, controller
 	var watcherStore *store.SourceInformerWatcherStore
 
 	if c.workOptions.WorkDriver == "kube" {
-		config := controllerContext.KubeConfig
+		config := kubeConfig
 		if c.workOptions.WorkDriverConfig != "" {
 			config, err = clientcmd.BuildConfigFromFlags("", c.workOptions.WorkDriverConfig)
 			if err != nil {
 				return err
 			}
+			if sharder != nil {
+				config = sharder.Fence(config)
+			}
 		}
 
 		workClient, err = workclientset.NewForConfig(config)
@@ -89,24 +136,14 @@ func (c *WorkHubManagerConfig) RunWorkHubManager(ctx context.Context, controller
 		// Refer to Event Based Manifestwork proposal in enhancements repo to get more details.
 
 		watcherStore = store.NewSourceInformerWatcherStore(ctx)
-
-		_, config, err := generic.NewConfigLoader(c.workOptions.WorkDriver, c.workOptions.WorkDriverConfig).
-			LoadConfig()
-		if err != nil {
+		sourceOptions := c.sourceOptions()
+		if err := sourceOptions.Validate(); err != nil {
 			return err
 		}
-
-		clientHolder, err := work.NewClientHolderBuilder(config).
-			WithClientID(c.workOptions.CloudEventsClientID).
-			WithSourceID(sourceID).
-			WithCodecs(codec.NewManifestBundleCodec()).
-			WithWorkClientWatcherStore(watcherStore).
-			NewSourceClientHolder(ctx)
+		workClient, err = source.NewWorkClientSet(ctx, sourceOptions, watcherStore)
 		if err != nil {
 			return err
 		}
-
-		workClient = clientHolder.WorkInterface()
 	}
 
 	factory := workinformers.NewSharedInformerFactoryWithOptions(workClient, 30*time.Minute, workInformOption)
@@ -117,14 +154,53 @@ func (c *WorkHubManagerConfig) RunWorkHubManager(ctx context.Context, controller
 		watcherStore.SetStore(informer.Informer().GetStore())
 	}
 
-	return RunControllerManagerWithInformers(
+	// every event source of the controllers is filtered, so the ManifestWorkReplicaSets owned by the other shards
+	// are not reconciled by this replica.
+	if sharder != nil {
+		replicaSetInformer = sharding.NewManifestWorkReplicaSetInformer(replicaSetInformer, sharder)
+		informer = sharding.NewKeyedInformer(informer.Informer(), informer.Lister(), sharder,
+			manifestworkreplicasetcontroller.ManifestWorkReplicaSetKey)
+		go sharder.Run(ctx)
+	}
+
+	// the manager stops with the error of the gateway if the gateway fails to serve
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	gatewayErr := make(chan error, 1)
+	if len(c.workOptions.GatewayOptions.BindAddress) != 0 {
+		if err := c.workOptions.GatewayOptions.Validate(); err != nil {
+			return err
+		}
+		kubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := gateway.NewServer(workClient, kubeClient).Run(ctx, c.workOptions.GatewayOptions); err != nil {
+				gatewayErr <- fmt.Errorf("failed to run the work gateway: %w", err)
+				cancel()
+			}
+		}()
+	}
+
+	if err := RunControllerManagerWithInformers(
 		ctx,
 		controllerContext,
 		replicaSetsClient,
 		workClient,
+		replicaSetInformer,
 		informer,
 		clusterInformerFactory,
-	)
+	); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-gatewayErr:
+		return err
+	default:
+		return nil
+	}
 }
 
 func RunControllerManagerWithInformers(
@@ -132,23 +208,22 @@ func RunControllerManagerWithInformers(
 	controllerContext *controllercmd.ControllerContext,
 	replicaSetClient workclientset.Interface,
 	workClient workclientset.Interface,
+	replicaSetInformer workv1alpha1informer.ManifestWorkReplicaSetInformer,
 	workInformer workv1informer.ManifestWorkInformer,
 	clusterInformers clusterinformers.SharedInformerFactory,
 ) error {
-	replicaSetInformerFactory := workinformers.NewSharedInformerFactory(replicaSetClient, 30*time.Minute)
-
 	manifestWorkReplicaSetController := manifestworkreplicasetcontroller.NewManifestWorkReplicaSetController(
 		controllerContext.EventRecorder,
 		replicaSetClient,
 		workapplier.NewWorkApplierWithTypedClient(workClient, workInformer.Lister()),
-		replicaSetInformerFactory.Work().V1alpha1().ManifestWorkReplicaSets(),
+		replicaSetInformer,
 		workInformer,
 		clusterInformers.Cluster().V1beta1().Placements(),
 		clusterInformers.Cluster().V1beta1().PlacementDecisions(),
 	)
 
 	go clusterInformers.Start(ctx.Done())
-	go replicaSetInformerFactory.Start(ctx.Done())
+	go replicaSetInformer.Informer().Run(ctx.Done())
 	go manifestWorkReplicaSetController.Run(ctx, 5)
 
 	go workInformer.Informer().Run(ctx.Done())
@@ -156,3 +231,17 @@ func RunControllerManagerWithInformers(
 	<-ctx.Done()
 	return nil
 }
+
+// sourceOptions returns the options of the work source client publishing the ManifestWorks of the
+// ManifestWorkReplicaSets with the cloudevents work drivers.
+func (c *WorkHubManagerConfig) sourceOptions() *source.Options {
+	return &source.Options{
+		SourceID:             sourceID,
+		WorkDriver:           c.workOptions.WorkDriver,
+		WorkDriverConfig:     c.workOptions.WorkDriverConfig,
+		ClientID:             c.workOptions.CloudEventsClientID,
+		EncryptionKeyDir:     c.workOptions.CloudEventsEncryptionKeyDir,
+		DeltaSpec:            c.workOptions.CloudEventsDeltaSpec,
+		GRPCTransportOptions: c.workOptions.GRPCTransportOptions,
+	}
+}
@@ -79,3 +79,32 @@ var _ = ginkgo.Describe("start hub manager", func() {
 		stopHub()
 	})
 })
+
+func TestSourceOptions(t *testing.T) {
+	opts := NewWorkHubManagerOptions()
+	opts.WorkDriver = "mqtt"
+	opts.WorkDriverConfig = "/etc/work/mqtt-config.yaml"
+	opts.CloudEventsEncryptionKeyDir = "/etc/work/keys"
+	opts.CloudEventsDeltaSpec = true
+
+	sourceOptions := NewWorkHubManagerConfig(opts).sourceOptions()
+	if err := sourceOptions.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if sourceOptions.SourceID != sourceID {
+		t.Errorf("expected source id %s, but got %s", sourceID, sourceOptions.SourceID)
+	}
+	if sourceOptions.WorkDriver != opts.WorkDriver || sourceOptions.WorkDriverConfig != opts.WorkDriverConfig {
+		t.Errorf("unexpected work driver %s with config %s", sourceOptions.WorkDriver, sourceOptions.WorkDriverConfig)
+	}
+	if sourceOptions.EncryptionKeyDir != opts.CloudEventsEncryptionKeyDir || !sourceOptions.DeltaSpec {
+		t.Errorf("unexpected cloudevents options %v", sourceOptions)
+	}
+	if sourceOptions.GRPCTransportOptions != opts.GRPCTransportOptions {
+		t.Errorf("expected the grpc transport options of the manager")
+	}
+	// the client id is generated for each replica if it is not set
+	if len(sourceOptions.ClientID) != 0 {
+		t.Errorf("expected empty client id, but got %s", sourceOptions.ClientID)
+	}
+}
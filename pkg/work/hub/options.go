@@ -2,6 +2,11 @@ package hub
 
 import (
 	"github.com/spf13/pflag"
+
+	"open-cluster-management.io/ocm/pkg/common/cloudevents"
+	"open-cluster-management.io/ocm/pkg/common/sharding"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
+	"open-cluster-management.io/ocm/pkg/work/hub/gateway"
 )
 
 // WorkHubManagerOptions defines the flags for work hub manager
@@ -10,11 +15,38 @@ type WorkHubManagerOptions struct {
 	WorkDriverConfig string
 
 	CloudEventsClientID string
+
+	CloudEventsEncryptionKeyDir string
+
+	// CloudEventsDeltaSpec sends the spec of the works as JSON patches when only a small part of the spec changes,
+	// the agents must enable it as well.
+	CloudEventsDeltaSpec bool
+
+	// GRPCTransportOptions configures the compression, the message sizes and the keepalive of the grpc connection
+	// when the work driver is grpc.
+	GRPCTransportOptions *cloudevents.GRPCTransportOptions
+
+	// ManifestWorkReplicaSetSelector is the label selector to scope the ManifestWorkReplicaSets watched by the
+	// controllers.
+	ManifestWorkReplicaSetSelector string
+
+	// ShardingOptions runs the controllers in multiple replicas, the ManifestWorkReplicaSets are assigned to the
+	// shards by the hash of their namespace/name whatever the strategy is.
+	ShardingOptions *sharding.Options
+
+	// TracingOptions exports the spans of the manifestworks created and updated by the controllers.
+	TracingOptions *tracing.Options
+
+	GatewayOptions *gateway.Options
 }
 
 func NewWorkHubManagerOptions() *WorkHubManagerOptions {
 	return &WorkHubManagerOptions{
-		WorkDriver: "kube",
+		WorkDriver:           "kube",
+		GRPCTransportOptions: cloudevents.NewGRPCTransportOptions(),
+		ShardingOptions:      sharding.NewOptions(),
+		TracingOptions:       tracing.NewOptions(),
+		GatewayOptions:       &gateway.Options{},
 	}
 }
 
@@ -25,5 +57,31 @@ func (o *WorkHubManagerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.WorkDriverConfig, "work-driver-config",
 		o.WorkDriverConfig, "The config file path of current work driver")
 	fs.StringVar(&o.CloudEventsClientID, "cloudevents-client-id",
-		o.CloudEventsClientID, "The ID of the cloudevents client when publishing works with cloudevents")
+		o.CloudEventsClientID, "The ID of the cloudevents client when publishing works with cloudevents, it must be unique "+
+			"per replica, a random ID prefixed with mwrsctrl-client- is generated if it is not set")
+	fs.StringVar(&o.CloudEventsEncryptionKeyDir, "cloudevents-encryption-key-dir",
+		o.CloudEventsEncryptionKeyDir, "The directory of the encryption keys of clusters when publishing works with "+
+			"cloudevents, each file is named with a cluster name and contains the base64 encoded 32 bytes key of the cluster")
+	fs.BoolVar(&o.CloudEventsDeltaSpec, "cloudevents-delta-spec", o.CloudEventsDeltaSpec,
+		"Send the spec of the works as JSON patches against the last generation when publishing works with cloudevents "+
+			"and the patch is much smaller than the full spec, the agents must enable it as well")
+	fs.StringVar(&o.ManifestWorkReplicaSetSelector, "manifestworkreplicaset-selector", o.ManifestWorkReplicaSetSelector,
+		"A label selector to scope the ManifestWorkReplicaSets watched by the controllers, so the ManifestWorkReplicaSets "+
+			"can be sharded across multiple work hub managers by labels")
+	o.GRPCTransportOptions.AddFlags(fs)
+	o.ShardingOptions.AddFlags(fs)
+	o.TracingOptions.AddFlags(fs)
+	fs.StringVar(&o.GatewayOptions.BindAddress, "work-gateway-bind-address", o.GatewayOptions.BindAddress,
+		"The address the work gateway serves the HTTP API of works on, the work gateway is disabled if it is empty")
+	fs.StringVar(&o.GatewayOptions.CertFile, "work-gateway-cert-file", o.GatewayOptions.CertFile,
+		"The serving certificate file of the work gateway")
+	fs.StringVar(&o.GatewayOptions.KeyFile, "work-gateway-key-file", o.GatewayOptions.KeyFile,
+		"The serving key file of the work gateway")
+	fs.StringVar(&o.GatewayOptions.ClientCAFile, "work-gateway-client-ca-file", o.GatewayOptions.ClientCAFile,
+		"The CA file to verify the client certificates of the work gateway requests")
+	fs.StringVar(&o.GatewayOptions.ClientSPIFFETrustDomain, "work-gateway-client-spiffe-trust-domain",
+		o.GatewayOptions.ClientSPIFFETrustDomain,
+		"The trust domain of the X509-SVIDs of the agents, a client certificate with the SPIFFE ID "+
+			"spiffe://<trust domain>/open-cluster-management/cluster/<cluster name> authenticates as the agents of the "+
+			"managed cluster. The SPIFFE IDs are not mapped if it is empty")
 }
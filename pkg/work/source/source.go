@@ -0,0 +1,244 @@
+// Package source is a client library for the external controllers, i.e. the work sources, to produce ManifestWorks
+// to the managed clusters through the cloudevents work drivers, e.g. MQTT and gRPC, instead of the hub apiserver.
+//
+// The client publishes the ManifestWorks of the source as cloudevents and caches them, with the status reported by
+// the work agents, in an informer. The works of a cluster are listed, watched, created, patched and deleted with the
+// ManifestWorkInterface returned by ManifestWorks, the same way as with the kube work client. Update and UpdateStatus
+// are not supported: the spec is changed with patches, and the status is only reported by the agents.
+//
+// A minimal source controller looks like:
+//
+//	o := source.NewOptions()
+//	o.SourceID = "my-source"
+//	o.WorkDriver = "grpc"
+//	o.WorkDriverConfig = "/etc/my-source/grpc-config.yaml"
+//	client, err := source.NewClient(ctx, o)
+//	if err != nil {
+//		return err
+//	}
+//	client.Start(ctx)
+//	if !client.WaitForCacheSync(ctx) {
+//		return fmt.Errorf("failed to sync the manifestworks")
+//	}
+//	work, err := client.ManifestWorks("cluster1").Create(ctx, manifestWork, metav1.CreateOptions{})
+package source
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/cache"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	workv1informer "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/constants"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/source/codec"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
+
+	"open-cluster-management.io/ocm/pkg/common/cloudevents"
+	"open-cluster-management.io/ocm/pkg/work/webhook/quota"
+)
+
+const defaultResyncPeriod = 30 * time.Minute
+
+// Options are the options of a work source client.
+type Options struct {
+	// SourceID is the unique id of the source. The agents report the status of the works to the source which
+	// published them, so the sources only see their own works.
+	SourceID string
+	// WorkDriver is the type of the cloudevents work driver, mqtt, grpc or kafka.
+	WorkDriver string
+	// WorkDriverConfig is the config file path of the work driver.
+	WorkDriverConfig string
+	// ClientID is the id of the cloudevents client. The MQTT broker disconnects a client when another one connects
+	// with the same id, so it must be unique per client, e.g. per replica of the source. A random id prefixed with
+	// <SourceID>-client- is generated for each client if it is not set.
+	ClientID string
+	// EncryptionKeyDir is the directory of the encryption keys of the clusters, the manifests of the works are
+	// encrypted with the key of their cluster if it is set.
+	EncryptionKeyDir string
+	// DeltaSpec sends the spec of the works as JSON patches when only a small part of the spec changes, the agents
+	// must enable it as well.
+	DeltaSpec bool
+	// GRPCTransportOptions configures the compression, the message sizes and the keepalive of the grpc connection
+	// when the work driver is grpc.
+	GRPCTransportOptions *cloudevents.GRPCTransportOptions
+	// QuotaPerMinute is the max number of the work creations and patches per cluster namespace per minute, the
+	// works published with the cloudevents drivers bypass the quota of the work webhook, so it is enforced by the
+	// client. The throttled requests wait for at most QuotaMaxWait before they are rejected.
+	QuotaPerMinute int
+	QuotaMaxWait   time.Duration
+}
+
+// NewOptions returns the options of a work source client with the default values set.
+func NewOptions() *Options {
+	return &Options{
+		GRPCTransportOptions: cloudevents.NewGRPCTransportOptions(),
+		QuotaMaxWait:         5 * time.Second,
+	}
+}
+
+// AddFlags adds the flags of the work source client to the flag set, for the sources exposing the options as flags.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.SourceID, "source-id", o.SourceID, "The unique id of the source publishing the works")
+	fs.StringVar(&o.WorkDriver, "work-driver", o.WorkDriver, "The type of work driver, it can be mqtt, grpc or kafka")
+	fs.StringVar(&o.WorkDriverConfig, "work-driver-config", o.WorkDriverConfig, "The config file path of the work driver")
+	fs.StringVar(&o.ClientID, "cloudevents-client-id", o.ClientID,
+		"The ID of the cloudevents client when publishing works with cloudevents, it must be unique per client, a "+
+			"random ID prefixed with <source-id>-client- is generated if it is not set")
+	fs.StringVar(&o.EncryptionKeyDir, "cloudevents-encryption-key-dir", o.EncryptionKeyDir,
+		"The directory of the encryption keys of clusters when publishing works with cloudevents, each file is named "+
+			"with a cluster name and contains the base64 encoded 32 bytes key of the cluster")
+	fs.BoolVar(&o.DeltaSpec, "cloudevents-delta-spec", o.DeltaSpec,
+		"Send the spec of the works as JSON patches against the last generation when publishing works with cloudevents "+
+			"and the patch is much smaller than the full spec, the agents must enable it as well")
+	fs.IntVar(&o.QuotaPerMinute, "cloudevents-quota-per-minute", o.QuotaPerMinute,
+		"The max number of the work creations and patches per cluster namespace per minute when publishing works "+
+			"with cloudevents, the throttled requests wait for at most --cloudevents-quota-max-wait and are rejected "+
+			"with TooManyRequests then. The quota is disabled if it is 0.")
+	fs.DurationVar(&o.QuotaMaxWait, "cloudevents-quota-max-wait", o.QuotaMaxWait,
+		"The max time a throttled work request waits for the quota when publishing works with cloudevents.")
+	o.GRPCTransportOptions.AddFlags(fs)
+}
+
+// Validate validates the options.
+func (o *Options) Validate() error {
+	if len(o.SourceID) == 0 {
+		return fmt.Errorf("source id is required")
+	}
+	switch o.WorkDriver {
+	case constants.ConfigTypeMQTT, constants.ConfigTypeGRPC, constants.ConfigTypeKafka:
+	default:
+		return fmt.Errorf("unsupported work driver %q, it must be one of %s, %s and %s",
+			o.WorkDriver, constants.ConfigTypeMQTT, constants.ConfigTypeGRPC, constants.ConfigTypeKafka)
+	}
+	if len(o.WorkDriverConfig) == 0 {
+		return fmt.Errorf("config file of the work driver is required")
+	}
+	return nil
+}
+
+// clientID returns the id of the cloudevents client, a random one is generated for each client if it is not set.
+func (o *Options) clientID() string {
+	if len(o.ClientID) > 0 {
+		return o.ClientID
+	}
+	return fmt.Sprintf("%s-client-%s", o.SourceID, rand.String(5))
+}
+
+// NewWorkClientSet builds a work clientset publishing the ManifestWorks of the source with the work driver. The works
+// are read from the watcher store, which must be set up by the caller, e.g. with the store of a ManifestWork informer
+// built with the clientset. Only the ManifestWorks of the returned clientset are supported.
+func NewWorkClientSet(ctx context.Context, o *Options, watcherStore store.WorkClientWatcherStore) (workclientset.Interface, error) {
+	_, config, err := generic.NewConfigLoader(o.WorkDriver, o.WorkDriverConfig).LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	cloudevents.ConfigureFIPS(config)
+
+	var workCodec generic.Codec[*workv1.ManifestWork] = codec.NewManifestBundleCodec()
+	var deltaCodec *cloudevents.DeltaCodec
+	if o.DeltaSpec {
+		deltaCodec = cloudevents.NewDeltaCodec(workCodec)
+		workCodec = deltaCodec
+	}
+	if len(o.EncryptionKeyDir) > 0 {
+		workCodec = cloudevents.NewEncryptionCodec(workCodec, cloudevents.NewDirEncryptionKeyGetter(o.EncryptionKeyDir))
+	}
+
+	var sourceOptions *options.CloudEventsSourceOptions
+	if grpcOptions, ok := config.(*grpcoptions.GRPCOptions); ok {
+		// the grpc connection is dialed with the grpc transport options
+		sourceOptions, err = cloudevents.NewGRPCSourceOptions(grpcOptions, o.GRPCTransportOptions, o.SourceID)
+	} else {
+		sourceOptions, err = generic.BuildCloudEventsSourceOptions(config, o.clientID(), o.SourceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	workClient, err := cloudevents.NewSourceWorkClientSet(ctx, sourceOptions, watcherStore, workCodec)
+	if err != nil {
+		return nil, err
+	}
+	if deltaCodec != nil {
+		// the spec of the works is the base of the next patches once it is published
+		workClient = deltaCodec.WrapClientSet(workClient)
+	}
+	if o.QuotaPerMinute > 0 {
+		limiter := quota.NewLimiter(o.QuotaPerMinute, 1, o.QuotaMaxWait)
+		workClient = cloudevents.WrapManifestWorks(workClient, limiter.WrapClient)
+	}
+	return workClient, nil
+}
+
+// Client is a work source client. The ManifestWorks of the source are cached by its informer, so the client must be
+// started and synced before the works are accessed.
+type Client struct {
+	workClient      workclientset.Interface
+	informerFactory workinformers.SharedInformerFactory
+	informer        workv1informer.ManifestWorkInformer
+}
+
+// NewClient builds a work source client with the options, the informer options, e.g. a tweak of the list options,
+// scope the ManifestWorks cached by the client.
+func NewClient(ctx context.Context, o *Options, informerOptions ...workinformers.SharedInformerOption) (*Client, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	watcherStore := store.NewSourceInformerWatcherStore(ctx)
+	workClient, err := NewWorkClientSet(ctx, o, watcherStore)
+	if err != nil {
+		return nil, err
+	}
+
+	informerFactory := workinformers.NewSharedInformerFactoryWithOptions(workClient, defaultResyncPeriod, informerOptions...)
+	informer := informerFactory.Work().V1().ManifestWorks()
+	// the informer store is used as the client store, so the works are read from the informer cache
+	watcherStore.SetStore(informer.Informer().GetStore())
+
+	return &Client{
+		workClient:      workClient,
+		informerFactory: informerFactory,
+		informer:        informer,
+	}, nil
+}
+
+// Start starts the informer of the client, it returns immediately.
+func (c *Client) Start(ctx context.Context) {
+	c.informerFactory.Start(ctx.Done())
+}
+
+// WaitForCacheSync waits until the ManifestWorks of the source are cached, it returns false if the context is done
+// before that.
+func (c *Client) WaitForCacheSync(ctx context.Context) bool {
+	return cache.WaitForCacheSync(ctx.Done(), c.informer.Informer().HasSynced)
+}
+
+// ManifestWorks returns the client of the ManifestWorks of the source on a managed cluster. The clients of different
+// clusters can be used concurrently.
+func (c *Client) ManifestWorks(clusterName string) workv1client.ManifestWorkInterface {
+	return c.workClient.WorkV1().ManifestWorks(clusterName)
+}
+
+// Informer returns the ManifestWork informer of the client, so the source controllers can add event handlers and
+// read the works with the lister.
+func (c *Client) Informer() workv1informer.ManifestWorkInformer {
+	return c.informer
+}
+
+// WorkClientSet returns the work clientset of the client, e.g. to build a work applier. Only the ManifestWorks of the
+// clientset are supported.
+func (c *Client) WorkClientSet() workclientset.Interface {
+	return c.workClient
+}
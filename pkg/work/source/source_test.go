@@ -0,0 +1,92 @@
+package source
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name        string
+		setOptions  func(o *Options)
+		expectedErr bool
+	}{
+		{
+			name: "valid",
+			setOptions: func(o *Options) {
+				o.SourceID = "source1"
+				o.WorkDriver = "grpc"
+				o.WorkDriverConfig = "/etc/source/grpc-config.yaml"
+			},
+		},
+		{
+			name: "without source id",
+			setOptions: func(o *Options) {
+				o.WorkDriver = "mqtt"
+				o.WorkDriverConfig = "/etc/source/mqtt-config.yaml"
+			},
+			expectedErr: true,
+		},
+		{
+			name: "kube work driver",
+			setOptions: func(o *Options) {
+				o.SourceID = "source1"
+				o.WorkDriver = "kube"
+				o.WorkDriverConfig = "/etc/source/kubeconfig"
+			},
+			expectedErr: true,
+		},
+		{
+			name: "without work driver config",
+			setOptions: func(o *Options) {
+				o.SourceID = "source1"
+				o.WorkDriver = "grpc"
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := NewOptions()
+			c.setOptions(o)
+			err := o.Validate()
+			if c.expectedErr && err == nil {
+				t.Errorf("expected error, but got nil")
+			}
+			if !c.expectedErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewClientWithoutDriverConfig(t *testing.T) {
+	o := NewOptions()
+	o.SourceID = "source1"
+	o.WorkDriver = "grpc"
+	o.WorkDriverConfig = "/nonexistent/grpc-config.yaml"
+	if _, err := NewClient(context.TODO(), o); err == nil {
+		t.Errorf("expected error when the work driver config does not exist, but got nil")
+	}
+}
+
+func TestClientID(t *testing.T) {
+	o := NewOptions()
+	o.SourceID = "source1"
+
+	clientID := o.clientID()
+	if !strings.HasPrefix(clientID, "source1-client-") {
+		t.Errorf("expected client id prefixed with source1-client-, but got %s", clientID)
+	}
+	// the clients connecting to the mqtt broker with the same id disconnect each other
+	if anotherClientID := o.clientID(); anotherClientID == clientID {
+		t.Errorf("expected unique client ids, but got %s twice", clientID)
+	}
+
+	o.ClientID = "client1"
+	if clientID := o.clientID(); clientID != "client1" {
+		t.Errorf("expected client id client1, but got %s", clientID)
+	}
+}
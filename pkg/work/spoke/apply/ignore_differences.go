@@ -0,0 +1,23 @@
+package apply
+
+import (
+	"context"
+)
+
+type ignoredFieldsKey struct{}
+
+// WithIgnoredFields returns a context with the ignored fields of the manifest to apply, see
+// helper.ManifestWorkIgnoreDifferencesAnnotationKey. The appliers keep the values of the ignored fields on the
+// managed cluster instead of reverting them to the manifest.
+func WithIgnoredFields(ctx context.Context, fields [][]string) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, ignoredFieldsKey{}, fields)
+}
+
+// ignoredFieldsFrom returns the ignored fields of the manifest to apply in the context.
+func ignoredFieldsFrom(ctx context.Context) [][]string {
+	fields, _ := ctx.Value(ignoredFieldsKey{}).([][]string)
+	return fields
+}
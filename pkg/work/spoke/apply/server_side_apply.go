@@ -2,7 +2,10 @@ package apply
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/openshift/library-go/pkg/operator/events"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -24,12 +27,68 @@ type ServerSideApply struct {
 
 type ServerSideApplyConflictError struct {
 	ssaErr error
+	// Conflicts are the fields conflicting with the other field managers.
+	Conflicts []FieldConflict
+}
+
+// FieldConflict is a field of the resource owned by another field manager, which conflicts with the apply.
+type FieldConflict struct {
+	// Field is the path of the field, e.g. .spec.replicas
+	Field string `json:"field"`
+	// Manager is the field manager owning the field.
+	Manager string `json:"manager"`
+}
+
+func newServerSideApplyConflictError(err error) *ServerSideApplyConflictError {
+	conflictErr := &ServerSideApplyConflictError{ssaErr: err}
+	statusErr, ok := err.(errors.APIStatus)
+	if !ok || statusErr.Status().Details == nil {
+		return conflictErr
+	}
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflictErr.Conflicts = append(conflictErr.Conflicts, FieldConflict{
+			Field:   cause.Field,
+			Manager: conflictManager(cause.Message),
+		})
+	}
+	return conflictErr
+}
+
+// conflictManager returns the field manager in the message of a conflict cause, which is in the form of
+// `conflict with "manager" using apps/v1`. The message is returned as it is if it is not in the form.
+func conflictManager(message string) string {
+	quoted := strings.TrimPrefix(message, "conflict with ")
+	prefix, err := strconv.QuotedPrefix(quoted)
+	if err != nil {
+		return message
+	}
+	manager, err := strconv.Unquote(prefix)
+	if err != nil {
+		return message
+	}
+	return manager
 }
 
 func (e *ServerSideApplyConflictError) Error() string {
 	return e.ssaErr.Error()
 }
 
+// ConflictsMessage returns the conflicts in json, so the users can resolve the ownership of the fields with the
+// status of the manifestwork. The error message is returned if there are no conflicts in the error.
+func (e *ServerSideApplyConflictError) ConflictsMessage() string {
+	if len(e.Conflicts) == 0 {
+		return e.Error()
+	}
+	data, err := json.Marshal(e.Conflicts)
+	if err != nil {
+		return e.Error()
+	}
+	return fmt.Sprintf("server side apply conflicts: %s", data)
+}
+
 func NewServerSideApply(client dynamic.Interface) *ServerSideApply {
 	return &ServerSideApply{client: client}
 }
@@ -52,6 +111,12 @@ func (c *ServerSideApply) Apply(
 		}
 	}
 
+	// the ignored fields are not applied, so they are left to the other field managers
+	if fields := ignoredFieldsFrom(ctx); len(fields) > 0 {
+		required = required.DeepCopy()
+		helper.RemoveFields(required, fields)
+	}
+
 	obj, err := c.client.
 		Resource(gvr).
 		Namespace(required.GetNamespace()).
@@ -63,7 +128,7 @@ func (c *ServerSideApply) Apply(
 	}
 
 	if errors.IsConflict(err) {
-		return obj, &ServerSideApplyConflictError{ssaErr: err}
+		return obj, newServerSideApplyConflictError(err)
 	}
 
 	if err == nil {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -98,7 +99,15 @@ func TestServerSideApply(t *testing.T) {
 
 			var ssaConflict *ServerSideApplyConflictError
 			if !errors.As(err, &ssaConflict) {
-				t.Errorf("expect serverside apply conflict error, but got %v", err)
+				t.Fatalf("expect serverside apply conflict error, but got %v", err)
+			}
+			expectedConflicts := []FieldConflict{{Field: ".metadata.annotations", Manager: "kubectl"}}
+			if !equality.Semantic.DeepEqual(ssaConflict.Conflicts, expectedConflicts) {
+				t.Errorf("expect conflicts %v, but got %v", expectedConflicts, ssaConflict.Conflicts)
+			}
+			expectedMessage := `server side apply conflicts: [{"field":".metadata.annotations","manager":"kubectl"}]`
+			if message := ssaConflict.ConflictsMessage(); message != expectedMessage {
+				t.Errorf("expect message %q, but got %q", expectedMessage, message)
 			}
 
 		})
@@ -131,11 +140,28 @@ func (r *reactor) React(action clienttesting.Action) (handled bool, ret runtime.
 		return true, nil, apierrors.NewApplyConflict([]metav1.StatusCause{
 			{
 				Type:    metav1.CauseTypeFieldManagerConflict,
-				Message: "field managed configl",
-				Field:   "metadata.annotations",
+				Message: `conflict with "kubectl" using v1`,
+				Field:   ".metadata.annotations",
 			},
 		}, "server side apply secret failed")
 	}
 
 	return true, nil, fmt.Errorf("PatchType is not supported")
 }
+
+func TestConflictManager(t *testing.T) {
+	cases := []struct {
+		message  string
+		expected string
+	}{
+		{message: `conflict with "kubectl"`, expected: "kubectl"},
+		{message: `conflict with "kube-controller-manager" using apps/v1`, expected: "kube-controller-manager"},
+		{message: `conflict with "manager" with subresource "status" using v1`, expected: "manager"},
+		{message: "unknown conflict", expected: "unknown conflict"},
+	}
+	for _, c := range cases {
+		if manager := conflictManager(c.message); manager != c.expected {
+			t.Errorf("expect manager %q of message %q, but got %q", c.expected, c.message, manager)
+		}
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
 )
 
 type UpdateApply struct {
@@ -54,6 +56,23 @@ func (c *UpdateApply) Apply(
 		WithDynamicClient(c.dynamicClient)
 
 	required.SetOwnerReferences([]metav1.OwnerReference{owner})
+
+	// keep the values of the ignored fields on the managed cluster, so the typed appliers do not revert them either
+	if fields := ignoredFieldsFrom(ctx); len(fields) > 0 {
+		existing, err := c.dynamicClient.
+			Resource(gvr).
+			Namespace(required.GetNamespace()).
+			Get(ctx, required.GetName(), metav1.GetOptions{})
+		switch {
+		case err == nil:
+			if err := helper.CopyFields(existing, required, fields); err != nil {
+				return nil, err
+			}
+		case !apierrors.IsNotFound(err):
+			return nil, err
+		}
+	}
+
 	results := resourceapply.ApplyDirectly(ctx, clientHolder, recorder, c.staticResourceCache, func(name string) ([]byte, error) {
 		return required.MarshalJSON()
 	}, "manifest")
@@ -98,6 +117,11 @@ func (c *UpdateApply) applyUnstructured(
 		return nil, false, err
 	}
 
+	// keep the values of the ignored fields on the managed cluster
+	if err := helper.CopyFields(existing, required, ignoredFieldsFrom(ctx)); err != nil {
+		return nil, false, err
+	}
+
 	// Merge OwnerRefs, Labels, and Annotations.
 	existingOwners := existing.GetOwnerReferences()
 	existingLabels := existing.GetLabels()
@@ -116,8 +140,13 @@ func (c *UpdateApply) applyUnstructured(
 	// Keep the finalizers unchanged
 	required.SetFinalizers(existing.GetFinalizers())
 
-	// Compare and update the unstrcuctured.
-	if !*modified && isSameUnstructured(required, existing) {
+	// Compare and update the unstrcuctured. With the ignored fields, only the fields in the manifest are compared,
+	// since the others are defaulted or changed by others on the managed cluster.
+	same := isSameUnstructured(required, existing)
+	if len(ignoredFieldsFrom(ctx)) > 0 {
+		same = isSubsetUnstructured(required, existing)
+	}
+	if !*modified && same {
 		return existing, false, nil
 	}
 	required.SetResourceVersion(existing.GetResourceVersion())
@@ -171,3 +200,72 @@ func isSameUnstructured(obj1, obj2 *unstructured.Unstructured) bool {
 
 	return equality.Semantic.DeepEqual(obj1Copy.Object, obj2Copy.Object)
 }
+
+// isSubsetUnstructured checks if the fields of the required object out of the metadata and status are semantically
+// equal to the fields of the existing object, the fields only in the existing object, e.g. the defaulted fields, are
+// not compared.
+func isSubsetUnstructured(required, existing *unstructured.Unstructured) bool {
+	if required.GroupVersionKind() != existing.GroupVersionKind() {
+		return false
+	}
+	if required.GetName() != existing.GetName() || required.GetNamespace() != existing.GetNamespace() {
+		return false
+	}
+
+	for key, value := range required.Object {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		if !isSubsetValue(value, existing.Object[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSubsetValue(required, existing interface{}) bool {
+	switch required := required.(type) {
+	case map[string]interface{}:
+		existing, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range required {
+			existingValue, found := existing[key]
+			if !found && !isEmptyValue(value) {
+				return false
+			}
+			if found && !isSubsetValue(value, existingValue) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		existing, ok := existing.([]interface{})
+		if !ok || len(required) != len(existing) {
+			return false
+		}
+		for i := range required {
+			if !isSubsetValue(required[i], existing[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equality.Semantic.DeepEqual(required, existing)
+	}
+}
+
+// isEmptyValue checks if the value is null, an empty object or an empty list, which are dropped by the apiserver.
+func isEmptyValue(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	default:
+		return false
+	}
+}
@@ -553,3 +553,152 @@ func newCRD(name string) *apiextensionsv1.CustomResourceDefinition {
 		},
 	}
 }
+
+func TestUpdateApplyIgnoredFields(t *testing.T) {
+	newDeployment := func(replicas int64, image, annotation string, defaulted bool) *unstructured.Unstructured {
+		container := map[string]interface{}{"name": "nginx", "image": image}
+		spec := map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": []interface{}{container}},
+			},
+		}
+		if defaulted {
+			// the fields defaulted by the apiserver are not in the manifest
+			container["imagePullPolicy"] = "IfNotPresent"
+			container["terminationMessagePath"] = "/dev/termination-log"
+			container["resources"] = map[string]interface{}{}
+			spec["revisionHistoryLimit"] = int64(10)
+			spec["progressDeadlineSeconds"] = int64(600)
+			spec["strategy"] = map[string]interface{}{"type": "RollingUpdate"}
+		}
+		obj := testingcommon.NewUnstructuredWithContent("apps/v1", "Deployment", "ns1", "test",
+			map[string]interface{}{"spec": spec, "status": map[string]interface{}{"replicas": replicas}})
+		obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1", Name: "test", UID: defaultOwner}})
+		obj.SetAnnotations(map[string]string{"example.com/revision": annotation})
+		return obj
+	}
+	ignoredFields := [][]string{
+		{"spec", "replicas"},
+		{"metadata", "annotations", "example.com/revision"},
+	}
+
+	cases := []struct {
+		name            string
+		existing        *unstructured.Unstructured
+		required        *unstructured.Unstructured
+		validateActions func(t *testing.T, actions []clienttesting.Action)
+	}{
+		{
+			name:     "ignored fields are changed",
+			existing: newDeployment(5, "nginx:1", "2", true),
+			required: newDeployment(1, "nginx:1", "1", false),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get")
+			},
+		},
+		{
+			name:     "other fields are changed",
+			existing: newDeployment(5, "nginx:1", "2", true),
+			required: newDeployment(1, "nginx:2", "1", false),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get", "update")
+				obj := actions[2].(clienttesting.UpdateActionImpl).Object.(*unstructured.Unstructured)
+				replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+				if replicas != 5 {
+					t.Errorf("Expect the replicas are kept, but got %d", replicas)
+				}
+				if revision := obj.GetAnnotations()["example.com/revision"]; revision != "2" {
+					t.Errorf("Expect the annotation is kept, but got %s", revision)
+				}
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				if len(containers) != 1 || containers[0].(map[string]interface{})["image"] != "nginx:2" {
+					t.Errorf("Expect the image is updated, but got %v", containers)
+				}
+			},
+		},
+		{
+			name:     "fields out of the manifest are removed",
+			existing: newDeployment(5, "nginx:1", "2", true),
+			required: func() *unstructured.Unstructured {
+				obj := newDeployment(1, "nginx:1", "1", false)
+				_ = unstructured.SetNestedField(obj.Object, int64(5), "spec", "revisionHistoryLimit")
+				return obj
+			}(),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get", "update")
+			},
+		},
+		{
+			name:     "create without the existing object",
+			required: newDeployment(1, "nginx:1", "1", false),
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "get", "get", "create")
+				obj := actions[2].(clienttesting.CreateActionImpl).Object.(*unstructured.Unstructured)
+				replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+				if replicas != 1 {
+					t.Errorf("Expect the replicas of the manifest, but got %d", replicas)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if c.existing != nil {
+				objects = append(objects, c.existing)
+			}
+			dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+			applier := NewUpdateApply(dynamicClient, nil, nil)
+
+			syncContext := testingcommon.NewFakeSyncContext(t, "test")
+			_, err := applier.Apply(WithIgnoredFields(context.TODO(), ignoredFields),
+				schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+				c.required, metav1.OwnerReference{APIVersion: "v1", Name: "test", UID: defaultOwner}, nil, syncContext.Recorder())
+			if err != nil {
+				t.Errorf("expect no error, but got %v", err)
+			}
+
+			c.validateActions(t, dynamicClient.Actions())
+		})
+	}
+}
+
+func TestUpdateApplyKubeIgnoredFields(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "v1", Name: "test", UID: defaultOwner}
+	existingSecret := spoketesting.NewSecretWithType("test", "ns1", "foo", corev1.SecretTypeOpaque)
+	existingSecret.Annotations = map[string]string{"example.com/revision": "2"}
+	existingSecret.OwnerReferences = []metav1.OwnerReference{owner}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existingSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := &unstructured.Unstructured{Object: content}
+	existing.SetAPIVersion("v1")
+	existing.SetKind("Secret")
+
+	required := existing.DeepCopy()
+	required.SetOwnerReferences(nil)
+	required.SetAnnotations(map[string]string{"example.com/revision": "1"})
+
+	dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	kubeClient := fake.NewSimpleClientset(existingSecret)
+	applier := NewUpdateApply(dynamicClient, kubeClient, nil)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, "test")
+	obj, err := applier.Apply(
+		WithIgnoredFields(context.TODO(), [][]string{{"metadata", "annotations", "example.com/revision"}}),
+		schema.GroupVersionResource{Version: "v1", Resource: "secrets"},
+		required, owner, nil, syncContext.Recorder())
+	if err != nil {
+		t.Errorf("expect no error, but got %v", err)
+	}
+
+	// the secret is applied with the typed client, and the ignored annotation is not reverted
+	if _, ok := obj.(*corev1.Secret); !ok {
+		t.Errorf("expect the secret applied with the typed client, but got %T", obj)
+	}
+	testingcommon.AssertActions(t, dynamicClient.Actions(), "get")
+	testingcommon.AssertActions(t, kubeClient.Actions(), "get")
+}
@@ -0,0 +1,310 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// webhookQueueSize is the max number of the records waiting to be sent to the webhook, the records are dropped
+	// once the queue is full.
+	webhookQueueSize = 1000
+	webhookTimeout   = 10 * time.Second
+	// maxFieldDepth is the depth of the field paths summarizing a patch.
+	maxFieldDepth = 2
+)
+
+// Record is an audit record of a change the work agent makes on the managed cluster for a manifestwork.
+type Record struct {
+	Time metav1.Time `json:"time"`
+	// Hub is the hash of the hub the manifestwork is from.
+	Hub string `json:"hub"`
+	// Work is the name of the manifestwork on the hub.
+	Work string `json:"work"`
+
+	Verb        string `json:"verb"`
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+
+	// Fields are the paths of the fields changed by a patch. An update replaces the whole object, so the fields
+	// are not summarized.
+	Fields []string `json:"fields,omitempty"`
+	// Code is the status code of the response, it is 0 if the request fails without a response.
+	Code int `json:"code"`
+}
+
+// Options is the options to record the changes on the managed cluster made for the manifestworks, auditing is
+// disabled if neither the log file nor the webhook is set.
+type Options struct {
+	LogFile    string
+	WebhookURL string
+}
+
+// NewOptions returns the audit options with default value set
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.LogFile, "audit-log-file", o.LogFile,
+		"The file the changes made on the managed cluster for the manifestworks are appended to as JSON lines. "+
+			"A record includes the hub and the manifestwork, the resource and a summary of the changed fields.")
+	fs.StringVar(&o.WebhookURL, "audit-webhook-url", o.WebhookURL,
+		"The URL the records of the changes made on the managed cluster for the manifestworks are posted to.")
+}
+
+type workKey struct{}
+
+type work struct {
+	hub  string
+	name string
+}
+
+// WithWork returns a context in which the changes are recorded for the manifestwork on the hub.
+func WithWork(ctx context.Context, hubHash, workName string) context.Context {
+	return context.WithValue(ctx, workKey{}, work{hub: hubHash, name: workName})
+}
+
+// Setup wraps the transport of the config to record the create, update, patch and delete requests sent with the
+// context of a manifestwork. The log file is closed and the webhook is stopped once the context is done.
+func (o *Options) Setup(ctx context.Context, config *rest.Config) error {
+	if len(o.LogFile) == 0 && len(o.WebhookURL) == 0 {
+		return nil
+	}
+
+	var sinks []func(*Record)
+	if len(o.LogFile) > 0 {
+		file, err := os.OpenFile(o.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("unable to open the audit log file: %w", err)
+		}
+		sinks = append(sinks, newFileSink(ctx, file))
+	}
+	if len(o.WebhookURL) > 0 {
+		sinks = append(sinks, newWebhookSink(ctx, o.WebhookURL, &http.Client{Timeout: webhookTimeout}))
+	}
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &auditRoundTripper{delegate: rt, sinks: sinks}
+	})
+	return nil
+}
+
+func newFileSink(ctx context.Context, file *os.File) func(*Record) {
+	lock := sync.Mutex{}
+	go func() {
+		<-ctx.Done()
+		lock.Lock()
+		defer lock.Unlock()
+		if err := file.Close(); err != nil {
+			klog.Errorf("failed to close the audit log file, %v", err)
+		}
+	}()
+
+	return func(record *Record) {
+		data, err := json.Marshal(record)
+		if err != nil {
+			klog.Errorf("failed to marshal the audit record, %v", err)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			klog.Errorf("failed to write the audit record, %v", err)
+		}
+	}
+}
+
+func newWebhookSink(ctx context.Context, url string, client *http.Client) func(*Record) {
+	queue := make(chan *Record, webhookQueueSize)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case record := <-queue:
+				if err := postRecord(ctx, url, client, record); err != nil {
+					klog.Errorf("failed to send the audit record of %s %s/%s to the webhook, %v",
+						record.Verb, record.Resource, record.Name, err)
+				}
+			}
+		}
+	}()
+
+	return func(record *Record) {
+		select {
+		case queue <- record:
+		default:
+			klog.Errorf("the audit webhook queue is full, drop the record of %s %s/%s",
+				record.Verb, record.Resource, record.Name)
+		}
+	}
+}
+
+func postRecord(ctx context.Context, url string, client *http.Client, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type auditRoundTripper struct {
+	delegate http.RoundTripper
+	sinks    []func(*Record)
+}
+
+func (rt *auditRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	w, ok := req.Context().Value(workKey{}).(work)
+	verb := requestVerb(req.Method)
+	if !ok || len(verb) == 0 {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	record := newRecord(req, verb)
+	record.Hub, record.Work = w.hub, w.name
+
+	resp, err := rt.delegate.RoundTrip(req)
+	if resp != nil {
+		record.Code = resp.StatusCode
+	}
+	record.Time = metav1.Now()
+	for _, sink := range rt.sinks {
+		sink(record)
+	}
+	return resp, err
+}
+
+func requestVerb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return ""
+	}
+}
+
+// newRecord builds the record from the path and the body of the request. The path is in the format of
+// /api/v1/namespaces/{namespace}/{resource}/{name}/{subresource} or
+// /apis/{group}/{version}/namespaces/{namespace}/{resource}/{name}/{subresource}.
+func newRecord(req *http.Request, verb string) *Record {
+	record := &Record{Verb: verb}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		record.APIVersion, segments = segments[1], segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		record.APIVersion, segments = segments[1]+"/"+segments[2], segments[3:]
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		record.Namespace, segments = segments[1], segments[2:]
+	}
+	if len(segments) > 0 {
+		record.Resource = segments[0]
+	}
+	if len(segments) > 1 {
+		record.Name = segments[1]
+	}
+	if len(segments) > 2 {
+		record.Subresource = segments[2]
+	}
+
+	if verb == "delete" || req.GetBody == nil {
+		return record
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return record
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return record
+	}
+
+	if types.PatchType(req.Header.Get("Content-Type")) == types.JSONPatchType {
+		var operations []struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(data, &operations); err == nil {
+			for _, operation := range operations {
+				record.Fields = append(record.Fields, strings.ReplaceAll(strings.Trim(operation.Path, "/"), "/", "."))
+			}
+		}
+		return record
+	}
+
+	object := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &object); err != nil {
+		return record
+	}
+	record.Kind, _ = object["kind"].(string)
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok && len(record.Name) == 0 {
+		record.Name, _ = metadata["name"].(string)
+	}
+	if verb == "patch" {
+		record.Fields = fieldPaths("", object, maxFieldDepth)
+		sort.Strings(record.Fields)
+	}
+	return record
+}
+
+// fieldPaths returns the paths of the fields in the object down to the depth.
+func fieldPaths(prefix string, object map[string]interface{}, depth int) []string {
+	var paths []string
+	for key, value := range object {
+		if len(prefix) == 0 && (key == "apiVersion" || key == "kind") {
+			continue
+		}
+		path := key
+		if len(prefix) > 0 {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && depth > 1 && len(nested) > 0 {
+			paths = append(paths, fieldPaths(path, nested, depth-1)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestAudit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"cm1","namespace":"ns1"}}`))
+	}))
+	defer server.Close()
+
+	received := make(chan Record, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := Record{}
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			t.Errorf("unexpected err: %v", err)
+		}
+		received <- record
+	}))
+	defer webhook.Close()
+
+	dir, err := os.MkdirTemp("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	options := NewOptions()
+	options.LogFile = path.Join(dir, "audit.log")
+	options.WebhookURL = webhook.URL
+	config := &rest.Config{Host: server.URL}
+	if err := options.Setup(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the requests without the work and the reads are not recorded
+	if _, err := kubeClient.CoreV1().ConfigMaps("ns1").Patch(context.TODO(), "cm1", types.MergePatchType,
+		[]byte(`{"data":{"a":"b"}}`), metav1.PatchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	workCtx := WithWork(context.TODO(), "hub1", "work1")
+	if _, err := kubeClient.CoreV1().ConfigMaps("ns1").Get(workCtx, "cm1", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := kubeClient.CoreV1().ConfigMaps("ns1").Patch(workCtx, "cm1", types.MergePatchType,
+		[]byte(`{"metadata":{"labels":{"app":"test"}},"data":{"a":"b"}}`), metav1.PatchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := kubeClient.CoreV1().ConfigMaps("ns1").Delete(workCtx, "cm1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Record{
+		{
+			Hub: "hub1", Work: "work1", Verb: "patch", APIVersion: "v1", Resource: "configmaps",
+			Namespace: "ns1", Name: "cm1", Fields: []string{"data.a", "metadata.labels"}, Code: http.StatusOK,
+		},
+		{
+			Hub: "hub1", Work: "work1", Verb: "delete", APIVersion: "v1", Resource: "configmaps",
+			Namespace: "ns1", Name: "cm1", Code: http.StatusOK,
+		},
+	}
+
+	data, err := os.ReadFile(options.LogFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d records, but got %v", len(expected), lines)
+	}
+	for i, line := range lines {
+		record := Record{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		assertRecord(t, expected[i], record)
+	}
+
+	for i := range expected {
+		select {
+		case record := <-received:
+			assertRecord(t, expected[i], record)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the record %d is sent to the webhook", i)
+		}
+	}
+}
+
+func TestNewRecord(t *testing.T) {
+	cases := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedRecord Record
+	}{
+		{
+			name:        "create",
+			method:      http.MethodPost,
+			path:        "/apis/apps/v1/namespaces/ns1/deployments",
+			contentType: "application/json",
+			body:        `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"deploy1"}}`,
+			expectedRecord: Record{
+				Verb: "create", APIVersion: "apps/v1", Kind: "Deployment", Resource: "deployments",
+				Namespace: "ns1", Name: "deploy1",
+			},
+		},
+		{
+			name:        "server side apply",
+			method:      http.MethodPatch,
+			path:        "/apis/rbac.authorization.k8s.io/v1/clusterroles/role1",
+			contentType: string(types.ApplyPatchType),
+			body:        "apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: role1\nrules: []\n",
+			expectedRecord: Record{
+				Verb: "patch", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole",
+				Resource: "clusterroles", Name: "role1", Fields: []string{"metadata.name", "rules"},
+			},
+		},
+		{
+			name:        "json patch",
+			method:      http.MethodPatch,
+			path:        "/api/v1/namespaces/ns1/pods/pod1/status",
+			contentType: string(types.JSONPatchType),
+			body:        `[{"op":"replace","path":"/status/phase","value":"Running"}]`,
+			expectedRecord: Record{
+				Verb: "patch", APIVersion: "v1", Resource: "pods", Subresource: "status",
+				Namespace: "ns1", Name: "pod1", Fields: []string{"status.phase"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(c.body)), nil
+			}
+			req.Header.Set("Content-Type", c.contentType)
+			assertRecord(t, c.expectedRecord, *newRecord(req, requestVerb(c.method)))
+		})
+	}
+}
+
+func assertRecord(t *testing.T, expected, actual Record) {
+	actual.Time = metav1.Time{}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected record %v, but got %v", expected, actual)
+	}
+}
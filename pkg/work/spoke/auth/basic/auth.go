@@ -131,6 +131,44 @@ func (v *SarValidator) CheckSubjectAccessReviews(ctx context.Context, sa *workap
 	return nil
 }
 
+// CheckReadAccess checks if the executor has permission to get the object of the gvr resource if the name is set,
+// or to list the objects otherwise, by subjectAccessReview requests.
+func (v *SarValidator) CheckReadAccess(ctx context.Context, executor *workapiv1.ManifestWorkExecutor,
+	gvr schema.GroupVersionResource, namespace, name string) error {
+	if executor == nil {
+		return nil
+	}
+
+	if err := v.ExecutorBasicCheck(executor); err != nil {
+		return err
+	}
+
+	verb := "list"
+	if len(name) > 0 {
+		verb = "get"
+	}
+	resource := authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Name:      name,
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+	}
+	sa := executor.Subject.ServiceAccount
+	allowed, err := validateBySubjectAccessReviews(ctx, v.kubeClient,
+		buildSubjectAccessReviews(sa.Namespace, sa.Name, resource, verb))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return &NotAllowedError{
+			Err: fmt.Errorf("not allowed to %s the resource %s %s, %s %s",
+				verb, resource.Group, resource.Resource, resource.Namespace, resource.Name),
+		}
+	}
+	return nil
+}
+
 // CheckEscalation checks whether the sa is escalated to operate the gvr(RBAC) resources.
 func (v *SarValidator) CheckEscalation(ctx context.Context, sa *workapiv1.ManifestWorkSubjectServiceAccount,
 	gvr schema.GroupVersionResource, namespace, name string, obj *unstructured.Unstructured) error {
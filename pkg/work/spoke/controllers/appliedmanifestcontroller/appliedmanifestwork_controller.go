@@ -14,7 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -24,8 +24,11 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/audit"
+	"open-cluster-management.io/ocm/pkg/work/spoke/target"
 )
 
 // AppliedManifestWorkController is to sync the applied resources of appliedmanifestwork with related
@@ -34,7 +37,8 @@ type AppliedManifestWorkController struct {
 	patcher                   patcher.Patcher[*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus]
 	manifestWorkLister        worklister.ManifestWorkNamespaceLister
 	appliedManifestWorkLister worklister.AppliedManifestWorkLister
-	spokeDynamicClient        dynamic.Interface
+	spokeMetadataClient       metadata.Interface
+	targets                   *target.Resolver
 	hubHash                   string
 	rateLimiter               workqueue.RateLimiter
 }
@@ -42,12 +46,14 @@ type AppliedManifestWorkController struct {
 // NewAppliedManifestWorkController returns a AppliedManifestWorkController
 func NewAppliedManifestWorkController(
 	recorder events.Recorder,
-	spokeDynamicClient dynamic.Interface,
+	spokeMetadataClient metadata.Interface,
+	targets *target.Resolver,
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
-	hubHash string) factory.Controller {
+	hubHash string,
+	controllerHealth *health.ControllerHealth) factory.Controller {
 
 	controller := &AppliedManifestWorkController{
 		patcher: patcher.NewPatcher[
@@ -55,7 +61,8 @@ func NewAppliedManifestWorkController(
 			appliedManifestWorkClient),
 		manifestWorkLister:        manifestWorkLister,
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
-		spokeDynamicClient:        spokeDynamicClient,
+		spokeMetadataClient:       spokeMetadataClient,
+		targets:                   targets,
 		hubHash:                   hubHash,
 		rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
 	}
@@ -66,7 +73,7 @@ func NewAppliedManifestWorkController(
 			helper.AppliedManifestworkQueueKeyFunc(hubHash),
 			helper.AppliedManifestworkHubHashFilter(hubHash),
 			appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("AppliedManifestWorkController", recorder)
+		WithSync(controllerHealth.ObserveSync(controller.sync)).ToController("AppliedManifestWorkController", recorder)
 }
 
 func (m *AppliedManifestWorkController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -121,6 +128,12 @@ func (m *AppliedManifestWorkController) syncManifestWork(
 	originalAppliedManifestWork *workapiv1.AppliedManifestWork) error {
 	appliedManifestWork := originalAppliedManifestWork.DeepCopy()
 
+	// the resources applied to a remote target cluster are tracked on the target cluster
+	metadataClient, err := m.targets.MetadataClient(appliedManifestWork, m.spokeMetadataClient)
+	if err != nil {
+		return err
+	}
+
 	// get the latest applied resources from the manifests in resource status. We get this from status instead of
 	// spec because manifests in spec are only resource templates, while resource status records the real resources
 	// maintained by the manifest work.
@@ -136,7 +149,7 @@ func (m *AppliedManifestWorkController) syncManifestWork(
 			continue
 		}
 
-		u, err := m.spokeDynamicClient.
+		u, err := metadataClient.
 			Resource(gvr).
 			Namespace(resourceStatus.ResourceMeta.Namespace).
 			Get(context.TODO(), resourceStatus.ResourceMeta.Name, metav1.GetOptions{})
@@ -169,7 +182,7 @@ func (m *AppliedManifestWorkController) syncManifestWork(
 		return utilerrors.NewAggregate(errs)
 	}
 
-	owner := helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+	owner := target.Owner(appliedManifestWork)
 
 	// delete applied resources which are no longer maintained by manifest work
 	noLongerMaintainedResources := findUntrackedResources(appliedManifestWork.Status.AppliedResources, appliedResources)
@@ -177,7 +190,7 @@ func (m *AppliedManifestWorkController) syncManifestWork(
 	reason := fmt.Sprintf("it is no longer maintained by manifestwork %s", manifestWork.Name)
 
 	resourcesPendingFinalization, errs := helper.DeleteAppliedResources(
-		ctx, noLongerMaintainedResources, reason, m.spokeDynamicClient, controllerContext.Recorder(), *owner)
+		audit.WithWork(ctx, m.hubHash, manifestWork.Name), noLongerMaintainedResources, reason, metadataClient, controllerContext.Recorder(), owner)
 	if len(errs) != 0 {
 		return utilerrors.NewAggregate(errs)
 	}
@@ -217,7 +230,7 @@ func (m *AppliedManifestWorkController) syncManifestWork(
 	// update appliedmanifestwork status with latest applied resources. if this conflicts, we'll try again later
 	// for retrying update without reassessing the status can cause overwriting of valid information.
 	appliedManifestWork.Status.AppliedResources = appliedResources
-	_, err := m.patcher.PatchStatus(ctx, appliedManifestWork, appliedManifestWork.Status, originalAppliedManifestWork.Status)
+	_, err = m.patcher.PatchStatus(ctx, appliedManifestWork, appliedManifestWork.Status, originalAppliedManifestWork.Status)
 	return err
 }
 
@@ -12,7 +12,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	fakedynamic "k8s.io/client-go/dynamic/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/diff"
@@ -218,7 +217,7 @@ func TestSyncManifestWork(t *testing.T) {
 			testingAppliedWork.Status.AppliedResources = c.appliedResources
 			testingWork.Status.ResourceStatus.Manifests = c.manifests
 
-			fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existingResources...)
+			fakeMetadataClient := testingcommon.NewFakeMetadataClient(c.existingResources...)
 			fakeClient := fakeworkclient.NewSimpleClientset(testingWork, testingAppliedWork)
 			informerFactory := workinformers.NewSharedInformerFactory(fakeClient, 5*time.Minute)
 			if err := informerFactory.Work().V1().ManifestWorks().Informer().GetStore().Add(testingWork); err != nil {
@@ -235,7 +234,7 @@ func TestSyncManifestWork(t *testing.T) {
 					*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus](
 					fakeClient.WorkV1().AppliedManifestWorks()),
 				appliedManifestWorkLister: informerFactory.Work().V1().AppliedManifestWorks().Lister(),
-				spokeDynamicClient:        fakeDynamicClient,
+				spokeMetadataClient:       fakeMetadataClient,
 				hubHash:                   "test",
 				rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(0, 1*time.Second),
 			}
@@ -248,7 +247,7 @@ func TestSyncManifestWork(t *testing.T) {
 			c.validateAppliedManifestWorkActions(t, fakeClient.Actions())
 
 			var deleteActions []clienttesting.DeleteActionImpl
-			for _, action := range fakeDynamicClient.Actions() {
+			for _, action := range fakeMetadataClient.Actions() {
 				if action.GetVerb() == "delete" {
 					deleteActions = append(deleteActions, action.(clienttesting.DeleteActionImpl))
 				}
@@ -14,6 +14,7 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 )
 
@@ -29,6 +30,7 @@ func NewAddFinalizerController(
 	manifestWorkClient workv1client.ManifestWorkInterface,
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
+	controllerHealth *health.ControllerHealth,
 ) factory.Controller {
 
 	controller := &AddFinalizerController{
@@ -40,7 +42,7 @@ func NewAddFinalizerController(
 
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, manifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("ManifestWorkAddFinalizerController", recorder)
+		WithSync(controllerHealth.ObserveSync(controller.sync)).ToController("ManifestWorkAddFinalizerController", recorder)
 }
 
 func (m *AddFinalizerController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -9,7 +9,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/events"
 	"k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -19,8 +19,11 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/audit"
+	"open-cluster-management.io/ocm/pkg/work/spoke/target"
 )
 
 // AppliedManifestWorkFinalizeController handles cleanup of appliedmanifestwork resources before deletion is allowed.
@@ -28,16 +31,19 @@ import (
 type AppliedManifestWorkFinalizeController struct {
 	patcher                   patcher.Patcher[*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus]
 	appliedManifestWorkLister worklister.AppliedManifestWorkLister
-	spokeDynamicClient        dynamic.Interface
+	spokeMetadataClient       metadata.Interface
+	targets                   *target.Resolver
 	rateLimiter               workqueue.RateLimiter
 }
 
 func NewAppliedManifestWorkFinalizeController(
 	recorder events.Recorder,
-	spokeDynamicClient dynamic.Interface,
+	spokeMetadataClient metadata.Interface,
+	targets *target.Resolver,
 	appliedManifestWorkClient workv1client.AppliedManifestWorkInterface,
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
 	agentID string,
+	controllerHealth *health.ControllerHealth,
 ) factory.Controller {
 
 	controller := &AppliedManifestWorkFinalizeController{
@@ -45,14 +51,15 @@ func NewAppliedManifestWorkFinalizeController(
 			*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus](
 			appliedManifestWorkClient),
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
-		spokeDynamicClient:        spokeDynamicClient,
+		spokeMetadataClient:       spokeMetadataClient,
+		targets:                   targets,
 		rateLimiter:               workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
 	}
 
 	return factory.New().
 		WithFilteredEventsInformersQueueKeysFunc(queue.QueueKeyByMetaName,
 			helper.AppliedManifestworkAgentIDFilter(agentID), appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("AppliedManifestWorkFinalizer", recorder)
+		WithSync(controllerHealth.ObserveSync(controller.sync)).ToController("AppliedManifestWorkFinalizer", recorder)
 }
 
 func (m *AppliedManifestWorkFinalizeController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -87,14 +94,20 @@ func (m *AppliedManifestWorkFinalizeController) syncAppliedManifestWork(ctx cont
 		return nil
 	}
 
-	owner := helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+	// the resources applied to a remote target cluster are deleted from the target cluster
+	metadataClient, err := m.targets.MetadataClient(appliedManifestWork, m.spokeMetadataClient)
+	if err != nil {
+		return err
+	}
+
+	owner := target.Owner(appliedManifestWork)
 
 	// Work is deleting, we remove its related resources on spoke cluster
 	// We still need to run delete for every resource even with ownerref on it, since ownerref does not handle cluster
 	// scoped resource correctly.
 	reason := fmt.Sprintf("manifestwork %s is terminating", appliedManifestWork.Spec.ManifestWorkName)
 	resourcesPendingFinalization, errs := helper.DeleteAppliedResources(
-		ctx, appliedManifestWork.Status.AppliedResources, reason, m.spokeDynamicClient, controllerContext.Recorder(), *owner)
+		audit.WithWork(ctx, appliedManifestWork.Spec.HubHash, appliedManifestWork.Spec.ManifestWorkName), appliedManifestWork.Status.AppliedResources, reason, metadataClient, controllerContext.Recorder(), owner)
 	appliedManifestWork.Status.AppliedResources = resourcesPendingFinalization
 	updatedAppliedManifestWork, err := m.patcher.PatchStatus(ctx, appliedManifestWork, appliedManifestWork.Status, originalManifestWork.Status)
 	if err != nil {
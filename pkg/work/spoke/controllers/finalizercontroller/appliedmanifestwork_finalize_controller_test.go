@@ -12,7 +12,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	fakedynamic "k8s.io/client-go/dynamic/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/util/workqueue"
 
@@ -37,21 +36,21 @@ func TestFinalize(t *testing.T) {
 		resourcesToRemove                  []workapiv1.AppliedManifestResourceMeta
 		terminated                         bool
 		validateAppliedManifestWorkActions func(t *testing.T, actions []clienttesting.Action)
-		validateDynamicActions             func(t *testing.T, actions []clienttesting.Action)
+		validateMetadataActions            func(t *testing.T, actions []clienttesting.Action)
 		expectedQueueLen                   int
 	}{
 		{
 			name:                               "skip when not delete",
 			existingFinalizers:                 []string{workapiv1.ManifestWorkFinalizer},
 			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
-			validateDynamicActions:             testingcommon.AssertNoActions,
+			validateMetadataActions:            testingcommon.AssertNoActions,
 		},
 		{
 			name:                               "skip when finalizer gone",
 			terminated:                         true,
 			existingFinalizers:                 []string{"other-finalizer"},
 			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
-			validateDynamicActions:             testingcommon.AssertNoActions,
+			validateMetadataActions:            testingcommon.AssertNoActions,
 		},
 		{
 			name:               "get resources and remove finalizer",
@@ -74,7 +73,7 @@ func TestFinalize(t *testing.T) {
 					t.Fatal(spew.Sdump(actions[0]))
 				}
 			},
-			validateDynamicActions: func(t *testing.T, actions []clienttesting.Action) {
+			validateMetadataActions: func(t *testing.T, actions []clienttesting.Action) {
 				if len(actions) != 4 {
 					t.Fatal(spew.Sdump(actions))
 				}
@@ -114,7 +113,7 @@ func TestFinalize(t *testing.T) {
 				{Version: "v1", ResourceIdentifier: workapiv1.ResourceIdentifier{Resource: "secrets", Namespace: "ns2", Name: "n2"}, UID: "ns2-n2"},
 			},
 			validateAppliedManifestWorkActions: testingcommon.AssertNoActions,
-			validateDynamicActions: func(t *testing.T, actions []clienttesting.Action) {
+			validateMetadataActions: func(t *testing.T, actions []clienttesting.Action) {
 				if len(actions) != 2 {
 					t.Fatal(spew.Sdump(actions))
 				}
@@ -154,7 +153,7 @@ func TestFinalize(t *testing.T) {
 					t.Fatal(spew.Sdump(actions[0]))
 				}
 			},
-			validateDynamicActions: func(t *testing.T, actions []clienttesting.Action) {
+			validateMetadataActions: func(t *testing.T, actions []clienttesting.Action) {
 				if len(actions) != 2 {
 					t.Fatal(spew.Sdump(actions))
 				}
@@ -184,14 +183,14 @@ func TestFinalize(t *testing.T) {
 			}
 			testingWork.Status.AppliedResources = append(testingWork.Status.AppliedResources, c.resourcesToRemove...)
 
-			fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existingResources...)
+			fakeMetadataClient := testingcommon.NewFakeMetadataClient(c.existingResources...)
 			fakeClient := fakeworkclient.NewSimpleClientset(testingWork)
 			controller := AppliedManifestWorkFinalizeController{
 				patcher: patcher.NewPatcher[
 					*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus](
 					fakeClient.WorkV1().AppliedManifestWorks()),
-				spokeDynamicClient: fakeDynamicClient,
-				rateLimiter:        workqueue.NewItemExponentialFailureRateLimiter(0, 1*time.Second),
+				spokeMetadataClient: fakeMetadataClient,
+				rateLimiter:         workqueue.NewItemExponentialFailureRateLimiter(0, 1*time.Second),
 			}
 
 			controllerContext := testingcommon.NewFakeSyncContext(t, testingWork.Name)
@@ -200,7 +199,7 @@ func TestFinalize(t *testing.T) {
 				t.Fatal(err)
 			}
 			c.validateAppliedManifestWorkActions(t, fakeClient.Actions())
-			c.validateDynamicActions(t, fakeDynamicClient.Actions())
+			c.validateMetadataActions(t, fakeMetadataClient.Actions())
 
 			queueLen := controllerContext.Queue().Len()
 			if queueLen != c.expectedQueueLen {
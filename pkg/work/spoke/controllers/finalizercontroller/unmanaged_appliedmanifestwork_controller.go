@@ -21,6 +21,7 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
 	"open-cluster-management.io/ocm/pkg/common/queue"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 )
@@ -30,6 +31,11 @@ const (
 	// If the AppliedManifestWork eviction grace period is set with a value that is larger than or equal to
 	// the bound, the eviction feature will be disabled.
 	EvictionGracePeriodBound = 100 * 365 * 24 * time.Hour
+
+	// AppliedManifestWorkEvictionHoldAnnotationKey is the annotation of an appliedmanifestwork to pause its
+	// eviction when it is set to "true", e.g. the appliedmanifestworks are held during a planned hub migration
+	// until the manifestworks are recreated on the new hub. The grace period restarts once the annotation is removed.
+	AppliedManifestWorkEvictionHoldAnnotationKey = "work.open-cluster-management.io/eviction-hold"
 )
 
 type unmanagedAppliedWorkController struct {
@@ -50,7 +56,9 @@ type unmanagedAppliedWorkController struct {
 //   - the appliedmanifestwork hub hash does not match the current hub hash of the work agent.
 //
 // One unmanaged appliedmanifestwork will be evicted from the managed cluster after a grace period (by
-// default, 60 minutes), after one appliedmanifestwork is evicted from the managed cluster, its owned
+// default, 60 minutes, it is set per klusterlet with the AppliedManifestWorkEvictionGracePeriod of the work
+// configuration), the eviction is paused if the appliedmanifestwork has the eviction hold annotation. After one
+// appliedmanifestwork is evicted from the managed cluster, its owned
 // resources will also be evicted from the managed cluster with Kubernetes garbage collection.
 func NewUnManagedAppliedWorkController(
 	recorder events.Recorder,
@@ -60,6 +68,7 @@ func NewUnManagedAppliedWorkController(
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
 	evictionGracePeriod time.Duration,
 	hubHash, agentID string,
+	controllerHealth *health.ControllerHealth,
 ) factory.Controller {
 	controller := &unmanagedAppliedWorkController{
 		manifestWorkLister:        manifestWorkLister,
@@ -82,7 +91,7 @@ func NewUnManagedAppliedWorkController(
 		WithFilteredEventsInformersQueueKeysFunc(
 			queue.QueueKeyByMetaName,
 			helper.AppliedManifestworkAgentIDFilter(agentID), appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ToController("UnManagedAppliedManifestWork", recorder)
+		WithSync(controllerHealth.ObserveSync(controller.sync)).ToController("UnManagedAppliedManifestWork", recorder)
 }
 
 func (m *unmanagedAppliedWorkController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -104,6 +113,12 @@ func (m *unmanagedAppliedWorkController) sync(ctx context.Context, controllerCon
 		return m.stopToEvictAppliedManifestWork(ctx, appliedManifestWork)
 	}
 
+	// Do NOT evict the AppliedManifestWork if its eviction is held
+	if appliedManifestWork.Annotations[AppliedManifestWorkEvictionHoldAnnotationKey] == "true" {
+		klog.V(4).Infof("The eviction of AppliedManifestWork %q is held", appliedManifestWorkName)
+		return m.stopToEvictAppliedManifestWork(ctx, appliedManifestWork)
+	}
+
 	_, err = m.manifestWorkLister.Get(appliedManifestWork.Spec.ManifestWorkName)
 	if errors.IsNotFound(err) {
 		// evict the current appliedmanifestwork when its relating manifestwork is missing on the hub
@@ -0,0 +1,180 @@
+package manifestcontroller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// AppliedManifestsAnnotationKey is the annotation on the AppliedManifestWork recording the manifests applied in the
+// last sync and the state of the resources right after they were applied.
+const AppliedManifestsAnnotationKey = "work.open-cluster-management.io/experimental-applied-manifests"
+
+// maxAppliedManifestsLength keeps the annotation well below the size limit of the annotations of an object, the
+// manifests are not recorded and always applied for the works exceeding it.
+const maxAppliedManifestsLength = 128 * 1024
+
+// appliedManifests is the content of the AppliedManifestsAnnotationKey annotation. A manifest is skipped on resync if
+// its hash is recorded and the resource on the managed cluster still has the recorded fingerprint, so the unchanged
+// manifests of a static work do not cause writes to the apiserver of the managed cluster.
+type appliedManifests struct {
+	// Generation is the generation of the manifestwork when the manifests were recorded.
+	Generation int64 `json:"generation"`
+	// Manifests maps the hash of an applied manifest to the fingerprint of the resource after it was applied.
+	Manifests map[string]string `json:"manifests,omitempty"`
+}
+
+// getAppliedManifests returns the manifests recorded on the appliedmanifestwork. Nothing is skipped if the
+// annotation is not set or invalid.
+func getAppliedManifests(appliedManifestWork *workapiv1.AppliedManifestWork) *appliedManifests {
+	applied := &appliedManifests{}
+	value, ok := appliedManifestWork.Annotations[AppliedManifestsAnnotationKey]
+	if !ok {
+		return applied
+	}
+	if err := json.Unmarshal([]byte(value), applied); err != nil {
+		klog.Warningf("Ignore the invalid annotation %s of appliedmanifestwork %s: %v",
+			AppliedManifestsAnnotationKey, appliedManifestWork.Name, err)
+		return &appliedManifests{}
+	}
+	return applied
+}
+
+// isUnchanged returns true if the manifest with the hash was applied and the resource is not changed since then.
+func (a *appliedManifests) isUnchanged(hash string, existing runtime.Object) bool {
+	fingerprint, ok := a.Manifests[hash]
+	return ok && len(fingerprint) > 0 && fingerprint == resourceFingerprint(existing)
+}
+
+// manifestHash returns the hash of a manifest together with everything else deciding how it is applied.
+func manifestHash(manifest workapiv1.Manifest, option *workapiv1.ManifestConfigOption, owner metav1.OwnerReference,
+	ignoredFields [][]string) (string, error) {
+	config, err := json.Marshal(struct {
+		Option        *workapiv1.ManifestConfigOption `json:"option,omitempty"`
+		Owner         metav1.OwnerReference           `json:"owner"`
+		IgnoredFields [][]string                      `json:"ignoredFields,omitempty"`
+	}{Option: option, Owner: owner, IgnoredFields: ignoredFields})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(manifest.Raw)
+	hash.Write(config)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// resourceFingerprint identifies the state of a resource. The generation and the metadata are used if the resource
+// has a generation, otherwise the hash of the content other than the status and the fields maintained by the
+// apiserver is used, since the resource version is also changed by the status updates. An empty string is returned
+// if the object is not read from the apiserver or the fingerprint cannot be built.
+func resourceFingerprint(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil || len(accessor.GetResourceVersion()) == 0 {
+		return ""
+	}
+	if accessor.GetGeneration() > 0 {
+		metadata, err := json.Marshal([]interface{}{
+			accessor.GetLabels(), accessor.GetAnnotations(), accessor.GetOwnerReferences()})
+		if err != nil {
+			return ""
+		}
+		hash := sha256.Sum256(metadata)
+		return fmt.Sprintf("%d-%s", accessor.GetGeneration(), hex.EncodeToString(hash[:8]))
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return ""
+	}
+	// the typed objects returned by the appliers have no type meta
+	for _, field := range [][]string{
+		{"apiVersion"}, {"kind"}, {"status"}, {"metadata", "resourceVersion"}, {"metadata", "managedFields"}} {
+		unstructured.RemoveNestedField(content, field...)
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:16])
+}
+
+// updateAppliedManifests records the manifests applied successfully on the appliedmanifestwork.
+func (m *ManifestWorkController) updateAppliedManifests(
+	ctx context.Context,
+	appliedManifestWork *workapiv1.AppliedManifestWork,
+	generation int64,
+	results []applyResult) error {
+	applied := &appliedManifests{Generation: generation, Manifests: map[string]string{}}
+	for _, result := range results {
+		if result.Error != nil || len(result.manifestHash) == 0 || result.Result == nil {
+			continue
+		}
+		if fingerprint := resourceFingerprint(result.Result); len(fingerprint) > 0 {
+			applied.Manifests[result.manifestHash] = fingerprint
+		}
+	}
+
+	value, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	if len(value) > maxAppliedManifestsLength {
+		klog.V(4).Infof("Skip recording the applied manifests of appliedmanifestwork %s with %d bytes",
+			appliedManifestWork.Name, len(value))
+		applied.Manifests = map[string]string{}
+		if value, err = json.Marshal(applied); err != nil {
+			return err
+		}
+	}
+
+	// the generation alone is not worth a write
+	if equality.Semantic.DeepEqual(applied.Manifests, getAppliedManifests(appliedManifestWork).Manifests) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AppliedManifestsAnnotationKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	patched, err := m.appliedManifestWorkClient.Patch(
+		ctx, appliedManifestWork.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	m.recordedVersions.Store(patched.Name, patched.ResourceVersion)
+	return nil
+}
+
+// appliedManifestWorkFilter filters the appliedmanifestworks of the hub, and ignores the events caused by the
+// controller recording the applied manifests, so the record does not trigger another sync of the manifestwork.
+func (m *ManifestWorkController) appliedManifestWorkFilter(obj interface{}) bool {
+	if !helper.AppliedManifestworkHubHashFilter(m.hubHash)(obj) {
+		return false
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return true
+	}
+	version, ok := m.recordedVersions.Load(accessor.GetName())
+	return !ok || version != accessor.GetResourceVersion()
+}
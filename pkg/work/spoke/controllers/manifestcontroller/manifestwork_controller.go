@@ -3,11 +3,14 @@ package manifestcontroller
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -18,8 +21,10 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
 	workinformer "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
@@ -27,16 +32,22 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
-	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/common/health"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
+	"open-cluster-management.io/ocm/pkg/work/spoke/audit"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
+	"open-cluster-management.io/ocm/pkg/work/spoke/metrics"
+	"open-cluster-management.io/ocm/pkg/work/spoke/target"
 )
 
 var (
 	ResyncInterval     = 5 * time.Minute
 	MaxRequeueDuration = 24 * time.Hour
+	// TargetRequeueDelay is the delay to sync a manifestwork again whose remote target cluster is not available.
+	TargetRequeueDelay = time.Minute
 )
 
 // ManifestWorkController is to reconcile the workload resources
@@ -48,11 +59,18 @@ type ManifestWorkController struct {
 	appliedManifestWorkPatcher patcher.Patcher[*workapiv1.AppliedManifestWork, workapiv1.AppliedManifestWorkSpec, workapiv1.AppliedManifestWorkStatus]
 	appliedManifestWorkLister  worklister.AppliedManifestWorkLister
 	spokeDynamicClient         dynamic.Interface
+	spokeMetadataClient        metadata.Interface
 	hubHash                    string
 	agentID                    string
 	restMapper                 meta.RESTMapper
 	appliers                   *apply.Appliers
 	validator                  auth.ExecutorValidator
+	targets                    *target.Resolver
+	metrics                    *metrics.WorkMetrics
+	pendingWorks               *pendingWorks
+	// recordedVersions maps the name of an appliedmanifestwork to its resource version after the applied manifests
+	// are recorded by the controller.
+	recordedVersions sync.Map
 }
 
 type applyResult struct {
@@ -60,12 +78,14 @@ type applyResult struct {
 	Error  error
 
 	resourceMeta workapiv1.ManifestResourceMeta
+	manifestHash string
 }
 
 // NewManifestWorkController returns a ManifestWorkController
 func NewManifestWorkController(
 	recorder events.Recorder,
 	spokeDynamicClient dynamic.Interface,
+	spokeMetadataClient metadata.Interface,
 	spokeKubeClient kubernetes.Interface,
 	spokeAPIExtensionClient apiextensionsclient.Interface,
 	manifestWorkClient workv1client.ManifestWorkInterface,
@@ -75,7 +95,9 @@ func NewManifestWorkController(
 	appliedManifestWorkInformer workinformer.AppliedManifestWorkInformer,
 	hubHash, agentID string,
 	restMapper meta.RESTMapper,
-	validator auth.ExecutorValidator) factory.Controller {
+	validator auth.ExecutorValidator,
+	targets *target.Resolver,
+	controllerHealth *health.ControllerHealth) factory.Controller {
 
 	controller := &ManifestWorkController{
 		manifestWorkPatcher: patcher.NewPatcher[
@@ -88,20 +110,24 @@ func NewManifestWorkController(
 			appliedManifestWorkClient),
 		appliedManifestWorkLister: appliedManifestWorkInformer.Lister(),
 		spokeDynamicClient:        spokeDynamicClient,
+		spokeMetadataClient:       spokeMetadataClient,
 		hubHash:                   hubHash,
 		agentID:                   agentID,
 		restMapper:                restMapper,
 		appliers:                  apply.NewAppliers(spokeDynamicClient, spokeKubeClient, spokeAPIExtensionClient),
 		validator:                 validator,
+		targets:                   targets,
+		metrics:                   metrics.NewWorkMetrics(clock.RealClock{}),
+		pendingWorks:              newPendingWorks(),
 	}
 
 	return factory.New().
-		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, manifestWorkInformer.Informer()).
+		WithInformersQueueKeysFunc(controller.pendingWorks.queueKeysFunc, manifestWorkInformer.Informer()).
 		WithFilteredEventsInformersQueueKeyFunc(
 			helper.AppliedManifestworkQueueKeyFunc(hubHash),
-			helper.AppliedManifestworkHubHashFilter(hubHash),
+			controller.appliedManifestWorkFilter,
 			appliedManifestWorkInformer.Informer()).
-		WithSync(controller.sync).ResyncEvery(ResyncInterval).ToController("ManifestWorkAgent", recorder)
+		WithSync(controllerHealth.ObserveSync(controller.sync)).ResyncEvery(ResyncInterval).ToController("ManifestWorkAgent", recorder)
 }
 
 // sync is the main reconcile loop for manifest work. It is triggered in two scenarios
@@ -110,10 +136,13 @@ func NewManifestWorkController(
 func (m *ManifestWorkController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
 	manifestWorkName := controllerContext.QueueKey()
 	klog.V(4).Infof("Reconciling ManifestWork %q", manifestWorkName)
+	m.pendingWorks.done(manifestWorkName)
 
 	oldManifestWork, err := m.manifestWorkLister.Get(manifestWorkName)
 	if apierrors.IsNotFound(err) {
 		// work not found, could have been deleted, do nothing.
+		m.metrics.Forget(manifestWorkName)
+		m.recordedVersions.Delete(fmt.Sprintf("%s-%s", m.hubHash, manifestWorkName))
 		return nil
 	}
 	if err != nil {
@@ -123,6 +152,7 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 
 	// no work to do if we're deleted
 	if !manifestWork.DeletionTimestamp.IsZero() {
+		m.metrics.Forget(manifestWorkName)
 		return nil
 	}
 
@@ -132,21 +162,91 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 		return nil
 	}
 
+	// the manifests of a paused manifestwork are not applied, so the changes on the managed cluster are kept
+	if isWorkPaused(manifestWork) {
+		klog.V(4).Infof("Skip applying the paused ManifestWork %q", manifestWorkName)
+		meta.SetStatusCondition(&manifestWork.Status.Conditions, metav1.Condition{
+			Type:               WorkPaused,
+			ObservedGeneration: manifestWork.Generation,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ManifestWorkPaused",
+			Message:            fmt.Sprintf("The reconciliation is paused by the annotation %s", ManifestWorkPausedAnnotationKey),
+		})
+		_, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status)
+		return err
+	}
+	meta.RemoveStatusCondition(&manifestWork.Status.Conditions, WorkPaused)
+
+	// the manifestworks with a higher priority are applied first
+	if m.pendingWorks.hasHigherPriority(workPriority(manifestWork)) {
+		klog.V(4).Infof("Defer ManifestWork %q for the manifestworks with a higher priority", manifestWorkName)
+		controllerContext.Queue().AddAfter(manifestWorkName, PriorityRequeueDelay)
+		return nil
+	}
+
+	// the manifests are not applied with invalid ignore differences rules, since the ignored fields would be reverted
+	ignoreRules, err := helper.GetIgnoreDifferencesRules(manifestWork)
+	if err != nil {
+		meta.SetStatusCondition(&manifestWork.Status.Conditions, metav1.Condition{
+			Type:               workapiv1.WorkApplied,
+			ObservedGeneration: manifestWork.Generation,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidIgnoreDifferencesRules",
+			Message:            err.Error(),
+		})
+		_, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status)
+		return err
+	}
+
+	// the changes on the managed cluster are audited for the manifestwork
+	ctx = audit.WithWork(ctx, m.hubHash, manifestWorkName)
+
+	// start to track the spec to applied duration if the current generation is not applied yet
+	if !meta.IsStatusConditionTrue(manifestWork.Status.Conditions, workapiv1.WorkApplied) ||
+		meta.FindStatusCondition(manifestWork.Status.Conditions, workapiv1.WorkApplied).ObservedGeneration != manifestWork.Generation {
+		// only the first apply of a generation is traced, the retries and the resyncs of the same generation are not
+		if m.metrics.ObserveSpec(manifestWorkName, manifestWork.Generation) {
+			// the span marks when the generation is received by the agent, the gap to the span applying the
+			// manifestwork on the hub is the time the transport takes to deliver it.
+			_, receiveSpan := tracing.StartWorkSpan(ctx, "ReceiveManifestWork", manifestWork)
+			receiveSpan.End()
+
+			var span trace.Span
+			ctx, span = tracing.StartWorkSpan(ctx, "ApplyManifestWork", manifestWork)
+			defer span.End()
+		}
+	}
+
+	// the manifests are applied to the remote target cluster if the manifestwork references its kubeconfig secret
+	clients, err := m.targetClients(manifestWork)
+	if err != nil {
+		return m.patchTargetFailure(ctx, controllerContext, oldManifestWork, manifestWork, "TargetClusterUnavailable", err)
+	}
+
 	// Apply appliedManifestWork
-	appliedManifestWork, err := m.applyAppliedManifestWork(ctx, manifestWork.Name, m.hubHash, m.agentID)
+	appliedManifestWork, err := m.applyAppliedManifestWork(ctx, manifestWork, m.hubHash, m.agentID)
 	if err != nil {
 		return err
 	}
+	// the applied resources are deleted from the cluster recorded on the appliedmanifestwork, so the target
+	// cluster cannot be changed once the manifestwork is applied.
+	if target.SecretName(appliedManifestWork) != target.SecretName(manifestWork) {
+		return m.patchTargetFailure(ctx, controllerContext, oldManifestWork, manifestWork, "TargetClusterChanged",
+			fmt.Errorf("the target cluster cannot be changed from the kubeconfig secret %q to %q",
+				target.SecretName(appliedManifestWork), target.SecretName(manifestWork)))
+	}
 
 	// We creat a ownerref instead of controller ref since multiple controller can declare the ownership of a manifests
 	owner := helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+	lastApplied := getAppliedManifests(appliedManifestWork)
 
 	var errs []error
 	// Apply resources on spoke cluster.
 	resourceResults := make([]applyResult, len(manifestWork.Spec.Workload.Manifests))
 	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		resourceResults = m.applyManifests(
-			ctx, manifestWork.Spec.Workload.Manifests, manifestWork.Spec, controllerContext.Recorder(), *owner, resourceResults)
+			ctx, clients, manifestWork.Spec.Workload.Manifests, manifestWork.Spec, ignoreRules, controllerContext.Recorder(),
+			*owner, lastApplied, resourceResults)
 
 		for _, result := range resourceResults {
 			if apierrors.IsConflict(result.Error) {
@@ -160,6 +260,10 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 		klog.Errorf("failed to apply resource with error %v", err)
 	}
 
+	if err := m.updateAppliedManifests(ctx, appliedManifestWork, manifestWork.Generation, resourceResults); err != nil {
+		errs = append(errs, fmt.Errorf("failed to record the applied manifests with err %w", err))
+	}
+
 	var newManifestConditions []workapiv1.ManifestCondition
 	var requeueTime = MaxRequeueDuration
 	for _, result := range resourceResults {
@@ -215,6 +319,8 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 	updated, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to update work status with err %w", err))
+	} else if meta.IsStatusConditionTrue(manifestWork.Status.Conditions, workapiv1.WorkApplied) {
+		m.metrics.Applied(manifestWorkName, manifestWork.Generation)
 	}
 
 	if !updated && requeueTime < MaxRequeueDuration {
@@ -229,8 +335,53 @@ func (m *ManifestWorkController) sync(ctx context.Context, controllerContext fac
 	return err
 }
 
-func (m *ManifestWorkController) applyAppliedManifestWork(ctx context.Context, workName, hubHash, agentID string) (*workapiv1.AppliedManifestWork, error) {
-	appliedManifestWorkName := fmt.Sprintf("%s-%s", m.hubHash, workName)
+// targetClients returns the clients of the cluster the manifests of the manifestwork are applied to.
+func (m *ManifestWorkController) targetClients(manifestWork *workapiv1.ManifestWork) (*target.Clients, error) {
+	secretName := target.SecretName(manifestWork)
+	if len(secretName) == 0 {
+		return &target.Clients{
+			RESTMapper:     m.restMapper,
+			DynamicClient:  m.spokeDynamicClient,
+			MetadataClient: m.spokeMetadataClient,
+			Appliers:       m.appliers,
+		}, nil
+	}
+	// the permissions of the executor are reviewed on the managed cluster, they do not apply to the remote target
+	if manifestWork.Spec.Executor != nil {
+		return nil, fmt.Errorf("the executor is not supported with the remote target cluster")
+	}
+	// the resources on the remote target cluster are not owned by the appliedmanifestwork, so all of them are
+	// deleted with the manifestwork
+	if deleteOption := manifestWork.Spec.DeleteOption; deleteOption != nil &&
+		deleteOption.PropagationPolicy != workapiv1.DeletePropagationPolicyTypeForeground {
+		return nil, fmt.Errorf("the delete propagation policy %s is not supported with the remote target cluster",
+			deleteOption.PropagationPolicy)
+	}
+	return m.targets.Clients(secretName)
+}
+
+// patchTargetFailure reports the manifestwork is not applied since its target cluster is not available, the
+// manifestwork is synced again after a while since the changes of the kubeconfig secrets are not watched.
+func (m *ManifestWorkController) patchTargetFailure(ctx context.Context, controllerContext factory.SyncContext,
+	oldManifestWork, manifestWork *workapiv1.ManifestWork, reason string, err error) error {
+	klog.V(2).Infof("The target cluster of ManifestWork %q is not available: %v", manifestWork.Name, err)
+	meta.SetStatusCondition(&manifestWork.Status.Conditions, metav1.Condition{
+		Type:               workapiv1.WorkApplied,
+		ObservedGeneration: manifestWork.Generation,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            err.Error(),
+	})
+	if _, err := m.manifestWorkPatcher.PatchStatus(ctx, manifestWork, manifestWork.Status, oldManifestWork.Status); err != nil {
+		return err
+	}
+	controllerContext.Queue().AddAfter(manifestWork.Name, TargetRequeueDelay)
+	return nil
+}
+
+func (m *ManifestWorkController) applyAppliedManifestWork(ctx context.Context, manifestWork *workapiv1.ManifestWork,
+	hubHash, agentID string) (*workapiv1.AppliedManifestWork, error) {
+	appliedManifestWorkName := fmt.Sprintf("%s-%s", m.hubHash, manifestWork.Name)
 	requiredAppliedWork := &workapiv1.AppliedManifestWork{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:       appliedManifestWorkName,
@@ -238,10 +389,13 @@ func (m *ManifestWorkController) applyAppliedManifestWork(ctx context.Context, w
 		},
 		Spec: workapiv1.AppliedManifestWorkSpec{
 			HubHash:          hubHash,
-			ManifestWorkName: workName,
+			ManifestWorkName: manifestWork.Name,
 			AgentID:          agentID,
 		},
 	}
+	if secretName := target.SecretName(manifestWork); len(secretName) > 0 {
+		requiredAppliedWork.Annotations = map[string]string{target.TargetKubeconfigSecretAnnotationKey: secretName}
+	}
 
 	appliedManifestWork, err := m.appliedManifestWorkLister.Get(appliedManifestWorkName)
 	switch {
@@ -258,20 +412,23 @@ func (m *ManifestWorkController) applyAppliedManifestWork(ctx context.Context, w
 
 func (m *ManifestWorkController) applyManifests(
 	ctx context.Context,
+	clients *target.Clients,
 	manifests []workapiv1.Manifest,
 	workSpec workapiv1.ManifestWorkSpec,
+	ignoreRules []helper.IgnoreDifferencesRule,
 	recorder events.Recorder,
 	owner metav1.OwnerReference,
+	lastApplied *appliedManifests,
 	existingResults []applyResult) []applyResult {
 
 	for index, manifest := range manifests {
 		switch {
 		case existingResults[index].Result == nil:
 			// Apply if there is no result.
-			existingResults[index] = m.applyOneManifest(ctx, index, manifest, workSpec, recorder, owner)
+			existingResults[index] = m.applyOneManifest(ctx, clients, index, manifest, workSpec, ignoreRules, recorder, owner, lastApplied)
 		case apierrors.IsConflict(existingResults[index].Error):
 			// Apply if there is a resource conflict error.
-			existingResults[index] = m.applyOneManifest(ctx, index, manifest, workSpec, recorder, owner)
+			existingResults[index] = m.applyOneManifest(ctx, clients, index, manifest, workSpec, ignoreRules, recorder, owner, lastApplied)
 		}
 	}
 
@@ -280,16 +437,35 @@ func (m *ManifestWorkController) applyManifests(
 
 func (m *ManifestWorkController) applyOneManifest(
 	ctx context.Context,
+	clients *target.Clients,
 	index int,
 	manifest workapiv1.Manifest,
 	workSpec workapiv1.ManifestWorkSpec,
+	ignoreRules []helper.IgnoreDifferencesRule,
 	recorder events.Recorder,
-	owner metav1.OwnerReference) applyResult {
-
-	result := applyResult{}
+	owner metav1.OwnerReference,
+	lastApplied *appliedManifests) (result applyResult) {
 
 	// parse the required and set resource meta
 	required := &unstructured.Unstructured{}
+	skipped := false
+	start := m.metrics.Now()
+	ctx, span := tracing.Start(ctx, "ApplyManifest", attribute.Int("ocm.manifest.index", index))
+	defer func() {
+		if skipped {
+			m.metrics.ManifestSkipped(required.GroupVersionKind())
+		} else {
+			m.metrics.ManifestApplied(required.GroupVersionKind(), start, applyErrorReason(result.Error), result.Error)
+		}
+		span.SetAttributes(
+			attribute.Bool("ocm.manifest.skipped", skipped),
+			attribute.String("ocm.manifest.kind", required.GetKind()),
+			attribute.String("ocm.manifest.namespace", required.GetNamespace()),
+			attribute.String("ocm.manifest.name", required.GetName()),
+		)
+		tracing.EndWithError(span, result.Error)
+	}()
+
 	if err := required.UnmarshalJSON(manifest.Raw); err != nil {
 		result.Error = err
 		return result
@@ -301,7 +477,7 @@ func (m *ManifestWorkController) applyOneManifest(
 		required.SetUID("")
 	}
 
-	resMeta, gvr, err := helper.BuildResourceMeta(index, required, m.restMapper)
+	resMeta, gvr, err := helper.BuildResourceMeta(index, required, clients.RESTMapper)
 	result.resourceMeta = resMeta
 	if err != nil {
 		result.Error = err
@@ -318,8 +494,9 @@ func (m *ManifestWorkController) applyOneManifest(
 		return result
 	}
 
-	// compute required ownerrefs based on delete option
-	requiredOwner := manageOwnerRef(ownedByTheWork, owner)
+	// compute required ownerrefs based on delete option, the resources on a remote target cluster are not owned by
+	// the appliedmanifestwork on the managed cluster.
+	requiredOwner := manageOwnerRef(ownedByTheWork && !clients.Remote, owner)
 
 	// find update strategy option.
 	option := helper.FindManifestConiguration(resMeta, workSpec.ManifestConfigs)
@@ -329,12 +506,54 @@ func (m *ManifestWorkController) applyOneManifest(
 		strategy = *option.UpdateStrategy
 	}
 
-	applier := m.appliers.GetApplier(strategy.Type)
-	result.Result, result.Error = applier.Apply(ctx, gvr, required, requiredOwner, option, recorder)
+	ignoredFields := helper.FindIgnoredFields(resMeta, ignoreRules)
+	result.manifestHash, err = manifestHash(manifest, option, requiredOwner, ignoredFields)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	// skip the apply if the manifest is applied already and the resource is not changed since then, the
+	// resource is applied again if it cannot be fetched.
+	if _, ok := lastApplied.Manifests[result.manifestHash]; ok {
+		var existing runtime.Object
+		existing, err = clients.MetadataClient.Resource(gvr).Namespace(resMeta.Namespace).Get(ctx, resMeta.Name, metav1.GetOptions{})
+		// the resources without generation are fingerprinted by the content, which is not in the metadata
+		if err == nil && existing.(metav1.Object).GetGeneration() == 0 {
+			existing, err = clients.DynamicClient.Resource(gvr).Namespace(resMeta.Namespace).Get(ctx, resMeta.Name, metav1.GetOptions{})
+		}
+		if err == nil && lastApplied.isUnchanged(result.manifestHash, existing) {
+			skipped = true
+			result.Result = existing
+			return result
+		}
+	}
+
+	applier := clients.Appliers.GetApplier(strategy.Type)
+	result.Result, result.Error = applier.Apply(apply.WithIgnoredFields(ctx, ignoredFields), gvr, required, requiredOwner, option, recorder)
 
 	return result
 }
 
+// applyErrorReason returns the reason of the error applying a manifest for the metrics.
+func applyErrorReason(err error) string {
+	var authError *basic.NotAllowedError
+	var ssaConflict *apply.ServerSideApplyConflictError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &authError):
+		return "NotAllowed"
+	case errors.As(err, &ssaConflict):
+		return "ServerSideApplyConflict"
+	}
+
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return "Unknown"
+}
+
 // manageOwnerRef return a ownerref based on the resource and the ownedByTheWork indicating whether the owneref
 // should be removed or added. If the resource is not owned by the work, the owner's UID is updated for removal.
 func manageOwnerRef(
@@ -368,6 +587,17 @@ func allInCondition(conditionType string, manifests []workapiv1.ManifestConditio
 }
 
 func buildAppliedStatusCondition(result applyResult) metav1.Condition {
+	// the conflicting fields and their managers are in the message, so the ownership of the fields can be resolved
+	// without the access to the managed cluster.
+	var ssaConflict *apply.ServerSideApplyConflictError
+	if errors.As(result.Error, &ssaConflict) {
+		return metav1.Condition{
+			Type:    workapiv1.ManifestApplied,
+			Status:  metav1.ConditionFalse,
+			Reason:  "AppliedManifestFailed",
+			Message: fmt.Sprintf("Failed to apply manifest: %s", ssaConflict.ConflictsMessage()),
+		}
+	}
 	if result.Error != nil {
 		return metav1.Condition{
 			Type:    workapiv1.ManifestApplied,
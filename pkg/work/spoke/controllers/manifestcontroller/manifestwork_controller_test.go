@@ -17,7 +17,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
+	kubeinformers "k8s.io/client-go/informers"
 	fakekube "k8s.io/client-go/kubernetes/fake"
+	fakemetadata "k8s.io/client-go/metadata/fake"
 	clienttesting "k8s.io/client-go/testing"
 
 	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
@@ -30,15 +32,17 @@ import (
 	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+	"open-cluster-management.io/ocm/pkg/work/spoke/target"
 )
 
 const defaultOwner = "testowner"
 
 type testController struct {
-	controller    *ManifestWorkController
-	dynamicClient *fakedynamic.FakeDynamicClient
-	workClient    *fakeworkclient.Clientset
-	kubeClient    *fakekube.Clientset
+	controller     *ManifestWorkController
+	dynamicClient  *fakedynamic.FakeDynamicClient
+	metadataClient *fakemetadata.FakeMetadataClient
+	workClient     *fakeworkclient.Clientset
+	kubeClient     *fakekube.Clientset
 }
 
 func newController(t *testing.T, work *workapiv1.ManifestWork, appliedWork *workapiv1.AppliedManifestWork, mapper meta.RESTMapper) *testController {
@@ -90,6 +94,8 @@ func (t *testController) withUnstructuredObject(objects ...runtime.Object) *test
 	dynamicClient := fakedynamic.NewSimpleDynamicClient(scheme, objects...)
 	t.controller.spokeDynamicClient = dynamicClient
 	t.dynamicClient = dynamicClient
+	t.metadataClient = testingcommon.NewFakeMetadataClient(objects...)
+	t.controller.spokeMetadataClient = t.metadataClient
 	return t
 }
 
@@ -783,3 +789,383 @@ func TestManageOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipUnchangedManifests(t *testing.T) {
+	cases := []struct {
+		name                      string
+		recordedValue             string
+		expectedMetadataAction    []string
+		expectedDynamicAction     []string
+		expectedAppliedWorkAction []string
+	}{
+		{
+			name:                   "resource is not changed",
+			recordedValue:          "val2",
+			expectedMetadataAction: []string{"get"},
+			expectedDynamicAction:  []string{"get"},
+		},
+		{
+			name:                      "resource is changed",
+			recordedValue:             "val1",
+			expectedMetadataAction:    []string{"get"},
+			expectedDynamicAction:     []string{"get", "get", "update"},
+			expectedAppliedWorkAction: []string{"patch"},
+		},
+		{
+			name:                      "manifest is not recorded",
+			expectedDynamicAction:     []string{"get", "update"},
+			expectedAppliedWorkAction: []string{"patch"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, workKey := spoketesting.NewManifestWork(0, testingcommon.NewUnstructuredWithContent(
+				"v1", "NewObject", "ns1", "n1", map[string]interface{}{"spec": map[string]interface{}{"key1": "val1"}}))
+			work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			appliedWork := spoketesting.NewAppliedManifestWork("", 0, "test")
+			spokeObject := testingcommon.NewUnstructuredWithContent(
+				"v1", "NewObject", "ns1", "n1", map[string]interface{}{"spec": map[string]interface{}{"key1": "val2"}})
+			spokeObject.SetResourceVersion("1")
+			if len(c.recordedValue) > 0 {
+				recordedObject := testingcommon.NewUnstructuredWithContent(
+					"v1", "NewObject", "ns1", "n1", map[string]interface{}{"spec": map[string]interface{}{"key1": c.recordedValue}})
+				recordedObject.SetResourceVersion("0")
+				hash, err := manifestHash(work.Spec.Workload.Manifests[0], nil, *helper.NewAppliedManifestWorkOwner(appliedWork), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				value, err := json.Marshal(&appliedManifests{Manifests: map[string]string{hash: resourceFingerprint(recordedObject)}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				appliedWork.Annotations = map[string]string{AppliedManifestsAnnotationKey: string(value)}
+			}
+
+			controller := newController(t, work, appliedWork, spoketesting.NewFakeRestMapper()).
+				withKubeObject().
+				withUnstructuredObject(spokeObject)
+			if err := controller.workClient.Tracker().Add(appliedWork); err != nil {
+				t.Fatal(err)
+			}
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+				t.Errorf("Should be success with no err: %v", err)
+			}
+
+			testingcommon.AssertActions(t, controller.metadataClient.Actions(), c.expectedMetadataAction...)
+			testingcommon.AssertActions(t, controller.dynamicClient.Actions(), c.expectedDynamicAction...)
+			var appliedWorkActions []clienttesting.Action
+			for _, action := range controller.workClient.Actions() {
+				if action.GetResource().Resource == "appliedmanifestworks" {
+					appliedWorkActions = append(appliedWorkActions, action)
+				}
+			}
+			testingcommon.AssertActions(t, appliedWorkActions, c.expectedAppliedWorkAction...)
+			if len(appliedWorkActions) == 0 {
+				return
+			}
+
+			patchedWork := &workapiv1.AppliedManifestWork{}
+			if err := json.Unmarshal(appliedWorkActions[0].(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+				t.Fatal(err)
+			}
+			applied := getAppliedManifests(patchedWork)
+			if len(applied.Manifests) != 1 {
+				t.Errorf("expected one manifest recorded, but got %v", applied)
+			}
+
+			// the record written by the controller does not enqueue the manifestwork again
+			recorded, err := controller.workClient.WorkV1().AppliedManifestWorks().Get(context.TODO(), appliedWork.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if controller.controller.appliedManifestWorkFilter(recorded) {
+				t.Errorf("expected the recorded appliedmanifestwork is filtered")
+			}
+			recorded.ResourceVersion += "1"
+			if !controller.controller.appliedManifestWorkFilter(recorded) {
+				t.Errorf("expected the changed appliedmanifestwork is not filtered")
+			}
+		})
+	}
+}
+
+func TestResourceFingerprint(t *testing.T) {
+	obj := testingcommon.NewUnstructuredWithContent("v1", "ConfigMap", "ns1", "cm1",
+		map[string]interface{}{"data": map[string]interface{}{"key1": "val1"}})
+	obj.SetResourceVersion("1")
+	fingerprint := resourceFingerprint(obj)
+	if len(fingerprint) == 0 {
+		t.Errorf("expected the fingerprint of the content")
+	}
+	// the resource version is not a part of the fingerprint
+	obj.SetResourceVersion("2")
+	if actual := resourceFingerprint(obj); actual != fingerprint {
+		t.Errorf("expected the fingerprint %q is not changed, but got %q", fingerprint, actual)
+	}
+	if err := unstructured.SetNestedField(obj.Object, "val2", "data", "key1"); err != nil {
+		t.Fatal(err)
+	}
+	if actual := resourceFingerprint(obj); actual == fingerprint {
+		t.Errorf("expected the fingerprint is changed with the content")
+	}
+
+	obj = testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy1")
+	obj.SetResourceVersion("1")
+	obj.SetGeneration(1)
+	fingerprint = resourceFingerprint(obj)
+	// the status update changes the resource version only
+	obj.SetResourceVersion("2")
+	if actual := resourceFingerprint(obj); actual != fingerprint {
+		t.Errorf("expected the fingerprint %q is not changed, but got %q", fingerprint, actual)
+	}
+	obj.SetLabels(map[string]string{"app": "test"})
+	if actual := resourceFingerprint(obj); actual == fingerprint {
+		t.Errorf("expected the fingerprint is changed with the labels")
+	}
+}
+
+func TestWorkPriority(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expected    int
+	}{
+		{name: "not set", expected: 0},
+		{name: "valid", annotations: map[string]string{ManifestWorkPriorityAnnotationKey: "50"}, expected: 50},
+		{name: "not a number", annotations: map[string]string{ManifestWorkPriorityAnnotationKey: "high"}, expected: 0},
+		{name: "out of range", annotations: map[string]string{ManifestWorkPriorityAnnotationKey: "101"}, expected: 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, _ := spoketesting.NewManifestWork(0)
+			work.Annotations = c.annotations
+			if actual := workPriority(work); actual != c.expected {
+				t.Errorf("expected priority %d, but got %d", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPendingWorks(t *testing.T) {
+	pending := newPendingWorks()
+	pending.add("work1", 10)
+	pending.add("work2", 50)
+	if !pending.hasHigherPriority(10) {
+		t.Errorf("expected a pending work with a higher priority than 10")
+	}
+
+	// requeue work2 with a lower priority
+	pending.add("work2", 0)
+	if pending.hasHigherPriority(10) {
+		t.Errorf("expected no pending work with a higher priority than 10, but got %v", pending.counts)
+	}
+
+	pending.done("work1")
+	pending.done("work1")
+	if !pending.hasHigherPriority(-1) || len(pending.counts) != 1 {
+		t.Errorf("expected only work2 is pending, but got %v", pending.counts)
+	}
+	pending.done("work2")
+	if len(pending.priorities) != 0 || len(pending.counts) != 0 {
+		t.Errorf("expected no pending work, but got %v", pending.counts)
+	}
+}
+
+func TestSyncWithPriority(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0, testingcommon.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Annotations = map[string]string{ManifestWorkPriorityAnnotationKey: "10"}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	controller.controller.pendingWorks = newPendingWorks()
+	controller.controller.pendingWorks.queueKeysFunc(work)
+	controller.controller.pendingWorks.add("work-critical", 100)
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertNoActions(t, controller.workClient.Actions())
+	testingcommon.AssertNoActions(t, controller.kubeClient.Actions())
+
+	// the work is synced once the work with a higher priority is done
+	controller.controller.pendingWorks.done("work-critical")
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertActions(t, controller.kubeClient.Actions(), "get", "create")
+}
+
+func TestSyncPausedWork(t *testing.T) {
+	work, workKey := spoketesting.NewManifestWork(0, testingcommon.NewUnstructured("v1", "Secret", "ns1", "test"))
+	work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+	work.Annotations = map[string]string{ManifestWorkPausedAnnotationKey: "true"}
+	controller := newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+
+	syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertActions(t, controller.workClient.Actions(), "patch")
+	testingcommon.AssertNoActions(t, controller.kubeClient.Actions())
+	pausedWork := &workapiv1.ManifestWork{}
+	if err := json.Unmarshal(controller.workClient.Actions()[0].(clienttesting.PatchActionImpl).Patch, pausedWork); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, pausedWork.Status.Conditions, WorkPaused, metav1.ConditionTrue)
+
+	// the manifests are applied and the paused condition is removed once the work is resumed
+	work.Annotations = map[string]string{}
+	work.Status.Conditions = pausedWork.Status.Conditions
+	controller = newController(t, work, nil, spoketesting.NewFakeRestMapper()).
+		withKubeObject().
+		withUnstructuredObject()
+	if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+		t.Fatal(err)
+	}
+	testingcommon.AssertActions(t, controller.kubeClient.Actions(), "get", "create")
+	var resumedWork *workapiv1.ManifestWork
+	for _, action := range controller.workClient.Actions() {
+		if action.GetResource().Resource != "manifestworks" {
+			continue
+		}
+		resumedWork = &workapiv1.ManifestWork{}
+		if err := json.Unmarshal(action.(clienttesting.PatchActionImpl).Patch, resumedWork); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if resumedWork == nil {
+		t.Fatal("expected the status of the work is patched")
+	}
+	if cond := meta.FindStatusCondition(resumedWork.Status.Conditions, WorkPaused); cond != nil {
+		t.Errorf("expected the paused condition is removed, but got %v", cond)
+	}
+	assertCondition(t, resumedWork.Status.Conditions, workapiv1.WorkApplied, metav1.ConditionTrue)
+}
+
+func TestSyncRemoteTargetWork(t *testing.T) {
+	kubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+users:
+- name: remote
+  user:
+    token: token
+`)
+	secretInformer := kubeinformers.NewSharedInformerFactory(fakekube.NewSimpleClientset(), 0).Core().V1().Secrets()
+	for _, name := range []string{"remote", "denied"} {
+		if err := secretInformer.Informer().GetStore().Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "targets", ResourceVersion: "1"},
+			Data:       map[string][]byte{target.KubeconfigSecretKey: kubeconfig},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolver, err := target.NewResolver(secretInformer, "targets", []string{"remote", "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name           string
+		targets        *target.Resolver
+		secretName     string
+		deleteOption   *workapiv1.DeleteOption
+		appliedWork    func(work *workapiv1.ManifestWork) *workapiv1.AppliedManifestWork
+		expectedReason string
+	}{
+		{
+			name:           "remote targets are disabled",
+			secretName:     "remote",
+			expectedReason: "TargetClusterUnavailable",
+		},
+		{
+			name:           "secret is not found",
+			targets:        resolver,
+			secretName:     "unknown",
+			expectedReason: "TargetClusterUnavailable",
+		},
+		{
+			name:           "secret is not allowed",
+			targets:        resolver,
+			secretName:     "denied",
+			expectedReason: "TargetClusterUnavailable",
+		},
+		{
+			name:           "orphan is not supported",
+			targets:        resolver,
+			secretName:     "remote",
+			deleteOption:   &workapiv1.DeleteOption{PropagationPolicy: workapiv1.DeletePropagationPolicyTypeOrphan},
+			expectedReason: "TargetClusterUnavailable",
+		},
+		{
+			name:       "target cluster is changed",
+			targets:    resolver,
+			secretName: "remote",
+			appliedWork: func(work *workapiv1.ManifestWork) *workapiv1.AppliedManifestWork {
+				return &workapiv1.AppliedManifestWork{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", "testhub", work.Name)},
+					Spec:       workapiv1.AppliedManifestWorkSpec{HubHash: "testhub", ManifestWorkName: work.Name},
+				}
+			},
+			expectedReason: "TargetClusterChanged",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work, workKey := spoketesting.NewManifestWork(0, testingcommon.NewUnstructured("v1", "Secret", "ns1", "test"))
+			work.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			work.Annotations = map[string]string{target.TargetKubeconfigSecretAnnotationKey: c.secretName}
+			work.Spec.DeleteOption = c.deleteOption
+			var appliedWork *workapiv1.AppliedManifestWork
+			if c.appliedWork != nil {
+				appliedWork = c.appliedWork(work)
+			}
+			controller := newController(t, work, appliedWork, spoketesting.NewFakeRestMapper()).
+				withKubeObject().
+				withUnstructuredObject()
+			controller.controller.hubHash = "testhub"
+			controller.controller.targets = c.targets
+
+			syncContext := testingcommon.NewFakeSyncContext(t, workKey)
+			if err := controller.toController().sync(context.TODO(), syncContext); err != nil {
+				t.Fatal(err)
+			}
+			testingcommon.AssertNoActions(t, controller.kubeClient.Actions())
+			testingcommon.AssertNoActions(t, controller.dynamicClient.Actions())
+
+			var patchedWork *workapiv1.ManifestWork
+			for _, action := range controller.workClient.Actions() {
+				if action.GetResource().Resource != "manifestworks" {
+					continue
+				}
+				patchedWork = &workapiv1.ManifestWork{}
+				if err := json.Unmarshal(action.(clienttesting.PatchActionImpl).Patch, patchedWork); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if patchedWork == nil {
+				t.Fatal("expected the status of the work is patched")
+			}
+			cond := meta.FindStatusCondition(patchedWork.Status.Conditions, workapiv1.WorkApplied)
+			if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != c.expectedReason {
+				t.Errorf("expected the applied condition with reason %s, but got %v", c.expectedReason, cond)
+			}
+		})
+	}
+}
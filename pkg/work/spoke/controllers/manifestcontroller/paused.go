@@ -0,0 +1,21 @@
+package manifestcontroller
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManifestWorkPausedAnnotationKey is the annotation of a manifestwork pausing its reconciliation on the managed
+// cluster when it is set to "true". The agent stops applying the manifests of a paused manifestwork, so the resources
+// on the managed cluster can be changed, e.g. to debug them, without being reverted by the agent. The resources are
+// applied again once the annotation is removed.
+const ManifestWorkPausedAnnotationKey = "work.open-cluster-management.io/paused"
+
+// WorkPaused is the condition type of a manifestwork indicating its reconciliation is paused by the annotation.
+const WorkPaused = "Paused"
+
+// isWorkPaused returns true if the manifestwork is paused by the annotation.
+func isWorkPaused(obj metav1.Object) bool {
+	return strings.EqualFold(obj.GetAnnotations()[ManifestWorkPausedAnnotationKey], "true")
+}
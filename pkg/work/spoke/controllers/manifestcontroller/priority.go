@@ -0,0 +1,117 @@
+package manifestcontroller
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+)
+
+// ManifestWorkPriorityAnnotationKey is the annotation of a manifestwork setting its priority in the range of [0,100],
+// the manifestworks with a higher priority are applied first when the agent catches up with a large number of
+// manifestworks, e.g. after the agent restarts or reconnects to the hub. The priority is 0 if it is not set or
+// invalid.
+const ManifestWorkPriorityAnnotationKey = "work.open-cluster-management.io/priority"
+
+const (
+	minWorkPriority = 0
+	maxWorkPriority = 100
+)
+
+// PriorityRequeueDelay is how long a manifestwork waits before it is synced again if there are manifestworks with
+// a higher priority waiting to be synced.
+var PriorityRequeueDelay = 1 * time.Second
+
+// workPriority returns the priority of the manifestwork from the annotation.
+func workPriority(obj metav1.Object) int {
+	value, ok := obj.GetAnnotations()[ManifestWorkPriorityAnnotationKey]
+	if !ok {
+		return minWorkPriority
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil || priority < minWorkPriority || priority > maxWorkPriority {
+		klog.Warningf("the manifestwork %s has invalid priority value %s.", obj.GetName(), value)
+		return minWorkPriority
+	}
+	return priority
+}
+
+// pendingWorks tracks the priorities of the manifestworks queued but not synced yet.
+type pendingWorks struct {
+	lock       sync.Mutex
+	priorities map[string]int
+	// counts is the number of the pending manifestworks by priority
+	counts map[int]int
+}
+
+func newPendingWorks() *pendingWorks {
+	return &pendingWorks{
+		priorities: map[string]int{},
+		counts:     map[int]int{},
+	}
+}
+
+// queueKeysFunc records the manifestwork as pending before it is queued by name.
+func (p *pendingWorks) queueKeysFunc(obj runtime.Object) []string {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		p.add(accessor.GetName(), workPriority(accessor))
+	}
+	return queue.QueueKeyByMetaName(obj)
+}
+
+func (p *pendingWorks) add(name string, priority int) {
+	if p == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if last, ok := p.priorities[name]; ok {
+		p.decrease(last)
+	}
+	p.priorities[name] = priority
+	p.counts[priority]++
+}
+
+// done removes the manifestwork from the pending ones once it is synced.
+func (p *pendingWorks) done(name string) {
+	if p == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if priority, ok := p.priorities[name]; ok {
+		p.decrease(priority)
+		delete(p.priorities, name)
+	}
+}
+
+func (p *pendingWorks) decrease(priority int) {
+	p.counts[priority]--
+	if p.counts[priority] == 0 {
+		delete(p.counts, priority)
+	}
+}
+
+// hasHigherPriority returns true if any pending manifestwork has a higher priority than the given one.
+func (p *pendingWorks) hasHigherPriority(priority int) bool {
+	if p == nil {
+		return false
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for pending := range p.counts {
+		if pending > priority {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package resourcereadcontroller
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ReadPolicy decides which resources on the managed cluster the ResourceReads are allowed to read. A resource is
+// allowed if it matches one of the allowed resources and none of the denied resources.
+//
+// A resource is in the format of resource.group, e.g. deployments.apps, and the resources of the core group have no
+// group suffix, e.g. configmaps. The * matches all the resources, and *.group matches all the resources of the group.
+type ReadPolicy struct {
+	Allowed []string
+	Denied  []string
+}
+
+// IsAllowed returns true if the resource is allowed to read.
+func (p *ReadPolicy) IsAllowed(gvr schema.GroupVersionResource) bool {
+	return matchResources(p.Allowed, gvr) && !matchResources(p.Denied, gvr)
+}
+
+func matchResources(patterns []string, gvr schema.GroupVersionResource) bool {
+	for _, pattern := range patterns {
+		resource, group, _ := strings.Cut(pattern, ".")
+		switch {
+		case pattern == "*":
+			return true
+		case group != gvr.Group:
+			continue
+		case resource == "*" || resource == gvr.Resource:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package resourcereadcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
+)
+
+const (
+	// maxReadItems is the max number of the objects listed by a ResourceRead.
+	maxReadItems = 100
+	// maxStatusSize is the max size in bytes of the objects returned in the status of a ResourceRead, to keep the
+	// ResourceRead well within the size limit of an object in etcd.
+	maxStatusSize = 512 * 1024
+	// maxReadAttempts is the max number of the attempts to read a resource with the transient failures, the
+	// ResourceRead is completed as failed afterwards.
+	maxReadAttempts = 5
+)
+
+// readValidator checks if the executor of a ResourceRead has permission to read the resource.
+type readValidator interface {
+	CheckReadAccess(ctx context.Context, executor *workapiv1.ManifestWorkExecutor,
+		gvr schema.GroupVersionResource, namespace, name string) error
+}
+
+// ResourceReadController handles the ResourceReads in the cluster namespace on the hub. It reads the requested
+// resource on the managed cluster once if the read is allowed by the policy and the executor, and returns the
+// sanitized objects in the status of the ResourceRead.
+type ResourceReadController struct {
+	resourceReadClient dynamic.ResourceInterface
+	resourceReadLister cache.GenericNamespaceLister
+	spokeDynamicClient dynamic.Interface
+	restMapper         meta.RESTMapper
+	validator          readValidator
+	policy             *ReadPolicy
+	rateLimiter        workqueue.RateLimiter
+}
+
+// NewResourceReadController returns a ResourceReadController
+func NewResourceReadController(
+	recorder events.Recorder,
+	resourceReadClient dynamic.ResourceInterface,
+	resourceReadInformer informers.GenericInformer,
+	clusterName string,
+	spokeDynamicClient dynamic.Interface,
+	restMapper meta.RESTMapper,
+	validator readValidator,
+	policy *ReadPolicy,
+) factory.Controller {
+	controller := &ResourceReadController{
+		resourceReadClient: resourceReadClient,
+		resourceReadLister: resourceReadInformer.Lister().ByNamespace(clusterName),
+		spokeDynamicClient: spokeDynamicClient,
+		restMapper:         restMapper,
+		validator:          validator,
+		policy:             policy,
+		rateLimiter:        workqueue.NewItemExponentialFailureRateLimiter(time.Second, 5*time.Minute),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, resourceReadInformer.Informer()).
+		WithSync(controller.sync).ToController("ResourceReadController", recorder)
+}
+
+func (c *ResourceReadController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
+	resourceReadName := controllerContext.QueueKey()
+	klog.V(4).Infof("Reconciling ResourceRead %q", resourceReadName)
+
+	obj, err := c.resourceReadLister.Get(resourceReadName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	resourceRead := &ResourceRead{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, resourceRead); err != nil {
+		return err
+	}
+
+	// the resource is read once
+	if meta.FindStatusCondition(resourceRead.Status.Conditions, ResourceReadConditionCompleted) != nil {
+		c.rateLimiter.Forget(resourceReadName)
+		return nil
+	}
+
+	status, err := c.read(ctx, resourceRead.Spec)
+	if err != nil {
+		// back off the transient failures, and give up once the attempts are exhausted, so a ResourceRead which
+		// cannot be read does not load the managed cluster forever.
+		if c.rateLimiter.NumRequeues(resourceReadName) < maxReadAttempts-1 {
+			klog.V(4).Infof("Failed to read the resource of ResourceRead %q, will retry: %v", resourceReadName, err)
+			controllerContext.Queue().AddAfter(resourceReadName, c.rateLimiter.When(resourceReadName))
+			return nil
+		}
+		status = failedStatus(fmt.Sprintf("failed to read the resource after %d attempts: %v", maxReadAttempts, err))
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+	_, err = c.resourceReadClient.Patch(ctx, resourceRead.Name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		return err
+	}
+	c.rateLimiter.Forget(resourceReadName)
+	return nil
+}
+
+func failedStatus(message string) *ResourceReadStatus {
+	status := &ResourceReadStatus{}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    ResourceReadConditionCompleted,
+		Status:  metav1.ConditionTrue,
+		Reason:  ResourceReadReasonFailed,
+		Message: message,
+	})
+	return status
+}
+
+// read reads the resource and returns the status of the ResourceRead. An error is returned only if the read is
+// supposed to be retried.
+func (c *ResourceReadController) read(ctx context.Context, spec ResourceReadSpec) (*ResourceReadStatus, error) {
+	status := &ResourceReadStatus{}
+	complete := func(reason, message string) (*ResourceReadStatus, error) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    ResourceReadConditionCompleted,
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		})
+		return status, nil
+	}
+
+	gvr, err := c.restMapper.ResourceFor(schema.GroupVersionResource{
+		Group: spec.Group, Version: spec.Version, Resource: spec.Resource})
+	if err != nil {
+		return complete(ResourceReadReasonFailed, err.Error())
+	}
+
+	if !c.policy.IsAllowed(gvr) {
+		return complete(ResourceReadReasonDenied,
+			fmt.Sprintf("the resource %s is not allowed to read by the work agent", gvr.GroupResource()))
+	}
+	err = c.validator.CheckReadAccess(ctx, spec.Executor, gvr, spec.Namespace, spec.Name)
+	var notAllowedErr *basic.NotAllowedError
+	switch {
+	case errors.As(err, &notAllowedErr):
+		return complete(ResourceReadReasonDenied, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	resourceClient := c.spokeDynamicClient.Resource(gvr).Namespace(spec.Namespace)
+	if len(spec.Name) > 0 {
+		obj, err := resourceClient.Get(ctx, spec.Name, metav1.GetOptions{})
+		if isReadFailure(err) {
+			return complete(ResourceReadReasonFailed, err.Error())
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw, err := sanitize(gvr, obj)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) > maxStatusSize {
+			return complete(ResourceReadReasonFailed, fmt.Sprintf("%s %s is larger than %d bytes",
+				gvr.GroupResource(), spec.Name, maxStatusSize))
+		}
+		status.Object = &runtime.RawExtension{Raw: raw}
+		return complete(ResourceReadReasonSucceeded, fmt.Sprintf("%s %s is read", gvr.GroupResource(), spec.Name))
+	}
+
+	list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: spec.LabelSelector, Limit: maxReadItems})
+	if isReadFailure(err) {
+		return complete(ResourceReadReasonFailed, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	size := 0
+	status.Truncated = len(list.GetContinue()) > 0
+	for i := range list.Items {
+		raw, err := sanitize(gvr, &list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		if size += len(raw); size > maxStatusSize {
+			status.Truncated = true
+			break
+		}
+		status.Items = append(status.Items, runtime.RawExtension{Raw: raw})
+	}
+	return complete(ResourceReadReasonSucceeded, fmt.Sprintf("%d %s are listed", len(status.Items), gvr.GroupResource()))
+}
+
+// isReadFailure returns true if the error is not going to be resolved by retrying the read.
+func isReadFailure(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsForbidden(err) || apierrors.IsBadRequest(err) ||
+		apierrors.IsInvalid(err) || apierrors.IsMethodNotSupported(err)
+}
+
+// sanitize removes the managed fields and the last applied configuration from the object, and the data of the
+// secrets, and returns the raw json of the object.
+func sanitize(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) ([]byte, error) {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+	if gvr.Group == "" && gvr.Resource == "secrets" {
+		unstructured.RemoveNestedField(obj.Object, "data")
+		unstructured.RemoveNestedField(obj.Object, "stringData")
+	}
+	return obj.MarshalJSON()
+}
@@ -0,0 +1,305 @@
+package resourcereadcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/workqueue"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+const clusterName = "cluster1"
+
+type fakeReadValidator struct {
+	allowed bool
+}
+
+func (v *fakeReadValidator) CheckReadAccess(_ context.Context, executor *workapiv1.ManifestWorkExecutor,
+	gvr schema.GroupVersionResource, namespace, name string) error {
+	if executor == nil || v.allowed {
+		return nil
+	}
+	return &basic.NotAllowedError{Err: fmt.Errorf("not allowed to get the resource %s, %s %s", gvr.Resource, namespace, name)}
+}
+
+func newResourceRead(name string, spec ResourceReadSpec, conditions ...metav1.Condition) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&ResourceRead{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ResourceReadResource.GroupVersion().String(),
+			Kind:       "ResourceRead",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: clusterName},
+		Spec:       spec,
+		Status:     ResourceReadStatus{Conditions: conditions},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestSync(t *testing.T) {
+	deployment := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy1")
+	deployment.SetAnnotations(map[string]string{corev1.LastAppliedConfigAnnotation: "{}", "app": "test"})
+	deployment.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "test"}})
+	largeDeployment := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", "deploy1")
+	largeDeployment.SetAnnotations(map[string]string{"large": strings.Repeat("a", maxStatusSize)})
+	largeDeployments := []runtime.Object{}
+	for i := 0; i < 3; i++ {
+		obj := testingcommon.NewUnstructured("apps/v1", "Deployment", "ns1", fmt.Sprintf("deploy%d", i))
+		obj.SetAnnotations(map[string]string{"large": strings.Repeat("a", maxStatusSize/2)})
+		largeDeployments = append(largeDeployments, obj)
+	}
+	secret := testingcommon.NewUnstructuredWithContent("v1", "Secret", "ns1", "secret1",
+		map[string]interface{}{"data": map[string]interface{}{"token": "dG9rZW4="}})
+	executor := &workapiv1.ManifestWorkExecutor{
+		Subject: workapiv1.ManifestWorkExecutorSubject{
+			Type: workapiv1.ExecutorSubjectTypeServiceAccount,
+			ServiceAccount: &workapiv1.ManifestWorkSubjectServiceAccount{
+				Namespace: "ns1",
+				Name:      "sa1",
+			},
+		},
+	}
+
+	cases := []struct {
+		name             string
+		resourceRead     *unstructured.Unstructured
+		spokeObjects     []runtime.Object
+		spokeErr         error
+		requeues         int
+		executorAllowed  bool
+		expectedReason   string
+		validateStatus   func(t *testing.T, status ResourceReadStatus)
+		expectedNoAction bool
+	}{
+		{
+			name: "get the object",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1"}),
+			expectedReason: ResourceReadReasonSucceeded,
+			validateStatus: func(t *testing.T, status ResourceReadStatus) {
+				obj := &unstructured.Unstructured{}
+				if err := obj.UnmarshalJSON(status.Object.Raw); err != nil {
+					t.Fatal(err)
+				}
+				if obj.GetName() != "deploy1" || len(obj.GetManagedFields()) != 0 {
+					t.Errorf("expected the sanitized deployment, but got %v", obj)
+				}
+				if _, ok := obj.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; ok {
+					t.Errorf("expected the last applied configuration is removed, but got %v", obj.GetAnnotations())
+				}
+			},
+		},
+		{
+			name:           "list the objects",
+			resourceRead:   newResourceRead("read1", ResourceReadSpec{Group: "apps", Resource: "deployments"}),
+			expectedReason: ResourceReadReasonSucceeded,
+			validateStatus: func(t *testing.T, status ResourceReadStatus) {
+				if len(status.Items) != 1 || status.Truncated {
+					t.Errorf("expected 1 deployment listed, but got %v", status)
+				}
+			},
+		},
+		{
+			name: "secret data is removed",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Resource: "secrets", Namespace: "ns1", Name: "secret1"}),
+			expectedReason: ResourceReadReasonSucceeded,
+			validateStatus: func(t *testing.T, status ResourceReadStatus) {
+				obj := &unstructured.Unstructured{}
+				if err := obj.UnmarshalJSON(status.Object.Raw); err != nil {
+					t.Fatal(err)
+				}
+				if _, found, _ := unstructured.NestedMap(obj.Object, "data"); found {
+					t.Errorf("expected the secret data is removed, but got %v", obj)
+				}
+			},
+		},
+		{
+			name: "denied by the policy",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Resource: "pods", Namespace: "ns1", Name: "pod1"}),
+			expectedReason: ResourceReadReasonDenied,
+		},
+		{
+			name: "denied by the executor",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1", Executor: executor}),
+			expectedReason: ResourceReadReasonDenied,
+		},
+		{
+			name: "allowed by the executor",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1", Executor: executor}),
+			executorAllowed: true,
+			expectedReason:  ResourceReadReasonSucceeded,
+		},
+		{
+			name: "object not found",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy2"}),
+			expectedReason: ResourceReadReasonFailed,
+		},
+		{
+			name: "unknown resource",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "statefulsets", Namespace: "ns1", Name: "sts1"}),
+			expectedReason: ResourceReadReasonFailed,
+		},
+		{
+			name: "object is too large",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1"}),
+			spokeObjects:   []runtime.Object{largeDeployment},
+			expectedReason: ResourceReadReasonFailed,
+		},
+		{
+			name:           "list is truncated by the size",
+			resourceRead:   newResourceRead("read1", ResourceReadSpec{Group: "apps", Resource: "deployments"}),
+			spokeObjects:   largeDeployments,
+			expectedReason: ResourceReadReasonSucceeded,
+			validateStatus: func(t *testing.T, status ResourceReadStatus) {
+				if len(status.Items) != 1 || !status.Truncated {
+					t.Errorf("expected 1 deployment listed and truncated, but got %d items, truncated %v",
+						len(status.Items), status.Truncated)
+				}
+			},
+		},
+		{
+			name: "read is retried",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1"}),
+			spokeErr:         apierrors.NewInternalError(fmt.Errorf("internal error")),
+			expectedNoAction: true,
+		},
+		{
+			name: "read fails after the attempts",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1"}),
+			spokeErr:       apierrors.NewInternalError(fmt.Errorf("internal error")),
+			requeues:       maxReadAttempts - 1,
+			expectedReason: ResourceReadReasonFailed,
+		},
+		{
+			name: "completed read",
+			resourceRead: newResourceRead("read1", ResourceReadSpec{
+				Group: "apps", Resource: "deployments", Namespace: "ns1", Name: "deploy1"},
+				metav1.Condition{Type: ResourceReadConditionCompleted, Status: metav1.ConditionTrue,
+					Reason: ResourceReadReasonSucceeded}),
+			expectedNoAction: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubDynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{ResourceReadResource: "ResourceReadList"}, c.resourceRead)
+			spokeObjects := c.spokeObjects
+			if spokeObjects == nil {
+				spokeObjects = []runtime.Object{deployment, secret}
+			}
+			spokeDynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+					{Version: "v1", Resource: "secrets"}:                    "SecretList",
+				}, spokeObjects...)
+			if c.spokeErr != nil {
+				spokeDynamicClient.PrependReactor("get", "deployments",
+					func(action clienttesting.Action) (bool, runtime.Object, error) {
+						return true, nil, c.spokeErr
+					})
+			}
+
+			informer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+				hubDynamicClient, 10*time.Minute, clusterName, nil).ForResource(ResourceReadResource)
+			if err := informer.Informer().GetStore().Add(c.resourceRead); err != nil {
+				t.Fatal(err)
+			}
+
+			controller := &ResourceReadController{
+				resourceReadClient: hubDynamicClient.Resource(ResourceReadResource).Namespace(clusterName),
+				resourceReadLister: informer.Lister().ByNamespace(clusterName),
+				spokeDynamicClient: spokeDynamicClient,
+				restMapper:         spoketesting.NewFakeRestMapper(),
+				validator:          &fakeReadValidator{allowed: c.executorAllowed},
+				policy:             &ReadPolicy{Allowed: []string{"*.apps", "secrets"}},
+				rateLimiter:        workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Second),
+			}
+			for i := 0; i < c.requeues; i++ {
+				controller.rateLimiter.When("read1")
+			}
+			if err := controller.sync(context.TODO(), testingcommon.NewFakeSyncContext(t, "read1")); err != nil {
+				t.Fatal(err)
+			}
+
+			actions := hubDynamicClient.Actions()
+			if c.expectedNoAction {
+				testingcommon.AssertNoActions(t, actions)
+				return
+			}
+			testingcommon.AssertActions(t, actions, "patch")
+			patchAction := actions[0].(clienttesting.PatchActionImpl)
+			if patchAction.Subresource != "status" {
+				t.Errorf("expected the status is patched, but got subresource %q", patchAction.Subresource)
+			}
+			resourceRead := &ResourceRead{}
+			if err := json.Unmarshal(patchAction.Patch, resourceRead); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(resourceRead.Status.Conditions, ResourceReadConditionCompleted)
+			if condition == nil || condition.Reason != c.expectedReason {
+				t.Errorf("expected the completed condition with reason %s, but got %v", c.expectedReason, condition)
+			}
+			if c.validateStatus != nil {
+				c.validateStatus(t, resourceRead.Status)
+			}
+		})
+	}
+}
+
+func TestReadPolicy(t *testing.T) {
+	policy := &ReadPolicy{
+		Allowed: []string{"*.apps", "configmaps", "roles.rbac.authorization.k8s.io"},
+		Denied:  []string{"replicasets.apps"},
+	}
+	cases := []struct {
+		gvr      schema.GroupVersionResource
+		expected bool
+	}{
+		{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, expected: true},
+		{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, expected: false},
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, expected: true},
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, expected: false},
+		{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, expected: true},
+		{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, expected: false},
+	}
+	for _, c := range cases {
+		if actual := policy.IsAllowed(c.gvr); actual != c.expected {
+			t.Errorf("expected %v for %s, but got %v", c.expected, c.gvr, actual)
+		}
+	}
+
+	if !(&ReadPolicy{Allowed: []string{"*"}}).IsAllowed(schema.GroupVersionResource{Version: "v1", Resource: "pods"}) {
+		t.Errorf("expected all the resources are allowed by *")
+	}
+}
@@ -0,0 +1,87 @@
+package resourcereadcontroller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// ResourceReadResource is the resource of the ResourceRead. A ResourceRead is created in the cluster namespace on the
+// hub to read a resource on the managed cluster once through the work agent, for debugging without direct access to
+// the managed cluster.
+var ResourceReadResource = schema.GroupVersionResource{
+	Group:    "debug.work.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "resourcereads",
+}
+
+const (
+	// ResourceReadConditionCompleted is true once the work agent handles the ResourceRead, the ResourceRead is not
+	// handled again after it is completed.
+	ResourceReadConditionCompleted = "Completed"
+
+	// The reasons of the completed condition.
+	ResourceReadReasonSucceeded = "Succeeded"
+	ResourceReadReasonDenied    = "Denied"
+	ResourceReadReasonFailed    = "Failed"
+)
+
+// ResourceRead requests the work agent to read a resource on the managed cluster, the sanitized objects are returned
+// in the status.
+type ResourceRead struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ResourceReadSpec `json:"spec"`
+	// +optional
+	Status ResourceReadStatus `json:"status,omitempty"`
+}
+
+type ResourceReadSpec struct {
+	// Group and Resource are the resource to read on the managed cluster.
+	// +optional
+	Group    string `json:"group,omitempty"`
+	Resource string `json:"resource"`
+
+	// Version is the version of the resource, the preferred version on the managed cluster is read if it is not set.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Namespace is the namespace of the objects, it is empty for the cluster scoped resources or to list the objects
+	// in all the namespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object to get, the objects are listed if it is empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// LabelSelector selects the listed objects, it is ignored if the name is set.
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// Executor is the subject the read is authorized against on the managed cluster in the same way as the
+	// executor of a ManifestWork. The read is only restricted by the policy of the work agent if it is not set.
+	// +optional
+	Executor *workv1.ManifestWorkExecutor `json:"executor,omitempty"`
+}
+
+type ResourceReadStatus struct {
+	// Conditions contains the completed condition of the read.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Object is the object read by name.
+	// +optional
+	Object *runtime.RawExtension `json:"object,omitempty"`
+
+	// Items are the objects listed.
+	// +optional
+	Items []runtime.RawExtension `json:"items,omitempty"`
+
+	// Truncated is true if there are more objects listed than returned in the items.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
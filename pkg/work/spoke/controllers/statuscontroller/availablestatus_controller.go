@@ -2,11 +2,13 @@ package statuscontroller
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -24,9 +26,13 @@ import (
 	workapiv1 "open-cluster-management.io/api/work/v1"
 	"open-cluster-management.io/sdk-go/pkg/patcher"
 
+	"open-cluster-management.io/ocm/pkg/common/health"
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/queue"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback"
+	"open-cluster-management.io/ocm/pkg/work/spoke/target"
 )
 
 const statusFeedbackConditionType = "StatusFeedbackSynced"
@@ -39,18 +45,24 @@ type AvailableStatusController struct {
 	patcher            patcher.Patcher[*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus]
 	manifestWorkLister worklister.ManifestWorkNamespaceLister
 	spokeDynamicClient dynamic.Interface
+	targets            *target.Resolver
 	statusReader       *statusfeedback.StatusReader
+	debouncer          *statusDebouncer
 }
 
 // NewAvailableStatusController returns a AvailableStatusController
 func NewAvailableStatusController(
 	recorder events.Recorder,
 	spokeDynamicClient dynamic.Interface,
+	targets *target.Resolver,
 	manifestWorkClient workv1client.ManifestWorkInterface,
 	manifestWorkInformer workinformer.ManifestWorkInformer,
 	manifestWorkLister worklister.ManifestWorkNamespaceLister,
 	maxJSONRawLength int32,
 	syncInterval time.Duration,
+	statusUpdateDebounceInterval time.Duration,
+	statusFeedbackMinChange int64,
+	controllerHealth *health.ControllerHealth,
 ) factory.Controller {
 	controller := &AvailableStatusController{
 		patcher: patcher.NewPatcher[
@@ -58,12 +70,14 @@ func NewAvailableStatusController(
 			manifestWorkClient),
 		manifestWorkLister: manifestWorkLister,
 		spokeDynamicClient: spokeDynamicClient,
+		targets:            targets,
 		statusReader:       statusfeedback.NewStatusReader().WithMaxJsonRawLength(maxJSONRawLength),
+		debouncer:          newStatusDebouncer(statusUpdateDebounceInterval, statusFeedbackMinChange),
 	}
 
 	return factory.New().
 		WithInformersQueueKeysFunc(queue.QueueKeyByMetaName, manifestWorkInformer.Informer()).
-		WithSync(controller.sync).ResyncEvery(syncInterval).ToController("AvailableStatusController", recorder)
+		WithSync(controllerHealth.ObserveSync(controller.sync)).ResyncEvery(syncInterval).ToController("AvailableStatusController", recorder)
 }
 
 func (c *AvailableStatusController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
@@ -73,6 +87,7 @@ func (c *AvailableStatusController) sync(ctx context.Context, controllerContext
 		manifestWork, err := c.manifestWorkLister.Get(manifestWorkName)
 		if errors.IsNotFound(err) {
 			// work not found, could have been deleted, do nothing.
+			c.debouncer.forget(manifestWorkName)
 			return nil
 		}
 		if err != nil {
@@ -80,6 +95,11 @@ func (c *AvailableStatusController) sync(ctx context.Context, controllerContext
 		}
 
 		err = c.syncManifestWork(ctx, manifestWork)
+		var rqe helpers.RequeueError
+		if stderrors.As(err, &rqe) {
+			controllerContext.Queue().AddAfter(manifestWorkName, rqe.RequeueTime)
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("unable to sync manifestwork %q: %w", manifestWork.Name, err)
 		}
@@ -114,10 +134,21 @@ func (c *AvailableStatusController) syncManifestWork(ctx context.Context, origin
 		return nil
 	}
 
+	// the status of the resources applied to a remote target cluster is read from the target cluster, the failure
+	// to reach the target cluster is reported by the manifestwork controller.
+	dynamicClient, err := c.targets.DynamicClient(manifestWork, c.spokeDynamicClient)
+	if err != nil {
+		klog.V(4).Infof("Skip the status of ManifestWork %q: %v", manifestWork.Name, err)
+		return nil
+	}
+
+	completionRules, completionRulesErr := getCompletionRules(manifestWork)
+	feedbackSchema, feedbackSchemaErr := helper.GetFeedbackSchema(manifestWork)
+
 	// handle status condition of manifests
 	// TODO revist this controller since this might bring races when user change the manifests in spec.
 	for index, manifest := range manifestWork.Status.ResourceStatus.Manifests {
-		obj, availableStatusCondition, err := buildAvailableStatusCondition(manifest.ResourceMeta, c.spokeDynamicClient)
+		obj, availableStatusCondition, err := buildAvailableStatusCondition(manifest.ResourceMeta, dynamicClient)
 		meta.SetStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions, availableStatusCondition)
 		if err != nil {
 			// skip getting status values if resource is not available.
@@ -125,23 +156,79 @@ func (c *AvailableStatusController) syncManifestWork(ctx context.Context, origin
 		}
 
 		// Read status of the resource according to feedback rules.
-		values, statusFeedbackCondition := c.getFeedbackValues(manifest.ResourceMeta, obj, manifestWork.Spec.ManifestConfigs)
+		values, statusFeedbackCondition, schemaVersion := c.getFeedbackValues(
+			manifest.ResourceMeta, obj, manifestWork.Spec.ManifestConfigs, feedbackSchema, feedbackSchemaErr)
 		meta.SetStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions, statusFeedbackCondition)
 		manifestWork.Status.ResourceStatus.Manifests[index].StatusFeedbacks.Values = values
+
+		// the consumers on the hub tell the values converted by the feedback schema from the ones as read by the
+		// version of the schema.
+		if len(schemaVersion) > 0 {
+			meta.SetStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions,
+				helper.NewFeedbackSchemaAppliedCondition(schemaVersion))
+		} else {
+			meta.RemoveStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions, helper.ManifestFeedbackSchemaApplied)
+		}
+
+		// a complete manifest is not evaluated again for the same generation, since the finished resource may be
+		// cleaned up afterwards.
+		rule := findCompletionRule(manifest.ResourceMeta, completionRules)
+		switch {
+		case rule == nil:
+			meta.RemoveStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions, ManifestComplete)
+		case !isManifestComplete(manifest, manifestWork.Generation):
+			meta.SetStatusCondition(&manifestWork.Status.ResourceStatus.Manifests[index].Conditions,
+				buildCompleteCondition(manifestWork.Generation, rule, obj, manifestWork.Status.ResourceStatus.Manifests[index]))
+		}
 	}
 
 	// aggregate ManifestConditions and update work status condition
 	workAvailableStatusCondition := aggregateManifestConditions(manifestWork.Generation, manifestWork.Status.ResourceStatus.Manifests)
 	meta.SetStatusCondition(&manifestWork.Status.Conditions, workAvailableStatusCondition)
 
+	// the manifestwork stays complete for the same generation
+	completeCondition := meta.FindStatusCondition(manifestWork.Status.Conditions, WorkComplete)
+	switch {
+	case completionRulesErr != nil:
+		meta.SetStatusCondition(&manifestWork.Status.Conditions, metav1.Condition{
+			Type:               WorkComplete,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidCompletionRules",
+			ObservedGeneration: manifestWork.Generation,
+			Message:            completionRulesErr.Error(),
+		})
+	case completionRules == nil:
+		meta.RemoveStatusCondition(&manifestWork.Status.Conditions, WorkComplete)
+	case completeCondition == nil || completeCondition.Status != metav1.ConditionTrue ||
+		completeCondition.ObservedGeneration != manifestWork.Generation:
+		meta.SetStatusCondition(&manifestWork.Status.Conditions, aggregateCompleteConditions(
+			manifestWork.Generation, manifestWork.Status.ResourceStatus.Manifests, completionRules))
+	}
+
 	// no work if the status of manifestwork does not change
 	if equality.Semantic.DeepEqual(originalManifestWork.Status.ResourceStatus, manifestWork.Status.ResourceStatus) &&
 		equality.Semantic.DeepEqual(originalManifestWork.Status.Conditions, manifestWork.Status.Conditions) {
 		return nil
 	}
 
-	// update status of manifestwork. if this conflicts, try again later
-	_, err := c.patcher.PatchStatus(ctx, manifestWork, manifestWork.Status, originalManifestWork.Status)
+	// merge the minor changes into one status update per debounce interval
+	if wait := c.debouncer.deferUpdate(originalManifestWork, manifestWork); wait > 0 {
+		return helpers.NewRequeueError("Status update is debounced", wait)
+	}
+
+	// update status of manifestwork. if this conflicts, try again later. Only the changes of the conditions are
+	// traced, the updates of the status feedback values are too frequent to be traced.
+	var span trace.Span
+	if !equality.Semantic.DeepEqual(originalManifestWork.Status.Conditions, manifestWork.Status.Conditions) {
+		ctx, span = tracing.StartWorkSpan(ctx, "ReturnManifestWorkStatus", manifestWork)
+	}
+	_, err = c.patcher.PatchStatus(ctx, manifestWork, manifestWork.Status, originalManifestWork.Status)
+	if span != nil {
+		tracing.EndWithError(span, err)
+	}
+	if err == nil {
+		c.debouncer.updated(manifestWork.Name)
+	}
 	return err
 }
 
@@ -204,7 +291,8 @@ func aggregateManifestConditions(generation int64, manifests []workapiv1.Manifes
 
 func (c *AvailableStatusController) getFeedbackValues(
 	resourceMeta workapiv1.ManifestResourceMeta, obj *unstructured.Unstructured,
-	manifestOptions []workapiv1.ManifestConfigOption) ([]workapiv1.FeedbackValue, metav1.Condition) {
+	manifestOptions []workapiv1.ManifestConfigOption,
+	feedbackSchema *helper.FeedbackSchema, feedbackSchemaErr error) ([]workapiv1.FeedbackValue, metav1.Condition, string) {
 	var errs []error
 	var values []workapiv1.FeedbackValue
 
@@ -215,7 +303,7 @@ func (c *AvailableStatusController) getFeedbackValues(
 			Type:   statusFeedbackConditionType,
 			Reason: "NoStatusFeedbackSynced",
 			Status: metav1.ConditionTrue,
-		}
+		}, ""
 	}
 
 	for _, rule := range option.FeedbackRules {
@@ -228,6 +316,19 @@ func (c *AvailableStatusController) getFeedbackValues(
 		}
 	}
 
+	// the values are reported as read if the feedback schema is invalid
+	var schemaVersion string
+	if feedbackSchemaErr != nil {
+		errs = append(errs, feedbackSchemaErr)
+	} else if valueSchemas := feedbackSchema.FindValueSchemas(resourceMeta); len(valueSchemas) > 0 {
+		converted, err := helper.ConvertFeedbackValues(values, valueSchemas)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		values = converted
+		schemaVersion = feedbackSchema.Version
+	}
+
 	err := utilerrors.NewAggregate(errs)
 
 	if err != nil {
@@ -236,14 +337,14 @@ func (c *AvailableStatusController) getFeedbackValues(
 			Reason:  "StatusFeedbackSyncFailed",
 			Status:  metav1.ConditionFalse,
 			Message: fmt.Sprintf("Sync status feedback failed with error %v", err),
-		}
+		}, schemaVersion
 	}
 
 	return values, metav1.Condition{
 		Type:   statusFeedbackConditionType,
 		Reason: "StatusFeedbackSynced",
 		Status: metav1.ConditionTrue,
-	}
+	}, schemaVersion
 }
 
 // buildAvailableStatusCondition returns a StatusCondition with type Available for a given manifest resource
@@ -21,6 +21,7 @@ import (
 
 	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
 	"open-cluster-management.io/ocm/pkg/work/spoke/statusfeedback"
 )
@@ -225,6 +226,7 @@ func TestStatusFeedback(t *testing.T) {
 		name              string
 		existingResources []runtime.Object
 		configOption      []workapiv1.ManifestConfigOption
+		annotations       map[string]string
 		manifests         []workapiv1.ManifestCondition
 		validateActions   func(t *testing.T, actions []clienttesting.Action)
 	}{
@@ -381,12 +383,134 @@ func TestStatusFeedback(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "convert the values with units",
+			existingResources: []runtime.Object{
+				testingcommon.NewUnstructuredWithContent("apps/v1", "Deployment", "ns1", "deploy1",
+					map[string]interface{}{
+						"spec": map[string]interface{}{"memory": "1Gi"},
+						"status": map[string]interface{}{
+							"replicas":       int64(3),
+							"lastUpdateTime": "2024-01-01T00:00:00Z",
+						},
+					}),
+			},
+			configOption: []workapiv1.ManifestConfigOption{
+				{
+					ResourceIdentifier: workapiv1.ResourceIdentifier{Group: "apps", Resource: "deployments", Name: "deploy1", Namespace: "ns1"},
+					FeedbackRules: []workapiv1.FeedbackRule{
+						{
+							Type: workapiv1.JSONPathsType,
+							JsonPaths: []workapiv1.JsonPath{
+								{Name: "replicas", Path: ".status.replicas"},
+								{Name: "memory", Path: ".spec.memory"},
+								{Name: "lastUpdateTime", Path: ".status.lastUpdateTime"},
+							},
+						},
+					},
+				},
+			},
+			annotations: map[string]string{
+				helper.ManifestWorkFeedbackSchemaAnnotationKey: `{"version":"v1","rules":[` +
+					`{"resourceIdentifier":{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},` +
+					`"values":[{"name":"memory","unit":"bytes"},{"name":"lastUpdateTime","unit":"timestamp"}]}]}`,
+			},
+			manifests: []workapiv1.ManifestCondition{
+				newManifest("apps", "v1", "deployments", "ns1", "deploy1"),
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				p := actions[0].(clienttesting.PatchActionImpl).Patch
+				work := &workapiv1.ManifestWork{}
+				if err := json.Unmarshal(p, work); err != nil {
+					t.Fatal(err)
+				}
+
+				expectedValues := []workapiv1.FeedbackValue{
+					{
+						Name:  "replicas",
+						Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(3)},
+					},
+					{
+						Name:  "memory",
+						Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(1073741824)},
+					},
+					{
+						Name:  "lastUpdateTime",
+						Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(1704067200)},
+					},
+				}
+				if !equality.Semantic.DeepEqual(work.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values, expectedValues) {
+					t.Fatal(spew.Sdump(work.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values))
+				}
+				if !hasStatusCondition(work.Status.ResourceStatus.Manifests[0].Conditions, statusFeedbackConditionType, metav1.ConditionTrue) {
+					t.Fatal(spew.Sdump(work.Status.ResourceStatus.Manifests[0].Conditions))
+				}
+				if version := helper.GetFeedbackSchemaVersion(work.Status.ResourceStatus.Manifests[0].Conditions); version != helper.FeedbackSchemaV1 {
+					t.Errorf("expected the feedback schema version %s, but got %q", helper.FeedbackSchemaV1, version)
+				}
+			},
+		},
+		{
+			name: "read the ignored fields",
+			existingResources: []runtime.Object{
+				testingcommon.NewUnstructuredWithContent("apps/v1", "Deployment", "ns1", "deploy1",
+					map[string]interface{}{
+						"spec":   map[string]interface{}{"replicas": int64(5)},
+						"status": map[string]interface{}{"replicas": int64(5)},
+					}),
+			},
+			configOption: []workapiv1.ManifestConfigOption{
+				{
+					ResourceIdentifier: workapiv1.ResourceIdentifier{Group: "apps", Resource: "deployments", Name: "deploy1", Namespace: "ns1"},
+					FeedbackRules: []workapiv1.FeedbackRule{
+						{
+							Type:      workapiv1.JSONPathsType,
+							JsonPaths: []workapiv1.JsonPath{{Name: "desiredReplicas", Path: ".spec.replicas"}},
+						},
+					},
+				},
+			},
+			annotations: map[string]string{
+				helper.ManifestWorkIgnoreDifferencesAnnotationKey: `[{"resourceIdentifier":` +
+					`{"group":"apps","resource":"deployments","namespace":"ns1","name":"deploy1"},"jsonPaths":[".spec.replicas"]}]`,
+			},
+			manifests: []workapiv1.ManifestCondition{
+				newManifest("apps", "v1", "deployments", "ns1", "deploy1"),
+			},
+			validateActions: func(t *testing.T, actions []clienttesting.Action) {
+				testingcommon.AssertActions(t, actions, "patch")
+				p := actions[0].(clienttesting.PatchActionImpl).Patch
+				work := &workapiv1.ManifestWork{}
+				if err := json.Unmarshal(p, work); err != nil {
+					t.Fatal(err)
+				}
+
+				// the replicas scaled by others on the managed cluster are still reported
+				expectedValues := []workapiv1.FeedbackValue{
+					{
+						Name:  "desiredReplicas",
+						Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: pointer.Int64(5)},
+					},
+				}
+				if !equality.Semantic.DeepEqual(work.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values, expectedValues) {
+					t.Fatal(spew.Sdump(work.Status.ResourceStatus.Manifests[0].StatusFeedbacks.Values))
+				}
+				if !hasStatusCondition(work.Status.ResourceStatus.Manifests[0].Conditions, statusFeedbackConditionType, metav1.ConditionTrue) {
+					t.Fatal(spew.Sdump(work.Status.ResourceStatus.Manifests[0].Conditions))
+				}
+				if !hasStatusCondition(work.Status.ResourceStatus.Manifests[0].Conditions, workapiv1.ManifestAvailable, metav1.ConditionTrue) {
+					t.Fatal(spew.Sdump(work.Status.ResourceStatus.Manifests[0].Conditions))
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			testingWork, _ := spoketesting.NewManifestWork(0)
 			testingWork.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			testingWork.Annotations = c.annotations
 			testingWork.Spec.ManifestConfigs = c.configOption
 			testingWork.Status = workapiv1.ManifestWorkStatus{
 				ResourceStatus: workapiv1.ManifestResourceStatus{
@@ -0,0 +1,302 @@
+package statuscontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/utils/lru"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// ManifestWorkCompletionRulesAnnotationKey is the annotation of a manifestwork defining the completion rules of its
+// manifests as a json list of CompletionRule. A manifestwork wrapping run-to-completion workloads, e.g. Jobs, is
+// complete once all the manifests with the completion rules are complete.
+// TODO move this to the api repo.
+const ManifestWorkCompletionRulesAnnotationKey = "work.open-cluster-management.io/completion-rules"
+
+const (
+	// WorkComplete is the condition type of a manifestwork, it is true once all the manifests with the completion
+	// rules are complete. It is never changed back to false for the same generation, so the manifestwork is still
+	// complete after the finished resources are cleaned up on the managed cluster.
+	WorkComplete = "Complete"
+	// ManifestComplete is the condition type of a manifest, it is true once the manifest satisfies its completion
+	// rule.
+	ManifestComplete = "Complete"
+)
+
+// CompletionRuleType is the type of a completion rule
+type CompletionRuleType string
+
+const (
+	// WellKnownCompletionsType completes a Job once it is complete or failed, and a Pod once it is succeeded or
+	// failed.
+	WellKnownCompletionsType CompletionRuleType = "WellKnownCompletions"
+	// CELCompletionType completes a manifest once the CEL expression over its status feedback values is true.
+	CELCompletionType CompletionRuleType = "CEL"
+)
+
+// CompletionRule defines when a manifest of a manifestwork is complete.
+type CompletionRule struct {
+	// ResourceIdentifier identifies the manifest in the manifestwork.
+	ResourceIdentifier workapiv1.ResourceIdentifier `json:"resourceIdentifier"`
+
+	// Type is the type of the rule, one of WellKnownCompletions and CEL.
+	Type CompletionRuleType `json:"type"`
+
+	// Expression is the CEL expression of a rule with the CEL type, evaluating to a bool. The status feedback
+	// values of the manifest are accessible with the variable "values" by name, e.g. values.succeeded > 0.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+}
+
+// getCompletionRules returns the completion rules of the manifestwork, nil is returned if there are none.
+func getCompletionRules(manifestWork *workapiv1.ManifestWork) ([]CompletionRule, error) {
+	value, ok := manifestWork.Annotations[ManifestWorkCompletionRulesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var rules []CompletionRule
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse the completion rules: %w", err)
+	}
+	for _, rule := range rules {
+		switch rule.Type {
+		case WellKnownCompletionsType:
+		case CELCompletionType:
+			if _, err := compileCompletionExpression(rule.Expression); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported completion rule type %q of %v", rule.Type, rule.ResourceIdentifier)
+		}
+	}
+	return rules, nil
+}
+
+// findCompletionRule returns the completion rule of the manifest, nil is returned if there is none.
+func findCompletionRule(resourceMeta workapiv1.ManifestResourceMeta, rules []CompletionRule) *CompletionRule {
+	identifier := workapiv1.ResourceIdentifier{
+		Group:     resourceMeta.Group,
+		Resource:  resourceMeta.Resource,
+		Namespace: resourceMeta.Namespace,
+		Name:      resourceMeta.Name,
+	}
+	for i := range rules {
+		if rules[i].ResourceIdentifier == identifier {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// isManifestComplete returns whether the manifest is complete for the generation of the manifestwork. The Complete
+// condition of a former generation is kept by the merge of the manifest conditions when the spec changes, so it is
+// evaluated again.
+func isManifestComplete(manifest workapiv1.ManifestCondition, generation int64) bool {
+	cond := meta.FindStatusCondition(manifest.Conditions, ManifestComplete)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == generation
+}
+
+// buildCompleteCondition returns the Complete condition of the manifest by its completion rule.
+func buildCompleteCondition(generation int64, rule *CompletionRule, obj *unstructured.Unstructured,
+	manifest workapiv1.ManifestCondition) metav1.Condition {
+	var complete bool
+	var reason string
+	var err error
+	switch rule.Type {
+	case WellKnownCompletionsType:
+		complete, reason, err = wellKnownCompletion(manifest.ResourceMeta, obj)
+	case CELCompletionType:
+		complete, err = celCompletion(rule.Expression, manifest.StatusFeedbacks)
+		reason = "CompletionExpressionTrue"
+	}
+	if err != nil {
+		return metav1.Condition{
+			Type:               ManifestComplete,
+			Status:             metav1.ConditionFalse,
+			Reason:             "CompletionRuleFailed",
+			ObservedGeneration: generation,
+			Message:            err.Error(),
+		}
+	}
+	if !complete {
+		return metav1.Condition{
+			Type:               ManifestComplete,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ResourceNotComplete",
+			ObservedGeneration: generation,
+			Message:            "Resource is not complete",
+		}
+	}
+	return metav1.Condition{
+		Type:               ManifestComplete,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		ObservedGeneration: generation,
+		Message:            "Resource is complete",
+	}
+}
+
+// wellKnownCompletion returns whether a Job or a Pod runs to completion, and the reason.
+func wellKnownCompletion(resourceMeta workapiv1.ManifestResourceMeta, obj *unstructured.Unstructured) (bool, string, error) {
+	switch {
+	case resourceMeta.Group == "batch" && resourceMeta.Resource == "jobs":
+		conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return false, "", err
+		}
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["status"] != string(metav1.ConditionTrue) {
+				continue
+			}
+			switch condition["type"] {
+			case "Complete":
+				return true, "JobComplete", nil
+			case "Failed":
+				return true, "JobFailed", nil
+			}
+		}
+		return false, "", nil
+	case resourceMeta.Group == "" && resourceMeta.Resource == "pods":
+		phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+		if err != nil {
+			return false, "", err
+		}
+		switch phase {
+		case "Succeeded":
+			return true, "PodSucceeded", nil
+		case "Failed":
+			return true, "PodFailed", nil
+		}
+		return false, "", nil
+	default:
+		return false, "", fmt.Errorf("no well-known completion for resource %s", resourceMeta.Resource)
+	}
+}
+
+func celCompletion(expression string, feedbacks workapiv1.StatusFeedbackResult) (bool, error) {
+	program, err := compileCompletionExpression(expression)
+	if err != nil {
+		return false, err
+	}
+	values := map[string]interface{}{}
+	for _, value := range feedbacks.Values {
+		switch {
+		case value.Value.Integer != nil:
+			values[value.Name] = *value.Value.Integer
+		case value.Value.String != nil:
+			values[value.Name] = *value.Value.String
+		case value.Value.Boolean != nil:
+			values[value.Name] = *value.Value.Boolean
+		case value.Value.JsonRaw != nil:
+			var raw interface{}
+			if err := json.Unmarshal([]byte(*value.Value.JsonRaw), &raw); err != nil {
+				return false, fmt.Errorf("failed to decode the feedback value %s: %w", value.Name, err)
+			}
+			values[value.Name] = raw
+		}
+	}
+	out, _, err := program.Eval(map[string]interface{}{"values": values})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate the completion expression %q: %w", expression, err)
+	}
+	complete, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("the completion expression %q does not evaluate to a bool", expression)
+	}
+	return complete, nil
+}
+
+// aggregateCompleteConditions returns the Complete condition of the manifestwork, the manifestwork is complete once
+// all the manifests with the completion rules are complete.
+func aggregateCompleteConditions(generation int64, manifests []workapiv1.ManifestCondition, rules []CompletionRule) metav1.Condition {
+	total, complete := 0, 0
+	for _, manifest := range manifests {
+		if findCompletionRule(manifest.ResourceMeta, rules) == nil {
+			continue
+		}
+		total++
+		if isManifestComplete(manifest, generation) {
+			complete++
+		}
+	}
+
+	if total > 0 && complete == total {
+		return metav1.Condition{
+			Type:               WorkComplete,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ResourcesComplete",
+			ObservedGeneration: generation,
+			Message:            "All resources with completion rules are complete",
+		}
+	}
+	return metav1.Condition{
+		Type:               WorkComplete,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ResourcesNotComplete",
+		ObservedGeneration: generation,
+		Message:            fmt.Sprintf("%d of %d resources with completion rules are complete", complete, total),
+	}
+}
+
+// completionProgramCacheSize is the max number of the compiled completion expressions cached.
+const completionProgramCacheSize = 1024
+
+var (
+	completionEnvOnce sync.Once
+	completionEnv     *cel.Env
+	completionEnvErr  error
+
+	completionPrograms = lru.New(completionProgramCacheSize)
+)
+
+// compileCompletionExpression compiles the expression, the programs are cached by the expressions since they are
+// evaluated on every sync of the manifestworks. The cost of an evaluation is limited as the expressions are provided
+// by the users.
+func compileCompletionExpression(expression string) (cel.Program, error) {
+	completionEnvOnce.Do(func() {
+		completionEnv, completionEnvErr = newCompletionEnv()
+	})
+	if completionEnvErr != nil {
+		return nil, completionEnvErr
+	}
+
+	if program, ok := completionPrograms.Get(expression); ok {
+		return program.(cel.Program), nil
+	}
+
+	ast, issues := completionEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile the completion expression %q: %w", expression, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("the completion expression %q must evaluate to a bool, but got %v", expression, ast.OutputType())
+	}
+	program, err := completionEnv.Program(ast, cel.CostLimit(celconfig.PerCallLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile the completion expression %q: %w", expression, err)
+	}
+	completionPrograms.Add(expression, program)
+	return program, nil
+}
+
+func newCompletionEnv() (*cel.Env, error) {
+	env, err := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion(), true).
+		Env(environment.StoredExpressions)
+	if err != nil {
+		return nil, err
+	}
+	return env.Extend(
+		cel.Variable("values", cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
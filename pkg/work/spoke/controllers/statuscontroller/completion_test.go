@@ -0,0 +1,188 @@
+package statuscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+)
+
+const jobCompletionRules = `[{"resourceIdentifier":{"group":"batch","resource":"jobs","namespace":"ns1","name":"job1"},` +
+	`"type":"WellKnownCompletions"}]`
+
+func newJob(conditionType string) *unstructured.Unstructured {
+	job := testingcommon.NewUnstructured("batch/v1", "Job", "ns1", "job1")
+	if len(conditionType) > 0 {
+		_ = unstructured.SetNestedSlice(job.Object, []interface{}{
+			map[string]interface{}{"type": conditionType, "status": "True"},
+		}, "status", "conditions")
+	}
+	return job
+}
+
+func TestSyncManifestWorkCompletion(t *testing.T) {
+	completeManifest := newManifest("batch", "v1", "jobs", "ns1", "job1")
+	completeManifest.Conditions = []metav1.Condition{{Type: ManifestComplete, Status: metav1.ConditionTrue, Reason: "JobComplete"}}
+
+	cases := []struct {
+		name              string
+		generation        int64
+		rules             string
+		existingResources []runtime.Object
+		manifests         []workapiv1.ManifestCondition
+		workConditions    []metav1.Condition
+		expectedManifest  metav1.ConditionStatus
+		expectedWork      metav1.ConditionStatus
+		expectedReason    string
+	}{
+		{
+			name:              "job is complete",
+			rules:             jobCompletionRules,
+			existingResources: []runtime.Object{newJob("Complete")},
+			manifests:         []workapiv1.ManifestCondition{newManifest("batch", "v1", "jobs", "ns1", "job1")},
+			expectedManifest:  metav1.ConditionTrue,
+			expectedWork:      metav1.ConditionTrue,
+			expectedReason:    "ResourcesComplete",
+		},
+		{
+			name:              "job is failed",
+			rules:             jobCompletionRules,
+			existingResources: []runtime.Object{newJob("Failed")},
+			manifests:         []workapiv1.ManifestCondition{newManifest("batch", "v1", "jobs", "ns1", "job1")},
+			expectedManifest:  metav1.ConditionTrue,
+			expectedWork:      metav1.ConditionTrue,
+			expectedReason:    "ResourcesComplete",
+		},
+		{
+			name:              "job is running",
+			rules:             jobCompletionRules,
+			existingResources: []runtime.Object{newJob("")},
+			manifests:         []workapiv1.ManifestCondition{newManifest("batch", "v1", "jobs", "ns1", "job1")},
+			expectedManifest:  metav1.ConditionFalse,
+			expectedWork:      metav1.ConditionFalse,
+			expectedReason:    "ResourcesNotComplete",
+		},
+		{
+			name:             "complete job is cleaned up",
+			rules:            jobCompletionRules,
+			manifests:        []workapiv1.ManifestCondition{completeManifest},
+			expectedManifest: metav1.ConditionTrue,
+			expectedWork:     metav1.ConditionTrue,
+			expectedReason:   "ResourcesComplete",
+		},
+		{
+			name:              "job of the former generation is complete",
+			generation:        1,
+			rules:             jobCompletionRules,
+			existingResources: []runtime.Object{newJob("")},
+			manifests:         []workapiv1.ManifestCondition{completeManifest},
+			workConditions: []metav1.Condition{
+				{Type: WorkComplete, Status: metav1.ConditionTrue, Reason: "ResourcesComplete"},
+			},
+			expectedManifest: metav1.ConditionFalse,
+			expectedWork:     metav1.ConditionFalse,
+			expectedReason:   "ResourcesNotComplete",
+		},
+		{
+			name:              "invalid completion rules",
+			rules:             `[{"type":"Unknown"}]`,
+			existingResources: []runtime.Object{newJob("Complete")},
+			manifests:         []workapiv1.ManifestCondition{newManifest("batch", "v1", "jobs", "ns1", "job1")},
+			expectedWork:      metav1.ConditionFalse,
+			expectedReason:    "InvalidCompletionRules",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testingWork, _ := spoketesting.NewManifestWork(0)
+			testingWork.Generation = c.generation
+			testingWork.Finalizers = []string{workapiv1.ManifestWorkFinalizer}
+			testingWork.Annotations = map[string]string{ManifestWorkCompletionRulesAnnotationKey: c.rules}
+			testingWork.Status = workapiv1.ManifestWorkStatus{
+				Conditions: append([]metav1.Condition{{Type: workapiv1.WorkApplied}}, c.workConditions...),
+				ResourceStatus: workapiv1.ManifestResourceStatus{
+					Manifests: c.manifests,
+				},
+			}
+
+			fakeClient := fakeworkclient.NewSimpleClientset(testingWork)
+			fakeDynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), c.existingResources...)
+			controller := AvailableStatusController{
+				spokeDynamicClient: fakeDynamicClient,
+				patcher: patcher.NewPatcher[
+					*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](
+					fakeClient.WorkV1().ManifestWorks(testingWork.Namespace)),
+			}
+
+			if err := controller.syncManifestWork(context.TODO(), testingWork); err != nil {
+				t.Fatal(err)
+			}
+			actions := fakeClient.Actions()
+			testingcommon.AssertActions(t, actions, "patch")
+			work := &workapiv1.ManifestWork{}
+			if err := json.Unmarshal(actions[0].(clienttesting.PatchActionImpl).Patch, work); err != nil {
+				t.Fatal(err)
+			}
+			if len(c.expectedManifest) > 0 &&
+				!hasStatusCondition(work.Status.ResourceStatus.Manifests[0].Conditions, ManifestComplete, c.expectedManifest) {
+				t.Errorf("expected manifest complete %s, but got %s", c.expectedManifest,
+					spew.Sdump(work.Status.ResourceStatus.Manifests[0].Conditions))
+			}
+			if manifestCond := meta.FindStatusCondition(
+				work.Status.ResourceStatus.Manifests[0].Conditions, ManifestComplete); manifestCond != nil &&
+				manifestCond.ObservedGeneration != c.generation {
+				t.Errorf("expected manifest complete of generation %d, but got %d", c.generation, manifestCond.ObservedGeneration)
+			}
+			cond := meta.FindStatusCondition(work.Status.Conditions, WorkComplete)
+			if cond == nil || cond.Status != c.expectedWork || cond.Reason != c.expectedReason {
+				t.Errorf("expected work complete %s with reason %s, but got %v", c.expectedWork, c.expectedReason, cond)
+			}
+		})
+	}
+}
+
+func TestCELCompletion(t *testing.T) {
+	feedbacks := workapiv1.StatusFeedbackResult{
+		Values: []workapiv1.FeedbackValue{
+			{Name: "succeeded", Value: workapiv1.FieldValue{Type: workapiv1.Integer, Integer: ptr.To[int64](1)}},
+		},
+	}
+	cases := []struct {
+		name             string
+		expression       string
+		expectedComplete bool
+		expectedErr      bool
+	}{
+		{name: "complete", expression: "values.succeeded > 0", expectedComplete: true},
+		{name: "not complete", expression: "values.succeeded > 1"},
+		{name: "missing value", expression: "values.failed > 0", expectedErr: true},
+		{name: "not a bool", expression: "values.succeeded", expectedErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			complete, err := celCompletion(c.expression, feedbacks)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if complete != c.expectedComplete {
+				t.Errorf("expected complete %v, but got %v", c.expectedComplete, complete)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package statuscontroller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/clock"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+// statusDebouncer defers the minor status changes of the manifestworks, so the frequent changes of the status
+// feedback values, e.g. the replicas of a deployment flapping, are merged into one status update per interval.
+// A change is minor if only the status feedback values change and every changed integer value changes less than
+// the minimum change, all the status feedback changes are minor if the minimum change is 0.
+type statusDebouncer struct {
+	interval  time.Duration
+	minChange int64
+	clock     clock.Clock
+
+	lock        sync.Mutex
+	lastUpdates map[string]time.Time
+}
+
+func newStatusDebouncer(interval time.Duration, minChange int64) *statusDebouncer {
+	return &statusDebouncer{
+		interval:    interval,
+		minChange:   minChange,
+		clock:       clock.RealClock{},
+		lastUpdates: map[string]time.Time{},
+	}
+}
+
+// deferUpdate returns how long to wait before updating the status of the manifestwork, it is 0 if the status
+// should be updated now.
+func (d *statusDebouncer) deferUpdate(original, updated *workapiv1.ManifestWork) time.Duration {
+	if d == nil || d.interval <= 0 || !d.isMinorChange(original, updated) {
+		return 0
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	lastUpdate, ok := d.lastUpdates[updated.Name]
+	if !ok {
+		return 0
+	}
+	if wait := d.interval - d.clock.Since(lastUpdate); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// updated records the time the status of the manifestwork is updated.
+func (d *statusDebouncer) updated(name string) {
+	if d == nil || d.interval <= 0 {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.lastUpdates[name] = d.clock.Now()
+}
+
+// forget drops the last update time of the manifestwork after it is deleted.
+func (d *statusDebouncer) forget(name string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.lastUpdates, name)
+}
+
+func (d *statusDebouncer) isMinorChange(original, updated *workapiv1.ManifestWork) bool {
+	if !equality.Semantic.DeepEqual(original.Status.Conditions, updated.Status.Conditions) {
+		return false
+	}
+
+	originalManifests := original.Status.ResourceStatus.Manifests
+	updatedManifests := updated.Status.ResourceStatus.Manifests
+	if len(originalManifests) != len(updatedManifests) {
+		return false
+	}
+	for i := range updatedManifests {
+		if !equality.Semantic.DeepEqual(originalManifests[i].ResourceMeta, updatedManifests[i].ResourceMeta) ||
+			!equality.Semantic.DeepEqual(originalManifests[i].Conditions, updatedManifests[i].Conditions) {
+			return false
+		}
+		if !d.isMinorFeedbackChange(originalManifests[i].StatusFeedbacks.Values, updatedManifests[i].StatusFeedbacks.Values) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *statusDebouncer) isMinorFeedbackChange(original, updated []workapiv1.FeedbackValue) bool {
+	if len(original) != len(updated) {
+		return false
+	}
+
+	originalValues := map[string]workapiv1.FieldValue{}
+	for _, value := range original {
+		originalValues[value.Name] = value.Value
+	}
+	for _, value := range updated {
+		originalValue, ok := originalValues[value.Name]
+		if !ok {
+			return false
+		}
+		if d.minChange <= 0 || equality.Semantic.DeepEqual(originalValue, value.Value) {
+			continue
+		}
+		if originalValue.Integer == nil || value.Value.Integer == nil {
+			return false
+		}
+		delta := *value.Value.Integer - *originalValue.Integer
+		if delta >= d.minChange || -delta >= d.minChange {
+			return false
+		}
+	}
+	return true
+}
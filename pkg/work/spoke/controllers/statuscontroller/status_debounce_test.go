@@ -0,0 +1,104 @@
+package statuscontroller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newFeedbackWork(replicas int64, available metav1.ConditionStatus) *workapiv1.ManifestWork {
+	return &workapiv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "work1", Namespace: "cluster1"},
+		Status: workapiv1.ManifestWorkStatus{
+			Conditions: []metav1.Condition{{Type: workapiv1.WorkAvailable, Status: available}},
+			ResourceStatus: workapiv1.ManifestResourceStatus{
+				Manifests: []workapiv1.ManifestCondition{
+					{
+						ResourceMeta: workapiv1.ManifestResourceMeta{Ordinal: 0, Kind: "Deployment", Name: "test"},
+						StatusFeedbacks: workapiv1.StatusFeedbackResult{
+							Values: []workapiv1.FeedbackValue{
+								{
+									Name: "ReadyReplicas",
+									Value: workapiv1.FieldValue{
+										Type:    workapiv1.Integer,
+										Integer: ptr.To(replicas),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestStatusDebouncer(t *testing.T) {
+	cases := []struct {
+		name         string
+		minChange    int64
+		updated      *workapiv1.ManifestWork
+		lastUpdate   time.Duration
+		expectedWait time.Duration
+	}{
+		{
+			name:         "never updated",
+			updated:      newFeedbackWork(2, metav1.ConditionTrue),
+			expectedWait: 0,
+		},
+		{
+			name:         "feedback change is debounced",
+			updated:      newFeedbackWork(2, metav1.ConditionTrue),
+			lastUpdate:   10 * time.Second,
+			expectedWait: 20 * time.Second,
+		},
+		{
+			name:         "feedback change after the interval",
+			updated:      newFeedbackWork(2, metav1.ConditionTrue),
+			lastUpdate:   40 * time.Second,
+			expectedWait: 0,
+		},
+		{
+			name:         "condition change is not debounced",
+			updated:      newFeedbackWork(2, metav1.ConditionFalse),
+			lastUpdate:   10 * time.Second,
+			expectedWait: 0,
+		},
+		{
+			name:         "feedback change under the min change is debounced",
+			minChange:    3,
+			updated:      newFeedbackWork(3, metav1.ConditionTrue),
+			lastUpdate:   10 * time.Second,
+			expectedWait: 20 * time.Second,
+		},
+		{
+			name:         "feedback change over the min change is not debounced",
+			minChange:    3,
+			updated:      newFeedbackWork(4, metav1.ConditionTrue),
+			lastUpdate:   10 * time.Second,
+			expectedWait: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := testingclock.NewFakeClock(time.Now())
+			debouncer := newStatusDebouncer(30*time.Second, c.minChange)
+			debouncer.clock = clock
+			if c.lastUpdate > 0 {
+				debouncer.updated("work1")
+				clock.Step(c.lastUpdate)
+			}
+
+			wait := debouncer.deferUpdate(newFeedbackWork(1, metav1.ConditionTrue), c.updated)
+			if wait != c.expectedWait {
+				t.Errorf("expected wait %v, but got %v", c.expectedWait, wait)
+			}
+		})
+	}
+}
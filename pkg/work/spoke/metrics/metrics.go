@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// Constants for metric names.
+	WorkAgentSubsystem           = "work_agent"
+	SpecToAppliedDurationKey     = "spec_to_applied_duration_seconds"
+	ManifestApplyDurationKey     = "manifest_apply_duration_seconds"
+	ManifestApplyErrorsTotalKey  = "manifest_apply_errors_total"
+	ManifestApplySkippedTotalKey = "manifest_apply_skipped_total"
+)
+
+var (
+	specToAppliedDuration = k8smetrics.NewHistogram(&k8smetrics.HistogramOpts{
+		Subsystem:      WorkAgentSubsystem,
+		Name:           SpecToAppliedDurationKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help: "How long in seconds it takes from the agent observing a new spec of a manifestwork until all its " +
+			"manifests are applied.",
+		Buckets: k8smetrics.ExponentialBuckets(0.01, 2, 16),
+	})
+
+	manifestApplyDuration = k8smetrics.NewHistogramVec(&k8smetrics.HistogramOpts{
+		Subsystem:      WorkAgentSubsystem,
+		Name:           ManifestApplyDurationKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "How long in seconds it takes to apply a manifest of a manifestwork.",
+		Buckets:        k8smetrics.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"group", "version", "kind"})
+
+	manifestApplyErrors = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      WorkAgentSubsystem,
+		Name:           ManifestApplyErrorsTotalKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of errors applying the manifests of the manifestworks.",
+	}, []string{"reason", "group", "version", "kind"})
+
+	manifestApplySkipped = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      WorkAgentSubsystem,
+		Name:           ManifestApplySkippedTotalKey,
+		StabilityLevel: k8smetrics.ALPHA,
+		Help:           "Number of manifests not applied again since they are not changed from the last apply.",
+	}, []string{"group", "version", "kind"})
+
+	metrics = []k8smetrics.Registerable{
+		specToAppliedDuration, manifestApplyDuration, manifestApplyErrors, manifestApplySkipped,
+	}
+)
+
+func init() {
+	// Register metrics on initialization.
+	for _, m := range metrics {
+		legacyregistry.MustRegister(m)
+	}
+}
+
+type specObservation struct {
+	generation int64
+	observed   time.Time
+}
+
+// WorkMetrics records the apply metrics of the manifestworks.
+type WorkMetrics struct {
+	clock clock.Clock
+
+	lock         sync.Mutex
+	specObserved map[string]specObservation
+}
+
+// NewWorkMetrics creates a new WorkMetrics instance.
+func NewWorkMetrics(clock clock.Clock) *WorkMetrics {
+	return &WorkMetrics{
+		clock:        clock,
+		specObserved: map[string]specObservation{},
+	}
+}
+
+// ObserveSpec marks the time a generation of the manifestwork is observed, it is kept if the generation was
+// already observed. It returns true if the generation is observed the first time.
+func (m *WorkMetrics) ObserveSpec(name string, generation int64) bool {
+	if m == nil {
+		return false
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if observation, exists := m.specObserved[name]; exists && observation.generation == generation {
+		return false
+	}
+	m.specObserved[name] = specObservation{generation: generation, observed: m.clock.Now()}
+	return true
+}
+
+// Applied records the spec to applied duration once the observed generation of the manifestwork is applied.
+func (m *WorkMetrics) Applied(name string, generation int64) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	observation, exists := m.specObserved[name]
+	if !exists || observation.generation != generation {
+		return
+	}
+	specToAppliedDuration.Observe(m.clock.Since(observation.observed).Seconds())
+	delete(m.specObserved, name)
+}
+
+// Forget drops the observation of the manifestwork, it is called after the manifestwork is deleted.
+func (m *WorkMetrics) Forget(name string) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.specObserved, name)
+}
+
+// ManifestApplied records the duration applying a manifest from the start time, and counts the error by the reason
+// if it fails.
+func (m *WorkMetrics) ManifestApplied(gvk schema.GroupVersionKind, start time.Time, reason string, err error) {
+	if m == nil {
+		return
+	}
+
+	manifestApplyDuration.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Observe(m.clock.Since(start).Seconds())
+	if err != nil {
+		manifestApplyErrors.WithLabelValues(reason, gvk.Group, gvk.Version, gvk.Kind).Inc()
+	}
+}
+
+// ManifestSkipped counts a manifest which is not applied since it is not changed from the last apply.
+func (m *WorkMetrics) ManifestSkipped(gvk schema.GroupVersionKind) {
+	if m == nil {
+		return
+	}
+
+	manifestApplySkipped.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Inc()
+}
+
+// Now returns the current time of the metrics clock.
+func (m *WorkMetrics) Now() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return m.clock.Now()
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/component-base/metrics/legacyregistry"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestMetrics(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Unix(0, 0))
+	metrics := NewWorkMetrics(c)
+
+	// the duration is from the first observation of the generation
+	if !metrics.ObserveSpec("work1", 1) {
+		t.Errorf("expected the generation is observed the first time")
+	}
+	c.Step(10 * time.Second)
+	if metrics.ObserveSpec("work1", 1) {
+		t.Errorf("expected the generation is observed before")
+	}
+	c.Step(10 * time.Second)
+	metrics.Applied("work1", 1)
+	// applied again is not observed
+	metrics.Applied("work1", 1)
+
+	// the old generation applied is not observed
+	metrics.ObserveSpec("work2", 2)
+	metrics.Applied("work2", 1)
+	metrics.Forget("work2")
+	metrics.Applied("work2", 2)
+
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	configMapGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	start := metrics.Now()
+	c.Step(time.Second)
+	metrics.ManifestApplied(deploymentGVK, start, "", nil)
+	metrics.ManifestApplied(deploymentGVK, start, "Forbidden", fmt.Errorf("forbidden"))
+	metrics.ManifestApplied(configMapGVK, start, "", nil)
+	metrics.ManifestSkipped(configMapGVK)
+	metrics.ManifestSkipped(configMapGVK)
+
+	mfs, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Errorf("failed to gather metrics")
+	}
+
+	for _, mf := range mfs {
+		switch *mf.Name {
+		case WorkAgentSubsystem + "_" + SpecToAppliedDurationKey:
+			for _, m := range mf.GetMetric() {
+				if m.GetHistogram().GetSampleCount() != 1 {
+					t.Errorf("spec to applied sample count is not correct")
+				}
+				if m.GetHistogram().GetSampleSum() != 20 {
+					t.Errorf("spec to applied sample sum is not correct")
+				}
+			}
+		case WorkAgentSubsystem + "_" + ManifestApplyDurationKey:
+			if len(mf.GetMetric()) != 2 {
+				t.Errorf("manifest apply duration metrics count is not correct")
+			}
+		case WorkAgentSubsystem + "_" + ManifestApplyErrorsTotalKey:
+			if len(mf.GetMetric()) != 1 || mf.GetMetric()[0].GetCounter().GetValue() != 1 {
+				t.Errorf("manifest apply errors are not correct")
+			}
+		case WorkAgentSubsystem + "_" + ManifestApplySkippedTotalKey:
+			if len(mf.GetMetric()) != 1 || mf.GetMetric()[0].GetCounter().GetValue() != 2 {
+				t.Errorf("manifest apply skipped are not correct")
+			}
+		}
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apiserver/pkg/server/healthz"
+
+	"open-cluster-management.io/ocm/pkg/common/cloudevents"
+	"open-cluster-management.io/ocm/pkg/common/health"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
+	"open-cluster-management.io/ocm/pkg/work/spoke/audit"
 )
 
 const (
@@ -11,25 +17,85 @@ const (
 	manifestCodecName       = "manifest"
 )
 
+// The names of the health contributors of the controllers and the hub connection.
+const (
+	manifestWorkHealthName                 = "manifestwork-controller"
+	addFinalizerHealthName                 = "add-finalizer-controller"
+	appliedManifestWorkFinalizeHealthName  = "appliedmanifestwork-finalize-controller"
+	manifestWorkFinalizeHealthName         = "manifestwork-finalize-controller"
+	unmanagedAppliedManifestWorkHealthName = "unmanaged-appliedmanifestwork-controller"
+	appliedManifestWorkHealthName          = "appliedmanifestwork-controller"
+	availableStatusHealthName              = "available-status-controller"
+	hubConnectivityHealthName              = "hub-connectivity"
+)
+
+var (
+	// HealthFailureThreshold is how long a controller keeps failing to sync, or the hub keeps unreachable, before
+	// the agent reports not ready.
+	HealthFailureThreshold = 5 * time.Minute
+	// HubConnectivityCheckInterval is the interval to probe the connection to the hub.
+	HubConnectivityCheckInterval = 30 * time.Second
+	// HubCABundleHealthCheckInterval is the interval to check if the CA bundles of the hub are changed.
+	HubCABundleHealthCheckInterval = 30 * time.Second
+)
+
 // WorkloadAgentOptions defines the flags for workload agent
 type WorkloadAgentOptions struct {
 	StatusSyncInterval                     time.Duration
+	StatusUpdateDebounceInterval           time.Duration
+	StatusFeedbackMinChange                int64
 	AppliedManifestWorkEvictionGracePeriod time.Duration
 	MaxJSONRawLength                       int32
 	WorkloadSourceDriver                   string
 	WorkloadSourceConfig                   string
 	CloudEventsClientID                    string
 	CloudEventsClientCodecs                []string
+	CloudEventsEncryptionKeyFile           string
+	CloudEventsDeltaSpec                   bool
+	CloudEventsResyncInterval              time.Duration
+	CloudEventsResyncWindow                time.Duration
+	TracingOptions                         *tracing.Options
+	AuditOptions                           *audit.Options
+	// GRPCTransportOptions configures the compression, the message sizes and the keepalive of the grpc connection
+	// when the workload source driver is grpc.
+	GRPCTransportOptions *cloudevents.GRPCTransportOptions
+	// ResourceReadAllowedResources and ResourceReadDeniedResources are the resources on the managed cluster the
+	// ResourceReads on the hub are allowed to read, the ResourceReads are not handled if no resource is allowed.
+	ResourceReadAllowedResources []string
+	ResourceReadDeniedResources  []string
+	// RemoteTargetSecretNamespace is the namespace of the kubeconfig secrets of the remote target clusters on the
+	// cluster the agent runs on, the manifestworks cannot be applied to the remote target clusters if it is empty.
+	RemoteTargetSecretNamespace string
+	// RemoteTargetAllowedSecrets are the names of the kubeconfig secrets in the remote target secret namespace the
+	// manifestworks are allowed to reference, no remote target cluster is allowed if it is empty.
+	RemoteTargetAllowedSecrets []string
+
+	controllerHealths     map[string]*health.ControllerHealth
+	hubConnectivityHealth *health.ConnectivityHealth
+	hubCABundleHealth     *health.HubCABundleHealth
 }
 
 // NewWorkloadAgentOptions returns the flags with default value set
 func NewWorkloadAgentOptions() *WorkloadAgentOptions {
+	controllerHealths := map[string]*health.ControllerHealth{}
+	for _, name := range controllerHealthNames() {
+		controllerHealths[name] = health.NewControllerHealth(name, HealthFailureThreshold)
+	}
+
 	return &WorkloadAgentOptions{
 		MaxJSONRawLength:                       1024,
 		StatusSyncInterval:                     10 * time.Second,
 		AppliedManifestWorkEvictionGracePeriod: 60 * time.Minute,
 		WorkloadSourceDriver:                   "kube",
 		WorkloadSourceConfig:                   "/spoke/hub-kubeconfig/kubeconfig",
+		GRPCTransportOptions:                   cloudevents.NewGRPCTransportOptions(),
+		TracingOptions:                         tracing.NewOptions(),
+		AuditOptions:                           audit.NewOptions(),
+		ResourceReadDeniedResources:            []string{"secrets"},
+		controllerHealths:                      controllerHealths,
+		hubConnectivityHealth: health.NewConnectivityHealth(
+			hubConnectivityHealthName, HubConnectivityCheckInterval, HealthFailureThreshold),
+		hubCABundleHealth: health.NewHubCABundleHealth(HubCABundleHealthCheckInterval),
 	}
 }
 
@@ -39,6 +105,13 @@ func (o *WorkloadAgentOptions) AddFlags(fs *pflag.FlagSet) {
 		o.MaxJSONRawLength, "The maximum size of the JSON raw string returned from status feedback")
 	fs.DurationVar(&o.StatusSyncInterval, "status-sync-interval",
 		o.StatusSyncInterval, "Interval to sync resource status to hub.")
+	fs.DurationVar(&o.StatusUpdateDebounceInterval, "status-update-debounce-interval", o.StatusUpdateDebounceInterval,
+		"The minimum interval between the status updates of a manifestwork when only its status feedback values "+
+			"change, the changes within the interval are merged into one update. It is disabled if it is 0.")
+	fs.Int64Var(&o.StatusFeedbackMinChange, "status-feedback-min-change", o.StatusFeedbackMinChange,
+		"The minimum change of an integer status feedback value to update the status of the manifestwork "+
+			"without waiting for the status update debounce interval. All the status feedback changes wait for "+
+			"the interval if it is 0.")
 	fs.DurationVar(&o.AppliedManifestWorkEvictionGracePeriod, "appliedmanifestwork-eviction-grace-period",
 		o.AppliedManifestWorkEvictionGracePeriod, "Grace period for appliedmanifestwork eviction")
 	fs.StringVar(&o.WorkloadSourceDriver, "workload-source-driver",
@@ -49,4 +122,63 @@ func (o *WorkloadAgentOptions) AddFlags(fs *pflag.FlagSet) {
 		o.CloudEventsClientID, "The ID of the cloudevents client when workload source source is based on cloudevents")
 	fs.StringSliceVar(&o.CloudEventsClientCodecs, "cloudevents-client-codecs", o.CloudEventsClientCodecs,
 		"The codecs for cloudevents client when workload source source is based on cloudevents, the valid codecs: manifest or manifestbundle")
+	fs.StringVar(&o.CloudEventsEncryptionKeyFile, "cloudevents-encryption-key-file", o.CloudEventsEncryptionKeyFile,
+		"The file of the base64 encoded 32 bytes key to decrypt the received works and encrypt the work status "+
+			"when workload source is based on cloudevents, the received works without encryption are rejected if it is set")
+	fs.BoolVar(&o.CloudEventsDeltaSpec, "cloudevents-delta-spec", o.CloudEventsDeltaSpec,
+		"Accept the spec of the works sent as JSON patches against the last received generation when workload source "+
+			"is based on cloudevents, it must be enabled together with the source")
+	fs.DurationVar(&o.CloudEventsResyncInterval, "cloudevents-resync-interval", o.CloudEventsResyncInterval,
+		"The interval to resync the works from the sources when workload source is based on cloudevents, "+
+			"the periodic resync is disabled if it is 0")
+	fs.DurationVar(&o.CloudEventsResyncWindow, "cloudevents-resync-window", o.CloudEventsResyncWindow,
+		"The duration to wait before resyncing the works when workload source is based on cloudevents, "+
+			"the resyncs triggered by the reconnects within the window are merged into one")
+	fs.StringSliceVar(&o.ResourceReadAllowedResources, "resource-read-allowed-resources", o.ResourceReadAllowedResources,
+		"The resources on the managed cluster the ResourceReads on the hub are allowed to read, in the format of "+
+			"resource.group, e.g. deployments.apps or configmaps, * matches all the resources and *.group matches "+
+			"the resources of the group. The ResourceReads are not handled if it is empty. It is only supported "+
+			"when the workload source driver is kube.")
+	fs.StringSliceVar(&o.ResourceReadDeniedResources, "resource-read-denied-resources", o.ResourceReadDeniedResources,
+		"The resources on the managed cluster the ResourceReads on the hub are not allowed to read even if they "+
+			"are allowed, in the same format as --resource-read-allowed-resources.")
+	fs.StringVar(&o.RemoteTargetSecretNamespace, "remote-target-secret-namespace", o.RemoteTargetSecretNamespace,
+		"The namespace of the kubeconfig secrets of the remote target clusters on the cluster the agent runs on. "+
+			"A manifestwork referencing a secret in the namespace with the annotation "+
+			"work.open-cluster-management.io/target-kubeconfig-secret is applied to the remote target cluster "+
+			"instead of the managed cluster. The remote target clusters are disabled if it is empty.")
+	fs.StringSliceVar(&o.RemoteTargetAllowedSecrets, "remote-target-allowed-secrets", o.RemoteTargetAllowedSecrets,
+		"The names of the kubeconfig secrets in the remote target secret namespace the manifestworks are allowed "+
+			"to reference. No remote target cluster is allowed if it is empty.")
+	o.GRPCTransportOptions.AddFlags(fs)
+	o.TracingOptions.AddFlags(fs)
+	o.AuditOptions.AddFlags(fs)
+}
+
+// GetHealthCheckers returns the liveness checks of the work agent running in its own process, they restart the agent
+// to trust the CA bundles of the hub distributed by the hub once they are changed.
+func (o *WorkloadAgentOptions) GetHealthCheckers() []healthz.HealthChecker {
+	return []healthz.HealthChecker{o.hubCABundleHealth}
+}
+
+// GetReadinessCheckers returns the readiness contributors of the controllers and the hub connection of the work
+// agent, they are aggregated by health.Readiness.
+func (o *WorkloadAgentOptions) GetReadinessCheckers() []healthz.HealthChecker {
+	checkers := []healthz.HealthChecker{o.hubConnectivityHealth}
+	for _, name := range controllerHealthNames() {
+		checkers = append(checkers, o.controllerHealths[name])
+	}
+	return checkers
+}
+
+func controllerHealthNames() []string {
+	return []string{
+		manifestWorkHealthName,
+		addFinalizerHealthName,
+		appliedManifestWorkFinalizeHealthName,
+		manifestWorkFinalizeHealthName,
+		unmanagedAppliedManifestWorkHealthName,
+		appliedManifestWorkHealthName,
+		availableStatusHealthName,
+	}
 }
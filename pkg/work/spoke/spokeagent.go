@@ -2,16 +2,22 @@ package spoke
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path"
 	"time"
 
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
-	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"github.com/openshift/library-go/pkg/operator/events"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 
 	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
 	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
@@ -19,19 +25,29 @@ import (
 	workv1informers "open-cluster-management.io/api/client/work/informers/externalversions/work/v1"
 	ocmfeature "open-cluster-management.io/api/feature"
 	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/constants"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic"
-	cloudeventswork "open-cluster-management.io/sdk-go/pkg/cloudevents/work"
+	cloudeventsoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/agent/codec"
 	"open-cluster-management.io/sdk-go/pkg/cloudevents/work/store"
 
+	"open-cluster-management.io/ocm/pkg/common/cloudevents"
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	commonhelpers "open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/common/options"
+	"open-cluster-management.io/ocm/pkg/common/tracing"
 	"open-cluster-management.io/ocm/pkg/features"
+	"open-cluster-management.io/ocm/pkg/registration/clientcert"
 	"open-cluster-management.io/ocm/pkg/work/helper"
 	"open-cluster-management.io/ocm/pkg/work/spoke/auth"
+	"open-cluster-management.io/ocm/pkg/work/spoke/auth/basic"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/appliedmanifestcontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/finalizercontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/manifestcontroller"
+	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/resourcereadcontroller"
 	"open-cluster-management.io/ocm/pkg/work/spoke/controllers/statuscontroller"
+	"open-cluster-management.io/ocm/pkg/work/spoke/target"
 )
 
 const (
@@ -44,11 +60,18 @@ const (
 	appliedManifestWorkFinalizeControllerWorkers = 10
 	manifestWorkFinalizeControllerWorkers        = 10
 	availableStatusControllerWorkers             = 10
+
+	// hubOutagePublishInterval is the interval to publish the outage of the hub observed by the circuit breaker.
+	hubOutagePublishInterval = 30 * time.Second
 )
 
 type WorkAgentConfig struct {
 	agentOptions *options.AgentOptions
 	workOptions  *WorkloadAgentOptions
+
+	// managementKubeClient is set only if the work agent runs in its own process, it is used to publish the FIPS
+	// violations of the agent and the outages of the hub to the registration agent.
+	managementKubeClient kubernetes.Interface
 }
 
 // NewWorkAgentConfig returns a WorkAgentConfig
@@ -67,38 +90,78 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 	if err != nil {
 		return err
 	}
-
-	spokeDynamicClient, err := dynamic.NewForConfig(spokeRestConfig)
-	if err != nil {
-		return err
-	}
-	spokeKubeClient, err := kubernetes.NewForConfig(spokeRestConfig)
-	if err != nil {
-		return err
+	if o.agentOptions.ReportDeniedActions {
+		commonhelpers.ReportDeniedActions(spokeRestConfig, controllerContext.EventRecorder)
 	}
-	spokeAPIExtensionClient, err := apiextensionsclient.NewForConfig(spokeRestConfig)
-	if err != nil {
+	if err := o.workOptions.AuditOptions.Setup(ctx, spokeRestConfig); err != nil {
 		return err
 	}
-	spokeWorkClient, err := workclientset.NewForConfig(spokeRestConfig)
+
+	spokeClients, err := NewSpokeClients(spokeRestConfig)
 	if err != nil {
 		return err
 	}
-	spokeWorkInformerFactory := workinformers.NewSharedInformerFactory(spokeWorkClient, 5*time.Minute)
 
-	httpClient, err := rest.HTTPClientFor(spokeRestConfig)
-	if err != nil {
-		return err
+	if fips.Enabled() || o.agentOptions.HubCircuitBreaker() != nil {
+		o.managementKubeClient, err = kubernetes.NewForConfig(controllerContext.KubeConfig)
+		if err != nil {
+			return err
+		}
 	}
-	restMapper, err := apiutil.NewDynamicRESTMapper(spokeRestConfig, httpClient)
+
+	// the CA bundles of the hub distributed by the registration agent are loaded into the hub client config, the
+	// agent is restarted once they are changed. In the singleton mode, the registration agent in the same process
+	// restarts the agent instead.
+	hubCABundleFile := path.Join(o.agentOptions.HubKubeconfigDir, clientcert.HubCABundleFile)
+	loadedHubCABundle, _ := os.ReadFile(path.Clean(hubCABundleFile))
+	go o.workOptions.hubCABundleHealth.Run(ctx, hubCABundleFile, loadedHubCABundle)
+
+	return o.RunWorkloadAgentWithSpokeClients(ctx, controllerContext, spokeClients)
+}
+
+// RunWorkloadAgentWithSpokeClients starts the controllers on agent with the given clients of the managed cluster.
+func (o *WorkAgentConfig) RunWorkloadAgentWithSpokeClients(ctx context.Context,
+	controllerContext *controllercmd.ControllerContext, spokeClients *SpokeClients) error {
+	// suppress the repeated events, e.g. the failures to apply the manifests during an outage of the managed cluster
+	recorder := commonhelpers.NewRateLimitedRecorder(controllerContext.EventRecorder, commonhelpers.EventRateLimitInterval)
+
+	shutdownTracing, err := o.workOptions.TracingOptions.Setup(
+		ctx, "work-agent", tracing.WorkDriverKey.String(o.workOptions.WorkloadSourceDriver))
 	if err != nil {
 		return err
 	}
+	defer shutdownTracing()
+
+	spokeRestConfig := spokeClients.RestConfig
+	spokeDynamicClient := spokeClients.DynamicClient
+	spokeMetadataClient := spokeClients.MetadataClient
+	spokeKubeClient := spokeClients.KubeClient
+	spokeAPIExtensionClient := spokeClients.APIExtensionClient
+	spokeWorkClient := spokeClients.WorkClient
+	spokeWorkInformerFactory := spokeClients.WorkInformerFactory
+	restMapper := spokeClients.RESTMapper
 
 	hubHost, hubWorkClient, hubWorkInformer, err := o.newHubWorkClientAndInformer(ctx, restMapper)
 	if err != nil {
 		return err
 	}
+	if o.managementKubeClient != nil && fips.Enabled() {
+		// the violations are recorded once the hub client is built, the failure is not fatal since the violations
+		// only affect the status of the ManagedCluster.
+		if err := fips.PublishViolations(ctx, o.managementKubeClient.CoreV1(), o.agentOptions.ComponentNamespace,
+			"work-agent"); err != nil {
+			klog.FromContext(ctx).Error(err, "Failed to publish the FIPS violations")
+		}
+	}
+	if breaker := o.agentOptions.HubCircuitBreaker(); o.managementKubeClient != nil && breaker != nil {
+		// the outages are published on the management cluster, so they are published while the hub is unreachable.
+		go wait.UntilWithContext(ctx, func(ctx context.Context) {
+			if err := commonhelpers.PublishHubOutage(ctx, o.managementKubeClient.CoreV1(),
+				o.agentOptions.ComponentNamespace, commonhelpers.LastHubOutage("work-agent", breaker)); err != nil {
+				klog.FromContext(ctx).Error(err, "Failed to publish the outage of the hub")
+			}
+		}, hubOutagePublishInterval)
+	}
 
 	agentID := o.agentOptions.AgentID
 	hubHash := helper.HubHash(hubHost)
@@ -106,19 +169,25 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		agentID = hubHash
 	}
 
+	targets, err := o.newTargetResolver(ctx, controllerContext)
+	if err != nil {
+		return err
+	}
+
 	// create controllers
 	validator := auth.NewFactory(
 		spokeRestConfig,
 		spokeKubeClient,
 		hubWorkInformer,
 		o.agentOptions.SpokeClusterName,
-		controllerContext.EventRecorder,
+		recorder,
 		restMapper,
 	).NewExecutorValidator(ctx, features.SpokeMutableFeatureGate.Enabled(ocmfeature.ExecutorValidatingCaches))
 
 	manifestWorkController := manifestcontroller.NewManifestWorkController(
-		controllerContext.EventRecorder,
+		recorder,
 		spokeDynamicClient,
+		spokeMetadataClient,
 		spokeKubeClient,
 		spokeAPIExtensionClient,
 		hubWorkClient,
@@ -129,74 +198,184 @@ func (o *WorkAgentConfig) RunWorkloadAgent(ctx context.Context, controllerContex
 		hubHash, agentID,
 		restMapper,
 		validator,
+		targets,
+		o.workOptions.controllerHealths[manifestWorkHealthName],
 	)
 	addFinalizerController := finalizercontroller.NewAddFinalizerController(
-		controllerContext.EventRecorder,
+		recorder,
 		hubWorkClient,
 		hubWorkInformer,
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
+		o.workOptions.controllerHealths[addFinalizerHealthName],
 	)
 	appliedManifestWorkFinalizeController := finalizercontroller.NewAppliedManifestWorkFinalizeController(
-		controllerContext.EventRecorder,
-		spokeDynamicClient,
+		recorder,
+		spokeMetadataClient,
+		targets,
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
 		agentID,
+		o.workOptions.controllerHealths[appliedManifestWorkFinalizeHealthName],
 	)
 	manifestWorkFinalizeController := finalizercontroller.NewManifestWorkFinalizeController(
-		controllerContext.EventRecorder,
+		recorder,
 		hubWorkClient,
 		hubWorkInformer,
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
 		hubHash,
+		o.workOptions.controllerHealths[manifestWorkFinalizeHealthName],
 	)
 	unmanagedAppliedManifestWorkController := finalizercontroller.NewUnManagedAppliedWorkController(
-		controllerContext.EventRecorder,
+		recorder,
 		hubWorkInformer,
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
 		o.workOptions.AppliedManifestWorkEvictionGracePeriod,
 		hubHash, agentID,
+		o.workOptions.controllerHealths[unmanagedAppliedManifestWorkHealthName],
 	)
 	appliedManifestWorkController := appliedmanifestcontroller.NewAppliedManifestWorkController(
-		controllerContext.EventRecorder,
-		spokeDynamicClient,
+		recorder,
+		spokeMetadataClient,
+		targets,
 		hubWorkInformer,
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		spokeWorkClient.WorkV1().AppliedManifestWorks(),
 		spokeWorkInformerFactory.Work().V1().AppliedManifestWorks(),
 		hubHash,
+		o.workOptions.controllerHealths[appliedManifestWorkHealthName],
 	)
 	availableStatusController := statuscontroller.NewAvailableStatusController(
-		controllerContext.EventRecorder,
+		recorder,
 		spokeDynamicClient,
+		targets,
 		hubWorkClient,
 		hubWorkInformer,
 		hubWorkInformer.Lister().ManifestWorks(o.agentOptions.SpokeClusterName),
 		o.workOptions.MaxJSONRawLength,
 		o.workOptions.StatusSyncInterval,
+		o.workOptions.StatusUpdateDebounceInterval,
+		o.workOptions.StatusFeedbackMinChange,
+		o.workOptions.controllerHealths[availableStatusHealthName],
 	)
 
+	// the resource reads are only supported with the kube driver, since they are custom resources on the hub.
+	if len(o.workOptions.ResourceReadAllowedResources) > 0 && o.workOptions.WorkloadSourceDriver == "kube" {
+		if err := o.runResourceReadController(ctx, recorder, spokeClients); err != nil {
+			return err
+		}
+	}
+
 	go spokeWorkInformerFactory.Start(ctx.Done())
 	go hubWorkInformer.Informer().Run(ctx.Done())
 
+	hubWorkSynced := hubWorkInformer.Informer().HasSynced
+	appliedWorkSynced := spokeWorkInformerFactory.Work().V1().AppliedManifestWorks().Informer().HasSynced
+	for name, h := range o.workOptions.controllerHealths {
+		if name == appliedManifestWorkFinalizeHealthName {
+			h.Start(appliedWorkSynced)
+			continue
+		}
+		h.Start(hubWorkSynced, appliedWorkSynced)
+	}
+	// the cloudevents clients read the works from the local store, so the hub connection is only probed with
+	// the kube driver.
+	if o.workOptions.WorkloadSourceDriver == "kube" {
+		go o.workOptions.hubConnectivityHealth.Run(ctx, func(ctx context.Context) error {
+			_, err := hubWorkClient.List(ctx, metav1.ListOptions{Limit: 1})
+			return err
+		})
+	}
+
 	go addFinalizerController.Run(ctx, 1)
-	go appliedManifestWorkFinalizeController.Run(ctx, appliedManifestWorkFinalizeControllerWorkers)
-	go unmanagedAppliedManifestWorkController.Run(ctx, 1)
-	go appliedManifestWorkController.Run(ctx, 1)
-	go manifestWorkController.Run(ctx, 1)
-	go manifestWorkFinalizeController.Run(ctx, manifestWorkFinalizeControllerWorkers)
 	go availableStatusController.Run(ctx, availableStatusControllerWorkers)
+	// the resources are neither applied nor deleted when the agent is paused, the status of the applied resources
+	// is still reported.
+	if o.agentOptions.Paused {
+		klog.FromContext(ctx).Info("The agent is paused, the manifestworks are not applied")
+	} else {
+		go appliedManifestWorkFinalizeController.Run(ctx, appliedManifestWorkFinalizeControllerWorkers)
+		go unmanagedAppliedManifestWorkController.Run(ctx, 1)
+		go appliedManifestWorkController.Run(ctx, 1)
+		go manifestWorkController.Run(ctx, 1)
+		go manifestWorkFinalizeController.Run(ctx, manifestWorkFinalizeControllerWorkers)
+	}
 
 	<-ctx.Done()
 
 	return nil
 }
 
-func buildCodecs(codecNames []string, restMapper meta.RESTMapper) []generic.Codec[*workv1.ManifestWork] {
+// newTargetResolver returns the resolver of the clients of the remote target clusters with the kubeconfig secrets
+// on the cluster the agent runs on, it is nil if the remote target clusters are disabled.
+func (o *WorkAgentConfig) newTargetResolver(ctx context.Context,
+	controllerContext *controllercmd.ControllerContext) (*target.Resolver, error) {
+	if len(o.workOptions.RemoteTargetSecretNamespace) == 0 {
+		return nil, nil
+	}
+	kubeClient, err := kubernetes.NewForConfig(controllerContext.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	secretInformer := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 10*time.Minute,
+		kubeinformers.WithNamespace(o.workOptions.RemoteTargetSecretNamespace)).Core().V1().Secrets()
+	resolver, err := target.NewResolver(secretInformer, o.workOptions.RemoteTargetSecretNamespace,
+		o.workOptions.RemoteTargetAllowedSecrets)
+	if err != nil {
+		return nil, err
+	}
+	go secretInformer.Informer().Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), secretInformer.Informer().HasSynced) {
+		return nil, fmt.Errorf("failed to sync the kubeconfig secrets of the remote target clusters")
+	}
+	return resolver, nil
+}
+
+// runResourceReadController starts the controller to read the resources on the managed cluster for the
+// ResourceReads in the cluster namespace on the hub.
+func (o *WorkAgentConfig) runResourceReadController(ctx context.Context,
+	recorder events.Recorder, spokeClients *SpokeClients) error {
+	hubConfig, err := o.agentOptions.HubKubeConfig(o.workOptions.WorkloadSourceConfig)
+	if err != nil {
+		return err
+	}
+	hubHTTPClient, err := o.agentOptions.HubHTTPClient(o.workOptions.WorkloadSourceConfig, hubConfig)
+	if err != nil {
+		return err
+	}
+	hubDynamicClient, err := dynamic.NewForConfigAndClient(hubConfig, hubHTTPClient)
+	if err != nil {
+		return err
+	}
+
+	resourceReadInformer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+		hubDynamicClient, 10*time.Minute, o.agentOptions.SpokeClusterName, nil).
+		ForResource(resourcereadcontroller.ResourceReadResource)
+	resourceReadController := resourcereadcontroller.NewResourceReadController(
+		recorder,
+		hubDynamicClient.Resource(resourcereadcontroller.ResourceReadResource).Namespace(o.agentOptions.SpokeClusterName),
+		resourceReadInformer,
+		o.agentOptions.SpokeClusterName,
+		spokeClients.DynamicClient,
+		spokeClients.RESTMapper,
+		basic.NewSARValidator(spokeClients.RestConfig, spokeClients.KubeClient),
+		&resourcereadcontroller.ReadPolicy{
+			Allowed: o.workOptions.ResourceReadAllowedResources,
+			Denied:  o.workOptions.ResourceReadDeniedResources,
+		},
+	)
+
+	go resourceReadInformer.Informer().Run(ctx.Done())
+	go resourceReadController.Run(ctx, 1)
+	return nil
+}
+
+// buildCodecs returns the codecs of the agent and the delta codecs wrapped by them, if the delta spec is enabled.
+func buildCodecs(codecNames []string, restMapper meta.RESTMapper, encryptionKeyFile string,
+	deltaSpec bool) ([]generic.Codec[*workv1.ManifestWork], []*cloudevents.DeltaCodec) {
 	codecs := []generic.Codec[*workv1.ManifestWork]{}
 	for _, name := range codecNames {
 		if name == manifestBundleCodecName {
@@ -207,7 +386,26 @@ func buildCodecs(codecNames []string, restMapper meta.RESTMapper) []generic.Code
 			codecs = append(codecs, codec.NewManifestCodec(restMapper))
 		}
 	}
-	return codecs
+
+	var deltaCodecs []*cloudevents.DeltaCodec
+	if deltaSpec {
+		for i := range codecs {
+			deltaCodec := cloudevents.NewDeltaCodec(codecs[i])
+			deltaCodecs = append(deltaCodecs, deltaCodec)
+			codecs[i] = deltaCodec
+		}
+	}
+
+	if len(encryptionKeyFile) == 0 {
+		return codecs, deltaCodecs
+	}
+
+	// the works are encrypted with the key of current cluster by the source
+	keyGetter := cloudevents.NewFileEncryptionKeyGetter(encryptionKeyFile)
+	for i := range codecs {
+		codecs[i] = cloudevents.NewEncryptionCodec(codecs[i], keyGetter)
+	}
+	return codecs, deltaCodecs
 }
 
 func (o *WorkAgentConfig) newHubWorkClientAndInformer(
@@ -216,15 +414,21 @@ func (o *WorkAgentConfig) newHubWorkClientAndInformer(
 ) (string, workv1client.ManifestWorkInterface, workv1informers.ManifestWorkInformer, error) {
 	var workClient workclientset.Interface
 	var watcherStore *store.AgentInformerWatcherStore
+	var deltaCodecs []*cloudevents.DeltaCodec
 	var hubHost string
 
 	if o.workOptions.WorkloadSourceDriver == "kube" {
-		config, err := clientcmd.BuildConfigFromFlags("", o.workOptions.WorkloadSourceConfig)
+		config, err := o.agentOptions.HubKubeConfig(o.workOptions.WorkloadSourceConfig)
 		if err != nil {
 			return "", nil, nil, err
 		}
 
-		workClient, err = workclientset.NewForConfig(config)
+		// the http client is shared with the registration agent in the Singleton mode.
+		hubHTTPClient, err := o.agentOptions.HubHTTPClient(o.workOptions.WorkloadSourceConfig, config)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		workClient, err = workclientset.NewForConfigAndClient(config, hubHTTPClient)
 		if err != nil {
 			return "", nil, nil, err
 		}
@@ -237,24 +441,11 @@ func (o *WorkAgentConfig) newHubWorkClientAndInformer(
 
 		watcherStore = store.NewAgentInformerWatcherStore()
 
-		serverHost, config, err := generic.NewConfigLoader(o.workOptions.WorkloadSourceDriver, o.workOptions.WorkloadSourceConfig).
-			LoadConfig()
-		if err != nil {
-			return "", nil, nil, err
-		}
-
-		clientHolder, err := cloudeventswork.NewClientHolderBuilder(config).
-			WithClientID(o.workOptions.CloudEventsClientID).
-			WithClusterName(o.agentOptions.SpokeClusterName).
-			WithCodecs(buildCodecs(o.workOptions.CloudEventsClientCodecs, restMapper)...).
-			WithWorkClientWatcherStore(watcherStore).
-			NewAgentClientHolder(ctx)
+		var err error
+		hubHost, workClient, deltaCodecs, err = o.newCloudEventsWorkClient(ctx, restMapper, watcherStore)
 		if err != nil {
 			return "", nil, nil, err
 		}
-
-		hubHost = serverHost
-		workClient = clientHolder.WorkInterface()
 	}
 
 	factory := workinformers.NewSharedInformerFactoryWithOptions(
@@ -269,5 +460,81 @@ func (o *WorkAgentConfig) newHubWorkClientAndInformer(
 		watcherStore.SetStore(informer.Informer().GetStore())
 	}
 
+	// the works removed by a resync are not received as deletion events by the delta codecs
+	for _, deltaCodec := range deltaCodecs {
+		if _, err := informer.Informer().AddEventHandler(deltaCodec.EvictionHandler()); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
 	return hubHost, workClient.WorkV1().ManifestWorks(o.agentOptions.SpokeClusterName), informer, nil
 }
+
+func (o *WorkAgentConfig) newCloudEventsWorkClient(
+	ctx context.Context,
+	restMapper meta.RESTMapper,
+	watcherStore *store.AgentInformerWatcherStore,
+) (string, workclientset.Interface, []*cloudevents.DeltaCodec, error) {
+	serverHost, agentOptions, err := o.newCloudEventsAgentOptions()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	codecs, deltaCodecs := buildCodecs(o.workOptions.CloudEventsClientCodecs, restMapper,
+		o.workOptions.CloudEventsEncryptionKeyFile, o.workOptions.CloudEventsDeltaSpec)
+	workClient, err := cloudevents.NewAgentWorkClientSet(
+		ctx,
+		agentOptions,
+		watcherStore,
+		cloudevents.ResyncOptions{
+			Interval: o.workOptions.CloudEventsResyncInterval,
+			Window:   o.workOptions.CloudEventsResyncWindow,
+		},
+		codecs...,
+	)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return serverHost, workClient, deltaCodecs, nil
+}
+
+func (o *WorkAgentConfig) newCloudEventsAgentOptions() (string, *cloudeventsoptions.CloudEventsAgentOptions, error) {
+	if o.workOptions.WorkloadSourceDriver == constants.ConfigTypeGRPC {
+		grpcOptions, err := grpc.BuildGRPCOptionsFromFlags(o.workOptions.WorkloadSourceConfig)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// if the client certificate is not specified, use the client certificate in the hub kubeconfig secret,
+		// which is issued and rotated by the client certificate controller of the registration agent.
+		if len(grpcOptions.CAFile) != 0 && len(grpcOptions.ClientCertFile) == 0 && len(grpcOptions.ClientKeyFile) == 0 {
+			grpcOptions.ClientCertFile = path.Join(o.agentOptions.HubKubeconfigDir, clientcert.TLSCertFile)
+			grpcOptions.ClientKeyFile = path.Join(o.agentOptions.HubKubeconfigDir, clientcert.TLSKeyFile)
+		}
+		cloudevents.ConfigureFIPS(grpcOptions)
+
+		agentOptions, err := cloudevents.NewGRPCAgentOptions(grpcOptions, o.workOptions.GRPCTransportOptions,
+			o.agentOptions.SpokeClusterName, o.workOptions.CloudEventsClientID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return grpcOptions.URL, agentOptions, nil
+	}
+
+	serverHost, config, err := generic.NewConfigLoader(o.workOptions.WorkloadSourceDriver, o.workOptions.WorkloadSourceConfig).
+		LoadConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	cloudevents.ConfigureFIPS(config)
+
+	agentOptions, err := generic.BuildCloudEventsAgentOptions(
+		config, o.agentOptions.SpokeClusterName, o.workOptions.CloudEventsClientID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return serverHost, agentOptions, nil
+}
@@ -0,0 +1,79 @@
+package spoke
+
+import (
+	"net/http"
+	"time"
+
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+)
+
+// SpokeClients are the clients and informers of the managed cluster used by the work agent. They share one http
+// client, so the connections to the managed cluster are reused, and they can be shared with the other agents
+// running in the same process, e.g. the registration agent in the Singleton mode.
+type SpokeClients struct {
+	RestConfig         *rest.Config
+	HTTPClient         *http.Client
+	RESTMapper         meta.RESTMapper
+	DynamicClient      dynamic.Interface
+	MetadataClient     metadata.Interface
+	KubeClient         kubernetes.Interface
+	APIExtensionClient apiextensionsclient.Interface
+	WorkClient         workclientset.Interface
+
+	// WorkInformerFactory is started by the work agent.
+	WorkInformerFactory workinformers.SharedInformerFactory
+}
+
+// NewSpokeClients builds the clients and informers of the managed cluster with the rest config.
+func NewSpokeClients(spokeRestConfig *rest.Config) (*SpokeClients, error) {
+	httpClient, err := rest.HTTPClientFor(spokeRestConfig)
+	if err != nil {
+		return nil, err
+	}
+	restMapper, err := apiutil.NewDynamicRESTMapper(spokeRestConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfigAndClient(spokeRestConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	metadataClient, err := metadata.NewForConfigAndClient(spokeRestConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfigAndClient(spokeRestConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	apiExtensionClient, err := apiextensionsclient.NewForConfigAndClient(spokeRestConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	workClient, err := workclientset.NewForConfigAndClient(spokeRestConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpokeClients{
+		RestConfig:          spokeRestConfig,
+		HTTPClient:          httpClient,
+		RESTMapper:          restMapper,
+		DynamicClient:       dynamicClient,
+		MetadataClient:      metadataClient,
+		KubeClient:          kubeClient,
+		APIExtensionClient:  apiExtensionClient,
+		WorkClient:          workClient,
+		WorkInformerFactory: workinformers.NewSharedInformerFactory(workClient, 5*time.Minute),
+	}, nil
+}
@@ -0,0 +1,27 @@
+package spoke
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestNewSpokeClients(t *testing.T) {
+	clients, err := NewSpokeClients(&rest.Config{Host: "https://localhost:6443"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clients.HTTPClient == nil || clients.RESTMapper == nil || clients.KubeClient == nil ||
+		clients.DynamicClient == nil || clients.MetadataClient == nil || clients.APIExtensionClient == nil || clients.WorkClient == nil ||
+		clients.WorkInformerFactory == nil {
+		t.Errorf("expect all clients are built, but got %v", clients)
+	}
+
+	_, err = NewSpokeClients(&rest.Config{
+		Host:            "https://localhost:6443",
+		TLSClientConfig: rest.TLSClientConfig{CertFile: "/nonexistent/tls.crt", KeyFile: "/nonexistent/tls.key"},
+	})
+	if err == nil {
+		t.Errorf("expect error with invalid client certificate, but got nil")
+	}
+}
@@ -0,0 +1,223 @@
+package target
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+	"open-cluster-management.io/ocm/pkg/work/spoke/apply"
+)
+
+// TargetKubeconfigSecretAnnotationKey is the annotation of a manifestwork referencing the secret with the kubeconfig
+// of the remote target cluster, the manifests of the manifestwork are applied to the target cluster instead of the
+// managed cluster. The secret is in the remote target secret namespace of the work agent, and the annotation is
+// copied to the appliedmanifestwork, so the applied resources are deleted from the same cluster.
+// TODO move this to the api repo.
+const TargetKubeconfigSecretAnnotationKey = "work.open-cluster-management.io/target-kubeconfig-secret"
+
+// KubeconfigSecretKey is the key of the kubeconfig in the secret of a remote target cluster.
+const KubeconfigSecretKey = "kubeconfig"
+
+// Owner returns the owner of the resources applied for the appliedmanifestwork. The resources on a remote target
+// cluster are not owned by the appliedmanifestwork on the managed cluster, since the garbage collector of the target
+// cluster would delete them, so the owner is empty and the resources are deleted by the uids recorded in the
+// appliedmanifestwork instead.
+func Owner(appliedManifestWork *workapiv1.AppliedManifestWork) metav1.OwnerReference {
+	if len(SecretName(appliedManifestWork)) > 0 {
+		return metav1.OwnerReference{}
+	}
+	return *helper.NewAppliedManifestWorkOwner(appliedManifestWork)
+}
+
+// SecretName returns the name of the secret of the remote target cluster of the object, it is empty if the
+// resources of the object are on the managed cluster.
+func SecretName(obj metav1.Object) string {
+	return obj.GetAnnotations()[TargetKubeconfigSecretAnnotationKey]
+}
+
+// Clients are the clients of a remote target cluster.
+type Clients struct {
+	// Remote is true for the clients of a remote target cluster, the resources applied with them are not owned by
+	// the appliedmanifestwork.
+	Remote bool
+
+	RESTMapper         meta.RESTMapper
+	DynamicClient      dynamic.Interface
+	MetadataClient     metadata.Interface
+	KubeClient         kubernetes.Interface
+	APIExtensionClient apiextensionsclient.Interface
+	Appliers           *apply.Appliers
+}
+
+// Resolver builds the clients of the remote target clusters from the allowed kubeconfig secrets in the remote target
+// secret namespace. The clients are cached until the secret is changed or deleted.
+type Resolver struct {
+	secretLister   corev1lister.SecretNamespaceLister
+	allowedSecrets sets.Set[string]
+
+	lock    sync.Mutex
+	clients map[string]*cachedClients
+}
+
+type cachedClients struct {
+	resourceVersion string
+	clients         *Clients
+}
+
+// NewResolver returns a Resolver reading the allowed kubeconfig secrets with the informer of the remote target secret
+// namespace, the manifestworks cannot reference the other secrets in the namespace.
+func NewResolver(secretInformer corev1informers.SecretInformer, namespace string, allowedSecrets []string) (*Resolver, error) {
+	r := &Resolver{
+		secretLister:   secretInformer.Lister().Secrets(namespace),
+		allowedSecrets: sets.New(allowedSecrets...),
+		clients:        map[string]*cachedClients{},
+	}
+	_, err := secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// the secrets are updated with the same resource version on the resyncs
+			if oldObj.(*corev1.Secret).ResourceVersion != newObj.(*corev1.Secret).ResourceVersion {
+				r.evict(newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			r.evict(obj)
+		},
+	})
+	return r, err
+}
+
+// evict removes the cached clients of the changed or deleted secret.
+func (r *Resolver) evict(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.clients, secret.Name)
+}
+
+// Clients returns the clients of the remote target cluster with the kubeconfig in the secret. An error is returned
+// if the remote target clusters are not enabled, i.e. the resolver is nil, or the secret is not allowed.
+func (r *Resolver) Clients(secretName string) (*Clients, error) {
+	if r == nil {
+		return nil, fmt.Errorf("the remote target clusters are not enabled on the work agent")
+	}
+	if !r.allowedSecrets.Has(secretName) {
+		return nil, fmt.Errorf("the kubeconfig secret %q of the remote target cluster is not allowed on the work agent", secretName)
+	}
+
+	secret, err := r.secretLister.Get(secretName)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("the kubeconfig secret %q of the remote target cluster is not found", secretName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if cached, ok := r.clients[secretName]; ok && cached.resourceVersion == secret.ResourceVersion {
+		return cached.clients, nil
+	}
+
+	kubeconfig, ok := secret.Data[KubeconfigSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("no %s in the kubeconfig secret %q of the remote target cluster", KubeconfigSecretKey, secretName)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in the secret %q of the remote target cluster: %w", secretName, err)
+	}
+	clients, err := newClients(config)
+	if err != nil {
+		return nil, err
+	}
+	r.clients[secretName] = &cachedClients{resourceVersion: secret.ResourceVersion, clients: clients}
+	return clients, nil
+}
+
+func newClients(config *rest.Config) (*Clients, error) {
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	restMapper, err := apiutil.NewDynamicRESTMapper(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	metadataClient, err := metadata.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	apiExtensionClient, err := apiextensionsclient.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &Clients{
+		Remote:             true,
+		RESTMapper:         restMapper,
+		DynamicClient:      dynamicClient,
+		MetadataClient:     metadataClient,
+		KubeClient:         kubeClient,
+		APIExtensionClient: apiExtensionClient,
+		Appliers:           apply.NewAppliers(dynamicClient, kubeClient, apiExtensionClient),
+	}, nil
+}
+
+// MetadataClient returns the metadata client of the cluster the resources of the object are applied to, it is the
+// local client if the object does not reference a remote target cluster.
+func (r *Resolver) MetadataClient(obj metav1.Object, local metadata.Interface) (metadata.Interface, error) {
+	secretName := SecretName(obj)
+	if len(secretName) == 0 {
+		return local, nil
+	}
+	clients, err := r.Clients(secretName)
+	if err != nil {
+		return nil, err
+	}
+	return clients.MetadataClient, nil
+}
+
+// DynamicClient returns the dynamic client of the cluster the resources of the object are applied to, it is the
+// local client if the object does not reference a remote target cluster.
+func (r *Resolver) DynamicClient(obj metav1.Object, local dynamic.Interface) (dynamic.Interface, error) {
+	secretName := SecretName(obj)
+	if len(secretName) == 0 {
+		return local, nil
+	}
+	clients, err := r.Clients(secretName)
+	if err != nil {
+		return nil, err
+	}
+	return clients.DynamicClient, nil
+}
@@ -0,0 +1,122 @@
+package target
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	workapiv1 "open-cluster-management.io/api/work/v1"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+users:
+- name: remote
+  user:
+    token: token
+`
+
+func newSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "targets"},
+		Data:       map[string][]byte{KubeconfigSecretKey: []byte(kubeconfig)},
+	}
+}
+
+func TestClients(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset(newSecret("remote"), newSecret("denied"))
+	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace("targets"))
+	secretInformer := informerFactory.Core().V1().Secrets()
+	resolver, err := NewResolver(secretInformer, "targets", []string{"remote", "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	informerFactory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), secretInformer.Informer().HasSynced) {
+		t.Fatal("failed to sync the secrets")
+	}
+
+	var nilResolver *Resolver
+	if _, err := nilResolver.Clients("remote"); err == nil {
+		t.Errorf("expected error if the remote target clusters are disabled")
+	}
+	if _, err := resolver.Clients("denied"); err == nil {
+		t.Errorf("expected error if the secret is not allowed")
+	}
+	if _, err := resolver.Clients("unknown"); err == nil {
+		t.Errorf("expected error if the secret is not found")
+	}
+
+	clients, err := resolver.Clients("remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clients.Remote {
+		t.Errorf("expected the clients of a remote target cluster")
+	}
+	if cached, _ := resolver.Clients("remote"); cached != clients {
+		t.Errorf("expected the clients are cached")
+	}
+
+	// the cached clients are evicted once the secret is changed or deleted
+	secret := newSecret("remote")
+	secret.ResourceVersion = "2"
+	if _, err := kubeClient.CoreV1().Secrets("targets").Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	assertEvicted(t, resolver, "remote")
+
+	if _, err := resolver.Clients("remote"); err != nil {
+		t.Fatal(err)
+	}
+	if err := kubeClient.CoreV1().Secrets("targets").Delete(ctx, "remote", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	assertEvicted(t, resolver, "remote")
+}
+
+func assertEvicted(t *testing.T, resolver *Resolver, secretName string) {
+	err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, 5*time.Second, true,
+		func(_ context.Context) (bool, error) {
+			resolver.lock.Lock()
+			defer resolver.lock.Unlock()
+			_, ok := resolver.clients[secretName]
+			return !ok, nil
+		})
+	if err != nil {
+		t.Errorf("expected the clients of the secret %q are evicted", secretName)
+	}
+}
+
+func TestOwner(t *testing.T) {
+	appliedWork := &workapiv1.AppliedManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "hub-work", UID: "uid"},
+	}
+	if owner := Owner(appliedWork); owner.UID != "uid" {
+		t.Errorf("expected the appliedmanifestwork owns the local resources, but got %v", owner)
+	}
+
+	appliedWork.Annotations = map[string]string{TargetKubeconfigSecretAnnotationKey: "remote"}
+	if owner := Owner(appliedWork); owner != (metav1.OwnerReference{}) {
+		t.Errorf("expected no owner of the remote resources, but got %v", owner)
+	}
+}
@@ -2,15 +2,22 @@ package common
 
 import (
 	"fmt"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	workv1 "open-cluster-management.io/api/work/v1"
 )
 
 type Validator struct {
 	limit int
+	// forbiddenKinds is the kinds not allowed in the manifests, in the format of kind.group, e.g. Namespace or
+	// ClusterRoleBinding.rbac.authorization.k8s.io
+	forbiddenKinds sets.Set[string]
+	// requireExecutor requires the executor to be set
+	requireExecutor bool
 }
 
 var ManifestValidator = &Validator{limit: 500 * 1024} // the default manifest limit is 500k.
@@ -19,6 +26,25 @@ func (m *Validator) WithLimit(limit int) {
 	m.limit = limit
 }
 
+// WithForbiddenKinds sets the kinds not allowed in the manifests, a kind is in the format of kind.group, and the
+// kinds of the core group have no group suffix.
+func (m *Validator) WithForbiddenKinds(kinds []string) {
+	m.forbiddenKinds = sets.New[string](kinds...)
+}
+
+// WithRequireExecutor requires the executor to be set in the manifestworks.
+func (m *Validator) WithRequireExecutor(require bool) {
+	m.requireExecutor = require
+}
+
+// ValidateExecutor returns an error if the executor is required but not set.
+func (m *Validator) ValidateExecutor(executor *workv1.ManifestWorkExecutor) error {
+	if m.requireExecutor && executor == nil {
+		return fmt.Errorf("the executor is required")
+	}
+	return nil
+}
+
 func (m *Validator) ValidateManifests(manifests []workv1.Manifest) error {
 	if len(manifests) == 0 {
 		return apierrors.NewBadRequest("Workload manifests should not be empty")
@@ -34,7 +60,7 @@ func (m *Validator) ValidateManifests(manifests []workv1.Manifest) error {
 	}
 
 	for _, manifest := range manifests {
-		err := validateManifest(manifest.Raw)
+		err := m.validateManifest(manifest.Raw)
 		if err != nil {
 			return err
 		}
@@ -43,7 +69,7 @@ func (m *Validator) ValidateManifests(manifests []workv1.Manifest) error {
 	return nil
 }
 
-func validateManifest(manifest []byte) error {
+func (m *Validator) validateManifest(manifest []byte) error {
 	// If the manifest cannot be decoded, return err
 	unstructuredObj := &unstructured.Unstructured{}
 	err := unstructuredObj.UnmarshalJSON(manifest)
@@ -62,3 +88,23 @@ func validateManifest(manifest []byte) error {
 
 	return nil
 }
+
+// ValidateForbiddenKinds returns an error if any of the manifests has a forbidden kind. Like the executor, it is only
+// validated on the creations and the spec changes, so the existing manifestworks can still be updated, e.g. to remove
+// the finalizers, after the kinds are forbidden.
+func (m *Validator) ValidateForbiddenKinds(manifests []workv1.Manifest) error {
+	if m.forbiddenKinds.Len() == 0 {
+		return nil
+	}
+	for _, manifest := range manifests {
+		unstructuredObj := &unstructured.Unstructured{}
+		if err := unstructuredObj.UnmarshalJSON(manifest.Raw); err != nil {
+			return err
+		}
+		gvk := unstructuredObj.GroupVersionKind()
+		if kind := strings.TrimSuffix(gvk.Kind+"."+gvk.Group, "."); m.forbiddenKinds.Has(kind) {
+			return fmt.Errorf("kind %s is not allowed in manifest", kind)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	workv1 "open-cluster-management.io/api/work/v1"
 )
@@ -59,3 +60,55 @@ func Test_Validator(t *testing.T) {
 		})
 	}
 }
+
+func newManifestWithKind(apiVersion, kind string) workv1.Manifest {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	objectStr, _ := obj.MarshalJSON()
+	return workv1.Manifest{RawExtension: runtime.RawExtension{Raw: objectStr}}
+}
+
+func TestValidateForbiddenKinds(t *testing.T) {
+	validator := &Validator{limit: 500 * 1024}
+	validator.WithForbiddenKinds([]string{"Namespace", "ClusterRoleBinding.rbac.authorization.k8s.io"})
+
+	cases := []struct {
+		name          string
+		manifest      workv1.Manifest
+		expectedError bool
+	}{
+		{name: "allowed kind", manifest: newManifestWithKind("v1", "ConfigMap")},
+		{name: "forbidden core kind", manifest: newManifestWithKind("v1", "Namespace"), expectedError: true},
+		{
+			name:          "forbidden kind with group",
+			manifest:      newManifestWithKind("rbac.authorization.k8s.io/v1", "ClusterRoleBinding"),
+			expectedError: true,
+		},
+		{name: "same kind in another group", manifest: newManifestWithKind("example.com/v1", "Namespace")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validator.ValidateForbiddenKinds([]workv1.Manifest{c.manifest})
+			if c.expectedError != (err != nil) {
+				t.Errorf("expected error %v, but got %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateExecutor(t *testing.T) {
+	validator := &Validator{}
+	if err := validator.ValidateExecutor(nil); err != nil {
+		t.Errorf("expected the executor is not required, but got %v", err)
+	}
+
+	validator.WithRequireExecutor(true)
+	if err := validator.ValidateExecutor(nil); err == nil {
+		t.Errorf("expected error if the executor is required")
+	}
+	if err := validator.ValidateExecutor(&workv1.ManifestWorkExecutor{}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
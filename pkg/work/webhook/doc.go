@@ -1,2 +1,2 @@
-// package webhook contains the manifestwork admission hook to validate the ManifestWork create and update operations
+// package webhook contains the manifestwork admission hooks to default and validate the ManifestWork create and update operations
 package webhook
@@ -1,12 +1,27 @@
 package webhook
 
-import "github.com/spf13/pflag"
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
 
 // Config contains the server (the webhook) cert and key.
 type Options struct {
-	Port          int
-	CertDir       string
-	ManifestLimit int
+	Port            int
+	CertDir         string
+	ManifestLimit   int
+	ForbiddenKinds  []string
+	RequireExecutor bool
+	// QuotaPerMinute is the max number of the manifestwork creations and updates per cluster namespace per minute,
+	// the throttled requests wait for at most QuotaMaxWait before they are rejected.
+	QuotaPerMinute int
+	QuotaMaxWait   time.Duration
+	// QuotaReplicas is the number of the replicas of the webhook server, each replica counts the quota on its own,
+	// so it admits its share of QuotaPerMinute.
+	QuotaReplicas int
+	// FIPSMode restricts the TLS configs of the webhook server to the FIPS approved algorithms.
+	FIPSMode bool
 }
 
 // NewOptions constructs a new set of default options for webhook.
@@ -14,6 +29,8 @@ func NewOptions() *Options {
 	return &Options{
 		Port:          9443,
 		ManifestLimit: 500 * 1024, // the default manifest limit is 500k.
+		QuotaMaxWait:  5 * time.Second,
+		QuotaReplicas: 1,
 	}
 }
 
@@ -25,4 +42,21 @@ func (c *Options) AddFlags(fs *pflag.FlagSet) {
 			"webhook server would look up the server key and certificate in {TempDir}/k8s-webhook-server/serving-certs")
 	fs.IntVar(&c.ManifestLimit, "manifestLimit", c.ManifestLimit,
 		"ManifestLimit is the max size of manifests in a manifestWork. If not set, the default is 500k.")
+	fs.StringSliceVar(&c.ForbiddenKinds, "forbidden-kinds", c.ForbiddenKinds,
+		"The kinds not allowed in the manifests of a manifestWork, in the format of kind.group, e.g. "+
+			"ClusterRoleBinding.rbac.authorization.k8s.io. The kinds of the core group have no group suffix, e.g. Namespace.")
+	fs.BoolVar(&c.RequireExecutor, "require-executor", c.RequireExecutor,
+		"Reject the manifestWorks without the executor.")
+	fs.IntVar(&c.QuotaPerMinute, "manifestwork-quota-per-minute", c.QuotaPerMinute,
+		"The max number of the manifestWork creations and spec updates per cluster namespace per minute, the "+
+			"throttled requests are queued and rejected with TooManyRequests if they wait longer than "+
+			"--manifestwork-quota-max-wait. The quota is disabled if it is 0.")
+	fs.DurationVar(&c.QuotaMaxWait, "manifestwork-quota-max-wait", c.QuotaMaxWait,
+		"The max time a throttled manifestWork request is queued, it must be less than the timeout of the webhook.")
+	fs.IntVar(&c.QuotaReplicas, "manifestwork-quota-replicas", c.QuotaReplicas,
+		"The number of the replicas of the webhook server, each replica admits its share of "+
+			"--manifestwork-quota-per-minute since the quota is counted by each replica on its own.")
+	fs.BoolVar(&c.FIPSMode, "fips-mode", c.FIPSMode,
+		"Restrict the TLS connections to the FIPS approved algorithms. The mode is always on if the binary is built "+
+			"with boringcrypto.")
 }
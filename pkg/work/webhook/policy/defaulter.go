@@ -0,0 +1,263 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/helper"
+)
+
+// Defaulter defaults the fields of the ManifestWorks with the presets of the ManifestWorkConfigs referenced by the
+// ManifestWorks, and then with the ManifestWorkDefaultingPolicies selecting their namespaces. The policies are
+// applied in the order of their names, so the first policy setting a field wins.
+type Defaulter struct {
+	policyInformer    cache.SharedIndexInformer
+	configInformer    cache.SharedIndexInformer
+	namespaceInformer cache.SharedIndexInformer
+	namespaceLister   corev1lister.NamespaceLister
+	restMapper        meta.RESTMapper
+}
+
+// NewDefaulter returns a Defaulter watching the policies, the configs and the namespaces on the hub. The rest mapper maps the
+// kinds of the manifests to the resources of the manifest configs, it is reset periodically if it is resettable, so
+// a cached rest mapper discovers the new CRDs.
+func NewDefaulter(dynamicClient dynamic.Interface, kubeClient kubernetes.Interface, restMapper meta.RESTMapper) *Defaulter {
+	namespaceInformer := kubeinformers.NewSharedInformerFactory(kubeClient, 10*time.Minute).Core().V1().Namespaces()
+	return &Defaulter{
+		policyInformer: dynamicinformer.NewFilteredDynamicInformer(
+			dynamicClient, ManifestWorkDefaultingPolicyResource, "", 10*time.Minute, cache.Indexers{}, nil).Informer(),
+		configInformer: dynamicinformer.NewFilteredDynamicInformer(
+			dynamicClient, ManifestWorkConfigResource, "", 10*time.Minute, cache.Indexers{}, nil).Informer(),
+		namespaceInformer: namespaceInformer.Informer(),
+		namespaceLister:   namespaceInformer.Lister(),
+		restMapper:        restMapper,
+	}
+}
+
+// Start runs the informers of the policies, the configs and the namespaces until the context is done.
+func (d *Defaulter) Start(ctx context.Context) error {
+	if mapper, ok := d.restMapper.(meta.ResettableRESTMapper); ok {
+		go wait.UntilWithContext(ctx, func(_ context.Context) {
+			mapper.Reset()
+		}, 10*time.Minute)
+	}
+	go d.namespaceInformer.Run(ctx.Done())
+	go d.configInformer.Run(ctx.Done())
+	d.policyInformer.Run(ctx.Done())
+	return nil
+}
+
+// ReadyCheck fails until the policies, the configs and the namespaces are synced.
+func (d *Defaulter) ReadyCheck(_ *http.Request) error {
+	if !d.hasSynced() {
+		return fmt.Errorf("the manifestwork defaulting policies are not synced")
+	}
+	return nil
+}
+
+func (d *Defaulter) hasSynced() bool {
+	return d.policyInformer.HasSynced() && d.configInformer.HasSynced() && d.namespaceInformer.HasSynced()
+}
+
+// Default sets the unset fields of the ManifestWork to the values of the presets it references, and then to the
+// values of the policies selecting its namespace. A BadRequest error is returned if a referenced preset does not
+// exist.
+func (d *Defaulter) Default(work *workv1.ManifestWork) error {
+	if d == nil {
+		return nil
+	}
+	if !d.hasSynced() {
+		return fmt.Errorf("the manifestwork defaulting policies are not synced")
+	}
+
+	if err := d.applyPresets(work); err != nil {
+		return err
+	}
+
+	policies, err := d.listPolicies(work.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if work.Spec.DeleteOption == nil && policy.Spec.DeleteOption != nil {
+			work.Spec.DeleteOption = policy.Spec.DeleteOption.DeepCopy()
+		}
+		if work.Spec.Executor == nil && policy.Spec.Executor != nil {
+			work.Spec.Executor = policy.Spec.Executor.DeepCopy()
+		}
+		if policy.Spec.UpdateStrategy != nil {
+			d.defaultUpdateStrategy(work, policy.Spec.UpdateStrategy)
+		}
+	}
+	return nil
+}
+
+// applyPresets sets the unset fields of the ManifestWork to the values of the presets referenced by its annotation,
+// in the order they are referenced.
+func (d *Defaulter) applyPresets(work *workv1.ManifestWork) error {
+	value := strings.TrimSpace(work.Annotations[ManifestWorkPresetsAnnotationKey])
+	if len(value) == 0 {
+		return nil
+	}
+
+	presets := d.listPresets()
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+		preset, ok := presets[name]
+		if !ok {
+			return apierrors.NewBadRequest(fmt.Sprintf("the preset %q referenced by the annotation %s is not found",
+				name, ManifestWorkPresetsAnnotationKey))
+		}
+		if work.Spec.DeleteOption == nil && preset.DeleteOption != nil {
+			work.Spec.DeleteOption = preset.DeleteOption.DeepCopy()
+		}
+		if preset.UpdateStrategy != nil {
+			d.defaultUpdateStrategy(work, preset.UpdateStrategy)
+		}
+	}
+	return nil
+}
+
+// listPresets returns the presets of all the configs by name. If the configs have presets with the same name, the
+// preset of the config with the first name wins.
+func (d *Defaulter) listPresets() map[string]ManifestWorkPreset {
+	var configs []*ManifestWorkConfig
+	for _, obj := range d.configInformer.GetStore().List() {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		config := &ManifestWorkConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, config); err != nil {
+			klog.Warningf("failed to convert the manifestwork config %q: %v", u.GetName(), err)
+			continue
+		}
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+
+	presets := map[string]ManifestWorkPreset{}
+	for _, config := range configs {
+		for _, preset := range config.Spec.Presets {
+			if _, ok := presets[preset.Name]; ok {
+				klog.Warningf("the preset %q of the manifestwork config %q is ignored, it is defined by another config",
+					preset.Name, config.Name)
+				continue
+			}
+			presets[preset.Name] = preset
+		}
+	}
+	return presets
+}
+
+// listPolicies returns the policies selecting the namespace sorted by name. There is no policy if the namespace is
+// not found in the cache.
+func (d *Defaulter) listPolicies(namespace string) ([]*ManifestWorkDefaultingPolicy, error) {
+	ns, err := d.namespaceLister.Get(namespace)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []*ManifestWorkDefaultingPolicy
+	for _, obj := range d.policyInformer.GetStore().List() {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		policy := &ManifestWorkDefaultingPolicy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, policy); err != nil {
+			klog.Warningf("failed to convert the manifestwork defaulting policy %q: %v", u.GetName(), err)
+			continue
+		}
+		selector := labels.Everything()
+		if policy.Spec.NamespaceSelector != nil {
+			selector, err = metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+			if err != nil {
+				klog.Warningf("invalid namespace selector of the manifestwork defaulting policy %q: %v", policy.Name, err)
+				continue
+			}
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies, nil
+}
+
+// defaultUpdateStrategy sets the update strategy of the manifests which do not have one in the manifest configs.
+func (d *Defaulter) defaultUpdateStrategy(work *workv1.ManifestWork, strategy *workv1.UpdateStrategy) {
+	for _, manifest := range work.Spec.Workload.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+			// the manifest is rejected by the validating webhook
+			continue
+		}
+
+		gvk := obj.GroupVersionKind()
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		if mapping, err := d.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			gvr = mapping.Resource
+		}
+		resourceMeta := workv1.ManifestResourceMeta{
+			Group:     gvr.Group,
+			Resource:  gvr.Resource,
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		}
+
+		config := helper.FindManifestConiguration(resourceMeta, work.Spec.ManifestConfigs)
+		if config == nil {
+			work.Spec.ManifestConfigs = append(work.Spec.ManifestConfigs, workv1.ManifestConfigOption{
+				ResourceIdentifier: workv1.ResourceIdentifier{
+					Group:     resourceMeta.Group,
+					Resource:  resourceMeta.Resource,
+					Name:      resourceMeta.Name,
+					Namespace: resourceMeta.Namespace,
+				},
+				UpdateStrategy: strategy.DeepCopy(),
+			})
+			continue
+		}
+		if config.UpdateStrategy != nil {
+			continue
+		}
+		for i := range work.Spec.ManifestConfigs {
+			if work.Spec.ManifestConfigs[i].ResourceIdentifier == config.ResourceIdentifier {
+				work.Spec.ManifestConfigs[i].UpdateStrategy = strategy.DeepCopy()
+			}
+		}
+	}
+}
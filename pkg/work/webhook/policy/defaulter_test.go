@@ -0,0 +1,320 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newPolicy(t *testing.T, name string, spec ManifestWorkDefaultingPolicySpec) *unstructured.Unstructured {
+	policy := &ManifestWorkDefaultingPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ManifestWorkDefaultingPolicyResource.GroupVersion().String(),
+			Kind:       "ManifestWorkDefaultingPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func newWork(namespace string) *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "work1", Namespace: namespace},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(
+						`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test","namespace":"default"}}`)}},
+					{RawExtension: runtime.RawExtension{Raw: []byte(
+						`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test","namespace":"default"}}`)}},
+				},
+			},
+		},
+	}
+}
+
+func newConfig(t *testing.T, name string, presets ...ManifestWorkPreset) *unstructured.Unstructured {
+	config := &ManifestWorkConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ManifestWorkConfigResource.GroupVersion().String(),
+			Kind:       "ManifestWorkConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       ManifestWorkConfigSpec{Presets: presets},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func newTestDefaulter(ctx context.Context, t *testing.T, objects ...runtime.Object) *Defaulter {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			ManifestWorkDefaultingPolicyResource: "ManifestWorkDefaultingPolicyList",
+			ManifestWorkConfigResource:           "ManifestWorkConfigList",
+		},
+		objects...)
+	kubeClient := kubefake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Labels: map[string]string{"env": "dev"}}},
+	)
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	defaulter := NewDefaulter(dynamicClient, kubeClient, restMapper)
+	go func() {
+		_ = defaulter.Start(ctx)
+	}()
+	if !cache.WaitForCacheSync(ctx.Done(), defaulter.hasSynced) {
+		t.Fatal("failed to sync the informers")
+	}
+	return defaulter
+}
+
+func TestDefault(t *testing.T) {
+	orphan := &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan}
+	executor := &workv1.ManifestWorkExecutor{
+		Subject: workv1.ManifestWorkExecutorSubject{
+			Type:           workv1.ExecutorSubjectTypeServiceAccount,
+			ServiceAccount: &workv1.ManifestWorkSubjectServiceAccount{Namespace: "default", Name: "sa"},
+		},
+	}
+	serverSideApply := &workv1.UpdateStrategy{Type: workv1.UpdateStrategyTypeServerSideApply}
+	createOnly := &workv1.UpdateStrategy{Type: workv1.UpdateStrategyTypeCreateOnly}
+
+	prodPolicy := newPolicy(t, "prod", ManifestWorkDefaultingPolicySpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		DeleteOption:      orphan,
+		Executor:          executor,
+		UpdateStrategy:    serverSideApply,
+	})
+
+	cases := []struct {
+		name     string
+		work     func() *workv1.ManifestWork
+		validate func(t *testing.T, work *workv1.ManifestWork)
+	}{
+		{
+			name: "namespace is not selected",
+			work: func() *workv1.ManifestWork { return newWork("cluster2") },
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if !equality.Semantic.DeepEqual(work, newWork("cluster2")) {
+					t.Errorf("expected the work is not defaulted, but got %v", work.Spec)
+				}
+			},
+		},
+		{
+			name: "namespace is not in the cache",
+			work: func() *workv1.ManifestWork { return newWork("cluster3") },
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if !equality.Semantic.DeepEqual(work, newWork("cluster3")) {
+					t.Errorf("expected the work is not defaulted, but got %v", work.Spec)
+				}
+			},
+		},
+		{
+			name: "fields are defaulted",
+			work: func() *workv1.ManifestWork { return newWork("cluster1") },
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if !equality.Semantic.DeepEqual(work.Spec.DeleteOption, orphan) {
+					t.Errorf("expected delete option %v, but got %v", orphan, work.Spec.DeleteOption)
+				}
+				if !equality.Semantic.DeepEqual(work.Spec.Executor, executor) {
+					t.Errorf("expected executor %v, but got %v", executor, work.Spec.Executor)
+				}
+				expected := []workv1.ManifestConfigOption{
+					{
+						ResourceIdentifier: workv1.ResourceIdentifier{
+							Group: "apps", Resource: "deployments", Name: "test", Namespace: "default"},
+						UpdateStrategy: serverSideApply,
+					},
+					{
+						ResourceIdentifier: workv1.ResourceIdentifier{
+							Resource: "configmaps", Name: "test", Namespace: "default"},
+						UpdateStrategy: serverSideApply,
+					},
+				}
+				if !equality.Semantic.DeepEqual(work.Spec.ManifestConfigs, expected) {
+					t.Errorf("expected manifest configs %v, but got %v", expected, work.Spec.ManifestConfigs)
+				}
+			},
+		},
+		{
+			name: "fields set by the producer are kept",
+			work: func() *workv1.ManifestWork {
+				work := newWork("cluster1")
+				work.Spec.DeleteOption = &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeForeground}
+				work.Spec.ManifestConfigs = []workv1.ManifestConfigOption{
+					{
+						ResourceIdentifier: workv1.ResourceIdentifier{
+							Group: "apps", Resource: "deployments", Name: "test", Namespace: "default"},
+						UpdateStrategy: createOnly,
+					},
+					{
+						ResourceIdentifier: workv1.ResourceIdentifier{
+							Resource: "configmaps", Name: "test", Namespace: "default"},
+						FeedbackRules: []workv1.FeedbackRule{{Type: workv1.WellKnownStatusType}},
+					},
+				}
+				return work
+			},
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if work.Spec.DeleteOption.PropagationPolicy != workv1.DeletePropagationPolicyTypeForeground {
+					t.Errorf("expected the delete option is kept, but got %v", work.Spec.DeleteOption)
+				}
+				if len(work.Spec.ManifestConfigs) != 2 {
+					t.Fatalf("expected 2 manifest configs, but got %v", work.Spec.ManifestConfigs)
+				}
+				if !equality.Semantic.DeepEqual(work.Spec.ManifestConfigs[0].UpdateStrategy, createOnly) {
+					t.Errorf("expected the update strategy is kept, but got %v", work.Spec.ManifestConfigs[0].UpdateStrategy)
+				}
+				if !equality.Semantic.DeepEqual(work.Spec.ManifestConfigs[1].UpdateStrategy, serverSideApply) {
+					t.Errorf("expected the update strategy is defaulted, but got %v", work.Spec.ManifestConfigs[1].UpdateStrategy)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			defaulter := newTestDefaulter(ctx, t, prodPolicy)
+
+			work := c.work()
+			if err := defaulter.Default(work); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			c.validate(t, work)
+		})
+	}
+}
+
+func TestDefaultPresets(t *testing.T) {
+	orphan := &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan}
+	forceApply := &workv1.UpdateStrategy{
+		Type:            workv1.UpdateStrategyTypeServerSideApply,
+		ServerSideApply: &workv1.ServerSideApplyConfig{Force: true},
+	}
+	serverSideApply := &workv1.UpdateStrategy{Type: workv1.UpdateStrategyTypeServerSideApply}
+
+	objects := []runtime.Object{
+		newConfig(t, "a-config",
+			ManifestWorkPreset{Name: "orphan-on-delete", DeleteOption: orphan},
+			ManifestWorkPreset{Name: "ssa-force", UpdateStrategy: forceApply}),
+		newConfig(t, "b-config", ManifestWorkPreset{Name: "ssa-force", UpdateStrategy: serverSideApply}),
+		newPolicy(t, "prod", ManifestWorkDefaultingPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			DeleteOption:      &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeForeground},
+			UpdateStrategy:    serverSideApply,
+		}),
+	}
+
+	cases := []struct {
+		name        string
+		namespace   string
+		presets     string
+		expectedErr bool
+		validate    func(t *testing.T, work *workv1.ManifestWork)
+	}{
+		{
+			name:      "no presets",
+			namespace: "cluster2",
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if work.Spec.DeleteOption != nil || len(work.Spec.ManifestConfigs) != 0 {
+					t.Errorf("expected the work is not defaulted, but got %v", work.Spec)
+				}
+			},
+		},
+		{
+			name:        "preset is not found",
+			namespace:   "cluster2",
+			presets:     "orphan-on-delete,unknown",
+			expectedErr: true,
+		},
+		{
+			name:      "presets are applied",
+			namespace: "cluster2",
+			presets:   "orphan-on-delete, ssa-force",
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if !equality.Semantic.DeepEqual(work.Spec.DeleteOption, orphan) {
+					t.Errorf("expected delete option %v, but got %v", orphan, work.Spec.DeleteOption)
+				}
+				if len(work.Spec.ManifestConfigs) != 2 {
+					t.Fatalf("expected 2 manifest configs, but got %v", work.Spec.ManifestConfigs)
+				}
+				for _, config := range work.Spec.ManifestConfigs {
+					if !equality.Semantic.DeepEqual(config.UpdateStrategy, forceApply) {
+						t.Errorf("expected the update strategy of the first config, but got %v", config.UpdateStrategy)
+					}
+				}
+			},
+		},
+		{
+			name:      "presets win over the policies",
+			namespace: "cluster1",
+			presets:   "orphan-on-delete",
+			validate: func(t *testing.T, work *workv1.ManifestWork) {
+				if !equality.Semantic.DeepEqual(work.Spec.DeleteOption, orphan) {
+					t.Errorf("expected delete option %v, but got %v", orphan, work.Spec.DeleteOption)
+				}
+				for _, config := range work.Spec.ManifestConfigs {
+					if !equality.Semantic.DeepEqual(config.UpdateStrategy, serverSideApply) {
+						t.Errorf("expected the update strategy of the policy, but got %v", config.UpdateStrategy)
+					}
+				}
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	defaulter := newTestDefaulter(ctx, t, objects...)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			work := newWork(c.namespace)
+			if len(c.presets) > 0 {
+				work.Annotations = map[string]string{ManifestWorkPresetsAnnotationKey: c.presets}
+			}
+			err := defaulter.Default(work)
+			if c.expectedErr {
+				if !apierrors.IsBadRequest(err) {
+					t.Errorf("expected bad request error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			c.validate(t, work)
+		})
+	}
+}
+
+func TestNilDefaulter(t *testing.T) {
+	var defaulter *Defaulter
+	if err := defaulter.Default(newWork("cluster1")); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package policy
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// ManifestWorkDefaultingPolicyResource is the resource of the ManifestWorkDefaultingPolicy, the policy is a cluster
+// scoped resource defined by the admin to default the fields of the ManifestWorks created in the selected cluster
+// namespaces.
+var ManifestWorkDefaultingPolicyResource = schema.GroupVersionResource{
+	Group:    "admission.work.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "manifestworkdefaultingpolicies",
+}
+
+// ManifestWorkDefaultingPolicy defaults the fields of the ManifestWorks when they are created. A field is only
+// defaulted if it is not set by the producer of the ManifestWork.
+type ManifestWorkDefaultingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ManifestWorkDefaultingPolicySpec `json:"spec"`
+}
+
+type ManifestWorkDefaultingPolicySpec struct {
+	// NamespaceSelector selects the cluster namespaces by the namespace labels, all the namespaces are selected if
+	// it is empty.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// DeleteOption is the default delete option of the ManifestWorks.
+	// +optional
+	DeleteOption *workv1.DeleteOption `json:"deleteOption,omitempty"`
+
+	// Executor is the default executor of the ManifestWorks.
+	// +optional
+	Executor *workv1.ManifestWorkExecutor `json:"executor,omitempty"`
+
+	// UpdateStrategy is the default update strategy of the manifests which do not have an update strategy in the
+	// manifest configs of the ManifestWorks.
+	// +optional
+	UpdateStrategy *workv1.UpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+// ManifestWorkConfigResource is the resource of the ManifestWorkConfig, the config is a cluster scoped resource
+// defined by the admin holding the named presets of the ManifestWork options.
+var ManifestWorkConfigResource = schema.GroupVersionResource{
+	Group:    "admission.work.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "manifestworkconfigs",
+}
+
+// ManifestWorkPresetsAnnotationKey is the annotation of a ManifestWork referencing the presets by their names
+// separated by commas, e.g. "orphan-on-delete,ssa-force". The presets are applied in the order they are referenced
+// when the ManifestWork is created.
+// TODO move this to the api repo.
+const ManifestWorkPresetsAnnotationKey = "work.open-cluster-management.io/presets"
+
+// ManifestWorkConfig defines the named presets of the delete and apply options of the ManifestWorks, so the
+// producers reference the presets by name instead of repeating the options in every ManifestWork.
+type ManifestWorkConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ManifestWorkConfigSpec `json:"spec"`
+}
+
+type ManifestWorkConfigSpec struct {
+	// Presets are the named presets of the ManifestWork options.
+	// +optional
+	Presets []ManifestWorkPreset `json:"presets,omitempty"`
+}
+
+// ManifestWorkPreset is a named set of the ManifestWork options. The options only apply to the fields which are not
+// set by the producer of the ManifestWork.
+type ManifestWorkPreset struct {
+	// Name is the name of the preset referenced by the ManifestWorks, it is unique across the configs.
+	Name string `json:"name"`
+
+	// DeleteOption is the delete option of the ManifestWorks.
+	// +optional
+	DeleteOption *workv1.DeleteOption `json:"deleteOption,omitempty"`
+
+	// UpdateStrategy is the update strategy of the manifests which do not have an update strategy in the manifest
+	// configs of the ManifestWorks.
+	// +optional
+	UpdateStrategy *workv1.UpdateStrategy `json:"updateStrategy,omitempty"`
+}
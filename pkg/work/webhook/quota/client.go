@@ -0,0 +1,49 @@
+package quota
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// WrapClient returns the manifestwork client of the namespace whose creations and patches wait for the quota of the
+// namespace. The work sources publishing the manifestworks with the cloudevents drivers bypass the webhook, so the
+// quota is enforced by their clients instead.
+func (l *Limiter) WrapClient(namespace string, client workv1client.ManifestWorkInterface) workv1client.ManifestWorkInterface {
+	if l == nil || l.perMinute <= 0 {
+		return client
+	}
+	return &limitedManifestWorkClient{ManifestWorkInterface: client, namespace: namespace, limiter: l}
+}
+
+type limitedManifestWorkClient struct {
+	workv1client.ManifestWorkInterface
+	namespace string
+	limiter   *Limiter
+}
+
+func (c *limitedManifestWorkClient) Create(
+	ctx context.Context, work *workv1.ManifestWork, opts metav1.CreateOptions) (*workv1.ManifestWork, error) {
+	if len(opts.DryRun) == 0 {
+		if err := c.limiter.Wait(ctx, work, "Create"); err != nil {
+			return nil, err
+		}
+	}
+	return c.ManifestWorkInterface.Create(ctx, work, opts)
+}
+
+// Patch waits for the quota unless the status is patched, the source client publishes the work once it is patched.
+func (c *limitedManifestWorkClient) Patch(ctx context.Context, name string, pt kubetypes.PatchType, data []byte,
+	opts metav1.PatchOptions, subresources ...string) (*workv1.ManifestWork, error) {
+	if len(opts.DryRun) == 0 && len(subresources) == 0 {
+		work := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: c.namespace, Name: name}}
+		if err := c.limiter.Wait(ctx, work, "Update"); err != nil {
+			return nil, err
+		}
+	}
+	return c.ManifestWorkInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
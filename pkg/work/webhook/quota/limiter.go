@@ -0,0 +1,136 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kevents "k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// pruneInterval is the interval to remove the limiters of the idle namespaces.
+const pruneInterval = 10 * time.Minute
+
+// Limiter caps the creations and the spec updates of the manifestworks per cluster namespace per minute, so a
+// runaway producer cannot flood the agents and the brokers of the clusters. A throttled request is queued until the
+// quota of the namespace is available again, and it is rejected with a TooManyRequests error if it cannot be admitted
+// within the max wait, the clients retry the request after the returned delay then. Each replica of the webhook
+// server counts its requests on its own, so the quota is divided by the replicas.
+type Limiter struct {
+	perMinute int
+	// perReplica is the quota counted by this replica.
+	perReplica int
+	maxWait    time.Duration
+	recorder   kevents.EventRecorder
+
+	lock      sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastPrune time.Time
+}
+
+// NewLimiter returns a limiter admitting the perMinute requests per cluster namespace per minute across the replicas
+// of the webhook server, all the requests are admitted if perMinute is not positive.
+func NewLimiter(perMinute, replicas int, maxWait time.Duration) *Limiter {
+	perReplica := perMinute
+	if replicas > 1 {
+		perReplica = int(math.Ceil(float64(perMinute) / float64(replicas)))
+	}
+	return &Limiter{
+		perMinute:  perMinute,
+		perReplica: perReplica,
+		maxWait:    maxWait,
+		limiters:   map[string]*rate.Limiter{},
+		lastPrune:  time.Now(),
+	}
+}
+
+// SetEventRecorder sets the recorder of the events of the throttled requests.
+func (l *Limiter) SetEventRecorder(recorder kevents.EventRecorder) {
+	l.recorder = recorder
+}
+
+// Wait waits until the request of the manifestwork is admitted by the quota of its namespace. A TooManyRequests
+// error is returned if the request cannot be admitted within the max wait or the context is done.
+func (l *Limiter) Wait(ctx context.Context, work *workv1.ManifestWork, action string) error {
+	if l == nil || l.perMinute <= 0 {
+		return nil
+	}
+
+	reservation := l.limiter(work.Namespace).Reserve()
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	if delay > l.maxWait {
+		reservation.Cancel()
+		return l.reject(work, action, delay)
+	}
+
+	l.record(work, corev1.EventTypeNormal, "ManifestWorkThrottled", action,
+		"The %s of the manifestwork is queued for %s by the quota of %d per minute in namespace %q",
+		action, delay.Round(time.Millisecond), l.perMinute, work.Namespace)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return l.reject(work, action, delay)
+	}
+}
+
+func (l *Limiter) limiter(namespace string) *rate.Limiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > pruneInterval {
+		l.prune(now)
+	}
+
+	limiter, ok := l.limiters[namespace]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(float64(l.perReplica)/time.Minute.Seconds()), l.perReplica)
+		l.limiters[namespace] = limiter
+	}
+	return limiter
+}
+
+// prune removes the limiters with the full quota, the namespaces had no requests for a while, so they start over with
+// a new limiter of the full quota.
+func (l *Limiter) prune(now time.Time) {
+	for namespace, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.limiters, namespace)
+		}
+	}
+	l.lastPrune = now
+}
+
+func (l *Limiter) reject(work *workv1.ManifestWork, action string, delay time.Duration) error {
+	l.record(work, corev1.EventTypeWarning, "ManifestWorkRejectedByQuota", action,
+		"The %s of the manifestwork is rejected by the quota of %d per minute in namespace %q, retry after %s",
+		action, l.perMinute, work.Namespace, delay.Round(time.Second))
+	return apierrors.NewTooManyRequests(
+		fmt.Sprintf("the quota of %d manifestwork creations and updates per minute in namespace %q is exceeded",
+			l.perMinute, work.Namespace),
+		int(math.Ceil(delay.Seconds())))
+}
+
+func (l *Limiter) record(work *workv1.ManifestWork, eventType, reason, action, note string, args ...interface{}) {
+	klog.V(4).Infof("%s: "+note, append([]interface{}{reason}, args...)...)
+	if l.recorder == nil {
+		return
+	}
+	l.recorder.Eventf(work, nil, eventType, reason, action, note, args...)
+}
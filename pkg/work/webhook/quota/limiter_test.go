@@ -0,0 +1,166 @@
+package quota
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kevents "k8s.io/client-go/tools/events"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newWork(namespace string) *workv1.ManifestWork {
+	return &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "work"}}
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	limiter := NewLimiter(0, 1, 0)
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	var nilLimiter *Limiter
+	if err := nilLimiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestLimiterReject(t *testing.T) {
+	recorder := kevents.NewFakeRecorder(10)
+	limiter := NewLimiter(2, 1, 0)
+	limiter.SetEventRecorder(recorder)
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	err := limiter.Wait(context.TODO(), newWork("cluster1"), "Update")
+	if !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected too many requests error, but got %v", err)
+	}
+	if delay, ok := apierrors.SuggestsClientDelay(err); !ok || delay <= 0 {
+		t.Errorf("expected a retry delay, but got %d", delay)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "ManifestWorkRejectedByQuota") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Errorf("expected an event of the rejected request")
+	}
+
+	// the quota is counted per namespace
+	if err := limiter.Wait(context.TODO(), newWork("cluster2"), "Create"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestLimiterQueue(t *testing.T) {
+	recorder := kevents.NewFakeRecorder(10)
+	// 10 requests per second
+	limiter := NewLimiter(600, 1, time.Second)
+	limiter.SetEventRecorder(recorder)
+
+	for i := 0; i < 600; i++ {
+		if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Errorf("expected the request is queued")
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "ManifestWorkThrottled") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Errorf("expected an event of the throttled request")
+	}
+
+	// the queued request is rejected once the context is done
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	if err := limiter.Wait(ctx, newWork("cluster1"), "Create"); !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected too many requests error, but got %v", err)
+	}
+}
+
+func TestLimiterReplicas(t *testing.T) {
+	// each of the 3 replicas admits 2 of the 5 requests per minute
+	limiter := NewLimiter(5, 3, 0)
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected too many requests error, but got %v", err)
+	}
+}
+
+func TestLimiterPrune(t *testing.T) {
+	limiter := NewLimiter(60, 1, 0)
+	if err := limiter.Wait(context.TODO(), newWork("cluster1"), "Create"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := limiter.Wait(context.TODO(), newWork("cluster2"), "Create"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the limiters with the full quota are removed
+	limiter.limiters["cluster2"] = rate.NewLimiter(1, 60)
+	limiter.lastPrune = time.Now().Add(-pruneInterval - time.Second)
+	limiter.limiter("cluster3")
+	if _, ok := limiter.limiters["cluster1"]; !ok {
+		t.Errorf("expected the limiter of the busy namespace is kept")
+	}
+	if _, ok := limiter.limiters["cluster2"]; ok {
+		t.Errorf("expected the limiter of the idle namespace is removed")
+	}
+}
+
+func TestWrapClient(t *testing.T) {
+	work := newWork("cluster1")
+	work.Spec.Workload.Manifests = []workv1.Manifest{{}}
+	workClient := fakeworkclient.NewSimpleClientset()
+	limiter := NewLimiter(1, 1, 0)
+	client := limiter.WrapClient("cluster1", workClient.WorkV1().ManifestWorks("cluster1"))
+
+	// the dry runs are not counted
+	dryRunWork := work.DeepCopy()
+	dryRunWork.Name = "dry-run"
+	if _, err := client.Create(context.TODO(), dryRunWork, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := client.Create(context.TODO(), work, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, err := client.Patch(context.TODO(), work.Name, types.MergePatchType, []byte(`{"metadata":{"labels":{"a":"b"}}}`),
+		metav1.PatchOptions{})
+	if !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected too many requests error, but got %v", err)
+	}
+
+	unlimited := workClient.WorkV1().ManifestWorks("cluster1")
+	if wrapped := NewLimiter(0, 1, 0).WrapClient("cluster1", unlimited); wrapped != unlimited {
+		t.Errorf("expected the client is not wrapped without the quota")
+	}
+}
@@ -8,8 +8,12 @@ import (
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -17,7 +21,11 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 
+	"open-cluster-management.io/ocm/pkg/common/fips"
+	"open-cluster-management.io/ocm/pkg/common/helpers"
 	"open-cluster-management.io/ocm/pkg/work/webhook/common"
+	"open-cluster-management.io/ocm/pkg/work/webhook/policy"
+	"open-cluster-management.io/ocm/pkg/work/webhook/quota"
 	webhookv1 "open-cluster-management.io/ocm/pkg/work/webhook/v1"
 )
 
@@ -34,6 +42,11 @@ func (c *Options) RunWebhookServer() error {
 	logger := klog.LoggerWithName(klog.FromContext(context.Background()), "work webhook")
 	ctrl.SetLogger(logger)
 
+	if c.FIPSMode {
+		fips.Enable()
+	}
+	fips.Validate()
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: ":8000",
@@ -42,6 +55,7 @@ func (c *Options) RunWebhookServer() error {
 				func(config *tls.Config) {
 					config.MinVersion = tls.VersionTLS12
 				},
+				fips.ConfigureTLS,
 			},
 			Port:    c.Port,
 			CertDir: c.CertDir,
@@ -64,14 +78,49 @@ func (c *Options) RunWebhookServer() error {
 	}
 
 	common.ManifestValidator.WithLimit(c.ManifestLimit)
+	common.ManifestValidator.WithForbiddenKinds(c.ForbiddenKinds)
+	common.ManifestValidator.WithRequireExecutor(c.RequireExecutor)
+
+	// the manifestworks are defaulted by the admin defined defaulting policies of their namespaces
+	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	// the kinds of the manifests are mapped with a cached rest mapper, so the unknown kinds do not hit the discovery
+	// on every request.
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery()))
+	defaulter := policy.NewDefaulter(dynamicClient, kubeClient, restMapper)
+	if err := mgr.Add(defaulter); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("manifestwork-defaulting-policies", defaulter.ReadyCheck); err != nil {
+		logger.Error(err, "unable to add readyz check handler")
+		return err
+	}
+
+	// the manifestwork creations and updates are limited per cluster namespace
+	ctx := ctrl.SetupSignalHandler()
+	limiter := quota.NewLimiter(c.QuotaPerMinute, c.QuotaReplicas, c.QuotaMaxWait)
+	recorder, err := helpers.NewEventRecorder(ctx, scheme, kubeClient, "cluster-manager-work-webhook")
+	if err != nil {
+		return err
+	}
+	limiter.SetEventRecorder(recorder)
 
-	if err = (&webhookv1.ManifestWorkWebhook{}).Init(mgr); err != nil {
+	workWebhook := &webhookv1.ManifestWorkWebhook{}
+	workWebhook.SetDefaulter(defaulter)
+	workWebhook.SetLimiter(limiter)
+	if err = workWebhook.Init(mgr); err != nil {
 		logger.Error(err, "unable to create ManagedCluster webhook")
 		return err
 	}
 
 	logger.Info("starting manager")
-	if err = mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err = mgr.Start(ctx); err != nil {
 		logger.Error(err, "problem running manager")
 		return err
 	}
@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+var _ webhook.CustomDefaulter = &ManifestWorkWebhook{}
+
+// Default implements webhook.CustomDefaulter, the ManifestWorks are defaulted by the presets they reference and the
+// defaulting policies of their namespaces when they are created.
+func (r *ManifestWorkWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	work, ok := obj.(*workv1.ManifestWork)
+	if !ok {
+		return apierrors.NewBadRequest("Request manifestwork obj format is not right")
+	}
+
+	if err := r.defaulter.Default(work); err != nil {
+		if apierrors.IsBadRequest(err) {
+			return err
+		}
+		return apierrors.NewInternalError(err)
+	}
+	return nil
+}
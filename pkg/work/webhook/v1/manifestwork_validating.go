@@ -29,7 +29,13 @@ func (r *ManifestWorkWebhook) ValidateCreate(ctx context.Context, obj runtime.Ob
 	if !ok {
 		return nil, apierrors.NewBadRequest("Request manifestwork obj format is not right")
 	}
-	return nil, r.validateRequest(work, nil, ctx)
+	if err := r.validateRequest(work, nil, ctx); err != nil {
+		return nil, err
+	}
+	if isDryRun(ctx) {
+		return nil, nil
+	}
+	return nil, r.limiter.Wait(ctx, work, "Create")
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -45,7 +51,21 @@ func (r *ManifestWorkWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj
 		return nil, apierrors.NewBadRequest("Request manifestwork obj format is not right")
 	}
 
-	return nil, r.validateRequest(newWork, oldWork, ctx)
+	if err := r.validateRequest(newWork, oldWork, ctx); err != nil {
+		return nil, err
+	}
+	// only the spec changes are sent to the agents, the other updates (e.g. the finalizers) are not limited
+	if reflect.DeepEqual(oldWork.Spec, newWork.Spec) || isDryRun(ctx) {
+		return nil, nil
+	}
+	return nil, r.limiter.Wait(ctx, newWork, "Update")
+}
+
+// isDryRun returns whether the request is a dry run, the dry runs are not counted by the quota since nothing is
+// persisted.
+func isDryRun(ctx context.Context) bool {
+	req, err := admission.RequestFromContext(ctx)
+	return err == nil && req.DryRun != nil && *req.DryRun
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -62,6 +82,16 @@ func (r *ManifestWorkWebhook) validateRequest(newWork, oldWork *workv1.ManifestW
 		return apierrors.NewBadRequest(err.Error())
 	}
 
+	// the restrictions are enforced on the new specs only, so the existing manifestworks can still be updated
+	if oldWork == nil || !reflect.DeepEqual(oldWork.Spec, newWork.Spec) {
+		if err := common.ManifestValidator.ValidateForbiddenKinds(newWork.Spec.Workload.Manifests); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		if err := common.ManifestValidator.ValidateExecutor(newWork.Spec.Executor); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+	}
+
 	req, err := admission.RequestFromContext(ctx)
 	if err != nil {
 		return apierrors.NewBadRequest(err.Error())
@@ -21,8 +21,11 @@ import (
 	ocmfeature "open-cluster-management.io/api/feature"
 	workv1 "open-cluster-management.io/api/work/v1"
 
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
 	"open-cluster-management.io/ocm/pkg/features"
 	"open-cluster-management.io/ocm/pkg/work/spoke/spoketesting"
+	"open-cluster-management.io/ocm/pkg/work/webhook/common"
+	"open-cluster-management.io/ocm/pkg/work/webhook/quota"
 )
 
 var manifestWorkSchema = metav1.GroupVersionResource{
@@ -322,3 +325,77 @@ func TestManifestWorkExecutorValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRestrictions(t *testing.T) {
+	common.ManifestValidator.WithForbiddenKinds([]string{"Namespace"})
+	common.ManifestValidator.WithRequireExecutor(true)
+	defer func() {
+		common.ManifestValidator.WithForbiddenKinds(nil)
+		common.ManifestValidator.WithRequireExecutor(false)
+	}()
+
+	mw := ManifestWorkWebhook{kubeClient: fakekube.NewSimpleClientset()}
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Resource:  manifestWorkSchema,
+			Operation: admissionv1.Update,
+			UserInfo:  authenticationv1.UserInfo{Username: "test1"},
+		},
+	})
+	namespace := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "ns1"},
+	}}
+	work, _ := spoketesting.NewManifestWork(0, namespace)
+
+	if err := mw.validateRequest(work, nil, ctx); err == nil {
+		t.Errorf("expected the creation is rejected")
+	}
+
+	// the existing manifestwork can be updated without changing the spec, e.g. to remove the finalizers
+	oldWork := work.DeepCopy()
+	oldWork.Finalizers = []string{workv1.ManifestWorkFinalizer}
+	if err := mw.validateRequest(work, oldWork, ctx); err != nil {
+		t.Errorf("expected the update is allowed, but got %v", err)
+	}
+
+	oldWork.Spec.DeleteOption = &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan}
+	if err := mw.validateRequest(work, oldWork, ctx); err == nil {
+		t.Errorf("expected the spec change is rejected")
+	}
+}
+
+func TestValidateCreateQuota(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews",
+		func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, &v1.SubjectAccessReview{Status: v1.SubjectAccessReviewStatus{Allowed: true}}, nil
+		},
+	)
+	mw := ManifestWorkWebhook{kubeClient: kubeClient, limiter: quota.NewLimiter(1, 1, 0)}
+	newContext := func(dryRun bool) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Resource:  manifestWorkSchema,
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "test1"},
+				DryRun:    &dryRun,
+			},
+		})
+	}
+	work, _ := spoketesting.NewManifestWork(0, testingcommon.NewUnstructured("v1", "ConfigMap", "ns1", "test"))
+
+	// the dry runs are not counted by the quota
+	for i := 0; i < 2; i++ {
+		if _, err := mw.ValidateCreate(newContext(true), work); err != nil {
+			t.Fatalf("expected the dry run is allowed, but got %v", err)
+		}
+	}
+	if _, err := mw.ValidateCreate(newContext(false), work); err != nil {
+		t.Fatalf("expected the creation is allowed, but got %v", err)
+	}
+	if _, err := mw.ValidateCreate(newContext(false), work); !apierrors.IsTooManyRequests(err) {
+		t.Errorf("expected the creation is rejected by the quota, but got %v", err)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	v1 "open-cluster-management.io/api/work/v1"
+
+	"open-cluster-management.io/ocm/pkg/work/webhook/policy"
+	"open-cluster-management.io/ocm/pkg/work/webhook/quota"
 )
 
 type ManifestWorkWebhook struct {
 	kubeClient kubernetes.Interface
+	defaulter  *policy.Defaulter
+	limiter    *quota.Limiter
 }
 
 func (r *ManifestWorkWebhook) Init(mgr ctrl.Manager) error {
@@ -25,9 +30,20 @@ func (r *ManifestWorkWebhook) SetExternalKubeClientSet(client kubernetes.Interfa
 	r.kubeClient = client
 }
 
+// SetDefaulter sets the defaulter of the manifestwork defaulting policies
+func (r *ManifestWorkWebhook) SetDefaulter(defaulter *policy.Defaulter) {
+	r.defaulter = defaulter
+}
+
+// SetLimiter sets the limiter of the manifestwork creations and updates per cluster namespace
+func (r *ManifestWorkWebhook) SetLimiter(limiter *quota.Limiter) {
+	r.limiter = limiter
+}
+
 func (r *ManifestWorkWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(r).
+		WithDefaulter(r).
 		For(&v1.ManifestWork{}).
 		Complete()
 }
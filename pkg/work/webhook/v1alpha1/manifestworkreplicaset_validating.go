@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"errors"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -55,7 +56,7 @@ func (r *ManifestWorkReplicaSetWebhook) ValidateDelete(_ context.Context, _ runt
 }
 
 func (r *ManifestWorkReplicaSetWebhook) validateRequest(
-	newmwrSet *workv1alpha1.ManifestWorkReplicaSet, _ *workv1alpha1.ManifestWorkReplicaSet,
+	newmwrSet *workv1alpha1.ManifestWorkReplicaSet, oldmwrSet *workv1alpha1.ManifestWorkReplicaSet,
 	ctx context.Context) error {
 	if err := checkFeatureEnabled(); err != nil {
 		return err
@@ -65,6 +66,17 @@ func (r *ManifestWorkReplicaSetWebhook) validateRequest(
 		return apierrors.NewBadRequest(err.Error())
 	}
 
+	// the restrictions are enforced on the new specs only, so the existing manifestworkreplicasets can still be updated
+	if oldmwrSet == nil || !reflect.DeepEqual(oldmwrSet.Spec, newmwrSet.Spec) {
+		template := newmwrSet.Spec.ManifestWorkTemplate
+		if err := common.ManifestValidator.ValidateForbiddenKinds(template.Workload.Manifests); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		if err := common.ManifestValidator.ValidateExecutor(template.Executor); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+	}
+
 	_, err := admission.RequestFromContext(ctx)
 	if err != nil {
 		return apierrors.NewBadRequest(err.Error())
@@ -0,0 +1,252 @@
+package simulator
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"time"
+
+	certificates "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	workinformers "open-cluster-management.io/api/client/work/informers/externalversions"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/patcher"
+
+	"open-cluster-management.io/ocm/pkg/registration/hub/user"
+)
+
+const leaseName = "managed-cluster-lease"
+
+// agent emulates the registration and work agents of a managed cluster.
+type agent struct {
+	clusterName          string
+	leaseDurationSeconds int32
+}
+
+// join creates the managed cluster and gets a client certificate issued for the agent with a csr, it returns the
+// config to connect to the hub with the client certificate.
+func (a *agent) join(ctx context.Context, hubKubeClient kubernetes.Interface,
+	hubClusterClient clusterclientset.Interface, hubConfig *rest.Config,
+	approveCSR bool, timeout time.Duration) (*rest.Config, error) {
+	_, err := hubClusterClient.ClusterV1().ManagedClusters().Create(ctx, &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: a.clusterName},
+		Spec: clusterv1.ManagedClusterSpec{
+			HubAcceptsClient:     true,
+			LeaseDurationSeconds: a.leaseDurationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	keyData, err := keyutil.MakeEllipticPrivateKeyPEM()
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := keyutil.ParsePrivateKeyPEM(keyData)
+	if err != nil {
+		return nil, err
+	}
+	csrData, err := certutil.MakeCSR(privateKey, &pkix.Name{
+		Organization: []string{fmt.Sprintf("%s%s", user.SubjectPrefix, a.clusterName), user.ManagedClustersGroup},
+		CommonName:   fmt.Sprintf("%s%s:%s", user.SubjectPrefix, a.clusterName, a.clusterName),
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := hubKubeClient.CertificatesV1().CertificateSigningRequests().Create(ctx,
+		&certificates.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: fmt.Sprintf("%s-", a.clusterName),
+				Labels:       map[string]string{clusterv1.ClusterNameLabelKey: a.clusterName},
+			},
+			Spec: certificates.CertificateSigningRequestSpec{
+				Request: csrData,
+				Usages: []certificates.KeyUsage{
+					certificates.UsageDigitalSignature,
+					certificates.UsageKeyEncipherment,
+					certificates.UsageClientAuth,
+				},
+				SignerName: certificates.KubeAPIServerClientSignerName,
+			},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if approveCSR {
+		csr.Status.Conditions = append(csr.Status.Conditions, certificates.CertificateSigningRequestCondition{
+			Type:    certificates.CertificateApproved,
+			Status:  "True",
+			Reason:  "SimulatorApprove",
+			Message: "Approved by the cluster simulator",
+		})
+		_, err = hubKubeClient.CertificatesV1().CertificateSigningRequests().UpdateApproval(
+			ctx, csr.Name, csr, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var certData []byte
+	err = wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		csr, err := hubKubeClient.CertificatesV1().CertificateSigningRequests().Get(ctx, csr.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		certData = csr.Status.Certificate
+		return len(certData) > 0, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("the client certificate is not issued: %w", err)
+	}
+
+	config := rest.AnonymousClientConfig(hubConfig)
+	config.CertData = certData
+	config.KeyData = keyData
+	return config, nil
+}
+
+// run reports the managed cluster joined and available, renews the lease and echoes the status of the
+// manifestworks with the client certificate of the agent until the context is done.
+func (a *agent) run(ctx context.Context, config *rest.Config) error {
+	kubeClient, clusterClient, workClient, err := agentClients(config)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterClient.ClusterV1().ManagedClusters().Get(ctx, a.clusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	clusterPatcher := patcher.NewPatcher[
+		*clusterv1.ManagedCluster, clusterv1.ManagedClusterSpec, clusterv1.ManagedClusterStatus](
+		clusterClient.ClusterV1().ManagedClusters())
+	newCluster := cluster.DeepCopy()
+	for _, conditionType := range []string{clusterv1.ManagedClusterConditionJoined, clusterv1.ManagedClusterConditionAvailable} {
+		meta.SetStatusCondition(&newCluster.Status.Conditions, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "SimulatedCluster",
+			Message: "The cluster is simulated",
+		})
+	}
+	if _, err := clusterPatcher.PatchStatus(ctx, newCluster, newCluster.Status, cluster.Status); err != nil {
+		return err
+	}
+
+	workInformerFactory := workinformers.NewSharedInformerFactoryWithOptions(
+		workClient, 10*time.Minute, workinformers.WithNamespace(a.clusterName))
+	workInterface := workClient.WorkV1().ManifestWorks(a.clusterName)
+	echo := func(obj interface{}) {
+		work, ok := obj.(*workapiv1.ManifestWork)
+		if !ok {
+			return
+		}
+		if err := echoWorkStatus(ctx, workInterface, work); err != nil {
+			klog.Errorf("failed to update the status of work %s/%s, %v", work.Namespace, work.Name, err)
+		}
+	}
+	_, err = workInformerFactory.Work().V1().ManifestWorks().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    echo,
+			UpdateFunc: func(_, newObj interface{}) { echo(newObj) },
+		})
+	if err != nil {
+		return err
+	}
+	workInformerFactory.Start(ctx.Done())
+
+	wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if err := renewLease(ctx, kubeClient.CoordinationV1(), a.clusterName); err != nil {
+			klog.Errorf("failed to renew the lease of cluster %s, %v", a.clusterName, err)
+		}
+	}, time.Duration(a.leaseDurationSeconds)*time.Second/4, 0.25, true)
+	return nil
+}
+
+// renewLease updates the renew time of the lease of the managed cluster, the lease is created by the hub once the
+// cluster is accepted.
+func renewLease(ctx context.Context, leaseClient coordinationv1client.CoordinationV1Interface, clusterName string) error {
+	lease, err := leaseClient.Leases(clusterName).Get(ctx, leaseName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+	_, err = leaseClient.Leases(clusterName).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// echoWorkStatus reports the manifests of the current generation of the manifestwork applied and available without
+// applying them.
+func echoWorkStatus(ctx context.Context, workClient workv1client.ManifestWorkInterface, work *workapiv1.ManifestWork) error {
+	if !work.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	applied := meta.FindStatusCondition(work.Status.Conditions, workapiv1.WorkApplied)
+	if applied != nil && applied.ObservedGeneration == work.Generation {
+		return nil
+	}
+
+	newWork := work.DeepCopy()
+	newWork.Status.ResourceStatus.Manifests = nil
+	for index, manifest := range work.Spec.Workload.Manifests {
+		resourceMeta := workapiv1.ManifestResourceMeta{Ordinal: int32(index)}
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(manifest.Raw); err == nil {
+			gvk := object.GroupVersionKind()
+			resourceMeta.Group, resourceMeta.Version, resourceMeta.Kind = gvk.Group, gvk.Version, gvk.Kind
+			// the simulated cluster has no rest mapper
+			resource, _ := meta.UnsafeGuessKindToResource(gvk)
+			resourceMeta.Resource = resource.Resource
+			resourceMeta.Namespace, resourceMeta.Name = object.GetNamespace(), object.GetName()
+		}
+		newWork.Status.ResourceStatus.Manifests = append(newWork.Status.ResourceStatus.Manifests,
+			workapiv1.ManifestCondition{
+				ResourceMeta: resourceMeta,
+				Conditions:   simulatedConditions(work.Generation, workapiv1.ManifestApplied, workapiv1.ManifestAvailable),
+			})
+	}
+	for _, condition := range simulatedConditions(work.Generation, workapiv1.WorkApplied, workapiv1.WorkAvailable) {
+		meta.SetStatusCondition(&newWork.Status.Conditions, condition)
+	}
+
+	workPatcher := patcher.NewPatcher[
+		*workapiv1.ManifestWork, workapiv1.ManifestWorkSpec, workapiv1.ManifestWorkStatus](workClient)
+	_, err := workPatcher.PatchStatus(ctx, newWork, newWork.Status, work.Status)
+	return err
+}
+
+func simulatedConditions(generation int64, conditionTypes ...string) []metav1.Condition {
+	var conditions []metav1.Condition
+	for _, conditionType := range conditionTypes {
+		conditions = append(conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             "SimulatedCluster",
+			Message:            "The manifests are not applied on the simulated cluster",
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+	return conditions
+}
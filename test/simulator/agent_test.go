@@ -0,0 +1,113 @@
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	fakeworkclient "open-cluster-management.io/api/client/work/clientset/versioned/fake"
+	workapiv1 "open-cluster-management.io/api/work/v1"
+
+	testingcommon "open-cluster-management.io/ocm/pkg/common/testing"
+)
+
+func TestRenewLease(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: leaseName},
+	})
+	if err := renewLease(context.TODO(), kubeClient.CoordinationV1(), "cluster1"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	testingcommon.AssertActions(t, kubeClient.Actions(), "get", "update")
+	lease := kubeClient.Actions()[1].(clienttesting.UpdateActionImpl).Object.(*coordinationv1.Lease)
+	if lease.Spec.RenewTime == nil {
+		t.Errorf("expected the lease is renewed")
+	}
+
+	// the lease is not created by the agent
+	kubeClient = kubefake.NewSimpleClientset()
+	if err := renewLease(context.TODO(), kubeClient.CoordinationV1(), "cluster1"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	testingcommon.AssertActions(t, kubeClient.Actions(), "get")
+}
+
+func TestEchoWorkStatus(t *testing.T) {
+	newWork := func(generation, observedGeneration int64) *workapiv1.ManifestWork {
+		work := &workapiv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "work1", Generation: generation},
+			Spec: workapiv1.ManifestWorkSpec{
+				Workload: workapiv1.ManifestsTemplate{
+					Manifests: []workapiv1.Manifest{
+						{RawExtension: runtime.RawExtension{
+							Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"namespace":"ns1","name":"deploy1"}}`),
+						}},
+					},
+				},
+			},
+		}
+		if observedGeneration > 0 {
+			work.Status.Conditions = simulatedConditions(observedGeneration, workapiv1.WorkApplied)
+		}
+		return work
+	}
+
+	cases := []struct {
+		name            string
+		work            *workapiv1.ManifestWork
+		expectedActions []string
+	}{
+		{
+			name:            "new work",
+			work:            newWork(1, 0),
+			expectedActions: []string{"patch"},
+		},
+		{
+			name:            "new generation",
+			work:            newWork(2, 1),
+			expectedActions: []string{"patch"},
+		},
+		{
+			name: "generation applied",
+			work: newWork(1, 1),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			workClient := fakeworkclient.NewSimpleClientset(c.work)
+			if err := echoWorkStatus(context.TODO(), workClient.WorkV1().ManifestWorks("cluster1"), c.work); err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			testingcommon.AssertActions(t, workClient.Actions(), c.expectedActions...)
+			if len(c.expectedActions) == 0 {
+				return
+			}
+
+			patch := workClient.Actions()[0].(clienttesting.PatchActionImpl).Patch
+			work := &workapiv1.ManifestWork{}
+			if err := json.Unmarshal(patch, work); err != nil {
+				t.Fatal(err)
+			}
+			for _, conditionType := range []string{workapiv1.WorkApplied, workapiv1.WorkAvailable} {
+				condition := meta.FindStatusCondition(work.Status.Conditions, conditionType)
+				if condition == nil || condition.ObservedGeneration != c.work.Generation {
+					t.Errorf("expected condition %s of generation %d, but got %v",
+						conditionType, c.work.Generation, work.Status.Conditions)
+				}
+			}
+			manifests := work.Status.ResourceStatus.Manifests
+			if len(manifests) != 1 || manifests[0].ResourceMeta.Resource != "deployments" ||
+				manifests[0].ResourceMeta.Name != "deploy1" {
+				t.Errorf("unexpected manifest conditions %v", manifests)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	goflag "flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	utilflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/logs"
+
+	"open-cluster-management.io/ocm/test/simulator"
+)
+
+// The simulator binary emulates the spoke agents of a number of managed clusters against a hub for scale testing.
+
+func main() {
+	pflag.CommandLine.SetNormalizeFunc(utilflag.WordSepNormalizeFunc)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+
+	logs.AddFlags(pflag.CommandLine)
+	logs.InitLogs()
+	defer logs.FlushLogs()
+
+	// the simulated clusters are cleaned up once the simulator is interrupted
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	command := newSimulatorCommand()
+	if err := command.ExecuteContext(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func newSimulatorCommand() *cobra.Command {
+	options := simulator.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "simulator",
+		Short: "Simulate the agents of the managed clusters against a hub",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(cmd.Context())
+		},
+	}
+
+	options.AddFlags(cmd.Flags())
+	return cmd
+}
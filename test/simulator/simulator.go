@@ -0,0 +1,202 @@
+// Package simulator emulates the spoke agents of a number of managed clusters against a real hub to test the
+// scalability of the hub. Each simulated cluster joins the hub with the csr flow of the registration agent, renews
+// its lease and echoes the applied status of the manifestworks like the work agent, without running any workload.
+//
+// The simulator is started with the kubeconfig of a user permitted to accept the managed clusters and to approve
+// the csrs, e.g.
+//
+//	go run ./test/simulator/cmd/simulator --hub-kubeconfig=/path/to/kubeconfig --clusters=1000 --cleanup
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	workclientset "open-cluster-management.io/api/client/work/clientset/versioned"
+)
+
+// Options is the options of the simulator.
+type Options struct {
+	HubKubeconfig        string
+	Clusters             int
+	ClusterNamePrefix    string
+	Concurrency          int
+	LeaseDurationSeconds int32
+	ApproveCSR           bool
+	Cleanup              bool
+	JoinTimeout          time.Duration
+}
+
+// NewOptions returns the simulator options with default value set
+func NewOptions() *Options {
+	return &Options{
+		Clusters:             100,
+		ClusterNamePrefix:    "simulated-cluster",
+		Concurrency:          20,
+		LeaseDurationSeconds: 60,
+		ApproveCSR:           true,
+		JoinTimeout:          5 * time.Minute,
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.HubKubeconfig, "hub-kubeconfig", o.HubKubeconfig,
+		"The kubeconfig of the hub, the user is supposed to be permitted to accept the clusters and approve the csrs.")
+	fs.IntVar(&o.Clusters, "clusters", o.Clusters, "The number of the simulated managed clusters.")
+	fs.StringVar(&o.ClusterNamePrefix, "cluster-name-prefix", o.ClusterNamePrefix,
+		"The prefix of the names of the simulated managed clusters.")
+	fs.IntVar(&o.Concurrency, "concurrency", o.Concurrency, "The number of the clusters joining the hub at the same time.")
+	fs.Int32Var(&o.LeaseDurationSeconds, "lease-duration-seconds", o.LeaseDurationSeconds,
+		"The lease duration of the simulated managed clusters.")
+	fs.BoolVar(&o.ApproveCSR, "approve-csr", o.ApproveCSR,
+		"Approve the csrs of the simulated clusters, disable it if the csrs are approved by the hub.")
+	fs.BoolVar(&o.Cleanup, "cleanup", o.Cleanup, "Delete the simulated managed clusters once the simulator stops.")
+	fs.DurationVar(&o.JoinTimeout, "join-timeout", o.JoinTimeout,
+		"The timeout for a simulated cluster to get its client certificate issued.")
+}
+
+func (o *Options) Validate() error {
+	if len(o.HubKubeconfig) == 0 {
+		return fmt.Errorf("hub-kubeconfig is required")
+	}
+	if o.Clusters <= 0 || o.Concurrency <= 0 {
+		return fmt.Errorf("clusters and concurrency must be positive")
+	}
+	return nil
+}
+
+// Run joins the simulated clusters to the hub and runs their agents until the context is done.
+func (o *Options) Run(ctx context.Context) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	hubConfig, err := clientcmd.BuildConfigFromFlags("", o.HubKubeconfig)
+	if err != nil {
+		return err
+	}
+	hubKubeClient, err := kubernetes.NewForConfig(hubConfig)
+	if err != nil {
+		return err
+	}
+	hubClusterClient, err := clusterclientset.NewForConfig(hubConfig)
+	if err != nil {
+		return err
+	}
+
+	if o.Cleanup {
+		defer o.cleanup(hubClusterClient)
+	}
+
+	var lock sync.Mutex
+	var joinDurations []time.Duration
+	var failed int
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, o.Concurrency)
+	for i := 0; i < o.Clusters; i++ {
+		agent := &agent{
+			clusterName:          fmt.Sprintf("%s-%d", o.ClusterNamePrefix, i),
+			leaseDurationSeconds: o.LeaseDurationSeconds,
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			start := time.Now()
+			agentConfig, err := agent.join(ctx, hubKubeClient, hubClusterClient, hubConfig, o.ApproveCSR, o.JoinTimeout)
+			<-sem
+			if err != nil {
+				klog.Errorf("cluster %s failed to join the hub, %v", agent.clusterName, err)
+				lock.Lock()
+				failed++
+				lock.Unlock()
+				return
+			}
+			lock.Lock()
+			joinDurations = append(joinDurations, time.Since(start))
+			lock.Unlock()
+
+			if err := agent.run(ctx, agentConfig); err != nil {
+				klog.Errorf("agent of cluster %s stopped, %v", agent.clusterName, err)
+			}
+		}()
+	}
+
+	// report the join durations once all the clusters join the hub or fail to join.
+	go func() {
+		err := wait.PollUntilContextCancel(ctx, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			klog.Infof("%d of %d clusters joined the hub", len(joinDurations), o.Clusters)
+			return len(joinDurations)+failed == o.Clusters, nil
+		})
+		if err != nil {
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		p50, p99, maxDuration := percentiles(joinDurations)
+		klog.Infof("%d clusters joined the hub and %d failed, join duration p50: %v, p99: %v, max: %v",
+			len(joinDurations), failed, p50, p99, maxDuration)
+	}()
+
+	wg.Wait()
+	return nil
+}
+
+func (o *Options) cleanup(hubClusterClient clusterclientset.Interface) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for i := 0; i < o.Clusters; i++ {
+		name := fmt.Sprintf("%s-%d", o.ClusterNamePrefix, i)
+		err := hubClusterClient.ClusterV1().ManagedClusters().Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			klog.Errorf("failed to delete cluster %s, %v", name, err)
+		}
+	}
+}
+
+// agentClients builds the clients of the hub with the config of the simulated agent.
+func agentClients(config *rest.Config) (
+	kubernetes.Interface, clusterclientset.Interface, workclientset.Interface, error) {
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	clusterClient, err := clusterclientset.NewForConfig(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	workClient, err := workclientset.NewForConfig(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return kubeClient, clusterClient, workClient, nil
+}
+
+func percentiles(durations []time.Duration) (p50, p99, maxDuration time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0, 0
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)*50/100], sorted[len(sorted)*99/100], sorted[len(sorted)-1]
+}